@@ -9,6 +9,9 @@ interface PrometheusAvailability {
   service?: string;
   port?: number;
   portName?: string;
+  topology?: 'single' | 'ha-replica' | 'service' | 'thanos-query' | 'thanos-query-frontend';
+  replicas?: number;
+  dedup?: boolean;
 }
 
 export function usePrometheusAvailability() {
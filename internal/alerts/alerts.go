@@ -0,0 +1,208 @@
+package alerts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Alert states
+const (
+	StatusFiring   = "firing"
+	StatusResolved = "resolved"
+)
+
+// WebhookMessage is the payload Alertmanager posts to webhook receivers
+type WebhookMessage struct {
+	Version           string            `json:"version"`
+	GroupKey          string            `json:"groupKey"`
+	Status            string            `json:"status"`
+	Receiver          string            `json:"receiver"`
+	GroupLabels       map[string]string `json:"groupLabels"`
+	CommonLabels      map[string]string `json:"commonLabels"`
+	CommonAnnotations map[string]string `json:"commonAnnotations"`
+	ExternalURL       string            `json:"externalURL"`
+	Alerts            []WebhookAlert    `json:"alerts"`
+}
+
+// WebhookAlert is a single alert in an Alertmanager webhook payload
+type WebhookAlert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+// Target is the Kubernetes object an alert is about, derived from its labels
+type Target struct {
+	ConfigID     string `json:"configId,omitempty"`
+	Cluster      string `json:"cluster,omitempty"`
+	Namespace    string `json:"namespace,omitempty"`
+	WorkloadKind string `json:"workloadKind,omitempty"`
+	WorkloadName string `json:"workloadName,omitempty"`
+	Pod          string `json:"pod,omitempty"`
+	Container    string `json:"container,omitempty"`
+	Node         string `json:"node,omitempty"`
+}
+
+// Acknowledgement records who acknowledged a firing alert
+type Acknowledgement struct {
+	By      string    `json:"by,omitempty"`
+	Comment string    `json:"comment,omitempty"`
+	At      time.Time `json:"at"`
+}
+
+// Alert is an external alert received from Alertmanager
+type Alert struct {
+	ID           string            `json:"id"`
+	Fingerprint  string            `json:"fingerprint"`
+	Name         string            `json:"name"`
+	Severity     string            `json:"severity,omitempty"`
+	Summary      string            `json:"summary,omitempty"`
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       *time.Time        `json:"endsAt,omitempty"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+	Receiver     string            `json:"receiver,omitempty"`
+	ExternalURL  string            `json:"externalURL,omitempty"`
+	Target
+	Acknowledged *Acknowledgement `json:"acknowledged,omitempty"`
+	ReceivedAt   time.Time        `json:"receivedAt"`
+	UpdatedAt    time.Time        `json:"updatedAt"`
+}
+
+// workloadLabels maps kube-state-metrics style labels to the workload kind they name,
+// in order of preference. "job" is not included since it is the Prometheus scrape job.
+var workloadLabels = []struct{ label, kind string }{
+	{"deployment", "Deployment"},
+	{"statefulset", "StatefulSet"},
+	{"daemonset", "DaemonSet"},
+	{"cronjob", "CronJob"},
+	{"job_name", "Job"},
+	{"replicaset", "ReplicaSet"},
+}
+
+// MapTarget derives the cluster, namespace and workload an alert refers to from its labels.
+// configID and cluster come from the webhook URL; a cluster label overrides the cluster
+// so one Alertmanager can serve several clusters in the same kubeconfig.
+func MapTarget(labels map[string]string, configID, cluster string) Target {
+	target := Target{ConfigID: configID, Cluster: cluster}
+	if c := labels["cluster"]; c != "" {
+		target.Cluster = c
+	}
+	target.Namespace = firstLabel(labels, "namespace", "exported_namespace")
+	target.Pod = firstLabel(labels, "pod", "pod_name", "exported_pod")
+	target.Container = firstLabel(labels, "container", "container_name")
+	target.Node = firstLabel(labels, "node", "kubernetes_node")
+
+	for _, wl := range workloadLabels {
+		value := labels[wl.label]
+		if value == "" {
+			continue
+		}
+		target.WorkloadKind, target.WorkloadName = wl.kind, value
+		break
+	}
+	if target.WorkloadKind == "ReplicaSet" {
+		// ReplicaSets created by Deployments carry a pod-template-hash suffix
+		if name, ok := trimGeneratedSuffix(target.WorkloadName, 1); ok {
+			target.WorkloadKind, target.WorkloadName = "Deployment", name
+		}
+	}
+	return target
+}
+
+func firstLabel(labels map[string]string, names ...string) string {
+	for _, name := range names {
+		if v := labels[name]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+var generatedSuffix = regexp.MustCompile(`^[a-z0-9]{5,10}$`)
+
+// trimGeneratedSuffix removes n trailing generated name segments (e.g. pod-template hashes)
+func trimGeneratedSuffix(name string, n int) (string, bool) {
+	parts := strings.Split(name, "-")
+	if len(parts) <= n {
+		return "", false
+	}
+	for _, p := range parts[len(parts)-n:] {
+		if !generatedSuffix.MatchString(p) {
+			return "", false
+		}
+	}
+	return strings.Join(parts[:len(parts)-n], "-"), true
+}
+
+var ordinalSuffix = regexp.MustCompile(`^(.+)-[0-9]+$`)
+
+// PodBelongsTo reports whether a pod name was generated for a workload of the given kind
+func PodBelongsTo(kind, name, pod string) bool {
+	switch kind {
+	case "Pod":
+		return pod == name
+	case "Deployment":
+		owner, ok := trimGeneratedSuffix(pod, 2)
+		return ok && owner == name
+	case "StatefulSet":
+		m := ordinalSuffix.FindStringSubmatch(pod)
+		return m != nil && m[1] == name
+	case "DaemonSet", "Job", "ReplicaSet":
+		owner, ok := trimGeneratedSuffix(pod, 1)
+		return ok && owner == name
+	case "CronJob":
+		// CronJob pods are named <cronjob>-<schedule time>-<suffix>
+		owner, ok := trimGeneratedSuffix(pod, 1)
+		if !ok {
+			return false
+		}
+		m := ordinalSuffix.FindStringSubmatch(owner)
+		return m != nil && m[1] == name
+	}
+	return false
+}
+
+// Matches reports whether the alert concerns the object kind/name; an empty kind matches
+// every alert in scope
+func (a *Alert) Matches(kind, name string) bool {
+	if kind == "" {
+		return true
+	}
+	if kind == a.WorkloadKind && name == a.WorkloadName {
+		return true
+	}
+	if kind == "Node" {
+		return a.Node == name
+	}
+	return a.Pod != "" && PodBelongsTo(kind, name, a.Pod)
+}
+
+// alertID identifies an alert instance per cluster so identical alerts from different
+// clusters are kept apart
+func alertID(configID, cluster, fingerprint string, labels map[string]string) string {
+	if fingerprint == "" {
+		keys := make([]string, 0, len(labels))
+		for k := range labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var b strings.Builder
+		for _, k := range keys {
+			b.WriteString(k + "=" + labels[k] + "\x00")
+		}
+		fingerprint = b.String()
+	}
+	sum := sha256.Sum256([]byte(configID + "|" + cluster + "|" + fingerprint))
+	return hex.EncodeToString(sum[:12])
+}
@@ -0,0 +1,40 @@
+package alerts
+
+import "testing"
+
+func TestMapTarget(t *testing.T) {
+	target := MapTarget(map[string]string{
+		"alertname":  "KubePodCrashLooping",
+		"cluster":    "eu-1",
+		"namespace":  "shop",
+		"replicaset": "checkout-7d9f8c6b5",
+		"pod":        "checkout-7d9f8c6b5-x2x9z",
+	}, "prod", "default")
+	if target.ConfigID != "prod" || target.Cluster != "eu-1" || target.Namespace != "shop" {
+		t.Fatalf("unexpected scope: %+v", target)
+	}
+	if target.WorkloadKind != "Deployment" || target.WorkloadName != "checkout" {
+		t.Fatalf("replicaset not mapped to its deployment: %+v", target)
+	}
+}
+
+func TestPodBelongsTo(t *testing.T) {
+	cases := []struct {
+		kind, name, pod string
+		want            bool
+	}{
+		{"Deployment", "checkout", "checkout-7d9f8c6b5-x2x9z", true},
+		{"Deployment", "check", "checkout-7d9f8c6b5-x2x9z", false},
+		{"StatefulSet", "db", "db-2", true},
+		{"StatefulSet", "db", "db-replica-2", false},
+		{"DaemonSet", "node-exporter", "node-exporter-abcde", true},
+		{"CronJob", "backup", "backup-28293840-k8s2d", true},
+		{"Job", "backup-28293840", "backup-28293840-k8s2d", true},
+		{"Pod", "web-0", "web-0", true},
+	}
+	for _, tc := range cases {
+		if got := PodBelongsTo(tc.kind, tc.name, tc.pod); got != tc.want {
+			t.Errorf("PodBelongsTo(%s, %s, %s) = %v, want %v", tc.kind, tc.name, tc.pod, got, tc.want)
+		}
+	}
+}
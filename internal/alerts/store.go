@@ -0,0 +1,189 @@
+package alerts
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+)
+
+const (
+	alertsCollection = "external_alerts"
+
+	// resolvedRetention is how long resolved alerts stay visible before being pruned
+	resolvedRetention = 24 * time.Hour
+)
+
+// Filter narrows the alerts returned by List; empty fields match everything
+type Filter struct {
+	ConfigID  string
+	Cluster   string
+	Namespace string
+	Kind      string // object kind, e.g. Deployment or Pod
+	Name      string
+	Status    string // firing (default), resolved or all
+}
+
+// Store keeps alerts received from Alertmanager
+type Store struct {
+	documents *storage.DocumentStore
+	logger    *logger.Logger
+
+	mu     sync.RWMutex
+	alerts map[string]*Alert
+}
+
+// NewStore creates an alert store, loading previously received alerts
+func NewStore(documents *storage.DocumentStore, log *logger.Logger) *Store {
+	s := &Store{
+		documents: documents,
+		logger:    log,
+		alerts:    map[string]*Alert{},
+	}
+	docs, err := documents.List(alertsCollection)
+	if err != nil {
+		log.WithError(err).Error("Failed to load external alerts")
+		return s
+	}
+	for id, data := range docs {
+		var alert Alert
+		if err := json.Unmarshal(data, &alert); err != nil {
+			log.WithError(err).WithField("alert", id).Error("Skipping unreadable external alert")
+			continue
+		}
+		s.alerts[alert.ID] = &alert
+	}
+	return s
+}
+
+// Ingest records the alerts in an Alertmanager webhook payload. configID and cluster
+// identify the cluster the Alertmanager belongs to. It returns the number of alerts stored.
+func (s *Store) Ingest(msg *WebhookMessage, configID, cluster string) int {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := 0
+	for _, wa := range msg.Alerts {
+		target := MapTarget(wa.Labels, configID, cluster)
+		id := alertID(target.ConfigID, target.Cluster, wa.Fingerprint, wa.Labels)
+
+		alert, exists := s.alerts[id]
+		if !exists {
+			alert = &Alert{ID: id, ReceivedAt: now}
+		} else if !alert.StartsAt.Equal(wa.StartsAt) && wa.Status == StatusFiring {
+			// A new firing episode needs a fresh acknowledgement
+			alert.Acknowledged = nil
+		}
+		alert.Fingerprint = wa.Fingerprint
+		alert.Name = wa.Labels["alertname"]
+		alert.Severity = wa.Labels["severity"]
+		alert.Summary = firstLabel(wa.Annotations, "summary", "message", "description")
+		alert.Status = wa.Status
+		if alert.Status != StatusResolved {
+			alert.Status = StatusFiring
+		}
+		alert.Labels = wa.Labels
+		alert.Annotations = wa.Annotations
+		alert.StartsAt = wa.StartsAt
+		alert.EndsAt = nil
+		if !wa.EndsAt.IsZero() {
+			endsAt := wa.EndsAt
+			alert.EndsAt = &endsAt
+		}
+		alert.GeneratorURL = wa.GeneratorURL
+		alert.Receiver = msg.Receiver
+		alert.ExternalURL = msg.ExternalURL
+		alert.Target = target
+		alert.UpdatedAt = now
+
+		s.alerts[id] = alert
+		if err := s.documents.Put(alertsCollection, id, alert); err != nil {
+			s.logger.WithError(err).WithField("alert", alert.Name).Error("Failed to store external alert")
+			continue
+		}
+		stored++
+	}
+	s.pruneLocked(now)
+	return stored
+}
+
+// List returns matching alerts, firing and unacknowledged first, then newest first
+func (s *Store) List(filter Filter) []Alert {
+	now := time.Now()
+	status := filter.Status
+	if status == "" {
+		status = StatusFiring
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	alerts := []Alert{}
+	for _, a := range s.alerts {
+		alert := *a
+		// Alertmanager keeps re-sending firing alerts; one not refreshed past endsAt has gone away
+		if alert.Status == StatusFiring && alert.EndsAt != nil && alert.EndsAt.Before(now) {
+			alert.Status = StatusResolved
+		}
+		if (status != "all" && alert.Status != status) ||
+			(filter.ConfigID != "" && alert.ConfigID != filter.ConfigID) ||
+			(filter.Cluster != "" && alert.Cluster != filter.Cluster) ||
+			(filter.Namespace != "" && alert.Namespace != filter.Namespace) ||
+			!alert.Matches(filter.Kind, filter.Name) {
+			continue
+		}
+		alerts = append(alerts, alert)
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		a, b := alerts[i], alerts[j]
+		if (a.Status == StatusFiring) != (b.Status == StatusFiring) {
+			return a.Status == StatusFiring
+		}
+		if (a.Acknowledged == nil) != (b.Acknowledged == nil) {
+			return a.Acknowledged == nil
+		}
+		return a.StartsAt.After(b.StartsAt)
+	})
+	return alerts
+}
+
+// Acknowledge sets or clears (ack nil) the acknowledgement of an alert
+func (s *Store) Acknowledge(id string, ack *Acknowledgement) (*Alert, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	alert, ok := s.alerts[id]
+	if !ok {
+		return nil, storage.ErrDocumentNotFound
+	}
+	alert.Acknowledged = ack
+	alert.UpdatedAt = time.Now()
+	if err := s.documents.Put(alertsCollection, id, alert); err != nil {
+		return nil, err
+	}
+	result := *alert
+	return &result, nil
+}
+
+// pruneLocked drops alerts resolved longer than the retention period
+func (s *Store) pruneLocked(now time.Time) {
+	for id, alert := range s.alerts {
+		resolvedAt := alert.UpdatedAt
+		if alert.Status == StatusFiring {
+			if alert.EndsAt == nil || alert.EndsAt.After(now) {
+				continue
+			}
+			resolvedAt = *alert.EndsAt
+		}
+		if now.Sub(resolvedAt) < resolvedRetention {
+			continue
+		}
+		if err := s.documents.Delete(alertsCollection, id); err != nil {
+			s.logger.WithError(err).Warn("Failed to prune external alert")
+			continue
+		}
+		delete(s.alerts, id)
+	}
+}
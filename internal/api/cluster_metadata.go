@@ -0,0 +1,228 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/clustermeta"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// clusterProbeTimeout bounds the calls made to enrich one context
+const clusterProbeTimeout = 5 * time.Second
+
+// ClusterInfo describes one kubeconfig context for the cluster switcher
+type ClusterInfo struct {
+	ConfigID      string                `json:"configId"`
+	ConfigName    string                `json:"configName"`
+	Context       string                `json:"context"`
+	Cluster       string                `json:"cluster"`
+	Server        string                `json:"server,omitempty"`
+	Reachable     bool                  `json:"reachable"`
+	Error         string                `json:"error,omitempty"`
+	ServerVersion string                `json:"serverVersion,omitempty"`
+	Platform      string                `json:"platform,omitempty"`
+	NodeCount     *int64                `json:"nodeCount,omitempty"`
+	Metadata      *clustermeta.Metadata `json:"metadata,omitempty"`
+}
+
+// probeCluster fills in the server version, platform and node count of a context
+func probeCluster(ctx context.Context, config *api.Config, info *ClusterInfo) {
+	configCopy := config.DeepCopy()
+	configCopy.CurrentContext = info.Context
+	restConfig, err := clientcmd.NewDefaultClientConfig(*configCopy, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		info.Error = "Failed to create client config: " + err.Error()
+		return
+	}
+	restConfig.Timeout = clusterProbeTimeout
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		info.Error = "Failed to create Kubernetes client: " + err.Error()
+		return
+	}
+
+	version, err := client.Discovery().ServerVersion()
+	if err != nil {
+		info.Error = "Cluster not reachable: " + err.Error()
+		return
+	}
+	info.Reachable = true
+	info.ServerVersion = version.GitVersion
+
+	// One node is enough to detect the platform; the remaining count comes with the page
+	var providerID string
+	var nodeLabels map[string]string
+	if nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{Limit: 1}); err == nil {
+		count := int64(len(nodes.Items))
+		if nodes.RemainingItemCount != nil {
+			count += *nodes.RemainingItemCount
+		}
+		info.NodeCount = &count
+		if len(nodes.Items) > 0 {
+			providerID = nodes.Items[0].Spec.ProviderID
+			nodeLabels = nodes.Items[0].Labels
+		}
+	}
+	info.Platform = clustermeta.DetectPlatform(version.GitVersion, providerID, nodeLabels)
+}
+
+// visibleConfigs returns the kubeconfigs the caller may see: all persistent ones and its own session-only ones
+func (h *KubeConfigHandler) visibleConfigs(c *gin.Context) map[string]*storage.KubeConfig {
+	sessionID := h.sessionID(c)
+	now := time.Now()
+	visible := map[string]*storage.KubeConfig{}
+	for id, metadata := range h.store.ListKubeConfigs() {
+		if metadata.VisibleTo(sessionID, now) {
+			visible[id] = metadata
+		}
+	}
+	return visible
+}
+
+// GetClusterInfo returns every stored kubeconfig context with live cluster details and saved metadata
+// @Summary Get cluster details for the cluster switcher
+// @Description Returns every kubeconfig context with its server version, detected platform (EKS, GKE, AKS, k3s, ...), node count, reachability and the user-edited display name, color and tags. Contexts are probed concurrently with a short timeout; pass probe=false to skip probing.
+// @Tags Configuration
+// @Produce json
+// @Param probe query bool false "Probe clusters for version, platform and node count (default true)"
+// @Success 200 {array} ClusterInfo "Cluster details"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/app/config/clusters [get]
+func (h *KubeConfigHandler) GetClusterInfo(c *gin.Context) {
+	saved, err := h.clusterMeta.List()
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to load cluster metadata")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	probe := c.DefaultQuery("probe", "true") != "false"
+
+	infos := []*ClusterInfo{}
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, 5)
+	for configID, metadata := range h.visibleConfigs(c) {
+		config, err := h.store.GetKubeConfig(configID)
+		if err != nil {
+			h.logger.WithError(err).WithField("config_id", configID).Error("Failed to get kubeconfig for cluster details")
+			continue
+		}
+		for contextName, kubeContext := range config.Contexts {
+			info := &ClusterInfo{
+				ConfigID:   configID,
+				ConfigName: metadata.Name,
+				Context:    contextName,
+				Cluster:    kubeContext.Cluster,
+				Metadata:   clustermeta.Lookup(saved, configID, contextName),
+			}
+			if cluster, ok := config.Clusters[kubeContext.Cluster]; ok {
+				info.Server = cluster.Server
+			}
+			infos = append(infos, info)
+			if !probe {
+				continue
+			}
+
+			wg.Add(1)
+			go func(config *api.Config, info *ClusterInfo) {
+				defer wg.Done()
+				semaphore <- struct{}{}
+				defer func() { <-semaphore }()
+				ctx, cancel := context.WithTimeout(c.Request.Context(), clusterProbeTimeout)
+				defer cancel()
+				probeCluster(ctx, config, info)
+			}(config, info)
+		}
+	}
+	wg.Wait()
+
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].ConfigName != infos[j].ConfigName {
+			return infos[i].ConfigName < infos[j].ConfigName
+		}
+		return infos[i].Context < infos[j].Context
+	})
+	c.JSON(http.StatusOK, infos)
+}
+
+// UpdateClusterMetadata saves the display name, color and tags of a kubeconfig context
+// @Summary Update cluster metadata
+// @Description Saves the display name, color and tags shown for a kubeconfig context in the cluster switcher
+// @Tags Configuration
+// @Accept json
+// @Produce json
+// @Param id path string true "Kubeconfig ID"
+// @Param context query string true "Context name"
+// @Param metadata body clustermeta.Metadata true "Cluster metadata"
+// @Success 200 {object} clustermeta.Metadata "Saved metadata"
+// @Failure 400 {object} map[string]interface{} "Bad request - invalid metadata"
+// @Failure 404 {object} map[string]interface{} "Kubeconfig or context not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/app/config/kubeconfigs/{id}/metadata [put]
+func (h *KubeConfigHandler) UpdateClusterMetadata(c *gin.Context) {
+	configID, contextName := c.Param("id"), c.Query("context")
+	if _, ok := h.visibleConfigs(c)[configID]; !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "kubeconfig not found"})
+		return
+	}
+	config, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if _, ok := config.Contexts[contextName]; !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "context not found in kubeconfig"})
+		return
+	}
+
+	var m clustermeta.Metadata
+	if err := c.ShouldBindJSON(&m); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	m.ConfigID, m.Context = configID, contextName
+	if err := m.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.clusterMeta.Save(&m); err != nil {
+		h.logger.WithError(err).WithField("config_id", configID).Error("Failed to save cluster metadata")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, m)
+}
+
+// DeleteClusterMetadata clears the display name, color and tags of a kubeconfig context
+// @Summary Delete cluster metadata
+// @Description Clears the saved display name, color and tags of a kubeconfig context
+// @Tags Configuration
+// @Produce json
+// @Param id path string true "Kubeconfig ID"
+// @Param context query string true "Context name"
+// @Success 200 {object} map[string]interface{} "Metadata cleared"
+// @Failure 404 {object} map[string]interface{} "Kubeconfig not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/v1/app/config/kubeconfigs/{id}/metadata [delete]
+func (h *KubeConfigHandler) DeleteClusterMetadata(c *gin.Context) {
+	configID := c.Param("id")
+	if _, ok := h.visibleConfigs(c)[configID]; !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "kubeconfig not found"})
+		return
+	}
+	if err := h.clusterMeta.Delete(configID, c.Query("context")); err != nil {
+		h.logger.WithError(err).WithField("config_id", configID).Error("Failed to delete cluster metadata")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Cluster metadata cleared"})
+}
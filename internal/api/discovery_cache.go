@@ -0,0 +1,35 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InvalidateDiscoveryCache drops the cached API discovery of a kubeconfig's clusters
+// @Summary Invalidate the API discovery cache
+// @Description Drops the cached API groups and REST mappings shared by apply, promote, compare, custom actions and custom resources, so CRDs installed or removed since discovery was cached are seen on the next request. Discovery is also refetched once the cache TTL (K8S_DISCOVERY_CACHE_TTL_SECONDS) passes. Without a cluster, every cluster of the kubeconfig is invalidated.
+// @Tags Configuration
+// @Produce json
+// @Param id path string true "Kubeconfig ID"
+// @Param cluster query string false "Cluster name (default all clusters of the kubeconfig)"
+// @Success 200 {object} map[string]interface{} "Number of cluster caches invalidated"
+// @Failure 404 {object} map[string]interface{} "Kubeconfig not found"
+// @Router /api/v1/app/config/kubeconfigs/{id}/discovery/invalidate [post]
+func (h *KubeConfigHandler) InvalidateDiscoveryCache(c *gin.Context) {
+	configID := c.Param("id")
+	if _, ok := h.visibleConfigs(c)[configID]; !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "kubeconfig not found"})
+		return
+	}
+	config, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	cluster := c.Query("cluster")
+	invalidated := h.clientFactory.InvalidateDiscovery(config, cluster)
+	h.logger.WithField("config_id", configID).WithField("cluster", cluster).WithField("invalidated", invalidated).Info("Invalidated API discovery cache")
+	c.JSON(http.StatusOK, gin.H{"invalidated": invalidated})
+}
@@ -39,7 +39,7 @@ func NewClusterRoleBindingsHandler(store *storage.KubeConfigStore, clientFactory
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *ClusterRoleBindingsHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *ClusterRoleBindingsHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -122,7 +122,7 @@ func (h *ClusterRoleBindingsHandler) GetClusterRoleBinding(c *gin.Context) {
 	// Check if this is an SSE request (EventSource expects SSE format)
 	acceptHeader := c.GetHeader("Accept")
 	if acceptHeader == "text/event-stream" {
-		h.sseHandler.SendSSEResponse(c, clusterRoleBinding)
+		h.sseHandler.SendSSEDetailResponse(c, clusterRoleBinding, clusterRoleBinding, client.RbacV1().ClusterRoleBindings().Watch)
 		return
 	}
 
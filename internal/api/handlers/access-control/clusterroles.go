@@ -44,7 +44,7 @@ func NewClusterRolesHandler(store *storage.KubeConfigStore, clientFactory *k8s.C
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *ClusterRolesHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *ClusterRolesHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -154,7 +154,7 @@ func (h *ClusterRolesHandler) GetClusterRole(c *gin.Context) {
 	// Check if this is an SSE request (EventSource expects SSE format)
 	acceptHeader := c.GetHeader("Accept")
 	if acceptHeader == "text/event-stream" {
-		h.sseHandler.SendSSEResponse(c, clusterRole)
+		h.sseHandler.SendSSEDetailResponse(c, clusterRole, clusterRole, client.RbacV1().ClusterRoles().Watch)
 		return
 	}
 
@@ -1,6 +1,7 @@
 package access_control
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
@@ -40,7 +41,7 @@ func NewRoleBindingsHandler(store *storage.KubeConfigStore, clientFactory *k8s.C
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *RoleBindingsHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *RoleBindingsHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -72,15 +73,14 @@ func (h *RoleBindingsHandler) GetRoleBindingsSSE(c *gin.Context) {
 
 	// Function to fetch and transform role bindings data
 	fetchRoleBindings := func() (interface{}, error) {
-		namespace := c.Query("namespace")
-		var roleBindingList *rbacV1.RoleBindingList
-		var err error
-
-		if namespace != "" {
-			roleBindingList, err = client.RbacV1().RoleBindings(namespace).List(c.Request.Context(), metav1.ListOptions{})
-		} else {
-			roleBindingList, err = client.RbacV1().RoleBindings("").List(c.Request.Context(), metav1.ListOptions{})
-		}
+		items, err := utils.ListInNamespaces(c.Request.Context(), utils.RequestedNamespaces(c), func(ctx context.Context, namespace string) ([]rbacV1.RoleBinding, error) {
+			list, err := client.RbacV1().RoleBindings(namespace).List(ctx, utils.ListOptions(c))
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		roleBindingList := &rbacV1.RoleBindingList{Items: items}
 
 		if err != nil {
 			return nil, err
@@ -133,7 +133,7 @@ func (h *RoleBindingsHandler) GetRoleBinding(c *gin.Context) {
 	// Check if this is an SSE request (EventSource expects SSE format)
 	acceptHeader := c.GetHeader("Accept")
 	if acceptHeader == "text/event-stream" {
-		h.sseHandler.SendSSEResponse(c, roleBinding)
+		h.sseHandler.SendSSEDetailResponse(c, roleBinding, roleBinding, client.RbacV1().RoleBindings(namespace).Watch)
 		return
 	}
 
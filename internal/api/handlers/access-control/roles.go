@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -45,7 +46,7 @@ func NewRolesHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFa
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *RolesHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *RolesHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -82,19 +83,22 @@ func (h *RolesHandler) GetRolesSSE(c *gin.Context) {
 
 	// Define the fetch function for periodic updates
 	fetchRoles := func() (interface{}, error) {
-		namespace := c.Query("namespace")
+		namespaces := utils.RequestedNamespaces(c)
+		namespace := strings.Join(namespaces, ",")
 		// Start child span for Kubernetes API call with timeout
 		ctxWithTimeout, cancel := context.WithTimeout(ctx, 30*time.Second)
 		defer cancel()
 		_, k8sSpan := h.tracingHelper.StartKubernetesAPISpan(ctxWithTimeout, "list", "roles", namespace)
 		defer k8sSpan.End()
 
-		var roleList *rbacV1.RoleList
-		if namespace != "" {
-			roleList, err = client.RbacV1().Roles(namespace).List(ctxWithTimeout, metav1.ListOptions{})
-		} else {
-			roleList, err = client.RbacV1().Roles("").List(ctxWithTimeout, metav1.ListOptions{})
-		}
+		items, err := utils.ListInNamespaces(ctxWithTimeout, namespaces, func(ctx context.Context, namespace string) ([]rbacV1.Role, error) {
+			list, err := client.RbacV1().Roles(namespace).List(ctx, utils.ListOptions(c))
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		roleList := &rbacV1.RoleList{Items: items}
 
 		if err != nil {
 			h.tracingHelper.RecordError(k8sSpan, err, "Failed to list roles")
@@ -163,7 +167,7 @@ func (h *RolesHandler) GetRole(c *gin.Context) {
 	// Check if this is an SSE request (EventSource expects SSE format)
 	acceptHeader := c.GetHeader("Accept")
 	if acceptHeader == "text/event-stream" {
-		h.sseHandler.SendSSEResponse(c, role)
+		h.sseHandler.SendSSEDetailResponse(c, role, role, client.RbacV1().Roles(namespace).Watch)
 		return
 	}
 
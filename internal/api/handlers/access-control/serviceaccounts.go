@@ -1,6 +1,7 @@
 package access_control
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
@@ -40,7 +41,7 @@ func NewServiceAccountsHandler(store *storage.KubeConfigStore, clientFactory *k8
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *ServiceAccountsHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *ServiceAccountsHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -71,6 +72,12 @@ func (h *ServiceAccountsHandler) getClientAndConfig(c *gin.Context) (*kubernetes
 // @Param config query string true "Kubernetes config ID"
 // @Param cluster query string false "Cluster name"
 // @Param namespace query string false "Namespace name (empty for all namespaces)"
+// @Param namespaces query string false "Comma-separated namespaces to list across, instead of namespace"
+// @Param namespaceGroup query string false "ID of a saved namespace group to list across"
+// @Param fields query string false "Comma-separated response fields to return, e.g. name,status,restarts; name, namespace and uid are always included"
+// @Param labelSelector query string false "Kubernetes label selector, e.g. app=web,tier!=cache"
+// @Param sort query string false "Response field to sort by, e.g. name or -restarts for descending"
+// @Param view query string false "ID of a saved view supplying namespaces, labelSelector, sort and fields"
 // @Success 200 {array} types.ServiceAccountListResponse "Stream of transformed Service Accounts or JSON array"
 // @Failure 400 {object} map[string]string "Bad request"
 // @Failure 500 {object} map[string]string "Internal server error"
@@ -87,15 +94,14 @@ func (h *ServiceAccountsHandler) GetServiceAccountsSSE(c *gin.Context) {
 
 	// Function to fetch and transform service accounts data
 	fetchServiceAccounts := func() (interface{}, error) {
-		namespace := c.Query("namespace")
-		var serviceAccountList *v1.ServiceAccountList
-		var err error
-
-		if namespace != "" {
-			serviceAccountList, err = client.CoreV1().ServiceAccounts(namespace).List(c.Request.Context(), metav1.ListOptions{})
-		} else {
-			serviceAccountList, err = client.CoreV1().ServiceAccounts("").List(c.Request.Context(), metav1.ListOptions{})
-		}
+		items, err := utils.ListInNamespaces(c.Request.Context(), utils.RequestedNamespaces(c), func(ctx context.Context, namespace string) ([]v1.ServiceAccount, error) {
+			list, err := client.CoreV1().ServiceAccounts(namespace).List(ctx, utils.ListOptions(c))
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		serviceAccountList := &v1.ServiceAccountList{Items: items}
 
 		if err != nil {
 			return nil, err
@@ -132,7 +138,7 @@ func (h *ServiceAccountsHandler) GetServiceAccountsSSE(c *gin.Context) {
 	}
 
 	// For non-SSE requests, return JSON
-	c.JSON(http.StatusOK, initialData)
+	h.sseHandler.SendJSON(c, initialData)
 }
 
 // GetServiceAccount returns a specific service account
@@ -172,7 +178,7 @@ func (h *ServiceAccountsHandler) GetServiceAccount(c *gin.Context) {
 	// Check if this is an SSE request (EventSource expects SSE format)
 	acceptHeader := c.GetHeader("Accept")
 	if acceptHeader == "text/event-stream" {
-		h.sseHandler.SendSSEResponse(c, serviceAccount)
+		h.sseHandler.SendSSEDetailResponse(c, serviceAccount, serviceAccount, client.CoreV1().ServiceAccounts(namespace).Watch)
 		return
 	}
 
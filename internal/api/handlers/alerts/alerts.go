@@ -0,0 +1,159 @@
+package alerts
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/alerts"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AlertsHandler receives Alertmanager webhooks and serves the alerts relevant to an object
+type AlertsHandler struct {
+	alerts *alerts.Store
+	logger *logger.Logger
+}
+
+// AcknowledgeRequest acknowledges a firing alert
+type AcknowledgeRequest struct {
+	By      string `json:"by"`
+	Comment string `json:"comment"`
+}
+
+// NewAlertsHandler creates a new external alerts handler
+func NewAlertsHandler(store *alerts.Store, log *logger.Logger) *AlertsHandler {
+	return &AlertsHandler{
+		alerts: store,
+		logger: log,
+	}
+}
+
+// ReceiveWebhook stores the alerts from an Alertmanager webhook notification
+// @Summary Receive Alertmanager webhook
+// @Description Receives an Alertmanager webhook payload and stores its alerts, mapping namespace, workload, pod and node labels to the objects they concern. Point an Alertmanager webhook receiver at this URL with the config (and optionally cluster) the Alertmanager monitors; a "cluster" alert label overrides the cluster.
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubeconfig ID the alerts belong to"
+// @Param cluster query string false "Cluster name the alerts belong to"
+// @Param payload body alerts.WebhookMessage true "Alertmanager webhook payload"
+// @Success 200 {object} map[string]interface{} "Number of alerts received"
+// @Failure 400 {object} map[string]string "Bad request - invalid payload"
+// @Router /api/v1/alerts/webhook [post]
+func (h *AlertsHandler) ReceiveWebhook(c *gin.Context) {
+	configID := c.Query("config")
+	if configID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "config parameter is required"})
+		return
+	}
+	var msg alerts.WebhookMessage
+	if err := c.ShouldBindJSON(&msg); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook payload: " + err.Error()})
+		return
+	}
+
+	stored := h.alerts.Ingest(&msg, configID, c.Query("cluster"))
+	h.logger.WithField("receiver", msg.Receiver).
+		WithField("status", msg.Status).
+		WithField("alerts", len(msg.Alerts)).
+		Debug("Received Alertmanager webhook")
+	c.JSON(http.StatusOK, gin.H{"received": len(msg.Alerts), "stored": stored})
+}
+
+// ListAlerts returns received alerts, optionally only those relevant to one object
+// @Summary List external alerts
+// @Description Lists alerts received from Alertmanager. With kind and name only alerts about that object are returned: alerts labelled with the workload, or with a pod or node belonging to it. Firing unacknowledged alerts are listed first.
+// @Tags Alerts
+// @Produce json
+// @Param config query string false "Kubeconfig ID"
+// @Param cluster query string false "Cluster name"
+// @Param namespace query string false "Namespace"
+// @Param kind query string false "Object kind (Deployment, StatefulSet, DaemonSet, Job, CronJob, Pod, Node)"
+// @Param name query string false "Object name, required with kind"
+// @Param status query string false "firing (default), resolved or all"
+// @Success 200 {array} alerts.Alert "Alerts"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Security BearerAuth
+// @Router /api/v1/alerts [get]
+func (h *AlertsHandler) ListAlerts(c *gin.Context) {
+	filter := alerts.Filter{
+		ConfigID:  c.Query("config"),
+		Cluster:   c.Query("cluster"),
+		Namespace: c.Query("namespace"),
+		Kind:      c.Query("kind"),
+		Name:      c.Query("name"),
+		Status:    strings.ToLower(c.Query("status")),
+	}
+	if filter.Kind != "" && filter.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required with kind"})
+		return
+	}
+	switch filter.Status {
+	case "", alerts.StatusFiring, alerts.StatusResolved, "all":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be firing, resolved or all"})
+		return
+	}
+	c.JSON(http.StatusOK, h.alerts.List(filter))
+}
+
+// AcknowledgeAlert marks an alert as acknowledged
+// @Summary Acknowledge alert
+// @Description Acknowledges an alert so it is shown as handled. The acknowledgement is cleared if the alert fires again after resolving.
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Param id path string true "Alert ID"
+// @Param request body AcknowledgeRequest false "Who acknowledged the alert and why"
+// @Success 200 {object} alerts.Alert "Acknowledged alert"
+// @Failure 404 {object} map[string]string "Alert not found"
+// @Security BearerAuth
+// @Router /api/v1/alerts/{id}/acknowledge [post]
+func (h *AlertsHandler) AcknowledgeAlert(c *gin.Context) {
+	var req AcknowledgeRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+			return
+		}
+	}
+	alert, err := h.alerts.Acknowledge(c.Param("id"), &alerts.Acknowledgement{
+		By:      req.By,
+		Comment: req.Comment,
+		At:      time.Now(),
+	})
+	h.respond(c, alert, err)
+}
+
+// UnacknowledgeAlert clears an alert's acknowledgement
+// @Summary Unacknowledge alert
+// @Description Clears the acknowledgement of an alert
+// @Tags Alerts
+// @Produce json
+// @Param id path string true "Alert ID"
+// @Success 200 {object} alerts.Alert "Alert"
+// @Failure 404 {object} map[string]string "Alert not found"
+// @Security BearerAuth
+// @Router /api/v1/alerts/{id}/acknowledge [delete]
+func (h *AlertsHandler) UnacknowledgeAlert(c *gin.Context) {
+	alert, err := h.alerts.Acknowledge(c.Param("id"), nil)
+	h.respond(c, alert, err)
+}
+
+func (h *AlertsHandler) respond(c *gin.Context, alert *alerts.Alert, err error) {
+	if errors.Is(err, storage.ErrDocumentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "alert not found"})
+		return
+	}
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to update alert acknowledgement")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, alert)
+}
@@ -0,0 +1,189 @@
+package apitokens
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/apitokens"
+	"github.com/Facets-cloud/kube-dash/internal/audit"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TokensHandler manages API tokens used by CI jobs and scripts
+type TokensHandler struct {
+	tokens  *apitokens.Store
+	store   *storage.KubeConfigStore
+	auditor *audit.Recorder
+	logger  *logger.Logger
+}
+
+// NewTokensHandler creates a new API token handler
+func NewTokensHandler(tokens *apitokens.Store, store *storage.KubeConfigStore, auditor *audit.Recorder, log *logger.Logger) *TokensHandler {
+	return &TokensHandler{
+		tokens:  tokens,
+		store:   store,
+		auditor: auditor,
+		logger:  log,
+	}
+}
+
+// CreateTokenRequest describes a new API token
+type CreateTokenRequest struct {
+	Name          string                   `json:"name" binding:"required"`
+	Description   string                   `json:"description"`
+	Kind          string                   `json:"kind"` // personal (default) or service
+	Owner         string                   `json:"owner"`
+	ReadOnly      bool                     `json:"readOnly"`
+	Clusters      []apitokens.ClusterScope `json:"clusters"`
+	ExpiresInDays int                      `json:"expiresInDays"` // 0 means the token never expires
+
+	// RequireElevation makes exec, debug pods and deletes need an approved access request
+	RequireElevation bool `json:"requireElevation"`
+}
+
+// CreateTokenResponse returns the new token along with its secret, which is shown only once
+type CreateTokenResponse struct {
+	Token  apitokens.Token `json:"token"`
+	Secret string          `json:"secret"`
+}
+
+func (h *TokensHandler) tokenError(c *gin.Context, err error) {
+	if errors.Is(err, storage.ErrDocumentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "API token not found"})
+		return
+	}
+	h.logger.WithError(err).Error("API token operation failed")
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+func (h *TokensHandler) record(c *gin.Context, action string, token *apitokens.Token) {
+	h.auditor.Record(audit.Event{
+		Action:     action,
+		Outcome:    audit.OutcomeSuccess,
+		RemoteAddr: c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+		Resource:   "APIToken/" + token.Name,
+		Details:    map[string]string{"token": token.ID, "kind": token.Kind, "owner": token.Owner},
+	})
+}
+
+// ListTokens returns all API tokens without their secrets
+// @Summary List API tokens
+// @Description Lists personal and service API tokens with their scopes and last use. Secrets are never returned.
+// @Tags API Tokens
+// @Produce json
+// @Success 200 {array} apitokens.Token "API tokens"
+// @Security BearerAuth
+// @Router /api/v1/auth/tokens [get]
+func (h *TokensHandler) ListTokens(c *gin.Context) {
+	c.JSON(http.StatusOK, h.tokens.List())
+}
+
+// CreateToken creates an API token
+// @Summary Create API token
+// @Description Creates a personal or service API token, optionally read-only, scoped to specific clusters, or requiring approved elevated access for exec and deletes. Send it as "Authorization: Bearer <secret>". The secret is returned only in this response.
+// @Tags API Tokens
+// @Accept json
+// @Produce json
+// @Param token body CreateTokenRequest true "Token definition"
+// @Success 201 {object} CreateTokenResponse "Created token and its secret"
+// @Failure 400 {object} map[string]string "Bad request - invalid token definition"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/auth/tokens [post]
+func (h *TokensHandler) CreateToken(c *gin.Context) {
+	var req CreateTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if req.Kind == "" {
+		req.Kind = apitokens.KindPersonal
+	}
+	if req.ExpiresInDays < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expiresInDays must not be negative"})
+		return
+	}
+	for _, scope := range req.Clusters {
+		if _, err := h.store.GetKubeConfig(scope.ConfigID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown config in cluster scope: " + scope.ConfigID})
+			return
+		}
+	}
+
+	token := apitokens.Token{
+		Name:        req.Name,
+		Description: req.Description,
+		Kind:        req.Kind,
+		Owner:       req.Owner,
+		ReadOnly:    req.ReadOnly,
+		Clusters:    req.Clusters,
+
+		RequireElevation: req.RequireElevation,
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		token.ExpiresAt = &expiresAt
+	}
+	if err := token.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	secret, err := h.tokens.Create(&token)
+	if err != nil {
+		h.tokenError(c, err)
+		return
+	}
+	h.record(c, "apitoken.create", &token)
+	c.JSON(http.StatusCreated, CreateTokenResponse{Token: token, Secret: secret})
+}
+
+// RevokeToken revokes an API token
+// @Summary Revoke API token
+// @Description Revokes an API token so it is rejected from now on. The token stays listed as revoked.
+// @Tags API Tokens
+// @Produce json
+// @Param id path string true "Token ID"
+// @Success 200 {object} apitokens.Token "Revoked token"
+// @Failure 404 {object} map[string]string "Token not found"
+// @Security BearerAuth
+// @Router /api/v1/auth/tokens/{id}/revoke [post]
+func (h *TokensHandler) RevokeToken(c *gin.Context) {
+	token, err := h.tokens.Revoke(c.Param("id"))
+	if err != nil {
+		h.tokenError(c, err)
+		return
+	}
+	h.record(c, "apitoken.revoke", token)
+	c.JSON(http.StatusOK, token)
+}
+
+// DeleteToken deletes an API token
+// @Summary Delete API token
+// @Description Deletes an API token permanently, revoking it if it was still active
+// @Tags API Tokens
+// @Produce json
+// @Param id path string true "Token ID"
+// @Success 200 {object} map[string]string "Token deleted"
+// @Failure 404 {object} map[string]string "Token not found"
+// @Security BearerAuth
+// @Router /api/v1/auth/tokens/{id} [delete]
+func (h *TokensHandler) DeleteToken(c *gin.Context) {
+	id := c.Param("id")
+	token, err := h.tokens.Get(id)
+	if err != nil {
+		h.tokenError(c, err)
+		return
+	}
+	if err := h.tokens.Delete(id); err != nil {
+		h.tokenError(c, err)
+		return
+	}
+	h.record(c, "apitoken.delete", token)
+	c.JSON(http.StatusOK, gin.H{"message": "API token deleted"})
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,8 +14,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
-	"k8s.io/client-go/discovery/cached/memory"
-	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/yaml"
 )
@@ -88,8 +88,20 @@ func cleanObjectForPatch(obj *unstructured.Unstructured) {
 // ApplyResources handles applying one or more Kubernetes resources provided as YAML.
 // It performs basic validation and uses server-side apply for idempotent creation/update.
 // Request: multipart/form-data with field "yaml" containing one or more YAML documents (--- separated)
-// Query params: config, cluster
+// Query params: config, cluster, strict
+// Workloads are linted for best-practice issues first. Findings are returned as warnings, or
+// block the whole apply when the server lint policy is strict or the request sets strict=true.
+// The YAML editor saves through this endpoint, so edits are linted the same way.
+// An "archive" file field holding a zip or tar of manifests is applied instead of the yaml field;
+// see applyArchive for its ordering and the stopOnError query parameter.
+// The state of every applied object before the apply is recorded as an apply set, whose ID is
+// returned as applySetId; see RollbackApplySet.
 func (h *ResourcesHandler) ApplyResources(c *gin.Context) {
+	if archive, err := c.FormFile("archive"); err == nil {
+		h.applyArchive(c, archive)
+		return
+	}
+
 	// Read YAML content from form field
 	yamlContent := c.PostForm("yaml")
 	if strings.TrimSpace(yamlContent) == "" {
@@ -105,38 +117,26 @@ func (h *ResourcesHandler) ApplyResources(c *gin.Context) {
 		return
 	}
 
-	clientset, _, err := h.getClientAndConfig(c)
+	_, config, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get clientset for apply")
 		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error(), "code": http.StatusBadRequest})
 		return
 	}
-
-	disco := clientset.Discovery()
-	restMapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(disco))
+	restMapper, _, err := h.clientFactory.GetRESTMapperForConfig(config, c.Query("cluster"))
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get REST mapper for apply")
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error(), "code": http.StatusBadRequest})
+		return
+	}
 
 	// Prepare decoder for multi-document YAML
 	decoder := utilyaml.NewYAMLOrJSONDecoder(strings.NewReader(yamlContent), 4096)
 
-	type failure struct {
-		Name      string `json:"name"`
-		Namespace string `json:"namespace,omitempty"`
-		Kind      string `json:"kind,omitempty"`
-		Group     string `json:"group,omitempty"`
-		Version   string `json:"version,omitempty"`
-		Message   string `json:"message"`
-	}
-	var failures []failure
+	var failures []applyFailure
 	var appliedCount int
-	type appliedResource struct {
-		Name      string `json:"name"`
-		Namespace string `json:"namespace,omitempty"`
-		Kind      string `json:"kind"`
-		Group     string `json:"group"`
-		Version   string `json:"version"`
-		Resource  string `json:"resource"`
-	}
 	var appliedResources []appliedResource
+	var objects []*unstructured.Unstructured
 
 	for {
 		// Decode each document into a map first to allow empty docs to be skipped
@@ -145,7 +145,7 @@ func (h *ResourcesHandler) ApplyResources(c *gin.Context) {
 			if err == io.EOF {
 				break
 			}
-			failures = append(failures, failure{Message: fmt.Sprintf("failed to decode YAML: %v", err)})
+			failures = append(failures, applyFailure{Message: fmt.Sprintf("failed to decode YAML: %v", err)})
 			break
 		}
 
@@ -155,120 +155,43 @@ func (h *ResourcesHandler) ApplyResources(c *gin.Context) {
 		}
 
 		obj := &unstructured.Unstructured{Object: raw}
-		gvk := obj.GroupVersionKind()
 
 		// Clean the object to remove fields that shouldn't be in patches
 		cleanObjectForPatch(obj)
+		objects = append(objects, obj)
+	}
 
-		// Enhanced validation for required fields
-		if gvk.Empty() || gvk.Kind == "" || gvk.Version == "" {
-			missingFields := []string{}
-			if gvk.Kind == "" {
-				missingFields = append(missingFields, "kind")
-			}
-			if gvk.Version == "" {
-				missingFields = append(missingFields, "apiVersion")
-			}
-
-			failures = append(failures, failure{
-				Name:    obj.GetName(),
-				Message: fmt.Sprintf("missing required fields: %s", strings.Join(missingFields, ", ")),
-			})
-			continue
-		}
-
-		// Validate metadata and name
-		if obj.GetName() == "" {
-			failures = append(failures, failure{
-				Name:    "unknown",
-				Kind:    gvk.Kind,
-				Group:   gvk.Group,
-				Version: gvk.Version,
-				Message: "missing required field: metadata.name",
-			})
-			continue
-		}
-
-		mapping, mapErr := restMapper.RESTMapping(schema.GroupKind{Group: gvk.Group, Kind: gvk.Kind}, gvk.Version)
-		if mapErr != nil {
-			failures = append(failures, failure{
-				Name:    obj.GetName(),
-				Kind:    gvk.Kind,
-				Group:   gvk.Group,
-				Version: gvk.Version,
-				Message: fmt.Sprintf("failed to resolve GVK to resource: %v", mapErr),
-			})
-			continue
-		}
-
-		// Determine resource interface based on scope
-		var ri dynamicResourceInterface
-		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
-			ns := obj.GetNamespace()
-			if strings.TrimSpace(ns) == "" {
-				// Default to "default" namespace when not provided
-				ns = "default"
-				obj.SetNamespace(ns)
-			}
-			ri = dynamicResourceInterface{namespaced: true, ns: ns, resource: mapping.Resource}
-		} else {
-			ri = dynamicResourceInterface{namespaced: false, resource: mapping.Resource}
-		}
-
-		// Marshal object back to YAML for server-side apply
-		payload, mErr := yaml.Marshal(obj.Object)
-		if mErr != nil {
-			failures = append(failures, failure{
-				Name:    obj.GetName(),
-				Kind:    gvk.Kind,
-				Group:   gvk.Group,
-				Version: gvk.Version,
-				Message: fmt.Sprintf("failed to marshal object to YAML: %v", mErr),
-			})
-			continue
-		}
-
-		// Perform server-side apply (idempotent)
-		var patchErr error
-		if ri.namespaced {
-			_, patchErr = dynamicClient.Resource(ri.resource).Namespace(ri.ns).Patch(
-				c.Request.Context(),
-				obj.GetName(),
-				types.ApplyPatchType,
-				payload,
-				metav1.PatchOptions{FieldManager: "kube-dash", Force: ptr.To(true)},
-			)
-		} else {
-			_, patchErr = dynamicClient.Resource(ri.resource).Patch(
-				c.Request.Context(),
-				obj.GetName(),
-				types.ApplyPatchType,
-				payload,
-				metav1.PatchOptions{FieldManager: "kube-dash", Force: ptr.To(true)},
-			)
-		}
+	warnings := []LintWarning{}
+	for _, obj := range objects {
+		warnings = append(warnings, h.linter.lint(obj)...)
+	}
+	strict := h.linter.mode == lintModeStrict || (h.linter.mode != lintModeOff && c.Query("strict") == "true")
+	if strict && len(warnings) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"message":  "blocked by lint policy",
+			"code":     http.StatusUnprocessableEntity,
+			"warnings": warnings,
+			"applied":  0,
+		})
+		return
+	}
 
-		if patchErr != nil {
-			failures = append(failures, failure{
-				Name:      obj.GetName(),
-				Namespace: obj.GetNamespace(),
-				Kind:      gvk.Kind,
-				Group:     gvk.Group,
-				Version:   gvk.Version,
-				Message:   patchErr.Error(),
-			})
+	recorder := h.newApplySetRecorder(c, dynamicClient, restMapper)
+	for _, obj := range objects {
+		applied, failure := recorder.apply(c.Request.Context(), obj)
+		if failure != nil {
+			failures = append(failures, *failure)
 			continue
 		}
 
 		appliedCount++
-		appliedResources = append(appliedResources, appliedResource{
-			Name:      obj.GetName(),
-			Namespace: obj.GetNamespace(),
-			Kind:      gvk.Kind,
-			Group:     gvk.Group,
-			Version:   gvk.Version,
-			Resource:  mapping.Resource.Resource,
-		})
+		appliedResources = append(appliedResources, *applied)
+	}
+
+	// Record the prior state of what was applied, so the apply can be rolled back
+	applySetID, err := recorder.save()
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to record apply set")
 	}
 
 	if len(failures) > 0 {
@@ -279,6 +202,8 @@ func (h *ResourcesHandler) ApplyResources(c *gin.Context) {
 			"applied":          appliedCount,
 			"failed":           len(failures),
 			"appliedResources": appliedResources,
+			"warnings":         warnings,
+			"applySetId":       applySetID,
 		})
 		return
 	}
@@ -288,9 +213,140 @@ func (h *ResourcesHandler) ApplyResources(c *gin.Context) {
 		"message":          "applied",
 		"applied":          appliedCount,
 		"appliedResources": appliedResources,
+		"warnings":         warnings,
+		"applySetId":       applySetID,
 	})
 }
 
+// applyFailure describes a document that could not be applied
+type applyFailure struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Kind      string `json:"kind,omitempty"`
+	Group     string `json:"group,omitempty"`
+	Version   string `json:"version,omitempty"`
+	Message   string `json:"message"`
+}
+
+// appliedResource describes a successfully applied object
+type appliedResource struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Kind      string `json:"kind"`
+	Group     string `json:"group"`
+	Version   string `json:"version"`
+	Resource  string `json:"resource"`
+	// ResourceVersion is the object's resourceVersion after the apply
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+}
+
+// applyObject validates a single object and server-side applies it; with dryRun the
+// API server validates and admits the object without persisting it
+func applyObject(ctx context.Context, dynamicClient dynamic.Interface, restMapper meta.RESTMapper, obj *unstructured.Unstructured, dryRun bool) (*appliedResource, *applyFailure) {
+	gvk := obj.GroupVersionKind()
+
+	// Enhanced validation for required fields
+	if gvk.Empty() || gvk.Kind == "" || gvk.Version == "" {
+		missingFields := []string{}
+		if gvk.Kind == "" {
+			missingFields = append(missingFields, "kind")
+		}
+		if gvk.Version == "" {
+			missingFields = append(missingFields, "apiVersion")
+		}
+
+		return nil, &applyFailure{
+			Name:    obj.GetName(),
+			Message: fmt.Sprintf("missing required fields: %s", strings.Join(missingFields, ", ")),
+		}
+	}
+
+	// Validate metadata and name
+	if obj.GetName() == "" {
+		return nil, &applyFailure{
+			Name:    "unknown",
+			Kind:    gvk.Kind,
+			Group:   gvk.Group,
+			Version: gvk.Version,
+			Message: "missing required field: metadata.name",
+		}
+	}
+
+	mapping, mapErr := restMapper.RESTMapping(schema.GroupKind{Group: gvk.Group, Kind: gvk.Kind}, gvk.Version)
+	if mapErr != nil {
+		return nil, &applyFailure{
+			Name:    obj.GetName(),
+			Kind:    gvk.Kind,
+			Group:   gvk.Group,
+			Version: gvk.Version,
+			Message: fmt.Sprintf("failed to resolve GVK to resource: %v", mapErr),
+		}
+	}
+
+	// Determine resource interface based on scope
+	var ri dynamicResourceInterface
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		ns := obj.GetNamespace()
+		if strings.TrimSpace(ns) == "" {
+			// Default to "default" namespace when not provided
+			ns = "default"
+			obj.SetNamespace(ns)
+		}
+		ri = dynamicResourceInterface{namespaced: true, ns: ns, resource: mapping.Resource}
+	} else {
+		ri = dynamicResourceInterface{namespaced: false, resource: mapping.Resource}
+	}
+
+	// Marshal object back to YAML for server-side apply
+	payload, mErr := yaml.Marshal(obj.Object)
+	if mErr != nil {
+		return nil, &applyFailure{
+			Name:    obj.GetName(),
+			Kind:    gvk.Kind,
+			Group:   gvk.Group,
+			Version: gvk.Version,
+			Message: fmt.Sprintf("failed to marshal object to YAML: %v", mErr),
+		}
+	}
+
+	// Perform server-side apply (idempotent)
+	opts := metav1.PatchOptions{FieldManager: "kube-dash", Force: ptr.To(true)}
+	if dryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+	var patched *unstructured.Unstructured
+	var patchErr error
+	if ri.namespaced {
+		patched, patchErr = dynamicClient.Resource(ri.resource).Namespace(ri.ns).Patch(ctx, obj.GetName(), types.ApplyPatchType, payload, opts)
+	} else {
+		patched, patchErr = dynamicClient.Resource(ri.resource).Patch(ctx, obj.GetName(), types.ApplyPatchType, payload, opts)
+	}
+
+	if patchErr != nil {
+		return nil, &applyFailure{
+			Name:      obj.GetName(),
+			Namespace: obj.GetNamespace(),
+			Kind:      gvk.Kind,
+			Group:     gvk.Group,
+			Version:   gvk.Version,
+			Message:   patchErr.Error(),
+		}
+	}
+
+	applied := &appliedResource{
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+		Kind:      gvk.Kind,
+		Group:     gvk.Group,
+		Version:   gvk.Version,
+		Resource:  mapping.Resource.Resource,
+	}
+	if patched != nil && !dryRun {
+		applied.ResourceVersion = patched.GetResourceVersion()
+	}
+	return applied, nil
+}
+
 type dynamicResourceInterface struct {
 	namespaced bool
 	ns         string
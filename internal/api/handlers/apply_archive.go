@@ -0,0 +1,441 @@
+package handlers
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+const (
+	// maxArchiveBytes bounds the size of an uploaded archive
+	maxArchiveBytes = 32 << 20
+	// maxArchiveFiles bounds the number of manifest files read from an archive
+	maxArchiveFiles = 1000
+	// maxExtractedBytes bounds the total size of the manifests extracted from an archive
+	maxExtractedBytes = 64 << 20
+	// crdEstablishTimeout bounds waiting for applied CRDs to be served before their custom resources are applied
+	crdEstablishTimeout = 30 * time.Second
+)
+
+// Statuses of a document applied from an archive
+const (
+	documentApplied = "applied"
+	documentFailed  = "failed"
+	documentSkipped = "skipped" // not attempted after an earlier failure with stopOnError
+)
+
+// Apply order of an archive's documents; documents of the same rank keep their file order
+const (
+	rankNamespace = iota
+	rankCRD
+	rankPrerequisite // identities, configuration, RBAC and storage that workloads reference
+	rankBuiltin
+	rankCustomResource
+)
+
+// prerequisiteKinds are built-in kinds applied before other built-in kinds
+var prerequisiteKinds = map[string]bool{
+	"ServiceAccount":        true,
+	"Secret":                true,
+	"ConfigMap":             true,
+	"ResourceQuota":         true,
+	"LimitRange":            true,
+	"PriorityClass":         true,
+	"StorageClass":          true,
+	"PersistentVolume":      true,
+	"PersistentVolumeClaim": true,
+	"ClusterRole":           true,
+	"ClusterRoleBinding":    true,
+	"Role":                  true,
+	"RoleBinding":           true,
+	"NetworkPolicy":         true,
+}
+
+// errArchiveTooLarge is returned when an archive or its extracted manifests exceed the limits
+var errArchiveTooLarge = errors.New("archive is too large")
+
+// archiveFile is a manifest file read from an archive
+type archiveFile struct {
+	Name    string
+	Content []byte
+}
+
+// archiveDocument is one object of a manifest file
+type archiveDocument struct {
+	File   string
+	Index  int // position of the document in its file, from zero
+	Object *unstructured.Unstructured
+	Rank   int
+}
+
+// ArchiveDocumentResult is the outcome of one document of an uploaded archive
+type ArchiveDocumentResult struct {
+	Index     int    `json:"index"`
+	Kind      string `json:"kind,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Status    string `json:"status"`
+	Message   string `json:"message,omitempty"`
+}
+
+// ArchiveFileResult is the outcome of every document of one manifest file
+type ArchiveFileResult struct {
+	File      string                  `json:"file"`
+	Error     string                  `json:"error,omitempty"` // the file could not be decoded
+	Documents []ArchiveDocumentResult `json:"documents"`
+}
+
+// isManifestFile reports whether an archive entry is a YAML or JSON manifest, skipping hidden
+// files and directories such as .git or __MACOSX
+func isManifestFile(name string) bool {
+	for _, segment := range strings.Split(path.Clean(name), "/") {
+		if (strings.HasPrefix(segment, ".") && segment != ".") || segment == "__MACOSX" {
+			return false
+		}
+	}
+	switch strings.ToLower(path.Ext(name)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+// limitedFiles collects manifest files within the archive limits
+type limitedFiles struct {
+	files []archiveFile
+	total int64
+}
+
+func (l *limitedFiles) add(name string, r io.Reader) error {
+	if len(l.files) >= maxArchiveFiles {
+		return fmt.Errorf("archive has more than %d manifest files", maxArchiveFiles)
+	}
+	content, err := io.ReadAll(io.LimitReader(r, maxExtractedBytes-l.total+1))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	l.total += int64(len(content))
+	if l.total > maxExtractedBytes {
+		return errArchiveTooLarge
+	}
+	l.files = append(l.files, archiveFile{Name: path.Clean(name), Content: content})
+	return nil
+}
+
+// readArchive extracts the manifest files of a zip, tar or gzipped tar archive, sorted by path
+func readArchive(data []byte) ([]archiveFile, error) {
+	files := &limitedFiles{}
+	switch {
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid zip archive: %w", err)
+		}
+		for _, f := range zr.File {
+			if f.FileInfo().IsDir() || !isManifestFile(f.Name) {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, fmt.Errorf("failed to open %s: %w", f.Name, err)
+			}
+			err = files.add(f.Name, rc)
+			rc.Close()
+			if err != nil {
+				return nil, err
+			}
+		}
+	default:
+		var r io.Reader = bytes.NewReader(data)
+		if bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+			gz, err := gzip.NewReader(r)
+			if err != nil {
+				return nil, fmt.Errorf("invalid gzip archive: %w", err)
+			}
+			defer gz.Close()
+			r = gz
+		}
+		tr := tar.NewReader(r)
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid tar archive: %w", err)
+			}
+			if header.Typeflag != tar.TypeReg || !isManifestFile(header.Name) {
+				continue
+			}
+			if err := files.add(header.Name, tr); err != nil {
+				return nil, err
+			}
+		}
+	}
+	sort.SliceStable(files.files, func(i, j int) bool { return files.files[i].Name < files.files[j].Name })
+	return files.files, nil
+}
+
+// applyRank places namespaces and CRDs first, then prerequisites and other built-in kinds, and
+// custom resources last. Kinds in a group defined by an uploaded CRD or unknown to client-go
+// are custom resources.
+func applyRank(obj *unstructured.Unstructured, crdGroups map[string]bool) int {
+	gvk := obj.GroupVersionKind()
+	switch {
+	case gvk.Group == "" && gvk.Kind == "Namespace":
+		return rankNamespace
+	case gvk.Group == "apiextensions.k8s.io" && gvk.Kind == "CustomResourceDefinition":
+		return rankCRD
+	case crdGroups[gvk.Group] || !scheme.Scheme.Recognizes(gvk):
+		return rankCustomResource
+	case prerequisiteKinds[gvk.Kind]:
+		return rankPrerequisite
+	}
+	return rankBuiltin
+}
+
+// orderDocuments decodes the manifest files and orders their documents for applying. Files that
+// cannot be decoded are reported in the results without documents.
+func orderDocuments(files []archiveFile) ([]archiveDocument, []ArchiveFileResult) {
+	results := make([]ArchiveFileResult, len(files))
+	var documents []archiveDocument
+	crdGroups := map[string]bool{}
+	for i, file := range files {
+		results[i] = ArchiveFileResult{File: file.Name, Documents: []ArchiveDocumentResult{}}
+		objects, err := decodeManifests(string(file.Content))
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		for index, obj := range objects {
+			cleanObjectForPatch(obj)
+			if obj.GetKind() == "CustomResourceDefinition" {
+				if group, _, _ := unstructured.NestedString(obj.Object, "spec", "group"); group != "" {
+					crdGroups[group] = true
+				}
+			}
+			documents = append(documents, archiveDocument{File: file.Name, Index: index, Object: obj})
+		}
+	}
+	for i := range documents {
+		documents[i].Rank = applyRank(documents[i].Object, crdGroups)
+	}
+	sort.SliceStable(documents, func(i, j int) bool { return documents[i].Rank < documents[j].Rank })
+	return documents, results
+}
+
+// waitForKinds waits until the REST mapper resolves the kinds of applied CRDs, resetting its
+// discovery cache between attempts. Kinds still unknown at the timeout fail when applied.
+func waitForKinds(ctx context.Context, restMapper meta.RESTMapper, kinds []schema.GroupKind) {
+	deadline := time.Now().Add(crdEstablishTimeout)
+	for {
+		if resettable, ok := restMapper.(meta.ResettableRESTMapper); ok {
+			resettable.Reset()
+		}
+		pending := false
+		for _, gk := range kinds {
+			if _, err := restMapper.RESTMapping(gk); err != nil {
+				pending = true
+				break
+			}
+		}
+		if !pending || time.Now().After(deadline) {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// readUpload reads an uploaded archive within maxArchiveBytes
+func readUpload(header *multipart.FileHeader) ([]byte, error) {
+	if header.Size > maxArchiveBytes {
+		return nil, errArchiveTooLarge
+	}
+	f, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, maxArchiveBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxArchiveBytes {
+		return nil, errArchiveTooLarge
+	}
+	return data, nil
+}
+
+// applyArchive applies the manifests of an uploaded zip or tar archive in dependency order:
+// namespaces, then CRDs, then built-in kinds, then custom resources once their CRDs are served.
+// With stopOnError=true nothing is applied when a file cannot be decoded, and applying stops at
+// the first failed document, leaving the rest skipped. Documents applied before the failure are
+// not rolled back automatically, but are recorded in the apply set like any other apply.
+func (h *ResourcesHandler) applyArchive(c *gin.Context, header *multipart.FileHeader) {
+	data, err := readUpload(header)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, errArchiveTooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		c.JSON(status, gin.H{"message": err.Error(), "code": status})
+		return
+	}
+	files, err := readArchive(data)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, errArchiveTooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		c.JSON(status, gin.H{"message": err.Error(), "code": status})
+		return
+	}
+	if len(files) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "archive contains no YAML or JSON manifests", "code": http.StatusBadRequest})
+		return
+	}
+	stopOnError, _ := strconv.ParseBool(c.Query("stopOnError"))
+
+	documents, results := orderDocuments(files)
+	fileIndex := make(map[string]int, len(results))
+	decodeFailures := 0
+	for i, result := range results {
+		fileIndex[result.File] = i
+		if result.Error != "" {
+			decodeFailures++
+		}
+	}
+	if stopOnError && decodeFailures > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "archive contains files that could not be decoded",
+			"code":    http.StatusBadRequest,
+			"applied": 0,
+			"files":   results,
+		})
+		return
+	}
+
+	dynamicClient, err := h.getDynamicClient(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get dynamic client for archive apply")
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error(), "code": http.StatusBadRequest})
+		return
+	}
+	_, config, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get clientset for archive apply")
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error(), "code": http.StatusBadRequest})
+		return
+	}
+	restMapper, _, err := h.clientFactory.GetRESTMapperForConfig(config, c.Query("cluster"))
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get REST mapper for archive apply")
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error(), "code": http.StatusBadRequest})
+		return
+	}
+
+	warnings := []LintWarning{}
+	for _, doc := range documents {
+		warnings = append(warnings, h.linter.lint(doc.Object)...)
+	}
+	strict := h.linter.mode == lintModeStrict || (h.linter.mode != lintModeOff && c.Query("strict") == "true")
+	if strict && len(warnings) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"message":  "blocked by lint policy",
+			"code":     http.StatusUnprocessableEntity,
+			"warnings": warnings,
+			"applied":  0,
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	recorder := h.newApplySetRecorder(c, dynamicClient, restMapper)
+	var appliedResources []appliedResource
+	var failures []applyFailure
+	var establishedKinds []schema.GroupKind
+	skipped := 0
+	stopped := false
+	for _, doc := range documents {
+		obj := doc.Object
+		result := ArchiveDocumentResult{Index: doc.Index, Kind: obj.GetKind(), Name: obj.GetName(), Namespace: obj.GetNamespace()}
+		if stopped {
+			result.Status = documentSkipped
+			skipped++
+		} else {
+			if doc.Rank > rankCRD && len(establishedKinds) > 0 {
+				waitForKinds(ctx, restMapper, establishedKinds)
+				establishedKinds = nil
+			}
+			applied, failure := recorder.apply(ctx, obj)
+			if failure != nil {
+				result.Status, result.Message = documentFailed, failure.Message
+				failures = append(failures, *failure)
+				stopped = stopOnError
+			} else {
+				result.Status, result.Namespace = documentApplied, applied.Namespace
+				appliedResources = append(appliedResources, *applied)
+				if doc.Rank == rankCRD {
+					group, _, _ := unstructured.NestedString(obj.Object, "spec", "group")
+					kind, _, _ := unstructured.NestedString(obj.Object, "spec", "names", "kind")
+					establishedKinds = append(establishedKinds, schema.GroupKind{Group: group, Kind: kind})
+				}
+			}
+		}
+		i := fileIndex[doc.File]
+		results[i].Documents = append(results[i].Documents, result)
+	}
+	for i := range results {
+		sort.Slice(results[i].Documents, func(a, b int) bool { return results[i].Documents[a].Index < results[i].Documents[b].Index })
+	}
+	applySetID, err := recorder.save()
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to record apply set for archive")
+	}
+
+	if len(failures) > 0 || decodeFailures > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message":          "failed to apply one or more resources",
+			"code":             http.StatusBadRequest,
+			"details":          failures,
+			"applied":          len(appliedResources),
+			"failed":           len(failures) + decodeFailures,
+			"skipped":          skipped,
+			"appliedResources": appliedResources,
+			"warnings":         warnings,
+			"files":            results,
+			"applySetId":       applySetID,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "applied",
+		"applied":          len(appliedResources),
+		"appliedResources": appliedResources,
+		"warnings":         warnings,
+		"files":            results,
+		"applySetId":       applySetID,
+	})
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"testing"
+)
+
+var archiveManifests = map[string]string{
+	"app/deployment.yaml": "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n  namespace: shop\n",
+	"app/widget.yaml":     "apiVersion: example.com/v1\nkind: Widget\nmetadata:\n  name: blue\n  namespace: shop\n",
+	"base/config.yaml":    "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: web\n  namespace: shop\n---\napiVersion: v1\nkind: Namespace\nmetadata:\n  name: shop\n",
+	"crds/widgets.yaml":   "apiVersion: apiextensions.k8s.io/v1\nkind: CustomResourceDefinition\nmetadata:\n  name: widgets.example.com\nspec:\n  group: example.com\n",
+	"README.md":           "# not a manifest",
+	".git/config.yaml":    "ignored: true",
+}
+
+func zipArchive(t *testing.T) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range archiveManifests {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func tarGzArchive(t *testing.T) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range archiveManifests {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		_, _ = tw.Write([]byte(content))
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadArchive(t *testing.T) {
+	for name, data := range map[string][]byte{"zip": zipArchive(t), "tar.gz": tarGzArchive(t)} {
+		files, err := readArchive(data)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		var names []string
+		for _, f := range files {
+			names = append(names, f.Name)
+		}
+		if len(names) != 4 || names[0] != "app/deployment.yaml" || names[3] != "crds/widgets.yaml" {
+			t.Errorf("%s: files = %v, want the four manifests sorted by path", name, names)
+		}
+	}
+
+	if _, err := readArchive([]byte("not an archive at all")); err == nil {
+		t.Error("expected an error for data that is not an archive")
+	}
+}
+
+func TestOrderDocuments(t *testing.T) {
+	files, err := readArchive(zipArchive(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files = append(files, archiveFile{Name: "broken.yaml", Content: []byte("kind: [unterminated")})
+
+	documents, results := orderDocuments(files)
+	var kinds []string
+	for _, doc := range documents {
+		kinds = append(kinds, doc.Object.GetKind())
+	}
+	want := []string{"Namespace", "CustomResourceDefinition", "ConfigMap", "Deployment", "Widget"}
+	if len(kinds) != len(want) {
+		t.Fatalf("kinds = %v, want %v", kinds, want)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Fatalf("kinds = %v, want %v", kinds, want)
+		}
+	}
+	if documents[0].File != "base/config.yaml" || documents[0].Index != 1 {
+		t.Errorf("namespace document = %s#%d, want base/config.yaml#1", documents[0].File, documents[0].Index)
+	}
+
+	last := results[len(results)-1]
+	if last.File != "broken.yaml" || last.Error == "" {
+		t.Errorf("expected broken.yaml to report a decode error, got %+v", last)
+	}
+}
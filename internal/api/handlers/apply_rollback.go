@@ -0,0 +1,384 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Facets-cloud/kube-dash/internal/apitokens"
+	"github.com/Facets-cloud/kube-dash/internal/applysets"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// Actions that undo an applied object
+const (
+	rollbackRevert    = "revert"    // the object existed before the apply and is restored to that state
+	rollbackRecreate  = "recreate"  // the object existed before the apply but was deleted since
+	rollbackDelete    = "delete"    // the apply created the object
+	rollbackUnchanged = "unchanged" // the apply created the object and it is already gone
+	rollbackSkip      = "skip"      // the prior state was not recorded
+)
+
+// Statuses of a rolled back object
+const (
+	rollbackPlanned  = "planned" // dry run
+	rollbackDone     = "done"
+	rollbackFailed   = "failed"
+	rollbackSkipped  = "skipped"
+	rollbackConflict = "conflict" // changed since the apply, and force was not set
+)
+
+// RollbackResult is the outcome of undoing one object of an apply set
+type RollbackResult struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Action    string `json:"action"`
+	Status    string `json:"status"`
+	// Changed is set when the object was modified after the apply; rolling back overwrites those
+	// changes and requires force
+	Changed bool   `json:"changed"`
+	Message string `json:"message,omitempty"`
+}
+
+// ApplySetRollback is the outcome of rolling back an apply set
+type ApplySetRollback struct {
+	ApplySetID string           `json:"applySetId"`
+	DryRun     bool             `json:"dryRun"`
+	Objects    []RollbackResult `json:"objects"` // in rollback order, the reverse of apply order
+	Failed     int              `json:"failed"`
+	Conflicts  int              `json:"conflicts"`
+}
+
+// applySetRecorder applies objects while recording their prior state into an apply set
+type applySetRecorder struct {
+	store         *applysets.Store
+	dynamicClient dynamic.Interface
+	restMapper    meta.RESTMapper
+	set           applysets.ApplySet
+}
+
+func (h *ResourcesHandler) newApplySetRecorder(c *gin.Context, dynamicClient dynamic.Interface, restMapper meta.RESTMapper) *applySetRecorder {
+	return &applySetRecorder{
+		store:         h.applySets,
+		dynamicClient: dynamicClient,
+		restMapper:    restMapper,
+		set:           applysets.ApplySet{ConfigID: c.Query("config"), Cluster: c.Query("cluster"), AppliedBy: apitokens.Actor(c)},
+	}
+}
+
+// apply reads the object's current state, applies it and records the state on success
+func (r *applySetRecorder) apply(ctx context.Context, obj *unstructured.Unstructured) (*appliedResource, *applyFailure) {
+	if r.store == nil {
+		return applyObject(ctx, r.dynamicClient, r.restMapper, obj, false)
+	}
+	prior := capturePrior(ctx, r.dynamicClient, r.restMapper, obj)
+	applied, failure := applyObject(ctx, r.dynamicClient, r.restMapper, obj, false)
+	if failure == nil && prior != nil {
+		prior.AppliedResourceVersion = applied.ResourceVersion
+		r.set.Objects = append(r.set.Objects, *prior)
+	}
+	return applied, failure
+}
+
+// save stores the apply set and returns its ID, or an empty string when nothing was applied
+func (r *applySetRecorder) save() (string, error) {
+	if r.store == nil || len(r.set.Objects) == 0 {
+		return "", nil
+	}
+	if err := r.store.Save(&r.set); err != nil {
+		return "", err
+	}
+	return r.set.ID, nil
+}
+
+// capturePrior reads the state of an object before it is applied. It returns nil when the object
+// cannot be mapped to a resource, as applying it fails too.
+func capturePrior(ctx context.Context, dynamicClient dynamic.Interface, restMapper meta.RESTMapper, obj *unstructured.Unstructured) *applysets.Object {
+	gvk := obj.GroupVersionKind()
+	if gvk.Kind == "" || gvk.Version == "" || obj.GetName() == "" {
+		return nil
+	}
+	mapping, err := restMapper.RESTMapping(schema.GroupKind{Group: gvk.Group, Kind: gvk.Kind}, gvk.Version)
+	if err != nil {
+		return nil
+	}
+	prior := &applysets.Object{
+		Group:    gvk.Group,
+		Version:  gvk.Version,
+		Kind:     gvk.Kind,
+		Resource: mapping.Resource.Resource,
+		Name:     obj.GetName(),
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		// applyObject defaults the namespace the same way
+		prior.Namespace = obj.GetNamespace()
+		if strings.TrimSpace(prior.Namespace) == "" {
+			prior.Namespace = "default"
+		}
+	}
+
+	current, err := resourceFor(dynamicClient, prior).Get(ctx, prior.Name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		prior.Existed = false
+	case err != nil:
+		prior.Existed = true
+		prior.Error = fmt.Sprintf("prior state could not be read: %v", err)
+	default:
+		prior.Existed = true
+		unstructured.RemoveNestedField(current.Object, "metadata", "managedFields")
+		prior.Prior = current.Object
+	}
+	return prior
+}
+
+// resourceFor returns the dynamic resource interface of a recorded object
+func resourceFor(dynamicClient dynamic.Interface, obj *applysets.Object) dynamic.ResourceInterface {
+	gvr := schema.GroupVersionResource{Group: obj.Group, Version: obj.Version, Resource: obj.Resource}
+	if obj.Namespace != "" {
+		return dynamicClient.Resource(gvr).Namespace(obj.Namespace)
+	}
+	return dynamicClient.Resource(gvr)
+}
+
+// planRollback decides how to undo each object of an apply set, last applied first
+func planRollback(ctx context.Context, dynamicClient dynamic.Interface, set *applysets.ApplySet) ([]RollbackResult, []*unstructured.Unstructured) {
+	results := make([]RollbackResult, 0, len(set.Objects))
+	currents := make([]*unstructured.Unstructured, 0, len(set.Objects))
+	for i := len(set.Objects) - 1; i >= 0; i-- {
+		obj := &set.Objects[i]
+		result := RollbackResult{Kind: obj.Kind, Namespace: obj.Namespace, Name: obj.Name}
+		if obj.Error != "" {
+			result.Action, result.Status, result.Message = rollbackSkip, rollbackSkipped, obj.Error
+			results = append(results, result)
+			currents = append(currents, nil)
+			continue
+		}
+
+		current, err := resourceFor(dynamicClient, obj).Get(ctx, obj.Name, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			result.Action, result.Status, result.Message = rollbackSkip, rollbackFailed, err.Error()
+			results = append(results, result)
+			currents = append(currents, nil)
+			continue
+		}
+		exists := err == nil
+		switch {
+		case obj.Existed && exists:
+			result.Action = rollbackRevert
+		case obj.Existed:
+			result.Action = rollbackRecreate
+		case exists:
+			result.Action = rollbackDelete
+		default:
+			result.Action = rollbackUnchanged
+		}
+		if exists && obj.AppliedResourceVersion != "" && current.GetResourceVersion() != obj.AppliedResourceVersion {
+			result.Changed = true
+		}
+		if !exists {
+			current = nil
+		}
+		results = append(results, result)
+		currents = append(currents, current)
+	}
+	return results, currents
+}
+
+// restorable returns a copy of a recorded prior state ready to be written back. The server-set
+// fields are dropped when the object is recreated; a revert replaces the current object at its
+// resourceVersion.
+func restorable(prior map[string]interface{}, current *unstructured.Unstructured) *unstructured.Unstructured {
+	obj := (&unstructured.Unstructured{Object: prior}).DeepCopy()
+	unstructured.RemoveNestedField(obj.Object, "metadata", "managedFields")
+	unstructured.RemoveNestedField(obj.Object, "metadata", "generation")
+	if current != nil {
+		obj.SetResourceVersion(current.GetResourceVersion())
+		return obj
+	}
+	for _, field := range []string{"uid", "resourceVersion", "creationTimestamp", "deletionTimestamp", "deletionGracePeriodSeconds", "selfLink"} {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+	delete(obj.Object, "status")
+	return obj
+}
+
+// rollback undoes the objects of an apply set. Objects changed since the apply are only
+// overwritten with force; without it they are reported as conflicts and nothing is changed.
+// With dryRun the API server validates each step without persisting it.
+func rollback(ctx context.Context, dynamicClient dynamic.Interface, set *applysets.ApplySet, dryRun, force bool) ApplySetRollback {
+	outcome := ApplySetRollback{ApplySetID: set.ID, DryRun: dryRun}
+	results, currents := planRollback(ctx, dynamicClient, set)
+	for _, result := range results {
+		if result.Changed && !force {
+			outcome.Conflicts++
+		}
+	}
+
+	var dryRunOpts []string
+	if dryRun {
+		dryRunOpts = []string{metav1.DryRunAll}
+	}
+	// Objects are in reverse apply order in results; map them back to their records
+	for i := range results {
+		result := &results[i]
+		obj := &set.Objects[len(set.Objects)-1-i]
+		if result.Status != "" {
+			if result.Status == rollbackFailed {
+				outcome.Failed++
+			}
+			continue
+		}
+		if outcome.Conflicts > 0 {
+			result.Status = rollbackSkipped
+			if result.Changed && !force {
+				result.Status = rollbackConflict
+				result.Message = "changed since the apply; roll back with force to overwrite"
+			}
+			continue
+		}
+
+		var err error
+		ri := resourceFor(dynamicClient, obj)
+		switch result.Action {
+		case rollbackRevert:
+			_, err = ri.Update(ctx, restorable(obj.Prior, currents[i]), metav1.UpdateOptions{DryRun: dryRunOpts})
+		case rollbackRecreate:
+			_, err = ri.Create(ctx, restorable(obj.Prior, nil), metav1.CreateOptions{DryRun: dryRunOpts})
+		case rollbackDelete:
+			err = ri.Delete(ctx, obj.Name, metav1.DeleteOptions{DryRun: dryRunOpts})
+			if apierrors.IsNotFound(err) {
+				err = nil
+			}
+		}
+		switch {
+		case err != nil:
+			result.Status, result.Message = rollbackFailed, err.Error()
+			outcome.Failed++
+		case dryRun:
+			result.Status = rollbackPlanned
+		default:
+			result.Status = rollbackDone
+		}
+	}
+	outcome.Objects = results
+	return outcome
+}
+
+// ListApplySets returns the recorded applies of a cluster
+// @Summary List apply sets
+// @Description Lists the applies recorded for a cluster, newest first, with the objects each one modified. Prior object states are omitted; get a single apply set to see them.
+// @Tags Resources
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Success 200 {array} applysets.ApplySet "Apply sets"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Router /api/v1/app/apply/sets [get]
+func (h *ResourcesHandler) ListApplySets(c *gin.Context) {
+	configID := c.Query("config")
+	if configID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "config parameter is required", "code": http.StatusBadRequest})
+		return
+	}
+	sets, err := h.applySets.List(applysets.Filter{ConfigID: configID, Cluster: c.Query("cluster")})
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list apply sets")
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error(), "code": http.StatusInternalServerError})
+		return
+	}
+	c.JSON(http.StatusOK, sets)
+}
+
+// GetApplySet returns a recorded apply with the prior state of its objects
+// @Summary Get apply set
+// @Description Returns a recorded apply with the state each object had before it, or whether the apply created it
+// @Tags Resources
+// @Produce json
+// @Param id path string true "Apply set ID"
+// @Success 200 {object} applysets.ApplySet "Apply set"
+// @Failure 404 {object} map[string]interface{} "Apply set not found"
+// @Router /api/v1/app/apply/sets/{id} [get]
+func (h *ResourcesHandler) GetApplySet(c *gin.Context) {
+	set, err := h.applySets.Get(c.Param("id"))
+	if err != nil {
+		h.applySetError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, set)
+}
+
+// RollbackApplySet undoes a recorded apply
+// @Summary Roll back an apply
+// @Description Restores the objects of a recorded apply to their state before it, last applied first: objects that existed are reverted, or recreated when they were deleted since, and objects the apply created are deleted. Objects changed after the apply are reported as conflicts and block the rollback unless force is set. With dryRun the API server validates every step without persisting it, previewing the rollback. An apply set can be rolled back once.
+// @Tags Resources
+// @Produce json
+// @Param id path string true "Apply set ID"
+// @Param dryRun query bool false "Preview the rollback without changing anything"
+// @Param force query bool false "Overwrite objects changed since the apply"
+// @Success 200 {object} ApplySetRollback "Rollback outcome"
+// @Failure 404 {object} map[string]interface{} "Apply set not found"
+// @Failure 409 {object} ApplySetRollback "Objects changed since the apply, or the apply set was already rolled back"
+// @Failure 502 {object} ApplySetRollback "Some objects could not be rolled back"
+// @Router /api/v1/app/apply/sets/{id}/rollback [post]
+func (h *ResourcesHandler) RollbackApplySet(c *gin.Context) {
+	set, err := h.applySets.Get(c.Param("id"))
+	if err != nil {
+		h.applySetError(c, err)
+		return
+	}
+	if set.RolledBackAt != nil {
+		h.applySetError(c, applysets.ErrRolledBack)
+		return
+	}
+	dryRun, _ := strconv.ParseBool(c.Query("dryRun"))
+	force, _ := strconv.ParseBool(c.Query("force"))
+
+	dynamicClient, _, err := h.dynamicClientFor(set.ConfigID, set.Cluster)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error(), "code": http.StatusBadRequest})
+		return
+	}
+
+	outcome := rollback(c.Request.Context(), dynamicClient, set, dryRun, force)
+	switch {
+	case outcome.Conflicts > 0:
+		c.JSON(http.StatusConflict, outcome)
+		return
+	case outcome.Failed > 0:
+		h.logger.WithField("apply_set", set.ID).WithField("failed", outcome.Failed).Warn("Apply set rollback failed for some objects")
+		c.JSON(http.StatusBadGateway, outcome)
+		return
+	}
+	if !dryRun {
+		if err := h.applySets.MarkRolledBack(set.ID, apitokens.Actor(c)); err != nil {
+			h.logger.WithError(err).WithField("apply_set", set.ID).Error("Failed to mark apply set rolled back")
+		}
+		h.logger.WithField("apply_set", set.ID).WithField("objects", len(outcome.Objects)).Info("Apply set rolled back")
+	}
+	c.JSON(http.StatusOK, outcome)
+}
+
+func (h *ResourcesHandler) applySetError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, storage.ErrDocumentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "apply set not found", "code": http.StatusNotFound})
+	case errors.Is(err, applysets.ErrRolledBack):
+		c.JSON(http.StatusConflict, gin.H{"message": err.Error(), "code": http.StatusConflict})
+	default:
+		h.logger.WithError(err).Error("Failed to load apply set")
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error(), "code": http.StatusInternalServerError})
+	}
+}
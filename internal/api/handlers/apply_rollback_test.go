@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Facets-cloud/kube-dash/internal/applysets"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func configMap(name string, data map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "shop"},
+		"data":       data,
+	}}
+}
+
+func recorded(name string, existed bool, prior *unstructured.Unstructured, appliedVersion string) applysets.Object {
+	obj := applysets.Object{Version: "v1", Kind: "ConfigMap", Resource: "configmaps", Namespace: "shop", Name: name, Existed: existed, AppliedResourceVersion: appliedVersion}
+	if prior != nil {
+		obj.Prior = prior.Object
+	}
+	return obj
+}
+
+// seededConfigMaps returns a fake client holding the state after an apply: settings was changed
+// by it, created was created by it, and removed existed before it but was deleted since
+func seededConfigMaps(t *testing.T) (dynamic.ResourceInterface, *dynamicfake.FakeDynamicClient) {
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{configMapsGVR: "ConfigMapList"})
+	configMaps := client.Resource(configMapsGVR).Namespace("shop")
+	settings := configMap("settings", map[string]interface{}{"level": "debug"})
+	settings.SetResourceVersion("2")
+	created := configMap("created", nil)
+	created.SetResourceVersion("3")
+	for _, obj := range []*unstructured.Unstructured{settings, created} {
+		if _, err := configMaps.Create(context.Background(), obj, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return configMaps, client
+}
+
+func TestRollbackApplySet(t *testing.T) {
+	ctx := context.Background()
+	set := &applysets.ApplySet{ID: "set-1", Objects: []applysets.Object{
+		recorded("settings", true, configMap("settings", map[string]interface{}{"level": "info"}), "2"),
+		recorded("created", false, nil, "3"),
+		recorded("removed", true, configMap("removed", map[string]interface{}{"keep": "me"}), "4"),
+	}}
+
+	_, client := seededConfigMaps(t)
+	preview := rollback(ctx, client, set, true, false)
+	if preview.Failed != 0 || preview.Conflicts != 0 || len(preview.Objects) != 3 {
+		t.Fatalf("dry run = %+v", preview)
+	}
+	wantActions := []string{rollbackRecreate, rollbackDelete, rollbackRevert}
+	for i, result := range preview.Objects {
+		if result.Action != wantActions[i] || result.Status != rollbackPlanned {
+			t.Errorf("dry run object %d = %+v, want planned %s", i, result, wantActions[i])
+		}
+	}
+
+	configMaps, client := seededConfigMaps(t)
+	outcome := rollback(ctx, client, set, false, false)
+	if outcome.Failed != 0 || outcome.Conflicts != 0 {
+		t.Fatalf("rollback = %+v", outcome)
+	}
+	current, err := configMaps.Get(ctx, "settings", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if level, _, _ := unstructured.NestedString(current.Object, "data", "level"); level != "info" {
+		t.Errorf("settings level = %q after rollback, want info", level)
+	}
+	if _, err := configMaps.Get(ctx, "created", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("created should be deleted, got %v", err)
+	}
+	if _, err := configMaps.Get(ctx, "removed", metav1.GetOptions{}); err != nil {
+		t.Errorf("removed should be recreated: %v", err)
+	}
+}
+
+func TestRollbackConflicts(t *testing.T) {
+	ctx := context.Background()
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{configMapsGVR: "ConfigMapList"})
+	configMaps := client.Resource(configMapsGVR).Namespace("shop")
+
+	edited := configMap("settings", map[string]interface{}{"level": "trace"})
+	edited.SetResourceVersion("7")
+	if _, err := configMaps.Create(ctx, edited, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	set := &applysets.ApplySet{ID: "set-2", Objects: []applysets.Object{
+		recorded("settings", true, configMap("settings", map[string]interface{}{"level": "info"}), "2"),
+	}}
+
+	outcome := rollback(ctx, client, set, false, false)
+	if outcome.Conflicts != 1 || outcome.Objects[0].Status != rollbackConflict || !outcome.Objects[0].Changed {
+		t.Fatalf("rollback of an object changed since the apply = %+v", outcome)
+	}
+	current, _ := configMaps.Get(ctx, "settings", metav1.GetOptions{})
+	if level, _, _ := unstructured.NestedString(current.Object, "data", "level"); level != "trace" {
+		t.Errorf("conflicting rollback changed the object to %q", level)
+	}
+
+	forced := rollback(ctx, client, set, false, true)
+	if forced.Conflicts != 0 || forced.Objects[0].Status != rollbackDone {
+		t.Fatalf("forced rollback = %+v", forced)
+	}
+	current, _ = configMaps.Get(ctx, "settings", metav1.GetOptions{})
+	if level, _, _ := unstructured.NestedString(current.Object, "data", "level"); level != "info" {
+		t.Errorf("settings level = %q after forced rollback, want info", level)
+	}
+}
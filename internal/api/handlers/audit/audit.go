@@ -0,0 +1,78 @@
+package audit
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/audit"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditHandler serves the audit trail
+type AuditHandler struct {
+	recorder *audit.Recorder
+	logger   *logger.Logger
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(recorder *audit.Recorder, log *logger.Logger) *AuditHandler {
+	return &AuditHandler{
+		recorder: recorder,
+		logger:   log,
+	}
+}
+
+// ListEvents returns audit events, newest first
+// @Summary List audit events
+// @Description Lists recorded audit events such as terminal exec attempts and policy denials, newest first
+// @Tags Audit
+// @Produce json
+// @Param action query string false "Only events with this action (e.g. terminal.exec)"
+// @Param outcome query string false "Only events with this outcome (allowed, denied, success, failure)"
+// @Param config query string false "Only events for this config ID"
+// @Param cluster query string false "Only events for this cluster"
+// @Param namespace query string false "Only events in this namespace"
+// @Param since query string false "Only events after this RFC3339 time"
+// @Param limit query int false "Maximum number of events (default 200)"
+// @Success 200 {array} audit.Event "Audit events"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/audit/events [get]
+func (h *AuditHandler) ListEvents(c *gin.Context) {
+	filter := audit.Filter{
+		Action:    c.Query("action"),
+		Outcome:   c.Query("outcome"),
+		ConfigID:  c.Query("config"),
+		Cluster:   c.Query("cluster"),
+		Namespace: c.Query("namespace"),
+		Limit:     200,
+	}
+	if s := c.Query("since"); s != "" {
+		since, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "since must be an RFC3339 time"})
+			return
+		}
+		filter.Since = since
+	}
+	if l := c.Query("limit"); l != "" {
+		limit, err := strconv.Atoi(l)
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		filter.Limit = limit
+	}
+
+	events, err := h.recorder.List(filter)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list audit events")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, events)
+}
@@ -8,6 +8,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Facets-cloud/kube-dash/internal/applysets"
+	"github.com/Facets-cloud/kube-dash/internal/config"
 	"github.com/Facets-cloud/kube-dash/internal/k8s"
 	"github.com/Facets-cloud/kube-dash/internal/storage"
 	"github.com/Facets-cloud/kube-dash/pkg/logger"
@@ -18,7 +20,6 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
 )
 
@@ -28,6 +29,8 @@ type ResourcesHandler struct {
 	clientFactory *k8s.ClientFactory
 	logger        *logger.Logger
 	helmHandler   HelmDeleter
+	linter        *linter
+	applySets     *applysets.Store // records applies for rollback; nil disables recording
 }
 
 // HelmDeleter interface for helm deletion operations
@@ -36,17 +39,19 @@ type HelmDeleter interface {
 }
 
 // NewResourcesHandler creates a new resources handler
-func NewResourcesHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger, helmHandler HelmDeleter) *ResourcesHandler {
+func NewResourcesHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger, helmHandler HelmDeleter, lintConfig *config.LintConfig, applySets *applysets.Store) *ResourcesHandler {
 	return &ResourcesHandler{
 		store:         store,
 		clientFactory: clientFactory,
 		logger:        log,
 		helmHandler:   helmHandler,
+		linter:        newLinter(lintConfig),
+		applySets:     applySets,
 	}
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *ResourcesHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, *api.Config, error) {
+func (h *ResourcesHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, *api.Config, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -81,36 +86,9 @@ func (h *ResourcesHandler) getDynamicClient(c *gin.Context) (dynamic.Interface,
 		return nil, fmt.Errorf("config not found: %w", err)
 	}
 
-	// Create a copy of the config and set the context to the specific cluster
-	configCopy := config.DeepCopy()
-
-	// Find the context that matches the cluster name
-	for contextName, context := range configCopy.Contexts {
-		if context.Cluster == cluster {
-			configCopy.CurrentContext = contextName
-			break
-		}
-	}
-
-	// If no matching context found, use the first context
-	if configCopy.CurrentContext == "" && len(configCopy.Contexts) > 0 {
-		for contextName := range configCopy.Contexts {
-			configCopy.CurrentContext = contextName
-			break
-		}
-	}
-
-	// Create client config
-	clientConfig := clientcmd.NewDefaultClientConfig(*configCopy, &clientcmd.ConfigOverrides{})
-	restConfig, err := clientConfig.ClientConfig()
-	if err != nil {
-		return nil, fmt.Errorf("failed to create client config: %w", err)
-	}
-
-	// Create dynamic client
-	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	dynamicClient, err := h.clientFactory.GetDynamicClientForConfig(config, cluster)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+		return nil, fmt.Errorf("failed to get dynamic client: %w", err)
 	}
 
 	return dynamicClient, nil
@@ -148,6 +126,7 @@ var resourceMapping = map[string]struct {
 	"persistentvolumeclaims": {schema.GroupVersionResource{Group: "", Version: "v1", Resource: "persistentvolumeclaims"}, true},
 	"limitranges":            {schema.GroupVersionResource{Group: "", Version: "v1", Resource: "limitranges"}, true},
 	"resourcequotas":         {schema.GroupVersionResource{Group: "", Version: "v1", Resource: "resourcequotas"}, true},
+	"replicationcontrollers": {schema.GroupVersionResource{Group: "", Version: "v1", Resource: "replicationcontrollers"}, true},
 
 	// Networking v1
 	"ingresses": {schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}, true},
@@ -0,0 +1,369 @@
+package bookmarks
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/apitokens"
+	"github.com/Facets-cloud/kube-dash/internal/bookmarks"
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Existence of a bookmarked resource, when re-validated
+const (
+	StatusExists  = "exists"  // the resource is still on the cluster
+	StatusMissing = "missing" // the resource, or its kind, is gone; the entry is stale
+	StatusUnknown = "unknown" // the cluster could not be asked, e.g. for lack of access
+)
+
+// validateConcurrency bounds the existence checks run at once
+const validateConcurrency = 10
+
+// ResourceRequest is the resource of a favorite or a view, on the cluster of the request
+type ResourceRequest struct {
+	APIVersion string `json:"apiVersion" binding:"required"`
+	Kind       string `json:"kind" binding:"required"`
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name" binding:"required"`
+}
+
+// Validation is the result of re-checking that a bookmarked resource exists
+type Validation struct {
+	Status string `json:"status,omitempty"`
+	Reason string `json:"reason,omitempty"` // why the status is missing or unknown
+}
+
+// FavoriteEntry is a favorite, with its existence when validation was requested
+type FavoriteEntry struct {
+	bookmarks.Favorite
+	Validation
+}
+
+// RecentEntry is a recently viewed resource, with its existence when validation was requested
+type RecentEntry struct {
+	bookmarks.RecentItem
+	Validation
+}
+
+// BookmarksHandler serves the favorite and recently viewed resources of users
+type BookmarksHandler struct {
+	bookmarks     *bookmarks.Store
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	logger        *logger.Logger
+}
+
+// NewBookmarksHandler creates a new resource bookmarks handler
+func NewBookmarksHandler(bookmarkStore *bookmarks.Store, store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger) *BookmarksHandler {
+	return &BookmarksHandler{
+		bookmarks:     bookmarkStore,
+		store:         store,
+		clientFactory: clientFactory,
+		logger:        log,
+	}
+}
+
+// resourceRef reads the resource of a request: from the body when it has one, otherwise from
+// the query parameters
+func resourceRef(c *gin.Context, fromBody bool) (bookmarks.ResourceRef, error) {
+	ref := bookmarks.ResourceRef{ConfigID: c.Query("config"), Cluster: c.Query("cluster")}
+	if fromBody {
+		var req ResourceRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			return ref, err
+		}
+		ref.APIVersion, ref.Kind, ref.Namespace, ref.Name = req.APIVersion, req.Kind, req.Namespace, req.Name
+	} else {
+		ref.APIVersion, ref.Kind, ref.Namespace, ref.Name = c.Query("apiVersion"), c.Query("kind"), c.Query("namespace"), c.Query("name")
+	}
+	return ref, ref.Validate()
+}
+
+// validate checks that each resource still exists, on whichever clusters they are
+func (h *BookmarksHandler) validate(ctx context.Context, refs []bookmarks.ResourceRef) []Validation {
+	results := make([]Validation, len(refs))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, validateConcurrency)
+	for i := range refs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = h.exists(ctx, refs[i])
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+// exists looks a resource up through the cluster's discovery and dynamic client
+func (h *BookmarksHandler) exists(ctx context.Context, ref bookmarks.ResourceRef) Validation {
+	config, err := h.store.GetKubeConfig(ref.ConfigID)
+	if err != nil {
+		return Validation{Status: StatusMissing, Reason: "kubeconfig not found"}
+	}
+	mapper, _, err := h.clientFactory.GetRESTMapperForConfig(config, ref.Cluster)
+	if err != nil {
+		return Validation{Status: StatusUnknown, Reason: err.Error()}
+	}
+	gvk := ref.GroupVersionKind()
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		return Validation{Status: StatusMissing, Reason: "kind " + gvk.Kind + " is not served by the cluster"}
+	}
+	if err != nil {
+		return Validation{Status: StatusUnknown, Reason: err.Error()}
+	}
+	client, err := h.clientFactory.GetDynamicClientForConfig(config, ref.Cluster)
+	if err != nil {
+		return Validation{Status: StatusUnknown, Reason: err.Error()}
+	}
+	namespace := ref.Namespace
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		namespace = ""
+	}
+	_, err = client.Resource(mapping.Resource).Namespace(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		return Validation{Status: StatusExists}
+	case apierrors.IsNotFound(err):
+		return Validation{Status: StatusMissing, Reason: "resource not found"}
+	default:
+		return Validation{Status: StatusUnknown, Reason: err.Error()}
+	}
+}
+
+// validationContext bounds the existence checks of a list
+func validationContext(c *gin.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(c.Request.Context(), 20*time.Second)
+}
+
+// GetFavorites lists the caller's favorite resources
+// @Summary List favorite resources
+// @Description Lists the resources the caller marked as favorites on a cluster, or on all clusters when config is omitted. With validate, each favorite is looked up on its cluster and reported as exists, missing (the resource or its kind is gone) or unknown (the cluster could not be asked).
+// @Tags Bookmarks
+// @Produce json
+// @Param config query string false "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param owner query string false "Caller whose favorites are listed; API token callers are identified by their token"
+// @Param validate query bool false "Check that each resource still exists"
+// @Success 200 {array} FavoriteEntry "Favorite resources"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/resource-favorites [get]
+func (h *BookmarksHandler) GetFavorites(c *gin.Context) {
+	owner, _ := apitokens.RequestOwner(c)
+	favorites, err := h.bookmarks.Favorites(owner, c.Query("config"), c.Query("cluster"))
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list resource favorites")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	entries := make([]FavoriteEntry, len(favorites))
+	refs := make([]bookmarks.ResourceRef, len(favorites))
+	for i, f := range favorites {
+		entries[i].Favorite, refs[i] = f, f.ResourceRef
+	}
+	if validate, _ := strconv.ParseBool(c.Query("validate")); validate {
+		ctx, cancel := validationContext(c)
+		defer cancel()
+		for i, v := range h.validate(ctx, refs) {
+			entries[i].Validation = v
+		}
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+// AddFavorite marks a resource as a favorite of the caller
+// @Summary Add a favorite resource
+// @Description Marks a resource of any kind as a favorite of the caller on a cluster. Adding an existing favorite keeps it unchanged.
+// @Tags Bookmarks
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param owner query string false "Caller whose favorite is added; API token callers are identified by their token"
+// @Param body body ResourceRequest true "Resource"
+// @Success 200 {object} bookmarks.Favorite "Favorite"
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/resource-favorites [put]
+func (h *BookmarksHandler) AddFavorite(c *gin.Context) {
+	ref, err := resourceRef(c, true)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	owner, _ := apitokens.RequestOwner(c)
+	favorite, err := h.bookmarks.AddFavorite(owner, ref)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to store resource favorite")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, favorite)
+}
+
+// RemoveFavorite unmarks a favorite resource of the caller
+// @Summary Remove a favorite resource
+// @Description Removes a resource from the caller's favorites on a cluster
+// @Tags Bookmarks
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param owner query string false "Caller whose favorite is removed; API token callers are identified by their token"
+// @Param apiVersion query string true "API version of the resource, e.g. apps/v1"
+// @Param kind query string true "Kind of the resource"
+// @Param namespace query string false "Namespace of the resource"
+// @Param name query string true "Name of the resource"
+// @Success 204 "Favorite removed"
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/resource-favorites [delete]
+func (h *BookmarksHandler) RemoveFavorite(c *gin.Context) {
+	ref, err := resourceRef(c, false)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	owner, _ := apitokens.RequestOwner(c)
+	if err := h.bookmarks.RemoveFavorite(owner, ref); err != nil {
+		h.logger.WithError(err).Error("Failed to remove resource favorite")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetRecent lists the resources the caller viewed last
+// @Summary List recently viewed resources
+// @Description Lists the resources the caller viewed on a cluster, or on all clusters when config is omitted, most recent first. Up to 50 resources are kept per cluster. With validate, each resource is looked up on its cluster and reported as exists, missing or unknown.
+// @Tags Bookmarks
+// @Produce json
+// @Param config query string false "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param owner query string false "Caller whose views are listed; API token callers are identified by their token"
+// @Param limit query int false "Maximum number of resources"
+// @Param validate query bool false "Check that each resource still exists"
+// @Success 200 {array} RecentEntry "Recently viewed resources"
+// @Failure 400 {object} map[string]string "Bad request - invalid limit"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/recent-resources [get]
+func (h *BookmarksHandler) GetRecent(c *gin.Context) {
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		var err error
+		if limit, err = strconv.Atoi(raw); err != nil || limit < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+	}
+	owner, _ := apitokens.RequestOwner(c)
+	items, err := h.bookmarks.Recent(owner, c.Query("config"), c.Query("cluster"), limit)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list recent resources")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	entries := make([]RecentEntry, len(items))
+	refs := make([]bookmarks.ResourceRef, len(items))
+	for i, item := range items {
+		entries[i].RecentItem, refs[i] = item, item.ResourceRef
+	}
+	if validate, _ := strconv.ParseBool(c.Query("validate")); validate {
+		ctx, cancel := validationContext(c)
+		defer cancel()
+		for i, v := range h.validate(ctx, refs) {
+			entries[i].Validation = v
+		}
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+// RecordView notes that the caller viewed a resource
+// @Summary Record a resource view
+// @Description Notes that the caller viewed a resource, moving it to the top of their recently viewed resources on the cluster
+// @Tags Bookmarks
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param owner query string false "Caller whose view is recorded; API token callers are identified by their token"
+// @Param body body ResourceRequest true "Resource"
+// @Success 200 {object} bookmarks.RecentItem "Recent item"
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/recent-resources [post]
+func (h *BookmarksHandler) RecordView(c *gin.Context) {
+	ref, err := resourceRef(c, true)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	owner, _ := apitokens.RequestOwner(c)
+	item, err := h.bookmarks.RecordView(owner, ref)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to record resource view")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+// ClearRecent forgets resources the caller viewed
+// @Summary Clear recently viewed resources
+// @Description Forgets one viewed resource when kind and name are given, otherwise all resources the caller viewed on the cluster
+// @Tags Bookmarks
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param owner query string false "Caller whose views are cleared; API token callers are identified by their token"
+// @Param apiVersion query string false "API version of the resource to forget"
+// @Param kind query string false "Kind of the resource to forget"
+// @Param namespace query string false "Namespace of the resource to forget"
+// @Param name query string false "Name of the resource to forget"
+// @Success 204 "Views cleared"
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/recent-resources [delete]
+func (h *BookmarksHandler) ClearRecent(c *gin.Context) {
+	owner, _ := apitokens.RequestOwner(c)
+	if c.Query("kind") == "" && c.Query("name") == "" {
+		if c.Query("config") == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "config parameter is required"})
+			return
+		}
+		if _, err := h.bookmarks.ClearRecent(owner, c.Query("config"), c.Query("cluster")); err != nil {
+			h.logger.WithError(err).Error("Failed to clear recent resources")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+		return
+	}
+	ref, err := resourceRef(c, false)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.bookmarks.RemoveRecent(owner, ref); err != nil {
+		h.logger.WithError(err).Error("Failed to remove recent resource")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,102 @@
+package bookmarks
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Facets-cloud/kube-dash/internal/bookmarks"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/internal/testharness"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+)
+
+func TestFavoritesValidation(t *testing.T) {
+	h := testharness.New(t, "widgets.yaml")
+	h.Clientset.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{{
+		GroupVersion: "example.com/v1",
+		APIResources: []metav1.APIResource{{Name: "widgets", Kind: "Widget", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}}},
+	}}
+	handler := NewBookmarksHandler(bookmarks.NewStore(storage.NewDocumentStore(nil), h.Logger), h.Store, h.Factory, h.Logger)
+	route := "/api/v1/resource-favorites"
+
+	for _, body := range []string{
+		`{"apiVersion":"example.com/v1","kind":"Widget","namespace":"shop","name":"blue"}`,
+		`{"apiVersion":"example.com/v1","kind":"Widget","namespace":"shop","name":"red"}`,
+		`{"apiVersion":"example.com/v1","kind":"Gadget","namespace":"shop","name":"blue"}`,
+	} {
+		if w := h.Do(http.MethodPut, route, handler.AddFavorite, route+"?owner=alice", strings.NewReader(body)); w.Code != http.StatusOK {
+			t.Fatalf("adding %s: status %d: %s", body, w.Code, w.Body.String())
+		}
+	}
+	if w := h.Do(http.MethodPut, route, handler.AddFavorite, route+"?owner=alice", strings.NewReader(`{"kind":"Widget","name":"blue"}`)); w.Code != http.StatusBadRequest {
+		t.Errorf("favorite without apiVersion: status %d, want 400", w.Code)
+	}
+
+	var entries []FavoriteEntry
+	h.DoJSON(http.MethodGet, route, handler.GetFavorites, route+"?owner=alice", nil, &entries)
+	if len(entries) != 3 || entries[0].Status != "" {
+		t.Fatalf("favorites = %+v, want 3 without validation", entries)
+	}
+
+	h.DoJSON(http.MethodGet, route, handler.GetFavorites, route+"?owner=alice&validate=true", nil, &entries)
+	status := map[string]string{}
+	for _, e := range entries {
+		status[e.Kind+"/"+e.Name] = e.Status
+	}
+	if status["Widget/blue"] != StatusExists || status["Widget/red"] != StatusMissing || status["Gadget/blue"] != StatusMissing {
+		t.Errorf("statuses = %v", status)
+	}
+
+	if w := h.Do(http.MethodDelete, route, handler.RemoveFavorite, route+"?owner=alice&apiVersion=example.com/v1&kind=Widget&namespace=shop&name=red", nil); w.Code != http.StatusNoContent {
+		t.Fatalf("remove: status %d", w.Code)
+	}
+	h.DoJSON(http.MethodGet, route, handler.GetFavorites, route+"?owner=alice", nil, &entries)
+	if len(entries) != 2 {
+		t.Errorf("got %d favorites after removal, want 2", len(entries))
+	}
+	h.DoJSON(http.MethodGet, route, handler.GetFavorites, route+"?owner=bob", nil, &entries)
+	if len(entries) != 0 {
+		t.Errorf("bob sees alice's favorites: %+v", entries)
+	}
+}
+
+func TestRecentResources(t *testing.T) {
+	h := testharness.New(t, "widgets.yaml")
+	handler := NewBookmarksHandler(bookmarks.NewStore(storage.NewDocumentStore(nil), h.Logger), h.Store, h.Factory, h.Logger)
+	route := "/api/v1/recent-resources"
+
+	for _, name := range []string{"blue", "red", "blue"} {
+		body := `{"apiVersion":"example.com/v1","kind":"Widget","namespace":"shop","name":"` + name + `"}`
+		if w := h.Do(http.MethodPost, route, handler.RecordView, route+"?owner=alice", strings.NewReader(body)); w.Code != http.StatusOK {
+			t.Fatalf("recording %s: status %d: %s", name, w.Code, w.Body.String())
+		}
+	}
+
+	var entries []RecentEntry
+	h.DoJSON(http.MethodGet, route, handler.GetRecent, route+"?owner=alice", nil, &entries)
+	if len(entries) != 2 || entries[0].Views+entries[1].Views != 3 {
+		t.Fatalf("recent = %+v, want blue and red with 3 views", entries)
+	}
+	h.DoJSON(http.MethodGet, route, handler.GetRecent, route+"?owner=alice&limit=1", nil, &entries)
+	if len(entries) != 1 {
+		t.Errorf("limit 1 returned %d items", len(entries))
+	}
+
+	if w := h.Do(http.MethodDelete, route, handler.ClearRecent, route+"?owner=alice&apiVersion=example.com/v1&kind=Widget&namespace=shop&name=red", nil); w.Code != http.StatusNoContent {
+		t.Fatalf("forget red: status %d", w.Code)
+	}
+	h.DoJSON(http.MethodGet, route, handler.GetRecent, route+"?owner=alice", nil, &entries)
+	if len(entries) != 1 || entries[0].Name != "blue" {
+		t.Errorf("recent after forgetting red = %+v", entries)
+	}
+	if w := h.Do(http.MethodDelete, route, handler.ClearRecent, route+"?owner=alice", nil); w.Code != http.StatusNoContent {
+		t.Fatalf("clear: status %d", w.Code)
+	}
+	h.DoJSON(http.MethodGet, route, handler.GetRecent, route+"?owner=alice", nil, &entries)
+	if len(entries) != 0 {
+		t.Errorf("recent after clearing = %+v", entries)
+	}
+}
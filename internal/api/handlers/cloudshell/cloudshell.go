@@ -64,7 +64,7 @@ func NewCloudShellHandler(store *storage.KubeConfigStore, clientFactory *k8s.Cli
 }
 
 // getActiveSessions gets the count of active cloud shell sessions for the given config, cluster, and namespace
-func (h *CloudShellHandler) getActiveSessions(client *kubernetes.Clientset, configID, cluster, namespace string) ([]*CloudShellSession, error) {
+func (h *CloudShellHandler) getActiveSessions(client kubernetes.Interface, configID, cluster, namespace string) ([]*CloudShellSession, error) {
 	// List pods with cloudshell label
 	pods, err := client.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{
 		LabelSelector: "app=cloudshell",
@@ -100,7 +100,7 @@ func (h *CloudShellHandler) getActiveSessions(client *kubernetes.Clientset, conf
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *CloudShellHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, *rest.Config, error) {
+func (h *CloudShellHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, *rest.Config, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -165,7 +165,7 @@ func (h *CloudShellHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clie
 }
 
 // checkCloudShellPermissions checks if the user has permissions to create, list, and delete cloud shell resources
-func (h *CloudShellHandler) checkCloudShellPermissions(client *kubernetes.Clientset, namespace string) error {
+func (h *CloudShellHandler) checkCloudShellPermissions(client kubernetes.Interface, namespace string) error {
 	// Define the permissions we need to check
 	permissions := []struct {
 		resource string
@@ -204,7 +204,7 @@ func (h *CloudShellHandler) checkCloudShellPermissions(client *kubernetes.Client
 }
 
 // checkCloudShellConnectionPermissions checks if the user has permissions to connect to a specific cloud shell pod
-func (h *CloudShellHandler) checkCloudShellConnectionPermissions(client *kubernetes.Clientset, podName, namespace string) error {
+func (h *CloudShellHandler) checkCloudShellConnectionPermissions(client kubernetes.Interface, podName, namespace string) error {
 	// Check if user can get the specific pod
 	getAccessReview := &authorizationv1.SelfSubjectAccessReview{
 		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
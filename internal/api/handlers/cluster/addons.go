@@ -0,0 +1,446 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/internal/tracing"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Addon categories
+const (
+	AddonCategoryDNS          = "dns"
+	AddonCategoryIngress      = "ingress"
+	AddonCategoryMetrics      = "metrics"
+	AddonCategoryCertificates = "certificates"
+	AddonCategoryCNI          = "cni"
+	AddonCategoryCSI          = "csi"
+	AddonCategoryProxy        = "proxy"
+)
+
+// Addon health, from best to worst
+const (
+	AddonHealthy  = "healthy"
+	AddonDegraded = "degraded" // some replicas are not ready
+	AddonDown     = "down"     // no replica is ready
+	// AddonRegistered is the health of a CSI driver registered without a recognized workload
+	AddonRegistered = "registered"
+)
+
+var addonHealthRank = map[string]int{AddonHealthy: 0, AddonRegistered: 0, AddonDegraded: 1, AddonDown: 2}
+
+// addonSpec describes how to recognize a well-known addon
+type addonSpec struct {
+	Name     string
+	Category string
+	// Names are workload names, also matched as a prefix followed by a dash
+	Names []string
+	// Labels are values of app.kubernetes.io/name, k8s-app or app
+	Labels []string
+	// Namespaces restrict generic names to the namespaces the addon is installed in
+	Namespaces []string
+	// Drivers are the names of the CSIDrivers the addon registers
+	Drivers []string
+}
+
+// addonCatalog lists the addons the inventory recognizes. Earlier entries win when a workload
+// matches several.
+var addonCatalog = []addonSpec{
+	{Name: "CoreDNS", Category: AddonCategoryDNS, Names: []string{"coredns"}, Labels: []string{"coredns"}},
+	{Name: "kube-dns", Category: AddonCategoryDNS, Names: []string{"kube-dns"}, Namespaces: []string{"kube-system"}},
+	{Name: "NodeLocal DNSCache", Category: AddonCategoryDNS, Names: []string{"node-local-dns"}, Labels: []string{"node-local-dns"}},
+	{Name: "ingress-nginx", Category: AddonCategoryIngress, Names: []string{"ingress-nginx-controller", "nginx-ingress-controller"}, Labels: []string{"ingress-nginx"}},
+	{Name: "Traefik", Category: AddonCategoryIngress, Names: []string{"traefik"}, Labels: []string{"traefik"}},
+	{Name: "HAProxy Ingress", Category: AddonCategoryIngress, Names: []string{"haproxy-ingress", "kubernetes-ingress"}, Labels: []string{"haproxy-ingress", "kubernetes-ingress"}},
+	{Name: "Contour", Category: AddonCategoryIngress, Names: []string{"contour"}, Labels: []string{"contour"}},
+	{Name: "AWS Load Balancer Controller", Category: AddonCategoryIngress, Names: []string{"aws-load-balancer-controller"}, Labels: []string{"aws-load-balancer-controller"}},
+	{Name: "metrics-server", Category: AddonCategoryMetrics, Names: []string{"metrics-server"}, Labels: []string{"metrics-server"}},
+	{Name: "cert-manager", Category: AddonCategoryCertificates, Names: []string{"cert-manager"}, Labels: []string{"cert-manager", "cainjector"}},
+	{Name: "Calico", Category: AddonCategoryCNI, Names: []string{"calico-node", "calico-kube-controllers", "calico-typha"}, Labels: []string{"calico-node", "calico-kube-controllers"}},
+	{Name: "Cilium", Category: AddonCategoryCNI, Names: []string{"cilium", "cilium-operator"}, Labels: []string{"cilium-agent", "cilium-operator"}},
+	{Name: "Flannel", Category: AddonCategoryCNI, Names: []string{"kube-flannel-ds", "flannel"}, Labels: []string{"flannel"}},
+	{Name: "Weave Net", Category: AddonCategoryCNI, Names: []string{"weave-net"}, Labels: []string{"weave-net"}},
+	{Name: "Amazon VPC CNI", Category: AddonCategoryCNI, Names: []string{"aws-node"}, Namespaces: []string{"kube-system"}},
+	{Name: "Azure CNI", Category: AddonCategoryCNI, Names: []string{"azure-cni", "azure-cns"}, Namespaces: []string{"kube-system"}},
+	{Name: "kube-proxy", Category: AddonCategoryProxy, Names: []string{"kube-proxy"}, Namespaces: []string{"kube-system"}},
+	{Name: "Amazon EBS CSI Driver", Category: AddonCategoryCSI, Names: []string{"ebs-csi"}, Labels: []string{"aws-ebs-csi-driver"}, Drivers: []string{"ebs.csi.aws.com"}},
+	{Name: "Amazon EFS CSI Driver", Category: AddonCategoryCSI, Names: []string{"efs-csi"}, Labels: []string{"aws-efs-csi-driver"}, Drivers: []string{"efs.csi.aws.com"}},
+	{Name: "GCE Persistent Disk CSI Driver", Category: AddonCategoryCSI, Names: []string{"pdcsi-node", "csi-gce-pd"}, Drivers: []string{"pd.csi.storage.gke.io"}},
+	{Name: "Azure Disk CSI Driver", Category: AddonCategoryCSI, Names: []string{"csi-azuredisk"}, Drivers: []string{"disk.csi.azure.com"}},
+	{Name: "Azure File CSI Driver", Category: AddonCategoryCSI, Names: []string{"csi-azurefile"}, Drivers: []string{"file.csi.azure.com"}},
+	{Name: "Secrets Store CSI Driver", Category: AddonCategoryCSI, Names: []string{"csi-secrets-store"}, Labels: []string{"secrets-store-csi-driver"}, Drivers: []string{"secrets-store.csi.k8s.io"}},
+	{Name: "Longhorn", Category: AddonCategoryCSI, Names: []string{"longhorn-manager", "longhorn-csi-plugin", "longhorn-driver-deployer"}, Drivers: []string{"driver.longhorn.io"}},
+	{Name: "Ceph CSI", Category: AddonCategoryCSI, Names: []string{"csi-rbdplugin", "csi-cephfsplugin"}, Drivers: []string{"rbd.csi.ceph.com", "cephfs.csi.ceph.com"}},
+}
+
+// AddonComponent is a Deployment or DaemonSet that belongs to an addon
+type AddonComponent struct {
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`
+	Image   string `json:"image"`
+	Version string `json:"version"`
+	Desired int32  `json:"desired"`
+	Ready   int32  `json:"ready"`
+	Health  string `json:"health"`
+}
+
+// Addon is a detected addon with its version and health
+type Addon struct {
+	Name       string           `json:"name"`
+	Category   string           `json:"category"`
+	Namespace  string           `json:"namespace,omitempty"`
+	Version    string           `json:"version"`
+	Health     string           `json:"health"` // the worst health of its components
+	Components []AddonComponent `json:"components"`
+	Drivers    []string         `json:"drivers,omitempty"` // registered CSIDrivers
+}
+
+// AddonReport lists the addons found in a cluster
+type AddonReport struct {
+	GeneratedAt time.Time      `json:"generatedAt"`
+	Addons      []Addon        `json:"addons"`
+	Counts      map[string]int `json:"counts"`  // addons per category
+	Skipped     []string       `json:"skipped"` // resources that could not be listed
+}
+
+// addonWorkload is a Deployment or DaemonSet reduced to what addon detection needs
+type addonWorkload struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Labels    map[string]string
+	Image     string // image of the first container
+	Desired   int32
+	Ready     int32
+}
+
+// AddonsHandler reports the well-known addons installed in a cluster
+type AddonsHandler struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	logger        *logger.Logger
+	tracingHelper *tracing.TracingHelper
+}
+
+// NewAddonsHandler creates a new AddonsHandler instance
+func NewAddonsHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger) *AddonsHandler {
+	return &AddonsHandler{
+		store:         store,
+		clientFactory: clientFactory,
+		logger:        log,
+		tracingHelper: tracing.GetTracingHelper(),
+	}
+}
+
+// getClient gets the Kubernetes client for the current request
+func (h *AddonsHandler) getClient(c *gin.Context) (kubernetes.Interface, error) {
+	configID := c.Query("config")
+	cluster := c.Query("cluster")
+
+	if configID == "" {
+		return nil, fmt.Errorf("config parameter is required")
+	}
+
+	config, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, fmt.Errorf("config not found: %w", err)
+	}
+
+	client, err := h.clientFactory.GetClientForConfig(config, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Kubernetes client: %w", err)
+	}
+
+	return client, nil
+}
+
+// matches reports whether a workload belongs to the addon
+func (s *addonSpec) matches(w *addonWorkload) bool {
+	if len(s.Namespaces) > 0 && !containsString(s.Namespaces, w.Namespace) {
+		return false
+	}
+	for _, name := range s.Names {
+		if w.Name == name || strings.HasPrefix(w.Name, name+"-") {
+			return true
+		}
+	}
+	for _, key := range []string{"app.kubernetes.io/name", "k8s-app", "app"} {
+		if value := w.Labels[key]; value != "" && containsString(s.Labels, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// imageVersion returns the tag of an image reference, ignoring any digest
+func imageVersion(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	slash := strings.LastIndex(image, "/")
+	colon := strings.LastIndex(image, ":")
+	if colon <= slash {
+		return ""
+	}
+	return image[colon+1:]
+}
+
+// replicaHealth rates a workload by how many of its desired replicas are ready
+func replicaHealth(desired, ready int32) string {
+	switch {
+	case ready <= 0:
+		return AddonDown
+	case ready < desired:
+		return AddonDegraded
+	}
+	return AddonHealthy
+}
+
+// detectAddons groups workloads into the catalog's addons per namespace and attaches the
+// registered CSIDrivers. Drivers without a recognized workload are reported on their own, since
+// managed clusters often run the node plugin outside the visible namespaces.
+func detectAddons(workloads []addonWorkload, drivers []storagev1.CSIDriver) []Addon {
+	byKey := map[string]*Addon{}
+	var keys []string
+	for i := range workloads {
+		w := &workloads[i]
+		for j := range addonCatalog {
+			spec := &addonCatalog[j]
+			if !spec.matches(w) {
+				continue
+			}
+			key := spec.Name + "/" + w.Namespace
+			addon, ok := byKey[key]
+			if !ok {
+				addon = &Addon{Name: spec.Name, Category: spec.Category, Namespace: w.Namespace, Components: []AddonComponent{}}
+				byKey[key] = addon
+				keys = append(keys, key)
+			}
+			addon.Components = append(addon.Components, AddonComponent{
+				Kind:    w.Kind,
+				Name:    w.Name,
+				Image:   w.Image,
+				Version: imageVersion(w.Image),
+				Desired: w.Desired,
+				Ready:   w.Ready,
+				Health:  replicaHealth(w.Desired, w.Ready),
+			})
+			break
+		}
+	}
+
+	registered := map[string]bool{}
+	for _, driver := range drivers {
+		registered[driver.Name] = true
+	}
+	claimed := map[string]bool{}
+	for _, key := range keys {
+		addon := byKey[key]
+		for j := range addonCatalog {
+			if addonCatalog[j].Name != addon.Name {
+				continue
+			}
+			for _, driver := range addonCatalog[j].Drivers {
+				if registered[driver] {
+					addon.Drivers = append(addon.Drivers, driver)
+					claimed[driver] = true
+				}
+			}
+		}
+	}
+
+	addons := make([]Addon, 0, len(keys)+len(drivers))
+	for _, key := range keys {
+		addon := byKey[key]
+		sort.Slice(addon.Components, func(i, j int) bool { return addon.Components[i].Name < addon.Components[j].Name })
+		addon.Health = AddonHealthy
+		for _, component := range addon.Components {
+			if addon.Version == "" {
+				addon.Version = component.Version
+			}
+			if addonHealthRank[component.Health] > addonHealthRank[addon.Health] {
+				addon.Health = component.Health
+			}
+		}
+		addons = append(addons, *addon)
+	}
+	for _, driver := range drivers {
+		if claimed[driver.Name] {
+			continue
+		}
+		name := driver.Name
+		for j := range addonCatalog {
+			if containsString(addonCatalog[j].Drivers, driver.Name) {
+				name = addonCatalog[j].Name
+			}
+		}
+		addons = append(addons, Addon{
+			Name:       name,
+			Category:   AddonCategoryCSI,
+			Health:     AddonRegistered,
+			Components: []AddonComponent{},
+			Drivers:    []string{driver.Name},
+		})
+	}
+
+	sort.SliceStable(addons, func(i, j int) bool {
+		if addons[i].Category != addons[j].Category {
+			return addons[i].Category < addons[j].Category
+		}
+		if addons[i].Name != addons[j].Name {
+			return addons[i].Name < addons[j].Name
+		}
+		return addons[i].Namespace < addons[j].Namespace
+	})
+	return addons
+}
+
+// firstImage returns the image of the first container of a pod template
+func firstImage(template *v1.PodTemplateSpec) string {
+	if len(template.Spec.Containers) == 0 {
+		return ""
+	}
+	return template.Spec.Containers[0].Image
+}
+
+// loadAddonWorkloads lists Deployments, DaemonSets and CSIDrivers across the cluster, recording
+// resources that could not be listed
+func loadAddonWorkloads(ctx context.Context, client kubernetes.Interface) ([]addonWorkload, []storagev1.CSIDriver, []string, error) {
+	var workloads []addonWorkload
+	var drivers []storagev1.CSIDriver
+	var skipped []string
+	opts := metav1.ListOptions{}
+	list := func(resource string, fn func() error) error {
+		if err := fn(); err != nil {
+			if apierrors.IsForbidden(err) {
+				skipped = append(skipped, resource)
+				return nil
+			}
+			return fmt.Errorf("failed to list %s: %w", resource, err)
+		}
+		return nil
+	}
+
+	err := list("deployments", func() error {
+		l, err := client.AppsV1().Deployments("").List(ctx, opts)
+		if err == nil {
+			for i := range l.Items {
+				d := &l.Items[i]
+				desired := int32(1)
+				if d.Spec.Replicas != nil {
+					desired = *d.Spec.Replicas
+				}
+				workloads = append(workloads, addonWorkload{
+					Kind: "Deployment", Namespace: d.Namespace, Name: d.Name, Labels: d.Labels,
+					Image: firstImage(&d.Spec.Template), Desired: desired, Ready: d.Status.ReadyReplicas,
+				})
+			}
+		}
+		return err
+	})
+	if err != nil {
+		return nil, nil, skipped, err
+	}
+	err = list("daemonsets", func() error {
+		l, err := client.AppsV1().DaemonSets("").List(ctx, opts)
+		if err == nil {
+			for i := range l.Items {
+				ds := &l.Items[i]
+				workloads = append(workloads, addonWorkload{
+					Kind: "DaemonSet", Namespace: ds.Namespace, Name: ds.Name, Labels: ds.Labels,
+					Image: firstImage(&ds.Spec.Template), Desired: ds.Status.DesiredNumberScheduled, Ready: ds.Status.NumberReady,
+				})
+			}
+		}
+		return err
+	})
+	if err != nil {
+		return nil, nil, skipped, err
+	}
+	err = list("csidrivers", func() error {
+		l, err := client.StorageV1().CSIDrivers().List(ctx, opts)
+		if err == nil {
+			drivers = l.Items
+		}
+		return err
+	})
+	if err != nil {
+		return nil, nil, skipped, err
+	}
+	return workloads, drivers, skipped, nil
+}
+
+// GetAddonInventory detects well-known cluster addons
+// @Summary Get cluster addon inventory
+// @Description Detects common addons (CoreDNS, ingress controllers, metrics-server, cert-manager, CNI plugins, kube-proxy and CSI drivers) from the names and labels of Deployments and DaemonSets and from registered CSIDrivers. Each addon reports the version from its image tag and its health: healthy when every replica is ready, degraded when some are not and down when none are. CSIDrivers without a recognized workload are reported as registered.
+// @Tags Cluster
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Success 200 {object} AddonReport
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/addons [get]
+func (h *AddonsHandler) GetAddonInventory(c *gin.Context) {
+	ctx, span := h.tracingHelper.StartAuthSpan(c.Request.Context(), "addons.inventory")
+	defer span.End()
+
+	client, err := h.getClient(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for addon inventory")
+		h.tracingHelper.RecordError(span, err, "Failed to get Kubernetes client")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	apiCtx, apiSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "addon-workloads", "")
+	workloads, drivers, skipped, err := loadAddonWorkloads(apiCtx, client)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list workloads for addon inventory")
+		h.tracingHelper.RecordError(apiSpan, err, "Failed to list resources")
+		apiSpan.End()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(apiSpan, "Workloads listed")
+	apiSpan.End()
+
+	report := AddonReport{
+		GeneratedAt: time.Now(),
+		Addons:      detectAddons(workloads, drivers),
+		Counts:      map[string]int{},
+		Skipped:     skipped,
+	}
+	if report.Skipped == nil {
+		report.Skipped = []string{}
+	}
+	for _, addon := range report.Addons {
+		report.Counts[addon.Category]++
+	}
+
+	h.tracingHelper.RecordSuccess(span, fmt.Sprintf("Detected %d addons", len(report.Addons)))
+	c.JSON(http.StatusOK, report)
+}
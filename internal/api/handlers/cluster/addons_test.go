@@ -0,0 +1,67 @@
+package cluster
+
+import (
+	"testing"
+
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDetectAddons(t *testing.T) {
+	workloads := []addonWorkload{
+		{Kind: "Deployment", Namespace: "kube-system", Name: "coredns", Labels: map[string]string{"k8s-app": "kube-dns"}, Image: "registry.k8s.io/coredns/coredns:v1.11.1", Desired: 2, Ready: 2},
+		{Kind: "Deployment", Namespace: "cert-manager", Name: "cert-manager", Image: "quay.io/jetstack/cert-manager-controller:v1.14.4", Desired: 1, Ready: 1},
+		{Kind: "Deployment", Namespace: "cert-manager", Name: "cert-manager-webhook", Labels: map[string]string{"app.kubernetes.io/name": "webhook"}, Image: "quay.io/jetstack/cert-manager-webhook:v1.14.4", Desired: 1, Ready: 0},
+		{Kind: "Deployment", Namespace: "ingress", Name: "edge", Labels: map[string]string{"app.kubernetes.io/name": "ingress-nginx"}, Image: "registry.k8s.io/ingress-nginx/controller:v1.10.0@sha256:abc", Desired: 3, Ready: 2},
+		{Kind: "DaemonSet", Namespace: "kube-system", Name: "aws-node", Image: "602401143452.dkr.ecr.us-west-2.amazonaws.com/amazon-k8s-cni:v1.18.0", Desired: 4, Ready: 4},
+		{Kind: "DaemonSet", Namespace: "kube-system", Name: "ebs-csi-node", Image: "public.ecr.aws/ebs-csi-driver/aws-ebs-csi-driver:v1.28.0", Desired: 4, Ready: 4},
+		{Kind: "DaemonSet", Namespace: "monitoring", Name: "aws-node", Image: "example/exporter:1.0", Desired: 4, Ready: 4},
+		{Kind: "Deployment", Namespace: "shop", Name: "checkout", Image: "example/checkout:2.1", Desired: 2, Ready: 2},
+	}
+	drivers := []storagev1.CSIDriver{
+		{ObjectMeta: metav1.ObjectMeta{Name: "ebs.csi.aws.com"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "efs.csi.aws.com"}},
+	}
+
+	addons := detectAddons(workloads, drivers)
+	byName := map[string]Addon{}
+	for _, addon := range addons {
+		byName[addon.Name] = addon
+	}
+	if len(addons) != 6 {
+		t.Fatalf("detectAddons() found %d addons, want 6: %+v", len(addons), addons)
+	}
+
+	if dns := byName["CoreDNS"]; dns.Category != AddonCategoryDNS || dns.Version != "v1.11.1" || dns.Health != AddonHealthy {
+		t.Errorf("CoreDNS = %+v", dns)
+	}
+	if cm := byName["cert-manager"]; len(cm.Components) != 2 || cm.Version != "v1.14.4" || cm.Health != AddonDown {
+		t.Errorf("cert-manager = %+v, want two components and down", cm)
+	}
+	if nginx := byName["ingress-nginx"]; nginx.Version != "v1.10.0" || nginx.Health != AddonDegraded || nginx.Namespace != "ingress" {
+		t.Errorf("ingress-nginx = %+v", nginx)
+	}
+	if cni := byName["Amazon VPC CNI"]; cni.Namespace != "kube-system" || cni.Version != "v1.18.0" {
+		t.Errorf("Amazon VPC CNI = %+v, want only the kube-system DaemonSet", cni)
+	}
+	if ebs := byName["Amazon EBS CSI Driver"]; len(ebs.Drivers) != 1 || ebs.Drivers[0] != "ebs.csi.aws.com" || ebs.Health != AddonHealthy {
+		t.Errorf("EBS CSI driver = %+v", ebs)
+	}
+	if efs := byName["Amazon EFS CSI Driver"]; efs.Health != AddonRegistered || len(efs.Components) != 0 {
+		t.Errorf("EFS CSI driver = %+v, want registered without components", efs)
+	}
+}
+
+func TestImageVersion(t *testing.T) {
+	tests := map[string]string{
+		"registry.k8s.io/metrics-server/metrics-server:v0.7.0": "v0.7.0",
+		"localhost:5000/coredns":                               "",
+		"traefik:3.0@sha256:abc":                               "3.0",
+		"nginx":                                                "",
+	}
+	for image, want := range tests {
+		if got := imageVersion(image); got != want {
+			t.Errorf("imageVersion(%q) = %q, want %q", image, got, want)
+		}
+	}
+}
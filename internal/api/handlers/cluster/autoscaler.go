@@ -0,0 +1,431 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/internal/tracing"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+// Cluster Autoscaler publishes its state in this ConfigMap
+const (
+	clusterAutoscalerStatusNamespace = "kube-system"
+	clusterAutoscalerStatusConfigMap = "cluster-autoscaler-status"
+	karpenterNodePoolLabel           = "karpenter.sh/nodepool"
+)
+
+// defaultAutoscalerEventWindow limits provisioning events to recent activity
+const defaultAutoscalerEventWindow = 6 * time.Hour
+
+// autoscalerEventReasons are event reasons emitted by Cluster Autoscaler and Karpenter for scaling decisions
+var autoscalerEventReasons = map[string]bool{
+	// Cluster Autoscaler
+	"TriggeredScaleUp": true, "NotTriggerScaleUp": true, "ScaledUpGroup": true, "ScaleDown": true,
+	"ScaleDownEmpty": true, "ScaleDownFailed": true, "FailedToScaleUpGroup": true,
+	// Karpenter
+	"Launched": true, "Registered": true, "Initialized": true, "Nominated": true, "Disrupting": true,
+	"DisruptionBlocked": true, "DisruptionTerminating": true, "TerminatingOnInterruption": true,
+	"InsufficientCapacityError": true, "FailedLaunch": true,
+}
+
+var (
+	karpenterNodePoolVersions  = []string{"v1", "v1beta1"}
+	karpenterNodeClaimVersions = []string{"v1", "v1beta1"}
+)
+
+// NodeGroupStatus is the current and allowed size of a node group or NodePool
+type NodeGroupStatus struct {
+	Source  string            `json:"source"` // cluster-autoscaler or karpenter
+	Name    string            `json:"name"`
+	Health  string            `json:"health,omitempty"`
+	Current int               `json:"current"`
+	Ready   int               `json:"ready"`
+	Target  int               `json:"target,omitempty"`
+	MinSize *int              `json:"minSize,omitempty"`
+	MaxSize *int              `json:"maxSize,omitempty"`
+	Limits  map[string]string `json:"limits,omitempty"` // Karpenter resource limits
+	Usage   map[string]string `json:"usage,omitempty"`  // Karpenter provisioned resources
+}
+
+// PendingPod is an unschedulable pod waiting on scale-up
+type PendingPod struct {
+	Name              string `json:"name"`
+	Namespace         string `json:"namespace"`
+	Reason            string `json:"reason"`
+	Message           string `json:"message"`
+	PendingSince      string `json:"pendingSince"`
+	ScaleUpTriggered  bool   `json:"scaleUpTriggered"`
+	AutoscalerMessage string `json:"autoscalerMessage,omitempty"`
+}
+
+// AutoscalerEvent is a recent provisioning or termination decision
+type AutoscalerEvent struct {
+	Time      string `json:"time"`
+	Source    string `json:"source"`
+	Reason    string `json:"reason"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Message   string `json:"message"`
+	Type      string `json:"type"`
+	Count     int32  `json:"count"`
+}
+
+// NodeClaimInfo summarizes a Karpenter NodeClaim
+type NodeClaimInfo struct {
+	Name         string `json:"name"`
+	NodePool     string `json:"nodePool"`
+	NodeName     string `json:"nodeName,omitempty"`
+	InstanceType string `json:"instanceType,omitempty"`
+	CapacityType string `json:"capacityType,omitempty"`
+	Zone         string `json:"zone,omitempty"`
+	Ready        bool   `json:"ready"`
+	Age          string `json:"age"`
+}
+
+// AutoscalerStatusResponse combines autoscaler activity for the nodes/overview pages
+type AutoscalerStatusResponse struct {
+	Autoscalers []string          `json:"autoscalers"`
+	NodeGroups  []NodeGroupStatus `json:"nodeGroups"`
+	PendingPods []PendingPod      `json:"pendingPods"`
+	Events      []AutoscalerEvent `json:"events"`
+	NodeClaims  []NodeClaimInfo   `json:"nodeClaims"`
+}
+
+// AutoscalerHandler surfaces Cluster Autoscaler and Karpenter activity
+type AutoscalerHandler struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	logger        *logger.Logger
+	tracingHelper *tracing.TracingHelper
+}
+
+// NewAutoscalerHandler creates a new AutoscalerHandler instance
+func NewAutoscalerHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger) *AutoscalerHandler {
+	return &AutoscalerHandler{
+		store:         store,
+		clientFactory: clientFactory,
+		logger:        log,
+		tracingHelper: tracing.GetTracingHelper(),
+	}
+}
+
+// getClients gets the typed and dynamic Kubernetes clients for the current request
+func (h *AutoscalerHandler) getClients(c *gin.Context) (kubernetes.Interface, dynamic.Interface, error) {
+	configID := c.Query("config")
+	cluster := c.Query("cluster")
+
+	if configID == "" {
+		return nil, nil, fmt.Errorf("config parameter is required")
+	}
+
+	config, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("config not found: %w", err)
+	}
+
+	client, err := h.clientFactory.GetClientForConfig(config, cluster)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get Kubernetes client: %w", err)
+	}
+
+	dynamicClient, err := h.clientFactory.GetDynamicClientForConfig(config, cluster)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get dynamic client: %w", err)
+	}
+
+	return client, dynamicClient, nil
+}
+
+// legacyNodeGroupPattern matches node groups in the plain-text status format used before Cluster Autoscaler 1.30
+var legacyNodeGroupPattern = regexp.MustCompile(`Name:\s+(\S+)\s*\n\s*Health:\s+(\w+)\s+\(ready=(\d+).*?registered=(\d+).*?cloudProviderTarget=(\d+)\s+\(minSize=(\d+),\s*maxSize=(\d+)\)`)
+
+// parseClusterAutoscalerStatus extracts node group sizes from the cluster-autoscaler-status ConfigMap
+func parseClusterAutoscalerStatus(status string) []NodeGroupStatus {
+	groups := []NodeGroupStatus{}
+
+	if matches := legacyNodeGroupPattern.FindAllStringSubmatch(status, -1); len(matches) > 0 {
+		for _, m := range matches {
+			ready, _ := strconv.Atoi(m[3])
+			registered, _ := strconv.Atoi(m[4])
+			target, _ := strconv.Atoi(m[5])
+			minSize, _ := strconv.Atoi(m[6])
+			maxSize, _ := strconv.Atoi(m[7])
+			groups = append(groups, NodeGroupStatus{
+				Source: "cluster-autoscaler", Name: m[1], Health: m[2],
+				Current: registered, Ready: ready, Target: target, MinSize: &minSize, MaxSize: &maxSize,
+			})
+		}
+		return groups
+	}
+
+	// Structured YAML format
+	var parsed struct {
+		NodeGroups []struct {
+			Name   string `json:"name"`
+			Health struct {
+				Status     string `json:"status"`
+				NodeCounts struct {
+					Registered struct {
+						Total int `json:"total"`
+						Ready int `json:"ready"`
+					} `json:"registered"`
+				} `json:"nodeCounts"`
+				CloudProviderTarget int `json:"cloudProviderTarget"`
+				MinSize             int `json:"minSize"`
+				MaxSize             int `json:"maxSize"`
+			} `json:"health"`
+		} `json:"nodeGroups"`
+	}
+	if err := yaml.Unmarshal([]byte(status), &parsed); err != nil {
+		return groups
+	}
+	for _, ng := range parsed.NodeGroups {
+		minSize, maxSize := ng.Health.MinSize, ng.Health.MaxSize
+		groups = append(groups, NodeGroupStatus{
+			Source: "cluster-autoscaler", Name: ng.Name, Health: ng.Health.Status,
+			Current: ng.Health.NodeCounts.Registered.Total, Ready: ng.Health.NodeCounts.Registered.Ready,
+			Target: ng.Health.CloudProviderTarget, MinSize: &minSize, MaxSize: &maxSize,
+		})
+	}
+	return groups
+}
+
+// listKarpenter lists resources from the first served Karpenter API version
+func listKarpenter(ctx context.Context, client dynamic.Interface, resource string, versions []string) ([]unstructured.Unstructured, bool, error) {
+	for _, version := range versions {
+		gvr := schema.GroupVersionResource{Group: "karpenter.sh", Version: version, Resource: resource}
+		list, err := client.Resource(gvr).List(ctx, metav1.ListOptions{})
+		if err == nil {
+			return list.Items, true, nil
+		}
+		if !apierrors.IsNotFound(err) {
+			return nil, true, err
+		}
+	}
+	return nil, false, nil
+}
+
+// stringMap converts an unstructured map of quantities to strings
+func stringMap(obj map[string]interface{}, fields ...string) map[string]string {
+	values, found, _ := unstructured.NestedMap(obj, fields...)
+	if !found {
+		return nil
+	}
+	out := make(map[string]string, len(values))
+	for k, v := range values {
+		out[k] = fmt.Sprint(v)
+	}
+	return out
+}
+
+// GetAutoscalerStatus reports node group sizes, pending pods and recent autoscaler decisions
+// @Summary Get autoscaler status
+// @Description Surfaces Cluster Autoscaler and Karpenter activity: pending pods blocked on scale-up, recent node provisioning and termination decisions, Karpenter NodeClaims and current vs max node group sizes
+// @Tags Cluster
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param window query string false "How far back to include autoscaler events (Go duration, default 6h)"
+// @Success 200 {object} AutoscalerStatusResponse "Autoscaler status"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/autoscaler/status [get]
+func (h *AutoscalerHandler) GetAutoscalerStatus(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	client, dynamicClient, err := h.getClients(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for autoscaler status")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
+
+	window := defaultAutoscalerEventWindow
+	if v := c.Query("window"); v != "" {
+		parsed, err := time.ParseDuration(v)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "window must be a positive duration such as 1h or 30m"})
+			return
+		}
+		window = parsed
+	}
+
+	reqCtx := c.Request.Context()
+	response := AutoscalerStatusResponse{
+		Autoscalers: []string{},
+		NodeGroups:  []NodeGroupStatus{},
+		PendingPods: []PendingPod{},
+		Events:      []AutoscalerEvent{},
+		NodeClaims:  []NodeClaimInfo{},
+	}
+
+	_, detectSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "get", "autoscaler-state", "")
+	defer detectSpan.End()
+
+	// Cluster Autoscaler
+	if cm, err := client.CoreV1().ConfigMaps(clusterAutoscalerStatusNamespace).Get(reqCtx, clusterAutoscalerStatusConfigMap, metav1.GetOptions{}); err == nil {
+		response.Autoscalers = append(response.Autoscalers, "cluster-autoscaler")
+		response.NodeGroups = append(response.NodeGroups, parseClusterAutoscalerStatus(cm.Data["status"])...)
+	} else if !apierrors.IsNotFound(err) && !apierrors.IsForbidden(err) {
+		h.logger.WithError(err).Warn("Failed to read cluster autoscaler status")
+	}
+
+	// Karpenter
+	nodeClaims, karpenterInstalled, err := listKarpenter(reqCtx, dynamicClient, "nodeclaims", karpenterNodeClaimVersions)
+	if err != nil {
+		h.logger.WithError(err).Warn("Failed to list Karpenter NodeClaims")
+	}
+	if karpenterInstalled {
+		response.Autoscalers = append(response.Autoscalers, "karpenter")
+		claimsPerPool := map[string]int{}
+		readyPerPool := map[string]int{}
+		for i := range nodeClaims {
+			claim := &nodeClaims[i]
+			labels := claim.GetLabels()
+			info := NodeClaimInfo{
+				Name:         claim.GetName(),
+				NodePool:     labels[karpenterNodePoolLabel],
+				InstanceType: labels["node.kubernetes.io/instance-type"],
+				CapacityType: labels["karpenter.sh/capacity-type"],
+				Zone:         labels["topology.kubernetes.io/zone"],
+				Age:          claim.GetCreationTimestamp().Format(time.RFC3339),
+			}
+			info.NodeName, _, _ = unstructured.NestedString(claim.Object, "status", "nodeName")
+			conditions, _, _ := unstructured.NestedSlice(claim.Object, "status", "conditions")
+			for _, entry := range conditions {
+				if m, ok := entry.(map[string]interface{}); ok && m["type"] == "Ready" {
+					info.Ready = m["status"] == "True"
+				}
+			}
+			claimsPerPool[info.NodePool]++
+			if info.Ready {
+				readyPerPool[info.NodePool]++
+			}
+			response.NodeClaims = append(response.NodeClaims, info)
+		}
+
+		nodePools, _, err := listKarpenter(reqCtx, dynamicClient, "nodepools", karpenterNodePoolVersions)
+		if err != nil {
+			h.logger.WithError(err).Warn("Failed to list Karpenter NodePools")
+		}
+		for i := range nodePools {
+			pool := &nodePools[i]
+			response.NodeGroups = append(response.NodeGroups, NodeGroupStatus{
+				Source:  "karpenter",
+				Name:    pool.GetName(),
+				Current: claimsPerPool[pool.GetName()],
+				Ready:   readyPerPool[pool.GetName()],
+				Limits:  stringMap(pool.Object, "spec", "limits"),
+				Usage:   stringMap(pool.Object, "status", "resources"),
+			})
+		}
+	}
+	h.tracingHelper.RecordSuccess(detectSpan, fmt.Sprintf("Detected %d autoscalers", len(response.Autoscalers)))
+
+	_, podsSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "events", "")
+	defer podsSpan.End()
+
+	events, err := client.CoreV1().Events("").List(reqCtx, metav1.ListOptions{})
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list events for autoscaler status")
+		h.tracingHelper.RecordError(podsSpan, err, "Failed to list events")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Latest autoscaler verdict per pod, used to annotate pending pods
+	since := time.Now().Add(-window)
+	podVerdicts := map[string]v1.Event{}
+	for _, event := range events.Items {
+		if !autoscalerEventReasons[event.Reason] {
+			continue
+		}
+		last := event.LastTimestamp.Time
+		if last.IsZero() {
+			last = event.EventTime.Time
+		}
+		if event.InvolvedObject.Kind == "Pod" {
+			key := event.InvolvedObject.Namespace + "/" + event.InvolvedObject.Name
+			if prev, ok := podVerdicts[key]; !ok || prev.LastTimestamp.Before(&event.LastTimestamp) {
+				podVerdicts[key] = event
+			}
+		}
+		if last.Before(since) {
+			continue
+		}
+		source := event.Source.Component
+		if source == "" {
+			source = event.ReportingController
+		}
+		response.Events = append(response.Events, AutoscalerEvent{
+			Time:      last.Format(time.RFC3339),
+			Source:    source,
+			Reason:    event.Reason,
+			Kind:      event.InvolvedObject.Kind,
+			Name:      event.InvolvedObject.Name,
+			Namespace: event.InvolvedObject.Namespace,
+			Message:   event.Message,
+			Type:      event.Type,
+			Count:     event.Count,
+		})
+	}
+	sort.Slice(response.Events, func(i, j int) bool { return response.Events[i].Time > response.Events[j].Time })
+
+	pods, err := client.CoreV1().Pods("").List(reqCtx, metav1.ListOptions{FieldSelector: "status.phase=Pending"})
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list pending pods for autoscaler status")
+		h.tracingHelper.RecordError(podsSpan, err, "Failed to list pending pods")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	for _, pod := range pods.Items {
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type != v1.PodScheduled || cond.Status != v1.ConditionFalse || cond.Reason != v1.PodReasonUnschedulable {
+				continue
+			}
+			pending := PendingPod{
+				Name:         pod.Name,
+				Namespace:    pod.Namespace,
+				Reason:       cond.Reason,
+				Message:      cond.Message,
+				PendingSince: cond.LastTransitionTime.Format(time.RFC3339),
+			}
+			if verdict, ok := podVerdicts[pod.Namespace+"/"+pod.Name]; ok {
+				pending.ScaleUpTriggered = verdict.Reason == "TriggeredScaleUp" || verdict.Reason == "Nominated"
+				pending.AutoscalerMessage = verdict.Message
+			}
+			response.PendingPods = append(response.PendingPods, pending)
+		}
+	}
+	sort.Slice(response.PendingPods, func(i, j int) bool {
+		return response.PendingPods[i].PendingSince < response.PendingPods[j].PendingSince
+	})
+	h.tracingHelper.RecordSuccess(podsSpan, fmt.Sprintf("Found %d pending pods and %d autoscaler events", len(response.PendingPods), len(response.Events)))
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,73 @@
+package cluster
+
+import (
+	"testing"
+)
+
+func TestParseClusterAutoscalerStatus(t *testing.T) {
+	type group struct {
+		name                                     string
+		health                                   string
+		current, ready, target, minSize, maxSize int
+	}
+	tests := []struct {
+		name     string
+		status   string
+		expected []group
+	}{
+		{
+			name: "Legacy text format",
+			status: `Cluster-autoscaler status at 2024-01-01 10:00:00 +0000 UTC:
+Cluster-wide:
+  Health:      Healthy (ready=3 unready=0 notStarted=0 longNotStarted=0 registered=3 longUnregistered=0)
+
+NodeGroups:
+  Name:        eks-workers
+  Health:      Healthy (ready=2 unready=1 notStarted=0 longNotStarted=0 registered=3 longUnregistered=0 cloudProviderTarget=3 (minSize=1, maxSize=10))
+               LastProbeTime:      2024-01-01 10:00:00 +0000 UTC
+`,
+			expected: []group{{"eks-workers", "Healthy", 3, 2, 3, 1, 10}},
+		},
+		{
+			name: "Structured YAML format",
+			status: `time: 2024-01-01 10:00:00 +0000 UTC
+autoscalerStatus: Running
+nodeGroups:
+- name: pool-a
+  health:
+    status: Unhealthy
+    nodeCounts:
+      registered:
+        total: 4
+        ready: 3
+    cloudProviderTarget: 5
+    minSize: 2
+    maxSize: 6
+`,
+			expected: []group{{"pool-a", "Unhealthy", 4, 3, 5, 2, 6}},
+		},
+		{
+			name:     "Empty status",
+			status:   "",
+			expected: []group{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := parseClusterAutoscalerStatus(tt.status)
+			if len(result) != len(tt.expected) {
+				t.Fatalf("parseClusterAutoscalerStatus() returned %d groups, expected %d", len(result), len(tt.expected))
+			}
+			for i, got := range result {
+				if got.MinSize == nil || got.MaxSize == nil {
+					t.Fatalf("parseClusterAutoscalerStatus() group %d has no min/max size", i)
+				}
+				actual := group{got.Name, got.Health, got.Current, got.Ready, got.Target, *got.MinSize, *got.MaxSize}
+				if actual != tt.expected[i] {
+					t.Errorf("parseClusterAutoscalerStatus() group %d = %+v, expected %+v", i, actual, tt.expected[i])
+				}
+			}
+		})
+	}
+}
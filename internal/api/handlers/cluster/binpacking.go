@@ -0,0 +1,435 @@
+package cluster
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+)
+
+// strandedThreshold is the share of a node's allocatable that must be stranded before it is reported as fragmented
+const strandedThreshold = 0.10
+
+// NodeFragmentation describes how much of a node's free capacity is usable by typical pods
+type NodeFragmentation struct {
+	Node                string        `json:"node"`
+	InstanceType        string        `json:"instanceType,omitempty"`
+	Schedulable         bool          `json:"schedulable"`
+	Allocatable         k8s.Resources `json:"allocatable"`
+	Requested           k8s.Resources `json:"requested"`
+	Free                k8s.Resources `json:"free"`
+	CPUPacking          float64       `json:"cpuPacking"`
+	MemoryPacking       float64       `json:"memoryPacking"`
+	StrandedMilliCPU    int64         `json:"strandedMilliCPU"`
+	StrandedMemoryBytes int64         `json:"strandedMemoryBytes"`
+	Fragmented          bool          `json:"fragmented"`
+	Constraint          string        `json:"constraint,omitempty"` // the exhausted resource stranding the other: cpu, memory or pods
+	Drainable           bool          `json:"drainable"`
+	DrainBlockers       []string      `json:"drainBlockers,omitempty"`
+}
+
+// PodMove is where a pod would be rescheduled if its node were drained
+type PodMove struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	ToNode    string `json:"toNode"`
+}
+
+// DrainCandidate is a node whose movable pods fit on the remaining nodes
+type DrainCandidate struct {
+	Node         string    `json:"node"`
+	InstanceType string    `json:"instanceType,omitempty"`
+	Moves        []PodMove `json:"moves"`
+}
+
+// BinPackingSummary aggregates fragmentation and repacking savings across the cluster
+type BinPackingSummary struct {
+	Nodes                   int            `json:"nodes"`
+	SchedulableNodes        int            `json:"schedulableNodes"`
+	CPUPacking              float64        `json:"cpuPacking"`
+	MemoryPacking           float64        `json:"memoryPacking"`
+	StrandedMilliCPU        int64          `json:"strandedMilliCPU"`
+	StrandedMemoryBytes     int64          `json:"strandedMemoryBytes"`
+	FragmentedNodes         int            `json:"fragmentedNodes"`
+	ReferenceMilliCPUPerGiB float64        `json:"referenceMilliCPUPerGiB"` // CPU-to-memory shape of the average pod
+	EstimatedNodeSavings    int            `json:"estimatedNodeSavings"`
+	SavingsByType           map[string]int `json:"savingsByInstanceType,omitempty"`
+}
+
+// BinPackingReport is the node fragmentation and repacking analysis for a cluster
+type BinPackingReport struct {
+	Summary         BinPackingSummary   `json:"summary"`
+	Nodes           []NodeFragmentation `json:"nodes"`
+	DrainPlan       []DrainCandidate    `json:"drainPlan"`
+	Recommendations []string            `json:"recommendations"`
+}
+
+const gib = 1 << 30
+
+func instanceType(node *v1.Node) string {
+	if t := node.Labels["node.kubernetes.io/instance-type"]; t != "" {
+		return t
+	}
+	return node.Labels["beta.kubernetes.io/instance-type"]
+}
+
+func isControlPlaneNode(node *v1.Node) bool {
+	_, controlPlane := node.Labels["node-role.kubernetes.io/control-plane"]
+	_, master := node.Labels["node-role.kubernetes.io/master"]
+	return controlPlane || master
+}
+
+// isDaemonSetPod reports whether a pod is run by a DaemonSet and so disappears with its node
+func isDaemonSetPod(pod *v1.Pod) bool {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Controller != nil && *ref.Controller && ref.Kind == "DaemonSet" {
+			return true
+		}
+	}
+	return false
+}
+
+// podDrainBlocker returns why a pod would keep its node from being drained, following the
+// cluster autoscaler's scale-down rules, or "" if the pod can be moved
+func podDrainBlocker(pod *v1.Pod) string {
+	if _, mirror := pod.Annotations[v1.MirrorPodAnnotationKey]; mirror {
+		return fmt.Sprintf("%s/%s is a static pod", pod.Namespace, pod.Name)
+	}
+	safeToEvict := pod.Annotations["cluster-autoscaler.kubernetes.io/safe-to-evict"]
+	if safeToEvict == "false" {
+		return fmt.Sprintf("%s/%s is annotated safe-to-evict=false", pod.Namespace, pod.Name)
+	}
+	if safeToEvict == "true" {
+		return ""
+	}
+	hasController := false
+	for _, ref := range pod.OwnerReferences {
+		if ref.Controller != nil && *ref.Controller {
+			hasController = true
+		}
+	}
+	if !hasController {
+		return fmt.Sprintf("%s/%s is not managed by a controller", pod.Namespace, pod.Name)
+	}
+	for _, volume := range pod.Spec.Volumes {
+		if volume.EmptyDir != nil && volume.EmptyDir.Medium != v1.StorageMediumMemory {
+			return fmt.Sprintf("%s/%s uses local storage", pod.Namespace, pod.Name)
+		}
+	}
+	return ""
+}
+
+// strandedCapacity returns the free CPU and memory that a pod with the reference shape
+// (milliCPU per byte) cannot use because the other resource, or pod slots, run out first
+func strandedCapacity(free k8s.Resources, milliCPUPerByte float64) (int64, int64, string) {
+	if free.MilliCPU < 0 || free.MemoryBytes < 0 {
+		return 0, 0, ""
+	}
+	if free.Pods <= 0 {
+		return free.MilliCPU, free.MemoryBytes, "pods"
+	}
+	if milliCPUPerByte <= 0 {
+		return 0, 0, ""
+	}
+	usableCPU := float64(free.MemoryBytes) * milliCPUPerByte
+	if usableCPU < float64(free.MilliCPU) {
+		return free.MilliCPU - int64(usableCPU), 0, "memory"
+	}
+	usableMemory := float64(free.MilliCPU) / milliCPUPerByte
+	if usableMemory < float64(free.MemoryBytes) {
+		return 0, free.MemoryBytes - int64(usableMemory), "cpu"
+	}
+	return 0, 0, ""
+}
+
+// schedulableTarget reports whether pods can be rescheduled onto a node
+func schedulableTarget(node *v1.Node) bool {
+	return !node.Spec.Unschedulable && isNodeReady(node)
+}
+
+// planDrains greedily removes the least-packed nodes whose movable pods fit on the remaining
+// nodes, placing each pod on the fullest node it fits (best fit) to consolidate capacity
+func planDrains(states []*nodeCapacityState) ([]DrainCandidate, map[string][]string) {
+	blockers := map[string][]string{}
+	requested := make(map[string]k8s.Resources, len(states))
+	for _, state := range states {
+		requested[state.Node.Name] = state.Requested
+	}
+	removed := map[string]bool{}
+	// Nodes that receive pods from a drained node are kept so the plan stays consistent
+	received := map[string]bool{}
+
+	candidates := make([]*nodeCapacityState, 0, len(states))
+	for _, state := range states {
+		switch {
+		case isControlPlaneNode(state.Node):
+			blockers[state.Node.Name] = []string{"control-plane node"}
+		case !schedulableTarget(state.Node):
+			blockers[state.Node.Name] = []string{"node is cordoned or not ready"}
+		default:
+			candidates = append(candidates, state)
+		}
+	}
+	utilization := func(s *nodeCapacityState) float64 {
+		r := requested[s.Node.Name]
+		cpu := k8s.PackingPercent(r.MilliCPU, s.Allocatable.MilliCPU)
+		mem := k8s.PackingPercent(r.MemoryBytes, s.Allocatable.MemoryBytes)
+		if cpu > mem {
+			return cpu
+		}
+		return mem
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return utilization(candidates[i]) < utilization(candidates[j]) })
+
+	var plan []DrainCandidate
+	for _, candidate := range candidates {
+		if received[candidate.Node.Name] {
+			continue
+		}
+		var movable []*v1.Pod
+		for _, pod := range candidate.Pods {
+			if isDaemonSetPod(pod) {
+				continue
+			}
+			if reason := podDrainBlocker(pod); reason != "" {
+				blockers[candidate.Node.Name] = append(blockers[candidate.Node.Name], reason)
+				continue
+			}
+			movable = append(movable, pod)
+		}
+		if len(blockers[candidate.Node.Name]) > 0 {
+			continue
+		}
+		// Place the largest pods first; they are the hardest to fit
+		sort.SliceStable(movable, func(i, j int) bool {
+			a, b := k8s.PodRequests(&movable[i].Spec), k8s.PodRequests(&movable[j].Spec)
+			return a.MilliCPU+a.MemoryBytes/(64<<20) > b.MilliCPU+b.MemoryBytes/(64<<20)
+		})
+
+		trial := make(map[string]k8s.Resources, len(requested))
+		for name, r := range requested {
+			trial[name] = r
+		}
+		moves := make([]PodMove, 0, len(movable))
+		for _, pod := range movable {
+			need := k8s.PodRequests(&pod.Spec)
+			var best *nodeCapacityState
+			bestScore := -1.0
+			for _, target := range states {
+				name := target.Node.Name
+				if name == candidate.Node.Name || removed[name] || !schedulableTarget(target.Node) ||
+					!toleratesNodeTaints(pod.Spec.Tolerations, target.Node.Spec.Taints) || !matchesNodeSelection(&pod.Spec, target.Node) {
+					continue
+				}
+				state := nodeCapacityState{Node: target.Node, Allocatable: target.Allocatable, Requested: trial[name]}
+				if !state.fits(need) {
+					continue
+				}
+				score := k8s.PackingPercent(trial[name].MilliCPU, target.Allocatable.MilliCPU) + k8s.PackingPercent(trial[name].MemoryBytes, target.Allocatable.MemoryBytes)
+				if score > bestScore {
+					best, bestScore = target, score
+				}
+			}
+			if best == nil {
+				blockers[candidate.Node.Name] = append(blockers[candidate.Node.Name], fmt.Sprintf("%s/%s does not fit on any other node", pod.Namespace, pod.Name))
+				break
+			}
+			trial[best.Node.Name] = trial[best.Node.Name].Add(need)
+			moves = append(moves, PodMove{Namespace: pod.Namespace, Pod: pod.Name, ToNode: best.Node.Name})
+		}
+		if len(blockers[candidate.Node.Name]) > 0 {
+			continue
+		}
+
+		removed[candidate.Node.Name] = true
+		requested = trial
+		for _, move := range moves {
+			received[move.ToNode] = true
+		}
+		plan = append(plan, DrainCandidate{Node: candidate.Node.Name, InstanceType: instanceType(candidate.Node), Moves: moves})
+	}
+	return plan, blockers
+}
+
+// analyzeBinPacking computes stranded capacity per node and a repacking plan
+func analyzeBinPacking(states []*nodeCapacityState) BinPackingReport {
+	report := BinPackingReport{Nodes: []NodeFragmentation{}, DrainPlan: []DrainCandidate{}, Recommendations: []string{}}
+
+	// The reference pod shape is the average of running pods that would be rescheduled
+	var podTotal, allocTotal, requestedTotal k8s.Resources
+	for _, state := range states {
+		allocTotal = allocTotal.Add(state.Allocatable)
+		requestedTotal = requestedTotal.Add(state.Requested)
+		for _, pod := range state.Pods {
+			if !isDaemonSetPod(pod) {
+				podTotal = podTotal.Add(k8s.PodRequests(&pod.Spec))
+			}
+		}
+	}
+	shape := podTotal
+	if shape.MilliCPU == 0 || shape.MemoryBytes == 0 {
+		shape = allocTotal
+	}
+	var milliCPUPerByte float64
+	if shape.MemoryBytes > 0 {
+		milliCPUPerByte = float64(shape.MilliCPU) / float64(shape.MemoryBytes)
+	}
+
+	plan, blockers := planDrains(states)
+	drainable := map[string]bool{}
+	for _, candidate := range plan {
+		drainable[candidate.Node] = true
+	}
+
+	summary := &report.Summary
+	summary.Nodes = len(states)
+	summary.ReferenceMilliCPUPerGiB = milliCPUPerByte * gib
+	summary.CPUPacking = k8s.PackingPercent(requestedTotal.MilliCPU, allocTotal.MilliCPU)
+	summary.MemoryPacking = k8s.PackingPercent(requestedTotal.MemoryBytes, allocTotal.MemoryBytes)
+
+	for _, state := range states {
+		free := k8s.Resources{
+			MilliCPU:    state.Allocatable.MilliCPU - state.Requested.MilliCPU,
+			MemoryBytes: state.Allocatable.MemoryBytes - state.Requested.MemoryBytes,
+			Pods:        state.Allocatable.Pods - state.Requested.Pods,
+		}
+		entry := NodeFragmentation{
+			Node:          state.Node.Name,
+			InstanceType:  instanceType(state.Node),
+			Schedulable:   schedulableTarget(state.Node),
+			Allocatable:   state.Allocatable,
+			Requested:     state.Requested,
+			Free:          free,
+			CPUPacking:    k8s.PackingPercent(state.Requested.MilliCPU, state.Allocatable.MilliCPU),
+			MemoryPacking: k8s.PackingPercent(state.Requested.MemoryBytes, state.Allocatable.MemoryBytes),
+			Drainable:     drainable[state.Node.Name],
+			DrainBlockers: blockers[state.Node.Name],
+		}
+		if entry.Schedulable {
+			summary.SchedulableNodes++
+			entry.StrandedMilliCPU, entry.StrandedMemoryBytes, entry.Constraint = strandedCapacity(free, milliCPUPerByte)
+			entry.Fragmented = float64(entry.StrandedMilliCPU) > strandedThreshold*float64(state.Allocatable.MilliCPU) ||
+				float64(entry.StrandedMemoryBytes) > strandedThreshold*float64(state.Allocatable.MemoryBytes)
+			if !entry.Fragmented {
+				entry.Constraint = ""
+			}
+		}
+		summary.StrandedMilliCPU += entry.StrandedMilliCPU
+		summary.StrandedMemoryBytes += entry.StrandedMemoryBytes
+		if entry.Fragmented {
+			summary.FragmentedNodes++
+		}
+		report.Nodes = append(report.Nodes, entry)
+	}
+	sort.Slice(report.Nodes, func(i, j int) bool {
+		a, b := report.Nodes[i], report.Nodes[j]
+		if a.Fragmented != b.Fragmented {
+			return a.Fragmented
+		}
+		return a.Node < b.Node
+	})
+
+	report.DrainPlan = append(report.DrainPlan, plan...)
+	summary.EstimatedNodeSavings = len(plan)
+	if len(plan) > 0 {
+		summary.SavingsByType = map[string]int{}
+		for _, candidate := range plan {
+			summary.SavingsByType[candidate.InstanceType]++
+		}
+	}
+
+	report.Recommendations = binPackingRecommendations(report)
+	return report
+}
+
+func binPackingRecommendations(report BinPackingReport) []string {
+	var recommendations []string
+	summary := report.Summary
+	if summary.EstimatedNodeSavings > 0 {
+		names := make([]string, 0, len(report.DrainPlan))
+		pods := 0
+		for _, candidate := range report.DrainPlan {
+			names = append(names, candidate.Node)
+			pods += len(candidate.Moves)
+		}
+		recommendations = append(recommendations, fmt.Sprintf("%d node(s) could be drained by repacking %d pod(s) onto the remaining nodes: %v. Lower the cluster autoscaler's scale-down utilization threshold or drain them manually.", summary.EstimatedNodeSavings, pods, names))
+	}
+
+	memoryBound, cpuBound, podBound := 0, 0, 0
+	for _, node := range report.Nodes {
+		if !node.Fragmented {
+			continue
+		}
+		switch node.Constraint {
+		case "memory":
+			memoryBound++
+		case "cpu":
+			cpuBound++
+		case "pods":
+			podBound++
+		}
+	}
+	if memoryBound > 0 {
+		recommendations = append(recommendations, fmt.Sprintf("%d node(s) run out of memory while CPU is left over (%.1f cores stranded cluster-wide). Consider memory-optimized instance types or lowering memory requests of over-provisioned workloads.", memoryBound, float64(summary.StrandedMilliCPU)/1000))
+	}
+	if cpuBound > 0 {
+		recommendations = append(recommendations, fmt.Sprintf("%d node(s) run out of CPU while memory is left over (%.1f GiB stranded cluster-wide). Consider compute-optimized instance types or lowering CPU requests of over-provisioned workloads.", cpuBound, float64(summary.StrandedMemoryBytes)/gib))
+	}
+	if podBound > 0 {
+		recommendations = append(recommendations, fmt.Sprintf("%d node(s) have reached their pod limit with CPU and memory to spare. Consider raising max pods per node or consolidating small pods.", podBound))
+	}
+	if len(recommendations) == 0 {
+		recommendations = append(recommendations, "Nodes are packed efficiently; no repacking opportunities found.")
+	}
+	return recommendations
+}
+
+// GetBinPackingReport reports stranded capacity per node and nodes that could be removed by repacking
+// @Summary Get node fragmentation and bin-packing report
+// @Description Computes per-node stranded capacity (free CPU that cannot be used because memory or pod slots ran out, and vice versa) relative to the average pod shape, and greedily plans which nodes could be drained if their pods were repacked onto the others, following cluster autoscaler scale-down rules. Pod affinity and topology spread are not considered, so the plan is an estimate.
+// @Tags Cluster
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name for multi-cluster setups"
+// @Success 200 {object} BinPackingReport "Bin-packing report"
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/capacity/bin-packing [get]
+func (h *NodesHandler) GetBinPackingReport(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for bin-packing report")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Successfully obtained Kubernetes client")
+
+	_, listSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "nodes", "")
+	defer listSpan.End()
+	states, err := loadNodeCapacity(ctx, client)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to load node capacity")
+		h.tracingHelper.RecordError(listSpan, err, "Failed to load node capacity")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.AddResourceAttributes(listSpan, "", "nodes", len(states))
+	h.tracingHelper.RecordSuccess(listSpan, fmt.Sprintf("Loaded capacity for %d nodes", len(states)))
+
+	_, analysisSpan := h.tracingHelper.StartDataProcessingSpan(ctx, "analyze-bin-packing")
+	defer analysisSpan.End()
+	report := analyzeBinPacking(states)
+	h.tracingHelper.RecordSuccess(analysisSpan, fmt.Sprintf("Found %d fragmented and %d drainable nodes", report.Summary.FragmentedNodes, report.Summary.EstimatedNodeSavings))
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,81 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testNode(name string) *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status:     v1.NodeStatus{Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}}},
+	}
+}
+
+func testPod(name, cpu, memory string) *v1.Pod {
+	controller := true
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "default",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "rs", Controller: &controller}},
+		},
+		Spec: v1.PodSpec{Containers: []v1.Container{{Name: "app", Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse(cpu),
+			v1.ResourceMemory: resource.MustParse(memory),
+		}}}}},
+	}
+}
+
+func testState(node *v1.Node, pods ...*v1.Pod) *nodeCapacityState {
+	state := &nodeCapacityState{
+		Node:        node,
+		Allocatable: k8s.Resources{MilliCPU: 4000, MemoryBytes: 16 * gib, Pods: 110},
+		Pods:        pods,
+	}
+	for _, pod := range pods {
+		state.Requested = state.Requested.Add(k8s.PodRequests(&pod.Spec))
+	}
+	return state
+}
+
+func TestStrandedCapacity(t *testing.T) {
+	perByte := 1000.0 / gib // 1 core per GiB
+	cpu, mem, constraint := strandedCapacity(k8s.Resources{MilliCPU: 3000, MemoryBytes: gib, Pods: 10}, perByte)
+	if constraint != "memory" || cpu != 2000 || mem != 0 {
+		t.Errorf("got cpu=%d mem=%d constraint=%q", cpu, mem, constraint)
+	}
+	cpu, mem, constraint = strandedCapacity(k8s.Resources{MilliCPU: 500, MemoryBytes: 4 * gib, Pods: 10}, perByte)
+	if constraint != "cpu" || cpu != 0 || mem != 3*gib+gib/2 {
+		t.Errorf("got cpu=%d mem=%d constraint=%q", cpu, mem, constraint)
+	}
+	if _, _, constraint = strandedCapacity(k8s.Resources{MilliCPU: 500, MemoryBytes: gib, Pods: 0}, perByte); constraint != "pods" {
+		t.Errorf("expected pods constraint, got %q", constraint)
+	}
+}
+
+func TestPlanDrains(t *testing.T) {
+	busy := testState(testNode("busy"), testPod("a", "2", "8Gi"))
+	light := testState(testNode("light"), testPod("b", "500m", "2Gi"))
+	pinned := testPod("c", "100m", "128Mi")
+	pinned.OwnerReferences = nil
+	blocked := testState(testNode("blocked"), pinned)
+
+	plan, blockers := planDrains([]*nodeCapacityState{busy, light, blocked})
+	if len(plan) != 1 || plan[0].Node != "light" || len(plan[0].Moves) != 1 || plan[0].Moves[0].ToNode != "busy" {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+	if len(blockers["blocked"]) == 0 {
+		t.Errorf("expected the node with an unmanaged pod to be blocked")
+	}
+
+	report := analyzeBinPacking([]*nodeCapacityState{busy, light, blocked})
+	if report.Summary.EstimatedNodeSavings != 1 || len(report.Recommendations) == 0 {
+		t.Errorf("unexpected summary: %+v", report.Summary)
+	}
+}
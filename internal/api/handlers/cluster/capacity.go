@@ -0,0 +1,362 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/kubernetes"
+)
+
+// nodeCapacityState is a node's allocatable resources and what is already requested on it
+type nodeCapacityState struct {
+	Node        *v1.Node
+	Allocatable k8s.Resources
+	Requested   k8s.Resources
+	Pods        []*v1.Pod
+}
+
+// fits reports whether the requested amount can be added without exceeding allocatable
+func (n *nodeCapacityState) fits(req k8s.Resources) bool {
+	next := n.Requested.Add(req)
+	return next.MilliCPU <= n.Allocatable.MilliCPU &&
+		next.MemoryBytes <= n.Allocatable.MemoryBytes &&
+		next.Pods <= n.Allocatable.Pods
+}
+
+// isNodeReady reports whether the node's Ready condition is true
+func isNodeReady(node *v1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// toleratesNodeTaints reports whether the tolerations cover every NoSchedule and NoExecute taint
+func toleratesNodeTaints(tolerations []v1.Toleration, taints []v1.Taint) bool {
+	for i := range taints {
+		if taints[i].Effect == v1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		tolerated := false
+		for j := range tolerations {
+			if tolerations[j].ToleratesTaint(&taints[i]) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesNodeSelection evaluates nodeSelector and required node affinity terms against node
+// labels, and the matchFields of the terms against the node's name
+func matchesNodeSelection(spec *v1.PodSpec, node *v1.Node) bool {
+	nodeLabels := labels.Set(node.Labels)
+	if len(spec.NodeSelector) > 0 && !labels.SelectorFromSet(spec.NodeSelector).Matches(nodeLabels) {
+		return false
+	}
+	if spec.Affinity == nil || spec.Affinity.NodeAffinity == nil || spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return true
+	}
+	// Terms are ORed, requirements within a term are ANDed, and an empty term matches no node
+	for _, term := range spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+			continue
+		}
+		expressions, ok := nodeSelectorRequirements(term.MatchExpressions)
+		if !ok || !expressions.Matches(nodeLabels) {
+			continue
+		}
+		if matchesNodeFields(term.MatchFields, node) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesNodeFields evaluates the matchFields of a node selector term. The scheduler only
+// supports metadata.name, so any other field never matches.
+func matchesNodeFields(reqs []v1.NodeSelectorRequirement, node *v1.Node) bool {
+	for _, field := range reqs {
+		if field.Key != metav1.ObjectNameField {
+			return false
+		}
+	}
+	selector, ok := nodeSelectorRequirements(reqs)
+	return ok && selector.Matches(labels.Set{metav1.ObjectNameField: node.Name})
+}
+
+// nodeSelectorRequirements turns node selector requirements into a selector, reporting false when
+// one of them is invalid
+func nodeSelectorRequirements(reqs []v1.NodeSelectorRequirement) (labels.Selector, bool) {
+	selector := labels.NewSelector()
+	for _, expr := range reqs {
+		req, err := labels.NewRequirement(expr.Key, nodeSelectorOperator(expr.Operator), expr.Values)
+		if err != nil {
+			return nil, false
+		}
+		selector = selector.Add(*req)
+	}
+	return selector, true
+}
+
+func nodeSelectorOperator(op v1.NodeSelectorOperator) selection.Operator {
+	switch op {
+	case v1.NodeSelectorOpIn:
+		return selection.In
+	case v1.NodeSelectorOpNotIn:
+		return selection.NotIn
+	case v1.NodeSelectorOpExists:
+		return selection.Exists
+	case v1.NodeSelectorOpDoesNotExist:
+		return selection.DoesNotExist
+	case v1.NodeSelectorOpGt:
+		return selection.GreaterThan
+	case v1.NodeSelectorOpLt:
+		return selection.LessThan
+	}
+	return selection.Operator(op)
+}
+
+// loadNodeCapacity builds the allocatable/requested state of every node from non-terminal pods
+func loadNodeCapacity(ctx context.Context, client kubernetes.Interface) ([]*nodeCapacityState, error) {
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	pods, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{FieldSelector: "status.phase!=Succeeded,status.phase!=Failed"})
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]*nodeCapacityState, 0, len(nodes.Items))
+	byName := make(map[string]*nodeCapacityState, len(nodes.Items))
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		state := &nodeCapacityState{
+			Node:        node,
+			Allocatable: k8s.NodeAllocatable(node),
+		}
+		states = append(states, state)
+		byName[node.Name] = state
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if state, ok := byName[pod.Spec.NodeName]; ok {
+			state.Requested = state.Requested.Add(k8s.PodRequests(&pod.Spec))
+			state.Pods = append(state.Pods, pod)
+		}
+	}
+	return states, nil
+}
+
+// CapacitySimulationRequest describes the pods to simulate
+type CapacitySimulationRequest struct {
+	Replicas   int32       `json:"replicas"`
+	PodSpec    *v1.PodSpec `json:"podSpec,omitempty"`
+	Deployment *struct {
+		Namespace string `json:"namespace"`
+		Name      string `json:"name"`
+	} `json:"deployment,omitempty"`
+}
+
+// NodePlacement describes a node's packing before and after the simulated placement
+type NodePlacement struct {
+	Node                string  `json:"node"`
+	Eligible            bool    `json:"eligible"`
+	Reason              string  `json:"reason,omitempty"`
+	PlacedReplicas      int32   `json:"placedReplicas"`
+	CPUPackingBefore    float64 `json:"cpuPackingBefore"`
+	CPUPackingAfter     float64 `json:"cpuPackingAfter"`
+	MemoryPackingBefore float64 `json:"memoryPackingBefore"`
+	MemoryPackingAfter  float64 `json:"memoryPackingAfter"`
+}
+
+// CapacitySimulationResponse is the outcome of a scheduling simulation
+type CapacitySimulationResponse struct {
+	Fits                bool            `json:"fits"`
+	RequestedReplicas   int32           `json:"requestedReplicas"`
+	PlacedReplicas      int32           `json:"placedReplicas"`
+	UnplacedReplicas    int32           `json:"unplacedReplicas"`
+	PerReplica          k8s.Resources   `json:"perReplica"`
+	CPUPackingBefore    float64         `json:"cpuPackingBefore"`
+	CPUPackingAfter     float64         `json:"cpuPackingAfter"`
+	MemoryPackingBefore float64         `json:"memoryPackingBefore"`
+	MemoryPackingAfter  float64         `json:"memoryPackingAfter"`
+	Nodes               []NodePlacement `json:"nodes"`
+}
+
+// simulatePlacement places replicas one at a time on the eligible node with the lowest resulting packing,
+// approximating the default scheduler's least-allocated scoring
+func simulatePlacement(states []*nodeCapacityState, spec *v1.PodSpec, replicas int32) CapacitySimulationResponse {
+	perReplica := k8s.PodRequests(spec)
+	response := CapacitySimulationResponse{RequestedReplicas: replicas, PerReplica: perReplica, Nodes: []NodePlacement{}}
+
+	var totalAlloc, totalBefore k8s.Resources
+	placements := make(map[string]*NodePlacement, len(states))
+	var eligible []*nodeCapacityState
+	for _, state := range states {
+		placement := &NodePlacement{
+			Node:                state.Node.Name,
+			CPUPackingBefore:    k8s.PackingPercent(state.Requested.MilliCPU, state.Allocatable.MilliCPU),
+			MemoryPackingBefore: k8s.PackingPercent(state.Requested.MemoryBytes, state.Allocatable.MemoryBytes),
+		}
+		placements[state.Node.Name] = placement
+		totalAlloc = totalAlloc.Add(state.Allocatable)
+		totalBefore = totalBefore.Add(state.Requested)
+
+		switch {
+		case state.Node.Spec.Unschedulable:
+			placement.Reason = "node is cordoned"
+		case !isNodeReady(state.Node):
+			placement.Reason = "node is not ready"
+		case !toleratesNodeTaints(spec.Tolerations, state.Node.Spec.Taints):
+			placement.Reason = "pod does not tolerate node taints"
+		case !matchesNodeSelection(spec, state.Node):
+			placement.Reason = "node does not match nodeSelector or affinity"
+		default:
+			placement.Eligible = true
+			eligible = append(eligible, state)
+		}
+	}
+
+	// Work on copies so the caller's snapshot remains untouched
+	simulated := make(map[string]k8s.Resources, len(eligible))
+	for _, state := range eligible {
+		simulated[state.Node.Name] = state.Requested
+	}
+
+	for i := int32(0); i < replicas; i++ {
+		var best *nodeCapacityState
+		bestScore := 0.0
+		for _, state := range eligible {
+			candidate := nodeCapacityState{Node: state.Node, Allocatable: state.Allocatable, Requested: simulated[state.Node.Name]}
+			if !candidate.fits(perReplica) {
+				continue
+			}
+			after := candidate.Requested.Add(perReplica)
+			score := k8s.PackingPercent(after.MilliCPU, state.Allocatable.MilliCPU) + k8s.PackingPercent(after.MemoryBytes, state.Allocatable.MemoryBytes)
+			if best == nil || score < bestScore {
+				best, bestScore = state, score
+			}
+		}
+		if best == nil {
+			break
+		}
+		simulated[best.Node.Name] = simulated[best.Node.Name].Add(perReplica)
+		placements[best.Node.Name].PlacedReplicas++
+		response.PlacedReplicas++
+	}
+
+	for _, state := range states {
+		placement := placements[state.Node.Name]
+		after := state.Requested
+		if requested, ok := simulated[state.Node.Name]; ok {
+			after = requested
+		}
+		placement.CPUPackingAfter = k8s.PackingPercent(after.MilliCPU, state.Allocatable.MilliCPU)
+		placement.MemoryPackingAfter = k8s.PackingPercent(after.MemoryBytes, state.Allocatable.MemoryBytes)
+		response.Nodes = append(response.Nodes, *placement)
+	}
+	sort.Slice(response.Nodes, func(i, j int) bool { return response.Nodes[i].Node < response.Nodes[j].Node })
+
+	totalAfter := totalBefore
+	for i := int32(0); i < response.PlacedReplicas; i++ {
+		totalAfter = totalAfter.Add(perReplica)
+	}
+	response.UnplacedReplicas = replicas - response.PlacedReplicas
+	response.Fits = response.UnplacedReplicas == 0
+	response.CPUPackingBefore = k8s.PackingPercent(totalBefore.MilliCPU, totalAlloc.MilliCPU)
+	response.CPUPackingAfter = k8s.PackingPercent(totalAfter.MilliCPU, totalAlloc.MilliCPU)
+	response.MemoryPackingBefore = k8s.PackingPercent(totalBefore.MemoryBytes, totalAlloc.MemoryBytes)
+	response.MemoryPackingAfter = k8s.PackingPercent(totalAfter.MemoryBytes, totalAlloc.MemoryBytes)
+	return response
+}
+
+// SimulateCapacity simulates scheduling additional replicas against current node allocatable
+// @Summary Simulate capacity for additional replicas
+// @Description Simulates adding N replicas of a pod spec, or of an existing deployment's template, against current node allocatable and existing requests. Reports whether they fit, where they land and how packing changes.
+// @Tags Cluster
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name for multi-cluster setups"
+// @Param body body CapacitySimulationRequest true "Simulation request (podSpec or deployment, plus replicas)"
+// @Success 200 {object} CapacitySimulationResponse "Simulation result"
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
+// @Failure 404 {object} map[string]string "Deployment not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/capacity/simulate [post]
+func (h *NodesHandler) SimulateCapacity(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for capacity simulation")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Successfully obtained Kubernetes client")
+
+	var req CapacitySimulationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if req.Replicas <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "replicas must be greater than zero"})
+		return
+	}
+	if (req.PodSpec == nil) == (req.Deployment == nil) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "exactly one of podSpec or deployment must be provided"})
+		return
+	}
+
+	spec := req.PodSpec
+	if req.Deployment != nil {
+		deployment, err := client.AppsV1().Deployments(req.Deployment.Namespace).Get(ctx, req.Deployment.Name, metav1.GetOptions{})
+		if err != nil {
+			h.logger.WithError(err).WithField("deployment", req.Deployment.Name).WithField("namespace", req.Deployment.Namespace).Error("Failed to get deployment for capacity simulation")
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		spec = &deployment.Spec.Template.Spec
+	}
+
+	_, listSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "nodes", "")
+	defer listSpan.End()
+	states, err := loadNodeCapacity(ctx, client)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to load node capacity")
+		h.tracingHelper.RecordError(listSpan, err, "Failed to load node capacity")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.AddResourceAttributes(listSpan, "", "nodes", len(states))
+	h.tracingHelper.RecordSuccess(listSpan, fmt.Sprintf("Loaded capacity for %d nodes", len(states)))
+
+	_, simSpan := h.tracingHelper.StartDataProcessingSpan(ctx, "simulate-placement")
+	defer simSpan.End()
+	response := simulatePlacement(states, spec, req.Replicas)
+	h.tracingHelper.RecordSuccess(simSpan, fmt.Sprintf("Placed %d of %d replicas", response.PlacedReplicas, response.RequestedReplicas))
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,48 @@
+package cluster
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+func requiredAffinity(terms ...v1.NodeSelectorTerm) *v1.PodSpec {
+	return &v1.PodSpec{Affinity: &v1.Affinity{NodeAffinity: &v1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{NodeSelectorTerms: terms},
+	}}}
+}
+
+func TestMatchesNodeSelection(t *testing.T) {
+	node := testNode("node-a")
+	node.Labels = map[string]string{"zone": "a"}
+
+	byName := func(op v1.NodeSelectorOperator, names ...string) v1.NodeSelectorTerm {
+		return v1.NodeSelectorTerm{MatchFields: []v1.NodeSelectorRequirement{{Key: "metadata.name", Operator: op, Values: names}}}
+	}
+	inZone := v1.NodeSelectorRequirement{Key: "zone", Operator: v1.NodeSelectorOpIn, Values: []string{"a"}}
+
+	cases := []struct {
+		name string
+		spec *v1.PodSpec
+		want bool
+	}{
+		{"no constraints", &v1.PodSpec{}, true},
+		{"node selector mismatch", &v1.PodSpec{NodeSelector: map[string]string{"zone": "b"}}, false},
+		{"expression match", requiredAffinity(v1.NodeSelectorTerm{MatchExpressions: []v1.NodeSelectorRequirement{inZone}}), true},
+		{"field only match", requiredAffinity(byName(v1.NodeSelectorOpIn, "node-a")), true},
+		{"field only mismatch", requiredAffinity(byName(v1.NodeSelectorOpIn, "node-b")), false},
+		{"field not in", requiredAffinity(byName(v1.NodeSelectorOpNotIn, "node-b")), true},
+		{"unsupported field", requiredAffinity(v1.NodeSelectorTerm{MatchFields: []v1.NodeSelectorRequirement{{Key: "spec.unschedulable", Operator: v1.NodeSelectorOpIn, Values: []string{"false"}}}}), false},
+		{"expression and field", requiredAffinity(v1.NodeSelectorTerm{
+			MatchExpressions: []v1.NodeSelectorRequirement{inZone},
+			MatchFields:      byName(v1.NodeSelectorOpIn, "node-b").MatchFields,
+		}), false},
+		{"empty term", requiredAffinity(v1.NodeSelectorTerm{}), false},
+		{"terms are ORed", requiredAffinity(byName(v1.NodeSelectorOpIn, "node-b"), byName(v1.NodeSelectorOpIn, "node-a")), true},
+	}
+	for _, tc := range cases {
+		if got := matchesNodeSelection(tc.spec, node); got != tc.want {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,417 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/types"
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/internal/tracing"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Control-plane health, from best to worst
+const (
+	ControlPlaneHealthy   = "healthy"
+	ControlPlaneUnknown   = "unknown" // the cluster does not expose the check, as on most managed control planes
+	ControlPlaneDegraded  = "degraded"
+	ControlPlaneUnhealthy = "unhealthy"
+)
+
+var controlPlaneRank = map[string]int{ControlPlaneHealthy: 0, ControlPlaneUnknown: 0, ControlPlaneDegraded: 1, ControlPlaneUnhealthy: 2}
+
+// Leader lease freshness
+const (
+	LeaseFresh   = "fresh"
+	LeaseStale   = "stale"   // not renewed within its lease duration, so no leader is active
+	LeaseMissing = "missing" // not found; managed control planes often hide it
+)
+
+// leaderLeases are the kube-system leases the control-plane components elect leaders with
+var leaderLeases = []string{"kube-scheduler", "kube-controller-manager"}
+
+// controlPlaneProbeTimeout bounds each request to the API server health endpoints
+const controlPlaneProbeTimeout = 10 * time.Second
+
+// HealthCheck is one named check reported by a health endpoint or component
+type HealthCheck struct {
+	Name    string `json:"name"`
+	Healthy bool   `json:"healthy"`
+	Message string `json:"message,omitempty"`
+}
+
+// EndpointHealth is the verbose result of /livez or /readyz
+type EndpointHealth struct {
+	Endpoint  string        `json:"endpoint"`
+	Reachable bool          `json:"reachable"`
+	Healthy   bool          `json:"healthy"`
+	Checks    []HealthCheck `json:"checks"`
+	Failed    []string      `json:"failed"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// EtcdHealth summarizes etcd from the API server checks and, where exposed, component statuses
+type EtcdHealth struct {
+	Status  string        `json:"status"`
+	Sources []string      `json:"sources"` // readyz, livez and/or componentstatuses
+	Checks  []HealthCheck `json:"checks"`
+}
+
+// LeaderLease is the leader election lease of a control-plane component
+type LeaderLease struct {
+	Component            string `json:"component"`
+	Status               string `json:"status"`
+	Holder               string `json:"holder,omitempty"`
+	RenewTime            string `json:"renewTime,omitempty"`
+	RenewTimeMs          int64  `json:"renewTimeMs,omitempty"`
+	LeaseDurationSeconds int32  `json:"leaseDurationSeconds,omitempty"`
+	SecondsSinceRenew    int64  `json:"secondsSinceRenew,omitempty"`
+	Transitions          int32  `json:"transitions,omitempty"`
+}
+
+// ControlPlaneHealth aggregates the health of the API server, etcd, scheduler and controller manager
+type ControlPlaneHealth struct {
+	Status        string         `json:"status"`
+	Issues        []string       `json:"issues"`
+	Livez         EndpointHealth `json:"livez"`
+	Readyz        EndpointHealth `json:"readyz"`
+	Etcd          EtcdHealth     `json:"etcd"`
+	Leases        []LeaderLease  `json:"leases"`
+	Components    []HealthCheck  `json:"components"` // deprecated ComponentStatuses, where still served
+	GeneratedAt   string         `json:"generatedAt"`
+	GeneratedAtMs int64          `json:"generatedAtMs,omitempty"`
+}
+
+// healthProbe fetches a raw API server path such as /readyz?verbose
+type healthProbe func(ctx context.Context, path string) ([]byte, error)
+
+// ControlPlaneHandler reports control-plane health
+type ControlPlaneHandler struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	logger        *logger.Logger
+	sseHandler    *utils.SSEHandler
+	tracingHelper *tracing.TracingHelper
+}
+
+// NewControlPlaneHandler creates a new ControlPlaneHandler instance
+func NewControlPlaneHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger) *ControlPlaneHandler {
+	return &ControlPlaneHandler{
+		store:         store,
+		clientFactory: clientFactory,
+		logger:        log,
+		sseHandler:    utils.NewSSEHandler(log),
+		tracingHelper: tracing.GetTracingHelper(),
+	}
+}
+
+// getClient gets the Kubernetes client for the current request
+func (h *ControlPlaneHandler) getClient(c *gin.Context) (kubernetes.Interface, error) {
+	configID := c.Query("config")
+	cluster := c.Query("cluster")
+
+	if configID == "" {
+		return nil, fmt.Errorf("config parameter is required")
+	}
+
+	config, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, fmt.Errorf("config not found: %w", err)
+	}
+
+	client, err := h.clientFactory.GetClientForConfig(config, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Kubernetes client: %w", err)
+	}
+
+	return client, nil
+}
+
+// parseHealthChecks reads the verbose output of /livez or /readyz: one "[+]name ok" or
+// "[-]name failed: reason" line per check
+func parseHealthChecks(body string) []HealthCheck {
+	checks := []HealthCheck{}
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		var healthy bool
+		switch {
+		case strings.HasPrefix(line, "[+]"):
+			healthy = true
+		case strings.HasPrefix(line, "[-]"):
+		default:
+			continue
+		}
+		name, message, _ := strings.Cut(line[3:], " ")
+		message = strings.TrimSpace(message)
+		if healthy && message == "ok" {
+			message = ""
+		}
+		checks = append(checks, HealthCheck{Name: name, Healthy: healthy, Message: message})
+	}
+	return checks
+}
+
+// probeEndpoint fetches the verbose checks of a health endpoint. The API server answers a failing
+// check with a 500 that still carries the verbose body, so the body is parsed either way.
+func probeEndpoint(ctx context.Context, probe healthProbe, endpoint string) EndpointHealth {
+	health := EndpointHealth{Endpoint: endpoint, Checks: []HealthCheck{}, Failed: []string{}}
+	probeCtx, cancel := context.WithTimeout(ctx, controlPlaneProbeTimeout)
+	defer cancel()
+
+	body, err := probe(probeCtx, endpoint+"?verbose")
+	health.Checks = parseHealthChecks(string(body))
+	for _, check := range health.Checks {
+		if !check.Healthy {
+			health.Failed = append(health.Failed, check.Name)
+		}
+	}
+	switch {
+	case len(health.Checks) > 0:
+		health.Reachable = true
+		health.Healthy = len(health.Failed) == 0
+	case err == nil:
+		// Some proxies strip the verbose output; a 200 still means every check passed
+		health.Reachable = true
+		health.Healthy = true
+	default:
+		health.Error = err.Error()
+	}
+	return health
+}
+
+// leaseFreshness reports whether a leader lease is still being renewed
+func leaseFreshness(component string, lease *coordinationv1.Lease, now time.Time) LeaderLease {
+	result := LeaderLease{Component: component, Status: LeaseMissing}
+	if lease == nil {
+		return result
+	}
+	if lease.Spec.HolderIdentity != nil {
+		result.Holder = *lease.Spec.HolderIdentity
+	}
+	if lease.Spec.LeaseTransitions != nil {
+		result.Transitions = *lease.Spec.LeaseTransitions
+	}
+	if lease.Spec.LeaseDurationSeconds != nil {
+		result.LeaseDurationSeconds = *lease.Spec.LeaseDurationSeconds
+	}
+	result.Status = LeaseStale
+	if lease.Spec.RenewTime == nil {
+		return result
+	}
+	renewed := lease.Spec.RenewTime.Time
+	result.RenewTime = types.TimeFormat(renewed)
+	result.RenewTimeMs = types.EpochMillis(renewed)
+	result.SecondsSinceRenew = int64(now.Sub(renewed).Seconds())
+	duration := time.Duration(result.LeaseDurationSeconds) * time.Second
+	if duration == 0 {
+		duration = 15 * time.Second // the default leader election lease duration
+	}
+	if result.Holder != "" && now.Sub(renewed) <= duration {
+		result.Status = LeaseFresh
+	}
+	return result
+}
+
+// etcdHealth gathers the etcd checks of the health endpoints and the etcd component statuses
+func etcdHealth(livez, readyz EndpointHealth, components []HealthCheck) EtcdHealth {
+	etcd := EtcdHealth{Status: ControlPlaneUnknown, Sources: []string{}, Checks: []HealthCheck{}}
+	add := func(source string, check HealthCheck) {
+		etcd.Checks = append(etcd.Checks, check)
+		if len(etcd.Sources) == 0 || etcd.Sources[len(etcd.Sources)-1] != source {
+			etcd.Sources = append(etcd.Sources, source)
+		}
+	}
+	for _, endpoint := range []EndpointHealth{livez, readyz} {
+		for _, check := range endpoint.Checks {
+			if strings.HasPrefix(check.Name, "etcd") {
+				check.Name = strings.TrimPrefix(endpoint.Endpoint, "/") + "/" + check.Name
+				add(strings.TrimPrefix(endpoint.Endpoint, "/"), check)
+			}
+		}
+	}
+	for _, check := range components {
+		if strings.HasPrefix(check.Name, "etcd") {
+			add("componentstatuses", check)
+		}
+	}
+	if len(etcd.Checks) == 0 {
+		return etcd
+	}
+	failed := 0
+	for _, check := range etcd.Checks {
+		if !check.Healthy {
+			failed++
+		}
+	}
+	switch {
+	case failed == 0:
+		etcd.Status = ControlPlaneHealthy
+	case failed == len(etcd.Checks):
+		etcd.Status = ControlPlaneUnhealthy
+	default:
+		etcd.Status = ControlPlaneDegraded
+	}
+	return etcd
+}
+
+// componentStatuses lists the deprecated ComponentStatuses, which newer clusters no longer serve
+func componentStatuses(ctx context.Context, client kubernetes.Interface) []HealthCheck {
+	checks := []HealthCheck{}
+	list, err := client.CoreV1().ComponentStatuses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return checks
+	}
+	for _, component := range list.Items {
+		check := HealthCheck{Name: component.Name}
+		for _, condition := range component.Conditions {
+			if condition.Type != v1.ComponentHealthy {
+				continue
+			}
+			check.Healthy = condition.Status == v1.ConditionTrue
+			check.Message = condition.Error
+			if check.Message == "" && !check.Healthy {
+				check.Message = condition.Message
+			}
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// worsen raises a health status to at least the given level
+func worsen(current, status string) string {
+	if controlPlaneRank[status] > controlPlaneRank[current] {
+		return status
+	}
+	return current
+}
+
+// buildControlPlaneHealth probes the health endpoints, etcd and leader leases of a cluster
+func buildControlPlaneHealth(ctx context.Context, client kubernetes.Interface, probe healthProbe, now time.Time) ControlPlaneHealth {
+	report := ControlPlaneHealth{
+		Status:        ControlPlaneHealthy,
+		Issues:        []string{},
+		Leases:        []LeaderLease{},
+		GeneratedAt:   types.TimeFormat(now),
+		GeneratedAtMs: types.EpochMillis(now),
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		report.Livez = probeEndpoint(ctx, probe, "/livez")
+	}()
+	go func() {
+		defer wg.Done()
+		report.Readyz = probeEndpoint(ctx, probe, "/readyz")
+	}()
+	go func() {
+		defer wg.Done()
+		report.Components = componentStatuses(ctx, client)
+	}()
+	wg.Wait()
+
+	for _, endpoint := range []EndpointHealth{report.Livez, report.Readyz} {
+		switch {
+		case !endpoint.Reachable:
+			report.Status = worsen(report.Status, ControlPlaneUnhealthy)
+			report.Issues = append(report.Issues, fmt.Sprintf("%s is unreachable: %s", endpoint.Endpoint, endpoint.Error))
+		case !endpoint.Healthy:
+			report.Status = worsen(report.Status, ControlPlaneUnhealthy)
+			report.Issues = append(report.Issues, fmt.Sprintf("%s failing checks: %s", endpoint.Endpoint, strings.Join(endpoint.Failed, ", ")))
+		}
+	}
+
+	report.Etcd = etcdHealth(report.Livez, report.Readyz, report.Components)
+	if report.Etcd.Status != ControlPlaneHealthy && report.Etcd.Status != ControlPlaneUnknown {
+		report.Status = worsen(report.Status, report.Etcd.Status)
+		report.Issues = append(report.Issues, "etcd is "+report.Etcd.Status)
+	}
+
+	for _, name := range leaderLeases {
+		lease, err := client.CoordinationV1().Leases("kube-system").Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			lease = nil
+			if !apierrors.IsNotFound(err) && !apierrors.IsForbidden(err) {
+				report.Issues = append(report.Issues, fmt.Sprintf("failed to read the %s lease: %v", name, err))
+			}
+		}
+		result := leaseFreshness(name, lease, now)
+		if result.Status == LeaseStale {
+			report.Status = worsen(report.Status, ControlPlaneDegraded)
+			report.Issues = append(report.Issues, fmt.Sprintf("%s has no active leader (lease not renewed for %ds)", name, result.SecondsSinceRenew))
+		}
+		report.Leases = append(report.Leases, result)
+	}
+
+	for _, component := range report.Components {
+		if !component.Healthy && !strings.HasPrefix(component.Name, "etcd") {
+			report.Status = worsen(report.Status, ControlPlaneDegraded)
+			report.Issues = append(report.Issues, fmt.Sprintf("%s is unhealthy: %s", component.Name, component.Message))
+		}
+	}
+	return report
+}
+
+// GetControlPlaneHealth reports the health of the control plane
+// @Summary Get control-plane health
+// @Description Aggregates the verbose /livez and /readyz checks of the API server, etcd health from those checks and the deprecated ComponentStatuses where still served, and the freshness of the kube-scheduler and kube-controller-manager leader leases. A lease not renewed within its duration means the component has no active leader. Checks a managed control plane hides are reported as unknown or missing rather than failing. Streams updates as Server-Sent Events when requested with Accept: text/event-stream.
+// @Tags Cluster
+// @Produce json
+// @Produce text/event-stream
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Success 200 {object} ControlPlaneHealth
+// @Failure 400 {object} map[string]string "Bad request"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/control-plane/health [get]
+func (h *ControlPlaneHandler) GetControlPlaneHealth(c *gin.Context) {
+	ctx, span := h.tracingHelper.StartAuthSpan(c.Request.Context(), "control-plane.health")
+	defer span.End()
+
+	client, err := h.getClient(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for control-plane health")
+		h.tracingHelper.RecordError(span, err, "Failed to get Kubernetes client")
+		h.sseHandler.SendSSEError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	probe := func(ctx context.Context, path string) ([]byte, error) {
+		endpoint, query, _ := strings.Cut(path, "?")
+		request := client.Discovery().RESTClient().Get().AbsPath(endpoint)
+		if query != "" {
+			request = request.Param(query, "")
+		}
+		return request.DoRaw(ctx)
+	}
+	fetch := func() (interface{}, error) {
+		return buildControlPlaneHealth(c.Request.Context(), client, probe, time.Now()), nil
+	}
+
+	_, apiSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "get", "control-plane-health", "")
+	report := buildControlPlaneHealth(c.Request.Context(), client, probe, time.Now())
+	h.tracingHelper.RecordSuccess(apiSpan, "Control-plane health is "+report.Status)
+	apiSpan.End()
+
+	if c.GetHeader("Accept") == "text/event-stream" {
+		h.sseHandler.SendSSEResponseWithUpdates(c, report, fetch)
+		return
+	}
+	h.sseHandler.SendJSON(c, report)
+}
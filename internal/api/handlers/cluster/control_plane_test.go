@@ -0,0 +1,82 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const readyzFailing = `[+]ping ok
+[+]log ok
+[-]etcd failed: reason withheld
+[+]poststarthook/start-informers ok
+readyz check failed`
+
+func leaderLease(name, holder string, renewed time.Time) *coordinationv1.Lease {
+	duration := int32(15)
+	renew := metav1.NewMicroTime(renewed)
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "kube-system"},
+		Spec:       coordinationv1.LeaseSpec{HolderIdentity: &holder, LeaseDurationSeconds: &duration, RenewTime: &renew},
+	}
+}
+
+func TestParseHealthChecks(t *testing.T) {
+	checks := parseHealthChecks(readyzFailing)
+	if len(checks) != 4 {
+		t.Fatalf("checks = %+v", checks)
+	}
+	if etcd := checks[2]; etcd.Name != "etcd" || etcd.Healthy || etcd.Message != "failed: reason withheld" {
+		t.Errorf("etcd check = %+v", etcd)
+	}
+	if ping := checks[0]; !ping.Healthy || ping.Message != "" {
+		t.Errorf("ping check = %+v", ping)
+	}
+}
+
+func TestBuildControlPlaneHealth(t *testing.T) {
+	now := time.Now()
+	client := fake.NewSimpleClientset(
+		leaderLease("kube-scheduler", "master-1_abc", now.Add(-5*time.Second)),
+		leaderLease("kube-controller-manager", "master-2_def", now.Add(-2*time.Minute)),
+	)
+	probe := func(_ context.Context, path string) ([]byte, error) {
+		if path == "/readyz?verbose" {
+			return []byte(readyzFailing), errors.New("the server is currently unable to handle the request")
+		}
+		return []byte("[+]ping ok\n[+]etcd ok\nlivez check passed"), nil
+	}
+
+	report := buildControlPlaneHealth(context.Background(), client, probe, now)
+	if report.Status != ControlPlaneUnhealthy {
+		t.Errorf("status = %q, want unhealthy", report.Status)
+	}
+	if !report.Livez.Healthy || report.Readyz.Healthy || len(report.Readyz.Failed) != 1 || report.Readyz.Failed[0] != "etcd" {
+		t.Errorf("livez = %+v, readyz = %+v", report.Livez, report.Readyz)
+	}
+	if report.Etcd.Status != ControlPlaneDegraded || len(report.Etcd.Checks) != 2 {
+		t.Errorf("etcd = %+v", report.Etcd)
+	}
+	if report.Leases[0].Status != LeaseFresh || report.Leases[0].Holder != "master-1_abc" {
+		t.Errorf("scheduler lease = %+v", report.Leases[0])
+	}
+	if report.Leases[1].Status != LeaseStale {
+		t.Errorf("controller-manager lease = %+v", report.Leases[1])
+	}
+}
+
+func TestBuildControlPlaneHealthManaged(t *testing.T) {
+	probe := func(context.Context, string) ([]byte, error) { return []byte("ok"), nil }
+	report := buildControlPlaneHealth(context.Background(), fake.NewSimpleClientset(), probe, time.Now())
+	if report.Status != ControlPlaneHealthy || len(report.Issues) != 0 {
+		t.Errorf("managed control plane = %+v", report)
+	}
+	if report.Etcd.Status != ControlPlaneUnknown || report.Leases[0].Status != LeaseMissing {
+		t.Errorf("hidden checks should be unknown/missing: etcd = %+v, leases = %+v", report.Etcd, report.Leases)
+	}
+}
@@ -0,0 +1,484 @@
+package cluster
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Drain simulation outcomes for a pod
+const (
+	drainActionEvict   = "evict"
+	drainActionSkip    = "skip"    // left on the node, e.g. DaemonSet and static pods
+	drainActionBlocked = "blocked" // the drain would stop at this pod
+
+	placementScheduled      = "scheduled"
+	placementPreempts       = "preempts"        // fits only by preempting lower-priority pods
+	placementUnschedulable  = "unschedulable"   // no node can take the replacement
+	placementNotRescheduled = "not-rescheduled" // no controller recreates the pod
+)
+
+// DrainSimulationOptions mirror the options of a real drain
+type DrainSimulationOptions struct {
+	IgnoreDaemonSets   bool `json:"ignoreDaemonSets"`
+	DeleteEmptyDirData bool `json:"deleteEmptyDirData"`
+	Force              bool `json:"force"` // evict pods not managed by a controller
+}
+
+// DrainPodOutcome is what a drain would do to one pod and where its replacement would land
+type DrainPodOutcome struct {
+	Namespace     string        `json:"namespace"`
+	Pod           string        `json:"pod"`
+	Owner         string        `json:"owner,omitempty"` // Kind/name of the controller
+	Priority      int32         `json:"priority"`
+	PriorityClass string        `json:"priorityClass,omitempty"`
+	Requests      k8s.Resources `json:"requests"`
+	Action        string        `json:"action"` // evict, skip or blocked
+	Reason        string        `json:"reason,omitempty"`
+	PDB           string        `json:"pdb,omitempty"`
+	Placement     string        `json:"placement,omitempty"` // scheduled, preempts, unschedulable or not-rescheduled
+	TargetNode    string        `json:"targetNode,omitempty"`
+	Victims       []string      `json:"victims,omitempty"` // pods preempted to make room
+}
+
+// DrainPDBBudget is how much of a PodDisruptionBudget the drain would use
+type DrainPDBBudget struct {
+	Namespace          string `json:"namespace"`
+	Name               string `json:"name"`
+	DisruptionsAllowed int32  `json:"disruptionsAllowed"`
+	Used               int32  `json:"used"`
+	BlockedPods        int    `json:"blockedPods"`
+}
+
+// DrainTargetNode is a node receiving rescheduled pods and how its packing changes
+type DrainTargetNode struct {
+	Node                string  `json:"node"`
+	ReceivedPods        int     `json:"receivedPods"`
+	PreemptedPods       int     `json:"preemptedPods,omitempty"`
+	CPUPackingBefore    float64 `json:"cpuPackingBefore"`
+	CPUPackingAfter     float64 `json:"cpuPackingAfter"`
+	MemoryPackingBefore float64 `json:"memoryPackingBefore"`
+	MemoryPackingAfter  float64 `json:"memoryPackingAfter"`
+}
+
+// DrainSimulation is the dry-run outcome of draining a node
+type DrainSimulation struct {
+	Node                 string                 `json:"node"`
+	Cordoned             bool                   `json:"cordoned"`
+	Options              DrainSimulationOptions `json:"options"`
+	Drainable            bool                   `json:"drainable"`        // no pod blocks the drain
+	FullyRescheduled     bool                   `json:"fullyRescheduled"` // every evicted controller-managed pod finds a node
+	Evicted              int                    `json:"evicted"`
+	Skipped              int                    `json:"skipped"`
+	Blocked              int                    `json:"blocked"`
+	Rescheduled          int                    `json:"rescheduled"`
+	Preempting           int                    `json:"preempting"`
+	Unschedulable        int                    `json:"unschedulable"`
+	Pods                 []DrainPodOutcome      `json:"pods"`
+	PodDisruptionBudgets []DrainPDBBudget       `json:"podDisruptionBudgets"`
+	TargetNodes          []DrainTargetNode      `json:"targetNodes"`
+}
+
+func podPriority(pod *v1.Pod) int32 {
+	if pod.Spec.Priority != nil {
+		return *pod.Spec.Priority
+	}
+	return 0
+}
+
+func podController(pod *v1.Pod) *metav1.OwnerReference {
+	for i := range pod.OwnerReferences {
+		if ref := &pod.OwnerReferences[i]; ref.Controller != nil && *ref.Controller {
+			return ref
+		}
+	}
+	return nil
+}
+
+func podReady(pod *v1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == v1.PodReady {
+			return cond.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// matchingPDBs returns the budgets whose selector selects the pod
+func matchingPDBs(pod *v1.Pod, pdbs []policyv1.PodDisruptionBudget) []*policyv1.PodDisruptionBudget {
+	var matched []*policyv1.PodDisruptionBudget
+	for i := range pdbs {
+		pdb := &pdbs[i]
+		if pdb.Namespace != pod.Namespace || pdb.Spec.Selector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || selector.Empty() {
+			continue
+		}
+		if selector.Matches(labels.Set(pod.Labels)) {
+			matched = append(matched, pdb)
+		}
+	}
+	return matched
+}
+
+// simulateDrain reports which pods draining the node would evict, skip or be blocked by, and
+// projects where evicted pods would be rescheduled. Evictions are checked against PDBs in
+// priority order, lowest first, consuming each budget's allowed disruptions. Replacements are
+// placed highest priority first, as the scheduling queue orders them, on the least-allocated
+// node that fits, preempting lower-priority pods when nothing fits. Returns nil if the node is unknown.
+func simulateDrain(states []*nodeCapacityState, nodeName string, pdbs []policyv1.PodDisruptionBudget, opts DrainSimulationOptions) *DrainSimulation {
+	var drained *nodeCapacityState
+	for _, state := range states {
+		if state.Node.Name == nodeName {
+			drained = state
+		}
+	}
+	if drained == nil {
+		return nil
+	}
+	sim := &DrainSimulation{
+		Node:                 nodeName,
+		Cordoned:             drained.Node.Spec.Unschedulable,
+		Options:              opts,
+		Pods:                 []DrainPodOutcome{},
+		PodDisruptionBudgets: []DrainPDBBudget{},
+		TargetNodes:          []DrainTargetNode{},
+	}
+
+	pods := append([]*v1.Pod{}, drained.Pods...)
+	sort.SliceStable(pods, func(i, j int) bool {
+		if pi, pj := podPriority(pods[i]), podPriority(pods[j]); pi != pj {
+			return pi < pj
+		}
+		return pods[i].Namespace+"/"+pods[i].Name < pods[j].Namespace+"/"+pods[j].Name
+	})
+
+	budgets := map[string]*DrainPDBBudget{}
+	var budgetOrder []string
+	outcomes := make([]DrainPodOutcome, len(pods))
+	for i, pod := range pods {
+		out := DrainPodOutcome{
+			Namespace:     pod.Namespace,
+			Pod:           pod.Name,
+			Priority:      podPriority(pod),
+			PriorityClass: pod.Spec.PriorityClassName,
+			Requests:      k8s.PodRequests(&pod.Spec),
+			Action:        drainActionEvict,
+		}
+		controller := podController(pod)
+		if controller != nil {
+			out.Owner = controller.Kind + "/" + controller.Name
+		}
+		hasEmptyDir := false
+		for _, volume := range pod.Spec.Volumes {
+			if volume.EmptyDir != nil {
+				hasEmptyDir = true
+			}
+		}
+
+		switch _, mirror := pod.Annotations[v1.MirrorPodAnnotationKey]; {
+		case mirror:
+			out.Action, out.Reason = drainActionSkip, "static pod cannot be evicted"
+		case isDaemonSetPod(pod) && opts.IgnoreDaemonSets:
+			out.Action, out.Reason = drainActionSkip, "DaemonSet pod stays with the node"
+		case isDaemonSetPod(pod):
+			out.Action, out.Reason = drainActionBlocked, "DaemonSet-managed pod; drain with ignoreDaemonSets"
+		case controller == nil && !opts.Force:
+			out.Action, out.Reason = drainActionBlocked, "not managed by a controller; drain with force to delete it"
+		case hasEmptyDir && !opts.DeleteEmptyDirData:
+			out.Action, out.Reason = drainActionBlocked, "uses emptyDir data; drain with deleteEmptyDirData"
+		}
+
+		if out.Action == drainActionEvict {
+			matched := matchingPDBs(pod, pdbs)
+			for _, pdb := range matched {
+				key := pdb.Namespace + "/" + pdb.Name
+				if budgets[key] == nil {
+					budgets[key] = &DrainPDBBudget{Namespace: pdb.Namespace, Name: pdb.Name, DisruptionsAllowed: pdb.Status.DisruptionsAllowed}
+					budgetOrder = append(budgetOrder, key)
+				}
+			}
+			switch {
+			case len(matched) > 1:
+				// The eviction API rejects pods covered by several budgets
+				out.Action, out.Reason = drainActionBlocked, "pod matches more than one PodDisruptionBudget"
+			case len(matched) == 1:
+				pdb := matched[0]
+				budget := budgets[pdb.Namespace+"/"+pdb.Name]
+				out.PDB = pdb.Name
+				unhealthyAllowed := !podReady(pod) && pdb.Spec.UnhealthyPodEvictionPolicy != nil &&
+					*pdb.Spec.UnhealthyPodEvictionPolicy == policyv1.AlwaysAllow
+				switch {
+				case unhealthyAllowed:
+					// Unready pods may be evicted without using the budget
+				case budget.Used < budget.DisruptionsAllowed:
+					budget.Used++
+				default:
+					budget.BlockedPods++
+					out.Action = drainActionBlocked
+					out.Reason = fmt.Sprintf("PodDisruptionBudget %s allows no more disruptions; the drain waits until replacements are ready", pdb.Name)
+				}
+			}
+		}
+		outcomes[i] = out
+	}
+
+	// Capacity as it would be with the drained node cordoned and its evicted pods gone
+	requested := make(map[string]k8s.Resources, len(states))
+	podsOn := make(map[string][]*v1.Pod, len(states))
+	for _, state := range states {
+		requested[state.Node.Name] = state.Requested
+		podsOn[state.Node.Name] = append([]*v1.Pod{}, state.Pods...)
+	}
+	targets := map[string]*DrainTargetNode{}
+	target := func(state *nodeCapacityState) *DrainTargetNode {
+		if targets[state.Node.Name] == nil {
+			targets[state.Node.Name] = &DrainTargetNode{
+				Node:                state.Node.Name,
+				CPUPackingBefore:    k8s.PackingPercent(state.Requested.MilliCPU, state.Allocatable.MilliCPU),
+				MemoryPackingBefore: k8s.PackingPercent(state.Requested.MemoryBytes, state.Allocatable.MemoryBytes),
+			}
+		}
+		return targets[state.Node.Name]
+	}
+
+	var reschedule []int
+	for i := range outcomes {
+		if outcomes[i].Action != drainActionEvict {
+			continue
+		}
+		if outcomes[i].Owner == "" {
+			outcomes[i].Placement = placementNotRescheduled
+			continue
+		}
+		reschedule = append(reschedule, i)
+	}
+	sort.SliceStable(reschedule, func(a, b int) bool {
+		oa, ob := outcomes[reschedule[a]], outcomes[reschedule[b]]
+		if oa.Priority != ob.Priority {
+			return oa.Priority > ob.Priority
+		}
+		return oa.Requests.MilliCPU+oa.Requests.MemoryBytes/(64<<20) > ob.Requests.MilliCPU+ob.Requests.MemoryBytes/(64<<20)
+	})
+
+	for _, i := range reschedule {
+		out := &outcomes[i]
+		pod := pods[i]
+		var eligible []*nodeCapacityState
+		for _, state := range states {
+			if state.Node.Name != nodeName && schedulableTarget(state.Node) &&
+				toleratesNodeTaints(pod.Spec.Tolerations, state.Node.Spec.Taints) && matchesNodeSelection(&pod.Spec, state.Node) {
+				eligible = append(eligible, state)
+			}
+		}
+
+		var best *nodeCapacityState
+		bestScore := 0.0
+		for _, state := range eligible {
+			candidate := nodeCapacityState{Allocatable: state.Allocatable, Requested: requested[state.Node.Name]}
+			if !candidate.fits(out.Requests) {
+				continue
+			}
+			after := candidate.Requested.Add(out.Requests)
+			score := k8s.PackingPercent(after.MilliCPU, state.Allocatable.MilliCPU) + k8s.PackingPercent(after.MemoryBytes, state.Allocatable.MemoryBytes)
+			if best == nil || score < bestScore {
+				best, bestScore = state, score
+			}
+		}
+		if best != nil {
+			out.Placement, out.TargetNode = placementScheduled, best.Node.Name
+			requested[best.Node.Name] = requested[best.Node.Name].Add(out.Requests)
+			podsOn[best.Node.Name] = append(podsOn[best.Node.Name], pod)
+			target(best).ReceivedPods++
+			continue
+		}
+
+		// Preemption picks the node needing the fewest lower-priority victims
+		var victims []*v1.Pod
+		if pod.Spec.PreemptionPolicy == nil || *pod.Spec.PreemptionPolicy != v1.PreemptNever {
+			for _, state := range eligible {
+				if found := preemptionVictims(state, requested[state.Node.Name], podsOn[state.Node.Name], out.Priority, out.Requests); found != nil &&
+					(best == nil || len(found) < len(victims)) {
+					best, victims = state, found
+				}
+			}
+		}
+		if best == nil {
+			out.Placement = placementUnschedulable
+			out.Reason = "no other node has room, even by preempting lower-priority pods"
+			if len(eligible) == 0 {
+				out.Reason = "no other schedulable node matches its tolerations, nodeSelector or affinity"
+			}
+			continue
+		}
+		out.Placement, out.TargetNode = placementPreempts, best.Node.Name
+		victimSet := map[*v1.Pod]bool{}
+		for _, victim := range victims {
+			victimSet[victim] = true
+			out.Victims = append(out.Victims, victim.Namespace+"/"+victim.Name)
+			r := k8s.PodRequests(&victim.Spec)
+			requested[best.Node.Name] = requested[best.Node.Name].Add(k8s.Resources{MilliCPU: -r.MilliCPU, MemoryBytes: -r.MemoryBytes, Pods: -r.Pods})
+		}
+		remaining := []*v1.Pod{pod}
+		for _, p := range podsOn[best.Node.Name] {
+			if !victimSet[p] {
+				remaining = append(remaining, p)
+			}
+		}
+		podsOn[best.Node.Name] = remaining
+		requested[best.Node.Name] = requested[best.Node.Name].Add(out.Requests)
+		t := target(best)
+		t.ReceivedPods++
+		t.PreemptedPods += len(victims)
+	}
+
+	for _, out := range outcomes {
+		switch out.Action {
+		case drainActionEvict:
+			sim.Evicted++
+		case drainActionSkip:
+			sim.Skipped++
+		case drainActionBlocked:
+			sim.Blocked++
+		}
+		switch out.Placement {
+		case placementScheduled:
+			sim.Rescheduled++
+		case placementPreempts:
+			sim.Rescheduled++
+			sim.Preempting++
+		case placementUnschedulable:
+			sim.Unschedulable++
+		}
+	}
+	sim.Pods = outcomes
+	sim.Drainable = sim.Blocked == 0
+	sim.FullyRescheduled = sim.Unschedulable == 0
+	for _, key := range budgetOrder {
+		sim.PodDisruptionBudgets = append(sim.PodDisruptionBudgets, *budgets[key])
+	}
+	for _, state := range states {
+		t, ok := targets[state.Node.Name]
+		if !ok {
+			continue
+		}
+		after := requested[state.Node.Name]
+		t.CPUPackingAfter = k8s.PackingPercent(after.MilliCPU, state.Allocatable.MilliCPU)
+		t.MemoryPackingAfter = k8s.PackingPercent(after.MemoryBytes, state.Allocatable.MemoryBytes)
+		sim.TargetNodes = append(sim.TargetNodes, *t)
+	}
+	return sim
+}
+
+// preemptionVictims returns the fewest lowest-priority pods to remove from a node so the
+// request fits, or nil if removing every lower-priority pod is not enough
+func preemptionVictims(state *nodeCapacityState, requested k8s.Resources, pods []*v1.Pod, priority int32, need k8s.Resources) []*v1.Pod {
+	var lower []*v1.Pod
+	for _, pod := range pods {
+		if podPriority(pod) < priority && !isDaemonSetPod(pod) {
+			lower = append(lower, pod)
+		}
+	}
+	sort.SliceStable(lower, func(i, j int) bool { return podPriority(lower[i]) < podPriority(lower[j]) })
+	candidate := nodeCapacityState{Allocatable: state.Allocatable, Requested: requested}
+	var victims []*v1.Pod
+	for _, pod := range lower {
+		if candidate.fits(need) {
+			break
+		}
+		r := k8s.PodRequests(&pod.Spec)
+		candidate.Requested = candidate.Requested.Add(k8s.Resources{MilliCPU: -r.MilliCPU, MemoryBytes: -r.MemoryBytes, Pods: -r.Pods})
+		victims = append(victims, pod)
+	}
+	if !candidate.fits(need) {
+		return nil
+	}
+	return victims
+}
+
+// queryBool parses an optional boolean query parameter
+func queryBool(c *gin.Context, name string, fallback bool) bool {
+	if v, err := strconv.ParseBool(c.Query(name)); err == nil {
+		return v
+	}
+	return fallback
+}
+
+// SimulateDrain reports what draining a node would do without evicting anything
+// @Summary Simulate node drain
+// @Description Dry run of a node drain: which pods would be evicted, which are skipped, which would block the drain (PodDisruptionBudgets without remaining disruptions, unmanaged pods, emptyDir data) and where evicted pods would be rescheduled given current requests, taints, node selectors and affinity. PDB budgets are consumed lowest priority first; replacements are placed highest priority first and may preempt lower-priority pods.
+// @Tags Cluster
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name for multi-cluster setups"
+// @Param name path string true "Node name"
+// @Param ignoreDaemonSets query bool false "Skip DaemonSet pods instead of blocking" default(true)
+// @Param deleteEmptyDirData query bool false "Allow evicting pods with emptyDir volumes" default(false)
+// @Param force query bool false "Allow deleting pods without a controller" default(false)
+// @Success 200 {object} DrainSimulation "Drain simulation"
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
+// @Failure 404 {object} map[string]string "Node not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/nodes/{name}/drain/simulate [get]
+func (h *NodesHandler) SimulateDrain(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for drain simulation")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Successfully obtained Kubernetes client")
+
+	name := c.Param("name")
+	opts := DrainSimulationOptions{
+		IgnoreDaemonSets:   queryBool(c, "ignoreDaemonSets", true),
+		DeleteEmptyDirData: queryBool(c, "deleteEmptyDirData", false),
+		Force:              queryBool(c, "force", false),
+	}
+
+	_, listSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "nodes", "")
+	defer listSpan.End()
+	states, err := loadNodeCapacity(ctx, client)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to load node capacity")
+		h.tracingHelper.RecordError(listSpan, err, "Failed to load node capacity")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	pdbs, err := client.PolicyV1().PodDisruptionBudgets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list pod disruption budgets for drain simulation")
+		h.tracingHelper.RecordError(listSpan, err, "Failed to list pod disruption budgets")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(listSpan, fmt.Sprintf("Loaded %d nodes and %d PDBs", len(states), len(pdbs.Items)))
+
+	_, simSpan := h.tracingHelper.StartDataProcessingSpan(ctx, "simulate-drain")
+	defer simSpan.End()
+	sim := simulateDrain(states, name, pdbs.Items, opts)
+	if sim == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("node %s not found", name)})
+		return
+	}
+	h.tracingHelper.RecordSuccess(simSpan, fmt.Sprintf("Simulated eviction of %d pods", sim.Evicted))
+
+	c.JSON(http.StatusOK, sim)
+}
@@ -0,0 +1,88 @@
+package cluster
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func withPriority(pod *v1.Pod, priority int32) *v1.Pod {
+	pod.Spec.Priority = &priority
+	return pod
+}
+
+func TestSimulateDrain(t *testing.T) {
+	web1 := testPod("web-1", "500m", "1Gi")
+	web1.Labels = map[string]string{"app": "web"}
+	web2 := testPod("web-2", "500m", "1Gi")
+	web2.Labels = map[string]string{"app": "web"}
+	big := withPriority(testPod("big", "3", "4Gi"), 1000)
+	bare := testPod("bare", "100m", "128Mi")
+	bare.OwnerReferences = nil
+	controller := true
+	ds := testPod("agent", "100m", "128Mi")
+	ds.OwnerReferences = []metav1.OwnerReference{{Kind: "DaemonSet", Name: "agent", Controller: &controller}}
+
+	drained := testState(testNode("drained"), web1, web2, big, bare, ds)
+	// Room for the web pods, but the big pod only fits by preempting the low-priority filler
+	filler := withPriority(testPod("filler", "2", "2Gi"), -10)
+	other := testState(testNode("other"), filler)
+	cordoned := testNode("cordoned")
+	cordoned.Spec.Unschedulable = true
+
+	pdbs := []policyv1.PodDisruptionBudget{{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}},
+		Status:     policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 1},
+	}}
+
+	sim := simulateDrain([]*nodeCapacityState{drained, other, testState(cordoned)}, "drained", pdbs, DrainSimulationOptions{IgnoreDaemonSets: true})
+	if sim == nil {
+		t.Fatal("expected a simulation")
+	}
+	outcomes := map[string]DrainPodOutcome{}
+	for _, out := range sim.Pods {
+		outcomes[out.Pod] = out
+	}
+	if outcomes["agent"].Action != drainActionSkip {
+		t.Errorf("expected DaemonSet pod to be skipped, got %+v", outcomes["agent"])
+	}
+	if outcomes["bare"].Action != drainActionBlocked {
+		t.Errorf("expected unmanaged pod to block without force, got %+v", outcomes["bare"])
+	}
+	if outcomes["web-1"].Action != drainActionEvict || outcomes["web-1"].Placement != placementScheduled || outcomes["web-1"].TargetNode != "other" {
+		t.Errorf("expected web-1 to be evicted and rescheduled on other, got %+v", outcomes["web-1"])
+	}
+	if outcomes["web-2"].Action != drainActionBlocked || outcomes["web-2"].PDB != "web" {
+		t.Errorf("expected web-2 to be blocked by the exhausted PDB, got %+v", outcomes["web-2"])
+	}
+	if b := outcomes["big"]; b.Placement != placementPreempts || len(b.Victims) != 1 || b.Victims[0] != "default/filler" {
+		t.Errorf("expected big pod to preempt filler, got %+v", b)
+	}
+	if sim.Drainable || sim.Blocked != 2 || sim.Evicted != 2 || sim.Preempting != 1 || !sim.FullyRescheduled {
+		t.Errorf("unexpected summary %+v", sim)
+	}
+	if len(sim.PodDisruptionBudgets) != 1 || sim.PodDisruptionBudgets[0].Used != 1 || sim.PodDisruptionBudgets[0].BlockedPods != 1 {
+		t.Errorf("unexpected PDB usage %+v", sim.PodDisruptionBudgets)
+	}
+
+	forced := simulateDrain([]*nodeCapacityState{drained, other}, "drained", nil, DrainSimulationOptions{Force: true})
+	for _, out := range forced.Pods {
+		switch out.Pod {
+		case "bare":
+			if out.Action != drainActionEvict || out.Placement != placementNotRescheduled {
+				t.Errorf("expected forced unmanaged pod to be deleted without replacement, got %+v", out)
+			}
+		case "agent":
+			if out.Action != drainActionBlocked {
+				t.Errorf("expected DaemonSet pod to block when not ignored, got %+v", out)
+			}
+		}
+	}
+
+	if simulateDrain([]*nodeCapacityState{other}, "missing", nil, DrainSimulationOptions{}) != nil {
+		t.Error("expected nil for an unknown node")
+	}
+}
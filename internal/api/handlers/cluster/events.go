@@ -1,8 +1,10 @@
 package cluster
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/Facets-cloud/kube-dash/internal/api/utils"
 	"github.com/Facets-cloud/kube-dash/internal/k8s"
@@ -11,6 +13,7 @@ import (
 	"github.com/Facets-cloud/kube-dash/pkg/logger"
 
 	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -36,7 +39,7 @@ func NewEventsHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientF
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the current request
-func (h *EventsHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *EventsHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -66,6 +69,12 @@ func (h *EventsHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientse
 // @Param config query string true "Kubernetes configuration ID"
 // @Param cluster query string false "Cluster name for multi-cluster setups"
 // @Param namespace query string false "Kubernetes namespace to filter events (empty for cluster-wide)"
+// @Param namespaces query string false "Comma-separated namespaces to list across, instead of namespace"
+// @Param namespaceGroup query string false "ID of a saved namespace group to list across"
+// @Param fields query string false "Comma-separated response fields to return, e.g. name,status,restarts; name, namespace and uid are always included"
+// @Param labelSelector query string false "Kubernetes label selector, e.g. app=web,tier!=cache"
+// @Param sort query string false "Response field to sort by, e.g. name or -restarts for descending"
+// @Param view query string false "ID of a saved view supplying namespaces, labelSelector, sort and fields"
 // @Success 200 {array} object "List of events"
 // @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
 // @Failure 403 {object} map[string]string "Forbidden - insufficient permissions"
@@ -87,13 +96,21 @@ func (h *EventsHandler) GetEvents(c *gin.Context) {
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Successfully obtained Kubernetes client")
 
-	namespace := c.Query("namespace")
+	namespaces := utils.RequestedNamespaces(c)
+	namespace := strings.Join(namespaces, ",")
 
 	// Start child span for Kubernetes API call
 	_, apiSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "events", namespace)
 	defer apiSpan.End()
 
-	events, err := client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	items, err := utils.ListInNamespaces(ctx, namespaces, func(ctx context.Context, namespace string) ([]corev1.Event, error) {
+		list, err := client.CoreV1().Events(namespace).List(ctx, utils.ListOptions(c))
+		if err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	})
+	events := &corev1.EventList{Items: items}
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to list events")
 		h.tracingHelper.RecordError(apiSpan, err, "Failed to list events")
@@ -116,7 +133,7 @@ func (h *EventsHandler) GetEvents(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, events.Items)
+	h.sseHandler.SendJSON(c, events.Items)
 }
 
 // GetEventsSSE returns events as Server-Sent Events with real-time updates
@@ -183,5 +200,5 @@ func (h *EventsHandler) GetEventsSSE(c *gin.Context) {
 	}
 
 	// For non-SSE requests, return JSON
-	c.JSON(http.StatusOK, initialData)
+	h.sseHandler.SendJSON(c, initialData)
 }
@@ -0,0 +1,640 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/internal/tracing"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Orphan categories reported by the hygiene report
+const (
+	orphanConfigMap        = "unused-configmap"
+	orphanSecret           = "unused-secret"
+	orphanServiceEndpoints = "service-without-endpoints"
+	orphanIngressBackend   = "ingress-missing-service"
+	orphanPVC              = "unused-pvc"
+	orphanExpiredJob       = "expired-job"
+	orphanFailedPod        = "failed-pod"
+)
+
+const (
+	defaultFailedPodDays   = 7
+	defaultFinishedJobDays = 7
+)
+
+// hygieneSystemNamespaces are skipped unless includeSystem is set
+var hygieneSystemNamespaces = map[string]bool{"kube-system": true, "kube-public": true, "kube-node-lease": true}
+
+// managedSecretTypes are created and consumed by Kubernetes or tooling rather than referenced by pods
+var managedSecretTypes = map[v1.SecretType]bool{
+	v1.SecretTypeServiceAccountToken: true,
+	v1.SecretTypeBootstrapToken:      true,
+	"helm.sh/release.v1":             true,
+}
+
+// CleanupAction is a call to the generic delete API that removes an orphaned object
+type CleanupAction struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Body   []CleanupTarget `json:"body"`
+}
+
+// CleanupTarget is an item of a delete API request body
+type CleanupTarget struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// OrphanFinding is an object that appears to be unused or left behind
+type OrphanFinding struct {
+	Category  string        `json:"category"`
+	Kind      string        `json:"kind"`
+	Name      string        `json:"name"`
+	Namespace string        `json:"namespace"`
+	Reason    string        `json:"reason"`
+	CreatedAt time.Time     `json:"createdAt"`
+	Cleanup   CleanupAction `json:"cleanup"`
+}
+
+// HygieneReport lists orphaned objects with the delete calls that clean them up
+type HygieneReport struct {
+	GeneratedAt     time.Time                `json:"generatedAt"`
+	Namespace       string                   `json:"namespace,omitempty"`
+	FailedPodDays   int                      `json:"failedPodDays"`
+	FinishedJobDays int                      `json:"finishedJobDays"`
+	Findings        []OrphanFinding          `json:"findings"`
+	Counts          map[string]int           `json:"counts"`
+	Cleanup         map[string]CleanupAction `json:"cleanup"` // all findings of a category in one delete call
+	Skipped         []string                 `json:"skipped"` // resources that could not be listed
+}
+
+// hygieneInventory is the cluster state the orphan checks run against
+type hygieneInventory struct {
+	Pods            []v1.Pod
+	ConfigMaps      []v1.ConfigMap
+	Secrets         []v1.Secret
+	Services        []v1.Service
+	EndpointSlices  []discoveryv1.EndpointSlice
+	Ingresses       []networkingv1.Ingress
+	PVCs            []v1.PersistentVolumeClaim
+	Jobs            []batchv1.Job
+	Templates       []v1.PodTemplateSpec // pod templates of workloads, including ones scaled to zero
+	ServiceAccounts []v1.ServiceAccount
+}
+
+// hygieneOptions tune the age thresholds of the orphan checks
+type hygieneOptions struct {
+	FailedPodAge   time.Duration
+	FinishedJobAge time.Duration
+}
+
+// HygieneHandler reports orphaned and leftover objects
+type HygieneHandler struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	logger        *logger.Logger
+	tracingHelper *tracing.TracingHelper
+}
+
+// NewHygieneHandler creates a new HygieneHandler instance
+func NewHygieneHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger) *HygieneHandler {
+	return &HygieneHandler{
+		store:         store,
+		clientFactory: clientFactory,
+		logger:        log,
+		tracingHelper: tracing.GetTracingHelper(),
+	}
+}
+
+// getClient gets the Kubernetes client for the current request
+func (h *HygieneHandler) getClient(c *gin.Context) (kubernetes.Interface, error) {
+	configID := c.Query("config")
+	cluster := c.Query("cluster")
+
+	if configID == "" {
+		return nil, fmt.Errorf("config parameter is required")
+	}
+
+	config, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, fmt.Errorf("config not found: %w", err)
+	}
+
+	client, err := h.clientFactory.GetClientForConfig(config, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Kubernetes client: %w", err)
+	}
+
+	return client, nil
+}
+
+// podSpecReferences records the ConfigMaps, Secrets and PVCs a pod spec uses
+func podSpecReferences(namespace string, spec *v1.PodSpec, configMaps, secrets, pvcs map[string]bool) {
+	key := func(name string) string { return namespace + "/" + name }
+	for _, secret := range spec.ImagePullSecrets {
+		secrets[key(secret.Name)] = true
+	}
+	for _, volume := range spec.Volumes {
+		switch {
+		case volume.ConfigMap != nil:
+			configMaps[key(volume.ConfigMap.Name)] = true
+		case volume.Secret != nil:
+			secrets[key(volume.Secret.SecretName)] = true
+		case volume.PersistentVolumeClaim != nil:
+			pvcs[key(volume.PersistentVolumeClaim.ClaimName)] = true
+		case volume.Projected != nil:
+			for _, source := range volume.Projected.Sources {
+				if source.ConfigMap != nil {
+					configMaps[key(source.ConfigMap.Name)] = true
+				}
+				if source.Secret != nil {
+					secrets[key(source.Secret.Name)] = true
+				}
+			}
+		}
+	}
+	containers := append(append([]v1.Container{}, spec.InitContainers...), spec.Containers...)
+	for _, container := range spec.EphemeralContainers {
+		containers = append(containers, v1.Container{Env: container.Env, EnvFrom: container.EnvFrom})
+	}
+	for _, container := range containers {
+		for _, source := range container.EnvFrom {
+			if source.ConfigMapRef != nil {
+				configMaps[key(source.ConfigMapRef.Name)] = true
+			}
+			if source.SecretRef != nil {
+				secrets[key(source.SecretRef.Name)] = true
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if env.ValueFrom.ConfigMapKeyRef != nil {
+				configMaps[key(env.ValueFrom.ConfigMapKeyRef.Name)] = true
+			}
+			if env.ValueFrom.SecretKeyRef != nil {
+				secrets[key(env.ValueFrom.SecretKeyRef.Name)] = true
+			}
+		}
+	}
+}
+
+func cleanupAction(resourceKind string, targets ...CleanupTarget) CleanupAction {
+	return CleanupAction{Method: http.MethodDelete, Path: "/api/v1/" + resourceKind, Body: targets}
+}
+
+// jobFinishedAt returns when a job completed or failed
+func jobFinishedAt(job *batchv1.Job) (time.Time, bool) {
+	for _, condition := range job.Status.Conditions {
+		if (condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed) && condition.Status == v1.ConditionTrue {
+			return condition.LastTransitionTime.Time, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// findOrphans runs the orphan checks. Objects with owner references are managed by a controller
+// and are left alone.
+func findOrphans(inv hygieneInventory, opts hygieneOptions, now time.Time) []OrphanFinding {
+	configMapRefs, secretRefs, pvcRefs := map[string]bool{}, map[string]bool{}, map[string]bool{}
+	for i := range inv.Pods {
+		podSpecReferences(inv.Pods[i].Namespace, &inv.Pods[i].Spec, configMapRefs, secretRefs, pvcRefs)
+	}
+	for i := range inv.Templates {
+		podSpecReferences(inv.Templates[i].Namespace, &inv.Templates[i].Spec, configMapRefs, secretRefs, pvcRefs)
+	}
+	for _, sa := range inv.ServiceAccounts {
+		for _, secret := range sa.Secrets {
+			secretRefs[sa.Namespace+"/"+secret.Name] = true
+		}
+		for _, secret := range sa.ImagePullSecrets {
+			secretRefs[sa.Namespace+"/"+secret.Name] = true
+		}
+	}
+	services := map[string]bool{}
+	for _, svc := range inv.Services {
+		services[svc.Namespace+"/"+svc.Name] = true
+	}
+	for _, ing := range inv.Ingresses {
+		for _, tls := range ing.Spec.TLS {
+			secretRefs[ing.Namespace+"/"+tls.SecretName] = true
+		}
+	}
+
+	var findings []OrphanFinding
+	add := func(category, kind, resourceKind string, meta metav1.ObjectMeta, reason string) {
+		findings = append(findings, OrphanFinding{
+			Category:  category,
+			Kind:      kind,
+			Name:      meta.Name,
+			Namespace: meta.Namespace,
+			Reason:    reason,
+			CreatedAt: meta.CreationTimestamp.Time,
+			Cleanup:   cleanupAction(resourceKind, CleanupTarget{Name: meta.Name, Namespace: meta.Namespace}),
+		})
+	}
+
+	for _, cm := range inv.ConfigMaps {
+		// kube-root-ca.crt is published into every namespace; leader election records are not mounted
+		if len(cm.OwnerReferences) > 0 || cm.Name == "kube-root-ca.crt" || cm.Annotations["control-plane.alpha.kubernetes.io/leader"] != "" {
+			continue
+		}
+		if !configMapRefs[cm.Namespace+"/"+cm.Name] {
+			add(orphanConfigMap, "ConfigMap", "configmaps", cm.ObjectMeta, "not referenced by any pod, workload template or service account")
+		}
+	}
+	for _, secret := range inv.Secrets {
+		if len(secret.OwnerReferences) > 0 || managedSecretTypes[secret.Type] || secret.Annotations["cert-manager.io/certificate-name"] != "" {
+			continue
+		}
+		if !secretRefs[secret.Namespace+"/"+secret.Name] {
+			add(orphanSecret, "Secret", "secrets", secret.ObjectMeta, "not referenced by any pod, workload template, service account or ingress")
+		}
+	}
+
+	readyEndpoints := map[string]int{}
+	for _, slice := range inv.EndpointSlices {
+		service := slice.Labels[discoveryv1.LabelServiceName]
+		for _, endpoint := range slice.Endpoints {
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				readyEndpoints[slice.Namespace+"/"+service]++
+			}
+		}
+	}
+	for _, svc := range inv.Services {
+		if svc.Spec.Type == v1.ServiceTypeExternalName {
+			continue
+		}
+		if readyEndpoints[svc.Namespace+"/"+svc.Name] == 0 {
+			reason := "no ready endpoints"
+			if len(svc.Spec.Selector) == 0 {
+				reason = "no selector and no ready endpoints"
+			}
+			add(orphanServiceEndpoints, "Service", "services", svc.ObjectMeta, reason)
+		}
+	}
+
+	for _, ing := range inv.Ingresses {
+		missing := map[string]bool{}
+		check := func(backend *networkingv1.IngressBackend) {
+			if backend != nil && backend.Service != nil && !services[ing.Namespace+"/"+backend.Service.Name] {
+				missing[backend.Service.Name] = true
+			}
+		}
+		check(ing.Spec.DefaultBackend)
+		for _, rule := range ing.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			for i := range rule.HTTP.Paths {
+				check(&rule.HTTP.Paths[i].Backend)
+			}
+		}
+		if len(missing) > 0 {
+			names := make([]string, 0, len(missing))
+			for name := range missing {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			add(orphanIngressBackend, "Ingress", "ingresses", ing.ObjectMeta, "backend services not found: "+strings.Join(names, ", "))
+		}
+	}
+
+	for _, pvc := range inv.PVCs {
+		if len(pvc.OwnerReferences) > 0 {
+			continue
+		}
+		if !pvcRefs[pvc.Namespace+"/"+pvc.Name] {
+			add(orphanPVC, "PersistentVolumeClaim", "persistentvolumeclaims", pvc.ObjectMeta, "not mounted by any pod or workload template")
+		}
+	}
+
+	for i := range inv.Jobs {
+		job := &inv.Jobs[i]
+		finishedAt, finished := jobFinishedAt(job)
+		if !finished {
+			continue
+		}
+		if job.Spec.TTLSecondsAfterFinished != nil {
+			ttl := time.Duration(*job.Spec.TTLSecondsAfterFinished) * time.Second
+			if now.Sub(finishedAt) > ttl {
+				add(orphanExpiredJob, "Job", "jobs", job.ObjectMeta, fmt.Sprintf("finished %s ago, past its %s TTL", now.Sub(finishedAt).Round(time.Minute), ttl))
+			}
+			continue
+		}
+		// CronJob history limits clean up the jobs they own
+		if len(job.OwnerReferences) == 0 && now.Sub(finishedAt) > opts.FinishedJobAge {
+			add(orphanExpiredJob, "Job", "jobs", job.ObjectMeta, fmt.Sprintf("finished %s ago and has no TTL", now.Sub(finishedAt).Round(time.Minute)))
+		}
+	}
+
+	for _, pod := range inv.Pods {
+		if pod.Status.Phase != v1.PodFailed {
+			continue
+		}
+		since := pod.CreationTimestamp.Time
+		if pod.Status.StartTime != nil {
+			since = pod.Status.StartTime.Time
+		}
+		if now.Sub(since) > opts.FailedPodAge {
+			reason := "failed"
+			if pod.Status.Reason != "" {
+				reason = pod.Status.Reason
+			}
+			add(orphanFailedPod, "Pod", "pods", pod.ObjectMeta, fmt.Sprintf("%s, started %s ago", reason, now.Sub(since).Round(time.Hour)))
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Category != findings[j].Category {
+			return findings[i].Category < findings[j].Category
+		}
+		if findings[i].Namespace != findings[j].Namespace {
+			return findings[i].Namespace < findings[j].Namespace
+		}
+		return findings[i].Name < findings[j].Name
+	})
+	return findings
+}
+
+// loadHygieneInventory lists the objects the orphan checks need, recording resources that could not be listed
+func loadHygieneInventory(ctx context.Context, client kubernetes.Interface, namespace string) (hygieneInventory, []string, error) {
+	var inv hygieneInventory
+	var skipped []string
+	opts := metav1.ListOptions{}
+	list := func(resource string, fn func() error) error {
+		if err := fn(); err != nil {
+			if apierrors.IsForbidden(err) {
+				skipped = append(skipped, resource)
+				return nil
+			}
+			return fmt.Errorf("failed to list %s: %w", resource, err)
+		}
+		return nil
+	}
+
+	steps := []struct {
+		resource string
+		fn       func() error
+	}{
+		{"pods", func() error {
+			l, err := client.CoreV1().Pods(namespace).List(ctx, opts)
+			if err == nil {
+				inv.Pods = l.Items
+			}
+			return err
+		}},
+		{"configmaps", func() error {
+			l, err := client.CoreV1().ConfigMaps(namespace).List(ctx, opts)
+			if err == nil {
+				inv.ConfigMaps = l.Items
+			}
+			return err
+		}},
+		{"secrets", func() error {
+			l, err := client.CoreV1().Secrets(namespace).List(ctx, opts)
+			if err == nil {
+				inv.Secrets = l.Items
+			}
+			return err
+		}},
+		{"services", func() error {
+			l, err := client.CoreV1().Services(namespace).List(ctx, opts)
+			if err == nil {
+				inv.Services = l.Items
+			}
+			return err
+		}},
+		{"endpointslices", func() error {
+			l, err := client.DiscoveryV1().EndpointSlices(namespace).List(ctx, opts)
+			if err == nil {
+				inv.EndpointSlices = l.Items
+			}
+			return err
+		}},
+		{"ingresses", func() error {
+			l, err := client.NetworkingV1().Ingresses(namespace).List(ctx, opts)
+			if err == nil {
+				inv.Ingresses = l.Items
+			}
+			return err
+		}},
+		{"persistentvolumeclaims", func() error {
+			l, err := client.CoreV1().PersistentVolumeClaims(namespace).List(ctx, opts)
+			if err == nil {
+				inv.PVCs = l.Items
+			}
+			return err
+		}},
+		{"jobs", func() error {
+			l, err := client.BatchV1().Jobs(namespace).List(ctx, opts)
+			if err == nil {
+				inv.Jobs = l.Items
+				for _, job := range l.Items {
+					inv.Templates = append(inv.Templates, templateIn(job.Namespace, job.Spec.Template))
+				}
+			}
+			return err
+		}},
+		{"serviceaccounts", func() error {
+			l, err := client.CoreV1().ServiceAccounts(namespace).List(ctx, opts)
+			if err == nil {
+				inv.ServiceAccounts = l.Items
+			}
+			return err
+		}},
+		{"deployments", func() error {
+			l, err := client.AppsV1().Deployments(namespace).List(ctx, opts)
+			if err == nil {
+				for _, d := range l.Items {
+					inv.Templates = append(inv.Templates, templateIn(d.Namespace, d.Spec.Template))
+				}
+			}
+			return err
+		}},
+		{"statefulsets", func() error {
+			l, err := client.AppsV1().StatefulSets(namespace).List(ctx, opts)
+			if err == nil {
+				for _, s := range l.Items {
+					inv.Templates = append(inv.Templates, statefulSetTemplate(&s))
+				}
+			}
+			return err
+		}},
+		{"daemonsets", func() error {
+			l, err := client.AppsV1().DaemonSets(namespace).List(ctx, opts)
+			if err == nil {
+				for _, d := range l.Items {
+					inv.Templates = append(inv.Templates, templateIn(d.Namespace, d.Spec.Template))
+				}
+			}
+			return err
+		}},
+		{"cronjobs", func() error {
+			l, err := client.BatchV1().CronJobs(namespace).List(ctx, opts)
+			if err == nil {
+				for _, cj := range l.Items {
+					inv.Templates = append(inv.Templates, templateIn(cj.Namespace, cj.Spec.JobTemplate.Spec.Template))
+				}
+			}
+			return err
+		}},
+	}
+	for _, step := range steps {
+		if err := list(step.resource, step.fn); err != nil {
+			return inv, skipped, err
+		}
+	}
+	return inv, skipped, nil
+}
+
+// templateIn sets the namespace of a workload's pod template, which templates leave empty
+func templateIn(namespace string, template v1.PodTemplateSpec) v1.PodTemplateSpec {
+	template.Namespace = namespace
+	return template
+}
+
+// statefulSetTemplate also counts the PVCs created from volume claim templates as used, since a
+// scaled-down StatefulSet keeps them for when it scales back up
+func statefulSetTemplate(sts *appsv1.StatefulSet) v1.PodTemplateSpec {
+	template := templateIn(sts.Namespace, sts.Spec.Template)
+	replicas := int32(1)
+	if sts.Spec.Replicas != nil && *sts.Spec.Replicas > replicas {
+		replicas = *sts.Spec.Replicas
+	}
+	for _, claim := range sts.Spec.VolumeClaimTemplates {
+		for i := int32(0); i < replicas; i++ {
+			template.Spec.Volumes = append(template.Spec.Volumes, v1.Volume{
+				Name: claim.Name,
+				VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
+					ClaimName: fmt.Sprintf("%s-%s-%d", claim.Name, sts.Name, i),
+				}},
+			})
+		}
+	}
+	return template
+}
+
+// positiveIntQuery parses an optional positive integer query parameter
+func positiveIntQuery(c *gin.Context, name string, defaultValue int) (int, error) {
+	v := c.Query(name)
+	if v == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("%s must be a positive integer", name)
+	}
+	return n, nil
+}
+
+// GetHygieneReport finds orphaned and leftover objects
+// @Summary Get cluster hygiene report
+// @Description Finds orphaned objects: ConfigMaps and Secrets not referenced by any pod, workload template, service account or ingress; Services without ready endpoints; Ingresses pointing to missing Services; PVCs not mounted by any pod or workload; finished Jobs past their TTL (or older than finishedJobDays without one); and failed pods older than failedPodDays. Objects owned by a controller and system namespaces are skipped. Each finding carries the generic delete API call that removes it, and cleanup groups the calls per category.
+// @Tags Cluster
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param namespace query string false "Limit the report to one namespace"
+// @Param includeSystem query bool false "Include kube-system, kube-public and kube-node-lease"
+// @Param failedPodDays query int false "Age in days after which failed pods are reported (default 7)"
+// @Param finishedJobDays query int false "Age in days after which finished Jobs without a TTL are reported (default 7)"
+// @Success 200 {object} HygieneReport
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/hygiene [get]
+func (h *HygieneHandler) GetHygieneReport(c *gin.Context) {
+	ctx, span := h.tracingHelper.StartAuthSpan(c.Request.Context(), "hygiene.report")
+	defer span.End()
+
+	failedPodDays, err := positiveIntQuery(c, "failedPodDays", defaultFailedPodDays)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	finishedJobDays, err := positiveIntQuery(c, "finishedJobDays", defaultFinishedJobDays)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	client, err := h.getClient(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for hygiene report")
+		h.tracingHelper.RecordError(span, err, "Failed to get Kubernetes client")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	namespace := c.Query("namespace")
+	apiCtx, apiSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "hygiene-inventory", namespace)
+	inv, skipped, err := loadHygieneInventory(apiCtx, client, namespace)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to load inventory for hygiene report")
+		h.tracingHelper.RecordError(apiSpan, err, "Failed to list resources")
+		apiSpan.End()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(apiSpan, "Inventory listed")
+	apiSpan.End()
+
+	now := time.Now()
+	findings := findOrphans(inv, hygieneOptions{
+		FailedPodAge:   time.Duration(failedPodDays) * 24 * time.Hour,
+		FinishedJobAge: time.Duration(finishedJobDays) * 24 * time.Hour,
+	}, now)
+
+	includeSystem := queryBool(c, "includeSystem", false)
+	report := HygieneReport{
+		GeneratedAt:     now,
+		Namespace:       namespace,
+		FailedPodDays:   failedPodDays,
+		FinishedJobDays: finishedJobDays,
+		Findings:        []OrphanFinding{},
+		Counts:          map[string]int{},
+		Cleanup:         map[string]CleanupAction{},
+		Skipped:         skipped,
+	}
+	if report.Skipped == nil {
+		report.Skipped = []string{}
+	}
+	for _, finding := range findings {
+		if namespace == "" && !includeSystem && hygieneSystemNamespaces[finding.Namespace] {
+			continue
+		}
+		report.Findings = append(report.Findings, finding)
+		report.Counts[finding.Category]++
+		action, ok := report.Cleanup[finding.Category]
+		if !ok {
+			action = CleanupAction{Method: finding.Cleanup.Method, Path: finding.Cleanup.Path, Body: []CleanupTarget{}}
+		}
+		action.Body = append(action.Body, finding.Cleanup.Body...)
+		report.Cleanup[finding.Category] = action
+	}
+
+	h.tracingHelper.RecordSuccess(span, "Hygiene report generated")
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,95 @@
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFindOrphans(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	meta := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: "shop"}
+	}
+	ttl := int32(3600)
+	finished := func(at time.Time) batchv1.JobStatus {
+		return batchv1.JobStatus{Conditions: []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue, LastTransitionTime: metav1.NewTime(at)}}}
+	}
+	ready := true
+
+	inv := hygieneInventory{
+		Pods: []v1.Pod{
+			{ObjectMeta: meta("web-1"), Spec: v1.PodSpec{
+				Containers: []v1.Container{{Name: "app", EnvFrom: []v1.EnvFromSource{{ConfigMapRef: &v1.ConfigMapEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "web-env"}}}}}},
+				Volumes:    []v1.Volume{{Name: "data", VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "web-data"}}}},
+			}, Status: v1.PodStatus{Phase: v1.PodRunning}},
+			{ObjectMeta: meta("crashed"), Status: v1.PodStatus{Phase: v1.PodFailed, Reason: "Evicted", StartTime: &metav1.Time{Time: now.Add(-10 * 24 * time.Hour)}}},
+			{ObjectMeta: meta("recent-failure"), Status: v1.PodStatus{Phase: v1.PodFailed, StartTime: &metav1.Time{Time: now.Add(-time.Hour)}}},
+		},
+		Templates: []v1.PodTemplateSpec{{ObjectMeta: meta(""), Spec: v1.PodSpec{
+			Containers: []v1.Container{{Name: "worker", Env: []v1.EnvVar{{Name: "TOKEN", ValueFrom: &v1.EnvVarSource{SecretKeyRef: &v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "worker-token"}}}}}}},
+		}}},
+		ConfigMaps: []v1.ConfigMap{{ObjectMeta: meta("web-env")}, {ObjectMeta: meta("old-env")}, {ObjectMeta: meta("kube-root-ca.crt")}},
+		Secrets: []v1.Secret{
+			{ObjectMeta: meta("worker-token")},
+			{ObjectMeta: meta("stale-creds")},
+			{ObjectMeta: meta("web-tls")},
+			{ObjectMeta: meta("sh.helm.release.v1.web.v1"), Type: "helm.sh/release.v1"},
+		},
+		Services: []v1.Service{
+			{ObjectMeta: meta("web"), Spec: v1.ServiceSpec{Selector: map[string]string{"app": "web"}}},
+			{ObjectMeta: meta("legacy"), Spec: v1.ServiceSpec{Selector: map[string]string{"app": "legacy"}}},
+			{ObjectMeta: meta("external"), Spec: v1.ServiceSpec{Type: v1.ServiceTypeExternalName}},
+		},
+		EndpointSlices: []discoveryv1.EndpointSlice{{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-abc", Namespace: "shop", Labels: map[string]string{discoveryv1.LabelServiceName: "web"}},
+			Endpoints:  []discoveryv1.Endpoint{{Conditions: discoveryv1.EndpointConditions{Ready: &ready}}},
+		}},
+		Ingresses: []networkingv1.Ingress{{ObjectMeta: meta("web"), Spec: networkingv1.IngressSpec{
+			TLS: []networkingv1.IngressTLS{{SecretName: "web-tls"}},
+			Rules: []networkingv1.IngressRule{{IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{Paths: []networkingv1.HTTPIngressPath{
+				{Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "web"}}},
+				{Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "checkout"}}},
+			}}}}},
+		}}},
+		PVCs: []v1.PersistentVolumeClaim{{ObjectMeta: meta("web-data")}, {ObjectMeta: meta("old-data")}},
+		Jobs: []batchv1.Job{
+			{ObjectMeta: meta("migrate"), Spec: batchv1.JobSpec{TTLSecondsAfterFinished: &ttl}, Status: finished(now.Add(-2 * time.Hour))},
+			{ObjectMeta: meta("backfill"), Status: finished(now.Add(-time.Hour))},
+		},
+	}
+
+	findings := findOrphans(inv, hygieneOptions{FailedPodAge: 7 * 24 * time.Hour, FinishedJobAge: 7 * 24 * time.Hour}, now)
+	got := map[string]string{}
+	for _, f := range findings {
+		got[f.Category+"/"+f.Name] = f.Reason
+		if f.Cleanup.Method != "DELETE" || len(f.Cleanup.Body) != 1 || f.Cleanup.Body[0].Name != f.Name {
+			t.Errorf("unexpected cleanup action %+v", f.Cleanup)
+		}
+	}
+	want := []string{
+		orphanConfigMap + "/old-env",
+		orphanSecret + "/stale-creds",
+		orphanServiceEndpoints + "/legacy",
+		orphanIngressBackend + "/web",
+		orphanPVC + "/old-data",
+		orphanExpiredJob + "/migrate",
+		orphanFailedPod + "/crashed",
+	}
+	for _, key := range want {
+		if _, ok := got[key]; !ok {
+			t.Errorf("expected finding %s, got %v", key, got)
+		}
+	}
+	if len(findings) != len(want) {
+		t.Errorf("expected %d findings, got %v", len(want), got)
+	}
+	if got[orphanIngressBackend+"/web"] != "backend services not found: checkout" {
+		t.Errorf("unexpected ingress reason %q", got[orphanIngressBackend+"/web"])
+	}
+}
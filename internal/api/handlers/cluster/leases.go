@@ -41,7 +41,7 @@ func NewLeasesHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientF
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the current request
-func (h *LeasesHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *LeasesHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -184,7 +184,7 @@ func (h *LeasesHandler) GetLeasesSSE(c *gin.Context) {
 	}
 
 	// For non-SSE requests, return JSON
-	c.JSON(http.StatusOK, initialData)
+	h.sseHandler.SendJSON(c, initialData)
 }
 
 // GetLease returns a specific lease
@@ -0,0 +1,437 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+
+	"github.com/gin-gonic/gin"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+)
+
+// restartedAtAnnotation is the pod template annotation kubectl rollout restart sets
+const restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+const (
+	defaultRestartParallelism = 1
+	maxRestartParallelism     = 10
+	defaultRolloutTimeout     = 5 * time.Minute
+	maxRolloutTimeout         = 30 * time.Minute
+	rolloutPollInterval       = 2 * time.Second
+)
+
+// BulkRestartRequest selects the workloads of a namespace to restart and how fast
+type BulkRestartRequest struct {
+	Selector string `json:"selector" binding:"required"` // label selector, e.g. app.kubernetes.io/part-of=shop
+	// Kinds limits the restart to Deployment, StatefulSet and/or DaemonSet; empty means all three
+	Kinds []string `json:"kinds,omitempty"`
+	// MaxParallel is how many workloads restart at once (default 1, at most 10)
+	MaxParallel int `json:"maxParallel,omitempty"`
+	// WaitForRollout holds each slot until the workload's rollout completes (default true)
+	WaitForRollout *bool `json:"waitForRollout,omitempty"`
+	// RolloutTimeoutSeconds bounds the wait for one rollout (default 300, at most 1800)
+	RolloutTimeoutSeconds int `json:"rolloutTimeoutSeconds,omitempty"`
+}
+
+// BulkRestartWorkload is the progress of one workload in a bulk restart
+type BulkRestartWorkload struct {
+	Kind       string     `json:"kind"`
+	Name       string     `json:"name"`
+	State      string     `json:"state"` // pending, restarting, rolling-out, done, skipped or failed
+	Message    string     `json:"message,omitempty"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// BulkRestartOperation tracks a running or finished bulk restart
+type BulkRestartOperation struct {
+	Namespace      string                `json:"namespace"`
+	Selector       string                `json:"selector"`
+	State          string                `json:"state"` // running, completed or failed
+	MaxParallel    int                   `json:"maxParallel"`
+	WaitForRollout bool                  `json:"waitForRollout"`
+	Total          int                   `json:"total"`
+	Processed      int                   `json:"processed"`
+	Failed         int                   `json:"failed"`
+	Workloads      []BulkRestartWorkload `json:"workloads"`
+	StartedAt      time.Time             `json:"startedAt"`
+	FinishedAt     *time.Time            `json:"finishedAt,omitempty"`
+}
+
+// restartTarget is one workload matched by the selector
+type restartTarget struct {
+	kind, name string
+	skip       string // reason the workload is left untouched
+}
+
+// restartKinds validates the requested kinds, defaulting to every restartable kind
+func restartKinds(kinds []string) (map[string]bool, error) {
+	selected := map[string]bool{}
+	if len(kinds) == 0 {
+		kinds = []string{"Deployment", "StatefulSet", "DaemonSet"}
+	}
+	for _, kind := range kinds {
+		switch kind {
+		case "Deployment", "StatefulSet", "DaemonSet":
+			selected[kind] = true
+		default:
+			return nil, fmt.Errorf("unsupported kind %q: kinds must be Deployment, StatefulSet or DaemonSet", kind)
+		}
+	}
+	return selected, nil
+}
+
+// planRestart lists the workloads matching the selector. Workloads whose pods would not be
+// replaced by a template change are skipped rather than reported restarted.
+func planRestart(ctx context.Context, client kubernetes.Interface, namespace, selector string, kinds map[string]bool) ([]restartTarget, error) {
+	var targets []restartTarget
+	opts := metav1.ListOptions{LabelSelector: selector}
+	if kinds["Deployment"] {
+		deployments, err := client.AppsV1().Deployments(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list deployments: %w", err)
+		}
+		for _, d := range deployments.Items {
+			target := restartTarget{kind: "Deployment", name: d.Name}
+			if d.Spec.Paused {
+				target.skip = "rollout is paused"
+			}
+			targets = append(targets, target)
+		}
+	}
+	if kinds["StatefulSet"] {
+		statefulSets, err := client.AppsV1().StatefulSets(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list statefulsets: %w", err)
+		}
+		for _, s := range statefulSets.Items {
+			target := restartTarget{kind: "StatefulSet", name: s.Name}
+			if s.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+				target.skip = "OnDelete update strategy: pods are only replaced when deleted"
+			}
+			targets = append(targets, target)
+		}
+	}
+	if kinds["DaemonSet"] {
+		daemonSets, err := client.AppsV1().DaemonSets(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list daemonsets: %w", err)
+		}
+		for _, ds := range daemonSets.Items {
+			target := restartTarget{kind: "DaemonSet", name: ds.Name}
+			if ds.Spec.UpdateStrategy.Type == appsv1.OnDeleteDaemonSetStrategyType {
+				target.skip = "OnDelete update strategy: pods are only replaced when deleted"
+			}
+			targets = append(targets, target)
+		}
+	}
+	return targets, nil
+}
+
+// restartWorkload sets the restartedAt pod template annotation, as kubectl rollout restart does
+func restartWorkload(ctx context.Context, client kubernetes.Interface, namespace string, target restartTarget, now time.Time) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{"template": map[string]interface{}{"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{restartedAtAnnotation: now.Format(time.RFC3339)},
+		}}},
+	})
+	if err != nil {
+		return err
+	}
+	switch target.kind {
+	case "Deployment":
+		_, err = client.AppsV1().Deployments(namespace).Patch(ctx, target.name, k8stypes.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case "StatefulSet":
+		_, err = client.AppsV1().StatefulSets(namespace).Patch(ctx, target.name, k8stypes.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case "DaemonSet":
+		_, err = client.AppsV1().DaemonSets(namespace).Patch(ctx, target.name, k8stypes.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	default:
+		err = fmt.Errorf("unsupported kind %s", target.kind)
+	}
+	return err
+}
+
+// deploymentRolledOut reports whether every replica runs the latest template and is available,
+// or an error once the rollout exceeded its progress deadline
+func deploymentRolledOut(d *appsv1.Deployment) (bool, error) {
+	if d.Status.ObservedGeneration < d.Generation {
+		return false, nil
+	}
+	for _, condition := range d.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Reason == "ProgressDeadlineExceeded" {
+			return false, fmt.Errorf("rollout exceeded its progress deadline")
+		}
+	}
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	return d.Status.UpdatedReplicas >= replicas && d.Status.Replicas <= d.Status.UpdatedReplicas &&
+		d.Status.AvailableReplicas >= d.Status.UpdatedReplicas, nil
+}
+
+// statefulSetRolledOut reports whether the replicas covered by the update strategy run the update revision and are ready
+func statefulSetRolledOut(s *appsv1.StatefulSet) bool {
+	if s.Status.ObservedGeneration < s.Generation {
+		return false
+	}
+	replicas := int32(1)
+	if s.Spec.Replicas != nil {
+		replicas = *s.Spec.Replicas
+	}
+	if ru := s.Spec.UpdateStrategy.RollingUpdate; ru != nil && ru.Partition != nil && *ru.Partition > 0 {
+		return s.Status.UpdatedReplicas >= replicas-*ru.Partition
+	}
+	return s.Status.UpdateRevision == s.Status.CurrentRevision && s.Status.ReadyReplicas >= replicas
+}
+
+// daemonSetRolledOut reports whether every scheduled daemon pod runs the latest template and is available
+func daemonSetRolledOut(ds *appsv1.DaemonSet) bool {
+	if ds.Status.ObservedGeneration < ds.Generation {
+		return false
+	}
+	return ds.Status.UpdatedNumberScheduled >= ds.Status.DesiredNumberScheduled &&
+		ds.Status.NumberAvailable >= ds.Status.DesiredNumberScheduled
+}
+
+// waitForRollout polls a workload until its rollout completes or the context ends
+func waitForRollout(ctx context.Context, client kubernetes.Interface, namespace string, target restartTarget) error {
+	ticker := time.NewTicker(rolloutPollInterval)
+	defer ticker.Stop()
+	for {
+		var done bool
+		var err error
+		switch target.kind {
+		case "Deployment":
+			var d *appsv1.Deployment
+			if d, err = client.AppsV1().Deployments(namespace).Get(ctx, target.name, metav1.GetOptions{}); err == nil {
+				done, err = deploymentRolledOut(d)
+			}
+		case "StatefulSet":
+			var s *appsv1.StatefulSet
+			if s, err = client.AppsV1().StatefulSets(namespace).Get(ctx, target.name, metav1.GetOptions{}); err == nil {
+				done = statefulSetRolledOut(s)
+			}
+		case "DaemonSet":
+			var ds *appsv1.DaemonSet
+			if ds, err = client.AppsV1().DaemonSets(namespace).Get(ctx, target.name, metav1.GetOptions{}); err == nil {
+				done = daemonSetRolledOut(ds)
+			}
+		}
+		if done {
+			return nil
+		}
+		if err != nil && ctx.Err() == nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("rollout did not complete in time")
+		case <-ticker.C:
+		}
+	}
+}
+
+// restartKey scopes bulk restart progress to a config, cluster and namespace
+func restartKey(configID, cluster, namespace string) string {
+	return fmt.Sprintf("%s/%s/%s", configID, cluster, namespace)
+}
+
+// newRestartTracker tracks bulk restarts; a running one blocks another in the same namespace
+func newRestartTracker() *utils.ProgressTracker[BulkRestartOperation] {
+	return utils.NewProgressTracker(utils.ProgressRetention,
+		func(op *BulkRestartOperation) bool { return op.State == "running" },
+		func(op *BulkRestartOperation) *BulkRestartOperation {
+			next := *op
+			next.Workloads = append([]BulkRestartWorkload{}, op.Workloads...)
+			return &next
+		})
+}
+
+// runBulkRestart restarts the targets with at most maxParallel in flight. A slot is held until the
+// workload's rollout completes, so pods are never replaced across all workloads at once.
+func (h *NamespacesHandler) runBulkRestart(client kubernetes.Interface, key, namespace string, targets []restartTarget, maxParallel int, wait bool, rolloutTimeout time.Duration) {
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, maxParallel)
+	for i, target := range targets {
+		if target.skip != "" {
+			h.restartOperations.Update(key, func(op *BulkRestartOperation) {
+				op.Workloads[i].State, op.Workloads[i].Message = "skipped", target.skip
+				op.Processed++
+			})
+			continue
+		}
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(i int, target restartTarget) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			h.restartOperations.Update(key, func(op *BulkRestartOperation) {
+				op.Workloads[i].State, op.Workloads[i].StartedAt = "restarting", ptr.To(time.Now())
+			})
+
+			ctx, cancel := context.WithTimeout(context.Background(), rolloutTimeout)
+			defer cancel()
+			err := restartWorkload(ctx, client, namespace, target, time.Now())
+			if err == nil && wait {
+				h.restartOperations.Update(key, func(op *BulkRestartOperation) { op.Workloads[i].State = "rolling-out" })
+				err = waitForRollout(ctx, client, namespace, target)
+			}
+
+			state, message := "done", ""
+			if err != nil {
+				state, message = "failed", err.Error()
+				h.logger.WithError(err).WithField("namespace", namespace).WithField("workload", target.kind+"/"+target.name).Error("Bulk restart step failed")
+			}
+			h.restartOperations.Update(key, func(op *BulkRestartOperation) {
+				op.Workloads[i].State, op.Workloads[i].Message, op.Workloads[i].FinishedAt = state, message, ptr.To(time.Now())
+				op.Processed++
+				if state == "failed" {
+					op.Failed++
+				}
+			})
+		}(i, target)
+	}
+	wg.Wait()
+
+	failed := 0
+	h.restartOperations.Update(key, func(op *BulkRestartOperation) {
+		failed = op.Failed
+		op.State = "completed"
+		if op.Failed > 0 {
+			op.State = "failed"
+		}
+		op.FinishedAt = ptr.To(time.Now())
+	})
+	h.logger.WithField("namespace", namespace).WithField("failed", failed).Info("Bulk restart finished")
+}
+
+// RestartNamespaceWorkloads rolling-restarts the workloads of a namespace matching a label selector
+// @Summary Bulk restart workloads by label selector
+// @Description Performs a rolling restart, like kubectl rollout restart, of every Deployment, StatefulSet and DaemonSet in the namespace matching the label selector, for example after a CA or secret rotation. Runs in the background; poll restart-status for per-workload progress. At most maxParallel workloads restart at once and, unless waitForRollout is false, each holds its slot until its rollout completes or times out. Paused Deployments and workloads with the OnDelete update strategy are skipped.
+// @Tags Cluster
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param name path string true "Namespace name"
+// @Param request body BulkRestartRequest true "Workloads to restart and throttling"
+// @Success 202 {object} BulkRestartOperation "Restart started"
+// @Failure 400 {object} map[string]string "Bad request - invalid selector, kinds or limits"
+// @Failure 404 {object} map[string]string "Namespace not found or no workload matches"
+// @Failure 409 {object} map[string]string "A bulk restart is already in progress"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/namespaces/{name}/restart [post]
+func (h *NamespacesHandler) RestartNamespaceWorkloads(c *gin.Context) {
+	var req BulkRestartRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if _, err := labels.Parse(req.Selector); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid selector: " + err.Error()})
+		return
+	}
+	kinds, err := restartKinds(req.Kinds)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	maxParallel := req.MaxParallel
+	if maxParallel == 0 {
+		maxParallel = defaultRestartParallelism
+	}
+	if maxParallel < 0 || maxParallel > maxRestartParallelism {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("maxParallel must be between 1 and %d", maxRestartParallelism)})
+		return
+	}
+	rolloutTimeout := defaultRolloutTimeout
+	if req.RolloutTimeoutSeconds != 0 {
+		rolloutTimeout = time.Duration(req.RolloutTimeoutSeconds) * time.Second
+		if rolloutTimeout < 0 || rolloutTimeout > maxRolloutTimeout {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("rolloutTimeoutSeconds must be between 1 and %d", int(maxRolloutTimeout.Seconds()))})
+			return
+		}
+	}
+	wait := req.WaitForRollout == nil || *req.WaitForRollout
+
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for bulk restart")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	namespace := c.Param("name")
+	key := restartKey(c.Query("config"), c.Query("cluster"), namespace)
+	if _, err := client.CoreV1().Namespaces().Get(c.Request.Context(), namespace, metav1.GetOptions{}); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	targets, err := planRestart(c.Request.Context(), client, namespace, req.Selector, kinds)
+	if err != nil {
+		h.logger.WithError(err).WithField("namespace", namespace).Error("Failed to plan bulk restart")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(targets) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("no workloads in namespace %s match %q", namespace, req.Selector)})
+		return
+	}
+
+	status := &BulkRestartOperation{
+		Namespace:      namespace,
+		Selector:       req.Selector,
+		State:          "running",
+		MaxParallel:    maxParallel,
+		WaitForRollout: wait,
+		Total:          len(targets),
+		Workloads:      make([]BulkRestartWorkload, len(targets)),
+		StartedAt:      time.Now(),
+	}
+	for i, target := range targets {
+		status.Workloads[i] = BulkRestartWorkload{Kind: target.kind, Name: target.name, State: "pending"}
+	}
+	if _, started := h.restartOperations.Start(key, status); !started {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("a bulk restart is already in progress for namespace %s", namespace)})
+		return
+	}
+
+	go h.runBulkRestart(client, key, namespace, targets, maxParallel, wait, rolloutTimeout)
+
+	c.JSON(http.StatusAccepted, status)
+}
+
+// GetNamespaceRestartStatus returns the progress of the latest bulk restart in a namespace
+// @Summary Get bulk restart status
+// @Description Returns the per-workload progress of the most recent bulk restart in the namespace
+// @Tags Cluster
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param name path string true "Namespace name"
+// @Success 200 {object} BulkRestartOperation "Bulk restart progress"
+// @Failure 404 {object} map[string]string "No bulk restart found"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/namespaces/{name}/restart-status [get]
+func (h *NamespacesHandler) GetNamespaceRestartStatus(c *gin.Context) {
+	op, ok := h.restartOperations.Load(restartKey(c.Query("config"), c.Query("cluster"), c.Param("name")))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no bulk restart found for this namespace"})
+		return
+	}
+	c.JSON(http.StatusOK, op)
+}
@@ -0,0 +1,106 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRolloutComplete(t *testing.T) {
+	replicas := int32(3)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Generation: 2},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status:     appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 4, UpdatedReplicas: 3, AvailableReplicas: 3},
+	}
+	if done, _ := deploymentRolledOut(deployment); done {
+		t.Error("expected a deployment with an old replica left to be rolling out")
+	}
+	deployment.Status.Replicas = 3
+	if done, err := deploymentRolledOut(deployment); !done || err != nil {
+		t.Errorf("deploymentRolledOut() = %v, %v; want done", done, err)
+	}
+	deployment.Status.Conditions = []appsv1.DeploymentCondition{{Type: appsv1.DeploymentProgressing, Reason: "ProgressDeadlineExceeded"}}
+	if _, err := deploymentRolledOut(deployment); err == nil {
+		t.Error("expected an exceeded progress deadline to fail the rollout")
+	}
+
+	partition := int32(2)
+	statefulSet := &appsv1.StatefulSet{
+		Spec:   appsv1.StatefulSetSpec{Replicas: &replicas},
+		Status: appsv1.StatefulSetStatus{ReadyReplicas: 3, CurrentRevision: "web-1", UpdateRevision: "web-2", UpdatedReplicas: 1},
+	}
+	if statefulSetRolledOut(statefulSet) {
+		t.Error("expected a statefulset with pods on the old revision to be rolling out")
+	}
+	statefulSet.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateStatefulSetStrategy{Partition: &partition}
+	if !statefulSetRolledOut(statefulSet) {
+		t.Error("expected pods below the partition not to hold the rollout")
+	}
+
+	daemonSet := &appsv1.DaemonSet{Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 4, UpdatedNumberScheduled: 4, NumberAvailable: 3}}
+	if daemonSetRolledOut(daemonSet) {
+		t.Error("expected a daemonset with an unavailable pod to be rolling out")
+	}
+}
+
+func TestBulkRestart(t *testing.T) {
+	selected := map[string]string{"uses-ca": "true"}
+	client := fake.NewSimpleClientset(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", Labels: selected}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "paused", Namespace: "shop", Labels: selected}, Spec: appsv1.DeploymentSpec{Paused: true}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "shop"}},
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "shop", Labels: selected}},
+		&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "shop", Labels: selected},
+			Spec: appsv1.DaemonSetSpec{UpdateStrategy: appsv1.DaemonSetUpdateStrategy{Type: appsv1.OnDeleteDaemonSetStrategyType}}},
+	)
+
+	if _, err := restartKinds([]string{"CronJob"}); err == nil {
+		t.Error("expected an unsupported kind to be rejected")
+	}
+	kinds, _ := restartKinds(nil)
+	targets, err := planRestart(context.Background(), client, "shop", "uses-ca=true", kinds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	skips := map[string]string{}
+	for _, target := range targets {
+		skips[target.name] = target.skip
+	}
+	if len(targets) != 4 || skips["web"] != "" || skips["db"] != "" || skips["paused"] == "" || skips["agent"] == "" {
+		t.Fatalf("unexpected plan %+v", targets)
+	}
+
+	h := NewNamespacesHandler(nil, nil, logger.New("error"))
+	key := restartKey("c1", "", "shop")
+	op := &BulkRestartOperation{Namespace: "shop", State: "running", Total: len(targets), Workloads: make([]BulkRestartWorkload, len(targets))}
+	h.restartOperations.Start(key, op)
+	h.runBulkRestart(client, key, "shop", targets, 2, false, time.Minute)
+
+	done, _ := h.restartOperations.Load(key)
+	if done.State != "completed" || done.Processed != 4 || done.Failed != 0 {
+		t.Fatalf("unexpected operation %+v", done)
+	}
+	states := map[string]string{}
+	for i, w := range done.Workloads {
+		states[targets[i].name] = w.State
+	}
+	if states["web"] != "done" || states["db"] != "done" || states["paused"] != "skipped" || states["agent"] != "skipped" {
+		t.Errorf("unexpected workload states %v", states)
+	}
+
+	web, _ := client.AppsV1().Deployments("shop").Get(context.Background(), "web", metav1.GetOptions{})
+	if web.Spec.Template.Annotations[restartedAtAnnotation] == "" {
+		t.Error("expected the restartedAt annotation to be set on the pod template")
+	}
+	other, _ := client.AppsV1().Deployments("shop").Get(context.Background(), "other", metav1.GetOptions{})
+	if other.Spec.Template.Annotations[restartedAtAnnotation] != "" {
+		t.Error("expected workloads outside the selector to be left alone")
+	}
+}
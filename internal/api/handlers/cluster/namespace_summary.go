@@ -0,0 +1,466 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsclient "k8s.io/metrics/pkg/client/clientset/versioned"
+)
+
+const (
+	namespaceSummaryTopN        = 10
+	namespaceSummaryEventLimit  = 20
+	namespaceSummaryMetricsWait = 2 * time.Second
+)
+
+// WorkloadHealthCount counts the workloads of one kind by health
+type WorkloadHealthCount struct {
+	Kind       string `json:"kind"`
+	Total      int    `json:"total"`
+	Healthy    int    `json:"healthy"`
+	Degraded   int    `json:"degraded"`
+	ScaledDown int    `json:"scaledDown"` // zero replicas or suspended
+}
+
+// RestartHotSpot is a container with many restarts
+type RestartHotSpot struct {
+	Pod        string `json:"pod"`
+	Container  string `json:"container"`
+	Restarts   int32  `json:"restarts"`
+	LastReason string `json:"lastReason,omitempty"`
+}
+
+// QuotaResourceUsage is the usage of one resource against a quota's hard limit
+type QuotaResourceUsage struct {
+	Resource string  `json:"resource"`
+	Used     string  `json:"used"`
+	Hard     string  `json:"hard"`
+	Percent  float64 `json:"percent"`
+}
+
+// QuotaUsage is the usage of a ResourceQuota
+type QuotaUsage struct {
+	Name      string               `json:"name"`
+	Resources []QuotaResourceUsage `json:"resources"`
+}
+
+// PodUsage is the current usage of a pod from the metrics API
+type PodUsage struct {
+	Pod         string `json:"pod"`
+	CPUMilli    int64  `json:"cpuMilli"`
+	MemoryBytes int64  `json:"memoryBytes"`
+}
+
+// WarningEvent is a recent warning event in the namespace
+type WarningEvent struct {
+	Reason   string    `json:"reason"`
+	Message  string    `json:"message"`
+	Object   string    `json:"object"`
+	Count    int32     `json:"count"`
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+// NamespaceSummary is everything the namespace page renders, in one payload
+type NamespaceSummary struct {
+	Namespace        string                `json:"namespace"`
+	Phase            string                `json:"phase"`
+	GeneratedAt      time.Time             `json:"generatedAt"`
+	Workloads        []WorkloadHealthCount `json:"workloads"`
+	PodPhases        map[string]int        `json:"podPhases"`
+	RestartHotSpots  []RestartHotSpot      `json:"restartHotSpots"`
+	Quotas           []QuotaUsage          `json:"quotas"`
+	MetricsAvailable bool                  `json:"metricsAvailable"`
+	TopCPU           []PodUsage            `json:"topCpu"`
+	TopMemory        []PodUsage            `json:"topMemory"`
+	WarningEvents    []WarningEvent        `json:"warningEvents"`
+	Skipped          []string              `json:"skipped"` // resources that could not be listed
+}
+
+// namespaceInventory holds the objects the summary is computed from
+type namespaceInventory struct {
+	Deployments  []appsv1.Deployment
+	StatefulSets []appsv1.StatefulSet
+	DaemonSets   []appsv1.DaemonSet
+	Jobs         []batchv1.Job
+	CronJobs     []batchv1.CronJob
+	Pods         []v1.Pod
+	Quotas       []v1.ResourceQuota
+	Events       []v1.Event
+	PodMetrics   []metricsv1beta1.PodMetrics
+}
+
+func countHealth(kind string, n int, status func(i int) (desired, ready int32, suspended bool)) WorkloadHealthCount {
+	count := WorkloadHealthCount{Kind: kind, Total: n}
+	for i := 0; i < n; i++ {
+		switch workloadHealth(status(i)) {
+		case healthScaledDown:
+			count.ScaledDown++
+		case healthHealthy:
+			count.Healthy++
+		default:
+			count.Degraded++
+		}
+	}
+	return count
+}
+
+// Workload health classes
+const (
+	healthHealthy    = "healthy"
+	healthDegraded   = "degraded"
+	healthScaledDown = "scaledDown"
+)
+
+// workloadHealth classifies a workload from its desired and ready replicas
+func workloadHealth(desired, ready int32, suspended bool) string {
+	switch {
+	case suspended || desired == 0:
+		return healthScaledDown
+	case ready >= desired:
+		return healthHealthy
+	default:
+		return healthDegraded
+	}
+}
+
+// summarizeWorkloads counts workloads by kind and health
+func summarizeWorkloads(inv *namespaceInventory) []WorkloadHealthCount {
+	replicas := func(r *int32) int32 {
+		if r == nil {
+			return 1
+		}
+		return *r
+	}
+	return []WorkloadHealthCount{
+		countHealth("Deployment", len(inv.Deployments), func(i int) (int32, int32, bool) {
+			d := &inv.Deployments[i]
+			return replicas(d.Spec.Replicas), d.Status.AvailableReplicas, false
+		}),
+		countHealth("StatefulSet", len(inv.StatefulSets), func(i int) (int32, int32, bool) {
+			s := &inv.StatefulSets[i]
+			return replicas(s.Spec.Replicas), s.Status.ReadyReplicas, false
+		}),
+		countHealth("DaemonSet", len(inv.DaemonSets), func(i int) (int32, int32, bool) {
+			d := &inv.DaemonSets[i]
+			return d.Status.DesiredNumberScheduled, d.Status.NumberReady, false
+		}),
+		countHealth("Job", len(inv.Jobs), func(i int) (int32, int32, bool) {
+			// A failed job is degraded; running and completed jobs are healthy
+			for _, condition := range inv.Jobs[i].Status.Conditions {
+				if condition.Type == batchv1.JobFailed && condition.Status == v1.ConditionTrue {
+					return 1, 0, false
+				}
+			}
+			return 1, 1, inv.Jobs[i].Spec.Suspend != nil && *inv.Jobs[i].Spec.Suspend
+		}),
+		countHealth("CronJob", len(inv.CronJobs), func(i int) (int32, int32, bool) {
+			return 1, 1, inv.CronJobs[i].Spec.Suspend != nil && *inv.CronJobs[i].Spec.Suspend
+		}),
+	}
+}
+
+// restartHotSpots returns the containers with the most restarts
+func restartHotSpots(pods []v1.Pod, limit int) []RestartHotSpot {
+	spots := []RestartHotSpot{}
+	for _, pod := range pods {
+		statuses := append(append([]v1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+		for _, status := range statuses {
+			if status.RestartCount == 0 {
+				continue
+			}
+			spot := RestartHotSpot{Pod: pod.Name, Container: status.Name, Restarts: status.RestartCount}
+			if status.LastTerminationState.Terminated != nil {
+				spot.LastReason = status.LastTerminationState.Terminated.Reason
+			}
+			if status.State.Waiting != nil && status.State.Waiting.Reason != "" {
+				spot.LastReason = status.State.Waiting.Reason
+			}
+			spots = append(spots, spot)
+		}
+	}
+	sort.SliceStable(spots, func(i, j int) bool { return spots[i].Restarts > spots[j].Restarts })
+	if len(spots) > limit {
+		spots = spots[:limit]
+	}
+	return spots
+}
+
+// quotaUsage reports each quota's used amount against its hard limit
+func quotaUsage(quotas []v1.ResourceQuota) []QuotaUsage {
+	usage := []QuotaUsage{}
+	for _, quota := range quotas {
+		q := QuotaUsage{Name: quota.Name, Resources: []QuotaResourceUsage{}}
+		for resource, hard := range quota.Status.Hard {
+			used := quota.Status.Used[resource]
+			r := QuotaResourceUsage{Resource: string(resource), Used: used.String(), Hard: hard.String()}
+			if hard.MilliValue() > 0 {
+				r.Percent = float64(used.MilliValue()) / float64(hard.MilliValue()) * 100
+			}
+			q.Resources = append(q.Resources, r)
+		}
+		sort.Slice(q.Resources, func(i, j int) bool { return q.Resources[i].Percent > q.Resources[j].Percent })
+		usage = append(usage, q)
+	}
+	return usage
+}
+
+// topConsumers returns the pods using the most CPU and the most memory
+func topConsumers(metrics []metricsv1beta1.PodMetrics, limit int) ([]PodUsage, []PodUsage) {
+	usage := make([]PodUsage, 0, len(metrics))
+	for _, pm := range metrics {
+		u := PodUsage{Pod: pm.Name}
+		for _, container := range pm.Containers {
+			if cpu, ok := container.Usage[v1.ResourceCPU]; ok {
+				u.CPUMilli += cpu.MilliValue()
+			}
+			if memory, ok := container.Usage[v1.ResourceMemory]; ok {
+				u.MemoryBytes += memory.Value()
+			}
+		}
+		usage = append(usage, u)
+	}
+	top := func(less func(a, b PodUsage) bool) []PodUsage {
+		sorted := append([]PodUsage{}, usage...)
+		sort.SliceStable(sorted, func(i, j int) bool { return less(sorted[i], sorted[j]) })
+		if len(sorted) > limit {
+			sorted = sorted[:limit]
+		}
+		return sorted
+	}
+	return top(func(a, b PodUsage) bool { return a.CPUMilli > b.CPUMilli }),
+		top(func(a, b PodUsage) bool { return a.MemoryBytes > b.MemoryBytes })
+}
+
+// recentWarnings returns the most recent warning events
+func recentWarnings(events []v1.Event, limit int) []WarningEvent {
+	warnings := []WarningEvent{}
+	for _, event := range events {
+		if event.Type != v1.EventTypeWarning {
+			continue
+		}
+		lastSeen := event.LastTimestamp.Time
+		if lastSeen.IsZero() {
+			lastSeen = event.EventTime.Time
+		}
+		if lastSeen.IsZero() {
+			lastSeen = event.CreationTimestamp.Time
+		}
+		count := event.Count
+		if count == 0 {
+			count = 1
+		}
+		warnings = append(warnings, WarningEvent{
+			Reason:   event.Reason,
+			Message:  event.Message,
+			Object:   event.InvolvedObject.Kind + "/" + event.InvolvedObject.Name,
+			Count:    count,
+			LastSeen: lastSeen,
+		})
+	}
+	sort.SliceStable(warnings, func(i, j int) bool { return warnings[i].LastSeen.After(warnings[j].LastSeen) })
+	if len(warnings) > limit {
+		warnings = warnings[:limit]
+	}
+	return warnings
+}
+
+// buildNamespaceSummary computes the summary from the listed objects
+func buildNamespaceSummary(namespace string, inv *namespaceInventory, metricsAvailable bool, now time.Time) NamespaceSummary {
+	summary := NamespaceSummary{
+		Namespace:        namespace,
+		GeneratedAt:      now,
+		Workloads:        summarizeWorkloads(inv),
+		PodPhases:        map[string]int{},
+		RestartHotSpots:  restartHotSpots(inv.Pods, namespaceSummaryTopN),
+		Quotas:           quotaUsage(inv.Quotas),
+		MetricsAvailable: metricsAvailable,
+		WarningEvents:    recentWarnings(inv.Events, namespaceSummaryEventLimit),
+		Skipped:          []string{},
+	}
+	for _, pod := range inv.Pods {
+		summary.PodPhases[string(pod.Status.Phase)]++
+	}
+	summary.TopCPU, summary.TopMemory = topConsumers(inv.PodMetrics, namespaceSummaryTopN)
+	return summary
+}
+
+// GetNamespaceSummary returns the namespace dashboard payload
+// @Summary Get Namespace summary
+// @Description Returns everything the namespace page renders in one call: workload counts by kind and health, pod phase distribution, containers with the most restarts, ResourceQuota usage, the pods using the most CPU and memory (when metrics-server is available) and recent warning events. Resources the caller cannot list are named in skipped.
+// @Tags Cluster
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name for multi-cluster setups"
+// @Param name path string true "Namespace name"
+// @Success 200 {object} NamespaceSummary
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
+// @Failure 404 {object} map[string]string "Namespace not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/namespaces/{name}/summary [get]
+func (h *NamespacesHandler) GetNamespaceSummary(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for namespace summary")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Successfully obtained Kubernetes client")
+
+	name := c.Param("name")
+	reqCtx := c.Request.Context()
+	ns, err := client.CoreV1().Namespaces().Get(reqCtx, name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.WithError(err).WithField("namespace", name).Error("Failed to get namespace for summary")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	_, listSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "namespace-summary", name)
+	defer listSpan.End()
+
+	var (
+		inv     namespaceInventory
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		skipped []string
+		failed  error
+	)
+	list := func(resource string, fn func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := fn(); err != nil {
+				mu.Lock()
+				defer mu.Unlock()
+				if apierrors.IsForbidden(err) {
+					skipped = append(skipped, resource)
+				} else if failed == nil {
+					failed = fmt.Errorf("failed to list %s: %w", resource, err)
+				}
+			}
+		}()
+	}
+	opts := metav1.ListOptions{}
+	list("deployments", func() error {
+		l, err := client.AppsV1().Deployments(name).List(reqCtx, opts)
+		if err == nil {
+			inv.Deployments = l.Items
+		}
+		return err
+	})
+	list("statefulsets", func() error {
+		l, err := client.AppsV1().StatefulSets(name).List(reqCtx, opts)
+		if err == nil {
+			inv.StatefulSets = l.Items
+		}
+		return err
+	})
+	list("daemonsets", func() error {
+		l, err := client.AppsV1().DaemonSets(name).List(reqCtx, opts)
+		if err == nil {
+			inv.DaemonSets = l.Items
+		}
+		return err
+	})
+	list("jobs", func() error {
+		l, err := client.BatchV1().Jobs(name).List(reqCtx, opts)
+		if err == nil {
+			inv.Jobs = l.Items
+		}
+		return err
+	})
+	list("cronjobs", func() error {
+		l, err := client.BatchV1().CronJobs(name).List(reqCtx, opts)
+		if err == nil {
+			inv.CronJobs = l.Items
+		}
+		return err
+	})
+	list("pods", func() error {
+		l, err := client.CoreV1().Pods(name).List(reqCtx, opts)
+		if err == nil {
+			inv.Pods = l.Items
+		}
+		return err
+	})
+	list("resourcequotas", func() error {
+		l, err := client.CoreV1().ResourceQuotas(name).List(reqCtx, opts)
+		if err == nil {
+			inv.Quotas = l.Items
+		}
+		return err
+	})
+	list("events", func() error {
+		l, err := client.CoreV1().Events(name).List(reqCtx, metav1.ListOptions{FieldSelector: "type=Warning"})
+		if err == nil {
+			inv.Events = l.Items
+		}
+		return err
+	})
+
+	// Metrics are best effort: metrics-server may be missing or slow
+	metricsAvailable := false
+	if mClient, err := h.getMetricsClient(c); err == nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			metricsCtx, cancel := context.WithTimeout(reqCtx, namespaceSummaryMetricsWait)
+			defer cancel()
+			if l, err := mClient.MetricsV1beta1().PodMetricses(name).List(metricsCtx, opts); err == nil {
+				inv.PodMetrics = l.Items
+				metricsAvailable = true
+			}
+		}()
+	}
+	wg.Wait()
+
+	if failed != nil {
+		h.logger.WithError(failed).WithField("namespace", name).Error("Failed to build namespace summary")
+		h.tracingHelper.RecordError(listSpan, failed, "Failed to list namespace resources")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": failed.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(listSpan, "Namespace resources listed")
+
+	summary := buildNamespaceSummary(name, &inv, metricsAvailable, time.Now())
+	summary.Phase = string(ns.Status.Phase)
+	if skipped != nil {
+		sort.Strings(skipped)
+		summary.Skipped = skipped
+	}
+	c.JSON(http.StatusOK, summary)
+}
+
+// getMetricsClient gets the metrics API client for the current request
+func (h *NamespacesHandler) getMetricsClient(c *gin.Context) (*metricsclient.Clientset, error) {
+	configID := c.Query("config")
+	if configID == "" {
+		return nil, fmt.Errorf("config parameter is required")
+	}
+	config, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, fmt.Errorf("config not found: %w", err)
+	}
+	return h.clientFactory.GetMetricsClientForConfig(config, c.Query("cluster"))
+}
@@ -0,0 +1,86 @@
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+func TestBuildNamespaceSummary(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	three, zero := int32(3), int32(0)
+	suspend := true
+	inv := &namespaceInventory{
+		Deployments: []appsv1.Deployment{
+			{Spec: appsv1.DeploymentSpec{Replicas: &three}, Status: appsv1.DeploymentStatus{AvailableReplicas: 3}},
+			{Spec: appsv1.DeploymentSpec{Replicas: &three}, Status: appsv1.DeploymentStatus{AvailableReplicas: 1}},
+			{Spec: appsv1.DeploymentSpec{Replicas: &zero}},
+		},
+		CronJobs: []batchv1.CronJob{{Spec: batchv1.CronJobSpec{Suspend: &suspend}}},
+		Jobs: []batchv1.Job{{Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+			{Type: batchv1.JobFailed, Status: v1.ConditionTrue},
+		}}}},
+		Pods: []v1.Pod{
+			{ObjectMeta: metav1.ObjectMeta{Name: "api"}, Status: v1.PodStatus{Phase: v1.PodRunning, ContainerStatuses: []v1.ContainerStatus{
+				{Name: "app", RestartCount: 12, State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+			}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "worker"}, Status: v1.PodStatus{Phase: v1.PodRunning, ContainerStatuses: []v1.ContainerStatus{
+				{Name: "app", RestartCount: 2, LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled"}}},
+			}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "seed"}, Status: v1.PodStatus{Phase: v1.PodSucceeded}},
+		},
+		Quotas: []v1.ResourceQuota{{ObjectMeta: metav1.ObjectMeta{Name: "compute"}, Status: v1.ResourceQuotaStatus{
+			Hard: v1.ResourceList{v1.ResourceRequestsCPU: resource.MustParse("4"), v1.ResourcePods: resource.MustParse("10")},
+			Used: v1.ResourceList{v1.ResourceRequestsCPU: resource.MustParse("3"), v1.ResourcePods: resource.MustParse("2")},
+		}}},
+		Events: []v1.Event{
+			{Type: v1.EventTypeWarning, Reason: "BackOff", LastTimestamp: metav1.NewTime(now.Add(-time.Minute))},
+			{Type: v1.EventTypeWarning, Reason: "FailedMount", LastTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+			{Type: v1.EventTypeNormal, Reason: "Pulled", LastTimestamp: metav1.NewTime(now)},
+		},
+		PodMetrics: []metricsv1beta1.PodMetrics{
+			{ObjectMeta: metav1.ObjectMeta{Name: "api"}, Containers: []metricsv1beta1.ContainerMetrics{{Usage: v1.ResourceList{
+				v1.ResourceCPU: resource.MustParse("250m"), v1.ResourceMemory: resource.MustParse("128Mi"),
+			}}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "worker"}, Containers: []metricsv1beta1.ContainerMetrics{{Usage: v1.ResourceList{
+				v1.ResourceCPU: resource.MustParse("50m"), v1.ResourceMemory: resource.MustParse("1Gi"),
+			}}}},
+		},
+	}
+
+	summary := buildNamespaceSummary("shop", inv, true, now)
+	deployments := summary.Workloads[0]
+	if deployments.Total != 3 || deployments.Healthy != 1 || deployments.Degraded != 1 || deployments.ScaledDown != 1 {
+		t.Errorf("unexpected deployment health %+v", deployments)
+	}
+	for _, w := range summary.Workloads {
+		if w.Kind == "Job" && w.Degraded != 1 {
+			t.Errorf("expected failed job to be degraded, got %+v", w)
+		}
+		if w.Kind == "CronJob" && w.ScaledDown != 1 {
+			t.Errorf("expected suspended cronjob to be scaled down, got %+v", w)
+		}
+	}
+	if summary.PodPhases["Running"] != 2 || summary.PodPhases["Succeeded"] != 1 {
+		t.Errorf("unexpected pod phases %v", summary.PodPhases)
+	}
+	if len(summary.RestartHotSpots) != 2 || summary.RestartHotSpots[0].Pod != "api" || summary.RestartHotSpots[0].LastReason != "CrashLoopBackOff" ||
+		summary.RestartHotSpots[1].LastReason != "OOMKilled" {
+		t.Errorf("unexpected restart hot spots %+v", summary.RestartHotSpots)
+	}
+	if q := summary.Quotas[0].Resources[0]; q.Resource != string(v1.ResourceRequestsCPU) || q.Percent != 75 {
+		t.Errorf("expected CPU quota to lead at 75%%, got %+v", q)
+	}
+	if summary.TopCPU[0].Pod != "api" || summary.TopMemory[0].Pod != "worker" {
+		t.Errorf("unexpected top consumers cpu=%+v memory=%+v", summary.TopCPU, summary.TopMemory)
+	}
+	if len(summary.WarningEvents) != 2 || summary.WarningEvents[0].Reason != "BackOff" || summary.WarningEvents[0].Count != 1 {
+		t.Errorf("unexpected warning events %+v", summary.WarningEvents)
+	}
+}
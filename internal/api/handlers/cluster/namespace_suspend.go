@@ -0,0 +1,411 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+)
+
+// Annotations recording suspended state so a namespace can be restored later
+const (
+	suspendedAtAnnotation       = "kube-dash.io/suspended-at"
+	suspendedReplicasAnnotation = "kube-dash.io/suspended-replicas"
+	suspendedCronJobAnnotation  = "kube-dash.io/suspended-cronjob"
+)
+
+// NamespaceSuspendWorkload is the progress of one workload in a suspend or resume
+type NamespaceSuspendWorkload struct {
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	Replicas *int32 `json:"replicas,omitempty"` // replicas recorded on suspend or restored on resume
+	State    string `json:"state"`              // pending, done, skipped or failed
+	Message  string `json:"message,omitempty"`
+}
+
+// NamespaceSuspendOperation tracks a running or finished suspend or resume
+type NamespaceSuspendOperation struct {
+	Namespace  string                     `json:"namespace"`
+	Operation  string                     `json:"operation"` // suspend or resume
+	State      string                     `json:"state"`     // running, completed or failed
+	Total      int                        `json:"total"`
+	Processed  int                        `json:"processed"`
+	Failed     int                        `json:"failed"`
+	Workloads  []NamespaceSuspendWorkload `json:"workloads"`
+	StartedAt  time.Time                  `json:"startedAt"`
+	FinishedAt *time.Time                 `json:"finishedAt,omitempty"`
+}
+
+// NamespaceSuspendStatus reports whether a namespace is suspended and the latest operation
+type NamespaceSuspendStatus struct {
+	Namespace   string                     `json:"namespace"`
+	Suspended   bool                       `json:"suspended"`
+	SuspendedAt string                     `json:"suspendedAt,omitempty"`
+	Operation   *NamespaceSuspendOperation `json:"operation,omitempty"`
+}
+
+// suspendKey scopes suspend progress to a config, cluster and namespace
+func suspendKey(configID, cluster, namespace string) string {
+	return fmt.Sprintf("%s/%s/%s", configID, cluster, namespace)
+}
+
+// suspendStep is one patch applied to a workload
+type suspendStep struct {
+	kind, name string
+	replicas   *int32
+	patch      map[string]interface{}
+	skip       string // reason the workload is left untouched
+}
+
+// planSuspend records current replicas and builds patches scaling workloads to zero and suspending
+// cronjobs. Workloads an HPA scales are left alone: the HPA would scale them back up and the
+// recorded replicas would go stale.
+func planSuspend(ctx context.Context, client kubernetes.Interface, namespace string) ([]suspendStep, error) {
+	hpas, err := client.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list horizontalpodautoscalers: %w", err)
+	}
+	autoscaled := make(map[string]string, len(hpas.Items))
+	for _, hpa := range hpas.Items {
+		autoscaled[hpa.Spec.ScaleTargetRef.Kind+"/"+hpa.Spec.ScaleTargetRef.Name] = hpa.Name
+	}
+
+	var steps []suspendStep
+	scaleDown := func(kind, name string, replicas *int32, annotations map[string]string) {
+		current := int32(1)
+		if replicas != nil {
+			current = *replicas
+		}
+		step := suspendStep{kind: kind, name: name, replicas: &current}
+		if _, ok := annotations[suspendedReplicasAnnotation]; ok {
+			step.skip = "already suspended"
+		} else if current == 0 {
+			step.skip = "already scaled to zero"
+		} else if hpa, ok := autoscaled[kind+"/"+name]; ok {
+			step.skip = fmt.Sprintf("scaled by HorizontalPodAutoscaler %s", hpa)
+		} else {
+			step.patch = map[string]interface{}{
+				"metadata": map[string]interface{}{"annotations": map[string]interface{}{suspendedReplicasAnnotation: strconv.Itoa(int(current))}},
+				"spec":     map[string]interface{}{"replicas": 0},
+			}
+		}
+		steps = append(steps, step)
+	}
+
+	deployments, err := client.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	for _, d := range deployments.Items {
+		scaleDown("Deployment", d.Name, d.Spec.Replicas, d.Annotations)
+	}
+	statefulSets, err := client.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statefulsets: %w", err)
+	}
+	for _, s := range statefulSets.Items {
+		scaleDown("StatefulSet", s.Name, s.Spec.Replicas, s.Annotations)
+	}
+	cronJobs, err := client.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cronjobs: %w", err)
+	}
+	for _, cj := range cronJobs.Items {
+		step := suspendStep{kind: "CronJob", name: cj.Name}
+		if cj.Spec.Suspend != nil && *cj.Spec.Suspend {
+			// Leave cronjobs the user suspended alone so resume does not enable them
+			step.skip = "already suspended"
+		} else {
+			step.patch = map[string]interface{}{
+				"metadata": map[string]interface{}{"annotations": map[string]interface{}{suspendedCronJobAnnotation: "true"}},
+				"spec":     map[string]interface{}{"suspend": true},
+			}
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// planResume builds patches restoring the replicas and cronjobs recorded by a suspend
+func planResume(ctx context.Context, client kubernetes.Interface, namespace string) ([]suspendStep, error) {
+	var steps []suspendStep
+	restore := func(kind, name string, annotations map[string]string) {
+		value, ok := annotations[suspendedReplicasAnnotation]
+		if !ok {
+			return
+		}
+		step := suspendStep{kind: kind, name: name}
+		replicas, err := strconv.Atoi(value)
+		if err != nil || replicas < 0 {
+			step.skip = fmt.Sprintf("invalid recorded replicas %q", value)
+		} else {
+			r := int32(replicas)
+			step.replicas = &r
+			step.patch = map[string]interface{}{
+				"metadata": map[string]interface{}{"annotations": map[string]interface{}{suspendedReplicasAnnotation: nil}},
+				"spec":     map[string]interface{}{"replicas": replicas},
+			}
+		}
+		steps = append(steps, step)
+	}
+
+	deployments, err := client.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	for _, d := range deployments.Items {
+		restore("Deployment", d.Name, d.Annotations)
+	}
+	statefulSets, err := client.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statefulsets: %w", err)
+	}
+	for _, s := range statefulSets.Items {
+		restore("StatefulSet", s.Name, s.Annotations)
+	}
+	cronJobs, err := client.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cronjobs: %w", err)
+	}
+	for _, cj := range cronJobs.Items {
+		if cj.Annotations[suspendedCronJobAnnotation] != "true" {
+			continue
+		}
+		steps = append(steps, suspendStep{kind: "CronJob", name: cj.Name, patch: map[string]interface{}{
+			"metadata": map[string]interface{}{"annotations": map[string]interface{}{suspendedCronJobAnnotation: nil}},
+			"spec":     map[string]interface{}{"suspend": false},
+		}})
+	}
+	return steps, nil
+}
+
+// applyStep merge-patches one workload
+func applyStep(ctx context.Context, client kubernetes.Interface, namespace string, step suspendStep) error {
+	data, err := json.Marshal(step.patch)
+	if err != nil {
+		return err
+	}
+	switch step.kind {
+	case "Deployment":
+		_, err = client.AppsV1().Deployments(namespace).Patch(ctx, step.name, k8stypes.MergePatchType, data, metav1.PatchOptions{})
+	case "StatefulSet":
+		_, err = client.AppsV1().StatefulSets(namespace).Patch(ctx, step.name, k8stypes.MergePatchType, data, metav1.PatchOptions{})
+	case "CronJob":
+		_, err = client.BatchV1().CronJobs(namespace).Patch(ctx, step.name, k8stypes.MergePatchType, data, metav1.PatchOptions{})
+	default:
+		err = fmt.Errorf("unsupported kind %s", step.kind)
+	}
+	return err
+}
+
+// newSuspendTracker tracks suspends and resumes; a running one blocks another of the same namespace
+func newSuspendTracker() *utils.ProgressTracker[NamespaceSuspendOperation] {
+	return utils.NewProgressTracker(utils.ProgressRetention,
+		func(op *NamespaceSuspendOperation) bool { return op.State == "running" },
+		func(op *NamespaceSuspendOperation) *NamespaceSuspendOperation {
+			next := *op
+			next.Workloads = append([]NamespaceSuspendWorkload{}, op.Workloads...)
+			return &next
+		})
+}
+
+// startSuspendOperation plans and runs a suspend or resume in the background
+func (h *NamespacesHandler) startSuspendOperation(c *gin.Context, operation string) {
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Errorf("Failed to get client for namespace %s", operation)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	namespace := c.Param("name")
+	key := suspendKey(c.Query("config"), c.Query("cluster"), namespace)
+	ns, err := client.CoreV1().Namespaces().Get(c.Request.Context(), namespace, metav1.GetOptions{})
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if operation == "resume" && ns.Annotations[suspendedAtAnnotation] == "" {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("namespace %s is not suspended", namespace)})
+		return
+	}
+
+	var steps []suspendStep
+	if operation == "suspend" {
+		steps, err = planSuspend(c.Request.Context(), client, namespace)
+	} else {
+		steps, err = planResume(c.Request.Context(), client, namespace)
+	}
+	if err != nil {
+		h.logger.WithError(err).WithField("namespace", namespace).Errorf("Failed to plan namespace %s", operation)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := &NamespaceSuspendOperation{
+		Namespace: namespace,
+		Operation: operation,
+		State:     "running",
+		Total:     len(steps),
+		Workloads: make([]NamespaceSuspendWorkload, len(steps)),
+		StartedAt: time.Now(),
+	}
+	for i, step := range steps {
+		status.Workloads[i] = NamespaceSuspendWorkload{Kind: step.kind, Name: step.name, Replicas: step.replicas, State: "pending"}
+	}
+	if existing, started := h.suspendOperations.Start(key, status); !started {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("a %s is already in progress for namespace %s", existing.Operation, namespace)})
+		return
+	}
+
+	go h.runSuspendOperation(client, key, namespace, operation, steps)
+
+	c.JSON(http.StatusAccepted, status)
+}
+
+func (h *NamespacesHandler) runSuspendOperation(client kubernetes.Interface, key, namespace, operation string, steps []suspendStep) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	// Mark the namespace first so a partially applied suspend can still be resumed
+	if operation == "suspend" {
+		h.patchNamespaceMarker(ctx, client, namespace, time.Now().UTC().Format(time.RFC3339))
+	}
+
+	failed := 0
+	for i, step := range steps {
+		state, message := "done", ""
+		if step.skip != "" {
+			state, message = "skipped", step.skip
+		} else if err := applyStep(ctx, client, namespace, step); err != nil {
+			state, message = "failed", err.Error()
+			failed++
+			h.logger.WithError(err).WithField("namespace", namespace).WithField("workload", step.kind+"/"+step.name).Errorf("Namespace %s step failed", operation)
+		}
+		h.suspendOperations.Update(key, func(s *NamespaceSuspendOperation) {
+			s.Workloads[i].State = state
+			s.Workloads[i].Message = message
+			s.Processed++
+			if state == "failed" {
+				s.Failed++
+			}
+		})
+	}
+
+	// Only clear the marker once everything was restored, so a failed resume can be retried
+	if operation == "resume" && failed == 0 {
+		h.patchNamespaceMarker(ctx, client, namespace, nil)
+	}
+
+	h.suspendOperations.Update(key, func(s *NamespaceSuspendOperation) {
+		s.State = "completed"
+		if failed > 0 {
+			s.State = "failed"
+		}
+		s.FinishedAt = ptr.To(time.Now())
+	})
+	h.logger.WithField("namespace", namespace).WithField("failed", failed).Infof("Namespace %s finished", operation)
+}
+
+// patchNamespaceMarker sets or clears (value nil) the suspended-at annotation on the namespace
+func (h *NamespacesHandler) patchNamespaceMarker(ctx context.Context, client kubernetes.Interface, namespace string, value interface{}) {
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": map[string]interface{}{suspendedAtAnnotation: value}},
+	})
+	if _, err := client.CoreV1().Namespaces().Patch(ctx, namespace, k8stypes.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		h.logger.WithError(err).WithField("namespace", namespace).Warn("Failed to update namespace suspend marker")
+	}
+}
+
+// SuspendNamespace scales all workloads in a namespace to zero and suspends its cronjobs
+// @Summary Suspend namespace
+// @Description Records current replica counts in annotations, scales all Deployments and StatefulSets to zero and suspends CronJobs. Workloads scaled by a HorizontalPodAutoscaler are skipped and listed with the HPA's name. Runs in the background; poll suspend-status for progress.
+// @Tags Cluster
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param name path string true "Namespace name"
+// @Success 202 {object} NamespaceSuspendOperation "Suspend started"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 404 {object} map[string]string "Namespace not found"
+// @Failure 409 {object} map[string]string "An operation is already in progress"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/namespaces/{name}/suspend [post]
+func (h *NamespacesHandler) SuspendNamespace(c *gin.Context) {
+	h.startSuspendOperation(c, "suspend")
+}
+
+// ResumeNamespace restores the workloads of a suspended namespace
+// @Summary Resume namespace
+// @Description Restores the replica counts recorded by a suspend and re-enables the CronJobs it suspended. Runs in the background; poll suspend-status for progress.
+// @Tags Cluster
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param name path string true "Namespace name"
+// @Success 202 {object} NamespaceSuspendOperation "Resume started"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 404 {object} map[string]string "Namespace not found"
+// @Failure 409 {object} map[string]string "Namespace is not suspended or an operation is already in progress"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/namespaces/{name}/resume [post]
+func (h *NamespacesHandler) ResumeNamespace(c *gin.Context) {
+	h.startSuspendOperation(c, "resume")
+}
+
+// GetNamespaceSuspendStatus returns whether a namespace is suspended and the progress of the latest operation
+// @Summary Get namespace suspend status
+// @Description Returns whether the namespace is suspended and the progress of the most recent suspend or resume
+// @Tags Cluster
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param name path string true "Namespace name"
+// @Success 200 {object} NamespaceSuspendStatus "Suspend status"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 404 {object} map[string]string "Namespace not found"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/namespaces/{name}/suspend-status [get]
+func (h *NamespacesHandler) GetNamespaceSuspendStatus(c *gin.Context) {
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for namespace suspend status")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	namespace := c.Param("name")
+	ns, err := client.CoreV1().Namespaces().Get(c.Request.Context(), namespace, metav1.GetOptions{})
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := NamespaceSuspendStatus{
+		Namespace:   namespace,
+		SuspendedAt: ns.Annotations[suspendedAtAnnotation],
+	}
+	status.Suspended = status.SuspendedAt != ""
+	if op, ok := h.suspendOperations.Load(suspendKey(c.Query("config"), c.Query("cluster"), namespace)); ok {
+		status.Operation = op
+	}
+	c.JSON(http.StatusOK, status)
+}
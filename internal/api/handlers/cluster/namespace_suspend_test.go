@@ -0,0 +1,123 @@
+package cluster
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+// runSuspend plans and runs a suspend or resume to completion, returning the finished operation
+func runSuspend(t *testing.T, h *NamespacesHandler, client kubernetes.Interface, operation string) *NamespaceSuspendOperation {
+	t.Helper()
+	plan := planSuspend
+	if operation == "resume" {
+		plan = planResume
+	}
+	steps, err := plan(context.Background(), client, "shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := suspendKey("c1", "", "shop")
+	op := &NamespaceSuspendOperation{Namespace: "shop", Operation: operation, State: "running", Total: len(steps), Workloads: make([]NamespaceSuspendWorkload, len(steps))}
+	if _, started := h.suspendOperations.Start(key, op); !started {
+		t.Fatalf("expected the %s to start", operation)
+	}
+	if _, started := h.suspendOperations.Start(key, op); started {
+		t.Fatalf("expected a second %s to be rejected while the first runs", operation)
+	}
+	h.runSuspendOperation(client, key, "shop", operation, steps)
+	done, _ := h.suspendOperations.Load(key)
+	return done
+}
+
+func TestNamespaceSuspendAndResume(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"}, Spec: appsv1.DeploymentSpec{Replicas: ptr.To(int32(3))}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "idle", Namespace: "shop"}, Spec: appsv1.DeploymentSpec{Replicas: ptr.To(int32(0))}},
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "shop"}, Spec: appsv1.StatefulSetSpec{Replicas: ptr.To(int32(2))}},
+		&batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "shop"}},
+		&batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "manual", Namespace: "shop"}, Spec: batchv1.CronJobSpec{Suspend: ptr.To(true)}},
+	)
+	h := NewNamespacesHandler(nil, nil, logger.New("error"))
+
+	suspended := runSuspend(t, h, client, "suspend")
+	if suspended.State != "completed" || suspended.Processed != 5 || suspended.Failed != 0 || suspended.FinishedAt == nil {
+		t.Fatalf("unexpected suspend %+v", suspended)
+	}
+	web, _ := client.AppsV1().Deployments("shop").Get(ctx, "web", metav1.GetOptions{})
+	db, _ := client.AppsV1().StatefulSets("shop").Get(ctx, "db", metav1.GetOptions{})
+	nightly, _ := client.BatchV1().CronJobs("shop").Get(ctx, "nightly", metav1.GetOptions{})
+	ns, _ := client.CoreV1().Namespaces().Get(ctx, "shop", metav1.GetOptions{})
+	if *web.Spec.Replicas != 0 || web.Annotations[suspendedReplicasAnnotation] != "3" || *db.Spec.Replicas != 0 {
+		t.Errorf("expected workloads scaled to zero with their replicas recorded, got web %v and db %v", web, db)
+	}
+	if !ptr.Deref(nightly.Spec.Suspend, false) || ns.Annotations[suspendedAtAnnotation] == "" {
+		t.Error("expected the cronjob to be suspended and the namespace marked")
+	}
+
+	resumed := runSuspend(t, h, client, "resume")
+	if resumed.State != "completed" || resumed.Total != 3 {
+		t.Fatalf("expected only workloads changed by the suspend to be resumed, got %+v", resumed)
+	}
+	web, _ = client.AppsV1().Deployments("shop").Get(ctx, "web", metav1.GetOptions{})
+	db, _ = client.AppsV1().StatefulSets("shop").Get(ctx, "db", metav1.GetOptions{})
+	idle, _ := client.AppsV1().Deployments("shop").Get(ctx, "idle", metav1.GetOptions{})
+	nightly, _ = client.BatchV1().CronJobs("shop").Get(ctx, "nightly", metav1.GetOptions{})
+	manual, _ := client.BatchV1().CronJobs("shop").Get(ctx, "manual", metav1.GetOptions{})
+	ns, _ = client.CoreV1().Namespaces().Get(ctx, "shop", metav1.GetOptions{})
+	if *web.Spec.Replicas != 3 || *db.Spec.Replicas != 2 || *idle.Spec.Replicas != 0 {
+		t.Errorf("expected the recorded replicas to be restored, got web %d, db %d, idle %d", *web.Spec.Replicas, *db.Spec.Replicas, *idle.Spec.Replicas)
+	}
+	if _, ok := web.Annotations[suspendedReplicasAnnotation]; ok {
+		t.Error("expected the recorded replicas to be cleared")
+	}
+	if ptr.Deref(nightly.Spec.Suspend, false) || !ptr.Deref(manual.Spec.Suspend, false) {
+		t.Error("expected only the cronjob suspended by kube-dash to be resumed")
+	}
+	if _, ok := ns.Annotations[suspendedAtAnnotation]; ok {
+		t.Error("expected the namespace marker to be cleared")
+	}
+}
+
+func TestNamespaceSuspendSkipsAutoscaledWorkloads(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"}, Spec: appsv1.DeploymentSpec{Replicas: ptr.To(int32(3))}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"}, Spec: appsv1.DeploymentSpec{Replicas: ptr.To(int32(4))}},
+		&autoscalingv2.HorizontalPodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-hpa", Namespace: "shop"},
+			Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+				ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "api"},
+				MaxReplicas:    10,
+			},
+		},
+	)
+	steps, err := planSuspend(context.Background(), client, "shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, step := range steps {
+		switch step.name {
+		case "api":
+			if step.patch != nil || !strings.Contains(step.skip, "api-hpa") {
+				t.Errorf("expected the autoscaled deployment to be skipped naming its HPA, got %+v", step)
+			}
+		case "web":
+			if step.patch == nil {
+				t.Errorf("expected the deployment without an HPA to be scaled down, got %+v", step)
+			}
+		}
+	}
+}
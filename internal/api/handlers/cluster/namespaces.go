@@ -26,6 +26,10 @@ type NamespacesHandler struct {
 	yamlHandler   *utils.YAMLHandler
 	eventsHandler *utils.EventsHandler
 	tracingHelper *tracing.TracingHelper
+
+	// Suspend/resume and bulk restart progress keyed by config/cluster/namespace
+	suspendOperations *utils.ProgressTracker[NamespaceSuspendOperation]
+	restartOperations *utils.ProgressTracker[BulkRestartOperation]
 }
 
 // NewNamespacesHandler creates a new NamespacesHandler instance
@@ -38,11 +42,14 @@ func NewNamespacesHandler(store *storage.KubeConfigStore, clientFactory *k8s.Cli
 		yamlHandler:   utils.NewYAMLHandler(log),
 		eventsHandler: utils.NewEventsHandler(log),
 		tracingHelper: tracing.GetTracingHelper(),
+
+		suspendOperations: newSuspendTracker(),
+		restartOperations: newRestartTracker(),
 	}
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the current request
-func (h *NamespacesHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *NamespacesHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -169,7 +176,7 @@ func (h *NamespacesHandler) GetNamespacesSSE(c *gin.Context) {
 	}
 
 	// For non-SSE requests, return JSON
-	c.JSON(http.StatusOK, initialData)
+	h.sseHandler.SendJSON(c, initialData)
 }
 
 // GetNamespace returns a specific namespace
@@ -221,7 +228,7 @@ func (h *NamespacesHandler) GetNamespace(c *gin.Context) {
 	// Check if this is an SSE request (EventSource expects SSE format)
 	acceptHeader := c.GetHeader("Accept")
 	if acceptHeader == "text/event-stream" {
-		h.sseHandler.SendSSEResponse(c, namespace)
+		h.sseHandler.SendSSEDetailResponse(c, namespace, namespace, client.CoreV1().Namespaces().Watch)
 		return
 	}
 
@@ -391,5 +398,5 @@ func (h *NamespacesHandler) GetNamespacePods(c *gin.Context) {
 	}
 
 	// For non-SSE requests, return JSON
-	c.JSON(http.StatusOK, initialData)
+	h.sseHandler.SendJSON(c, initialData)
 }
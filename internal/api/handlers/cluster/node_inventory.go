@@ -0,0 +1,305 @@
+package cluster
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// eolWarningWindow is how long before end of life a version is flagged as approaching it
+const eolWarningWindow = 90 * 24 * time.Hour
+
+// maxKubeletSkew is the number of minor versions a kubelet may trail the API server by
+const maxKubeletSkew = 3
+
+// eolRelease is the end-of-life date of one release line
+type eolRelease struct {
+	match string // minor version, or OS image prefix
+	eol   string // YYYY-MM-DD
+}
+
+// kubernetesEOL lists upstream end-of-life dates by minor version. Minor versions older than
+// the first entry are end of life; newer ones are still supported.
+var kubernetesEOL = []eolRelease{
+	{"1.25", "2023-10-28"}, {"1.26", "2024-02-28"}, {"1.27", "2024-06-28"}, {"1.28", "2024-10-28"},
+	{"1.29", "2025-02-28"}, {"1.30", "2025-06-28"}, {"1.31", "2025-10-28"}, {"1.32", "2026-02-28"},
+	{"1.33", "2026-06-28"}, {"1.34", "2026-10-27"}, {"1.35", "2027-02-28"},
+}
+
+// containerdEOL lists containerd end-of-life dates by minor version
+var containerdEOL = []eolRelease{
+	{"1.4", "2022-03-03"}, {"1.5", "2023-02-28"}, {"1.6", "2025-07-23"}, {"1.7", "2026-03-10"}, {"2.0", "2025-11-07"},
+}
+
+// osImageEOL lists end of standard support of common node OS images by image name prefix
+var osImageEOL = []eolRelease{
+	{"Ubuntu 16.04", "2021-04-30"}, {"Ubuntu 18.04", "2023-05-31"}, {"Ubuntu 20.04", "2025-05-31"},
+	{"Ubuntu 22.04", "2027-06-01"}, {"Ubuntu 24.04", "2029-06-01"},
+	{"CentOS Linux 7", "2024-06-30"}, {"CentOS Linux 8", "2021-12-31"}, {"CentOS Stream 8", "2024-05-31"},
+	{"Debian GNU/Linux 9", "2020-07-06"}, {"Debian GNU/Linux 10", "2022-09-10"}, {"Debian GNU/Linux 11", "2024-08-14"},
+	{"Debian GNU/Linux 12", "2026-06-10"},
+	{"Amazon Linux 2023", "2029-06-30"}, {"Amazon Linux 2", "2026-06-30"},
+	{"Red Hat Enterprise Linux 7", "2024-06-30"},
+}
+
+var minorVersionPattern = regexp.MustCompile(`(\d+)\.(\d+)`)
+
+// VersionFlag is a patch-planning hint for one component version
+type VersionFlag struct {
+	Component string `json:"component"` // kubelet, containerRuntime or osImage
+	Version   string `json:"version"`
+	Severity  string `json:"severity"` // eol or warning
+	Message   string `json:"message"`
+}
+
+// NodeConfiguration is a group of nodes sharing the same OS, kernel, runtime and kubelet
+type NodeConfiguration struct {
+	KubeletVersion   string        `json:"kubeletVersion"`
+	ContainerRuntime string        `json:"containerRuntime"`
+	KernelVersion    string        `json:"kernelVersion"`
+	OSImage          string        `json:"osImage"`
+	OperatingSystem  string        `json:"operatingSystem"`
+	Architecture     string        `json:"architecture"`
+	Count            int           `json:"count"`
+	Nodes            []string      `json:"nodes"`
+	Flags            []VersionFlag `json:"flags"`
+}
+
+// NodeInventorySummary counts nodes by each version dimension
+type NodeInventorySummary struct {
+	Nodes             int            `json:"nodes"`
+	Configurations    int            `json:"configurations"`
+	FlaggedNodes      int            `json:"flaggedNodes"`
+	ServerVersion     string         `json:"serverVersion,omitempty"`
+	KubeletVersions   map[string]int `json:"kubeletVersions"`
+	ContainerRuntimes map[string]int `json:"containerRuntimes"`
+	KernelVersions    map[string]int `json:"kernelVersions"`
+	OSImages          map[string]int `json:"osImages"`
+}
+
+// NodeInventoryReport groups nodes by identical OS and component versions with end-of-life hints
+type NodeInventoryReport struct {
+	Summary        NodeInventorySummary `json:"summary"`
+	Configurations []NodeConfiguration  `json:"configurations"`
+}
+
+// minorVersion extracts the major and minor version from a version string such as v1.29.3-eks-1 or 1.7.11
+func minorVersion(version string) (major, minor int, ok bool) {
+	m := minorVersionPattern.FindStringSubmatch(version)
+	if m == nil {
+		return 0, 0, false
+	}
+	major, _ = strconv.Atoi(m[1])
+	minor, _ = strconv.Atoi(m[2])
+	return major, minor, true
+}
+
+// eolFlag flags a version whose release line is past or near its end-of-life date
+func eolFlag(component, version, label, eol string, now time.Time) *VersionFlag {
+	date, err := time.Parse("2006-01-02", eol)
+	if err != nil {
+		return nil
+	}
+	switch {
+	case !now.Before(date):
+		return &VersionFlag{Component: component, Version: version, Severity: "eol",
+			Message: fmt.Sprintf("%s reached end of life on %s and no longer receives security fixes", label, eol)}
+	case date.Sub(now) <= eolWarningWindow:
+		return &VersionFlag{Component: component, Version: version, Severity: "warning",
+			Message: fmt.Sprintf("%s reaches end of life on %s", label, eol)}
+	}
+	return nil
+}
+
+// minorEOLFlag looks a version's minor release up in an EOL table keyed by minor version
+func minorEOLFlag(component, version, name string, table []eolRelease, now time.Time) *VersionFlag {
+	major, minor, ok := minorVersion(version)
+	if !ok {
+		return nil
+	}
+	line := fmt.Sprintf("%d.%d", major, minor)
+	for _, release := range table {
+		if release.match == line {
+			return eolFlag(component, version, name+" "+line, release.eol, now)
+		}
+	}
+	// Older than every tracked release line
+	if firstMajor, firstMinor, ok := minorVersion(table[0].match); ok && (major < firstMajor || major == firstMajor && minor < firstMinor) {
+		return &VersionFlag{Component: component, Version: version, Severity: "eol",
+			Message: fmt.Sprintf("%s %s is long past end of life and no longer receives security fixes", name, line)}
+	}
+	return nil
+}
+
+// nodeVersionFlags returns the EOL and version skew hints for one node configuration
+func nodeVersionFlags(info v1.NodeSystemInfo, serverVersion string, now time.Time) []VersionFlag {
+	flags := []VersionFlag{}
+	add := func(f *VersionFlag) {
+		if f != nil {
+			flags = append(flags, *f)
+		}
+	}
+
+	add(minorEOLFlag("kubelet", info.KubeletVersion, "Kubernetes", kubernetesEOL, now))
+	if _, serverMinor, ok := minorVersion(serverVersion); ok {
+		if _, kubeletMinor, ok := minorVersion(info.KubeletVersion); ok && serverMinor-kubeletMinor > maxKubeletSkew {
+			flags = append(flags, VersionFlag{Component: "kubelet", Version: info.KubeletVersion, Severity: "eol",
+				Message: fmt.Sprintf("kubelet trails the API server (%s) by more than %d minor versions, which is unsupported", serverVersion, maxKubeletSkew)})
+		}
+	}
+
+	runtime, version, _ := strings.Cut(info.ContainerRuntimeVersion, "://")
+	switch runtime {
+	case "containerd":
+		add(minorEOLFlag("containerRuntime", info.ContainerRuntimeVersion, "containerd", containerdEOL, now))
+	case "cri-o":
+		// CRI-O follows the Kubernetes release cycle
+		add(minorEOLFlag("containerRuntime", info.ContainerRuntimeVersion, "CRI-O", kubernetesEOL, now))
+	case "docker":
+		flags = append(flags, VersionFlag{Component: "containerRuntime", Version: info.ContainerRuntimeVersion, Severity: "eol",
+			Message: fmt.Sprintf("Docker %s via dockershim is unsupported since Kubernetes 1.24", version)})
+	}
+
+	for _, release := range osImageEOL {
+		if strings.HasPrefix(info.OSImage, release.match) {
+			add(eolFlag("osImage", info.OSImage, release.match, release.eol, now))
+			break
+		}
+	}
+	return flags
+}
+
+// buildNodeInventory groups nodes by identical system info and flags EOL versions. Kernel versions
+// are reported but not flagged, since distributions backport fixes into older kernels.
+func buildNodeInventory(nodes []v1.Node, serverVersion string, now time.Time) NodeInventoryReport {
+	report := NodeInventoryReport{
+		Summary: NodeInventorySummary{
+			Nodes:             len(nodes),
+			ServerVersion:     serverVersion,
+			KubeletVersions:   map[string]int{},
+			ContainerRuntimes: map[string]int{},
+			KernelVersions:    map[string]int{},
+			OSImages:          map[string]int{},
+		},
+		Configurations: []NodeConfiguration{},
+	}
+
+	groups := map[v1.NodeSystemInfo]*NodeConfiguration{}
+	for _, node := range nodes {
+		info := node.Status.NodeInfo
+		report.Summary.KubeletVersions[info.KubeletVersion]++
+		report.Summary.ContainerRuntimes[info.ContainerRuntimeVersion]++
+		report.Summary.KernelVersions[info.KernelVersion]++
+		report.Summary.OSImages[info.OSImage]++
+
+		// Group on the versioned fields only; machine and boot IDs are unique per node
+		key := v1.NodeSystemInfo{
+			KubeletVersion:          info.KubeletVersion,
+			ContainerRuntimeVersion: info.ContainerRuntimeVersion,
+			KernelVersion:           info.KernelVersion,
+			OSImage:                 info.OSImage,
+			OperatingSystem:         info.OperatingSystem,
+			Architecture:            info.Architecture,
+		}
+		group, ok := groups[key]
+		if !ok {
+			group = &NodeConfiguration{
+				KubeletVersion:   info.KubeletVersion,
+				ContainerRuntime: info.ContainerRuntimeVersion,
+				KernelVersion:    info.KernelVersion,
+				OSImage:          info.OSImage,
+				OperatingSystem:  info.OperatingSystem,
+				Architecture:     info.Architecture,
+				Flags:            nodeVersionFlags(key, serverVersion, now),
+			}
+			groups[key] = group
+		}
+		group.Count++
+		group.Nodes = append(group.Nodes, node.Name)
+	}
+
+	for _, group := range groups {
+		sort.Strings(group.Nodes)
+		if len(group.Flags) > 0 {
+			report.Summary.FlaggedNodes += group.Count
+		}
+		report.Configurations = append(report.Configurations, *group)
+	}
+	report.Summary.Configurations = len(report.Configurations)
+	// Flagged configurations first, then the largest groups
+	sort.Slice(report.Configurations, func(i, j int) bool {
+		a, b := report.Configurations[i], report.Configurations[j]
+		if (len(a.Flags) > 0) != (len(b.Flags) > 0) {
+			return len(a.Flags) > 0
+		}
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Nodes[0] < b.Nodes[0]
+	})
+	return report
+}
+
+// GetNodeInventory reports node OS, kernel, runtime and kubelet versions grouped by configuration
+// @Summary Get node version inventory
+// @Description Groups nodes by identical kubelet, container runtime, kernel and OS image versions and flags release lines that are past or within 90 days of end of life, along with kubelets trailing the API server by more than the supported skew. Kernel versions are reported but not flagged because distributions backport fixes. Intended for patch planning.
+// @Tags Cluster
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name for multi-cluster setups"
+// @Param flagged query bool false "Only return configurations with flags"
+// @Success 200 {object} NodeInventoryReport "Node inventory report"
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/nodes/inventory [get]
+func (h *NodesHandler) GetNodeInventory(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for node inventory")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Successfully obtained Kubernetes client")
+
+	_, listSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "nodes", "")
+	defer listSpan.End()
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list nodes for inventory")
+		h.tracingHelper.RecordError(listSpan, err, "Failed to list nodes")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	serverVersion := ""
+	if info, err := client.Discovery().ServerVersion(); err == nil {
+		serverVersion = info.GitVersion
+	}
+
+	report := buildNodeInventory(nodes.Items, serverVersion, time.Now())
+	h.tracingHelper.RecordSuccess(listSpan, fmt.Sprintf("Grouped %d nodes into %d configurations", report.Summary.Nodes, report.Summary.Configurations))
+
+	if c.Query("flagged") == "true" {
+		flagged := []NodeConfiguration{}
+		for _, conf := range report.Configurations {
+			if len(conf.Flags) > 0 {
+				flagged = append(flagged, conf)
+			}
+		}
+		report.Configurations = flagged
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,57 @@
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBuildNodeInventory(t *testing.T) {
+	node := func(name string, info v1.NodeSystemInfo) v1.Node {
+		info.MachineID = name
+		return v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}, Status: v1.NodeStatus{NodeInfo: info}}
+	}
+	current := v1.NodeSystemInfo{KubeletVersion: "v1.35.1", ContainerRuntimeVersion: "containerd://2.1.4", KernelVersion: "6.8.0", OSImage: "Ubuntu 24.04.1 LTS", OperatingSystem: "linux", Architecture: "amd64"}
+	old := v1.NodeSystemInfo{KubeletVersion: "v1.30.4-eks-a737599", ContainerRuntimeVersion: "containerd://1.7.11", KernelVersion: "5.10.0", OSImage: "Amazon Linux 2", OperatingSystem: "linux", Architecture: "arm64"}
+	nodes := []v1.Node{node("b", current), node("a", current), node("c", current), node("old-1", old)}
+
+	now := time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC)
+	report := buildNodeInventory(nodes, "v1.35.0", now)
+	if report.Summary.Nodes != 4 || report.Summary.Configurations != 2 || report.Summary.FlaggedNodes != 1 {
+		t.Fatalf("unexpected summary: %+v", report.Summary)
+	}
+	if report.Summary.KubeletVersions["v1.35.1"] != 3 || report.Summary.OSImages["Amazon Linux 2"] != 1 {
+		t.Errorf("unexpected version counts: %+v", report.Summary)
+	}
+
+	flagged := report.Configurations[0]
+	if flagged.KubeletVersion != old.KubeletVersion || flagged.Count != 1 {
+		t.Fatalf("expected the flagged configuration first, got %+v", report.Configurations)
+	}
+	severities := map[string]string{}
+	for _, f := range flagged.Flags {
+		severities[f.Component] = f.Severity
+	}
+	// 1.30 is past EOL and trails 1.35 by five minors, containerd 1.7 is past EOL, Amazon Linux 2 ends within 90 days
+	if len(flagged.Flags) != 4 || severities["kubelet"] != "eol" || severities["containerRuntime"] != "eol" || severities["osImage"] != "warning" {
+		t.Errorf("unexpected flags: %+v", flagged.Flags)
+	}
+
+	healthy := report.Configurations[1]
+	if healthy.Count != 3 || len(healthy.Flags) != 0 || healthy.Nodes[0] != "a" {
+		t.Errorf("unexpected healthy configuration: %+v", healthy)
+	}
+}
+
+func TestNodeVersionFlagsRuntimes(t *testing.T) {
+	now := time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC)
+	flags := nodeVersionFlags(v1.NodeSystemInfo{KubeletVersion: "v1.22.0", ContainerRuntimeVersion: "docker://20.10.7"}, "", now)
+	if len(flags) != 2 || flags[0].Component != "kubelet" || flags[1].Component != "containerRuntime" {
+		t.Errorf("expected old kubelet and dockershim flags, got %+v", flags)
+	}
+	if flags := nodeVersionFlags(v1.NodeSystemInfo{KubeletVersion: "v1.35.0", ContainerRuntimeVersion: "cri-o://1.35.0"}, "", now); len(flags) != 0 {
+		t.Errorf("expected no flags for supported versions, got %+v", flags)
+	}
+}
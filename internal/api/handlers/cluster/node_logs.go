@@ -0,0 +1,361 @@
+package cluster
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/config"
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// unitName matches systemd unit names and the Windows service names the kubelet accepts
+var unitName = regexp.MustCompile(`^[a-zA-Z0-9._@:-]+$`)
+
+// NodeLogsHandler reads kubelet and system logs of nodes through the nodes proxy, so node-level
+// debugging does not need SSH
+type NodeLogsHandler struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	logger        *logger.Logger
+	config        *config.NodeLogsConfig
+}
+
+// NewNodeLogsHandler creates a new node logs handler
+func NewNodeLogsHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger, cfg *config.NodeLogsConfig) *NodeLogsHandler {
+	return &NodeLogsHandler{
+		store:         store,
+		clientFactory: clientFactory,
+		logger:        log,
+		config:        cfg,
+	}
+}
+
+// NodeLogAccess is the result of the node log preflight
+type NodeLogAccess struct {
+	Node    string `json:"node"`
+	Enabled bool   `json:"enabled"` // node log access is enabled on this server
+	Allowed bool   `json:"allowed"` // the caller may get nodes/proxy on the node
+	Reason  string `json:"reason,omitempty"`
+}
+
+// NodeLogs is a slice of a node's logs
+type NodeLogs struct {
+	Node      string   `json:"node"`
+	Units     []string `json:"units,omitempty"`
+	File      string   `json:"file,omitempty"`
+	Lines     []string `json:"lines"`
+	Truncated bool     `json:"truncated"` // earlier lines were dropped to stay within the line limit
+}
+
+// nodeLogQuery holds the filters passed to the kubelet's node log query
+type nodeLogQuery struct {
+	units     []string
+	file      string
+	sinceTime string
+	untilTime string
+	tailLines int
+	pattern   string
+	boot      string
+}
+
+// parseNodeLogQuery reads and validates the filters of a node log request
+func parseNodeLogQuery(values url.Values) (nodeLogQuery, error) {
+	q := nodeLogQuery{
+		file:      strings.TrimPrefix(values.Get("file"), "/"),
+		sinceTime: values.Get("sinceTime"),
+		untilTime: values.Get("untilTime"),
+		pattern:   values.Get("pattern"),
+		boot:      values.Get("boot"),
+	}
+	for _, value := range values["unit"] {
+		for _, unit := range strings.Split(value, ",") {
+			if unit = strings.TrimSpace(unit); unit != "" {
+				q.units = append(q.units, unit)
+			}
+		}
+	}
+	if len(q.units) == 0 && q.file == "" {
+		q.units = []string{"kubelet"}
+	}
+	if len(q.units) > 0 && q.file != "" {
+		return q, fmt.Errorf("unit and file cannot be combined")
+	}
+	for _, unit := range q.units {
+		if !unitName.MatchString(unit) {
+			return q, fmt.Errorf("invalid unit name %q", unit)
+		}
+	}
+	if q.file != "" {
+		for _, segment := range strings.Split(q.file, "/") {
+			if segment == ".." {
+				return q, fmt.Errorf("file must be a path below /var/log")
+			}
+		}
+	}
+
+	var since, until time.Time
+	var err error
+	if q.sinceTime != "" {
+		if since, err = time.Parse(time.RFC3339, q.sinceTime); err != nil {
+			return q, fmt.Errorf("sinceTime must be an RFC3339 timestamp")
+		}
+	}
+	if q.untilTime != "" {
+		if until, err = time.Parse(time.RFC3339, q.untilTime); err != nil {
+			return q, fmt.Errorf("untilTime must be an RFC3339 timestamp")
+		}
+	}
+	if !since.IsZero() && !until.IsZero() && until.Before(since) {
+		return q, fmt.Errorf("untilTime must not be before sinceTime")
+	}
+	if value := values.Get("tailLines"); value != "" {
+		if q.tailLines, err = strconv.Atoi(value); err != nil || q.tailLines < 1 {
+			return q, fmt.Errorf("tailLines must be a positive number")
+		}
+	}
+	if q.boot != "" {
+		if boot, err := strconv.Atoi(q.boot); err != nil || boot > 0 {
+			return q, fmt.Errorf("boot must be 0 for the current boot or negative for earlier ones")
+		}
+	}
+	if q.pattern != "" {
+		if _, err := regexp.Compile(q.pattern); err != nil {
+			return q, fmt.Errorf("invalid pattern: %v", err)
+		}
+	}
+	if q.file != "" && (q.sinceTime != "" || q.untilTime != "" || q.pattern != "" || q.boot != "") {
+		return q, fmt.Errorf("time, pattern and boot filters apply to units only")
+	}
+	return q, nil
+}
+
+// getClient gets the Kubernetes client for the current request
+func (h *NodeLogsHandler) getClient(c *gin.Context) (kubernetes.Interface, error) {
+	configID := c.Query("config")
+	cluster := c.Query("cluster")
+
+	if configID == "" {
+		return nil, fmt.Errorf("config parameter is required")
+	}
+
+	config, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, fmt.Errorf("config not found: %w", err)
+	}
+
+	client, err := h.clientFactory.GetClientForConfig(config, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Kubernetes client: %w", err)
+	}
+
+	return client, nil
+}
+
+// checkAccess reports whether node log access is enabled and the caller may proxy to the node
+func (h *NodeLogsHandler) checkAccess(ctx context.Context, client kubernetes.Interface, node string) (NodeLogAccess, error) {
+	access := NodeLogAccess{Node: node, Enabled: h.config.Enabled}
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Resource:    "nodes",
+				Subresource: "proxy",
+				Verb:        "get",
+				Name:        node,
+			},
+		},
+	}
+	result, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return access, err
+	}
+	access.Allowed = result.Status.Allowed
+	switch {
+	case !access.Enabled:
+		access.Reason = "node log access is disabled on this server; set ENABLE_NODE_LOGS=true to enable it"
+	case !access.Allowed:
+		access.Reason = "get on nodes/proxy is required to read node logs"
+		if result.Status.Reason != "" {
+			access.Reason += ": " + result.Status.Reason
+		}
+	}
+	return access, nil
+}
+
+// readLastLines reads lines from r, keeping at most max of the last ones
+func readLastLines(r io.Reader, max int) ([]string, bool, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	// Once full, lines is used as a ring whose oldest line is at next
+	var lines []string
+	next := 0
+	truncated := false
+	for scanner.Scan() {
+		if max > 0 && len(lines) == max {
+			lines[next] = scanner.Text()
+			next = (next + 1) % max
+			truncated = true
+			continue
+		}
+		lines = append(lines, scanner.Text())
+	}
+	if next > 0 {
+		lines = append(lines[next:], lines[:next]...)
+	}
+	if lines == nil {
+		lines = []string{}
+	}
+	return lines, truncated, scanner.Err()
+}
+
+// isLogListing reports whether the kubelet answered with its /var/log directory listing, which it
+// does for log queries when the NodeLogQuery feature is off
+func isLogListing(head []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(head), []byte("<pre>"))
+}
+
+// GetNodeLogAccess checks whether the caller can read a node's logs
+// @Summary Check node log access
+// @Description Preflight for node logs: reports whether node log access is enabled on this server and whether the caller may get nodes/proxy on the node.
+// @Tags Cluster
+// @Produce json
+// @Param name path string true "Node name"
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Success 200 {object} NodeLogAccess "Node log access"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/nodes/{name}/logs/access [get]
+func (h *NodeLogsHandler) GetNodeLogAccess(c *gin.Context) {
+	client, err := h.getClient(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	access, err := h.checkAccess(c.Request.Context(), client, c.Param("name"))
+	if err != nil {
+		h.logger.WithError(err).WithField("node", c.Param("name")).Error("Failed to check node log access")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to check permissions: %v", err)})
+		return
+	}
+	c.JSON(http.StatusOK, access)
+}
+
+// GetNodeLogs returns kubelet, journal or /var/log file logs of a node
+// @Summary Get node logs
+// @Description Reads logs of a node through the nodes proxy. Units are queried through the kubelet's node log query (journald on Linux, which needs the NodeLogQuery feature gate and enableSystemLogQuery on the kubelet); file reads a file below /var/log. Defaults to the kubelet unit. Requires node log access to be enabled on the server and get on nodes/proxy for the node.
+// @Tags Cluster
+// @Produce json
+// @Param name path string true "Node name"
+// @Param unit query []string false "Units to read, e.g. kubelet or containerd (defaults to kubelet)"
+// @Param file query string false "File below /var/log to read instead of units"
+// @Param sinceTime query string false "RFC3339 time to read logs from"
+// @Param untilTime query string false "RFC3339 time to read logs until"
+// @Param tailLines query int false "Number of lines to read from the end"
+// @Param pattern query string false "Regular expression lines must match"
+// @Param boot query int false "Boot to read, 0 for the current boot and negative for earlier ones"
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Success 200 {object} NodeLogs "Node logs"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 403 {object} map[string]string "Node log access disabled or not permitted"
+// @Failure 501 {object} map[string]string "Node log query not enabled on the kubelet"
+// @Failure 502 {object} map[string]string "Failed to read logs from the node"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/nodes/{name}/logs [get]
+func (h *NodeLogsHandler) GetNodeLogs(c *gin.Context) {
+	if !h.config.Enabled {
+		c.JSON(http.StatusForbidden, gin.H{"error": "node log access is disabled on this server"})
+		return
+	}
+	node := c.Param("name")
+	if node == "." || node == ".." {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid node name"})
+		return
+	}
+	query, err := parseNodeLogQuery(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	client, err := h.getClient(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	access, err := h.checkAccess(ctx, client, node)
+	if err != nil {
+		h.logger.WithError(err).WithField("node", node).Error("Failed to check node log access")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to check permissions: %v", err)})
+		return
+	}
+	if !access.Allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": access.Reason})
+		return
+	}
+
+	request := client.CoreV1().RESTClient().Get().AbsPath("/api/v1/nodes/" + node + "/proxy/logs/" + query.file)
+	for _, unit := range query.units {
+		request = request.Param("query", unit)
+	}
+	for param, value := range map[string]string{"sinceTime": query.sinceTime, "untilTime": query.untilTime, "pattern": query.pattern, "boot": query.boot} {
+		if value != "" {
+			request = request.Param(param, value)
+		}
+	}
+	if query.tailLines > 0 {
+		request = request.Param("tailLines", strconv.Itoa(query.tailLines))
+	}
+
+	stream, err := request.Stream(ctx)
+	if err != nil {
+		h.logger.WithError(err).WithField("node", node).Error("Failed to read node logs")
+		c.JSON(http.StatusBadGateway, gin.H{"error": fmt.Sprintf("failed to read logs from node %s: %v", node, err)})
+		return
+	}
+	defer stream.Close()
+
+	reader := bufio.NewReader(stream)
+	if len(query.units) > 0 {
+		if head, _ := reader.Peek(16); isLogListing(head) {
+			c.JSON(http.StatusNotImplemented, gin.H{"error": "the kubelet on this node does not support log queries; enable the NodeLogQuery feature gate and enableSystemLogQuery, or read a file below /var/log instead"})
+			return
+		}
+	}
+	lines, truncated, err := readLastLines(reader, h.config.MaxLines)
+	if err != nil {
+		h.logger.WithError(err).WithField("node", node).Error("Failed to read node logs")
+		c.JSON(http.StatusBadGateway, gin.H{"error": fmt.Sprintf("failed to read logs from node %s: %v", node, err)})
+		return
+	}
+
+	h.logger.WithField("node", node).WithField("units", strings.Join(query.units, ",")).WithField("file", query.file).Info("Read node logs")
+	c.JSON(http.StatusOK, NodeLogs{
+		Node:      node,
+		Units:     query.units,
+		File:      query.file,
+		Lines:     lines,
+		Truncated: truncated,
+	})
+}
@@ -0,0 +1,61 @@
+package cluster
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseNodeLogQuery(t *testing.T) {
+	q, err := parseNodeLogQuery(url.Values{})
+	if err != nil || !reflect.DeepEqual(q.units, []string{"kubelet"}) {
+		t.Errorf("default query = %+v, %v, want the kubelet unit", q, err)
+	}
+
+	q, err = parseNodeLogQuery(url.Values{
+		"unit":      {"kubelet,containerd", "systemd-journald.service"},
+		"sinceTime": {"2025-03-01T10:00:00Z"},
+		"tailLines": {"100"},
+		"boot":      {"-1"},
+	})
+	if err != nil || len(q.units) != 3 || q.tailLines != 100 || q.boot != "-1" {
+		t.Errorf("unit query = %+v, %v", q, err)
+	}
+
+	q, err = parseNodeLogQuery(url.Values{"file": {"/pods/app.log"}})
+	if err != nil || q.file != "pods/app.log" || len(q.units) != 0 {
+		t.Errorf("file query = %+v, %v", q, err)
+	}
+
+	invalid := []url.Values{
+		{"unit": {"kubelet; rm"}},
+		{"file": {"../etc/shadow"}},
+		{"unit": {"kubelet"}, "file": {"syslog"}},
+		{"file": {"syslog"}, "pattern": {"error"}},
+		{"sinceTime": {"yesterday"}},
+		{"sinceTime": {"2025-03-01T10:00:00Z"}, "untilTime": {"2025-03-01T09:00:00Z"}},
+		{"tailLines": {"0"}},
+		{"boot": {"1"}},
+		{"pattern": {"("}},
+	}
+	for _, values := range invalid {
+		if _, err := parseNodeLogQuery(values); err == nil {
+			t.Errorf("parseNodeLogQuery(%v) succeeded, want an error", values)
+		}
+	}
+}
+
+func TestReadLastLines(t *testing.T) {
+	lines, truncated, err := readLastLines(strings.NewReader("a\nb\nc\nd\n"), 2)
+	if err != nil || truncated != true || !reflect.DeepEqual(lines, []string{"c", "d"}) {
+		t.Errorf("readLastLines() = %v, %v, %v", lines, truncated, err)
+	}
+	lines, truncated, _ = readLastLines(strings.NewReader("a\nb"), 5)
+	if truncated || len(lines) != 2 {
+		t.Errorf("readLastLines() = %v, %v, want both lines", lines, truncated)
+	}
+	if !isLogListing([]byte("<pre>\n<a href=")) || isLogListing([]byte("Mar 01 kubelet")) {
+		t.Error("isLogListing() did not tell the directory listing from log lines")
+	}
+}
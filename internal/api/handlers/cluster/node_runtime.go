@@ -0,0 +1,62 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// kubeletConfigTimeout bounds the kubelet configz lookup so a slow node never blocks the detail view
+const kubeletConfigTimeout = 2 * time.Second
+
+// NodeRuntimeInfo describes a node's container runtime
+type NodeRuntimeInfo struct {
+	Runtime        string `json:"runtime"`                // containerd, cri-o or docker
+	Version        string `json:"version"`                // runtime version without the runtime prefix
+	RuntimeVersion string `json:"runtimeVersion"`         // as reported by the kubelet, e.g. containerd://1.7.13
+	CgroupDriver   string `json:"cgroupDriver,omitempty"` // systemd or cgroupfs, from the kubelet configuration
+	CgroupError    string `json:"cgroupError,omitempty"`  // why the cgroup driver could not be read, e.g. no nodes/proxy access
+}
+
+// nodeRuntime returns the runtime reported in a node's status
+func nodeRuntime(node *v1.Node) NodeRuntimeInfo {
+	reported := node.Status.NodeInfo.ContainerRuntimeVersion
+	runtime, version, found := strings.Cut(reported, "://")
+	if !found {
+		runtime, version = "", reported
+	}
+	return NodeRuntimeInfo{Runtime: runtime, Version: version, RuntimeVersion: reported}
+}
+
+// parseCgroupDriver reads the cgroup driver from a kubelet /configz response
+func parseCgroupDriver(raw []byte) (string, error) {
+	var configz struct {
+		KubeletConfig struct {
+			CgroupDriver string `json:"cgroupDriver"`
+		} `json:"kubeletconfig"`
+	}
+	if err := json.Unmarshal(raw, &configz); err != nil {
+		return "", err
+	}
+	return configz.KubeletConfig.CgroupDriver, nil
+}
+
+// nodeRuntimeInfo returns the node's runtime, adding the cgroup driver from the kubelet's
+// configuration through the API server's node proxy on a best-effort basis
+func nodeRuntimeInfo(ctx context.Context, client kubernetes.Interface, node *v1.Node) NodeRuntimeInfo {
+	info := nodeRuntime(node)
+	ctx, cancel := context.WithTimeout(ctx, kubeletConfigTimeout)
+	defer cancel()
+	raw, err := client.CoreV1().RESTClient().Get().Resource("nodes").Name(node.Name).SubResource("proxy").Suffix("configz").DoRaw(ctx)
+	if err == nil {
+		info.CgroupDriver, err = parseCgroupDriver(raw)
+	}
+	if err != nil {
+		info.CgroupError = err.Error()
+	}
+	return info
+}
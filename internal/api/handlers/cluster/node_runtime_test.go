@@ -0,0 +1,22 @@
+package cluster
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestNodeRuntime(t *testing.T) {
+	node := &v1.Node{Status: v1.NodeStatus{NodeInfo: v1.NodeSystemInfo{ContainerRuntimeVersion: "containerd://1.7.13"}}}
+	if info := nodeRuntime(node); info.Runtime != "containerd" || info.Version != "1.7.13" {
+		t.Errorf("unexpected runtime %+v", info)
+	}
+
+	driver, err := parseCgroupDriver([]byte(`{"kubeletconfig":{"cgroupDriver":"systemd","maxPods":110}}`))
+	if err != nil || driver != "systemd" {
+		t.Errorf("cgroup driver = %q, %v", driver, err)
+	}
+	if _, err := parseCgroupDriver([]byte("not json")); err == nil {
+		t.Error("expected an error for an invalid configz response")
+	}
+}
@@ -47,6 +47,8 @@ type NodeListResponse struct {
 	Name            string   `json:"name"`
 	ResourceVersion string   `json:"resourceVersion"`
 	Roles           []string `json:"roles"`
+	Spot            bool     `json:"spot"`
+	SpotProvider    string   `json:"spotProvider,omitempty"`
 	Spec            struct {
 		PodCIDR       string   `json:"podCIDR"`
 		PodCIDRs      []string `json:"podCIDRs"`
@@ -109,7 +111,7 @@ func NewNodesHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFa
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the current request
-func (h *NodesHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *NodesHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -249,6 +251,10 @@ func (h *NodesHandler) transformNodeToResponse(node *v1.Node) NodeListResponse {
 		UID:             string(node.UID),
 	}
 
+	// Spot/preemptible capacity can be reclaimed by the provider at short notice
+	response.SpotProvider = transformers.SpotProvider(node.Labels)
+	response.Spot = response.SpotProvider != ""
+
 	// Set spec fields
 	if node.Spec.PodCIDR != "" {
 		response.Spec.PodCIDR = node.Spec.PodCIDR
@@ -423,12 +429,12 @@ func (h *NodesHandler) GetNodesSSE(c *gin.Context) {
 	}
 
 	// For non-SSE requests, return JSON
-	c.JSON(http.StatusOK, initialData)
+	h.sseHandler.SendJSON(c, initialData)
 }
 
 // GetNode returns a specific node
 // @Summary Get Node by name
-// @Description Retrieves detailed information about a specific node in the cluster
+// @Description Retrieves detailed information about a specific node in the cluster. The runtime field holds the container runtime and version and, when the caller may proxy to the kubelet, its cgroup driver.
 // @Tags Cluster
 // @Accept json
 // @Produce json,text/event-stream
@@ -497,11 +503,12 @@ func (h *NodesHandler) GetNode(c *gin.Context) {
 	if status, ok := enhancedNode["status"].(map[string]interface{}); ok {
 		status["issues"] = transformedResponse.Status.Issues
 	}
+	enhancedNode["runtime"] = nodeRuntimeInfo(ctx, client, node)
 
 	// Check if this is an SSE request (EventSource expects SSE format)
 	acceptHeader := c.GetHeader("Accept")
 	if acceptHeader == "text/event-stream" {
-		h.sseHandler.SendSSEResponse(c, enhancedNode)
+		h.sseHandler.SendSSEDetailResponse(c, enhancedNode, node, client.CoreV1().Nodes().Watch)
 		return
 	}
 
@@ -641,10 +648,17 @@ func (h *NodesHandler) GetNodePods(c *gin.Context) {
 			return nil, err
 		}
 
+		spot := false
+		if node, err := client.CoreV1().Nodes().Get(c.Request.Context(), nodeName, metav1.GetOptions{}); err == nil {
+			spot = transformers.SpotProvider(node.Labels) != ""
+		}
+
 		// Transform pods to frontend-expected format
 		var response []types.PodListResponse
 		for _, pod := range podList.Items {
-			response = append(response, transformers.TransformPodToResponse(&pod, configID, cluster))
+			podResponse := transformers.TransformPodToResponse(&pod, configID, cluster)
+			podResponse.Spot = spot
+			response = append(response, podResponse)
 		}
 
 		return response, nil
@@ -667,7 +681,7 @@ func (h *NodesHandler) GetNodePods(c *gin.Context) {
 	}
 
 	// For non-SSE requests, return JSON
-	c.JSON(http.StatusOK, initialData)
+	h.sseHandler.SendJSON(c, initialData)
 }
 
 // NodeActionRequest represents the request format for node actions
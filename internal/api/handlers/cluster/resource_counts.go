@@ -0,0 +1,541 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/internal/tracing"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
+)
+
+const (
+	// resourceCountTTL keeps counts across UI polls so every poll does not page through large kinds
+	resourceCountTTL = 15 * time.Second
+	// resourceCountPageSize is the page size when the API server does not report remainingItemCount
+	resourceCountPageSize = 500
+	// resourceCountWorkers bounds concurrent count requests against the API server
+	resourceCountWorkers = 8
+)
+
+// countableKind is a resource kind the counts endpoint understands
+type countableKind struct {
+	gvr        schema.GroupVersionResource
+	namespaced bool
+}
+
+var countableKinds = map[string]countableKind{
+	"pods":                     {schema.GroupVersionResource{Version: "v1", Resource: "pods"}, true},
+	"services":                 {schema.GroupVersionResource{Version: "v1", Resource: "services"}, true},
+	"endpoints":                {schema.GroupVersionResource{Version: "v1", Resource: "endpoints"}, true},
+	"configmaps":               {schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, true},
+	"secrets":                  {schema.GroupVersionResource{Version: "v1", Resource: "secrets"}, true},
+	"serviceaccounts":          {schema.GroupVersionResource{Version: "v1", Resource: "serviceaccounts"}, true},
+	"persistentvolumeclaims":   {schema.GroupVersionResource{Version: "v1", Resource: "persistentvolumeclaims"}, true},
+	"limitranges":              {schema.GroupVersionResource{Version: "v1", Resource: "limitranges"}, true},
+	"resourcequotas":           {schema.GroupVersionResource{Version: "v1", Resource: "resourcequotas"}, true},
+	"events":                   {schema.GroupVersionResource{Version: "v1", Resource: "events"}, true},
+	"deployments":              {schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, true},
+	"statefulsets":             {schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"}, true},
+	"daemonsets":               {schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"}, true},
+	"replicasets":              {schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}, true},
+	"replicationcontrollers":   {schema.GroupVersionResource{Version: "v1", Resource: "replicationcontrollers"}, true},
+	"jobs":                     {schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}, true},
+	"cronjobs":                 {schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"}, true},
+	"ingresses":                {schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}, true},
+	"networkpolicies":          {schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "networkpolicies"}, true},
+	"horizontalpodautoscalers": {schema.GroupVersionResource{Group: "autoscaling", Version: "v2", Resource: "horizontalpodautoscalers"}, true},
+	"poddisruptionbudgets":     {schema.GroupVersionResource{Group: "policy", Version: "v1", Resource: "poddisruptionbudgets"}, true},
+	"roles":                    {schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "roles"}, true},
+	"rolebindings":             {schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "rolebindings"}, true},
+	"nodes":                    {schema.GroupVersionResource{Version: "v1", Resource: "nodes"}, false},
+	"namespaces":               {schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}, false},
+	"persistentvolumes":        {schema.GroupVersionResource{Version: "v1", Resource: "persistentvolumes"}, false},
+	"storageclasses":           {schema.GroupVersionResource{Group: "storage.k8s.io", Version: "v1", Resource: "storageclasses"}, false},
+	"clusterroles":             {schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}, false},
+	"clusterrolebindings":      {schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterrolebindings"}, false},
+}
+
+// podPhases are the pod phases counted with field selectors, so pod summaries never fetch pod specs
+var podPhases = []v1.PodPhase{v1.PodPending, v1.PodRunning, v1.PodSucceeded, v1.PodFailed, v1.PodUnknown}
+
+// ResourceCount is the number of objects of one kind
+type ResourceCount struct {
+	Kind  string `json:"kind"`
+	Count int    `json:"count"`
+	Error string `json:"error,omitempty"`
+}
+
+// ResourceCountsResponse holds the counts of the requested kinds
+type ResourceCountsResponse struct {
+	Namespaces []string        `json:"namespaces,omitempty"` // empty for all namespaces
+	Counts     []ResourceCount `json:"counts"`
+}
+
+// ResourceSummary is the number of objects of one kind broken down by status: phase for pods and
+// persistent volume claims, readiness for nodes, and healthy, degraded or scaledDown for workloads
+type ResourceSummary struct {
+	Kind       string         `json:"kind"`
+	Namespaces []string       `json:"namespaces,omitempty"`
+	Total      int            `json:"total"`
+	Breakdown  map[string]int `json:"breakdown"`
+}
+
+type cachedResult struct {
+	value     interface{}
+	fetchedAt time.Time
+}
+
+// ResourceCountsHandler serves totals and status breakdowns without streaming full lists
+type ResourceCountsHandler struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	logger        *logger.Logger
+	tracingHelper *tracing.TracingHelper
+
+	mu    sync.Mutex
+	cache map[string]cachedResult
+}
+
+// NewResourceCountsHandler creates a new resource counts handler
+func NewResourceCountsHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger) *ResourceCountsHandler {
+	return &ResourceCountsHandler{
+		store:         store,
+		clientFactory: clientFactory,
+		logger:        log,
+		tracingHelper: tracing.GetTracingHelper(),
+		cache:         make(map[string]cachedResult),
+	}
+}
+
+func (h *ResourceCountsHandler) cached(key string) (interface{}, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	entry, ok := h.cache[key]
+	if !ok || time.Since(entry.fetchedAt) > resourceCountTTL {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (h *ResourceCountsHandler) remember(key string, value interface{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for k, entry := range h.cache {
+		if time.Since(entry.fetchedAt) > resourceCountTTL {
+			delete(h.cache, k)
+		}
+	}
+	h.cache[key] = cachedResult{value: value, fetchedAt: time.Now()}
+}
+
+// cacheKey identifies a count or summary request by cluster, namespaces and label selector
+func cacheKey(c *gin.Context, kinds string, namespaces []string) string {
+	return strings.Join([]string{c.Query("config"), c.Query("cluster"), kinds, strings.Join(namespaces, ","), utils.ListOptions(c).LabelSelector}, "|")
+}
+
+func (h *ResourceCountsHandler) getClients(c *gin.Context) (kubernetes.Interface, metadata.Interface, error) {
+	configID := c.Query("config")
+	cluster := c.Query("cluster")
+
+	if configID == "" {
+		return nil, nil, fmt.Errorf("config parameter is required")
+	}
+
+	config, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("config not found: %w", err)
+	}
+
+	client, err := h.clientFactory.GetClientForConfig(config, cluster)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get Kubernetes client: %w", err)
+	}
+	metadataClient, err := h.clientFactory.GetMetadataClientForConfig(config, cluster)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get metadata client: %w", err)
+	}
+	return client, metadataClient, nil
+}
+
+// countObjects counts objects with metadata-only lists. A single-item page is enough when the API
+// server reports remainingItemCount, which it does not for lists filtered by a selector; otherwise
+// the list is paged through.
+func countObjects(ctx context.Context, client metadata.Interface, gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) (int, error) {
+	opts.Limit = 1
+	list, err := client.Resource(gvr).Namespace(namespace).List(ctx, opts)
+	if err != nil {
+		return 0, err
+	}
+	if list.Continue == "" {
+		return len(list.Items), nil
+	}
+	if list.RemainingItemCount != nil {
+		return len(list.Items) + int(*list.RemainingItemCount), nil
+	}
+
+	count := 0
+	opts.Limit = resourceCountPageSize
+	opts.Continue = ""
+	for {
+		page, err := client.Resource(gvr).Namespace(namespace).List(ctx, opts)
+		if err != nil {
+			return 0, err
+		}
+		count += len(page.Items)
+		if page.Continue == "" {
+			return count, nil
+		}
+		opts.Continue = page.Continue
+	}
+}
+
+// countInNamespaces sums countObjects over the requested namespaces
+func countInNamespaces(ctx context.Context, client metadata.Interface, kind countableKind, namespaces []string, opts metav1.ListOptions) (int, error) {
+	if !kind.namespaced || len(namespaces) == 0 {
+		return countObjects(ctx, client, kind.gvr, "", opts)
+	}
+	total := 0
+	for _, namespace := range namespaces {
+		count, err := countObjects(ctx, client, kind.gvr, namespace, opts)
+		if err != nil {
+			return 0, err
+		}
+		total += count
+	}
+	return total, nil
+}
+
+// pageThrough visits every object of a typed list page by page, so large kinds are never held in memory at once
+func pageThrough[T any](ctx context.Context, namespaces []string, opts metav1.ListOptions, list func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]T, string, error), visit func(*T)) error {
+	if len(namespaces) == 0 {
+		namespaces = []string{""}
+	}
+	for _, namespace := range namespaces {
+		opts.Limit = resourceCountPageSize
+		opts.Continue = ""
+		for {
+			items, next, err := list(ctx, namespace, opts)
+			if err != nil {
+				return err
+			}
+			for i := range items {
+				visit(&items[i])
+			}
+			if next == "" {
+				break
+			}
+			opts.Continue = next
+		}
+	}
+	return nil
+}
+
+// requestedKinds parses the kinds parameter, defaulting to every countable kind
+func requestedKinds(value string) ([]string, error) {
+	var kinds []string
+	seen := map[string]bool{}
+	for _, kind := range strings.Split(value, ",") {
+		kind = strings.ToLower(strings.TrimSpace(kind))
+		if kind == "" || seen[kind] {
+			continue
+		}
+		if _, ok := countableKinds[kind]; !ok {
+			return nil, fmt.Errorf("unsupported kind %q", kind)
+		}
+		seen[kind] = true
+		kinds = append(kinds, kind)
+	}
+	if len(kinds) == 0 {
+		for kind := range countableKinds {
+			kinds = append(kinds, kind)
+		}
+	}
+	sort.Strings(kinds)
+	return kinds, nil
+}
+
+// GetResourceCounts returns object totals per kind
+// @Summary Get resource counts
+// @Description Counts objects per kind with metadata-only lists, without transferring object specs or statuses, so totals stay cheap on clusters with tens of thousands of pods. Counts are cached for 15 seconds. A kind that cannot be counted, e.g. for lack of permission, reports an error instead of failing the request.
+// @Tags Cluster
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param kinds query string false "Comma-separated kinds to count, e.g. pods,deployments; defaults to all supported kinds"
+// @Param namespace query string false "Namespace filter for namespaced kinds"
+// @Param namespaces query string false "Comma-separated namespaces to count across, instead of namespace"
+// @Param namespaceGroup query string false "ID of a saved namespace group to count across"
+// @Param labelSelector query string false "Kubernetes label selector, e.g. app=web,tier!=cache"
+// @Success 200 {object} ResourceCountsResponse "Resource counts"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Security KubeConfig
+// @Router /api/v1/resource-counts [get]
+func (h *ResourceCountsHandler) GetResourceCounts(c *gin.Context) {
+	kinds, err := requestedKinds(c.Query("kinds"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	namespaces := utils.RequestedNamespaces(c)
+	key := cacheKey(c, strings.Join(kinds, ","), namespaces)
+	if value, ok := h.cached(key); ok {
+		c.JSON(http.StatusOK, value)
+		return
+	}
+
+	_, metadataClient, err := h.getClients(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for resource counts")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, span := h.tracingHelper.StartKubernetesAPISpan(c.Request.Context(), "count", "resources", strings.Join(namespaces, ","))
+	defer span.End()
+
+	opts := utils.ListOptions(c)
+	counts := make([]ResourceCount, len(kinds))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < resourceCountWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				counts[i].Kind = kinds[i]
+				count, err := countInNamespaces(ctx, metadataClient, countableKinds[kinds[i]], namespaces, opts)
+				if err != nil {
+					counts[i].Error = err.Error()
+					continue
+				}
+				counts[i].Count = count
+			}
+		}()
+	}
+	for i := range kinds {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	response := ResourceCountsResponse{Namespaces: namespaces, Counts: counts}
+	h.remember(key, response)
+	h.tracingHelper.AddResourceAttributes(span, "resources", "count", len(counts))
+	h.tracingHelper.RecordSuccess(span, fmt.Sprintf("Counted %d kinds", len(counts)))
+	c.JSON(http.StatusOK, response)
+}
+
+// summarize computes the status breakdown of a kind
+func summarize(ctx context.Context, client kubernetes.Interface, metadataClient metadata.Interface, kind string, namespaces []string, opts metav1.ListOptions) (map[string]int, error) {
+	breakdown := map[string]int{}
+	replicas := func(r *int32) int32 {
+		if r == nil {
+			return 1
+		}
+		return *r
+	}
+
+	switch kind {
+	case "pods":
+		for _, phase := range podPhases {
+			phaseOpts := opts
+			phaseOpts.FieldSelector = "status.phase=" + string(phase)
+			count, err := countInNamespaces(ctx, metadataClient, countableKinds[kind], namespaces, phaseOpts)
+			if err != nil {
+				return nil, err
+			}
+			if count > 0 {
+				breakdown[string(phase)] = count
+			}
+		}
+		return breakdown, nil
+	case "nodes":
+		return breakdown, pageThrough(ctx, nil, opts, func(ctx context.Context, _ string, opts metav1.ListOptions) ([]v1.Node, string, error) {
+			list, err := client.CoreV1().Nodes().List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+			return list.Items, list.Continue, nil
+		}, func(node *v1.Node) {
+			status := "NotReady"
+			for _, condition := range node.Status.Conditions {
+				if condition.Type == v1.NodeReady && condition.Status == v1.ConditionTrue {
+					status = "Ready"
+				}
+			}
+			if node.Spec.Unschedulable {
+				status += ",SchedulingDisabled"
+			}
+			breakdown[status]++
+		})
+	case "persistentvolumeclaims":
+		return breakdown, pageThrough(ctx, namespaces, opts, func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]v1.PersistentVolumeClaim, string, error) {
+			list, err := client.CoreV1().PersistentVolumeClaims(namespace).List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+			return list.Items, list.Continue, nil
+		}, func(pvc *v1.PersistentVolumeClaim) {
+			breakdown[string(pvc.Status.Phase)]++
+		})
+	case "deployments":
+		return breakdown, pageThrough(ctx, namespaces, opts, func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.Deployment, string, error) {
+			list, err := client.AppsV1().Deployments(namespace).List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+			return list.Items, list.Continue, nil
+		}, func(d *appsv1.Deployment) {
+			breakdown[workloadHealth(replicas(d.Spec.Replicas), d.Status.AvailableReplicas, false)]++
+		})
+	case "statefulsets":
+		return breakdown, pageThrough(ctx, namespaces, opts, func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.StatefulSet, string, error) {
+			list, err := client.AppsV1().StatefulSets(namespace).List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+			return list.Items, list.Continue, nil
+		}, func(s *appsv1.StatefulSet) {
+			breakdown[workloadHealth(replicas(s.Spec.Replicas), s.Status.ReadyReplicas, false)]++
+		})
+	case "daemonsets":
+		return breakdown, pageThrough(ctx, namespaces, opts, func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.DaemonSet, string, error) {
+			list, err := client.AppsV1().DaemonSets(namespace).List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+			return list.Items, list.Continue, nil
+		}, func(d *appsv1.DaemonSet) {
+			breakdown[workloadHealth(d.Status.DesiredNumberScheduled, d.Status.NumberReady, false)]++
+		})
+	case "replicasets":
+		return breakdown, pageThrough(ctx, namespaces, opts, func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.ReplicaSet, string, error) {
+			list, err := client.AppsV1().ReplicaSets(namespace).List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+			return list.Items, list.Continue, nil
+		}, func(r *appsv1.ReplicaSet) {
+			breakdown[workloadHealth(replicas(r.Spec.Replicas), r.Status.ReadyReplicas, false)]++
+		})
+	case "replicationcontrollers":
+		return breakdown, pageThrough(ctx, namespaces, opts, func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]v1.ReplicationController, string, error) {
+			list, err := client.CoreV1().ReplicationControllers(namespace).List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+			return list.Items, list.Continue, nil
+		}, func(r *v1.ReplicationController) {
+			breakdown[workloadHealth(replicas(r.Spec.Replicas), r.Status.ReadyReplicas, false)]++
+		})
+	case "jobs":
+		return breakdown, pageThrough(ctx, namespaces, opts, func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]batchv1.Job, string, error) {
+			list, err := client.BatchV1().Jobs(namespace).List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+			return list.Items, list.Continue, nil
+		}, func(job *batchv1.Job) {
+			status := "Running"
+			for _, condition := range job.Status.Conditions {
+				if condition.Status != v1.ConditionTrue {
+					continue
+				}
+				switch condition.Type {
+				case batchv1.JobComplete:
+					status = "Complete"
+				case batchv1.JobFailed:
+					status = "Failed"
+				case batchv1.JobSuspended:
+					status = "Suspended"
+				}
+			}
+			breakdown[status]++
+		})
+	}
+	return nil, fmt.Errorf("no status summary for %s; supported kinds are %s", kind, strings.Join(summaryKinds, ", "))
+}
+
+// summaryKinds are the kinds summarize breaks down by status
+var summaryKinds = []string{"daemonsets", "deployments", "jobs", "nodes", "persistentvolumeclaims", "pods", "replicasets", "replicationcontrollers", "statefulsets"}
+
+// GetResourceSummary returns the status breakdown of a kind
+// @Summary Get resource status summary
+// @Description Breaks the objects of a kind down by status without streaming the list: pods by phase using metadata-only counts, persistent volume claims by phase, nodes by readiness, jobs by outcome, and deployments, statefulsets, daemonsets, replicasets and replicationcontrollers into healthy, degraded and scaledDown. Lists are paged so large clusters are never held in memory. Summaries are cached for 15 seconds.
+// @Tags Cluster
+// @Produce json
+// @Param kind path string true "Kind: pods, nodes, persistentvolumeclaims, deployments, statefulsets, daemonsets, replicasets or jobs"
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param namespace query string false "Namespace filter for namespaced kinds"
+// @Param namespaces query string false "Comma-separated namespaces to summarize across, instead of namespace"
+// @Param namespaceGroup query string false "ID of a saved namespace group to summarize across"
+// @Param labelSelector query string false "Kubernetes label selector, e.g. app=web,tier!=cache"
+// @Success 200 {object} ResourceSummary "Status breakdown"
+// @Failure 400 {object} map[string]string "Bad request - unsupported kind"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security KubeConfig
+// @Router /api/v1/resource-counts/{kind}/summary [get]
+func (h *ResourceCountsHandler) GetResourceSummary(c *gin.Context) {
+	kind := strings.ToLower(c.Param("kind"))
+	if i := sort.SearchStrings(summaryKinds, kind); i == len(summaryKinds) || summaryKinds[i] != kind {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("no status summary for %s; supported kinds are %s", kind, strings.Join(summaryKinds, ", "))})
+		return
+	}
+	namespaces := utils.RequestedNamespaces(c)
+	if !countableKinds[kind].namespaced {
+		namespaces = nil
+	}
+	key := cacheKey(c, kind+"/summary", namespaces)
+	if value, ok := h.cached(key); ok {
+		c.JSON(http.StatusOK, value)
+		return
+	}
+
+	client, metadataClient, err := h.getClients(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for resource summary")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, span := h.tracingHelper.StartKubernetesAPISpan(c.Request.Context(), "summarize", kind, strings.Join(namespaces, ","))
+	defer span.End()
+
+	breakdown, err := summarize(ctx, client, metadataClient, kind, namespaces, utils.ListOptions(c))
+	if err != nil {
+		h.logger.WithError(err).WithField("kind", kind).Error("Failed to summarize resources")
+		h.tracingHelper.RecordError(span, err, "Failed to summarize resources")
+		if utils.IsPermissionError(err) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	summary := ResourceSummary{Kind: kind, Namespaces: namespaces, Breakdown: breakdown}
+	for _, count := range breakdown {
+		summary.Total += count
+	}
+	h.remember(key, summary)
+	h.tracingHelper.RecordSuccess(span, fmt.Sprintf("Summarized %d %s", summary.Total, kind))
+	c.JSON(http.StatusOK, summary)
+}
@@ -0,0 +1,86 @@
+package cluster
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	metadatafake "k8s.io/client-go/metadata/fake"
+)
+
+func TestRequestedKinds(t *testing.T) {
+	kinds, err := requestedKinds(" Pods,deployments,pods, ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"deployments", "pods"}; !reflect.DeepEqual(kinds, want) {
+		t.Errorf("kinds = %v, want %v", kinds, want)
+	}
+	if all, _ := requestedKinds(""); len(all) != len(countableKinds) {
+		t.Errorf("expected every countable kind by default, got %d", len(all))
+	}
+	if _, err := requestedKinds("pods,widgets"); err == nil {
+		t.Error("expected an error for an unsupported kind")
+	}
+}
+
+func TestCountInNamespaces(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := metav1.AddMetaToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	pod := func(namespace, name string) *metav1.PartialObjectMetadata {
+		return &metav1.PartialObjectMetadata{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		}
+	}
+	client := metadatafake.NewSimpleMetadataClient(scheme, pod("shop", "web-0"), pod("shop", "web-1"), pod("batch", "job-0"), pod("kube-system", "dns"))
+
+	count, err := countInNamespaces(context.Background(), client, countableKinds["pods"], []string{"shop", "batch"}, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Errorf("count = %d, want 3", count)
+	}
+	if all, _ := countInNamespaces(context.Background(), client, countableKinds["pods"], nil, metav1.ListOptions{}); all != 4 {
+		t.Errorf("count across all namespaces = %d, want 4", all)
+	}
+}
+
+func TestSummarizeWorkloads(t *testing.T) {
+	replicas := func(n int32) *int32 { return &n }
+	client := fake.NewSimpleClientset(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"}, Spec: appsv1.DeploymentSpec{Replicas: replicas(3)}, Status: appsv1.DeploymentStatus{AvailableReplicas: 3}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"}, Spec: appsv1.DeploymentSpec{Replicas: replicas(2)}, Status: appsv1.DeploymentStatus{AvailableReplicas: 1}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "old", Namespace: "shop"}, Spec: appsv1.DeploymentSpec{Replicas: replicas(0)}},
+		&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "done", Namespace: "shop"}, Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}}},
+		&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "broken", Namespace: "shop"}, Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: v1.ConditionTrue}}}},
+		&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "running", Namespace: "shop"}},
+	)
+
+	deployments, err := summarize(context.Background(), client, nil, "deployments", []string{"shop"}, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]int{healthHealthy: 1, healthDegraded: 1, healthScaledDown: 1}; !reflect.DeepEqual(deployments, want) {
+		t.Errorf("deployments = %v, want %v", deployments, want)
+	}
+	jobs, err := summarize(context.Background(), client, nil, "jobs", nil, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]int{"Complete": 1, "Failed": 1, "Running": 1}; !reflect.DeepEqual(jobs, want) {
+		t.Errorf("jobs = %v, want %v", jobs, want)
+	}
+	if _, err := summarize(context.Background(), client, nil, "configmaps", nil, metav1.ListOptions{}); err == nil {
+		t.Error("expected an error for a kind without a summary")
+	}
+}
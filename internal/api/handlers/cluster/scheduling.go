@@ -0,0 +1,335 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/handlers/metrics"
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/internal/tracing"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Stages of a pending pod
+const (
+	PendingStageGated       = "gated"       // held back by scheduling gates
+	PendingStageUnscheduled = "unscheduled" // waiting in the scheduler queue
+	PendingStageStarting    = "starting"    // bound to a node, containers not yet running
+)
+
+const (
+	schedulingMetricsTimeout = 20 * time.Second
+	defaultSchedulingWindow  = time.Hour
+)
+
+// PendingPodInfo is a Pending pod with how long it has waited and why
+type PendingPodInfo struct {
+	Name           string  `json:"name"`
+	Namespace      string  `json:"namespace"`
+	Stage          string  `json:"stage"`
+	Reason         string  `json:"reason,omitempty"`
+	Message        string  `json:"message,omitempty"`
+	NodeName       string  `json:"nodeName,omitempty"`
+	NominatedNode  string  `json:"nominatedNode,omitempty"` // node the scheduler preempts pods on for this one
+	SchedulerName  string  `json:"schedulerName,omitempty"`
+	PendingSince   string  `json:"pendingSince"`
+	PendingSeconds float64 `json:"pendingSeconds"`
+}
+
+// SchedulingLatency are scheduler metric percentiles over a window; nil fields lack samples
+type SchedulingLatency struct {
+	Window                 string             `json:"window"`
+	P50Seconds             *float64           `json:"p50Seconds,omitempty"` // pod creation to binding, including queue time
+	P90Seconds             *float64           `json:"p90Seconds,omitempty"`
+	P99Seconds             *float64           `json:"p99Seconds,omitempty"`
+	AttemptP99Seconds      *float64           `json:"attemptP99Seconds,omitempty"` // one scheduling cycle
+	AttemptsPerSecond      map[string]float64 `json:"attemptsPerSecond"`           // by result: scheduled, unschedulable, error
+	QueuedPods             map[string]float64 `json:"queuedPods"`                  // scheduler queue sizes: active, backoff, unschedulable, gated
+	SchedulerMetricsFound  bool               `json:"schedulerMetricsFound"`
+	SchedulerMetricsReason string             `json:"schedulerMetricsReason,omitempty"`
+}
+
+// SchedulingReport combines the pending pod queue with historical scheduling latency
+type SchedulingReport struct {
+	GeneratedAt     time.Time          `json:"generatedAt"`
+	Namespace       string             `json:"namespace,omitempty"`
+	PendingPods     []PendingPodInfo   `json:"pendingPods"`
+	PendingByStage  map[string]int     `json:"pendingByStage"`
+	PendingByReason map[string]int     `json:"pendingByReason"`
+	OldestPending   float64            `json:"oldestPendingSeconds"`
+	Latency         *SchedulingLatency `json:"latency"`
+}
+
+// SchedulingHandler reports pending pods and scheduling latency
+type SchedulingHandler struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	prometheus    metrics.PrometheusQuerier
+	logger        *logger.Logger
+	tracingHelper *tracing.TracingHelper
+}
+
+// NewSchedulingHandler creates a new SchedulingHandler instance
+func NewSchedulingHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, prometheus metrics.PrometheusQuerier, log *logger.Logger) *SchedulingHandler {
+	return &SchedulingHandler{
+		store:         store,
+		clientFactory: clientFactory,
+		prometheus:    prometheus,
+		logger:        log,
+		tracingHelper: tracing.GetTracingHelper(),
+	}
+}
+
+// getClient gets the Kubernetes client for the current request
+func (h *SchedulingHandler) getClient(c *gin.Context) (kubernetes.Interface, error) {
+	configID := c.Query("config")
+	cluster := c.Query("cluster")
+
+	if configID == "" {
+		return nil, fmt.Errorf("config parameter is required")
+	}
+
+	config, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, fmt.Errorf("config not found: %w", err)
+	}
+
+	client, err := h.clientFactory.GetClientForConfig(config, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Kubernetes client: %w", err)
+	}
+
+	return client, nil
+}
+
+// pendingPodInfo classifies a Pending pod. Pods are pending from creation, so the wait is
+// measured from the creation timestamp.
+func pendingPodInfo(pod *v1.Pod, now time.Time) PendingPodInfo {
+	info := PendingPodInfo{
+		Name:           pod.Name,
+		Namespace:      pod.Namespace,
+		Stage:          PendingStageUnscheduled,
+		NodeName:       pod.Spec.NodeName,
+		NominatedNode:  pod.Status.NominatedNodeName,
+		SchedulerName:  pod.Spec.SchedulerName,
+		PendingSince:   pod.CreationTimestamp.Format(time.RFC3339),
+		PendingSeconds: math.Max(0, now.Sub(pod.CreationTimestamp.Time).Seconds()),
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == v1.PodScheduled {
+			info.Reason, info.Message = cond.Reason, cond.Message
+		}
+	}
+	switch {
+	case len(pod.Spec.SchedulingGates) > 0:
+		info.Stage = PendingStageGated
+		if info.Reason == "" {
+			info.Reason = v1.PodReasonSchedulingGated
+		}
+	case pod.Spec.NodeName != "":
+		info.Stage = PendingStageStarting
+		info.Reason, info.Message = "ContainersNotReady", ""
+		statuses := append(append([]v1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+		for _, status := range statuses {
+			if status.State.Waiting != nil && status.State.Waiting.Reason != "" {
+				info.Reason, info.Message = status.State.Waiting.Reason, status.State.Waiting.Message
+				break
+			}
+		}
+	case info.Reason == "":
+		// Not yet seen by the scheduler
+		info.Reason = "Queued"
+	}
+	return info
+}
+
+// schedulingQueries are the PromQL queries over the kube-scheduler metrics. The SLI histogram
+// replaced scheduler_pod_scheduling_duration_seconds in Kubernetes 1.27, so both are tried.
+func schedulingQueries(window string) (quantiles map[float64]string, attemptP99, attempts, queued string) {
+	buckets := fmt.Sprintf(
+		"sum by (le) (rate(scheduler_pod_scheduling_sli_duration_seconds_bucket[%[1]s])) or sum by (le) (rate(scheduler_pod_scheduling_duration_seconds_bucket[%[1]s]))",
+		window)
+	quantiles = map[float64]string{}
+	for _, q := range []float64{0.5, 0.9, 0.99} {
+		quantiles[q] = fmt.Sprintf("histogram_quantile(%g, %s)", q, buckets)
+	}
+	attemptP99 = fmt.Sprintf("histogram_quantile(0.99, sum by (le) (rate(scheduler_scheduling_attempt_duration_seconds_bucket[%s])))", window)
+	attempts = fmt.Sprintf("sum by (result) (rate(scheduler_schedule_attempts_total[%s]))", window)
+	queued = "sum by (queue) (scheduler_pending_pods)"
+	return quantiles, attemptP99, attempts, queued
+}
+
+// schedulerVector is the response of an instant Prometheus query
+type schedulerVector struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		Result []struct {
+			Metric map[string]string `json:"metric"`
+			Value  [2]interface{}    `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// parseSchedulerVector maps a label of an instant query result to its value, leaving out NaN
+// samples such as quantiles of empty histograms
+func parseSchedulerVector(raw []byte, label string) (map[string]float64, error) {
+	var vector schedulerVector
+	if err := json.Unmarshal(raw, &vector); err != nil {
+		return nil, fmt.Errorf("invalid Prometheus response: %w", err)
+	}
+	if vector.Status != "success" {
+		return nil, fmt.Errorf("prometheus query failed: %s", vector.Error)
+	}
+	values := map[string]float64{}
+	for _, sample := range vector.Data.Result {
+		text, _ := sample.Value[1].(string)
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			continue
+		}
+		values[sample.Metric[label]] = value
+	}
+	return values, nil
+}
+
+// schedulingLatency queries the kube-scheduler metrics. Managed control planes often do not
+// expose them, which is reported rather than treated as an error.
+func (h *SchedulingHandler) schedulingLatency(ctx context.Context, client kubernetes.Interface, targetKey string, window time.Duration) (*SchedulingLatency, error) {
+	latency := &SchedulingLatency{Window: window.String(), AttemptsPerSecond: map[string]float64{}, QueuedPods: map[string]float64{}}
+	run := func(query, label string) (map[string]float64, error) {
+		raw, err := h.prometheus.Query(ctx, client, targetKey, "/api/v1/query", map[string]string{"query": query})
+		if err != nil {
+			return nil, err
+		}
+		return parseSchedulerVector(raw, label)
+	}
+	single := func(query string) (*float64, error) {
+		values, err := run(query, "")
+		if err != nil {
+			return nil, err
+		}
+		if v, ok := values[""]; ok {
+			return &v, nil
+		}
+		return nil, nil
+	}
+
+	windowText := strconv.Itoa(int(window.Seconds())) + "s"
+	quantiles, attemptP99, attempts, queued := schedulingQueries(windowText)
+	var err error
+	for q, target := range map[float64]**float64{0.5: &latency.P50Seconds, 0.9: &latency.P90Seconds, 0.99: &latency.P99Seconds} {
+		if *target, err = single(quantiles[q]); err != nil {
+			return nil, err
+		}
+	}
+	if latency.AttemptP99Seconds, err = single(attemptP99); err != nil {
+		return nil, err
+	}
+	if latency.AttemptsPerSecond, err = run(attempts, "result"); err != nil {
+		return nil, err
+	}
+	if latency.QueuedPods, err = run(queued, "queue"); err != nil {
+		return nil, err
+	}
+	latency.SchedulerMetricsFound = latency.P50Seconds != nil || len(latency.AttemptsPerSecond) > 0 || len(latency.QueuedPods) > 0
+	if !latency.SchedulerMetricsFound {
+		latency.SchedulerMetricsReason = "Prometheus has no kube-scheduler metrics; managed control planes often do not expose them"
+	}
+	return latency, nil
+}
+
+// GetSchedulingReport reports the pending pod queue and scheduling latency
+// @Summary Get pod scheduling report
+// @Description Lists Pending pods with how long they have waited and why: gated by scheduling gates, unscheduled with the scheduler's reason, or bound to a node with containers still starting. When Prometheus scrapes kube-scheduler, adds scheduling latency percentiles (pod creation to binding) over the window, the p99 of a scheduling attempt, attempt rates by result and the scheduler queue sizes. Without Prometheus or scheduler metrics, latency carries the reason instead of values.
+// @Tags Cluster
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param namespace query string false "Limit pending pods to one namespace"
+// @Param window query string false "Latency window between 5m and 168h (default 1h)"
+// @Success 200 {object} SchedulingReport
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/scheduling [get]
+func (h *SchedulingHandler) GetSchedulingReport(c *gin.Context) {
+	ctx, span := h.tracingHelper.StartAuthSpan(c.Request.Context(), "scheduling.report")
+	defer span.End()
+
+	window := defaultSchedulingWindow
+	if v := c.Query("window"); v != "" {
+		parsed, err := time.ParseDuration(v)
+		if err != nil || parsed < 5*time.Minute || parsed > 7*24*time.Hour {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "window must be a duration between 5m and 168h"})
+			return
+		}
+		window = parsed
+	}
+
+	client, err := h.getClient(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for scheduling report")
+		h.tracingHelper.RecordError(span, err, "Failed to get Kubernetes client")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	namespace := c.Query("namespace")
+	apiCtx, apiSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "pods", namespace)
+	pods, err := client.CoreV1().Pods(namespace).List(apiCtx, metav1.ListOptions{FieldSelector: "status.phase=Pending"})
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list pending pods for scheduling report")
+		h.tracingHelper.RecordError(apiSpan, err, "Failed to list pending pods")
+		apiSpan.End()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(apiSpan, fmt.Sprintf("Found %d pending pods", len(pods.Items)))
+	apiSpan.End()
+
+	now := time.Now()
+	report := SchedulingReport{
+		GeneratedAt:     now,
+		Namespace:       namespace,
+		PendingPods:     []PendingPodInfo{},
+		PendingByStage:  map[string]int{},
+		PendingByReason: map[string]int{},
+	}
+	for i := range pods.Items {
+		info := pendingPodInfo(&pods.Items[i], now)
+		report.PendingPods = append(report.PendingPods, info)
+		report.PendingByStage[info.Stage]++
+		report.PendingByReason[info.Reason]++
+		report.OldestPending = math.Max(report.OldestPending, info.PendingSeconds)
+	}
+	sort.Slice(report.PendingPods, func(i, j int) bool {
+		return report.PendingPods[i].PendingSeconds > report.PendingPods[j].PendingSeconds
+	})
+
+	metricsCtx, cancel := context.WithTimeout(ctx, schedulingMetricsTimeout)
+	defer cancel()
+	targetKey := c.Query("config") + "|" + c.Query("cluster")
+	latency, err := h.schedulingLatency(metricsCtx, client, targetKey, window)
+	if err != nil {
+		h.logger.WithError(err).Debug("Scheduling latency unavailable")
+		latency = &SchedulingLatency{Window: window.String(), AttemptsPerSecond: map[string]float64{}, QueuedPods: map[string]float64{}, SchedulerMetricsReason: err.Error()}
+	}
+	report.Latency = latency
+
+	h.tracingHelper.RecordSuccess(span, fmt.Sprintf("Reported %d pending pods", len(report.PendingPods)))
+	c.JSON(http.StatusOK, report)
+}
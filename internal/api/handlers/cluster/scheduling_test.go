@@ -0,0 +1,97 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+func TestPendingPodInfo(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	meta := metav1.ObjectMeta{Name: "web-1", Namespace: "shop", CreationTimestamp: metav1.NewTime(now.Add(-90 * time.Second))}
+
+	unschedulable := &v1.Pod{ObjectMeta: meta, Status: v1.PodStatus{Conditions: []v1.PodCondition{{
+		Type: v1.PodScheduled, Status: v1.ConditionFalse, Reason: v1.PodReasonUnschedulable, Message: "0/3 nodes are available: 3 Insufficient cpu.",
+	}}}}
+	info := pendingPodInfo(unschedulable, now)
+	if info.Stage != PendingStageUnscheduled || info.Reason != v1.PodReasonUnschedulable || info.PendingSeconds != 90 {
+		t.Errorf("unschedulable pod = %+v", info)
+	}
+
+	gated := &v1.Pod{ObjectMeta: meta, Spec: v1.PodSpec{SchedulingGates: []v1.PodSchedulingGate{{Name: "example.com/quota"}}}}
+	if info := pendingPodInfo(gated, now); info.Stage != PendingStageGated || info.Reason != v1.PodReasonSchedulingGated {
+		t.Errorf("gated pod = %+v", info)
+	}
+
+	starting := &v1.Pod{ObjectMeta: meta, Spec: v1.PodSpec{NodeName: "node-a"}, Status: v1.PodStatus{
+		Conditions:        []v1.PodCondition{{Type: v1.PodScheduled, Status: v1.ConditionTrue}},
+		ContainerStatuses: []v1.ContainerStatus{{Name: "app", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "back-off pulling image"}}}},
+	}}
+	if info := pendingPodInfo(starting, now); info.Stage != PendingStageStarting || info.Reason != "ImagePullBackOff" || info.NodeName != "node-a" {
+		t.Errorf("starting pod = %+v", info)
+	}
+
+	if info := pendingPodInfo(&v1.Pod{ObjectMeta: meta}, now); info.Reason != "Queued" {
+		t.Errorf("pod not yet seen by the scheduler = %+v", info)
+	}
+}
+
+// fakeSchedulerPrometheus answers queries by metric name
+type fakeSchedulerPrometheus struct {
+	results map[string]string
+	err     error
+}
+
+func (f *fakeSchedulerPrometheus) Query(_ context.Context, _ kubernetes.Interface, _, _ string, params map[string]string) ([]byte, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	for metric, result := range f.results {
+		if strings.Contains(params["query"], metric) {
+			return []byte(`{"status":"success","data":{"resultType":"vector","result":[` + result + `]}}`), nil
+		}
+	}
+	return []byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`), nil
+}
+
+func TestSchedulingLatency(t *testing.T) {
+	prom := &fakeSchedulerPrometheus{results: map[string]string{
+		"histogram_quantile(0.5, sum by (le) (rate(scheduler_pod_scheduling_sli": `{"metric":{},"value":[1714564800,"0.012"]}`,
+		"histogram_quantile(0.99, sum by (le) (rate(scheduler_pod_scheduling_sli": `{"metric":{},"value":[1714564800,"NaN"]}`,
+		"scheduler_schedule_attempts_total": `{"metric":{"result":"scheduled"},"value":[1714564800,"1.5"]},{"metric":{"result":"unschedulable"},"value":[1714564800,"0.25"]}`,
+		"scheduler_pending_pods":            `{"metric":{"queue":"unschedulable"},"value":[1714564800,"4"]}`,
+	}}
+	h := &SchedulingHandler{prometheus: prom, logger: logger.New("error")}
+
+	latency, err := h.schedulingLatency(context.Background(), nil, "c1|", time.Hour)
+	if err != nil {
+		t.Fatalf("schedulingLatency() = %v", err)
+	}
+	if latency.P50Seconds == nil || *latency.P50Seconds != 0.012 {
+		t.Errorf("p50 = %v, want 0.012", latency.P50Seconds)
+	}
+	if latency.P99Seconds != nil {
+		t.Errorf("p99 of a NaN sample = %v, want nil", *latency.P99Seconds)
+	}
+	if latency.AttemptsPerSecond["unschedulable"] != 0.25 || latency.QueuedPods["unschedulable"] != 4 || !latency.SchedulerMetricsFound {
+		t.Errorf("latency = %+v", latency)
+	}
+
+	empty := &SchedulingHandler{prometheus: &fakeSchedulerPrometheus{}, logger: logger.New("error")}
+	if latency, err := empty.schedulingLatency(context.Background(), nil, "c1|", time.Hour); err != nil || latency.SchedulerMetricsFound || latency.SchedulerMetricsReason == "" {
+		t.Errorf("latency without scheduler metrics = %+v %v", latency, err)
+	}
+
+	failing := &SchedulingHandler{prometheus: &fakeSchedulerPrometheus{err: fmt.Errorf("prometheus not available")}, logger: logger.New("error")}
+	if _, err := failing.schedulingLatency(context.Background(), nil, "c1|", time.Hour); err == nil {
+		t.Error("schedulingLatency() without Prometheus returned no error")
+	}
+}
@@ -0,0 +1,285 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/transformers"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// SpotWorkload is a workload whose scheduled pods all run on spot or preemptible nodes
+type SpotWorkload struct {
+	Kind          string   `json:"kind"`
+	Namespace     string   `json:"namespace"`
+	Name          string   `json:"name"`
+	Replicas      int32    `json:"replicas"`
+	PodsOnSpot    int      `json:"podsOnSpot"`
+	Nodes         []string `json:"nodes"`
+	PDBs          []string `json:"pdbs,omitempty"`
+	SingleReplica bool     `json:"singleReplica"`
+	HasPDB        bool     `json:"hasPDB"`
+	Critical      bool     `json:"critical"`
+	Reasons       []string `json:"reasons,omitempty"`
+}
+
+// SpotRiskSummary counts spot capacity and the workloads depending on it
+type SpotRiskSummary struct {
+	Nodes             int            `json:"nodes"`
+	SpotNodes         int            `json:"spotNodes"`
+	SpotProviders     map[string]int `json:"spotProviders,omitempty"`
+	PodsOnSpot        int            `json:"podsOnSpot"`
+	SpotOnlyWorkloads int            `json:"spotOnlyWorkloads"`
+	CriticalWorkloads int            `json:"criticalWorkloads"`
+}
+
+// SpotRiskReport lists workloads that would be fully disrupted if spot capacity were reclaimed
+type SpotRiskReport struct {
+	Summary   SpotRiskSummary `json:"summary"`
+	Workloads []SpotWorkload  `json:"workloads"`
+}
+
+// spotWorkloadKey identifies a workload as kind/namespace/name
+type spotWorkloadKey struct {
+	kind, namespace, name string
+}
+
+// analyzeSpotRisk groups scheduled pods by their Deployment or StatefulSet and reports the
+// workloads whose pods all run on spot nodes. rsOwners maps namespace/replicaset to its deployment.
+func analyzeSpotRisk(nodes []v1.Node, pods []v1.Pod, rsOwners map[string]string, replicas map[spotWorkloadKey]int32,
+	templates map[spotWorkloadKey]map[string]string, pdbs []policyv1.PodDisruptionBudget) SpotRiskReport {
+	report := SpotRiskReport{Workloads: []SpotWorkload{}}
+	report.Summary.Nodes = len(nodes)
+
+	spotNodes := map[string]bool{}
+	for i := range nodes {
+		if provider := transformers.SpotProvider(nodes[i].Labels); provider != "" {
+			spotNodes[nodes[i].Name] = true
+			if report.Summary.SpotProviders == nil {
+				report.Summary.SpotProviders = map[string]int{}
+			}
+			report.Summary.SpotProviders[provider]++
+		}
+	}
+	report.Summary.SpotNodes = len(spotNodes)
+
+	type placement struct {
+		total, onSpot int
+		nodes         map[string]bool
+	}
+	workloads := map[spotWorkloadKey]*placement{}
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		if spotNodes[pod.Spec.NodeName] {
+			report.Summary.PodsOnSpot++
+		}
+		ref := metav1.GetControllerOf(pod)
+		if ref == nil {
+			continue
+		}
+		var key spotWorkloadKey
+		switch ref.Kind {
+		case "ReplicaSet":
+			deployment, ok := rsOwners[pod.Namespace+"/"+ref.Name]
+			if !ok {
+				continue
+			}
+			key = spotWorkloadKey{"Deployment", pod.Namespace, deployment}
+		case "StatefulSet":
+			key = spotWorkloadKey{"StatefulSet", pod.Namespace, ref.Name}
+		default:
+			continue
+		}
+		p, ok := workloads[key]
+		if !ok {
+			p = &placement{nodes: map[string]bool{}}
+			workloads[key] = p
+		}
+		p.total++
+		if spotNodes[pod.Spec.NodeName] {
+			p.onSpot++
+			p.nodes[pod.Spec.NodeName] = true
+		}
+	}
+
+	for key, p := range workloads {
+		if p.onSpot == 0 || p.onSpot < p.total {
+			continue
+		}
+		workload := SpotWorkload{
+			Kind:       key.kind,
+			Namespace:  key.namespace,
+			Name:       key.name,
+			Replicas:   replicas[key],
+			PodsOnSpot: p.onSpot,
+			Nodes:      make([]string, 0, len(p.nodes)),
+		}
+		for node := range p.nodes {
+			workload.Nodes = append(workload.Nodes, node)
+		}
+		sort.Strings(workload.Nodes)
+
+		podLabels := labels.Set(templates[key])
+		for i := range pdbs {
+			pdb := &pdbs[i]
+			if pdb.Namespace != key.namespace || pdb.Spec.Selector == nil {
+				continue
+			}
+			selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+			if err != nil || selector.Empty() || !selector.Matches(podLabels) {
+				continue
+			}
+			workload.PDBs = append(workload.PDBs, pdb.Name)
+		}
+		workload.HasPDB = len(workload.PDBs) > 0
+		workload.SingleReplica = workload.Replicas <= 1
+
+		if workload.SingleReplica {
+			workload.Reasons = append(workload.Reasons, "single replica: reclaiming its spot node causes an outage")
+		} else if len(workload.Nodes) == 1 {
+			workload.Reasons = append(workload.Reasons, "all replicas share one spot node")
+		}
+		if !workload.HasPDB {
+			workload.Reasons = append(workload.Reasons, "no PodDisruptionBudget limits simultaneous evictions")
+		}
+		workload.Critical = len(workload.Reasons) > 0
+		if workload.Critical {
+			report.Summary.CriticalWorkloads++
+		}
+		report.Workloads = append(report.Workloads, workload)
+	}
+	report.Summary.SpotOnlyWorkloads = len(report.Workloads)
+
+	sort.Slice(report.Workloads, func(i, j int) bool {
+		a, b := report.Workloads[i], report.Workloads[j]
+		if a.Critical != b.Critical {
+			return a.Critical
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return report
+}
+
+// loadSpotRisk lists the objects needed for the spot risk analysis
+func loadSpotRisk(ctx context.Context, client kubernetes.Interface, namespace string) (SpotRiskReport, error) {
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return SpotRiskReport{}, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{FieldSelector: "status.phase!=Succeeded,status.phase!=Failed"})
+	if err != nil {
+		return SpotRiskReport{}, fmt.Errorf("failed to list pods: %w", err)
+	}
+	replicaSets, err := client.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return SpotRiskReport{}, fmt.Errorf("failed to list replicasets: %w", err)
+	}
+	deployments, err := client.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return SpotRiskReport{}, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	statefulSets, err := client.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return SpotRiskReport{}, fmt.Errorf("failed to list statefulsets: %w", err)
+	}
+	pdbs, err := client.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return SpotRiskReport{}, fmt.Errorf("failed to list pod disruption budgets: %w", err)
+	}
+
+	rsOwners := map[string]string{}
+	for i := range replicaSets.Items {
+		rs := &replicaSets.Items[i]
+		if ref := metav1.GetControllerOf(rs); ref != nil && ref.Kind == "Deployment" {
+			rsOwners[rs.Namespace+"/"+rs.Name] = ref.Name
+		}
+	}
+	replicas := map[spotWorkloadKey]int32{}
+	templates := map[spotWorkloadKey]map[string]string{}
+	for i := range deployments.Items {
+		d := &deployments.Items[i]
+		key := spotWorkloadKey{"Deployment", d.Namespace, d.Name}
+		replicas[key] = 1
+		if d.Spec.Replicas != nil {
+			replicas[key] = *d.Spec.Replicas
+		}
+		templates[key] = d.Spec.Template.Labels
+	}
+	for i := range statefulSets.Items {
+		s := &statefulSets.Items[i]
+		key := spotWorkloadKey{"StatefulSet", s.Namespace, s.Name}
+		replicas[key] = 1
+		if s.Spec.Replicas != nil {
+			replicas[key] = *s.Spec.Replicas
+		}
+		templates[key] = s.Spec.Template.Labels
+	}
+
+	return analyzeSpotRisk(nodes.Items, pods.Items, rsOwners, replicas, templates, pdbs.Items), nil
+}
+
+// GetSpotRisk reports workloads that run only on spot or preemptible nodes
+// @Summary Get spot capacity risk report
+// @Description Detects spot/preemptible nodes from common cloud provider and autoscaler labels (EKS, Karpenter, GKE, AKS, Spot Ocean) and reports Deployments and StatefulSets whose scheduled pods all run on them. Workloads with a single replica, all replicas on one node, or no PodDisruptionBudget are flagged critical.
+// @Tags Cluster
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name for multi-cluster setups"
+// @Param namespace query string false "Only report workloads in this namespace"
+// @Param critical query bool false "Only return critical workloads"
+// @Success 200 {object} SpotRiskReport "Spot risk report"
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/capacity/spot-risk [get]
+func (h *NodesHandler) GetSpotRisk(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for spot risk report")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Successfully obtained Kubernetes client")
+
+	namespace := c.Query("namespace")
+	_, listSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "workloads", namespace)
+	defer listSpan.End()
+	report, err := loadSpotRisk(ctx, client, namespace)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to build spot risk report")
+		h.tracingHelper.RecordError(listSpan, err, "Failed to build spot risk report")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(listSpan, fmt.Sprintf("Found %d spot-only workloads", report.Summary.SpotOnlyWorkloads))
+
+	if c.Query("critical") == "true" {
+		critical := []SpotWorkload{}
+		for _, w := range report.Workloads {
+			if w.Critical {
+				critical = append(critical, w)
+			}
+		}
+		report.Workloads = critical
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,60 @@
+package cluster
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAnalyzeSpotRisk(t *testing.T) {
+	nodes := []v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "spot-a", Labels: map[string]string{"karpenter.sh/capacity-type": "spot"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "spot-b", Labels: map[string]string{"cloud.google.com/gke-spot": "true"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "ondemand", Labels: map[string]string{"karpenter.sh/capacity-type": "on-demand"}}},
+	}
+	controller := true
+	pod := func(name, owner, kind, node string) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", OwnerReferences: []metav1.OwnerReference{{Kind: kind, Name: owner, Controller: &controller}}},
+			Spec:       v1.PodSpec{NodeName: node},
+		}
+	}
+	pods := []v1.Pod{
+		pod("api-1", "api-rs", "ReplicaSet", "spot-a"),
+		pod("api-2", "api-rs", "ReplicaSet", "spot-b"),
+		pod("worker-1", "worker-rs", "ReplicaSet", "spot-a"),
+		pod("db-0", "db", "StatefulSet", "spot-a"),
+		pod("db-1", "db", "StatefulSet", "ondemand"),
+	}
+	rsOwners := map[string]string{"shop/api-rs": "api", "shop/worker-rs": "worker"}
+	replicas := map[spotWorkloadKey]int32{
+		{"Deployment", "shop", "api"}:    2,
+		{"Deployment", "shop", "worker"}: 1,
+		{"StatefulSet", "shop", "db"}:    2,
+	}
+	templates := map[spotWorkloadKey]map[string]string{
+		{"Deployment", "shop", "api"}:    {"app": "api"},
+		{"Deployment", "shop", "worker"}: {"app": "worker"},
+	}
+	pdbs := []policyv1.PodDisruptionBudget{{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"},
+		Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}}},
+	}}
+
+	report := analyzeSpotRisk(nodes, pods, rsOwners, replicas, templates, pdbs)
+	if report.Summary.SpotNodes != 2 || report.Summary.PodsOnSpot != 4 {
+		t.Fatalf("unexpected summary: %+v", report.Summary)
+	}
+	if len(report.Workloads) != 2 {
+		t.Fatalf("expected api and worker to be spot-only, got %+v", report.Workloads)
+	}
+	worker, api := report.Workloads[0], report.Workloads[1]
+	if worker.Name != "worker" || !worker.Critical || !worker.SingleReplica || worker.HasPDB {
+		t.Errorf("worker should be critical: %+v", worker)
+	}
+	if api.Name != "api" || api.Critical || !api.HasPDB {
+		t.Errorf("api spread over two nodes with a PDB should not be critical: %+v", api)
+	}
+}
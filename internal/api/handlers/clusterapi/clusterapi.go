@@ -0,0 +1,771 @@
+package clusterapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/internal/tracing"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+const capiGroup = "cluster.x-k8s.io"
+
+// Labels and annotations set by Cluster API and the cluster autoscaler
+const (
+	clusterNameLabel        = "cluster.x-k8s.io/cluster-name"
+	deploymentNameLabel     = "cluster.x-k8s.io/deployment-name"
+	controlPlaneLabel       = "cluster.x-k8s.io/control-plane"
+	autoscalerMinAnnotation = "cluster.x-k8s.io/cluster-api-autoscaler-node-group-min-size"
+	autoscalerMaxAnnotation = "cluster.x-k8s.io/cluster-api-autoscaler-node-group-max-size"
+)
+
+// capiVersions are the Cluster API versions to try, newest first
+var capiVersions = []string{"v1beta2", "v1beta1"}
+
+// Health values reported for clusters, machine deployments and machines
+const (
+	healthHealthy     = "Healthy"
+	healthProgressing = "Progressing"
+	healthDegraded    = "Degraded"
+	healthFailed      = "Failed"
+)
+
+// CAPICluster summarizes a Cluster API Cluster
+type CAPICluster struct {
+	Name                string         `json:"name"`
+	Namespace           string         `json:"namespace"`
+	Phase               string         `json:"phase"`
+	Health              string         `json:"health"`
+	ClusterClass        string         `json:"clusterClass,omitempty"`
+	KubernetesVersion   string         `json:"kubernetesVersion,omitempty"`
+	InfrastructureKind  string         `json:"infrastructureKind,omitempty"`
+	ControlPlaneKind    string         `json:"controlPlaneKind,omitempty"`
+	InfrastructureReady bool           `json:"infrastructureReady"`
+	ControlPlaneReady   bool           `json:"controlPlaneReady"`
+	Paused              bool           `json:"paused"`
+	Machines            int            `json:"machines"`
+	MachinesByPhase     map[string]int `json:"machinesByPhase"`
+	Message             string         `json:"message,omitempty"`
+	CreatedAt           string         `json:"createdAt"`
+}
+
+// MachineDeployment summarizes a Cluster API MachineDeployment
+type MachineDeployment struct {
+	Name               string `json:"name"`
+	Namespace          string `json:"namespace"`
+	ClusterName        string `json:"clusterName"`
+	Phase              string `json:"phase"`
+	Health             string `json:"health"`
+	KubernetesVersion  string `json:"kubernetesVersion,omitempty"`
+	InfrastructureKind string `json:"infrastructureKind,omitempty"`
+	Replicas           int64  `json:"replicas"`
+	ReadyReplicas      int64  `json:"readyReplicas"`
+	AvailableReplicas  int64  `json:"availableReplicas"`
+	UpToDateReplicas   int64  `json:"upToDateReplicas"`
+	AutoscalerMin      *int64 `json:"autoscalerMin,omitempty"`
+	AutoscalerMax      *int64 `json:"autoscalerMax,omitempty"`
+	Paused             bool   `json:"paused"`
+	Message            string `json:"message,omitempty"`
+	CreatedAt          string `json:"createdAt"`
+}
+
+// Machine summarizes a Cluster API Machine
+type Machine struct {
+	Name              string `json:"name"`
+	Namespace         string `json:"namespace"`
+	ClusterName       string `json:"clusterName"`
+	MachineDeployment string `json:"machineDeployment,omitempty"`
+	ControlPlane      bool   `json:"controlPlane"`
+	Phase             string `json:"phase"`
+	Health            string `json:"health"`
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+	NodeName          string `json:"nodeName,omitempty"`
+	ProviderID        string `json:"providerId,omitempty"`
+	FailureReason     string `json:"failureReason,omitempty"`
+	Message           string `json:"message,omitempty"`
+	CreatedAt         string `json:"createdAt"`
+}
+
+// HealthSummary counts objects by health and phase
+type HealthSummary struct {
+	Total       int            `json:"total"`
+	Healthy     int            `json:"healthy"`
+	Progressing int            `json:"progressing"`
+	Degraded    int            `json:"degraded"`
+	Failed      int            `json:"failed"`
+	ByPhase     map[string]int `json:"byPhase"`
+}
+
+func (s *HealthSummary) add(health, phase string) {
+	s.Total++
+	switch health {
+	case healthHealthy:
+		s.Healthy++
+	case healthProgressing:
+		s.Progressing++
+	case healthDegraded:
+		s.Degraded++
+	case healthFailed:
+		s.Failed++
+	}
+	s.ByPhase[phase]++
+}
+
+// ClustersResponse lists Cluster API clusters
+type ClustersResponse struct {
+	Installed  bool          `json:"installed"`
+	APIVersion string        `json:"apiVersion,omitempty"`
+	Summary    HealthSummary `json:"summary"`
+	Clusters   []CAPICluster `json:"clusters"`
+}
+
+// MachineDeploymentsResponse lists Cluster API machine deployments
+type MachineDeploymentsResponse struct {
+	Installed          bool                `json:"installed"`
+	APIVersion         string              `json:"apiVersion,omitempty"`
+	Summary            HealthSummary       `json:"summary"`
+	MachineDeployments []MachineDeployment `json:"machineDeployments"`
+}
+
+// MachinesResponse lists Cluster API machines
+type MachinesResponse struct {
+	Installed  bool          `json:"installed"`
+	APIVersion string        `json:"apiVersion,omitempty"`
+	Summary    HealthSummary `json:"summary"`
+	Machines   []Machine     `json:"machines"`
+}
+
+// ClusterAPIHandler surfaces Cluster API clusters, machine deployments and machines
+type ClusterAPIHandler struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	logger        *logger.Logger
+	tracingHelper *tracing.TracingHelper
+}
+
+// NewClusterAPIHandler creates a new Cluster API handler
+func NewClusterAPIHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger) *ClusterAPIHandler {
+	return &ClusterAPIHandler{
+		store:         store,
+		clientFactory: clientFactory,
+		logger:        log,
+		tracingHelper: tracing.GetTracingHelper(),
+	}
+}
+
+// getDynamicClient gets the dynamic Kubernetes client for the current request
+func (h *ClusterAPIHandler) getDynamicClient(c *gin.Context) (dynamic.Interface, error) {
+	configID := c.Query("config")
+	cluster := c.Query("cluster")
+
+	if configID == "" {
+		return nil, fmt.Errorf("config parameter is required")
+	}
+
+	config, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, fmt.Errorf("config not found: %w", err)
+	}
+
+	client, err := h.clientFactory.GetDynamicClientForConfig(config, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dynamic client: %w", err)
+	}
+
+	return client, nil
+}
+
+// listCAPI lists a Cluster API resource using the first served API version; installed is false when the CRD is absent
+func listCAPI(ctx context.Context, client dynamic.Interface, resource, namespace string, opts metav1.ListOptions) ([]unstructured.Unstructured, string, bool, error) {
+	for _, version := range capiVersions {
+		gvr := schema.GroupVersionResource{Group: capiGroup, Version: version, Resource: resource}
+		list, err := client.Resource(gvr).Namespace(namespace).List(ctx, opts)
+		if err == nil {
+			return list.Items, version, true, nil
+		}
+		if !apierrors.IsNotFound(err) {
+			return nil, "", true, fmt.Errorf("failed to list %s: %w", resource, err)
+		}
+	}
+	return nil, "", false, nil
+}
+
+// clusterSelector narrows a list to one workload cluster by its cluster-name label
+func clusterSelector(clusterName string) metav1.ListOptions {
+	if clusterName == "" {
+		return metav1.ListOptions{}
+	}
+	return metav1.ListOptions{LabelSelector: clusterNameLabel + "=" + clusterName}
+}
+
+// condition returns the status and message of the first present condition type
+func condition(obj *unstructured.Unstructured, conditionTypes ...string) (string, string) {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, conditionType := range conditionTypes {
+		for _, entry := range conditions {
+			m, ok := entry.(map[string]interface{})
+			if !ok || m["type"] != conditionType {
+				continue
+			}
+			status, _ := m["status"].(string)
+			message, _ := m["message"].(string)
+			return status, message
+		}
+	}
+	return "", ""
+}
+
+// nestedInt64 reads an integer field whether it decoded as int64 or float64
+func nestedInt64(obj map[string]interface{}, fields ...string) (int64, bool) {
+	value, found, _ := unstructured.NestedFieldNoCopy(obj, fields...)
+	if !found {
+		return 0, false
+	}
+	switch v := value.(type) {
+	case int64:
+		return v, true
+	case float64:
+		return int64(v), true
+	}
+	return 0, false
+}
+
+// firstInt64 returns the first of several integer fields that is set
+func firstInt64(obj map[string]interface{}, paths ...[]string) int64 {
+	for _, path := range paths {
+		if v, ok := nestedInt64(obj, path...); ok {
+			return v
+		}
+	}
+	return 0
+}
+
+// firstString returns the first of several string fields that is non-empty
+func firstString(obj map[string]interface{}, paths ...[]string) string {
+	for _, path := range paths {
+		if v, _, _ := unstructured.NestedString(obj, path...); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// firstBool returns the first of several boolean fields that is set
+func firstBool(obj map[string]interface{}, paths ...[]string) bool {
+	for _, path := range paths {
+		if v, found, _ := unstructured.NestedBool(obj, path...); found {
+			return v
+		}
+	}
+	return false
+}
+
+// paused reports whether reconciliation is paused via spec.paused or the paused annotation
+func paused(obj *unstructured.Unstructured) bool {
+	if _, ok := obj.GetAnnotations()["cluster.x-k8s.io/paused"]; ok {
+		return true
+	}
+	p, _, _ := unstructured.NestedBool(obj.Object, "spec", "paused")
+	return p
+}
+
+// failureMessage returns the terminal failure reported by v1beta1 objects, including the
+// deprecated fields v1beta2 keeps for compatibility
+func failureMessage(obj *unstructured.Unstructured) (string, string) {
+	reason := firstString(obj.Object, []string{"status", "failureReason"}, []string{"status", "deprecated", "v1beta1", "failureReason"})
+	message := firstString(obj.Object, []string{"status", "failureMessage"}, []string{"status", "deprecated", "v1beta1", "failureMessage"})
+	return reason, message
+}
+
+// transformCluster builds a CAPICluster; machine counts are filled in by the caller
+func transformCluster(obj *unstructured.Unstructured) CAPICluster {
+	cluster := CAPICluster{
+		Name:            obj.GetName(),
+		Namespace:       obj.GetNamespace(),
+		MachinesByPhase: map[string]int{},
+		Paused:          paused(obj),
+		CreatedAt:       obj.GetCreationTimestamp().Format(time.RFC3339),
+	}
+	cluster.Phase, _, _ = unstructured.NestedString(obj.Object, "status", "phase")
+	if cluster.Phase == "" {
+		cluster.Phase = "Unknown"
+	}
+	cluster.ClusterClass = firstString(obj.Object, []string{"spec", "topology", "classRef", "name"}, []string{"spec", "topology", "class"})
+	cluster.KubernetesVersion, _, _ = unstructured.NestedString(obj.Object, "spec", "topology", "version")
+	cluster.InfrastructureKind, _, _ = unstructured.NestedString(obj.Object, "spec", "infrastructureRef", "kind")
+	cluster.ControlPlaneKind, _, _ = unstructured.NestedString(obj.Object, "spec", "controlPlaneRef", "kind")
+	cluster.InfrastructureReady = firstBool(obj.Object, []string{"status", "initialization", "infrastructureProvisioned"}, []string{"status", "infrastructureReady"})
+	cluster.ControlPlaneReady = firstBool(obj.Object, []string{"status", "initialization", "controlPlaneInitialized"}, []string{"status", "controlPlaneReady"})
+
+	ready, message := condition(obj, "Available", "Ready")
+	_, failure := failureMessage(obj)
+	switch {
+	case cluster.Phase == "Failed" || failure != "":
+		cluster.Health = healthFailed
+		cluster.Message = failure
+	case cluster.Phase == "Provisioned" && cluster.InfrastructureReady && cluster.ControlPlaneReady && ready != "False":
+		cluster.Health = healthHealthy
+	case cluster.Phase == "Provisioned" && ready == "False":
+		cluster.Health = healthDegraded
+	default:
+		cluster.Health = healthProgressing
+	}
+	if cluster.Message == "" && ready != "True" {
+		cluster.Message = message
+	}
+	return cluster
+}
+
+// machineDeploymentPhase derives a phase for objects whose status does not report one
+func machineDeploymentPhase(replicas, ready, upToDate int64) string {
+	switch {
+	case ready < replicas:
+		return "ScalingUp"
+	case ready > replicas:
+		return "ScalingDown"
+	case upToDate < replicas:
+		return "Updating"
+	default:
+		return "Running"
+	}
+}
+
+// transformMachineDeployment builds a MachineDeployment summary
+func transformMachineDeployment(obj *unstructured.Unstructured) MachineDeployment {
+	md := MachineDeployment{
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+		Paused:    paused(obj),
+		CreatedAt: obj.GetCreationTimestamp().Format(time.RFC3339),
+	}
+	md.ClusterName, _, _ = unstructured.NestedString(obj.Object, "spec", "clusterName")
+	md.KubernetesVersion, _, _ = unstructured.NestedString(obj.Object, "spec", "template", "spec", "version")
+	md.InfrastructureKind, _, _ = unstructured.NestedString(obj.Object, "spec", "template", "spec", "infrastructureRef", "kind")
+	md.Replicas = firstInt64(obj.Object, []string{"spec", "replicas"}, []string{"status", "replicas"})
+	md.ReadyReplicas = firstInt64(obj.Object, []string{"status", "v1beta2", "readyReplicas"}, []string{"status", "readyReplicas"})
+	md.AvailableReplicas = firstInt64(obj.Object, []string{"status", "v1beta2", "availableReplicas"}, []string{"status", "availableReplicas"})
+	md.UpToDateReplicas = firstInt64(obj.Object, []string{"status", "upToDateReplicas"}, []string{"status", "v1beta2", "upToDateReplicas"}, []string{"status", "updatedReplicas"})
+
+	annotations := obj.GetAnnotations()
+	if v, err := strconv.ParseInt(annotations[autoscalerMinAnnotation], 10, 64); err == nil {
+		md.AutoscalerMin = &v
+	}
+	if v, err := strconv.ParseInt(annotations[autoscalerMaxAnnotation], 10, 64); err == nil {
+		md.AutoscalerMax = &v
+	}
+
+	md.Phase, _, _ = unstructured.NestedString(obj.Object, "status", "phase")
+	if md.Phase == "" {
+		md.Phase = machineDeploymentPhase(md.Replicas, md.ReadyReplicas, md.UpToDateReplicas)
+	}
+	_, failure := failureMessage(obj)
+	available, message := condition(obj, "Available", "Ready")
+	switch {
+	case md.Phase == "Failed" || failure != "":
+		md.Health = healthFailed
+		md.Message = failure
+	case md.AvailableReplicas < md.Replicas && available == "False":
+		md.Health = healthDegraded
+	case md.ReadyReplicas != md.Replicas || md.UpToDateReplicas < md.Replicas:
+		md.Health = healthProgressing
+	default:
+		md.Health = healthHealthy
+	}
+	if md.Message == "" && available != "True" {
+		md.Message = message
+	}
+	return md
+}
+
+// transformMachine builds a Machine summary
+func transformMachine(obj *unstructured.Unstructured) Machine {
+	labels := obj.GetLabels()
+	_, controlPlane := labels[controlPlaneLabel]
+	machine := Machine{
+		Name:              obj.GetName(),
+		Namespace:         obj.GetNamespace(),
+		MachineDeployment: labels[deploymentNameLabel],
+		ControlPlane:      controlPlane,
+		CreatedAt:         obj.GetCreationTimestamp().Format(time.RFC3339),
+	}
+	machine.ClusterName, _, _ = unstructured.NestedString(obj.Object, "spec", "clusterName")
+	machine.KubernetesVersion, _, _ = unstructured.NestedString(obj.Object, "spec", "version")
+	machine.ProviderID, _, _ = unstructured.NestedString(obj.Object, "spec", "providerID")
+	machine.NodeName, _, _ = unstructured.NestedString(obj.Object, "status", "nodeRef", "name")
+	machine.Phase, _, _ = unstructured.NestedString(obj.Object, "status", "phase")
+	if machine.Phase == "" {
+		machine.Phase = "Unknown"
+	}
+
+	var failure string
+	machine.FailureReason, failure = failureMessage(obj)
+	ready, message := condition(obj, "Ready")
+	switch {
+	case machine.Phase == "Failed" || failure != "":
+		machine.Health = healthFailed
+		machine.Message = failure
+	case machine.Phase == "Running" && ready != "False":
+		machine.Health = healthHealthy
+	case machine.Phase == "Running":
+		machine.Health = healthDegraded
+	default:
+		machine.Health = healthProgressing
+	}
+	if machine.Message == "" && ready != "True" {
+		machine.Message = message
+	}
+	return machine
+}
+
+// healthRank orders the least healthy objects first
+func healthRank(health string) int {
+	switch health {
+	case healthFailed:
+		return 0
+	case healthDegraded:
+		return 1
+	case healthProgressing:
+		return 2
+	}
+	return 3
+}
+
+// GetClusters lists Cluster API Clusters with their provisioning state and machines
+// @Summary List Cluster API clusters
+// @Description Detects Cluster API and lists Clusters with phase, infrastructure and control plane readiness, ClusterClass topology and machine counts by phase. Installed is false when the Cluster API CRDs are absent.
+// @Tags ClusterAPI
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param namespace query string false "Namespace to filter (empty for all namespaces)"
+// @Success 200 {object} ClustersResponse "Cluster API clusters"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/clusterapi/clusters [get]
+func (h *ClusterAPIHandler) GetClusters(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	client, err := h.getDynamicClient(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for Cluster API clusters")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
+
+	namespace := c.Query("namespace")
+
+	_, listSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "clusters.cluster.x-k8s.io", namespace)
+	defer listSpan.End()
+
+	response := ClustersResponse{Summary: HealthSummary{ByPhase: map[string]int{}}, Clusters: []CAPICluster{}}
+	items, version, installed, err := listCAPI(c.Request.Context(), client, "clusters", namespace, metav1.ListOptions{})
+	if err == nil && installed {
+		var machines []unstructured.Unstructured
+		machines, _, _, err = listCAPI(c.Request.Context(), client, "machines", namespace, metav1.ListOptions{})
+		if err == nil {
+			response.Installed, response.APIVersion = true, capiGroup+"/"+version
+			response.Clusters = buildClusters(items, machines)
+		}
+	}
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list Cluster API clusters")
+		h.tracingHelper.RecordError(listSpan, err, "Failed to list Cluster API clusters")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	for _, cluster := range response.Clusters {
+		response.Summary.add(cluster.Health, cluster.Phase)
+	}
+	h.tracingHelper.AddResourceAttributes(listSpan, "", "clusters.cluster.x-k8s.io", len(response.Clusters))
+	h.tracingHelper.RecordSuccess(listSpan, fmt.Sprintf("Listed %d Cluster API clusters", len(response.Clusters)))
+
+	c.JSON(http.StatusOK, response)
+}
+
+// buildClusters summarizes clusters with the machines that belong to each, least healthy first
+func buildClusters(items, machines []unstructured.Unstructured) []CAPICluster {
+	clusters := make([]CAPICluster, 0, len(items))
+	index := map[string]int{}
+	for i := range items {
+		cluster := transformCluster(&items[i])
+		index[cluster.Namespace+"/"+cluster.Name] = len(clusters)
+		clusters = append(clusters, cluster)
+	}
+	for i := range machines {
+		machine := transformMachine(&machines[i])
+		if idx, ok := index[machine.Namespace+"/"+machine.ClusterName]; ok {
+			clusters[idx].Machines++
+			clusters[idx].MachinesByPhase[machine.Phase]++
+		}
+	}
+	sort.SliceStable(clusters, func(i, j int) bool {
+		a, b := clusters[i], clusters[j]
+		if healthRank(a.Health) != healthRank(b.Health) {
+			return healthRank(a.Health) < healthRank(b.Health)
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return clusters
+}
+
+// GetMachineDeployments lists Cluster API MachineDeployments with replica health
+// @Summary List Cluster API machine deployments
+// @Description Lists MachineDeployments with desired, ready, available and up-to-date replicas, phase, health and cluster autoscaler bounds, optionally for one workload cluster
+// @Tags ClusterAPI
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param namespace query string false "Namespace to filter (empty for all namespaces)"
+// @Param clusterName query string false "Only machine deployments of this Cluster API cluster"
+// @Success 200 {object} MachineDeploymentsResponse "Machine deployments"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/clusterapi/machinedeployments [get]
+func (h *ClusterAPIHandler) GetMachineDeployments(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	client, err := h.getDynamicClient(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for machine deployments")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
+
+	namespace := c.Query("namespace")
+
+	_, listSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "machinedeployments", namespace)
+	defer listSpan.End()
+
+	items, version, installed, err := listCAPI(c.Request.Context(), client, "machinedeployments", namespace, clusterSelector(c.Query("clusterName")))
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list machine deployments")
+		h.tracingHelper.RecordError(listSpan, err, "Failed to list machine deployments")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := MachineDeploymentsResponse{Installed: installed, Summary: HealthSummary{ByPhase: map[string]int{}}, MachineDeployments: []MachineDeployment{}}
+	if installed {
+		response.APIVersion = capiGroup + "/" + version
+	}
+	for i := range items {
+		md := transformMachineDeployment(&items[i])
+		response.Summary.add(md.Health, md.Phase)
+		response.MachineDeployments = append(response.MachineDeployments, md)
+	}
+	sort.SliceStable(response.MachineDeployments, func(i, j int) bool {
+		a, b := response.MachineDeployments[i], response.MachineDeployments[j]
+		if healthRank(a.Health) != healthRank(b.Health) {
+			return healthRank(a.Health) < healthRank(b.Health)
+		}
+		if a.ClusterName != b.ClusterName {
+			return a.ClusterName < b.ClusterName
+		}
+		return a.Name < b.Name
+	})
+	h.tracingHelper.AddResourceAttributes(listSpan, "", "machinedeployments", len(items))
+	h.tracingHelper.RecordSuccess(listSpan, fmt.Sprintf("Listed %d machine deployments", len(items)))
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetMachines lists Cluster API Machines with their phase and node
+// @Summary List Cluster API machines
+// @Description Lists Machines with phase, health, node and provider ID, optionally for one workload cluster or MachineDeployment
+// @Tags ClusterAPI
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param namespace query string false "Namespace to filter (empty for all namespaces)"
+// @Param clusterName query string false "Only machines of this Cluster API cluster"
+// @Param machineDeployment query string false "Only machines of this MachineDeployment"
+// @Success 200 {object} MachinesResponse "Machines"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/clusterapi/machines [get]
+func (h *ClusterAPIHandler) GetMachines(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	client, err := h.getDynamicClient(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for machines")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
+
+	namespace := c.Query("namespace")
+	opts := clusterSelector(c.Query("clusterName"))
+	if md := c.Query("machineDeployment"); md != "" {
+		selector := deploymentNameLabel + "=" + md
+		if opts.LabelSelector != "" {
+			selector = opts.LabelSelector + "," + selector
+		}
+		opts.LabelSelector = selector
+	}
+
+	_, listSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "machines", namespace)
+	defer listSpan.End()
+
+	items, version, installed, err := listCAPI(c.Request.Context(), client, "machines", namespace, opts)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list machines")
+		h.tracingHelper.RecordError(listSpan, err, "Failed to list machines")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := MachinesResponse{Installed: installed, Summary: HealthSummary{ByPhase: map[string]int{}}, Machines: []Machine{}}
+	if installed {
+		response.APIVersion = capiGroup + "/" + version
+	}
+	for i := range items {
+		machine := transformMachine(&items[i])
+		response.Summary.add(machine.Health, machine.Phase)
+		response.Machines = append(response.Machines, machine)
+	}
+	sort.SliceStable(response.Machines, func(i, j int) bool {
+		a, b := response.Machines[i], response.Machines[j]
+		if healthRank(a.Health) != healthRank(b.Health) {
+			return healthRank(a.Health) < healthRank(b.Health)
+		}
+		if a.ClusterName != b.ClusterName {
+			return a.ClusterName < b.ClusterName
+		}
+		return a.Name < b.Name
+	})
+	h.tracingHelper.AddResourceAttributes(listSpan, "", "machines", len(items))
+	h.tracingHelper.RecordSuccess(listSpan, fmt.Sprintf("Listed %d machines", len(items)))
+
+	c.JSON(http.StatusOK, response)
+}
+
+// checkAutoscalerBounds rejects replica counts the cluster autoscaler would immediately undo
+func checkAutoscalerBounds(md MachineDeployment, replicas int64) error {
+	if md.AutoscalerMin != nil && replicas < *md.AutoscalerMin {
+		return fmt.Errorf("replicas %d is below the cluster autoscaler minimum of %d", replicas, *md.AutoscalerMin)
+	}
+	if md.AutoscalerMax != nil && replicas > *md.AutoscalerMax {
+		return fmt.Errorf("replicas %d is above the cluster autoscaler maximum of %d", replicas, *md.AutoscalerMax)
+	}
+	return nil
+}
+
+// ScaleMachineDeployment sets the desired replicas of a MachineDeployment
+// @Summary Scale a Cluster API machine deployment
+// @Description Sets spec.replicas of a MachineDeployment. Counts outside the cluster autoscaler bounds annotated on the MachineDeployment are rejected, since the autoscaler would revert them.
+// @Tags ClusterAPI
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param namespace query string true "MachineDeployment namespace"
+// @Param name path string true "MachineDeployment name"
+// @Param body body object{replicas=int} true "Desired replicas"
+// @Success 200 {object} MachineDeployment "Scaled machine deployment"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters or outside autoscaler bounds"
+// @Failure 404 {object} map[string]string "MachineDeployment not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/clusterapi/machinedeployments/{name}/scale [post]
+func (h *ClusterAPIHandler) ScaleMachineDeployment(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	client, err := h.getDynamicClient(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for scaling machine deployment")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
+
+	name := c.Param("name")
+	namespace := c.Query("namespace")
+	if namespace == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		return
+	}
+	var body struct {
+		Replicas *int64 `json:"replicas"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || body.Replicas == nil || *body.Replicas < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "replicas must be a non-negative integer"})
+		return
+	}
+	replicas := *body.Replicas
+
+	_, scaleSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "scale", "machinedeployments", namespace)
+	defer scaleSpan.End()
+
+	for _, version := range capiVersions {
+		resource := client.Resource(schema.GroupVersionResource{Group: capiGroup, Version: version, Resource: "machinedeployments"}).Namespace(namespace)
+		obj, err := resource.Get(c.Request.Context(), name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			h.logger.WithError(err).WithField("machineDeployment", name).Error("Failed to get machine deployment")
+			h.tracingHelper.RecordError(scaleSpan, err, "Failed to get machine deployment")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if err := checkAutoscalerBounds(transformMachineDeployment(obj), replicas); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		patch := []byte(fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas))
+		scaled, err := resource.Patch(c.Request.Context(), name, types.MergePatchType, patch, metav1.PatchOptions{})
+		if err != nil {
+			h.logger.WithError(err).WithField("machineDeployment", name).Error("Failed to scale machine deployment")
+			h.tracingHelper.RecordError(scaleSpan, err, "Failed to scale machine deployment")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		h.tracingHelper.AddResourceAttributes(scaleSpan, name, "machinedeployments", int(replicas))
+		h.tracingHelper.RecordSuccess(scaleSpan, fmt.Sprintf("Scaled machine deployment to %d replicas", replicas))
+
+		h.logger.WithField("machineDeployment", name).WithField("namespace", namespace).WithField("replicas", replicas).Info("Scaled machine deployment")
+		c.JSON(http.StatusOK, transformMachineDeployment(scaled))
+		return
+	}
+
+	c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("MachineDeployment %s/%s not found", namespace, name)})
+}
@@ -0,0 +1,90 @@
+package clusterapi
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func object(namespace, name string, labels, annotations map[string]string, spec, status map[string]interface{}) unstructured.Unstructured {
+	obj := unstructured.Unstructured{Object: map[string]interface{}{"spec": spec, "status": status}}
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetLabels(labels)
+	obj.SetAnnotations(annotations)
+	return obj
+}
+
+func TestTransformMachineDeployment(t *testing.T) {
+	obj := object("fleet", "workers", nil,
+		map[string]string{autoscalerMinAnnotation: "2", autoscalerMaxAnnotation: "5"},
+		map[string]interface{}{"clusterName": "prod", "replicas": int64(3)},
+		map[string]interface{}{"phase": "ScalingUp", "readyReplicas": int64(2), "availableReplicas": int64(2), "updatedReplicas": int64(3)},
+	)
+	md := transformMachineDeployment(&obj)
+	if md.ClusterName != "prod" || md.Replicas != 3 || md.ReadyReplicas != 2 || md.UpToDateReplicas != 3 {
+		t.Fatalf("unexpected machine deployment %+v", md)
+	}
+	if md.Health != healthProgressing {
+		t.Errorf("health = %q, want %q", md.Health, healthProgressing)
+	}
+	if md.AutoscalerMin == nil || *md.AutoscalerMin != 2 || md.AutoscalerMax == nil || *md.AutoscalerMax != 5 {
+		t.Errorf("autoscaler bounds not read: %+v", md)
+	}
+	if err := checkAutoscalerBounds(md, 1); err == nil {
+		t.Error("expected replicas below the autoscaler minimum to be rejected")
+	}
+	if err := checkAutoscalerBounds(md, 6); err == nil {
+		t.Error("expected replicas above the autoscaler maximum to be rejected")
+	}
+	if err := checkAutoscalerBounds(md, 4); err != nil {
+		t.Errorf("replicas within bounds rejected: %v", err)
+	}
+}
+
+func TestBuildClustersCountsMachines(t *testing.T) {
+	clusters := []unstructured.Unstructured{
+		object("fleet", "healthy", nil, nil,
+			map[string]interface{}{"topology": map[string]interface{}{"class": "aws-default", "version": "v1.30.2"}},
+			map[string]interface{}{"phase": "Provisioned", "infrastructureReady": true, "controlPlaneReady": true},
+		),
+		object("fleet", "broken", nil, nil,
+			map[string]interface{}{},
+			map[string]interface{}{"phase": "Failed", "failureMessage": "VPC quota exceeded"},
+		),
+	}
+	machines := []unstructured.Unstructured{
+		object("fleet", "healthy-cp-0", map[string]string{controlPlaneLabel: ""}, nil,
+			map[string]interface{}{"clusterName": "healthy"},
+			map[string]interface{}{"phase": "Running", "nodeRef": map[string]interface{}{"name": "ip-10-0-0-1"}},
+		),
+		object("fleet", "healthy-md-0", map[string]string{deploymentNameLabel: "workers"}, nil,
+			map[string]interface{}{"clusterName": "healthy"},
+			map[string]interface{}{"phase": "Provisioning"},
+		),
+		object("other", "healthy-md-1", nil, nil,
+			map[string]interface{}{"clusterName": "healthy"},
+			map[string]interface{}{"phase": "Running"},
+		),
+	}
+
+	got := buildClusters(clusters, machines)
+	if len(got) != 2 || got[0].Name != "broken" {
+		t.Fatalf("expected failed cluster first, got %+v", got)
+	}
+	if got[0].Health != healthFailed || got[0].Message != "VPC quota exceeded" {
+		t.Errorf("failed cluster = %+v", got[0])
+	}
+	healthy := got[1]
+	if healthy.Health != healthHealthy || healthy.ClusterClass != "aws-default" || healthy.KubernetesVersion != "v1.30.2" {
+		t.Errorf("healthy cluster = %+v", healthy)
+	}
+	if healthy.Machines != 2 || healthy.MachinesByPhase["Running"] != 1 || healthy.MachinesByPhase["Provisioning"] != 1 {
+		t.Errorf("machines in another namespace should not count: %+v", healthy)
+	}
+
+	machine := transformMachine(&machines[0])
+	if !machine.ControlPlane || machine.NodeName != "ip-10-0-0-1" || machine.Health != healthHealthy {
+		t.Errorf("control plane machine = %+v", machine)
+	}
+}
@@ -0,0 +1,293 @@
+package compare
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/internal/tracing"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/restmapper"
+)
+
+// defaultKinds are compared when the caller does not choose kinds
+var defaultKinds = []string{"deployments", "statefulsets", "daemonsets", "cronjobs", "services", "ingresses", "configmaps", "horizontalpodautoscalers"}
+
+// Side identifies one side of a comparison
+type Side struct {
+	ConfigID  string `json:"configId"`
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ObjectDiff lists the differing fields of an object present on both sides
+type ObjectDiff struct {
+	Name  string      `json:"name"`
+	Diffs []FieldDiff `json:"diffs"`
+}
+
+// KindComparison is the comparison result for one kind
+type KindComparison struct {
+	Kind      string       `json:"kind"`
+	Resource  string       `json:"resource"`
+	OnlyLeft  []string     `json:"onlyLeft"`
+	OnlyRight []string     `json:"onlyRight"`
+	Different []ObjectDiff `json:"different"`
+	Identical int          `json:"identical"`
+	Error     string       `json:"error,omitempty"`
+}
+
+// ComparisonSummary totals the comparison across kinds
+type ComparisonSummary struct {
+	OnlyLeft  int  `json:"onlyLeft"`
+	OnlyRight int  `json:"onlyRight"`
+	Different int  `json:"different"`
+	Identical int  `json:"identical"`
+	InParity  bool `json:"inParity"`
+}
+
+// ComparisonResult is the response of a cluster comparison
+type ComparisonResult struct {
+	Left    Side              `json:"left"`
+	Right   Side              `json:"right"`
+	Ignore  []string          `json:"ignore,omitempty"`
+	Kinds   []KindComparison  `json:"kinds"`
+	Summary ComparisonSummary `json:"summary"`
+}
+
+// CompareHandler compares resources between clusters or namespaces
+type CompareHandler struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	logger        *logger.Logger
+	tracingHelper *tracing.TracingHelper
+}
+
+// NewCompareHandler creates a new cluster comparison handler
+func NewCompareHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger) *CompareHandler {
+	return &CompareHandler{
+		store:         store,
+		clientFactory: clientFactory,
+		logger:        log,
+		tracingHelper: tracing.GetTracingHelper(),
+	}
+}
+
+// sideClients holds the clients for one side of a comparison
+type sideClients struct {
+	client  kubernetes.Interface
+	dynamic dynamic.Interface
+	mapper  meta.RESTMapper
+}
+
+// getSideClients gets the dynamic client and REST mapper for a config and cluster
+func (h *CompareHandler) getSideClients(side Side) (*sideClients, error) {
+	if side.ConfigID == "" {
+		return nil, fmt.Errorf("config parameter is required")
+	}
+
+	config, err := h.store.GetKubeConfig(side.ConfigID)
+	if err != nil {
+		return nil, fmt.Errorf("config not found: %w", err)
+	}
+
+	client, err := h.clientFactory.GetClientForConfig(config, side.Cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Kubernetes client: %w", err)
+	}
+	dynamicClient, err := h.clientFactory.GetDynamicClientForConfig(config, side.Cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dynamic client: %w", err)
+	}
+
+	shared, discovery, err := h.clientFactory.GetRESTMapperForConfig(config, side.Cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get REST mapper: %w", err)
+	}
+	mapper := restmapper.NewShortcutExpander(shared, discovery, nil)
+	return &sideClients{client: client, dynamic: dynamicClient, mapper: mapper}, nil
+}
+
+// builtinControllers own objects whose names and specs are generated per cluster
+var builtinControllers = map[string]bool{"Deployment": true, "ReplicaSet": true, "StatefulSet": true, "DaemonSet": true, "Job": true, "CronJob": true, "Service": true}
+
+// ownedByBuiltinController reports whether an object is generated by a built-in controller
+// (ReplicaSets, Jobs, Pods, EndpointSlices) and so mirrors an object compared elsewhere
+func ownedByBuiltinController(refs []metav1.OwnerReference) bool {
+	for _, ref := range refs {
+		if ref.Controller != nil && *ref.Controller && builtinControllers[ref.Kind] {
+			return true
+		}
+	}
+	return false
+}
+
+// listNormalized lists a resource on one side and returns normalized objects keyed by name,
+// or namespace/name when comparing across all namespaces
+func listNormalized(ctx context.Context, clients *sideClients, mapping *meta.RESTMapping, namespace string) (map[string]map[string]interface{}, error) {
+	ri := clients.dynamic.Resource(mapping.Resource)
+	var resource dynamic.ResourceInterface = ri
+	namespaced := mapping.Scope.Name() == meta.RESTScopeNameNamespace
+	if namespaced && namespace != "" {
+		resource = ri.Namespace(namespace)
+	}
+
+	list, err := resource.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make(map[string]map[string]interface{}, len(list.Items))
+	for _, item := range list.Items {
+		key := item.GetName()
+		if namespaced && namespace == "" {
+			key = item.GetNamespace() + "/" + key
+		}
+		if ownedByBuiltinController(item.GetOwnerReferences()) {
+			continue
+		}
+		objects[key] = Normalize(item.Object, mapping.GroupVersionKind.Kind)
+	}
+	return objects, nil
+}
+
+// compareKind compares one kind between both sides
+func compareKind(ctx context.Context, left, right *sideClients, leftSide, rightSide Side, kind string, ignore []string) KindComparison {
+	result := KindComparison{Kind: kind, OnlyLeft: []string{}, OnlyRight: []string{}, Different: []ObjectDiff{}}
+
+	mapping, err := resolveMapping(left.mapper, kind)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Kind = mapping.GroupVersionKind.Kind
+	result.Resource = mapping.Resource.Resource
+
+	leftObjects, err := listNormalized(ctx, left, mapping, leftSide.Namespace)
+	if err != nil {
+		result.Error = fmt.Sprintf("left: %v", err)
+		return result
+	}
+	rightObjects, err := listNormalized(ctx, right, mapping, rightSide.Namespace)
+	if err != nil {
+		result.Error = fmt.Sprintf("right: %v", err)
+		return result
+	}
+
+	for key, leftObj := range leftObjects {
+		rightObj, ok := rightObjects[key]
+		if !ok {
+			result.OnlyLeft = append(result.OnlyLeft, key)
+			continue
+		}
+		if diffs := Diff(leftObj, rightObj, ignore); len(diffs) > 0 {
+			result.Different = append(result.Different, ObjectDiff{Name: key, Diffs: diffs})
+		} else {
+			result.Identical++
+		}
+	}
+	for key := range rightObjects {
+		if _, ok := leftObjects[key]; !ok {
+			result.OnlyRight = append(result.OnlyRight, key)
+		}
+	}
+
+	sort.Strings(result.OnlyLeft)
+	sort.Strings(result.OnlyRight)
+	sort.Slice(result.Different, func(i, j int) bool { return result.Different[i].Name < result.Different[j].Name })
+	return result
+}
+
+// splitList parses a comma-separated query value
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// CompareClusters compares resources between two clusters or namespaces
+// @Summary Compare clusters
+// @Description Compares the chosen kinds between two clusters, or two namespaces across clusters, reporting objects present on only one side and field-level diffs for shared objects. Server-populated fields (status, uids, resource versions, cluster IPs, node ports, managed fields) are ignored and Secret values are compared by digest.
+// @Tags Cluster
+// @Accept json
+// @Produce json
+// @Param leftConfig query string true "Kubernetes config ID of the left side"
+// @Param leftCluster query string false "Cluster name of the left side"
+// @Param leftNamespace query string false "Namespace of the left side (all namespaces when empty)"
+// @Param rightConfig query string false "Kubernetes config ID of the right side (defaults to leftConfig)"
+// @Param rightCluster query string false "Cluster name of the right side"
+// @Param rightNamespace query string false "Namespace of the right side (defaults to leftNamespace)"
+// @Param kinds query string false "Comma-separated kinds, resources or short names (default deployments,statefulsets,daemonsets,cronjobs,services,ingresses,configmaps,horizontalpodautoscalers)"
+// @Param ignore query string false "Comma-separated field paths to ignore, e.g. spec.replicas,metadata.labels"
+// @Success 200 {object} ComparisonResult "Comparison result"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/compare [get]
+func (h *CompareHandler) CompareClusters(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-compare-clients")
+	defer clientSpan.End()
+
+	left := Side{ConfigID: c.Query("leftConfig"), Cluster: c.Query("leftCluster"), Namespace: c.Query("leftNamespace")}
+	right := Side{ConfigID: c.DefaultQuery("rightConfig", left.ConfigID), Cluster: c.Query("rightCluster"), Namespace: c.DefaultQuery("rightNamespace", left.Namespace)}
+	if left == right {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "left and right sides must differ in config, cluster or namespace"})
+		return
+	}
+	if (left.Namespace == "") != (right.Namespace == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "either both sides or neither side must set a namespace"})
+		return
+	}
+
+	leftClients, err := h.getSideClients(left)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get left clients for comparison")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get left clients")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "left: " + err.Error()})
+		return
+	}
+	rightClients, err := h.getSideClients(right)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get right clients for comparison")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get right clients")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "right: " + err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Comparison clients obtained")
+
+	kinds := splitList(c.Query("kinds"))
+	if len(kinds) == 0 {
+		kinds = defaultKinds
+	}
+	result := ComparisonResult{Left: left, Right: right, Ignore: splitList(c.Query("ignore"))}
+
+	_, compareSpan := h.tracingHelper.StartDataProcessingSpan(ctx, "compare-clusters")
+	defer compareSpan.End()
+
+	for _, kind := range kinds {
+		kc := compareKind(c.Request.Context(), leftClients, rightClients, left, right, kind, result.Ignore)
+		result.Summary.OnlyLeft += len(kc.OnlyLeft)
+		result.Summary.OnlyRight += len(kc.OnlyRight)
+		result.Summary.Different += len(kc.Different)
+		result.Summary.Identical += kc.Identical
+		result.Kinds = append(result.Kinds, kc)
+	}
+	result.Summary.InParity = result.Summary.OnlyLeft == 0 && result.Summary.OnlyRight == 0 && result.Summary.Different == 0
+
+	h.tracingHelper.RecordSuccess(compareSpan, fmt.Sprintf("Compared %d kinds", len(kinds)))
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,211 @@
+package compare
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// workloadSpecDefaults are the values the API server fills into workload specs when a manifest omits them
+var workloadSpecDefaults = map[string]map[string]interface{}{
+	"Deployment": {
+		"replicas":                1,
+		"revisionHistoryLimit":    10,
+		"progressDeadlineSeconds": 600,
+		"strategy": map[string]interface{}{
+			"type":          "RollingUpdate",
+			"rollingUpdate": map[string]interface{}{"maxSurge": "25%", "maxUnavailable": "25%"},
+		},
+	},
+	"StatefulSet": {
+		"replicas":             1,
+		"revisionHistoryLimit": 10,
+		"podManagementPolicy":  "OrderedReady",
+		"updateStrategy": map[string]interface{}{
+			"type":          "RollingUpdate",
+			"rollingUpdate": map[string]interface{}{"partition": 0},
+		},
+		"persistentVolumeClaimRetentionPolicy": map[string]interface{}{"whenDeleted": "Retain", "whenScaled": "Retain"},
+	},
+	"DaemonSet": {
+		"revisionHistoryLimit": 10,
+		"updateStrategy": map[string]interface{}{
+			"type":          "RollingUpdate",
+			"rollingUpdate": map[string]interface{}{"maxSurge": 0, "maxUnavailable": 1},
+		},
+	},
+	"CronJob": {
+		"concurrencyPolicy":          "Allow",
+		"suspend":                    false,
+		"successfulJobsHistoryLimit": 3,
+		"failedJobsHistoryLimit":     1,
+	},
+	"Job": jobSpecDefaults,
+}
+
+var jobSpecDefaults = map[string]interface{}{
+	"backoffLimit":   6,
+	"completions":    1,
+	"parallelism":    1,
+	"completionMode": "NonIndexed",
+	"suspend":        false,
+}
+
+var podSpecDefaults = map[string]interface{}{
+	"restartPolicy":                 "Always",
+	"terminationGracePeriodSeconds": 30,
+	"dnsPolicy":                     "ClusterFirst",
+	"schedulerName":                 "default-scheduler",
+	"enableServiceLinks":            true,
+	"securityContext":               map[string]interface{}{},
+}
+
+var containerDefaults = map[string]interface{}{
+	"terminationMessagePath":   "/dev/termination-log",
+	"terminationMessagePolicy": "File",
+	"resources":                map[string]interface{}{},
+}
+
+var probeDefaults = map[string]interface{}{
+	"timeoutSeconds":   1,
+	"periodSeconds":    10,
+	"successThreshold": 1,
+	"failureThreshold": 3,
+}
+
+// StripDefaults removes fields of a normalized workload that hold the value the API server
+// would default them to, so a manifest that omits them matches the live object. Numbers are
+// converted to their JSON representation first so decoded YAML and live objects compare equal.
+func StripDefaults(obj map[string]interface{}, kind string) map[string]interface{} {
+	obj = jsonValue(obj).(map[string]interface{})
+	spec, ok := obj["spec"].(map[string]interface{})
+	if !ok {
+		return obj
+	}
+	removeDefaults(spec, workloadSpecDefaults[kind])
+
+	switch kind {
+	case "CronJob":
+		if jobTemplate, ok := spec["jobTemplate"].(map[string]interface{}); ok {
+			if jobSpec, ok := jobTemplate["spec"].(map[string]interface{}); ok {
+				removeDefaults(jobSpec, jobSpecDefaults)
+				stripPodTemplateDefaults(jobSpec)
+			}
+		}
+	case "Deployment", "StatefulSet", "DaemonSet", "Job", "ReplicaSet":
+		stripPodTemplateDefaults(spec)
+	}
+	return obj
+}
+
+func stripPodTemplateDefaults(spec map[string]interface{}) {
+	template, ok := spec["template"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	podSpec, ok := template["spec"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	removeDefaults(podSpec, podSpecDefaults)
+	// serviceAccount is the deprecated alias the API server mirrors from serviceAccountName
+	if podSpec["serviceAccount"] == podSpec["serviceAccountName"] {
+		delete(podSpec, "serviceAccount")
+	}
+
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, _ := podSpec[field].([]interface{})
+		for _, c := range containers {
+			if container, ok := c.(map[string]interface{}); ok {
+				stripContainerDefaults(container)
+			}
+		}
+	}
+	volumes, _ := podSpec["volumes"].([]interface{})
+	for _, v := range volumes {
+		volume, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, source := range []string{"configMap", "secret"} {
+			if s, ok := volume[source].(map[string]interface{}); ok {
+				removeDefaults(s, map[string]interface{}{"defaultMode": 420})
+			}
+		}
+	}
+}
+
+func stripContainerDefaults(container map[string]interface{}) {
+	removeDefaults(container, containerDefaults)
+	if image, _ := container["image"].(string); container["imagePullPolicy"] == defaultPullPolicy(image) {
+		delete(container, "imagePullPolicy")
+	}
+	ports, _ := container["ports"].([]interface{})
+	for _, p := range ports {
+		if port, ok := p.(map[string]interface{}); ok {
+			removeDefaults(port, map[string]interface{}{"protocol": "TCP"})
+		}
+	}
+	for _, field := range []string{"livenessProbe", "readinessProbe", "startupProbe"} {
+		probe, ok := container[field].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		removeDefaults(probe, probeDefaults)
+		if httpGet, ok := probe["httpGet"].(map[string]interface{}); ok {
+			removeDefaults(httpGet, map[string]interface{}{"scheme": "HTTP"})
+		}
+	}
+}
+
+// defaultPullPolicy is the pull policy the API server assigns to an image without an explicit one
+func defaultPullPolicy(image string) string {
+	if strings.Contains(image, "@") {
+		return "IfNotPresent"
+	}
+	name := image[strings.LastIndex(image, "/")+1:]
+	if !strings.Contains(name, ":") || strings.HasSuffix(name, ":latest") {
+		return "Always"
+	}
+	return "IfNotPresent"
+}
+
+// removeDefaults deletes fields equal to their default; nested defaults are removed field by field
+// and the parent is dropped once nothing but defaults remained
+func removeDefaults(obj map[string]interface{}, defaults map[string]interface{}) {
+	for key, def := range defaults {
+		value, ok := obj[key]
+		if !ok {
+			continue
+		}
+		if nested, ok := def.(map[string]interface{}); ok && len(nested) > 0 {
+			if m, ok := value.(map[string]interface{}); ok {
+				removeDefaults(m, nested)
+				if len(m) == 0 {
+					delete(obj, key)
+				}
+			}
+			continue
+		}
+		if m, ok := value.(map[string]interface{}); ok && len(m) == 0 {
+			delete(obj, key)
+			continue
+		}
+		if fmt.Sprint(jsonValue(value)) == fmt.Sprint(jsonValue(def)) {
+			delete(obj, key)
+		}
+	}
+}
+
+// jsonValue round-trips a value through JSON so integers decoded from live objects and YAML alike become float64
+func jsonValue(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return v
+	}
+	return out
+}
@@ -0,0 +1,218 @@
+package compare
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// FieldDiff is a single differing field between two objects
+type FieldDiff struct {
+	Path  string      `json:"path"`
+	Left  interface{} `json:"left,omitempty"`
+	Right interface{} `json:"right,omitempty"`
+}
+
+// serverMetadataFields are populated by the API server and never meaningful across clusters
+var serverMetadataFields = []string{
+	"uid", "resourceVersion", "generation", "creationTimestamp", "managedFields",
+	"selfLink", "ownerReferences", "deletionTimestamp", "deletionGracePeriodSeconds", "generateName",
+}
+
+// serverAnnotations are written by controllers and tooling rather than by users
+var serverAnnotations = []string{
+	"kubectl.kubernetes.io/last-applied-configuration",
+	"deployment.kubernetes.io/revision",
+	"kubectl.kubernetes.io/restartedAt",
+}
+
+// serverSpecFields are allocated by the cluster and differ even for identical manifests
+var serverSpecFields = map[string][]string{
+	"Service":               {"clusterIP", "clusterIPs", "healthCheckNodePort"},
+	"PersistentVolumeClaim": {"volumeName"},
+	"Job":                   {"selector"},
+}
+
+// Normalize strips server-populated fields so objects from different clusters
+// can be compared. Secret values are replaced with digests so they are never returned.
+func Normalize(obj map[string]interface{}, kind string) map[string]interface{} {
+	delete(obj, "status")
+
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		for _, field := range serverMetadataFields {
+			delete(metadata, field)
+		}
+		// Namespaces are the comparison scope, not part of the object
+		delete(metadata, "namespace")
+		if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+			for _, a := range serverAnnotations {
+				delete(annotations, a)
+			}
+			if len(annotations) == 0 {
+				delete(metadata, "annotations")
+			}
+		}
+	}
+
+	if spec, ok := obj["spec"].(map[string]interface{}); ok {
+		for _, field := range serverSpecFields[kind] {
+			delete(spec, field)
+		}
+		if template, ok := spec["template"].(map[string]interface{}); ok {
+			if templateMetadata, ok := template["metadata"].(map[string]interface{}); ok {
+				delete(templateMetadata, "creationTimestamp")
+				if annotations, ok := templateMetadata["annotations"].(map[string]interface{}); ok {
+					delete(annotations, "kubectl.kubernetes.io/restartedAt")
+				}
+			}
+		}
+		if kind == "Service" {
+			// Node ports are allocated per cluster unless pinned
+			if ports, ok := spec["ports"].([]interface{}); ok {
+				for _, p := range ports {
+					if port, ok := p.(map[string]interface{}); ok {
+						delete(port, "nodePort")
+					}
+				}
+			}
+		}
+	}
+
+	if kind == "Secret" {
+		for _, field := range []string{"data", "stringData"} {
+			if data, ok := obj[field].(map[string]interface{}); ok {
+				for k, v := range data {
+					sum := sha256.Sum256([]byte(fmt.Sprint(v)))
+					data[k] = "sha256:" + hex.EncodeToString(sum[:8])
+				}
+			}
+		}
+	}
+
+	// kube-root-ca.crt is published into every namespace with the cluster's own CA
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok && kind == "ConfigMap" && metadata["name"] == "kube-root-ca.crt" {
+		delete(obj, "data")
+	}
+	return obj
+}
+
+// Diff returns the differing fields between two normalized values, skipping ignored path prefixes
+func Diff(left, right interface{}, ignore []string) []FieldDiff {
+	var diffs []FieldDiff
+	diffValues("", left, right, ignore, &diffs)
+	return diffs
+}
+
+func ignored(path string, ignore []string) bool {
+	for _, prefix := range ignore {
+		if path == prefix || strings.HasPrefix(path, prefix+".") || strings.HasPrefix(path, prefix+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+func diffValues(path string, left, right interface{}, ignore []string, diffs *[]FieldDiff) {
+	if ignored(path, ignore) {
+		return
+	}
+
+	leftMap, leftIsMap := left.(map[string]interface{})
+	rightMap, rightIsMap := right.(map[string]interface{})
+	if leftIsMap && rightIsMap {
+		keys := map[string]bool{}
+		for k := range leftMap {
+			keys[k] = true
+		}
+		for k := range rightMap {
+			keys[k] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+		for _, k := range sorted {
+			diffValues(joinPath(path, k), leftMap[k], rightMap[k], ignore, diffs)
+		}
+		return
+	}
+
+	leftList, leftIsList := left.([]interface{})
+	rightList, rightIsList := right.([]interface{})
+	if leftIsList && rightIsList {
+		leftNamed, lok := byName(leftList)
+		rightNamed, rok := byName(rightList)
+		if lok && rok {
+			// Lists of named items (containers, env, ports) are matched by name, not position
+			names := map[string]bool{}
+			for n := range leftNamed {
+				names[n] = true
+			}
+			for n := range rightNamed {
+				names[n] = true
+			}
+			sorted := make([]string, 0, len(names))
+			for n := range names {
+				sorted = append(sorted, n)
+			}
+			sort.Strings(sorted)
+			for _, n := range sorted {
+				diffValues(fmt.Sprintf("%s[name=%s]", path, n), leftNamed[n], rightNamed[n], ignore, diffs)
+			}
+			return
+		}
+		max := len(leftList)
+		if len(rightList) > max {
+			max = len(rightList)
+		}
+		for i := 0; i < max; i++ {
+			var l, r interface{}
+			if i < len(leftList) {
+				l = leftList[i]
+			}
+			if i < len(rightList) {
+				r = rightList[i]
+			}
+			diffValues(fmt.Sprintf("%s[%d]", path, i), l, r, ignore, diffs)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(left, right) {
+		*diffs = append(*diffs, FieldDiff{Path: path, Left: left, Right: right})
+	}
+}
+
+// byName indexes a list by each element's name field, failing if any element is unnamed
+func byName(list []interface{}) (map[string]interface{}, bool) {
+	if len(list) == 0 {
+		return map[string]interface{}{}, true
+	}
+	named := make(map[string]interface{}, len(list))
+	for _, item := range list {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		name, ok := m["name"].(string)
+		if !ok || name == "" {
+			return nil, false
+		}
+		if _, dup := named[name]; dup {
+			return nil, false
+		}
+		named[name] = item
+	}
+	return named, true
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
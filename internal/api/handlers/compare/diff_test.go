@@ -0,0 +1,114 @@
+package compare
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func decode(t *testing.T, s string) map[string]interface{} {
+	t.Helper()
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(s), &obj); err != nil {
+		t.Fatal(err)
+	}
+	return obj
+}
+
+func TestNormalizeAndDiff(t *testing.T) {
+	left := Normalize(decode(t, `{
+		"metadata": {"name": "api", "namespace": "staging", "uid": "a", "resourceVersion": "1",
+			"annotations": {"deployment.kubernetes.io/revision": "3"}},
+		"spec": {"replicas": 2, "template": {"spec": {"containers": [
+			{"name": "sidecar", "image": "envoy:1.29"},
+			{"name": "app", "image": "api:1.0", "env": [{"name": "MODE", "value": "x"}]}
+		]}}},
+		"status": {"readyReplicas": 2}
+	}`), "Deployment")
+	right := Normalize(decode(t, `{
+		"metadata": {"name": "api", "namespace": "prod", "uid": "b", "resourceVersion": "9"},
+		"spec": {"replicas": 6, "template": {"spec": {"containers": [
+			{"name": "app", "image": "api:1.1", "env": [{"name": "MODE", "value": "x"}]},
+			{"name": "sidecar", "image": "envoy:1.29"}
+		]}}}
+	}`), "Deployment")
+
+	diffs := Diff(left, right, nil)
+	paths := map[string]bool{}
+	for _, d := range diffs {
+		paths[d.Path] = true
+	}
+	if len(diffs) != 2 || !paths["spec.replicas"] || !paths["spec.template.spec.containers[name=app].image"] {
+		t.Fatalf("unexpected diffs: %+v", diffs)
+	}
+
+	if diffs := Diff(left, right, []string{"spec.replicas", "spec.template.spec.containers"}); len(diffs) != 0 {
+		t.Fatalf("expected ignored paths to be skipped, got %+v", diffs)
+	}
+}
+
+func TestNormalizeHidesSecretValues(t *testing.T) {
+	secret := Normalize(decode(t, `{"metadata": {"name": "db"}, "data": {"password": "c2VjcmV0"}}`), "Secret")
+	value := secret["data"].(map[string]interface{})["password"].(string)
+	if value == "c2VjcmV0" || len(value) != len("sha256:")+16 {
+		t.Fatalf("secret value not replaced by digest: %q", value)
+	}
+}
+
+func TestServiceAllocatedFieldsIgnored(t *testing.T) {
+	left := Normalize(decode(t, `{"spec": {"clusterIP": "10.0.0.1", "ports": [{"name": "http", "port": 80, "nodePort": 30001}]}}`), "Service")
+	right := Normalize(decode(t, `{"spec": {"clusterIP": "10.1.0.9", "ports": [{"name": "http", "port": 80, "nodePort": 31555}]}}`), "Service")
+	if diffs := Diff(left, right, nil); len(diffs) != 0 {
+		t.Fatalf("expected no diffs, got %+v", diffs)
+	}
+}
+
+func TestStripDefaultsMatchesManifestWithLiveObject(t *testing.T) {
+	manifest := decode(t, `{
+		"kind": "Deployment", "metadata": {"name": "api"},
+		"spec": {"template": {"spec": {"containers": [
+			{"name": "app", "image": "api:1.0", "ports": [{"containerPort": 8080}],
+			 "readinessProbe": {"httpGet": {"path": "/ready", "port": 8080}}}
+		]}}}
+	}`)
+	live := decode(t, `{
+		"kind": "Deployment", "metadata": {"name": "api", "annotations": {"meta.helm.sh/release-name": "api"}},
+		"spec": {"replicas": 1, "revisionHistoryLimit": 10, "progressDeadlineSeconds": 600,
+			"strategy": {"type": "RollingUpdate", "rollingUpdate": {"maxSurge": "25%", "maxUnavailable": "25%"}},
+			"template": {"spec": {"restartPolicy": "Always", "dnsPolicy": "ClusterFirst", "securityContext": {},
+				"schedulerName": "default-scheduler", "terminationGracePeriodSeconds": 30,
+				"containers": [
+					{"name": "app", "image": "api:1.0", "imagePullPolicy": "IfNotPresent", "resources": {},
+					 "terminationMessagePath": "/dev/termination-log", "terminationMessagePolicy": "File",
+					 "ports": [{"containerPort": 8080, "protocol": "TCP"}],
+					 "readinessProbe": {"httpGet": {"path": "/ready", "port": 8080, "scheme": "HTTP"},
+						"timeoutSeconds": 1, "periodSeconds": 10, "successThreshold": 1, "failureThreshold": 3}}
+				]}}}
+	}`)
+
+	left := StripDefaults(Normalize(manifest, "Deployment"), "Deployment")
+	right := stripHelmOwnership(StripDefaults(Normalize(live, "Deployment"), "Deployment"))
+	if diffs := Diff(left, right, nil); len(diffs) != 0 {
+		t.Fatalf("expected defaulted fields to be dropped, got %+v", diffs)
+	}
+
+	live["spec"].(map[string]interface{})["revisionHistoryLimit"] = 3
+	right = StripDefaults(Normalize(live, "Deployment"), "Deployment")
+	if diffs := Diff(left, right, []string{"metadata"}); len(diffs) != 1 || diffs[0].Path != "spec.revisionHistoryLimit" {
+		t.Fatalf("expected a non-default value to be reported, got %+v", diffs)
+	}
+}
+
+func TestDefaultPullPolicy(t *testing.T) {
+	for image, want := range map[string]string{
+		"nginx":                      "Always",
+		"nginx:latest":               "Always",
+		"nginx:1.27":                 "IfNotPresent",
+		"registry:5000/team/app":     "Always",
+		"registry:5000/team/app:1.0": "IfNotPresent",
+		"app@sha256:abc":             "IfNotPresent",
+	} {
+		if got := defaultPullPolicy(image); got != want {
+			t.Errorf("defaultPullPolicy(%q) = %q, want %q", image, got, want)
+		}
+	}
+}
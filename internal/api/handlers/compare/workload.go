@@ -0,0 +1,262 @@
+package compare
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	helmstorage "helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// WorkloadComparison is the field-level diff of one workload between two sides
+type WorkloadComparison struct {
+	Kind     string      `json:"kind"`
+	Resource string      `json:"resource"`
+	Name     string      `json:"name"`
+	Left     Side        `json:"left"`
+	Right    Side        `json:"right"`
+	Release  string      `json:"release,omitempty"`  // set when the left side is a Helm release revision
+	Revision int         `json:"revision,omitempty"` // Helm revision compared against the live object
+	Ignore   []string    `json:"ignore,omitempty"`
+	Diffs    []FieldDiff `json:"diffs"`
+	InParity bool        `json:"inParity"`
+}
+
+// resolveMapping resolves a kind, resource or short name to its REST mapping
+func resolveMapping(mapper meta.RESTMapper, kind string) (*meta.RESTMapping, error) {
+	gvr, err := mapper.ResourceFor(schema.GroupVersionResource{Resource: strings.ToLower(kind)})
+	if err != nil {
+		return nil, fmt.Errorf("unknown kind: %v", err)
+	}
+	gvk, err := mapper.KindFor(gvr)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+}
+
+// getNormalized fetches one object and normalizes it for comparison, defaulted fields included
+func getNormalized(ctx context.Context, clients *sideClients, mapping *meta.RESTMapping, namespace, name string) (map[string]interface{}, error) {
+	obj, err := clients.dynamic.Resource(mapping.Resource).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	kind := mapping.GroupVersionKind.Kind
+	return StripDefaults(Normalize(obj.Object, kind), kind), nil
+}
+
+// manifestObject finds a kind/name object in a rendered Helm manifest
+func manifestObject(manifest, kind, name string) (map[string]interface{}, error) {
+	for _, doc := range releaseutil.SplitManifests(manifest) {
+		var obj map[string]interface{}
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil || obj == nil {
+			continue
+		}
+		metadata, _ := obj["metadata"].(map[string]interface{})
+		if obj["kind"] == kind && metadata["name"] == name {
+			return obj, nil
+		}
+	}
+	return nil, fmt.Errorf("%s %s is not part of the release manifest", kind, name)
+}
+
+// helmOwnershipAnnotations are added by Helm to the live object at install time and never appear in the rendered manifest
+var helmOwnershipAnnotations = []string{"meta.helm.sh/release-name", "meta.helm.sh/release-namespace"}
+
+// stripHelmOwnership removes Helm's ownership annotations from a normalized live object
+func stripHelmOwnership(obj map[string]interface{}) map[string]interface{} {
+	metadata, _ := obj["metadata"].(map[string]interface{})
+	annotations, ok := metadata["annotations"].(map[string]interface{})
+	if !ok {
+		return obj
+	}
+	for _, a := range helmOwnershipAnnotations {
+		delete(annotations, a)
+	}
+	if len(annotations) == 0 {
+		delete(metadata, "annotations")
+	}
+	return obj
+}
+
+// CompareWorkloads compares a same-named workload between two namespaces
+// @Summary Compare a workload
+// @Description Diffs the spec of a same-named workload across two namespaces, possibly in different clusters. Server-populated fields are ignored and fields holding the value the API server defaults them to are dropped, so only meaningful differences are reported.
+// @Tags Cluster
+// @Produce json
+// @Param kind query string true "Kind, resource or short name, e.g. deployments"
+// @Param name query string true "Workload name"
+// @Param leftConfig query string true "Kubernetes config ID of the left side"
+// @Param leftCluster query string false "Cluster name of the left side"
+// @Param leftNamespace query string true "Namespace of the left side"
+// @Param rightConfig query string false "Kubernetes config ID of the right side (defaults to leftConfig)"
+// @Param rightCluster query string false "Cluster name of the right side"
+// @Param rightNamespace query string true "Namespace of the right side"
+// @Param ignore query string false "Comma-separated field paths to ignore, e.g. spec.replicas"
+// @Success 200 {object} WorkloadComparison "Workload comparison"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 404 {object} map[string]string "Workload not found on one side"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/compare/workload [get]
+func (h *CompareHandler) CompareWorkloads(c *gin.Context) {
+	kind, name := c.Query("kind"), c.Query("name")
+	left := Side{ConfigID: c.Query("leftConfig"), Cluster: c.Query("leftCluster"), Namespace: c.Query("leftNamespace")}
+	right := Side{ConfigID: c.DefaultQuery("rightConfig", left.ConfigID), Cluster: c.Query("rightCluster"), Namespace: c.Query("rightNamespace")}
+	if kind == "" || name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "kind and name parameters are required"})
+		return
+	}
+	if left.Namespace == "" || right.Namespace == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "leftNamespace and rightNamespace parameters are required"})
+		return
+	}
+	if left == right {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "left and right sides must differ in config, cluster or namespace"})
+		return
+	}
+
+	leftClients, err := h.getSideClients(left)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "left: " + err.Error()})
+		return
+	}
+	rightClients, err := h.getSideClients(right)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "right: " + err.Error()})
+		return
+	}
+	mapping, err := resolveMapping(leftClients.mapper, kind)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	leftObj, err := getNormalized(ctx, leftClients, mapping, left.Namespace, name)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "left: " + err.Error()})
+		return
+	}
+	rightObj, err := getNormalized(ctx, rightClients, mapping, right.Namespace, name)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "right: " + err.Error()})
+		return
+	}
+
+	result := WorkloadComparison{
+		Kind:     mapping.GroupVersionKind.Kind,
+		Resource: mapping.Resource.Resource,
+		Name:     name,
+		Left:     left,
+		Right:    right,
+		Ignore:   splitList(c.Query("ignore")),
+	}
+	result.Diffs = Diff(leftObj, rightObj, result.Ignore)
+	if result.Diffs == nil {
+		result.Diffs = []FieldDiff{}
+	}
+	result.InParity = len(result.Diffs) == 0
+	c.JSON(http.StatusOK, result)
+}
+
+// CompareHelmRevision compares a workload as rendered in a Helm release revision with the live object
+// @Summary Compare a workload with its Helm release
+// @Description Diffs a workload as rendered by a Helm release revision (the deployed revision by default) against the live object, dropping server-populated and defaulted fields so only drift and manual edits are reported. The left side of the result is the release manifest.
+// @Tags Cluster
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name"
+// @Param namespace query string true "Release namespace"
+// @Param release query string true "Helm release name"
+// @Param revision query int false "Release revision (defaults to the deployed revision)"
+// @Param kind query string true "Kind, resource or short name, e.g. deployments"
+// @Param name query string true "Workload name"
+// @Param ignore query string false "Comma-separated field paths to ignore, e.g. spec.replicas"
+// @Success 200 {object} WorkloadComparison "Workload comparison"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 404 {object} map[string]string "Release, revision or workload not found"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/compare/workload/helm [get]
+func (h *CompareHandler) CompareHelmRevision(c *gin.Context) {
+	side := Side{ConfigID: c.Query("config"), Cluster: c.Query("cluster"), Namespace: c.Query("namespace")}
+	releaseName, kind, name := c.Query("release"), c.Query("kind"), c.Query("name")
+	if side.Namespace == "" || releaseName == "" || kind == "" || name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace, release, kind and name parameters are required"})
+		return
+	}
+	revision := 0
+	if v := c.Query("revision"); v != "" {
+		r, err := strconv.Atoi(v)
+		if err != nil || r < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "revision must be a positive integer"})
+			return
+		}
+		revision = r
+	}
+
+	clients, err := h.getSideClients(side)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	mapping, err := resolveMapping(clients.mapper, kind)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Releases are read from Helm's secret storage in the release namespace
+	releases := helmstorage.Init(driver.NewSecrets(clients.client.CoreV1().Secrets(side.Namespace)))
+	var rel *release.Release
+	if revision > 0 {
+		rel, err = releases.Get(releaseName, revision)
+	} else if rel, err = releases.Deployed(releaseName); err != nil {
+		// A release whose latest upgrade failed has no deployed revision; fall back to the latest one
+		rel, err = releases.Last(releaseName)
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("release %s not found: %v", releaseName, err)})
+		return
+	}
+
+	kindName := mapping.GroupVersionKind.Kind
+	rendered, err := manifestObject(rel.Manifest, kindName, name)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	live, err := getNormalized(c.Request.Context(), clients, mapping, side.Namespace, name)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "live: " + err.Error()})
+		return
+	}
+
+	result := WorkloadComparison{
+		Kind:     kindName,
+		Resource: mapping.Resource.Resource,
+		Name:     name,
+		Left:     side,
+		Right:    side,
+		Release:  releaseName,
+		Revision: rel.Version,
+		Ignore:   splitList(c.Query("ignore")),
+	}
+	result.Diffs = Diff(StripDefaults(Normalize(rendered, kindName), kindName), stripHelmOwnership(live), result.Ignore)
+	if result.Diffs == nil {
+		result.Diffs = []FieldDiff{}
+	}
+	result.InParity = len(result.Diffs) == 0
+	c.JSON(http.StatusOK, result)
+}
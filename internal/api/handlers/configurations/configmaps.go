@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/Facets-cloud/kube-dash/internal/api/transformers"
@@ -15,6 +16,7 @@ import (
 	"github.com/Facets-cloud/kube-dash/pkg/logger"
 
 	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -44,7 +46,7 @@ func NewConfigMapsHandler(store *storage.KubeConfigStore, clientFactory *k8s.Cli
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *ConfigMapsHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *ConfigMapsHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -74,6 +76,12 @@ func (h *ConfigMapsHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clie
 // @Param config query string true "Kubernetes config ID"
 // @Param cluster query string false "Cluster name"
 // @Param namespace query string true "Namespace name"
+// @Param namespaces query string false "Comma-separated namespaces to list across, instead of namespace"
+// @Param namespaceGroup query string false "ID of a saved namespace group to list across"
+// @Param fields query string false "Comma-separated response fields to return, e.g. name,status,restarts; name, namespace and uid are always included"
+// @Param labelSelector query string false "Kubernetes label selector, e.g. app=web,tier!=cache"
+// @Param sort query string false "Response field to sort by, e.g. name or -restarts for descending"
+// @Param view query string false "ID of a saved view supplying namespaces, labelSelector, sort and fields"
 // @Success 200 {array} types.ConfigMapListResponse "List of transformed ConfigMaps"
 // @Failure 400 {object} map[string]string "Bad request"
 // @Failure 500 {object} map[string]string "Internal server error"
@@ -89,22 +97,30 @@ func (h *ConfigMapsHandler) GetConfigMaps(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for configmaps")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get client for configmaps")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed for configmaps")
 
-	namespace := c.Query("namespace")
+	namespaces := utils.RequestedNamespaces(c)
+	namespace := strings.Join(namespaces, ",")
 
 	// Start child span for Kubernetes API call
 	_, k8sSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "configmap", namespace)
 	defer k8sSpan.End()
 
-	configMapList, err := client.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{})
+	items, err := utils.ListInNamespaces(ctx, namespaces, func(ctx context.Context, namespace string) ([]corev1.ConfigMap, error) {
+		list, err := client.CoreV1().ConfigMaps(namespace).List(ctx, utils.ListOptions(c))
+		if err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	})
+	configMapList := &corev1.ConfigMapList{Items: items}
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to list configmaps")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to list configmaps")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(k8sSpan, "configmaps", "configmap", len(configMapList.Items))
@@ -121,7 +137,7 @@ func (h *ConfigMapsHandler) GetConfigMaps(c *gin.Context) {
 	}
 	h.tracingHelper.RecordSuccess(processSpan, "Successfully transformed configmaps data")
 
-	c.JSON(http.StatusOK, transformedConfigMaps)
+	h.sseHandler.SendJSON(c, transformedConfigMaps)
 }
 
 // GetConfigMapsSSE returns configmaps as Server-Sent Events with real-time updates
@@ -134,6 +150,12 @@ func (h *ConfigMapsHandler) GetConfigMaps(c *gin.Context) {
 // @Param config query string true "Kubernetes config ID"
 // @Param cluster query string false "Cluster name"
 // @Param namespace query string true "Namespace name"
+// @Param namespaces query string false "Comma-separated namespaces to list across, instead of namespace"
+// @Param namespaceGroup query string false "ID of a saved namespace group to list across"
+// @Param fields query string false "Comma-separated response fields to return, e.g. name,status,restarts; name, namespace and uid are always included"
+// @Param labelSelector query string false "Kubernetes label selector, e.g. app=web,tier!=cache"
+// @Param sort query string false "Response field to sort by, e.g. name or -restarts for descending"
+// @Param view query string false "ID of a saved view supplying namespaces, labelSelector, sort and fields"
 // @Success 200 {array} types.ConfigMapListResponse "Stream of transformed ConfigMaps or JSON array"
 // @Failure 400 {object} map[string]string "Bad request"
 // @Failure 500 {object} map[string]string "Internal server error"
@@ -154,7 +176,8 @@ func (h *ConfigMapsHandler) GetConfigMapsSSE(c *gin.Context) {
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed for configmaps SSE")
 
-	namespace := c.Query("namespace")
+	namespaces := utils.RequestedNamespaces(c)
+	namespace := strings.Join(namespaces, ",")
 
 	// Function to fetch and transform configmaps data
 	fetchConfigMaps := func() (interface{}, error) {
@@ -166,7 +189,14 @@ func (h *ConfigMapsHandler) GetConfigMapsSSE(c *gin.Context) {
 		_, k8sSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "configmap", namespace)
 		defer k8sSpan.End()
 
-		configMapList, err := client.CoreV1().ConfigMaps(namespace).List(fetchCtx, metav1.ListOptions{})
+		items, err := utils.ListInNamespaces(fetchCtx, namespaces, func(ctx context.Context, namespace string) ([]corev1.ConfigMap, error) {
+			list, err := client.CoreV1().ConfigMaps(namespace).List(ctx, utils.ListOptions(c))
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		configMapList := &corev1.ConfigMapList{Items: items}
 		if err != nil {
 			h.tracingHelper.RecordError(k8sSpan, err, "Failed to list configmaps")
 			return nil, err
@@ -211,7 +241,7 @@ func (h *ConfigMapsHandler) GetConfigMapsSSE(c *gin.Context) {
 	}
 
 	// For non-SSE requests, return JSON
-	c.JSON(http.StatusOK, initialData)
+	h.sseHandler.SendJSON(c, initialData)
 }
 
 // GetConfigMap returns a specific configmap
@@ -240,7 +270,7 @@ func (h *ConfigMapsHandler) GetConfigMap(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for configmap")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get client for configmap")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed for configmap")
@@ -256,14 +286,14 @@ func (h *ConfigMapsHandler) GetConfigMap(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("configmap", name).WithField("namespace", namespace).Error("Failed to get configmap")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get configmap")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(k8sSpan, name, "configmap", 1)
 	h.tracingHelper.RecordSuccess(k8sSpan, "Successfully retrieved configmap")
 
 	// Always send SSE format for detail endpoints since they're used by EventSource
-	h.sseHandler.SendSSEResponse(c, configMap)
+	h.sseHandler.SendSSEDetailResponse(c, configMap, configMap, client.CoreV1().ConfigMaps(namespace).Watch)
 }
 
 // GetConfigMapByName returns a specific configmap by name using namespace from query parameters
@@ -292,7 +322,7 @@ func (h *ConfigMapsHandler) GetConfigMapByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for configmap")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get client for configmap")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed for configmap")
@@ -302,7 +332,7 @@ func (h *ConfigMapsHandler) GetConfigMapByName(c *gin.Context) {
 
 	if namespace == "" {
 		h.logger.WithField("configmap", name).Error("Namespace is required for configmap lookup")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -314,14 +344,14 @@ func (h *ConfigMapsHandler) GetConfigMapByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("configmap", name).WithField("namespace", namespace).Error("Failed to get configmap")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get configmap")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(k8sSpan, name, "configmap", 1)
 	h.tracingHelper.RecordSuccess(k8sSpan, "Successfully retrieved configmap by name")
 
 	// Always send SSE format for detail endpoints since they're used by EventSource
-	h.sseHandler.SendSSEResponse(c, configMap)
+	h.sseHandler.SendSSEDetailResponse(c, configMap, configMap, client.CoreV1().ConfigMaps(namespace).Watch)
 }
 
 // GetConfigMapYAMLByName returns the YAML representation of a specific configmap by name using namespace from query parameters
@@ -349,7 +379,7 @@ func (h *ConfigMapsHandler) GetConfigMapYAMLByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for configmap YAML")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get client for configmap YAML")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed for configmap YAML")
@@ -359,7 +389,7 @@ func (h *ConfigMapsHandler) GetConfigMapYAMLByName(c *gin.Context) {
 
 	if namespace == "" {
 		h.logger.WithField("configmap", name).Error("Namespace is required for configmap YAML lookup")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -371,7 +401,7 @@ func (h *ConfigMapsHandler) GetConfigMapYAMLByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("configmap", name).WithField("namespace", namespace).Error("Failed to get configmap for YAML")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get configmap for YAML")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(k8sSpan, name, "configmap", 1)
@@ -410,7 +440,7 @@ func (h *ConfigMapsHandler) GetConfigMapYAML(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for configmap YAML")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get client for configmap YAML")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed for configmap YAML")
@@ -426,7 +456,7 @@ func (h *ConfigMapsHandler) GetConfigMapYAML(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("configmap", name).WithField("namespace", namespace).Error("Failed to get configmap for YAML")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get configmap for YAML")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(k8sSpan, name, "configmap", 1)
@@ -463,7 +493,7 @@ func (h *ConfigMapsHandler) GetConfigMapEventsByName(c *gin.Context) {
 
 	if namespace == "" {
 		h.logger.WithField("configmap", name).Error("Namespace is required for configmap events lookup")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -475,7 +505,7 @@ func (h *ConfigMapsHandler) GetConfigMapEventsByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for configmap events")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get client for configmap events")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed for configmap events")
@@ -517,7 +547,7 @@ func (h *ConfigMapsHandler) GetConfigMapEvents(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for configmap events")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get client for configmap events")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed for configmap events")
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/Facets-cloud/kube-dash/internal/api/transformers"
@@ -15,6 +16,7 @@ import (
 	"github.com/Facets-cloud/kube-dash/pkg/logger"
 
 	"github.com/gin-gonic/gin"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -44,7 +46,7 @@ func NewHPAsHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFac
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *HPAsHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *HPAsHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -91,7 +93,7 @@ func (h *HPAsHandler) GetHPA(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for HPA")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -107,7 +109,7 @@ func (h *HPAsHandler) GetHPA(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("hpa", name).WithField("namespace", namespace).Error("Failed to get HPA")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get HPA")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(k8sSpan, "Successfully retrieved HPA")
@@ -116,7 +118,7 @@ func (h *HPAsHandler) GetHPA(c *gin.Context) {
 	// Check if this is an SSE request (EventSource expects SSE format)
 	acceptHeader := c.GetHeader("Accept")
 	if acceptHeader == "text/event-stream" {
-		h.sseHandler.SendSSEResponse(c, hpa)
+		h.sseHandler.SendSSEDetailResponse(c, hpa, hpa, client.AutoscalingV2().HorizontalPodAutoscalers(namespace).Watch)
 		return
 	}
 
@@ -149,7 +151,7 @@ func (h *HPAsHandler) GetHPAByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for HPA")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -159,7 +161,7 @@ func (h *HPAsHandler) GetHPAByName(c *gin.Context) {
 	if namespace == "" {
 		h.logger.WithField("hpa", name).Error("Namespace is required for HPA lookup")
 		h.tracingHelper.RecordError(clientSpan, fmt.Errorf("namespace parameter is required"), "Namespace parameter is required")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -172,7 +174,7 @@ func (h *HPAsHandler) GetHPAByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("hpa", name).WithField("namespace", namespace).Error("Failed to get HPA")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get HPA")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(k8sSpan, "Successfully retrieved HPA")
@@ -181,7 +183,7 @@ func (h *HPAsHandler) GetHPAByName(c *gin.Context) {
 	// Check if this is an SSE request (EventSource expects SSE format)
 	acceptHeader := c.GetHeader("Accept")
 	if acceptHeader == "text/event-stream" {
-		h.sseHandler.SendSSEResponse(c, hpa)
+		h.sseHandler.SendSSEDetailResponse(c, hpa, hpa, client.AutoscalingV2().HorizontalPodAutoscalers(namespace).Watch)
 		return
 	}
 
@@ -213,7 +215,7 @@ func (h *HPAsHandler) GetHPAYAMLByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for HPA YAML")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -223,7 +225,7 @@ func (h *HPAsHandler) GetHPAYAMLByName(c *gin.Context) {
 	if namespace == "" {
 		h.logger.WithField("hpa", name).Error("Namespace is required for HPA YAML lookup")
 		h.tracingHelper.RecordError(clientSpan, fmt.Errorf("namespace parameter is required"), "Namespace parameter is required")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -236,7 +238,7 @@ func (h *HPAsHandler) GetHPAYAMLByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("hpa", name).WithField("namespace", namespace).Error("Failed to get HPA for YAML")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get HPA for YAML")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(k8sSpan, "Successfully retrieved HPA for YAML")
@@ -275,7 +277,7 @@ func (h *HPAsHandler) GetHPAYAML(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for HPA YAML")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -291,7 +293,7 @@ func (h *HPAsHandler) GetHPAYAML(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("hpa", name).WithField("namespace", namespace).Error("Failed to get HPA for YAML")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get HPA for YAML")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(k8sSpan, "Successfully retrieved HPA for YAML")
@@ -328,7 +330,7 @@ func (h *HPAsHandler) GetHPAEventsByName(c *gin.Context) {
 
 	if namespace == "" {
 		h.logger.WithField("hpa", name).Error("Namespace is required for HPA events lookup")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -340,7 +342,7 @@ func (h *HPAsHandler) GetHPAEventsByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for HPA events")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -383,7 +385,7 @@ func (h *HPAsHandler) GetHPAEvents(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for HPA events")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -406,6 +408,12 @@ func (h *HPAsHandler) GetHPAEvents(c *gin.Context) {
 // @Param config query string true "Kubernetes config ID"
 // @Param cluster query string false "Cluster name"
 // @Param namespace query string true "Namespace name"
+// @Param namespaces query string false "Comma-separated namespaces to list across, instead of namespace"
+// @Param namespaceGroup query string false "ID of a saved namespace group to list across"
+// @Param fields query string false "Comma-separated response fields to return, e.g. name,status,restarts; name, namespace and uid are always included"
+// @Param labelSelector query string false "Kubernetes label selector, e.g. app=web,tier!=cache"
+// @Param sort query string false "Response field to sort by, e.g. name or -restarts for descending"
+// @Param view query string false "ID of a saved view supplying namespaces, labelSelector, sort and fields"
 // @Success 200 {array} types.HPAListResponse "List of transformed HPAs"
 // @Failure 400 {object} map[string]string "Bad request"
 // @Failure 500 {object} map[string]string "Internal server error"
@@ -421,22 +429,30 @@ func (h *HPAsHandler) GetHPAs(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for HPAs")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
 
-	namespace := c.Query("namespace")
+	namespaces := utils.RequestedNamespaces(c)
+	namespace := strings.Join(namespaces, ",")
 
 	// Start child span for Kubernetes API call
 	_, k8sSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "hpas", namespace)
 	defer k8sSpan.End()
 
-	hpaList, err := client.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, metav1.ListOptions{})
+	items, err := utils.ListInNamespaces(ctx, namespaces, func(ctx context.Context, namespace string) ([]autoscalingv2.HorizontalPodAutoscaler, error) {
+		list, err := client.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, utils.ListOptions(c))
+		if err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	})
+	hpaList := &autoscalingv2.HorizontalPodAutoscalerList{Items: items}
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to list HPAs")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to list HPAs")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(k8sSpan, "Successfully listed HPAs")
@@ -454,7 +470,7 @@ func (h *HPAsHandler) GetHPAs(c *gin.Context) {
 	h.tracingHelper.RecordSuccess(processSpan, "Successfully transformed HPAs")
 	h.tracingHelper.AddResourceAttributes(processSpan, "transformed-hpas", "hpa", len(transformedHPAs))
 
-	c.JSON(http.StatusOK, transformedHPAs)
+	h.sseHandler.SendJSON(c, transformedHPAs)
 }
 
 // GetHPAsSSE returns HPAs as Server-Sent Events with real-time updates
@@ -467,6 +483,12 @@ func (h *HPAsHandler) GetHPAs(c *gin.Context) {
 // @Param config query string true "Kubernetes config ID"
 // @Param cluster query string false "Cluster name"
 // @Param namespace query string true "Namespace name"
+// @Param namespaces query string false "Comma-separated namespaces to list across, instead of namespace"
+// @Param namespaceGroup query string false "ID of a saved namespace group to list across"
+// @Param fields query string false "Comma-separated response fields to return, e.g. name,status,restarts; name, namespace and uid are always included"
+// @Param labelSelector query string false "Kubernetes label selector, e.g. app=web,tier!=cache"
+// @Param sort query string false "Response field to sort by, e.g. name or -restarts for descending"
+// @Param view query string false "ID of a saved view supplying namespaces, labelSelector, sort and fields"
 // @Success 200 {array} types.HPAListResponse "Stream of transformed HPAs or JSON array"
 // @Failure 400 {object} map[string]string "Bad request"
 // @Failure 500 {object} map[string]string "Internal server error"
@@ -487,7 +509,8 @@ func (h *HPAsHandler) GetHPAsSSE(c *gin.Context) {
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed for HPAs SSE")
 
-	namespace := c.Query("namespace")
+	namespaces := utils.RequestedNamespaces(c)
+	namespace := strings.Join(namespaces, ",")
 
 	// Function to fetch and transform HPAs data
 	fetchHPAs := func() (interface{}, error) {
@@ -499,7 +522,14 @@ func (h *HPAsHandler) GetHPAsSSE(c *gin.Context) {
 		fetchCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		hpaList, err := client.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(fetchCtx, metav1.ListOptions{})
+		items, err := utils.ListInNamespaces(fetchCtx, namespaces, func(ctx context.Context, namespace string) ([]autoscalingv2.HorizontalPodAutoscaler, error) {
+			list, err := client.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, utils.ListOptions(c))
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		hpaList := &autoscalingv2.HorizontalPodAutoscalerList{Items: items}
 		if err != nil {
 			h.tracingHelper.RecordError(fetchSpan, err, "Failed to fetch HPAs for SSE")
 			return nil, err
@@ -1,8 +1,10 @@
 package configurations
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/Facets-cloud/kube-dash/internal/api/transformers"
 	"github.com/Facets-cloud/kube-dash/internal/api/types"
@@ -13,6 +15,7 @@ import (
 	"github.com/Facets-cloud/kube-dash/pkg/logger"
 
 	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -42,7 +45,7 @@ func NewLimitRangesHandler(store *storage.KubeConfigStore, clientFactory *k8s.Cl
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *LimitRangesHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *LimitRangesHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -72,20 +75,28 @@ func (h *LimitRangesHandler) GetLimitRanges(c *gin.Context) {
 	if err != nil {
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
 		h.logger.WithError(err).Error("Failed to get client for limit ranges")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
-	namespace := c.Query("namespace")
+	namespaces := utils.RequestedNamespaces(c)
+	namespace := strings.Join(namespaces, ",")
 	h.tracingHelper.RecordSuccess(clientSpan, "Successfully obtained Kubernetes client")
 
 	// Start Kubernetes API call span
 	_, apiSpan := h.tracingHelper.StartKubernetesAPISpan(c.Request.Context(), "list", "limitranges", namespace)
 	defer apiSpan.End()
-	limitRangeList, err := client.CoreV1().LimitRanges(namespace).List(c.Request.Context(), metav1.ListOptions{})
+	items, err := utils.ListInNamespaces(c.Request.Context(), namespaces, func(ctx context.Context, namespace string) ([]corev1.LimitRange, error) {
+		list, err := client.CoreV1().LimitRanges(namespace).List(ctx, utils.ListOptions(c))
+		if err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	})
+	limitRangeList := &corev1.LimitRangeList{Items: items}
 	if err != nil {
 		h.tracingHelper.RecordError(apiSpan, err, "Failed to list limit ranges")
 		h.logger.WithError(err).Error("Failed to list limit ranges")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(apiSpan, "Successfully listed limit ranges")
@@ -117,7 +128,8 @@ func (h *LimitRangesHandler) GetLimitRangesSSE(c *gin.Context) {
 		h.sseHandler.SendSSEError(c, http.StatusBadRequest, err.Error())
 		return
 	}
-	namespace := c.Query("namespace")
+	namespaces := utils.RequestedNamespaces(c)
+	namespace := strings.Join(namespaces, ",")
 	h.tracingHelper.RecordSuccess(clientSpan, "Successfully obtained Kubernetes client")
 
 	// Function to fetch and transform limit ranges data
@@ -125,7 +137,14 @@ func (h *LimitRangesHandler) GetLimitRangesSSE(c *gin.Context) {
 		// Start data fetching span
 		_, fetchSpan := h.tracingHelper.StartKubernetesAPISpan(c.Request.Context(), "list", "limitranges", namespace)
 		defer fetchSpan.End()
-		limitRangeList, err := client.CoreV1().LimitRanges(namespace).List(c.Request.Context(), metav1.ListOptions{})
+		items, err := utils.ListInNamespaces(c.Request.Context(), namespaces, func(ctx context.Context, namespace string) ([]corev1.LimitRange, error) {
+			list, err := client.CoreV1().LimitRanges(namespace).List(ctx, utils.ListOptions(c))
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		limitRangeList := &corev1.LimitRangeList{Items: items}
 		if err != nil {
 			h.tracingHelper.RecordError(fetchSpan, err, "Failed to list limit ranges")
 			return nil, err
@@ -173,7 +192,7 @@ func (h *LimitRangesHandler) GetLimitRange(c *gin.Context) {
 	if err != nil {
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
 		h.logger.WithError(err).Error("Failed to get client for limit range")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	namespace := c.Param("namespace")
@@ -187,7 +206,7 @@ func (h *LimitRangesHandler) GetLimitRange(c *gin.Context) {
 	if err != nil {
 		h.tracingHelper.RecordError(apiSpan, err, "Failed to get limit range")
 		h.logger.WithError(err).WithField("limitrange", name).WithField("namespace", namespace).Error("Failed to get limit range")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(apiSpan, "Successfully retrieved limit range")
@@ -196,7 +215,7 @@ func (h *LimitRangesHandler) GetLimitRange(c *gin.Context) {
 	// Check if this is an SSE request (EventSource expects SSE format)
 	acceptHeader := c.GetHeader("Accept")
 	if acceptHeader == "text/event-stream" {
-		h.sseHandler.SendSSEResponse(c, limitRange)
+		h.sseHandler.SendSSEDetailResponse(c, limitRange, limitRange, client.CoreV1().LimitRanges(namespace).Watch)
 		return
 	}
 
@@ -212,7 +231,7 @@ func (h *LimitRangesHandler) GetLimitRangeByName(c *gin.Context) {
 	if err != nil {
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
 		h.logger.WithError(err).Error("Failed to get client for limit range")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -222,7 +241,7 @@ func (h *LimitRangesHandler) GetLimitRangeByName(c *gin.Context) {
 	if namespace == "" {
 		h.tracingHelper.RecordError(clientSpan, fmt.Errorf("namespace parameter is required"), "Missing namespace parameter")
 		h.logger.WithField("limitrange", name).Error("Namespace is required for limit range lookup")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Successfully obtained Kubernetes client")
@@ -234,7 +253,7 @@ func (h *LimitRangesHandler) GetLimitRangeByName(c *gin.Context) {
 	if err != nil {
 		h.tracingHelper.RecordError(apiSpan, err, "Failed to get limit range")
 		h.logger.WithError(err).WithField("limitrange", name).WithField("namespace", namespace).Error("Failed to get limit range")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(apiSpan, "Successfully retrieved limit range")
@@ -243,7 +262,7 @@ func (h *LimitRangesHandler) GetLimitRangeByName(c *gin.Context) {
 	// Check if this is an SSE request (EventSource expects SSE format)
 	acceptHeader := c.GetHeader("Accept")
 	if acceptHeader == "text/event-stream" {
-		h.sseHandler.SendSSEResponse(c, limitRange)
+		h.sseHandler.SendSSEDetailResponse(c, limitRange, limitRange, client.CoreV1().LimitRanges(namespace).Watch)
 		return
 	}
 
@@ -259,7 +278,7 @@ func (h *LimitRangesHandler) GetLimitRangeYAMLByName(c *gin.Context) {
 	if err != nil {
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
 		h.logger.WithError(err).Error("Failed to get client for limit range YAML")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -269,7 +288,7 @@ func (h *LimitRangesHandler) GetLimitRangeYAMLByName(c *gin.Context) {
 	if namespace == "" {
 		h.tracingHelper.RecordError(clientSpan, fmt.Errorf("namespace parameter is required"), "Missing namespace parameter")
 		h.logger.WithField("limitrange", name).Error("Namespace is required for limit range YAML lookup")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Successfully obtained Kubernetes client")
@@ -281,7 +300,7 @@ func (h *LimitRangesHandler) GetLimitRangeYAMLByName(c *gin.Context) {
 	if err != nil {
 		h.tracingHelper.RecordError(apiSpan, err, "Failed to get limit range for YAML")
 		h.logger.WithError(err).WithField("limitrange", name).WithField("namespace", namespace).Error("Failed to get limit range for YAML")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(apiSpan, "Successfully retrieved limit range for YAML")
@@ -303,7 +322,7 @@ func (h *LimitRangesHandler) GetLimitRangeYAML(c *gin.Context) {
 	if err != nil {
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
 		h.logger.WithError(err).Error("Failed to get client for limit range YAML")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	namespace := c.Param("namespace")
@@ -317,7 +336,7 @@ func (h *LimitRangesHandler) GetLimitRangeYAML(c *gin.Context) {
 	if err != nil {
 		h.tracingHelper.RecordError(apiSpan, err, "Failed to get limit range for YAML")
 		h.logger.WithError(err).WithField("limitrange", name).WithField("namespace", namespace).Error("Failed to get limit range for YAML")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(apiSpan, "Successfully retrieved limit range for YAML")
@@ -337,7 +356,7 @@ func (h *LimitRangesHandler) GetLimitRangeEventsByName(c *gin.Context) {
 
 	if namespace == "" {
 		h.logger.WithField("limitrange", name).Error("Namespace is required for limit range events lookup")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -348,7 +367,7 @@ func (h *LimitRangesHandler) GetLimitRangeEventsByName(c *gin.Context) {
 	if err != nil {
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
 		h.logger.WithError(err).Error("Failed to get client for limit range events")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Successfully obtained Kubernetes client")
@@ -373,7 +392,7 @@ func (h *LimitRangesHandler) GetLimitRangeEvents(c *gin.Context) {
 	if err != nil {
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
 		h.logger.WithError(err).Error("Failed to get client for limit range events")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Successfully obtained Kubernetes client")
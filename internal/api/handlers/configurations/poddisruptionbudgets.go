@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/Facets-cloud/kube-dash/internal/api/transformers"
@@ -15,6 +16,7 @@ import (
 	"github.com/Facets-cloud/kube-dash/pkg/logger"
 
 	"github.com/gin-gonic/gin"
+	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -44,7 +46,7 @@ func NewPodDisruptionBudgetsHandler(store *storage.KubeConfigStore, clientFactor
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *PodDisruptionBudgetsHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *PodDisruptionBudgetsHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -74,6 +76,12 @@ func (h *PodDisruptionBudgetsHandler) getClientAndConfig(c *gin.Context) (*kuber
 // @Param config query string true "Kubernetes config ID"
 // @Param cluster query string false "Cluster name"
 // @Param namespace query string true "Namespace name"
+// @Param namespaces query string false "Comma-separated namespaces to list across, instead of namespace"
+// @Param namespaceGroup query string false "ID of a saved namespace group to list across"
+// @Param fields query string false "Comma-separated response fields to return, e.g. name,status,restarts; name, namespace and uid are always included"
+// @Param labelSelector query string false "Kubernetes label selector, e.g. app=web,tier!=cache"
+// @Param sort query string false "Response field to sort by, e.g. name or -restarts for descending"
+// @Param view query string false "ID of a saved view supplying namespaces, labelSelector, sort and fields"
 // @Success 200 {array} types.PodDisruptionBudgetListResponse "List of transformed Pod Disruption Budgets"
 // @Failure 400 {object} map[string]string "Bad request"
 // @Failure 500 {object} map[string]string "Internal server error"
@@ -89,22 +97,30 @@ func (h *PodDisruptionBudgetsHandler) GetPodDisruptionBudgets(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for pod disruption budgets")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
 
-	namespace := c.Query("namespace")
+	namespaces := utils.RequestedNamespaces(c)
+	namespace := strings.Join(namespaces, ",")
 
 	// Start child span for Kubernetes API call
 	_, k8sSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "poddisruptionbudgets", namespace)
 	defer k8sSpan.End()
 
-	podDisruptionBudgetList, err := client.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{})
+	items, err := utils.ListInNamespaces(ctx, namespaces, func(ctx context.Context, namespace string) ([]policyv1.PodDisruptionBudget, error) {
+		list, err := client.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, utils.ListOptions(c))
+		if err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	})
+	podDisruptionBudgetList := &policyv1.PodDisruptionBudgetList{Items: items}
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to list pod disruption budgets")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to list pod disruption budgets")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(k8sSpan, "Successfully listed pod disruption budgets")
@@ -122,7 +138,7 @@ func (h *PodDisruptionBudgetsHandler) GetPodDisruptionBudgets(c *gin.Context) {
 	h.tracingHelper.RecordSuccess(processSpan, "Successfully transformed pod disruption budgets")
 	h.tracingHelper.AddResourceAttributes(processSpan, "transformed-poddisruptionbudgets", "poddisruptionbudget", len(transformedPodDisruptionBudgets))
 
-	c.JSON(http.StatusOK, transformedPodDisruptionBudgets)
+	h.sseHandler.SendJSON(c, transformedPodDisruptionBudgets)
 }
 
 // GetPodDisruptionBudgetsSSE returns pod disruption budgets as Server-Sent Events with real-time updates
@@ -135,6 +151,12 @@ func (h *PodDisruptionBudgetsHandler) GetPodDisruptionBudgets(c *gin.Context) {
 // @Param config query string true "Kubernetes config ID"
 // @Param cluster query string false "Cluster name"
 // @Param namespace query string true "Namespace name"
+// @Param namespaces query string false "Comma-separated namespaces to list across, instead of namespace"
+// @Param namespaceGroup query string false "ID of a saved namespace group to list across"
+// @Param fields query string false "Comma-separated response fields to return, e.g. name,status,restarts; name, namespace and uid are always included"
+// @Param labelSelector query string false "Kubernetes label selector, e.g. app=web,tier!=cache"
+// @Param sort query string false "Response field to sort by, e.g. name or -restarts for descending"
+// @Param view query string false "ID of a saved view supplying namespaces, labelSelector, sort and fields"
 // @Success 200 {array} types.PodDisruptionBudgetListResponse "Stream of transformed Pod Disruption Budgets or JSON array"
 // @Failure 400 {object} map[string]string "Bad request"
 // @Failure 500 {object} map[string]string "Internal server error"
@@ -155,7 +177,8 @@ func (h *PodDisruptionBudgetsHandler) GetPodDisruptionBudgetsSSE(c *gin.Context)
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed for pod disruption budgets SSE")
 
-	namespace := c.Query("namespace")
+	namespaces := utils.RequestedNamespaces(c)
+	namespace := strings.Join(namespaces, ",")
 
 	// Function to fetch and transform pod disruption budgets data
 	fetchPodDisruptionBudgets := func() (interface{}, error) {
@@ -167,7 +190,14 @@ func (h *PodDisruptionBudgetsHandler) GetPodDisruptionBudgetsSSE(c *gin.Context)
 		fetchCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		podDisruptionBudgetList, err := client.PolicyV1().PodDisruptionBudgets(namespace).List(fetchCtx, metav1.ListOptions{})
+		items, err := utils.ListInNamespaces(fetchCtx, namespaces, func(ctx context.Context, namespace string) ([]policyv1.PodDisruptionBudget, error) {
+			list, err := client.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, utils.ListOptions(c))
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		podDisruptionBudgetList := &policyv1.PodDisruptionBudgetList{Items: items}
 		if err != nil {
 			h.tracingHelper.RecordError(fetchSpan, err, "Failed to fetch pod disruption budgets for SSE")
 			return nil, err
@@ -233,7 +263,7 @@ func (h *PodDisruptionBudgetsHandler) GetPodDisruptionBudget(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for pod disruption budget")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -249,7 +279,7 @@ func (h *PodDisruptionBudgetsHandler) GetPodDisruptionBudget(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("poddisruptionbudget", name).WithField("namespace", namespace).Error("Failed to get pod disruption budget")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get pod disruption budget")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(k8sSpan, "Successfully retrieved pod disruption budget")
@@ -258,7 +288,7 @@ func (h *PodDisruptionBudgetsHandler) GetPodDisruptionBudget(c *gin.Context) {
 	// Check if this is an SSE request (EventSource expects SSE format)
 	acceptHeader := c.GetHeader("Accept")
 	if acceptHeader == "text/event-stream" {
-		h.sseHandler.SendSSEResponse(c, podDisruptionBudget)
+		h.sseHandler.SendSSEDetailResponse(c, podDisruptionBudget, podDisruptionBudget, client.PolicyV1().PodDisruptionBudgets(namespace).Watch)
 		return
 	}
 
@@ -275,7 +305,7 @@ func (h *PodDisruptionBudgetsHandler) GetPodDisruptionBudgetByName(c *gin.Contex
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for pod disruption budget")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -285,7 +315,7 @@ func (h *PodDisruptionBudgetsHandler) GetPodDisruptionBudgetByName(c *gin.Contex
 	if namespace == "" {
 		h.logger.WithField("poddisruptionbudget", name).Error("Namespace is required for pod disruption budget lookup")
 		h.tracingHelper.RecordError(clientSpan, fmt.Errorf("namespace parameter is required"), "Namespace parameter is required")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -298,7 +328,7 @@ func (h *PodDisruptionBudgetsHandler) GetPodDisruptionBudgetByName(c *gin.Contex
 	if err != nil {
 		h.logger.WithError(err).WithField("poddisruptionbudget", name).WithField("namespace", namespace).Error("Failed to get pod disruption budget")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get pod disruption budget")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(k8sSpan, "Successfully retrieved pod disruption budget")
@@ -307,7 +337,7 @@ func (h *PodDisruptionBudgetsHandler) GetPodDisruptionBudgetByName(c *gin.Contex
 	// Check if this is an SSE request (EventSource expects SSE format)
 	acceptHeader := c.GetHeader("Accept")
 	if acceptHeader == "text/event-stream" {
-		h.sseHandler.SendSSEResponse(c, podDisruptionBudget)
+		h.sseHandler.SendSSEDetailResponse(c, podDisruptionBudget, podDisruptionBudget, client.PolicyV1().PodDisruptionBudgets(namespace).Watch)
 		return
 	}
 
@@ -324,7 +354,7 @@ func (h *PodDisruptionBudgetsHandler) GetPodDisruptionBudgetYAMLByName(c *gin.Co
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for pod disruption budget YAML")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -334,7 +364,7 @@ func (h *PodDisruptionBudgetsHandler) GetPodDisruptionBudgetYAMLByName(c *gin.Co
 	if namespace == "" {
 		h.logger.WithField("poddisruptionbudget", name).Error("Namespace is required for pod disruption budget YAML lookup")
 		h.tracingHelper.RecordError(clientSpan, fmt.Errorf("namespace parameter is required"), "Namespace parameter is required")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -347,7 +377,7 @@ func (h *PodDisruptionBudgetsHandler) GetPodDisruptionBudgetYAMLByName(c *gin.Co
 	if err != nil {
 		h.logger.WithError(err).WithField("poddisruptionbudget", name).WithField("namespace", namespace).Error("Failed to get pod disruption budget for YAML")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get pod disruption budget for YAML")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(k8sSpan, "Successfully retrieved pod disruption budget for YAML")
@@ -371,7 +401,7 @@ func (h *PodDisruptionBudgetsHandler) GetPodDisruptionBudgetYAML(c *gin.Context)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for pod disruption budget YAML")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -387,7 +417,7 @@ func (h *PodDisruptionBudgetsHandler) GetPodDisruptionBudgetYAML(c *gin.Context)
 	if err != nil {
 		h.logger.WithError(err).WithField("poddisruptionbudget", name).WithField("namespace", namespace).Error("Failed to get pod disruption budget for YAML")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get pod disruption budget for YAML")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(k8sSpan, "Successfully retrieved pod disruption budget for YAML")
@@ -408,7 +438,7 @@ func (h *PodDisruptionBudgetsHandler) GetPodDisruptionBudgetEventsByName(c *gin.
 
 	if namespace == "" {
 		h.logger.WithField("poddisruptionbudget", name).Error("Namespace is required for pod disruption budget events lookup")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -420,7 +450,7 @@ func (h *PodDisruptionBudgetsHandler) GetPodDisruptionBudgetEventsByName(c *gin.
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for pod disruption budget events")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -447,7 +477,7 @@ func (h *PodDisruptionBudgetsHandler) GetPodDisruptionBudgetEvents(c *gin.Contex
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for pod disruption budget events")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
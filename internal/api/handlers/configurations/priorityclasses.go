@@ -44,7 +44,7 @@ func NewPriorityClassesHandler(store *storage.KubeConfigStore, clientFactory *k8
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *PriorityClassesHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *PriorityClassesHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -75,7 +75,7 @@ func (h *PriorityClassesHandler) GetPriorityClasses(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for priority classes")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -88,7 +88,7 @@ func (h *PriorityClassesHandler) GetPriorityClasses(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to list priority classes")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to list priority classes")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(k8sSpan, "Successfully listed priority classes")
@@ -171,14 +171,14 @@ func (h *PriorityClassesHandler) GetPriorityClass(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for priority class")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	name := c.Param("name")
 	if name == "" {
 		h.tracingHelper.RecordError(clientSpan, fmt.Errorf("priority class name is required"), "Priority class name is required")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "priority class name is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "priority class name is required")
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -191,7 +191,7 @@ func (h *PriorityClassesHandler) GetPriorityClass(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("name", name).Error("Failed to get priority class")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get priority class")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(k8sSpan, "Successfully retrieved priority class")
@@ -210,14 +210,14 @@ func (h *PriorityClassesHandler) GetPriorityClassByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for priority class by name")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	name := c.Param("name")
 	if name == "" {
 		h.tracingHelper.RecordError(clientSpan, fmt.Errorf("priority class name is required"), "Priority class name is required")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "priority class name is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "priority class name is required")
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -230,7 +230,7 @@ func (h *PriorityClassesHandler) GetPriorityClassByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("name", name).Error("Failed to get priority class by name")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get priority class by name")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(k8sSpan, "Successfully retrieved priority class by name")
@@ -249,14 +249,14 @@ func (h *PriorityClassesHandler) GetPriorityClassYAMLByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for priority class YAML by name")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	name := c.Param("name")
 	if name == "" {
 		h.tracingHelper.RecordError(clientSpan, fmt.Errorf("priority class name is required"), "Priority class name is required")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "priority class name is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "priority class name is required")
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -269,7 +269,7 @@ func (h *PriorityClassesHandler) GetPriorityClassYAMLByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("name", name).Error("Failed to get priority class for YAML by name")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get priority class for YAML by name")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(k8sSpan, "Successfully retrieved priority class for YAML by name")
@@ -293,14 +293,14 @@ func (h *PriorityClassesHandler) GetPriorityClassYAML(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for priority class YAML")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	name := c.Param("name")
 	if name == "" {
 		h.tracingHelper.RecordError(clientSpan, fmt.Errorf("priority class name is required"), "Priority class name is required")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "priority class name is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "priority class name is required")
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -313,7 +313,7 @@ func (h *PriorityClassesHandler) GetPriorityClassYAML(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("name", name).Error("Failed to get priority class for YAML")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get priority class for YAML")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(k8sSpan, "Successfully retrieved priority class for YAML")
@@ -337,14 +337,14 @@ func (h *PriorityClassesHandler) GetPriorityClassEventsByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for priority class events by name")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	name := c.Param("name")
 	if name == "" {
 		h.tracingHelper.RecordError(clientSpan, fmt.Errorf("priority class name is required"), "Priority class name is required")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "priority class name is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "priority class name is required")
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -368,14 +368,14 @@ func (h *PriorityClassesHandler) GetPriorityClassEvents(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for priority class events")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	name := c.Param("name")
 	if name == "" {
 		h.tracingHelper.RecordError(clientSpan, fmt.Errorf("priority class name is required"), "Priority class name is required")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "priority class name is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "priority class name is required")
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/Facets-cloud/kube-dash/internal/api/transformers"
@@ -16,6 +17,7 @@ import (
 	"github.com/Facets-cloud/kube-dash/pkg/logger"
 
 	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -45,7 +47,7 @@ func NewResourceQuotasHandler(store *storage.KubeConfigStore, clientFactory *k8s
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *ResourceQuotasHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *ResourceQuotasHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -76,22 +78,30 @@ func (h *ResourceQuotasHandler) GetResourceQuotas(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for resource quotas")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
 
-	namespace := c.Query("namespace")
+	namespaces := utils.RequestedNamespaces(c)
+	namespace := strings.Join(namespaces, ",")
 
 	// Start child span for Kubernetes API call
 	_, k8sSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "resourcequotas", namespace)
 	defer k8sSpan.End()
 
-	resourceQuotaList, err := client.CoreV1().ResourceQuotas(namespace).List(ctx, metav1.ListOptions{})
+	items, err := utils.ListInNamespaces(ctx, namespaces, func(ctx context.Context, namespace string) ([]corev1.ResourceQuota, error) {
+		list, err := client.CoreV1().ResourceQuotas(namespace).List(ctx, utils.ListOptions(c))
+		if err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	})
+	resourceQuotaList := &corev1.ResourceQuotaList{Items: items}
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to list resource quotas")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to list resource quotas")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(k8sSpan, "Successfully listed resource quotas")
@@ -127,7 +137,8 @@ func (h *ResourceQuotasHandler) GetResourceQuotasSSE(c *gin.Context) {
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed for resource quotas SSE")
 
-	namespace := c.Query("namespace")
+	namespaces := utils.RequestedNamespaces(c)
+	namespace := strings.Join(namespaces, ",")
 
 	// Function to fetch and transform resource quotas data
 	fetchResourceQuotas := func() (interface{}, error) {
@@ -139,7 +150,14 @@ func (h *ResourceQuotasHandler) GetResourceQuotasSSE(c *gin.Context) {
 		fetchCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		resourceQuotaList, err := client.CoreV1().ResourceQuotas(namespace).List(fetchCtx, metav1.ListOptions{})
+		items, err := utils.ListInNamespaces(fetchCtx, namespaces, func(ctx context.Context, namespace string) ([]corev1.ResourceQuota, error) {
+			list, err := client.CoreV1().ResourceQuotas(namespace).List(ctx, utils.ListOptions(c))
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		resourceQuotaList := &corev1.ResourceQuotaList{Items: items}
 		if err != nil {
 			h.tracingHelper.RecordError(fetchSpan, err, "Failed to fetch resource quotas for SSE")
 			return nil, err
@@ -189,7 +207,7 @@ func (h *ResourceQuotasHandler) GetResourceQuota(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for resource quota")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -205,7 +223,7 @@ func (h *ResourceQuotasHandler) GetResourceQuota(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("resourcequota", name).WithField("namespace", namespace).Error("Failed to get resource quota")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get resource quota")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(k8sSpan, "Successfully retrieved resource quota")
@@ -214,7 +232,7 @@ func (h *ResourceQuotasHandler) GetResourceQuota(c *gin.Context) {
 	// Check if this is an SSE request (EventSource expects SSE format)
 	acceptHeader := c.GetHeader("Accept")
 	if acceptHeader == "text/event-stream" {
-		h.sseHandler.SendSSEResponse(c, resourceQuota)
+		h.sseHandler.SendSSEDetailResponse(c, resourceQuota, resourceQuota, client.CoreV1().ResourceQuotas(namespace).Watch)
 		return
 	}
 
@@ -231,7 +249,7 @@ func (h *ResourceQuotasHandler) GetResourceQuotaByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for resource quota")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -241,7 +259,7 @@ func (h *ResourceQuotasHandler) GetResourceQuotaByName(c *gin.Context) {
 	if namespace == "" {
 		h.logger.WithField("resourcequota", name).Error("Namespace is required for resource quota lookup")
 		h.tracingHelper.RecordError(clientSpan, fmt.Errorf("namespace parameter is required"), "Namespace parameter is required")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -254,7 +272,7 @@ func (h *ResourceQuotasHandler) GetResourceQuotaByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("resourcequota", name).WithField("namespace", namespace).Error("Failed to get resource quota")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get resource quota")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(k8sSpan, "Successfully retrieved resource quota")
@@ -263,7 +281,7 @@ func (h *ResourceQuotasHandler) GetResourceQuotaByName(c *gin.Context) {
 	// Check if this is an SSE request (EventSource expects SSE format)
 	acceptHeader := c.GetHeader("Accept")
 	if acceptHeader == "text/event-stream" {
-		h.sseHandler.SendSSEResponse(c, resourceQuota)
+		h.sseHandler.SendSSEDetailResponse(c, resourceQuota, resourceQuota, client.CoreV1().ResourceQuotas(namespace).Watch)
 		return
 	}
 
@@ -280,7 +298,7 @@ func (h *ResourceQuotasHandler) GetResourceQuotaYAMLByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for resource quota YAML")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -290,7 +308,7 @@ func (h *ResourceQuotasHandler) GetResourceQuotaYAMLByName(c *gin.Context) {
 	if namespace == "" {
 		h.logger.WithField("resourcequota", name).Error("Namespace is required for resource quota YAML lookup")
 		h.tracingHelper.RecordError(clientSpan, fmt.Errorf("namespace parameter is required"), "Namespace parameter is required")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -303,7 +321,7 @@ func (h *ResourceQuotasHandler) GetResourceQuotaYAMLByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("resourcequota", name).WithField("namespace", namespace).Error("Failed to get resource quota for YAML")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get resource quota for YAML")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(k8sSpan, "Successfully retrieved resource quota for YAML")
@@ -318,7 +336,7 @@ func (h *ResourceQuotasHandler) GetResourceQuotaYAMLByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to marshal resource quota to YAML")
 		h.tracingHelper.RecordError(yamlSpan, err, "Failed to convert to YAML")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to convert to YAML"})
+		utils.RespondErrorMessage(c, http.StatusInternalServerError, "Failed to convert to YAML")
 		return
 	}
 	h.tracingHelper.RecordSuccess(yamlSpan, "Successfully generated YAML response")
@@ -347,7 +365,7 @@ func (h *ResourceQuotasHandler) GetResourceQuotaYAML(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for resource quota YAML")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -363,7 +381,7 @@ func (h *ResourceQuotasHandler) GetResourceQuotaYAML(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("resourcequota", name).WithField("namespace", namespace).Error("Failed to get resource quota for YAML")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get resource quota for YAML")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(k8sSpan, "Successfully retrieved resource quota for YAML")
@@ -384,7 +402,7 @@ func (h *ResourceQuotasHandler) GetResourceQuotaEventsByName(c *gin.Context) {
 
 	if namespace == "" {
 		h.logger.WithField("resourcequota", name).Error("Namespace is required for resource quota events lookup")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -396,7 +414,7 @@ func (h *ResourceQuotasHandler) GetResourceQuotaEventsByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for resource quota events")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -423,7 +441,7 @@ func (h *ResourceQuotasHandler) GetResourceQuotaEvents(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for resource quota events")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -44,7 +44,7 @@ func NewRuntimeClassesHandler(store *storage.KubeConfigStore, clientFactory *k8s
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *RuntimeClassesHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *RuntimeClassesHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -75,7 +75,7 @@ func (h *RuntimeClassesHandler) GetRuntimeClasses(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for runtime classes")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -88,7 +88,7 @@ func (h *RuntimeClassesHandler) GetRuntimeClasses(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to list runtime classes")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to list runtime classes")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(k8sSpan, "Successfully listed runtime classes")
@@ -106,7 +106,7 @@ func (h *RuntimeClassesHandler) GetRuntimeClassesSSE(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -144,7 +144,7 @@ func (h *RuntimeClassesHandler) GetRuntimeClassesSSE(c *gin.Context) {
 	initialData, err := fetchRuntimeClasses()
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to fetch runtime classes")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -155,7 +155,7 @@ func (h *RuntimeClassesHandler) GetRuntimeClassesSSE(c *gin.Context) {
 func (h *RuntimeClassesHandler) GetRuntimeClass(c *gin.Context) {
 	name := c.Param("name")
 	if name == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Runtime class name is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "Runtime class name is required")
 		return
 	}
 
@@ -167,7 +167,7 @@ func (h *RuntimeClassesHandler) GetRuntimeClass(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for runtime class")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -180,7 +180,7 @@ func (h *RuntimeClassesHandler) GetRuntimeClass(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("name", name).Error("Failed to get runtime class")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get runtime class")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(k8sSpan, "Successfully retrieved runtime class")
@@ -193,7 +193,7 @@ func (h *RuntimeClassesHandler) GetRuntimeClass(c *gin.Context) {
 func (h *RuntimeClassesHandler) GetRuntimeClassByName(c *gin.Context) {
 	name := c.Param("name")
 	if name == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Runtime class name is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "Runtime class name is required")
 		return
 	}
 
@@ -205,7 +205,7 @@ func (h *RuntimeClassesHandler) GetRuntimeClassByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for runtime class by name")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -218,7 +218,7 @@ func (h *RuntimeClassesHandler) GetRuntimeClassByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("name", name).Error("Failed to get runtime class by name")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get runtime class")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(k8sSpan, "Successfully retrieved runtime class")
@@ -232,7 +232,7 @@ func (h *RuntimeClassesHandler) GetRuntimeClassByName(c *gin.Context) {
 func (h *RuntimeClassesHandler) GetRuntimeClassYAMLByName(c *gin.Context) {
 	name := c.Param("name")
 	if name == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Runtime class name is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "Runtime class name is required")
 		return
 	}
 
@@ -244,7 +244,7 @@ func (h *RuntimeClassesHandler) GetRuntimeClassYAMLByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for runtime class YAML")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -257,7 +257,7 @@ func (h *RuntimeClassesHandler) GetRuntimeClassYAMLByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get runtime class for YAML")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get runtime class")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(k8sSpan, "Successfully retrieved runtime class")
@@ -270,7 +270,7 @@ func (h *RuntimeClassesHandler) GetRuntimeClassYAMLByName(c *gin.Context) {
 func (h *RuntimeClassesHandler) GetRuntimeClassYAML(c *gin.Context) {
 	name := c.Param("name")
 	if name == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Runtime class name is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "Runtime class name is required")
 		return
 	}
 
@@ -282,7 +282,7 @@ func (h *RuntimeClassesHandler) GetRuntimeClassYAML(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for runtime class YAML")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -295,7 +295,7 @@ func (h *RuntimeClassesHandler) GetRuntimeClassYAML(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get runtime class for YAML")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get runtime class")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(k8sSpan, "Successfully retrieved runtime class")
@@ -308,7 +308,7 @@ func (h *RuntimeClassesHandler) GetRuntimeClassYAML(c *gin.Context) {
 func (h *RuntimeClassesHandler) GetRuntimeClassEventsByName(c *gin.Context) {
 	name := c.Param("name")
 	if name == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Runtime class name is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "Runtime class name is required")
 		return
 	}
 
@@ -320,7 +320,7 @@ func (h *RuntimeClassesHandler) GetRuntimeClassEventsByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for runtime class events")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -336,7 +336,7 @@ func (h *RuntimeClassesHandler) GetRuntimeClassEventsByName(c *gin.Context) {
 func (h *RuntimeClassesHandler) GetRuntimeClassEvents(c *gin.Context) {
 	name := c.Param("name")
 	if name == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Runtime class name is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "Runtime class name is required")
 		return
 	}
 
@@ -348,7 +348,7 @@ func (h *RuntimeClassesHandler) GetRuntimeClassEvents(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for runtime class events")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -0,0 +1,209 @@
+package configurations
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Key-level changes of a secret edit
+const (
+	secretKeyAdded    = "added"
+	secretKeyRemoved  = "removed"
+	secretKeyModified = "modified"
+)
+
+// maskedValue stands in for secret values in edit diffs
+const maskedValue = "********"
+
+// SecretEditRequest is a key-level edit of a secret's data. Values in set are plaintext and are
+// base64-encoded by the server; setBase64 carries values that are already encoded, such as
+// binary data. The edit is rejected when the secret changed since resourceVersion was read.
+type SecretEditRequest struct {
+	ResourceVersion string            `json:"resourceVersion" binding:"required"`
+	Set             map[string]string `json:"set,omitempty"`
+	SetBase64       map[string]string `json:"setBase64,omitempty"`
+	Remove          []string          `json:"remove,omitempty"`
+	DryRun          bool              `json:"dryRun"` // only return the diff
+}
+
+// SecretKeyChange is a changed key of a secret. Values are masked; sizes are in decoded bytes.
+type SecretKeyChange struct {
+	Key      string `json:"key"`
+	Change   string `json:"change"` // added, removed or modified
+	OldValue string `json:"oldValue,omitempty"`
+	NewValue string `json:"newValue,omitempty"`
+	OldSize  *int   `json:"oldSize,omitempty"`
+	NewSize  *int   `json:"newSize,omitempty"`
+}
+
+// SecretEditResult is the outcome of a secret edit
+type SecretEditResult struct {
+	Namespace       string            `json:"namespace"`
+	Name            string            `json:"name"`
+	ResourceVersion string            `json:"resourceVersion"` // after the edit; unchanged for dry runs and no-op edits
+	Changes         []SecretKeyChange `json:"changes"`
+	Unchanged       int               `json:"unchanged"` // keys left as they were
+	DryRun          bool              `json:"dryRun"`
+}
+
+// editedSecretData applies an edit to a copy of a secret's data
+func editedSecretData(current map[string][]byte, req *SecretEditRequest) (map[string][]byte, error) {
+	data := make(map[string][]byte, len(current)+len(req.Set)+len(req.SetBase64))
+	for key, value := range current {
+		data[key] = value
+	}
+	validKey := func(key string) error {
+		if errs := validation.IsConfigMapKey(key); len(errs) > 0 {
+			return fmt.Errorf("invalid key %q: %s", key, strings.Join(errs, "; "))
+		}
+		return nil
+	}
+	for key, value := range req.Set {
+		if err := validKey(key); err != nil {
+			return nil, err
+		}
+		data[key] = []byte(value)
+	}
+	for key, encoded := range req.SetBase64 {
+		if err := validKey(key); err != nil {
+			return nil, err
+		}
+		if _, ok := req.Set[key]; ok {
+			return nil, fmt.Errorf("key %q is in both set and setBase64", key)
+		}
+		value, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("value of key %q is not valid base64: %w", key, err)
+		}
+		data[key] = value
+	}
+	for _, key := range req.Remove {
+		_, set := req.Set[key]
+		_, setEncoded := req.SetBase64[key]
+		if set || setEncoded {
+			return nil, fmt.Errorf("key %q is both set and removed", key)
+		}
+		if _, ok := data[key]; !ok {
+			return nil, fmt.Errorf("key %q does not exist", key)
+		}
+		delete(data, key)
+	}
+	return data, nil
+}
+
+// diffSecretData compares secret data key by key, masking values, and counts unchanged keys
+func diffSecretData(before, after map[string][]byte) ([]SecretKeyChange, int) {
+	changes := []SecretKeyChange{}
+	unchanged := 0
+	size := func(value []byte) *int {
+		n := len(value)
+		return &n
+	}
+	for key, old := range before {
+		value, ok := after[key]
+		switch {
+		case !ok:
+			changes = append(changes, SecretKeyChange{Key: key, Change: secretKeyRemoved, OldValue: maskedValue, OldSize: size(old)})
+		case !bytes.Equal(old, value):
+			changes = append(changes, SecretKeyChange{Key: key, Change: secretKeyModified, OldValue: maskedValue, NewValue: maskedValue, OldSize: size(old), NewSize: size(value)})
+		default:
+			unchanged++
+		}
+	}
+	for key, value := range after {
+		if _, ok := before[key]; !ok {
+			changes = append(changes, SecretKeyChange{Key: key, Change: secretKeyAdded, NewValue: maskedValue, NewSize: size(value)})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes, unchanged
+}
+
+// UpdateSecretData edits the keys of a secret
+// @Summary Edit secret data
+// @Description Sets and removes keys of a secret. Plaintext values in set are base64-encoded by the server, values in setBase64 are stored as given. The edit is rejected with 409 when the secret's resourceVersion no longer matches the one the caller read, so concurrent edits are not overwritten. The response lists the keys added, removed and modified, with values masked; with dryRun the secret is left unchanged.
+// @Tags Secrets
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name"
+// @Param namespace path string true "Namespace name"
+// @Param name path string true "Secret name"
+// @Param request body SecretEditRequest true "Key-level edit"
+// @Success 200 {object} SecretEditResult "Changed keys"
+// @Failure 400 {object} map[string]string "Bad request - invalid keys or values"
+// @Failure 404 {object} map[string]string "Secret not found"
+// @Failure 409 {object} map[string]string "The secret changed since it was read, or is immutable"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/secrets/{namespace}/{name}/data [put]
+func (h *SecretsHandler) UpdateSecretData(c *gin.Context) {
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for secret edit")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	var req SecretEditRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+
+	ctx := c.Request.Context()
+	namespace, name := c.Param("namespace"), c.Param("name")
+	secret, err := client.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		utils.RespondError(c, http.StatusNotFound, err)
+		return
+	}
+	if secret.ResourceVersion != req.ResourceVersion {
+		utils.RespondError(c, http.StatusConflict, apierrors.NewConflict(schema.GroupResource{Resource: "secrets"}, name,
+			fmt.Errorf("the secret changed since resourceVersion %s was read and is now at %s; reload it and edit again", req.ResourceVersion, secret.ResourceVersion)))
+		return
+	}
+
+	data, err := editedSecretData(secret.Data, &req)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	result := SecretEditResult{Namespace: namespace, Name: name, ResourceVersion: secret.ResourceVersion, DryRun: req.DryRun}
+	result.Changes, result.Unchanged = diffSecretData(secret.Data, data)
+	if req.DryRun || len(result.Changes) == 0 {
+		c.JSON(http.StatusOK, result)
+		return
+	}
+	if secret.Immutable != nil && *secret.Immutable {
+		utils.RespondErrorMessage(c, http.StatusConflict, fmt.Sprintf("secret %s is immutable", name))
+		return
+	}
+
+	updated := secret.DeepCopy()
+	updated.Data = data
+	updated.StringData = nil
+	// The API server rejects the update if the secret changed after it was read above
+	saved, err := client.CoreV1().Secrets(namespace).Update(ctx, updated, metav1.UpdateOptions{})
+	if err != nil {
+		h.logger.WithError(err).WithField("secret", name).WithField("namespace", namespace).Error("Failed to update secret data")
+		utils.RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	result.ResourceVersion = saved.ResourceVersion
+	h.logger.WithField("secret", name).WithField("namespace", namespace).WithField("changes", len(result.Changes)).Info("Secret data edited")
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,82 @@
+package configurations
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Facets-cloud/kube-dash/internal/testharness"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDiffSecretData(t *testing.T) {
+	before := map[string][]byte{"user": []byte("admin"), "password": []byte("hunter2"), "host": []byte("db")}
+	after, err := editedSecretData(before, &SecretEditRequest{
+		Set:       map[string]string{"password": "correct-horse", "host": "db"},
+		SetBase64: map[string]string{"cert": "AAEC"},
+		Remove:    []string{"user"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, unchanged := diffSecretData(before, after)
+	var got []string
+	for _, change := range changes {
+		got = append(got, change.Key+":"+change.Change)
+		if strings.Contains(change.OldValue+change.NewValue, "hunter2") || strings.Contains(change.NewValue, "correct-horse") {
+			t.Errorf("change %s leaks a value", change.Key)
+		}
+	}
+	if want := "cert:added password:modified user:removed"; strings.Join(got, " ") != want || unchanged != 1 {
+		t.Errorf("changes = %v (%d unchanged), want %s (1 unchanged)", got, unchanged, want)
+	}
+	if *changes[0].NewSize != 3 || *changes[1].NewSize != len("correct-horse") {
+		t.Errorf("unexpected sizes: %+v", changes)
+	}
+
+	invalid := []SecretEditRequest{
+		{Set: map[string]string{"bad key": "x"}},
+		{SetBase64: map[string]string{"cert": "not base64!"}},
+		{Remove: []string{"missing"}},
+		{Set: map[string]string{"user": "x"}, Remove: []string{"user"}},
+	}
+	for i, req := range invalid {
+		if _, err := editedSecretData(before, &req); err == nil {
+			t.Errorf("case %d: expected an error", i)
+		}
+	}
+}
+
+func TestUpdateSecretData(t *testing.T) {
+	h := testharness.New(t, "secrets.yaml")
+	handler := NewSecretsHandler(h.Store, h.Factory, h.Logger)
+	route := "/api/v1/secrets/:namespace/:name/data"
+	target := "/api/v1/secrets/shop/db-credentials/data"
+
+	w := h.Do(http.MethodPut, route, handler.UpdateSecretData, target, strings.NewReader(`{"resourceVersion": "40", "set": {"password": "x"}}`))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("stale resourceVersion answered %d, want 409: %s", w.Code, w.Body.String())
+	}
+
+	var dryRun SecretEditResult
+	h.DoJSON(http.MethodPut, route, handler.UpdateSecretData, target, strings.NewReader(`{"resourceVersion": "41", "set": {"password": "s3cret"}, "dryRun": true}`), &dryRun)
+	if len(dryRun.Changes) != 1 || dryRun.Changes[0].Change != secretKeyModified {
+		t.Errorf("unexpected dry run: %+v", dryRun)
+	}
+
+	var result SecretEditResult
+	h.DoJSON(http.MethodPut, route, handler.UpdateSecretData, target, strings.NewReader(`{"resourceVersion": "41", "set": {"password": "s3cret", "port": "5432"}, "remove": ["host"]}`), &result)
+	if len(result.Changes) != 3 || result.Unchanged != 1 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	secret, err := h.Clientset.CoreV1().Secrets("shop").Get(context.Background(), "db-credentials", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(secret.Data["password"]) != "s3cret" || string(secret.Data["port"]) != "5432" || secret.Data["host"] != nil || string(secret.Data["username"]) != "admin" {
+		t.Errorf("unexpected secret data: %v", secret.Data)
+	}
+}
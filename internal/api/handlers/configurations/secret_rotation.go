@@ -0,0 +1,500 @@
+package configurations
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+)
+
+// Rotation step actions, in the order they run
+const (
+	rotationCreateSecret         = "create-secret"
+	rotationUpdateServiceAccount = "update-serviceaccount"
+	rotationUpdateWorkload       = "update-workload"
+	rotationRestartWorkload      = "restart-workload"
+	rotationDeleteSecret         = "delete-secret"
+)
+
+// RegistryCredentials are the registry login a rotated docker-registry secret is built from
+type RegistryCredentials struct {
+	Server   string `json:"server" binding:"required"`
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+	Email    string `json:"email,omitempty"`
+}
+
+// SecretRotationRequest describes the replacement for a docker-registry secret
+type SecretRotationRequest struct {
+	NewName          string               `json:"newName,omitempty"`          // defaults to <name>-<timestamp>
+	DockerConfigJSON string               `json:"dockerConfigJson,omitempty"` // raw .dockerconfigjson, instead of registry
+	Registry         *RegistryCredentials `json:"registry,omitempty"`
+	RestartWorkloads bool                 `json:"restartWorkloads"` // restart workloads pulling through an updated service account
+}
+
+// SecretRotationStep is the progress of one step of a rotation
+type SecretRotationStep struct {
+	Action  string `json:"action"`
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`
+	State   string `json:"state"` // pending, done, skipped or failed
+	Message string `json:"message,omitempty"`
+}
+
+// SecretRotation tracks a running or finished rotation of a docker-registry secret
+type SecretRotation struct {
+	Namespace  string               `json:"namespace"`
+	OldSecret  string               `json:"oldSecret"`
+	NewSecret  string               `json:"newSecret"`
+	State      string               `json:"state"` // running, awaiting-confirmation, completed or failed
+	Total      int                  `json:"total"`
+	Processed  int                  `json:"processed"`
+	Failed     int                  `json:"failed"`
+	Steps      []SecretRotationStep `json:"steps"`
+	StartedAt  time.Time            `json:"startedAt"`
+	FinishedAt *time.Time           `json:"finishedAt,omitempty"`
+}
+
+// rotationWorkload is a workload whose pod template may pull through the rotated secret
+type rotationWorkload struct {
+	kind, name string
+	path       []string // location of the pod template
+	spec       *corev1.PodSpec
+}
+
+// rotationStep is one change made by a rotation
+type rotationStep struct {
+	action, kind, name string
+	patch              map[string]interface{}
+}
+
+// rotationKey scopes rotation progress to a config, cluster and secret
+func rotationKey(configID, cluster, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", configID, cluster, namespace, name)
+}
+
+// replacePullSecret swaps the old secret for the new one in a list of pull secrets, reporting whether it was referenced
+func replacePullSecret(refs []corev1.LocalObjectReference, oldName, newName string) ([]corev1.LocalObjectReference, bool) {
+	found, hasNew := false, false
+	for _, ref := range refs {
+		found = found || ref.Name == oldName
+		hasNew = hasNew || ref.Name == newName
+	}
+	if !found {
+		return refs, false
+	}
+	replaced := []corev1.LocalObjectReference{}
+	for _, ref := range refs {
+		switch {
+		case ref.Name != oldName:
+			replaced = append(replaced, ref)
+		case !hasNew:
+			replaced = append(replaced, corev1.LocalObjectReference{Name: newName})
+		}
+	}
+	return replaced, true
+}
+
+// nestedPatch wraps value in maps along path
+func nestedPatch(path []string, value interface{}) map[string]interface{} {
+	patch := map[string]interface{}{path[len(path)-1]: value}
+	for i := len(path) - 2; i >= 0; i-- {
+		patch = map[string]interface{}{path[i]: patch}
+	}
+	return patch
+}
+
+// planSecretRotation builds the steps that move service accounts and workloads from the old secret to the new one.
+// Pod templates referencing the secret directly roll out when patched; with restart, workloads pulling through an
+// updated service account are restarted so their pods pick up the new secret at admission.
+func planSecretRotation(oldName, newName string, serviceAccounts []corev1.ServiceAccount, workloads []rotationWorkload, restart bool) []rotationStep {
+	steps := []rotationStep{{action: rotationCreateSecret, kind: "Secret", name: newName}}
+
+	updatedAccounts := map[string]bool{}
+	for _, sa := range serviceAccounts {
+		if refs, ok := replacePullSecret(sa.ImagePullSecrets, oldName, newName); ok {
+			updatedAccounts[sa.Name] = true
+			steps = append(steps, rotationStep{action: rotationUpdateServiceAccount, kind: "ServiceAccount", name: sa.Name,
+				patch: map[string]interface{}{"imagePullSecrets": refs}})
+		}
+	}
+
+	var restarts []rotationStep
+	for _, w := range workloads {
+		if refs, ok := replacePullSecret(w.spec.ImagePullSecrets, oldName, newName); ok {
+			steps = append(steps, rotationStep{action: rotationUpdateWorkload, kind: w.kind, name: w.name,
+				patch: nestedPatch(append(append([]string{}, w.path...), "spec"), map[string]interface{}{"imagePullSecrets": refs})})
+			continue
+		}
+		account := w.spec.ServiceAccountName
+		if account == "" {
+			account = "default"
+		}
+		if restart && updatedAccounts[account] {
+			annotations := map[string]interface{}{"kubectl.kubernetes.io/restartedAt": time.Now().Format(time.RFC3339)}
+			restarts = append(restarts, rotationStep{action: rotationRestartWorkload, kind: w.kind, name: w.name,
+				patch: nestedPatch(append(append([]string{}, w.path...), "metadata"), map[string]interface{}{"annotations": annotations})})
+		}
+	}
+	steps = append(steps, restarts...)
+	return append(steps, rotationStep{action: rotationDeleteSecret, kind: "Secret", name: oldName})
+}
+
+// buildDockerConfigJSON builds a .dockerconfigjson document for a single registry login
+func buildDockerConfigJSON(creds *RegistryCredentials) ([]byte, error) {
+	entry := map[string]string{
+		"username": creds.Username,
+		"password": creds.Password,
+		"auth":     base64.StdEncoding.EncodeToString([]byte(creds.Username + ":" + creds.Password)),
+	}
+	if creds.Email != "" {
+		entry["email"] = creds.Email
+	}
+	return json.Marshal(map[string]interface{}{"auths": map[string]interface{}{creds.Server: entry}})
+}
+
+// listRotationWorkloads lists the workloads in a namespace with their pod templates
+func listRotationWorkloads(ctx context.Context, client kubernetes.Interface, namespace string) ([]rotationWorkload, error) {
+	var workloads []rotationWorkload
+	template := []string{"spec", "template"}
+
+	deployments, err := client.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	for i := range deployments.Items {
+		workloads = append(workloads, rotationWorkload{"Deployment", deployments.Items[i].Name, template, &deployments.Items[i].Spec.Template.Spec})
+	}
+	statefulSets, err := client.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statefulsets: %w", err)
+	}
+	for i := range statefulSets.Items {
+		workloads = append(workloads, rotationWorkload{"StatefulSet", statefulSets.Items[i].Name, template, &statefulSets.Items[i].Spec.Template.Spec})
+	}
+	daemonSets, err := client.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list daemonsets: %w", err)
+	}
+	for i := range daemonSets.Items {
+		workloads = append(workloads, rotationWorkload{"DaemonSet", daemonSets.Items[i].Name, template, &daemonSets.Items[i].Spec.Template.Spec})
+	}
+	cronJobs, err := client.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cronjobs: %w", err)
+	}
+	for i := range cronJobs.Items {
+		workloads = append(workloads, rotationWorkload{"CronJob", cronJobs.Items[i].Name, []string{"spec", "jobTemplate", "spec", "template"}, &cronJobs.Items[i].Spec.JobTemplate.Spec.Template.Spec})
+	}
+	return workloads, nil
+}
+
+// applyRotationStep merge-patches a service account or workload
+func applyRotationStep(ctx context.Context, client kubernetes.Interface, namespace string, step rotationStep) error {
+	data, err := json.Marshal(step.patch)
+	if err != nil {
+		return err
+	}
+	switch step.kind {
+	case "ServiceAccount":
+		_, err = client.CoreV1().ServiceAccounts(namespace).Patch(ctx, step.name, k8stypes.MergePatchType, data, metav1.PatchOptions{})
+	case "Deployment":
+		_, err = client.AppsV1().Deployments(namespace).Patch(ctx, step.name, k8stypes.MergePatchType, data, metav1.PatchOptions{})
+	case "StatefulSet":
+		_, err = client.AppsV1().StatefulSets(namespace).Patch(ctx, step.name, k8stypes.MergePatchType, data, metav1.PatchOptions{})
+	case "DaemonSet":
+		_, err = client.AppsV1().DaemonSets(namespace).Patch(ctx, step.name, k8stypes.MergePatchType, data, metav1.PatchOptions{})
+	case "CronJob":
+		_, err = client.BatchV1().CronJobs(namespace).Patch(ctx, step.name, k8stypes.MergePatchType, data, metav1.PatchOptions{})
+	default:
+		err = fmt.Errorf("unsupported kind %s", step.kind)
+	}
+	return err
+}
+
+// newRotationTracker tracks rotations; one running or awaiting confirmation blocks another of the same secret
+func newRotationTracker() *utils.ProgressTracker[SecretRotation] {
+	return utils.NewProgressTracker(utils.ProgressRetention,
+		func(r *SecretRotation) bool { return r.State == "running" || r.State == "awaiting-confirmation" },
+		func(r *SecretRotation) *SecretRotation {
+			next := *r
+			next.Steps = append([]SecretRotationStep{}, r.Steps...)
+			return &next
+		})
+}
+
+// RotateSecret starts replacing a docker-registry secret with a new one
+// @Summary Rotate docker-registry secret
+// @Description Creates a new docker-registry secret, moves service accounts and workload pod templates in the namespace from the old secret to the new one and optionally restarts workloads that pull through an updated service account. Runs in the background; poll rotation for progress. The old secret is only deleted once the rotation is confirmed.
+// @Tags Secrets
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name"
+// @Param namespace path string true "Namespace name"
+// @Param name path string true "Secret name"
+// @Param request body SecretRotationRequest true "Replacement credentials"
+// @Success 202 {object} SecretRotation "Rotation started"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters or not a docker-registry secret"
+// @Failure 404 {object} map[string]string "Secret not found"
+// @Failure 409 {object} map[string]string "A rotation is already in progress or the new secret exists"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/secrets/{namespace}/{name}/rotate [post]
+func (h *SecretsHandler) RotateSecret(c *gin.Context) {
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for secret rotation")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	var req SecretRotationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+	var dockerConfig []byte
+	switch {
+	case req.Registry != nil && req.DockerConfigJSON != "":
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "provide either registry or dockerConfigJson, not both")
+		return
+	case req.Registry != nil:
+		if dockerConfig, err = buildDockerConfigJSON(req.Registry); err != nil {
+			utils.RespondError(c, http.StatusBadRequest, err)
+			return
+		}
+	case req.DockerConfigJSON != "":
+		if !json.Valid([]byte(req.DockerConfigJSON)) {
+			utils.RespondErrorMessage(c, http.StatusBadRequest, "dockerConfigJson is not valid JSON")
+			return
+		}
+		dockerConfig = []byte(req.DockerConfigJSON)
+	default:
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "registry or dockerConfigJson is required")
+		return
+	}
+
+	namespace, name := c.Param("namespace"), c.Param("name")
+	if req.NewName == "" {
+		req.NewName = fmt.Sprintf("%s-%s", name, time.Now().UTC().Format("20060102150405"))
+	}
+	if req.NewName == name {
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "newName must differ from the secret being rotated")
+		return
+	}
+
+	key := rotationKey(c.Query("config"), c.Query("cluster"), namespace, name)
+
+	ctx := c.Request.Context()
+	old, err := client.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		utils.RespondError(c, http.StatusNotFound, err)
+		return
+	}
+	if old.Type != corev1.SecretTypeDockerConfigJson && old.Type != corev1.SecretTypeDockercfg {
+		utils.RespondErrorMessage(c, http.StatusBadRequest, fmt.Sprintf("secret %s has type %s, only docker-registry secrets can be rotated", name, old.Type))
+		return
+	}
+	if _, err := client.CoreV1().Secrets(namespace).Get(ctx, req.NewName, metav1.GetOptions{}); err == nil {
+		utils.RespondErrorMessage(c, http.StatusConflict, fmt.Sprintf("secret %s already exists", req.NewName))
+		return
+	}
+
+	serviceAccounts, err := client.CoreV1().ServiceAccounts(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		utils.RespondErrorMessage(c, http.StatusInternalServerError, fmt.Sprintf("failed to list service accounts: %v", err))
+		return
+	}
+	workloads, err := listRotationWorkloads(ctx, client, namespace)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	steps := planSecretRotation(name, req.NewName, serviceAccounts.Items, workloads, req.RestartWorkloads)
+
+	replacement := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: req.NewName, Namespace: namespace, Labels: old.Labels},
+		Type:       corev1.SecretTypeDockerConfigJson,
+		Data:       map[string][]byte{corev1.DockerConfigJsonKey: dockerConfig},
+	}
+
+	status := &SecretRotation{
+		Namespace: namespace,
+		OldSecret: name,
+		NewSecret: req.NewName,
+		State:     "running",
+		Total:     len(steps),
+		Steps:     make([]SecretRotationStep, len(steps)),
+		StartedAt: time.Now(),
+	}
+	for i, step := range steps {
+		status.Steps[i] = SecretRotationStep{Action: step.action, Kind: step.kind, Name: step.name, State: "pending"}
+	}
+	if existing, started := h.rotations.Start(key, status); !started {
+		utils.RespondErrorMessage(c, http.StatusConflict, fmt.Sprintf("a rotation of secret %s is already %s", name, existing.State))
+		return
+	}
+
+	go h.runSecretRotation(client, key, namespace, replacement, steps)
+
+	c.JSON(http.StatusAccepted, status)
+}
+
+// runSecretRotation creates the new secret and applies every step but the final delete, which waits for confirmation
+func (h *SecretsHandler) runSecretRotation(client kubernetes.Interface, key, namespace string, replacement *corev1.Secret, steps []rotationStep) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	failed := 0
+	for i, step := range steps {
+		if step.action == rotationDeleteSecret {
+			continue
+		}
+		state, message := "done", ""
+		var err error
+		if step.action == rotationCreateSecret {
+			_, err = client.CoreV1().Secrets(namespace).Create(ctx, replacement, metav1.CreateOptions{})
+		} else {
+			err = applyRotationStep(ctx, client, namespace, step)
+		}
+		if err != nil {
+			state, message = "failed", err.Error()
+			failed++
+			h.logger.WithError(err).WithField("namespace", namespace).WithField("target", step.kind+"/"+step.name).Error("Secret rotation step failed")
+		}
+		h.rotations.Update(key, func(r *SecretRotation) {
+			r.Steps[i].State = state
+			r.Steps[i].Message = message
+			r.Processed++
+			if state == "failed" {
+				r.Failed++
+			}
+		})
+		// Nothing can be moved to a secret that was not created
+		if step.action == rotationCreateSecret && err != nil {
+			break
+		}
+	}
+
+	h.rotations.Update(key, func(r *SecretRotation) {
+		if failed > 0 {
+			// Keep the old secret: something may still depend on it
+			for i := range r.Steps {
+				if r.Steps[i].State == "pending" {
+					r.Steps[i].State = "skipped"
+					r.Steps[i].Message = "rotation failed; the old secret is kept"
+				}
+			}
+			r.State = "failed"
+			r.FinishedAt = ptr.To(time.Now())
+			return
+		}
+		r.State = "awaiting-confirmation"
+	})
+	h.logger.WithField("namespace", namespace).WithField("secret", replacement.Name).WithField("failed", failed).Info("Secret rotation applied")
+}
+
+// ConfirmSecretRotation deletes the old secret of a rotation awaiting confirmation
+// @Summary Confirm secret rotation
+// @Description Deletes the rotated secret once every service account and workload has been moved to its replacement
+// @Tags Secrets
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name"
+// @Param namespace path string true "Namespace name"
+// @Param name path string true "Secret name"
+// @Success 200 {object} SecretRotation "Rotation completed"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 404 {object} map[string]string "No rotation found"
+// @Failure 409 {object} map[string]string "Rotation is not awaiting confirmation"
+// @Failure 500 {object} map[string]string "Failed to delete the old secret"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/secrets/{namespace}/{name}/rotation/confirm [post]
+func (h *SecretsHandler) ConfirmSecretRotation(c *gin.Context) {
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for secret rotation confirmation")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	namespace, name := c.Param("namespace"), c.Param("name")
+	key := rotationKey(c.Query("config"), c.Query("cluster"), namespace, name)
+	existing, ok := h.rotations.Load(key)
+	if !ok {
+		utils.RespondErrorMessage(c, http.StatusNotFound, fmt.Sprintf("no rotation found for secret %s", name))
+		return
+	}
+	if state := existing.State; state != "awaiting-confirmation" {
+		utils.RespondErrorMessage(c, http.StatusConflict, fmt.Sprintf("rotation of secret %s is %s, not awaiting confirmation", name, state))
+		return
+	}
+
+	err = client.CoreV1().Secrets(namespace).Delete(c.Request.Context(), name, metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		err = nil
+	}
+	h.rotations.Update(key, func(r *SecretRotation) {
+		last := &r.Steps[len(r.Steps)-1]
+		if err != nil {
+			last.State, last.Message = "failed", err.Error()
+			return
+		}
+		last.State = "done"
+		r.Processed++
+		r.State = "completed"
+		r.FinishedAt = ptr.To(time.Now())
+	})
+	current, _ := h.rotations.Load(key)
+	if err != nil {
+		h.logger.WithError(err).WithField("namespace", namespace).WithField("secret", name).Error("Failed to delete rotated secret")
+		response := utils.NewErrorResponse(c, http.StatusInternalServerError, err)
+		c.JSON(response.Status, struct {
+			utils.ErrorResponse
+			Rotation *SecretRotation `json:"rotation"`
+		}{response, current})
+		return
+	}
+	c.JSON(http.StatusOK, current)
+}
+
+// GetSecretRotation returns the progress of the latest rotation of a secret
+// @Summary Get secret rotation status
+// @Description Returns the progress of the most recent rotation of a docker-registry secret
+// @Tags Secrets
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name"
+// @Param namespace path string true "Namespace name"
+// @Param name path string true "Secret name"
+// @Success 200 {object} SecretRotation "Rotation progress"
+// @Failure 404 {object} map[string]string "No rotation found"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/secrets/{namespace}/{name}/rotation [get]
+func (h *SecretsHandler) GetSecretRotation(c *gin.Context) {
+	namespace, name := c.Param("namespace"), c.Param("name")
+	rotation, ok := h.rotations.Load(rotationKey(c.Query("config"), c.Query("cluster"), namespace, name))
+	if !ok {
+		utils.RespondErrorMessage(c, http.StatusNotFound, fmt.Sprintf("no rotation found for secret %s", name))
+		return
+	}
+	c.JSON(http.StatusOK, rotation)
+}
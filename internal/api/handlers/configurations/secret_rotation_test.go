@@ -0,0 +1,66 @@
+package configurations
+
+import (
+	"encoding/json"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPlanSecretRotation(t *testing.T) {
+	refs := func(names ...string) []corev1.LocalObjectReference {
+		var out []corev1.LocalObjectReference
+		for _, n := range names {
+			out = append(out, corev1.LocalObjectReference{Name: n})
+		}
+		return out
+	}
+	serviceAccounts := []corev1.ServiceAccount{
+		{ObjectMeta: metav1.ObjectMeta{Name: "default"}, ImagePullSecrets: refs("old", "other")},
+		{ObjectMeta: metav1.ObjectMeta{Name: "builder"}, ImagePullSecrets: refs("other")},
+	}
+	template := []string{"spec", "template"}
+	workloads := []rotationWorkload{
+		{"Deployment", "api", template, &corev1.PodSpec{ImagePullSecrets: refs("old")}},
+		{"Deployment", "web", template, &corev1.PodSpec{}},
+		{"StatefulSet", "db", template, &corev1.PodSpec{ServiceAccountName: "builder"}},
+	}
+
+	steps := planSecretRotation("old", "new", serviceAccounts, workloads, true)
+	var got []string
+	for _, s := range steps {
+		got = append(got, s.action+":"+s.kind+"/"+s.name)
+	}
+	want := []string{
+		"create-secret:Secret/new",
+		"update-serviceaccount:ServiceAccount/default",
+		"update-workload:Deployment/api",
+		"restart-workload:Deployment/web",
+		"delete-secret:Secret/old",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("step %d = %s, want %s", i, got[i], want[i])
+		}
+	}
+
+	patch, _ := json.Marshal(steps[2].patch)
+	if want := `{"spec":{"template":{"spec":{"imagePullSecrets":[{"name":"new"}]}}}}`; string(patch) != want {
+		t.Errorf("workload patch = %s, want %s", patch, want)
+	}
+}
+
+func TestReplacePullSecret(t *testing.T) {
+	// The old reference is dropped rather than duplicating one to the new secret
+	refs, ok := replacePullSecret([]corev1.LocalObjectReference{{Name: "new"}, {Name: "old"}}, "old", "new")
+	if !ok || len(refs) != 1 || refs[0].Name != "new" {
+		t.Errorf("got %v, %v", refs, ok)
+	}
+	if _, ok := replacePullSecret([]corev1.LocalObjectReference{{Name: "other"}}, "old", "new"); ok {
+		t.Error("expected no replacement when the old secret is not referenced")
+	}
+}
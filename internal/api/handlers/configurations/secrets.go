@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/Facets-cloud/kube-dash/internal/api/transformers"
@@ -15,6 +16,7 @@ import (
 	"github.com/Facets-cloud/kube-dash/pkg/logger"
 
 	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -28,6 +30,9 @@ type SecretsHandler struct {
 	yamlHandler   *utils.YAMLHandler
 	eventsHandler *utils.EventsHandler
 	tracingHelper *tracing.TracingHelper
+
+	// Docker-registry secret rotations keyed by config/cluster/namespace/name
+	rotations *utils.ProgressTracker[SecretRotation]
 }
 
 // NewSecretsHandler creates a new SecretsHandler
@@ -40,11 +45,13 @@ func NewSecretsHandler(store *storage.KubeConfigStore, clientFactory *k8s.Client
 		yamlHandler:   utils.NewYAMLHandler(log),
 		eventsHandler: utils.NewEventsHandler(log),
 		tracingHelper: tracing.GetTracingHelper(),
+
+		rotations: newRotationTracker(),
 	}
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *SecretsHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *SecretsHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -74,6 +81,12 @@ func (h *SecretsHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clients
 // @Param config query string true "Kubernetes config ID"
 // @Param cluster query string false "Cluster name"
 // @Param namespace query string true "Namespace name"
+// @Param namespaces query string false "Comma-separated namespaces to list across, instead of namespace"
+// @Param namespaceGroup query string false "ID of a saved namespace group to list across"
+// @Param fields query string false "Comma-separated response fields to return, e.g. name,status,restarts; name, namespace and uid are always included"
+// @Param labelSelector query string false "Kubernetes label selector, e.g. app=web,tier!=cache"
+// @Param sort query string false "Response field to sort by, e.g. name or -restarts for descending"
+// @Param view query string false "ID of a saved view supplying namespaces, labelSelector, sort and fields"
 // @Success 200 {array} types.SecretListResponse "List of transformed secrets"
 // @Failure 400 {object} map[string]string "Bad request"
 // @Failure 500 {object} map[string]string "Internal server error"
@@ -89,22 +102,30 @@ func (h *SecretsHandler) GetSecrets(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for secrets")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
 
-	namespace := c.Query("namespace")
+	namespaces := utils.RequestedNamespaces(c)
+	namespace := strings.Join(namespaces, ",")
 
 	// Start child span for Kubernetes API call
 	_, k8sSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "secrets", namespace)
 	defer k8sSpan.End()
 
-	secretList, err := client.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{})
+	items, err := utils.ListInNamespaces(ctx, namespaces, func(ctx context.Context, namespace string) ([]corev1.Secret, error) {
+		list, err := client.CoreV1().Secrets(namespace).List(ctx, utils.ListOptions(c))
+		if err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	})
+	secretList := &corev1.SecretList{Items: items}
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to list secrets")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to list secrets")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(k8sSpan, "Successfully listed secrets")
@@ -122,7 +143,7 @@ func (h *SecretsHandler) GetSecrets(c *gin.Context) {
 	h.tracingHelper.RecordSuccess(processSpan, "Successfully transformed secrets")
 	h.tracingHelper.AddResourceAttributes(processSpan, "transformed-secrets", "secret", len(transformedSecrets))
 
-	c.JSON(http.StatusOK, transformedSecrets)
+	h.sseHandler.SendJSON(c, transformedSecrets)
 }
 
 // GetSecretsSSE returns secrets as Server-Sent Events with real-time updates
@@ -135,6 +156,12 @@ func (h *SecretsHandler) GetSecrets(c *gin.Context) {
 // @Param config query string true "Kubernetes config ID"
 // @Param cluster query string false "Cluster name"
 // @Param namespace query string true "Namespace name"
+// @Param namespaces query string false "Comma-separated namespaces to list across, instead of namespace"
+// @Param namespaceGroup query string false "ID of a saved namespace group to list across"
+// @Param fields query string false "Comma-separated response fields to return, e.g. name,status,restarts; name, namespace and uid are always included"
+// @Param labelSelector query string false "Kubernetes label selector, e.g. app=web,tier!=cache"
+// @Param sort query string false "Response field to sort by, e.g. name or -restarts for descending"
+// @Param view query string false "ID of a saved view supplying namespaces, labelSelector, sort and fields"
 // @Success 200 {array} types.SecretListResponse "Stream of transformed secrets or JSON array"
 // @Failure 400 {object} map[string]string "Bad request"
 // @Failure 500 {object} map[string]string "Internal server error"
@@ -155,7 +182,8 @@ func (h *SecretsHandler) GetSecretsSSE(c *gin.Context) {
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed for secrets SSE")
 
-	namespace := c.Query("namespace")
+	namespaces := utils.RequestedNamespaces(c)
+	namespace := strings.Join(namespaces, ",")
 
 	// Function to fetch and transform secrets data
 	fetchSecrets := func() (interface{}, error) {
@@ -167,7 +195,14 @@ func (h *SecretsHandler) GetSecretsSSE(c *gin.Context) {
 		fetchCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		secretList, err := client.CoreV1().Secrets(namespace).List(fetchCtx, metav1.ListOptions{})
+		items, err := utils.ListInNamespaces(fetchCtx, namespaces, func(ctx context.Context, namespace string) ([]corev1.Secret, error) {
+			list, err := client.CoreV1().Secrets(namespace).List(ctx, utils.ListOptions(c))
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		secretList := &corev1.SecretList{Items: items}
 		if err != nil {
 			h.tracingHelper.RecordError(fetchSpan, err, "Failed to fetch secrets for SSE")
 			return nil, err
@@ -213,7 +248,7 @@ func (h *SecretsHandler) GetSecretsSSE(c *gin.Context) {
 	}
 
 	// For non-SSE requests, return JSON
-	c.JSON(http.StatusOK, initialData)
+	h.sseHandler.SendJSON(c, initialData)
 }
 
 // GetSecret returns a specific secret
@@ -242,7 +277,7 @@ func (h *SecretsHandler) GetSecret(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for secret")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -258,7 +293,7 @@ func (h *SecretsHandler) GetSecret(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("secret", name).WithField("namespace", namespace).Error("Failed to get secret")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get secret")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(k8sSpan, "Successfully retrieved secret")
@@ -267,7 +302,7 @@ func (h *SecretsHandler) GetSecret(c *gin.Context) {
 	// Check if this is an SSE request (EventSource expects SSE format)
 	acceptHeader := c.GetHeader("Accept")
 	if acceptHeader == "text/event-stream" {
-		h.sseHandler.SendSSEResponse(c, secret)
+		h.sseHandler.SendSSEDetailResponse(c, secret, secret, client.CoreV1().Secrets(namespace).Watch)
 		return
 	}
 
@@ -300,7 +335,7 @@ func (h *SecretsHandler) GetSecretByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for secret")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -310,7 +345,7 @@ func (h *SecretsHandler) GetSecretByName(c *gin.Context) {
 
 	if namespace == "" {
 		h.logger.WithField("secret", name).Error("Namespace is required for secret lookup")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -322,7 +357,7 @@ func (h *SecretsHandler) GetSecretByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("secret", name).WithField("namespace", namespace).Error("Failed to get secret")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get secret")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(k8sSpan, "Successfully retrieved secret")
@@ -331,7 +366,7 @@ func (h *SecretsHandler) GetSecretByName(c *gin.Context) {
 	// Check if this is an SSE request (EventSource expects SSE format)
 	acceptHeader := c.GetHeader("Accept")
 	if acceptHeader == "text/event-stream" {
-		h.sseHandler.SendSSEResponse(c, secret)
+		h.sseHandler.SendSSEDetailResponse(c, secret, secret, client.CoreV1().Secrets(namespace).Watch)
 		return
 	}
 
@@ -363,7 +398,7 @@ func (h *SecretsHandler) GetSecretYAMLByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for secret YAML")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -373,7 +408,7 @@ func (h *SecretsHandler) GetSecretYAMLByName(c *gin.Context) {
 
 	if namespace == "" {
 		h.logger.WithField("secret", name).Error("Namespace is required for secret YAML lookup")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -385,7 +420,7 @@ func (h *SecretsHandler) GetSecretYAMLByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("secret", name).WithField("namespace", namespace).Error("Failed to get secret for YAML")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get secret for YAML")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(k8sSpan, "Successfully retrieved secret for YAML")
@@ -424,7 +459,7 @@ func (h *SecretsHandler) GetSecretYAML(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for secret YAML")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -440,7 +475,7 @@ func (h *SecretsHandler) GetSecretYAML(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("secret", name).WithField("namespace", namespace).Error("Failed to get secret for YAML")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get secret for YAML")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(k8sSpan, "Successfully retrieved secret for YAML")
@@ -477,7 +512,7 @@ func (h *SecretsHandler) GetSecretEventsByName(c *gin.Context) {
 
 	if namespace == "" {
 		h.logger.WithField("secret", name).Error("Namespace is required for secret events lookup")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -489,7 +524,7 @@ func (h *SecretsHandler) GetSecretEventsByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for secret events")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -532,7 +567,7 @@ func (h *SecretsHandler) GetSecretEvents(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for secret events")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
@@ -0,0 +1,483 @@
+package cost
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/config"
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/internal/tracing"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Cost sources
+const (
+	sourceOpenCost = "opencost"
+	sourceKubecost = "kubecost"
+	sourceEstimate = "estimate"
+)
+
+// idleAllocationName is the allocation OpenCost and Kubecost use for unallocated node cost
+const idleAllocationName = "__idle__"
+
+const hoursPerMonth = 730
+
+// costProvider is an in-cluster OpenCost or Kubecost service reached through the API server proxy
+type costProvider struct {
+	Kind      string
+	Namespace string
+	Service   string
+	Port      string
+	Path      string
+}
+
+// CostAllocation is the cost of one namespace or workload over the window
+type CostAllocation struct {
+	Name        string   `json:"name"`
+	Namespace   string   `json:"namespace,omitempty"`
+	CPUCost     float64  `json:"cpuCost"`
+	MemoryCost  float64  `json:"memoryCost"`
+	StorageCost float64  `json:"storageCost"`
+	GPUCost     float64  `json:"gpuCost"`
+	NetworkCost float64  `json:"networkCost"`
+	SharedCost  float64  `json:"sharedCost"`
+	TotalCost   float64  `json:"totalCost"`
+	Efficiency  *float64 `json:"efficiency,omitempty"` // usage / request, only reported by OpenCost/Kubecost
+}
+
+// CostReport is the unified cost response regardless of the data source
+type CostReport struct {
+	Source      string           `json:"source"` // opencost, kubecost or estimate
+	Window      string           `json:"window"`
+	Aggregate   string           `json:"aggregate"`
+	Currency    string           `json:"currency"`
+	Allocations []CostAllocation `json:"allocations"`
+	IdleCost    float64          `json:"idleCost"`
+	TotalCost   float64          `json:"totalCost"`
+	Note        string           `json:"note,omitempty"`
+}
+
+// CostHandler serves cost allocation from OpenCost/Kubecost with a request-based fallback
+type CostHandler struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	logger        *logger.Logger
+	tracingHelper *tracing.TracingHelper
+	config        *config.CostConfig
+	httpClient    *http.Client
+
+	// Discovered providers keyed by config/cluster; a nil value means none was found
+	providers sync.Map
+}
+
+// NewCostHandler creates a new cost handler
+func NewCostHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger, cfg *config.CostConfig) *CostHandler {
+	return &CostHandler{
+		store:         store,
+		clientFactory: clientFactory,
+		logger:        log,
+		tracingHelper: tracing.GetTracingHelper(),
+		config:        cfg,
+		httpClient:    &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// getClient gets the Kubernetes client for the current request
+func (h *CostHandler) getClient(c *gin.Context) (kubernetes.Interface, error) {
+	configID := c.Query("config")
+	cluster := c.Query("cluster")
+	if configID == "" {
+		return nil, fmt.Errorf("config parameter is required")
+	}
+	cfg, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, fmt.Errorf("config not found: %w", err)
+	}
+	client, err := h.clientFactory.GetClientForConfig(cfg, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Kubernetes client: %w", err)
+	}
+	return client, nil
+}
+
+// discoverProvider finds an OpenCost or Kubecost service exposing the allocation API
+func (h *CostHandler) discoverProvider(ctx context.Context, client kubernetes.Interface) *costProvider {
+	svcs, err := client.CoreV1().Services("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil
+	}
+	for _, s := range svcs.Items {
+		var candidate *costProvider
+		switch {
+		case strings.Contains(s.Name, "kubecost-cost-analyzer"):
+			candidate = &costProvider{Kind: sourceKubecost, Path: "model/allocation"}
+		case s.Name == "opencost" || s.Labels["app.kubernetes.io/name"] == "opencost":
+			candidate = &costProvider{Kind: sourceOpenCost, Path: "allocation/compute"}
+		default:
+			continue
+		}
+		for _, p := range s.Spec.Ports {
+			if p.Port == 9003 || p.Port == 9090 {
+				candidate.Namespace, candidate.Service = s.Namespace, s.Name
+				candidate.Port = strconv.Itoa(int(p.Port))
+				break
+			}
+		}
+		if candidate.Service == "" {
+			continue
+		}
+		params := url.Values{"window": {"1h"}, "aggregate": {"cluster"}}
+		if _, err := h.proxyProvider(ctx, client, candidate, params); err == nil {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// proxyProvider queries the allocation API through the service proxy
+func (h *CostHandler) proxyProvider(ctx context.Context, client kubernetes.Interface, provider *costProvider, params url.Values) ([]byte, error) {
+	req := client.CoreV1().RESTClient().Get().
+		Namespace(provider.Namespace).
+		Resource("services").
+		Name(provider.Service + ":" + provider.Port).
+		SubResource("proxy").
+		Suffix(provider.Path)
+	for k, values := range params {
+		for _, v := range values {
+			req = req.Param(k, v)
+		}
+	}
+	return req.DoRaw(ctx)
+}
+
+// getExternal queries an externally configured OpenCost allocation API
+func (h *CostHandler) getExternal(ctx context.Context, params url.Values) ([]byte, error) {
+	endpoint := strings.TrimSuffix(h.config.OpenCostURL, "/") + "/allocation/compute?" + params.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("opencost returned %s", resp.Status)
+	}
+	return body, nil
+}
+
+// parseAllocations converts an OpenCost/Kubecost allocation response into the unified shape
+func parseAllocations(raw []byte) ([]CostAllocation, float64, error) {
+	type allocation struct {
+		Name             string  `json:"name"`
+		CPUCost          float64 `json:"cpuCost"`
+		RAMCost          float64 `json:"ramCost"`
+		PVCost           float64 `json:"pvCost"`
+		GPUCost          float64 `json:"gpuCost"`
+		NetworkCost      float64 `json:"networkCost"`
+		LoadBalancerCost float64 `json:"loadBalancerCost"`
+		SharedCost       float64 `json:"sharedCost"`
+		ExternalCost     float64 `json:"externalCost"`
+		TotalCost        float64 `json:"totalCost"`
+		TotalEfficiency  float64 `json:"totalEfficiency"`
+		Properties       struct {
+			Namespace string `json:"namespace"`
+		} `json:"properties"`
+	}
+	var resp struct {
+		Code    int                     `json:"code"`
+		Message string                  `json:"message"`
+		Data    []map[string]allocation `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, 0, err
+	}
+	if resp.Code != 0 && resp.Code != http.StatusOK {
+		return nil, 0, fmt.Errorf("allocation API error: %s", resp.Message)
+	}
+
+	// The API returns one set per step; sum them over the window
+	byName := map[string]*CostAllocation{}
+	idle := 0.0
+	for _, set := range resp.Data {
+		for name, a := range set {
+			if name == idleAllocationName {
+				idle += a.TotalCost
+				continue
+			}
+			entry := byName[name]
+			if entry == nil {
+				entry = &CostAllocation{Name: name, Namespace: a.Properties.Namespace}
+				byName[name] = entry
+			}
+			entry.CPUCost += a.CPUCost
+			entry.MemoryCost += a.RAMCost
+			entry.StorageCost += a.PVCost
+			entry.GPUCost += a.GPUCost
+			entry.NetworkCost += a.NetworkCost + a.LoadBalancerCost
+			entry.SharedCost += a.SharedCost + a.ExternalCost
+			entry.TotalCost += a.TotalCost
+			if a.TotalEfficiency > 0 {
+				efficiency := a.TotalEfficiency
+				entry.Efficiency = &efficiency
+			}
+		}
+	}
+
+	allocations := make([]CostAllocation, 0, len(byName))
+	for _, entry := range byName {
+		allocations = append(allocations, *entry)
+	}
+	return allocations, idle, nil
+}
+
+// parseWindow accepts OpenCost style windows (e.g. 24h, 7d) and returns their duration
+func parseWindow(window string) (time.Duration, error) {
+	if strings.HasSuffix(window, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(window, "d"))
+		if err != nil || days <= 0 {
+			return 0, fmt.Errorf("invalid window %q", window)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(window)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid window %q", window)
+	}
+	return d, nil
+}
+
+// workloadName resolves the top-level controller of a pod, collapsing ReplicaSets into their Deployment
+func workloadName(pod *v1.Pod) string {
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil {
+		return pod.Name
+	}
+	if ref.Kind == "ReplicaSet" {
+		if hash := pod.Labels["pod-template-hash"]; hash != "" {
+			return strings.TrimSuffix(ref.Name, "-"+hash)
+		}
+	}
+	return ref.Name
+}
+
+// estimateCosts prices current resource requests over the window, attributing unrequested node capacity to idle
+func (h *CostHandler) estimateCosts(ctx context.Context, client kubernetes.Interface, aggregate string, window time.Duration) ([]CostAllocation, float64, error) {
+	hours := window.Hours()
+	cpuPrice := h.config.CPUCoreHourly * hours
+	memPrice := h.config.MemoryGBHourly * hours
+	storagePrice := h.config.StorageGBMonthly * hours / hoursPerMonth
+
+	pods, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, 0, err
+	}
+	byName := map[string]*CostAllocation{}
+	requestedCost := 0.0
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		cpu, mem := 0.0, 0.0
+		for _, container := range pod.Spec.Containers {
+			cpu += container.Resources.Requests.Cpu().AsApproximateFloat64()
+			mem += container.Resources.Requests.Memory().AsApproximateFloat64() / (1 << 30)
+		}
+
+		name := pod.Namespace
+		if aggregate == "controller" {
+			name = pod.Namespace + "/" + workloadName(pod)
+		}
+		entry := byName[name]
+		if entry == nil {
+			entry = &CostAllocation{Name: name, Namespace: pod.Namespace}
+			byName[name] = entry
+		}
+		entry.CPUCost += cpu * cpuPrice
+		entry.MemoryCost += mem * memPrice
+		entry.TotalCost += cpu*cpuPrice + mem*memPrice
+		requestedCost += cpu*cpuPrice + mem*memPrice
+	}
+
+	// Persistent volume claims are charged to their namespace
+	if pvcs, err := client.CoreV1().PersistentVolumeClaims("").List(ctx, metav1.ListOptions{}); err == nil && aggregate == "namespace" {
+		for _, pvc := range pvcs.Items {
+			size := pvc.Spec.Resources.Requests.Storage().AsApproximateFloat64() / (1 << 30)
+			entry := byName[pvc.Namespace]
+			if entry == nil {
+				entry = &CostAllocation{Name: pvc.Namespace, Namespace: pvc.Namespace}
+				byName[pvc.Namespace] = entry
+			}
+			entry.StorageCost += size * storagePrice
+			entry.TotalCost += size * storagePrice
+		}
+	}
+
+	nodeCost := 0.0
+	if nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{}); err == nil {
+		for _, node := range nodes.Items {
+			cpu := node.Status.Allocatable.Cpu().AsApproximateFloat64()
+			mem := node.Status.Allocatable.Memory().AsApproximateFloat64() / (1 << 30)
+			nodeCost += cpu*cpuPrice + mem*memPrice
+		}
+	}
+	idle := nodeCost - requestedCost
+	if idle < 0 {
+		idle = 0
+	}
+
+	allocations := make([]CostAllocation, 0, len(byName))
+	for _, entry := range byName {
+		allocations = append(allocations, *entry)
+	}
+	return allocations, idle, nil
+}
+
+// GetCosts returns cost per namespace or workload from OpenCost/Kubecost, or a request-based estimate
+// @Summary Get cost allocation
+// @Description Returns actual cost per namespace or workload with idle cost attribution when OpenCost or Kubecost is installed (or OPENCOST_URL is set), otherwise a request-based estimate using configured prices. The response shape is the same for every source.
+// @Tags Cost
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param window query string false "Time window such as 24h or 7d (default 24h)"
+// @Param aggregate query string false "namespace (default) or controller"
+// @Param namespace query string false "Only return allocations in this namespace"
+// @Param shareIdle query bool false "Distribute idle cost across allocations (OpenCost/Kubecost only)"
+// @Success 200 {object} CostReport "Cost report"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/cost/allocation [get]
+func (h *CostHandler) GetCosts(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	client, err := h.getClient(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for cost allocation")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
+
+	window := c.DefaultQuery("window", "24h")
+	windowDuration, err := parseWindow(window)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	aggregate := c.DefaultQuery("aggregate", "namespace")
+	if aggregate != "namespace" && aggregate != "controller" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "aggregate must be namespace or controller"})
+		return
+	}
+
+	_, costSpan := h.tracingHelper.StartDataProcessingSpan(ctx, "compute-cost-allocation")
+	defer costSpan.End()
+
+	key := c.Query("config") + "|" + c.Query("cluster")
+	report, err := h.ComputeReport(c.Request.Context(), client, key, window, windowDuration, aggregate, c.Query("namespace"), c.Query("shareIdle") == "true")
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to estimate costs")
+		h.tracingHelper.RecordError(costSpan, err, "Failed to estimate costs")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(costSpan, fmt.Sprintf("Computed %d allocations from %s", len(report.Allocations), report.Source))
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ComputeReport builds a cost report from OpenCost/Kubecost, falling back to the request-based estimate.
+// key identifies the cluster for provider discovery caching.
+func (h *CostHandler) ComputeReport(ctx context.Context, client kubernetes.Interface, key, window string, windowDuration time.Duration, aggregate, namespace string, shareIdle bool) (*CostReport, error) {
+	report := CostReport{Window: window, Aggregate: aggregate, Currency: "USD"}
+	params := url.Values{
+		"window":      {window},
+		"aggregate":   {aggregate},
+		"includeIdle": {"true"},
+		"idle":        {"true"},
+		"shareIdle":   {strconv.FormatBool(shareIdle)},
+	}
+
+	var (
+		raw []byte
+		err error
+	)
+	if h.config.OpenCostURL != "" {
+		report.Source = sourceOpenCost
+		raw, err = h.getExternal(ctx, params)
+	} else {
+		cached, ok := h.providers.Load(key)
+		if !ok {
+			cached = h.discoverProvider(ctx, client)
+			h.providers.Store(key, cached)
+		}
+		if provider, _ := cached.(*costProvider); provider != nil {
+			report.Source = provider.Kind
+			raw, err = h.proxyProvider(ctx, client, provider, params)
+			if err != nil {
+				// Forget the provider so the next request rediscovers it
+				h.providers.Delete(key)
+			}
+		}
+	}
+
+	var allocations []CostAllocation
+	if raw != nil && err == nil {
+		allocations, report.IdleCost, err = parseAllocations(raw)
+	}
+	if report.Source == "" || err != nil {
+		if err != nil {
+			h.logger.WithError(err).Warn("Cost provider query failed, falling back to request-based estimate")
+			report.Note = fmt.Sprintf("%s unavailable (%v), showing request-based estimate", report.Source, err)
+		} else {
+			report.Note = "OpenCost/Kubecost not detected, showing request-based estimate"
+		}
+		report.Source = sourceEstimate
+		allocations, report.IdleCost, err = h.estimateCosts(ctx, client, aggregate, windowDuration)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	report.Allocations = []CostAllocation{}
+	for _, allocation := range allocations {
+		if namespace != "" && allocation.Namespace != namespace && allocation.Name != namespace {
+			continue
+		}
+		report.Allocations = append(report.Allocations, allocation)
+		report.TotalCost += allocation.TotalCost
+	}
+	if namespace == "" {
+		report.TotalCost += report.IdleCost
+	}
+	sort.Slice(report.Allocations, func(i, j int) bool { return report.Allocations[i].TotalCost > report.Allocations[j].TotalCost })
+	return &report, nil
+}
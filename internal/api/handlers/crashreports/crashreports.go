@@ -0,0 +1,159 @@
+package crashreports
+
+import (
+	"net/http"
+
+	"github.com/Facets-cloud/kube-dash/internal/crashreports"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CrashReportsHandler serves captured crash-loop reports and manages which clusters are watched
+type CrashReportsHandler struct {
+	watcher *crashreports.Watcher
+	store   *storage.KubeConfigStore
+	logger  *logger.Logger
+}
+
+// NewCrashReportsHandler creates a new crash reports handler
+func NewCrashReportsHandler(watcher *crashreports.Watcher, store *storage.KubeConfigStore, log *logger.Logger) *CrashReportsHandler {
+	return &CrashReportsHandler{
+		watcher: watcher,
+		store:   store,
+		logger:  log,
+	}
+}
+
+func (h *CrashReportsHandler) list(c *gin.Context, q crashreports.Query) {
+	if q.ConfigID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "config parameter is required"})
+		return
+	}
+	reports, err := h.watcher.List(q)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list crash reports")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, reports)
+}
+
+// GetPodCrashReports returns the crash reports captured for a pod
+// @Summary Get pod crash reports
+// @Description Returns the reports captured the first time each container of the pod entered CrashLoopBackOff: the termination reason, exit code and message, the tail of the crashed instance's logs and the pod's events at that moment. Reports are only captured for clusters with crash report capture enabled.
+// @Tags Workloads
+// @Produce json
+// @Param namespace path string true "Namespace name"
+// @Param name path string true "Pod name"
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Success 200 {array} crashreports.Report "Crash reports, most recent first"
+// @Failure 400 {object} map[string]string "Bad request - missing config"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/pods/{namespace}/{name}/crash-reports [get]
+func (h *CrashReportsHandler) GetPodCrashReports(c *gin.Context) {
+	h.list(c, crashreports.Query{
+		ConfigID:  c.Query("config"),
+		Cluster:   c.Query("cluster"),
+		Namespace: c.Param("namespace"),
+		Pod:       c.Param("name"),
+	})
+}
+
+// ListCrashReports returns the crash reports captured in a cluster
+// @Summary List crash reports
+// @Description Lists the crash reports captured in a cluster, optionally within one namespace, most recent first
+// @Tags Workloads
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param namespace query string false "Namespace name"
+// @Success 200 {array} crashreports.Report "Crash reports, most recent first"
+// @Failure 400 {object} map[string]string "Bad request - missing config"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/crash-reports [get]
+func (h *CrashReportsHandler) ListCrashReports(c *gin.Context) {
+	h.list(c, crashreports.Query{
+		ConfigID:  c.Query("config"),
+		Cluster:   c.Query("cluster"),
+		Namespace: c.Query("namespace"),
+	})
+}
+
+// ListWatchedClusters returns the clusters watched for crash loops
+// @Summary List crash report clusters
+// @Description Lists the clusters whose pods are watched for crash loops, with when each last captured a report and the last watch error
+// @Tags Workloads
+// @Produce json
+// @Success 200 {array} crashreports.WatchedCluster "Watched clusters"
+// @Security BearerAuth
+// @Router /api/v1/crash-reports/clusters [get]
+func (h *CrashReportsHandler) ListWatchedClusters(c *gin.Context) {
+	c.JSON(http.StatusOK, h.watcher.Clusters())
+}
+
+// EnableCapture starts watching a cluster for crash loops
+// @Summary Enable crash report capture
+// @Description Starts watching the pods of a cluster and capturing a report the first time each container enters CrashLoopBackOff
+// @Tags Workloads
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Success 200 {object} crashreports.WatchedCluster "Watched cluster"
+// @Failure 400 {object} map[string]string "Bad request - missing config"
+// @Failure 404 {object} map[string]string "Kubeconfig not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/crash-reports/clusters [post]
+func (h *CrashReportsHandler) EnableCapture(c *gin.Context) {
+	configID := c.Query("config")
+	cluster := c.Query("cluster")
+	if configID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "config parameter is required"})
+		return
+	}
+	if _, err := h.store.GetKubeConfig(configID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "kubeconfig not found"})
+		return
+	}
+	watched, err := h.watcher.Enable(configID, cluster)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to enable crash report capture")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.logger.WithField("config", configID).WithField("cluster", cluster).Info("Enabled crash report capture")
+	c.JSON(http.StatusOK, watched)
+}
+
+// DisableCapture stops watching a cluster for crash loops
+// @Summary Disable crash report capture
+// @Description Stops watching the pods of a cluster. Captured reports are kept until they expire.
+// @Tags Workloads
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Success 204 "Capture disabled"
+// @Failure 400 {object} map[string]string "Bad request - missing config"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/crash-reports/clusters [delete]
+func (h *CrashReportsHandler) DisableCapture(c *gin.Context) {
+	configID := c.Query("config")
+	if configID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "config parameter is required"})
+		return
+	}
+	if err := h.watcher.Disable(configID, c.Query("cluster")); err != nil {
+		h.logger.WithError(err).Error("Failed to disable crash report capture")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
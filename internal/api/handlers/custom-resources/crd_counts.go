@@ -0,0 +1,159 @@
+package custom_resources
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/transformers"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// instanceCountTTL keeps counts across SSE refreshes so every poll does not list every CRD
+	instanceCountTTL = time.Minute
+	// instanceCountWorkers bounds concurrent count requests against the API server
+	instanceCountWorkers = 8
+	// instanceCountPageSize is used when the API server does not report remainingItemCount
+	instanceCountPageSize = 500
+)
+
+type instanceCount struct {
+	count     int
+	err       string
+	fetchedAt time.Time
+}
+
+// instanceCountCache holds per-cluster CRD instance counts
+type instanceCountCache struct {
+	mu      sync.Mutex
+	entries map[string]instanceCount
+}
+
+func newInstanceCountCache() *instanceCountCache {
+	return &instanceCountCache{entries: map[string]instanceCount{}}
+}
+
+func (c *instanceCountCache) get(key string) (instanceCount, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || time.Since(entry.fetchedAt) > instanceCountTTL {
+		return instanceCount{}, false
+	}
+	return entry, true
+}
+
+func (c *instanceCountCache) put(key string, entry instanceCount) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry
+}
+
+// countInstances counts the objects of a resource with metadata-only lists. A single-item page
+// is enough when the API server reports remainingItemCount; otherwise the list is paged through.
+func countInstances(ctx context.Context, client metadata.Interface, gvr schema.GroupVersionResource) (int, error) {
+	list, err := client.Resource(gvr).List(ctx, metav1.ListOptions{Limit: 1})
+	if err != nil {
+		return 0, err
+	}
+	if list.Continue == "" {
+		return len(list.Items), nil
+	}
+	if list.RemainingItemCount != nil {
+		return len(list.Items) + int(*list.RemainingItemCount), nil
+	}
+
+	count := 0
+	opts := metav1.ListOptions{Limit: instanceCountPageSize}
+	for {
+		page, err := client.Resource(gvr).List(ctx, opts)
+		if err != nil {
+			return 0, err
+		}
+		count += len(page.Items)
+		if page.Continue == "" {
+			return count, nil
+		}
+		opts.Continue = page.Continue
+	}
+}
+
+// addInstanceCounts fills in the instance count of each CRD, reusing recent counts
+func (h *CustomResourceDefinitionsHandler) addInstanceCounts(ctx context.Context, restConfig *rest.Config, cacheKey string, crds []transformers.CustomResourceDefinition) {
+	client, err := metadata.NewForConfig(restConfig)
+	if err != nil {
+		for i := range crds {
+			crds[i].InstanceCountError = err.Error()
+		}
+		return
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < instanceCountWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				crd := &crds[i]
+				key := cacheKey + "/" + crd.Name
+				entry, ok := h.instanceCounts.get(key)
+				if !ok {
+					version := crd.VersionStatus.StorageVersion
+					if !containsString(crd.VersionStatus.Served, version) {
+						version = crd.ActiveVersion
+					}
+					gvr := schema.GroupVersionResource{Group: crd.Spec.Group, Version: version, Resource: crd.Spec.Names.Plural}
+					count, err := countInstances(ctx, client, gvr)
+					entry = instanceCount{count: count, fetchedAt: time.Now()}
+					if err != nil {
+						entry.err = err.Error()
+					}
+					if ctx.Err() != nil {
+						continue
+					}
+					h.instanceCounts.put(key, entry)
+				}
+				if entry.err != "" {
+					crd.InstanceCountError = entry.err
+					continue
+				}
+				count := entry.count
+				crd.InstanceCount = &count
+			}
+		}()
+	}
+	for i := range crds {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// withInstanceCounts adds instance counts when the request asks for them with counts=true
+func (h *CustomResourceDefinitionsHandler) withInstanceCounts(ctx context.Context, c *gin.Context, crds []transformers.CustomResourceDefinition) {
+	if c.Query("counts") != "true" || len(crds) == 0 {
+		return
+	}
+	restConfig, err := h.getRestConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Warn("Failed to get REST config for CRD instance counts")
+		return
+	}
+	h.addInstanceCounts(ctx, restConfig, c.Query("config")+"/"+c.Query("cluster"), crds)
+}
@@ -0,0 +1,369 @@
+package custom_resources
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/transformers"
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+	"github.com/Facets-cloud/kube-dash/internal/apitokens"
+	"github.com/Facets-cloud/kube-dash/internal/crdfavorites"
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// uncategorized names the bucket of CRDs that declare no category
+const uncategorized = "uncategorized"
+
+// NavigationEntry is a CRD as listed in the custom resources navigation
+type NavigationEntry struct {
+	Name       string   `json:"name"`
+	Kind       string   `json:"kind"`
+	Plural     string   `json:"plural"`
+	Group      string   `json:"group"`
+	Version    string   `json:"version"`
+	Scope      string   `json:"scope"`
+	Categories []string `json:"categories"`
+	QueryParam string   `json:"queryParam"`
+	Favorite   bool     `json:"favorite"`
+	Pinned     bool     `json:"pinned"`
+}
+
+// NavigationSection is an API group or category and the CRDs in it. CRDs are left out of
+// collapsed sections, which only carry their count.
+type NavigationSection struct {
+	Name    string            `json:"name"`
+	Count   int               `json:"count"`
+	Entries []NavigationEntry `json:"entries,omitempty"`
+}
+
+// Navigation organizes the CRDs of a cluster into sections, after the caller's favorites
+type Navigation struct {
+	Favorites []NavigationEntry   `json:"favorites"` // pinned first; favorites whose CRD is gone are left out
+	Sections  []NavigationSection `json:"sections"`
+	Total     int                 `json:"total"` // CRDs matching the search
+}
+
+// FavoriteRequest is the body of a favorite CRD update
+type FavoriteRequest struct {
+	Pinned bool `json:"pinned"`
+}
+
+// CRDNavigationHandler organizes CRDs by API group and category and keeps per-user favorites
+type CRDNavigationHandler struct {
+	favorites     *crdfavorites.Store
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	logger        *logger.Logger
+}
+
+// NewCRDNavigationHandler creates a new CRD navigation handler
+func NewCRDNavigationHandler(favorites *crdfavorites.Store, store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger) *CRDNavigationHandler {
+	return &CRDNavigationHandler{
+		favorites:     favorites,
+		store:         store,
+		clientFactory: clientFactory,
+		logger:        log,
+	}
+}
+
+// listCRDs lists the CRDs of the requested cluster in the frontend format
+func (h *CRDNavigationHandler) listCRDs(c *gin.Context) ([]transformers.CustomResourceDefinition, error) {
+	configID := c.Query("config")
+	if configID == "" {
+		return nil, fmt.Errorf("config parameter is required")
+	}
+	config, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, fmt.Errorf("config not found: %w", err)
+	}
+	dynamicClient, err := h.clientFactory.GetDynamicClientForConfig(config, c.Query("cluster"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dynamic client: %w", err)
+	}
+
+	gvr := schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	list, err := dynamicClient.Resource(gvr).List(c.Request.Context(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return transformers.TransformCustomResourceDefinitions(list.Items), nil
+}
+
+// favoritesByName returns the caller's favorites on the requested cluster keyed by CRD name
+func (h *CRDNavigationHandler) favoritesByName(c *gin.Context) (map[string]crdfavorites.Favorite, []crdfavorites.Favorite) {
+	owner, _ := apitokens.RequestOwner(c)
+	favorites, err := h.favorites.List(owner, c.Query("config"), c.Query("cluster"))
+	if err != nil {
+		h.logger.WithError(err).Warn("Failed to read CRD favorites")
+		return map[string]crdfavorites.Favorite{}, nil
+	}
+	byName := make(map[string]crdfavorites.Favorite, len(favorites))
+	for _, f := range favorites {
+		byName[f.Name] = f
+	}
+	return byName, favorites
+}
+
+// navigationEntry reduces a CRD to its navigation entry and marks the caller's favorites
+func navigationEntry(crd transformers.CustomResourceDefinition, favorites map[string]crdfavorites.Favorite) NavigationEntry {
+	favorite, ok := favorites[crd.Name]
+	return NavigationEntry{
+		Name:       crd.Name,
+		Kind:       crd.Spec.Names.Kind,
+		Plural:     crd.Spec.Names.Plural,
+		Group:      crd.Spec.Group,
+		Version:    crd.ActiveVersion,
+		Scope:      crd.Scope,
+		Categories: crd.Spec.Names.Categories,
+		QueryParam: crd.QueryParam,
+		Favorite:   ok,
+		Pinned:     ok && favorite.Pinned,
+	}
+}
+
+// matchesSearch reports whether a CRD's name, kind, group or categories contain the search term
+func matchesSearch(entry NavigationEntry, search string) bool {
+	if search == "" {
+		return true
+	}
+	fields := append([]string{entry.Name, entry.Kind, entry.Group}, entry.Categories...)
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), search) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildNavigation sorts CRDs into the sections returned by sectionsOf. Only the section named
+// by only is kept when it is set, and collapsed sections carry counts without entries.
+func buildNavigation(crds []transformers.CustomResourceDefinition, stored []crdfavorites.Favorite, favorites map[string]crdfavorites.Favorite, sectionsOf func(NavigationEntry) []string, search, only string, collapsed bool) *Navigation {
+	search = strings.ToLower(strings.TrimSpace(search))
+	nav := &Navigation{Favorites: []NavigationEntry{}, Sections: []NavigationSection{}}
+	byName := map[string]NavigationEntry{}
+	sections := map[string]*NavigationSection{}
+	for _, crd := range crds {
+		entry := navigationEntry(crd, favorites)
+		byName[entry.Name] = entry
+		if !matchesSearch(entry, search) {
+			continue
+		}
+		nav.Total++
+		for _, name := range sectionsOf(entry) {
+			if only != "" && name != only {
+				continue
+			}
+			section := sections[name]
+			if section == nil {
+				section = &NavigationSection{Name: name}
+				sections[name] = section
+			}
+			section.Count++
+			if !collapsed {
+				section.Entries = append(section.Entries, entry)
+			}
+		}
+	}
+
+	// Stored favorites are already ordered pinned first
+	for _, f := range stored {
+		if entry, ok := byName[f.Name]; ok {
+			nav.Favorites = append(nav.Favorites, entry)
+		}
+	}
+	for _, section := range sections {
+		sort.Slice(section.Entries, func(i, j int) bool {
+			if section.Entries[i].Kind != section.Entries[j].Kind {
+				return section.Entries[i].Kind < section.Entries[j].Kind
+			}
+			return section.Entries[i].Name < section.Entries[j].Name
+		})
+		nav.Sections = append(nav.Sections, *section)
+	}
+	sort.Slice(nav.Sections, func(i, j int) bool {
+		// The uncategorized bucket goes last
+		if (nav.Sections[i].Name == uncategorized) != (nav.Sections[j].Name == uncategorized) {
+			return nav.Sections[j].Name == uncategorized
+		}
+		return nav.Sections[i].Name < nav.Sections[j].Name
+	})
+	return nav
+}
+
+// serveNavigation lists the CRDs and answers with them sorted into sections
+func (h *CRDNavigationHandler) serveNavigation(c *gin.Context, only string, sectionsOf func(NavigationEntry) []string) {
+	crds, err := h.listCRDs(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list custom resource definitions for navigation")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	favorites, stored := h.favoritesByName(c)
+	c.JSON(http.StatusOK, buildNavigation(crds, stored, favorites, sectionsOf, c.Query("search"), only, c.Query("collapsed") == "true"))
+}
+
+// GetCRDGroups organizes CRDs by API group
+// @Summary Get CRDs by API group
+// @Description Organizes the Custom Resource Definitions of a cluster by API group, after the caller's favorites with pinned ones first. On clusters with hundreds of CRDs, collapsed=true returns only the count of each group, and group then loads the CRDs of the expanded group.
+// @Tags Custom Resources
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param owner query string false "Caller whose favorites are marked; API token callers are identified by their token"
+// @Param search query string false "Only CRDs whose name, kind, group or categories contain this text"
+// @Param group query string false "Only this API group"
+// @Param collapsed query bool false "Return group counts without the CRDs"
+// @Success 200 {object} Navigation "CRDs by API group"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Router /api/v1/customresourcedefinitions/groups [get]
+// @Security BearerAuth
+// @Security KubeConfig
+func (h *CRDNavigationHandler) GetCRDGroups(c *gin.Context) {
+	h.serveNavigation(c, c.Query("group"), func(entry NavigationEntry) []string {
+		return []string{entry.Group}
+	})
+}
+
+// GetCRDCategories organizes CRDs by category
+// @Summary Get CRDs by category
+// @Description Organizes the Custom Resource Definitions of a cluster by the categories they declare, such as all or categories defined by an operator, after the caller's favorites with pinned ones first. A CRD is listed under each of its categories; CRDs without one are listed under uncategorized.
+// @Tags Custom Resources
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param owner query string false "Caller whose favorites are marked; API token callers are identified by their token"
+// @Param search query string false "Only CRDs whose name, kind, group or categories contain this text"
+// @Param category query string false "Only this category"
+// @Param collapsed query bool false "Return category counts without the CRDs"
+// @Success 200 {object} Navigation "CRDs by category"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Router /api/v1/customresourcedefinitions/categories [get]
+// @Security BearerAuth
+// @Security KubeConfig
+func (h *CRDNavigationHandler) GetCRDCategories(c *gin.Context) {
+	h.serveNavigation(c, c.Query("category"), func(entry NavigationEntry) []string {
+		if len(entry.Categories) == 0 {
+			return []string{uncategorized}
+		}
+		return entry.Categories
+	})
+}
+
+// GetCRDFavorites lists the caller's favorite CRDs
+// @Summary List favorite CRDs
+// @Description Lists the caller's favorite Custom Resource Definitions on a cluster, pinned ones first
+// @Tags Custom Resources
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param owner query string false "Caller whose favorites are listed; API token callers are identified by their token"
+// @Success 200 {array} crdfavorites.Favorite "Favorite CRDs"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/customresourcedefinitions/favorites [get]
+// @Security BearerAuth
+func (h *CRDNavigationHandler) GetCRDFavorites(c *gin.Context) {
+	configID := c.Query("config")
+	if configID == "" {
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "config parameter is required")
+		return
+	}
+	owner, _ := apitokens.RequestOwner(c)
+	favorites, err := h.favorites.List(owner, configID, c.Query("cluster"))
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list CRD favorites")
+		utils.RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, favorites)
+}
+
+// SetCRDFavorite marks a CRD as a favorite of the caller
+// @Summary Favorite a CRD
+// @Description Marks a Custom Resource Definition as a favorite of the caller on a cluster, optionally pinned to the top of the navigation. Setting an existing favorite updates whether it is pinned.
+// @Tags Custom Resources
+// @Accept json
+// @Produce json
+// @Param name path string true "CRD name"
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param owner query string false "Caller whose favorite is set; API token callers are identified by their token"
+// @Param body body FavoriteRequest false "Whether the favorite is pinned"
+// @Success 200 {object} crdfavorites.Favorite "Stored favorite"
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
+// @Failure 404 {object} map[string]string "Kubeconfig not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/customresourcedefinitions/favorites/{name} [put]
+// @Security BearerAuth
+func (h *CRDNavigationHandler) SetCRDFavorite(c *gin.Context) {
+	configID := c.Query("config")
+	if configID == "" {
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "config parameter is required")
+		return
+	}
+	if _, err := h.store.GetKubeConfig(configID); err != nil {
+		utils.RespondErrorMessage(c, http.StatusNotFound, "kubeconfig not found")
+		return
+	}
+	var req FavoriteRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.RespondErrorMessage(c, http.StatusBadRequest, "Invalid request body: "+err.Error())
+			return
+		}
+	}
+	owner, _ := apitokens.RequestOwner(c)
+	favorite := &crdfavorites.Favorite{Owner: owner, ConfigID: configID, Cluster: c.Query("cluster"), Name: c.Param("name"), Pinned: req.Pinned}
+	if err := favorite.Validate(); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	if err := h.favorites.Set(favorite); err != nil {
+		h.logger.WithError(err).Error("Failed to store CRD favorite")
+		utils.RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, favorite)
+}
+
+// DeleteCRDFavorite removes a CRD from the caller's favorites
+// @Summary Unfavorite a CRD
+// @Description Removes a Custom Resource Definition from the caller's favorites on a cluster
+// @Tags Custom Resources
+// @Param name path string true "CRD name"
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param owner query string false "Caller whose favorite is removed; API token callers are identified by their token"
+// @Success 204 "Favorite removed"
+// @Failure 400 {object} map[string]string "Bad request - missing parameters"
+// @Failure 404 {object} map[string]string "Not a favorite"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/customresourcedefinitions/favorites/{name} [delete]
+// @Security BearerAuth
+func (h *CRDNavigationHandler) DeleteCRDFavorite(c *gin.Context) {
+	configID := c.Query("config")
+	if configID == "" {
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "config parameter is required")
+		return
+	}
+	owner, _ := apitokens.RequestOwner(c)
+	if err := h.favorites.Delete(owner, configID, c.Query("cluster"), c.Param("name")); err != nil {
+		if errors.Is(err, storage.ErrDocumentNotFound) {
+			utils.RespondErrorMessage(c, http.StatusNotFound, "not a favorite")
+			return
+		}
+		h.logger.WithError(err).Error("Failed to delete CRD favorite")
+		utils.RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
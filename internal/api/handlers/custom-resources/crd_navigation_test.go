@@ -0,0 +1,67 @@
+package custom_resources
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Facets-cloud/kube-dash/internal/crdfavorites"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/internal/testharness"
+)
+
+func TestCRDNavigation(t *testing.T) {
+	h := testharness.New(t, "crds.yaml")
+	handler := NewCRDNavigationHandler(crdfavorites.NewStore(storage.NewDocumentStore(nil), h.Logger), h.Store, h.Factory, h.Logger)
+	favoriteRoute := "/api/v1/customresourcedefinitions/favorites/:name"
+
+	if w := h.Do(http.MethodPut, favoriteRoute, handler.SetCRDFavorite, "/api/v1/customresourcedefinitions/favorites/widgets.example.com?owner=alice", nil); w.Code != http.StatusOK {
+		t.Fatalf("favorite answered %d: %s", w.Code, w.Body.String())
+	}
+	pinned := "/api/v1/customresourcedefinitions/favorites/issuers.cert-manager.io?owner=alice"
+	var favorite crdfavorites.Favorite
+	h.DoJSON(http.MethodPut, favoriteRoute, handler.SetCRDFavorite, pinned, strings.NewReader(`{"pinned": true}`), &favorite)
+	if !favorite.Pinned {
+		t.Errorf("favorite = %+v, want pinned", favorite)
+	}
+
+	route := "/api/v1/customresourcedefinitions/groups"
+	var groups Navigation
+	h.DoJSON(http.MethodGet, route, handler.GetCRDGroups, route+"?owner=alice", nil, &groups)
+	if groups.Total != 4 || len(groups.Sections) != 2 {
+		t.Fatalf("unexpected groups: %+v", groups)
+	}
+	if section := groups.Sections[0]; section.Name != "cert-manager.io" || section.Count != 2 || section.Entries[0].Kind != "Certificate" {
+		t.Errorf("unexpected first group: %+v", section)
+	}
+	if len(groups.Favorites) != 2 || groups.Favorites[0].Name != "issuers.cert-manager.io" || !groups.Favorites[0].Pinned {
+		t.Errorf("unexpected favorites: %+v", groups.Favorites)
+	}
+
+	var collapsed Navigation
+	h.DoJSON(http.MethodGet, route, handler.GetCRDGroups, route+"?group=example.com&collapsed=true", nil, &collapsed)
+	if len(collapsed.Sections) != 1 || collapsed.Sections[0].Count != 2 || collapsed.Sections[0].Entries != nil || len(collapsed.Favorites) != 0 {
+		t.Errorf("unexpected collapsed group: %+v", collapsed)
+	}
+
+	route = "/api/v1/customresourcedefinitions/categories"
+	var categories Navigation
+	h.DoJSON(http.MethodGet, route, handler.GetCRDCategories, route, nil, &categories)
+	names := []string{}
+	for _, section := range categories.Sections {
+		names = append(names, section.Name)
+	}
+	if len(names) != 3 || names[0] != "all" || names[1] != "cert-manager" || names[2] != uncategorized {
+		t.Errorf("categories = %v, want [all cert-manager uncategorized]", names)
+	}
+
+	var searched Navigation
+	h.DoJSON(http.MethodGet, route, handler.GetCRDCategories, route+"?search=ISSUER", nil, &searched)
+	if searched.Total != 1 || len(searched.Sections) != 1 || searched.Sections[0].Entries[0].Name != "issuers.cert-manager.io" {
+		t.Errorf("unexpected search result: %+v", searched)
+	}
+
+	if w := h.Do(http.MethodPut, favoriteRoute, handler.SetCRDFavorite, "/api/v1/customresourcedefinitions/favorites/widgets", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid CRD name answered %d, want 400", w.Code)
+	}
+}
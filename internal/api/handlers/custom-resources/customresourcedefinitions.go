@@ -17,31 +17,34 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
 // CustomResourceDefinitionsHandler handles CustomResourceDefinitions operations
 type CustomResourceDefinitionsHandler struct {
-	store         *storage.KubeConfigStore
-	clientFactory *k8s.ClientFactory
-	logger        *logger.Logger
-	sseHandler    *utils.SSEHandler
-	tracingHelper *tracing.TracingHelper
+	store          *storage.KubeConfigStore
+	clientFactory  *k8s.ClientFactory
+	logger         *logger.Logger
+	sseHandler     *utils.SSEHandler
+	tracingHelper  *tracing.TracingHelper
+	instanceCounts *instanceCountCache
 }
 
 // NewCustomResourceDefinitionsHandler creates a new CustomResourceDefinitionsHandler
 func NewCustomResourceDefinitionsHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger) *CustomResourceDefinitionsHandler {
 	return &CustomResourceDefinitionsHandler{
-		store:         store,
-		clientFactory: clientFactory,
-		logger:        log,
-		sseHandler:    utils.NewSSEHandler(log),
-		tracingHelper: tracing.GetTracingHelper(),
+		store:          store,
+		clientFactory:  clientFactory,
+		logger:         log,
+		sseHandler:     utils.NewSSEHandler(log),
+		tracingHelper:  tracing.GetTracingHelper(),
+		instanceCounts: newInstanceCountCache(),
 	}
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *CustomResourceDefinitionsHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *CustomResourceDefinitionsHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -64,6 +67,26 @@ func (h *CustomResourceDefinitionsHandler) getClientAndConfig(c *gin.Context) (*
 
 // getDynamicClient gets the dynamic client for custom resources
 func (h *CustomResourceDefinitionsHandler) getDynamicClient(c *gin.Context) (dynamic.Interface, error) {
+	configID := c.Query("config")
+	if configID == "" {
+		return nil, fmt.Errorf("config parameter is required")
+	}
+
+	config, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, fmt.Errorf("config not found: %w", err)
+	}
+
+	dynamicClient, err := h.clientFactory.GetDynamicClientForConfig(config, c.Query("cluster"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dynamic client: %w", err)
+	}
+
+	return dynamicClient, nil
+}
+
+// getRestConfig builds the REST config for the requested config ID and cluster
+func (h *CustomResourceDefinitionsHandler) getRestConfig(c *gin.Context) (*rest.Config, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -102,23 +125,18 @@ func (h *CustomResourceDefinitionsHandler) getDynamicClient(c *gin.Context) (dyn
 		return nil, fmt.Errorf("failed to create client config: %w", err)
 	}
 
-	// Create dynamic client
-	dynamicClient, err := dynamic.NewForConfig(restConfig)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
-	}
-
-	return dynamicClient, nil
+	return restConfig, nil
 }
 
 // GetCustomResourceDefinitions returns all CRDs
 // @Summary Get Custom Resource Definitions
-// @Description Get all Custom Resource Definitions (CRDs) in the cluster
+// @Description Get all Custom Resource Definitions (CRDs) in the cluster with their served and stored versions and whether a storage version migration is needed. With counts=true each CRD also carries its instance count.
 // @Tags Custom Resources
 // @Accept json
 // @Produce json
 // @Param config query string true "Kubernetes configuration ID"
 // @Param cluster query string false "Cluster name"
+// @Param counts query bool false "Include per-CRD instance counts"
 // @Success 200 {array} map[string]interface{} "List of Custom Resource Definitions"
 // @Failure 400 {object} map[string]string "Bad request"
 // @Failure 500 {object} map[string]string "Internal server error"
@@ -135,7 +153,7 @@ func (h *CustomResourceDefinitionsHandler) GetCustomResourceDefinitions(c *gin.C
 	dynamicClient, err := h.getDynamicClient(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get dynamic client for CRDs")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get dynamic client")
 		clientSpan.End()
 		h.tracingHelper.RecordError(span, err, "GetCustomResourceDefinitions failed")
@@ -156,7 +174,7 @@ func (h *CustomResourceDefinitionsHandler) GetCustomResourceDefinitions(c *gin.C
 	crdList, err := dynamicClient.Resource(gvr).List(apiCtx, metav1.ListOptions{})
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to list custom resource definitions")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		h.tracingHelper.RecordError(apiSpan, err, "Failed to list CRDs")
 		apiSpan.End()
 		h.tracingHelper.RecordError(span, err, "GetCustomResourceDefinitions failed")
@@ -178,6 +196,7 @@ func (h *CustomResourceDefinitionsHandler) GetCustomResourceDefinitions(c *gin.C
 
 	// Transform to frontend format
 	transformed := transformers.TransformCustomResourceDefinitions(crds)
+	h.withInstanceCounts(apiCtx, c, transformed)
 	h.tracingHelper.RecordSuccess(processingSpan, "Data processing completed")
 	processingSpan.End()
 
@@ -187,12 +206,13 @@ func (h *CustomResourceDefinitionsHandler) GetCustomResourceDefinitions(c *gin.C
 
 // GetCustomResourceDefinitionsSSE returns CRDs as Server-Sent Events with real-time updates
 // @Summary Get Custom Resource Definitions (SSE)
-// @Description Get all Custom Resource Definitions with real-time updates via Server-Sent Events
+// @Description Get all Custom Resource Definitions with real-time updates via Server-Sent Events, including served and stored versions and whether a storage version migration is needed. With counts=true each CRD also carries its instance count, refreshed at most once a minute.
 // @Tags Custom Resources
 // @Accept json
 // @Produce text/event-stream
 // @Param config query string true "Kubernetes configuration ID"
 // @Param cluster query string false "Cluster name"
+// @Param counts query bool false "Include per-CRD instance counts"
 // @Success 200 {array} map[string]interface{} "Stream of Custom Resource Definitions data"
 // @Failure 400 {object} map[string]string "Bad request"
 // @Failure 500 {object} map[string]string "Internal server error"
@@ -253,6 +273,7 @@ func (h *CustomResourceDefinitionsHandler) GetCustomResourceDefinitionsSSE(c *gi
 
 		// Transform to frontend format
 		transformed := transformers.TransformCustomResourceDefinitions(crds)
+		h.withInstanceCounts(apiCtx, c, transformed)
 		h.tracingHelper.RecordSuccess(processingSpan, "Data processing completed")
 		return transformed, nil
 	}
@@ -281,7 +302,7 @@ func (h *CustomResourceDefinitionsHandler) GetCustomResourceDefinitionsSSE(c *gi
 	}
 
 	// For non-SSE requests, return JSON
-	c.JSON(http.StatusOK, initialData)
+	h.sseHandler.SendJSON(c, initialData)
 	h.tracingHelper.RecordSuccess(span, "CRD SSE operation completed")
 }
 
@@ -315,7 +336,7 @@ func (h *CustomResourceDefinitionsHandler) GetCustomResourceDefinition(c *gin.Co
 	dynamicClient, err := h.getDynamicClient(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get dynamic client for CRD")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get dynamic client")
 		clientSpan.End()
 		h.tracingHelper.RecordError(span, err, "GetCustomResourceDefinition failed")
@@ -335,7 +356,7 @@ func (h *CustomResourceDefinitionsHandler) GetCustomResourceDefinition(c *gin.Co
 	crd, err := dynamicClient.Resource(gvr).Get(apiCtx, name, metav1.GetOptions{})
 	if err != nil {
 		h.logger.WithError(err).WithField("crd", name).Error("Failed to get custom resource definition")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		h.tracingHelper.RecordError(apiSpan, err, "Failed to get CRD")
 		apiSpan.End()
 		h.tracingHelper.RecordError(span, err, "GetCustomResourceDefinition failed")
@@ -347,7 +368,7 @@ func (h *CustomResourceDefinitionsHandler) GetCustomResourceDefinition(c *gin.Co
 	// Check if this is an SSE request (EventSource expects SSE format)
 	acceptHeader := c.GetHeader("Accept")
 	if acceptHeader == "text/event-stream" {
-		h.sseHandler.SendSSEResponse(c, crd)
+		h.sseHandler.SendSSEDetailResponse(c, crd, crd, dynamicClient.Resource(gvr).Watch)
 		h.tracingHelper.RecordSuccess(span, "CRD SSE response sent")
 		return
 	}
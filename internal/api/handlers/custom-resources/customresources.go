@@ -13,11 +13,11 @@ import (
 
 	"github.com/gin-gonic/gin"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/tools/clientcmd"
 )
 
 // CustomResourcesHandler handles CustomResources operations
@@ -45,7 +45,7 @@ func NewCustomResourcesHandler(store *storage.KubeConfigStore, clientFactory *k8
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *CustomResourcesHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *CustomResourcesHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -80,39 +80,40 @@ func (h *CustomResourcesHandler) getDynamicClient(c *gin.Context) (dynamic.Inter
 		return nil, fmt.Errorf("config not found: %w", err)
 	}
 
-	// Create a copy of the config and set the context to the specific cluster
-	configCopy := config.DeepCopy()
-
-	// Find the context that matches the cluster name
-	for contextName, context := range configCopy.Contexts {
-		if context.Cluster == cluster {
-			configCopy.CurrentContext = contextName
-			break
-		}
+	dynamicClient, err := h.clientFactory.GetDynamicClientForConfig(config, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dynamic client: %w", err)
 	}
 
-	// If no matching context found, use the first context
-	if configCopy.CurrentContext == "" && len(configCopy.Contexts) > 0 {
-		for contextName := range configCopy.Contexts {
-			configCopy.CurrentContext = contextName
-			break
-		}
-	}
+	return dynamicClient, nil
+}
 
-	// Create client config
-	clientConfig := clientcmd.NewDefaultClientConfig(*configCopy, &clientcmd.ConfigOverrides{})
-	restConfig, err := clientConfig.ClientConfig()
+// resolveResource fills in the preferred served version of a resource when none is given, using
+// the cluster's shared discovery cache. A resource missing from the cache invalidates it once, so
+// CRDs installed since discovery was cached are found.
+func (h *CustomResourcesHandler) resolveResource(c *gin.Context, group, version, resource string) (schema.GroupVersionResource, error) {
+	gvr := schema.GroupVersionResource{Group: group, Version: version, Resource: resource}
+	if version != "" {
+		return gvr, nil
+	}
+	config, err := h.store.GetKubeConfig(c.Query("config"))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client config: %w", err)
+		return gvr, fmt.Errorf("config not found: %w", err)
 	}
-
-	// Create dynamic client
-	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	cluster := c.Query("cluster")
+	mapper, _, err := h.clientFactory.GetRESTMapperForConfig(config, cluster)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+		return gvr, fmt.Errorf("failed to get REST mapper: %w", err)
 	}
-
-	return dynamicClient, nil
+	resolved, err := mapper.ResourceFor(gvr)
+	if meta.IsNoMatchError(err) {
+		h.clientFactory.InvalidateDiscovery(config, cluster)
+		if mapper, _, err = h.clientFactory.GetRESTMapperForConfig(config, cluster); err != nil {
+			return gvr, fmt.Errorf("failed to get REST mapper: %w", err)
+		}
+		resolved, err = mapper.ResourceFor(gvr)
+	}
+	return resolved, err
 }
 
 // GetCustomResources returns custom resources for a specific CRD
@@ -122,7 +123,7 @@ func (h *CustomResourcesHandler) getDynamicClient(c *gin.Context) (dynamic.Inter
 // @Accept json
 // @Produce json
 // @Param group query string true "Resource group"
-// @Param version query string true "Resource version"
+// @Param version query string false "Resource version (defaults to the preferred served version)"
 // @Param resource query string true "Resource name"
 // @Param namespace query string false "Namespace (if empty, returns cluster-wide resources)"
 // @Param config query string true "Kubernetes configuration ID"
@@ -146,9 +147,9 @@ func (h *CustomResourcesHandler) GetCustomResources(c *gin.Context) {
 	// Add resource attributes
 	h.tracingHelper.AddResourceAttributes(span, resource, "custom_resource", 1)
 
-	if group == "" || version == "" || resource == "" {
-		err := fmt.Errorf("group, version, and resource parameters are required")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if group == "" || resource == "" {
+		err := fmt.Errorf("group and resource parameters are required")
+		utils.RespondError(c, http.StatusBadRequest, err)
 		h.tracingHelper.RecordError(span, err, "GetCustomResources failed")
 		return
 	}
@@ -158,7 +159,7 @@ func (h *CustomResourcesHandler) GetCustomResources(c *gin.Context) {
 	dynamicClient, err := h.getDynamicClient(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get dynamic client for custom resources")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get dynamic client")
 		clientSpan.End()
 		h.tracingHelper.RecordError(span, err, "GetCustomResources failed")
@@ -167,13 +168,15 @@ func (h *CustomResourcesHandler) GetCustomResources(c *gin.Context) {
 	h.tracingHelper.RecordSuccess(clientSpan, "Dynamic client acquired")
 	clientSpan.End()
 
+	gvr, err := h.resolveResource(c, group, version, resource)
+	if err != nil {
+		utils.RespondError(c, http.StatusNotFound, err)
+		h.tracingHelper.RecordError(span, err, "GetCustomResources failed")
+		return
+	}
+
 	// Child span for Kubernetes API operations
 	apiCtx, apiSpan := h.tracingHelper.StartKubernetesAPISpan(clientCtx, "list", "custom_resources", namespace)
-	gvr := schema.GroupVersionResource{
-		Group:    group,
-		Version:  version,
-		Resource: resource,
-	}
 
 	var crList interface{}
 	var err2 error
@@ -186,7 +189,7 @@ func (h *CustomResourcesHandler) GetCustomResources(c *gin.Context) {
 
 	if err2 != nil {
 		h.logger.WithError(err2).Error("Failed to list custom resources")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err2.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err2)
 		h.tracingHelper.RecordError(apiSpan, err2, "Failed to list custom resources")
 		apiSpan.End()
 		h.tracingHelper.RecordError(span, err2, "GetCustomResources failed")
@@ -221,12 +224,12 @@ func (h *CustomResourcesHandler) GetCustomResources(c *gin.Context) {
 // @Accept json
 // @Produce text/event-stream
 // @Param group query string true "Resource group"
-// @Param version query string true "Resource version"
+// @Param version query string false "Resource version (defaults to the preferred served version)"
 // @Param resource query string true "Resource name"
 // @Param namespace query string false "Namespace (if empty, returns cluster-wide resources)"
 // @Param config query string true "Kubernetes configuration ID"
 // @Param cluster query string false "Cluster name"
-// @Success 200 {object} map[string]interface{} "Stream of custom resources data with additional printer columns"
+// @Success 200 {object} CustomResourcesPayload "Stream of custom resources data with additional printer columns"
 // @Failure 400 {object} map[string]string "Bad request - missing required parameters"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /api/v1/customresources/sse [get]
@@ -245,8 +248,8 @@ func (h *CustomResourcesHandler) GetCustomResourcesSSE(c *gin.Context) {
 	// Add resource attributes
 	h.tracingHelper.AddResourceAttributes(span, resource, "custom_resource", 1)
 
-	if group == "" || version == "" || resource == "" {
-		err := fmt.Errorf("group, version, and resource parameters are required")
+	if group == "" || resource == "" {
+		err := fmt.Errorf("group and resource parameters are required")
 		h.sseHandler.SendSSEError(c, http.StatusBadRequest, err.Error())
 		h.tracingHelper.RecordError(span, err, "GetCustomResourcesSSE failed")
 		return
@@ -266,10 +269,11 @@ func (h *CustomResourcesHandler) GetCustomResourcesSSE(c *gin.Context) {
 	h.tracingHelper.RecordSuccess(clientSpan, "Dynamic client acquired")
 	clientSpan.End()
 
-	gvr := schema.GroupVersionResource{
-		Group:    group,
-		Version:  version,
-		Resource: resource,
+	gvr, err := h.resolveResource(c, group, version, resource)
+	if err != nil {
+		h.sseHandler.SendSSEError(c, http.StatusNotFound, err.Error())
+		h.tracingHelper.RecordError(span, err, "GetCustomResourcesSSE failed")
+		return
 	}
 
 	// Helper to fetch and shape list response
@@ -304,12 +308,12 @@ func (h *CustomResourcesHandler) GetCustomResourcesSSE(c *gin.Context) {
 		}
 
 		// Best-effort: derive additional printer columns from CRD
-		apc, _ := h.getAdditionalPrinterColumns(c, dynamicClient, group, resource, version)
+		apc, _ := h.getAdditionalPrinterColumns(c, dynamicClient, group, resource, gvr.Version)
 
 		h.tracingHelper.RecordSuccess(processingSpan, "Data processing completed")
-		return gin.H{
-			"additionalPrinterColumns": apc,
-			"list":                     items,
+		return CustomResourcesPayload{
+			AdditionalPrinterColumns: apc,
+			List:                     items,
 		}, nil
 	}
 
@@ -335,7 +339,7 @@ func (h *CustomResourcesHandler) GetCustomResourcesSSE(c *gin.Context) {
 	}
 
 	// For non-SSE requests, return JSON
-	c.JSON(http.StatusOK, initialData)
+	h.sseHandler.SendJSON(c, initialData)
 	h.tracingHelper.RecordSuccess(span, "Custom resources SSE operation completed")
 }
 
@@ -374,7 +378,7 @@ func (h *CustomResourcesHandler) GetCustomResource(c *gin.Context) {
 
 	if group == "" || version == "" || resource == "" {
 		err := fmt.Errorf("group, version, and resource parameters are required")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		h.tracingHelper.RecordError(span, err, "GetCustomResource failed")
 		return
 	}
@@ -384,7 +388,7 @@ func (h *CustomResourcesHandler) GetCustomResource(c *gin.Context) {
 	dynamicClient, err := h.getDynamicClient(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get dynamic client for custom resource")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get dynamic client")
 		clientSpan.End()
 		h.tracingHelper.RecordError(span, err, "GetCustomResource failed")
@@ -401,18 +405,15 @@ func (h *CustomResourcesHandler) GetCustomResource(c *gin.Context) {
 		Resource: resource,
 	}
 
-	var cr interface{}
-	var err2 error
-
+	var client dynamic.ResourceInterface = dynamicClient.Resource(gvr)
 	if namespace != "" {
-		cr, err2 = dynamicClient.Resource(gvr).Namespace(namespace).Get(apiCtx, name, metav1.GetOptions{})
-	} else {
-		cr, err2 = dynamicClient.Resource(gvr).Get(apiCtx, name, metav1.GetOptions{})
+		client = dynamicClient.Resource(gvr).Namespace(namespace)
 	}
 
+	cr, err2 := client.Get(apiCtx, name, metav1.GetOptions{})
 	if err2 != nil {
 		h.logger.WithError(err2).WithField("custom_resource", name).Error("Failed to get custom resource")
-		c.JSON(http.StatusNotFound, gin.H{"error": err2.Error()})
+		utils.RespondError(c, http.StatusNotFound, err2)
 		h.tracingHelper.RecordError(apiSpan, err2, "Failed to get custom resource")
 		apiSpan.End()
 		h.tracingHelper.RecordError(span, err2, "GetCustomResource failed")
@@ -424,7 +425,7 @@ func (h *CustomResourcesHandler) GetCustomResource(c *gin.Context) {
 	// Check if this is an SSE request (EventSource expects SSE format)
 	acceptHeader := c.GetHeader("Accept")
 	if acceptHeader == "text/event-stream" {
-		h.sseHandler.SendSSEResponse(c, cr)
+		h.sseHandler.SendSSEDetailResponse(c, cr, cr, client.Watch)
 		h.tracingHelper.RecordSuccess(span, "Custom resource SSE response sent")
 		return
 	}
@@ -468,7 +469,7 @@ func (h *CustomResourcesHandler) GetCustomResourceYAML(c *gin.Context) {
 
 	if group == "" || version == "" || resource == "" {
 		err := fmt.Errorf("group, version, and resource parameters are required")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		h.tracingHelper.RecordError(span, err, "GetCustomResourceYAML failed")
 		return
 	}
@@ -478,7 +479,7 @@ func (h *CustomResourcesHandler) GetCustomResourceYAML(c *gin.Context) {
 	dynamicClient, err := h.getDynamicClient(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get dynamic client for custom resource YAML")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get dynamic client")
 		clientSpan.End()
 		h.tracingHelper.RecordError(span, err, "GetCustomResourceYAML failed")
@@ -494,7 +495,7 @@ func (h *CustomResourcesHandler) GetCustomResourceYAML(c *gin.Context) {
 	obj, err2 := dynamicClient.Resource(gvr).Namespace(namespace).Get(apiCtx, name, metav1.GetOptions{})
 	if err2 != nil {
 		h.logger.WithError(err2).WithField("custom_resource", name).Error("Failed to get custom resource for YAML")
-		c.JSON(http.StatusNotFound, gin.H{"error": err2.Error()})
+		utils.RespondError(c, http.StatusNotFound, err2)
 		h.tracingHelper.RecordError(apiSpan, err2, "Failed to get custom resource")
 		apiSpan.End()
 		h.tracingHelper.RecordError(span, err2, "GetCustomResourceYAML failed")
@@ -546,7 +547,7 @@ func (h *CustomResourcesHandler) GetCustomResourceYAMLByName(c *gin.Context) {
 
 	if group == "" || version == "" || resource == "" {
 		err := fmt.Errorf("group, version, and resource parameters are required")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		h.tracingHelper.RecordError(span, err, "GetCustomResourceYAMLByName failed")
 		return
 	}
@@ -556,7 +557,7 @@ func (h *CustomResourcesHandler) GetCustomResourceYAMLByName(c *gin.Context) {
 	dynamicClient, err := h.getDynamicClient(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get dynamic client for custom resource YAML")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get dynamic client")
 		clientSpan.End()
 		h.tracingHelper.RecordError(span, err, "GetCustomResourceYAMLByName failed")
@@ -578,7 +579,7 @@ func (h *CustomResourcesHandler) GetCustomResourceYAMLByName(c *gin.Context) {
 	}
 	if err2 != nil {
 		h.logger.WithError(err2).WithField("custom_resource", name).Error("Failed to get custom resource for YAML")
-		c.JSON(http.StatusNotFound, gin.H{"error": err2.Error()})
+		utils.RespondError(c, http.StatusNotFound, err2)
 		h.tracingHelper.RecordError(apiSpan, err2, "Failed to get custom resource")
 		apiSpan.End()
 		h.tracingHelper.RecordError(span, err2, "GetCustomResourceYAMLByName failed")
@@ -640,7 +641,7 @@ func (h *CustomResourcesHandler) GetCustomResourceEvents(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for custom resource events")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get client")
 		clientSpan.End()
 		h.tracingHelper.RecordError(span, err, "GetCustomResourceEvents failed")
@@ -740,7 +741,7 @@ func (h *CustomResourcesHandler) GetCustomResourceEventsByName(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for custom resource events")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get client")
 		clientSpan.End()
 		h.tracingHelper.RecordError(span, err, "GetCustomResourceEventsByName failed")
@@ -0,0 +1,50 @@
+package custom_resources
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Facets-cloud/kube-dash/internal/testharness"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+)
+
+func TestGetCustomResources(t *testing.T) {
+	h := testharness.New(t, "widgets.yaml")
+	handler := NewCustomResourcesHandler(h.Store, h.Factory, h.Logger)
+	route := "/api/v1/customresources"
+
+	var items []map[string]interface{}
+	h.DoJSON(http.MethodGet, route, handler.GetCustomResources, route+"?group=example.com&version=v1&resource=widgets&namespace=shop", nil, &items)
+	if len(items) != 1 {
+		t.Fatalf("got %d widgets in shop, want 1", len(items))
+	}
+	if metadata, _ := items[0]["metadata"].(map[string]interface{}); metadata["name"] != "blue" {
+		t.Errorf("widget = %v, want blue", items[0])
+	}
+
+	if w := h.Do(http.MethodGet, route, handler.GetCustomResources, route+"?version=v1&resource=widgets", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("missing group answered %d, want 400", w.Code)
+	}
+}
+
+func TestGetCustomResourcesPreferredVersion(t *testing.T) {
+	h := testharness.New(t, "widgets.yaml")
+	h.Clientset.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{{
+		GroupVersion: "example.com/v1",
+		APIResources: []metav1.APIResource{{Name: "widgets", Kind: "Widget", Namespaced: true, Verbs: metav1.Verbs{"list"}}},
+	}}
+	handler := NewCustomResourcesHandler(h.Store, h.Factory, h.Logger)
+	route := "/api/v1/customresources"
+
+	var items []map[string]interface{}
+	h.DoJSON(http.MethodGet, route, handler.GetCustomResources, route+"?group=example.com&resource=widgets", nil, &items)
+	if len(items) != 2 {
+		t.Errorf("got %d widgets without a version, want 2", len(items))
+	}
+
+	if w := h.Do(http.MethodGet, route, handler.GetCustomResources, route+"?group=example.com&resource=gadgets", nil); w.Code != http.StatusNotFound {
+		t.Errorf("unknown resource answered %d, want 404", w.Code)
+	}
+}
@@ -0,0 +1,159 @@
+package custom_resources
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/transformers"
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// CustomResourcesPayload is a custom resource list with the printer columns of its CRD, as sent
+// by the v1 list stream
+type CustomResourcesPayload struct {
+	AdditionalPrinterColumns []transformers.AdditionalPrinterColumn `json:"additionalPrinterColumns"`
+	List                     []interface{}                          `json:"list"`
+}
+
+// CustomResourceItem is a custom resource with its metadata lifted out. Spec and status follow
+// the schema of the CRD, so they are passed through as objects.
+type CustomResourceItem struct {
+	APIVersion        string                 `json:"apiVersion"`
+	Kind              string                 `json:"kind"`
+	Name              string                 `json:"name"`
+	Namespace         string                 `json:"namespace,omitempty"`
+	UID               string                 `json:"uid"`
+	ResourceVersion   string                 `json:"resourceVersion"`
+	CreationTimestamp time.Time              `json:"creationTimestamp"`
+	Labels            map[string]string      `json:"labels"`
+	Annotations       map[string]string      `json:"annotations"`
+	Columns           map[string]string      `json:"columns"` // printer column values by column name
+	Spec              map[string]interface{} `json:"spec,omitempty" swaggertype:"object"`
+	Status            map[string]interface{} `json:"status,omitempty" swaggertype:"object"`
+}
+
+// CustomResourceList is the custom resources of one CRD
+type CustomResourceList struct {
+	Group     string                                 `json:"group"`
+	Version   string                                 `json:"version"`
+	Resource  string                                 `json:"resource"`
+	Namespace string                                 `json:"namespace,omitempty"`
+	Columns   []transformers.AdditionalPrinterColumn `json:"columns"`
+	Items     []CustomResourceItem                   `json:"items"`
+	Total     int                                    `json:"total"`
+}
+
+// printerColumnValue renders a printer column of an object; missing fields render empty
+func printerColumnValue(object map[string]interface{}, path string) string {
+	jp := jsonpath.New("column").AllowMissingKeys(true)
+	if err := jp.Parse(fmt.Sprintf("{%s}", path)); err != nil {
+		return ""
+	}
+	var out bytes.Buffer
+	if err := jp.Execute(&out, object); err != nil {
+		return ""
+	}
+	return out.String()
+}
+
+// customResourceItem converts a custom resource, rendering the given printer columns
+func customResourceItem(obj *unstructured.Unstructured, columns []transformers.AdditionalPrinterColumn) CustomResourceItem {
+	item := CustomResourceItem{
+		APIVersion:        obj.GetAPIVersion(),
+		Kind:              obj.GetKind(),
+		Name:              obj.GetName(),
+		Namespace:         obj.GetNamespace(),
+		UID:               string(obj.GetUID()),
+		ResourceVersion:   obj.GetResourceVersion(),
+		CreationTimestamp: obj.GetCreationTimestamp().Time,
+		Labels:            obj.GetLabels(),
+		Annotations:       obj.GetAnnotations(),
+		Columns:           map[string]string{},
+	}
+	if item.Labels == nil {
+		item.Labels = map[string]string{}
+	}
+	if item.Annotations == nil {
+		item.Annotations = map[string]string{}
+	}
+	item.Spec, _, _ = unstructured.NestedMap(obj.Object, "spec")
+	item.Status, _, _ = unstructured.NestedMap(obj.Object, "status")
+	for _, column := range columns {
+		item.Columns[column.Name] = printerColumnValue(obj.Object, column.JSONPath)
+	}
+	return item
+}
+
+// ListCustomResourcesV2 returns the custom resources of a CRD with a stable schema
+// @Summary List custom resources
+// @Description Lists the custom resources of a CRD. Each item carries its metadata as fields, the values of the CRD's printer columns, and its spec and status as given by the CRD schema. Items are sorted by namespace and name.
+// @Tags Custom Resources
+// @Produce json
+// @Param group query string true "Resource group"
+// @Param version query string false "Resource version (defaults to the preferred served version)"
+// @Param resource query string true "Resource plural name"
+// @Param namespace query string false "Namespace (if empty, lists across all namespaces)"
+// @Param labelSelector query string false "Label selector"
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Success 200 {object} CustomResourceList "Custom resources"
+// @Failure 400 {object} map[string]string "Bad request - missing required parameters"
+// @Failure 404 {object} map[string]string "Resource not served by the cluster"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v2/customresources [get]
+func (h *CustomResourcesHandler) ListCustomResourcesV2(c *gin.Context) {
+	group, resource, namespace := c.Query("group"), c.Query("resource"), c.Query("namespace")
+	if group == "" || resource == "" {
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "group and resource parameters are required")
+		return
+	}
+
+	dynamicClient, err := h.getDynamicClient(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	gvr, err := h.resolveResource(c, group, c.Query("version"), resource)
+	if err != nil {
+		utils.RespondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	list, err := dynamicClient.Resource(gvr).Namespace(namespace).List(c.Request.Context(), metav1.ListOptions{LabelSelector: c.Query("labelSelector")})
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list custom resources")
+		utils.RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	columns, _ := h.getAdditionalPrinterColumns(c, dynamicClient, group, resource, gvr.Version)
+
+	result := CustomResourceList{
+		Group:     gvr.Group,
+		Version:   gvr.Version,
+		Resource:  gvr.Resource,
+		Namespace: namespace,
+		Columns:   columns,
+		Items:     make([]CustomResourceItem, 0, len(list.Items)),
+		Total:     len(list.Items),
+	}
+	for i := range list.Items {
+		result.Items = append(result.Items, customResourceItem(&list.Items[i], columns))
+	}
+	sort.Slice(result.Items, func(i, j int) bool {
+		if result.Items[i].Namespace != result.Items[j].Namespace {
+			return result.Items[i].Namespace < result.Items[j].Namespace
+		}
+		return result.Items[i].Name < result.Items[j].Name
+	})
+	c.JSON(http.StatusOK, result)
+}
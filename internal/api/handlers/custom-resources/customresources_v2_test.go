@@ -0,0 +1,36 @@
+package custom_resources
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Facets-cloud/kube-dash/internal/testharness"
+)
+
+func TestListCustomResourcesV2(t *testing.T) {
+	h := testharness.New(t, "widgets.yaml")
+	handler := NewCustomResourcesHandler(h.Store, h.Factory, h.Logger)
+	route := "/api/v2/customresources"
+
+	var list CustomResourceList
+	h.DoJSON(http.MethodGet, route, handler.ListCustomResourcesV2, route+"?group=example.com&version=v1&resource=widgets", nil, &list)
+	if list.Total != 2 || len(list.Items) != 2 || len(list.Columns) != 1 || list.Columns[0].Name != "Size" {
+		t.Fatalf("list = %+v, want both widgets with the Size column", list)
+	}
+	blue := list.Items[0]
+	if blue.Name != "blue" || blue.Namespace != "shop" || blue.Kind != "Widget" || blue.Columns["Size"] != "3" || blue.Spec["size"] == nil {
+		t.Errorf("first widget = %+v", blue)
+	}
+	if blue.Labels == nil || blue.Annotations == nil {
+		t.Errorf("labels and annotations should be empty maps, got %v and %v", blue.Labels, blue.Annotations)
+	}
+
+	h.DoJSON(http.MethodGet, route, handler.ListCustomResourcesV2, route+"?group=example.com&version=v1&resource=widgets&namespace=tools", nil, &list)
+	if list.Total != 1 || list.Items[0].Name != "green" || list.Items[0].Columns["Size"] != "1" {
+		t.Errorf("tools widgets = %+v", list.Items)
+	}
+
+	if w := h.Do(http.MethodGet, route, handler.ListCustomResourcesV2, route+"?version=v1&resource=widgets", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("missing group answered %d, want 400", w.Code)
+	}
+}
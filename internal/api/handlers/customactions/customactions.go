@@ -0,0 +1,240 @@
+package customactions
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+	"github.com/Facets-cloud/kube-dash/internal/apitokens"
+	"github.com/Facets-cloud/kube-dash/internal/audit"
+	"github.com/Facets-cloud/kube-dash/internal/customactions"
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// CustomActionsHandler serves the operator-defined actions on resources and runs them
+type CustomActionsHandler struct {
+	registry      *customactions.Registry
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	auditor       *audit.Recorder
+	logger        *logger.Logger
+}
+
+// NewCustomActionsHandler creates a new custom actions handler
+func NewCustomActionsHandler(registry *customactions.Registry, store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, auditor *audit.Recorder, log *logger.Logger) *CustomActionsHandler {
+	return &CustomActionsHandler{
+		registry:      registry,
+		store:         store,
+		clientFactory: clientFactory,
+		auditor:       auditor,
+		logger:        log,
+	}
+}
+
+// ActionSummary is what the UI needs to render an action; request templates and headers, which
+// may carry credentials, are left out
+type ActionSummary struct {
+	ID          string               `json:"id"`
+	Name        string               `json:"name"`
+	Description string               `json:"description,omitempty"`
+	Icon        string               `json:"icon,omitempty"`
+	Confirm     string               `json:"confirm,omitempty"`
+	Type        string               `json:"type"` // http or patch
+	Target      customactions.Target `json:"target"`
+}
+
+// ResourceRef identifies the resource an action runs on
+type ResourceRef struct {
+	Group     string `json:"group"` // empty for the core group
+	Kind      string `json:"kind" binding:"required"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name" binding:"required"`
+}
+
+func summarize(actions []customactions.Action) []ActionSummary {
+	summaries := make([]ActionSummary, 0, len(actions))
+	for _, a := range actions {
+		summaries = append(summaries, ActionSummary{
+			ID:          a.ID,
+			Name:        a.Name,
+			Description: a.Description,
+			Icon:        a.Icon,
+			Confirm:     a.Confirm,
+			Type:        a.Type(),
+			Target:      a.Target,
+		})
+	}
+	return summaries
+}
+
+// getResource resolves a resource's kind and fetches it, returning the dynamic client of its
+// kind and namespace for patch actions
+func (h *CustomActionsHandler) getResource(c *gin.Context, ref ResourceRef) (*unstructured.Unstructured, dynamic.ResourceInterface, error) {
+	configID := c.Query("config")
+	if configID == "" {
+		return nil, nil, fmt.Errorf("config parameter is required")
+	}
+	config, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("config not found: %w", err)
+	}
+	mapper, _, err := h.clientFactory.GetRESTMapperForConfig(config, c.Query("cluster"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get Kubernetes client: %w", err)
+	}
+	dynamicClient, err := h.clientFactory.GetDynamicClientForConfig(config, c.Query("cluster"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get dynamic client: %w", err)
+	}
+	mapping, err := mapper.RESTMapping(schema.GroupKind{Group: ref.Group, Kind: ref.Kind})
+	if err != nil {
+		return nil, nil, fmt.Errorf("unknown kind: %w", err)
+	}
+
+	var resource dynamic.ResourceInterface = dynamicClient.Resource(mapping.Resource)
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		if ref.Namespace == "" {
+			return nil, nil, fmt.Errorf("namespace is required for %s", ref.Kind)
+		}
+		resource = dynamicClient.Resource(mapping.Resource).Namespace(ref.Namespace)
+	}
+	obj, err := resource.Get(c.Request.Context(), ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	return obj, resource, nil
+}
+
+// ListActions returns all configured actions
+// @Summary List custom actions
+// @Description Lists the actions operators defined in the custom actions file (CUSTOM_ACTIONS_FILE), with the resources each one targets
+// @Tags Custom Actions
+// @Produce json
+// @Success 200 {array} customactions.ActionSummary "Configured actions"
+// @Security BearerAuth
+// @Router /api/v1/custom-actions [get]
+func (h *CustomActionsHandler) ListActions(c *gin.Context) {
+	c.JSON(http.StatusOK, summarize(h.registry.List()))
+}
+
+// GetResourceActions returns the actions that apply to a resource
+// @Summary Get the custom actions of a resource
+// @Description Returns the configured actions whose target selects the resource, by kind, namespace, labels, annotations and the kube-dash.io/actions opt-in annotation
+// @Tags Custom Actions
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param group query string false "API group; empty for the core group"
+// @Param kind query string true "Resource kind"
+// @Param namespace query string false "Namespace of namespaced resources"
+// @Param name query string true "Resource name"
+// @Success 200 {array} customactions.ActionSummary "Applicable actions"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 404 {object} utils.ErrorResponse "Resource not found"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/custom-actions/resource [get]
+func (h *CustomActionsHandler) GetResourceActions(c *gin.Context) {
+	ref := ResourceRef{Group: c.Query("group"), Kind: c.Query("kind"), Namespace: c.Query("namespace"), Name: c.Query("name")}
+	if ref.Kind == "" || ref.Name == "" {
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "kind and name parameters are required")
+		return
+	}
+	// Most kinds have no actions; skip the API calls for them
+	if !h.registry.TargetsKind(ref.Group, ref.Kind) {
+		c.JSON(http.StatusOK, []ActionSummary{})
+		return
+	}
+	obj, _, err := h.getResource(c, ref)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	c.JSON(http.StatusOK, summarize(h.registry.ForResource(obj)))
+}
+
+// RunAction runs an action on a resource
+// @Summary Run a custom action
+// @Description Runs a configured action on a resource: calls its HTTP endpoint or applies its annotation and label patch. Runs are recorded in the audit trail.
+// @Tags Custom Actions
+// @Accept json
+// @Produce json
+// @Param id path string true "Action ID"
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param resource body customactions.ResourceRef true "Resource to run the action on"
+// @Success 200 {object} customactions.Result "Action result"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 404 {object} utils.ErrorResponse "Action or resource not found"
+// @Failure 422 {object} utils.ErrorResponse "The action does not apply to the resource"
+// @Failure 502 {object} utils.ErrorResponse "The action's endpoint failed"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/custom-actions/{id}/run [post]
+func (h *CustomActionsHandler) RunAction(c *gin.Context) {
+	action, err := h.registry.Get(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusNotFound, err)
+		return
+	}
+	var ref ResourceRef
+	if err := c.ShouldBindJSON(&ref); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	obj, resource, err := h.getResource(c, ref)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	data := customactions.NewTemplateData(obj, c.Query("config"), c.Query("cluster"), apitokens.Actor(c))
+	result, err := h.registry.Run(c.Request.Context(), action, obj, resource, data)
+	h.record(c, action, obj, result, err)
+	switch {
+	case errors.Is(err, customactions.ErrNotApplicable):
+		utils.RespondError(c, http.StatusUnprocessableEntity, err)
+	case err != nil:
+		h.logger.WithError(err).WithField("action", action.ID).Warn("Custom action failed")
+		utils.RespondError(c, http.StatusBadGateway, err)
+	default:
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// record adds an action run to the audit trail
+func (h *CustomActionsHandler) record(c *gin.Context, action *customactions.Action, obj *unstructured.Unstructured, result *customactions.Result, err error) {
+	event := audit.Event{
+		Action:     "custom-action.run",
+		Outcome:    audit.OutcomeSuccess,
+		RemoteAddr: c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+		ConfigID:   c.Query("config"),
+		Cluster:    c.Query("cluster"),
+		Namespace:  obj.GetNamespace(),
+		Resource:   obj.GetKind() + "/" + obj.GetName(),
+		Details: map[string]string{
+			"action": action.ID,
+			"type":   action.Type(),
+			"actor":  apitokens.Actor(c),
+		},
+	}
+	if err != nil {
+		event.Outcome = audit.OutcomeFailure
+		event.Reason = err.Error()
+	}
+	if result != nil && result.StatusCode != 0 {
+		event.Details["statusCode"] = fmt.Sprint(result.StatusCode)
+	}
+	h.auditor.Record(event)
+}
@@ -0,0 +1,342 @@
+package dashboards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/handlers/metrics"
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+	"github.com/Facets-cloud/kube-dash/internal/apitokens"
+	"github.com/Facets-cloud/kube-dash/internal/dashboards"
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	defaultRange = time.Hour
+	// maxPoints bounds the samples per series when the step is derived from the range
+	maxPoints = 240
+	minStep   = 15 * time.Second
+	// panelConcurrency bounds the Prometheus queries a dashboard run issues at once
+	panelConcurrency = 4
+	panelTimeout     = 20 * time.Second
+)
+
+// DashboardsHandler manages saved PromQL dashboards and runs their panels
+type DashboardsHandler struct {
+	dashboards    *dashboards.Store
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	prometheus    metrics.PrometheusQuerier
+	sseHandler    *utils.SSEHandler
+	logger        *logger.Logger
+}
+
+// DashboardRun is one evaluation of all panels of a dashboard
+type DashboardRun struct {
+	DashboardID string                   `json:"dashboardId"`
+	Start       time.Time                `json:"start"`
+	End         time.Time                `json:"end"`
+	Step        string                   `json:"step"`
+	Panels      []dashboards.PanelResult `json:"panels"`
+}
+
+// NewDashboardsHandler creates a new dashboards handler
+func NewDashboardsHandler(store *dashboards.Store, kubeStore *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, prometheus metrics.PrometheusQuerier, log *logger.Logger) *DashboardsHandler {
+	return &DashboardsHandler{
+		dashboards:    store,
+		store:         kubeStore,
+		clientFactory: clientFactory,
+		prometheus:    prometheus,
+		sseHandler:    utils.NewSSEHandler(log),
+		logger:        log,
+	}
+}
+
+func (h *DashboardsHandler) dashboardError(c *gin.Context, err error) {
+	if errors.Is(err, storage.ErrDocumentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "dashboard not found"})
+		return
+	}
+	h.logger.WithError(err).Error("Dashboard operation failed")
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+// load returns a dashboard the caller may access. Dashboards owned by someone else are
+// hidden from callers authenticated with an API token.
+func (h *DashboardsHandler) load(c *gin.Context) (*dashboards.Dashboard, bool) {
+	d, err := h.dashboards.Get(c.Param("id"))
+	if err != nil {
+		h.dashboardError(c, err)
+		return nil, false
+	}
+	if owner, authenticated := apitokens.RequestOwner(c); authenticated && d.Owner != "" && d.Owner != owner {
+		h.dashboardError(c, storage.ErrDocumentNotFound)
+		return nil, false
+	}
+	return d, true
+}
+
+// ListDashboards returns saved dashboards
+// @Summary List dashboards
+// @Description Lists saved PromQL dashboards visible to the caller: its own and shared ones, optionally only those for a cluster. The owner is taken from the API token when one is used.
+// @Tags Dashboards
+// @Produce json
+// @Param owner query string false "Only the owner's and shared dashboards"
+// @Param config query string false "Kubeconfig ID"
+// @Param cluster query string false "Cluster name"
+// @Success 200 {array} dashboards.Dashboard "Dashboards"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/dashboards [get]
+func (h *DashboardsHandler) ListDashboards(c *gin.Context) {
+	owner, _ := apitokens.RequestOwner(c)
+	list, err := h.dashboards.List(dashboards.Filter{Owner: owner, ConfigID: c.Query("config"), Cluster: c.Query("cluster")})
+	if err != nil {
+		h.dashboardError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// GetDashboard returns a single dashboard
+// @Summary Get dashboard
+// @Description Returns a saved dashboard by ID
+// @Tags Dashboards
+// @Produce json
+// @Param id path string true "Dashboard ID"
+// @Success 200 {object} dashboards.Dashboard "Dashboard"
+// @Failure 404 {object} map[string]string "Dashboard not found"
+// @Security BearerAuth
+// @Router /api/v1/dashboards/{id} [get]
+func (h *DashboardsHandler) GetDashboard(c *gin.Context) {
+	d, ok := h.load(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, d)
+}
+
+// CreateDashboard saves a new dashboard
+// @Summary Create dashboard
+// @Description Saves a named dashboard of PromQL panels (query, legend template, unit, thresholds) for a cluster. Dashboards without an owner are shared.
+// @Tags Dashboards
+// @Accept json
+// @Produce json
+// @Param dashboard body dashboards.Dashboard true "Dashboard"
+// @Success 201 {object} dashboards.Dashboard "Created dashboard"
+// @Failure 400 {object} map[string]string "Bad request - invalid dashboard"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/dashboards [post]
+func (h *DashboardsHandler) CreateDashboard(c *gin.Context) {
+	var d dashboards.Dashboard
+	if err := c.ShouldBindJSON(&d); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	d.ID = ""
+	if owner, authenticated := apitokens.RequestOwner(c); authenticated {
+		d.Owner = owner
+	}
+	if err := d.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.dashboards.Save(&d); err != nil {
+		h.dashboardError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, d)
+}
+
+// UpdateDashboard replaces a dashboard
+// @Summary Update dashboard
+// @Description Replaces a dashboard, keeping its ID, owner and creation time
+// @Tags Dashboards
+// @Accept json
+// @Produce json
+// @Param id path string true "Dashboard ID"
+// @Param dashboard body dashboards.Dashboard true "Dashboard"
+// @Success 200 {object} dashboards.Dashboard "Updated dashboard"
+// @Failure 400 {object} map[string]string "Bad request - invalid dashboard"
+// @Failure 404 {object} map[string]string "Dashboard not found"
+// @Security BearerAuth
+// @Router /api/v1/dashboards/{id} [put]
+func (h *DashboardsHandler) UpdateDashboard(c *gin.Context) {
+	existing, ok := h.load(c)
+	if !ok {
+		return
+	}
+	var d dashboards.Dashboard
+	if err := c.ShouldBindJSON(&d); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	d.ID = existing.ID
+	d.Owner = existing.Owner
+	d.CreatedAt = existing.CreatedAt
+	if err := d.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.dashboards.Save(&d); err != nil {
+		h.dashboardError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, d)
+}
+
+// DeleteDashboard deletes a dashboard
+// @Summary Delete dashboard
+// @Description Deletes a saved dashboard
+// @Tags Dashboards
+// @Produce json
+// @Param id path string true "Dashboard ID"
+// @Success 200 {object} map[string]string "Dashboard deleted"
+// @Failure 404 {object} map[string]string "Dashboard not found"
+// @Security BearerAuth
+// @Router /api/v1/dashboards/{id} [delete]
+func (h *DashboardsHandler) DeleteDashboard(c *gin.Context) {
+	d, ok := h.load(c)
+	if !ok {
+		return
+	}
+	if err := h.dashboards.Delete(d.ID); err != nil {
+		h.dashboardError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Dashboard deleted"})
+}
+
+// runParams resolves the time range and step of a run from the query, falling back to the dashboard defaults
+func runParams(c *gin.Context, d *dashboards.Dashboard) (time.Duration, time.Duration, error) {
+	rangeValue := c.DefaultQuery("range", d.Range)
+	rng := defaultRange
+	if rangeValue != "" {
+		parsed, err := dashboards.ParseDuration(rangeValue)
+		if err != nil {
+			return 0, 0, err
+		}
+		rng = parsed
+	}
+	step := rng / maxPoints
+	if stepValue := c.DefaultQuery("step", d.Step); stepValue != "" {
+		parsed, err := dashboards.ParseDuration(stepValue)
+		if err != nil {
+			return 0, 0, err
+		}
+		step = parsed
+	}
+	if step < minStep {
+		step = minStep
+	}
+	return rng, step, nil
+}
+
+// run evaluates every panel of a dashboard concurrently. Panel failures are reported per panel.
+func (h *DashboardsHandler) run(ctx context.Context, client kubernetes.Interface, targetKey string, d *dashboards.Dashboard, rng, step time.Duration) DashboardRun {
+	end := time.Now()
+	start := end.Add(-rng)
+	result := DashboardRun{
+		DashboardID: d.ID,
+		Start:       start,
+		End:         end,
+		Step:        step.String(),
+		Panels:      make([]dashboards.PanelResult, len(d.Panels)),
+	}
+
+	sem := make(chan struct{}, panelConcurrency)
+	var wg sync.WaitGroup
+	for i := range d.Panels {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			panel := &d.Panels[i]
+			path := "/api/v1/query_range"
+			params := map[string]string{
+				"query": panel.Query,
+				"start": strconv.FormatInt(start.Unix(), 10),
+				"end":   strconv.FormatInt(end.Unix(), 10),
+				"step":  fmt.Sprintf("%ds", int(step.Seconds())),
+			}
+			if panel.Type == dashboards.PanelStat {
+				path = "/api/v1/query"
+				params = map[string]string{"query": panel.Query, "time": strconv.FormatInt(end.Unix(), 10)}
+			}
+
+			panelCtx, cancel := context.WithTimeout(ctx, panelTimeout)
+			defer cancel()
+			raw, err := h.prometheus.Query(panelCtx, client, targetKey, path, params)
+			if err == nil {
+				result.Panels[i], err = dashboards.ParseResult(panel, raw)
+			}
+			if err != nil {
+				result.Panels[i] = dashboards.PanelResult{PanelID: panel.ID, Series: []dashboards.Series{}, Error: err.Error()}
+			}
+		}(i)
+	}
+	wg.Wait()
+	return result
+}
+
+// RunDashboardSSE streams the results of all panels of a dashboard
+// @Summary Run dashboard
+// @Description Runs every panel query of a dashboard against the cluster's Prometheus and streams all results in one batched Server-Sent Events message, refreshed periodically. Stat panels run instant queries; a failing panel reports its error without failing the others. The dashboard's cluster is used unless config and cluster are given.
+// @Tags Dashboards
+// @Produce text/event-stream
+// @Param id path string true "Dashboard ID"
+// @Param config query string false "Kubeconfig ID, defaults to the dashboard's"
+// @Param cluster query string false "Cluster name, defaults to the dashboard's"
+// @Param range query string false "Time range such as 1h or 7d, defaults to the dashboard's or 1h"
+// @Param step query string false "Query resolution such as 30s, derived from the range by default"
+// @Success 200 {object} DashboardRun "Streamed panel results"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 404 {object} map[string]string "Dashboard not found"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/dashboards/{id}/run [get]
+func (h *DashboardsHandler) RunDashboardSSE(c *gin.Context) {
+	d, ok := h.load(c)
+	if !ok {
+		return
+	}
+	rng, step, err := runParams(c, d)
+	if err != nil {
+		h.sseHandler.SendSSEError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	configID, cluster := d.ConfigID, d.Cluster
+	if c.Query("config") != "" {
+		configID, cluster = c.Query("config"), c.Query("cluster")
+	}
+	cfg, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		h.sseHandler.SendSSEError(c, http.StatusBadRequest, "config not found: "+err.Error())
+		return
+	}
+	client, err := h.clientFactory.GetClientForConfig(cfg, cluster)
+	if err != nil {
+		h.sseHandler.SendSSEError(c, http.StatusBadRequest, "failed to get Kubernetes client: "+err.Error())
+		return
+	}
+
+	targetKey := configID + "|" + cluster
+	fetch := func() (interface{}, error) {
+		return h.run(c.Request.Context(), client, targetKey, d, rng, step), nil
+	}
+	initial, _ := fetch()
+	h.sseHandler.SendSSEResponseWithUpdates(c, initial, fetch)
+}
@@ -0,0 +1,273 @@
+package elevation
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Facets-cloud/kube-dash/internal/apitokens"
+	"github.com/Facets-cloud/kube-dash/internal/audit"
+	"github.com/Facets-cloud/kube-dash/internal/elevation"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ElevationHandler serves requests for temporary exec and delete rights and their approval
+type ElevationHandler struct {
+	requests *elevation.Store
+	store    *storage.KubeConfigStore
+	auditor  *audit.Recorder
+	logger   *logger.Logger
+}
+
+// NewElevationHandler creates a new elevated access handler
+func NewElevationHandler(requests *elevation.Store, store *storage.KubeConfigStore, auditor *audit.Recorder, log *logger.Logger) *ElevationHandler {
+	return &ElevationHandler{
+		requests: requests,
+		store:    store,
+		auditor:  auditor,
+		logger:   log,
+	}
+}
+
+// NewAccessRequest describes the rights being requested
+type NewAccessRequest struct {
+	ConfigID        string   `json:"configId" binding:"required"`
+	Cluster         string   `json:"cluster"`
+	Namespace       string   `json:"namespace" binding:"required"`
+	Actions         []string `json:"actions" binding:"required"` // exec and/or delete
+	Reason          string   `json:"reason" binding:"required"`
+	DurationMinutes int      `json:"durationMinutes"` // 0 uses the configured default
+}
+
+// DecisionRequest carries an approver's decision
+type DecisionRequest struct {
+	Note            string `json:"note"`
+	DurationMinutes int    `json:"durationMinutes"` // approval only; 0 grants the requested duration
+}
+
+func (h *ElevationHandler) requestError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, storage.ErrDocumentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "access request not found"})
+	case errors.Is(err, elevation.ErrNotPending), errors.Is(err, elevation.ErrNotApproved):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		h.logger.WithError(err).Error("Elevated access operation failed")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func (h *ElevationHandler) record(c *gin.Context, action string, req *elevation.Request) {
+	h.auditor.Record(audit.Event{
+		Action:     action,
+		Outcome:    audit.OutcomeSuccess,
+		Reason:     req.Reason,
+		RemoteAddr: c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+		ConfigID:   req.ConfigID,
+		Cluster:    req.Cluster,
+		Namespace:  req.Namespace,
+		Resource:   "AccessRequest/" + req.ID,
+		Details: map[string]string{
+			"requester": req.Requester,
+			"token":     req.TokenID,
+			"actions":   strings.Join(req.Actions, ","),
+			"actor":     apitokens.Actor(c),
+			"note":      req.DecisionNote,
+		},
+	})
+}
+
+// ListAccessRequests returns elevated access requests, newest first
+// @Summary List elevated access requests
+// @Description Lists requests for temporary exec or delete rights with their effective status (pending, approved, denied, revoked or expired). Callers using an elevation-bound API token only see their own requests.
+// @Tags Access Requests
+// @Produce json
+// @Param status query string false "Only requests with this status"
+// @Param config query string false "Only requests for this config ID"
+// @Param namespace query string false "Only requests for this namespace"
+// @Success 200 {array} elevation.Request "Access requests"
+// @Security BearerAuth
+// @Router /api/v1/access-requests [get]
+func (h *ElevationHandler) ListAccessRequests(c *gin.Context) {
+	filter := elevation.Filter{
+		Status:    c.Query("status"),
+		ConfigID:  c.Query("config"),
+		Namespace: c.Query("namespace"),
+	}
+	if token, ok := apitokens.FromContext(c); ok && token.RequireElevation {
+		filter.TokenID = token.ID
+	}
+	c.JSON(http.StatusOK, h.requests.List(filter))
+}
+
+// GetAccessRequest returns a single elevated access request
+// @Summary Get elevated access request
+// @Description Returns an elevated access request by ID with its effective status
+// @Tags Access Requests
+// @Produce json
+// @Param id path string true "Request ID"
+// @Success 200 {object} elevation.Request "Access request"
+// @Failure 404 {object} map[string]string "Request not found"
+// @Security BearerAuth
+// @Router /api/v1/access-requests/{id} [get]
+func (h *ElevationHandler) GetAccessRequest(c *gin.Context) {
+	req, err := h.requests.Get(c.Param("id"))
+	if err != nil {
+		h.requestError(c, err)
+		return
+	}
+	if token, ok := apitokens.FromContext(c); ok && token.RequireElevation && req.TokenID != token.ID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "access request not found"})
+		return
+	}
+	c.JSON(http.StatusOK, req)
+}
+
+// CreateAccessRequest files a request for temporary exec or delete rights on a namespace
+// @Summary Request elevated access
+// @Description Requests temporary exec (including debug pods) or delete rights on a namespace for the calling API token. The token must require elevation; an approver then grants the request for a bounded duration.
+// @Tags Access Requests
+// @Accept json
+// @Produce json
+// @Param request body NewAccessRequest true "Requested rights"
+// @Success 201 {object} elevation.Request "Pending access request"
+// @Failure 400 {object} map[string]string "Bad request - invalid request or caller does not need elevation"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/access-requests [post]
+func (h *ElevationHandler) CreateAccessRequest(c *gin.Context) {
+	token, ok := apitokens.FromContext(c)
+	if !ok || !token.RequireElevation {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "elevated access is requested with an API token that requires elevation"})
+		return
+	}
+	var body NewAccessRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if _, err := h.store.GetKubeConfig(body.ConfigID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown config: " + body.ConfigID})
+		return
+	}
+	if !token.AllowsCluster(body.ConfigID, body.Cluster) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "API token is not scoped to this cluster"})
+		return
+	}
+
+	requester := token.Owner
+	if requester == "" {
+		requester = token.Name
+	}
+	req := elevation.Request{
+		TokenID:         token.ID,
+		Requester:       requester,
+		ConfigID:        body.ConfigID,
+		Cluster:         body.Cluster,
+		Namespace:       body.Namespace,
+		Actions:         body.Actions,
+		Reason:          body.Reason,
+		DurationMinutes: body.DurationMinutes,
+	}
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.requests.Create(&req); err != nil {
+		h.requestError(c, err)
+		return
+	}
+	h.record(c, "elevation.request", &req)
+	c.JSON(http.StatusCreated, req)
+}
+
+// decide runs an approver action after checking the caller may approve
+func (h *ElevationHandler) decide(c *gin.Context, action string, apply func(id, approver string, body DecisionRequest) (*elevation.Request, error)) {
+	// Elevation-bound tokens are the requesters; they can never approve, including their own requests
+	if token, ok := apitokens.FromContext(c); ok && token.RequireElevation {
+		c.JSON(http.StatusForbidden, gin.H{"error": "API tokens that require elevation cannot decide access requests"})
+		return
+	}
+	var body DecisionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+			return
+		}
+	}
+	if body.DurationMinutes < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "durationMinutes must not be negative"})
+		return
+	}
+	req, err := apply(c.Param("id"), apitokens.Actor(c), body)
+	if err != nil {
+		h.requestError(c, err)
+		return
+	}
+	h.record(c, action, req)
+	c.JSON(http.StatusOK, req)
+}
+
+// ApproveAccessRequest grants a pending request
+// @Summary Approve elevated access request
+// @Description Grants a pending request for the requested duration, or a shorter one given by the approver, capped by the configured maximum. Callers using an elevation-bound API token cannot approve.
+// @Tags Access Requests
+// @Accept json
+// @Produce json
+// @Param id path string true "Request ID"
+// @Param decision body DecisionRequest false "Approval note and duration"
+// @Success 200 {object} elevation.Request "Approved request"
+// @Failure 403 {object} map[string]string "Caller cannot approve"
+// @Failure 404 {object} map[string]string "Request not found"
+// @Failure 409 {object} map[string]string "Request is not pending"
+// @Security BearerAuth
+// @Router /api/v1/access-requests/{id}/approve [post]
+func (h *ElevationHandler) ApproveAccessRequest(c *gin.Context) {
+	h.decide(c, "elevation.approve", func(id, approver string, body DecisionRequest) (*elevation.Request, error) {
+		return h.requests.Approve(id, approver, body.Note, body.DurationMinutes)
+	})
+}
+
+// DenyAccessRequest rejects a pending request
+// @Summary Deny elevated access request
+// @Description Rejects a pending request. Callers using an elevation-bound API token cannot deny.
+// @Tags Access Requests
+// @Accept json
+// @Produce json
+// @Param id path string true "Request ID"
+// @Param decision body DecisionRequest false "Denial note"
+// @Success 200 {object} elevation.Request "Denied request"
+// @Failure 403 {object} map[string]string "Caller cannot decide"
+// @Failure 404 {object} map[string]string "Request not found"
+// @Failure 409 {object} map[string]string "Request is not pending"
+// @Security BearerAuth
+// @Router /api/v1/access-requests/{id}/deny [post]
+func (h *ElevationHandler) DenyAccessRequest(c *gin.Context) {
+	h.decide(c, "elevation.deny", func(id, approver string, body DecisionRequest) (*elevation.Request, error) {
+		return h.requests.Deny(id, approver, body.Note)
+	})
+}
+
+// RevokeAccessRequest ends an active grant early
+// @Summary Revoke elevated access
+// @Description Ends an approved grant before it expires. Callers using an elevation-bound API token cannot revoke.
+// @Tags Access Requests
+// @Accept json
+// @Produce json
+// @Param id path string true "Request ID"
+// @Param decision body DecisionRequest false "Revocation note"
+// @Success 200 {object} elevation.Request "Revoked request"
+// @Failure 403 {object} map[string]string "Caller cannot decide"
+// @Failure 404 {object} map[string]string "Request not found"
+// @Failure 409 {object} map[string]string "Request is not an active grant"
+// @Security BearerAuth
+// @Router /api/v1/access-requests/{id}/revoke [post]
+func (h *ElevationHandler) RevokeAccessRequest(c *gin.Context) {
+	h.decide(c, "elevation.revoke", func(id, approver string, body DecisionRequest) (*elevation.Request, error) {
+		return h.requests.Revoke(id, approver, body.Note)
+	})
+}
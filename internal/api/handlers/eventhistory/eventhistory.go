@@ -0,0 +1,200 @@
+package eventhistory
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/eventhistory"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/internal/types"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+)
+
+// EventHistoryHandler serves recorded Kubernetes events and manages which clusters are recorded
+type EventHistoryHandler struct {
+	recorder *eventhistory.Recorder
+	store    *storage.KubeConfigStore
+	logger   *logger.Logger
+}
+
+// EventHistoryResponse is a page of recorded events
+type EventHistoryResponse struct {
+	Events []*types.StoredEvent `json:"events"`
+	Total  int                  `json:"total"`
+	Limit  int                  `json:"limit"`
+	Offset int                  `json:"offset"`
+}
+
+// NewEventHistoryHandler creates a new event history handler
+func NewEventHistoryHandler(recorder *eventhistory.Recorder, store *storage.KubeConfigStore, log *logger.Logger) *EventHistoryHandler {
+	return &EventHistoryHandler{
+		recorder: recorder,
+		store:    store,
+		logger:   log,
+	}
+}
+
+// parseTime parses an RFC3339 query parameter, returning nil when it is absent
+func parseTime(c *gin.Context, name string) (*time.Time, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, true
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + name + ": expected an RFC3339 timestamp"})
+		return nil, false
+	}
+	return &t, true
+}
+
+// GetEventHistory returns recorded events matching the filters
+// @Summary Get event history
+// @Description Queries Kubernetes events recorded for a cluster, including events the cluster has already expired. Events are matched on namespace, involved object kind and name, reason and type, and returned most recently seen first.
+// @Tags Events
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param namespace query string false "Namespace"
+// @Param kind query string false "Involved object kind, e.g. Pod"
+// @Param name query string false "Involved object name"
+// @Param reason query string false "Event reason, e.g. BackOff"
+// @Param type query string false "Event type (Normal or Warning)"
+// @Param since query string false "Only events last seen at or after this RFC3339 time"
+// @Param until query string false "Only events last seen at or before this RFC3339 time"
+// @Param limit query int false "Page size" default(100)
+// @Param offset query int false "Page offset" default(0)
+// @Success 200 {object} EventHistoryResponse "Recorded events"
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/event-history [get]
+func (h *EventHistoryHandler) GetEventHistory(c *gin.Context) {
+	configID := c.Query("config")
+	if configID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "config parameter is required"})
+		return
+	}
+	since, ok := parseTime(c, "since")
+	if !ok {
+		return
+	}
+	until, ok := parseTime(c, "until")
+	if !ok {
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultLimit)))
+	if err != nil || limit <= 0 || limit > maxLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and " + strconv.Itoa(maxLimit)})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "offset must be a non-negative integer"})
+		return
+	}
+
+	events, total, err := h.recorder.Query(types.EventFilter{
+		ConfigID:     configID,
+		Cluster:      c.Query("cluster"),
+		Namespace:    c.Query("namespace"),
+		InvolvedKind: c.Query("kind"),
+		InvolvedName: c.Query("name"),
+		Reason:       c.Query("reason"),
+		Type:         c.Query("type"),
+		Since:        since,
+		Until:        until,
+		Limit:        limit,
+		Offset:       offset,
+	})
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to query event history")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if events == nil {
+		events = []*types.StoredEvent{}
+	}
+	c.JSON(http.StatusOK, EventHistoryResponse{Events: events, Total: total, Limit: limit, Offset: offset})
+}
+
+// ListRecordedClusters returns the clusters whose events are recorded
+// @Summary List event history clusters
+// @Description Lists the clusters whose events are recorded in the background, with when each last recorded an event and the last watch error
+// @Tags Events
+// @Produce json
+// @Success 200 {array} eventhistory.RecordedCluster "Recorded clusters"
+// @Security BearerAuth
+// @Router /api/v1/event-history/clusters [get]
+func (h *EventHistoryHandler) ListRecordedClusters(c *gin.Context) {
+	c.JSON(http.StatusOK, h.recorder.Clusters())
+}
+
+// EnableRecording starts recording the events of a cluster
+// @Summary Enable event history recording
+// @Description Starts watching the events of a cluster and persisting them with the configured retention, so they remain queryable after the cluster expires them
+// @Tags Events
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Success 200 {object} eventhistory.RecordedCluster "Recorded cluster"
+// @Failure 400 {object} map[string]string "Bad request - missing config"
+// @Failure 404 {object} map[string]string "Kubeconfig not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/event-history/clusters [post]
+func (h *EventHistoryHandler) EnableRecording(c *gin.Context) {
+	configID := c.Query("config")
+	cluster := c.Query("cluster")
+	if configID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "config parameter is required"})
+		return
+	}
+	if _, err := h.store.GetKubeConfig(configID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "kubeconfig not found"})
+		return
+	}
+	recorded, err := h.recorder.Enable(configID, cluster)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to enable event history recording")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.logger.WithField("config", configID).WithField("cluster", cluster).Info("Enabled event history recording")
+	c.JSON(http.StatusOK, recorded)
+}
+
+// DisableRecording stops recording the events of a cluster
+// @Summary Disable event history recording
+// @Description Stops watching the events of a cluster. Events already recorded are kept until they expire.
+// @Tags Events
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Success 204 "Recording disabled"
+// @Failure 400 {object} map[string]string "Bad request - missing config"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/event-history/clusters [delete]
+func (h *EventHistoryHandler) DisableRecording(c *gin.Context) {
+	configID := c.Query("config")
+	if configID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "config parameter is required"})
+		return
+	}
+	if err := h.recorder.Disable(configID, c.Query("cluster")); err != nil {
+		h.logger.WithError(err).Error("Failed to disable event history recording")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,374 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// openAPISchemaRefPrefix prefixes references between component schemas of an OpenAPI v3 document
+const openAPISchemaRefPrefix = "#/components/schemas/"
+
+// openAPIDocument is the part of a group version's OpenAPI v3 document needed to explain fields
+type openAPIDocument struct {
+	Components struct {
+		Schemas map[string]*openAPISchema `json:"schemas"`
+	} `json:"components"`
+}
+
+// openAPISchema is an OpenAPI v3 schema with the Kubernetes extensions that matter for editing
+type openAPISchema struct {
+	Ref         string                    `json:"$ref,omitempty"`
+	AllOf       []*openAPISchema          `json:"allOf,omitempty"`
+	Type        string                    `json:"type,omitempty"`
+	Format      string                    `json:"format,omitempty"`
+	Description string                    `json:"description,omitempty"`
+	Properties  map[string]*openAPISchema `json:"properties,omitempty"`
+	Required    []string                  `json:"required,omitempty"`
+	Items       *openAPISchema            `json:"items,omitempty"`
+	// AdditionalProperties is a schema for map values, or a boolean
+	AdditionalProperties json.RawMessage `json:"additionalProperties,omitempty"`
+	Enum                 []interface{}   `json:"enum,omitempty"`
+	Default              interface{}     `json:"default,omitempty"`
+	Minimum              *float64        `json:"minimum,omitempty"`
+	Maximum              *float64        `json:"maximum,omitempty"`
+	MinLength            *int64          `json:"minLength,omitempty"`
+	MaxLength            *int64          `json:"maxLength,omitempty"`
+	MinItems             *int64          `json:"minItems,omitempty"`
+	MaxItems             *int64          `json:"maxItems,omitempty"`
+	Pattern              string          `json:"pattern,omitempty"`
+	Nullable             bool            `json:"nullable,omitempty"`
+
+	GroupVersionKinds []schema.GroupVersionKind `json:"x-kubernetes-group-version-kind,omitempty"`
+	IntOrString       bool                      `json:"x-kubernetes-int-or-string,omitempty"`
+	PreserveUnknown   bool                      `json:"x-kubernetes-preserve-unknown-fields,omitempty"`
+	ListType          string                    `json:"x-kubernetes-list-type,omitempty"`
+	ListMapKeys       []string                  `json:"x-kubernetes-list-map-keys,omitempty"`
+	Validations       []struct {
+		Rule    string `json:"rule"`
+		Message string `json:"message,omitempty"`
+	} `json:"x-kubernetes-validations,omitempty"`
+}
+
+// ExplainField documents one field: its type, description and validation hints
+type ExplainField struct {
+	Name        string        `json:"name"`
+	Type        string        `json:"type"`
+	Description string        `json:"description,omitempty"`
+	Required    bool          `json:"required,omitempty"`
+	Format      string        `json:"format,omitempty"`
+	Enum        []interface{} `json:"enum,omitempty"`
+	Default     interface{}   `json:"default,omitempty"`
+	// Validation hints, each a short rule such as "minimum: 0" or a CEL rule's message
+	Validation []string `json:"validation,omitempty"`
+}
+
+// ExplainResult documents a field of a kind, or the kind itself for an empty path, with the
+// fields nested under it
+type ExplainResult struct {
+	Group   string         `json:"group"`
+	Version string         `json:"version"`
+	Kind    string         `json:"kind"`
+	Path    string         `json:"path,omitempty"`
+	Field   ExplainField   `json:"field"`
+	Fields  []ExplainField `json:"fields"`
+}
+
+// resolve follows $ref and single-element allOf wrappers to the referenced schema, returning it
+// with the name of the last schema referenced
+func (d *openAPIDocument) resolve(s *openAPISchema) (*openAPISchema, string) {
+	name := ""
+	for depth := 0; s != nil && depth < 10; depth++ {
+		ref := s.Ref
+		if ref == "" && len(s.AllOf) == 1 {
+			ref = s.AllOf[0].Ref
+		}
+		if !strings.HasPrefix(ref, openAPISchemaRefPrefix) {
+			break
+		}
+		name = strings.TrimPrefix(ref, openAPISchemaRefPrefix)
+		s = d.Components.Schemas[name]
+	}
+	return s, name
+}
+
+// findKind returns the schema of a group version kind
+func (d *openAPIDocument) findKind(gvk schema.GroupVersionKind) *openAPISchema {
+	for _, s := range d.Components.Schemas {
+		for _, candidate := range s.GroupVersionKinds {
+			if candidate == gvk {
+				return s
+			}
+		}
+	}
+	return nil
+}
+
+// mapValues returns the schema of a map's values, if the schema is a map
+func (s *openAPISchema) mapValues() *openAPISchema {
+	if len(s.AdditionalProperties) == 0 {
+		return nil
+	}
+	var values openAPISchema
+	if err := json.Unmarshal(s.AdditionalProperties, &values); err != nil {
+		return nil
+	}
+	return &values
+}
+
+// typeName renders a schema's type the way kubectl explain does: a primitive type, the short
+// name of a referenced object such as Container, []T for lists and map[string]T for maps
+func (d *openAPIDocument) typeName(s *openAPISchema) string {
+	resolved, ref := d.resolve(s)
+	if resolved == nil {
+		return "Object"
+	}
+	if ref != "" && (resolved.Type == "" || resolved.Type == "object") {
+		return ref[strings.LastIndex(ref, ".")+1:]
+	}
+	switch {
+	case resolved.IntOrString || resolved.Format == "int-or-string":
+		return "IntOrString"
+	case resolved.Type == "array" && resolved.Items != nil:
+		return "[]" + d.typeName(resolved.Items)
+	case resolved.Type == "object" && resolved.mapValues() != nil && len(resolved.Properties) == 0:
+		return "map[string]" + d.typeName(resolved.mapValues())
+	case resolved.Type == "object" || resolved.Type == "":
+		return "Object"
+	}
+	return resolved.Type
+}
+
+// validationHints lists the constraints of a schema in a readable form
+func validationHints(s *openAPISchema) []string {
+	var hints []string
+	if s.Minimum != nil {
+		hints = append(hints, fmt.Sprintf("minimum: %v", *s.Minimum))
+	}
+	if s.Maximum != nil {
+		hints = append(hints, fmt.Sprintf("maximum: %v", *s.Maximum))
+	}
+	if s.MinLength != nil {
+		hints = append(hints, fmt.Sprintf("minLength: %d", *s.MinLength))
+	}
+	if s.MaxLength != nil {
+		hints = append(hints, fmt.Sprintf("maxLength: %d", *s.MaxLength))
+	}
+	if s.MinItems != nil {
+		hints = append(hints, fmt.Sprintf("minItems: %d", *s.MinItems))
+	}
+	if s.MaxItems != nil {
+		hints = append(hints, fmt.Sprintf("maxItems: %d", *s.MaxItems))
+	}
+	if s.Pattern != "" {
+		hints = append(hints, "pattern: "+s.Pattern)
+	}
+	if s.Nullable {
+		hints = append(hints, "nullable")
+	}
+	if s.ListType != "" {
+		hint := "list type: " + s.ListType
+		if len(s.ListMapKeys) > 0 {
+			hint += " keyed by " + strings.Join(s.ListMapKeys, ", ")
+		}
+		hints = append(hints, hint)
+	}
+	if s.PreserveUnknown {
+		hints = append(hints, "unknown fields are preserved")
+	}
+	for _, v := range s.Validations {
+		if v.Message != "" {
+			hints = append(hints, v.Message)
+		} else {
+			hints = append(hints, "rule: "+v.Rule)
+		}
+	}
+	return hints
+}
+
+// describeField documents a field; the field's own description wins over its type's
+func (d *openAPIDocument) describeField(name string, s *openAPISchema, required bool) ExplainField {
+	resolved, _ := d.resolve(s)
+	if resolved == nil {
+		resolved = s
+	}
+	field := ExplainField{Name: name, Type: d.typeName(s), Description: s.Description, Required: required}
+	if field.Description == "" {
+		field.Description = resolved.Description
+	}
+	for _, candidate := range []*openAPISchema{s, resolved} {
+		if field.Format == "" {
+			field.Format = candidate.Format
+		}
+		if field.Enum == nil {
+			field.Enum = candidate.Enum
+		}
+		if field.Default == nil {
+			field.Default = candidate.Default
+		}
+	}
+	field.Validation = validationHints(s)
+	if resolved != s {
+		field.Validation = append(field.Validation, validationHints(resolved)...)
+	}
+	// An empty object default only says the field is an object; it is not a hint worth showing
+	if m, ok := field.Default.(map[string]interface{}); ok && len(m) == 0 {
+		field.Default = nil
+	}
+	return field
+}
+
+// container returns the schema whose properties are a field's children: lists are explained
+// through their items and maps through their values, like kubectl explain
+func (d *openAPIDocument) container(s *openAPISchema) *openAPISchema {
+	for depth := 0; s != nil && depth < 10; depth++ {
+		s, _ = d.resolve(s)
+		switch {
+		case s == nil:
+			return nil
+		case s.Type == "array" && s.Items != nil:
+			s = s.Items
+		case len(s.Properties) == 0 && s.mapValues() != nil:
+			s = s.mapValues()
+		default:
+			return s
+		}
+	}
+	return s
+}
+
+// explainSchema walks a dot-separated field path from a kind's schema and documents the field
+// it ends at with the fields nested under it
+func explainSchema(doc *openAPIDocument, gvk schema.GroupVersionKind, path string) (*ExplainResult, error) {
+	root := doc.findKind(gvk)
+	if root == nil {
+		return nil, fmt.Errorf("no OpenAPI schema for %s", gvk.String())
+	}
+	result := &ExplainResult{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind, Path: path, Fields: []ExplainField{}}
+	result.Field = doc.describeField(gvk.Kind, root, false)
+	result.Field.Type = "Object"
+
+	current := root
+	if path = strings.Trim(path, "."); path != "" {
+		walked := []string{}
+		for _, segment := range strings.Split(path, ".") {
+			parent := doc.container(current)
+			if parent == nil || parent.Properties[segment] == nil {
+				return nil, fmt.Errorf("field %q does not exist in %s", strings.Join(append(walked, segment), "."), gvk.Kind)
+			}
+			required := false
+			for _, r := range parent.Required {
+				required = required || r == segment
+			}
+			current = parent.Properties[segment]
+			walked = append(walked, segment)
+			result.Field = doc.describeField(segment, current, required)
+		}
+	}
+
+	if parent := doc.container(current); parent != nil {
+		required := map[string]bool{}
+		for _, r := range parent.Required {
+			required[r] = true
+		}
+		for name, child := range parent.Properties {
+			field := doc.describeField(name, child, required[name])
+			field.Format, field.Enum, field.Default, field.Validation = "", nil, nil, nil
+			result.Fields = append(result.Fields, field)
+		}
+		sort.Slice(result.Fields, func(i, j int) bool { return result.Fields[i].Name < result.Fields[j].Name })
+	}
+	return result, nil
+}
+
+// openAPIPath is the discovery path of a group version's OpenAPI v3 document
+func openAPIPath(gv schema.GroupVersion) string {
+	if gv.Group == "" {
+		return "api/" + gv.Version
+	}
+	return "apis/" + gv.Group + "/" + gv.Version
+}
+
+// Explain documents a field of any resource from the cluster's OpenAPI schema
+// @Summary Explain a resource field
+// @Description Serves field documentation for any resource from the target cluster's OpenAPI v3 schema, like kubectl explain: the type, description, whether it is required, enum values, defaults and validation hints (bounds, patterns, list types, CEL rules of CRDs) of the field at path, and the fields nested under it. Lists and maps are explained through their items, so spec.template.spec.containers.image works. With an empty path the kind itself is explained. Schemas come from the cached discovery of the cluster and refresh with it.
+// @Tags Resources
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param resource query string true "Resource or kind, optionally with its group (e.g. deployments, Deployment, certificates.cert-manager.io)"
+// @Param apiVersion query string false "API version to explain (e.g. apps/v1); defaults to the preferred version"
+// @Param path query string false "Dot-separated field path (e.g. spec.strategy.rollingUpdate.maxSurge)"
+// @Success 200 {object} ExplainResult "Field documentation"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Resource or field not found"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/explain [get]
+func (h *ResourcesHandler) Explain(c *gin.Context) {
+	resource := c.Query("resource")
+	if resource == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "resource parameter is required"})
+		return
+	}
+	config, err := h.store.GetKubeConfig(c.Query("config"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "config not found: " + err.Error()})
+		return
+	}
+	mapper, discoveryClient, err := h.clientFactory.GetRESTMapperForConfig(config, c.Query("cluster"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	gr := schema.ParseGroupResource(strings.ToLower(resource))
+	partial := gr.WithVersion("")
+	if apiVersion := c.Query("apiVersion"); apiVersion != "" {
+		gv, err := schema.ParseGroupVersion(apiVersion)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		partial = schema.GroupVersionResource{Group: gv.Group, Version: gv.Version, Resource: gr.Resource}
+	}
+	gvk, err := mapper.KindFor(partial)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("resource %q not found: %v", resource, err)})
+		return
+	}
+
+	paths, err := discoveryClient.OpenAPIV3().Paths()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to fetch OpenAPI schema: " + err.Error()})
+		return
+	}
+	groupVersion, ok := paths[openAPIPath(gvk.GroupVersion())]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("the cluster publishes no OpenAPI schema for %s", gvk.GroupVersion())})
+		return
+	}
+	raw, err := groupVersion.Schema(runtime.ContentTypeJSON)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to fetch OpenAPI schema: " + err.Error()})
+		return
+	}
+	var doc openAPIDocument
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to parse OpenAPI schema: " + err.Error()})
+		return
+	}
+
+	result, err := explainSchema(&doc, gvk, c.Query("path"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// deploymentOpenAPI is a trimmed apps/v1 OpenAPI v3 document in the shape the API server serves
+const deploymentOpenAPI = `{"components":{"schemas":{
+	"io.k8s.api.apps.v1.Deployment": {
+		"description": "Deployment enables declarative updates for Pods and ReplicaSets.",
+		"type": "object",
+		"properties": {
+			"spec": {"allOf": [{"$ref": "#/components/schemas/io.k8s.api.apps.v1.DeploymentSpec"}], "default": {}, "description": "Specification of the desired behavior of the Deployment."}
+		},
+		"x-kubernetes-group-version-kind": [{"group": "apps", "kind": "Deployment", "version": "v1"}]
+	},
+	"io.k8s.api.apps.v1.DeploymentSpec": {
+		"type": "object",
+		"required": ["selector", "template"],
+		"properties": {
+			"replicas": {"type": "integer", "format": "int32", "minimum": 0, "description": "Number of desired pods."},
+			"selector": {"allOf": [{"$ref": "#/components/schemas/io.k8s.apimachinery.pkg.apis.meta.v1.LabelSelector"}]},
+			"strategy": {"allOf": [{"$ref": "#/components/schemas/io.k8s.api.apps.v1.DeploymentStrategy"}], "default": {}, "description": "The deployment strategy to use."},
+			"template": {"allOf": [{"$ref": "#/components/schemas/io.k8s.api.core.v1.PodTemplateSpec"}], "default": {}}
+		}
+	},
+	"io.k8s.api.apps.v1.DeploymentStrategy": {
+		"type": "object",
+		"properties": {
+			"rollingUpdate": {"allOf": [{"$ref": "#/components/schemas/io.k8s.api.apps.v1.RollingUpdateDeployment"}]},
+			"type": {"type": "string", "enum": ["Recreate", "RollingUpdate"], "description": "Type of deployment."}
+		}
+	},
+	"io.k8s.api.apps.v1.RollingUpdateDeployment": {
+		"type": "object",
+		"properties": {
+			"maxSurge": {"allOf": [{"$ref": "#/components/schemas/io.k8s.apimachinery.pkg.util.intstr.IntOrString"}], "description": "The maximum number of pods that can be scheduled above the desired number of pods."}
+		}
+	},
+	"io.k8s.apimachinery.pkg.util.intstr.IntOrString": {"type": "string", "format": "int-or-string", "description": "IntOrString is a type that can hold an int32 or a string."},
+	"io.k8s.apimachinery.pkg.apis.meta.v1.LabelSelector": {
+		"type": "object",
+		"properties": {
+			"matchLabels": {"type": "object", "additionalProperties": {"type": "string", "default": ""}}
+		}
+	},
+	"io.k8s.api.core.v1.PodTemplateSpec": {
+		"type": "object",
+		"properties": {"spec": {"allOf": [{"$ref": "#/components/schemas/io.k8s.api.core.v1.PodSpec"}]}}
+	},
+	"io.k8s.api.core.v1.PodSpec": {
+		"type": "object",
+		"required": ["containers"],
+		"properties": {
+			"containers": {"type": "array", "items": {"allOf": [{"$ref": "#/components/schemas/io.k8s.api.core.v1.Container"}], "default": {}},
+				"x-kubernetes-list-type": "map", "x-kubernetes-list-map-keys": ["name"]}
+		}
+	},
+	"io.k8s.api.core.v1.Container": {
+		"type": "object",
+		"required": ["name"],
+		"properties": {
+			"image": {"type": "string", "description": "Container image name."},
+			"name": {"type": "string"}
+		}
+	}
+}}}`
+
+func TestExplainSchema(t *testing.T) {
+	var doc openAPIDocument
+	if err := json.Unmarshal([]byte(deploymentOpenAPI), &doc); err != nil {
+		t.Fatal(err)
+	}
+	gvk := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+
+	kind, err := explainSchema(&doc, gvk, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kind.Field.Name != "Deployment" || kind.Field.Description == "" || len(kind.Fields) != 1 || kind.Fields[0].Type != "DeploymentSpec" {
+		t.Errorf("unexpected kind explanation %+v", kind)
+	}
+
+	surge, err := explainSchema(&doc, gvk, "spec.strategy.rollingUpdate.maxSurge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if surge.Field.Type != "IntOrString" || surge.Field.Description != "The maximum number of pods that can be scheduled above the desired number of pods." {
+		t.Errorf("unexpected maxSurge explanation %+v", surge.Field)
+	}
+
+	spec, _ := explainSchema(&doc, gvk, "spec")
+	var names []string
+	for _, f := range spec.Fields {
+		names = append(names, f.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"replicas", "selector", "strategy", "template"}) || !spec.Fields[1].Required || spec.Fields[0].Required {
+		t.Errorf("unexpected spec fields %+v", spec.Fields)
+	}
+	if spec.Field.Default != nil {
+		t.Errorf("expected the empty object default to be dropped, got %v", spec.Field.Default)
+	}
+
+	replicas, _ := explainSchema(&doc, gvk, "spec.replicas")
+	if replicas.Field.Type != "integer" || replicas.Field.Format != "int32" || !reflect.DeepEqual(replicas.Field.Validation, []string{"minimum: 0"}) {
+		t.Errorf("unexpected replicas explanation %+v", replicas.Field)
+	}
+	strategyType, _ := explainSchema(&doc, gvk, "spec.strategy.type")
+	if len(strategyType.Field.Enum) != 2 {
+		t.Errorf("expected enum values, got %+v", strategyType.Field)
+	}
+
+	containers, _ := explainSchema(&doc, gvk, "spec.template.spec.containers")
+	if containers.Field.Type != "[]Container" || !containers.Field.Required || containers.Field.Validation[0] != "list type: map keyed by name" {
+		t.Errorf("unexpected containers explanation %+v", containers.Field)
+	}
+	image, err := explainSchema(&doc, gvk, "spec.template.spec.containers.image")
+	if err != nil || image.Field.Type != "string" || image.Field.Description != "Container image name." {
+		t.Errorf("expected lists to be explained through their items, got %+v, %v", image, err)
+	}
+	matchLabels, _ := explainSchema(&doc, gvk, "spec.selector.matchLabels")
+	if matchLabels.Field.Type != "map[string]string" {
+		t.Errorf("unexpected matchLabels type %q", matchLabels.Field.Type)
+	}
+
+	if _, err := explainSchema(&doc, gvk, "spec.strategy.maxSurge"); err == nil || err.Error() != `field "spec.strategy.maxSurge" does not exist in Deployment` {
+		t.Errorf("expected an unknown field error, got %v", err)
+	}
+	if _, err := explainSchema(&doc, schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"}, ""); err == nil {
+		t.Error("expected an error for a kind without a schema")
+	}
+}
@@ -17,8 +17,10 @@ type FeatureFlagsHandler struct {
 
 // FeatureFlagsResponse represents the response structure for feature flags
 type FeatureFlagsResponse struct {
-	EnableTracing    bool `json:"enableTracing"`
-	EnableCloudShell bool `json:"enableCloudShell"`
+	EnableTracing      bool `json:"enableTracing"`
+	EnableCloudShell   bool `json:"enableCloudShell"`
+	EnableNodeLogs     bool `json:"enableNodeLogs"`
+	EnableServiceProxy bool `json:"enableServiceProxy"`
 }
 
 // NewFeatureFlagsHandler creates a new feature flags handler
@@ -30,7 +32,7 @@ func NewFeatureFlagsHandler(log *logger.Logger) *FeatureFlagsHandler {
 
 // GetFeatureFlags returns the current feature flag configuration
 // @Summary Get Feature Flags
-// @Description Get the current feature flag configuration including tracing and cloud shell enablement
+// @Description Get the current feature flag configuration including tracing, cloud shell, node log and service proxy enablement
 // @Tags System
 // @Accept json
 // @Produce json
@@ -41,12 +43,16 @@ func (h *FeatureFlagsHandler) GetFeatureFlags(c *gin.Context) {
 	// Read runtime environment variables
 	enableTracing := h.getBoolEnvVar("ENABLE_TRACING", false)
 	enableCloudShell := h.getBoolEnvVar("ENABLE_CLOUD_SHELL", false)
+	enableNodeLogs := h.getBoolEnvVar("ENABLE_NODE_LOGS", false)
+	enableServiceProxy := h.getBoolEnvVar("ENABLE_SERVICE_PROXY", false)
 
 	h.logger.WithField("enableTracing", enableTracing).WithField("enableCloudShell", enableCloudShell).Debug("Serving feature flags")
 
 	response := FeatureFlagsResponse{
-		EnableTracing:    enableTracing,
-		EnableCloudShell: enableCloudShell,
+		EnableTracing:      enableTracing,
+		EnableCloudShell:   enableCloudShell,
+		EnableNodeLogs:     enableNodeLogs,
+		EnableServiceProxy: enableServiceProxy,
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -0,0 +1,391 @@
+package gitops
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/internal/tracing"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// GitOps tools
+const (
+	toolArgoCD = "argocd"
+	toolFlux   = "flux"
+)
+
+// gitOpsKind describes a GitOps CRD and the API versions to try, newest first
+type gitOpsKind struct {
+	tool     string
+	kind     string
+	group    string
+	resource string
+	versions []string
+}
+
+var gitOpsKinds = []gitOpsKind{
+	{tool: toolArgoCD, kind: "Application", group: "argoproj.io", resource: "applications", versions: []string{"v1alpha1"}},
+	{tool: toolFlux, kind: "Kustomization", group: "kustomize.toolkit.fluxcd.io", resource: "kustomizations", versions: []string{"v1", "v1beta2"}},
+	{tool: toolFlux, kind: "HelmRelease", group: "helm.toolkit.fluxcd.io", resource: "helmreleases", versions: []string{"v2", "v2beta2", "v2beta1"}},
+}
+
+// GitOpsApplication is the normalized status of an Argo CD Application or Flux reconciler
+type GitOpsApplication struct {
+	Tool           string `json:"tool"`
+	Kind           string `json:"kind"`
+	Name           string `json:"name"`
+	Namespace      string `json:"namespace"`
+	SyncStatus     string `json:"syncStatus"` // Synced/OutOfSync for Argo CD, Ready/NotReady for Flux
+	Health         string `json:"health"`
+	Suspended      bool   `json:"suspended"`
+	Source         string `json:"source"`
+	Revision       string `json:"revision"`
+	LastReconciled string `json:"lastReconciled,omitempty"`
+	Message        string `json:"message,omitempty"`
+}
+
+// GitOpsManagedResource is a resource applied by a GitOps application
+type GitOpsManagedResource struct {
+	Group     string `json:"group,omitempty"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Status    string `json:"status,omitempty"`
+	Health    string `json:"health,omitempty"`
+}
+
+// GitOpsApplicationsResponse lists applications across the detected tools
+type GitOpsApplicationsResponse struct {
+	Tools        []string            `json:"tools"`
+	Applications []GitOpsApplication `json:"applications"`
+}
+
+// GitOpsApplicationDetail adds the managed resource inventory to an application
+type GitOpsApplicationDetail struct {
+	GitOpsApplication
+	Resources []GitOpsManagedResource `json:"resources"`
+}
+
+// GitOpsHandler surfaces Argo CD and Flux reconciliation status
+type GitOpsHandler struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	logger        *logger.Logger
+	tracingHelper *tracing.TracingHelper
+}
+
+// NewGitOpsHandler creates a new GitOps handler
+func NewGitOpsHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger) *GitOpsHandler {
+	return &GitOpsHandler{
+		store:         store,
+		clientFactory: clientFactory,
+		logger:        log,
+		tracingHelper: tracing.GetTracingHelper(),
+	}
+}
+
+// getDynamicClient gets the dynamic Kubernetes client for the current request
+func (h *GitOpsHandler) getDynamicClient(c *gin.Context) (dynamic.Interface, error) {
+	configID := c.Query("config")
+	cluster := c.Query("cluster")
+
+	if configID == "" {
+		return nil, fmt.Errorf("config parameter is required")
+	}
+
+	config, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, fmt.Errorf("config not found: %w", err)
+	}
+
+	client, err := h.clientFactory.GetDynamicClientForConfig(config, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dynamic client: %w", err)
+	}
+
+	return client, nil
+}
+
+// listKind lists objects of a GitOps kind using the first served API version; installed is false when the CRD is absent
+func listKind(ctx context.Context, client dynamic.Interface, kind gitOpsKind, namespace string) ([]unstructured.Unstructured, bool, error) {
+	for _, version := range kind.versions {
+		gvr := schema.GroupVersionResource{Group: kind.group, Version: version, Resource: kind.resource}
+		list, err := client.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		if err == nil {
+			return list.Items, true, nil
+		}
+		if !apierrors.IsNotFound(err) {
+			return nil, true, fmt.Errorf("failed to list %s: %w", kind.resource, err)
+		}
+	}
+	return nil, false, nil
+}
+
+// readyCondition returns the status, reason/message and transition time of the Ready condition
+func readyCondition(obj *unstructured.Unstructured) (string, string, string) {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, condition := range conditions {
+		m, ok := condition.(map[string]interface{})
+		if !ok || m["type"] != "Ready" {
+			continue
+		}
+		status, _ := m["status"].(string)
+		message, _ := m["message"].(string)
+		transition, _ := m["lastTransitionTime"].(string)
+		return status, message, transition
+	}
+	return "Unknown", "", ""
+}
+
+// normalizeApplication maps a tool-specific object onto GitOpsApplication
+func normalizeApplication(kind gitOpsKind, obj *unstructured.Unstructured) GitOpsApplication {
+	app := GitOpsApplication{Tool: kind.tool, Kind: kind.kind, Name: obj.GetName(), Namespace: obj.GetNamespace()}
+
+	if kind.tool == toolArgoCD {
+		app.SyncStatus, _, _ = unstructured.NestedString(obj.Object, "status", "sync", "status")
+		app.Health, _, _ = unstructured.NestedString(obj.Object, "status", "health", "status")
+		app.Revision, _, _ = unstructured.NestedString(obj.Object, "status", "sync", "revision")
+		app.LastReconciled, _, _ = unstructured.NestedString(obj.Object, "status", "reconciledAt")
+		repo, _, _ := unstructured.NestedString(obj.Object, "spec", "source", "repoURL")
+		path, _, _ := unstructured.NestedString(obj.Object, "spec", "source", "path")
+		if chart, _, _ := unstructured.NestedString(obj.Object, "spec", "source", "chart"); chart != "" {
+			path = chart
+		}
+		app.Source = strings.TrimSuffix(repo+"/"+path, "/")
+		if phase, _, _ := unstructured.NestedString(obj.Object, "status", "operationState", "phase"); phase == "Failed" || phase == "Error" {
+			app.Message, _, _ = unstructured.NestedString(obj.Object, "status", "operationState", "message")
+		}
+		return app
+	}
+
+	ready, message, transition := readyCondition(obj)
+	app.Suspended, _, _ = unstructured.NestedBool(obj.Object, "spec", "suspend")
+	app.Message = message
+	app.LastReconciled = transition
+	switch ready {
+	case "True":
+		app.SyncStatus, app.Health = "Ready", "Healthy"
+	case "False":
+		app.SyncStatus, app.Health = "NotReady", "Degraded"
+	default:
+		app.SyncStatus, app.Health = "Unknown", "Progressing"
+	}
+
+	switch kind.kind {
+	case "Kustomization":
+		app.Revision, _, _ = unstructured.NestedString(obj.Object, "status", "lastAppliedRevision")
+		sourceKind, _, _ := unstructured.NestedString(obj.Object, "spec", "sourceRef", "kind")
+		sourceName, _, _ := unstructured.NestedString(obj.Object, "spec", "sourceRef", "name")
+		path, _, _ := unstructured.NestedString(obj.Object, "spec", "path")
+		app.Source = fmt.Sprintf("%s/%s:%s", sourceKind, sourceName, path)
+	case "HelmRelease":
+		app.Revision, _, _ = unstructured.NestedString(obj.Object, "status", "lastAppliedRevision")
+		if app.Revision == "" {
+			// helm.toolkit.fluxcd.io/v2 records revisions in the release history
+			history, _, _ := unstructured.NestedSlice(obj.Object, "status", "history")
+			if len(history) > 0 {
+				if latest, ok := history[0].(map[string]interface{}); ok {
+					app.Revision, _ = latest["chartVersion"].(string)
+				}
+			}
+		}
+		chart, _, _ := unstructured.NestedString(obj.Object, "spec", "chart", "spec", "chart")
+		sourceKind, _, _ := unstructured.NestedString(obj.Object, "spec", "chart", "spec", "sourceRef", "kind")
+		sourceName, _, _ := unstructured.NestedString(obj.Object, "spec", "chart", "spec", "sourceRef", "name")
+		app.Source = fmt.Sprintf("%s/%s:%s", sourceKind, sourceName, chart)
+	}
+	return app
+}
+
+// managedResources extracts the resource inventory an application reports
+func managedResources(kind gitOpsKind, obj *unstructured.Unstructured) []GitOpsManagedResource {
+	resources := []GitOpsManagedResource{}
+	switch kind.kind {
+	case "Application":
+		entries, _, _ := unstructured.NestedSlice(obj.Object, "status", "resources")
+		for _, entry := range entries {
+			m, ok := entry.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			r := GitOpsManagedResource{}
+			r.Group, _ = m["group"].(string)
+			r.Kind, _ = m["kind"].(string)
+			r.Namespace, _ = m["namespace"].(string)
+			r.Name, _ = m["name"].(string)
+			r.Status, _ = m["status"].(string)
+			if health, ok := m["health"].(map[string]interface{}); ok {
+				r.Health, _ = health["status"].(string)
+			}
+			resources = append(resources, r)
+		}
+	case "Kustomization":
+		// Inventory IDs are "<namespace>_<name>_<group>_<kind>"
+		entries, _, _ := unstructured.NestedSlice(obj.Object, "status", "inventory", "entries")
+		for _, entry := range entries {
+			m, ok := entry.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			id, _ := m["id"].(string)
+			parts := strings.Split(id, "_")
+			if len(parts) != 4 {
+				continue
+			}
+			resources = append(resources, GitOpsManagedResource{Namespace: parts[0], Name: parts[1], Group: parts[2], Kind: parts[3]})
+		}
+	}
+	return resources
+}
+
+// GetApplications lists Argo CD Applications and Flux Kustomizations/HelmReleases with their sync status
+// @Summary List GitOps applications
+// @Description Detects Argo CD Applications and Flux Kustomizations/HelmReleases and reports sync/health status, last reconciliation and source revision
+// @Tags GitOps
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param namespace query string false "Namespace to filter (empty for all namespaces)"
+// @Success 200 {object} GitOpsApplicationsResponse "GitOps applications"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/gitops/applications [get]
+func (h *GitOpsHandler) GetApplications(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	client, err := h.getDynamicClient(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for GitOps applications")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
+
+	namespace := c.Query("namespace")
+
+	_, listSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "gitops-applications", namespace)
+	defer listSpan.End()
+
+	response := GitOpsApplicationsResponse{Tools: []string{}, Applications: []GitOpsApplication{}}
+	detected := map[string]bool{}
+	for _, kind := range gitOpsKinds {
+		items, installed, err := listKind(c.Request.Context(), client, kind, namespace)
+		if err != nil {
+			h.logger.WithError(err).WithField("kind", kind.kind).Error("Failed to list GitOps applications")
+			h.tracingHelper.RecordError(listSpan, err, "Failed to list GitOps applications")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if !installed {
+			continue
+		}
+		if !detected[kind.tool] {
+			detected[kind.tool] = true
+			response.Tools = append(response.Tools, kind.tool)
+		}
+		for i := range items {
+			response.Applications = append(response.Applications, normalizeApplication(kind, &items[i]))
+		}
+	}
+
+	sort.Slice(response.Applications, func(i, j int) bool {
+		a, b := response.Applications[i], response.Applications[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	h.tracingHelper.AddResourceAttributes(listSpan, "", "gitops-applications", len(response.Applications))
+	h.tracingHelper.RecordSuccess(listSpan, fmt.Sprintf("Listed %d GitOps applications", len(response.Applications)))
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetApplication returns a single GitOps application with the resources it manages
+// @Summary Get GitOps application
+// @Description Returns the status of an Argo CD Application or Flux Kustomization/HelmRelease along with its managed resource inventory
+// @Tags GitOps
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param namespace path string true "Application namespace"
+// @Param name path string true "Application name"
+// @Param kind query string true "Application kind (Application, Kustomization or HelmRelease)"
+// @Success 200 {object} GitOpsApplicationDetail "GitOps application"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 404 {object} map[string]string "Application not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/gitops/applications/{namespace}/{name} [get]
+func (h *GitOpsHandler) GetApplication(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	client, err := h.getDynamicClient(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for GitOps application")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
+
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	kindName := c.Query("kind")
+
+	var kind *gitOpsKind
+	for i := range gitOpsKinds {
+		if strings.EqualFold(gitOpsKinds[i].kind, kindName) {
+			kind = &gitOpsKinds[i]
+		}
+	}
+	if kind == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "kind must be one of Application, Kustomization or HelmRelease"})
+		return
+	}
+
+	_, getSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "get", kind.resource, namespace)
+	defer getSpan.End()
+
+	for _, version := range kind.versions {
+		gvr := schema.GroupVersionResource{Group: kind.group, Version: version, Resource: kind.resource}
+		obj, err := client.Resource(gvr).Namespace(namespace).Get(c.Request.Context(), name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			h.logger.WithError(err).WithField("name", name).Error("Failed to get GitOps application")
+			h.tracingHelper.RecordError(getSpan, err, "Failed to get GitOps application")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		h.tracingHelper.RecordSuccess(getSpan, "GitOps application retrieved")
+		c.JSON(http.StatusOK, GitOpsApplicationDetail{
+			GitOpsApplication: normalizeApplication(*kind, obj),
+			Resources:         managedResources(*kind, obj),
+		})
+		return
+	}
+
+	c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("%s %s/%s not found", kind.kind, namespace, name)})
+}
@@ -13,6 +13,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Facets-cloud/kube-dash/internal/api/types"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -29,6 +31,7 @@ type HelmChart struct {
 	AppVersion  string                `json:"appVersion"`
 	Version     string                `json:"version"`
 	Created     string                `json:"created"`
+	CreatedMs   int64                 `json:"createdMs,omitempty"`
 	Digest      string                `json:"digest"`
 	Urls        []string              `json:"urls"`
 	Repository  HelmChartRepository   `json:"repository"`
@@ -129,8 +132,8 @@ func (h *HelmHandler) resolveRepoPathFromPackageOrName(c *gin.Context, packageOr
 	repoFilter := strings.TrimSpace(c.Query("repository"))
 
 	searchURL := fmt.Sprintf(
-		"https://artifacthub.io/api/v1/packages/search?kind=0&ts_query_web=%s&limit=%d",
-		url.QueryEscape(chartName), 25,
+		"%s/api/v1/packages/search?kind=0&ts_query_web=%s&limit=%d",
+		h.artifactHubURL, url.QueryEscape(chartName), 25,
 	)
 
 	client := &http.Client{Timeout: 30 * time.Second}
@@ -200,15 +203,17 @@ func (h *HelmHandler) resolveRepoPathFromPackageOrName(c *gin.Context, packageOr
 // @Accept json
 // @Produce json
 // @Param q query string false "Search query"
-// @Param limit query int false "Maximum number of results" default(20)
-// @Param offset query int false "Offset for pagination" default(0)
+// @Param page query int false "Page number" default(1)
+// @Param size query int false "Results per page" default(20)
+// @Param category query string false "Artifact Hub category filter"
 // @Param repository query string false "Repository filter"
-// @Success 200 {object} map[string]interface{} "Search results"
+// @Success 200 {object} HelmChartsSearchResponse "Search results"
 // @Failure 400 {object} map[string]string "Bad request"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Security BearerAuth
 // @Security KubeConfig
-// @Router /api/v1/helm/charts/search [get]
+// @Router /api/v1/helmcharts [get]
+// @Router /api/v2/helmcharts [get]
 func (h *HelmHandler) SearchHelmCharts(c *gin.Context) {
 	// Start main span for Helm charts search operation
 	ctx, span := h.tracingHelper.StartAuthSpan(c.Request.Context(), "helm.search_charts")
@@ -240,7 +245,7 @@ func (h *HelmHandler) SearchHelmCharts(c *gin.Context) {
 	// Child span for API URL building
 	urlCtx, urlSpan := h.tracingHelper.StartDataProcessingSpan(validationCtx, "helm.build_api_url")
 	// Build Artifact Hub API URL
-	apiURL := "https://artifacthub.io/api/v1/packages/search"
+	apiURL := h.artifactHubURL + "/api/v1/packages/search"
 	params := []string{
 		fmt.Sprintf("kind=0"), // 0 = Helm charts
 		fmt.Sprintf("offset=%d", (pageInt-1)*sizeInt),
@@ -344,7 +349,8 @@ func (h *HelmHandler) SearchHelmCharts(c *gin.Context) {
 			Description: item.Description,
 			AppVersion:  item.AppVersion,
 			Version:     item.Version,
-			Created:     time.Unix(item.CreatedAt, 0).Format(time.RFC3339),
+			Created:     types.TimeFormat(time.Unix(item.CreatedAt, 0)),
+			CreatedMs:   types.EpochMillis(time.Unix(item.CreatedAt, 0)),
 			Repository: HelmChartRepository{
 				Name:     item.Repository.Name,
 				URL:      item.Repository.URL,
@@ -476,7 +482,7 @@ func (h *HelmHandler) GetHelmChartDetails(c *gin.Context) {
 	// Create child span for HTTP request
 	httpCtx, httpSpan := h.tracingHelper.StartKubernetesAPISpan(resolveCtx, "fetch_chart_details", "helm", "")
 	// Fetch package details using the repo path
-	apiURL := fmt.Sprintf("https://artifacthub.io/api/v1/packages/%s", repoPath)
+	apiURL := fmt.Sprintf("%s/api/v1/packages/%s", h.artifactHubURL, repoPath)
 	client := &http.Client{Timeout: 30 * time.Second}
 	req, err := http.NewRequestWithContext(httpCtx, "GET", apiURL, nil)
 	if err != nil {
@@ -591,7 +597,7 @@ func (h *HelmHandler) GetHelmChartVersions(c *gin.Context) {
 	// Create child span for HTTP request
 	httpCtx, httpSpan := h.tracingHelper.StartKubernetesAPISpan(resolveCtx, "fetch_chart_versions", "helm", "")
 	// Make HTTP request to Artifact Hub for package details using repo path
-	apiURL := fmt.Sprintf("https://artifacthub.io/api/v1/packages/%s", repoPath)
+	apiURL := fmt.Sprintf("%s/api/v1/packages/%s", h.artifactHubURL, repoPath)
 	h.tracingHelper.AddResourceAttributes(httpSpan, apiURL, "http_url", 1)
 	client := &http.Client{Timeout: 30 * time.Second}
 	resp, err := client.Get(apiURL)
@@ -664,7 +670,11 @@ func (h *HelmHandler) GetHelmChartVersions(c *gin.Context) {
 				item["appVersion"] = av
 			}
 			if ts, ok := m["ts"].(float64); ok {
-				item["created"] = time.Unix(int64(ts), 0).Format(time.RFC3339)
+				created := time.Unix(int64(ts), 0)
+				item["created"] = types.TimeFormat(created)
+				if ms := types.EpochMillis(created); ms != 0 {
+					item["createdMs"] = ms
+				}
 			}
 			if pre, ok := m["prerelease"].(bool); ok {
 				item["prerelease"] = pre
@@ -718,7 +728,7 @@ func (h *HelmHandler) GetHelmChartTemplates(c *gin.Context) {
 	// Create child span for HTTP request
 	httpCtx, httpSpan := h.tracingHelper.StartKubernetesAPISpan(resolveCtx, "fetch_version_details", "helm", "")
 	// Fetch package version details to obtain content_url
-	detailsURL := fmt.Sprintf("https://artifacthub.io/api/v1/packages/%s/%s", repoPath, version)
+	detailsURL := fmt.Sprintf("%s/api/v1/packages/%s/%s", h.artifactHubURL, repoPath, version)
 	h.tracingHelper.AddResourceAttributes(httpSpan, detailsURL, "http_url", 1)
 	client := &http.Client{Timeout: 30 * time.Second}
 	req, err := http.NewRequest("GET", detailsURL, nil)
@@ -0,0 +1,30 @@
+package helm
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/testharness"
+)
+
+func TestSearchHelmCharts(t *testing.T) {
+	h := testharness.New(t)
+	artifactHub := testharness.ReplayServer(t, map[string]string{
+		"/api/v1/packages/search": "artifacthub/search_nginx.json",
+	})
+	handler := NewHelmHandler(h.Store, h.Factory, k8s.NewHelmClientFactory(), h.Logger)
+	handler.SetArtifactHubURL(artifactHub.URL)
+
+	var resp HelmChartsSearchResponse
+	h.DoJSON(http.MethodGet, "/api/v1/helm/charts/search", handler.SearchHelmCharts, "/api/v1/helm/charts/search?q=nginx", nil, &resp)
+	if resp.Total != 2 || len(resp.Data) != 2 {
+		t.Fatalf("got %d charts, want 2", len(resp.Data))
+	}
+	if nginx := resp.Data[0]; nginx.Name != "nginx" || nginx.Repository.Name != "bitnami" || nginx.Icon == "" {
+		t.Errorf("first chart = %+v", nginx)
+	}
+	if resp.Data[1].Icon != "" || !resp.Data[1].Repository.Official {
+		t.Errorf("second chart = %+v, want an official chart without icon", resp.Data[1])
+	}
+}
@@ -0,0 +1,198 @@
+package helm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HelmChartLink is a link published with a chart, such as its source or documentation
+type HelmChartLink struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// HelmChartVersionSummary is a published version of a chart
+type HelmChartVersionSummary struct {
+	Version    string `json:"version"`
+	AppVersion string `json:"appVersion,omitempty"`
+	Created    string `json:"created"`
+	CreatedMs  int64  `json:"createdMs,omitempty"`
+}
+
+// HelmChartDetails describes a chart package, with the default values of its latest version
+type HelmChartDetails struct {
+	PackageID     string                    `json:"packageId"`
+	Name          string                    `json:"name"`
+	DisplayName   string                    `json:"displayName,omitempty"`
+	Description   string                    `json:"description"`
+	Version       string                    `json:"version"`
+	AppVersion    string                    `json:"appVersion"`
+	Created       string                    `json:"created"`
+	CreatedMs     int64                     `json:"createdMs,omitempty"`
+	Home          string                    `json:"home,omitempty"`
+	License       string                    `json:"license,omitempty"`
+	Icon          string                    `json:"icon,omitempty"`
+	Keywords      []string                  `json:"keywords"`
+	Maintainers   []HelmChartMaintainer     `json:"maintainers"`
+	Links         []HelmChartLink           `json:"links"`
+	Repository    HelmChartRepository       `json:"repository"`
+	Versions      []HelmChartVersionSummary `json:"versions"`
+	Readme        string                    `json:"readme,omitempty"`
+	DefaultValues string                    `json:"defaultValues"`
+	// ValuesError is set when the chart archive could not be read for its default values
+	ValuesError string `json:"valuesError,omitempty"`
+}
+
+// artifactHubPackage is the part of an Artifact Hub package used for chart details
+type artifactHubPackage struct {
+	PackageID   string   `json:"package_id"`
+	Name        string   `json:"name"`
+	DisplayName string   `json:"display_name"`
+	Description string   `json:"description"`
+	Version     string   `json:"version"`
+	AppVersion  string   `json:"app_version"`
+	HomeURL     string   `json:"home_url"`
+	License     string   `json:"license"`
+	LogoImageID string   `json:"logo_image_id"`
+	Keywords    []string `json:"keywords"`
+	Readme      string   `json:"readme"`
+	ContentURL  string   `json:"content_url"`
+	CreatedAt   int64    `json:"ts"`
+	Maintainers []struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	} `json:"maintainers"`
+	Links []struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"links"`
+	Repository struct {
+		Name     string `json:"name"`
+		URL      string `json:"url"`
+		Official bool   `json:"official"`
+	} `json:"repository"`
+	AvailableVersions []struct {
+		Version    string `json:"version"`
+		AppVersion string `json:"app_version"`
+		CreatedAt  int64  `json:"ts"`
+	} `json:"available_versions"`
+}
+
+// fetchArtifactHubPackage reads a package by its repository path; the status is Artifact Hub's
+// when it answered with an error
+func (h *HelmHandler) fetchArtifactHubPackage(ctx context.Context, repoPath string) (*artifactHubPackage, int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v1/packages/%s", h.artifactHubURL, repoPath), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("User-Agent", "kube-dash/1.0")
+	resp, err := (&http.Client{Timeout: 30 * time.Second}).Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode, fmt.Errorf("Artifact Hub API returned status %d", resp.StatusCode)
+	}
+	var pkg artifactHubPackage
+	if err := json.NewDecoder(resp.Body).Decode(&pkg); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse package details: %w", err)
+	}
+	return &pkg, http.StatusOK, nil
+}
+
+// chartDetails converts an Artifact Hub package into chart details
+func chartDetails(pkg *artifactHubPackage) HelmChartDetails {
+	details := HelmChartDetails{
+		PackageID:   pkg.PackageID,
+		Name:        pkg.Name,
+		DisplayName: pkg.DisplayName,
+		Description: pkg.Description,
+		Version:     pkg.Version,
+		AppVersion:  pkg.AppVersion,
+		Created:     types.TimeFormat(time.Unix(pkg.CreatedAt, 0).UTC()),
+		CreatedMs:   types.EpochMillis(time.Unix(pkg.CreatedAt, 0)),
+		Home:        pkg.HomeURL,
+		License:     pkg.License,
+		Keywords:    pkg.Keywords,
+		Maintainers: []HelmChartMaintainer{},
+		Links:       []HelmChartLink{},
+		Repository: HelmChartRepository{
+			Name:     pkg.Repository.Name,
+			URL:      pkg.Repository.URL,
+			Official: pkg.Repository.Official,
+		},
+		Versions: []HelmChartVersionSummary{},
+		Readme:   pkg.Readme,
+	}
+	if details.Keywords == nil {
+		details.Keywords = []string{}
+	}
+	if pkg.LogoImageID != "" {
+		details.Icon = fmt.Sprintf("https://artifacthub.io/image/%s", pkg.LogoImageID)
+	}
+	for _, m := range pkg.Maintainers {
+		details.Maintainers = append(details.Maintainers, HelmChartMaintainer{Name: m.Name, Email: m.Email})
+	}
+	for _, l := range pkg.Links {
+		details.Links = append(details.Links, HelmChartLink{Name: l.Name, URL: l.URL})
+	}
+	for _, v := range pkg.AvailableVersions {
+		details.Versions = append(details.Versions, HelmChartVersionSummary{
+			Version:    v.Version,
+			AppVersion: v.AppVersion,
+			Created:    types.TimeFormat(time.Unix(v.CreatedAt, 0).UTC()),
+			CreatedMs:  types.EpochMillis(time.Unix(v.CreatedAt, 0)),
+		})
+	}
+	return details
+}
+
+// GetHelmChartDetailsV2 returns typed details of a chart
+// @Summary Get Helm chart details
+// @Description Returns the details of a chart from Artifact Hub, with its published versions and the default values of its latest version. The chart is identified by a package ID returned by chart search or by a chart name. Unlike the v1 endpoint, failures are reported with an error status rather than in the body.
+// @Tags Helm
+// @Produce json
+// @Param packageId path string true "Package ID or chart name"
+// @Param repository query string false "Repository to prefer when a chart name is given"
+// @Success 200 {object} HelmChartDetails "Chart details"
+// @Failure 404 {object} map[string]string "Chart not found"
+// @Failure 502 {object} map[string]string "Artifact Hub request failed"
+// @Security BearerAuth
+// @Router /api/v2/helmcharts/{packageId} [get]
+func (h *HelmHandler) GetHelmChartDetailsV2(c *gin.Context) {
+	packageID := c.Param("packageId")
+	repoPath, ok := h.resolveRepoPathFromPackageOrName(c, packageID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("chart %s not found", packageID)})
+		return
+	}
+
+	pkg, status, err := h.fetchArtifactHubPackage(c.Request.Context(), repoPath)
+	if err != nil {
+		h.logger.Warn("Failed to fetch chart details from Artifact Hub", "packageId", packageID, "repoPath", repoPath, "error", err)
+		if status == http.StatusNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("chart %s not found", packageID)})
+			return
+		}
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	details := chartDetails(pkg)
+	if pkg.ContentURL != "" {
+		if values, err := h.fetchDefaultValuesFromArtifactHub(pkg.ContentURL); err == nil {
+			details.DefaultValues = values
+		} else {
+			details.ValuesError = err.Error()
+		}
+	}
+	c.JSON(http.StatusOK, details)
+}
@@ -0,0 +1,33 @@
+package helm
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/testharness"
+)
+
+func TestGetHelmChartDetailsV2(t *testing.T) {
+	h := testharness.New(t)
+	artifactHub := testharness.ReplayServer(t, map[string]string{
+		"/api/v1/packages/search":             "artifacthub/search_nginx.json",
+		"/api/v1/packages/helm/bitnami/nginx": "artifacthub/package_nginx.json",
+	})
+	handler := NewHelmHandler(h.Store, h.Factory, k8s.NewHelmClientFactory(), h.Logger)
+	handler.SetArtifactHubURL(artifactHub.URL)
+	route := "/api/v2/helmcharts/:packageId"
+
+	var details HelmChartDetails
+	h.DoJSON(http.MethodGet, route, handler.GetHelmChartDetailsV2, "/api/v2/helmcharts/nginx", nil, &details)
+	if details.Name != "nginx" || details.Repository.Name != "bitnami" || details.Created != "2024-10-15T00:00:00Z" || details.Icon == "" {
+		t.Errorf("details = %+v", details)
+	}
+	if len(details.Versions) != 2 || details.Versions[1].Version != "18.2.3" || len(details.Maintainers) != 1 || len(details.Links) != 1 {
+		t.Errorf("versions = %+v, maintainers = %+v, links = %+v", details.Versions, details.Maintainers, details.Links)
+	}
+
+	if w := h.Do(http.MethodGet, route, handler.GetHelmChartDetailsV2, "/api/v2/helmcharts/ingress-nginx", nil); w.Code != http.StatusNotFound {
+		t.Errorf("chart missing from Artifact Hub: status %d, want 404", w.Code)
+	}
+}
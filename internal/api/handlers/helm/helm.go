@@ -31,6 +31,9 @@ import (
 	networkingv1 "k8s.io/api/networking/v1"
 )
 
+// defaultArtifactHubURL is the Artifact Hub instance charts are searched on
+const defaultArtifactHubURL = "https://artifacthub.io"
+
 // CacheEntry represents a cached item with expiration
 type CacheEntry struct {
 	Data      interface{}
@@ -59,6 +62,9 @@ type HelmHandler struct {
 	// Cache for quick chart name -> repo path resolution to avoid repeated AH searches
 	chartNameRepoPath map[string]string
 	chartNameMux      sync.RWMutex
+
+	// Base URL of the Artifact Hub API, replaced in tests by a server replaying recorded responses
+	artifactHubURL string
 }
 
 // NewHelmHandler creates a new Helm handler
@@ -75,6 +81,7 @@ func NewHelmHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFac
 		cacheTTL:          120 * time.Second, // Increased to 2 minutes for better performance
 		pkgIDRepoPath:     make(map[string]string),
 		chartNameRepoPath: make(map[string]string),
+		artifactHubURL:    defaultArtifactHubURL,
 	}
 
 	// Start background cache cleanup
@@ -83,6 +90,12 @@ func NewHelmHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFac
 	return handler
 }
 
+// SetArtifactHubURL points chart search and details at another Artifact Hub, such as a mirror or
+// a fixture server
+func (h *HelmHandler) SetArtifactHubURL(baseURL string) {
+	h.artifactHubURL = strings.TrimSuffix(baseURL, "/")
+}
+
 // setPackageRepoPath stores a mapping of Artifact Hub package ID to repository path
 func (h *HelmHandler) setPackageRepoPath(packageID, repo, chart string) {
 	if packageID == "" || repo == "" || chart == "" {
@@ -303,7 +316,7 @@ func (h *HelmHandler) GetHelmReleasesSSE(c *gin.Context) {
 	}
 
 	// For non-SSE requests, return JSON
-	c.JSON(http.StatusOK, initialData)
+	h.sseHandler.SendJSON(c, initialData)
 	h.tracingHelper.RecordSuccess(span, "GetHelmReleasesSSE completed successfully (JSON)")
 }
 
@@ -364,7 +377,8 @@ func (h *HelmHandler) fetchHelmReleasesOptimized(config *api.Config, cluster, na
 			Namespace:   rel.Namespace,
 			Status:      string(rel.Info.Status),
 			Revision:    rel.Version,
-			Updated:     rel.Info.LastDeployed.Time,
+			Updated:     types.FormatTimestamp(rel.Info.LastDeployed.Time, time.RFC3339Nano),
+			UpdatedMs:   types.EpochMillis(rel.Info.LastDeployed.Time),
 			Chart:       rel.Chart.Metadata.Name,
 			AppVersion:  rel.Chart.Metadata.AppVersion,
 			Version:     rel.Chart.Metadata.Version,
@@ -499,7 +513,7 @@ func (h *HelmHandler) GetHelmReleaseDetails(c *gin.Context) {
 	}
 
 	// For non-SSE requests, return JSON
-	c.JSON(http.StatusOK, initialData)
+	h.sseHandler.SendJSON(c, initialData)
 	h.tracingHelper.RecordSuccess(span, "Helm release details operation completed")
 }
 
@@ -531,7 +545,8 @@ func (h *HelmHandler) fetchHelmReleaseDetailsOptimized(config *api.Config, clust
 				Namespace:   rel.Namespace,
 				Status:      string(rel.Info.Status),
 				Revision:    rel.Version,
-				Updated:     rel.Info.LastDeployed.Time,
+				Updated:     types.FormatTimestamp(rel.Info.LastDeployed.Time, time.RFC3339Nano),
+				UpdatedMs:   types.EpochMillis(rel.Info.LastDeployed.Time),
 				Chart:       rel.Chart.Metadata.Name,
 				AppVersion:  rel.Chart.Metadata.AppVersion,
 				Version:     rel.Chart.Metadata.Version,
@@ -584,7 +599,8 @@ func (h *HelmHandler) fetchHelmReleaseDetailsOptimized(config *api.Config, clust
 		for _, hist := range helmHistory {
 			historyItem := types.HelmReleaseHistory{
 				Revision:    hist.Version,
-				Updated:     hist.Info.LastDeployed.Time,
+				Updated:     types.FormatTimestamp(hist.Info.LastDeployed.Time, time.RFC3339Nano),
+				UpdatedMs:   types.EpochMillis(hist.Info.LastDeployed.Time),
 				Status:      string(hist.Info.Status),
 				Chart:       hist.Chart.Metadata.Name,
 				AppVersion:  hist.Chart.Metadata.AppVersion,
@@ -699,6 +715,7 @@ func (h *HelmHandler) GetHelmReleaseHistory(c *gin.Context) {
 				historyItem := types.HelmReleaseHistoryResponse{
 					Revision:    hist.Version,
 					Updated:     types.TimeFormat(hist.Info.LastDeployed.Time),
+					UpdatedMs:   types.EpochMillis(hist.Info.LastDeployed.Time),
 					Status:      string(hist.Info.Status),
 					Chart:       hist.Chart.Metadata.Name,
 					AppVersion:  hist.Chart.Metadata.AppVersion,
@@ -737,7 +754,7 @@ func (h *HelmHandler) GetHelmReleaseHistory(c *gin.Context) {
 	}
 
 	// For non-SSE requests, return JSON
-	c.JSON(http.StatusOK, initialData)
+	h.sseHandler.SendJSON(c, initialData)
 	h.tracingHelper.RecordSuccess(span, "Helm release history operation completed")
 }
 
@@ -829,7 +846,7 @@ func (h *HelmHandler) GetHelmReleaseResources(c *gin.Context) {
 	}
 
 	// For non-SSE requests, return JSON
-	c.JSON(http.StatusOK, initialData)
+	h.sseHandler.SendJSON(c, initialData)
 	h.tracingHelper.RecordSuccess(span, "Helm release resources operation completed")
 }
 
@@ -959,7 +976,8 @@ func (h *HelmHandler) fetchHelmReleaseResourcesOptimized(config *api.Config, clu
 							Namespace:  deployment.Namespace,
 							Status:     getDeploymentStatus(deployment),
 							Age:        getAge(deployment.CreationTimestamp.Time),
-							Created:    deployment.CreationTimestamp.Time.Format(time.RFC3339),
+							Created:    types.TimeFormat(deployment.CreationTimestamp.Time),
+							CreatedMs:  types.EpochMillis(deployment.CreationTimestamp.Time),
 							Labels:     deployment.Labels,
 							APIVersion: "apps/v1",
 						}
@@ -979,7 +997,8 @@ func (h *HelmHandler) fetchHelmReleaseResourcesOptimized(config *api.Config, clu
 							Namespace:  service.Namespace,
 							Status:     getServiceStatus(service),
 							Age:        getAge(service.CreationTimestamp.Time),
-							Created:    service.CreationTimestamp.Time.Format(time.RFC3339),
+							Created:    types.TimeFormat(service.CreationTimestamp.Time),
+							CreatedMs:  types.EpochMillis(service.CreationTimestamp.Time),
 							Labels:     service.Labels,
 							APIVersion: "v1",
 						}
@@ -999,7 +1018,8 @@ func (h *HelmHandler) fetchHelmReleaseResourcesOptimized(config *api.Config, clu
 							Namespace:  configMap.Namespace,
 							Status:     "Active",
 							Age:        getAge(configMap.CreationTimestamp.Time),
-							Created:    configMap.CreationTimestamp.Time.Format(time.RFC3339),
+							Created:    types.TimeFormat(configMap.CreationTimestamp.Time),
+							CreatedMs:  types.EpochMillis(configMap.CreationTimestamp.Time),
 							Labels:     configMap.Labels,
 							APIVersion: "v1",
 						}
@@ -1019,7 +1039,8 @@ func (h *HelmHandler) fetchHelmReleaseResourcesOptimized(config *api.Config, clu
 							Namespace:  secret.Namespace,
 							Status:     "Active",
 							Age:        getAge(secret.CreationTimestamp.Time),
-							Created:    secret.CreationTimestamp.Time.Format(time.RFC3339),
+							Created:    types.TimeFormat(secret.CreationTimestamp.Time),
+							CreatedMs:  types.EpochMillis(secret.CreationTimestamp.Time),
 							Labels:     secret.Labels,
 							APIVersion: "v1",
 						}
@@ -1039,7 +1060,8 @@ func (h *HelmHandler) fetchHelmReleaseResourcesOptimized(config *api.Config, clu
 							Namespace:  pvc.Namespace,
 							Status:     string(pvc.Status.Phase),
 							Age:        getAge(pvc.CreationTimestamp.Time),
-							Created:    pvc.CreationTimestamp.Time.Format(time.RFC3339),
+							Created:    types.TimeFormat(pvc.CreationTimestamp.Time),
+							CreatedMs:  types.EpochMillis(pvc.CreationTimestamp.Time),
 							Labels:     pvc.Labels,
 							APIVersion: "v1",
 						}
@@ -1059,7 +1081,8 @@ func (h *HelmHandler) fetchHelmReleaseResourcesOptimized(config *api.Config, clu
 							Namespace:  sa.Namespace,
 							Status:     "Active",
 							Age:        getAge(sa.CreationTimestamp.Time),
-							Created:    sa.CreationTimestamp.Time.Format(time.RFC3339),
+							Created:    types.TimeFormat(sa.CreationTimestamp.Time),
+							CreatedMs:  types.EpochMillis(sa.CreationTimestamp.Time),
 							Labels:     sa.Labels,
 							APIVersion: "v1",
 						}
@@ -1079,7 +1102,8 @@ func (h *HelmHandler) fetchHelmReleaseResourcesOptimized(config *api.Config, clu
 							Namespace:  sts.Namespace,
 							Status:     getStatefulSetStatus(sts),
 							Age:        getAge(sts.CreationTimestamp.Time),
-							Created:    sts.CreationTimestamp.Time.Format(time.RFC3339),
+							Created:    types.TimeFormat(sts.CreationTimestamp.Time),
+							CreatedMs:  types.EpochMillis(sts.CreationTimestamp.Time),
 							Labels:     sts.Labels,
 							APIVersion: "apps/v1",
 						}
@@ -1099,7 +1123,8 @@ func (h *HelmHandler) fetchHelmReleaseResourcesOptimized(config *api.Config, clu
 							Namespace:  ds.Namespace,
 							Status:     getDaemonSetStatus(ds),
 							Age:        getAge(ds.CreationTimestamp.Time),
-							Created:    ds.CreationTimestamp.Time.Format(time.RFC3339),
+							Created:    types.TimeFormat(ds.CreationTimestamp.Time),
+							CreatedMs:  types.EpochMillis(ds.CreationTimestamp.Time),
 							Labels:     ds.Labels,
 							APIVersion: "apps/v1",
 						}
@@ -1119,7 +1144,8 @@ func (h *HelmHandler) fetchHelmReleaseResourcesOptimized(config *api.Config, clu
 							Namespace:  rs.Namespace,
 							Status:     getReplicaSetStatus(rs),
 							Age:        getAge(rs.CreationTimestamp.Time),
-							Created:    rs.CreationTimestamp.Time.Format(time.RFC3339),
+							Created:    types.TimeFormat(rs.CreationTimestamp.Time),
+							CreatedMs:  types.EpochMillis(rs.CreationTimestamp.Time),
 							Labels:     rs.Labels,
 							APIVersion: "apps/v1",
 						}
@@ -1139,7 +1165,8 @@ func (h *HelmHandler) fetchHelmReleaseResourcesOptimized(config *api.Config, clu
 							Namespace:  ingress.Namespace,
 							Status:     getIngressStatus(ingress),
 							Age:        getAge(ingress.CreationTimestamp.Time),
-							Created:    ingress.CreationTimestamp.Time.Format(time.RFC3339),
+							Created:    types.TimeFormat(ingress.CreationTimestamp.Time),
+							CreatedMs:  types.EpochMillis(ingress.CreationTimestamp.Time),
 							Labels:     ingress.Labels,
 							APIVersion: "networking.k8s.io/v1",
 						}
@@ -1159,7 +1186,8 @@ func (h *HelmHandler) fetchHelmReleaseResourcesOptimized(config *api.Config, clu
 							Namespace:  job.Namespace,
 							Status:     getJobStatus(job),
 							Age:        getAge(job.CreationTimestamp.Time),
-							Created:    job.CreationTimestamp.Time.Format(time.RFC3339),
+							Created:    types.TimeFormat(job.CreationTimestamp.Time),
+							CreatedMs:  types.EpochMillis(job.CreationTimestamp.Time),
 							Labels:     job.Labels,
 							APIVersion: "batch/v1",
 						}
@@ -1179,7 +1207,8 @@ func (h *HelmHandler) fetchHelmReleaseResourcesOptimized(config *api.Config, clu
 							Namespace:  cronJob.Namespace,
 							Status:     getCronJobStatus(cronJob),
 							Age:        getAge(cronJob.CreationTimestamp.Time),
-							Created:    cronJob.CreationTimestamp.Time.Format(time.RFC3339),
+							Created:    types.TimeFormat(cronJob.CreationTimestamp.Time),
+							CreatedMs:  types.EpochMillis(cronJob.CreationTimestamp.Time),
 							Labels:     cronJob.Labels,
 							APIVersion: "batch/v1",
 						}
@@ -1199,7 +1228,8 @@ func (h *HelmHandler) fetchHelmReleaseResourcesOptimized(config *api.Config, clu
 							Namespace:  hpa.Namespace,
 							Status:     "Active",
 							Age:        getAge(hpa.CreationTimestamp.Time),
-							Created:    hpa.CreationTimestamp.Time.Format(time.RFC3339),
+							Created:    types.TimeFormat(hpa.CreationTimestamp.Time),
+							CreatedMs:  types.EpochMillis(hpa.CreationTimestamp.Time),
 							Labels:     hpa.Labels,
 							APIVersion: "autoscaling/v2",
 						}
@@ -1891,8 +1921,8 @@ func (h *HelmHandler) resolveRepoURLFromChartName(chartName, repositoryFilter st
 		return "", false
 	}
 	searchURL := fmt.Sprintf(
-		"https://artifacthub.io/api/v1/packages/search?kind=0&ts_query_web=%s&limit=%d",
-		url.QueryEscape(chartName), 25,
+		"%s/api/v1/packages/search?kind=0&ts_query_web=%s&limit=%d",
+		h.artifactHubURL, url.QueryEscape(chartName), 25,
 	)
 	client := &http.Client{Timeout: 30 * time.Second}
 	req, _ := http.NewRequest("GET", searchURL, nil)
@@ -0,0 +1,655 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+// Preflight issue codes
+const (
+	PreflightNamespaceMissing   = "namespace-missing"
+	PreflightNamespaceCreate    = "namespace-create"
+	PreflightReleaseExists      = "release-exists"
+	PreflightReleaseMissing     = "release-missing"
+	PreflightUnknownKind        = "unknown-kind"
+	PreflightForbidden          = "forbidden"
+	PreflightOwnershipConflict  = "ownership-conflict"
+	PreflightCRDExists          = "crd-exists"
+	PreflightCRDSkippedUpgrade  = "crd-not-upgraded"
+	PreflightUnverified         = "unverified"
+	preflightAccessReviewWorker = 8
+)
+
+// helmReleaseNameAnnotation and helmReleaseNamespaceAnnotation mark the release that owns an object
+const (
+	helmReleaseNameAnnotation      = "meta.helm.sh/release-name"
+	helmReleaseNamespaceAnnotation = "meta.helm.sh/release-namespace"
+)
+
+var crdResource = schema.GroupResource{Group: "apiextensions.k8s.io", Resource: "customresourcedefinitions"}
+
+// PreflightIssue is a problem the install or upgrade would run into
+type PreflightIssue struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Kind      string `json:"kind,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// PermissionCheck is the result of one access review of the acting credentials
+type PermissionCheck struct {
+	Verb      string `json:"verb"`
+	Group     string `json:"group"`
+	Resource  string `json:"resource"`
+	Namespace string `json:"namespace,omitempty"`
+	Allowed   bool   `json:"allowed"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// PreflightReport lists what blocks an install or upgrade and what deserves attention
+type PreflightReport struct {
+	Release     string            `json:"release"`
+	Namespace   string            `json:"namespace"`
+	Chart       string            `json:"chart"`
+	Version     string            `json:"version"`
+	Upgrade     bool              `json:"upgrade"`
+	Ready       bool              `json:"ready"` // no blocking issues
+	Resources   int               `json:"resources"`
+	Blocking    []PreflightIssue  `json:"blocking"`
+	Warnings    []PreflightIssue  `json:"warnings"`
+	Permissions []PermissionCheck `json:"permissions"`
+}
+
+// renderedObject is an object of the rendered chart
+type renderedObject struct {
+	APIVersion string
+	Kind       string
+	Name       string
+	Namespace  string // empty when the template leaves it to the release namespace
+}
+
+// chartCRD is a CustomResourceDefinition shipped by the chart
+type chartCRD struct {
+	Name       string // <plural>.<group>
+	Group      string
+	Kind       string
+	Plural     string
+	Namespaced bool
+	Templated  bool // rendered from templates/ rather than shipped in crds/, so Helm manages it like any object
+}
+
+// preflightInput is what the checks run against
+type preflightInput struct {
+	Release       string
+	Namespace     string
+	Upgrade       bool
+	ReleaseExists bool
+	Objects       []renderedObject
+	CRDs          []chartCRD
+}
+
+// preflighter runs the checks against a cluster
+type preflighter struct {
+	client  kubernetes.Interface
+	dynamic dynamic.Interface
+	mapper  meta.RESTMapper
+}
+
+// parseManifest reads the objects and CRDs of a manifest. CRDs from crds/ are not objects of
+// the release, while templated ones are both.
+func parseManifest(manifest string, templated bool) ([]renderedObject, []chartCRD) {
+	var objects []renderedObject
+	var crds []chartCRD
+	docs := releaseutil.SplitManifests(manifest)
+	keys := make([]string, 0, len(docs))
+	for key := range docs {
+		keys = append(keys, key)
+	}
+	sort.Sort(releaseutil.BySplitManifestsOrder(keys))
+	for _, key := range keys {
+		var obj struct {
+			APIVersion string `json:"apiVersion"`
+			Kind       string `json:"kind"`
+			Metadata   struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"metadata"`
+			Spec struct {
+				Group string `json:"group"`
+				Names struct {
+					Kind   string `json:"kind"`
+					Plural string `json:"plural"`
+				} `json:"names"`
+				Scope string `json:"scope"`
+			} `json:"spec"`
+		}
+		if err := yaml.Unmarshal([]byte(docs[key]), &obj); err != nil || obj.Kind == "" {
+			continue
+		}
+		if obj.Kind == "CustomResourceDefinition" && strings.HasPrefix(obj.APIVersion, crdResource.Group+"/") {
+			crds = append(crds, chartCRD{
+				Name:       obj.Metadata.Name,
+				Group:      obj.Spec.Group,
+				Kind:       obj.Spec.Names.Kind,
+				Plural:     obj.Spec.Names.Plural,
+				Namespaced: obj.Spec.Scope != "Cluster",
+				Templated:  templated,
+			})
+			if !templated {
+				continue
+			}
+		}
+		objects = append(objects, renderedObject{
+			APIVersion: obj.APIVersion,
+			Kind:       obj.Kind,
+			Name:       obj.Metadata.Name,
+			Namespace:  obj.Metadata.Namespace,
+		})
+	}
+	return objects, crds
+}
+
+// chartCRDs reads the CRDs in the crds/ directories of a chart and its subcharts
+func chartCRDs(ch *chart.Chart) []chartCRD {
+	var crds []chartCRD
+	for _, crd := range ch.CRDObjects() {
+		_, found := parseManifest(string(crd.File.Data), false)
+		crds = append(crds, found...)
+	}
+	return crds
+}
+
+// accessKey identifies one access review
+type accessKey struct {
+	verb      string
+	group     string
+	resource  string
+	namespace string
+}
+
+// reviewAccess runs the access reviews concurrently, in order of the keys
+func (p *preflighter) reviewAccess(ctx context.Context, keys []accessKey) []PermissionCheck {
+	checks := make([]PermissionCheck, len(keys))
+	sem := make(chan struct{}, preflightAccessReviewWorker)
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func(i int, key accessKey) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			check := PermissionCheck{Verb: key.verb, Group: key.group, Resource: key.resource, Namespace: key.namespace}
+			review := &authorizationv1.SelfSubjectAccessReview{
+				Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+					ResourceAttributes: &authorizationv1.ResourceAttributes{
+						Namespace: key.namespace,
+						Verb:      key.verb,
+						Group:     key.group,
+						Resource:  key.resource,
+					},
+				},
+			}
+			result, err := p.client.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+			if err != nil {
+				check.Reason = err.Error()
+			} else {
+				check.Allowed = result.Status.Allowed
+				check.Reason = result.Status.Reason
+			}
+			checks[i] = check
+		}(i, key)
+	}
+	wg.Wait()
+	return checks
+}
+
+// resolvedObject is a rendered object with its API resource
+type resolvedObject struct {
+	renderedObject
+	resource   schema.GroupVersionResource
+	namespaced bool
+	fromChart  bool // served by a CRD the chart installs, so it cannot exist yet
+}
+
+// resolve maps the objects to API resources, falling back to the chart's own CRDs
+func (p *preflighter) resolve(in *preflightInput, report *PreflightReport) []resolvedObject {
+	crdKinds := map[schema.GroupKind]chartCRD{}
+	for _, crd := range in.CRDs {
+		crdKinds[schema.GroupKind{Group: crd.Group, Kind: crd.Kind}] = crd
+	}
+	var resolved []resolvedObject
+	for _, obj := range in.Objects {
+		gv, err := schema.ParseGroupVersion(obj.APIVersion)
+		if err != nil {
+			report.Blocking = append(report.Blocking, PreflightIssue{Code: PreflightUnknownKind, Kind: obj.Kind, Name: obj.Name,
+				Message: fmt.Sprintf("invalid apiVersion %q", obj.APIVersion)})
+			continue
+		}
+		gk := schema.GroupKind{Group: gv.Group, Kind: obj.Kind}
+		if mapping, err := p.mapper.RESTMapping(gk, gv.Version); err == nil {
+			resolved = append(resolved, resolvedObject{renderedObject: obj, resource: mapping.Resource, namespaced: mapping.Scope.Name() == meta.RESTScopeNameNamespace})
+			continue
+		}
+		if crd, ok := crdKinds[gk]; ok {
+			resolved = append(resolved, resolvedObject{renderedObject: obj, resource: gv.WithResource(crd.Plural), namespaced: crd.Namespaced, fromChart: true})
+			continue
+		}
+		report.Blocking = append(report.Blocking, PreflightIssue{Code: PreflightUnknownKind, Kind: obj.Kind, Name: obj.Name,
+			Message: fmt.Sprintf("the cluster does not serve %s %s and the chart does not define it", obj.APIVersion, obj.Kind)})
+	}
+	return resolved
+}
+
+// checkNamespaces verifies the release namespace and the namespaces objects are placed in
+func (p *preflighter) checkNamespaces(ctx context.Context, in *preflightInput, objects []resolvedObject, report *PreflightReport) []accessKey {
+	var keys []accessKey
+	rendered := map[string]bool{}
+	for _, obj := range objects {
+		if obj.resource.Group == "" && obj.resource.Resource == "namespaces" {
+			rendered[obj.Name] = true
+		}
+	}
+	namespaces := map[string]bool{in.Namespace: true}
+	for _, obj := range objects {
+		if obj.namespaced && obj.Namespace != "" {
+			namespaces[obj.Namespace] = true
+		}
+	}
+	names := make([]string, 0, len(namespaces))
+	for name := range namespaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		_, err := p.client.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+		switch {
+		case err == nil || rendered[name]:
+		case apierrors.IsNotFound(err) && name == in.Namespace && !in.Upgrade:
+			// Installs create the release namespace
+			report.Warnings = append(report.Warnings, PreflightIssue{Code: PreflightNamespaceCreate, Kind: "Namespace", Name: name,
+				Message: fmt.Sprintf("namespace %s does not exist and will be created", name)})
+			keys = append(keys, accessKey{verb: "create", resource: "namespaces"})
+		case apierrors.IsNotFound(err):
+			report.Blocking = append(report.Blocking, PreflightIssue{Code: PreflightNamespaceMissing, Kind: "Namespace", Name: name,
+				Message: fmt.Sprintf("namespace %s does not exist", name)})
+		default:
+			report.Warnings = append(report.Warnings, PreflightIssue{Code: PreflightUnverified, Kind: "Namespace", Name: name,
+				Message: fmt.Sprintf("could not check namespace %s: %v", name, err)})
+		}
+	}
+	return keys
+}
+
+// checkCRDs reports CRDs from crds/ that already exist, or that an upgrade will not install
+func (p *preflighter) checkCRDs(ctx context.Context, in *preflightInput, report *PreflightReport) []accessKey {
+	var keys []accessKey
+	for _, crd := range in.CRDs {
+		if crd.Templated {
+			continue
+		}
+		obj, err := p.dynamic.Resource(crdResource.WithVersion("v1")).Get(ctx, crd.Name, metav1.GetOptions{})
+		switch {
+		case err == nil:
+			report.Warnings = append(report.Warnings, PreflightIssue{Code: PreflightCRDExists, Kind: "CustomResourceDefinition", Name: obj.GetName(),
+				Message: fmt.Sprintf("CRD %s already exists; Helm leaves it unchanged", crd.Name)})
+		case apierrors.IsNotFound(err) && in.Upgrade:
+			report.Warnings = append(report.Warnings, PreflightIssue{Code: PreflightCRDSkippedUpgrade, Kind: "CustomResourceDefinition", Name: crd.Name,
+				Message: fmt.Sprintf("CRD %s is missing and Helm does not install CRDs on upgrade", crd.Name)})
+		case apierrors.IsNotFound(err):
+			keys = append(keys, accessKey{verb: "create", group: crdResource.Group, resource: crdResource.Resource})
+		default:
+			report.Warnings = append(report.Warnings, PreflightIssue{Code: PreflightUnverified, Kind: "CustomResourceDefinition", Name: crd.Name,
+				Message: fmt.Sprintf("could not check CRD %s: %v", crd.Name, err)})
+		}
+	}
+	return keys
+}
+
+// checkOwnership finds existing objects another release or no release owns, which Helm refuses
+// to adopt
+func (p *preflighter) checkOwnership(ctx context.Context, in *preflightInput, objects []resolvedObject, report *PreflightReport) {
+	issues := make([]*PreflightIssue, len(objects))
+	sem := make(chan struct{}, preflightAccessReviewWorker)
+	var wg sync.WaitGroup
+	for i := range objects {
+		obj := &objects[i]
+		if obj.fromChart {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, obj *resolvedObject) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			resource := p.dynamic.Resource(obj.resource)
+			namespace := ""
+			var live interface{ GetAnnotations() map[string]string }
+			var err error
+			if obj.namespaced {
+				namespace = objectNamespace(obj, in.Namespace)
+				live, err = resource.Namespace(namespace).Get(ctx, obj.Name, metav1.GetOptions{})
+			} else {
+				live, err = resource.Get(ctx, obj.Name, metav1.GetOptions{})
+			}
+			if err != nil {
+				return
+			}
+			annotations := live.GetAnnotations()
+			owner, ownerNamespace := annotations[helmReleaseNameAnnotation], annotations[helmReleaseNamespaceAnnotation]
+			if owner == in.Release && ownerNamespace == in.Namespace {
+				return
+			}
+			message := fmt.Sprintf("%s %s already exists and is not managed by Helm", obj.Kind, obj.Name)
+			if owner != "" {
+				message = fmt.Sprintf("%s %s already exists and belongs to release %s/%s", obj.Kind, obj.Name, ownerNamespace, owner)
+			}
+			issues[i] = &PreflightIssue{Code: PreflightOwnershipConflict, Kind: obj.Kind, Name: obj.Name, Namespace: namespace, Message: message}
+		}(i, obj)
+	}
+	wg.Wait()
+	for _, issue := range issues {
+		if issue != nil {
+			report.Blocking = append(report.Blocking, *issue)
+		}
+	}
+}
+
+// objectNamespace returns the namespace a namespaced object is created in
+func objectNamespace(obj *resolvedObject, releaseNamespace string) string {
+	if obj.Namespace != "" {
+		return obj.Namespace
+	}
+	return releaseNamespace
+}
+
+// run checks the namespaces, the release, the chart's CRDs, object ownership and the acting
+// credentials' permissions for every rendered resource kind
+func (p *preflighter) run(ctx context.Context, in *preflightInput) PreflightReport {
+	report := PreflightReport{
+		Release:     in.Release,
+		Namespace:   in.Namespace,
+		Upgrade:     in.Upgrade,
+		Resources:   len(in.Objects),
+		Blocking:    []PreflightIssue{},
+		Warnings:    []PreflightIssue{},
+		Permissions: []PermissionCheck{},
+	}
+	switch {
+	case in.Upgrade && !in.ReleaseExists:
+		report.Blocking = append(report.Blocking, PreflightIssue{Code: PreflightReleaseMissing, Name: in.Release, Namespace: in.Namespace,
+			Message: fmt.Sprintf("release %s does not exist in namespace %s", in.Release, in.Namespace)})
+	case !in.Upgrade && in.ReleaseExists:
+		report.Blocking = append(report.Blocking, PreflightIssue{Code: PreflightReleaseExists, Name: in.Release, Namespace: in.Namespace,
+			Message: fmt.Sprintf("release %s already exists in namespace %s", in.Release, in.Namespace)})
+	}
+
+	objects := p.resolve(in, &report)
+	keys := p.checkNamespaces(ctx, in, objects, &report)
+	keys = append(keys, p.checkCRDs(ctx, in, &report)...)
+	if !in.Upgrade {
+		p.checkOwnership(ctx, in, objects, &report)
+	}
+
+	// Helm keeps release state in Secrets of the release namespace
+	verbs := []string{"create"}
+	if in.Upgrade {
+		verbs = []string{"create", "patch"}
+	}
+	keys = append(keys, accessKey{verb: "create", resource: "secrets", namespace: in.Namespace})
+	if in.Upgrade {
+		keys = append(keys, accessKey{verb: "update", resource: "secrets", namespace: in.Namespace})
+	}
+	for _, obj := range objects {
+		namespace := ""
+		if obj.namespaced {
+			namespace = objectNamespace(&obj, in.Namespace)
+		}
+		for _, verb := range verbs {
+			keys = append(keys, accessKey{verb: verb, group: obj.resource.Group, resource: obj.resource.Resource, namespace: namespace})
+		}
+	}
+	keys = uniqueAccessKeys(keys)
+
+	report.Permissions = p.reviewAccess(ctx, keys)
+	for _, check := range report.Permissions {
+		if check.Allowed {
+			continue
+		}
+		resource := check.Resource
+		if check.Group != "" {
+			resource += "." + check.Group
+		}
+		where := "cluster-wide"
+		if check.Namespace != "" {
+			where = "in namespace " + check.Namespace
+		}
+		report.Blocking = append(report.Blocking, PreflightIssue{Code: PreflightForbidden, Namespace: check.Namespace,
+			Message: fmt.Sprintf("cannot %s %s %s", check.Verb, resource, where)})
+	}
+	report.Ready = len(report.Blocking) == 0
+	return report
+}
+
+// uniqueAccessKeys drops repeated access reviews and sorts the rest
+func uniqueAccessKeys(keys []accessKey) []accessKey {
+	seen := map[accessKey]bool{}
+	unique := make([]accessKey, 0, len(keys))
+	for _, key := range keys {
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, key)
+		}
+	}
+	sort.Slice(unique, func(i, j int) bool {
+		a, b := unique[i], unique[j]
+		if a.namespace != b.namespace {
+			return a.namespace < b.namespace
+		}
+		if a.group != b.group {
+			return a.group < b.group
+		}
+		if a.resource != b.resource {
+			return a.resource < b.resource
+		}
+		return a.verb < b.verb
+	})
+	return unique
+}
+
+// renderChart renders the chart without touching the cluster, using its version and APIs so
+// capability checks in templates resolve as they would on install
+func renderChart(ch *chart.Chart, release, namespace string, upgrade bool, vals map[string]interface{}, client kubernetes.Interface) (string, error) {
+	install := action.NewInstall(&action.Configuration{Log: func(string, ...interface{}) {}})
+	install.ReleaseName = release
+	install.Namespace = namespace
+	install.DryRun = true
+	install.ClientOnly = true
+	install.IsUpgrade = upgrade
+	install.Replace = true
+	if version, err := client.Discovery().ServerVersion(); err == nil {
+		if kubeVersion, err := chartutil.ParseKubeVersion(version.GitVersion); err == nil {
+			install.KubeVersion = kubeVersion
+		}
+	}
+	if apiVersions, err := action.GetVersionSet(client.Discovery()); err == nil {
+		install.APIVersions = apiVersions
+	}
+	rel, err := install.Run(ch, vals)
+	if err != nil {
+		return "", err
+	}
+	var manifest strings.Builder
+	manifest.WriteString(rel.Manifest)
+	for _, hook := range rel.Hooks {
+		manifest.WriteString("\n---\n")
+		manifest.WriteString(hook.Manifest)
+	}
+	return manifest.String(), nil
+}
+
+// PreflightHelmChart checks whether an install or upgrade can succeed
+// @Summary Preflight a Helm install or upgrade
+// @Description Renders the chart with the given values and checks, without changing the cluster, that the release namespace exists or can be created, namespaces objects are placed in exist, every rendered kind is served by the cluster or defined by the chart, CRDs in crds/ can be created, no existing object belongs to another release, and the acting credentials can create (and on upgrade patch) every rendered resource kind, using one SelfSubjectAccessReview per kind and namespace. Blocking issues would make Helm fail part way; warnings would not.
+// @Tags Helm
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name"
+// @Param request body object true "Release name, namespace, chart, repository, version, values (YAML) and upgrade"
+// @Success 200 {object} PreflightReport
+// @Failure 400 {object} map[string]string "Bad request"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/helmcharts/preflight [post]
+func (h *HelmHandler) PreflightHelmChart(c *gin.Context) {
+	ctx, span := h.tracingHelper.StartAuthSpan(c.Request.Context(), "helm.preflight_chart")
+	defer span.End()
+
+	var request struct {
+		Name       string `json:"name" binding:"required"`
+		Namespace  string `json:"namespace"`
+		Chart      string `json:"chart" binding:"required"`
+		Repository string `json:"repository"`
+		Version    string `json:"version"`
+		Values     string `json:"values"`
+		Upgrade    bool   `json:"upgrade"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.tracingHelper.RecordError(span, err, "PreflightHelmChart failed")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if request.Namespace == "" {
+		request.Namespace = "default"
+	}
+	h.tracingHelper.AddResourceAttributes(span, request.Name, "helm_release", 1)
+
+	config, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.tracingHelper.RecordError(span, err, "PreflightHelmChart failed")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	cluster := c.Query("cluster")
+	actionConfig, err := h.helmFactory.GetHelmClientForConfig(config, cluster)
+	if err != nil {
+		h.tracingHelper.RecordError(span, err, "PreflightHelmChart failed")
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to get Helm client: %v", err)})
+		return
+	}
+	client, err := h.clientFactory.GetClientForConfig(config, cluster)
+	if err != nil {
+		h.tracingHelper.RecordError(span, err, "PreflightHelmChart failed")
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to get Kubernetes client: %v", err)})
+		return
+	}
+	dynamicClient, err := h.clientFactory.GetDynamicClientForConfig(config, cluster)
+	if err != nil {
+		h.tracingHelper.RecordError(span, err, "PreflightHelmChart failed")
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to get dynamic client: %v", err)})
+		return
+	}
+	mapper, _, err := h.clientFactory.GetRESTMapperForConfig(config, cluster)
+	if err != nil {
+		h.tracingHelper.RecordError(span, err, "PreflightHelmChart failed")
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to get REST mapper: %v", err)})
+		return
+	}
+
+	vals := map[string]interface{}{}
+	if request.Values != "" {
+		if err := yaml.Unmarshal([]byte(request.Values), &vals); err != nil {
+			h.tracingHelper.RecordError(span, err, "PreflightHelmChart failed")
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid YAML in custom values"})
+			return
+		}
+	}
+
+	_, chartSpan := h.tracingHelper.StartDataProcessingSpan(ctx, "helm.load_chart")
+	pathOptions := action.NewInstall(actionConfig)
+	pathOptions.ChartPathOptions.Version = request.Version
+	if request.Repository != "" {
+		pathOptions.ChartPathOptions.RepoURL = request.Repository
+	} else if request.Upgrade {
+		if repoURL, ok := h.resolveRepoURLFromChartName(request.Chart, c.Query("repository")); ok {
+			pathOptions.ChartPathOptions.RepoURL = repoURL
+		}
+	}
+	chartPath, err := pathOptions.LocateChart(request.Chart, cli.New())
+	if err != nil {
+		h.tracingHelper.RecordError(chartSpan, err, "Failed to locate chart")
+		chartSpan.End()
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to locate chart: %v", err)})
+		return
+	}
+	ch, err := loader.Load(chartPath)
+	if err != nil {
+		h.tracingHelper.RecordError(chartSpan, err, "Failed to load chart")
+		chartSpan.End()
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to load chart: %v", err)})
+		return
+	}
+	manifest, err := renderChart(ch, request.Name, request.Namespace, request.Upgrade, vals, client)
+	if err != nil {
+		h.tracingHelper.RecordError(chartSpan, err, "Failed to render chart")
+		chartSpan.End()
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to render chart: %v", err)})
+		return
+	}
+	h.tracingHelper.RecordSuccess(chartSpan, "Chart rendered")
+	chartSpan.End()
+
+	objects, templatedCRDs := parseManifest(manifest, true)
+	in := &preflightInput{
+		Release:   request.Name,
+		Namespace: request.Namespace,
+		Upgrade:   request.Upgrade,
+		Objects:   objects,
+		CRDs:      append(chartCRDs(ch), templatedCRDs...),
+	}
+	// Helm stores each release revision in a Secret labelled with the release name and status
+	revisions, err := client.CoreV1().Secrets(request.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "owner=helm,name=" + request.Name + ",status!=uninstalled",
+	})
+	if err == nil {
+		in.ReleaseExists = len(revisions.Items) > 0
+	} else {
+		in.ReleaseExists = request.Upgrade
+	}
+
+	checkCtx, checkSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "preflight", "helm_release", request.Namespace)
+	p := &preflighter{
+		client:  client,
+		dynamic: dynamicClient,
+		mapper:  mapper,
+	}
+	report := p.run(checkCtx, in)
+	report.Chart = ch.Metadata.Name
+	report.Version = ch.Metadata.Version
+	h.tracingHelper.RecordSuccess(checkSpan, fmt.Sprintf("%d blocking issues, %d warnings", len(report.Blocking), len(report.Warnings)))
+	checkSpan.End()
+
+	c.JSON(http.StatusOK, report)
+	h.tracingHelper.RecordSuccess(span, "PreflightHelmChart completed")
+}
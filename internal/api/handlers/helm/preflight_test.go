@@ -0,0 +1,146 @@
+package helm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const preflightManifest = `
+---
+# Source: web/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web-config
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: web-reader
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: web-widget
+---
+apiVersion: policy/v1beta1
+kind: PodSecurityPolicy
+metadata:
+  name: web-psp
+`
+
+const preflightCRDs = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+spec:
+  group: example.com
+  names:
+    kind: Widget
+    plural: widgets
+  scope: Namespaced
+`
+
+func preflightMapper() meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"}, meta.RESTScopeRoot)
+	return mapper
+}
+
+func TestParseManifest(t *testing.T) {
+	objects, crds := parseManifest(preflightManifest, true)
+	if len(objects) != 5 || objects[0].Kind != "ConfigMap" || objects[0].Name != "web-config" {
+		t.Errorf("objects = %+v", objects)
+	}
+	if len(crds) != 0 {
+		t.Errorf("crds = %+v, want none", crds)
+	}
+	objects, crds = parseManifest(preflightCRDs, false)
+	if len(objects) != 0 || len(crds) != 1 || crds[0].Plural != "widgets" || !crds[0].Namespaced {
+		t.Errorf("crds/ objects = %+v crds = %+v", objects, crds)
+	}
+}
+
+func TestPreflightRun(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	var reviews int
+	client.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		reviews++
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		attrs := review.Spec.ResourceAttributes
+		review.Status.Allowed = attrs.Resource != "clusterroles"
+		return true, review, nil
+	})
+
+	existing := &unstructured.Unstructured{}
+	existing.SetAPIVersion("v1")
+	existing.SetKind("ConfigMap")
+	existing.SetNamespace("shop")
+	existing.SetName("web-config")
+	existing.SetAnnotations(map[string]string{helmReleaseNameAnnotation: "legacy", helmReleaseNamespaceAnnotation: "shop"})
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		{Version: "v1", Resource: "configmaps"}:                                       "ConfigMapList",
+		{Group: "apps", Version: "v1", Resource: "deployments"}:                       "DeploymentList",
+		{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}: "ClusterRoleList",
+		crdResource.WithVersion("v1"):                                                 "CustomResourceDefinitionList",
+	}, existing)
+
+	objects, _ := parseManifest(preflightManifest, true)
+	_, crds := parseManifest(preflightCRDs, false)
+	p := &preflighter{client: client, dynamic: dynamicClient, mapper: preflightMapper()}
+	report := p.run(context.Background(), &preflightInput{Release: "web", Namespace: "shop", Objects: objects, CRDs: crds})
+
+	codes := map[string]string{}
+	for _, issue := range report.Blocking {
+		codes[issue.Code] += issue.Message + ";"
+	}
+	if report.Ready {
+		t.Error("report is ready despite blocking issues")
+	}
+	if !strings.Contains(codes[PreflightUnknownKind], "PodSecurityPolicy") || strings.Contains(codes[PreflightUnknownKind], "Widget") {
+		t.Errorf("unknown kinds = %q, want only PodSecurityPolicy", codes[PreflightUnknownKind])
+	}
+	if !strings.Contains(codes[PreflightOwnershipConflict], "release shop/legacy") {
+		t.Errorf("ownership conflicts = %q", codes[PreflightOwnershipConflict])
+	}
+	if codes[PreflightForbidden] != "cannot create clusterroles.rbac.authorization.k8s.io cluster-wide;" {
+		t.Errorf("forbidden = %q", codes[PreflightForbidden])
+	}
+	if len(report.Warnings) != 1 || report.Warnings[0].Code != PreflightNamespaceCreate {
+		t.Errorf("warnings = %+v, want the namespace to be created", report.Warnings)
+	}
+	// namespaces, CRD, secrets, configmaps, deployments, clusterroles and widgets, each reviewed once
+	if reviews != 7 || len(report.Permissions) != 7 {
+		t.Errorf("ran %d access reviews for %d checks, want 7", reviews, len(report.Permissions))
+	}
+
+	// An upgrade needs the release and namespace to exist and adopts its own objects
+	if _, err := client.CoreV1().Namespaces().Create(context.Background(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}}, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	upgrade := p.run(context.Background(), &preflightInput{Release: "web", Namespace: "shop", Upgrade: true, Objects: objects[:2]})
+	if len(upgrade.Blocking) != 1 || upgrade.Blocking[0].Code != PreflightReleaseMissing {
+		t.Errorf("upgrade blocking = %+v, want only the missing release", upgrade.Blocking)
+	}
+}
@@ -0,0 +1,271 @@
+package helm
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/release"
+	helmstorage "helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/client-go/kubernetes"
+)
+
+// How a chart default changed between the installed and the new chart version
+const (
+	ValuesDefaultChanged = "changed"
+	ValuesDefaultAdded   = "added"
+	ValuesDefaultRemoved = "removed"
+)
+
+// What a changed default means for the release on upgrade
+const (
+	ValuesImpactApplies        = "applies"          // the release does not set the key, so the new default takes effect
+	ValuesImpactOverridden     = "overridden"       // the release sets its own value, so the upgrade keeps it
+	ValuesImpactPinsOldDefault = "pins-old-default" // the release sets the installed default, keeping the old behavior
+	ValuesImpactObsolete       = "obsolete"         // the release sets a key the new chart no longer defaults
+)
+
+// ValuesDiffEntry is a chart default that differs between the installed and the new chart
+// version, with the release's own value for it
+type ValuesDiffEntry struct {
+	Path             string      `json:"path"`
+	Change           string      `json:"change"` // changed, added or removed
+	InstalledDefault interface{} `json:"installedDefault,omitempty"`
+	NewDefault       interface{} `json:"newDefault,omitempty"`
+	UserSet          bool        `json:"userSet"`
+	UserValue        interface{} `json:"userValue,omitempty"`
+	Impact           string      `json:"impact"` // applies, overridden, pins-old-default or obsolete
+}
+
+// ValuesDiffReport three-way compares a release's values with the defaults of its installed chart
+// and of a new chart version
+type ValuesDiffReport struct {
+	Release          string            `json:"release"`
+	Namespace        string            `json:"namespace"`
+	Chart            string            `json:"chart"`
+	InstalledVersion string            `json:"installedVersion"`
+	NewVersion       string            `json:"newVersion"`
+	Applies          int               `json:"applies"` // changed defaults the upgrade silently applies
+	Entries          []ValuesDiffEntry `json:"entries"`
+}
+
+// valuesPath renders a path of values keys, quoting keys that contain dots
+func valuesPath(parent, key string) string {
+	if strings.Contains(key, ".") {
+		return fmt.Sprintf("%s[%q]", parent, key)
+	}
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+// flattenValues maps the path of every leaf of a values tree to its value, recording the keys
+// that lead to it. Lists and empty maps are leaves.
+func flattenValues(values map[string]interface{}, parent string, keys []string, out map[string]interface{}, leafKeys map[string][]string) {
+	for key, value := range values {
+		path := valuesPath(parent, key)
+		childKeys := append(append([]string{}, keys...), key)
+		if child, ok := value.(map[string]interface{}); ok && len(child) > 0 {
+			flattenValues(child, path, childKeys, out, leafKeys)
+			continue
+		}
+		out[path] = value
+		leafKeys[path] = childKeys
+	}
+}
+
+// userValue returns what the release's values set at a path. A value set on an ancestor that is
+// not a map replaces the whole subtree, so it counts as setting the path.
+func userValue(values map[string]interface{}, keys []string) (interface{}, bool) {
+	current := values
+	for i, key := range keys {
+		value, ok := current[key]
+		if !ok {
+			return nil, false
+		}
+		if i == len(keys)-1 {
+			return value, true
+		}
+		child, isMap := value.(map[string]interface{})
+		if !isMap {
+			return value, true
+		}
+		current = child
+	}
+	return nil, false
+}
+
+// valuesEqual compares two values by their JSON form, so numbers decoded differently compare equal
+func valuesEqual(a, b interface{}) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
+
+// diffChartValues lists the defaults that differ between two chart versions and what each
+// difference means for a release with the given values
+func diffChartValues(user, installedDefaults, newDefaults map[string]interface{}) []ValuesDiffEntry {
+	installed, next := map[string]interface{}{}, map[string]interface{}{}
+	keys := map[string][]string{}
+	flattenValues(installedDefaults, "", nil, installed, keys)
+	flattenValues(newDefaults, "", nil, next, keys)
+
+	entries := []ValuesDiffEntry{}
+	for path := range keys {
+		oldValue, inOld := installed[path]
+		newValue, inNew := next[path]
+		entry := ValuesDiffEntry{Path: path, InstalledDefault: oldValue, NewDefault: newValue}
+		switch {
+		case inOld && inNew && valuesEqual(oldValue, newValue):
+			continue
+		case inOld && inNew:
+			entry.Change = ValuesDefaultChanged
+		case inNew:
+			entry.Change = ValuesDefaultAdded
+		default:
+			entry.Change = ValuesDefaultRemoved
+		}
+		entry.UserValue, entry.UserSet = userValue(user, keys[path])
+
+		switch {
+		case !entry.UserSet && entry.Change == ValuesDefaultRemoved:
+			// Neither side sets the key any more; nothing the release relies on changes
+			continue
+		case !entry.UserSet:
+			entry.Impact = ValuesImpactApplies
+		case entry.Change == ValuesDefaultRemoved:
+			entry.Impact = ValuesImpactObsolete
+		case inOld && valuesEqual(entry.UserValue, oldValue):
+			entry.Impact = ValuesImpactPinsOldDefault
+		default:
+			entry.Impact = ValuesImpactOverridden
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	return entries
+}
+
+// latestRelease reads a release from Helm's secret storage in its namespace, preferring the
+// deployed revision
+func latestRelease(client kubernetes.Interface, namespace, name string) (*release.Release, error) {
+	releases := helmstorage.Init(driver.NewSecrets(client.CoreV1().Secrets(namespace)))
+	rel, err := releases.Deployed(name)
+	if err != nil {
+		// A release whose latest upgrade failed has no deployed revision; fall back to the latest one
+		rel, err = releases.Last(name)
+	}
+	return rel, err
+}
+
+// GetHelmReleaseValuesDiff compares a release's values with the defaults of a new chart version
+// @Summary Diff Helm release values against a new chart version
+// @Description Three-way compares the user values of a release, the default values of its installed chart and the default values of a new chart version. Every default that changed, was added or was removed upstream is listed with the release's own value for it and the impact on upgrade: applies (the release does not set the key, so the new default silently takes effect), overridden (the release sets its own value), pins-old-default (the release sets the installed default, keeping the old behavior, often from a copied values file) or obsolete (the release sets a key the new chart no longer defaults). Keys containing dots are quoted in paths, as in podAnnotations["prometheus.io/scrape"].
+// @Tags Helm
+// @Produce json
+// @Param name path string true "Release name"
+// @Param namespace query string false "Release namespace" default(default)
+// @Param version query string true "New chart version"
+// @Param chart query string false "Chart name (defaults to the installed chart)"
+// @Param repository query string false "Chart repository URL (resolved on Artifact Hub by chart name when empty)"
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name"
+// @Success 200 {object} ValuesDiffReport
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Release not found"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/helmreleases/{name}/values-diff [get]
+func (h *HelmHandler) GetHelmReleaseValuesDiff(c *gin.Context) {
+	ctx, span := h.tracingHelper.StartAuthSpan(c.Request.Context(), "helm.release_values_diff")
+	defer span.End()
+
+	name, version := c.Param("name"), c.Query("version")
+	if version == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "version parameter is required"})
+		return
+	}
+	namespace := c.DefaultQuery("namespace", "default")
+	h.tracingHelper.AddResourceAttributes(span, name, "helm_release", 1)
+
+	config, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.tracingHelper.RecordError(span, err, "GetHelmReleaseValuesDiff failed")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	cluster := c.Query("cluster")
+	actionConfig, err := h.helmFactory.GetHelmClientForConfig(config, cluster)
+	if err != nil {
+		h.tracingHelper.RecordError(span, err, "GetHelmReleaseValuesDiff failed")
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to get Helm client: %v", err)})
+		return
+	}
+	client, err := h.clientFactory.GetClientForConfig(config, cluster)
+	if err != nil {
+		h.tracingHelper.RecordError(span, err, "GetHelmReleaseValuesDiff failed")
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to get Kubernetes client: %v", err)})
+		return
+	}
+
+	rel, err := latestRelease(client, namespace, name)
+	if err != nil {
+		h.tracingHelper.RecordError(span, err, "GetHelmReleaseValuesDiff failed")
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("release %s not found: %v", name, err)})
+		return
+	}
+	if rel.Chart == nil || rel.Chart.Metadata == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("release %s does not record its chart", name)})
+		return
+	}
+
+	_, chartSpan := h.tracingHelper.StartDataProcessingSpan(ctx, "helm.load_chart")
+	chartName := c.DefaultQuery("chart", rel.Chart.Metadata.Name)
+	pathOptions := action.NewInstall(actionConfig)
+	pathOptions.ChartPathOptions.Version = version
+	if repository := c.Query("repository"); repository != "" {
+		pathOptions.ChartPathOptions.RepoURL = repository
+	} else if repoURL, ok := h.resolveRepoURLFromChartName(chartName, ""); ok {
+		pathOptions.ChartPathOptions.RepoURL = repoURL
+	}
+	chartPath, err := pathOptions.LocateChart(chartName, cli.New())
+	if err != nil {
+		h.tracingHelper.RecordError(chartSpan, err, "Failed to locate chart")
+		chartSpan.End()
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to locate chart: %v", err)})
+		return
+	}
+	ch, err := loader.Load(chartPath)
+	if err != nil {
+		h.tracingHelper.RecordError(chartSpan, err, "Failed to load chart")
+		chartSpan.End()
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to load chart: %v", err)})
+		return
+	}
+	h.tracingHelper.RecordSuccess(chartSpan, "Chart loaded")
+	chartSpan.End()
+
+	report := ValuesDiffReport{
+		Release:          rel.Name,
+		Namespace:        rel.Namespace,
+		Chart:            ch.Metadata.Name,
+		InstalledVersion: rel.Chart.Metadata.Version,
+		NewVersion:       ch.Metadata.Version,
+		Entries:          diffChartValues(rel.Config, rel.Chart.Values, ch.Values),
+	}
+	for _, entry := range report.Entries {
+		if entry.Impact == ValuesImpactApplies {
+			report.Applies++
+		}
+	}
+	c.JSON(http.StatusOK, report)
+	h.tracingHelper.RecordSuccess(span, "GetHelmReleaseValuesDiff completed")
+}
@@ -0,0 +1,116 @@
+package helm
+
+import (
+	"fmt"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/yaml"
+)
+
+func mustValues(t *testing.T, doc string) map[string]interface{} {
+	t.Helper()
+	values := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(doc), &values); err != nil {
+		t.Fatal(err)
+	}
+	return values
+}
+
+func TestDiffChartValues(t *testing.T) {
+	installed := mustValues(t, `
+replicaCount: 1
+image:
+  tag: "1.0"
+  pullPolicy: IfNotPresent
+service:
+  type: ClusterIP
+  port: 80
+podAnnotations:
+  prometheus.io/scrape: "true"
+legacy:
+  enabled: true
+ingress:
+  enabled: false
+`)
+	next := mustValues(t, `
+replicaCount: 2
+image:
+  tag: "2.0"
+  pullPolicy: Always
+service:
+  type: ClusterIP
+  port: 8080
+podAnnotations:
+  prometheus.io/scrape: "false"
+ingress:
+  enabled: false
+metrics:
+  enabled: true
+`)
+	user := mustValues(t, `
+replicaCount: 3
+image:
+  tag: "1.0"
+service: {port: 8443}
+legacy:
+  enabled: false
+podAnnotations: []
+`)
+
+	entries := diffChartValues(user, installed, next)
+	want := map[string]struct {
+		change, impact string
+	}{
+		"replicaCount":                           {ValuesDefaultChanged, ValuesImpactOverridden},
+		"image.tag":                              {ValuesDefaultChanged, ValuesImpactPinsOldDefault},
+		"image.pullPolicy":                       {ValuesDefaultChanged, ValuesImpactApplies},
+		"service.port":                           {ValuesDefaultChanged, ValuesImpactOverridden},
+		`podAnnotations["prometheus.io/scrape"]`: {ValuesDefaultChanged, ValuesImpactOverridden},
+		"legacy.enabled":                         {ValuesDefaultRemoved, ValuesImpactObsolete},
+		"metrics.enabled":                        {ValuesDefaultAdded, ValuesImpactApplies},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("entries = %+v, want %d", entries, len(want))
+	}
+	for i, entry := range entries {
+		expected, ok := want[entry.Path]
+		if !ok || entry.Change != expected.change || entry.Impact != expected.impact {
+			t.Errorf("entry %s = %s/%s, want %s/%s", entry.Path, entry.Change, entry.Impact, expected.change, expected.impact)
+		}
+		if i > 0 && entries[i-1].Path > entry.Path {
+			t.Errorf("entries are not sorted by path: %s before %s", entries[i-1].Path, entry.Path)
+		}
+	}
+	if entries[0].Path != "image.pullPolicy" || entries[0].InstalledDefault != "IfNotPresent" || entries[0].NewDefault != "Always" || entries[0].UserSet {
+		t.Errorf("first entry = %+v", entries[0])
+	}
+}
+
+func TestLatestRelease(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	secrets := driver.NewSecrets(client.CoreV1().Secrets("shop"))
+	for _, rel := range []*release.Release{
+		{Name: "web", Namespace: "shop", Version: 1, Info: &release.Info{Status: release.StatusSuperseded}, Chart: &chart.Chart{Metadata: &chart.Metadata{Name: "web", Version: "1.0.0"}}},
+		{Name: "web", Namespace: "shop", Version: 2, Info: &release.Info{Status: release.StatusDeployed}, Chart: &chart.Chart{Metadata: &chart.Metadata{Name: "web", Version: "1.1.0"}}},
+		{Name: "web", Namespace: "shop", Version: 3, Info: &release.Info{Status: release.StatusFailed}, Chart: &chart.Chart{Metadata: &chart.Metadata{Name: "web", Version: "2.0.0"}}},
+	} {
+		if err := secrets.Create(fmt.Sprintf("sh.helm.release.v1.%s.v%d", rel.Name, rel.Version), rel); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rel, err := latestRelease(client, "shop", "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rel.Version != 2 || rel.Chart.Metadata.Version != "1.1.0" {
+		t.Errorf("release = revision %d of chart %s, want the deployed revision 2", rel.Version, rel.Chart.Metadata.Version)
+	}
+	if _, err := latestRelease(client, "other", "web"); err == nil {
+		t.Error("latestRelease() found a release in another namespace")
+	}
+}
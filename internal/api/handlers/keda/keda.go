@@ -0,0 +1,827 @@
+package keda
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/internal/tracing"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// pausedAnnotation stops KEDA from scaling the target, leaving it at its current replicas
+	pausedAnnotation = "autoscaling.keda.sh/paused"
+	// pausedReplicasAnnotation scales the target to a fixed replica count and stops scaling
+	pausedReplicasAnnotation = "autoscaling.keda.sh/paused-replicas"
+
+	// KEDA defaults applied when the spec leaves a field unset
+	defaultPollingInterval = 30
+	defaultCooldownPeriod  = 300
+	defaultMaxReplicas     = 100
+)
+
+var (
+	scaledObjectGVR = schema.GroupVersionResource{Group: "keda.sh", Version: "v1alpha1", Resource: "scaledobjects"}
+	scaledJobGVR    = schema.GroupVersionResource{Group: "keda.sh", Version: "v1alpha1", Resource: "scaledjobs"}
+)
+
+// sensitiveMetadataKeys mark trigger metadata values that are redacted in responses
+var sensitiveMetadataKeys = []string{"password", "secret", "token", "connectionstring", "apikey", "accesskey"}
+
+// TriggerInfo describes one KEDA scaler trigger
+type TriggerInfo struct {
+	Index             int               `json:"index"`
+	Type              string            `json:"type"`
+	Name              string            `json:"name,omitempty"`
+	MetricType        string            `json:"metricType,omitempty"`
+	Metadata          map[string]string `json:"metadata"`
+	AuthenticationRef string            `json:"authenticationRef,omitempty"`
+}
+
+// MetricDecision pairs a metric the HPA scales on with its current and target values
+type MetricDecision struct {
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	TriggerIndex *int   `json:"triggerIndex,omitempty"`
+	Current      string `json:"current,omitempty"`
+	Target       string `json:"target,omitempty"`
+}
+
+// HPACondition is a condition the HPA reports about its last scaling decision
+type HPACondition struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// ScalingDecision is the current scaling state of the HPA KEDA manages for a ScaledObject
+type ScalingDecision struct {
+	HPAName         string           `json:"hpaName"`
+	Found           bool             `json:"found"`
+	CurrentReplicas int32            `json:"currentReplicas"`
+	DesiredReplicas int32            `json:"desiredReplicas"`
+	MinReplicas     int32            `json:"minReplicas"`
+	MaxReplicas     int32            `json:"maxReplicas"`
+	LastScaleTime   string           `json:"lastScaleTime,omitempty"`
+	Metrics         []MetricDecision `json:"metrics"`
+	Conditions      []HPACondition   `json:"conditions"`
+}
+
+// ScaledObjectInfo summarizes a KEDA ScaledObject
+type ScaledObjectInfo struct {
+	Name            string           `json:"name"`
+	Namespace       string           `json:"namespace"`
+	TargetKind      string           `json:"targetKind"`
+	TargetName      string           `json:"targetName"`
+	MinReplicas     int64            `json:"minReplicas"`
+	MaxReplicas     int64            `json:"maxReplicas"`
+	IdleReplicas    *int64           `json:"idleReplicas,omitempty"`
+	PollingInterval int64            `json:"pollingInterval"`
+	CooldownPeriod  int64            `json:"cooldownPeriod"`
+	Triggers        []TriggerInfo    `json:"triggers"`
+	Ready           bool             `json:"ready"`
+	Active          bool             `json:"active"`
+	Fallback        bool             `json:"fallback"`
+	Paused          bool             `json:"paused"`
+	PausedReplicas  *int64           `json:"pausedReplicas,omitempty"`
+	Reason          string           `json:"reason,omitempty"`
+	Message         string           `json:"message,omitempty"`
+	LastActiveTime  string           `json:"lastActiveTime,omitempty"`
+	HPAName         string           `json:"hpaName"`
+	Scaling         *ScalingDecision `json:"scaling,omitempty"`
+}
+
+// ScaledJobInfo summarizes a KEDA ScaledJob
+type ScaledJobInfo struct {
+	Name            string        `json:"name"`
+	Namespace       string        `json:"namespace"`
+	MaxReplicas     int64         `json:"maxReplicas"`
+	PollingInterval int64         `json:"pollingInterval"`
+	ScalingStrategy string        `json:"scalingStrategy"`
+	Triggers        []TriggerInfo `json:"triggers"`
+	Ready           bool          `json:"ready"`
+	Active          bool          `json:"active"`
+	Paused          bool          `json:"paused"`
+	Reason          string        `json:"reason,omitempty"`
+	Message         string        `json:"message,omitempty"`
+	LastActiveTime  string        `json:"lastActiveTime,omitempty"`
+}
+
+// ScaledObjectsResponse lists ScaledObjects and whether KEDA is installed
+type ScaledObjectsResponse struct {
+	Installed     bool               `json:"installed"`
+	ScaledObjects []ScaledObjectInfo `json:"scaledObjects"`
+}
+
+// ScaledJobsResponse lists ScaledJobs and whether KEDA is installed
+type ScaledJobsResponse struct {
+	Installed  bool            `json:"installed"`
+	ScaledJobs []ScaledJobInfo `json:"scaledJobs"`
+}
+
+// PauseRequest optionally pins a paused ScaledObject to a replica count
+type PauseRequest struct {
+	Replicas *int32 `json:"replicas,omitempty"`
+}
+
+// KedaHandler serves KEDA ScaledObjects and ScaledJobs
+type KedaHandler struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	logger        *logger.Logger
+	tracingHelper *tracing.TracingHelper
+}
+
+// NewKedaHandler creates a new KEDA handler
+func NewKedaHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger) *KedaHandler {
+	return &KedaHandler{
+		store:         store,
+		clientFactory: clientFactory,
+		logger:        log,
+		tracingHelper: tracing.GetTracingHelper(),
+	}
+}
+
+// getClients gets the dynamic and typed Kubernetes clients for the current request
+func (h *KedaHandler) getClients(c *gin.Context) (dynamic.Interface, kubernetes.Interface, error) {
+	configID := c.Query("config")
+	cluster := c.Query("cluster")
+
+	if configID == "" {
+		return nil, nil, fmt.Errorf("config parameter is required")
+	}
+
+	config, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("config not found: %w", err)
+	}
+
+	dynamicClient, err := h.clientFactory.GetDynamicClientForConfig(config, cluster)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get dynamic client: %w", err)
+	}
+	client, err := h.clientFactory.GetClientForConfig(config, cluster)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get client: %w", err)
+	}
+
+	return dynamicClient, client, nil
+}
+
+// condition returns the status, reason and message of a status condition
+func condition(obj *unstructured.Unstructured, conditionType string) (string, string, string) {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, entry := range conditions {
+		m, ok := entry.(map[string]interface{})
+		if !ok || m["type"] != conditionType {
+			continue
+		}
+		status, _ := m["status"].(string)
+		reason, _ := m["reason"].(string)
+		message, _ := m["message"].(string)
+		return status, reason, message
+	}
+	return "", "", ""
+}
+
+// int64Field returns an integer spec field, or the fallback when unset
+func int64Field(obj *unstructured.Unstructured, fallback int64, fields ...string) int64 {
+	if v, found, _ := unstructured.NestedInt64(obj.Object, fields...); found {
+		return v
+	}
+	return fallback
+}
+
+// redactMetadata returns trigger metadata with credential-like values hidden
+func redactMetadata(metadata map[string]interface{}) map[string]string {
+	result := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		str := fmt.Sprint(value)
+		lower := strings.ToLower(key)
+		// *FromEnv keys name an environment variable, not the value itself
+		if !strings.HasSuffix(lower, "fromenv") {
+			for _, sensitive := range sensitiveMetadataKeys {
+				if strings.Contains(lower, sensitive) {
+					str = "[redacted]"
+					break
+				}
+			}
+		}
+		result[key] = str
+	}
+	return result
+}
+
+// transformTriggers lists the scaler triggers of a ScaledObject or ScaledJob
+func transformTriggers(obj *unstructured.Unstructured) []TriggerInfo {
+	entries, _, _ := unstructured.NestedSlice(obj.Object, "spec", "triggers")
+	triggers := make([]TriggerInfo, 0, len(entries))
+	for i, entry := range entries {
+		m, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		trigger := TriggerInfo{Index: i}
+		trigger.Type, _, _ = unstructured.NestedString(m, "type")
+		trigger.Name, _, _ = unstructured.NestedString(m, "name")
+		trigger.MetricType, _, _ = unstructured.NestedString(m, "metricType")
+		metadata, _, _ := unstructured.NestedMap(m, "metadata")
+		trigger.Metadata = redactMetadata(metadata)
+		if ref, _, _ := unstructured.NestedString(m, "authenticationRef", "name"); ref != "" {
+			kind, _, _ := unstructured.NestedString(m, "authenticationRef", "kind")
+			if kind == "" {
+				kind = "TriggerAuthentication"
+			}
+			trigger.AuthenticationRef = kind + "/" + ref
+		}
+		triggers = append(triggers, trigger)
+	}
+	return triggers
+}
+
+// pausedState reports whether a KEDA object is paused and the replica count it is pinned to
+func pausedState(obj *unstructured.Unstructured) (bool, *int64) {
+	annotations := obj.GetAnnotations()
+	if value, ok := annotations[pausedReplicasAnnotation]; ok {
+		if replicas, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return true, &replicas
+		}
+	}
+	if paused, err := strconv.ParseBool(annotations[pausedAnnotation]); err == nil && paused {
+		return true, nil
+	}
+	status, _, _ := condition(obj, "Paused")
+	return status == "True", nil
+}
+
+// hpaName returns the name of the HPA KEDA creates for a ScaledObject
+func hpaName(obj *unstructured.Unstructured) string {
+	if name, _, _ := unstructured.NestedString(obj.Object, "status", "hpaName"); name != "" {
+		return name
+	}
+	if name, _, _ := unstructured.NestedString(obj.Object, "spec", "advanced", "horizontalPodAutoscalerConfig", "name"); name != "" {
+		return name
+	}
+	return "keda-hpa-" + obj.GetName()
+}
+
+// transformScaledObject builds a ScaledObjectInfo without its scaling decision
+func transformScaledObject(obj *unstructured.Unstructured) ScaledObjectInfo {
+	info := ScaledObjectInfo{
+		Name:            obj.GetName(),
+		Namespace:       obj.GetNamespace(),
+		MinReplicas:     int64Field(obj, 0, "spec", "minReplicaCount"),
+		MaxReplicas:     int64Field(obj, defaultMaxReplicas, "spec", "maxReplicaCount"),
+		PollingInterval: int64Field(obj, defaultPollingInterval, "spec", "pollingInterval"),
+		CooldownPeriod:  int64Field(obj, defaultCooldownPeriod, "spec", "cooldownPeriod"),
+		Triggers:        transformTriggers(obj),
+		HPAName:         hpaName(obj),
+	}
+	info.TargetKind, _, _ = unstructured.NestedString(obj.Object, "spec", "scaleTargetRef", "kind")
+	if info.TargetKind == "" {
+		info.TargetKind = "Deployment"
+	}
+	info.TargetName, _, _ = unstructured.NestedString(obj.Object, "spec", "scaleTargetRef", "name")
+	if idle, found, _ := unstructured.NestedInt64(obj.Object, "spec", "idleReplicaCount"); found {
+		info.IdleReplicas = &idle
+	}
+
+	ready, reason, message := condition(obj, "Ready")
+	info.Ready = ready == "True"
+	info.Reason = reason
+	info.Message = message
+	active, _, _ := condition(obj, "Active")
+	info.Active = active == "True"
+	fallback, _, _ := condition(obj, "Fallback")
+	info.Fallback = fallback == "True"
+	info.Paused, info.PausedReplicas = pausedState(obj)
+	info.LastActiveTime, _, _ = unstructured.NestedString(obj.Object, "status", "lastActiveTime")
+	return info
+}
+
+// transformScaledJob builds a ScaledJobInfo
+func transformScaledJob(obj *unstructured.Unstructured) ScaledJobInfo {
+	info := ScaledJobInfo{
+		Name:            obj.GetName(),
+		Namespace:       obj.GetNamespace(),
+		MaxReplicas:     int64Field(obj, defaultMaxReplicas, "spec", "maxReplicaCount"),
+		PollingInterval: int64Field(obj, defaultPollingInterval, "spec", "pollingInterval"),
+		Triggers:        transformTriggers(obj),
+	}
+	info.ScalingStrategy, _, _ = unstructured.NestedString(obj.Object, "spec", "scalingStrategy", "strategy")
+	if info.ScalingStrategy == "" {
+		info.ScalingStrategy = "default"
+	}
+	ready, reason, message := condition(obj, "Ready")
+	info.Ready = ready == "True"
+	info.Reason = reason
+	info.Message = message
+	active, _, _ := condition(obj, "Active")
+	info.Active = active == "True"
+	info.Paused, _ = pausedState(obj)
+	info.LastActiveTime, _, _ = unstructured.NestedString(obj.Object, "status", "lastActiveTime")
+	return info
+}
+
+// triggerIndex maps a KEDA external metric name ("s<index>-<scaler>") to its trigger
+func triggerIndex(metricName string) *int {
+	if !strings.HasPrefix(metricName, "s") {
+		return nil
+	}
+	prefix, _, found := strings.Cut(metricName[1:], "-")
+	if !found {
+		return nil
+	}
+	index, err := strconv.Atoi(prefix)
+	if err != nil {
+		return nil
+	}
+	return &index
+}
+
+// metricTargetValue formats the value, average value or utilization of a metric target
+func metricTargetValue(target autoscalingv2.MetricTarget) string {
+	switch {
+	case target.AverageUtilization != nil:
+		return fmt.Sprintf("%d%%", *target.AverageUtilization)
+	case target.AverageValue != nil:
+		return target.AverageValue.String()
+	case target.Value != nil:
+		return target.Value.String()
+	}
+	return ""
+}
+
+// metricCurrentValue formats the current value of a metric
+func metricCurrentValue(current autoscalingv2.MetricValueStatus) string {
+	switch {
+	case current.AverageUtilization != nil:
+		return fmt.Sprintf("%d%%", *current.AverageUtilization)
+	case current.AverageValue != nil:
+		return current.AverageValue.String()
+	case current.Value != nil:
+		return current.Value.String()
+	}
+	return ""
+}
+
+// scalingDecision reads the replica counts, metric values and conditions of a KEDA-managed HPA
+func scalingDecision(hpa *autoscalingv2.HorizontalPodAutoscaler) *ScalingDecision {
+	decision := &ScalingDecision{
+		HPAName:         hpa.Name,
+		Found:           true,
+		CurrentReplicas: hpa.Status.CurrentReplicas,
+		DesiredReplicas: hpa.Status.DesiredReplicas,
+		MaxReplicas:     hpa.Spec.MaxReplicas,
+		Metrics:         []MetricDecision{},
+		Conditions:      []HPACondition{},
+	}
+	if hpa.Spec.MinReplicas != nil {
+		decision.MinReplicas = *hpa.Spec.MinReplicas
+	}
+	if hpa.Status.LastScaleTime != nil {
+		decision.LastScaleTime = hpa.Status.LastScaleTime.UTC().Format(time.RFC3339)
+	}
+
+	current := map[string]string{}
+	for _, status := range hpa.Status.CurrentMetrics {
+		var name, value string
+		switch status.Type {
+		case autoscalingv2.ResourceMetricSourceType:
+			if status.Resource != nil {
+				name, value = string(status.Resource.Name), metricCurrentValue(status.Resource.Current)
+			}
+		case autoscalingv2.ContainerResourceMetricSourceType:
+			if status.ContainerResource != nil {
+				name, value = string(status.ContainerResource.Name), metricCurrentValue(status.ContainerResource.Current)
+			}
+		case autoscalingv2.ExternalMetricSourceType:
+			if status.External != nil {
+				name, value = status.External.Metric.Name, metricCurrentValue(status.External.Current)
+			}
+		case autoscalingv2.ObjectMetricSourceType:
+			if status.Object != nil {
+				name, value = status.Object.Metric.Name, metricCurrentValue(status.Object.Current)
+			}
+		case autoscalingv2.PodsMetricSourceType:
+			if status.Pods != nil {
+				name, value = status.Pods.Metric.Name, metricCurrentValue(status.Pods.Current)
+			}
+		}
+		current[string(status.Type)+"/"+name] = value
+	}
+
+	for _, spec := range hpa.Spec.Metrics {
+		metric := MetricDecision{Type: string(spec.Type)}
+		switch spec.Type {
+		case autoscalingv2.ResourceMetricSourceType:
+			if spec.Resource != nil {
+				metric.Name, metric.Target = string(spec.Resource.Name), metricTargetValue(spec.Resource.Target)
+			}
+		case autoscalingv2.ContainerResourceMetricSourceType:
+			if spec.ContainerResource != nil {
+				metric.Name, metric.Target = string(spec.ContainerResource.Name), metricTargetValue(spec.ContainerResource.Target)
+			}
+		case autoscalingv2.ExternalMetricSourceType:
+			if spec.External != nil {
+				metric.Name, metric.Target = spec.External.Metric.Name, metricTargetValue(spec.External.Target)
+				metric.TriggerIndex = triggerIndex(metric.Name)
+			}
+		case autoscalingv2.ObjectMetricSourceType:
+			if spec.Object != nil {
+				metric.Name, metric.Target = spec.Object.Metric.Name, metricTargetValue(spec.Object.Target)
+			}
+		case autoscalingv2.PodsMetricSourceType:
+			if spec.Pods != nil {
+				metric.Name, metric.Target = spec.Pods.Metric.Name, metricTargetValue(spec.Pods.Target)
+			}
+		}
+		metric.Current = current[metric.Type+"/"+metric.Name]
+		decision.Metrics = append(decision.Metrics, metric)
+	}
+
+	for _, cond := range hpa.Status.Conditions {
+		decision.Conditions = append(decision.Conditions, HPACondition{
+			Type:    string(cond.Type),
+			Status:  string(cond.Status),
+			Reason:  cond.Reason,
+			Message: cond.Message,
+		})
+	}
+	return decision
+}
+
+// pausePatch builds the merge patch that pauses a KEDA object, optionally at a fixed replica count
+func pausePatch(replicas *int32) ([]byte, error) {
+	annotations := map[string]interface{}{pausedAnnotation: "true", pausedReplicasAnnotation: nil}
+	if replicas != nil {
+		annotations = map[string]interface{}{pausedAnnotation: nil, pausedReplicasAnnotation: strconv.Itoa(int(*replicas))}
+	}
+	return json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"annotations": annotations}})
+}
+
+// resumePatch builds the merge patch that removes the pause annotations
+func resumePatch() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"annotations": map[string]interface{}{
+		pausedAnnotation:         nil,
+		pausedReplicasAnnotation: nil,
+	}}})
+}
+
+// listHPAs indexes the HPAs of a namespace by name; errors leave scaling decisions unset
+func (h *KedaHandler) listHPAs(ctx context.Context, client kubernetes.Interface, namespace string) map[string]*autoscalingv2.HorizontalPodAutoscaler {
+	hpas := map[string]*autoscalingv2.HorizontalPodAutoscaler{}
+	list, err := client.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		h.logger.WithError(err).WithField("namespace", namespace).Warn("Failed to list HPAs for KEDA scaling decisions")
+		return hpas
+	}
+	for i := range list.Items {
+		hpa := &list.Items[i]
+		hpas[hpa.Namespace+"/"+hpa.Name] = hpa
+	}
+	return hpas
+}
+
+// GetScaledObjects lists KEDA ScaledObjects with their triggers and current scaling decisions
+// @Summary List KEDA ScaledObjects
+// @Description Lists KEDA ScaledObjects with trigger configuration, pause state and the replica counts and metric values of the HPA KEDA manages for each. Reports installed=false when KEDA is not installed.
+// @Tags KEDA
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param namespace query string false "Namespace to filter (empty for all namespaces)"
+// @Success 200 {object} ScaledObjectsResponse "ScaledObjects"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/keda/scaledobjects [get]
+func (h *KedaHandler) GetScaledObjects(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	dynamicClient, client, err := h.getClients(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for scaled objects")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
+
+	namespace := c.Query("namespace")
+
+	_, listSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "scaledobjects", namespace)
+	defer listSpan.End()
+
+	list, err := dynamicClient.Resource(scaledObjectGVR).Namespace(namespace).List(c.Request.Context(), metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			h.tracingHelper.RecordSuccess(listSpan, "KEDA not installed")
+			c.JSON(http.StatusOK, ScaledObjectsResponse{ScaledObjects: []ScaledObjectInfo{}})
+			return
+		}
+		h.logger.WithError(err).Error("Failed to list scaled objects")
+		h.tracingHelper.RecordError(listSpan, err, "Failed to list scaled objects")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := ScaledObjectsResponse{Installed: true, ScaledObjects: make([]ScaledObjectInfo, 0, len(list.Items))}
+	if len(list.Items) > 0 {
+		hpas := h.listHPAs(c.Request.Context(), client, namespace)
+		for i := range list.Items {
+			info := transformScaledObject(&list.Items[i])
+			if hpa, ok := hpas[info.Namespace+"/"+info.HPAName]; ok {
+				info.Scaling = scalingDecision(hpa)
+			}
+			response.ScaledObjects = append(response.ScaledObjects, info)
+		}
+	}
+	sort.Slice(response.ScaledObjects, func(i, j int) bool {
+		a, b := response.ScaledObjects[i], response.ScaledObjects[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	h.tracingHelper.AddResourceAttributes(listSpan, "", "scaledobjects", len(list.Items))
+	h.tracingHelper.RecordSuccess(listSpan, fmt.Sprintf("Listed %d scaled objects", len(list.Items)))
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetScaledObject returns one ScaledObject with the scaling decision of its HPA
+// @Summary Get a KEDA ScaledObject
+// @Description Returns a KEDA ScaledObject with its trigger configuration, pause state and the current scaling decision of the HPA KEDA manages for it
+// @Tags KEDA
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param namespace path string true "ScaledObject namespace"
+// @Param name path string true "ScaledObject name"
+// @Success 200 {object} ScaledObjectInfo "ScaledObject"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 404 {object} map[string]string "ScaledObject not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/keda/scaledobjects/{namespace}/{name} [get]
+func (h *KedaHandler) GetScaledObject(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	dynamicClient, client, err := h.getClients(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for scaled object")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
+
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+
+	_, getSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "get", "scaledobjects", namespace)
+	defer getSpan.End()
+
+	obj, err := dynamicClient.Resource(scaledObjectGVR).Namespace(namespace).Get(c.Request.Context(), name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("scaled object %s/%s not found", namespace, name)})
+			return
+		}
+		h.logger.WithError(err).WithField("scaledobject", name).Error("Failed to get scaled object")
+		h.tracingHelper.RecordError(getSpan, err, "Failed to get scaled object")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	info := transformScaledObject(obj)
+	hpa, err := client.AutoscalingV2().HorizontalPodAutoscalers(namespace).Get(c.Request.Context(), info.HPAName, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		info.Scaling = scalingDecision(hpa)
+	case apierrors.IsNotFound(err):
+		// KEDA has not created the HPA yet, or the object is paused at zero replicas
+		info.Scaling = &ScalingDecision{HPAName: info.HPAName, Metrics: []MetricDecision{}, Conditions: []HPACondition{}}
+	default:
+		h.logger.WithError(err).WithField("hpa", info.HPAName).Warn("Failed to get KEDA HPA")
+	}
+	h.tracingHelper.RecordSuccess(getSpan, "Scaled object retrieved")
+
+	c.JSON(http.StatusOK, info)
+}
+
+// GetScaledJobs lists KEDA ScaledJobs with their triggers
+// @Summary List KEDA ScaledJobs
+// @Description Lists KEDA ScaledJobs with trigger configuration, scaling strategy and pause state. Reports installed=false when KEDA is not installed.
+// @Tags KEDA
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param namespace query string false "Namespace to filter (empty for all namespaces)"
+// @Success 200 {object} ScaledJobsResponse "ScaledJobs"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/keda/scaledjobs [get]
+func (h *KedaHandler) GetScaledJobs(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	dynamicClient, _, err := h.getClients(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for scaled jobs")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
+
+	namespace := c.Query("namespace")
+
+	_, listSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "scaledjobs", namespace)
+	defer listSpan.End()
+
+	list, err := dynamicClient.Resource(scaledJobGVR).Namespace(namespace).List(c.Request.Context(), metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			h.tracingHelper.RecordSuccess(listSpan, "KEDA not installed")
+			c.JSON(http.StatusOK, ScaledJobsResponse{ScaledJobs: []ScaledJobInfo{}})
+			return
+		}
+		h.logger.WithError(err).Error("Failed to list scaled jobs")
+		h.tracingHelper.RecordError(listSpan, err, "Failed to list scaled jobs")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := ScaledJobsResponse{Installed: true, ScaledJobs: make([]ScaledJobInfo, 0, len(list.Items))}
+	for i := range list.Items {
+		response.ScaledJobs = append(response.ScaledJobs, transformScaledJob(&list.Items[i]))
+	}
+	sort.Slice(response.ScaledJobs, func(i, j int) bool {
+		a, b := response.ScaledJobs[i], response.ScaledJobs[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	h.tracingHelper.AddResourceAttributes(listSpan, "", "scaledjobs", len(list.Items))
+	h.tracingHelper.RecordSuccess(listSpan, fmt.Sprintf("Listed %d scaled jobs", len(list.Items)))
+
+	c.JSON(http.StatusOK, response)
+}
+
+// kindResource maps the :kind route segment to a KEDA resource
+func kindResource(kind string) (schema.GroupVersionResource, bool) {
+	switch kind {
+	case "scaledobjects":
+		return scaledObjectGVR, true
+	case "scaledjobs":
+		return scaledJobGVR, true
+	}
+	return schema.GroupVersionResource{}, false
+}
+
+// patchPause applies a pause or resume patch to a ScaledObject or ScaledJob
+func (h *KedaHandler) patchPause(c *gin.Context, operation string, buildPatch func(kind string) ([]byte, error)) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	dynamicClient, _, err := h.getClients(c)
+	if err != nil {
+		h.logger.WithError(err).Errorf("Failed to get client to %s KEDA object", operation)
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
+
+	kind := c.Param("kind")
+	gvr, ok := kindResource(kind)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "kind must be scaledobjects or scaledjobs"})
+		return
+	}
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+
+	patch, err := buildPatch(kind)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	_, patchSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "patch", kind, namespace)
+	defer patchSpan.End()
+
+	obj, err := dynamicClient.Resource(gvr).Namespace(namespace).Patch(c.Request.Context(), name, k8stypes.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("%s %s/%s not found", kind, namespace, name)})
+			return
+		}
+		h.logger.WithError(err).WithField("name", name).Errorf("Failed to %s KEDA object", operation)
+		h.tracingHelper.RecordError(patchSpan, err, "Failed to patch pause annotations")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(patchSpan, fmt.Sprintf("KEDA object %sd", operation))
+
+	h.logger.WithField("kind", kind).WithField("namespace", namespace).WithField("name", name).Infof("KEDA object %sd", operation)
+	paused, replicas := pausedState(obj)
+	c.JSON(http.StatusOK, gin.H{
+		"message":        fmt.Sprintf("%s %s/%s %sd", kind, namespace, name, operation),
+		"paused":         paused,
+		"pausedReplicas": replicas,
+	})
+}
+
+// PauseScaling pauses KEDA autoscaling of a ScaledObject or ScaledJob
+// @Summary Pause KEDA autoscaling
+// @Description Sets the KEDA pause annotations on a ScaledObject or ScaledJob. ScaledObjects can be pinned to a replica count with the paused-replicas annotation; otherwise they stay at their current replicas.
+// @Tags KEDA
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param kind path string true "scaledobjects or scaledjobs"
+// @Param namespace path string true "Namespace"
+// @Param name path string true "Name"
+// @Param request body PauseRequest false "Replica count to pin a paused ScaledObject to"
+// @Success 200 {object} map[string]interface{} "Paused"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 404 {object} map[string]string "Object not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/keda/{kind}/{namespace}/{name}/pause [post]
+func (h *KedaHandler) PauseScaling(c *gin.Context) {
+	var req PauseRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body: " + err.Error()})
+			return
+		}
+	}
+	h.patchPause(c, "pause", func(kind string) ([]byte, error) {
+		if req.Replicas != nil {
+			if kind != "scaledobjects" {
+				return nil, fmt.Errorf("replicas can only be set when pausing a ScaledObject")
+			}
+			if *req.Replicas < 0 {
+				return nil, fmt.Errorf("replicas must not be negative")
+			}
+		}
+		return pausePatch(req.Replicas)
+	})
+}
+
+// ResumeScaling resumes KEDA autoscaling of a ScaledObject or ScaledJob
+// @Summary Resume KEDA autoscaling
+// @Description Removes the KEDA pause annotations from a ScaledObject or ScaledJob so KEDA resumes scaling it
+// @Tags KEDA
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param kind path string true "scaledobjects or scaledjobs"
+// @Param namespace path string true "Namespace"
+// @Param name path string true "Name"
+// @Success 200 {object} map[string]interface{} "Resumed"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 404 {object} map[string]string "Object not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/keda/{kind}/{namespace}/{name}/resume [post]
+func (h *KedaHandler) ResumeScaling(c *gin.Context) {
+	h.patchPause(c, "resume", func(string) ([]byte, error) { return resumePatch() })
+}
@@ -0,0 +1,117 @@
+package keda
+
+import (
+	"encoding/json"
+	"testing"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestTransformScaledObject(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":        "worker",
+			"namespace":   "shop",
+			"annotations": map[string]interface{}{pausedReplicasAnnotation: "2"},
+		},
+		"spec": map[string]interface{}{
+			"scaleTargetRef":  map[string]interface{}{"name": "worker"},
+			"maxReplicaCount": int64(20),
+			"triggers": []interface{}{
+				map[string]interface{}{
+					"type":              "rabbitmq",
+					"metadata":          map[string]interface{}{"queueName": "orders", "password": "hunter2", "hostFromEnv": "RABBIT_HOST"},
+					"authenticationRef": map[string]interface{}{"name": "rabbit-auth"},
+				},
+			},
+		},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True"},
+				map[string]interface{}{"type": "Active", "status": "False"},
+			},
+		},
+	}}
+	info := transformScaledObject(obj)
+	if info.TargetKind != "Deployment" || info.MinReplicas != 0 || info.MaxReplicas != 20 || info.PollingInterval != defaultPollingInterval || info.CooldownPeriod != defaultCooldownPeriod {
+		t.Errorf("spec = %+v", info)
+	}
+	if !info.Ready || info.Active || !info.Paused || info.PausedReplicas == nil || *info.PausedReplicas != 2 {
+		t.Errorf("state = %+v", info)
+	}
+	if info.HPAName != "keda-hpa-worker" {
+		t.Errorf("hpaName = %q", info.HPAName)
+	}
+	trigger := info.Triggers[0]
+	if trigger.Metadata["password"] != "[redacted]" || trigger.Metadata["queueName"] != "orders" || trigger.Metadata["hostFromEnv"] != "RABBIT_HOST" {
+		t.Errorf("metadata = %v", trigger.Metadata)
+	}
+	if trigger.AuthenticationRef != "TriggerAuthentication/rabbit-auth" {
+		t.Errorf("authenticationRef = %q", trigger.AuthenticationRef)
+	}
+}
+
+func TestScalingDecision(t *testing.T) {
+	minReplicas := int32(1)
+	target := resource.MustParse("5")
+	current := resource.MustParse("12")
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "keda-hpa-worker"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			MinReplicas: &minReplicas,
+			MaxReplicas: 20,
+			Metrics: []autoscalingv2.MetricSpec{{
+				Type: autoscalingv2.ExternalMetricSourceType,
+				External: &autoscalingv2.ExternalMetricSource{
+					Metric: autoscalingv2.MetricIdentifier{Name: "s0-rabbitmq-orders"},
+					Target: autoscalingv2.MetricTarget{Type: autoscalingv2.AverageValueMetricType, AverageValue: &target},
+				},
+			}},
+		},
+		Status: autoscalingv2.HorizontalPodAutoscalerStatus{
+			CurrentReplicas: 2,
+			DesiredReplicas: 3,
+			CurrentMetrics: []autoscalingv2.MetricStatus{{
+				Type: autoscalingv2.ExternalMetricSourceType,
+				External: &autoscalingv2.ExternalMetricStatus{
+					Metric:  autoscalingv2.MetricIdentifier{Name: "s0-rabbitmq-orders"},
+					Current: autoscalingv2.MetricValueStatus{AverageValue: &current},
+				},
+			}},
+		},
+	}
+	decision := scalingDecision(hpa)
+	if decision.MinReplicas != 1 || decision.DesiredReplicas != 3 || len(decision.Metrics) != 1 {
+		t.Fatalf("decision = %+v", decision)
+	}
+	metric := decision.Metrics[0]
+	if metric.Current != "12" || metric.Target != "5" || metric.TriggerIndex == nil || *metric.TriggerIndex != 0 {
+		t.Errorf("metric = %+v", metric)
+	}
+}
+
+func TestPausePatch(t *testing.T) {
+	replicas := int32(0)
+	data, err := pausePatch(&replicas)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var patch struct {
+		Metadata struct {
+			Annotations map[string]*string `json:"annotations"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(data, &patch); err != nil {
+		t.Fatal(err)
+	}
+	annotations := patch.Metadata.Annotations
+	if v := annotations[pausedReplicasAnnotation]; v == nil || *v != "0" {
+		t.Errorf("paused-replicas = %v", v)
+	}
+	if v, ok := annotations[pausedAnnotation]; !ok || v != nil {
+		t.Errorf("paused annotation should be removed, got %v", v)
+	}
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Facets-cloud/kube-dash/internal/config"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Lint modes of the server policy
+const (
+	lintModeWarn   = "warn"
+	lintModeStrict = "strict"
+	lintModeOff    = "off"
+)
+
+// Lint rule IDs, usable in the disabled rules of the server policy
+const (
+	lintMissingLimits         = "missing-resource-limits"
+	lintLatestTag             = "latest-tag"
+	lintMissingLivenessProbe  = "missing-liveness-probe"
+	lintHostPath              = "host-path-mount"
+	lintPrivileged            = "privileged"
+	lintMissingTopologySpread = "missing-topology-spread"
+)
+
+// LintWarning is a best-practice finding on a submitted object
+type LintWarning struct {
+	Rule      string `json:"rule"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Path      string `json:"path"`
+	Message   string `json:"message"`
+}
+
+// linter checks workload manifests against the server lint policy
+type linter struct {
+	mode     string
+	disabled map[string]bool
+}
+
+func newLinter(cfg *config.LintConfig) *linter {
+	l := &linter{mode: lintModeWarn, disabled: map[string]bool{}}
+	if cfg == nil {
+		return l
+	}
+	switch mode := strings.ToLower(strings.TrimSpace(cfg.Mode)); mode {
+	case lintModeStrict, lintModeOff:
+		l.mode = mode
+	}
+	for _, rule := range cfg.DisabledRules {
+		l.disabled[strings.TrimSpace(rule)] = true
+	}
+	return l
+}
+
+// podSpecPath returns the path of the pod spec in an object, if it has one
+func podSpecPath(kind string) ([]string, bool) {
+	if kind == "Pod" {
+		return []string{"spec"}, true
+	}
+	path, ok := podTemplatePaths[kind]
+	if !ok {
+		return nil, false
+	}
+	return append(append([]string{}, path...), "spec"), true
+}
+
+// lint returns the findings for one object; objects without a pod spec have none
+func (l *linter) lint(obj *unstructured.Unstructured) []LintWarning {
+	if l.mode == lintModeOff {
+		return nil
+	}
+	kind := obj.GetKind()
+	path, ok := podSpecPath(kind)
+	if !ok {
+		return nil
+	}
+	podSpec, found, _ := unstructured.NestedMap(obj.Object, path...)
+	if !found {
+		return nil
+	}
+	prefix := strings.Join(path, ".")
+
+	var warnings []LintWarning
+	report := func(rule, fieldPath, message string) {
+		if l.disabled[rule] {
+			return
+		}
+		warnings = append(warnings, LintWarning{
+			Rule:      rule,
+			Kind:      kind,
+			Name:      obj.GetName(),
+			Namespace: obj.GetNamespace(),
+			Path:      fieldPath,
+			Message:   message,
+		})
+	}
+
+	// Jobs run to completion, so liveness probes are not expected
+	batch := kind == "Job" || kind == "CronJob"
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, _, _ := unstructured.NestedSlice(podSpec, field)
+		for _, item := range containers {
+			container, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _ := container["name"].(string)
+			containerPath := fmt.Sprintf("%s.%s[name=%s]", prefix, field, name)
+
+			if image, _ := container["image"].(string); image != "" && usesLatestTag(image) {
+				report(lintLatestTag, containerPath+".image", fmt.Sprintf("container %q uses image %q without a pinned tag", name, image))
+			}
+			if privileged, _, _ := unstructured.NestedBool(container, "securityContext", "privileged"); privileged {
+				report(lintPrivileged, containerPath+".securityContext.privileged", fmt.Sprintf("container %q runs privileged", name))
+			}
+			if field == "initContainers" {
+				continue
+			}
+			limits, _, _ := unstructured.NestedMap(container, "resources", "limits")
+			var missing []string
+			for _, resource := range []string{"cpu", "memory"} {
+				if _, ok := limits[resource]; !ok {
+					missing = append(missing, resource)
+				}
+			}
+			if len(missing) > 0 {
+				report(lintMissingLimits, containerPath+".resources.limits", fmt.Sprintf("container %q has no %s limit", name, strings.Join(missing, " or ")))
+			}
+			if _, ok := container["livenessProbe"]; !ok && !batch {
+				report(lintMissingLivenessProbe, containerPath+".livenessProbe", fmt.Sprintf("container %q has no liveness probe", name))
+			}
+		}
+	}
+
+	volumes, _, _ := unstructured.NestedSlice(podSpec, "volumes")
+	for _, item := range volumes {
+		volume, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if hostPath, ok := volume["hostPath"].(map[string]interface{}); ok {
+			name, _ := volume["name"].(string)
+			report(lintHostPath, fmt.Sprintf("%s.volumes[name=%s].hostPath", prefix, name), fmt.Sprintf("volume %q mounts host path %v", name, hostPath["path"]))
+		}
+	}
+
+	if kind == "Deployment" || kind == "StatefulSet" || kind == "ReplicaSet" {
+		// Decoded manifests hold numbers as float64
+		var replicas float64
+		switch v := obj.Object["spec"].(map[string]interface{})["replicas"].(type) {
+		case float64:
+			replicas = v
+		case int64:
+			replicas = float64(v)
+		}
+		_, hasSpread := podSpec["topologySpreadConstraints"]
+		_, hasAntiAffinity, _ := unstructured.NestedMap(podSpec, "affinity", "podAntiAffinity")
+		if replicas > 1 && !hasSpread && !hasAntiAffinity {
+			report(lintMissingTopologySpread, prefix+".topologySpreadConstraints", "replicas are not spread across nodes or zones")
+		}
+	}
+	return warnings
+}
+
+// usesLatestTag reports whether an image reference is untagged or tagged latest; digests are pinned
+func usesLatestTag(image string) bool {
+	if strings.Contains(image, "@") {
+		return false
+	}
+	name := image[strings.LastIndex(image, "/")+1:]
+	i := strings.LastIndex(name, ":")
+	return i < 0 || name[i+1:] == "latest"
+}
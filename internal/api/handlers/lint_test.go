@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/Facets-cloud/kube-dash/internal/config"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestLint(t *testing.T) {
+	manifest := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: default
+spec:
+  replicas: 3
+  template:
+    spec:
+      initContainers:
+      - name: setup
+        image: busybox:1.36
+      containers:
+      - name: app
+        image: registry.internal/web
+        securityContext:
+          privileged: true
+        resources:
+          limits:
+            cpu: 500m
+      - name: pinned
+        image: web@sha256:abc
+        livenessProbe:
+          httpGet:
+            path: /healthz
+            port: 8080
+        resources:
+          limits:
+            cpu: 500m
+            memory: 256Mi
+      volumes:
+      - name: docker
+        hostPath:
+          path: /var/run/docker.sock
+`
+	objects, err := decodeManifests(manifest)
+	if err != nil || len(objects) != 1 {
+		t.Fatalf("failed to decode manifest: %v", err)
+	}
+
+	rules := map[string]int{}
+	for _, w := range newLinter(nil).lint(objects[0]) {
+		rules[w.Rule]++
+	}
+	want := map[string]int{
+		lintLatestTag:             1,
+		lintPrivileged:            1,
+		lintMissingLimits:         1,
+		lintMissingLivenessProbe:  1,
+		lintHostPath:              1,
+		lintMissingTopologySpread: 1,
+	}
+	for rule, count := range want {
+		if rules[rule] != count {
+			t.Errorf("expected %d %s findings, got %d (%v)", count, rule, rules[rule], rules)
+		}
+	}
+
+	strict := newLinter(&config.LintConfig{Mode: "Strict", DisabledRules: []string{lintHostPath}})
+	for _, w := range strict.lint(objects[0]) {
+		if w.Rule == lintHostPath {
+			t.Errorf("expected disabled rule to be skipped, got %+v", w)
+		}
+	}
+	if strict.mode != lintModeStrict {
+		t.Errorf("expected strict mode, got %q", strict.mode)
+	}
+	if w := newLinter(&config.LintConfig{Mode: "off"}).lint(objects[0]); len(w) != 0 {
+		t.Errorf("expected no findings when linting is off, got %+v", w)
+	}
+
+	configMap := &unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap"}}
+	if w := newLinter(nil).lint(configMap); len(w) != 0 {
+		t.Errorf("expected objects without a pod spec to be skipped, got %+v", w)
+	}
+}
+
+func TestUsesLatestTag(t *testing.T) {
+	for image, want := range map[string]bool{
+		"nginx":                     true,
+		"nginx:latest":              true,
+		"localhost:5000/app":        true,
+		"localhost:5000/app:1.2":    false,
+		"nginx@sha256:0123":         false,
+		"ghcr.io/org/app:v1.0.0-rc": false,
+	} {
+		if got := usesLatestTag(image); got != want {
+			t.Errorf("usesLatestTag(%q) = %v, want %v", image, got, want)
+		}
+	}
+}
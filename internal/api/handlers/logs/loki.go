@@ -0,0 +1,525 @@
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/types"
+	"github.com/Facets-cloud/kube-dash/internal/config"
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/internal/tracing"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// defaultLokiLimit is the number of lines returned when no limit is requested
+const defaultLokiLimit = 1000
+
+// lokiServiceNames are in-cluster Loki service names in order of preference (gateway and query paths first)
+var lokiServiceNames = []string{"loki-gateway", "loki-query-frontend", "loki-read", "loki"}
+
+// labelNamePattern restricts user supplied label names to valid LogQL identifiers
+var labelNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// lokiTarget is an in-cluster Loki service reached through the API server proxy
+type lokiTarget struct {
+	Namespace string
+	Service   string
+	Port      string
+}
+
+// LokiLogEntry is a single log line returned by Loki
+type LokiLogEntry struct {
+	Timestamp   string            `json:"timestamp"`
+	TimestampMs int64             `json:"timestampMs,omitempty"`
+	Line        string            `json:"line"`
+	Labels      map[string]string `json:"labels"`
+}
+
+// LokiQueryResponse is the result of a LogQL range query
+type LokiQueryResponse struct {
+	Query     string         `json:"query"`
+	Start     string         `json:"start"`
+	End       string         `json:"end"`
+	Entries   []LokiLogEntry `json:"entries"`
+	Truncated bool           `json:"truncated"`
+}
+
+// LokiHandler runs LogQL queries against an external or in-cluster Loki
+type LokiHandler struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	logger        *logger.Logger
+	tracingHelper *tracing.TracingHelper
+	config        *config.LokiConfig
+	httpClient    *http.Client
+
+	// Discovered in-cluster targets keyed by config/cluster
+	targets sync.Map
+}
+
+// NewLokiHandler creates a new Loki handler
+func NewLokiHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger, cfg *config.LokiConfig) *LokiHandler {
+	return &LokiHandler{
+		store:         store,
+		clientFactory: clientFactory,
+		logger:        log,
+		tracingHelper: tracing.GetTracingHelper(),
+		config:        cfg,
+		httpClient:    &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// getClient gets the Kubernetes client for the current request
+func (h *LokiHandler) getClient(c *gin.Context) (kubernetes.Interface, error) {
+	configID := c.Query("config")
+	cluster := c.Query("cluster")
+	if configID == "" {
+		return nil, fmt.Errorf("config parameter is required")
+	}
+	cfg, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, fmt.Errorf("config not found: %w", err)
+	}
+	client, err := h.clientFactory.GetClientForConfig(cfg, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Kubernetes client: %w", err)
+	}
+	return client, nil
+}
+
+// discoverLoki finds a Loki service in the cluster that answers the labels API
+func (h *LokiHandler) discoverLoki(ctx context.Context, client kubernetes.Interface) (*lokiTarget, error) {
+	svcs, err := client.CoreV1().Services("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services for discovery: %w", err)
+	}
+
+	rank := func(name string) int {
+		for i, preferred := range lokiServiceNames {
+			if strings.HasSuffix(name, preferred) {
+				return i
+			}
+		}
+		return len(lokiServiceNames)
+	}
+	var candidates []lokiTarget
+	for _, s := range svcs.Items {
+		nameLower := strings.ToLower(s.Name)
+		if !strings.Contains(nameLower, "loki") && s.Labels["app.kubernetes.io/name"] != "loki" {
+			continue
+		}
+		// Headless and memberlist services cannot be proxied to
+		if strings.Contains(nameLower, "headless") || strings.Contains(nameLower, "memberlist") {
+			continue
+		}
+		for _, p := range s.Spec.Ports {
+			portName := strings.ToLower(p.Name)
+			if p.Port == 3100 || p.Port == 80 || strings.Contains(portName, "http") {
+				port := p.Name
+				if port == "" {
+					port = strconv.Itoa(int(p.Port))
+				}
+				candidates = append(candidates, lokiTarget{Namespace: s.Namespace, Service: s.Name, Port: port})
+				break
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return rank(candidates[i].Service) < rank(candidates[j].Service) })
+
+	for i := range candidates {
+		if _, err := h.proxyLoki(ctx, client, &candidates[i], "loki/api/v1/labels", nil); err == nil {
+			return &candidates[i], nil
+		}
+	}
+	return nil, fmt.Errorf("loki not found")
+}
+
+// proxyLoki performs a GET against Loki through the API server service proxy
+func (h *LokiHandler) proxyLoki(ctx context.Context, client kubernetes.Interface, target *lokiTarget, path string, params url.Values) ([]byte, error) {
+	req := client.CoreV1().RESTClient().Get().
+		Namespace(target.Namespace).
+		Resource("services").
+		Name(target.Service + ":" + target.Port).
+		SubResource("proxy").
+		Suffix(path)
+	if h.config.TenantID != "" {
+		req = req.SetHeader("X-Scope-OrgID", h.config.TenantID)
+	}
+	for k, values := range params {
+		for _, v := range values {
+			req = req.Param(k, v)
+		}
+	}
+	return req.DoRaw(ctx)
+}
+
+// getExternal performs a GET against the configured external Loki URL
+func (h *LokiHandler) getExternal(ctx context.Context, path string, params url.Values) ([]byte, error) {
+	endpoint := strings.TrimSuffix(h.config.URL, "/") + "/" + path
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if h.config.TenantID != "" {
+		req.Header.Set("X-Scope-OrgID", h.config.TenantID)
+	}
+	if h.config.Username != "" {
+		req.SetBasicAuth(h.config.Username, h.config.Password)
+	}
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("loki returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// query calls the Loki HTTP API, using the external URL when configured and in-cluster discovery otherwise
+func (h *LokiHandler) query(c *gin.Context, path string, params url.Values) ([]byte, error) {
+	if h.config.URL != "" {
+		return h.getExternal(c.Request.Context(), path, params)
+	}
+
+	client, err := h.getClient(c)
+	if err != nil {
+		return nil, err
+	}
+	key := c.Query("config") + "|" + c.Query("cluster")
+	if cached, ok := h.targets.Load(key); ok {
+		raw, err := h.proxyLoki(c.Request.Context(), client, cached.(*lokiTarget), path, params)
+		if err == nil {
+			return raw, nil
+		}
+		// The service may have moved; rediscover below
+		h.targets.Delete(key)
+	}
+	target, err := h.discoverLoki(c.Request.Context(), client)
+	if err != nil {
+		return nil, err
+	}
+	h.targets.Store(key, target)
+	return h.proxyLoki(c.Request.Context(), client, target, path, params)
+}
+
+// escapeLogQL escapes a value for use inside a double-quoted LogQL string
+func escapeLogQL(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
+
+// buildLogQL assembles a stream selector and line filter from request parameters
+func buildLogQL(c *gin.Context) (string, error) {
+	var matchers []string
+	if ns := c.Query("namespace"); ns != "" {
+		matchers = append(matchers, fmt.Sprintf(`namespace="%s"`, escapeLogQL(ns)))
+	}
+	if pod := c.Query("pod"); pod != "" {
+		matchers = append(matchers, fmt.Sprintf(`pod="%s"`, escapeLogQL(pod)))
+	} else if workload := c.Query("workload"); workload != "" {
+		// Pods of a Deployment, StatefulSet, DaemonSet or Job are prefixed with the workload name
+		matchers = append(matchers, fmt.Sprintf(`pod=~"%s-.*"`, escapeLogQL(regexp.QuoteMeta(workload))))
+	}
+	if container := c.Query("container"); container != "" {
+		matchers = append(matchers, fmt.Sprintf(`container="%s"`, escapeLogQL(container)))
+	}
+	for _, pair := range c.QueryArray("label") {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || !labelNamePattern.MatchString(name) {
+			return "", fmt.Errorf("invalid label filter %q, expected name=value", pair)
+		}
+		matchers = append(matchers, fmt.Sprintf(`%s="%s"`, name, escapeLogQL(value)))
+	}
+	if len(matchers) == 0 {
+		return "", fmt.Errorf("either query or at least one of namespace, pod, workload, container or label is required")
+	}
+
+	query := "{" + strings.Join(matchers, ", ") + "}"
+	if filter := c.Query("filter"); filter != "" {
+		query += fmt.Sprintf(` |= "%s"`, escapeLogQL(filter))
+	}
+	return query, nil
+}
+
+// parseTimeParam accepts RFC3339 timestamps or durations relative to now (e.g. 2h)
+func parseTimeParam(value string, fallback time.Time) (time.Time, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q, expected RFC3339 or a duration such as 2h", value)
+}
+
+// parseLokiStreams flattens a streams result into log entries
+func parseLokiStreams(raw []byte) ([]LokiLogEntry, error) {
+	var resp struct {
+		Status string `json:"status"`
+		Data   struct {
+			ResultType string `json:"resultType"`
+			Result     []struct {
+				Stream map[string]string `json:"stream"`
+				Values [][2]string       `json:"values"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Status != "success" {
+		return nil, fmt.Errorf("loki query failed with status %q", resp.Status)
+	}
+	if resp.Data.ResultType != "streams" {
+		return nil, fmt.Errorf("unsupported result type %q, only log queries are supported", resp.Data.ResultType)
+	}
+
+	type timed struct {
+		nanos int64
+		entry LokiLogEntry
+	}
+	var all []timed
+	for _, stream := range resp.Data.Result {
+		for _, value := range stream.Values {
+			nanos, err := strconv.ParseInt(value[0], 10, 64)
+			if err != nil {
+				continue
+			}
+			all = append(all, timed{nanos: nanos, entry: LokiLogEntry{
+				Timestamp:   time.Unix(0, nanos).UTC().Format(time.RFC3339Nano),
+				TimestampMs: types.EpochMillis(time.Unix(0, nanos)),
+				Line:        value[1],
+				Labels:      stream.Stream,
+			}})
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].nanos < all[j].nanos })
+
+	entries := make([]LokiLogEntry, len(all))
+	for i := range all {
+		entries[i] = all[i].entry
+	}
+	return entries, nil
+}
+
+// GetAvailability reports whether a Loki backend is reachable
+// @Summary Check Loki availability
+// @Description Checks whether Loki is configured externally or can be discovered in the cluster
+// @Tags Logs
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Success 200 {object} map[string]interface{} "Loki availability status"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/logs/loki/availability [get]
+func (h *LokiHandler) GetAvailability(c *gin.Context) {
+	_, span := h.tracingHelper.StartDataProcessingSpan(c.Request.Context(), "loki-availability")
+	defer span.End()
+
+	if _, err := h.query(c, "loki/api/v1/labels", nil); err != nil {
+		h.tracingHelper.RecordSuccess(span, "Loki not available")
+		c.JSON(http.StatusOK, gin.H{"available": false, "reason": err.Error()})
+		return
+	}
+
+	response := gin.H{"available": true, "source": "in-cluster"}
+	if h.config.URL != "" {
+		response["source"] = "external"
+	} else if target, ok := h.targets.Load(c.Query("config") + "|" + c.Query("cluster")); ok {
+		t := target.(*lokiTarget)
+		response["namespace"] = t.Namespace
+		response["service"] = t.Service
+	}
+	h.tracingHelper.RecordSuccess(span, "Loki available")
+	c.JSON(http.StatusOK, response)
+}
+
+// QueryLogs runs a LogQL range query for a pod, workload or arbitrary selector
+// @Summary Query historical logs from Loki
+// @Description Runs a LogQL range query over an arbitrary time range. Either pass a raw LogQL query, or build one from namespace, pod or workload, container, label filters and a line filter.
+// @Tags Logs
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param query query string false "Raw LogQL log query (overrides the selector parameters)"
+// @Param namespace query string false "Namespace label"
+// @Param pod query string false "Pod name"
+// @Param workload query string false "Workload name; matches pods prefixed with it"
+// @Param container query string false "Container name"
+// @Param label query []string false "Additional label filters as name=value" collectionFormat(multi)
+// @Param filter query string false "Only lines containing this text"
+// @Param start query string false "Start time (RFC3339 or duration ago, default 1h)"
+// @Param end query string false "End time (RFC3339 or duration ago, default now)"
+// @Param limit query int false "Maximum lines to return (default 1000)"
+// @Param direction query string false "backward (newest first, default) or forward"
+// @Success 200 {object} LokiQueryResponse "Log entries in chronological order"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 502 {object} map[string]string "Loki unavailable or query failed"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/logs/loki/query [get]
+func (h *LokiHandler) QueryLogs(c *gin.Context) {
+	_, span := h.tracingHelper.StartDataProcessingSpan(c.Request.Context(), "loki-query-range")
+	defer span.End()
+
+	query := c.Query("query")
+	if query == "" {
+		built, err := buildLogQL(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		query = built
+	}
+
+	now := time.Now()
+	end, err := parseTimeParam(c.Query("end"), now)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	start, err := parseTimeParam(c.Query("start"), end.Add(-time.Hour))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !start.Before(end) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start must be before end"})
+		return
+	}
+
+	limit := defaultLokiLimit
+	if v := c.Query("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		limit = parsed
+	}
+	if h.config.MaxLines > 0 && limit > h.config.MaxLines {
+		limit = h.config.MaxLines
+	}
+	direction := c.DefaultQuery("direction", "backward")
+	if direction != "backward" && direction != "forward" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "direction must be backward or forward"})
+		return
+	}
+
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("start", strconv.FormatInt(start.UnixNano(), 10))
+	params.Set("end", strconv.FormatInt(end.UnixNano(), 10))
+	params.Set("limit", strconv.Itoa(limit))
+	params.Set("direction", direction)
+
+	raw, err := h.query(c, "loki/api/v1/query_range", params)
+	if err != nil {
+		h.logger.WithError(err).WithField("query", query).Error("Loki query failed")
+		h.tracingHelper.RecordError(span, err, "Loki query failed")
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+	entries, err := parseLokiStreams(raw)
+	if err != nil {
+		h.tracingHelper.RecordError(span, err, "Failed to parse Loki response")
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(span, fmt.Sprintf("Loki returned %d lines", len(entries)))
+
+	c.JSON(http.StatusOK, LokiQueryResponse{
+		Query:     query,
+		Start:     start.UTC().Format(time.RFC3339),
+		End:       end.UTC().Format(time.RFC3339),
+		Entries:   entries,
+		Truncated: len(entries) >= limit,
+	})
+}
+
+// GetLabels lists Loki label names, or the values of one label, for building filters
+// @Summary List Loki labels
+// @Description Lists label names known to Loki, or the values of a single label when name is given
+// @Tags Logs
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param name query string false "Label name to list values for"
+// @Param start query string false "Start time (RFC3339 or duration ago, default 6h)"
+// @Success 200 {array} string "Label names or values"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 502 {object} map[string]string "Loki unavailable or query failed"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/logs/loki/labels [get]
+func (h *LokiHandler) GetLabels(c *gin.Context) {
+	_, span := h.tracingHelper.StartDataProcessingSpan(c.Request.Context(), "loki-labels")
+	defer span.End()
+
+	start, err := parseTimeParam(c.Query("start"), time.Now().Add(-6*time.Hour))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	path := "loki/api/v1/labels"
+	if name := c.Query("name"); name != "" {
+		if !labelNamePattern.MatchString(name) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid label name"})
+			return
+		}
+		path = "loki/api/v1/label/" + name + "/values"
+	}
+	params := url.Values{}
+	params.Set("start", strconv.FormatInt(start.UnixNano(), 10))
+
+	raw, err := h.query(c, path, params)
+	if err != nil {
+		h.tracingHelper.RecordError(span, err, "Loki label query failed")
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+	var resp struct {
+		Status string   `json:"status"`
+		Data   []string `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+	if resp.Data == nil {
+		resp.Data = []string{}
+	}
+	h.tracingHelper.RecordSuccess(span, fmt.Sprintf("Loki returned %d labels", len(resp.Data)))
+	c.JSON(http.StatusOK, resp.Data)
+}
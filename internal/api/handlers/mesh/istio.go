@@ -0,0 +1,301 @@
+package mesh
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	virtualServiceGVR     = schema.GroupVersionResource{Group: "networking.istio.io", Version: "v1beta1", Resource: "virtualservices"}
+	destinationRuleGVR    = schema.GroupVersionResource{Group: "networking.istio.io", Version: "v1beta1", Resource: "destinationrules"}
+	peerAuthenticationGVR = schema.GroupVersionResource{Group: "security.istio.io", Version: "v1beta1", Resource: "peerauthentications"}
+)
+
+const (
+	// istioRootNamespace holds mesh-wide Istio configuration
+	istioRootNamespace = "istio-system"
+	clusterDomain      = "cluster.local"
+	// mtlsModeDefault applies when no PeerAuthentication sets a mode
+	mtlsModeDefault = "PERMISSIVE"
+)
+
+// VirtualServiceInfo summarizes an Istio VirtualService
+type VirtualServiceInfo struct {
+	Name      string      `json:"name"`
+	Namespace string      `json:"namespace"`
+	Hosts     []string    `json:"hosts"`
+	Gateways  []string    `json:"gateways,omitempty"`
+	Routes    []RouteInfo `json:"routes"`
+}
+
+// RouteInfo is one http, tcp or tls route of a VirtualService
+type RouteInfo struct {
+	Protocol     string             `json:"protocol"` // http, tcp or tls
+	Name         string             `json:"name,omitempty"`
+	Match        []string           `json:"match,omitempty"` // e.g. "uri prefix /api"; any one matching selects the route
+	Destinations []RouteDestination `json:"destinations"`
+	Timeout      string             `json:"timeout,omitempty"`
+}
+
+// RouteDestination is a weighted destination of a route
+type RouteDestination struct {
+	Host   string `json:"host"`
+	Subset string `json:"subset,omitempty"`
+	Port   int64  `json:"port,omitempty"`
+	Weight int64  `json:"weight,omitempty"`
+}
+
+// DestinationRuleInfo summarizes an Istio DestinationRule
+type DestinationRuleInfo struct {
+	Name             string       `json:"name"`
+	Namespace        string       `json:"namespace"`
+	Host             string       `json:"host"`
+	Subsets          []SubsetInfo `json:"subsets,omitempty"`
+	TLSMode          string       `json:"tlsMode,omitempty"` // client-side TLS: DISABLE, SIMPLE, MUTUAL or ISTIO_MUTUAL
+	LoadBalancer     string       `json:"loadBalancer,omitempty"`
+	OutlierDetection bool         `json:"outlierDetection"`
+}
+
+// SubsetInfo is a named version of a service selected by labels
+type SubsetInfo struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// MTLSStatus is the inbound mutual TLS mode of a workload and where it comes from
+type MTLSStatus struct {
+	Mode   string `json:"mode"`   // STRICT, PERMISSIVE or DISABLE for Istio; AUTOMATIC for Linkerd
+	Source string `json:"source"` // the PeerAuthentication that sets it, or the mesh default
+}
+
+// peerAuthentication is the part of a PeerAuthentication that decides the mTLS mode
+type peerAuthentication struct {
+	namespace string
+	name      string
+	selector  map[string]string
+	mode      string
+}
+
+// serviceHost returns the fully qualified host name of a service
+func serviceHost(namespace, name string) string {
+	return name + "." + namespace + ".svc." + clusterDomain
+}
+
+// resolveHost expands a host of Istio config in namespace: short names are services in that
+// namespace and .svc names lack the cluster domain
+func resolveHost(host, namespace string) string {
+	switch {
+	case host == "*" || strings.HasPrefix(host, "*."):
+		return host
+	case !strings.Contains(host, "."):
+		return serviceHost(namespace, host)
+	case strings.HasSuffix(host, ".svc"):
+		return host + "." + clusterDomain
+	}
+	return host
+}
+
+// hostMatches reports whether a resolved host, possibly a wildcard, covers target
+func hostMatches(host, target string) bool {
+	if host == "*" || host == target {
+		return true
+	}
+	return strings.HasPrefix(host, "*.") && strings.HasSuffix(target, host[1:])
+}
+
+// stringSlice reads a string list from an unstructured object
+func stringSlice(obj map[string]interface{}, fields ...string) []string {
+	values, _, _ := unstructured.NestedStringSlice(obj, fields...)
+	return values
+}
+
+// describeStringMatch renders an Istio StringMatch ({exact|prefix|regex: value}) as "kind value"
+func describeStringMatch(match interface{}) string {
+	m, ok := match.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	for _, kind := range []string{"exact", "prefix", "regex"} {
+		if value, ok := m[kind].(string); ok {
+			return kind + " " + value
+		}
+	}
+	return ""
+}
+
+// describeMatch renders one match block of a route
+func describeMatch(match map[string]interface{}) string {
+	var parts []string
+	for _, field := range []string{"uri", "method", "authority", "scheme"} {
+		if desc := describeStringMatch(match[field]); desc != "" {
+			parts = append(parts, field+" "+desc)
+		}
+	}
+	if headers, ok := match["headers"].(map[string]interface{}); ok {
+		names := make([]string, 0, len(headers))
+		for name := range headers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			parts = append(parts, "header "+name+" "+describeStringMatch(headers[name]))
+		}
+	}
+	if port, ok, _ := unstructured.NestedInt64(match, "port"); ok {
+		parts = append(parts, fmt.Sprintf("port %d", port))
+	}
+	if sniHosts := stringSlice(match, "sniHosts"); len(sniHosts) > 0 {
+		parts = append(parts, "sni "+strings.Join(sniHosts, ","))
+	}
+	if gateways := stringSlice(match, "gateways"); len(gateways) > 0 {
+		parts = append(parts, "gateway "+strings.Join(gateways, ","))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// transformVirtualService summarizes a VirtualService, resolving destination hosts
+func transformVirtualService(obj *unstructured.Unstructured) VirtualServiceInfo {
+	spec, _, _ := unstructured.NestedMap(obj.Object, "spec")
+	info := VirtualServiceInfo{
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+		Hosts:     stringSlice(spec, "hosts"),
+		Gateways:  stringSlice(spec, "gateways"),
+		Routes:    []RouteInfo{},
+	}
+	for _, protocol := range []string{"http", "tcp", "tls"} {
+		routes, _, _ := unstructured.NestedSlice(spec, protocol)
+		for _, r := range routes {
+			route, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			ri := RouteInfo{Protocol: protocol, Destinations: []RouteDestination{}}
+			ri.Name, _, _ = unstructured.NestedString(route, "name")
+			ri.Timeout, _, _ = unstructured.NestedString(route, "timeout")
+			matches, _, _ := unstructured.NestedSlice(route, "match")
+			for _, m := range matches {
+				if match, ok := m.(map[string]interface{}); ok {
+					if desc := describeMatch(match); desc != "" {
+						ri.Match = append(ri.Match, desc)
+					}
+				}
+			}
+			destinations, _, _ := unstructured.NestedSlice(route, "route")
+			for _, d := range destinations {
+				dest, ok := d.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				rd := RouteDestination{}
+				host, _, _ := unstructured.NestedString(dest, "destination", "host")
+				rd.Host = resolveHost(host, info.Namespace)
+				rd.Subset, _, _ = unstructured.NestedString(dest, "destination", "subset")
+				rd.Port, _, _ = unstructured.NestedInt64(dest, "destination", "port", "number")
+				rd.Weight, _, _ = unstructured.NestedInt64(dest, "weight")
+				ri.Destinations = append(ri.Destinations, rd)
+			}
+			info.Routes = append(info.Routes, ri)
+		}
+	}
+	return info
+}
+
+// routesTo reports whether a VirtualService serves or routes to a service host
+func (vs *VirtualServiceInfo) routesTo(host string) bool {
+	for _, h := range vs.Hosts {
+		if hostMatches(resolveHost(h, vs.Namespace), host) {
+			return true
+		}
+	}
+	for _, route := range vs.Routes {
+		for _, dest := range route.Destinations {
+			if dest.Host == host {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// transformDestinationRule summarizes a DestinationRule
+func transformDestinationRule(obj *unstructured.Unstructured) DestinationRuleInfo {
+	spec, _, _ := unstructured.NestedMap(obj.Object, "spec")
+	info := DestinationRuleInfo{Name: obj.GetName(), Namespace: obj.GetNamespace()}
+	host, _, _ := unstructured.NestedString(spec, "host")
+	info.Host = resolveHost(host, info.Namespace)
+	info.TLSMode, _, _ = unstructured.NestedString(spec, "trafficPolicy", "tls", "mode")
+	info.LoadBalancer, _, _ = unstructured.NestedString(spec, "trafficPolicy", "loadBalancer", "simple")
+	_, info.OutlierDetection, _ = unstructured.NestedMap(spec, "trafficPolicy", "outlierDetection")
+	subsets, _, _ := unstructured.NestedSlice(spec, "subsets")
+	for _, s := range subsets {
+		subset, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		si := SubsetInfo{}
+		si.Name, _, _ = unstructured.NestedString(subset, "name")
+		si.Labels, _, _ = unstructured.NestedStringMap(subset, "labels")
+		info.Subsets = append(info.Subsets, si)
+	}
+	return info
+}
+
+// transformPeerAuthentication reads the selector and mode of a PeerAuthentication
+func transformPeerAuthentication(obj *unstructured.Unstructured) peerAuthentication {
+	pa := peerAuthentication{namespace: obj.GetNamespace(), name: obj.GetName()}
+	pa.selector, _, _ = unstructured.NestedStringMap(obj.Object, "spec", "selector", "matchLabels")
+	pa.mode, _, _ = unstructured.NestedString(obj.Object, "spec", "mtls", "mode")
+	if pa.mode == "UNSET" {
+		pa.mode = ""
+	}
+	return pa
+}
+
+// effectiveMTLS resolves the inbound mTLS mode of a workload the way Istio does: a workload
+// PeerAuthentication overrides the namespace one, which overrides the mesh-wide one in the root
+// namespace. UNSET modes inherit from the next level; port-level overrides are not considered.
+func effectiveMTLS(policies []peerAuthentication, namespace string, podLabels map[string]string) MTLSStatus {
+	sort.Slice(policies, func(i, j int) bool { return policies[i].name < policies[j].name })
+	var workload, namespaced, meshWide *peerAuthentication
+	for i := range policies {
+		pa := &policies[i]
+		if pa.mode == "" {
+			continue
+		}
+		switch {
+		case pa.namespace == namespace && len(pa.selector) > 0 && labelsMatch(pa.selector, podLabels):
+			if workload == nil {
+				workload = pa
+			}
+		case pa.namespace == namespace && len(pa.selector) == 0:
+			if namespaced == nil {
+				namespaced = pa
+			}
+		case pa.namespace == istioRootNamespace && len(pa.selector) == 0:
+			if meshWide == nil {
+				meshWide = pa
+			}
+		}
+	}
+	for _, pa := range []*peerAuthentication{workload, namespaced, meshWide} {
+		if pa != nil {
+			return MTLSStatus{Mode: pa.mode, Source: "PeerAuthentication " + pa.namespace + "/" + pa.name}
+		}
+	}
+	return MTLSStatus{Mode: mtlsModeDefault, Source: "Istio default"}
+}
+
+// labelsMatch reports whether labels carry every key and value of selector
+func labelsMatch(selector, labels map[string]string) bool {
+	for key, value := range selector {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+package mesh
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestResolveHost(t *testing.T) {
+	tests := []struct{ host, namespace, want string }{
+		{"reviews", "shop", "reviews.shop.svc.cluster.local"},
+		{"reviews.shop.svc", "other", "reviews.shop.svc.cluster.local"},
+		{"reviews.shop.svc.cluster.local", "other", "reviews.shop.svc.cluster.local"},
+		{"api.example.com", "shop", "api.example.com"},
+		{"*.shop.svc.cluster.local", "shop", "*.shop.svc.cluster.local"},
+	}
+	for _, tt := range tests {
+		if got := resolveHost(tt.host, tt.namespace); got != tt.want {
+			t.Errorf("resolveHost(%q, %q) = %q, want %q", tt.host, tt.namespace, got, tt.want)
+		}
+	}
+	if !hostMatches("*.shop.svc.cluster.local", "reviews.shop.svc.cluster.local") || hostMatches("*.shop.svc.cluster.local", "reviews.other.svc.cluster.local") {
+		t.Error("hostMatches() does not handle wildcards")
+	}
+}
+
+func TestTransformVirtualService(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "frontend", "namespace": "edge"},
+		"spec": map[string]interface{}{
+			"hosts":    []interface{}{"shop.example.com"},
+			"gateways": []interface{}{"edge/public"},
+			"http": []interface{}{
+				map[string]interface{}{
+					"name": "canary",
+					"match": []interface{}{map[string]interface{}{
+						"uri":     map[string]interface{}{"prefix": "/api"},
+						"headers": map[string]interface{}{"x-canary": map[string]interface{}{"exact": "1"}},
+					}},
+					"route": []interface{}{
+						map[string]interface{}{"destination": map[string]interface{}{"host": "reviews.shop.svc", "subset": "v2", "port": map[string]interface{}{"number": int64(9080)}}, "weight": int64(10)},
+						map[string]interface{}{"destination": map[string]interface{}{"host": "frontend"}, "weight": int64(90)},
+					},
+					"timeout": "5s",
+				},
+			},
+		},
+	}}
+	vs := transformVirtualService(obj)
+	if len(vs.Routes) != 1 || vs.Routes[0].Name != "canary" || vs.Routes[0].Timeout != "5s" {
+		t.Fatalf("routes = %+v", vs.Routes)
+	}
+	if match := vs.Routes[0].Match; len(match) != 1 || match[0] != "uri prefix /api, header x-canary exact 1" {
+		t.Errorf("match = %q", match)
+	}
+	dest := vs.Routes[0].Destinations[0]
+	if dest.Host != "reviews.shop.svc.cluster.local" || dest.Subset != "v2" || dest.Port != 9080 || dest.Weight != 10 {
+		t.Errorf("destination = %+v", dest)
+	}
+	if !vs.routesTo("reviews.shop.svc.cluster.local") || !vs.routesTo("frontend.edge.svc.cluster.local") || vs.routesTo("ratings.shop.svc.cluster.local") {
+		t.Error("routesTo() does not follow route destinations")
+	}
+}
+
+func TestEffectiveMTLS(t *testing.T) {
+	policies := []peerAuthentication{
+		{namespace: "istio-system", name: "default", mode: "STRICT"},
+		{namespace: "shop", name: "namespace", mode: "PERMISSIVE"},
+		{namespace: "shop", name: "legacy", selector: map[string]string{"app": "legacy"}, mode: "DISABLE"},
+		{namespace: "shop", name: "inherit", selector: map[string]string{"app": "reviews"}, mode: ""},
+	}
+	tests := []struct {
+		namespace string
+		labels    map[string]string
+		want      MTLSStatus
+	}{
+		{"shop", map[string]string{"app": "legacy"}, MTLSStatus{"DISABLE", "PeerAuthentication shop/legacy"}},
+		{"shop", map[string]string{"app": "reviews"}, MTLSStatus{"PERMISSIVE", "PeerAuthentication shop/namespace"}},
+		{"payments", map[string]string{"app": "api"}, MTLSStatus{"STRICT", "PeerAuthentication istio-system/default"}},
+	}
+	for _, tt := range tests {
+		if got := effectiveMTLS(policies, tt.namespace, tt.labels); got != tt.want {
+			t.Errorf("effectiveMTLS(%s, %v) = %+v, want %+v", tt.namespace, tt.labels, got, tt.want)
+		}
+	}
+	if got := effectiveMTLS(nil, "shop", nil); got.Mode != "PERMISSIVE" {
+		t.Errorf("effectiveMTLS() without policies = %+v, want the permissive default", got)
+	}
+}
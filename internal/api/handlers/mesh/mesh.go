@@ -0,0 +1,497 @@
+package mesh
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/handlers/metrics"
+	"github.com/Facets-cloud/kube-dash/internal/api/transformers"
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/internal/tracing"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	appsV1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	metricsTimeout = 20 * time.Second
+	defaultWindow  = 5 * time.Minute
+)
+
+// MeshHandler serves Istio and Linkerd control plane, routing, mTLS and golden metrics views
+type MeshHandler struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	prometheus    metrics.PrometheusQuerier
+	logger        *logger.Logger
+	tracingHelper *tracing.TracingHelper
+}
+
+// NewMeshHandler creates a new service mesh handler
+func NewMeshHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, prometheus metrics.PrometheusQuerier, log *logger.Logger) *MeshHandler {
+	return &MeshHandler{
+		store:         store,
+		clientFactory: clientFactory,
+		prometheus:    prometheus,
+		logger:        log,
+		tracingHelper: tracing.GetTracingHelper(),
+	}
+}
+
+// ControlPlane is an installed Istio revision or Linkerd control plane
+type ControlPlane struct {
+	Mesh      string `json:"mesh"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Revision  string `json:"revision,omitempty"`
+	Version   string `json:"version"`
+	Ready     bool   `json:"ready"`
+}
+
+// NamespaceMesh is the mesh membership of a namespace's pods
+type NamespaceMesh struct {
+	Namespace      string `json:"namespace"`
+	Mesh           string `json:"mesh,omitempty"`
+	Injection      string `json:"injection"` // enabled, disabled, ambient or unset
+	Revision       string `json:"revision,omitempty"`
+	Pods           int    `json:"pods"`
+	MeshedPods     int    `json:"meshedPods"`
+	UninjectedPods int    `json:"uninjectedPods"` // running without a sidecar although injection is enabled; restart to inject
+	SkewedPods     int    `json:"skewedPods"`     // running a proxy of another version than their control plane
+}
+
+// MeshStatus lists the control planes and the namespaces taking part in a mesh
+type MeshStatus struct {
+	ControlPlanes []ControlPlane  `json:"controlPlanes"`
+	Namespaces    []NamespaceMesh `json:"namespaces"`
+}
+
+// ServiceMesh is the mesh view of a service: its pods' data plane, mTLS and Istio routing
+type ServiceMesh struct {
+	Namespace        string                `json:"namespace"`
+	Name             string                `json:"name"`
+	Mesh             string                `json:"mesh,omitempty"` // mesh of the service's pods
+	Pods             int                   `json:"pods"`
+	MeshedPods       int                   `json:"meshedPods"`
+	SkewedPods       int                   `json:"skewedPods"`
+	MTLS             *MTLSStatus           `json:"mtls,omitempty"`
+	VirtualServices  []VirtualServiceInfo  `json:"virtualServices"`
+	DestinationRules []DestinationRuleInfo `json:"destinationRules"`
+}
+
+// clients gets the typed and dynamic clients for the request's config and cluster
+func (h *MeshHandler) clients(c *gin.Context) (kubernetes.Interface, dynamic.Interface, error) {
+	configID := c.Query("config")
+	if configID == "" {
+		return nil, nil, fmt.Errorf("config parameter is required")
+	}
+	config, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("config not found: %w", err)
+	}
+	client, err := h.clientFactory.GetClientForConfig(config, c.Query("cluster"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get Kubernetes client: %w", err)
+	}
+	dynamicClient, err := h.clientFactory.GetDynamicClientForConfig(config, c.Query("cluster"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get dynamic client: %w", err)
+	}
+	return client, dynamicClient, nil
+}
+
+// controlPlanes lists the istiod and linkerd-destination deployments
+func controlPlanes(ctx context.Context, client kubernetes.Interface) ([]appsV1.Deployment, error) {
+	var deployments []appsV1.Deployment
+	for _, selector := range []string{transformers.IstiodSelector, transformers.LinkerdDestinationSelector} {
+		list, err := client.AppsV1().Deployments("").List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return nil, err
+		}
+		deployments = append(deployments, list.Items...)
+	}
+	return deployments, nil
+}
+
+// namespaceInjection reads the sidecar injection setting of a namespace
+func namespaceInjection(ns *v1.Namespace) (mesh, injection, revision string) {
+	switch {
+	case ns.Labels["istio.io/dataplane-mode"] == "ambient":
+		return transformers.MeshIstio, "ambient", ""
+	case ns.Labels["istio-injection"] == "enabled":
+		return transformers.MeshIstio, "enabled", ""
+	case ns.Labels["istio.io/rev"] != "":
+		return transformers.MeshIstio, "enabled", ns.Labels["istio.io/rev"]
+	case ns.Labels["istio-injection"] == "disabled":
+		return transformers.MeshIstio, "disabled", ""
+	case ns.Annotations["linkerd.io/inject"] == "enabled":
+		return transformers.MeshLinkerd, "enabled", ""
+	case ns.Annotations["linkerd.io/inject"] == "disabled":
+		return transformers.MeshLinkerd, "disabled", ""
+	}
+	return "", "unset", ""
+}
+
+// injectionOptOut reports whether a pod opts out of sidecar injection or cannot take one
+func injectionOptOut(pod *v1.Pod) bool {
+	return pod.Spec.HostNetwork ||
+		pod.Labels["sidecar.istio.io/inject"] == "false" ||
+		pod.Annotations["sidecar.istio.io/inject"] == "false" ||
+		pod.Annotations["linkerd.io/inject"] == "disabled"
+}
+
+// buildMeshStatus sums up mesh membership per namespace. Namespaces are listed when injection
+// is configured on them or any of their pods is meshed.
+func buildMeshStatus(deployments []appsV1.Deployment, namespaces []v1.Namespace, pods []v1.Pod) MeshStatus {
+	status := MeshStatus{ControlPlanes: []ControlPlane{}, Namespaces: []NamespaceMesh{}}
+	versions := transformers.MeshControlPlaneVersions(deployments)
+	for _, d := range deployments {
+		cp := ControlPlane{Mesh: transformers.MeshLinkerd, Namespace: d.Namespace, Name: d.Name, Version: versions[transformers.MeshLinkerd]}
+		if d.Labels["app"] == "istiod" {
+			cp.Mesh = transformers.MeshIstio
+			cp.Revision = d.Labels["istio.io/rev"]
+			if cp.Revision == "" {
+				cp.Revision = "default"
+			}
+			cp.Version = versions[transformers.MeshIstio+"/"+cp.Revision]
+		}
+		desired := int32(1)
+		if d.Spec.Replicas != nil {
+			desired = *d.Spec.Replicas
+		}
+		cp.Ready = desired > 0 && d.Status.ReadyReplicas >= desired
+		status.ControlPlanes = append(status.ControlPlanes, cp)
+	}
+
+	byName := map[string]*NamespaceMesh{}
+	for i := range namespaces {
+		ns := &NamespaceMesh{Namespace: namespaces[i].Name}
+		ns.Mesh, ns.Injection, ns.Revision = namespaceInjection(&namespaces[i])
+		byName[ns.Namespace] = ns
+	}
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase != v1.PodRunning && pod.Status.Phase != v1.PodPending {
+			continue
+		}
+		ns, ok := byName[pod.Namespace]
+		if !ok {
+			ns = &NamespaceMesh{Namespace: pod.Namespace, Injection: "unset"}
+			byName[pod.Namespace] = ns
+		}
+		ns.Pods++
+		sidecar := transformers.PodMeshSidecar(pod)
+		if sidecar == nil {
+			if ns.Injection == "enabled" && !injectionOptOut(pod) {
+				ns.UninjectedPods++
+			}
+			continue
+		}
+		ns.MeshedPods++
+		if ns.Mesh == "" {
+			ns.Mesh = sidecar.Mesh
+		}
+		transformers.ApplyMeshVersionSkew(sidecar, versions)
+		if sidecar.VersionSkew {
+			ns.SkewedPods++
+		}
+	}
+	for _, ns := range byName {
+		if ns.Injection != "unset" || ns.MeshedPods > 0 {
+			status.Namespaces = append(status.Namespaces, *ns)
+		}
+	}
+	sort.Slice(status.Namespaces, func(i, j int) bool { return status.Namespaces[i].Namespace < status.Namespaces[j].Namespace })
+	return status
+}
+
+// GetMeshStatus lists the mesh control planes and per-namespace mesh membership
+// @Summary Get service mesh status
+// @Description Detects Istio revisions and Linkerd control planes with their versions, and for every namespace with injection configured or meshed pods counts the pods with a sidecar, the pods that still need a restart to be injected and the pods whose proxy version differs from their control plane
+// @Tags Service Mesh
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Success 200 {object} MeshStatus "Control planes and namespaces"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/mesh/status [get]
+func (h *MeshHandler) GetMeshStatus(c *gin.Context) {
+	ctx, span := h.tracingHelper.StartAuthSpan(c.Request.Context(), "mesh.status")
+	defer span.End()
+
+	client, _, err := h.clients(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	deployments, err := controlPlanes(ctx, client)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	namespaces, err := client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	pods, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{ResourceVersion: "0"})
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	h.tracingHelper.RecordSuccess(span, fmt.Sprintf("Checked %d pods", len(pods.Items)))
+	c.JSON(http.StatusOK, buildMeshStatus(deployments, namespaces.Items, pods.Items))
+}
+
+// servicePods returns a service and the pods it selects
+func servicePods(ctx context.Context, client kubernetes.Interface, namespace, name string) (*v1.Service, []v1.Pod, error) {
+	svc, err := client.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(svc.Spec.Selector) == 0 {
+		return svc, nil, nil
+	}
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: labels.SelectorFromSet(svc.Spec.Selector).String()})
+	if err != nil {
+		return nil, nil, err
+	}
+	return svc, pods.Items, nil
+}
+
+// listIstio lists an Istio resource in all namespaces; a missing CRD means Istio is not installed
+func listIstio(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource) ([]unstructured.Unstructured, error) {
+	list, err := client.Resource(gvr).List(ctx, metav1.ListOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// GetServiceMesh returns the mesh view of a service
+// @Summary Get the service mesh view of a service
+// @Description Returns whether the service's pods are meshed and run the current proxy version, their effective inbound mTLS mode (from Istio PeerAuthentications, or automatic for Linkerd), and the Istio VirtualServices and DestinationRules that serve or route to the service
+// @Tags Service Mesh
+// @Produce json
+// @Param namespace path string true "Namespace name"
+// @Param name path string true "Service name"
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Success 200 {object} ServiceMesh "Mesh view of the service"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 404 {object} utils.ErrorResponse "Service not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/mesh/services/{namespace}/{name} [get]
+func (h *MeshHandler) GetServiceMesh(c *gin.Context) {
+	ctx, span := h.tracingHelper.StartAuthSpan(c.Request.Context(), "mesh.service")
+	defer span.End()
+
+	namespace, name := c.Param("namespace"), c.Param("name")
+	client, dynamicClient, err := h.clients(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	_, pods, err := servicePods(ctx, client, namespace, name)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	result := ServiceMesh{Namespace: namespace, Name: name, VirtualServices: []VirtualServiceInfo{}, DestinationRules: []DestinationRuleInfo{}}
+	var versions map[string]string
+	var podLabels map[string]string
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase != v1.PodRunning {
+			continue
+		}
+		result.Pods++
+		if podLabels == nil {
+			podLabels = pod.Labels
+		}
+		sidecar := transformers.PodMeshSidecar(pod)
+		if sidecar == nil {
+			continue
+		}
+		result.MeshedPods++
+		result.Mesh = sidecar.Mesh
+		if versions == nil {
+			deployments, _ := controlPlanes(ctx, client)
+			versions = transformers.MeshControlPlaneVersions(deployments)
+		}
+		transformers.ApplyMeshVersionSkew(sidecar, versions)
+		if sidecar.VersionSkew {
+			result.SkewedPods++
+		}
+	}
+
+	if result.Mesh == transformers.MeshLinkerd {
+		mode := "AUTOMATIC"
+		if result.MeshedPods < result.Pods {
+			mode = "PARTIAL"
+		}
+		result.MTLS = &MTLSStatus{Mode: mode, Source: "Linkerd proxies encrypt traffic between meshed pods"}
+	}
+
+	host := serviceHost(namespace, name)
+	virtualServices, err := listIstio(ctx, dynamicClient, virtualServiceGVR)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	for i := range virtualServices {
+		vs := transformVirtualService(&virtualServices[i])
+		if vs.routesTo(host) {
+			result.VirtualServices = append(result.VirtualServices, vs)
+		}
+	}
+	destinationRules, err := listIstio(ctx, dynamicClient, destinationRuleGVR)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	for i := range destinationRules {
+		dr := transformDestinationRule(&destinationRules[i])
+		if hostMatches(dr.Host, host) {
+			result.DestinationRules = append(result.DestinationRules, dr)
+		}
+	}
+	if result.Mesh == transformers.MeshIstio {
+		peerAuthentications, err := listIstio(ctx, dynamicClient, peerAuthenticationGVR)
+		if err != nil {
+			utils.RespondError(c, http.StatusInternalServerError, err)
+			return
+		}
+		policies := make([]peerAuthentication, 0, len(peerAuthentications))
+		for i := range peerAuthentications {
+			policies = append(policies, transformPeerAuthentication(&peerAuthentications[i]))
+		}
+		mtls := effectiveMTLS(policies, namespace, podLabels)
+		result.MTLS = &mtls
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetServiceGoldenMetrics returns a service's golden metrics per route
+// @Summary Get service mesh golden metrics
+// @Description Returns the inbound request rate, success rate and p50/p95/p99 latency of a meshed service from Prometheus, like linkerd viz routes: per ServiceProfile route for Linkerd (unmatched requests under [DEFAULT]) and for the whole service under [ALL] for Istio, whose standard metrics carry no route
+// @Tags Service Mesh
+// @Produce json
+// @Param namespace path string true "Namespace name"
+// @Param name path string true "Service name"
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param mesh query string false "istio or linkerd; detected from the service's pods by default"
+// @Param window query string false "Rate window such as 5m or 1h (default 5m)"
+// @Success 200 {object} GoldenMetrics "Golden metrics per route"
+// @Failure 400 {object} utils.ErrorResponse "Bad request or the service is not meshed"
+// @Failure 404 {object} utils.ErrorResponse "Service not found"
+// @Failure 502 {object} utils.ErrorResponse "Prometheus query failed"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/mesh/services/{namespace}/{name}/metrics [get]
+func (h *MeshHandler) GetServiceGoldenMetrics(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), metricsTimeout)
+	defer cancel()
+
+	namespace, name := c.Param("namespace"), c.Param("name")
+	window := defaultWindow
+	if v := c.Query("window"); v != "" {
+		parsed, err := time.ParseDuration(v)
+		if err != nil || parsed < time.Minute || parsed > 24*time.Hour {
+			utils.RespondErrorMessage(c, http.StatusBadRequest, "window must be a duration between 1m and 24h")
+			return
+		}
+		window = parsed
+	}
+	client, _, err := h.clients(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	mesh := c.Query("mesh")
+	if mesh == "" {
+		_, pods, err := servicePods(ctx, client, namespace, name)
+		if err != nil {
+			utils.RespondError(c, http.StatusInternalServerError, err)
+			return
+		}
+		for i := range pods {
+			if sidecar := transformers.PodMeshSidecar(&pods[i]); sidecar != nil {
+				mesh = sidecar.Mesh
+				break
+			}
+		}
+	}
+	if mesh != transformers.MeshIstio && mesh != transformers.MeshLinkerd {
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "service is not part of an Istio or Linkerd mesh")
+		return
+	}
+
+	windowText := strconv.Itoa(int(window.Seconds())) + "s"
+	queries := newGoldenQueries(mesh, namespace, name, windowText)
+	targetKey := c.Query("config") + "|" + c.Query("cluster")
+	run := func(query string) (map[string]float64, error) {
+		raw, err := h.prometheus.Query(ctx, client, targetKey, "/api/v1/query", map[string]string{"query": query})
+		if err != nil {
+			return nil, err
+		}
+		return parseVector(raw, queries.routeLabel)
+	}
+
+	requests, err := run(queries.requests)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadGateway, err)
+		return
+	}
+	successes, err := run(queries.successes)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadGateway, err)
+		return
+	}
+	responses, err := run(queries.responses)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadGateway, err)
+		return
+	}
+	quantiles := map[float64]map[string]float64{}
+	for quantile, query := range queries.quantiles {
+		if quantiles[quantile], err = run(query); err != nil {
+			utils.RespondError(c, http.StatusBadGateway, err)
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, GoldenMetrics{
+		Namespace: namespace,
+		Service:   name,
+		Mesh:      mesh,
+		Window:    window.String(),
+		Routes:    buildRouteMetrics(requests, successes, responses, quantiles),
+	})
+}
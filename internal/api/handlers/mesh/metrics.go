@@ -0,0 +1,165 @@
+package mesh
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/transformers"
+)
+
+const (
+	// defaultRouteName labels Linkerd requests that match no ServiceProfile route, as the CLI does
+	defaultRouteName = "[DEFAULT]"
+	// allRoutesName labels Istio traffic, which its standard metrics do not break down by route
+	allRoutesName = "[ALL]"
+)
+
+// RouteMetrics are the golden metrics of one route of a service
+type RouteMetrics struct {
+	Route        string   `json:"route"`
+	RequestRate  float64  `json:"requestRate"`            // requests per second
+	SuccessRate  *float64 `json:"successRate,omitempty"`  // fraction of successful responses, nil without responses
+	LatencyP50Ms *float64 `json:"latencyP50Ms,omitempty"` // response latency percentiles in milliseconds
+	LatencyP95Ms *float64 `json:"latencyP95Ms,omitempty"`
+	LatencyP99Ms *float64 `json:"latencyP99Ms,omitempty"`
+}
+
+// GoldenMetrics are a service's inbound request rate, success rate and latency per route
+type GoldenMetrics struct {
+	Namespace string         `json:"namespace"`
+	Service   string         `json:"service"`
+	Mesh      string         `json:"mesh"`
+	Window    string         `json:"window"`
+	Routes    []RouteMetrics `json:"routes"`
+}
+
+// goldenQueries are the PromQL queries behind the golden metrics, all grouped by route label
+type goldenQueries struct {
+	routeLabel string // empty when the mesh's metrics carry no route
+	requests   string
+	successes  string
+	responses  string
+	quantiles  map[float64]string
+}
+
+// newGoldenQueries builds the inbound golden metric queries of a service: Linkerd route metrics
+// from ServiceProfiles, or Istio's standard metrics reported by the destination proxies
+func newGoldenQueries(mesh, namespace, service, window string) goldenQueries {
+	var q goldenQueries
+	var requestMetric, responseMetric, latencyMetric, selector, success string
+	if mesh == transformers.MeshLinkerd {
+		q.routeLabel = "rt_route"
+		requestMetric, responseMetric, latencyMetric = "route_request_total", "route_response_total", "route_response_latency_ms_bucket"
+		selector = fmt.Sprintf(`direction="inbound",namespace=%q,dst=~%q`, namespace, regexp.QuoteMeta(serviceHost(namespace, service))+"(:.*)?")
+		success = `classification="success"`
+	} else {
+		requestMetric, responseMetric, latencyMetric = "istio_requests_total", "istio_requests_total", "istio_request_duration_milliseconds_bucket"
+		selector = fmt.Sprintf(`reporter="destination",destination_service_namespace=%q,destination_service_name=%q`, namespace, service)
+		success = `response_code!~"5.."`
+	}
+
+	by := func(labels ...string) string {
+		if q.routeLabel != "" {
+			labels = append(labels, q.routeLabel)
+		}
+		return "sum by (" + strings.Join(labels, ", ") + ")"
+	}
+	rate := func(metric, extra string) string {
+		sel := selector
+		if extra != "" {
+			sel += "," + extra
+		}
+		return fmt.Sprintf("rate(%s{%s}[%s])", metric, sel, window)
+	}
+	q.requests = fmt.Sprintf("%s (%s)", by(), rate(requestMetric, ""))
+	q.successes = fmt.Sprintf("%s (%s)", by(), rate(responseMetric, success))
+	q.responses = fmt.Sprintf("%s (%s)", by(), rate(responseMetric, ""))
+	q.quantiles = map[float64]string{}
+	for _, quantile := range []float64{0.5, 0.95, 0.99} {
+		q.quantiles[quantile] = fmt.Sprintf("histogram_quantile(%g, %s (%s))", quantile, by("le"), rate(latencyMetric, ""))
+	}
+	return q
+}
+
+// promVector is the response of an instant Prometheus query
+type promVector struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		Result []struct {
+			Metric map[string]string `json:"metric"`
+			Value  [2]interface{}    `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// parseVector maps the route label of an instant query result to its value, leaving out NaN
+// samples such as quantiles of routes without traffic
+func parseVector(raw []byte, routeLabel string) (map[string]float64, error) {
+	var vector promVector
+	if err := json.Unmarshal(raw, &vector); err != nil {
+		return nil, fmt.Errorf("invalid Prometheus response: %w", err)
+	}
+	if vector.Status != "success" {
+		return nil, fmt.Errorf("prometheus query failed: %s", vector.Error)
+	}
+	values := map[string]float64{}
+	for _, sample := range vector.Data.Result {
+		text, _ := sample.Value[1].(string)
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			continue
+		}
+		values[routeName(sample.Metric, routeLabel)] = value
+	}
+	return values, nil
+}
+
+func routeName(metric map[string]string, routeLabel string) string {
+	if routeLabel == "" {
+		return allRoutesName
+	}
+	if route := metric[routeLabel]; route != "" {
+		return route
+	}
+	return defaultRouteName
+}
+
+// buildRouteMetrics combines the query results into per-route metrics, busiest routes first
+func buildRouteMetrics(requests, successes, responses map[string]float64, quantiles map[float64]map[string]float64) []RouteMetrics {
+	routeSet := map[string]bool{}
+	for route := range requests {
+		routeSet[route] = true
+	}
+	for route := range responses {
+		routeSet[route] = true
+	}
+	routes := make([]RouteMetrics, 0, len(routeSet))
+	for route := range routeSet {
+		rm := RouteMetrics{Route: route, RequestRate: requests[route]}
+		if total := responses[route]; total > 0 {
+			rate := successes[route] / total
+			rm.SuccessRate = &rate
+		}
+		pick := func(q float64) *float64 {
+			if v, ok := quantiles[q][route]; ok {
+				return &v
+			}
+			return nil
+		}
+		rm.LatencyP50Ms, rm.LatencyP95Ms, rm.LatencyP99Ms = pick(0.5), pick(0.95), pick(0.99)
+		routes = append(routes, rm)
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].RequestRate != routes[j].RequestRate {
+			return routes[i].RequestRate > routes[j].RequestRate
+		}
+		return routes[i].Route < routes[j].Route
+	})
+	return routes
+}
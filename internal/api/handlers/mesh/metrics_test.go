@@ -0,0 +1,55 @@
+package mesh
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewGoldenQueries(t *testing.T) {
+	q := newGoldenQueries("linkerd", "shop", "reviews", "300s")
+	want := `sum by (rt_route) (rate(route_request_total{direction="inbound",namespace="shop",dst=~"reviews\\.shop\\.svc\\.cluster\\.local(:.*)?"}[300s]))`
+	if q.requests != want {
+		t.Errorf("linkerd requests query = %s\nwant %s", q.requests, want)
+	}
+	if !strings.HasPrefix(q.quantiles[0.95], "histogram_quantile(0.95, sum by (le, rt_route) (rate(route_response_latency_ms_bucket{") {
+		t.Errorf("linkerd p95 query = %s", q.quantiles[0.95])
+	}
+
+	q = newGoldenQueries("istio", "shop", "reviews", "300s")
+	if q.routeLabel != "" || !strings.Contains(q.successes, `response_code!~"5.."`) || !strings.HasPrefix(q.requests, "sum by () (rate(istio_requests_total{") {
+		t.Errorf("istio queries = %+v", q)
+	}
+}
+
+func TestBuildRouteMetrics(t *testing.T) {
+	requests, err := parseVector([]byte(`{"status":"success","data":{"resultType":"vector","result":[
+		{"metric":{"rt_route":"GET /books"},"value":[1700000000,"12.5"]},
+		{"metric":{},"value":[1700000000,"0.5"]}]}}`), "rt_route")
+	if err != nil {
+		t.Fatal(err)
+	}
+	responses := map[string]float64{"GET /books": 12.5, defaultRouteName: 0.5}
+	successes := map[string]float64{"GET /books": 10}
+	p95, err := parseVector([]byte(`{"status":"success","data":{"resultType":"vector","result":[
+		{"metric":{"rt_route":"GET /books"},"value":[1700000000,"42"]},
+		{"metric":{},"value":[1700000000,"NaN"]}]}}`), "rt_route")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	routes := buildRouteMetrics(requests, successes, responses, map[float64]map[string]float64{0.95: p95})
+	if len(routes) != 2 || routes[0].Route != "GET /books" || routes[1].Route != defaultRouteName {
+		t.Fatalf("routes = %+v", routes)
+	}
+	books := routes[0]
+	if books.RequestRate != 12.5 || books.SuccessRate == nil || *books.SuccessRate != 0.8 || books.LatencyP95Ms == nil || *books.LatencyP95Ms != 42 {
+		t.Errorf("books route = %+v", books)
+	}
+	if routes[1].SuccessRate == nil || *routes[1].SuccessRate != 0 || routes[1].LatencyP95Ms != nil {
+		t.Errorf("default route = %+v, want 0%% success and no latency", routes[1])
+	}
+
+	if _, err := parseVector([]byte(`{"status":"error","error":"parse error"}`), ""); err == nil {
+		t.Error("parseVector() accepted a failed query")
+	}
+}
@@ -0,0 +1,288 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/dynamic"
+)
+
+// metadataPatchConcurrency bounds the patches a bulk metadata edit issues at once
+const metadataPatchConcurrency = 8
+
+// MetadataOperations adds and removes keys of a label or annotation map
+type MetadataOperations struct {
+	Add    map[string]string `json:"add,omitempty"`
+	Remove []string          `json:"remove,omitempty"`
+}
+
+// BulkMetadataRequest selects objects of one kind and edits their labels and annotations
+type BulkMetadataRequest struct {
+	Namespaces  []string           `json:"namespaces,omitempty"` // namespaced kinds only; empty selects all namespaces
+	Selector    string             `json:"selector,omitempty"`   // label selector, e.g. app.kubernetes.io/part-of=shop
+	Labels      MetadataOperations `json:"labels"`
+	Annotations MetadataOperations `json:"annotations"`
+	DryRun      bool               `json:"dryRun"`
+}
+
+// MetadataEditResult reports the outcome of a bulk metadata edit on one object
+type MetadataEditResult struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Status    string `json:"status"` // patched, unchanged or failed
+	Error     string `json:"error,omitempty"`
+}
+
+// BulkMetadataResponse summarises a bulk metadata edit
+type BulkMetadataResponse struct {
+	DryRun    bool                 `json:"dryRun"`
+	Matched   int                  `json:"matched"`
+	Patched   int                  `json:"patched"`
+	Unchanged int                  `json:"unchanged"`
+	Failed    int                  `json:"failed"`
+	Results   []MetadataEditResult `json:"results"`
+}
+
+// validate checks the operations are well-formed label or annotation edits
+func (ops MetadataOperations) validate(field string, isLabel bool) error {
+	for key, value := range ops.Add {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("%s key %q: %s", field, key, strings.Join(errs, "; "))
+		}
+		if isLabel {
+			if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+				return fmt.Errorf("%s value %q: %s", field, value, strings.Join(errs, "; "))
+			}
+		}
+	}
+	for _, key := range ops.Remove {
+		if _, ok := ops.Add[key]; ok {
+			return fmt.Errorf("%s key %q is both added and removed", field, key)
+		}
+	}
+	return nil
+}
+
+// changes returns the map patch the operations make to current, or nil when nothing changes.
+// Removed keys map to nil so the merge patch deletes them.
+func (ops MetadataOperations) changes(current map[string]string) map[string]interface{} {
+	patch := map[string]interface{}{}
+	for key, value := range ops.Add {
+		if existing, ok := current[key]; !ok || existing != value {
+			patch[key] = value
+		}
+	}
+	for _, key := range ops.Remove {
+		if _, ok := current[key]; ok {
+			patch[key] = nil
+		}
+	}
+	if len(patch) == 0 {
+		return nil
+	}
+	return patch
+}
+
+// buildMetadataPatch returns the merge patch that applies req to obj, or nil when obj already matches
+func buildMetadataPatch(obj *unstructured.Unstructured, req *BulkMetadataRequest) ([]byte, error) {
+	metadata := map[string]interface{}{}
+	if patch := req.Labels.changes(obj.GetLabels()); patch != nil {
+		metadata["labels"] = patch
+	}
+	if patch := req.Annotations.changes(obj.GetAnnotations()); patch != nil {
+		metadata["annotations"] = patch
+	}
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(map[string]interface{}{"metadata": metadata})
+}
+
+// BulkEditMetadata adds and removes labels and annotations on every object of a kind matching a selector
+// @Summary Bulk edit labels and annotations
+// @Description Lists objects of the resource kind matching the label selector (optionally within namespaces) and patches their labels and annotations concurrently, reporting a result per object. Objects that already match are left untouched. Custom resources take group, version and resource query parameters.
+// @Tags Resources
+// @Accept json
+// @Produce json
+// @Param resourcekind path string true "Resource kind, e.g. deployments, or customresources"
+// @Param config query string true "Kubeconfig ID"
+// @Param cluster query string false "Cluster name"
+// @Param group query string false "Custom resource group"
+// @Param version query string false "Custom resource version"
+// @Param resource query string false "Custom resource plural"
+// @Param request body BulkMetadataRequest true "Selection and label/annotation operations"
+// @Success 200 {object} BulkMetadataResponse
+// @Failure 400 {object} map[string]string "Bad request - invalid selector or operations"
+// @Failure 422 {object} BulkMetadataResponse "One or more objects failed to patch"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/bulk/{resourcekind}/metadata [patch]
+func (h *ResourcesHandler) BulkEditMetadata(c *gin.Context) {
+	resourceKind := c.Param("resourcekind")
+
+	var req BulkMetadataRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if len(req.Labels.Add)+len(req.Labels.Remove)+len(req.Annotations.Add)+len(req.Annotations.Remove) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no label or annotation operations provided"})
+		return
+	}
+	if err := req.Labels.validate("label", true); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := req.Annotations.validate("annotation", false); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	selector, err := labels.Parse(req.Selector)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid selector: " + err.Error()})
+		return
+	}
+
+	// Resolve the kind; strategic merge is only understood by built-in types
+	var gvr schema.GroupVersionResource
+	var namespaced bool
+	patchType := types.StrategicMergePatchType
+	switch resourceKind {
+	case "helmreleases":
+		c.JSON(http.StatusBadRequest, gin.H{"error": "helm releases do not support metadata editing"})
+		return
+	case "customresources":
+		group, version, resource := c.Query("group"), c.Query("version"), c.Query("resource")
+		if version == "" || resource == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "version and resource query params are required for customresources"})
+			return
+		}
+		gvr = schema.GroupVersionResource{Group: group, Version: version, Resource: resource}
+		namespaced = len(req.Namespaces) > 0
+		patchType = types.MergePatchType
+	default:
+		mapping, ok := resourceMapping[resourceKind]
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unsupported resource kind: %s", resourceKind)})
+			return
+		}
+		gvr = mapping.GVR
+		namespaced = mapping.Namespaced
+		if !namespaced && len(req.Namespaces) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s are cluster-scoped and cannot be selected by namespace", resourceKind)})
+			return
+		}
+	}
+	// Refuse to tag every object of a kind in the cluster by accident
+	if selector.Empty() && len(req.Namespaces) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a selector or namespaces are required"})
+		return
+	}
+
+	dynamicClient, err := h.getDynamicClient(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	var namespaces []string
+	if namespaced {
+		namespaces = req.Namespaces
+	}
+	objects, err := utils.ListInNamespaces(ctx, namespaces, func(ctx context.Context, namespace string) ([]unstructured.Unstructured, error) {
+		list, err := dynamicClient.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to list %s: %v", resourceKind, err)})
+		return
+	}
+
+	resp := patchMetadata(ctx, dynamicClient.Resource(gvr), objects, &req, patchType)
+
+	h.logger.WithField("resource_kind", resourceKind).
+		WithField("selector", selector.String()).
+		WithField("dryRun", req.DryRun).
+		Infof("Bulk edited metadata: %d matched, %d patched, %d failed", resp.Matched, resp.Patched, resp.Failed)
+
+	status := http.StatusOK
+	if resp.Failed > 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, resp)
+}
+
+// patchMetadata patches the objects concurrently and collects a result per object, sorted by namespace and name
+func patchMetadata(ctx context.Context, client dynamic.NamespaceableResourceInterface, objects []unstructured.Unstructured, req *BulkMetadataRequest, patchType types.PatchType) BulkMetadataResponse {
+	results := make([]MetadataEditResult, len(objects))
+	options := metav1.PatchOptions{}
+	if req.DryRun {
+		options.DryRun = []string{metav1.DryRunAll}
+	}
+
+	sem := make(chan struct{}, metadataPatchConcurrency)
+	var wg sync.WaitGroup
+	for i := range objects {
+		obj := &objects[i]
+		results[i] = MetadataEditResult{Name: obj.GetName(), Namespace: obj.GetNamespace()}
+		patch, err := buildMetadataPatch(obj, req)
+		if err != nil {
+			results[i].Status, results[i].Error = "failed", err.Error()
+			continue
+		}
+		if patch == nil {
+			results[i].Status = "unchanged"
+			continue
+		}
+
+		wg.Add(1)
+		go func(result *MetadataEditResult, patch []byte) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if _, err := client.Namespace(result.Namespace).Patch(ctx, result.Name, patchType, patch, options); err != nil {
+				result.Status, result.Error = "failed", err.Error()
+				return
+			}
+			result.Status = "patched"
+		}(&results[i], patch)
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Namespace != results[j].Namespace {
+			return results[i].Namespace < results[j].Namespace
+		}
+		return results[i].Name < results[j].Name
+	})
+	resp := BulkMetadataResponse{DryRun: req.DryRun, Matched: len(results), Results: results}
+	for _, result := range results {
+		switch result.Status {
+		case "patched":
+			resp.Patched++
+		case "unchanged":
+			resp.Unchanged++
+		default:
+			resp.Failed++
+		}
+	}
+	return resp
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestBuildMetadataPatch(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetLabels(map[string]string{"team": "payments", "legacy": "true"})
+	obj.SetAnnotations(map[string]string{"example.com/cost-center": "cc-1"})
+
+	req := &BulkMetadataRequest{
+		Labels:      MetadataOperations{Add: map[string]string{"team": "payments", "tier": "backend"}, Remove: []string{"legacy", "missing"}},
+		Annotations: MetadataOperations{Add: map[string]string{"example.com/cost-center": "cc-1"}},
+	}
+	patch, err := buildMetadataPatch(obj, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"metadata":{"labels":{"legacy":null,"tier":"backend"}}}`; string(patch) != want {
+		t.Errorf("got %s, want %s", patch, want)
+	}
+
+	// An object that already matches needs no patch
+	obj.SetLabels(map[string]string{"team": "payments", "tier": "backend"})
+	if patch, _ := buildMetadataPatch(obj, req); patch != nil {
+		t.Errorf("expected no patch, got %s", patch)
+	}
+}
+
+func TestMetadataOperationsValidate(t *testing.T) {
+	cases := []struct {
+		ops     MetadataOperations
+		isLabel bool
+		valid   bool
+	}{
+		{MetadataOperations{Add: map[string]string{"example.com/team": "payments"}}, true, true},
+		{MetadataOperations{Add: map[string]string{"bad key!": "x"}}, true, false},
+		{MetadataOperations{Add: map[string]string{"team": "has spaces"}}, true, false},
+		{MetadataOperations{Add: map[string]string{"note": "has spaces"}}, false, true},
+		{MetadataOperations{Add: map[string]string{"team": "a"}, Remove: []string{"team"}}, true, false},
+	}
+	for i, tc := range cases {
+		if err := tc.ops.validate("label", tc.isLabel); (err == nil) != tc.valid {
+			t.Errorf("case %d: valid=%v, err=%v", i, tc.valid, err)
+		}
+	}
+}
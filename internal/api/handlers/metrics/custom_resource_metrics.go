@@ -0,0 +1,301 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// customMetricsAnnotation declares PromQL panel templates on a CRD (for all of its resources) or on a single custom resource
+const customMetricsAnnotation = "kube-dash.io/metrics"
+
+// MetricPanelTemplate is one panel declared in the kube-dash.io/metrics annotation. The annotation is a JSON
+// object keyed by panel title whose values are either a PromQL template string or an object with query, unit
+// and legend. Templates are Go templates over .Name, .Namespace, .Kind and .Labels, with every value escaped
+// for use inside a PromQL label matcher; legends substitute series labels written as {{label}}.
+type MetricPanelTemplate struct {
+	Query  string `json:"query"`
+	Unit   string `json:"unit,omitempty"`
+	Legend string `json:"legend,omitempty"`
+}
+
+// UnmarshalJSON accepts either a bare query string or a panel object
+func (p *MetricPanelTemplate) UnmarshalJSON(data []byte) error {
+	var query string
+	if err := json.Unmarshal(data, &query); err == nil {
+		p.Query = query
+		return nil
+	}
+	type panel MetricPanelTemplate
+	var full panel
+	if err := json.Unmarshal(data, &full); err != nil {
+		return err
+	}
+	*p = MetricPanelTemplate(full)
+	return nil
+}
+
+// CustomMetricPanel is a resolved panel with its range-query series
+type CustomMetricPanel struct {
+	Title  string   `json:"title"`
+	Source string   `json:"source"` // crd or resource, whichever annotation declared the panel
+	Query  string   `json:"query,omitempty"`
+	Unit   string   `json:"unit,omitempty"`
+	Legend string   `json:"legend,omitempty"`
+	Series []series `json:"series"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// CustomResourceMetricsResponse holds the metrics panels of a custom resource
+type CustomResourceMetricsResponse struct {
+	Panels []CustomMetricPanel `json:"panels"`
+}
+
+type metricTemplateData struct {
+	Name      string
+	Namespace string
+	Kind      string
+	Labels    map[string]string
+}
+
+var legendLabelPattern = regexp.MustCompile(`\{\{\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*\}\}`)
+
+// parseMetricPanels parses a kube-dash.io/metrics annotation value
+func parseMetricPanels(value string) (map[string]MetricPanelTemplate, error) {
+	panels := map[string]MetricPanelTemplate{}
+	if strings.TrimSpace(value) == "" {
+		return panels, nil
+	}
+	if err := json.Unmarshal([]byte(value), &panels); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", customMetricsAnnotation, err)
+	}
+	for title, panel := range panels {
+		if strings.TrimSpace(panel.Query) == "" {
+			return nil, fmt.Errorf("invalid %s annotation: panel %q has no query", customMetricsAnnotation, title)
+		}
+	}
+	return panels, nil
+}
+
+// renderPanelQuery resolves a panel query template against the custom resource
+func renderPanelQuery(query string, obj *unstructured.Unstructured) (string, error) {
+	tmpl, err := template.New("query").Option("missingkey=error").Parse(query)
+	if err != nil {
+		return "", err
+	}
+	labels := map[string]string{}
+	for k, v := range obj.GetLabels() {
+		labels[k] = escapeLabelValue(v)
+	}
+	data := metricTemplateData{
+		Name:      escapeLabelValue(obj.GetName()),
+		Namespace: escapeLabelValue(obj.GetNamespace()),
+		Kind:      escapeLabelValue(obj.GetKind()),
+		Labels:    labels,
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// parsePanelMatrix converts a range query result into series named by the panel legend, the series labels, or the panel title
+func parsePanelMatrix(raw []byte, title, legend string) ([]series, error) {
+	var resp promQueryRangeResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Status != "success" {
+		return nil, fmt.Errorf("prometheus query failed")
+	}
+	parsed, err := parseMatrix(raw)
+	if err != nil {
+		return nil, err
+	}
+	for i, r := range resp.Data.Result {
+		parsed[i].Metric = seriesName(r.Metric, title, legend)
+	}
+	return parsed, nil
+}
+
+func seriesName(metric map[string]string, title, legend string) string {
+	if legend != "" {
+		return legendLabelPattern.ReplaceAllStringFunc(legend, func(m string) string {
+			return metric[legendLabelPattern.FindStringSubmatch(m)[1]]
+		})
+	}
+	keys := make([]string, 0, len(metric))
+	for k := range metric {
+		if k != "__name__" {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return title
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", k, metric[k]))
+	}
+	return strings.Join(pairs, ",")
+}
+
+// resolvePanels merges the panels declared on the CRD with those on the resource, the resource winning on title clashes
+func resolvePanels(crd, obj *unstructured.Unstructured) ([]CustomMetricPanel, error) {
+	declared := map[string]CustomMetricPanel{}
+	templates := map[string]MetricPanelTemplate{}
+	for _, source := range []struct {
+		name string
+		obj  *unstructured.Unstructured
+	}{{"crd", crd}, {"resource", obj}} {
+		if source.obj == nil {
+			continue
+		}
+		panels, err := parseMetricPanels(source.obj.GetAnnotations()[customMetricsAnnotation])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", source.name, err)
+		}
+		for title, panel := range panels {
+			templates[title] = panel
+			declared[title] = CustomMetricPanel{Title: title, Source: source.name, Unit: panel.Unit, Legend: panel.Legend}
+		}
+	}
+
+	result := make([]CustomMetricPanel, 0, len(declared))
+	for title, panel := range declared {
+		query, err := renderPanelQuery(templates[title].Query, obj)
+		if err != nil {
+			panel.Error = fmt.Sprintf("failed to resolve query template: %v", err)
+		}
+		panel.Query = query
+		panel.Series = []series{}
+		result = append(result, panel)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Title < result[j].Title })
+	return result, nil
+}
+
+// GetCustomResourceMetrics returns the metrics panels declared for a custom resource
+// @Summary Get custom resource metrics
+// @Description Resolves the PromQL templates declared in the kube-dash.io/metrics annotation of the resource's CRD and of the resource itself, and returns the range-query series of each panel. The annotation is a JSON object keyed by panel title whose values are a query template or an object with query, unit and legend; templates can use .Name, .Namespace, .Kind and .Labels. Resources without declared panels return an empty list.
+// @Tags Metrics
+// @Produce json
+// @Param namespace path string false "Namespace name (omitted for cluster-scoped resources)"
+// @Param name path string true "Custom resource name"
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name"
+// @Param group query string true "API group"
+// @Param version query string true "API version"
+// @Param resource query string true "Resource plural name"
+// @Param range query string false "Time range for metrics" default(1h)
+// @Param step query string false "Step interval for metrics" default(60s)
+// @Success 200 {object} CustomResourceMetricsResponse "Resolved panels with series"
+// @Failure 400 {object} map[string]string "Bad request or invalid annotation"
+// @Failure 404 {object} map[string]string "Custom resource or Prometheus not found"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/metrics/customresources/{namespace}/{name} [get]
+// @Router /api/v1/metrics/customresource/{name} [get]
+// @Router /api/v2/metrics/customresources/{namespace}/{name} [get]
+// @Router /api/v2/metrics/customresource/{name} [get]
+func (h *PrometheusHandler) GetCustomResourceMetrics(c *gin.Context) {
+	client, err := h.getClient(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	group, version, resource := c.Query("group"), c.Query("version"), c.Query("resource")
+	if version == "" || resource == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "version and resource parameters are required"})
+		return
+	}
+	cfg, err := h.store.GetKubeConfig(c.Query("config"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("config not found: %v", err)})
+		return
+	}
+	dynamicClient, err := h.clientFactory.GetDynamicClientForConfig(cfg, c.Query("cluster"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to get dynamic client: %v", err)})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 20*time.Second)
+	defer cancel()
+
+	gvr := schema.GroupVersionResource{Group: group, Version: version, Resource: resource}
+	namespace := c.Param("namespace")
+	var obj *unstructured.Unstructured
+	if namespace != "" {
+		obj, err = dynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, c.Param("name"), metav1.GetOptions{})
+	} else {
+		obj, err = dynamicClient.Resource(gvr).Get(ctx, c.Param("name"), metav1.GetOptions{})
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("failed to get custom resource: %v", err)})
+		return
+	}
+
+	// Core and aggregated API resources have no CRD; only the resource's own annotation applies then
+	crdGVR := schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	crd, err := dynamicClient.Resource(crdGVR).Get(ctx, fmt.Sprintf("%s.%s", resource, group), metav1.GetOptions{})
+	if err != nil {
+		crd = nil
+	}
+
+	panels, err := resolvePanels(crd, obj)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(panels) == 0 {
+		c.JSON(http.StatusOK, CustomResourceMetricsResponse{Panels: panels})
+		return
+	}
+
+	discoveryCtx, discoveryCancel := context.WithTimeout(ctx, 4*time.Second)
+	defer discoveryCancel()
+	target, err := h.discoverPrometheus(discoveryCtx, client)
+	if err != nil || target == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "prometheus not available"})
+		return
+	}
+
+	now := time.Now()
+	params := map[string]string{
+		"start": fmt.Sprintf("%d", now.Add(-parsePromRange(c.DefaultQuery("range", "1h"))).Unix()),
+		"end":   fmt.Sprintf("%d", now.Unix()),
+		"step":  c.DefaultQuery("step", "60s"),
+	}
+	for i := range panels {
+		if panels[i].Error != "" {
+			continue
+		}
+		params["query"] = panels[i].Query
+		raw, err := h.proxyPrometheus(ctx, client, target, "/api/v1/query_range", params)
+		if err != nil {
+			panels[i].Error = err.Error()
+			continue
+		}
+		parsed, err := parsePanelMatrix(raw, panels[i].Title, panels[i].Legend)
+		if err != nil {
+			panels[i].Error = err.Error()
+			continue
+		}
+		panels[i].Series = parsed
+	}
+	c.JSON(http.StatusOK, CustomResourceMetricsResponse{Panels: panels})
+}
@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func annotated(kind, namespace, name string, labels map[string]string, metricsAnnotation string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetLabels(labels)
+	if metricsAnnotation != "" {
+		obj.SetAnnotations(map[string]string{customMetricsAnnotation: metricsAnnotation})
+	}
+	return obj
+}
+
+func TestResolvePanels(t *testing.T) {
+	crd := annotated("CustomResourceDefinition", "", "queues.example.com", nil, `{
+		"Depth": {"query": "sum(queue_depth{namespace=\"{{.Namespace}}\",queue=\"{{.Name}}\"})", "unit": "messages"},
+		"Consumers": "sum(queue_consumers{queue=\"{{.Name}}\"})"
+	}`)
+	obj := annotated("Queue", "shop", `orders"x`, map[string]string{"team": "payments"}, `{
+		"Consumers": {"query": "sum(consumers{team=\"{{.Labels.team}}\"}) by (pod)", "legend": "{{pod}}"},
+		"Broken": "sum(x{a=\"{{.Labels.missing}}\"})"
+	}`)
+
+	panels, err := resolvePanels(crd, obj)
+	if err != nil {
+		t.Fatalf("resolvePanels() error = %v", err)
+	}
+	if len(panels) != 3 || panels[0].Title != "Broken" || panels[1].Title != "Consumers" || panels[2].Title != "Depth" {
+		t.Fatalf("unexpected panels: %+v", panels)
+	}
+	if panels[0].Error == "" {
+		t.Error("expected a template error for a missing label")
+	}
+	if p := panels[1]; p.Source != "resource" || p.Query != `sum(consumers{team="payments"}) by (pod)` || p.Legend != "{{pod}}" {
+		t.Errorf("expected the resource to override the CRD panel, got %+v", p)
+	}
+	if p := panels[2]; p.Source != "crd" || p.Unit != "messages" || p.Query != `sum(queue_depth{namespace="shop",queue="orders\"x"})` {
+		t.Errorf("unexpected CRD panel: %+v", p)
+	}
+
+	if _, err := resolvePanels(nil, annotated("Queue", "shop", "q", nil, `{"Empty": ""}`)); err == nil {
+		t.Error("expected an error for a panel without a query")
+	}
+}
+
+func TestParsePanelMatrixNamesSeries(t *testing.T) {
+	raw := []byte(`{"status":"success","data":{"resultType":"matrix","result":[
+		{"metric":{"pod":"a","__name__":"x"},"values":[[1,"2"]]},
+		{"metric":{},"values":[[1,"3"]]}
+	]}}`)
+	got, err := parsePanelMatrix(raw, "Depth", "")
+	if err != nil || len(got) != 2 || got[0].Metric != "pod=a" || got[1].Metric != "Depth" {
+		t.Errorf("parsePanelMatrix() = %+v, %v", got, err)
+	}
+	got, _ = parsePanelMatrix(raw, "Depth", "consumer {{ pod }}")
+	if got[0].Metric != "consumer a" {
+		t.Errorf("legend series name = %q", got[0].Metric)
+	}
+}
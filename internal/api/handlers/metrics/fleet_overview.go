@@ -0,0 +1,281 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/types"
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+	"github.com/Facets-cloud/kube-dash/internal/apitokens"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// fleetOverviewCacheTTL keeps each cluster's overview for about four scrape intervals
+	fleetOverviewCacheTTL = time.Minute
+	// fleetOverviewErrorTTL keeps an unreachable cluster from stalling every fleet request
+	fleetOverviewErrorTTL = 15 * time.Second
+	// fleetOverviewTimeout bounds discovery and the overview queries of one cluster
+	fleetOverviewTimeout = 10 * time.Second
+	// fleetOverviewParallelism is how many clusters are queried at once
+	fleetOverviewParallelism = 8
+	defaultFleetWorst        = 5
+)
+
+// FleetClusterOverview is the cluster overview of one cluster in the fleet, or why it is missing
+type FleetClusterOverview struct {
+	ConfigID               string  `json:"configId"`
+	ConfigName             string  `json:"configName"`
+	Cluster                string  `json:"cluster"`
+	Reachable              bool    `json:"reachable"`
+	Error                  string  `json:"error,omitempty"`
+	Nodes                  float64 `json:"nodes"`
+	CPUPacking             float64 `json:"cpuPacking"`
+	MemoryPacking          float64 `json:"memoryPacking"`
+	TotalAllocatableCPU    float64 `json:"totalAllocatableCpu"`
+	TotalCPURequests       float64 `json:"totalCpuRequests"`
+	TotalAllocatableMemory float64 `json:"totalAllocatableMemory"`
+	TotalMemoryRequests    float64 `json:"totalMemoryRequests"`
+	PodsPresent            float64 `json:"podsPresent"`
+	PodsCapacity           float64 `json:"podsCapacity"`
+	KubernetesVersion      string  `json:"kubernetesVersion,omitempty"`
+	Cached                 bool    `json:"cached"`
+}
+
+// FleetOffender is a cluster among the most tightly packed in the fleet
+type FleetOffender struct {
+	ConfigID   string  `json:"configId"`
+	ConfigName string  `json:"configName"`
+	Cluster    string  `json:"cluster"`
+	Resource   string  `json:"resource"` // cpu or memory, whichever is packed tighter
+	Packing    float64 `json:"packing"`
+}
+
+// FleetOverview rolls the cluster overviews of every reachable cluster up into fleet totals
+type FleetOverview struct {
+	GeneratedAt   string                 `json:"generatedAt"`
+	GeneratedAtMs int64                  `json:"generatedAtMs,omitempty"`
+	Clusters      int                    `json:"clusters"`
+	Reachable     int                    `json:"reachable"`
+	TotalNodes    float64                `json:"totalNodes"`
+	CPUPacking    float64                `json:"cpuPacking"`
+	MemoryPacking float64                `json:"memoryPacking"`
+	PodsPresent   float64                `json:"podsPresent"`
+	PodsCapacity  float64                `json:"podsCapacity"`
+	Worst         []FleetOffender        `json:"worst"`
+	ClusterStats  []FleetClusterOverview `json:"clusterStats"`
+}
+
+// fleetTarget is one kubeconfig context to include in the rollup
+type fleetTarget struct {
+	configID   string
+	configName string
+	cluster    string
+}
+
+// fleetClusterFromOverview reads the instant values of a cluster overview payload
+func fleetClusterFromOverview(target fleetTarget, payload gin.H) FleetClusterOverview {
+	stats := FleetClusterOverview{ConfigID: target.configID, ConfigName: target.configName, Cluster: target.cluster, Reachable: true}
+	instant, _ := payload["instant"].(gin.H)
+	number := func(key string) float64 {
+		v, _ := instant[key].(float64)
+		return v
+	}
+	stats.Nodes = number("node_count")
+	stats.CPUPacking = number("cpu_packing")
+	stats.MemoryPacking = number("memory_packing")
+	stats.TotalAllocatableCPU = number("total_allocatable_cpu")
+	stats.TotalCPURequests = number("total_cpu_requests")
+	stats.TotalAllocatableMemory = number("total_allocatable_memory")
+	stats.TotalMemoryRequests = number("total_memory_requests")
+	stats.PodsPresent = number("pods_present")
+	stats.PodsCapacity = number("pods_capacity")
+	stats.KubernetesVersion, _ = instant["kubernetes_version"].(string)
+	return stats
+}
+
+// buildFleetOverview sums the reachable clusters and ranks them by their tighter packing. Fleet
+// packing is requests over allocatable across all clusters, so large clusters weigh more.
+func buildFleetOverview(clusters []FleetClusterOverview, worst int, now time.Time) FleetOverview {
+	fleet := FleetOverview{
+		GeneratedAt:   types.TimeFormat(now),
+		GeneratedAtMs: types.EpochMillis(now),
+		Clusters:      len(clusters),
+		Worst:         []FleetOffender{},
+		ClusterStats:  clusters,
+	}
+	var cpuRequests, cpuAllocatable, memoryRequests, memoryAllocatable float64
+	for _, cluster := range clusters {
+		if !cluster.Reachable {
+			continue
+		}
+		fleet.Reachable++
+		fleet.TotalNodes += cluster.Nodes
+		fleet.PodsPresent += cluster.PodsPresent
+		fleet.PodsCapacity += cluster.PodsCapacity
+		cpuRequests += cluster.TotalCPURequests
+		cpuAllocatable += cluster.TotalAllocatableCPU
+		memoryRequests += cluster.TotalMemoryRequests
+		memoryAllocatable += cluster.TotalAllocatableMemory
+
+		offender := FleetOffender{ConfigID: cluster.ConfigID, ConfigName: cluster.ConfigName, Cluster: cluster.Cluster, Resource: "cpu", Packing: cluster.CPUPacking}
+		if cluster.MemoryPacking > cluster.CPUPacking {
+			offender.Resource, offender.Packing = "memory", cluster.MemoryPacking
+		}
+		fleet.Worst = append(fleet.Worst, offender)
+	}
+	if cpuAllocatable > 0 {
+		fleet.CPUPacking = cpuRequests / cpuAllocatable * 100
+	}
+	if memoryAllocatable > 0 {
+		fleet.MemoryPacking = memoryRequests / memoryAllocatable * 100
+	}
+
+	sort.SliceStable(fleet.Worst, func(i, j int) bool { return fleet.Worst[i].Packing > fleet.Worst[j].Packing })
+	if len(fleet.Worst) > worst {
+		fleet.Worst = fleet.Worst[:worst]
+	}
+	return fleet
+}
+
+// fleetTargets lists the contexts of every persistent kubeconfig, or of one kubeconfig when the
+// config parameter is set. Only the clusters of the caller's API token scope and kubeconfigs its
+// session may use are listed.
+func (h *PrometheusHandler) fleetTargets(c *gin.Context) ([]fleetTarget, error) {
+	configID := c.Query("config")
+	token, hasToken := apitokens.FromContext(c)
+	sessionID := utils.SessionID(c)
+	now := time.Now()
+
+	var targets []fleetTarget
+	for id, metadata := range h.store.ListKubeConfigs() {
+		// Session-only kubeconfigs belong to one browser session and join the rollup only by name
+		if (configID != "" && id != configID) || (configID == "" && metadata.SessionOnly) || !metadata.VisibleTo(sessionID, now) {
+			continue
+		}
+		kubeconfig, err := h.store.GetKubeConfig(id)
+		if err != nil {
+			if configID != "" {
+				return nil, fmt.Errorf("config not found: %w", err)
+			}
+			continue
+		}
+		for cluster := range kubeconfig.Contexts {
+			if hasToken && !token.AllowsCluster(id, cluster) {
+				continue
+			}
+			targets = append(targets, fleetTarget{configID: id, configName: metadata.Name, cluster: cluster})
+		}
+	}
+	if configID != "" && len(targets) == 0 {
+		return nil, fmt.Errorf("config not found: %s", configID)
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].configName != targets[j].configName {
+			return targets[i].configName < targets[j].configName
+		}
+		return targets[i].cluster < targets[j].cluster
+	})
+	return targets, nil
+}
+
+// fleetClusterOverview returns the cached overview of one cluster or queries its Prometheus
+func (h *PrometheusHandler) fleetClusterOverview(ctx context.Context, target fleetTarget, refresh bool) FleetClusterOverview {
+	cacheKey := h.getCacheKey("fleet-overview", target.configID, target.cluster, "", "", "")
+	if !refresh {
+		if cached, ok := h.getFromCache(cacheKey); ok {
+			stats := cached.(FleetClusterOverview)
+			stats.Cached = true
+			return stats
+		}
+	}
+
+	failed := func(err error) FleetClusterOverview {
+		stats := FleetClusterOverview{ConfigID: target.configID, ConfigName: target.configName, Cluster: target.cluster, Error: err.Error()}
+		h.setCache(cacheKey, stats, fleetOverviewErrorTTL)
+		return stats
+	}
+	kubeconfig, err := h.store.GetKubeConfig(target.configID)
+	if err != nil {
+		return failed(fmt.Errorf("config not found: %w", err))
+	}
+	client, err := h.clientFactory.GetClientForConfig(kubeconfig, target.cluster)
+	if err != nil {
+		return failed(fmt.Errorf("failed to get Kubernetes client: %w", err))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, fleetOverviewTimeout)
+	defer cancel()
+	promTarget, err := h.discoverPrometheus(ctx, client)
+	if err != nil {
+		return failed(fmt.Errorf("prometheus not available"))
+	}
+	// A one-step range keeps the overview's range queries to a single sample
+	payload, err := h.fetchClusterOverview(ctx, client, promTarget, target.configID, target.cluster, "5m", "5m")
+	if err != nil {
+		return failed(err)
+	}
+	stats := fleetClusterFromOverview(target, payload)
+	h.setCache(cacheKey, stats, fleetOverviewCacheTTL)
+	return stats
+}
+
+// GetFleetOverview rolls the cluster overview of every cluster up into fleet totals
+// @Summary Fleet overview across clusters
+// @Description Runs the cluster overview metric set against the Prometheus of every kubeconfig context in parallel and returns fleet totals (nodes, pods, CPU and memory packing weighted by allocatable) with the most tightly packed clusters. A cluster that is unreachable or has no Prometheus is reported with its error and does not fail the rollup. Per-cluster results are cached for a minute, failures for 15 seconds; refresh=true bypasses the cache. Session-only kubeconfigs are included only when requested with config.
+// @Tags Metrics
+// @Produce json
+// @Param config query string false "Only roll up the contexts of this kubeconfig"
+// @Param worst query int false "Number of most tightly packed clusters to return (default 5)"
+// @Param refresh query bool false "Query every cluster even when a cached overview exists"
+// @Success 200 {object} FleetOverview "Fleet rollup"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 404 {object} map[string]string "Config not found"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/metrics/overview/fleet [get]
+func (h *PrometheusHandler) GetFleetOverview(c *gin.Context) {
+	worst := defaultFleetWorst
+	if w := c.Query("worst"); w != "" {
+		n, err := strconv.Atoi(w)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "worst must be a non-negative integer"})
+			return
+		}
+		worst = n
+	}
+	refresh := c.Query("refresh") == "true"
+
+	targets, err := h.fleetTargets(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	h.clearExpiredCache()
+
+	clusters := make([]FleetClusterOverview, len(targets))
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, fleetOverviewParallelism)
+	for i, target := range targets {
+		wg.Add(1)
+		go func(i int, target fleetTarget) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			clusters[i] = h.fleetClusterOverview(c.Request.Context(), target, refresh)
+			if clusters[i].Error != "" && !clusters[i].Cached {
+				h.logger.WithField("config_id", target.configID).WithField("cluster", target.cluster).
+					Warnf("Fleet overview skipped cluster: %s", clusters[i].Error)
+			}
+		}(i, target)
+	}
+	wg.Wait()
+
+	c.JSON(http.StatusOK, buildFleetOverview(clusters, worst, time.Now()))
+}
@@ -0,0 +1,126 @@
+package metrics
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+	"github.com/Facets-cloud/kube-dash/internal/apitokens"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestFleetClusterFromOverview(t *testing.T) {
+	payload := gin.H{"instant": gin.H{
+		"node_count":            3.0,
+		"cpu_packing":           72.5,
+		"total_allocatable_cpu": 12.0,
+		"kubernetes_version":    "v1.30.2",
+	}}
+	stats := fleetClusterFromOverview(fleetTarget{configID: "c1", configName: "prod", cluster: "eu"}, payload)
+	if !stats.Reachable || stats.Nodes != 3 || stats.CPUPacking != 72.5 || stats.TotalAllocatableCPU != 12 || stats.KubernetesVersion != "v1.30.2" {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if stats.ConfigName != "prod" || stats.Cluster != "eu" {
+		t.Errorf("expected the target to be kept, got %+v", stats)
+	}
+}
+
+func TestBuildFleetOverview(t *testing.T) {
+	clusters := []FleetClusterOverview{
+		{ConfigID: "c1", Cluster: "small", Reachable: true, Nodes: 2, CPUPacking: 90, MemoryPacking: 40,
+			TotalAllocatableCPU: 4, TotalCPURequests: 3.6, TotalAllocatableMemory: 8, TotalMemoryRequests: 3.2, PodsPresent: 20, PodsCapacity: 220},
+		{ConfigID: "c1", Cluster: "large", Reachable: true, Nodes: 10, CPUPacking: 30, MemoryPacking: 60,
+			TotalAllocatableCPU: 36, TotalCPURequests: 10.4, TotalAllocatableMemory: 72, TotalMemoryRequests: 43.2, PodsPresent: 200, PodsCapacity: 1100},
+		{ConfigID: "c2", Cluster: "down", Error: "prometheus not available"},
+	}
+
+	fleet := buildFleetOverview(clusters, 1, time.Now())
+	if fleet.Clusters != 3 || fleet.Reachable != 2 || fleet.TotalNodes != 12 || fleet.PodsPresent != 220 || fleet.PodsCapacity != 1320 {
+		t.Fatalf("unexpected totals %+v", fleet)
+	}
+	// (3.6 + 10.4) / (4 + 36) and (3.2 + 43.2) / (8 + 72)
+	if math.Abs(fleet.CPUPacking-35) > 1e-9 || math.Abs(fleet.MemoryPacking-58) > 1e-9 {
+		t.Errorf("expected packing weighted by allocatable, got cpu %v memory %v", fleet.CPUPacking, fleet.MemoryPacking)
+	}
+	if len(fleet.Worst) != 1 || fleet.Worst[0].Cluster != "small" || fleet.Worst[0].Resource != "cpu" || fleet.Worst[0].Packing != 90 {
+		t.Errorf("unexpected worst offenders %+v", fleet.Worst)
+	}
+	if len(fleet.ClusterStats) != 3 || fleet.ClusterStats[2].Error == "" {
+		t.Errorf("expected the unreachable cluster to be reported, got %+v", fleet.ClusterStats)
+	}
+}
+
+func fleetTestConfig(contexts ...string) *api.Config {
+	config := api.NewConfig()
+	config.AuthInfos["u"] = &api.AuthInfo{Token: "secret"}
+	for _, name := range contexts {
+		config.Clusters[name] = &api.Cluster{Server: "https://" + name + ".example.com"}
+		config.Contexts[name] = &api.Context{Cluster: name, AuthInfo: "u"}
+	}
+	return config
+}
+
+func TestFleetTargetsScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := storage.NewKubeConfigStore()
+	prod, err := store.AddKubeConfig(fleetTestConfig("eu", "us"), "prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.AddKubeConfig(fleetTestConfig("dev"), "staging"); err != nil {
+		t.Fatal(err)
+	}
+	contractor, err := store.AddSessionKubeConfig(fleetTestConfig("tmp"), "contractor", "session-a", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokens := apitokens.NewStore(storage.NewDocumentStore(nil), logger.New("error"))
+	secret, err := tokens.Create(&apitokens.Token{Name: "ci", Kind: apitokens.KindService, ReadOnly: true, Clusters: []apitokens.ClusterScope{{ConfigID: prod, Cluster: "eu"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := &PrometheusHandler{store: store}
+	var listed []string
+	router := gin.New()
+	router.Use(apitokens.Middleware(tokens, nil))
+	router.GET("/api/v1/metrics/overview/fleet", func(c *gin.Context) {
+		targets, _ := h.fleetTargets(c)
+		listed = nil
+		for _, target := range targets {
+			listed = append(listed, target.configName+"/"+target.cluster)
+		}
+	})
+	list := func(header, value string, query ...string) []string {
+		path := "/api/v1/metrics/overview/fleet"
+		if len(query) > 0 {
+			path += "?" + query[0]
+		}
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(header, value)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+		return listed
+	}
+
+	if got := list("Authorization", "Bearer "+secret); !reflect.DeepEqual(got, []string{"prod/eu"}) {
+		t.Errorf("expected a scoped token to see only its cluster, got %v", got)
+	}
+	if got := list(utils.SessionHeader, "session-b"); !reflect.DeepEqual(got, []string{"prod/eu", "prod/us", "staging/dev"}) {
+		t.Errorf("expected the dashboard to see every persistent cluster, got %v", got)
+	}
+	if got := list(utils.SessionHeader, "session-a", "config="+contractor); !reflect.DeepEqual(got, []string{"contractor/tmp"}) {
+		t.Errorf("expected the owning session to see its session-only kubeconfig by name, got %v", got)
+	}
+	if got := list(utils.SessionHeader, "session-b", "config="+contractor); len(got) != 0 {
+		t.Errorf("expected another session's kubeconfig to be hidden, got %v", got)
+	}
+}
@@ -0,0 +1,300 @@
+package metrics
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxBatchRequests bounds the metric requests of one batch
+	maxBatchRequests = 500
+	// batchPodsPerQuery bounds the pods matched by one grouped query, keeping PromQL selectors short
+	batchPodsPerQuery = 100
+	// batchQueryConcurrency bounds the grouped queries sent to Prometheus at once
+	batchQueryConcurrency = 4
+	// batchCacheTTL lets list pages polled by several users share results
+	batchCacheTTL = 15 * time.Second
+	// maxBatchPoints is the most points per series Prometheus returns for a range query
+	maxBatchPoints = 11000
+)
+
+// batchQueries are the grouped PromQL queries for each batchable metric; %s is the pod selector
+var batchQueries = map[string]string{
+	"cpu":        `1000 * sum by (namespace,pod) (rate(container_cpu_usage_seconds_total{%s,container!~"POD|istio-proxy|istio-init"}[5m]))`,
+	"memory":     `sum by (namespace,pod) (container_memory_working_set_bytes{%s,container!~"POD|istio-proxy|istio-init"})`,
+	"network-rx": `sum by (namespace,pod) (rate(container_network_receive_bytes_total{%s}[5m]))`,
+	"network-tx": `sum by (namespace,pod) (rate(container_network_transmit_bytes_total{%s}[5m]))`,
+}
+
+// MetricRequest asks for one metric of one pod
+type MetricRequest struct {
+	Metric    string `json:"metric"` // cpu (millicores), memory (bytes), network-rx or network-tx (bytes/s)
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+}
+
+// MetricsBatchRequest is a set of pod metric requests resolved together
+type MetricsBatchRequest struct {
+	Range    string          `json:"range,omitempty"` // default 15m
+	Step     string          `json:"step,omitempty"`  // default 60s
+	Requests []MetricRequest `json:"requests"`
+}
+
+// MetricsBatchError reports a grouped query that failed; its pods have no points
+type MetricsBatchError struct {
+	Metric    string `json:"metric"`
+	Namespace string `json:"namespace"`
+	Error     string `json:"error"`
+}
+
+// MetricsBatchResponse holds the series of every requested pod, keyed by metric and then by
+// namespace/pod. Requested pods without data have an empty series.
+type MetricsBatchResponse struct {
+	Range   string                            `json:"range"`
+	Step    string                            `json:"step"`
+	Results map[string]map[string][]timePoint `json:"results"`
+	Queries int                               `json:"queries"` // grouped PromQL queries resolved
+	Errors  []MetricsBatchError               `json:"errors,omitempty"`
+}
+
+// batchGroup is one grouped query: a metric for a set of pods in a namespace
+type batchGroup struct {
+	metric    string
+	namespace string
+	pods      []string
+}
+
+// podKey keys batch results
+func podKey(namespace, pod string) string {
+	return namespace + "/" + pod
+}
+
+// groupBatch validates the requests and groups them into one query per metric, namespace and
+// chunk of pods, dropping duplicates
+func groupBatch(requests []MetricRequest) ([]batchGroup, error) {
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("at least one request is required")
+	}
+	if len(requests) > maxBatchRequests {
+		return nil, fmt.Errorf("a batch may hold at most %d requests", maxBatchRequests)
+	}
+	pods := map[[2]string]map[string]bool{}
+	for i, req := range requests {
+		if _, ok := batchQueries[req.Metric]; !ok {
+			return nil, fmt.Errorf("request %d: unsupported metric %q; use cpu, memory, network-rx or network-tx", i, req.Metric)
+		}
+		if req.Namespace == "" || req.Pod == "" {
+			return nil, fmt.Errorf("request %d: namespace and pod are required", i)
+		}
+		key := [2]string{req.Metric, req.Namespace}
+		if pods[key] == nil {
+			pods[key] = map[string]bool{}
+		}
+		pods[key][req.Pod] = true
+	}
+
+	var groups []batchGroup
+	for key, set := range pods {
+		names := make([]string, 0, len(set))
+		for name := range set {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for start := 0; start < len(names); start += batchPodsPerQuery {
+			end := min(start+batchPodsPerQuery, len(names))
+			groups = append(groups, batchGroup{metric: key[0], namespace: key[1], pods: names[start:end]})
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].metric != groups[j].metric {
+			return groups[i].metric < groups[j].metric
+		}
+		if groups[i].namespace != groups[j].namespace {
+			return groups[i].namespace < groups[j].namespace
+		}
+		return groups[i].pods[0] < groups[j].pods[0]
+	})
+	return groups, nil
+}
+
+// query renders the grouped PromQL query, matching the pods with an anchored regex
+func (g batchGroup) query() string {
+	quoted := make([]string, len(g.pods))
+	for i, pod := range g.pods {
+		quoted[i] = regexp.QuoteMeta(pod)
+	}
+	selector := fmt.Sprintf(`namespace="%s",pod=~"%s"`, escapeLabelValue(g.namespace), escapeLabelValue(strings.Join(quoted, "|")))
+	return fmt.Sprintf(batchQueries[g.metric], selector)
+}
+
+// parsePodMatrix converts a matrix grouped by namespace and pod into series keyed by namespace/pod
+func parsePodMatrix(raw []byte) (map[string][]timePoint, error) {
+	var resp promQueryRangeResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Status != "success" {
+		return nil, fmt.Errorf("prometheus query failed")
+	}
+	out := map[string][]timePoint{}
+	for _, r := range resp.Data.Result {
+		points := make([]timePoint, 0, len(r.Values))
+		for _, pair := range r.Values {
+			if len(pair) != 2 {
+				continue
+			}
+			ts, ok := pair[0].(float64)
+			if !ok {
+				continue
+			}
+			v, err := parseFloat(fmt.Sprintf("%v", pair[1]))
+			if err != nil {
+				continue
+			}
+			points = append(points, timePoint{T: ts, V: v})
+		}
+		out[podKey(r.Metric["namespace"], r.Metric["pod"])] = points
+	}
+	return out, nil
+}
+
+// batchCacheKey identifies a batch by its grouped queries, so request order does not matter
+func batchCacheKey(groups []batchGroup) string {
+	sum := sha256.New()
+	for _, g := range groups {
+		fmt.Fprintf(sum, "%s\x00%s\x00%s\n", g.metric, g.namespace, strings.Join(g.pods, ","))
+	}
+	return hex.EncodeToString(sum.Sum(nil)[:16])
+}
+
+// GetMetricsBatch resolves many pod metric requests with grouped queries
+// @Summary Get batched pod metrics
+// @Description Resolves many pod metric requests, such as CPU sparklines for every row of a pod list, with one grouped PromQL query (sum by pod) per metric and namespace instead of a stream per pod. Pods are matched in chunks of 100 per query. Results are keyed by metric and then by namespace/pod; requested pods without data have an empty series, and failed queries are listed under errors. Results are cached for 15 seconds.
+// @Tags Metrics
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name"
+// @Param body body MetricsBatchRequest true "Metric requests"
+// @Success 200 {object} MetricsBatchResponse "Series keyed by metric and pod"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Prometheus not available"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/metrics/batch [post]
+// @Router /api/v2/metrics/batch [post]
+func (h *PrometheusHandler) GetMetricsBatch(c *gin.Context) {
+	client, err := h.getClient(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req MetricsBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if req.Range == "" {
+		req.Range = "15m"
+	}
+	if req.Step == "" {
+		req.Step = "60s"
+	}
+	step, err := time.ParseDuration(req.Step)
+	if err != nil || step < time.Second {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "step must be a duration of at least 1s"})
+		return
+	}
+	window := parsePromRange(req.Range)
+	if int(window/step) > maxBatchPoints {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("range and step give more than %d points per series", maxBatchPoints)})
+		return
+	}
+	groups, err := groupBatch(req.Requests)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cacheKey := h.getCacheKey("metrics-batch", c.Query("config"), c.Query("cluster"), batchCacheKey(groups), req.Range, req.Step)
+	if cached, ok := h.getFromCache(cacheKey); ok {
+		c.JSON(http.StatusOK, cached)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 20*time.Second)
+	defer cancel()
+	target, err := h.discoverPrometheus(ctx, client)
+	if err != nil || target == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "prometheus not available"})
+		return
+	}
+
+	response := MetricsBatchResponse{Range: req.Range, Step: req.Step, Results: map[string]map[string][]timePoint{}, Queries: len(groups)}
+	for _, g := range groups {
+		if response.Results[g.metric] == nil {
+			response.Results[g.metric] = map[string][]timePoint{}
+		}
+		for _, pod := range g.pods {
+			response.Results[g.metric][podKey(g.namespace, pod)] = []timePoint{}
+		}
+	}
+
+	now := time.Now()
+	start := now.Add(-window)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, batchQueryConcurrency)
+	for _, g := range groups {
+		wg.Add(1)
+		go func(g batchGroup) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			raw, err := h.proxyPrometheus(ctx, client, target, "/api/v1/query_range", map[string]string{
+				"query": g.query(),
+				"start": fmt.Sprintf("%d", start.Unix()),
+				"end":   fmt.Sprintf("%d", now.Unix()),
+				"step":  req.Step,
+			})
+			var points map[string][]timePoint
+			if err == nil {
+				points, err = parsePodMatrix(raw)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				response.Errors = append(response.Errors, MetricsBatchError{Metric: g.metric, Namespace: g.namespace, Error: err.Error()})
+				return
+			}
+			for key, series := range points {
+				if _, requested := response.Results[g.metric][key]; requested {
+					response.Results[g.metric][key] = series
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	sort.Slice(response.Errors, func(i, j int) bool {
+		return response.Errors[i].Metric+response.Errors[i].Namespace < response.Errors[j].Metric+response.Errors[j].Namespace
+	})
+
+	if len(response.Errors) == 0 {
+		h.setCache(cacheKey, response, batchCacheTTL)
+	}
+	c.JSON(http.StatusOK, response)
+}
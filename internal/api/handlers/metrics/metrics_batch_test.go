@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Facets-cloud/kube-dash/internal/testharness"
+)
+
+func TestGroupBatch(t *testing.T) {
+	requests := []MetricRequest{
+		{Metric: "cpu", Namespace: "shop", Pod: "web-2"},
+		{Metric: "cpu", Namespace: "shop", Pod: "web-1"},
+		{Metric: "cpu", Namespace: "shop", Pod: "web-1"},
+		{Metric: "memory", Namespace: "shop", Pod: "web-1"},
+		{Metric: "cpu", Namespace: "billing", Pod: "api-0"},
+	}
+	groups, err := groupBatch(requests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 3 {
+		t.Fatalf("groups = %+v, want one per metric and namespace", groups)
+	}
+	if g := groups[1]; g.metric != "cpu" || g.namespace != "shop" || strings.Join(g.pods, ",") != "web-1,web-2" {
+		t.Errorf("group = %+v", g)
+	}
+	if q := groups[1].query(); !strings.Contains(q, `namespace="shop",pod=~"web-1|web-2"`) || !strings.HasPrefix(q, "1000 * sum by (namespace,pod)") {
+		t.Errorf("query = %s", q)
+	}
+
+	var many []MetricRequest
+	for i := 0; i < 250; i++ {
+		many = append(many, MetricRequest{Metric: "cpu", Namespace: "shop", Pod: fmt.Sprintf("pod-%03d", i)})
+	}
+	if groups, _ := groupBatch(many); len(groups) != 3 || len(groups[2].pods) != 50 {
+		t.Errorf("250 pods grouped into %d queries", len(groups))
+	}
+
+	for _, invalid := range [][]MetricRequest{nil, {{Metric: "disk", Namespace: "shop", Pod: "web"}}, {{Metric: "cpu", Pod: "web"}}} {
+		if _, err := groupBatch(invalid); err == nil {
+			t.Errorf("groupBatch(%+v) succeeded, want an error", invalid)
+		}
+	}
+}
+
+func TestBatchQueryEscapesPodNames(t *testing.T) {
+	q := batchGroup{metric: "memory", namespace: "shop", pods: []string{"web.v2-0"}}.query()
+	if !strings.Contains(q, `pod=~"web\\.v2-0"`) {
+		t.Errorf("query = %s, want the dot escaped for the regex", q)
+	}
+}
+
+func TestParsePodMatrix(t *testing.T) {
+	raw := []byte(`{"status":"success","data":{"resultType":"matrix","result":[
+		{"metric":{"namespace":"shop","pod":"web-1"},"values":[[1700000000,"12.5"],[1700000060,"NaN"]]},
+		{"metric":{"namespace":"shop","pod":"web-2"},"values":[[1700000000,"3"]]}]}}`)
+	series, err := parsePodMatrix(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series["shop/web-1"]) != 2 || series["shop/web-1"][0].V != 12.5 || len(series["shop/web-2"]) != 1 {
+		t.Errorf("series = %+v", series)
+	}
+	if _, err := parsePodMatrix([]byte(`{"status":"error"}`)); err == nil {
+		t.Error("parsePodMatrix() accepted a failed query")
+	}
+}
+
+func TestParsePodMatrixRecorded(t *testing.T) {
+	series, err := parsePodMatrix(testharness.Recorded(t, "prometheus/pod_cpu_matrix.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != 2 || len(series["shop/web-7d4b9c6f5-abcde"]) != 3 {
+		t.Errorf("series = %+v, want three points for each of two pods", series)
+	}
+}
@@ -0,0 +1,240 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// nodeHeatmapCacheTTL keeps the heatmap about one scrape interval old at most
+const nodeHeatmapCacheTTL = 15 * time.Second
+
+// Columns of the node heatmap, in order
+const (
+	HeatmapCPU     = "cpu"
+	HeatmapMemory  = "memory"
+	HeatmapPods    = "pods"
+	HeatmapDisk    = "disk"
+	HeatmapNetwork = "network"
+)
+
+// heatmapMetrics is the column order of every heatmap row
+var heatmapMetrics = []string{HeatmapCPU, HeatmapMemory, HeatmapPods, HeatmapDisk, HeatmapNetwork}
+
+// How network utilization is normalized
+const (
+	NetworkBasisLinkSpeed   = "linkSpeed"   // against the link speed reported by node-exporter
+	NetworkBasisBusiestNode = "busiestNode" // against the busiest node, when link speeds are unknown
+)
+
+// nodeHeatmapDevices excludes loopback and virtual interfaces, whose traffic is counted twice
+const nodeHeatmapDevices = `device!~"lo|veth.*|cali.*|cni.*|flannel.*|docker.*|cilium.*|vxlan.*|tunl.*"`
+
+// nodeByName joins node-exporter series with the node name
+const nodeByName = `on(instance) group_left(nodename) node_uname_info`
+
+// nodeHeatmapQueries are the instant queries of one heatmap, by name; all are keyed by nodename
+var nodeHeatmapQueries = map[string]string{
+	HeatmapCPU:     fmt.Sprintf(`100 * (1 - avg by (nodename) (rate(node_cpu_seconds_total{mode="idle"}[5m]) * %s))`, nodeByName),
+	HeatmapMemory:  fmt.Sprintf(`100 * (1 - sum by (nodename) (node_memory_MemAvailable_bytes * %s) / sum by (nodename) (node_memory_MemTotal_bytes * %s))`, nodeByName, nodeByName),
+	HeatmapDisk:    fmt.Sprintf(`100 * max by (nodename) ((1 - node_filesystem_avail_bytes{fstype!~"tmpfs|overlay",mountpoint="/"} / node_filesystem_size_bytes{fstype!~"tmpfs|overlay",mountpoint="/"}) * %s)`, nodeByName),
+	HeatmapNetwork: fmt.Sprintf(`sum by (nodename) ((rate(node_network_receive_bytes_total{%s}[5m]) + rate(node_network_transmit_bytes_total{%s}[5m])) * %s)`, nodeHeatmapDevices, nodeHeatmapDevices, nodeByName),
+	"linkSpeed":    fmt.Sprintf(`sum by (nodename) (node_network_speed_bytes{%s} * %s)`, nodeHeatmapDevices, nodeByName),
+}
+
+// NodeHeatmap is the utilization of every node as a compact matrix. Values[i][j] is the
+// utilization of Nodes[i] for Metrics[j] in percent, or null when there is no data.
+type NodeHeatmap struct {
+	Metrics      []string     `json:"metrics"`
+	Nodes        []string     `json:"nodes"`
+	Values       [][]*float64 `json:"values"`
+	NetworkBasis string       `json:"networkBasis"` // linkSpeed or busiestNode
+	GeneratedAt  time.Time    `json:"generatedAt"`
+	Warnings     []string     `json:"warnings,omitempty"`
+}
+
+// heatmapPercent rounds a utilization to one decimal and clamps it to 0-100
+func heatmapPercent(value float64) *float64 {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return nil
+	}
+	value = math.Round(math.Min(math.Max(value, 0), 100)*10) / 10
+	return &value
+}
+
+// byNodename indexes instant query samples by their nodename label
+func byNodename(samples []vectorSample) map[string]float64 {
+	out := make(map[string]float64, len(samples))
+	for _, s := range samples {
+		if name := s.Metric["nodename"]; name != "" {
+			out[name] = s.Value
+		}
+	}
+	return out
+}
+
+// buildNodeHeatmap lays out node utilization as a matrix. Pod density comes from the API server
+// since Prometheus may not run kube-state-metrics; network traffic is normalized against the
+// link speed when every node with traffic reports one, otherwise against the busiest node.
+func buildNodeHeatmap(nodes []v1.Node, pods []v1.Pod, samples map[string][]vectorSample) NodeHeatmap {
+	heatmap := NodeHeatmap{
+		Metrics:      heatmapMetrics,
+		Nodes:        make([]string, 0, len(nodes)),
+		Values:       make([][]*float64, 0, len(nodes)),
+		NetworkBasis: NetworkBasisLinkSpeed,
+	}
+
+	podsByNode := map[string]int{}
+	for _, pod := range pods {
+		if pod.Spec.NodeName != "" && pod.Status.Phase != v1.PodSucceeded && pod.Status.Phase != v1.PodFailed {
+			podsByNode[pod.Spec.NodeName]++
+		}
+	}
+
+	values := map[string]map[string]float64{}
+	for _, metric := range []string{HeatmapCPU, HeatmapMemory, HeatmapDisk, HeatmapNetwork, "linkSpeed"} {
+		values[metric] = byNodename(samples[metric])
+	}
+	busiest := 0.0
+	for _, node := range nodes {
+		traffic, ok := values[HeatmapNetwork][node.Name]
+		if !ok {
+			continue
+		}
+		busiest = math.Max(busiest, traffic)
+		if values["linkSpeed"][node.Name] <= 0 {
+			heatmap.NetworkBasis = NetworkBasisBusiestNode
+		}
+	}
+
+	sorted := append([]v1.Node(nil), nodes...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	missing := 0
+	for _, node := range sorted {
+		row := make([]*float64, len(heatmapMetrics))
+		for j, metric := range heatmapMetrics {
+			switch metric {
+			case HeatmapPods:
+				if capacity := node.Status.Allocatable.Pods().Value(); capacity > 0 {
+					row[j] = heatmapPercent(100 * float64(podsByNode[node.Name]) / float64(capacity))
+				}
+			case HeatmapNetwork:
+				traffic, ok := values[HeatmapNetwork][node.Name]
+				switch {
+				case !ok:
+				case heatmap.NetworkBasis == NetworkBasisLinkSpeed:
+					// Links are full duplex, so the capacity is the speed in both directions
+					row[j] = heatmapPercent(100 * traffic / (2 * values["linkSpeed"][node.Name]))
+				case busiest > 0:
+					row[j] = heatmapPercent(100 * traffic / busiest)
+				default:
+					row[j] = heatmapPercent(0)
+				}
+			default:
+				if value, ok := values[metric][node.Name]; ok {
+					row[j] = heatmapPercent(value)
+				}
+			}
+		}
+		if row[0] == nil && row[1] == nil {
+			missing++
+		}
+		heatmap.Nodes = append(heatmap.Nodes, node.Name)
+		heatmap.Values = append(heatmap.Values, row)
+	}
+	if missing > 0 {
+		heatmap.Warnings = append(heatmap.Warnings, fmt.Sprintf("%d of %d nodes have no node-exporter data", missing, len(nodes)))
+	}
+	return heatmap
+}
+
+// GetNodeHeatmap returns the utilization of all nodes as a matrix for a cluster heatmap
+// @Summary Get node utilization heatmap
+// @Description Returns the current CPU, memory, pod, root disk and network utilization of every node as percentages in a compact matrix, from one set of instant Prometheus queries and the node and pod lists, so a cluster heatmap can be drawn without a stream per node. Pod utilization is running pods against allocatable pods. Network utilization is traffic against link speed, or against the busiest node when link speeds are not reported (networkBasis says which). Cells are null when a node has no data for a metric.
+// @Tags Metrics
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name"
+// @Success 200 {object} NodeHeatmap "Node utilization matrix"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Prometheus not available"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/metrics/nodes/heatmap [get]
+// @Router /api/v2/metrics/nodes/heatmap [get]
+func (h *PrometheusHandler) GetNodeHeatmap(c *gin.Context) {
+	client, err := h.getClient(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cacheKey := h.getCacheKey("node-heatmap", c.Query("config"), c.Query("cluster"), "", "", "")
+	if cached, ok := h.getFromCache(cacheKey); ok {
+		c.JSON(http.StatusOK, cached)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+	target, err := h.discoverPrometheus(ctx, client)
+	if err != nil || target == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "prometheus not available"})
+		return
+	}
+
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	pods, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{FieldSelector: "status.phase!=Succeeded,status.phase!=Failed"})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	samples := make(map[string][]vectorSample, len(nodeHeatmapQueries))
+	var failed []string
+	for name, query := range nodeHeatmapQueries {
+		wg.Add(1)
+		go func(name, query string) {
+			defer wg.Done()
+			raw, err := h.proxyPrometheus(ctx, client, target, "/api/v1/query", map[string]string{"query": query})
+			var parsed []vectorSample
+			if err == nil {
+				parsed, err = parseVector(raw)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				h.logger.WithError(err).WithField("metric", name).Warn("Node heatmap query failed")
+				failed = append(failed, name)
+				return
+			}
+			samples[name] = parsed
+		}(name, query)
+	}
+	wg.Wait()
+
+	heatmap := buildNodeHeatmap(nodes.Items, pods.Items, samples)
+	heatmap.GeneratedAt = time.Now().UTC()
+	sort.Strings(failed)
+	for _, name := range failed {
+		heatmap.Warnings = append(heatmap.Warnings, fmt.Sprintf("the %s query failed", name))
+	}
+
+	h.setCache(cacheKey, heatmap, nodeHeatmapCacheTTL)
+	c.JSON(http.StatusOK, heatmap)
+}
@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func heatmapNode(name string, pods int64) v1.Node {
+	return v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status:     v1.NodeStatus{Allocatable: v1.ResourceList{v1.ResourcePods: *resource.NewQuantity(pods, resource.DecimalSI)}},
+	}
+}
+
+func heatmapSamples(values map[string]float64) []vectorSample {
+	var out []vectorSample
+	for node, value := range values {
+		out = append(out, vectorSample{Metric: map[string]string{"nodename": node}, Value: value})
+	}
+	return out
+}
+
+func TestBuildNodeHeatmap(t *testing.T) {
+	nodes := []v1.Node{heatmapNode("worker-b", 10), heatmapNode("worker-a", 4), heatmapNode("worker-c", 0)}
+	pods := []v1.Pod{
+		{Spec: v1.PodSpec{NodeName: "worker-a"}, Status: v1.PodStatus{Phase: v1.PodRunning}},
+		{Spec: v1.PodSpec{NodeName: "worker-a"}, Status: v1.PodStatus{Phase: v1.PodPending}},
+		{Spec: v1.PodSpec{NodeName: "worker-a"}, Status: v1.PodStatus{Phase: v1.PodSucceeded}},
+		{Spec: v1.PodSpec{NodeName: "worker-b"}, Status: v1.PodStatus{Phase: v1.PodRunning}},
+	}
+	samples := map[string][]vectorSample{
+		HeatmapCPU:     heatmapSamples(map[string]float64{"worker-a": 42.345, "worker-b": 103}),
+		HeatmapMemory:  heatmapSamples(map[string]float64{"worker-a": 60, "worker-b": 70}),
+		HeatmapDisk:    heatmapSamples(map[string]float64{"worker-a": 10}),
+		HeatmapNetwork: heatmapSamples(map[string]float64{"worker-a": 250, "worker-b": 125}),
+		"linkSpeed":    heatmapSamples(map[string]float64{"worker-a": 1250}),
+	}
+
+	heatmap := buildNodeHeatmap(nodes, pods, samples)
+	if len(heatmap.Nodes) != 3 || heatmap.Nodes[0] != "worker-a" || heatmap.Nodes[2] != "worker-c" {
+		t.Fatalf("nodes = %v, want sorted by name", heatmap.Nodes)
+	}
+	// worker-b reports no link speed, so traffic is relative to the busiest node
+	if heatmap.NetworkBasis != NetworkBasisBusiestNode {
+		t.Errorf("network basis = %s", heatmap.NetworkBasis)
+	}
+
+	want := [][]interface{}{
+		{42.3, 60.0, 50.0, 10.0, 100.0},
+		{100.0, 70.0, 10.0, nil, 50.0},
+		{nil, nil, nil, nil, nil},
+	}
+	for i, row := range heatmap.Values {
+		for j, cell := range row {
+			switch expected := want[i][j].(type) {
+			case nil:
+				if cell != nil {
+					t.Errorf("%s %s = %v, want null", heatmap.Nodes[i], heatmap.Metrics[j], *cell)
+				}
+			case float64:
+				if cell == nil || *cell != expected {
+					t.Errorf("%s %s = %v, want %v", heatmap.Nodes[i], heatmap.Metrics[j], cell, expected)
+				}
+			}
+		}
+	}
+	if len(heatmap.Warnings) != 1 {
+		t.Errorf("warnings = %v, want one for worker-c", heatmap.Warnings)
+	}
+
+	samples["linkSpeed"] = heatmapSamples(map[string]float64{"worker-a": 1250, "worker-b": 125})
+	heatmap = buildNodeHeatmap(nodes, pods, samples)
+	if heatmap.NetworkBasis != NetworkBasisLinkSpeed || *heatmap.Values[0][4] != 10 || *heatmap.Values[1][4] != 50 {
+		t.Errorf("link speed network = %s %v %v", heatmap.NetworkBasis, *heatmap.Values[0][4], *heatmap.Values[1][4])
+	}
+}
@@ -0,0 +1,363 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// overviewRefreshInterval matches the refresh rate of the overview SSE stream
+	overviewRefreshInterval = 5 * time.Second
+	// overviewQueryTimeout bounds one round of overview queries
+	overviewQueryTimeout = 30 * time.Second
+	// overviewSendQueue is the number of messages buffered per subscriber; slow clients skip updates
+	overviewSendQueue    = 4
+	overviewWriteTimeout = 10 * time.Second
+)
+
+var overviewUpgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool {
+		return true // Allow all origins for now
+	},
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+// OverviewControl is a message from a cluster overview WebSocket client
+type OverviewControl struct {
+	Type   string   `json:"type"` // configure, refresh or ping
+	Range  string   `json:"range,omitempty"`
+	Step   string   `json:"step,omitempty"`
+	Select []string `json:"select,omitempty"` // "series", "instant" or instant keys such as "node_count"; empty selects everything
+}
+
+// OverviewMessage is a message to a cluster overview WebSocket client
+type OverviewMessage struct {
+	Type      string      `json:"type"` // overview, configured, error or pong
+	Range     string      `json:"range,omitempty"`
+	Step      string      `json:"step,omitempty"`
+	Select    []string    `json:"select,omitempty"`
+	Data      interface{} `json:"data,omitempty"`
+	Error     string      `json:"error,omitempty"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+// overviewView is a range and step; subscribers with the same view share one set of queries
+type overviewView struct {
+	rng  string
+	step string
+}
+
+// overviewSubscriber is one WebSocket client of a pipeline
+type overviewSubscriber struct {
+	out chan OverviewMessage
+
+	mu     sync.Mutex
+	view   overviewView
+	filter []string
+}
+
+func (s *overviewSubscriber) settings() (overviewView, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.view, s.filter
+}
+
+// deliver queues a message, dropping it when the client is not keeping up
+func (s *overviewSubscriber) deliver(msg OverviewMessage) {
+	select {
+	case s.out <- msg:
+	default:
+	}
+}
+
+// overviewPipeline polls Prometheus for one cluster on behalf of all its subscribers
+type overviewPipeline struct {
+	handler  *PrometheusHandler
+	key      string
+	client   kubernetes.Interface
+	target   *promTarget
+	configID string
+	cluster  string
+	refresh  chan overviewView
+	cancel   context.CancelFunc
+
+	mu          sync.Mutex
+	subscribers map[*overviewSubscriber]struct{}
+	latest      map[overviewView]OverviewMessage
+}
+
+// views returns the distinct views subscribers currently want
+func (p *overviewPipeline) views() map[overviewView][]*overviewSubscriber {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	views := map[overviewView][]*overviewSubscriber{}
+	for sub := range p.subscribers {
+		view, _ := sub.settings()
+		views[view] = append(views[view], sub)
+	}
+	return views
+}
+
+// poll fetches a view once and fans the result out to its subscribers
+func (p *overviewPipeline) poll(ctx context.Context, view overviewView, subscribers []*overviewSubscriber) {
+	queryCtx, cancel := context.WithTimeout(ctx, overviewQueryTimeout)
+	defer cancel()
+	msg := OverviewMessage{Type: "overview", Range: view.rng, Step: view.step, Timestamp: time.Now()}
+	data, err := p.handler.fetchClusterOverview(queryCtx, p.client, p.target, p.configID, p.cluster, view.rng, view.step)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		msg = OverviewMessage{Type: "error", Range: view.rng, Step: view.step, Error: err.Error(), Timestamp: time.Now()}
+	} else {
+		msg.Data = data
+		p.mu.Lock()
+		p.latest[view] = msg
+		p.mu.Unlock()
+	}
+	for _, sub := range subscribers {
+		_, filter := sub.settings()
+		sub.deliver(selectOverview(msg, filter))
+	}
+}
+
+func (p *overviewPipeline) run(ctx context.Context) {
+	ticker := time.NewTicker(overviewRefreshInterval)
+	defer ticker.Stop()
+	pollAll := func() {
+		var wg sync.WaitGroup
+		for view, subs := range p.views() {
+			wg.Add(1)
+			go func(view overviewView, subs []*overviewSubscriber) {
+				defer wg.Done()
+				p.poll(ctx, view, subs)
+			}(view, subs)
+		}
+		wg.Wait()
+	}
+	pollAll()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pollAll()
+		case view := <-p.refresh:
+			if subs := p.views()[view]; len(subs) > 0 {
+				p.poll(ctx, view, subs)
+			}
+		}
+	}
+}
+
+// configure changes a subscriber's view, answering from the latest shared result when there is one
+func (p *overviewPipeline) configure(sub *overviewSubscriber, view overviewView, filter []string) {
+	sub.mu.Lock()
+	sub.view, sub.filter = view, filter
+	sub.mu.Unlock()
+	sub.deliver(OverviewMessage{Type: "configured", Range: view.rng, Step: view.step, Select: filter, Timestamp: time.Now()})
+
+	p.mu.Lock()
+	latest, ok := p.latest[view]
+	p.mu.Unlock()
+	if ok && time.Since(latest.Timestamp) < overviewRefreshInterval {
+		sub.deliver(selectOverview(latest, filter))
+		return
+	}
+	p.requestRefresh(view)
+}
+
+func (p *overviewPipeline) requestRefresh(view overviewView) {
+	select {
+	case p.refresh <- view:
+	default:
+	}
+}
+
+// selectOverview trims an overview payload to the sections a subscriber selected
+func selectOverview(msg OverviewMessage, filter []string) OverviewMessage {
+	payload, ok := msg.Data.(gin.H)
+	if !ok || len(filter) == 0 {
+		return msg
+	}
+	selected := map[string]bool{}
+	for _, name := range filter {
+		selected[name] = true
+	}
+	trimmed := gin.H{}
+	if selected["series"] {
+		trimmed["series"] = payload["series"]
+	}
+	if instant, ok := payload["instant"].(gin.H); ok {
+		if selected["instant"] {
+			trimmed["instant"] = instant
+		} else {
+			values := gin.H{}
+			for key, value := range instant {
+				if selected[key] {
+					values[key] = value
+				}
+			}
+			if len(values) > 0 {
+				trimmed["instant"] = values
+			}
+		}
+	}
+	msg.Data = trimmed
+	msg.Select = filter
+	return msg
+}
+
+// subscribe joins the cluster's pipeline, starting it for the first subscriber
+func (h *PrometheusHandler) subscribe(c *gin.Context, sub *overviewSubscriber) (*overviewPipeline, error) {
+	configID := c.Query("config")
+	cluster := c.Query("cluster")
+	key := configID + "/" + cluster
+
+	h.overviewMu.Lock()
+	defer h.overviewMu.Unlock()
+	if p, ok := h.overviewPipelines[key]; ok {
+		p.mu.Lock()
+		p.subscribers[sub] = struct{}{}
+		p.mu.Unlock()
+		return p, nil
+	}
+
+	client, err := h.getClient(c)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 4*time.Second)
+	defer cancel()
+	target, err := h.discoverPrometheus(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	runCtx, stop := context.WithCancel(context.Background())
+	p := &overviewPipeline{
+		handler:     h,
+		key:         key,
+		client:      client,
+		target:      target,
+		configID:    configID,
+		cluster:     cluster,
+		refresh:     make(chan overviewView, 8),
+		cancel:      stop,
+		subscribers: map[*overviewSubscriber]struct{}{sub: {}},
+		latest:      map[overviewView]OverviewMessage{},
+	}
+	h.overviewPipelines[key] = p
+	go p.run(runCtx)
+	return p, nil
+}
+
+// unsubscribe leaves a pipeline, stopping it with the last subscriber
+func (h *PrometheusHandler) unsubscribe(p *overviewPipeline, sub *overviewSubscriber) {
+	h.overviewMu.Lock()
+	defer h.overviewMu.Unlock()
+	p.mu.Lock()
+	delete(p.subscribers, sub)
+	empty := len(p.subscribers) == 0
+	p.mu.Unlock()
+	if empty {
+		p.cancel()
+		delete(h.overviewPipelines, p.key)
+	}
+}
+
+// HandleClusterOverviewWS streams the cluster overview over a WebSocket
+// @Summary Stream cluster overview over WebSocket
+// @Description WebSocket variant of the cluster overview stream. Clients change the range, step and selected sections on the fly by sending {"type":"configure","range":"1h","step":"60s","select":["series","node_count"]}, force an update with {"type":"refresh"} and keep the connection alive with {"type":"ping"}. One upstream Prometheus pipeline per cluster serves all subscribers, and subscribers with the same range and step share the same queries.
+// @Tags Metrics
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param range query string false "Initial range, e.g. 15m, 1h, 1d (default 15m)"
+// @Param step query string false "Initial step, e.g. 15s (default 15s)"
+// @Param select query string false "Comma-separated sections to send: series, instant or instant keys"
+// @Success 101 {string} string "Switching Protocols"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/metrics/overview/prometheus/ws [get]
+func (h *PrometheusHandler) HandleClusterOverviewWS(c *gin.Context) {
+	if c.Query("config") == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "config parameter is required"})
+		return
+	}
+	conn, err := overviewUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to upgrade cluster overview connection to WebSocket")
+		return
+	}
+	defer conn.Close()
+
+	sub := &overviewSubscriber{out: make(chan OverviewMessage, overviewSendQueue)}
+	view := overviewView{rng: c.DefaultQuery("range", "15m"), step: c.DefaultQuery("step", "15s")}
+	var filter []string
+	if v := c.Query("select"); v != "" {
+		filter = strings.Split(v, ",")
+	}
+	sub.view, sub.filter = view, filter
+
+	pipeline, err := h.subscribe(c, sub)
+	if err != nil {
+		conn.SetWriteDeadline(time.Now().Add(overviewWriteTimeout))
+		conn.WriteJSON(OverviewMessage{Type: "error", Error: err.Error(), Timestamp: time.Now()})
+		return
+	}
+	defer h.unsubscribe(pipeline, sub)
+	pipeline.configure(sub, view, filter)
+
+	// Reader: control messages from the client
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			var control OverviewControl
+			if err := conn.ReadJSON(&control); err != nil {
+				return
+			}
+			switch control.Type {
+			case "configure":
+				current, currentFilter := sub.settings()
+				if control.Range != "" {
+					current.rng = control.Range
+				}
+				if control.Step != "" {
+					current.step = control.Step
+				}
+				if control.Select != nil {
+					currentFilter = control.Select
+				}
+				pipeline.configure(sub, current, currentFilter)
+			case "refresh":
+				current, _ := sub.settings()
+				pipeline.requestRefresh(current)
+			case "ping":
+				sub.deliver(OverviewMessage{Type: "pong", Timestamp: time.Now()})
+			}
+		}
+	}()
+
+	// Writer: the only goroutine writing to the connection
+	for {
+		select {
+		case <-done:
+			return
+		case msg := <-sub.out:
+			conn.SetWriteDeadline(time.Now().Add(overviewWriteTimeout))
+			if err := conn.WriteJSON(msg); err != nil {
+				return
+			}
+		}
+	}
+}
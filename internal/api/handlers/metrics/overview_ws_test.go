@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSelectOverview(t *testing.T) {
+	msg := OverviewMessage{Type: "overview", Data: gin.H{
+		"series":  []string{"node_count"},
+		"instant": gin.H{"node_count": 3, "cpu_packing": 0.5},
+	}}
+
+	if got := selectOverview(msg, nil); len(got.Data.(gin.H)) != 2 {
+		t.Errorf("expected empty selection to keep everything, got %v", got.Data)
+	}
+
+	got := selectOverview(msg, []string{"node_count"}).Data.(gin.H)
+	if _, ok := got["series"]; ok {
+		t.Errorf("expected series to be dropped, got %v", got)
+	}
+	instant := got["instant"].(gin.H)
+	if len(instant) != 1 || instant["node_count"] != 3 {
+		t.Errorf("expected only node_count, got %v", instant)
+	}
+
+	got = selectOverview(msg, []string{"series", "instant"}).Data.(gin.H)
+	if len(got["instant"].(gin.H)) != 2 || got["series"] == nil {
+		t.Errorf("expected series and all instant values, got %v", got)
+	}
+	if len(msg.Data.(gin.H)["instant"].(gin.H)) != 2 {
+		t.Errorf("selection must not modify the shared payload")
+	}
+}
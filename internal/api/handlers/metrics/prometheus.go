@@ -13,6 +13,7 @@ import (
 	"github.com/Facets-cloud/kube-dash/internal/api/utils"
 	"github.com/Facets-cloud/kube-dash/internal/k8s"
 	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/internal/thresholds"
 	"github.com/Facets-cloud/kube-dash/internal/tracing"
 	"github.com/Facets-cloud/kube-dash/pkg/logger"
 
@@ -35,23 +36,34 @@ type PrometheusHandler struct {
 	logger        *logger.Logger
 	sseHandler    *utils.SSEHandler
 	tracingHelper *tracing.TracingHelper
+	thresholds    *thresholds.Manager
 
 	// Cache for metrics operations
 	cache    map[string]CacheEntry
 	cacheMux sync.RWMutex
 	cacheTTL time.Duration
+
+	// Discovered Prometheus targets keyed by cache key, used by Query
+	targets sync.Map
+
+	// Shared cluster overview pipelines keyed by config and cluster, used by HandleClusterOverviewWS
+	overviewPipelines map[string]*overviewPipeline
+	overviewMu        sync.Mutex
 }
 
 // NewPrometheusHandler creates a new Prometheus metrics handler
-func NewPrometheusHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger) *PrometheusHandler {
+func NewPrometheusHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger, thresholdManager *thresholds.Manager) *PrometheusHandler {
 	return &PrometheusHandler{
 		store:         store,
 		clientFactory: clientFactory,
 		logger:        log,
 		sseHandler:    utils.NewSSEHandler(log),
 		tracingHelper: tracing.GetTracingHelper(),
+		thresholds:    thresholdManager,
 		cache:         make(map[string]CacheEntry),
 		cacheTTL:      5 * time.Minute, // 5 minute cache TTL for metrics
+
+		overviewPipelines: make(map[string]*overviewPipeline),
 	}
 }
 
@@ -96,7 +108,7 @@ func (h *PrometheusHandler) clearExpiredCache() {
 }
 
 // getClient returns a Kubernetes client for the given config and cluster
-func (h *PrometheusHandler) getClient(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *PrometheusHandler) getClient(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 	if configID == "" {
@@ -121,18 +133,25 @@ type promTarget struct {
 	Service   string
 	PortName  string
 	IsService bool
+	// Topology describes what was chosen among the candidates; Replicas counts HA twins of a pod
+	Topology string
+	Replicas int
+	// Dedup asks a Thanos querier to merge the series of HA replicas
+	Dedup bool
 }
 
-// discoverPrometheus attempts to find a running Prometheus pod and port in the cluster
-func (h *PrometheusHandler) discoverPrometheus(ctx context.Context, client *kubernetes.Clientset) (*promTarget, error) {
+// discoverPrometheus attempts to find a running Prometheus pod and port in the cluster. A Thanos
+// query frontend or querier wins over Prometheus pods, and among HA replicas the longest running
+// ready replica is chosen so repeated discoveries stick to the same one.
+func (h *PrometheusHandler) discoverPrometheus(ctx context.Context, client kubernetes.Interface) (*promTarget, error) {
+	if thanos, err := h.discoverThanosQuery(ctx, client); err == nil {
+		return thanos, nil
+	}
+
 	// First, simplified path: look for pods with the canonical label
 	labeledPods, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{LabelSelector: "app.kubernetes.io/name=prometheus"})
 	if err == nil {
-		for _, p := range labeledPods.Items {
-			// Ensure running
-			if p.Status.Phase != v1.PodRunning {
-				continue
-			}
+		for _, p := range orderPrometheusReplicas(labeledPods.Items) {
 			// Pick first matching port (9090 or any name containing 'web')
 			port := 0
 			for _, c := range p.Spec.Containers {
@@ -151,7 +170,7 @@ func (h *PrometheusHandler) discoverPrometheus(ctx context.Context, client *kube
 			}
 			// Verify target
 			if h.verifyPrometheus(ctx, client, p.Namespace, p.Name, port) == nil {
-				return &promTarget{Namespace: p.Namespace, Pod: p.Name, Port: port}, nil
+				return podTarget(&p, port, labeledPods.Items), nil
 			}
 		}
 	}
@@ -194,12 +213,12 @@ func (h *PrometheusHandler) discoverPrometheus(ctx context.Context, client *kube
 	for _, ns := range namespaces {
 		pods, err := client.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{})
 		if err == nil {
-			for _, p := range pods.Items {
+			for _, p := range orderPrometheusReplicas(pods.Items) {
 				ok, port := isPromPod(&p)
 				if ok {
 					// Verify
 					if h.verifyPrometheus(ctx, client, ns, p.Name, port) == nil {
-						return &promTarget{Namespace: ns, Pod: p.Name, Port: port}, nil
+						return podTarget(&p, port, pods.Items), nil
 					}
 				}
 			}
@@ -211,11 +230,11 @@ func (h *PrometheusHandler) discoverPrometheus(ctx context.Context, client *kube
 	if err != nil {
 		return nil, fmt.Errorf("failed to list pods for discovery: %w", err)
 	}
-	for _, p := range pods.Items {
+	for _, p := range orderPrometheusReplicas(pods.Items) {
 		ok, port := isPromPod(&p)
 		if ok {
 			if h.verifyPrometheus(ctx, client, p.Namespace, p.Name, port) == nil {
-				return &promTarget{Namespace: p.Namespace, Pod: p.Name, Port: port}, nil
+				return podTarget(&p, port, pods.Items), nil
 			}
 		}
 	}
@@ -229,7 +248,7 @@ func (h *PrometheusHandler) discoverPrometheus(ctx context.Context, client *kube
 }
 
 // verifyPrometheus calls /api/v1/status/buildinfo via pod proxy to confirm target
-func (h *PrometheusHandler) verifyPrometheus(ctx context.Context, client *kubernetes.Clientset, namespace, pod string, port int) error {
+func (h *PrometheusHandler) verifyPrometheus(ctx context.Context, client kubernetes.Interface, namespace, pod string, port int) error {
 	// GET /api/v1/status/buildinfo
 	raw, err := client.CoreV1().RESTClient().Get().
 		Namespace(namespace).
@@ -253,7 +272,7 @@ func (h *PrometheusHandler) verifyPrometheus(ctx context.Context, client *kubern
 }
 
 // proxyPrometheus performs a GET call against the Prometheus HTTP API via pod/service proxy
-func (h *PrometheusHandler) proxyPrometheus(ctx context.Context, client *kubernetes.Clientset, target *promTarget, path string, params map[string]string) ([]byte, error) {
+func (h *PrometheusHandler) proxyPrometheus(ctx context.Context, client kubernetes.Interface, target *promTarget, path string, params map[string]string) ([]byte, error) {
 	req := client.CoreV1().RESTClient().Get().
 		Namespace(target.Namespace)
 	// Choose pod or service proxy
@@ -277,11 +296,43 @@ func (h *PrometheusHandler) proxyPrometheus(ctx context.Context, client *kuberne
 	for k, v := range params {
 		req = req.Param(k, v)
 	}
+	if target.Dedup {
+		for k, v := range dedupParams(path, params) {
+			req = req.Param(k, v)
+		}
+	}
 	return req.DoRaw(ctx)
 }
 
+// PrometheusQuerier runs Prometheus API requests against a cluster. PrometheusHandler implements
+// it for the handlers and subsystems that query Prometheus outside its own endpoints.
+type PrometheusQuerier interface {
+	Query(ctx context.Context, client kubernetes.Interface, targetKey, path string, params map[string]string) ([]byte, error)
+}
+
+var _ PrometheusQuerier = (*PrometheusHandler)(nil)
+
+// Query runs a Prometheus API request for callers outside the HTTP handlers.
+// The discovered target is cached under targetKey and rediscovered on failure.
+func (h *PrometheusHandler) Query(ctx context.Context, client kubernetes.Interface, targetKey, path string, params map[string]string) ([]byte, error) {
+	if cached, ok := h.targets.Load(targetKey); ok {
+		raw, err := h.proxyPrometheus(ctx, client, cached.(*promTarget), path, params)
+		if err == nil {
+			return raw, nil
+		}
+		h.targets.Delete(targetKey)
+	}
+
+	target, err := h.discoverPrometheus(ctx, client)
+	if err != nil || target == nil {
+		return nil, fmt.Errorf("prometheus not available: %v", err)
+	}
+	h.targets.Store(targetKey, target)
+	return h.proxyPrometheus(ctx, client, target, path, params)
+}
+
 // discoverPrometheusViaService finds a Prometheus Service by common names/labels/ports and verifies it
-func (h *PrometheusHandler) discoverPrometheusViaService(ctx context.Context, client *kubernetes.Clientset) (*promTarget, error) {
+func (h *PrometheusHandler) discoverPrometheusViaService(ctx context.Context, client kubernetes.Interface) (*promTarget, error) {
 	svcs, err := client.CoreV1().Services("").List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, err
@@ -307,14 +358,14 @@ func (h *PrometheusHandler) discoverPrometheusViaService(ctx context.Context, cl
 	}
 	for _, c := range candidates {
 		if err := h.verifyPrometheusService(ctx, client, c.ns, c.name, c.portName, c.port); err == nil {
-			return &promTarget{Namespace: c.ns, Service: c.name, PortName: c.portName, Port: c.port, IsService: true}, nil
+			return &promTarget{Namespace: c.ns, Service: c.name, PortName: c.portName, Port: c.port, IsService: true, Topology: topologyService}, nil
 		}
 	}
 	return nil, fmt.Errorf("prometheus service not found")
 }
 
 // verifyPrometheusService calls buildinfo via the Service proxy
-func (h *PrometheusHandler) verifyPrometheusService(ctx context.Context, client *kubernetes.Clientset, namespace, svcName, portName string, port int) error {
+func (h *PrometheusHandler) verifyPrometheusService(ctx context.Context, client kubernetes.Interface, namespace, svcName, portName string, port int) error {
 	req := client.CoreV1().RESTClient().Get().
 		Namespace(namespace).
 		Resource("services").
@@ -340,23 +391,47 @@ func (h *PrometheusHandler) verifyPrometheusService(ctx context.Context, client
 	return nil
 }
 
+// PrometheusAvailability reports whether Prometheus is installed and reachable, and where it was
+// found: a pod, or a service when discovered through one. Topology is single, ha-replica (one of
+// Replicas HA twins, queried without deduplication), service, thanos-query or thanos-query-frontend.
+type PrometheusAvailability struct {
+	Installed bool   `json:"installed"`
+	Reachable bool   `json:"reachable"`
+	Namespace string `json:"namespace,omitempty"`
+	Pod       string `json:"pod,omitempty"`
+	Service   string `json:"service,omitempty"`
+	Port      int    `json:"port,omitempty"`
+	PortName  string `json:"portName,omitempty"`
+	Topology  string `json:"topology,omitempty"`
+	Replicas  int    `json:"replicas,omitempty"`
+	Dedup     bool   `json:"dedup,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// PodMetricsPayload is an update of the pod metrics stream: CPU, memory and network series
+type PodMetricsPayload struct {
+	Series []series `json:"series"`
+}
+
 // GetAvailability returns whether Prometheus is installed and reachable
 // @Summary Check Prometheus availability
-// @Description Checks if Prometheus is installed and reachable in the cluster
+// @Description Checks if Prometheus is installed and reachable in the cluster and reports the chosen topology. A Thanos query frontend or querier is preferred and queried with deduplication; otherwise the longest running replica of an HA pair is used.
 // @Tags Metrics
 // @Accept json
 // @Produce json
 // @Param config query string true "Kubernetes config ID"
 // @Param cluster query string false "Cluster name"
-// @Success 200 {object} map[string]interface{} "Prometheus availability status"
-// @Failure 400 {object} map[string]string "Bad request"
+// @Success 200 {object} PrometheusAvailability "Prometheus availability status"
+// @Failure 400 {object} PrometheusAvailability "Bad request"
 // @Security BearerAuth
 // @Security KubeConfig
 // @Router /api/v1/metrics/prometheus/availability [get]
+// @Router /api/v2/metrics/prometheus/availability [get]
 func (h *PrometheusHandler) GetAvailability(c *gin.Context) {
 	client, err := h.getClient(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"installed": false, "reachable": false, "error": err.Error()})
+		c.JSON(http.StatusBadRequest, PrometheusAvailability{Error: err.Error()})
 		return
 	}
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
@@ -365,19 +440,12 @@ func (h *PrometheusHandler) GetAvailability(c *gin.Context) {
 	// Try full discovery (verifies Prometheus is reachable and healthy)
 	target, err := h.discoverPrometheus(ctx, client)
 	if err == nil && target != nil {
-		resp := gin.H{
-			"installed": true,
-			"reachable": true,
-			"namespace": target.Namespace,
-			"port":      target.Port,
-		}
+		resp := PrometheusAvailability{Installed: true, Reachable: true, Namespace: target.Namespace, Port: target.Port,
+			Topology: target.Topology, Replicas: target.Replicas, Dedup: target.Dedup}
 		if target.IsService {
-			resp["service"] = target.Service
-			if target.PortName != "" {
-				resp["portName"] = target.PortName
-			}
+			resp.Service, resp.PortName = target.Service, target.PortName
 		} else {
-			resp["pod"] = target.Pod
+			resp.Pod = target.Pod
 		}
 		c.JSON(http.StatusOK, resp)
 		return
@@ -425,11 +493,11 @@ func (h *PrometheusHandler) GetAvailability(c *gin.Context) {
 	}
 
 	if present {
-		c.JSON(http.StatusOK, gin.H{"installed": true, "reachable": false, "reason": "prometheus detected but unreachable"})
+		c.JSON(http.StatusOK, PrometheusAvailability{Installed: true, Reason: "prometheus detected but unreachable"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"installed": false, "reachable": false})
+	c.JSON(http.StatusOK, PrometheusAvailability{})
 }
 
 // ---------- Helpers for Prometheus responses ----------
@@ -527,7 +595,7 @@ func strconvParseFloat(s string) (float64, error) { return json.Number(s).Float6
 // @Param name path string true "Pod name"
 // @Param range query string false "Time range for metrics" default(15m)
 // @Param step query string false "Step interval for metrics" default(15s)
-// @Success 200 {object} map[string]interface{} "Stream of pod metrics"
+// @Success 200 {object} PodMetricsPayload "Stream of pod metrics"
 // @Failure 400 {object} map[string]string "Bad request"
 // @Failure 404 {object} map[string]string "Prometheus not available"
 // @Security BearerAuth
@@ -630,8 +698,8 @@ func (h *PrometheusHandler) GetPodMetricsSSE(c *gin.Context) {
 		txSeries, _ := parseMatrix(txRaw)
 
 		h.tracingHelper.RecordSuccess(querySpan, "All Prometheus queries completed successfully")
-		payload := gin.H{
-			"series": append(append(cpuSeries, memSeries...), append(rxSeries, txSeries...)...),
+		payload := PodMetricsPayload{
+			Series: append(append(cpuSeries, memSeries...), append(rxSeries, txSeries...)...),
 		}
 		return payload, nil
 	}
@@ -644,7 +712,9 @@ func (h *PrometheusHandler) GetPodMetricsSSE(c *gin.Context) {
 	h.sseHandler.SendSSEResponseWithUpdates(c, initial, fetch)
 }
 
-// GetPodEnhancedMetricsSSE streams enhanced Prometheus-based pod metrics with CPU average/maximum and memory usage
+// GetPodEnhancedMetricsSSE streams enhanced Prometheus-based pod metrics with CPU average/maximum and memory usage.
+// Matching threshold rules, and inline thresholds from the thresholds parameter, are evaluated on
+// every update and reported under "alerts".
 func (h *PrometheusHandler) GetPodEnhancedMetricsSSE(c *gin.Context) {
 	// Start child span for client setup
 	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
@@ -663,6 +733,12 @@ func (h *PrometheusHandler) GetPodEnhancedMetricsSSE(c *gin.Context) {
 	rng := c.DefaultQuery("range", "15m")
 	step := c.DefaultQuery("step", "15s")
 
+	alertSession, err := h.thresholdSession(c, thresholds.KindPod, namespace, name)
+	if err != nil {
+		h.sseHandler.SendSSEError(c, http.StatusBadRequest, "invalid thresholds: "+err.Error())
+		return
+	}
+
 	// Scale timeout based on range duration for longer queries
 	timeoutDuration := 4 * time.Second
 	rangeDuration := parsePromRange(rng)
@@ -973,7 +1049,8 @@ func (h *PrometheusHandler) GetPodEnhancedMetricsSSE(c *gin.Context) {
 		} else {
 			h.logger.Info("No VPA recommendations found for pod:", "namespace", namespace, "name", name)
 		}
-		return payload, nil
+		samples := podAlertSamples(cpuAvgSeries, cpuMaxSeries, memUsageSeries, cpuLimit, memoryLimit, cpuRequest, memoryRequest)
+		return withAlerts(payload, alertSession, samples), nil
 	}
 
 	initial, err := fetch()
@@ -986,7 +1063,9 @@ func (h *PrometheusHandler) GetPodEnhancedMetricsSSE(c *gin.Context) {
 
 // ---------- Node metrics ----------
 
-// GetNodeMetricsSSE streams Prometheus-based node metrics as SSE
+// GetNodeMetricsSSE streams Prometheus-based node metrics as SSE.
+// Matching threshold rules, and inline thresholds from the thresholds parameter, are evaluated on
+// every update and reported under "alerts".
 func (h *PrometheusHandler) GetNodeMetricsSSE(c *gin.Context) {
 	client, err := h.getClient(c)
 	if err != nil {
@@ -999,6 +1078,12 @@ func (h *PrometheusHandler) GetNodeMetricsSSE(c *gin.Context) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
+	alertSession, err := h.thresholdSession(c, thresholds.KindNode, "", nodeName)
+	if err != nil {
+		h.sseHandler.SendSSEError(c, http.StatusBadRequest, "invalid thresholds: "+err.Error())
+		return
+	}
+
 	// Clear expired cache entries periodically
 	h.clearExpiredCache()
 
@@ -1052,7 +1137,8 @@ func (h *PrometheusHandler) GetNodeMetricsSSE(c *gin.Context) {
 		// Check cache first
 		if cachedData, found := h.getFromCache(cacheKey); found {
 			h.logger.Debug("Returning cached node metrics", "node", nodeName, "range", rng)
-			return cachedData, nil
+			snapshot := cachedData.(nodeMetricsSnapshot)
+			return withAlerts(snapshot.payload, alertSession, snapshot.samples), nil
 		}
 
 		now := time.Now()
@@ -1324,10 +1410,11 @@ func (h *PrometheusHandler) GetNodeMetricsSSE(c *gin.Context) {
 		}
 
 		// Cache the result
-		h.setCache(cacheKey, payload, h.cacheTTL)
+		samples := nodeAlertSamples(cpuSeries, memSeries, fsSeries, cpuUtilSeries, memUtilSeries)
+		h.setCache(cacheKey, nodeMetricsSnapshot{payload: payload, samples: samples}, h.cacheTTL)
 		h.logger.Debug("Cached node metrics", "node", nodeName, "range", rng, "ttl", h.cacheTTL)
 
-		return payload, nil
+		return withAlerts(payload, alertSession, samples), nil
 	}
 
 	initial, err := fetch()
@@ -1394,6 +1481,20 @@ func (h *PrometheusHandler) GetClusterOverviewSSE(c *gin.Context) {
 		return
 	}
 
+	fetch := func() (interface{}, error) {
+		return h.fetchClusterOverview(c.Request.Context(), client, target, configID, cluster, rng, step)
+	}
+
+	initial, err := fetch()
+	if err != nil {
+		h.sseHandler.SendSSEError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.sseHandler.SendSSEResponseWithUpdates(c, initial, fetch)
+}
+
+// fetchClusterOverview runs the cluster overview queries for a range and step
+func (h *PrometheusHandler) fetchClusterOverview(ctx context.Context, client kubernetes.Interface, target *promTarget, configID, cluster, rng, step string) (gin.H, error) {
 	// New cluster stats queries
 	// Node count: rely on kube-state-metrics condition for Ready nodes
 	qNodeCount := "sum(kube_node_status_condition{condition=\"Ready\",status=\"true\"} == 1)"
@@ -1408,135 +1509,126 @@ func (h *PrometheusHandler) GetClusterOverviewSSE(c *gin.Context) {
 	qTotalAllocatableMemory := "sum(kube_node_status_allocatable{resource=\"memory\"})"
 	qTotalMemoryRequests := "sum(kube_pod_container_resource_requests{resource=\"memory\"})"
 
-	fetch := func() (interface{}, error) {
-		now := time.Now()
-		start := now.Add(-parsePromRange(rng))
-		params := map[string]string{
-			"start": fmt.Sprintf("%d", start.Unix()),
-			"end":   fmt.Sprintf("%d", now.Unix()),
-			"step":  step,
-		}
-
-		// Node count series
-		params["query"] = qNodeCount
-		nodeCountRaw, err := h.proxyPrometheus(c.Request.Context(), client, target, "/api/v1/query_range", params)
-		if err != nil {
-			return nil, err
-		}
-		nodeCountSeries, _ := parseMatrix(nodeCountRaw)
-		for i := range nodeCountSeries {
-			nodeCountSeries[i].Metric = "node_count"
-		}
+	now := time.Now()
+	start := now.Add(-parsePromRange(rng))
+	params := map[string]string{
+		"start": fmt.Sprintf("%d", start.Unix()),
+		"end":   fmt.Sprintf("%d", now.Unix()),
+		"step":  step,
+	}
 
-		// CPU packing series
-		params["query"] = qCPUPacking
-		cpuPackingRaw, err := h.proxyPrometheus(c.Request.Context(), client, target, "/api/v1/query_range", params)
-		if err != nil {
-			return nil, err
-		}
-		cpuPackingSeries, _ := parseMatrix(cpuPackingRaw)
+	// Node count series
+	params["query"] = qNodeCount
+	nodeCountRaw, err := h.proxyPrometheus(ctx, client, target, "/api/v1/query_range", params)
+	if err != nil {
+		return nil, err
+	}
+	nodeCountSeries, _ := parseMatrix(nodeCountRaw)
+	for i := range nodeCountSeries {
+		nodeCountSeries[i].Metric = "node_count"
+	}
 
-		// Memory packing series
-		params["query"] = qMemoryPacking
-		memoryPackingRaw, err := h.proxyPrometheus(c.Request.Context(), client, target, "/api/v1/query_range", params)
-		if err != nil {
-			return nil, err
-		}
-		memoryPackingSeries, _ := parseMatrix(memoryPackingRaw)
+	// CPU packing series
+	params["query"] = qCPUPacking
+	cpuPackingRaw, err := h.proxyPrometheus(ctx, client, target, "/api/v1/query_range", params)
+	if err != nil {
+		return nil, err
+	}
+	cpuPackingSeries, _ := parseMatrix(cpuPackingRaw)
 
-		// Instant values for current metrics
-		nodeCountInstantRaw, _ := h.proxyPrometheus(c.Request.Context(), client, target, "/api/v1/query", map[string]string{"query": qNodeCount})
-		nodeCountInstant, _ := parseVectorSum(nodeCountInstantRaw)
-		cpuPackingInstantRaw, _ := h.proxyPrometheus(c.Request.Context(), client, target, "/api/v1/query", map[string]string{"query": qCPUPacking})
-		cpuPackingInstant, _ := parseVectorSum(cpuPackingInstantRaw)
-		memoryPackingInstantRaw, _ := h.proxyPrometheus(c.Request.Context(), client, target, "/api/v1/query", map[string]string{"query": qMemoryPacking})
-		memoryPackingInstant, _ := parseVectorSum(memoryPackingInstantRaw)
-
-		// CPU allocation summary metrics
-		totalAllocatableCPURaw, _ := h.proxyPrometheus(c.Request.Context(), client, target, "/api/v1/query", map[string]string{"query": qTotalAllocatableCPU})
-		totalAllocatableCPU, _ := parseVectorSum(totalAllocatableCPURaw)
-		totalCPURequestsRaw, _ := h.proxyPrometheus(c.Request.Context(), client, target, "/api/v1/query", map[string]string{"query": qTotalCPURequests})
-		totalCPURequests, _ := parseVectorSum(totalCPURequestsRaw)
-
-		// Memory allocation summary metrics
-		totalAllocatableMemoryRaw, _ := h.proxyPrometheus(c.Request.Context(), client, target, "/api/v1/query", map[string]string{"query": qTotalAllocatableMemory})
-		totalAllocatableMemory, _ := parseVectorSum(totalAllocatableMemoryRaw)
-		totalMemoryRequestsRaw, _ := h.proxyPrometheus(c.Request.Context(), client, target, "/api/v1/query", map[string]string{"query": qTotalMemoryRequests})
-		totalMemoryRequests, _ := parseVectorSum(totalMemoryRequestsRaw)
-
-		// Pods capacity (max accommodated) and present (any phase)
-		qPodsCapacityWithUnit := `sum(kube_node_status_capacity{resource="pods",unit="integer"})`
-		qPodsCapacity := `sum(kube_node_status_capacity{resource="pods"})`
-		qPodsCapacityLegacy := `sum(kube_node_status_capacity_pods)`
-		qPodsPresent := `sum(max by (namespace,pod) (kube_pod_status_phase == 1))`
-
-		podsCapacity := 0.0
-		if raw, err := h.proxyPrometheus(c.Request.Context(), client, target, "/api/v1/query", map[string]string{"query": qPodsCapacityWithUnit}); err == nil {
+	// Memory packing series
+	params["query"] = qMemoryPacking
+	memoryPackingRaw, err := h.proxyPrometheus(ctx, client, target, "/api/v1/query_range", params)
+	if err != nil {
+		return nil, err
+	}
+	memoryPackingSeries, _ := parseMatrix(memoryPackingRaw)
+
+	// Instant values for current metrics
+	nodeCountInstantRaw, _ := h.proxyPrometheus(ctx, client, target, "/api/v1/query", map[string]string{"query": qNodeCount})
+	nodeCountInstant, _ := parseVectorSum(nodeCountInstantRaw)
+	cpuPackingInstantRaw, _ := h.proxyPrometheus(ctx, client, target, "/api/v1/query", map[string]string{"query": qCPUPacking})
+	cpuPackingInstant, _ := parseVectorSum(cpuPackingInstantRaw)
+	memoryPackingInstantRaw, _ := h.proxyPrometheus(ctx, client, target, "/api/v1/query", map[string]string{"query": qMemoryPacking})
+	memoryPackingInstant, _ := parseVectorSum(memoryPackingInstantRaw)
+
+	// CPU allocation summary metrics
+	totalAllocatableCPURaw, _ := h.proxyPrometheus(ctx, client, target, "/api/v1/query", map[string]string{"query": qTotalAllocatableCPU})
+	totalAllocatableCPU, _ := parseVectorSum(totalAllocatableCPURaw)
+	totalCPURequestsRaw, _ := h.proxyPrometheus(ctx, client, target, "/api/v1/query", map[string]string{"query": qTotalCPURequests})
+	totalCPURequests, _ := parseVectorSum(totalCPURequestsRaw)
+
+	// Memory allocation summary metrics
+	totalAllocatableMemoryRaw, _ := h.proxyPrometheus(ctx, client, target, "/api/v1/query", map[string]string{"query": qTotalAllocatableMemory})
+	totalAllocatableMemory, _ := parseVectorSum(totalAllocatableMemoryRaw)
+	totalMemoryRequestsRaw, _ := h.proxyPrometheus(ctx, client, target, "/api/v1/query", map[string]string{"query": qTotalMemoryRequests})
+	totalMemoryRequests, _ := parseVectorSum(totalMemoryRequestsRaw)
+
+	// Pods capacity (max accommodated) and present (any phase)
+	qPodsCapacityWithUnit := `sum(kube_node_status_capacity{resource="pods",unit="integer"})`
+	qPodsCapacity := `sum(kube_node_status_capacity{resource="pods"})`
+	qPodsCapacityLegacy := `sum(kube_node_status_capacity_pods)`
+	qPodsPresent := `sum(max by (namespace,pod) (kube_pod_status_phase == 1))`
+
+	podsCapacity := 0.0
+	if raw, err := h.proxyPrometheus(ctx, client, target, "/api/v1/query", map[string]string{"query": qPodsCapacityWithUnit}); err == nil {
+		podsCapacity, _ = parseVectorSum(raw)
+	}
+	if podsCapacity == 0 {
+		if raw, err := h.proxyPrometheus(ctx, client, target, "/api/v1/query", map[string]string{"query": qPodsCapacity}); err == nil {
 			podsCapacity, _ = parseVectorSum(raw)
 		}
-		if podsCapacity == 0 {
-			if raw, err := h.proxyPrometheus(c.Request.Context(), client, target, "/api/v1/query", map[string]string{"query": qPodsCapacity}); err == nil {
-				podsCapacity, _ = parseVectorSum(raw)
-			}
-		}
-		if podsCapacity == 0 {
-			if raw, err := h.proxyPrometheus(c.Request.Context(), client, target, "/api/v1/query", map[string]string{"query": qPodsCapacityLegacy}); err == nil {
-				podsCapacity, _ = parseVectorSum(raw)
-			}
+	}
+	if podsCapacity == 0 {
+		if raw, err := h.proxyPrometheus(ctx, client, target, "/api/v1/query", map[string]string{"query": qPodsCapacityLegacy}); err == nil {
+			podsCapacity, _ = parseVectorSum(raw)
 		}
+	}
 
-		podsPresentRaw, _ := h.proxyPrometheus(c.Request.Context(), client, target, "/api/v1/query", map[string]string{"query": qPodsPresent})
-		podsPresent, _ := parseVectorSum(podsPresentRaw)
+	podsPresentRaw, _ := h.proxyPrometheus(ctx, client, target, "/api/v1/query", map[string]string{"query": qPodsPresent})
+	podsPresent, _ := parseVectorSum(podsPresentRaw)
 
-		// Kubernetes server version (best-effort)
-		k8sVersion := ""
-		if info, err := client.Discovery().ServerVersion(); err == nil && info != nil {
-			if info.GitVersion != "" {
-				k8sVersion = info.GitVersion
-			} else if info.String() != "" {
-				k8sVersion = info.String()
-			}
+	// Kubernetes server version (best-effort)
+	k8sVersion := ""
+	if info, err := client.Discovery().ServerVersion(); err == nil && info != nil {
+		if info.GitVersion != "" {
+			k8sVersion = info.GitVersion
+		} else if info.String() != "" {
+			k8sVersion = info.String()
 		}
+	}
 
-		// Metrics server availability (best-effort, short timeout)
-		metricsServer := false
-		if configID != "" {
-			if cfg, err := h.store.GetKubeConfig(configID); err == nil {
-				if mClient, err := h.clientFactory.GetMetricsClientForConfig(cfg, cluster); err == nil && mClient != nil {
-					ctx2, cancel2 := context.WithTimeout(c.Request.Context(), 800*time.Millisecond)
-					defer cancel2()
-					if _, err := mClient.MetricsV1beta1().NodeMetricses().List(ctx2, metav1.ListOptions{Limit: 1}); err == nil {
-						metricsServer = true
-					}
+	// Metrics server availability (best-effort, short timeout)
+	metricsServer := false
+	if configID != "" {
+		if cfg, err := h.store.GetKubeConfig(configID); err == nil {
+			if mClient, err := h.clientFactory.GetMetricsClientForConfig(cfg, cluster); err == nil && mClient != nil {
+				ctx2, cancel2 := context.WithTimeout(ctx, 800*time.Millisecond)
+				defer cancel2()
+				if _, err := mClient.MetricsV1beta1().NodeMetricses().List(ctx2, metav1.ListOptions{Limit: 1}); err == nil {
+					metricsServer = true
 				}
 			}
 		}
-
-		payload := gin.H{
-			"series": append(append(nodeCountSeries, cpuPackingSeries...), memoryPackingSeries...),
-			"instant": gin.H{
-				"node_count":               nodeCountInstant,
-				"cpu_packing":              cpuPackingInstant,
-				"memory_packing":           memoryPackingInstant,
-				"total_allocatable_cpu":    totalAllocatableCPU,
-				"total_cpu_requests":       totalCPURequests,
-				"total_allocatable_memory": totalAllocatableMemory,
-				"total_memory_requests":    totalMemoryRequests,
-				"pods_capacity":            podsCapacity,
-				"pods_present":             podsPresent,
-				"kubernetes_version":       k8sVersion,
-				"metrics_server":           metricsServer,
-			},
-		}
-		return payload, nil
 	}
 
-	initial, err := fetch()
-	if err != nil {
-		h.sseHandler.SendSSEError(c, http.StatusInternalServerError, err.Error())
-		return
+	payload := gin.H{
+		"series": append(append(nodeCountSeries, cpuPackingSeries...), memoryPackingSeries...),
+		"instant": gin.H{
+			"node_count":               nodeCountInstant,
+			"cpu_packing":              cpuPackingInstant,
+			"memory_packing":           memoryPackingInstant,
+			"total_allocatable_cpu":    totalAllocatableCPU,
+			"total_cpu_requests":       totalCPURequests,
+			"total_allocatable_memory": totalAllocatableMemory,
+			"total_memory_requests":    totalMemoryRequests,
+			"pods_capacity":            podsCapacity,
+			"pods_present":             podsPresent,
+			"kubernetes_version":       k8sVersion,
+			"metrics_server":           metricsServer,
+		},
 	}
-	h.sseHandler.SendSSEResponseWithUpdates(c, initial, fetch)
+	return payload, nil
 }
 
 // ---------- utilities ----------
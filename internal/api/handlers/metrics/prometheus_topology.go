@@ -0,0 +1,169 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Topologies of the discovered Prometheus target, reported by GetAvailability
+const (
+	topologySingle              = "single"
+	topologyHAReplica           = "ha-replica"
+	topologyService             = "service"
+	topologyThanosQuery         = "thanos-query"
+	topologyThanosQueryFrontend = "thanos-query-frontend"
+)
+
+// thanosQueryRole classifies a Service as a Thanos query frontend or querier. The frontend
+// ranks first because it caches and splits range queries in front of the querier.
+func thanosQueryRole(svc *v1.Service) (string, bool) {
+	name := strings.ToLower(svc.Name)
+	component := strings.ToLower(svc.Labels["app.kubernetes.io/component"])
+	app := strings.ToLower(svc.Labels["app.kubernetes.io/name"])
+	switch {
+	case app == "thanos-query-frontend" || component == "query-frontend" || strings.Contains(name, "query-frontend"):
+		return topologyThanosQueryFrontend, true
+	case app == "thanos-query" || app == "thanos-querier" || (strings.Contains(app, "thanos") && component == "query") ||
+		strings.Contains(name, "thanos-query") || strings.Contains(name, "thanos-querier"):
+		return topologyThanosQuery, true
+	}
+	return "", false
+}
+
+// thanosQueryPort picks the HTTP port of a Thanos query Service, skipping the gRPC StoreAPI port
+func thanosQueryPort(svc *v1.Service) (string, int, bool) {
+	for _, p := range svc.Spec.Ports {
+		portName := strings.ToLower(p.Name)
+		if strings.Contains(portName, "grpc") || p.Port == 10901 {
+			continue
+		}
+		if strings.Contains(portName, "http") || strings.Contains(portName, "web") || p.Port == 10902 || p.Port == 9090 {
+			return p.Name, int(p.Port), true
+		}
+	}
+	return "", 0, false
+}
+
+// discoverThanosQuery finds a Thanos query frontend or querier Service and verifies it. Both
+// deduplicate HA Prometheus replicas, so they are preferred over any single Prometheus.
+func (h *PrometheusHandler) discoverThanosQuery(ctx context.Context, client kubernetes.Interface) (*promTarget, error) {
+	svcs, err := client.CoreV1().Services("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var candidates []*promTarget
+	for i := range svcs.Items {
+		svc := &svcs.Items[i]
+		role, ok := thanosQueryRole(svc)
+		if !ok {
+			continue
+		}
+		portName, port, ok := thanosQueryPort(svc)
+		if !ok {
+			continue
+		}
+		candidates = append(candidates, &promTarget{
+			Namespace: svc.Namespace, Service: svc.Name, PortName: portName, Port: port, IsService: true,
+			Topology: role, Dedup: true,
+		})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Topology == topologyThanosQueryFrontend && candidates[j].Topology != topologyThanosQueryFrontend
+	})
+	for _, c := range candidates {
+		if err := h.verifyPrometheusService(ctx, client, c.Namespace, c.Service, c.PortName, c.Port); err == nil {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("thanos query not found")
+}
+
+// prometheusReplicaGroup identifies the set of replicas a Prometheus pod belongs to: its
+// owning StatefulSet, the operator's prometheus label, or its instance label
+func prometheusReplicaGroup(pod *v1.Pod) string {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Controller != nil && *ref.Controller {
+			return pod.Namespace + "/" + ref.Kind + "/" + ref.Name
+		}
+	}
+	if name := pod.Labels["prometheus"]; name != "" {
+		return pod.Namespace + "/prometheus/" + name
+	}
+	if instance := pod.Labels["app.kubernetes.io/instance"]; instance != "" {
+		return pod.Namespace + "/instance/" + instance
+	}
+	return pod.Namespace + "/pod/" + pod.Name
+}
+
+// orderPrometheusReplicas sorts running pods so that, within each replica group, the ready
+// replica that started first comes first. Picking the same long-running replica on every
+// discovery keeps a restarted HA twin from serving its gap.
+func orderPrometheusReplicas(pods []v1.Pod) []v1.Pod {
+	ordered := make([]v1.Pod, 0, len(pods))
+	for _, p := range pods {
+		if p.Status.Phase == v1.PodRunning {
+			ordered = append(ordered, p)
+		}
+	}
+	ready := func(p *v1.Pod) bool {
+		for _, c := range p.Status.Conditions {
+			if c.Type == v1.PodReady {
+				return c.Status == v1.ConditionTrue
+			}
+		}
+		return false
+	}
+	started := func(p *v1.Pod) metav1.Time {
+		if p.Status.StartTime != nil {
+			return *p.Status.StartTime
+		}
+		return p.CreationTimestamp
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := &ordered[i], &ordered[j]
+		if ready(a) != ready(b) {
+			return ready(a)
+		}
+		if sa, sb := started(a), started(b); !sa.Equal(&sb) {
+			return sa.Before(&sb)
+		}
+		return a.Name < b.Name
+	})
+	return ordered
+}
+
+// podTarget builds the target of a chosen Prometheus pod, counting its HA replicas among pods
+func podTarget(pod *v1.Pod, port int, pods []v1.Pod) *promTarget {
+	group := prometheusReplicaGroup(pod)
+	replicas := 0
+	for i := range pods {
+		if pods[i].Status.Phase == v1.PodRunning && prometheusReplicaGroup(&pods[i]) == group {
+			replicas++
+		}
+	}
+	target := &promTarget{Namespace: pod.Namespace, Pod: pod.Name, Port: port, Topology: topologySingle, Replicas: replicas}
+	if replicas > 1 {
+		target.Topology = topologyHAReplica
+	}
+	return target
+}
+
+// dedupParams returns the Thanos deduplication parameters for a query API path, unless the
+// caller already chose them. Other paths, such as buildinfo or targets, get none.
+func dedupParams(path string, params map[string]string) map[string]string {
+	switch strings.TrimPrefix(path, "/") {
+	case "api/v1/query", "api/v1/query_range", "api/v1/series", "api/v1/labels":
+	default:
+		return nil
+	}
+	if _, ok := params["dedup"]; ok {
+		return nil
+	}
+	return map[string]string{"dedup": "true"}
+}
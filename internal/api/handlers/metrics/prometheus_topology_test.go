@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestThanosQueryRole(t *testing.T) {
+	service := func(name string, labels map[string]string) *v1.Service {
+		return &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+	cases := []struct {
+		svc  *v1.Service
+		role string
+	}{
+		{service("thanos-query-frontend", nil), topologyThanosQueryFrontend},
+		{service("obs-frontend", map[string]string{"app.kubernetes.io/component": "query-frontend"}), topologyThanosQueryFrontend},
+		{service("thanos-querier", nil), topologyThanosQuery},
+		{service("query", map[string]string{"app.kubernetes.io/name": "thanos", "app.kubernetes.io/component": "query"}), topologyThanosQuery},
+		{service("prometheus-k8s", nil), ""},
+		{service("thanos-store", map[string]string{"app.kubernetes.io/name": "thanos", "app.kubernetes.io/component": "store"}), ""},
+	}
+	for _, tc := range cases {
+		if role, _ := thanosQueryRole(tc.svc); role != tc.role {
+			t.Errorf("thanosQueryRole(%s) = %q, want %q", tc.svc.Name, role, tc.role)
+		}
+	}
+
+	svc := &v1.Service{Spec: v1.ServiceSpec{Ports: []v1.ServicePort{{Name: "grpc", Port: 10901}, {Name: "http", Port: 10902}}}}
+	if name, port, ok := thanosQueryPort(svc); !ok || name != "http" || port != 10902 {
+		t.Errorf("thanosQueryPort() = %q, %d, %v; want the HTTP port", name, port, ok)
+	}
+}
+
+func TestPodTargetPicksLongestRunningReplica(t *testing.T) {
+	controller := true
+	now := time.Now()
+	replica := func(name string, started time.Time, ready v1.ConditionStatus) v1.Pod {
+		start := metav1.NewTime(started)
+		return v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "monitoring",
+				OwnerReferences: []metav1.OwnerReference{{Kind: "StatefulSet", Name: "prometheus-k8s", Controller: &controller}}},
+			Status: v1.PodStatus{Phase: v1.PodRunning, StartTime: &start,
+				Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: ready}}},
+		}
+	}
+	pods := []v1.Pod{
+		replica("prometheus-k8s-0", now.Add(-time.Hour), v1.ConditionTrue),
+		replica("prometheus-k8s-1", now.Add(-48*time.Hour), v1.ConditionTrue),
+		replica("prometheus-k8s-2", now.Add(-72*time.Hour), v1.ConditionFalse),
+		{ObjectMeta: metav1.ObjectMeta{Name: "prometheus-k8s-3"}, Status: v1.PodStatus{Phase: v1.PodPending}},
+	}
+
+	ordered := orderPrometheusReplicas(pods)
+	if len(ordered) != 3 || ordered[0].Name != "prometheus-k8s-1" || ordered[2].Name != "prometheus-k8s-2" {
+		t.Fatalf("expected the oldest ready replica first and pending pods dropped, got %v", ordered)
+	}
+	target := podTarget(&ordered[0], 9090, pods)
+	if target.Topology != topologyHAReplica || target.Replicas != 3 || target.Dedup {
+		t.Errorf("unexpected target %+v", target)
+	}
+	if single := podTarget(&ordered[0], 9090, pods[:2][1:]); single.Topology != topologySingle || single.Replicas != 1 {
+		t.Errorf("expected a lone replica to be single, got %+v", single)
+	}
+}
+
+func TestDedupParams(t *testing.T) {
+	if got := dedupParams("/api/v1/query_range", map[string]string{"query": "up"}); got["dedup"] != "true" {
+		t.Errorf("expected dedup on range queries, got %v", got)
+	}
+	if got := dedupParams("/api/v1/query", map[string]string{"dedup": "false"}); got != nil {
+		t.Errorf("expected an explicit dedup to be kept, got %v", got)
+	}
+	if got := dedupParams("api/v1/status/buildinfo", nil); got != nil {
+		t.Errorf("expected no dedup for buildinfo, got %v", got)
+	}
+}
@@ -0,0 +1,317 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// defaultThrottleThreshold is the throttled-period ratio above which a CPU limit is flagged as too low
+const defaultThrottleThreshold = 0.25
+
+// vectorSample is a single labelled value from an instant query
+type vectorSample struct {
+	Metric map[string]string
+	Value  float64
+}
+
+// ContainerResourceFinding summarizes throttling and OOM behaviour for one container of a workload
+type ContainerResourceFinding struct {
+	Container       string   `json:"container"`
+	Pods            int      `json:"pods"`
+	CPULimit        string   `json:"cpuLimit,omitempty"`
+	MemoryLimit     string   `json:"memoryLimit,omitempty"`
+	ThrottlingRatio float64  `json:"throttlingRatio"`
+	OOMKills        int      `json:"oomKills"`
+	LastOOMKilled   bool     `json:"lastOOMKilled"`
+	Flags           []string `json:"flags"`
+}
+
+// WorkloadResourceAnalysis groups container findings by owning workload
+type WorkloadResourceAnalysis struct {
+	Namespace  string                     `json:"namespace"`
+	Kind       string                     `json:"kind"`
+	Name       string                     `json:"name"`
+	Containers []ContainerResourceFinding `json:"containers"`
+}
+
+// ResourceAnalysisResponse is the result of a throttling and OOM analysis
+type ResourceAnalysisResponse struct {
+	Window            string                     `json:"window"`
+	ThrottleThreshold float64                    `json:"throttleThreshold"`
+	Workloads         []WorkloadResourceAnalysis `json:"workloads"`
+	FlaggedContainers int                        `json:"flaggedContainers"`
+}
+
+// parseVector converts an instant query result into labelled samples
+func parseVector(raw []byte) ([]vectorSample, error) {
+	var resp promQueryResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Status != "success" {
+		return nil, fmt.Errorf("prometheus query failed")
+	}
+	out := make([]vectorSample, 0, len(resp.Data.Result))
+	for _, r := range resp.Data.Result {
+		if len(r.Value) != 2 {
+			continue
+		}
+		v, err := parseFloat(fmt.Sprintf("%v", r.Value[1]))
+		if err != nil {
+			continue
+		}
+		out = append(out, vectorSample{Metric: r.Metric, Value: v})
+	}
+	return out, nil
+}
+
+// workloadOwner resolves the top-level controller of a pod, following ReplicaSets and Jobs one level up
+func workloadOwner(pod *v1.Pod, rsOwners, jobOwners map[string]metav1.OwnerReference) (string, string) {
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil {
+		return "Pod", pod.Name
+	}
+	switch ref.Kind {
+	case "ReplicaSet":
+		if owner, ok := rsOwners[ref.Name]; ok {
+			return owner.Kind, owner.Name
+		}
+	case "Job":
+		if owner, ok := jobOwners[ref.Name]; ok {
+			return owner.Kind, owner.Name
+		}
+	}
+	return ref.Kind, ref.Name
+}
+
+// controllerOwners maps ReplicaSet and Job names to their controllers in the given namespace
+func controllerOwners(ctx context.Context, client kubernetes.Interface, namespace string) (map[string]metav1.OwnerReference, map[string]metav1.OwnerReference) {
+	rsOwners := map[string]metav1.OwnerReference{}
+	jobOwners := map[string]metav1.OwnerReference{}
+	if rsList, err := client.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+		for _, rs := range rsList.Items {
+			if ref := metav1.GetControllerOf(&rs); ref != nil {
+				rsOwners[rs.Name] = *ref
+			}
+		}
+	}
+	if jobList, err := client.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+		for _, job := range jobList.Items {
+			if ref := metav1.GetControllerOf(&job); ref != nil {
+				jobOwners[job.Name] = *ref
+			}
+		}
+	}
+	return rsOwners, jobOwners
+}
+
+// GetResourceAnalysis reports CPU throttling ratios and OOM kills per workload container
+// @Summary Analyze CPU throttling and OOM kills
+// @Description Uses Prometheus cAdvisor and kube-state-metrics series to report per-container CPU throttling ratios and OOMKill counts over a window, grouped by workload, and flags containers whose limits appear too low
+// @Tags Metrics
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name"
+// @Param namespace query string false "Namespace to analyze (empty for all namespaces)"
+// @Param window query string false "Analysis window" default(1h)
+// @Param throttleThreshold query number false "Throttled period ratio that flags a CPU limit" default(0.25)
+// @Success 200 {object} ResourceAnalysisResponse "Throttling and OOM findings"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Prometheus not available"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/metrics/analysis/resources [get]
+// @Router /api/v2/metrics/analysis/resources [get]
+func (h *PrometheusHandler) GetResourceAnalysis(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	client, err := h.getClient(c)
+	if err != nil {
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Successfully obtained Kubernetes client")
+
+	namespace := c.Query("namespace")
+	window := c.DefaultQuery("window", "1h")
+	threshold := defaultThrottleThreshold
+	if raw := c.Query("throttleThreshold"); raw != "" {
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil || parsed <= 0 || parsed >= 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "throttleThreshold must be between 0 and 1"})
+			return
+		}
+		threshold = parsed
+	}
+	// Normalize the window through the shared range parser so PromQL always receives a valid duration
+	windowDuration := parsePromRange(window)
+	promWindow := fmt.Sprintf("%ds", int(windowDuration.Seconds()))
+
+	cacheKey := h.getCacheKey("resource-analysis", c.Query("config"), c.Query("cluster"), namespace, window, fmt.Sprintf("%.2f", threshold))
+	if cached, ok := h.getFromCache(cacheKey); ok {
+		c.JSON(http.StatusOK, cached)
+		return
+	}
+
+	discoveryCtx, discoverySpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "discover", "prometheus", "")
+	defer discoverySpan.End()
+	timeoutCtx, cancel := context.WithTimeout(discoveryCtx, 4*time.Second)
+	defer cancel()
+	target, err := h.discoverPrometheus(timeoutCtx, client)
+	if err != nil {
+		h.tracingHelper.RecordError(discoverySpan, err, "Failed to discover Prometheus")
+		c.JSON(http.StatusNotFound, gin.H{"error": "prometheus not available"})
+		return
+	}
+	h.tracingHelper.RecordSuccess(discoverySpan, "Successfully discovered Prometheus target")
+
+	selector := `container!~"POD|"`
+	if namespace != "" {
+		selector = fmt.Sprintf(`namespace="%s",%s`, escapeLabelValue(namespace), selector)
+	}
+	qThrottled := fmt.Sprintf("sum by (namespace,pod,container) (increase(container_cpu_cfs_throttled_periods_total{%s}[%s])) / sum by (namespace,pod,container) (increase(container_cpu_cfs_periods_total{%s}[%s]))", selector, promWindow, selector, promWindow)
+	qOOMEvents := fmt.Sprintf("sum by (namespace,pod,container) (increase(container_oom_events_total{%s}[%s]))", selector, promWindow)
+	qLastOOM := fmt.Sprintf(`max by (namespace,pod,container) (kube_pod_container_status_last_terminated_reason{reason="OOMKilled",%s})`, selector)
+
+	queryCtx, querySpan := h.tracingHelper.StartMetricsSpan(ctx, "execute-prometheus-queries")
+	defer querySpan.End()
+
+	throttledRaw, err := h.proxyPrometheus(queryCtx, client, target, "/api/v1/query", map[string]string{"query": qThrottled})
+	if err != nil {
+		h.tracingHelper.RecordError(querySpan, err, "Throttling query failed")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	throttled, _ := parseVector(throttledRaw)
+	// OOM series are best-effort; older cAdvisor versions and clusters without kube-state-metrics lack them
+	var oomEvents, lastOOM []vectorSample
+	if raw, err := h.proxyPrometheus(queryCtx, client, target, "/api/v1/query", map[string]string{"query": qOOMEvents}); err == nil {
+		oomEvents, _ = parseVector(raw)
+	}
+	if raw, err := h.proxyPrometheus(queryCtx, client, target, "/api/v1/query", map[string]string{"query": qLastOOM}); err == nil {
+		lastOOM, _ = parseVector(raw)
+	}
+	h.tracingHelper.RecordSuccess(querySpan, "Resource analysis queries completed")
+
+	_, k8sSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "pods", namespace)
+	defer k8sSpan.End()
+	pods, err := client.CoreV1().Pods(namespace).List(c.Request.Context(), metav1.ListOptions{})
+	if err != nil {
+		h.tracingHelper.RecordError(k8sSpan, err, "Failed to list pods")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	rsOwners, jobOwners := controllerOwners(c.Request.Context(), client, namespace)
+	h.tracingHelper.AddResourceAttributes(k8sSpan, "", "pods", len(pods.Items))
+	h.tracingHelper.RecordSuccess(k8sSpan, fmt.Sprintf("Listed %d pods", len(pods.Items)))
+
+	type containerKey struct{ namespace, pod, container string }
+	type workloadKey struct{ namespace, kind, name string }
+
+	podIndex := make(map[string]*v1.Pod, len(pods.Items))
+	for i := range pods.Items {
+		podIndex[pods.Items[i].Namespace+"/"+pods.Items[i].Name] = &pods.Items[i]
+	}
+
+	throttleByContainer := map[containerKey]float64{}
+	for _, s := range throttled {
+		throttleByContainer[containerKey{s.Metric["namespace"], s.Metric["pod"], s.Metric["container"]}] = s.Value
+	}
+	oomByContainer := map[containerKey]float64{}
+	for _, s := range oomEvents {
+		oomByContainer[containerKey{s.Metric["namespace"], s.Metric["pod"], s.Metric["container"]}] = s.Value
+	}
+	lastOOMByContainer := map[containerKey]bool{}
+	for _, s := range lastOOM {
+		if s.Value > 0 {
+			lastOOMByContainer[containerKey{s.Metric["namespace"], s.Metric["pod"], s.Metric["container"]}] = true
+		}
+	}
+
+	_, processSpan := h.tracingHelper.StartDataProcessingSpan(ctx, "aggregate-resource-findings")
+	defer processSpan.End()
+
+	findings := map[workloadKey]map[string]*ContainerResourceFinding{}
+	for _, pod := range podIndex {
+		kind, name := workloadOwner(pod, rsOwners, jobOwners)
+		wk := workloadKey{pod.Namespace, kind, name}
+		if findings[wk] == nil {
+			findings[wk] = map[string]*ContainerResourceFinding{}
+		}
+		for _, container := range pod.Spec.Containers {
+			ck := containerKey{pod.Namespace, pod.Name, container.Name}
+			finding := findings[wk][container.Name]
+			if finding == nil {
+				finding = &ContainerResourceFinding{Container: container.Name, Flags: []string{}}
+				if limit, ok := container.Resources.Limits[v1.ResourceCPU]; ok {
+					finding.CPULimit = limit.String()
+				}
+				if limit, ok := container.Resources.Limits[v1.ResourceMemory]; ok {
+					finding.MemoryLimit = limit.String()
+				}
+				findings[wk][container.Name] = finding
+			}
+			finding.Pods++
+			// Report the worst pod so one throttled replica is not averaged away
+			if ratio := throttleByContainer[ck]; ratio > finding.ThrottlingRatio {
+				finding.ThrottlingRatio = ratio
+			}
+			finding.OOMKills += int(oomByContainer[ck] + 0.5)
+			if lastOOMByContainer[ck] {
+				finding.LastOOMKilled = true
+			}
+		}
+	}
+
+	response := ResourceAnalysisResponse{
+		Window:            window,
+		ThrottleThreshold: threshold,
+		Workloads:         []WorkloadResourceAnalysis{},
+	}
+	for wk, containers := range findings {
+		workload := WorkloadResourceAnalysis{Namespace: wk.namespace, Kind: wk.kind, Name: wk.name, Containers: []ContainerResourceFinding{}}
+		for _, finding := range containers {
+			if finding.CPULimit != "" && finding.ThrottlingRatio >= threshold {
+				finding.Flags = append(finding.Flags, "cpu-limit-too-low")
+			}
+			if finding.OOMKills > 0 || finding.LastOOMKilled {
+				finding.Flags = append(finding.Flags, "memory-limit-too-low")
+			}
+			if len(finding.Flags) > 0 {
+				response.FlaggedContainers++
+			}
+			workload.Containers = append(workload.Containers, *finding)
+		}
+		sort.Slice(workload.Containers, func(i, j int) bool { return workload.Containers[i].Container < workload.Containers[j].Container })
+		response.Workloads = append(response.Workloads, workload)
+	}
+	sort.Slice(response.Workloads, func(i, j int) bool {
+		a, b := response.Workloads[i], response.Workloads[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+	h.tracingHelper.AddResourceAttributes(processSpan, "", "workloads", len(response.Workloads))
+	h.tracingHelper.RecordSuccess(processSpan, fmt.Sprintf("Flagged %d containers", response.FlaggedContainers))
+
+	h.setCache(cacheKey, response, time.Minute)
+	c.JSON(http.StatusOK, response)
+}
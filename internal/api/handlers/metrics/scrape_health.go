@@ -0,0 +1,202 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// scrapeHealthCacheTTL is short so the view reflects scrapes within a couple of intervals
+const scrapeHealthCacheTTL = 15 * time.Second
+
+// slowScrapeRatio is the share of the scrape timeout above which a scrape is reported as slow
+const slowScrapeRatio = 0.8
+
+// promTargetsResponse is the subset of the Prometheus /api/v1/targets response used for scrape health
+type promTargetsResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		ActiveTargets []promActiveTarget `json:"activeTargets"`
+	} `json:"data"`
+}
+
+type promActiveTarget struct {
+	Labels             map[string]string `json:"labels"`
+	ScrapePool         string            `json:"scrapePool"`
+	ScrapeURL          string            `json:"scrapeUrl"`
+	LastError          string            `json:"lastError"`
+	LastScrape         time.Time         `json:"lastScrape"`
+	LastScrapeDuration float64           `json:"lastScrapeDuration"`
+	Health             string            `json:"health"`
+	ScrapeInterval     string            `json:"scrapeInterval"`
+	ScrapeTimeout      string            `json:"scrapeTimeout"`
+}
+
+// UnhealthyTarget is a scrape target that is down or scraping close to its timeout
+type UnhealthyTarget struct {
+	Instance           string    `json:"instance"`
+	ScrapeURL          string    `json:"scrapeUrl"`
+	Health             string    `json:"health"`
+	LastError          string    `json:"lastError,omitempty"`
+	LastScrape         time.Time `json:"lastScrape"`
+	LastScrapeDuration float64   `json:"lastScrapeDuration"` // seconds
+	ScrapeTimeout      string    `json:"scrapeTimeout,omitempty"`
+}
+
+// JobScrapeHealth summarises the scrape targets of one job
+type JobScrapeHealth struct {
+	Job                 string            `json:"job"`
+	Targets             int               `json:"targets"`
+	Up                  int               `json:"up"`
+	Down                int               `json:"down"`
+	Unknown             int               `json:"unknown"`
+	Slow                int               `json:"slow"`
+	AvgScrapeDuration   float64           `json:"avgScrapeDuration"` // seconds
+	MaxScrapeDuration   float64           `json:"maxScrapeDuration"` // seconds
+	ScrapeInterval      string            `json:"scrapeInterval,omitempty"`
+	UnhealthyTargets    []UnhealthyTarget `json:"unhealthyTargets"`
+	totalScrapeDuration float64
+}
+
+// ScrapeHealthResponse summarises Prometheus scrape health grouped by job
+type ScrapeHealthResponse struct {
+	Targets int               `json:"targets"`
+	Up      int               `json:"up"`
+	Down    int               `json:"down"`
+	Unknown int               `json:"unknown"`
+	Slow    int               `json:"slow"`
+	Jobs    []JobScrapeHealth `json:"jobs"`
+}
+
+// summarizeTargets groups active targets by job, counting health states and collecting the targets that need attention
+func summarizeTargets(raw []byte) (*ScrapeHealthResponse, error) {
+	var resp promTargetsResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse targets response: %w", err)
+	}
+	if resp.Status != "success" {
+		return nil, fmt.Errorf("prometheus returned status %q", resp.Status)
+	}
+
+	jobs := map[string]*JobScrapeHealth{}
+	result := &ScrapeHealthResponse{Jobs: []JobScrapeHealth{}}
+	for _, t := range resp.Data.ActiveTargets {
+		name := t.Labels["job"]
+		if name == "" {
+			name = t.ScrapePool
+		}
+		job, ok := jobs[name]
+		if !ok {
+			job = &JobScrapeHealth{Job: name, ScrapeInterval: t.ScrapeInterval, UnhealthyTargets: []UnhealthyTarget{}}
+			jobs[name] = job
+		}
+
+		job.Targets++
+		job.totalScrapeDuration += t.LastScrapeDuration
+		if t.LastScrapeDuration > job.MaxScrapeDuration {
+			job.MaxScrapeDuration = t.LastScrapeDuration
+		}
+		slow := false
+		if timeout, err := time.ParseDuration(t.ScrapeTimeout); err == nil && timeout > 0 {
+			slow = t.LastScrapeDuration >= timeout.Seconds()*slowScrapeRatio
+		}
+		switch t.Health {
+		case "up":
+			job.Up++
+		case "down":
+			job.Down++
+		default:
+			job.Unknown++
+		}
+		if slow {
+			job.Slow++
+		}
+		if t.Health == "down" || slow {
+			job.UnhealthyTargets = append(job.UnhealthyTargets, UnhealthyTarget{
+				Instance:           t.Labels["instance"],
+				ScrapeURL:          t.ScrapeURL,
+				Health:             t.Health,
+				LastError:          t.LastError,
+				LastScrape:         t.LastScrape,
+				LastScrapeDuration: t.LastScrapeDuration,
+				ScrapeTimeout:      t.ScrapeTimeout,
+			})
+		}
+	}
+
+	for _, job := range jobs {
+		job.AvgScrapeDuration = job.totalScrapeDuration / float64(job.Targets)
+		sort.Slice(job.UnhealthyTargets, func(i, j int) bool { return job.UnhealthyTargets[i].Instance < job.UnhealthyTargets[j].Instance })
+		result.Targets += job.Targets
+		result.Up += job.Up
+		result.Down += job.Down
+		result.Unknown += job.Unknown
+		result.Slow += job.Slow
+		result.Jobs = append(result.Jobs, *job)
+	}
+	// Jobs with down targets first, then by name
+	sort.Slice(result.Jobs, func(i, j int) bool {
+		if (result.Jobs[i].Down > 0) != (result.Jobs[j].Down > 0) {
+			return result.Jobs[i].Down > 0
+		}
+		return result.Jobs[i].Job < result.Jobs[j].Job
+	})
+	return result, nil
+}
+
+// GetScrapeHealth returns Prometheus scrape target health grouped by job
+// @Summary Get Prometheus scrape health
+// @Description Summarises the active Prometheus scrape targets by job: how many are up, down or unknown, average and maximum scrape durations, and the down or slow targets with their last scrape error. Use it to tell whether empty charts are caused by scraping rather than the dashboard.
+// @Tags Metrics
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name"
+// @Success 200 {object} ScrapeHealthResponse "Scrape health by job"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Prometheus not available"
+// @Failure 502 {object} map[string]string "Prometheus targets request failed"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/metrics/prometheus/targets [get]
+// @Router /api/v2/metrics/prometheus/targets [get]
+func (h *PrometheusHandler) GetScrapeHealth(c *gin.Context) {
+	client, err := h.getClient(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cacheKey := h.getCacheKey("scrape-health", c.Query("config"), c.Query("cluster"), "", "", "")
+	if cached, ok := h.getFromCache(cacheKey); ok {
+		c.JSON(http.StatusOK, cached)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+	target, err := h.discoverPrometheus(ctx, client)
+	if err != nil || target == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "prometheus not available"})
+		return
+	}
+
+	raw, err := h.proxyPrometheus(ctx, client, target, "/api/v1/targets", map[string]string{"state": "active"})
+	if err != nil {
+		h.logger.WithError(err).Warn("Failed to fetch Prometheus targets")
+		c.JSON(http.StatusBadGateway, gin.H{"error": fmt.Sprintf("failed to fetch prometheus targets: %v", err)})
+		return
+	}
+	summary, err := summarizeTargets(raw)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.setCache(cacheKey, summary, scrapeHealthCacheTTL)
+	c.JSON(http.StatusOK, summary)
+}
@@ -0,0 +1,38 @@
+package metrics
+
+import "testing"
+
+func TestSummarizeTargets(t *testing.T) {
+	raw := []byte(`{"status":"success","data":{"activeTargets":[
+		{"labels":{"job":"node-exporter","instance":"10.0.0.2:9100"},"scrapeUrl":"http://10.0.0.2:9100/metrics","health":"up","lastScrapeDuration":0.02,"scrapeInterval":"30s","scrapeTimeout":"10s"},
+		{"labels":{"job":"node-exporter","instance":"10.0.0.1:9100"},"scrapeUrl":"http://10.0.0.1:9100/metrics","health":"down","lastError":"connection refused","lastScrapeDuration":0.04,"scrapeInterval":"30s","scrapeTimeout":"10s"},
+		{"labels":{"job":"apiserver","instance":"10.0.0.9:443"},"health":"up","lastScrapeDuration":9,"scrapeTimeout":"10s"},
+		{"labels":{"instance":"x"},"scrapePool":"kubelet","health":"unknown","scrapeTimeout":"10s"}
+	]}}`)
+
+	summary, err := summarizeTargets(raw)
+	if err != nil {
+		t.Fatalf("summarizeTargets() error = %v", err)
+	}
+	if summary.Targets != 4 || summary.Up != 2 || summary.Down != 1 || summary.Unknown != 1 || summary.Slow != 1 {
+		t.Errorf("unexpected totals: %+v", summary)
+	}
+	if len(summary.Jobs) != 3 || summary.Jobs[0].Job != "node-exporter" || summary.Jobs[1].Job != "apiserver" || summary.Jobs[2].Job != "kubelet" {
+		t.Fatalf("unexpected job order: %+v", summary.Jobs)
+	}
+
+	nodes := summary.Jobs[0]
+	if nodes.AvgScrapeDuration < 0.0299 || nodes.AvgScrapeDuration > 0.0301 || nodes.MaxScrapeDuration != 0.04 {
+		t.Errorf("durations = %v/%v", nodes.AvgScrapeDuration, nodes.MaxScrapeDuration)
+	}
+	if len(nodes.UnhealthyTargets) != 1 || nodes.UnhealthyTargets[0].LastError != "connection refused" {
+		t.Errorf("unhealthy targets = %+v", nodes.UnhealthyTargets)
+	}
+	if api := summary.Jobs[1]; api.Slow != 1 || len(api.UnhealthyTargets) != 1 {
+		t.Errorf("expected the apiserver target to be reported slow: %+v", api)
+	}
+
+	if _, err := summarizeTargets([]byte(`{"status":"error"}`)); err == nil {
+		t.Error("expected an error for a failed response")
+	}
+}
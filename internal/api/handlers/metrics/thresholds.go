@@ -0,0 +1,119 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/thresholds"
+
+	"github.com/gin-gonic/gin"
+)
+
+// nodeMetricsSnapshot is a cached node metrics payload with the samples thresholds are evaluated on
+type nodeMetricsSnapshot struct {
+	payload gin.H
+	samples map[string][]thresholds.Sample
+}
+
+// thresholdSession starts threshold evaluation for a metrics stream. Inline thresholds come
+// from the thresholds query parameter, e.g. thresholds=cpu_limit_percent>80 for 5m.
+func (h *PrometheusHandler) thresholdSession(c *gin.Context, kind, namespace, name string) (*thresholds.Session, error) {
+	if h.thresholds == nil {
+		return nil, nil
+	}
+	target := thresholds.Target{
+		ConfigID:  c.Query("config"),
+		Cluster:   c.Query("cluster"),
+		Kind:      kind,
+		Namespace: namespace,
+		Name:      name,
+	}
+	inline, err := thresholds.ParseInline(c.Query("thresholds"), target)
+	if err != nil {
+		return nil, err
+	}
+	return h.thresholds.NewSession(target, inline), nil
+}
+
+// withAlerts returns a copy of the payload with the threshold alert state added, leaving
+// payloads without applicable thresholds unchanged
+func withAlerts(payload gin.H, session *thresholds.Session, samples map[string][]thresholds.Sample) gin.H {
+	if session == nil {
+		return payload
+	}
+	alerts := session.Evaluate(samples, time.Now())
+	if alerts.Rules == 0 && len(alerts.Events) == 0 {
+		return payload
+	}
+	result := make(gin.H, len(payload)+1)
+	for k, v := range payload {
+		result[k] = v
+	}
+	result["alerts"] = alerts
+	return result
+}
+
+// firstSeriesSamples converts the first series to threshold samples
+func firstSeriesSamples(s []series) []thresholds.Sample {
+	if len(s) == 0 {
+		return nil
+	}
+	samples := make([]thresholds.Sample, len(s[0].Points))
+	for i, p := range s[0].Points {
+		samples[i] = thresholds.Sample{T: p.T, V: p.V}
+	}
+	return samples
+}
+
+// scaledSamples returns samples multiplied by factor, or nil if factor is not positive
+func scaledSamples(samples []thresholds.Sample, factor float64) []thresholds.Sample {
+	if samples == nil || factor <= 0 {
+		return nil
+	}
+	scaled := make([]thresholds.Sample, len(samples))
+	for i, s := range samples {
+		scaled[i] = thresholds.Sample{T: s.T, V: s.V * factor}
+	}
+	return scaled
+}
+
+// addSamples sets the metric's samples when there are any, so metrics without data keep their alert state
+func addSamples(samples map[string][]thresholds.Sample, metric string, values []thresholds.Sample) {
+	if len(values) > 0 {
+		samples[metric] = values
+	}
+}
+
+// podAlertSamples derives the thresholdable pod metrics. Percentages are relative to the
+// summed container limits and requests and are omitted when those are not set.
+func podAlertSamples(cpuAvg, cpuMax, mem []series, cpuLimit, memoryLimit, cpuRequest, memoryRequest float64) map[string][]thresholds.Sample {
+	samples := map[string][]thresholds.Sample{}
+	cpu := firstSeriesSamples(cpuAvg)
+	memory := firstSeriesSamples(mem)
+	addSamples(samples, "cpu_average", cpu)
+	addSamples(samples, "cpu_maximum", firstSeriesSamples(cpuMax))
+	addSamples(samples, "memory_usage", memory)
+	if cpuLimit > 0 {
+		addSamples(samples, "cpu_limit_percent", scaledSamples(cpu, 100/cpuLimit))
+	}
+	if memoryLimit > 0 {
+		addSamples(samples, "memory_limit_percent", scaledSamples(memory, 100/memoryLimit))
+	}
+	if cpuRequest > 0 {
+		addSamples(samples, "cpu_request_percent", scaledSamples(cpu, 100/cpuRequest))
+	}
+	if memoryRequest > 0 {
+		addSamples(samples, "memory_request_percent", scaledSamples(memory, 100/memoryRequest))
+	}
+	return samples
+}
+
+// nodeAlertSamples derives the thresholdable node metrics from the node stream's series
+func nodeAlertSamples(cpu, mem, fs, cpuRequests, memoryRequests []series) map[string][]thresholds.Sample {
+	samples := map[string][]thresholds.Sample{}
+	addSamples(samples, "cpu_usage_percent", firstSeriesSamples(cpu))
+	addSamples(samples, "memory_usage_percent", firstSeriesSamples(mem))
+	addSamples(samples, "disk_usage_percent", firstSeriesSamples(fs))
+	addSamples(samples, "cpu_requests_percent", scaledSamples(firstSeriesSamples(cpuRequests), 100))
+	addSamples(samples, "memory_requests_percent", scaledSamples(firstSeriesSamples(memoryRequests), 100))
+	return samples
+}
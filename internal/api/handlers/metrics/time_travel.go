@@ -0,0 +1,410 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/eventhistory"
+	"github.com/Facets-cloud/kube-dash/internal/rollouts"
+	"github.com/Facets-cloud/kube-dash/internal/types"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// defaultTimeTravelWindow is the span of series around the incident when no window is given
+	defaultTimeTravelWindow = time.Hour
+	// maxTimeTravelWindow bounds the span of series around the incident
+	maxTimeTravelWindow = 7 * 24 * time.Hour
+	// timeTravelPoints is the number of points per series when no step is given
+	timeTravelPoints = 240
+	// maxTimeTravelEvents bounds the correlated events returned
+	maxTimeTravelEvents = 500
+)
+
+// Sources of the correlated events
+const (
+	EventsSourceHistory = "history" // events recorded in the background
+	EventsSourceLive    = "live"    // events still held by the API server, which keeps about an hour
+)
+
+// timeTravelQueries are the PromQL queries per target kind; %s is the pod selector or node name
+var timeTravelQueries = map[string][]timeTravelQuery{
+	"workload": {
+		{"cpu", "millicores", `1000 * sum(rate(container_cpu_usage_seconds_total{%s,container!~"POD|istio-proxy|istio-init"}[5m]))`},
+		{"memory", "bytes", `sum(container_memory_working_set_bytes{%s,container!~"POD|istio-proxy|istio-init"})`},
+		{"restarts", "count", `sum(increase(kube_pod_container_status_restarts_total{%s}[5m]))`},
+		{"ready-pods", "count", `sum(kube_pod_status_ready{%s,condition="true"})`},
+		{"network-rx", "bytes/s", `sum(rate(container_network_receive_bytes_total{%s}[5m]))`},
+		{"network-tx", "bytes/s", `sum(rate(container_network_transmit_bytes_total{%s}[5m]))`},
+	},
+	"node": {
+		{"cpu", "percent", `100 * (sum by (instance) (rate(node_cpu_seconds_total{mode!="idle",mode!="iowait",mode!="steal"}[5m])) / sum by (instance) (rate(node_cpu_seconds_total[5m]))) * on(instance) group_left(nodename) node_uname_info{nodename="%[1]s"}`},
+		{"memory", "percent", `100 * (1 - (node_memory_MemAvailable_bytes / node_memory_MemTotal_bytes)) * on(instance) group_left(nodename) node_uname_info{nodename="%[1]s"}`},
+		{"filesystem", "percent", `100 * (1 - (node_filesystem_avail_bytes{fstype!~"tmpfs|overlay",mountpoint="/"} / node_filesystem_size_bytes{fstype!~"tmpfs|overlay",mountpoint="/"})) * on(instance) group_left(nodename) node_uname_info{nodename="%[1]s"}`},
+		{"pods", "count", `count(kube_pod_info{node="%[1]s"})`},
+		{"network-rx", "bytes/s", `sum by (instance) (rate(node_network_receive_bytes_total{device!~"lo"}[5m])) * on(instance) group_left(nodename) node_uname_info{nodename="%[1]s"}`},
+		{"network-tx", "bytes/s", `sum by (instance) (rate(node_network_transmit_bytes_total{device!~"lo"}[5m])) * on(instance) group_left(nodename) node_uname_info{nodename="%[1]s"}`},
+	},
+}
+
+// timeTravelQuery is a metric of a time-travel bundle and the PromQL query computing it
+type timeTravelQuery struct {
+	metric, unit, query string
+}
+
+// timeTravelKinds maps the kind parameter to the kind events are reported for
+var timeTravelKinds = map[string]string{
+	"pod":         "Pod",
+	"deployment":  "Deployment",
+	"statefulset": "StatefulSet",
+	"daemonset":   "DaemonSet",
+	"node":        "Node",
+}
+
+// TimeTravelTarget is the workload, pod or node an incident is reconstructed for
+type TimeTravelTarget struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+// TimeTravelSeries is one metric of the target across the window
+type TimeTravelSeries struct {
+	Metric string      `json:"metric"`
+	Unit   string      `json:"unit"`
+	Points []timePoint `json:"points"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// TimeTravelBundle is the state of a target around an incident: metric series across the window,
+// with the events and rollouts that happened in it
+type TimeTravelBundle struct {
+	Target          TimeTravelTarget     `json:"target"`
+	At              time.Time            `json:"at"`
+	Start           time.Time            `json:"start"`
+	End             time.Time            `json:"end"` // the window is cut short at the current time
+	Step            string               `json:"step"`
+	Series          []TimeTravelSeries   `json:"series"`
+	MetricsError    string               `json:"metricsError,omitempty"` // Prometheus was not available
+	Events          []*types.StoredEvent `json:"events"`                 // oldest first
+	EventsSource    string               `json:"eventsSource"`
+	EventsError     string               `json:"eventsError,omitempty"`
+	Rollouts        []rollouts.Rollout   `json:"rollouts"` // oldest first
+	RolloutsTracked bool                 `json:"rolloutsTracked"`
+}
+
+// TimeTravelHandler reconstructs the state of a workload, pod or node around an incident
+type TimeTravelHandler struct {
+	prometheus *PrometheusHandler
+	events     *eventhistory.Recorder
+	rollouts   *rollouts.Tracker
+	logger     *logger.Logger
+}
+
+// NewTimeTravelHandler creates a new time-travel handler
+func NewTimeTravelHandler(prometheus *PrometheusHandler, events *eventhistory.Recorder, rolloutTracker *rollouts.Tracker, log *logger.Logger) *TimeTravelHandler {
+	return &TimeTravelHandler{
+		prometheus: prometheus,
+		events:     events,
+		rollouts:   rolloutTracker,
+		logger:     log,
+	}
+}
+
+// parseIncidentTime parses an RFC 3339 timestamp or Unix seconds
+func parseIncidentTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, fmt.Errorf("at parameter is required")
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+	return time.Time{}, fmt.Errorf("at must be an RFC 3339 timestamp or Unix seconds")
+}
+
+// timeTravelWindow centers the window on the incident, ending it no later than now, and picks the
+// step: the requested one, or one giving timeTravelPoints points, of at least 15 seconds
+func timeTravelWindow(at, now time.Time, window, step time.Duration) (time.Time, time.Time, time.Duration) {
+	start, end := at.Add(-window/2), at.Add(window/2)
+	if end.After(now) {
+		end = now
+	}
+	if step <= 0 {
+		step = (window / timeTravelPoints).Truncate(time.Second)
+		if step < 15*time.Second {
+			step = 15 * time.Second
+		}
+	}
+	return start, end, step
+}
+
+// podSelector matches the pods of a target: the pod itself, or the pods named after a workload
+func (t TimeTravelTarget) podSelector() string {
+	if t.Kind == "pod" {
+		return fmt.Sprintf(`namespace="%s",pod="%s"`, escapeLabelValue(t.Namespace), escapeLabelValue(t.Name))
+	}
+	return fmt.Sprintf(`namespace="%s",pod=~"%s-.*"`, escapeLabelValue(t.Namespace), escapeLabelValue(regexp.QuoteMeta(t.Name)))
+}
+
+// queries renders the PromQL queries of the target
+func (t TimeTravelTarget) queries() []timeTravelQuery {
+	group, selector := "workload", t.podSelector()
+	if t.Kind == "node" {
+		group, selector = "node", escapeLabelValue(t.Name)
+	}
+	out := []timeTravelQuery{}
+	for _, q := range timeTravelQueries[group] {
+		out = append(out, timeTravelQuery{metric: q.metric, unit: q.unit, query: fmt.Sprintf(q.query, selector)})
+	}
+	return out
+}
+
+// emptySeries returns a series without points for each query
+func emptySeries(queries []timeTravelQuery) []TimeTravelSeries {
+	out := make([]TimeTravelSeries, len(queries))
+	for i, q := range queries {
+		out[i] = TimeTravelSeries{Metric: q.metric, Unit: q.unit, Points: []timePoint{}}
+	}
+	return out
+}
+
+// relatedEvent reports whether an event is about the target: the object itself or, for
+// workloads, the ReplicaSets and pods named after it
+func (t TimeTravelTarget) relatedEvent(e *types.StoredEvent) bool {
+	kind := timeTravelKinds[t.Kind]
+	if e.InvolvedKind == kind && e.InvolvedName == t.Name {
+		return true
+	}
+	switch t.Kind {
+	case "deployment", "statefulset", "daemonset":
+		return (e.InvolvedKind == "Pod" || e.InvolvedKind == "ReplicaSet") && strings.HasPrefix(e.InvolvedName, t.Name+"-")
+	}
+	return false
+}
+
+// relatedRollout reports whether a deployment rollout concerns the target. Nodes are related to
+// every rollout, since any workload may have been scheduled on them.
+func (t TimeTravelTarget) relatedRollout(r *rollouts.Rollout) bool {
+	switch t.Kind {
+	case "node":
+		return true
+	case "deployment":
+		return r.Namespace == t.Namespace && r.Deployment == t.Name
+	case "pod":
+		return r.Namespace == t.Namespace && strings.HasPrefix(t.Name, r.Deployment+"-")
+	}
+	return false
+}
+
+// fetchSeries runs the target's range queries concurrently
+func (h *TimeTravelHandler) fetchSeries(ctx context.Context, client kubernetes.Interface, target *promTarget, queries []timeTravelQuery, start, end time.Time, step time.Duration) []TimeTravelSeries {
+	out := emptySeries(queries)
+	var wg sync.WaitGroup
+	for i := range queries {
+		wg.Add(1)
+		go func(s *TimeTravelSeries, query string) {
+			defer wg.Done()
+			raw, err := h.prometheus.proxyPrometheus(ctx, client, target, "/api/v1/query_range", map[string]string{
+				"query": query,
+				"start": fmt.Sprintf("%d", start.Unix()),
+				"end":   fmt.Sprintf("%d", end.Unix()),
+				"step":  fmt.Sprintf("%ds", int(step.Seconds())),
+			})
+			var result []series
+			if err == nil {
+				result, err = parseMatrix(raw)
+			}
+			if err != nil {
+				s.Error = err.Error()
+				return
+			}
+			if len(result) > 0 {
+				s.Points = result[0].Points
+			}
+		}(&out[i], queries[i].query)
+	}
+	wg.Wait()
+	return out
+}
+
+// recordsEvents reports whether the cluster's events are recorded in the background
+func (h *TimeTravelHandler) recordsEvents(configID, cluster string) bool {
+	for _, recorded := range h.events.Clusters() {
+		if recorded.ConfigID == configID && recorded.Cluster == cluster {
+			return true
+		}
+	}
+	return false
+}
+
+// correlatedEvents returns the target's events in the window, from the recorded history when the
+// cluster is recorded and from the API server otherwise
+func (h *TimeTravelHandler) correlatedEvents(ctx context.Context, client kubernetes.Interface, configID, cluster string, target TimeTravelTarget, start, end time.Time) ([]*types.StoredEvent, string, error) {
+	var candidates []*types.StoredEvent
+	source := EventsSourceLive
+	if h.recordsEvents(configID, cluster) {
+		source = EventsSourceHistory
+		stored, _, err := h.events.Query(types.EventFilter{ConfigID: configID, Cluster: cluster, Namespace: target.Namespace, Since: &start})
+		if err != nil {
+			return nil, source, err
+		}
+		candidates = stored
+	} else {
+		list, err := client.CoreV1().Events(target.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, source, err
+		}
+		for i := range list.Items {
+			candidates = append(candidates, eventhistory.ToStoredEvent(configID, cluster, &list.Items[i]))
+		}
+	}
+
+	events := []*types.StoredEvent{}
+	for _, e := range candidates {
+		if e.LastSeen.Before(start) || e.FirstSeen.After(end) || !target.relatedEvent(e) {
+			continue
+		}
+		events = append(events, e)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].LastSeen.Before(events[j].LastSeen) })
+	if len(events) > maxTimeTravelEvents {
+		events = events[len(events)-maxTimeTravelEvents:]
+	}
+	return events, source, nil
+}
+
+// correlatedRollouts returns the recorded rollouts of the target that overlap the window
+func (h *TimeTravelHandler) correlatedRollouts(configID, cluster string, target TimeTravelTarget, start, end time.Time) []rollouts.Rollout {
+	result := []rollouts.Rollout{}
+	for _, r := range h.rollouts.List(rollouts.Query{ConfigID: configID, Cluster: cluster}) {
+		if r.StartedAt.After(end) || (r.CompletedAt != nil && r.CompletedAt.Before(start)) || !target.relatedRollout(&r) {
+			continue
+		}
+		result = append(result, r)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].StartedAt.Before(result[j].StartedAt) })
+	return result
+}
+
+// GetTimeTravel reconstructs the state of a target around an incident
+// @Summary Get metrics, events and rollouts around an incident
+// @Description Returns the range series of a workload, pod or node across a window centered on an incident timestamp, with the events and deployment rollouts in the same window, so the state at the time can be reconstructed without aligning separate charts. Workload series sum the pods named after the workload. Events come from the recorded event history when the cluster is recorded, and otherwise from the API server, which only keeps about an hour. Rollouts are those recorded by rollout tracking; a node is correlated with every rollout in the window. Series are empty with metricsError set when Prometheus is not available.
+// @Tags Metrics
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name"
+// @Param kind query string true "Target kind: pod, deployment, statefulset, daemonset or node"
+// @Param namespace query string false "Target namespace; required except for nodes"
+// @Param name query string true "Target name"
+// @Param at query string true "Incident time, as an RFC 3339 timestamp or Unix seconds"
+// @Param window query string false "Span of the window centered on the incident (default 1h, at most 168h)"
+// @Param step query string false "Series resolution, e.g. 30s (default: window / 240, at least 15s)"
+// @Success 200 {object} TimeTravelBundle "Series, events and rollouts around the incident"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/metrics/time-travel [get]
+// @Router /api/v2/metrics/time-travel [get]
+func (h *TimeTravelHandler) GetTimeTravel(c *gin.Context) {
+	client, err := h.prometheus.getClient(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	target := TimeTravelTarget{Kind: strings.ToLower(c.Query("kind")), Namespace: c.Query("namespace"), Name: c.Query("name")}
+	if _, ok := timeTravelKinds[target.Kind]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "kind must be pod, deployment, statefulset, daemonset or node"})
+		return
+	}
+	if target.Kind == "node" {
+		target.Namespace = ""
+	} else if target.Namespace == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		return
+	}
+	if target.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name parameter is required"})
+		return
+	}
+
+	now := time.Now()
+	at, err := parseIncidentTime(c.Query("at"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if at.After(now) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "at must not be in the future"})
+		return
+	}
+	window := defaultTimeTravelWindow
+	if raw := c.Query("window"); raw != "" {
+		if window, err = time.ParseDuration(raw); err != nil || window < time.Minute || window > maxTimeTravelWindow {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("window must be a duration between 1m and %s", maxTimeTravelWindow)})
+			return
+		}
+	}
+	var step time.Duration
+	if raw := c.Query("step"); raw != "" {
+		if step, err = time.ParseDuration(raw); err != nil || step < time.Second {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "step must be a duration of at least 1s"})
+			return
+		}
+		if int(window/step) > maxBatchPoints {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("window and step give more than %d points per series", maxBatchPoints)})
+			return
+		}
+	}
+	start, end, step := timeTravelWindow(at, now, window, step)
+
+	configID, cluster := c.Query("config"), c.Query("cluster")
+	bundle := TimeTravelBundle{
+		Target: target,
+		At:     at,
+		Start:  start,
+		End:    end,
+		Step:   step.String(),
+	}
+	queries := target.queries()
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		promTarget, err := h.prometheus.discoverPrometheus(ctx, client)
+		if err != nil || promTarget == nil {
+			bundle.Series, bundle.MetricsError = emptySeries(queries), "prometheus not available"
+			return
+		}
+		bundle.Series = h.fetchSeries(ctx, client, promTarget, queries, start, end, step)
+	}()
+
+	events, source, err := h.correlatedEvents(ctx, client, configID, cluster, target, start, end)
+	bundle.Events, bundle.EventsSource = events, source
+	if err != nil {
+		h.logger.WithError(err).Warn("Failed to read events for time-travel view")
+		bundle.Events, bundle.EventsError = []*types.StoredEvent{}, err.Error()
+	}
+	bundle.Rollouts = h.correlatedRollouts(configID, cluster, target, start, end)
+	bundle.RolloutsTracked = h.rollouts.TrackedCluster(configID, cluster) != nil
+	wg.Wait()
+
+	c.JSON(http.StatusOK, bundle)
+}
@@ -0,0 +1,125 @@
+package metrics
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/config"
+	"github.com/Facets-cloud/kube-dash/internal/eventhistory"
+	"github.com/Facets-cloud/kube-dash/internal/rollouts"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/internal/testharness"
+	"github.com/Facets-cloud/kube-dash/internal/types"
+)
+
+func TestTimeTravelWindow(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	at := now.Add(-2 * time.Hour)
+	start, end, step := timeTravelWindow(at, now, time.Hour, 0)
+	if !start.Equal(at.Add(-30*time.Minute)) || !end.Equal(at.Add(30*time.Minute)) || step != 15*time.Second {
+		t.Errorf("window = %s..%s step %s, want centered on the incident with a 15s step", start, end, step)
+	}
+
+	start, end, step = timeTravelWindow(now.Add(-10*time.Minute), now, 24*time.Hour, 0)
+	if !end.Equal(now) || !start.Equal(now.Add(-12*time.Hour-10*time.Minute)) || step != 6*time.Minute {
+		t.Errorf("window = %s..%s step %s, want cut short at now", start, end, step)
+	}
+
+	if _, _, step := timeTravelWindow(at, now, time.Hour, time.Minute); step != time.Minute {
+		t.Errorf("step = %s, want the requested step", step)
+	}
+}
+
+func TestParseIncidentTime(t *testing.T) {
+	want := time.Date(2026, 1, 10, 10, 0, 0, 0, time.UTC)
+	for _, value := range []string{"2026-01-10T10:00:00Z", "1768039200"} {
+		if got, err := parseIncidentTime(value); err != nil || !got.Equal(want) {
+			t.Errorf("parseIncidentTime(%q) = %s, %v", value, got, err)
+		}
+	}
+	for _, value := range []string{"", "yesterday"} {
+		if _, err := parseIncidentTime(value); err == nil {
+			t.Errorf("parseIncidentTime(%q) succeeded, want an error", value)
+		}
+	}
+}
+
+func TestTimeTravelQueries(t *testing.T) {
+	workload := TimeTravelTarget{Kind: "deployment", Namespace: "shop", Name: "web.v2"}.queries()
+	if len(workload) != 6 || workload[0].metric != "cpu" || !strings.Contains(workload[0].query, `namespace="shop",pod=~"web\\.v2-.*"`) {
+		t.Errorf("workload queries = %+v", workload)
+	}
+	pod := TimeTravelTarget{Kind: "pod", Namespace: "shop", Name: "web-1"}.queries()
+	if !strings.Contains(pod[1].query, `pod="web-1"`) {
+		t.Errorf("pod query = %s", pod[1].query)
+	}
+	node := TimeTravelTarget{Kind: "node", Name: "worker-1"}.queries()
+	if len(node) != 6 || !strings.Contains(node[0].query, `node_uname_info{nodename="worker-1"}`) || !strings.Contains(node[3].query, `kube_pod_info{node="worker-1"}`) {
+		t.Errorf("node queries = %+v", node)
+	}
+}
+
+func TestTimeTravelCorrelation(t *testing.T) {
+	deployment := TimeTravelTarget{Kind: "deployment", Namespace: "shop", Name: "web"}
+	for _, c := range []struct {
+		kind, name string
+		want       bool
+	}{
+		{"Deployment", "web", true},
+		{"ReplicaSet", "web-7d9f", true},
+		{"Pod", "web-7d9f-abcde", true},
+		{"Pod", "webhook-5c8b-xyz", false},
+		{"Service", "web-internal", false},
+	} {
+		if got := deployment.relatedEvent(&types.StoredEvent{InvolvedKind: c.kind, InvolvedName: c.name}); got != c.want {
+			t.Errorf("relatedEvent(%s/%s) = %v, want %v", c.kind, c.name, got, c.want)
+		}
+	}
+
+	pod := TimeTravelTarget{Kind: "pod", Namespace: "shop", Name: "web-7d9f-abcde"}
+	if !pod.relatedRollout(&rollouts.Rollout{Namespace: "shop", Deployment: "web"}) || pod.relatedRollout(&rollouts.Rollout{Namespace: "shop", Deployment: "webhook"}) {
+		t.Error("pod rollouts should be those of the deployment the pod is named after")
+	}
+	if !(TimeTravelTarget{Kind: "node", Name: "worker-1"}).relatedRollout(&rollouts.Rollout{Namespace: "billing", Deployment: "api"}) {
+		t.Error("nodes should be correlated with every rollout")
+	}
+}
+
+func TestGetTimeTravelWithoutPrometheus(t *testing.T) {
+	h := testharness.New(t, "incident.yaml")
+	documents := storage.NewDocumentStore(nil)
+	handler := NewTimeTravelHandler(
+		NewPrometheusHandler(h.Store, h.Factory, h.Logger, nil),
+		eventhistory.NewRecorder(h.Store, h.Factory, documents, nil, h.Logger, &config.EventHistoryConfig{}),
+		rollouts.NewTracker(h.Store, h.Factory, documents, h.Logger, &config.RolloutsConfig{}),
+		h.Logger,
+	)
+	route := "/api/v1/metrics/time-travel"
+
+	var bundle TimeTravelBundle
+	h.DoJSON(http.MethodGet, route, handler.GetTimeTravel, route+"?kind=deployment&namespace=shop&name=web&at=2026-01-10T10:00:00Z&window=20m", nil, &bundle)
+	if bundle.MetricsError == "" || len(bundle.Series) != 6 || bundle.Series[0].Points == nil {
+		t.Errorf("series = %+v, metricsError = %q; want empty series with an error", bundle.Series, bundle.MetricsError)
+	}
+	if bundle.EventsSource != EventsSourceLive || len(bundle.Events) != 2 || bundle.Events[0].Reason != "ScalingReplicaSet" || bundle.Events[1].Reason != "BackOff" {
+		t.Errorf("events from %s = %+v, want the deployment's and its pod's events in the window, oldest first", bundle.EventsSource, bundle.Events)
+	}
+	if bundle.RolloutsTracked || bundle.Rollouts == nil {
+		t.Errorf("rollouts = %+v tracked %v", bundle.Rollouts, bundle.RolloutsTracked)
+	}
+
+	for _, query := range []string{
+		"kind=job&namespace=shop&name=web&at=2026-01-10T10:00:00Z",
+		"kind=deployment&name=web&at=2026-01-10T10:00:00Z",
+		"kind=deployment&namespace=shop&name=web",
+		"kind=deployment&namespace=shop&name=web&at=2100-01-01T00:00:00Z",
+		"kind=deployment&namespace=shop&name=web&at=2026-01-10T10:00:00Z&window=720h",
+	} {
+		if w := h.Do(http.MethodGet, route, handler.GetTimeTravel, route+"?"+query, nil); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status %d, want 400", query, w.Code)
+		}
+	}
+}
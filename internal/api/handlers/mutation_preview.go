@@ -0,0 +1,495 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/handlers/compare"
+
+	"github.com/gin-gonic/gin"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+)
+
+var podsGVR = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+
+// podTemplatePaths locates the pod template of workload kinds; injectors mutate the pods
+// created from it rather than the workload itself
+var podTemplatePaths = map[string][]string{
+	"Deployment":  {"spec", "template"},
+	"StatefulSet": {"spec", "template"},
+	"DaemonSet":   {"spec", "template"},
+	"ReplicaSet":  {"spec", "template"},
+	"Job":         {"spec", "template"},
+	"CronJob":     {"spec", "jobTemplate", "spec", "template"},
+}
+
+// defaultedFields are filled in by API server defaulting and built-in admission plugins rather
+// than by mutating webhooks
+var defaultedFields = map[string]bool{
+	"terminationMessagePath": true, "terminationMessagePolicy": true, "imagePullPolicy": true, "protocol": true,
+	"restartPolicy": true, "dnsPolicy": true, "schedulerName": true, "terminationGracePeriodSeconds": true,
+	"enableServiceLinks": true, "preemptionPolicy": true, "priority": true, "serviceAccount": true,
+	"serviceAccountName": true, "defaultMode": true, "revisionHistoryLimit": true, "progressDeadlineSeconds": true,
+	"strategy": true, "podManagementPolicy": true, "updateStrategy": true, "persistentVolumeClaimRetentionPolicy": true,
+	"sessionAffinity": true, "ipFamilies": true, "ipFamilyPolicy": true, "internalTrafficPolicy": true,
+	"targetPort": true, "backoffLimit": true, "completions": true, "parallelism": true, "completionMode": true,
+	"suspend": true, "podReplacementPolicy": true, "manualSelector": true, "concurrencyPolicy": true,
+	"failedJobsHistoryLimit": true, "successfulJobsHistoryLimit": true,
+}
+
+// defaultedLabels are set by the API server and controllers
+var defaultedLabels = []string{
+	"kubernetes.io/metadata.name", "controller-uid", "batch.kubernetes.io/controller-uid", "job-name", "batch.kubernetes.io/job-name",
+}
+
+// Mutation is a field admission changed between the submitted and the admitted object
+type Mutation struct {
+	Path      string      `json:"path"`
+	Change    string      `json:"change"` // added, changed or removed
+	Submitted interface{} `json:"submitted,omitempty"`
+	Result    interface{} `json:"result,omitempty"`
+	Defaulted bool        `json:"defaulted,omitempty"` // API server default rather than a webhook
+}
+
+// MatchedWebhook is a mutating webhook whose rules and selectors select the object
+type MatchedWebhook struct {
+	Configuration      string `json:"configuration"`
+	Webhook            string `json:"webhook"`
+	FailurePolicy      string `json:"failurePolicy,omitempty"`
+	SideEffects        string `json:"sideEffects,omitempty"`
+	ReinvocationPolicy string `json:"reinvocationPolicy,omitempty"`
+	Note               string `json:"note,omitempty"`
+}
+
+// PodMutationPreview is what admission does to a pod created from a workload's template
+type PodMutationPreview struct {
+	Mutations      []Mutation       `json:"mutations"`
+	HiddenDefaults int              `json:"hiddenDefaults"`
+	Webhooks       []MatchedWebhook `json:"webhooks"`
+	Error          string           `json:"error,omitempty"`
+}
+
+// MutationPreview is the admission outcome of one submitted object
+type MutationPreview struct {
+	Kind           string              `json:"kind"`
+	Name           string              `json:"name"`
+	Namespace      string              `json:"namespace,omitempty"`
+	Operation      string              `json:"operation,omitempty"` // CREATE or UPDATE
+	Mutations      []Mutation          `json:"mutations"`
+	HiddenDefaults int                 `json:"hiddenDefaults"` // defaulted fields left out of mutations
+	Webhooks       []MatchedWebhook    `json:"webhooks"`
+	Pod            *PodMutationPreview `json:"pod,omitempty"`
+	Error          string              `json:"error,omitempty"`
+}
+
+// decodeManifests decodes multi-document YAML or JSON, skipping empty documents
+func decodeManifests(content string) ([]*unstructured.Unstructured, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(strings.NewReader(content), 4096)
+	var objects []*unstructured.Unstructured
+	for {
+		var raw map[string]interface{}
+		if err := decoder.Decode(&raw); err != nil {
+			if err == io.EOF {
+				return objects, nil
+			}
+			return nil, fmt.Errorf("failed to decode YAML: %w", err)
+		}
+		if len(raw) > 0 {
+			objects = append(objects, &unstructured.Unstructured{Object: raw})
+		}
+	}
+}
+
+// isDefaultedMutation reports whether an added field is most likely API server defaulting or a
+// built-in admission plugin (service account token volume, default tolerations) rather than a webhook
+func isDefaultedMutation(d compare.FieldDiff) bool {
+	if d.Left != nil {
+		return false
+	}
+	switch v := d.Right.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return true
+		}
+		if key, _ := v["key"].(string); strings.Contains(d.Path, "tolerations[") &&
+			(key == "node.kubernetes.io/not-ready" || key == "node.kubernetes.io/unreachable") {
+			return true
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			return true
+		}
+	}
+	if strings.Contains(d.Path, "[name=kube-api-access-") {
+		return true
+	}
+	for _, label := range defaultedLabels {
+		if strings.HasSuffix(d.Path, ".labels."+label) {
+			return true
+		}
+	}
+	if d.Path == "type" || d.Path == "spec.type" {
+		return true
+	}
+	key := d.Path[strings.LastIndex(d.Path, ".")+1:]
+	if i := strings.Index(key, "["); i >= 0 {
+		key = key[:i]
+	}
+	return defaultedFields[key]
+}
+
+// admissionMutations diffs the submitted object against the admitted one. Fields missing from
+// the submission that the live object already has with the same value are not mutations;
+// live is nil for new objects.
+func admissionMutations(submitted, result, live interface{}, includeDefaults bool) ([]Mutation, int) {
+	existing := map[string]bool{}
+	if live != nil {
+		for _, d := range compare.Diff(live, result, nil) {
+			existing[d.Path] = true
+		}
+	}
+	var diffs []compare.FieldDiff
+	for _, d := range compare.Diff(submitted, result, nil) {
+		// Split lists added wholesale, e.g. the first volume, so each named item is judged on its own
+		if list, ok := d.Right.([]interface{}); ok && d.Left == nil && len(list) > 0 {
+			if names := itemNames(list); names != nil {
+				for i, item := range list {
+					diffs = append(diffs, compare.FieldDiff{Path: fmt.Sprintf("%s[name=%s]", d.Path, names[i]), Right: item})
+				}
+				continue
+			}
+		}
+		diffs = append(diffs, d)
+	}
+
+	mutations := []Mutation{}
+	hidden := 0
+	for _, d := range diffs {
+		if live != nil && d.Left == nil && !existing[d.Path] {
+			continue
+		}
+		m := Mutation{Path: d.Path, Submitted: d.Left, Result: d.Right, Defaulted: isDefaultedMutation(d)}
+		switch {
+		case d.Left == nil:
+			m.Change = "added"
+		case d.Right == nil:
+			m.Change = "removed"
+		default:
+			m.Change = "changed"
+		}
+		if m.Defaulted && !includeDefaults {
+			hidden++
+			continue
+		}
+		mutations = append(mutations, m)
+	}
+	return mutations, hidden
+}
+
+// itemNames returns the name of each list item, or nil if any item is unnamed
+func itemNames(list []interface{}) []string {
+	names := make([]string, len(list))
+	for i, item := range list {
+		m, _ := item.(map[string]interface{})
+		name, _ := m["name"].(string)
+		if name == "" {
+			return nil
+		}
+		names[i] = name
+	}
+	return names
+}
+
+func containsOrWildcard(values []string, value string) bool {
+	for _, v := range values {
+		if v == value || v == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// matchingWebhooks returns the mutating webhooks that would be called for an operation on a resource
+func matchingWebhooks(configs []admissionregistrationv1.MutatingWebhookConfiguration, gvr schema.GroupVersionResource, operation admissionregistrationv1.OperationType, objectLabels, namespaceLabels map[string]string) []MatchedWebhook {
+	matched := []MatchedWebhook{}
+	for _, config := range configs {
+		for _, webhook := range config.Webhooks {
+			ruleMatch := false
+			for _, rule := range webhook.Rules {
+				ops := make([]string, len(rule.Operations))
+				for i, op := range rule.Operations {
+					ops[i] = string(op)
+				}
+				if containsOrWildcard(ops, string(operation)) && containsOrWildcard(rule.APIGroups, gvr.Group) &&
+					containsOrWildcard(rule.APIVersions, gvr.Version) &&
+					(containsOrWildcard(rule.Resources, gvr.Resource) || containsOrWildcard(rule.Resources, "*/*")) {
+					ruleMatch = true
+					break
+				}
+			}
+			if !ruleMatch {
+				continue
+			}
+			if namespaceLabels != nil && webhook.NamespaceSelector != nil {
+				if selector, err := metav1.LabelSelectorAsSelector(webhook.NamespaceSelector); err != nil || !selector.Matches(labels.Set(namespaceLabels)) {
+					continue
+				}
+			}
+			if webhook.ObjectSelector != nil {
+				if selector, err := metav1.LabelSelectorAsSelector(webhook.ObjectSelector); err != nil || !selector.Matches(labels.Set(objectLabels)) {
+					continue
+				}
+			}
+			m := MatchedWebhook{Configuration: config.Name, Webhook: webhook.Name}
+			if webhook.FailurePolicy != nil {
+				m.FailurePolicy = string(*webhook.FailurePolicy)
+			}
+			if webhook.SideEffects != nil {
+				m.SideEffects = string(*webhook.SideEffects)
+				if *webhook.SideEffects == admissionregistrationv1.SideEffectClassSome || *webhook.SideEffects == admissionregistrationv1.SideEffectClassUnknown {
+					m.Note = "webhook does not support dry-run; dry-run requests it intercepts are rejected"
+				}
+			}
+			if webhook.ReinvocationPolicy != nil {
+				m.ReinvocationPolicy = string(*webhook.ReinvocationPolicy)
+			}
+			matched = append(matched, m)
+		}
+	}
+	return matched
+}
+
+// mutationPreviewer dry-runs submitted objects against one cluster
+type mutationPreviewer struct {
+	client          dynamic.Interface
+	mapper          meta.RESTMapper
+	webhooks        []admissionregistrationv1.MutatingWebhookConfiguration
+	includeDefaults bool
+	namespaceLabels map[string]map[string]string
+}
+
+func (p *mutationPreviewer) labelsOfNamespace(ctx context.Context, namespace string) map[string]string {
+	if l, ok := p.namespaceLabels[namespace]; ok {
+		return l
+	}
+	l := map[string]string{"kubernetes.io/metadata.name": namespace}
+	if ns, err := p.client.Resource(namespacesGVR).Get(ctx, namespace, metav1.GetOptions{}); err == nil {
+		l = ns.GetLabels()
+	}
+	p.namespaceLabels[namespace] = l
+	return l
+}
+
+// preview dry-runs an object: new objects are created and existing ones server-side applied,
+// so admission sees the same operation a real apply would make
+func (p *mutationPreviewer) preview(ctx context.Context, obj *unstructured.Unstructured) MutationPreview {
+	gvk := obj.GroupVersionKind()
+	name := obj.GetName()
+	if name == "" {
+		name = obj.GetGenerateName()
+	}
+	result := MutationPreview{Kind: gvk.Kind, Name: name, Mutations: []Mutation{}, Webhooks: []MatchedWebhook{}}
+	if gvk.Kind == "" || gvk.Version == "" {
+		result.Error = "missing required fields: apiVersion and kind"
+		return result
+	}
+	if name == "" {
+		result.Error = "missing required field: metadata.name"
+		return result
+	}
+	mapping, err := p.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to resolve GVK to resource: %v", err)
+		return result
+	}
+
+	var resource dynamic.ResourceInterface = p.client.Resource(mapping.Resource)
+	var namespaceLabels map[string]string
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		if obj.GetNamespace() == "" {
+			obj.SetNamespace("default")
+		}
+		result.Namespace = obj.GetNamespace()
+		resource = p.client.Resource(mapping.Resource).Namespace(obj.GetNamespace())
+		namespaceLabels = p.labelsOfNamespace(ctx, obj.GetNamespace())
+	}
+	submitted := compare.Normalize(obj.DeepCopy().Object, gvk.Kind)
+
+	var live *unstructured.Unstructured
+	if obj.GetName() != "" {
+		live, err = resource.Get(ctx, obj.GetName(), metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			result.Error = err.Error()
+			return result
+		}
+		if err != nil {
+			live = nil
+		}
+	}
+
+	var admitted *unstructured.Unstructured
+	if live == nil {
+		result.Operation = string(admissionregistrationv1.Create)
+		admitted, err = resource.Create(ctx, obj, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}, FieldManager: "kube-dash"})
+	} else {
+		result.Operation = string(admissionregistrationv1.Update)
+		payload, mErr := yaml.Marshal(obj.Object)
+		if mErr != nil {
+			result.Error = fmt.Sprintf("failed to marshal object to YAML: %v", mErr)
+			return result
+		}
+		admitted, err = resource.Patch(ctx, obj.GetName(), types.ApplyPatchType, payload,
+			metav1.PatchOptions{DryRun: []string{metav1.DryRunAll}, FieldManager: "kube-dash", Force: ptr.To(true)})
+	}
+	result.Webhooks = matchingWebhooks(p.webhooks, mapping.Resource, admissionregistrationv1.OperationType(result.Operation), obj.GetLabels(), namespaceLabels)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	var liveObject interface{}
+	if live != nil {
+		liveObject = compare.Normalize(live.Object, gvk.Kind)
+	}
+	result.Mutations, result.HiddenDefaults = admissionMutations(submitted, compare.Normalize(admitted.DeepCopy().Object, gvk.Kind), liveObject, p.includeDefaults)
+
+	if path, ok := podTemplatePaths[gvk.Kind]; ok {
+		if template, found, _ := unstructured.NestedMap(admitted.Object, path...); found {
+			result.Pod = p.previewPod(ctx, template, admitted.GetName(), obj.GetNamespace(), namespaceLabels)
+		}
+	}
+	return result
+}
+
+// previewPod dry-run creates a pod from a workload's admitted pod template
+func (p *mutationPreviewer) previewPod(ctx context.Context, template map[string]interface{}, owner, namespace string, namespaceLabels map[string]string) *PodMutationPreview {
+	preview := &PodMutationPreview{Mutations: []Mutation{}}
+	metadata, _ := template["metadata"].(map[string]interface{})
+	podMetadata := map[string]interface{}{"generateName": owner + "-", "namespace": namespace}
+	submitted := map[string]interface{}{"metadata": map[string]interface{}{}, "spec": template["spec"]}
+	for _, field := range []string{"labels", "annotations"} {
+		if v, ok := metadata[field]; ok {
+			podMetadata[field] = v
+			submitted["metadata"].(map[string]interface{})[field] = v
+		}
+	}
+	pod := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   podMetadata,
+		"spec":       template["spec"],
+	}}
+	preview.Webhooks = matchingWebhooks(p.webhooks, podsGVR, admissionregistrationv1.Create, pod.GetLabels(), namespaceLabels)
+
+	admitted, err := p.client.Resource(podsGVR).Namespace(namespace).Create(ctx, pod, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}, FieldManager: "kube-dash"})
+	if err != nil {
+		preview.Error = err.Error()
+		return preview
+	}
+	result := map[string]interface{}{"metadata": map[string]interface{}{}, "spec": admitted.Object["spec"]}
+	for _, field := range []string{"labels", "annotations"} {
+		if v, ok := admitted.Object["metadata"].(map[string]interface{})[field]; ok {
+			result["metadata"].(map[string]interface{})[field] = v
+		}
+	}
+	preview.Mutations, preview.HiddenDefaults = admissionMutations(compare.Normalize(submitted, "Pod"), compare.Normalize(result, "Pod"), nil, p.includeDefaults)
+	return preview
+}
+
+// PreviewMutations dry-runs manifests and reports what admission would change
+// @Summary Preview admission mutations
+// @Description Submits manifests with server-side dry-run and returns, per object, the difference between the submitted and the admitted object, so changes made by mutating webhooks such as Istio, Vault agent or OPA mutate are visible before applying. For workloads a pod created from the admitted template is dry-run as well, since injectors usually mutate pods. API server defaults are left out unless includeDefaults is set. The mutating webhooks matching each object are listed.
+// @Tags Resources
+// @Accept multipart/form-data
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name for multi-cluster setups"
+// @Param includeDefaults query bool false "Include fields filled in by API server defaulting"
+// @Param yaml formData string true "One or more YAML documents"
+// @Success 200 {object} map[string]interface{} "Per-object mutation previews"
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid manifests"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/app/apply/preview [post]
+func (h *ResourcesHandler) PreviewMutations(c *gin.Context) {
+	yamlContent := c.PostForm("yaml")
+	if strings.TrimSpace(yamlContent) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "yaml field is required"})
+		return
+	}
+	objects, err := decodeManifests(yamlContent)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(objects) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no objects in yaml"})
+		return
+	}
+
+	dynamicClient, err := h.getDynamicClient(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get dynamic client for mutation preview")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	clientset, config, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get clientset for mutation preview")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	mapper, _, err := h.clientFactory.GetRESTMapperForConfig(config, c.Query("cluster"))
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get REST mapper for mutation preview")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	includeDefaults, _ := strconv.ParseBool(c.Query("includeDefaults"))
+	previewer := &mutationPreviewer{
+		client:          dynamicClient,
+		mapper:          mapper,
+		includeDefaults: includeDefaults,
+		namespaceLabels: map[string]map[string]string{},
+	}
+	webhookNote := ""
+	if configs, err := clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, metav1.ListOptions{}); err == nil {
+		previewer.webhooks = configs.Items
+	} else {
+		h.logger.WithError(err).Warn("Failed to list mutating webhook configurations for mutation preview")
+		webhookNote = "mutating webhook configurations could not be listed: " + err.Error()
+	}
+
+	previews := make([]MutationPreview, 0, len(objects))
+	failed := 0
+	for _, obj := range objects {
+		preview := previewer.preview(ctx, obj)
+		if preview.Error != "" {
+			failed++
+		}
+		previews = append(previews, preview)
+	}
+
+	response := gin.H{"objects": previews, "failed": failed}
+	if webhookNote != "" {
+		response["note"] = webhookNote
+	}
+	c.JSON(http.StatusOK, response)
+}
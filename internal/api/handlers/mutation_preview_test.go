@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"testing"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAdmissionMutations(t *testing.T) {
+	submitted := map[string]interface{}{
+		"metadata": map[string]interface{}{"labels": map[string]interface{}{"app": "web"}},
+		"spec": map[string]interface{}{"containers": []interface{}{
+			map[string]interface{}{"name": "app", "image": "web:1"},
+		}},
+	}
+	result := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels":      map[string]interface{}{"app": "web"},
+			"annotations": map[string]interface{}{"sidecar.istio.io/status": "injected"},
+		},
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"name": "app", "image": "registry.internal/web:1", "imagePullPolicy": "IfNotPresent"},
+				map[string]interface{}{"name": "istio-proxy", "image": "proxyv2"},
+			},
+			"dnsPolicy": "ClusterFirst",
+			"volumes":   []interface{}{map[string]interface{}{"name": "kube-api-access-x1", "projected": map[string]interface{}{}}},
+		},
+	}
+
+	mutations, hidden := admissionMutations(submitted, result, nil, false)
+	changes := map[string]string{}
+	for _, m := range mutations {
+		changes[m.Path] = m.Change
+	}
+	if len(mutations) != 3 || changes["metadata.annotations"] != "added" ||
+		changes["spec.containers[name=istio-proxy]"] != "added" || changes["spec.containers[name=app].image"] != "changed" {
+		t.Errorf("unexpected mutations %+v", mutations)
+	}
+	// imagePullPolicy, dnsPolicy and the service account token volume
+	if hidden != 3 {
+		t.Errorf("expected 3 hidden defaults, got %d", hidden)
+	}
+	if all, _ := admissionMutations(submitted, result, nil, true); len(all) != 6 {
+		t.Errorf("expected defaults to be included, got %+v", all)
+	}
+
+	// Fields the live object already has are not attributed to admission
+	live := map[string]interface{}{"metadata": result["metadata"], "spec": map[string]interface{}{}}
+	mutations, _ = admissionMutations(submitted, result, live, false)
+	for _, m := range mutations {
+		if m.Path == "metadata.annotations" {
+			t.Errorf("expected existing annotation to be ignored, got %+v", mutations)
+		}
+	}
+}
+
+func TestMatchingWebhooks(t *testing.T) {
+	none := admissionregistrationv1.SideEffectClassNone
+	configs := []admissionregistrationv1.MutatingWebhookConfiguration{{
+		ObjectMeta: metav1.ObjectMeta{Name: "istio-sidecar-injector"},
+		Webhooks: []admissionregistrationv1.MutatingWebhook{{
+			Name:              "sidecar-injector.istio.io",
+			SideEffects:       &none,
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"istio-injection": "enabled"}},
+			Rules: []admissionregistrationv1.RuleWithOperations{{
+				Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+				Rule:       admissionregistrationv1.Rule{APIGroups: []string{""}, APIVersions: []string{"v1"}, Resources: []string{"pods"}},
+			}},
+		}},
+	}}
+
+	injected := map[string]string{"istio-injection": "enabled"}
+	if matched := matchingWebhooks(configs, podsGVR, admissionregistrationv1.Create, nil, injected); len(matched) != 1 || matched[0].Webhook != "sidecar-injector.istio.io" {
+		t.Errorf("expected injector to match pods in labelled namespace, got %+v", matched)
+	}
+	if matched := matchingWebhooks(configs, podsGVR, admissionregistrationv1.Create, nil, map[string]string{}); len(matched) != 0 {
+		t.Errorf("expected namespace selector to exclude unlabelled namespace, got %+v", matched)
+	}
+	if matched := matchingWebhooks(configs, podsGVR, admissionregistrationv1.Update, nil, injected); len(matched) != 0 {
+		t.Errorf("expected CREATE-only webhook not to match updates, got %+v", matched)
+	}
+}
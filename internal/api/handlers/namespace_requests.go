@@ -0,0 +1,342 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Facets-cloud/kube-dash/internal/apitokens"
+	"github.com/Facets-cloud/kube-dash/internal/audit"
+	"github.com/Facets-cloud/kube-dash/internal/nsrequests"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Annotations recording who owns a namespace created from a namespace request
+const (
+	namespaceOwnerAnnotation      = "kube-dash.io/owner"
+	namespaceRequestAnnotation    = "kube-dash.io/namespace-request"
+	namespaceApprovedByAnnotation = "kube-dash.io/approved-by"
+)
+
+// NewNamespaceRequest describes the namespace being requested
+type NewNamespaceRequest struct {
+	ConfigID  string `json:"configId" binding:"required"`
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace" binding:"required"`
+	Tier      string `json:"tier" binding:"required"` // ID of a requestable namespace template
+	Reason    string `json:"reason" binding:"required"`
+}
+
+// NamespaceRequestDecision carries an approver's note
+type NamespaceRequestDecision struct {
+	Note string `json:"note"`
+}
+
+// NamespaceRequestApproval is the approved request and the outcome of creating its namespace
+type NamespaceRequestApproval struct {
+	Request *nsrequests.Request       `json:"request"`
+	Result  *NamespaceBootstrapResult `json:"result,omitempty"`
+}
+
+// ownerAnnotations records the requester, the request and its approver on the namespace
+func ownerAnnotations(req *nsrequests.Request) map[string]string {
+	return map[string]string{
+		namespaceOwnerAnnotation:      req.Requester,
+		namespaceRequestAnnotation:    req.ID,
+		namespaceApprovedByAnnotation: req.DecidedBy,
+	}
+}
+
+func (h *NamespaceTemplatesHandler) namespaceRequestError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, storage.ErrDocumentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "namespace request not found"})
+	case errors.Is(err, nsrequests.ErrNotDecidable), errors.Is(err, nsrequests.ErrDuplicate):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		h.logger.WithError(err).Error("Namespace request operation failed")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// recordNamespaceRequest adds a namespace request event to the audit trail
+func (h *NamespaceTemplatesHandler) recordNamespaceRequest(c *gin.Context, action string, req *nsrequests.Request) {
+	outcome := audit.OutcomeSuccess
+	if req.Status == nsrequests.StatusFailed {
+		outcome = audit.OutcomeFailure
+	}
+	h.auditor.Record(audit.Event{
+		Action:     action,
+		Outcome:    outcome,
+		Reason:     req.Reason,
+		RemoteAddr: c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+		ConfigID:   req.ConfigID,
+		Cluster:    req.Cluster,
+		Namespace:  req.Namespace,
+		Resource:   "NamespaceRequest/" + req.ID,
+		Details: map[string]string{
+			"requester": req.Requester,
+			"tier":      req.TierName,
+			"actor":     apitokens.Actor(c),
+			"note":      req.DecisionNote,
+			"applied":   fmt.Sprint(req.Applied),
+			"failed":    fmt.Sprint(req.Failed),
+		},
+	})
+}
+
+// canDecide stops callers that may not approve or deny a request: elevation-bound API tokens,
+// and the API token that filed it
+func canDecide(c *gin.Context, req *nsrequests.Request) bool {
+	token, ok := apitokens.FromContext(c)
+	if !ok {
+		return true
+	}
+	if token.RequireElevation {
+		c.JSON(http.StatusForbidden, gin.H{"error": "API tokens that require elevation cannot decide namespace requests"})
+		return false
+	}
+	if req.TokenID == token.ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "a namespace request cannot be decided with the API token that filed it"})
+		return false
+	}
+	return true
+}
+
+// ListNamespaceRequests returns namespace requests, newest first
+// @Summary List namespace requests
+// @Description Lists self-service namespace requests with their status (pending, provisioning, approved, failed or denied)
+// @Tags Cluster
+// @Produce json
+// @Param status query string false "Only requests with this status"
+// @Param config query string false "Only requests for this config ID"
+// @Param mine query bool false "Only requests filed by the caller"
+// @Success 200 {array} nsrequests.Request "Namespace requests"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/namespace-requests [get]
+func (h *NamespaceTemplatesHandler) ListNamespaceRequests(c *gin.Context) {
+	filter := nsrequests.Filter{Status: c.Query("status"), ConfigID: c.Query("config")}
+	if c.Query("mine") == "true" {
+		filter.Requester = apitokens.Actor(c)
+	}
+	requests, err := h.requests.List(filter)
+	if err != nil {
+		h.namespaceRequestError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, requests)
+}
+
+// GetNamespaceRequest returns a single namespace request
+// @Summary Get namespace request
+// @Description Returns a self-service namespace request by ID
+// @Tags Cluster
+// @Produce json
+// @Param id path string true "Request ID"
+// @Success 200 {object} nsrequests.Request "Namespace request"
+// @Failure 404 {object} map[string]string "Request not found"
+// @Security BearerAuth
+// @Router /api/v1/namespace-requests/{id} [get]
+func (h *NamespaceTemplatesHandler) GetNamespaceRequest(c *gin.Context) {
+	req, err := h.requests.Get(c.Param("id"))
+	if err != nil {
+		h.namespaceRequestError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, req)
+}
+
+// CreateNamespaceRequest files a request for a new namespace of a size tier
+// @Summary Request a namespace
+// @Description Files a request for a new namespace bootstrapped from a size tier, a namespace template marked requestable. Nothing is created until an approver approves the request. Only one pending request per namespace and cluster is accepted.
+// @Tags Cluster
+// @Accept json
+// @Produce json
+// @Param request body NewNamespaceRequest true "Requested namespace"
+// @Success 201 {object} nsrequests.Request "Pending namespace request"
+// @Failure 400 {object} map[string]string "Bad request - invalid request, unknown config or tier"
+// @Failure 409 {object} map[string]string "An open request for this namespace already exists"
+// @Security BearerAuth
+// @Router /api/v1/namespace-requests [post]
+func (h *NamespaceTemplatesHandler) CreateNamespaceRequest(c *gin.Context) {
+	var body NewNamespaceRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if _, err := h.resources.store.GetKubeConfig(body.ConfigID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown config: " + body.ConfigID})
+		return
+	}
+	req := nsrequests.Request{
+		Requester: apitokens.Actor(c),
+		ConfigID:  body.ConfigID,
+		Cluster:   body.Cluster,
+		Namespace: body.Namespace,
+		Tier:      body.Tier,
+		Reason:    body.Reason,
+	}
+	if token, ok := apitokens.FromContext(c); ok {
+		if !token.AllowsCluster(body.ConfigID, body.Cluster) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "API token is not scoped to this cluster"})
+			return
+		}
+		req.TokenID = token.ID
+	}
+	template, err := h.templates.Get(body.Tier)
+	if err != nil || !template.Requestable {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown tier: " + body.Tier})
+		return
+	}
+	req.TierName = template.Name
+
+	if err := h.requests.Create(&req); err != nil {
+		if errors.Is(err, nsrequests.ErrDuplicate) {
+			h.namespaceRequestError(c, err)
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.recordNamespaceRequest(c, "namespace.request", &req)
+	c.JSON(http.StatusCreated, req)
+}
+
+// bindDecision reads the optional decision body
+func bindDecision(c *gin.Context) (NamespaceRequestDecision, bool) {
+	var body NamespaceRequestDecision
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+			return body, false
+		}
+	}
+	return body, true
+}
+
+// ApproveNamespaceRequest approves a request and creates its namespace
+// @Summary Approve namespace request
+// @Description Approves a pending request, or retries a failed one, and creates the namespace from the request's tier through the namespace template bootstrap: the namespace gets the template's labels and annotations plus kube-dash.io/owner, kube-dash.io/namespace-request and kube-dash.io/approved-by annotations, then the template's objects are applied into it. The request ends approved, or failed with the outcome of each object when any was rejected. Elevation-bound API tokens and the token that filed the request cannot approve.
+// @Tags Cluster
+// @Accept json
+// @Produce json
+// @Param id path string true "Request ID"
+// @Param decision body NamespaceRequestDecision false "Approval note"
+// @Success 201 {object} NamespaceRequestApproval "Namespace created"
+// @Failure 400 {object} map[string]interface{} "Bad request, or one or more objects failed"
+// @Failure 403 {object} map[string]string "Caller cannot approve"
+// @Failure 404 {object} map[string]string "Request not found"
+// @Failure 409 {object} map[string]interface{} "Request is not pending, its tier was deleted, or the namespace already exists"
+// @Security BearerAuth
+// @Router /api/v1/namespace-requests/{id}/approve [post]
+func (h *NamespaceTemplatesHandler) ApproveNamespaceRequest(c *gin.Context) {
+	req, err := h.requests.Get(c.Param("id"))
+	if err != nil {
+		h.namespaceRequestError(c, err)
+		return
+	}
+	if !canDecide(c, req) {
+		return
+	}
+	body, ok := bindDecision(c)
+	if !ok {
+		return
+	}
+	template, err := h.templates.Get(req.Tier)
+	if err != nil {
+		if errors.Is(err, storage.ErrDocumentNotFound) {
+			c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("tier %q no longer exists; deny the request instead", req.TierName)})
+			return
+		}
+		h.templateError(c, err)
+		return
+	}
+	objects, err := template.Render(req.Namespace)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	dynamicClient, restMapper, err := h.resources.dynamicClientFor(req.ConfigID, req.Cluster)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	req, err = h.requests.StartProvisioning(req.ID, apitokens.Actor(c), body.Note)
+	if err != nil {
+		h.namespaceRequestError(c, err)
+		return
+	}
+	ctx := c.Request.Context()
+	if _, err := dynamicClient.Resource(namespaceGVR).Get(ctx, req.Namespace, metav1.GetOptions{}); err == nil || !apierrors.IsNotFound(err) {
+		status, message := http.StatusConflict, fmt.Sprintf("namespace %q already exists", req.Namespace)
+		if err != nil {
+			status, message = http.StatusBadRequest, err.Error()
+		}
+		if req, err = h.requests.Complete(req.ID, 0, 0, message); err != nil {
+			h.namespaceRequestError(c, err)
+			return
+		}
+		h.recordNamespaceRequest(c, "namespace.request.approve", req)
+		c.JSON(status, gin.H{"error": message, "request": req})
+		return
+	}
+
+	result := createNamespace(ctx, dynamicClient, restMapper, namespaceObject(req.Namespace, template, nil, ownerAnnotations(req)), template, objects, false)
+	if req, err = h.requests.Complete(req.ID, result.Applied, result.Failed, ""); err != nil {
+		h.namespaceRequestError(c, err)
+		return
+	}
+	h.recordNamespaceRequest(c, "namespace.request.approve", req)
+	if result.Failed > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "failed to apply one or more resources",
+			"code":    http.StatusBadRequest,
+			"request": req,
+			"result":  result,
+		})
+		return
+	}
+	c.JSON(http.StatusCreated, NamespaceRequestApproval{Request: req, Result: result})
+}
+
+// DenyNamespaceRequest rejects a request
+// @Summary Deny namespace request
+// @Description Rejects a pending or failed namespace request. Elevation-bound API tokens and the token that filed the request cannot deny.
+// @Tags Cluster
+// @Accept json
+// @Produce json
+// @Param id path string true "Request ID"
+// @Param decision body NamespaceRequestDecision false "Denial note"
+// @Success 200 {object} nsrequests.Request "Denied request"
+// @Failure 403 {object} map[string]string "Caller cannot deny"
+// @Failure 404 {object} map[string]string "Request not found"
+// @Failure 409 {object} map[string]string "Request is not pending"
+// @Security BearerAuth
+// @Router /api/v1/namespace-requests/{id}/deny [post]
+func (h *NamespaceTemplatesHandler) DenyNamespaceRequest(c *gin.Context) {
+	req, err := h.requests.Get(c.Param("id"))
+	if err != nil {
+		h.namespaceRequestError(c, err)
+		return
+	}
+	if !canDecide(c, req) {
+		return
+	}
+	body, ok := bindDecision(c)
+	if !ok {
+		return
+	}
+	if req, err = h.requests.Deny(req.ID, apitokens.Actor(c), body.Note); err != nil {
+		h.namespaceRequestError(c, err)
+		return
+	}
+	h.recordNamespaceRequest(c, "namespace.request.deny", req)
+	c.JSON(http.StatusOK, req)
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/Facets-cloud/kube-dash/internal/nsrequests"
+	"github.com/Facets-cloud/kube-dash/internal/nstemplates"
+)
+
+func TestNamespaceFromRequestRecordsOwner(t *testing.T) {
+	tier := &nstemplates.Template{
+		Name:        "small",
+		Labels:      map[string]string{"tier": "small"},
+		Annotations: map[string]string{namespaceOwnerAnnotation: "platform"},
+		Requestable: true,
+	}
+	req := &nsrequests.Request{ID: "r-1", Requester: "alice", DecidedBy: "carol", Namespace: "payments-dev"}
+
+	ns := namespaceObject(req.Namespace, tier, nil, ownerAnnotations(req))
+	annotations := ns.GetAnnotations()
+	if annotations[namespaceOwnerAnnotation] != "alice" || annotations[namespaceRequestAnnotation] != "r-1" || annotations[namespaceApprovedByAnnotation] != "carol" {
+		t.Errorf("annotations = %v, want the requester, request and approver recorded", annotations)
+	}
+	if annotations[namespaceTemplateAnnotation] != "small" || ns.GetLabels()["tier"] != "small" {
+		t.Errorf("namespace = %v, want the tier's metadata kept", ns.Object)
+	}
+}
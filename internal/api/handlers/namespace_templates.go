@@ -0,0 +1,534 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Facets-cloud/kube-dash/internal/apitokens"
+	"github.com/Facets-cloud/kube-dash/internal/audit"
+	"github.com/Facets-cloud/kube-dash/internal/nsrequests"
+	"github.com/Facets-cloud/kube-dash/internal/nstemplates"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/dynamic"
+)
+
+// namespaceTemplateAnnotation records the template a namespace was bootstrapped from
+const namespaceTemplateAnnotation = "kube-dash.io/namespace-template"
+
+// Outcomes of one object in a namespace bootstrap
+const (
+	bootstrapApplied   = "applied"
+	bootstrapValidated = "validated" // dry run: admitted by the API server without being persisted
+	bootstrapSkipped   = "skipped"
+	bootstrapFailed    = "failed"
+)
+
+// Reasons a bootstrap object failed
+const (
+	bootstrapReasonAdmission = "admission-denied" // rejected by an admission webhook or plugin
+	bootstrapReasonQuota     = "quota-exceeded"
+	bootstrapReasonForbidden = "forbidden" // the kubeconfig credentials lack RBAC permissions
+	bootstrapReasonInvalid   = "invalid"
+	bootstrapReasonError     = "error"
+)
+
+var namespaceGVR = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+
+// NamespaceCreateRequest describes a namespace to create and the template to bootstrap it with
+type NamespaceCreateRequest struct {
+	Name        string            `json:"name"`
+	Labels      map[string]string `json:"labels,omitempty"`      // override the template's labels
+	Annotations map[string]string `json:"annotations,omitempty"` // override the template's annotations
+	Template    string            `json:"template,omitempty"`    // template ID
+	DryRun      bool              `json:"dryRun,omitempty"`
+}
+
+// NamespaceTemplateApplyRequest applies a template to an existing namespace
+type NamespaceTemplateApplyRequest struct {
+	Template string `json:"template"`
+	DryRun   bool   `json:"dryRun,omitempty"`
+}
+
+// BootstrapResult is the outcome of one object of a namespace bootstrap
+type BootstrapResult struct {
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	Group    string `json:"group,omitempty"`
+	Version  string `json:"version,omitempty"`
+	Resource string `json:"resource,omitempty"`
+	Status   string `json:"status"`
+	Reason   string `json:"reason,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+// NamespaceBootstrapResult is the outcome of creating or bootstrapping a namespace. The
+// namespace itself is the first result when it was created.
+type NamespaceBootstrapResult struct {
+	Namespace string            `json:"namespace"`
+	Template  string            `json:"template,omitempty"`
+	DryRun    bool              `json:"dryRun"`
+	Results   []BootstrapResult `json:"results"`
+	Applied   int               `json:"applied"`
+	Failed    int               `json:"failed"`
+}
+
+// NamespaceTemplatesHandler manages namespace bootstrap templates and creates namespaces from
+// them, directly or through approved namespace requests
+type NamespaceTemplatesHandler struct {
+	templates *nstemplates.Store
+	requests  *nsrequests.Store
+	resources *ResourcesHandler
+	auditor   *audit.Recorder
+	logger    *logger.Logger
+}
+
+// NewNamespaceTemplatesHandler creates a new namespace templates handler; objects are applied
+// through the resources handler's server-side apply pipeline
+func NewNamespaceTemplatesHandler(templates *nstemplates.Store, requests *nsrequests.Store, resources *ResourcesHandler, auditor *audit.Recorder, log *logger.Logger) *NamespaceTemplatesHandler {
+	return &NamespaceTemplatesHandler{
+		templates: templates,
+		requests:  requests,
+		resources: resources,
+		auditor:   auditor,
+		logger:    log,
+	}
+}
+
+// classifyBootstrapError tells admission rejections apart from missing permissions and invalid
+// objects. Admission plugins and RBAC both answer Forbidden, so the message decides.
+func classifyBootstrapError(message string) string {
+	switch {
+	case strings.Contains(message, "admission webhook"):
+		return bootstrapReasonAdmission
+	case strings.Contains(message, "exceeded quota"):
+		return bootstrapReasonQuota
+	case strings.Contains(message, "is forbidden: User ") || strings.Contains(message, "cannot patch resource") || strings.Contains(message, "cannot create resource"):
+		return bootstrapReasonForbidden
+	case strings.Contains(message, "is forbidden"):
+		return bootstrapReasonAdmission
+	case strings.Contains(message, "is invalid"):
+		return bootstrapReasonInvalid
+	}
+	return bootstrapReasonError
+}
+
+// namespaceObject builds a namespace from a template's metadata and the request's overrides
+func namespaceObject(name string, template *nstemplates.Template, labels, annotations map[string]string) *unstructured.Unstructured {
+	ns := &unstructured.Unstructured{}
+	ns.SetAPIVersion("v1")
+	ns.SetKind("Namespace")
+	ns.SetName(name)
+	mergedLabels := map[string]string{}
+	mergedAnnotations := map[string]string{}
+	if template != nil {
+		for k, v := range template.Labels {
+			mergedLabels[k] = v
+		}
+		for k, v := range template.Annotations {
+			mergedAnnotations[k] = v
+		}
+		mergedAnnotations[namespaceTemplateAnnotation] = template.Name
+	}
+	for k, v := range labels {
+		mergedLabels[k] = v
+	}
+	for k, v := range annotations {
+		mergedAnnotations[k] = v
+	}
+	if len(mergedLabels) > 0 {
+		ns.SetLabels(mergedLabels)
+	}
+	if len(mergedAnnotations) > 0 {
+		ns.SetAnnotations(mergedAnnotations)
+	}
+	return ns
+}
+
+// applyBootstrapObject applies one object and reports its outcome
+func applyBootstrapObject(ctx context.Context, dynamicClient dynamic.Interface, restMapper meta.RESTMapper, obj *unstructured.Unstructured, dryRun bool) BootstrapResult {
+	gvk := obj.GroupVersionKind()
+	result := BootstrapResult{Kind: gvk.Kind, Name: obj.GetName(), Group: gvk.Group, Version: gvk.Version}
+	if obj.GetKind() != "Namespace" {
+		mapping, err := restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err == nil && mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+			result.Status, result.Reason = bootstrapFailed, bootstrapReasonInvalid
+			result.Message = "cluster-scoped resources cannot be part of a namespace template"
+			return result
+		}
+	}
+	applied, failure := applyObject(ctx, dynamicClient, restMapper, obj, dryRun)
+	if failure != nil {
+		result.Status, result.Reason, result.Message = bootstrapFailed, classifyBootstrapError(failure.Message), failure.Message
+		return result
+	}
+	result.Resource = applied.Resource
+	result.Status = bootstrapApplied
+	if dryRun {
+		result.Status = bootstrapValidated
+	}
+	return result
+}
+
+// bootstrap applies a template's objects into a namespace, in template order. When the
+// namespace does not exist yet, a dry run can only render the objects.
+func bootstrap(ctx context.Context, dynamicClient dynamic.Interface, restMapper meta.RESTMapper, result *NamespaceBootstrapResult, objects []*unstructured.Unstructured, namespaceExists bool) {
+	for _, obj := range objects {
+		var outcome BootstrapResult
+		if result.DryRun && !namespaceExists {
+			gvk := obj.GroupVersionKind()
+			outcome = BootstrapResult{Kind: gvk.Kind, Name: obj.GetName(), Group: gvk.Group, Version: gvk.Version, Status: bootstrapSkipped,
+				Message: "rendered only; admission is checked once the namespace exists"}
+		} else {
+			outcome = applyBootstrapObject(ctx, dynamicClient, restMapper, obj, result.DryRun)
+		}
+		result.record(outcome)
+	}
+}
+
+// createNamespace creates a namespace that does not exist yet and, once it is admitted,
+// bootstraps it with the template's rendered objects
+func createNamespace(ctx context.Context, dynamicClient dynamic.Interface, restMapper meta.RESTMapper, ns *unstructured.Unstructured, template *nstemplates.Template, objects []*unstructured.Unstructured, dryRun bool) *NamespaceBootstrapResult {
+	result := &NamespaceBootstrapResult{Namespace: ns.GetName(), DryRun: dryRun, Results: []BootstrapResult{}}
+	if template != nil {
+		result.Template = template.Name
+	}
+	nsResult := applyBootstrapObject(ctx, dynamicClient, restMapper, ns, dryRun)
+	result.record(nsResult)
+	if nsResult.Status != bootstrapFailed {
+		bootstrap(ctx, dynamicClient, restMapper, result, objects, false)
+	}
+	return result
+}
+
+// record adds an outcome and updates the counts
+func (r *NamespaceBootstrapResult) record(outcome BootstrapResult) {
+	r.Results = append(r.Results, outcome)
+	switch outcome.Status {
+	case bootstrapApplied, bootstrapValidated:
+		r.Applied++
+	case bootstrapFailed:
+		r.Failed++
+	}
+}
+
+func (h *NamespaceTemplatesHandler) templateError(c *gin.Context, err error) {
+	if errors.Is(err, storage.ErrDocumentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "namespace template not found"})
+		return
+	}
+	h.logger.WithError(err).Error("Namespace template operation failed")
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+// recordBootstrap adds a namespace creation or bootstrap to the audit trail
+func (h *NamespaceTemplatesHandler) recordBootstrap(c *gin.Context, action string, result *NamespaceBootstrapResult) {
+	if result.DryRun {
+		return
+	}
+	outcome := audit.OutcomeSuccess
+	if result.Failed > 0 {
+		outcome = audit.OutcomeFailure
+	}
+	h.auditor.Record(audit.Event{
+		Action:     action,
+		Outcome:    outcome,
+		RemoteAddr: c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+		ConfigID:   c.Query("config"),
+		Cluster:    c.Query("cluster"),
+		Namespace:  result.Namespace,
+		Resource:   "Namespace/" + result.Namespace,
+		Details: map[string]string{
+			"template": result.Template,
+			"applied":  fmt.Sprint(result.Applied),
+			"failed":   fmt.Sprint(result.Failed),
+			"actor":    apitokens.Actor(c),
+		},
+	})
+}
+
+// respondBootstrap answers with the bootstrap result, failing the request if any object failed
+func respondBootstrap(c *gin.Context, result *NamespaceBootstrapResult, successStatus int) {
+	if result.Failed > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "failed to apply one or more resources",
+			"code":    http.StatusBadRequest,
+			"result":  result,
+		})
+		return
+	}
+	c.JSON(successStatus, result)
+}
+
+// ListNamespaceTemplates lists namespace bootstrap templates
+// @Summary List namespace templates
+// @Description Lists the namespace bootstrap templates, sorted by name. With requestable=true only the templates offered as size tiers for namespace requests are listed.
+// @Tags Cluster
+// @Produce json
+// @Param requestable query bool false "Only templates offered as namespace request tiers"
+// @Success 200 {array} nstemplates.Template "Namespace templates"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/namespace-templates [get]
+func (h *NamespaceTemplatesHandler) ListNamespaceTemplates(c *gin.Context) {
+	templates, err := h.templates.List()
+	if err != nil {
+		h.templateError(c, err)
+		return
+	}
+	if c.Query("requestable") == "true" {
+		tiers := []nstemplates.Template{}
+		for _, t := range templates {
+			if t.Requestable {
+				tiers = append(tiers, t)
+			}
+		}
+		templates = tiers
+	}
+	c.JSON(http.StatusOK, templates)
+}
+
+// GetNamespaceTemplate returns a namespace template
+// @Summary Get namespace template
+// @Description Returns a namespace bootstrap template
+// @Tags Cluster
+// @Produce json
+// @Param id path string true "Template ID"
+// @Success 200 {object} nstemplates.Template "Namespace template"
+// @Failure 404 {object} map[string]string "Template not found"
+// @Security BearerAuth
+// @Router /api/v1/namespace-templates/{id} [get]
+func (h *NamespaceTemplatesHandler) GetNamespaceTemplate(c *gin.Context) {
+	t, err := h.templates.Get(c.Param("id"))
+	if err != nil {
+		h.templateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// CreateNamespaceTemplate saves a new namespace template
+// @Summary Create namespace template
+// @Description Saves a namespace bootstrap template: labels and annotations for the namespace, and multi-document YAML of namespaced objects such as a ResourceQuota, LimitRange, NetworkPolicies, Roles and RoleBindings. ${NAMESPACE} in the YAML is replaced with the namespace name; objects may not set another namespace.
+// @Tags Cluster
+// @Accept json
+// @Produce json
+// @Param body body nstemplates.Template true "Namespace template"
+// @Success 201 {object} nstemplates.Template "Created template"
+// @Failure 400 {object} map[string]string "Bad request - invalid template"
+// @Security BearerAuth
+// @Router /api/v1/namespace-templates [post]
+func (h *NamespaceTemplatesHandler) CreateNamespaceTemplate(c *gin.Context) {
+	var t nstemplates.Template
+	if err := c.ShouldBindJSON(&t); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	t.ID = ""
+	if err := h.templates.Save(&t); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, t)
+}
+
+// UpdateNamespaceTemplate replaces a namespace template
+// @Summary Update namespace template
+// @Description Replaces a namespace bootstrap template; namespaces already bootstrapped from it are not changed
+// @Tags Cluster
+// @Accept json
+// @Produce json
+// @Param id path string true "Template ID"
+// @Param body body nstemplates.Template true "Namespace template"
+// @Success 200 {object} nstemplates.Template "Updated template"
+// @Failure 400 {object} map[string]string "Bad request - invalid template"
+// @Failure 404 {object} map[string]string "Template not found"
+// @Security BearerAuth
+// @Router /api/v1/namespace-templates/{id} [put]
+func (h *NamespaceTemplatesHandler) UpdateNamespaceTemplate(c *gin.Context) {
+	existing, err := h.templates.Get(c.Param("id"))
+	if err != nil {
+		h.templateError(c, err)
+		return
+	}
+	var t nstemplates.Template
+	if err := c.ShouldBindJSON(&t); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	t.ID, t.CreatedAt = existing.ID, existing.CreatedAt
+	if err := h.templates.Save(&t); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// DeleteNamespaceTemplate removes a namespace template
+// @Summary Delete namespace template
+// @Description Removes a namespace bootstrap template; namespaces bootstrapped from it are not changed
+// @Tags Cluster
+// @Param id path string true "Template ID"
+// @Success 204 "Template deleted"
+// @Failure 404 {object} map[string]string "Template not found"
+// @Security BearerAuth
+// @Router /api/v1/namespace-templates/{id} [delete]
+func (h *NamespaceTemplatesHandler) DeleteNamespaceTemplate(c *gin.Context) {
+	if err := h.templates.Delete(c.Param("id")); err != nil {
+		h.templateError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// loadTemplate returns the template with the ID, or nil when none is requested
+func (h *NamespaceTemplatesHandler) loadTemplate(c *gin.Context, id string) (*nstemplates.Template, bool) {
+	if id == "" {
+		return nil, true
+	}
+	t, err := h.templates.Get(id)
+	if err != nil {
+		h.templateError(c, err)
+		return nil, false
+	}
+	return t, true
+}
+
+// CreateNamespace creates a namespace, optionally bootstrapped from a template
+// @Summary Create namespace
+// @Description Creates a namespace with the template's labels and annotations (overridden by the request's), then server-side applies the template's objects into it in order and reports the outcome of each. Failures are classified as admission-denied (admission webhooks and plugins such as PodSecurity), quota-exceeded, forbidden (missing RBAC permissions of the kubeconfig credentials), invalid or error. With dryRun the namespace is validated by the API server and admission without being persisted, and the template's objects are only rendered.
+// @Tags Cluster
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param body body NamespaceCreateRequest true "Namespace to create"
+// @Success 201 {object} NamespaceBootstrapResult "Namespace created and bootstrapped"
+// @Success 200 {object} NamespaceBootstrapResult "Dry run result"
+// @Failure 400 {object} map[string]interface{} "Bad request, or one or more objects failed"
+// @Failure 404 {object} map[string]string "Template not found"
+// @Failure 409 {object} map[string]string "Namespace already exists"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/namespaces [post]
+func (h *NamespaceTemplatesHandler) CreateNamespace(c *gin.Context) {
+	var req NamespaceCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if errs := validation.IsDNS1123Label(req.Name); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid namespace name %q: %s", req.Name, strings.Join(errs, "; "))})
+		return
+	}
+	template, ok := h.loadTemplate(c, req.Template)
+	if !ok {
+		return
+	}
+	var objects []*unstructured.Unstructured
+	if template != nil {
+		var err error
+		if objects, err = template.Render(req.Name); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if c.Query("config") == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "config parameter is required"})
+		return
+	}
+	dynamicClient, restMapper, err := h.resources.dynamicClientFor(c.Query("config"), c.Query("cluster"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx := c.Request.Context()
+	if _, err := dynamicClient.Resource(namespaceGVR).Get(ctx, req.Name, metav1.GetOptions{}); err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("namespace %q already exists; apply a template to it instead", req.Name)})
+		return
+	} else if !apierrors.IsNotFound(err) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result := createNamespace(ctx, dynamicClient, restMapper, namespaceObject(req.Name, template, req.Labels, req.Annotations), template, objects, req.DryRun)
+	h.recordBootstrap(c, "namespace.create", result)
+
+	status := http.StatusCreated
+	if req.DryRun {
+		status = http.StatusOK
+	}
+	respondBootstrap(c, result, status)
+}
+
+// ApplyNamespaceTemplate applies a template to an existing namespace
+// @Summary Apply namespace template
+// @Description Server-side applies a template's labels and annotations to an existing namespace and its objects into it, reporting the outcome of each object as for namespace creation. With dryRun every object is checked by the API server and admission without being persisted, which shows what admission policies would reject.
+// @Tags Cluster
+// @Accept json
+// @Produce json
+// @Param name path string true "Namespace name"
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param body body NamespaceTemplateApplyRequest true "Template to apply"
+// @Success 200 {object} NamespaceBootstrapResult "Template applied"
+// @Failure 400 {object} map[string]interface{} "Bad request, or one or more objects failed"
+// @Failure 404 {object} map[string]string "Namespace or template not found"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/namespaces/{name}/template [post]
+func (h *NamespaceTemplatesHandler) ApplyNamespaceTemplate(c *gin.Context) {
+	var req NamespaceTemplateApplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Template == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "template is required"})
+		return
+	}
+	name := c.Param("name")
+	template, ok := h.loadTemplate(c, req.Template)
+	if !ok {
+		return
+	}
+	objects, err := template.Render(name)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if c.Query("config") == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "config parameter is required"})
+		return
+	}
+	dynamicClient, restMapper, err := h.resources.dynamicClientFor(c.Query("config"), c.Query("cluster"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx := c.Request.Context()
+	if _, err := dynamicClient.Resource(namespaceGVR).Get(ctx, name, metav1.GetOptions{}); err != nil {
+		status := http.StatusBadRequest
+		if apierrors.IsNotFound(err) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	result := &NamespaceBootstrapResult{Namespace: name, Template: template.Name, DryRun: req.DryRun, Results: []BootstrapResult{}}
+	result.record(applyBootstrapObject(ctx, dynamicClient, restMapper, namespaceObject(name, template, nil, nil), req.DryRun))
+	bootstrap(ctx, dynamicClient, restMapper, result, objects, true)
+	h.recordBootstrap(c, "namespace.template", result)
+	respondBootstrap(c, result, http.StatusOK)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Facets-cloud/kube-dash/internal/nstemplates"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestClassifyBootstrapError(t *testing.T) {
+	cases := map[string]string{
+		`admission webhook "validate.kyverno.svc" denied the request: label team is required`:                                            bootstrapReasonAdmission,
+		`pods "x" is forbidden: violates PodSecurity "restricted:latest"`:                                                                bootstrapReasonAdmission,
+		`resourcequotas "quota" is forbidden: User "dev" cannot patch resource "resourcequotas" in API group "" in the namespace "shop"`: bootstrapReasonForbidden,
+		`persistentvolumeclaims "data" is forbidden: exceeded quota: quota, requested: requests.storage=10Gi`:                            bootstrapReasonQuota,
+		`LimitRange "limits" is invalid: spec.limits[0].type: Required value`:                                                            bootstrapReasonInvalid,
+		`failed to resolve GVK to resource: no matches for kind "Policy" in version "kyverno.io/v1"`:                                     bootstrapReasonError,
+	}
+	for message, want := range cases {
+		if got := classifyBootstrapError(message); got != want {
+			t.Errorf("classifyBootstrapError(%q) = %s, want %s", message, got, want)
+		}
+	}
+}
+
+func TestNamespaceObject(t *testing.T) {
+	tmpl := &nstemplates.Template{
+		Name:        "team",
+		Labels:      map[string]string{"team": "payments", "tier": "standard"},
+		Annotations: map[string]string{"owner": "payments@example.com"},
+	}
+	ns := namespaceObject("shop", tmpl, map[string]string{"tier": "critical"}, nil)
+	if ns.GetName() != "shop" || ns.GetKind() != "Namespace" {
+		t.Fatalf("namespace = %v", ns.Object)
+	}
+	labels := ns.GetLabels()
+	if labels["team"] != "payments" || labels["tier"] != "critical" {
+		t.Errorf("labels = %v, want request labels to override the template's", labels)
+	}
+	if ns.GetAnnotations()[namespaceTemplateAnnotation] != "team" {
+		t.Errorf("annotations = %v, want the template recorded", ns.GetAnnotations())
+	}
+	if plain := namespaceObject("shop", nil, nil, nil); plain.GetAnnotations() != nil || plain.GetLabels() != nil {
+		t.Errorf("namespace without template = %v", plain.Object)
+	}
+}
+
+func TestBootstrapDryRunBeforeNamespaceExists(t *testing.T) {
+	quota := &unstructured.Unstructured{}
+	quota.SetAPIVersion("v1")
+	quota.SetKind("ResourceQuota")
+	quota.SetName("quota")
+
+	result := &NamespaceBootstrapResult{Namespace: "shop", DryRun: true}
+	bootstrap(context.Background(), nil, nil, result, []*unstructured.Unstructured{quota}, false)
+	if len(result.Results) != 1 || result.Results[0].Status != bootstrapSkipped || result.Applied != 0 || result.Failed != 0 {
+		t.Errorf("result = %+v, want the quota rendered but skipped", result)
+	}
+}
@@ -0,0 +1,212 @@
+package namespacegroups
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Facets-cloud/kube-dash/internal/apitokens"
+	"github.com/Facets-cloud/kube-dash/internal/namespacegroups"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NamespaceGroupsHandler manages saved namespace groups and resolves them on list requests
+type NamespaceGroupsHandler struct {
+	groups *namespacegroups.Store
+	logger *logger.Logger
+}
+
+// NewNamespaceGroupsHandler creates a new namespace groups handler
+func NewNamespaceGroupsHandler(groups *namespacegroups.Store, log *logger.Logger) *NamespaceGroupsHandler {
+	return &NamespaceGroupsHandler{
+		groups: groups,
+		logger: log,
+	}
+}
+
+func (h *NamespaceGroupsHandler) groupError(c *gin.Context, err error) {
+	if errors.Is(err, storage.ErrDocumentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "namespace group not found"})
+		return
+	}
+	h.logger.WithError(err).Error("Namespace group operation failed")
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+// load returns a group the caller may access. Groups owned by someone else are
+// hidden from callers authenticated with an API token.
+func (h *NamespaceGroupsHandler) load(c *gin.Context, id string) (*namespacegroups.Group, error) {
+	g, err := h.groups.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if owner, authenticated := apitokens.RequestOwner(c); authenticated && g.Owner != "" && g.Owner != owner {
+		return nil, storage.ErrDocumentNotFound
+	}
+	return g, nil
+}
+
+// ResolveNamespaceGroup expands the namespaceGroup query parameter of a request into the
+// namespaces parameter, so list handlers only deal with comma-separated namespaces
+func (h *NamespaceGroupsHandler) ResolveNamespaceGroup(c *gin.Context) {
+	id := c.Query("namespaceGroup")
+	if id == "" {
+		c.Next()
+		return
+	}
+	g, err := h.load(c, id)
+	if err != nil {
+		h.groupError(c, err)
+		c.Abort()
+		return
+	}
+	if config := c.Query("config"); config != "" && (g.ConfigID != config || g.Cluster != c.Query("cluster")) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace group belongs to a different cluster"})
+		c.Abort()
+		return
+	}
+	query := c.Request.URL.Query()
+	query.Del("namespaceGroup")
+	query.Del("namespace")
+	query.Set("namespaces", strings.Join(g.Namespaces, ","))
+	c.Request.URL.RawQuery = query.Encode()
+	c.Next()
+}
+
+// ListNamespaceGroups returns saved namespace groups
+// @Summary List namespace groups
+// @Description Lists saved namespace groups visible to the caller: its own and shared ones, optionally only those for a cluster. Pass a group's ID as the namespaceGroup parameter of any list endpoint to list across its namespaces.
+// @Tags Cluster
+// @Produce json
+// @Param owner query string false "Only the owner's and shared groups"
+// @Param config query string false "Kubeconfig ID"
+// @Param cluster query string false "Cluster name"
+// @Success 200 {array} namespacegroups.Group "Namespace groups"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/namespace-groups [get]
+func (h *NamespaceGroupsHandler) ListNamespaceGroups(c *gin.Context) {
+	owner, _ := apitokens.RequestOwner(c)
+	list, err := h.groups.List(namespacegroups.Filter{Owner: owner, ConfigID: c.Query("config"), Cluster: c.Query("cluster")})
+	if err != nil {
+		h.groupError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// GetNamespaceGroup returns a single namespace group
+// @Summary Get namespace group
+// @Description Returns a saved namespace group by ID
+// @Tags Cluster
+// @Produce json
+// @Param id path string true "Namespace group ID"
+// @Success 200 {object} namespacegroups.Group "Namespace group"
+// @Failure 404 {object} map[string]string "Namespace group not found"
+// @Security BearerAuth
+// @Router /api/v1/namespace-groups/{id} [get]
+func (h *NamespaceGroupsHandler) GetNamespaceGroup(c *gin.Context) {
+	g, err := h.load(c, c.Param("id"))
+	if err != nil {
+		h.groupError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, g)
+}
+
+// CreateNamespaceGroup saves a new namespace group
+// @Summary Create namespace group
+// @Description Saves a named set of namespaces of a cluster. Groups without an owner are shared.
+// @Tags Cluster
+// @Accept json
+// @Produce json
+// @Param group body namespacegroups.Group true "Namespace group"
+// @Success 201 {object} namespacegroups.Group "Created namespace group"
+// @Failure 400 {object} map[string]string "Bad request - invalid group"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/namespace-groups [post]
+func (h *NamespaceGroupsHandler) CreateNamespaceGroup(c *gin.Context) {
+	var g namespacegroups.Group
+	if err := c.ShouldBindJSON(&g); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	g.ID = ""
+	if owner, authenticated := apitokens.RequestOwner(c); authenticated {
+		g.Owner = owner
+	}
+	if err := g.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.groups.Save(&g); err != nil {
+		h.groupError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, g)
+}
+
+// UpdateNamespaceGroup replaces a namespace group
+// @Summary Update namespace group
+// @Description Replaces a namespace group, keeping its ID, owner and creation time
+// @Tags Cluster
+// @Accept json
+// @Produce json
+// @Param id path string true "Namespace group ID"
+// @Param group body namespacegroups.Group true "Namespace group"
+// @Success 200 {object} namespacegroups.Group "Updated namespace group"
+// @Failure 400 {object} map[string]string "Bad request - invalid group"
+// @Failure 404 {object} map[string]string "Namespace group not found"
+// @Security BearerAuth
+// @Router /api/v1/namespace-groups/{id} [put]
+func (h *NamespaceGroupsHandler) UpdateNamespaceGroup(c *gin.Context) {
+	existing, err := h.load(c, c.Param("id"))
+	if err != nil {
+		h.groupError(c, err)
+		return
+	}
+	var g namespacegroups.Group
+	if err := c.ShouldBindJSON(&g); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	g.ID = existing.ID
+	g.Owner = existing.Owner
+	g.CreatedAt = existing.CreatedAt
+	if err := g.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.groups.Save(&g); err != nil {
+		h.groupError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, g)
+}
+
+// DeleteNamespaceGroup deletes a namespace group
+// @Summary Delete namespace group
+// @Description Deletes a saved namespace group
+// @Tags Cluster
+// @Produce json
+// @Param id path string true "Namespace group ID"
+// @Success 200 {object} map[string]string "Namespace group deleted"
+// @Failure 404 {object} map[string]string "Namespace group not found"
+// @Security BearerAuth
+// @Router /api/v1/namespace-groups/{id} [delete]
+func (h *NamespaceGroupsHandler) DeleteNamespaceGroup(c *gin.Context) {
+	g, err := h.load(c, c.Param("id"))
+	if err != nil {
+		h.groupError(c, err)
+		return
+	}
+	if err := h.groups.Delete(g.ID); err != nil {
+		h.groupError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Namespace group deleted"})
+}
@@ -0,0 +1,367 @@
+package namespaceprefs
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/apitokens"
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/namespaceprefs"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Ways a namespace's access was determined
+const (
+	AccessMethodClusterWide = "cluster-wide" // the credentials may list pods in all namespaces
+	AccessMethodReview      = "ssar"         // a SelfSubjectAccessReview allowed listing pods
+	AccessMethodList        = "list"         // listing pods succeeded where access reviews failed
+)
+
+// Sources of the default namespace
+const (
+	DefaultSourcePreference = "preference"
+	DefaultSourceKubeconfig = "kubeconfig"
+	DefaultSourceFallback   = "fallback"
+)
+
+// probeConcurrency bounds the access reviews run at once
+const probeConcurrency = 10
+
+// NamespaceAccess is a namespace the credentials can work in
+type NamespaceAccess struct {
+	Name   string `json:"name"`
+	Method string `json:"method"`
+}
+
+// AccessibleNamespaces are the namespaces of a cluster the credentials can access and the
+// namespace the caller opens by default
+type AccessibleNamespaces struct {
+	Namespaces        []NamespaceAccess `json:"namespaces"`
+	ClusterWide       bool              `json:"clusterWide"`       // pods can be listed across all namespaces
+	CanListNamespaces bool              `json:"canListNamespaces"` // false when only candidate namespaces were probed
+	DefaultNamespace  string            `json:"defaultNamespace,omitempty"`
+	DefaultSource     string            `json:"defaultSource,omitempty"`
+}
+
+// PreferenceRequest is the body of a default namespace update
+type PreferenceRequest struct {
+	Namespace string `json:"namespace"`
+}
+
+// NamespacePreferencesHandler serves the namespaces a caller can access and their default namespace per cluster
+type NamespacePreferencesHandler struct {
+	prefs         *namespaceprefs.Store
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	logger        *logger.Logger
+}
+
+// NewNamespacePreferencesHandler creates a new namespace preferences handler
+func NewNamespacePreferencesHandler(prefs *namespaceprefs.Store, store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger) *NamespacePreferencesHandler {
+	return &NamespacePreferencesHandler{
+		prefs:         prefs,
+		store:         store,
+		clientFactory: clientFactory,
+		logger:        log,
+	}
+}
+
+// canListPods asks the API server whether the credentials may list pods in a namespace, or in
+// all namespaces when namespace is empty
+func canListPods(ctx context.Context, client kubernetes.Interface, namespace string) (bool, error) {
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{Verb: "list", Resource: "pods", Namespace: namespace},
+		},
+	}
+	result, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return result.Status.Allowed, nil
+}
+
+// probeNamespace reports whether pods can be listed in a namespace, falling back to listing them
+// when the API server does not answer access reviews
+func probeNamespace(ctx context.Context, client kubernetes.Interface, namespace string) (string, bool) {
+	allowed, err := canListPods(ctx, client, namespace)
+	if err == nil {
+		return AccessMethodReview, allowed
+	}
+	_, err = client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{Limit: 1})
+	return AccessMethodList, err == nil
+}
+
+// resolveAccess finds the namespaces the credentials can list pods in. Credentials that cannot
+// list namespaces only have the candidates probed, such as the kubeconfig context namespace.
+func resolveAccess(ctx context.Context, client kubernetes.Interface, candidates []string) (*AccessibleNamespaces, error) {
+	access := &AccessibleNamespaces{Namespaces: []NamespaceAccess{}}
+	clusterWide, err := canListPods(ctx, client, "")
+	access.ClusterWide = err == nil && clusterWide
+
+	var names []string
+	list, err := client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	switch {
+	case err == nil:
+		access.CanListNamespaces = true
+		for _, ns := range list.Items {
+			names = append(names, ns.Name)
+		}
+	case apierrors.IsForbidden(err):
+		seen := map[string]bool{}
+		for _, ns := range candidates {
+			if ns != "" && !seen[ns] && len(validation.IsDNS1123Label(ns)) == 0 {
+				seen[ns] = true
+				names = append(names, ns)
+			}
+		}
+	default:
+		return nil, err
+	}
+
+	if access.ClusterWide {
+		for _, name := range names {
+			access.Namespaces = append(access.Namespaces, NamespaceAccess{Name: name, Method: AccessMethodClusterWide})
+		}
+	} else {
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		semaphore := make(chan struct{}, probeConcurrency)
+		for _, name := range names {
+			wg.Add(1)
+			go func(name string) {
+				defer wg.Done()
+				semaphore <- struct{}{}
+				defer func() { <-semaphore }()
+				if method, ok := probeNamespace(ctx, client, name); ok {
+					mu.Lock()
+					access.Namespaces = append(access.Namespaces, NamespaceAccess{Name: name, Method: method})
+					mu.Unlock()
+				}
+			}(name)
+		}
+		wg.Wait()
+	}
+	sort.Slice(access.Namespaces, func(i, j int) bool { return access.Namespaces[i].Name < access.Namespaces[j].Name })
+	return access, nil
+}
+
+// contextNamespace returns the namespace set on the first kubeconfig context of a cluster
+func contextNamespace(config *api.Config, cluster string) string {
+	names := make([]string, 0, len(config.Contexts))
+	for name := range config.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if ctx := config.Contexts[name]; ctx.Cluster == cluster || (cluster == "" && name == config.CurrentContext) {
+			if ctx.Namespace != "" {
+				return ctx.Namespace
+			}
+		}
+	}
+	return ""
+}
+
+// chooseDefault picks the default namespace: the stored preference, then the kubeconfig context
+// namespace, then default or the first accessible namespace
+func chooseDefault(access *AccessibleNamespaces, preferred, fromKubeconfig string) (string, string) {
+	if preferred != "" {
+		return preferred, DefaultSourcePreference
+	}
+	if fromKubeconfig != "" {
+		return fromKubeconfig, DefaultSourceKubeconfig
+	}
+	for _, ns := range access.Namespaces {
+		if ns.Name == "default" {
+			return ns.Name, DefaultSourceFallback
+		}
+	}
+	if len(access.Namespaces) > 0 {
+		return access.Namespaces[0].Name, DefaultSourceFallback
+	}
+	return "", ""
+}
+
+// cluster validates the config and cluster query parameters
+func (h *NamespacePreferencesHandler) cluster(c *gin.Context) (string, string, *api.Config, bool) {
+	configID := c.Query("config")
+	if configID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "config parameter is required"})
+		return "", "", nil, false
+	}
+	config, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "kubeconfig not found"})
+		return "", "", nil, false
+	}
+	return configID, c.Query("cluster"), config, true
+}
+
+// preferred returns the caller's stored default namespace, if any
+func (h *NamespacePreferencesHandler) preferred(owner, configID, cluster string) string {
+	pref, err := h.prefs.Get(owner, configID, cluster)
+	if err != nil {
+		if !errors.Is(err, storage.ErrDocumentNotFound) {
+			h.logger.WithError(err).Warn("Failed to read namespace preference")
+		}
+		return ""
+	}
+	return pref.Namespace
+}
+
+// GetAccessibleNamespaces lists the namespaces the credentials can access
+// @Summary List accessible namespaces
+// @Description Lists only the namespaces of a cluster in which the kubeconfig credentials may list pods, so users with namespace-scoped roles are not shown namespaces that fail with permission errors. Each namespace is checked with a SelfSubjectAccessReview, or with a pod list where access reviews fail; credentials that may list pods cluster-wide skip the checks. Credentials that cannot list namespaces have only candidate namespaces checked: the kubeconfig context namespace, the caller's default namespace and the candidates parameter. The response also carries the caller's default namespace.
+// @Tags Cluster
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param owner query string false "Caller whose default namespace is returned; API token callers are identified by their token"
+// @Param candidates query string false "Comma-separated namespaces to check when namespaces cannot be listed"
+// @Success 200 {object} AccessibleNamespaces "Accessible namespaces"
+// @Failure 400 {object} map[string]string "Bad request - missing parameters"
+// @Failure 404 {object} map[string]string "Kubeconfig not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/accessible-namespaces [get]
+func (h *NamespacePreferencesHandler) GetAccessibleNamespaces(c *gin.Context) {
+	configID, cluster, config, ok := h.cluster(c)
+	if !ok {
+		return
+	}
+	client, err := h.clientFactory.GetClientForConfig(config, cluster)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for accessible namespaces")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	owner, _ := apitokens.RequestOwner(c)
+	preferred := h.preferred(owner, configID, cluster)
+	fromKubeconfig := contextNamespace(config, cluster)
+	candidates := []string{fromKubeconfig, preferred}
+	if raw := c.Query("candidates"); raw != "" {
+		for _, ns := range strings.Split(raw, ",") {
+			candidates = append(candidates, strings.TrimSpace(ns))
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+	access, err := resolveAccess(ctx, client, candidates)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to resolve accessible namespaces")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	access.DefaultNamespace, access.DefaultSource = chooseDefault(access, preferred, fromKubeconfig)
+	c.JSON(http.StatusOK, access)
+}
+
+// GetNamespacePreferences lists the caller's default namespaces
+// @Summary List default namespaces
+// @Description Lists the caller's default namespace per cluster, optionally only for one kubeconfig
+// @Tags Cluster
+// @Produce json
+// @Param config query string false "Kubeconfig ID"
+// @Param owner query string false "Caller whose preferences are listed; API token callers are identified by their token"
+// @Success 200 {array} namespaceprefs.Preference "Default namespaces"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/namespace-preferences [get]
+func (h *NamespacePreferencesHandler) GetNamespacePreferences(c *gin.Context) {
+	owner, _ := apitokens.RequestOwner(c)
+	prefs, err := h.prefs.List(owner, c.Query("config"))
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list namespace preferences")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, prefs)
+}
+
+// SetNamespacePreference stores the caller's default namespace on a cluster
+// @Summary Set default namespace
+// @Description Stores the namespace the caller opens by default on a cluster, replacing any previous default
+// @Tags Cluster
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param owner query string false "Caller whose default is set; API token callers are identified by their token"
+// @Param body body PreferenceRequest true "Default namespace"
+// @Success 200 {object} namespaceprefs.Preference "Stored default namespace"
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
+// @Failure 404 {object} map[string]string "Kubeconfig not found"
+// @Security BearerAuth
+// @Router /api/v1/namespace-preferences [put]
+func (h *NamespacePreferencesHandler) SetNamespacePreference(c *gin.Context) {
+	configID, cluster, _, ok := h.cluster(c)
+	if !ok {
+		return
+	}
+	var req PreferenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	owner, _ := apitokens.RequestOwner(c)
+	pref := &namespaceprefs.Preference{Owner: owner, ConfigID: configID, Cluster: cluster, Namespace: req.Namespace}
+	if err := pref.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.prefs.Set(pref); err != nil {
+		h.logger.WithError(err).Error("Failed to store namespace preference")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, pref)
+}
+
+// DeleteNamespacePreference removes the caller's default namespace on a cluster
+// @Summary Clear default namespace
+// @Description Removes the caller's default namespace on a cluster, so the kubeconfig context namespace applies again
+// @Tags Cluster
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param owner query string false "Caller whose default is cleared; API token callers are identified by their token"
+// @Success 204 "Default namespace cleared"
+// @Failure 400 {object} map[string]string "Bad request - missing parameters"
+// @Failure 404 {object} map[string]string "No default namespace set"
+// @Security BearerAuth
+// @Router /api/v1/namespace-preferences [delete]
+func (h *NamespacePreferencesHandler) DeleteNamespacePreference(c *gin.Context) {
+	configID := c.Query("config")
+	if configID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "config parameter is required"})
+		return
+	}
+	owner, _ := apitokens.RequestOwner(c)
+	if err := h.prefs.Delete(owner, configID, c.Query("cluster")); err != nil {
+		if errors.Is(err, storage.ErrDocumentNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "no default namespace set"})
+			return
+		}
+		h.logger.WithError(err).Error("Failed to delete namespace preference")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,125 @@
+package namespaceprefs
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// allowPodsIn answers pod list access reviews, allowing only the given namespaces
+func allowPodsIn(client *fake.Clientset, namespaces ...string) {
+	allowed := map[string]bool{}
+	for _, ns := range namespaces {
+		allowed[ns] = true
+	}
+	client.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		review.Status.Allowed = allowed[review.Spec.ResourceAttributes.Namespace]
+		return true, review, nil
+	})
+}
+
+func names(access *AccessibleNamespaces) []string {
+	out := []string{}
+	for _, ns := range access.Namespaces {
+		out = append(out, ns.Name)
+	}
+	return out
+}
+
+func namespace(name string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+}
+
+func TestResolveAccessProbesListedNamespaces(t *testing.T) {
+	client := fake.NewSimpleClientset(namespace("shop"), namespace("payments"), namespace("kube-system"))
+	allowPodsIn(client, "shop", "payments")
+
+	access, err := resolveAccess(context.Background(), client, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !access.CanListNamespaces || access.ClusterWide {
+		t.Errorf("access = %+v, want namespace listing without cluster-wide access", access)
+	}
+	if want := []string{"payments", "shop"}; !reflect.DeepEqual(names(access), want) {
+		t.Errorf("namespaces = %v, want %v", names(access), want)
+	}
+}
+
+func TestResolveAccessFallsBackToCandidates(t *testing.T) {
+	client := fake.NewSimpleClientset(namespace("shop"))
+	client.PrependReactor("list", "namespaces", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "namespaces"}, "", nil)
+	})
+	client.PrependReactor("create", "selfsubjectaccessreviews", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewServiceUnavailable("authorization unavailable")
+	})
+	client.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetNamespace() != "shop" {
+			return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", nil)
+		}
+		return true, &corev1.PodList{}, nil
+	})
+
+	access, err := resolveAccess(context.Background(), client, []string{"shop", "", "shop", "billing", "Not_Valid"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []NamespaceAccess{{Name: "shop", Method: AccessMethodList}}
+	if access.CanListNamespaces || !reflect.DeepEqual(access.Namespaces, want) {
+		t.Errorf("access = %+v, want only shop via list", access)
+	}
+}
+
+func TestResolveAccessClusterWide(t *testing.T) {
+	client := fake.NewSimpleClientset(namespace("default"), namespace("shop"))
+	allowPodsIn(client, "")
+
+	access, err := resolveAccess(context.Background(), client, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !access.ClusterWide || len(access.Namespaces) != 2 || access.Namespaces[0].Method != AccessMethodClusterWide {
+		t.Errorf("access = %+v, want every namespace cluster-wide", access)
+	}
+}
+
+func TestChooseDefault(t *testing.T) {
+	access := &AccessibleNamespaces{Namespaces: []NamespaceAccess{{Name: "default"}, {Name: "shop"}}}
+	cases := []struct {
+		preferred, fromKubeconfig string
+		want, source              string
+	}{
+		{"shop", "payments", "shop", DefaultSourcePreference},
+		{"", "payments", "payments", DefaultSourceKubeconfig},
+		{"", "", "default", DefaultSourceFallback},
+	}
+	for _, tc := range cases {
+		if got, source := chooseDefault(access, tc.preferred, tc.fromKubeconfig); got != tc.want || source != tc.source {
+			t.Errorf("chooseDefault(%q, %q) = %q, %q; want %q, %q", tc.preferred, tc.fromKubeconfig, got, source, tc.want, tc.source)
+		}
+	}
+	if got, _ := chooseDefault(&AccessibleNamespaces{Namespaces: []NamespaceAccess{{Name: "shop"}}}, "", ""); got != "shop" {
+		t.Errorf("chooseDefault() = %q, want the first accessible namespace", got)
+	}
+
+	config := &api.Config{Contexts: map[string]*api.Context{
+		"b": {Cluster: "prod", Namespace: "payments"},
+		"a": {Cluster: "prod", Namespace: "shop"},
+		"c": {Cluster: "staging", Namespace: "qa"},
+	}}
+	if got := contextNamespace(config, "prod"); got != "shop" {
+		t.Errorf("contextNamespace() = %q, want shop", got)
+	}
+}
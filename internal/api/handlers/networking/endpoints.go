@@ -1,8 +1,10 @@
 package networking
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/Facets-cloud/kube-dash/internal/api/transformers"
 	"github.com/Facets-cloud/kube-dash/internal/api/types"
@@ -13,6 +15,7 @@ import (
 	"github.com/Facets-cloud/kube-dash/pkg/logger"
 
 	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -42,7 +45,7 @@ func NewEndpointsHandler(store *storage.KubeConfigStore, clientFactory *k8s.Clie
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *EndpointsHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *EndpointsHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -79,7 +82,8 @@ func (h *EndpointsHandler) GetEndpointsSSE(c *gin.Context) {
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
 
-	namespace := c.Query("namespace")
+	namespaces := utils.RequestedNamespaces(c)
+	namespace := strings.Join(namespaces, ",")
 	h.tracingHelper.AddResourceAttributes(clientSpan, namespace, "endpoints", 0)
 
 	// Function to fetch endpoints data
@@ -88,7 +92,14 @@ func (h *EndpointsHandler) GetEndpointsSSE(c *gin.Context) {
 		_, apiSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "endpoints", namespace)
 		defer apiSpan.End()
 
-		endpointList, err := client.CoreV1().Endpoints(namespace).List(c.Request.Context(), metav1.ListOptions{})
+		items, err := utils.ListInNamespaces(c.Request.Context(), namespaces, func(ctx context.Context, namespace string) ([]corev1.Endpoints, error) {
+			list, err := client.CoreV1().Endpoints(namespace).List(ctx, utils.ListOptions(c))
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		endpointList := &corev1.EndpointsList{Items: items}
 		if err != nil {
 			h.tracingHelper.RecordError(apiSpan, err, "Failed to list endpoints")
 			return nil, err
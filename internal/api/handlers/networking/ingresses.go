@@ -1,8 +1,10 @@
 package networking
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/Facets-cloud/kube-dash/internal/api/transformers"
 	"github.com/Facets-cloud/kube-dash/internal/api/types"
@@ -13,6 +15,7 @@ import (
 	"github.com/Facets-cloud/kube-dash/pkg/logger"
 
 	"github.com/gin-gonic/gin"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -42,7 +45,7 @@ func NewIngressesHandler(store *storage.KubeConfigStore, clientFactory *k8s.Clie
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *IngressesHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *IngressesHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -72,6 +75,12 @@ func (h *IngressesHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clien
 // @Param config query string true "Kubernetes config ID"
 // @Param cluster query string false "Cluster name (for multi-cluster configs)"
 // @Param namespace query string false "Namespace to filter ingresses (empty for all namespaces)"
+// @Param namespaces query string false "Comma-separated namespaces to list across, instead of namespace"
+// @Param namespaceGroup query string false "ID of a saved namespace group to list across"
+// @Param fields query string false "Comma-separated response fields to return, e.g. name,status,restarts; name, namespace and uid are always included"
+// @Param labelSelector query string false "Kubernetes label selector, e.g. app=web,tier!=cache"
+// @Param sort query string false "Response field to sort by, e.g. name or -restarts for descending"
+// @Param view query string false "ID of a saved view supplying namespaces, labelSelector, sort and fields"
 // @Success 200 {array} types.IngressListResponse "Stream of ingress data"
 // @Failure 400 {object} map[string]string "Bad request - invalid parameters"
 // @Failure 403 {object} map[string]string "Forbidden - insufficient permissions"
@@ -93,7 +102,8 @@ func (h *IngressesHandler) GetIngressesSSE(c *gin.Context) {
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
 
-	namespace := c.Query("namespace")
+	namespaces := utils.RequestedNamespaces(c)
+	namespace := strings.Join(namespaces, ",")
 	h.tracingHelper.AddResourceAttributes(clientSpan, namespace, "ingresses", 0)
 
 	// Function to fetch ingresses data
@@ -102,7 +112,14 @@ func (h *IngressesHandler) GetIngressesSSE(c *gin.Context) {
 		_, apiSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "ingresses", namespace)
 		defer apiSpan.End()
 
-		ingressList, err := client.NetworkingV1().Ingresses(namespace).List(c.Request.Context(), metav1.ListOptions{})
+		items, err := utils.ListInNamespaces(c.Request.Context(), namespaces, func(ctx context.Context, namespace string) ([]networkingv1.Ingress, error) {
+			list, err := client.NetworkingV1().Ingresses(namespace).List(ctx, utils.ListOptions(c))
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		ingressList := &networkingv1.IngressList{Items: items}
 		if err != nil {
 			h.tracingHelper.RecordError(apiSpan, err, "Failed to list ingresses")
 			return nil, err
@@ -201,7 +218,7 @@ func (h *IngressesHandler) GetIngress(c *gin.Context) {
 	// Check if this is an SSE request (EventSource expects SSE format)
 	acceptHeader := c.GetHeader("Accept")
 	if acceptHeader == "text/event-stream" {
-		h.sseHandler.SendSSEResponse(c, ingress)
+		h.sseHandler.SendSSEDetailResponse(c, ingress, ingress, client.NetworkingV1().Ingresses(namespace).Watch)
 	} else {
 		c.JSON(http.StatusOK, ingress)
 	}
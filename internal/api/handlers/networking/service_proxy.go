@@ -0,0 +1,272 @@
+package networking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/apitokens"
+	"github.com/Facets-cloud/kube-dash/internal/audit"
+	"github.com/Facets-cloud/kube-dash/internal/config"
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// hopHeaders are connection-level headers that a proxy must not forward
+var hopHeaders = []string{"Connection", "Keep-Alive", "Proxy-Authenticate", "Proxy-Authorization", "Te", "Trailer", "Transfer-Encoding", "Upgrade"}
+
+// strippedRequestHeaders carry kube-dash's own credentials or would change who the API server
+// acts as, so they never reach the service
+var strippedRequestHeaders = []string{"Authorization", "Cookie"}
+
+// ServiceProxyHandler proxies HTTP requests to allowlisted in-cluster services through the API
+// server service proxy, so admin UIs such as Alertmanager or Kiali are reachable without an ingress
+type ServiceProxyHandler struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	auditor       *audit.Recorder
+	logger        *logger.Logger
+	config        *config.ServiceProxyConfig
+}
+
+// NewServiceProxyHandler creates a new service proxy handler
+func NewServiceProxyHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, auditor *audit.Recorder, log *logger.Logger, cfg *config.ServiceProxyConfig) *ServiceProxyHandler {
+	return &ServiceProxyHandler{
+		store:         store,
+		clientFactory: clientFactory,
+		auditor:       auditor,
+		logger:        log,
+		config:        cfg,
+	}
+}
+
+// serviceAllowed reports whether an allowlist entry of the form namespace/service:port matches a
+// target. Any part may be *, and an entry without a port matches every port.
+func serviceAllowed(allowlist []string, namespace, service, port string) bool {
+	for _, entry := range allowlist {
+		ref, entryPort, hasPort := strings.Cut(strings.TrimSpace(entry), ":")
+		entryNamespace, entryService, ok := strings.Cut(ref, "/")
+		if !ok {
+			continue
+		}
+		if !matchPart(entryNamespace, namespace) || !matchPart(entryService, service) {
+			continue
+		}
+		if !hasPort || matchPart(entryPort, port) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchPart(pattern, value string) bool {
+	return pattern == "*" || pattern == value
+}
+
+// proxyPath validates the path of a proxied request. Dot segments are rejected: the API server
+// cleans the URL, so they would leave the service proxy for other API paths.
+func proxyPath(path string) (string, error) {
+	for _, segment := range strings.Split(path, "/") {
+		if segment == ".." || segment == "." {
+			return "", fmt.Errorf("path must not contain . or .. segments")
+		}
+	}
+	return strings.TrimPrefix(path, "/"), nil
+}
+
+// proxyQuery is the query string forwarded to the service, without kube-dash's own parameters
+func proxyQuery(values url.Values) string {
+	forwarded := url.Values{}
+	for key, value := range values {
+		if key == "config" || key == "cluster" {
+			continue
+		}
+		forwarded[key] = value
+	}
+	return forwarded.Encode()
+}
+
+// copyHeaders copies headers except hop-by-hop headers and those listed in skip
+func copyHeaders(dst, src http.Header, skip []string) {
+	for key, values := range src {
+		if headerIn(key, hopHeaders) || headerIn(key, skip) || strings.HasPrefix(http.CanonicalHeaderKey(key), "Impersonate-") {
+			continue
+		}
+		for _, value := range values {
+			dst.Add(key, value)
+		}
+	}
+}
+
+func headerIn(key string, headers []string) bool {
+	for _, header := range headers {
+		if strings.EqualFold(key, header) {
+			return true
+		}
+	}
+	return false
+}
+
+// getClient gets the Kubernetes client for the config and cluster query parameters
+func (h *ServiceProxyHandler) getClient(c *gin.Context) (kubernetes.Interface, error) {
+	configID := c.Query("config")
+	if configID == "" {
+		return nil, fmt.Errorf("config parameter is required")
+	}
+	config, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, fmt.Errorf("config not found: %w", err)
+	}
+	client, err := h.clientFactory.GetClientForConfig(config, c.Query("cluster"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Kubernetes client: %w", err)
+	}
+	return client, nil
+}
+
+// record adds a proxied request to the audit trail
+func (h *ServiceProxyHandler) record(c *gin.Context, outcome, reason string, status int) {
+	event := audit.Event{
+		Action:     "service.proxy",
+		Outcome:    outcome,
+		Reason:     reason,
+		RemoteAddr: c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+		ConfigID:   c.Query("config"),
+		Cluster:    c.Query("cluster"),
+		Namespace:  c.Param("namespace"),
+		Resource:   "Service/" + c.Param("name"),
+		Details: map[string]string{
+			"method": c.Request.Method,
+			"port":   c.Param("port"),
+			"path":   c.Param("path"),
+			"actor":  apitokens.Actor(c),
+		},
+	}
+	if status != 0 {
+		event.Details["statusCode"] = fmt.Sprint(status)
+	}
+	h.auditor.Record(event)
+}
+
+// ProxyService forwards an HTTP request to a service port through the API server
+// @Summary Proxy request to a service
+// @Description Forwards the request method, path, query, headers and body to an in-cluster service through the API server service proxy and streams back its status, headers and body, so admin UIs of tools such as Alertmanager or Kiali are reachable without an ingress. Only services on the SERVICE_PROXY_ALLOWED_SERVICES allowlist (namespace/service:port entries, * as wildcard) can be reached, and only when ENABLE_SERVICE_PROXY is set. kube-dash credentials, cookies and impersonation headers are not forwarded, and the config and cluster query parameters are removed. The kubeconfig credentials need get on services/proxy. Requests with a method other than GET, HEAD or OPTIONS and denied requests are audited.
+// @Tags Networking
+// @Accept */*
+// @Produce */*
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name for multi-cluster setups"
+// @Param namespace path string true "Service namespace"
+// @Param name path string true "Service name"
+// @Param port path string true "Service port name or number"
+// @Param path path string true "Path on the service"
+// @Success 200 {string} string "Response of the service"
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
+// @Failure 403 {object} map[string]string "Service proxy disabled or service not allowlisted"
+// @Failure 413 {object} map[string]string "Request body too large"
+// @Failure 502 {object} map[string]string "Service unreachable"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/services/{namespace}/{name}/proxy/{port}/{path} [get]
+// @Router /api/v1/services/{namespace}/{name}/proxy/{port}/{path} [post]
+// @Router /api/v1/services/{namespace}/{name}/proxy/{port}/{path} [put]
+// @Router /api/v1/services/{namespace}/{name}/proxy/{port}/{path} [patch]
+// @Router /api/v1/services/{namespace}/{name}/proxy/{port}/{path} [delete]
+func (h *ServiceProxyHandler) ProxyService(c *gin.Context) {
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	port := c.Param("port")
+
+	if !h.config.Enabled {
+		c.JSON(http.StatusForbidden, gin.H{"error": "service proxy is disabled; set ENABLE_SERVICE_PROXY=true to enable it"})
+		return
+	}
+	if !serviceAllowed(h.config.AllowedServices, namespace, name, port) {
+		h.record(c, audit.OutcomeDenied, "service not allowlisted", 0)
+		c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("service %s/%s:%s is not in SERVICE_PROXY_ALLOWED_SERVICES", namespace, name, port)})
+		return
+	}
+	path, err := proxyPath(c.Param("path"))
+	if err != nil {
+		h.record(c, audit.OutcomeDenied, err.Error(), 0)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	client, err := h.getClient(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for service proxy")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	restClient, ok := client.CoreV1().RESTClient().(*rest.RESTClient)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "service proxy needs a REST client"})
+		return
+	}
+
+	target := restClient.Get().
+		Namespace(namespace).
+		Resource("services").
+		Name(name + ":" + port).
+		SubResource("proxy").
+		Suffix(path).
+		URL()
+	// The request builder cleans the path, which drops the trailing slash many UIs rely on
+	if strings.HasSuffix(path, "/") && !strings.HasSuffix(target.Path, "/") {
+		target.Path += "/"
+	}
+	target.RawQuery = proxyQuery(c.Request.URL.Query())
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.config.TimeoutSeconds)*time.Second)
+	defer cancel()
+	var body io.Reader
+	if c.Request.Body != nil && c.Request.ContentLength != 0 {
+		body = http.MaxBytesReader(c.Writer, c.Request.Body, int64(h.config.MaxBodyBytes))
+	}
+	req, err := http.NewRequestWithContext(ctx, c.Request.Method, target.String(), body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	copyHeaders(req.Header, c.Request.Header, strippedRequestHeaders)
+	req.ContentLength = c.Request.ContentLength
+
+	audited := c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead && c.Request.Method != http.MethodOptions
+	resp, err := restClient.Client.Do(req)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("request body exceeds %d bytes", h.config.MaxBodyBytes)})
+			return
+		}
+		h.logger.WithError(err).WithField("service", namespace+"/"+name+":"+port).Error("Service proxy request failed")
+		if audited {
+			h.record(c, audit.OutcomeFailure, err.Error(), 0)
+		}
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+	defer resp.Body.Close()
+	if audited {
+		h.record(c, audit.OutcomeSuccess, "", resp.StatusCode)
+	}
+
+	// Cookies set by the service would be scoped to kube-dash's origin
+	copyHeaders(c.Writer.Header(), resp.Header, []string{"Set-Cookie"})
+	c.Status(resp.StatusCode)
+	if _, err := io.Copy(c.Writer, resp.Body); err != nil {
+		h.logger.WithError(err).WithField("service", namespace+"/"+name+":"+port).Debug("Service proxy response interrupted")
+	}
+}
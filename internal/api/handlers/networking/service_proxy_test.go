@@ -0,0 +1,60 @@
+package networking
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestServiceAllowed(t *testing.T) {
+	allowlist := []string{"monitoring/alertmanager:9093", "istio-system/kiali", "tools/*:http"}
+	cases := []struct {
+		namespace, service, port string
+		want                     bool
+	}{
+		{"monitoring", "alertmanager", "9093", true},
+		{"monitoring", "alertmanager", "9094", false},
+		{"monitoring", "prometheus", "9090", false},
+		{"istio-system", "kiali", "20001", true},
+		{"tools", "grafana", "http", true},
+		{"tools", "grafana", "https", false},
+		{"default", "kiali", "20001", false},
+	}
+	for _, tc := range cases {
+		if got := serviceAllowed(allowlist, tc.namespace, tc.service, tc.port); got != tc.want {
+			t.Errorf("serviceAllowed(%s/%s:%s) = %v, want %v", tc.namespace, tc.service, tc.port, got, tc.want)
+		}
+	}
+	if serviceAllowed(nil, "monitoring", "alertmanager", "9093") {
+		t.Error("serviceAllowed() allowed a service with an empty allowlist")
+	}
+}
+
+func TestProxyPath(t *testing.T) {
+	if path, err := proxyPath("/api/v2/alerts/"); err != nil || path != "api/v2/alerts/" {
+		t.Errorf("proxyPath() = %q, %v", path, err)
+	}
+	for _, path := range []string{"/../../secrets", "/ui/./x", "/a/.."} {
+		if _, err := proxyPath(path); err == nil {
+			t.Errorf("proxyPath(%q) succeeded, want an error", path)
+		}
+	}
+}
+
+func TestProxyHeadersAndQuery(t *testing.T) {
+	if got := proxyQuery(url.Values{"config": {"c1"}, "cluster": {"prod"}, "filter": {"a=b"}}); got != "filter=a%3Db" {
+		t.Errorf("proxyQuery() = %q", got)
+	}
+
+	src := http.Header{}
+	src.Set("Authorization", "Bearer kube-dash")
+	src.Set("Cookie", "session=1")
+	src.Set("Impersonate-User", "admin")
+	src.Set("Connection", "keep-alive")
+	src.Set("Accept", "application/json")
+	dst := http.Header{}
+	copyHeaders(dst, src, strippedRequestHeaders)
+	if len(dst) != 1 || dst.Get("Accept") != "application/json" {
+		t.Errorf("forwarded headers = %v, want only Accept", dst)
+	}
+}
@@ -1,8 +1,10 @@
 package networking
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/Facets-cloud/kube-dash/internal/api/transformers"
 	"github.com/Facets-cloud/kube-dash/internal/api/types"
@@ -13,6 +15,7 @@ import (
 	"github.com/Facets-cloud/kube-dash/pkg/logger"
 
 	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -42,7 +45,7 @@ func NewServicesHandler(store *storage.KubeConfigStore, clientFactory *k8s.Clien
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *ServicesHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *ServicesHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -72,6 +75,12 @@ func (h *ServicesHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Client
 // @Param config query string true "Kubernetes configuration ID"
 // @Param cluster query string false "Cluster name"
 // @Param namespace query string false "Namespace filter"
+// @Param namespaces query string false "Comma-separated namespaces to list across, instead of namespace"
+// @Param namespaceGroup query string false "ID of a saved namespace group to list across"
+// @Param fields query string false "Comma-separated response fields to return, e.g. name,status,restarts; name, namespace and uid are always included"
+// @Param labelSelector query string false "Kubernetes label selector, e.g. app=web,tier!=cache"
+// @Param sort query string false "Response field to sort by, e.g. name or -restarts for descending"
+// @Param view query string false "ID of a saved view supplying namespaces, labelSelector, sort and fields"
 // @Success 200 {array} types.ServiceListResponse "Stream of service data"
 // @Failure 400 {object} map[string]string "Bad request"
 // @Failure 500 {object} map[string]string "Internal server error"
@@ -91,7 +100,8 @@ func (h *ServicesHandler) GetServicesSSE(c *gin.Context) {
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed")
 
-	namespace := c.Query("namespace")
+	namespaces := utils.RequestedNamespaces(c)
+	namespace := strings.Join(namespaces, ",")
 	h.tracingHelper.AddResourceAttributes(clientSpan, namespace, "services", 0)
 
 	// Function to fetch services data
@@ -100,7 +110,14 @@ func (h *ServicesHandler) GetServicesSSE(c *gin.Context) {
 		_, apiSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "services", namespace)
 		defer apiSpan.End()
 
-		serviceList, err := client.CoreV1().Services(namespace).List(c.Request.Context(), metav1.ListOptions{})
+		items, err := utils.ListInNamespaces(c.Request.Context(), namespaces, func(ctx context.Context, namespace string) ([]corev1.Service, error) {
+			list, err := client.CoreV1().Services(namespace).List(ctx, utils.ListOptions(c))
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		serviceList := &corev1.ServiceList{Items: items}
 		if err != nil {
 			h.tracingHelper.RecordError(apiSpan, err, "Failed to list services")
 			return nil, err
@@ -143,7 +160,7 @@ func (h *ServicesHandler) GetServicesSSE(c *gin.Context) {
 		h.sseHandler.SendSSEResponseWithUpdates(c, initialData, fetchServices)
 	} else {
 		// For non-SSE requests, return JSON
-		c.JSON(http.StatusOK, initialData)
+		h.sseHandler.SendJSON(c, initialData)
 	}
 }
 
@@ -195,7 +212,7 @@ func (h *ServicesHandler) GetService(c *gin.Context) {
 	apiSpan.End()
 
 	// Always send SSE format for detail endpoints since they're used by EventSource
-	h.sseHandler.SendSSEResponse(c, service)
+	h.sseHandler.SendSSEDetailResponse(c, service, service, client.CoreV1().Services(namespace).Watch)
 	h.tracingHelper.RecordSuccess(span, "Service request completed")
 }
 
@@ -242,7 +259,7 @@ func (h *ServicesHandler) GetServiceByName(c *gin.Context) {
 	apiSpan.End()
 
 	// Always send SSE format for detail endpoints since they're used by EventSource
-	h.sseHandler.SendSSEResponse(c, service)
+	h.sseHandler.SendSSEDetailResponse(c, service, service, client.CoreV1().Services(namespace).Watch)
 	h.tracingHelper.RecordSuccess(span, "Service by name request completed")
 }
 
@@ -0,0 +1,247 @@
+package notifications
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/notifications"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationsHandler manages notification rules and exposes delivery history
+type NotificationsHandler struct {
+	engine *notifications.Engine
+	logger *logger.Logger
+}
+
+// MuteRequest mutes a rule for a duration; a zero duration unmutes it
+type MuteRequest struct {
+	DurationMinutes int `json:"durationMinutes"`
+}
+
+// NewNotificationsHandler creates a new notifications handler
+func NewNotificationsHandler(engine *notifications.Engine, log *logger.Logger) *NotificationsHandler {
+	return &NotificationsHandler{
+		engine: engine,
+		logger: log,
+	}
+}
+
+func (h *NotificationsHandler) ruleError(c *gin.Context, err error) {
+	if errors.Is(err, storage.ErrDocumentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "notification rule not found"})
+		return
+	}
+	h.logger.WithError(err).Error("Notification rule operation failed")
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+// ListRules returns all notification rules
+// @Summary List notification rules
+// @Description Lists rules that route cluster events, Prometheus alerts and metric thresholds to Slack, webhooks or email
+// @Tags Notifications
+// @Produce json
+// @Success 200 {array} notifications.Rule "Notification rules"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/notifications/rules [get]
+func (h *NotificationsHandler) ListRules(c *gin.Context) {
+	rules, err := h.engine.ListRules()
+	if err != nil {
+		h.ruleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rules)
+}
+
+// GetRule returns a single notification rule
+// @Summary Get notification rule
+// @Description Returns a notification rule by ID
+// @Tags Notifications
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Success 200 {object} notifications.Rule "Notification rule"
+// @Failure 404 {object} map[string]string "Rule not found"
+// @Security BearerAuth
+// @Router /api/v1/notifications/rules/{id} [get]
+func (h *NotificationsHandler) GetRule(c *gin.Context) {
+	rule, err := h.engine.GetRule(c.Param("id"))
+	if err != nil {
+		h.ruleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// CreateRule creates a notification rule
+// @Summary Create notification rule
+// @Description Creates a rule matching events (namespace, kind, reason, type), firing Prometheus alerts or a PromQL threshold and delivering to channels
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Param rule body notifications.Rule true "Notification rule"
+// @Success 201 {object} notifications.Rule "Created rule"
+// @Failure 400 {object} map[string]string "Bad request - invalid rule"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/notifications/rules [post]
+func (h *NotificationsHandler) CreateRule(c *gin.Context) {
+	var rule notifications.Rule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	rule.ID = ""
+	if err := rule.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.engine.SaveRule(&rule); err != nil {
+		h.ruleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, rule)
+}
+
+// UpdateRule replaces a notification rule
+// @Summary Update notification rule
+// @Description Replaces a notification rule, keeping its ID and creation time
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Param rule body notifications.Rule true "Notification rule"
+// @Success 200 {object} notifications.Rule "Updated rule"
+// @Failure 400 {object} map[string]string "Bad request - invalid rule"
+// @Failure 404 {object} map[string]string "Rule not found"
+// @Security BearerAuth
+// @Router /api/v1/notifications/rules/{id} [put]
+func (h *NotificationsHandler) UpdateRule(c *gin.Context) {
+	existing, err := h.engine.GetRule(c.Param("id"))
+	if err != nil {
+		h.ruleError(c, err)
+		return
+	}
+	var rule notifications.Rule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	rule.ID = existing.ID
+	rule.CreatedAt = existing.CreatedAt
+	if err := rule.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.engine.SaveRule(&rule); err != nil {
+		h.ruleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteRule deletes a notification rule
+// @Summary Delete notification rule
+// @Description Deletes a notification rule and stops evaluating it
+// @Tags Notifications
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Success 200 {object} map[string]string "Rule deleted"
+// @Failure 404 {object} map[string]string "Rule not found"
+// @Security BearerAuth
+// @Router /api/v1/notifications/rules/{id} [delete]
+func (h *NotificationsHandler) DeleteRule(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := h.engine.GetRule(id); err != nil {
+		h.ruleError(c, err)
+		return
+	}
+	if err := h.engine.DeleteRule(id); err != nil {
+		h.ruleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Notification rule deleted"})
+}
+
+// MuteRule mutes or unmutes a notification rule
+// @Summary Mute notification rule
+// @Description Suppresses deliveries for a rule for the given number of minutes; 0 unmutes the rule
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Param request body MuteRequest true "Mute duration"
+// @Success 200 {object} notifications.Rule "Updated rule"
+// @Failure 400 {object} map[string]string "Bad request - invalid duration"
+// @Failure 404 {object} map[string]string "Rule not found"
+// @Security BearerAuth
+// @Router /api/v1/notifications/rules/{id}/mute [post]
+func (h *NotificationsHandler) MuteRule(c *gin.Context) {
+	var req MuteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if req.DurationMinutes < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "durationMinutes must not be negative"})
+		return
+	}
+	var until time.Time
+	if req.DurationMinutes > 0 {
+		until = time.Now().Add(time.Duration(req.DurationMinutes) * time.Minute)
+	}
+	rule, err := h.engine.MuteRule(c.Param("id"), until)
+	if err != nil {
+		h.ruleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// TestRule sends a test notification through a rule's channels
+// @Summary Test notification rule
+// @Description Sends a synthetic notification to every channel of the rule and returns the delivery results
+// @Tags Notifications
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Success 200 {array} notifications.Delivery "Delivery results"
+// @Failure 404 {object} map[string]string "Rule not found"
+// @Security BearerAuth
+// @Router /api/v1/notifications/rules/{id}/test [post]
+func (h *NotificationsHandler) TestRule(c *gin.Context) {
+	rule, err := h.engine.GetRule(c.Param("id"))
+	if err != nil {
+		h.ruleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, h.engine.TestRule(c.Request.Context(), rule))
+}
+
+// ListDeliveries returns recent delivery history
+// @Summary List notification deliveries
+// @Description Returns recent notification deliveries, newest first
+// @Tags Notifications
+// @Produce json
+// @Param rule query string false "Only deliveries for this rule ID"
+// @Param limit query int false "Maximum number of deliveries (default 100)"
+// @Success 200 {array} notifications.Delivery "Delivery history"
+// @Failure 400 {object} map[string]string "Bad request - invalid limit"
+// @Security BearerAuth
+// @Router /api/v1/notifications/deliveries [get]
+func (h *NotificationsHandler) ListDeliveries(c *gin.Context) {
+	limit := 100
+	if l := c.Query("limit"); l != "" {
+		parsed, err := strconv.Atoi(l)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		limit = parsed
+	}
+	c.JSON(http.StatusOK, h.engine.Deliveries(c.Query("rule"), limit))
+}
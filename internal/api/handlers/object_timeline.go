@@ -0,0 +1,362 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/types"
+	"github.com/Facets-cloud/kube-dash/internal/applysets"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+// Sources of object timeline entries
+const (
+	timelineCreated       = "created"       // the object's creation timestamp
+	timelineManagedFields = "managedFields" // the last change of one field manager
+	timelineRevision      = "revision"      // a rollout revision recorded by the controller
+	timelineApply         = "apply"         // an apply made through the dashboard
+	timelineRollback      = "rollback"      // a rollback of such an apply
+)
+
+// timelineFieldDepth limits how deep managed field paths are reported
+const timelineFieldDepth = 3
+
+// TimelineEntry is one reconstructed change of an object
+type TimelineEntry struct {
+	Time       string   `json:"time"`
+	TimeMs     int64    `json:"timeMs,omitempty"`
+	Source     string   `json:"source"`
+	Actor      string   `json:"actor,omitempty"` // field manager, change cause or dashboard user
+	Operation  string   `json:"operation,omitempty"`
+	Fields     []string `json:"fields,omitempty"`
+	Summary    string   `json:"summary"`
+	Revision   string   `json:"revision,omitempty"`
+	ApplySetID string   `json:"applySetId,omitempty"`
+}
+
+// ObjectTimeline is the best-effort change history of one object, newest first
+type ObjectTimeline struct {
+	Group     string          `json:"group,omitempty"`
+	Version   string          `json:"version"`
+	Resource  string          `json:"resource"`
+	Kind      string          `json:"kind"`
+	Namespace string          `json:"namespace,omitempty"`
+	Name      string          `json:"name"`
+	Entries   []TimelineEntry `json:"entries"`
+	Notes     []string        `json:"notes,omitempty"`
+}
+
+// newTimelineEntry stamps an entry with its time in the API timestamp format
+func newTimelineEntry(t time.Time, source string) TimelineEntry {
+	return TimelineEntry{Time: types.TimeFormat(t), TimeMs: types.EpochMillis(t), Source: source}
+}
+
+// managedFieldPaths flattens a FieldsV1 set into dotted paths no deeper than depth. List items
+// keyed by a field (k:{"name":"app"}) are shown as [name=app], set values (v:"x") as [x].
+func managedFieldPaths(fields *metav1.FieldsV1, depth int) []string {
+	if fields == nil || len(fields.Raw) == 0 {
+		return nil
+	}
+	var tree map[string]interface{}
+	if err := json.Unmarshal(fields.Raw, &tree); err != nil {
+		return nil
+	}
+	var paths []string
+	var walk func(node map[string]interface{}, prefix string, level int)
+	walk = func(node map[string]interface{}, prefix string, level int) {
+		for key, child := range node {
+			segment, ok := fieldPathSegment(key)
+			if !ok {
+				continue
+			}
+			path := prefix + segment
+			if prefix != "" && !strings.HasPrefix(segment, "[") {
+				path = prefix + "." + segment
+			}
+			children, _ := child.(map[string]interface{})
+			if level+1 >= depth || !hasFieldChildren(children) {
+				paths = append(paths, path)
+				continue
+			}
+			walk(children, path, level+1)
+		}
+	}
+	walk(tree, "", 0)
+	sort.Strings(paths)
+	return paths
+}
+
+// fieldPathSegment renders one FieldsV1 key; "." marks the node itself and is skipped
+func fieldPathSegment(key string) (string, bool) {
+	switch {
+	case strings.HasPrefix(key, "f:"):
+		return key[2:], true
+	case strings.HasPrefix(key, "k:"):
+		var item map[string]interface{}
+		if err := json.Unmarshal([]byte(key[2:]), &item); err != nil {
+			return "[" + key[2:] + "]", true
+		}
+		parts := make([]string, 0, len(item))
+		for k, v := range item {
+			parts = append(parts, fmt.Sprintf("%s=%v", k, v))
+		}
+		sort.Strings(parts)
+		return "[" + strings.Join(parts, ",") + "]", true
+	case strings.HasPrefix(key, "v:"):
+		return "[" + strings.Trim(key[2:], `"`) + "]", true
+	case strings.HasPrefix(key, "i:"):
+		return "[" + key[2:] + "]", true
+	}
+	return "", false
+}
+
+// hasFieldChildren reports whether a FieldsV1 node has fields below it besides itself
+func hasFieldChildren(node map[string]interface{}) bool {
+	for key := range node {
+		if key != "." {
+			return true
+		}
+	}
+	return false
+}
+
+// managedFieldsEntries turns each field manager's last operation into an entry. The API server
+// keeps only the latest timestamp per manager and operation, so earlier changes are not visible.
+func managedFieldsEntries(obj metav1.Object) []TimelineEntry {
+	var entries []TimelineEntry
+	for _, managed := range obj.GetManagedFields() {
+		if managed.Time == nil {
+			continue
+		}
+		entry := newTimelineEntry(managed.Time.Time, timelineManagedFields)
+		entry.Actor = managed.Manager
+		entry.Operation = string(managed.Operation)
+		entry.Fields = managedFieldPaths(managed.FieldsV1, timelineFieldDepth)
+		target := "fields"
+		if managed.Subresource != "" {
+			target = managed.Subresource + " fields"
+		}
+		entry.Summary = fmt.Sprintf("%s last changed %d %s (%s)", managed.Manager, len(entry.Fields), target, managed.Operation)
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// containerImages returns name=image for each container at path in an object
+func containerImages(obj map[string]interface{}, path ...string) []string {
+	containers, _, _ := unstructured.NestedSlice(obj, path...)
+	var images []string
+	for _, c := range containers {
+		container, _ := c.(map[string]interface{})
+		name, _ := container["name"].(string)
+		image, _ := container["image"].(string)
+		if image != "" {
+			images = append(images, name+"="+image)
+		}
+	}
+	return images
+}
+
+// revisionEntries turns the ReplicaSets or ControllerRevisions owned by a workload into one
+// entry per rollout revision, with the change cause and images of each
+func revisionEntries(owner k8stypes.UID, revisions []unstructured.Unstructured) []TimelineEntry {
+	var entries []TimelineEntry
+	for _, rev := range revisions {
+		owned := false
+		for _, ref := range rev.GetOwnerReferences() {
+			if ref.UID == owner {
+				owned = true
+			}
+		}
+		if !owned {
+			continue
+		}
+
+		var revision string
+		var images []string
+		if rev.GetKind() == "ControllerRevision" {
+			n, _, _ := unstructured.NestedInt64(rev.Object, "revision")
+			revision = fmt.Sprintf("%d", n)
+			images = containerImages(rev.Object, "data", "spec", "template", "spec", "containers")
+		} else {
+			revision = rev.GetAnnotations()["deployment.kubernetes.io/revision"]
+			images = containerImages(rev.Object, "spec", "template", "spec", "containers")
+		}
+		if revision == "" {
+			continue
+		}
+
+		entry := newTimelineEntry(rev.GetCreationTimestamp().Time, timelineRevision)
+		entry.Revision = revision
+		entry.Actor = rev.GetAnnotations()["kubernetes.io/change-cause"]
+		entry.Summary = fmt.Sprintf("revision %s (%s)", revision, rev.GetName())
+		if len(images) > 0 {
+			entry.Summary += ": " + strings.Join(images, ", ")
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// applySetEntries returns an entry for every recorded apply, and rollback, of the object
+func applySetEntries(sets []applysets.ApplySet, gvr schema.GroupVersionResource, namespace, name string) []TimelineEntry {
+	var entries []TimelineEntry
+	for _, set := range sets {
+		for _, obj := range set.Objects {
+			if obj.Group != gvr.Group || obj.Resource != gvr.Resource || obj.Namespace != namespace || obj.Name != name {
+				continue
+			}
+			entry := newTimelineEntry(set.AppliedAt, timelineApply)
+			entry.Actor = set.AppliedBy
+			entry.ApplySetID = set.ID
+			entry.Summary = "applied through the dashboard, updating the object"
+			if !obj.Existed {
+				entry.Summary = "applied through the dashboard, creating the object"
+			}
+			entries = append(entries, entry)
+
+			if set.RolledBackAt != nil {
+				rollback := newTimelineEntry(*set.RolledBackAt, timelineRollback)
+				rollback.Actor = set.RolledBackBy
+				rollback.ApplySetID = set.ID
+				rollback.Summary = "apply rolled back through the dashboard"
+				entries = append(entries, rollback)
+			}
+		}
+	}
+	return entries
+}
+
+// buildObjectTimeline merges the entries of every source, newest first
+func buildObjectTimeline(obj *unstructured.Unstructured, gvr schema.GroupVersionResource, revisions []unstructured.Unstructured, sets []applysets.ApplySet) ObjectTimeline {
+	timeline := ObjectTimeline{
+		Group:     gvr.Group,
+		Version:   gvr.Version,
+		Resource:  gvr.Resource,
+		Kind:      obj.GetKind(),
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		Entries:   []TimelineEntry{},
+	}
+
+	created := newTimelineEntry(obj.GetCreationTimestamp().Time, timelineCreated)
+	created.Summary = obj.GetKind() + " created"
+	timeline.Entries = append(timeline.Entries, created)
+	timeline.Entries = append(timeline.Entries, managedFieldsEntries(obj)...)
+	timeline.Entries = append(timeline.Entries, revisionEntries(obj.GetUID(), revisions)...)
+	timeline.Entries = append(timeline.Entries, applySetEntries(sets, gvr, obj.GetNamespace(), obj.GetName())...)
+
+	// Creation sorts after the changes made at the same instant, such as the first revision
+	sort.SliceStable(timeline.Entries, func(i, j int) bool {
+		a, b := timeline.Entries[i], timeline.Entries[j]
+		if a.TimeMs != b.TimeMs {
+			return a.TimeMs > b.TimeMs
+		}
+		return b.Source == timelineCreated && a.Source != timelineCreated
+	})
+	return timeline
+}
+
+// revisionSource names the resource holding a workload's rollout revisions, if it has any
+func revisionSource(gvr schema.GroupVersionResource) (schema.GroupVersionResource, bool) {
+	if gvr.Group != "apps" {
+		return schema.GroupVersionResource{}, false
+	}
+	switch gvr.Resource {
+	case "deployments":
+		return schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}, true
+	case "statefulsets", "daemonsets":
+		return schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "controllerrevisions"}, true
+	}
+	return schema.GroupVersionResource{}, false
+}
+
+// GetObjectTimeline reconstructs who changed an object and when
+// @Summary Get object change timeline
+// @Description Reconstructs a best-effort change timeline for any object from its managedFields (the last operation of each field manager and the fields it owns), the rollout revisions of Deployments, StatefulSets and DaemonSets with their change cause and images, and the applies and rollbacks recorded by the dashboard. Managed fields only keep the latest change per manager, so earlier changes by the same manager are not shown.
+// @Tags Resources
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param group query string false "API group (empty for the core group)"
+// @Param version query string true "API version"
+// @Param resource query string true "Resource, e.g. deployments"
+// @Param namespace query string false "Namespace (empty for cluster-scoped resources)"
+// @Param name query string true "Object name"
+// @Success 200 {object} ObjectTimeline "Timeline, newest first"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 404 {object} map[string]interface{} "Object not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/objects/timeline [get]
+func (h *ResourcesHandler) GetObjectTimeline(c *gin.Context) {
+	gvr := schema.GroupVersionResource{Group: c.Query("group"), Version: c.Query("version"), Resource: c.Query("resource")}
+	namespace := c.Query("namespace")
+	name := c.Query("name")
+	if gvr.Version == "" || gvr.Resource == "" || name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "version, resource and name parameters are required", "code": http.StatusBadRequest})
+		return
+	}
+
+	dynamicClient, err := h.getDynamicClient(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get dynamic client for object timeline")
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error(), "code": http.StatusBadRequest})
+		return
+	}
+	ctx := c.Request.Context()
+
+	obj, err := dynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		status := http.StatusInternalServerError
+		if apierrors.IsNotFound(err) {
+			status = http.StatusNotFound
+		} else if apierrors.IsForbidden(err) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"message": err.Error(), "code": status})
+		return
+	}
+
+	var notes []string
+	var revisions []unstructured.Unstructured
+	if source, ok := revisionSource(gvr); ok {
+		opts := metav1.ListOptions{}
+		if selector, found, _ := unstructured.NestedMap(obj.Object, "spec", "selector"); found {
+			var labelSelector metav1.LabelSelector
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(selector, &labelSelector); err == nil {
+				opts.LabelSelector = metav1.FormatLabelSelector(&labelSelector)
+			}
+		}
+		list, err := dynamicClient.Resource(source).Namespace(namespace).List(ctx, opts)
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("rollout revisions unavailable: %v", err))
+		} else {
+			revisions = list.Items
+		}
+	}
+
+	var sets []applysets.ApplySet
+	if h.applySets != nil {
+		sets, err = h.applySets.List(applysets.Filter{ConfigID: c.Query("config"), Cluster: c.Query("cluster")})
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("recorded applies unavailable: %v", err))
+		}
+	}
+
+	timeline := buildObjectTimeline(obj, gvr, revisions, sets)
+	timeline.Notes = notes
+	c.JSON(http.StatusOK, timeline)
+}
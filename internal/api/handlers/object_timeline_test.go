@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/applysets"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestManagedFieldPaths(t *testing.T) {
+	fields := &metav1.FieldsV1{Raw: []byte(`{
+		"f:metadata": {"f:annotations": {"f:owner": {}}},
+		"f:spec": {
+			"f:replicas": {},
+			"f:template": {"f:spec": {"f:containers": {"k:{\"name\":\"app\"}": {".": {}, "f:image": {}}}}}
+		}
+	}`)}
+
+	got := managedFieldPaths(fields, timelineFieldDepth)
+	want := []string{"metadata.annotations.owner", "spec.replicas", "spec.template.spec"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("managedFieldPaths() = %v, want %v", got, want)
+	}
+
+	got = managedFieldPaths(fields, 6)
+	if got[len(got)-1] != "spec.template.spec.containers[name=app].image" {
+		t.Errorf("expected keyed list items to be rendered, got %v", got)
+	}
+}
+
+func TestBuildObjectTimeline(t *testing.T) {
+	created := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	scaled := created.Add(2 * time.Hour)
+	applied := created.Add(time.Hour)
+	rolledBack := created.Add(90 * time.Minute)
+
+	deployment := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "web", "namespace": "shop", "uid": "d-1"},
+	}}
+	deployment.SetCreationTimestamp(metav1.NewTime(created))
+	deployment.SetManagedFields([]metav1.ManagedFieldsEntry{
+		{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationUpdate, Time: &metav1.Time{Time: scaled},
+			FieldsType: "FieldsV1", FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:replicas":{}}}`)}},
+		{Manager: "kube-controller-manager", Operation: metav1.ManagedFieldsOperationUpdate, Subresource: "status", Time: &metav1.Time{Time: scaled},
+			FieldsType: "FieldsV1", FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:status":{"f:replicas":{}}}`)}},
+	})
+
+	replicaSet := func(name, revision, owner string, at time.Time) unstructured.Unstructured {
+		rs := unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "ReplicaSet",
+			"metadata": map[string]interface{}{
+				"name":            name,
+				"annotations":     map[string]interface{}{"deployment.kubernetes.io/revision": revision, "kubernetes.io/change-cause": "bump image"},
+				"ownerReferences": []interface{}{map[string]interface{}{"apiVersion": "apps/v1", "kind": "Deployment", "name": "web", "uid": owner}},
+			},
+			"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{
+				"containers": []interface{}{map[string]interface{}{"name": "app", "image": "web:" + revision}},
+			}}},
+		}}
+		rs.SetCreationTimestamp(metav1.NewTime(at))
+		return rs
+	}
+	revisions := []unstructured.Unstructured{
+		replicaSet("web-a", "1", "d-1", created),
+		replicaSet("web-b", "2", "d-1", applied),
+		replicaSet("other", "7", "d-2", applied),
+	}
+
+	sets := []applysets.ApplySet{{
+		ID: "set-1", AppliedBy: "alice", AppliedAt: applied, RolledBackAt: &rolledBack, RolledBackBy: "bob",
+		Objects: []applysets.Object{
+			{Group: "apps", Version: "v1", Kind: "Deployment", Resource: "deployments", Namespace: "shop", Name: "web", Existed: true},
+			{Version: "v1", Kind: "ConfigMap", Resource: "configmaps", Namespace: "shop", Name: "web"},
+		},
+	}}
+
+	gvr := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	timeline := buildObjectTimeline(deployment, gvr, revisions, sets)
+
+	var sources []string
+	for _, entry := range timeline.Entries {
+		sources = append(sources, entry.Source)
+	}
+	want := []string{
+		timelineManagedFields, timelineManagedFields, // 11:00
+		timelineRollback,                // 10:30
+		timelineRevision, timelineApply, // 10:00
+		timelineRevision, timelineCreated, // 09:00
+	}
+	if !reflect.DeepEqual(sources, want) {
+		t.Fatalf("unexpected entry order %v, want %v", sources, want)
+	}
+
+	kubectl := timeline.Entries[0]
+	if kubectl.Actor != "kubectl" || !reflect.DeepEqual(kubectl.Fields, []string{"spec.replicas"}) {
+		t.Errorf("unexpected managed fields entry %+v", kubectl)
+	}
+	if status := timeline.Entries[1]; status.Summary != "kube-controller-manager last changed 1 status fields (Update)" {
+		t.Errorf("unexpected status entry summary %q", status.Summary)
+	}
+	if revision := timeline.Entries[3]; revision.Revision != "2" || revision.Actor != "bump image" || revision.Summary != "revision 2 (web-b): app=web:2" {
+		t.Errorf("unexpected revision entry %+v", revision)
+	}
+	if apply := timeline.Entries[4]; apply.Actor != "alice" || apply.ApplySetID != "set-1" {
+		t.Errorf("unexpected apply entry %+v", apply)
+	}
+	if rollback := timeline.Entries[2]; rollback.Actor != "bob" {
+		t.Errorf("unexpected rollback entry %+v", rollback)
+	}
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// WatchObject streams one object of any resource
+// @Summary Watch a single object
+// @Description Sends the current state of one object of any resource, then keeps the stream open on a field-selector watch shared with every other stream showing the same object. Each change is sent as a changed-externally event naming the actor and an object event with the new state; nothing is sent while the object is unchanged. Requests without Accept: text/event-stream get the current state as JSON.
+// @Tags Resources
+// @Produce json
+// @Produce text/event-stream
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name"
+// @Param group query string false "API group (empty for the core group)"
+// @Param version query string true "API version"
+// @Param resource query string true "Resource, e.g. deployments"
+// @Param namespace query string false "Namespace (empty for cluster-scoped resources)"
+// @Param name query string true "Object name"
+// @Success 200 {object} map[string]interface{} "Object"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Object not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/objects/watch [get]
+func (h *ResourcesHandler) WatchObject(c *gin.Context) {
+	sse := utils.NewSSEHandler(h.logger)
+	gvr := schema.GroupVersionResource{Group: c.Query("group"), Version: c.Query("version"), Resource: c.Query("resource")}
+	namespace := c.Query("namespace")
+	name := c.Query("name")
+	if gvr.Version == "" || gvr.Resource == "" || name == "" {
+		sse.SendSSEError(c, http.StatusBadRequest, "version, resource and name parameters are required")
+		return
+	}
+
+	dynamicClient, err := h.getDynamicClient(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get dynamic client for object watch")
+		sse.SendSSEError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	var resource dynamic.ResourceInterface = dynamicClient.Resource(gvr)
+	if namespace != "" {
+		resource = dynamicClient.Resource(gvr).Namespace(namespace)
+	}
+
+	obj, err := resource.Get(c.Request.Context(), name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			sse.SendSSEError(c, http.StatusNotFound, fmt.Sprintf("%s %s not found", gvr.Resource, name))
+			return
+		}
+		if utils.IsPermissionError(err) {
+			sse.SendSSEPermissionError(c, err)
+			return
+		}
+		h.logger.WithError(err).WithField("resource", gvr.String()).WithField("name", name).Error("Failed to get object to watch")
+		sse.SendSSEError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if c.GetHeader("Accept") != "text/event-stream" {
+		c.JSON(http.StatusOK, obj)
+		return
+	}
+	sse.SendSSEDetailResponse(c, obj, obj, resource.Watch)
+}
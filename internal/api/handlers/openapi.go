@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/swaggo/swag"
+)
+
+// v2PathPrefix is the prefix of the versioned API surface with stable schemas
+const v2PathPrefix = "/api/v2/"
+
+// OpenAPIHandler serves the OpenAPI document of the v2 API, for generating clients
+type OpenAPIHandler struct {
+	logger  *logger.Logger
+	readDoc func() (string, error)
+}
+
+// NewOpenAPIHandler creates a new OpenAPI handler reading the document generated by swag
+func NewOpenAPIHandler(log *logger.Logger) *OpenAPIHandler {
+	return &OpenAPIHandler{
+		logger:  log,
+		readDoc: func() (string, error) { return swag.ReadDoc() },
+	}
+}
+
+// collectRefs adds the definitions referenced anywhere in a document value
+func collectRefs(value interface{}, refs map[string]bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if ref, ok := child.(string); ok && key == "$ref" {
+				refs[strings.TrimPrefix(ref, "#/definitions/")] = true
+				continue
+			}
+			collectRefs(child, refs)
+		}
+	case []interface{}:
+		for _, child := range v {
+			collectRefs(child, refs)
+		}
+	}
+}
+
+// v2Document reduces an OpenAPI document to the v2 paths and the definitions they reference,
+// directly or through other definitions
+func v2Document(raw []byte) (map[string]interface{}, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	allPaths, _ := doc["paths"].(map[string]interface{})
+	paths := map[string]interface{}{}
+	refs := map[string]bool{}
+	for path, item := range allPaths {
+		if strings.HasPrefix(path, v2PathPrefix) {
+			paths[path] = item
+			collectRefs(item, refs)
+		}
+	}
+
+	allDefinitions, _ := doc["definitions"].(map[string]interface{})
+	definitions := map[string]interface{}{}
+	pending := make([]string, 0, len(refs))
+	for name := range refs {
+		pending = append(pending, name)
+	}
+	for len(pending) > 0 {
+		name := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		definition, ok := allDefinitions[name]
+		if !ok {
+			continue
+		}
+		definitions[name] = definition
+		nested := map[string]bool{}
+		collectRefs(definition, nested)
+		for ref := range nested {
+			if !refs[ref] {
+				refs[ref] = true
+				pending = append(pending, ref)
+			}
+		}
+	}
+
+	doc["paths"] = paths
+	doc["definitions"] = definitions
+	info, _ := doc["info"].(map[string]interface{})
+	if info == nil {
+		info = map[string]interface{}{}
+	}
+	info["version"] = "2.0"
+	doc["info"] = info
+	return doc, nil
+}
+
+// GetOpenAPIV2 returns the OpenAPI document of the v2 API
+// @Summary Get the v2 OpenAPI document
+// @Description Returns the Swagger 2.0 document of the /api/v2 endpoints with only the definitions they use. The v2 endpoints return typed responses whose schemas only change in backwards-compatible ways, so the document can be used to generate API clients.
+// @Tags System
+// @Produce json
+// @Success 200 {object} map[string]interface{} "OpenAPI document"
+// @Failure 503 {object} map[string]string "API documentation not available"
+// @Router /api/v2/openapi.json [get]
+func (h *OpenAPIHandler) GetOpenAPIV2(c *gin.Context) {
+	raw, err := h.readDoc()
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to read API documentation")
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "API documentation not available"})
+		return
+	}
+	doc, err := v2Document([]byte(raw))
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to parse API documentation")
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "API documentation not available"})
+		return
+	}
+	c.JSON(http.StatusOK, doc)
+}
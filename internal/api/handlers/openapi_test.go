@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+const testSwaggerDoc = `{
+	"swagger": "2.0",
+	"info": {"title": "kube-dash API", "version": "1.0"},
+	"paths": {
+		"/api/v1/helmcharts": {"get": {"responses": {"200": {"schema": {"$ref": "#/definitions/helm.HelmChartsSearchResponse"}}}}},
+		"/api/v2/helmcharts": {"get": {"responses": {"200": {"schema": {"$ref": "#/definitions/helm.HelmChartsSearchResponse"}}}}},
+		"/api/v2/metrics/batch": {"post": {"responses": {"200": {"schema": {"$ref": "#/definitions/metrics.MetricsBatchResponse"}}}}},
+		"/api/v1/pods": {"get": {"responses": {"200": {"schema": {"$ref": "#/definitions/transformers.Pod"}}}}}
+	},
+	"definitions": {
+		"helm.HelmChartsSearchResponse": {"properties": {"data": {"type": "array", "items": {"$ref": "#/definitions/helm.HelmChart"}}}},
+		"helm.HelmChart": {"properties": {"repository": {"$ref": "#/definitions/helm.HelmChartRepository"}}},
+		"helm.HelmChartRepository": {"properties": {"name": {"type": "string"}}},
+		"metrics.MetricsBatchResponse": {"properties": {"step": {"type": "string"}}},
+		"transformers.Pod": {"properties": {"name": {"type": "string"}}}
+	}
+}`
+
+func TestGetOpenAPIV2(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewOpenAPIHandler(logger.New("error"))
+	handler.readDoc = func() (string, error) { return testSwaggerDoc, nil }
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v2/openapi.json", nil)
+	handler.GetOpenAPIV2(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body.String())
+	}
+
+	var doc struct {
+		Info        map[string]string          `json:"info"`
+		Paths       map[string]json.RawMessage `json:"paths"`
+		Definitions map[string]json.RawMessage `json:"definitions"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Paths) != 2 || doc.Paths["/api/v2/helmcharts"] == nil || doc.Paths["/api/v2/metrics/batch"] == nil {
+		t.Errorf("paths = %v, want only the v2 paths", doc.Paths)
+	}
+	for _, name := range []string{"helm.HelmChartsSearchResponse", "helm.HelmChart", "helm.HelmChartRepository", "metrics.MetricsBatchResponse"} {
+		if doc.Definitions[name] == nil {
+			t.Errorf("definition %s missing", name)
+		}
+	}
+	if len(doc.Definitions) != 4 || doc.Info["version"] != "2.0" || doc.Info["title"] != "kube-dash API" {
+		t.Errorf("definitions = %d, info = %v", len(doc.Definitions), doc.Info)
+	}
+}
@@ -0,0 +1,199 @@
+package podcleanup
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/podcleanup"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PodCleanupHandler serves evicted and failed pod cleanup policies, runs and reports
+type PodCleanupHandler struct {
+	cleaner *podcleanup.Cleaner
+	store   *storage.KubeConfigStore
+	logger  *logger.Logger
+}
+
+// PolicyRequest is the body of a policy update
+type PolicyRequest struct {
+	Enabled           bool     `json:"enabled"`
+	MaxAgeHours       int      `json:"maxAgeHours"`
+	EvictedOnly       bool     `json:"evictedOnly"`
+	ExcludeNamespaces []string `json:"excludeNamespaces"`
+}
+
+// RunRequest is the body of an on-demand cleanup; unset fields fall back to the cluster policy
+type RunRequest struct {
+	MaxAgeHours *int  `json:"maxAgeHours"`
+	EvictedOnly *bool `json:"evictedOnly"`
+	DryRun      bool  `json:"dryRun"`
+}
+
+// NewPodCleanupHandler creates a new pod cleanup handler
+func NewPodCleanupHandler(cleaner *podcleanup.Cleaner, store *storage.KubeConfigStore, log *logger.Logger) *PodCleanupHandler {
+	return &PodCleanupHandler{
+		cleaner: cleaner,
+		store:   store,
+		logger:  log,
+	}
+}
+
+// cluster validates the config and cluster query parameters
+func (h *PodCleanupHandler) cluster(c *gin.Context) (string, string, bool) {
+	configID := c.Query("config")
+	cluster := c.Query("cluster")
+	if configID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "config parameter is required"})
+		return "", "", false
+	}
+	if _, err := h.store.GetKubeConfig(configID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "kubeconfig not found"})
+		return "", "", false
+	}
+	return configID, cluster, true
+}
+
+// GetPolicy returns the pod cleanup policy of a cluster
+// @Summary Get pod cleanup policy
+// @Description Returns the evicted and failed pod cleanup policy of a cluster. Clusters without a stored policy get a disabled default policy.
+// @Tags Cluster
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Success 200 {object} podcleanup.Policy "Cleanup policy"
+// @Failure 400 {object} map[string]string "Bad request - missing parameters"
+// @Failure 404 {object} map[string]string "Kubeconfig not found"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/pod-cleanup/policy [get]
+func (h *PodCleanupHandler) GetPolicy(c *gin.Context) {
+	configID, cluster, ok := h.cluster(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, h.cleaner.Policy(configID, cluster))
+}
+
+// UpdatePolicy stores the pod cleanup policy of a cluster
+// @Summary Update pod cleanup policy
+// @Description Stores the cleanup policy of a cluster. When enabled, the background job deletes failed pods (or only evicted pods) older than maxAgeHours. Namespaces in excludeNamespaces and namespaces annotated kube-dash.io/pod-cleanup=disabled are never cleaned. Pods owned by Jobs are always kept.
+// @Tags Cluster
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param body body PolicyRequest true "Cleanup policy"
+// @Success 200 {object} podcleanup.Policy "Stored policy"
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
+// @Failure 404 {object} map[string]string "Kubeconfig not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/pod-cleanup/policy [put]
+func (h *PodCleanupHandler) UpdatePolicy(c *gin.Context) {
+	configID, cluster, ok := h.cluster(c)
+	if !ok {
+		return
+	}
+	var req PolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.MaxAgeHours < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "maxAgeHours must not be negative"})
+		return
+	}
+	policy, err := h.cleaner.SetPolicy(podcleanup.Policy{
+		ConfigID:          configID,
+		Cluster:           cluster,
+		Enabled:           req.Enabled,
+		MaxAgeHours:       req.MaxAgeHours,
+		EvictedOnly:       req.EvictedOnly,
+		ExcludeNamespaces: req.ExcludeNamespaces,
+	})
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to store pod cleanup policy")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+// RunCleanup deletes old evicted and failed pods on demand
+// @Summary Run pod cleanup
+// @Description Deletes failed pods older than the threshold right away, using the cluster policy for any field not set in the request. A dry run only reports the pods that would be deleted and is not recorded.
+// @Tags Cluster
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param body body RunRequest false "Run overrides"
+// @Success 200 {object} podcleanup.Run "Cleanup report"
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
+// @Failure 404 {object} map[string]string "Kubeconfig not found"
+// @Failure 500 {object} map[string]string "Cleanup failed"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/pod-cleanup/run [post]
+func (h *PodCleanupHandler) RunCleanup(c *gin.Context) {
+	configID, cluster, ok := h.cluster(c)
+	if !ok {
+		return
+	}
+	var req RunRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	policy := h.cleaner.Policy(configID, cluster)
+	if req.MaxAgeHours != nil {
+		if *req.MaxAgeHours < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "maxAgeHours must not be negative"})
+			return
+		}
+		policy.MaxAgeHours = *req.MaxAgeHours
+	}
+	if req.EvictedOnly != nil {
+		policy.EvictedOnly = *req.EvictedOnly
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Minute)
+	defer cancel()
+	run, err := h.cleaner.Run(ctx, policy, "manual", req.DryRun)
+	if err != nil {
+		h.logger.WithError(err).WithField("config", configID).WithField("cluster", cluster).Error("Pod cleanup failed")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "run": run})
+		return
+	}
+	h.logger.WithField("config", configID).WithField("cluster", cluster).WithField("deleted", run.Deleted).Info("Pod cleanup completed")
+	c.JSON(http.StatusOK, run)
+}
+
+// GetRuns returns the recorded cleanup reports of a cluster
+// @Summary List pod cleanup reports
+// @Description Lists the recorded scheduled and manual cleanup runs of a cluster, newest first, with the pods each run deleted
+// @Tags Cluster
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Success 200 {array} podcleanup.Run "Cleanup reports"
+// @Failure 400 {object} map[string]string "Bad request - missing parameters"
+// @Failure 404 {object} map[string]string "Kubeconfig not found"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/pod-cleanup/runs [get]
+func (h *PodCleanupHandler) GetRuns(c *gin.Context) {
+	configID, cluster, ok := h.cluster(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, h.cleaner.Runs(configID, cluster))
+}
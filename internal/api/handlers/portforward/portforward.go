@@ -211,7 +211,7 @@ func (h *PortForwardHandler) startInactivityMonitor() {
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *PortForwardHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, *rest.Config, error) {
+func (h *PortForwardHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, *rest.Config, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -483,7 +483,7 @@ func (h *PortForwardHandler) HandlePortForward(c *gin.Context) {
 }
 
 // startPodPortForward starts port forwarding for a pod
-func (h *PortForwardHandler) startPodPortForward(session *PortForwardSession, client *kubernetes.Clientset, restConfig *rest.Config) error {
+func (h *PortForwardHandler) startPodPortForward(session *PortForwardSession, client kubernetes.Interface, restConfig *rest.Config) error {
 	// Verify pod exists and is running
 	pod, err := client.CoreV1().Pods(session.Namespace).Get(context.Background(), session.ResourceName, metav1.GetOptions{})
 	if err != nil {
@@ -621,7 +621,7 @@ func (h *PortForwardHandler) startPodPortForward(session *PortForwardSession, cl
 }
 
 // startServicePortForward starts port forwarding for a service
-func (h *PortForwardHandler) startServicePortForward(session *PortForwardSession, client *kubernetes.Clientset, restConfig *rest.Config) error {
+func (h *PortForwardHandler) startServicePortForward(session *PortForwardSession, client kubernetes.Interface, restConfig *rest.Config) error {
 	// For services, we need to find a pod to forward to
 	// This is a simplified implementation - in practice, you might want to select a specific pod
 	pods, err := client.CoreV1().Pods(session.Namespace).List(context.Background(), metav1.ListOptions{
@@ -0,0 +1,649 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/handlers/compare"
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+	"github.com/Facets-cloud/kube-dash/internal/apitokens"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// promotableWorkloads maps the workload kinds that can be promoted to their resources
+var promotableWorkloads = map[string]schema.GroupVersionResource{
+	"Deployment":  {Group: "apps", Version: "v1", Resource: "deployments"},
+	"StatefulSet": {Group: "apps", Version: "v1", Resource: "statefulsets"},
+	"DaemonSet":   {Group: "apps", Version: "v1", Resource: "daemonsets"},
+}
+
+var (
+	namespacesGVR      = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+	servicesGVR        = schema.GroupVersionResource{Version: "v1", Resource: "services"}
+	configMapsGVR      = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	secretsGVR         = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	serviceAccountsGVR = schema.GroupVersionResource{Version: "v1", Resource: "serviceaccounts"}
+)
+
+// PromoteTarget is the cluster and namespace a workload is promoted to
+type PromoteTarget struct {
+	ConfigID  string `json:"configId" binding:"required"`
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace"`
+}
+
+// PromoteMapping rewrites environment-specific fields before applying. A mapping either
+// sets (or deletes) the field at Path, or replaces Find with Replace in every string value.
+// Kind and Name restrict the mapping to matching objects.
+type PromoteMapping struct {
+	Kind    string      `json:"kind,omitempty"`
+	Name    string      `json:"name,omitempty"`
+	Path    string      `json:"path,omitempty"` // e.g. spec.replicas, spec.template.spec.containers[name=app].image, metadata.annotations[example.com/team]
+	Value   interface{} `json:"value,omitempty"`
+	Delete  bool        `json:"delete,omitempty"`
+	Find    string      `json:"find,omitempty"`
+	Replace string      `json:"replace,omitempty"`
+}
+
+// PromoteRequest describes a workload promotion
+type PromoteRequest struct {
+	Namespace      string           `json:"namespace" binding:"required"`
+	Kind           string           `json:"kind"` // Deployment (default), StatefulSet or DaemonSet
+	Name           string           `json:"name" binding:"required"`
+	Target         PromoteTarget    `json:"target"`
+	Mappings       []PromoteMapping `json:"mappings"`
+	SkipServices   bool             `json:"skipServices"`
+	SkipConfigMaps bool             `json:"skipConfigMaps"`
+	SkipSecrets    bool             `json:"skipSecrets"`
+	DryRun         bool             `json:"dryRun"`
+}
+
+// PromotedObject reports what promotion does to one object on the target
+type PromotedObject struct {
+	Kind      string              `json:"kind"`
+	Name      string              `json:"name"`
+	Namespace string              `json:"namespace,omitempty"`
+	Action    string              `json:"action"` // create, update or unchanged
+	Diffs     []compare.FieldDiff `json:"diffs,omitempty"`
+	Applied   bool                `json:"applied"`
+	Note      string              `json:"note,omitempty"`
+	Error     string              `json:"error,omitempty"`
+}
+
+// PromoteResult is the outcome of a promotion or its dry-run preview
+type PromoteResult struct {
+	DryRun  bool             `json:"dryRun"`
+	Target  PromoteTarget    `json:"target"`
+	Objects []PromotedObject `json:"objects"`
+	Applied int              `json:"applied"`
+	Failed  int              `json:"failed"`
+}
+
+// dynamicClientFor gets a dynamic client and REST mapper for an explicit config and cluster
+func (h *ResourcesHandler) dynamicClientFor(configID, cluster string) (dynamic.Interface, meta.RESTMapper, error) {
+	config, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("config not found: %w", err)
+	}
+	mapper, _, err := h.clientFactory.GetRESTMapperForConfig(config, cluster)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get Kubernetes client: %w", err)
+	}
+	dynamicClient, err := h.clientFactory.GetDynamicClientForConfig(config, cluster)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get dynamic client: %w", err)
+	}
+	return dynamicClient, mapper, nil
+}
+
+// podTemplateReferences returns the ConfigMaps, Secrets and ServiceAccount a pod template uses
+func podTemplateReferences(spec *corev1.PodSpec) (configMaps, secrets map[string]bool, serviceAccount string) {
+	configMaps, secrets = map[string]bool{}, map[string]bool{}
+	for _, v := range spec.Volumes {
+		if v.ConfigMap != nil {
+			configMaps[v.ConfigMap.Name] = true
+		}
+		if v.Secret != nil {
+			secrets[v.Secret.SecretName] = true
+		}
+		if v.Projected != nil {
+			for _, src := range v.Projected.Sources {
+				if src.ConfigMap != nil {
+					configMaps[src.ConfigMap.Name] = true
+				}
+				if src.Secret != nil {
+					secrets[src.Secret.Name] = true
+				}
+			}
+		}
+	}
+	containers := append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...)
+	for _, c := range containers {
+		for _, env := range c.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if env.ValueFrom.ConfigMapKeyRef != nil {
+				configMaps[env.ValueFrom.ConfigMapKeyRef.Name] = true
+			}
+			if env.ValueFrom.SecretKeyRef != nil {
+				secrets[env.ValueFrom.SecretKeyRef.Name] = true
+			}
+		}
+		for _, from := range c.EnvFrom {
+			if from.ConfigMapRef != nil {
+				configMaps[from.ConfigMapRef.Name] = true
+			}
+			if from.SecretRef != nil {
+				secrets[from.SecretRef.Name] = true
+			}
+		}
+	}
+	for _, ref := range spec.ImagePullSecrets {
+		secrets[ref.Name] = true
+	}
+	if spec.ServiceAccountName != "" && spec.ServiceAccountName != "default" {
+		serviceAccount = spec.ServiceAccountName
+	}
+	return configMaps, secrets, serviceAccount
+}
+
+// sanitizeForPromotion strips cluster-specific state so an object can be applied elsewhere
+func sanitizeForPromotion(obj *unstructured.Unstructured, namespace string) {
+	delete(obj.Object, "status")
+	for _, field := range []string{"uid", "resourceVersion", "generation", "creationTimestamp", "managedFields", "selfLink", "ownerReferences", "generateName"} {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+	unstructured.RemoveNestedField(obj.Object, "metadata", "annotations", "kubectl.kubernetes.io/last-applied-configuration")
+	unstructured.RemoveNestedField(obj.Object, "metadata", "annotations", "deployment.kubernetes.io/revision")
+	unstructured.RemoveNestedField(obj.Object, "spec", "template", "metadata", "creationTimestamp")
+	obj.SetNamespace(namespace)
+
+	switch obj.GetKind() {
+	case "Service":
+		// Cluster IPs and node ports are allocated by the target cluster
+		unstructured.RemoveNestedField(obj.Object, "spec", "clusterIP")
+		unstructured.RemoveNestedField(obj.Object, "spec", "clusterIPs")
+		unstructured.RemoveNestedField(obj.Object, "spec", "healthCheckNodePort")
+		if ports, ok, _ := unstructured.NestedSlice(obj.Object, "spec", "ports"); ok {
+			for _, p := range ports {
+				if port, ok := p.(map[string]interface{}); ok {
+					delete(port, "nodePort")
+				}
+			}
+			_ = unstructured.SetNestedSlice(obj.Object, ports, "spec", "ports")
+		}
+	case "ServiceAccount":
+		// Token secrets are generated per cluster
+		unstructured.RemoveNestedField(obj.Object, "secrets")
+	}
+}
+
+// applyPromoteMapping applies one mapping to an object if its kind and name filters match.
+// Find/replace covers string values only, so base64 Secret data is left untouched.
+func applyPromoteMapping(obj *unstructured.Unstructured, m PromoteMapping) error {
+	if m.Kind != "" && !strings.EqualFold(m.Kind, obj.GetKind()) {
+		return nil
+	}
+	if m.Name != "" && m.Name != obj.GetName() {
+		return nil
+	}
+	if m.Path != "" {
+		if m.Delete {
+			return deletePath(obj.Object, m.Path)
+		}
+		return setPath(obj.Object, m.Path, m.Value)
+	}
+	if m.Find != "" {
+		obj.Object = replaceStrings(obj.Object, m.Find, m.Replace).(map[string]interface{})
+	}
+	return nil
+}
+
+// replaceStrings substitutes find with replace in every string value (not keys)
+func replaceStrings(value interface{}, find, replace string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return strings.ReplaceAll(v, find, replace)
+	case map[string]interface{}:
+		for k, item := range v {
+			v[k] = replaceStrings(item, find, replace)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = replaceStrings(item, find, replace)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// pathSegment is one step of a mapping path: a map key, a list index or a list item selected by name
+type pathSegment struct {
+	key   string
+	index int
+	name  string
+	kind  int // 0 key, 1 index, 2 name selector
+}
+
+// parsePath splits a path such as spec.containers[name=app].image or metadata.labels[app.kubernetes.io/name]
+func parsePath(path string) ([]pathSegment, error) {
+	var segments []pathSegment
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			segments = append(segments, pathSegment{key: current.String()})
+			current.Reset()
+		}
+	}
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case '.':
+			flush()
+		case '[':
+			flush()
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated [ in path %q", path)
+			}
+			inner := path[i+1 : i+end]
+			i += end
+			if strings.HasPrefix(inner, "name=") {
+				segments = append(segments, pathSegment{name: strings.TrimPrefix(inner, "name="), kind: 2})
+			} else if n, err := strconv.Atoi(inner); err == nil {
+				segments = append(segments, pathSegment{index: n, kind: 1})
+			} else if inner != "" {
+				segments = append(segments, pathSegment{key: inner})
+			} else {
+				return nil, fmt.Errorf("empty [] in path %q", path)
+			}
+		default:
+			current.WriteByte(path[i])
+		}
+	}
+	flush()
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("empty path")
+	}
+	return segments, nil
+}
+
+// resolveParent walks all but the last segment, creating missing maps, and returns the container
+func resolveParent(obj map[string]interface{}, segments []pathSegment) (interface{}, error) {
+	var current interface{} = obj
+	for i, seg := range segments[:len(segments)-1] {
+		next := segments[i+1]
+		switch seg.kind {
+		case 0:
+			m, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s is not an object", seg.key)
+			}
+			child, exists := m[seg.key]
+			if !exists || child == nil {
+				if next.kind != 0 {
+					return nil, fmt.Errorf("%s does not exist", seg.key)
+				}
+				child = map[string]interface{}{}
+				m[seg.key] = child
+			}
+			current = child
+		default:
+			item, err := listItem(current, seg)
+			if err != nil {
+				return nil, err
+			}
+			current = item
+		}
+	}
+	return current, nil
+}
+
+// listItem selects a list element by index or name
+func listItem(container interface{}, seg pathSegment) (interface{}, error) {
+	list, ok := container.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("not a list")
+	}
+	if seg.kind == 1 {
+		if seg.index < 0 || seg.index >= len(list) {
+			return nil, fmt.Errorf("index %d out of range", seg.index)
+		}
+		return list[seg.index], nil
+	}
+	for _, item := range list {
+		if m, ok := item.(map[string]interface{}); ok && m["name"] == seg.name {
+			return m, nil
+		}
+	}
+	return nil, fmt.Errorf("no item named %s", seg.name)
+}
+
+// setPath sets the value at path, creating intermediate objects
+func setPath(obj map[string]interface{}, path string, value interface{}) error {
+	segments, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	parent, err := resolveParent(obj, segments)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	last := segments[len(segments)-1]
+	if last.kind == 0 {
+		m, ok := parent.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: parent is not an object", path)
+		}
+		m[last.key] = value
+		return nil
+	}
+	list, ok := parent.([]interface{})
+	if !ok {
+		return fmt.Errorf("%s: parent is not a list", path)
+	}
+	if last.kind == 1 {
+		if last.index < 0 || last.index >= len(list) {
+			return fmt.Errorf("%s: index out of range", path)
+		}
+		list[last.index] = value
+		return nil
+	}
+	for i, item := range list {
+		if m, ok := item.(map[string]interface{}); ok && m["name"] == last.name {
+			list[i] = value
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: no item named %s", path, last.name)
+}
+
+// deletePath removes the map key at path; a missing field is not an error
+func deletePath(obj map[string]interface{}, path string) error {
+	segments, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	last := segments[len(segments)-1]
+	if last.kind != 0 {
+		return fmt.Errorf("%s: only object fields can be deleted", path)
+	}
+	parent, err := resolveParent(obj, segments)
+	if err != nil {
+		return nil
+	}
+	if m, ok := parent.(map[string]interface{}); ok {
+		delete(m, last.key)
+	}
+	return nil
+}
+
+// collectPromotion gathers the workload and the objects it references from the source namespace
+func collectPromotion(ctx context.Context, client dynamic.Interface, req *PromoteRequest, gvr schema.GroupVersionResource) ([]*unstructured.Unstructured, error) {
+	workload, err := client.Resource(gvr).Namespace(req.Namespace).Get(ctx, req.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s %s/%s: %w", req.Kind, req.Namespace, req.Name, err)
+	}
+
+	var template corev1.PodTemplateSpec
+	if raw, ok, _ := unstructured.NestedMap(workload.Object, "spec", "template"); ok {
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, &template); err != nil {
+			return nil, fmt.Errorf("failed to read pod template: %w", err)
+		}
+	}
+	configMaps, secrets, serviceAccount := podTemplateReferences(&template.Spec)
+
+	var objects []*unstructured.Unstructured
+	if serviceAccount != "" {
+		if sa, err := client.Resource(serviceAccountsGVR).Namespace(req.Namespace).Get(ctx, serviceAccount, metav1.GetOptions{}); err == nil {
+			objects = append(objects, sa)
+		}
+	}
+	if !req.SkipConfigMaps {
+		for name := range configMaps {
+			cm, err := client.Resource(configMapsGVR).Namespace(req.Namespace).Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				// Optional references may legitimately be missing
+				continue
+			}
+			objects = append(objects, cm)
+		}
+	}
+	if !req.SkipSecrets {
+		for name := range secrets {
+			secret, err := client.Resource(secretsGVR).Namespace(req.Namespace).Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				continue
+			}
+			if t, _, _ := unstructured.NestedString(secret.Object, "type"); t == string(corev1.SecretTypeServiceAccountToken) {
+				continue
+			}
+			objects = append(objects, secret)
+		}
+	}
+	if !req.SkipServices {
+		services, err := client.Resource(servicesGVR).Namespace(req.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list services: %w", err)
+		}
+		for i := range services.Items {
+			selector, _, _ := unstructured.NestedStringMap(services.Items[i].Object, "spec", "selector")
+			if selectorMatches(selector, template.Labels) {
+				objects = append(objects, &services.Items[i])
+			}
+		}
+	}
+	return append(objects, workload), nil
+}
+
+// selectorMatches reports whether a non-empty equality selector matches labels
+func selectorMatches(selector, labels map[string]string) bool {
+	if len(selector) == 0 {
+		return false
+	}
+	for k, v := range selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// previewObject compares a prepared object with its current state on the target
+func previewObject(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) PromotedObject {
+	result := PromotedObject{Kind: obj.GetKind(), Name: obj.GetName(), Namespace: obj.GetNamespace()}
+	var resource dynamic.ResourceInterface = client.Resource(gvr)
+	if obj.GetNamespace() != "" {
+		resource = client.Resource(gvr).Namespace(obj.GetNamespace())
+	}
+	existing, err := resource.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		result.Action = "create"
+		return result
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	desired := compare.Normalize(obj.DeepCopy().Object, obj.GetKind())
+	current := compare.Normalize(existing.Object, obj.GetKind())
+	// Only fields the promotion sets are compared; server defaults on the target are not drift
+	result.Diffs = compare.Diff(pick(current, desired), desired, nil)
+	result.Action = "update"
+	if len(result.Diffs) == 0 {
+		result.Action = "unchanged"
+	}
+	return result
+}
+
+// pick projects current onto the shape of desired so fields only defaulted on the server are ignored
+func pick(current, desired interface{}) interface{} {
+	desiredMap, ok := desired.(map[string]interface{})
+	currentMap, ok2 := current.(map[string]interface{})
+	if !ok || !ok2 {
+		return current
+	}
+	picked := make(map[string]interface{}, len(desiredMap))
+	for k, v := range desiredMap {
+		if cv, exists := currentMap[k]; exists {
+			picked[k] = pick(cv, v)
+		}
+	}
+	return picked
+}
+
+// PromoteWorkload copies a workload and the objects it references to another cluster
+// @Summary Promote workload across clusters
+// @Description Takes a Deployment, StatefulSet or DaemonSet with the Services selecting it and the ConfigMaps, Secrets and ServiceAccount it references, strips cluster-specific fields, rewrites environment-specific values with the supplied mappings and server-side applies the result to a target cluster and namespace. With dryRun the changes are previewed as per-object diffs and validated by the target API server without being persisted.
+// @Tags Resources
+// @Accept json
+// @Produce json
+// @Param config query string true "Source Kubernetes config ID"
+// @Param cluster query string false "Source cluster name"
+// @Param request body PromoteRequest true "Promotion request"
+// @Success 200 {object} PromoteResult "Promotion result or preview"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters or mappings"
+// @Failure 404 {object} map[string]string "Workload not found"
+// @Failure 422 {object} PromoteResult "One or more objects failed to apply"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/promote [post]
+func (h *ResourcesHandler) PromoteWorkload(c *gin.Context) {
+	var req PromoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if req.Kind == "" {
+		req.Kind = "Deployment"
+	}
+	gvr, ok := promotableWorkloads[req.Kind]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "kind must be Deployment, StatefulSet or DaemonSet"})
+		return
+	}
+	if req.Target.Namespace == "" {
+		req.Target.Namespace = req.Namespace
+	}
+	for _, m := range req.Mappings {
+		if (m.Path == "") == (m.Find == "") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "each mapping needs exactly one of path or find"})
+			return
+		}
+	}
+	if req.Target.ConfigID == c.Query("config") && req.Target.Cluster == c.Query("cluster") && req.Target.Namespace == req.Namespace {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "target must differ from the source"})
+		return
+	}
+	// The token middleware only sees the source cluster in the query string
+	if token, ok := apitokens.FromContext(c); ok && !token.AllowsCluster(req.Target.ConfigID, req.Target.Cluster) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "API token is not scoped to the target cluster"})
+		return
+	}
+	// Nor does the session middleware see the target kubeconfig
+	if !h.store.UsableBy(req.Target.ConfigID, utils.SessionID(c)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "target: kubeconfig not found: " + req.Target.ConfigID})
+		return
+	}
+
+	source, err := h.getDynamicClient(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	target, targetMapper, err := h.dynamicClientFor(req.Target.ConfigID, req.Target.Cluster)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "target: " + err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	objects, err := collectPromotion(ctx, source, &req, gvr)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if apierrors.IsNotFound(err) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	for _, obj := range objects {
+		sanitizeForPromotion(obj, req.Target.Namespace)
+		for _, m := range req.Mappings {
+			if err := applyPromoteMapping(obj, m); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("mapping on %s/%s: %v", obj.GetKind(), obj.GetName(), err)})
+				return
+			}
+		}
+	}
+
+	// Create the target namespace first when it does not exist yet
+	newNamespace := false
+	if _, err := target.Resource(namespacesGVR).Get(ctx, req.Target.Namespace, metav1.GetOptions{}); apierrors.IsNotFound(err) {
+		ns := &unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "v1", "kind": "Namespace"}}
+		ns.SetName(req.Target.Namespace)
+		objects = append([]*unstructured.Unstructured{ns}, objects...)
+		newNamespace = true
+	}
+
+	result := PromoteResult{DryRun: req.DryRun, Target: req.Target, Objects: []PromotedObject{}}
+	for _, obj := range objects {
+		gvk := obj.GroupVersionKind()
+		mapping, err := targetMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			result.Objects = append(result.Objects, PromotedObject{Kind: obj.GetKind(), Name: obj.GetName(), Namespace: obj.GetNamespace(), Error: fmt.Sprintf("kind not served by target: %v", err)})
+			result.Failed++
+			continue
+		}
+		var promoted PromotedObject
+		if newNamespace {
+			promoted = PromotedObject{Kind: obj.GetKind(), Name: obj.GetName(), Namespace: obj.GetNamespace(), Action: "create"}
+		} else {
+			promoted = previewObject(ctx, target, mapping.Resource, obj)
+		}
+
+		// A dry run cannot validate objects in a namespace that is only created by the same promotion
+		if req.DryRun && newNamespace && obj.GetKind() != "Namespace" {
+			promoted.Note = "target namespace does not exist yet; server-side validation skipped"
+			result.Objects = append(result.Objects, promoted)
+			continue
+		}
+
+		if _, failure := applyObject(ctx, target, targetMapper, obj, req.DryRun); failure != nil {
+			promoted.Error = failure.Message
+			result.Failed++
+		} else {
+			promoted.Applied = !req.DryRun
+			if !req.DryRun {
+				result.Applied++
+			}
+		}
+		result.Objects = append(result.Objects, promoted)
+	}
+
+	h.logger.WithField("workload", req.Kind+"/"+req.Namespace+"/"+req.Name).
+		WithField("target", req.Target.ConfigID+"/"+req.Target.Cluster+"/"+req.Target.Namespace).
+		WithField("dryRun", req.DryRun).
+		Infof("Promoted workload: %d applied, %d failed", result.Applied, result.Failed)
+
+	status := http.StatusOK
+	if result.Failed > 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, result)
+}
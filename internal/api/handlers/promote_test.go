@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestPromoteMappings(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "api"},
+		"spec": map[string]interface{}{
+			"replicas": int64(1),
+			"template": map[string]interface{}{"spec": map[string]interface{}{"containers": []interface{}{
+				map[string]interface{}{"name": "app", "image": "registry.staging.example.com/api:1.2", "env": []interface{}{
+					map[string]interface{}{"name": "DB_HOST", "value": "db.staging.svc"},
+				}},
+			}}},
+		},
+	}}
+
+	mappings := []PromoteMapping{
+		{Kind: "Deployment", Path: "spec.replicas", Value: 4},
+		{Find: "staging", Replace: "prod"},
+		{Path: "spec.template.spec.containers[name=app].env[0].value", Value: "db.prod.internal"},
+		{Path: "metadata.annotations[example.com/promoted-from]", Value: "staging"},
+		{Kind: "Service", Path: "spec.type", Value: "LoadBalancer"},
+	}
+	for _, m := range mappings {
+		if err := applyPromoteMapping(obj, m); err != nil {
+			t.Fatalf("mapping %+v: %v", m, err)
+		}
+	}
+
+	if v, _, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", "replicas"); v != 4 {
+		t.Errorf("replicas = %v", v)
+	}
+	container := obj.Object["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})["containers"].([]interface{})[0].(map[string]interface{})
+	if container["image"] != "registry.prod.example.com/api:1.2" {
+		t.Errorf("image = %v", container["image"])
+	}
+	if env := container["env"].([]interface{})[0].(map[string]interface{}); env["value"] != "db.prod.internal" {
+		t.Errorf("env value = %v", env["value"])
+	}
+	if obj.GetAnnotations()["example.com/promoted-from"] != "staging" {
+		t.Errorf("annotations = %v", obj.GetAnnotations())
+	}
+	if _, found, _ := unstructured.NestedString(obj.Object, "spec", "type"); found {
+		t.Errorf("mapping for another kind was applied")
+	}
+
+	if err := applyPromoteMapping(obj, PromoteMapping{Path: "spec.template.spec.containers[name=missing].image", Value: "x"}); err == nil {
+		t.Errorf("expected error for unknown container")
+	}
+	if err := applyPromoteMapping(obj, PromoteMapping{Path: "spec.replicas", Delete: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", "replicas"); found {
+		t.Errorf("replicas not deleted")
+	}
+}
+
+func TestPromoteRejectsForeignSessionTarget(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	config := api.NewConfig()
+	config.Clusters["c1"] = &api.Cluster{Server: "https://c1.example.com"}
+	config.AuthInfos["u1"] = &api.AuthInfo{Token: "secret"}
+	config.Contexts["c1"] = &api.Context{Cluster: "c1", AuthInfo: "u1"}
+	store := storage.NewKubeConfigStore()
+	private, err := store.AddSessionKubeConfig(config, "private", "session-a", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := &ResourcesHandler{store: store}
+
+	body := `{"namespace":"shop","name":"web","target":{"configId":"` + private + `","cluster":"c1"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/promote?config=shared&cluster=c1", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(utils.SessionHeader, "session-b")
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = req
+	h.PromoteWorkload(c)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected another session's target kubeconfig to be rejected, got %d: %s", rec.Code, rec.Body)
+	}
+}
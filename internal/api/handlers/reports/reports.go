@@ -0,0 +1,273 @@
+package reports
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Facets-cloud/kube-dash/internal/reports"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReportsHandler manages report schedules and serves generated reports
+type ReportsHandler struct {
+	scheduler *reports.Scheduler
+	logger    *logger.Logger
+}
+
+// NewReportsHandler creates a new reports handler
+func NewReportsHandler(scheduler *reports.Scheduler, log *logger.Logger) *ReportsHandler {
+	return &ReportsHandler{
+		scheduler: scheduler,
+		logger:    log,
+	}
+}
+
+func (h *ReportsHandler) storageError(c *gin.Context, err error, what string) {
+	if errors.Is(err, storage.ErrDocumentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": what + " not found"})
+		return
+	}
+	h.logger.WithError(err).Error("Report operation failed")
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+// ListSchedules returns all report schedules
+// @Summary List report schedules
+// @Description Lists schedules that periodically generate overview, cost, deprecation and security reports
+// @Tags Reports
+// @Produce json
+// @Success 200 {array} reports.Schedule "Report schedules"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/reports/schedules [get]
+func (h *ReportsHandler) ListSchedules(c *gin.Context) {
+	schedules, err := h.scheduler.ListSchedules()
+	if err != nil {
+		h.storageError(c, err, "report schedule")
+		return
+	}
+	c.JSON(http.StatusOK, schedules)
+}
+
+// GetSchedule returns a single report schedule
+// @Summary Get report schedule
+// @Description Returns a report schedule by ID
+// @Tags Reports
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Success 200 {object} reports.Schedule "Report schedule"
+// @Failure 404 {object} map[string]string "Schedule not found"
+// @Security BearerAuth
+// @Router /api/v1/reports/schedules/{id} [get]
+func (h *ReportsHandler) GetSchedule(c *gin.Context) {
+	schedule, err := h.scheduler.GetSchedule(c.Param("id"))
+	if err != nil {
+		h.storageError(c, err, "report schedule")
+		return
+	}
+	c.JSON(http.StatusOK, schedule)
+}
+
+// CreateSchedule creates a report schedule
+// @Summary Create report schedule
+// @Description Creates a schedule generating the selected reports as JSON, HTML or PDF at a fixed interval, optionally delivered to Slack, webhook or email channels
+// @Tags Reports
+// @Accept json
+// @Produce json
+// @Param schedule body reports.Schedule true "Report schedule"
+// @Success 201 {object} reports.Schedule "Created schedule"
+// @Failure 400 {object} map[string]string "Bad request - invalid schedule"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/reports/schedules [post]
+func (h *ReportsHandler) CreateSchedule(c *gin.Context) {
+	var schedule reports.Schedule
+	if err := c.ShouldBindJSON(&schedule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	schedule.ID = ""
+	schedule.LastRunAt = nil
+	if err := schedule.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.scheduler.SaveSchedule(&schedule); err != nil {
+		h.storageError(c, err, "report schedule")
+		return
+	}
+	c.JSON(http.StatusCreated, schedule)
+}
+
+// UpdateSchedule replaces a report schedule
+// @Summary Update report schedule
+// @Description Replaces a report schedule, keeping its ID, creation time and run history
+// @Tags Reports
+// @Accept json
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Param schedule body reports.Schedule true "Report schedule"
+// @Success 200 {object} reports.Schedule "Updated schedule"
+// @Failure 400 {object} map[string]string "Bad request - invalid schedule"
+// @Failure 404 {object} map[string]string "Schedule not found"
+// @Security BearerAuth
+// @Router /api/v1/reports/schedules/{id} [put]
+func (h *ReportsHandler) UpdateSchedule(c *gin.Context) {
+	existing, err := h.scheduler.GetSchedule(c.Param("id"))
+	if err != nil {
+		h.storageError(c, err, "report schedule")
+		return
+	}
+	var schedule reports.Schedule
+	if err := c.ShouldBindJSON(&schedule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	schedule.ID = existing.ID
+	schedule.CreatedAt = existing.CreatedAt
+	schedule.LastRunAt = existing.LastRunAt
+	schedule.LastReportID = existing.LastReportID
+	schedule.LastError = existing.LastError
+	if err := schedule.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.scheduler.SaveSchedule(&schedule); err != nil {
+		h.storageError(c, err, "report schedule")
+		return
+	}
+	c.JSON(http.StatusOK, schedule)
+}
+
+// DeleteSchedule deletes a report schedule and its generated reports
+// @Summary Delete report schedule
+// @Description Deletes a report schedule together with its stored reports
+// @Tags Reports
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Success 200 {object} map[string]string "Schedule deleted"
+// @Failure 404 {object} map[string]string "Schedule not found"
+// @Security BearerAuth
+// @Router /api/v1/reports/schedules/{id} [delete]
+func (h *ReportsHandler) DeleteSchedule(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := h.scheduler.GetSchedule(id); err != nil {
+		h.storageError(c, err, "report schedule")
+		return
+	}
+	if err := h.scheduler.DeleteSchedule(id); err != nil {
+		h.storageError(c, err, "report schedule")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Report schedule deleted"})
+}
+
+// RunSchedule generates a schedule's reports immediately
+// @Summary Run report schedule now
+// @Description Generates the schedule's reports immediately, stores the artifact and delivers it to the configured channels
+// @Tags Reports
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Success 200 {object} reports.Artifact "Generated report metadata"
+// @Failure 404 {object} map[string]string "Schedule not found"
+// @Failure 409 {object} map[string]string "Schedule is already running"
+// @Failure 500 {object} map[string]string "Report generation failed"
+// @Security BearerAuth
+// @Router /api/v1/reports/schedules/{id}/run [post]
+func (h *ReportsHandler) RunSchedule(c *gin.Context) {
+	artifact, err := h.scheduler.RunSchedule(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, reports.ErrScheduleRunning) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.storageError(c, err, "report schedule")
+		return
+	}
+	c.JSON(http.StatusOK, artifact)
+}
+
+// ListArtifacts returns generated reports
+// @Summary List generated reports
+// @Description Lists stored reports newest first, without their content
+// @Tags Reports
+// @Produce json
+// @Param schedule query string false "Only reports generated by this schedule ID"
+// @Param limit query int false "Maximum number of reports"
+// @Success 200 {array} reports.Artifact "Generated reports"
+// @Failure 400 {object} map[string]string "Bad request - invalid limit"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/reports/artifacts [get]
+func (h *ReportsHandler) ListArtifacts(c *gin.Context) {
+	artifacts, err := h.scheduler.ListArtifacts(c.Query("schedule"))
+	if err != nil {
+		h.storageError(c, err, "report")
+		return
+	}
+	if l := c.Query("limit"); l != "" {
+		limit, err := strconv.Atoi(l)
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		if limit < len(artifacts) {
+			artifacts = artifacts[:limit]
+		}
+	}
+	c.JSON(http.StatusOK, artifacts)
+}
+
+// DownloadArtifact serves the content of a generated report
+// @Summary Download generated report
+// @Description Downloads a stored report as JSON, HTML or PDF
+// @Tags Reports
+// @Produce json
+// @Produce html
+// @Produce application/pdf
+// @Param id path string true "Report ID"
+// @Success 200 {file} file "Report content"
+// @Failure 404 {object} map[string]string "Report not found"
+// @Failure 410 {object} map[string]string "Report content removed by object storage retention"
+// @Security BearerAuth
+// @Router /api/v1/reports/artifacts/{id}/download [get]
+func (h *ReportsHandler) DownloadArtifact(c *gin.Context) {
+	artifact, err := h.scheduler.ReadArtifact(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, reports.ErrArtifactExpired) {
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.storageError(c, err, "report")
+		return
+	}
+	c.Header("Content-Disposition", "attachment; filename=\""+artifact.Filename+"\"")
+	c.Data(http.StatusOK, artifact.ContentType, artifact.Content)
+}
+
+// DeleteArtifact deletes a generated report
+// @Summary Delete generated report
+// @Description Deletes a stored report
+// @Tags Reports
+// @Produce json
+// @Param id path string true "Report ID"
+// @Success 200 {object} map[string]string "Report deleted"
+// @Failure 404 {object} map[string]string "Report not found"
+// @Security BearerAuth
+// @Router /api/v1/reports/artifacts/{id} [delete]
+func (h *ReportsHandler) DeleteArtifact(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := h.scheduler.GetArtifact(id); err != nil {
+		h.storageError(c, err, "report")
+		return
+	}
+	if err := h.scheduler.DeleteArtifact(id); err != nil {
+		h.storageError(c, err, "report")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Report deleted"})
+}
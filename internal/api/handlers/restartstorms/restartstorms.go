@@ -0,0 +1,98 @@
+package restartstorms
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+	"github.com/Facets-cloud/kube-dash/internal/restartstorms"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RestartStormsHandler serves the restart storm incidents of a cluster
+type RestartStormsHandler struct {
+	detector   *restartstorms.Detector
+	store      *storage.KubeConfigStore
+	logger     *logger.Logger
+	sseHandler *utils.SSEHandler
+}
+
+// NewRestartStormsHandler creates a new restart storms handler
+func NewRestartStormsHandler(detector *restartstorms.Detector, store *storage.KubeConfigStore, log *logger.Logger) *RestartStormsHandler {
+	return &RestartStormsHandler{
+		detector:   detector,
+		store:      store,
+		logger:     log,
+		sseHandler: utils.NewSSEHandler(log),
+	}
+}
+
+// GetRestartStorms returns the restart storm incidents of a cluster
+// @Summary Get restart storms
+// @Description Returns current restart storms of a cluster: namespaces or nodes where many containers restarted within the detection window, with the affected containers, termination reasons and likely causes (node reboots and readiness changes, ConfigMap and Secret edits, workload rollouts). The first request adds the cluster to background analysis; recently resolved incidents are included after the active ones. Streams updates as Server-Sent Events when requested with Accept text/event-stream.
+// @Tags Cluster
+// @Produce json
+// @Produce text/event-stream
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Success 200 {object} restartstorms.Report "Restart storm incidents"
+// @Failure 400 {object} map[string]string "Bad request - missing config"
+// @Failure 404 {object} map[string]string "Kubeconfig not found"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/restart-storms [get]
+func (h *RestartStormsHandler) GetRestartStorms(c *gin.Context) {
+	configID := c.Query("config")
+	cluster := c.Query("cluster")
+	if configID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "config parameter is required"})
+		return
+	}
+	if _, err := h.store.GetKubeConfig(configID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "kubeconfig not found"})
+		return
+	}
+
+	h.detector.Track(configID, cluster)
+	if !h.detector.Analyzed(configID, cluster) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		if err := h.detector.Analyze(ctx, configID, cluster); err != nil {
+			// The error is reported in the response; the background analysis keeps retrying
+			h.logger.WithError(err).WithField("config", configID).WithField("cluster", cluster).Warn("Failed to analyze restart storms")
+		}
+		cancel()
+	}
+
+	report := h.detector.Report(configID, cluster)
+	if c.GetHeader("Accept") == "text/event-stream" {
+		h.sseHandler.SendSSEResponseWithUpdates(c, report, func() (interface{}, error) {
+			return h.detector.Report(configID, cluster), nil
+		})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// StopRestartStormAnalysis removes a cluster from background restart storm analysis
+// @Summary Stop restart storm analysis
+// @Description Stops analyzing a cluster for restart storms in the background and forgets its incidents. Requesting its restart storms again resumes the analysis.
+// @Tags Cluster
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Success 204 "Analysis stopped"
+// @Failure 400 {object} map[string]string "Bad request - missing config"
+// @Security BearerAuth
+// @Router /api/v1/restart-storms [delete]
+func (h *RestartStormsHandler) StopRestartStormAnalysis(c *gin.Context) {
+	configID := c.Query("config")
+	if configID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "config parameter is required"})
+		return
+	}
+	h.detector.Untrack(configID, c.Query("cluster"))
+	c.Status(http.StatusNoContent)
+}
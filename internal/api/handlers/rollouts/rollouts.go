@@ -0,0 +1,155 @@
+package rollouts
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/rollouts"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultWindow = 30 * 24 * time.Hour
+
+// RolloutsHandler serves deployment rollout history and delivery analytics
+type RolloutsHandler struct {
+	tracker *rollouts.Tracker
+	store   *storage.KubeConfigStore
+	logger  *logger.Logger
+}
+
+// RolloutAnalyticsResponse is the rollout analytics for a cluster over a window
+type RolloutAnalyticsResponse struct {
+	rollouts.Analytics
+	Tracking *rollouts.TrackedCluster `json:"tracking"`
+}
+
+// NewRolloutsHandler creates a new rollouts handler
+func NewRolloutsHandler(tracker *rollouts.Tracker, store *storage.KubeConfigStore, log *logger.Logger) *RolloutsHandler {
+	return &RolloutsHandler{
+		tracker: tracker,
+		store:   store,
+		logger:  log,
+	}
+}
+
+// parseWindow parses a window such as 30d, 2w or 12h
+func parseWindow(value string) (time.Duration, error) {
+	if value == "" {
+		return defaultWindow, nil
+	}
+	unit := value[len(value)-1]
+	if unit == 'd' || unit == 'w' {
+		n, err := strconv.Atoi(strings.TrimSpace(value[:len(value)-1]))
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid window %q", value)
+		}
+		days := n
+		if unit == 'w' {
+			days *= 7
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid window %q", value)
+	}
+	return d, nil
+}
+
+// observe registers the cluster for tracking and refreshes its rollouts
+func (h *RolloutsHandler) observe(c *gin.Context) (string, string, bool) {
+	configID := c.Query("config")
+	cluster := c.Query("cluster")
+	if configID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "config parameter is required"})
+		return "", "", false
+	}
+	if _, err := h.store.GetKubeConfig(configID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "kubeconfig not found"})
+		return "", "", false
+	}
+	h.tracker.Track(configID, cluster)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+	if err := h.tracker.Observe(ctx, configID, cluster); err != nil {
+		// Recorded history is still served when the cluster cannot be reached
+		h.logger.WithError(err).WithField("config", configID).WithField("cluster", cluster).Warn("Failed to observe rollouts")
+	}
+	return configID, cluster, true
+}
+
+// GetRolloutAnalytics returns deploy frequency, rollout duration and failure rates per deployment
+// @Summary Get rollout analytics
+// @Description Reports per-deployment deploy frequency, average/median/max rollout duration and change failure rate (failed or rolled back rollouts) over a window. Rollouts are derived from ReplicaSet creation and deployment Progressing conditions; querying a cluster starts tracking it in the background so history outlives the deployment's revision history limit.
+// @Tags Workloads
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param namespace query string false "Only include deployments in this namespace"
+// @Param window query string false "Analysis window such as 30d, 2w or 12h" default(30d)
+// @Success 200 {object} RolloutAnalyticsResponse "Rollout analytics"
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
+// @Failure 404 {object} map[string]string "Kubeconfig not found"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/analytics/rollouts [get]
+func (h *RolloutsHandler) GetRolloutAnalytics(c *gin.Context) {
+	window, err := parseWindow(c.Query("window"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	configID, cluster, ok := h.observe(c)
+	if !ok {
+		return
+	}
+	until := time.Now()
+	since := until.Add(-window)
+	list := h.tracker.List(rollouts.Query{ConfigID: configID, Cluster: cluster, Namespace: c.Query("namespace"), Since: since})
+	c.JSON(http.StatusOK, RolloutAnalyticsResponse{
+		Analytics: rollouts.Analyze(list, since, until),
+		Tracking:  h.tracker.TrackedCluster(configID, cluster),
+	})
+}
+
+// GetDeploymentRollouts returns the recorded rollouts of one deployment
+// @Summary Get deployment rollout history
+// @Description Lists the recorded rollouts of a deployment, newest first, with start and completion times, status and whether the revision was a rollback or was rolled back
+// @Tags Workloads
+// @Produce json
+// @Param namespace path string true "Namespace name"
+// @Param name path string true "Deployment name"
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param window query string false "Only include rollouts started within this window, such as 30d" default(30d)
+// @Success 200 {array} rollouts.Rollout "Rollouts"
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
+// @Failure 404 {object} map[string]string "Kubeconfig not found"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/deployments/{namespace}/{name}/rollouts [get]
+func (h *RolloutsHandler) GetDeploymentRollouts(c *gin.Context) {
+	window, err := parseWindow(c.Query("window"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	configID, cluster, ok := h.observe(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, h.tracker.List(rollouts.Query{
+		ConfigID:   configID,
+		Cluster:    cluster,
+		Namespace:  c.Param("namespace"),
+		Deployment: c.Param("name"),
+		Since:      time.Now().Add(-window),
+	}))
+}
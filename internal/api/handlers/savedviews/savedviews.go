@@ -0,0 +1,252 @@
+package savedviews
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Facets-cloud/kube-dash/internal/apitokens"
+	"github.com/Facets-cloud/kube-dash/internal/savedviews"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SavedViewsHandler manages saved list views and applies them on list requests
+type SavedViewsHandler struct {
+	views  *savedviews.Store
+	logger *logger.Logger
+}
+
+// NewSavedViewsHandler creates a new saved views handler
+func NewSavedViewsHandler(views *savedviews.Store, log *logger.Logger) *SavedViewsHandler {
+	return &SavedViewsHandler{
+		views:  views,
+		logger: log,
+	}
+}
+
+func (h *SavedViewsHandler) viewError(c *gin.Context, err error) {
+	if errors.Is(err, storage.ErrDocumentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "saved view not found"})
+		return
+	}
+	h.logger.WithError(err).Error("Saved view operation failed")
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+// load returns a view the caller may apply. Unshared views owned by someone else are
+// hidden from callers authenticated with an API token.
+func (h *SavedViewsHandler) load(c *gin.Context, id string) (*savedviews.View, error) {
+	v, err := h.views.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if owner, authenticated := apitokens.RequestOwner(c); authenticated && !v.VisibleTo(owner) {
+		return nil, storage.ErrDocumentNotFound
+	}
+	return v, nil
+}
+
+// loadOwned returns a view the caller may change. Callers authenticated with an API token
+// may only change their own views, even ones shared with them.
+func (h *SavedViewsHandler) loadOwned(c *gin.Context, id string) (*savedviews.View, bool) {
+	v, err := h.load(c, id)
+	if err != nil {
+		h.viewError(c, err)
+		return nil, false
+	}
+	if owner, authenticated := apitokens.RequestOwner(c); authenticated && v.Owner != owner {
+		c.JSON(http.StatusForbidden, gin.H{"error": "only the owner can change a saved view"})
+		return nil, false
+	}
+	return v, true
+}
+
+// listKind returns the list endpoint a request is for, e.g. pods for /api/v1/pods
+func listKind(c *gin.Context) string {
+	path := strings.TrimPrefix(c.FullPath(), "/api/v1/")
+	if i := strings.Index(path, "/"); i >= 0 {
+		path = path[:i]
+	}
+	return path
+}
+
+// ResolveView expands the view query parameter of a list request into the namespaces,
+// labelSelector, sort and fields parameters. Parameters given explicitly on the request win
+// over the view's, so a client can narrow or re-sort a view without saving a new one.
+func (h *SavedViewsHandler) ResolveView(c *gin.Context) {
+	id := c.Query("view")
+	if id == "" {
+		c.Next()
+		return
+	}
+	v, err := h.load(c, id)
+	if err != nil {
+		h.viewError(c, err)
+		c.Abort()
+		return
+	}
+	if kind := listKind(c); kind != v.Kind {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "saved view is for " + v.Kind + " lists, not " + kind})
+		c.Abort()
+		return
+	}
+	if v.ConfigID != "" && (v.ConfigID != c.Query("config") || v.Cluster != c.Query("cluster")) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "saved view belongs to a different cluster"})
+		c.Abort()
+		return
+	}
+
+	query := c.Request.URL.Query()
+	query.Del("view")
+	setDefault := func(key, value string) {
+		if value != "" && query.Get(key) == "" {
+			query.Set(key, value)
+		}
+	}
+	if query.Get("namespace") == "" && query.Get("namespaceGroup") == "" {
+		setDefault("namespaces", strings.Join(v.Namespaces, ","))
+	}
+	setDefault("labelSelector", v.LabelSelector)
+	setDefault("sort", v.Sort)
+	setDefault("fields", strings.Join(v.Columns, ","))
+	c.Request.URL.RawQuery = query.Encode()
+	c.Next()
+}
+
+// ListSavedViews returns saved views
+// @Summary List saved views
+// @Description Lists saved list views visible to the caller: its own, shared ones and unowned ones, optionally only those for one list endpoint or config. Pass a view's ID as the view parameter of its list endpoint to apply it.
+// @Tags Saved Views
+// @Produce json
+// @Param owner query string false "Only the owner's and shared views"
+// @Param kind query string false "Only views for this list endpoint, e.g. pods"
+// @Param config query string false "Only views for this kubeconfig ID or not bound to one"
+// @Success 200 {array} savedviews.View "Saved views"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/saved-views [get]
+func (h *SavedViewsHandler) ListSavedViews(c *gin.Context) {
+	owner, _ := apitokens.RequestOwner(c)
+	list, err := h.views.List(savedviews.Filter{Owner: owner, Kind: strings.ToLower(c.Query("kind")), ConfigID: c.Query("config")})
+	if err != nil {
+		h.viewError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// GetSavedView returns a single saved view
+// @Summary Get saved view
+// @Description Returns a saved list view by ID
+// @Tags Saved Views
+// @Produce json
+// @Param id path string true "Saved view ID"
+// @Success 200 {object} savedviews.View "Saved view"
+// @Failure 404 {object} map[string]string "Saved view not found"
+// @Security BearerAuth
+// @Router /api/v1/saved-views/{id} [get]
+func (h *SavedViewsHandler) GetSavedView(c *gin.Context) {
+	v, err := h.load(c, c.Param("id"))
+	if err != nil {
+		h.viewError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, v)
+}
+
+// CreateSavedView saves a new view
+// @Summary Create saved view
+// @Description Saves a list view: the list endpoint it is for, and optionally its namespaces, label selector, sort and columns. Callers using an API token own the views they create; set shared to let others apply them. Views without an owner are shared.
+// @Tags Saved Views
+// @Accept json
+// @Produce json
+// @Param view body savedviews.View true "Saved view"
+// @Success 201 {object} savedviews.View "Created saved view"
+// @Failure 400 {object} map[string]string "Bad request - invalid view"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/saved-views [post]
+func (h *SavedViewsHandler) CreateSavedView(c *gin.Context) {
+	var v savedviews.View
+	if err := c.ShouldBindJSON(&v); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	v.ID = ""
+	if owner, authenticated := apitokens.RequestOwner(c); authenticated {
+		v.Owner = owner
+	}
+	if err := v.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.views.Save(&v); err != nil {
+		h.viewError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, v)
+}
+
+// UpdateSavedView replaces a saved view
+// @Summary Update saved view
+// @Description Replaces a saved view, keeping its ID, owner and creation time. Callers using an API token may only update their own views.
+// @Tags Saved Views
+// @Accept json
+// @Produce json
+// @Param id path string true "Saved view ID"
+// @Param view body savedviews.View true "Saved view"
+// @Success 200 {object} savedviews.View "Updated saved view"
+// @Failure 400 {object} map[string]string "Bad request - invalid view"
+// @Failure 403 {object} map[string]string "Caller does not own the view"
+// @Failure 404 {object} map[string]string "Saved view not found"
+// @Security BearerAuth
+// @Router /api/v1/saved-views/{id} [put]
+func (h *SavedViewsHandler) UpdateSavedView(c *gin.Context) {
+	existing, ok := h.loadOwned(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	var v savedviews.View
+	if err := c.ShouldBindJSON(&v); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	v.ID = existing.ID
+	v.Owner = existing.Owner
+	v.CreatedAt = existing.CreatedAt
+	if err := v.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.views.Save(&v); err != nil {
+		h.viewError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, v)
+}
+
+// DeleteSavedView deletes a saved view
+// @Summary Delete saved view
+// @Description Deletes a saved view. Callers using an API token may only delete their own views.
+// @Tags Saved Views
+// @Produce json
+// @Param id path string true "Saved view ID"
+// @Success 200 {object} map[string]string "Saved view deleted"
+// @Failure 403 {object} map[string]string "Caller does not own the view"
+// @Failure 404 {object} map[string]string "Saved view not found"
+// @Security BearerAuth
+// @Router /api/v1/saved-views/{id} [delete]
+func (h *SavedViewsHandler) DeleteSavedView(c *gin.Context) {
+	v, ok := h.loadOwned(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	if err := h.views.Delete(v.ID); err != nil {
+		h.viewError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Saved view deleted"})
+}
@@ -0,0 +1,267 @@
+package scaleschedules
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/scaleschedules"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ScaleSchedulesHandler serves time-based deployment scaling schedules and their run history
+type ScaleSchedulesHandler struct {
+	scheduler *scaleschedules.Scheduler
+	store     *storage.KubeConfigStore
+	logger    *logger.Logger
+}
+
+// ScheduleRequest is the body of a schedule create or update
+type ScheduleRequest struct {
+	Namespace       string                  `json:"namespace"`
+	Deployment      string                  `json:"deployment"`
+	Timezone        string                  `json:"timezone"`
+	Windows         []scaleschedules.Window `json:"windows"`
+	DefaultReplicas int32                   `json:"defaultReplicas"`
+	Enabled         bool                    `json:"enabled"`
+}
+
+// ScheduleResponse is a stored schedule with the autoscalers it conflicts with
+type ScheduleResponse struct {
+	scaleschedules.Status
+	Conflicts []scaleschedules.Conflict `json:"conflicts,omitempty"`
+}
+
+// NewScaleSchedulesHandler creates a new scale schedules handler
+func NewScaleSchedulesHandler(scheduler *scaleschedules.Scheduler, store *storage.KubeConfigStore, log *logger.Logger) *ScaleSchedulesHandler {
+	return &ScaleSchedulesHandler{
+		scheduler: scheduler,
+		store:     store,
+		logger:    log,
+	}
+}
+
+// cluster validates the config and cluster query parameters
+func (h *ScaleSchedulesHandler) cluster(c *gin.Context) (string, string, bool) {
+	configID := c.Query("config")
+	cluster := c.Query("cluster")
+	if configID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "config parameter is required"})
+		return "", "", false
+	}
+	if _, err := h.store.GetKubeConfig(configID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "kubeconfig not found"})
+		return "", "", false
+	}
+	return configID, cluster, true
+}
+
+// schedule returns the schedule named in the path if it belongs to the requested cluster
+func (h *ScaleSchedulesHandler) schedule(c *gin.Context) (scaleschedules.Schedule, bool) {
+	configID, cluster, ok := h.cluster(c)
+	if !ok {
+		return scaleschedules.Schedule{}, false
+	}
+	sched, err := h.scheduler.Get(c.Param("id"))
+	if err != nil || sched.ConfigID != configID || sched.Cluster != cluster {
+		c.JSON(http.StatusNotFound, gin.H{"error": "scale schedule not found"})
+		return scaleschedules.Schedule{}, false
+	}
+	return sched, true
+}
+
+// save stores a schedule and responds with it and its conflicts
+func (h *ScaleSchedulesHandler) save(c *gin.Context, sched scaleschedules.Schedule, status int) {
+	saved, err := h.scheduler.Save(sched)
+	if err != nil {
+		switch {
+		case errors.Is(err, scaleschedules.ErrScheduleExists):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, scaleschedules.ErrScheduleNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+	conflicts, err := h.scheduler.Conflicts(ctx, saved)
+	if err != nil {
+		// The schedule is stored; conflicts are checked again on every run
+		h.logger.WithError(err).WithField("schedule", saved.ID).Warn("Failed to check scale schedule conflicts")
+	}
+	c.JSON(status, ScheduleResponse{Status: saved.Status(time.Now()), Conflicts: conflicts})
+}
+
+// GetSchedules lists the scale schedules of a cluster
+// @Summary List scale schedules
+// @Description Lists the time-based scaling schedules of a cluster with each schedule's current target replicas and when the target next changes
+// @Tags Workloads
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Success 200 {array} scaleschedules.Status "Scale schedules"
+// @Failure 400 {object} map[string]string "Bad request - missing parameters"
+// @Failure 404 {object} map[string]string "Kubeconfig not found"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/scale-schedules [get]
+func (h *ScaleSchedulesHandler) GetSchedules(c *gin.Context) {
+	configID, cluster, ok := h.cluster(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, h.scheduler.List(configID, cluster))
+}
+
+// CreateSchedule stores a new scale schedule for a deployment
+// @Summary Create scale schedule
+// @Description Stores time-based replica rules for a deployment, e.g. 5 replicas on weekdays from 09:00 to 18:00 and 1 otherwise. The first matching window sets the replicas; windows whose end is before their start run past midnight. When enabled, the scheduler sets the replicas each time the target changes, so manual scaling in between lasts until the next change. Runs are skipped while a HorizontalPodAutoscaler scales the deployment; such conflicts are returned. A deployment can have only one schedule.
+// @Tags Workloads
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param body body ScheduleRequest true "Schedule"
+// @Success 201 {object} ScheduleResponse "Stored schedule"
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
+// @Failure 404 {object} map[string]string "Kubeconfig not found"
+// @Failure 409 {object} map[string]string "The deployment already has a schedule"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/scale-schedules [post]
+func (h *ScaleSchedulesHandler) CreateSchedule(c *gin.Context) {
+	configID, cluster, ok := h.cluster(c)
+	if !ok {
+		return
+	}
+	var req ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.save(c, scaleschedules.Schedule{
+		ConfigID:        configID,
+		Cluster:         cluster,
+		Namespace:       req.Namespace,
+		Deployment:      req.Deployment,
+		Timezone:        req.Timezone,
+		Windows:         req.Windows,
+		DefaultReplicas: req.DefaultReplicas,
+		Enabled:         req.Enabled,
+	}, http.StatusCreated)
+}
+
+// UpdateSchedule replaces the rules of a scale schedule
+// @Summary Update scale schedule
+// @Description Replaces the rules of a scale schedule. The scheduler applies the target again on its next evaluation.
+// @Tags Workloads
+// @Accept json
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param body body ScheduleRequest true "Schedule"
+// @Success 200 {object} ScheduleResponse "Stored schedule"
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
+// @Failure 404 {object} map[string]string "Schedule not found"
+// @Failure 409 {object} map[string]string "The deployment already has a schedule"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/scale-schedules/{id} [put]
+func (h *ScaleSchedulesHandler) UpdateSchedule(c *gin.Context) {
+	sched, ok := h.schedule(c)
+	if !ok {
+		return
+	}
+	var req ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sched.Namespace = req.Namespace
+	sched.Deployment = req.Deployment
+	sched.Timezone = req.Timezone
+	sched.Windows = req.Windows
+	sched.DefaultReplicas = req.DefaultReplicas
+	sched.Enabled = req.Enabled
+	h.save(c, sched, http.StatusOK)
+}
+
+// DeleteSchedule removes a scale schedule
+// @Summary Delete scale schedule
+// @Description Removes a scale schedule and its run history. The deployment keeps its current replicas.
+// @Tags Workloads
+// @Param id path string true "Schedule ID"
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Success 204 "Schedule deleted"
+// @Failure 404 {object} map[string]string "Schedule not found"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/scale-schedules/{id} [delete]
+func (h *ScaleSchedulesHandler) DeleteSchedule(c *gin.Context) {
+	sched, ok := h.schedule(c)
+	if !ok {
+		return
+	}
+	h.scheduler.Delete(sched.ID)
+	c.Status(http.StatusNoContent)
+}
+
+// RunSchedule applies a scale schedule's current target right away
+// @Summary Run scale schedule
+// @Description Sets the deployment to the schedule's current target now, whether or not the schedule is enabled, and records the run. The run is skipped while a HorizontalPodAutoscaler scales the deployment.
+// @Tags Workloads
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Success 200 {object} scaleschedules.Run "Run record"
+// @Failure 404 {object} map[string]string "Schedule not found"
+// @Failure 500 {object} map[string]string "Scaling failed"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/scale-schedules/{id}/run [post]
+func (h *ScaleSchedulesHandler) RunSchedule(c *gin.Context) {
+	sched, ok := h.schedule(c)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+	run, err := h.scheduler.Run(ctx, sched.ID, scaleschedules.TriggerManual)
+	if err != nil {
+		h.logger.WithError(err).WithField("schedule", sched.ID).Error("Scale schedule run failed")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "run": run})
+		return
+	}
+	c.JSON(http.StatusOK, run)
+}
+
+// GetScheduleRuns returns the run history of a scale schedule
+// @Summary List scale schedule runs
+// @Description Lists the scheduled and manual runs of a scale schedule, newest first, with the replicas before and after and any conflicting autoscalers
+// @Tags Workloads
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Success 200 {array} scaleschedules.Run "Run history"
+// @Failure 404 {object} map[string]string "Schedule not found"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/scale-schedules/{id}/runs [get]
+func (h *ScaleSchedulesHandler) GetScheduleRuns(c *gin.Context) {
+	sched, ok := h.schedule(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, h.scheduler.Runs(sched.ID))
+}
@@ -0,0 +1,366 @@
+package security
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/internal/tracing"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Pod Security Standards levels
+const (
+	levelBaseline   = "baseline"
+	levelRestricted = "restricted"
+)
+
+// PSA namespace labels
+const (
+	psaEnforceLabel        = "pod-security.kubernetes.io/enforce"
+	psaEnforceVersionLabel = "pod-security.kubernetes.io/enforce-version"
+	psaAuditLabel          = "pod-security.kubernetes.io/audit"
+	psaWarnLabel           = "pod-security.kubernetes.io/warn"
+)
+
+// baselineCapabilities may be added without violating the baseline level
+var baselineCapabilities = map[v1.Capability]bool{
+	"AUDIT_WRITE": true, "CHOWN": true, "DAC_OVERRIDE": true, "FOWNER": true, "FSETID": true, "KILL": true, "MKNOD": true,
+	"NET_BIND_SERVICE": true, "SETFCAP": true, "SETGID": true, "SETPCAP": true, "SETUID": true, "SYS_CHROOT": true,
+}
+
+// safeSysctls may be set without violating the baseline level
+var safeSysctls = map[string]bool{
+	"kernel.shm_rmid_forced": true, "net.ipv4.ip_local_port_range": true, "net.ipv4.ip_unprivileged_port_start": true,
+	"net.ipv4.tcp_syncookies": true, "net.ipv4.ping_group_range": true, "net.ipv4.ip_local_reserved_ports": true,
+	"net.ipv4.tcp_keepalive_time": true, "net.ipv4.tcp_fin_timeout": true, "net.ipv4.tcp_keepalive_intvl": true,
+	"net.ipv4.tcp_keepalive_probes": true,
+}
+
+// PodSecurityViolation is one failed Pod Security Standards check
+type PodSecurityViolation struct {
+	Pod          string `json:"pod"`
+	WorkloadKind string `json:"workloadKind"`
+	WorkloadName string `json:"workloadName"`
+	Container    string `json:"container,omitempty"`
+	Check        string `json:"check"`
+	Level        string `json:"level"`
+	Message      string `json:"message"`
+}
+
+// NamespacePodSecurityReport summarizes violations for one namespace against its PSA labels
+type NamespacePodSecurityReport struct {
+	Namespace            string                 `json:"namespace"`
+	Enforce              string                 `json:"enforce,omitempty"`
+	EnforceVersion       string                 `json:"enforceVersion,omitempty"`
+	Audit                string                 `json:"audit,omitempty"`
+	Warn                 string                 `json:"warn,omitempty"`
+	PodsEvaluated        int                    `json:"podsEvaluated"`
+	BaselineViolations   int                    `json:"baselineViolations"`
+	RestrictedViolations int                    `json:"restrictedViolations"`
+	CompliantLevel       string                 `json:"compliantLevel"` // highest level every pod satisfies: restricted, baseline or privileged
+	Violations           []PodSecurityViolation `json:"violations"`
+}
+
+// PodSecurityHandler audits workloads against the Pod Security Standards
+type PodSecurityHandler struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	logger        *logger.Logger
+	tracingHelper *tracing.TracingHelper
+}
+
+// NewPodSecurityHandler creates a new Pod Security audit handler
+func NewPodSecurityHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger) *PodSecurityHandler {
+	return &PodSecurityHandler{
+		store:         store,
+		clientFactory: clientFactory,
+		logger:        log,
+		tracingHelper: tracing.GetTracingHelper(),
+	}
+}
+
+// getClientAndConfig gets the Kubernetes client for the current request
+func (h *PodSecurityHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
+	configID := c.Query("config")
+	cluster := c.Query("cluster")
+
+	if configID == "" {
+		return nil, fmt.Errorf("config parameter is required")
+	}
+
+	config, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, fmt.Errorf("config not found: %w", err)
+	}
+
+	client, err := h.clientFactory.GetClientForConfig(config, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Kubernetes client: %w", err)
+	}
+
+	return client, nil
+}
+
+// evaluatePodSecurity runs the baseline and restricted checks against a pod spec
+func evaluatePodSecurity(spec *v1.PodSpec) []PodSecurityViolation {
+	var violations []PodSecurityViolation
+	add := func(container, check, level, message string) {
+		violations = append(violations, PodSecurityViolation{Container: container, Check: check, Level: level, Message: message})
+	}
+
+	// Baseline: host namespaces
+	if spec.HostNetwork {
+		add("", "hostNamespaces", levelBaseline, "hostNetwork is true")
+	}
+	if spec.HostPID {
+		add("", "hostNamespaces", levelBaseline, "hostPID is true")
+	}
+	if spec.HostIPC {
+		add("", "hostNamespaces", levelBaseline, "hostIPC is true")
+	}
+
+	// Volumes
+	for _, volume := range spec.Volumes {
+		if volume.HostPath != nil {
+			add("", "hostPathVolumes", levelBaseline, fmt.Sprintf("volume %q uses hostPath %s", volume.Name, volume.HostPath.Path))
+			continue
+		}
+		allowed := volume.ConfigMap != nil || volume.CSI != nil || volume.DownwardAPI != nil || volume.EmptyDir != nil ||
+			volume.Ephemeral != nil || volume.PersistentVolumeClaim != nil || volume.Projected != nil || volume.Secret != nil
+		if !allowed {
+			add("", "restrictedVolumes", levelRestricted, fmt.Sprintf("volume %q uses a volume type not allowed by restricted", volume.Name))
+		}
+	}
+
+	podSC := spec.SecurityContext
+	if podSC == nil {
+		podSC = &v1.PodSecurityContext{}
+	}
+	for _, sysctl := range podSC.Sysctls {
+		if !safeSysctls[sysctl.Name] {
+			add("", "sysctls", levelBaseline, fmt.Sprintf("unsafe sysctl %s", sysctl.Name))
+		}
+	}
+	if podSC.SELinuxOptions != nil && (podSC.SELinuxOptions.User != "" || podSC.SELinuxOptions.Role != "") {
+		add("", "seLinux", levelBaseline, "pod sets a custom SELinux user or role")
+	}
+	if podSC.SeccompProfile != nil && podSC.SeccompProfile.Type == v1.SeccompProfileTypeUnconfined {
+		add("", "seccompProfile", levelBaseline, "pod seccompProfile is Unconfined")
+	}
+
+	containers := append(append([]v1.Container{}, spec.InitContainers...), spec.Containers...)
+	for _, c := range containers {
+		sc := c.SecurityContext
+		if sc == nil {
+			sc = &v1.SecurityContext{}
+		}
+
+		for _, port := range c.Ports {
+			if port.HostPort != 0 {
+				add(c.Name, "hostPorts", levelBaseline, fmt.Sprintf("hostPort %d is used", port.HostPort))
+			}
+		}
+		if sc.Privileged != nil && *sc.Privileged {
+			add(c.Name, "privileged", levelBaseline, "container is privileged")
+		}
+		if sc.ProcMount != nil && *sc.ProcMount == v1.UnmaskedProcMount {
+			add(c.Name, "procMount", levelBaseline, "procMount is Unmasked")
+		}
+		if sc.SELinuxOptions != nil && (sc.SELinuxOptions.User != "" || sc.SELinuxOptions.Role != "") {
+			add(c.Name, "seLinux", levelBaseline, "container sets a custom SELinux user or role")
+		}
+
+		// Capabilities
+		droppedAll := false
+		if sc.Capabilities != nil {
+			for _, capability := range sc.Capabilities.Add {
+				if !baselineCapabilities[capability] {
+					add(c.Name, "capabilities", levelBaseline, fmt.Sprintf("adds capability %s", capability))
+				} else if capability != "NET_BIND_SERVICE" {
+					add(c.Name, "capabilities", levelRestricted, fmt.Sprintf("adds capability %s", capability))
+				}
+			}
+			for _, capability := range sc.Capabilities.Drop {
+				if capability == "ALL" {
+					droppedAll = true
+				}
+			}
+		}
+		if !droppedAll {
+			add(c.Name, "capabilities", levelRestricted, "capabilities do not drop ALL")
+		}
+
+		// Restricted: privilege escalation
+		if sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+			add(c.Name, "allowPrivilegeEscalation", levelRestricted, "allowPrivilegeEscalation is not set to false")
+		}
+
+		// Restricted: running as non-root, container settings override the pod
+		runAsNonRoot := podSC.RunAsNonRoot
+		if sc.RunAsNonRoot != nil {
+			runAsNonRoot = sc.RunAsNonRoot
+		}
+		if runAsNonRoot == nil || !*runAsNonRoot {
+			add(c.Name, "runAsNonRoot", levelRestricted, "runAsNonRoot is not set to true")
+		}
+		runAsUser := podSC.RunAsUser
+		if sc.RunAsUser != nil {
+			runAsUser = sc.RunAsUser
+		}
+		if runAsUser != nil && *runAsUser == 0 {
+			add(c.Name, "runAsUser", levelRestricted, "runAsUser is 0 (root)")
+		}
+
+		// Seccomp: baseline forbids Unconfined, restricted requires an explicit profile
+		seccomp := podSC.SeccompProfile
+		if sc.SeccompProfile != nil {
+			seccomp = sc.SeccompProfile
+			if seccomp.Type == v1.SeccompProfileTypeUnconfined {
+				add(c.Name, "seccompProfile", levelBaseline, "container seccompProfile is Unconfined")
+			}
+		}
+		if seccomp == nil || (seccomp.Type != v1.SeccompProfileTypeRuntimeDefault && seccomp.Type != v1.SeccompProfileTypeLocalhost) {
+			add(c.Name, "seccompProfile", levelRestricted, "seccompProfile is not RuntimeDefault or Localhost")
+		}
+	}
+
+	return violations
+}
+
+// GetPodSecurityAudit evaluates pods against the Pod Security Standards and reports violations per namespace
+// @Summary Pod Security admission audit
+// @Description Evaluates running workloads against the baseline and restricted Pod Security Standards (privileged containers, hostPath, host namespaces, running as root, capabilities, seccomp and more) and reports violations per namespace alongside its current PSA labels
+// @Tags Security
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param namespace query string false "Namespace to audit (empty for all namespaces)"
+// @Success 200 {array} NamespacePodSecurityReport "Pod Security audit per namespace"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/security/pod-security [get]
+func (h *PodSecurityHandler) GetPodSecurityAudit(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for pod security audit")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
+
+	namespace := c.Query("namespace")
+
+	_, listSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "pods", namespace)
+	defer listSpan.End()
+
+	pods, err := client.CoreV1().Pods(namespace).List(c.Request.Context(), metav1.ListOptions{})
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list pods for pod security audit")
+		h.tracingHelper.RecordError(listSpan, err, "Failed to list pods")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	namespaceLabels := map[string]map[string]string{}
+	if namespace != "" {
+		if ns, err := client.CoreV1().Namespaces().Get(c.Request.Context(), namespace, metav1.GetOptions{}); err == nil {
+			namespaceLabels[ns.Name] = ns.Labels
+		}
+	} else if nsList, err := client.CoreV1().Namespaces().List(c.Request.Context(), metav1.ListOptions{}); err == nil {
+		for _, ns := range nsList.Items {
+			namespaceLabels[ns.Name] = ns.Labels
+		}
+	}
+	h.tracingHelper.AddResourceAttributes(listSpan, "", "pods", len(pods.Items))
+	h.tracingHelper.RecordSuccess(listSpan, fmt.Sprintf("Listed %d pods", len(pods.Items)))
+
+	_, evalSpan := h.tracingHelper.StartDataProcessingSpan(ctx, "evaluate-pod-security")
+	defer evalSpan.End()
+
+	out := BuildPodSecurityReports(pods.Items, namespaceLabels)
+	h.tracingHelper.AddResourceAttributes(evalSpan, "", "namespaces", len(out))
+	h.tracingHelper.RecordSuccess(evalSpan, "Pod security evaluation completed")
+
+	c.JSON(http.StatusOK, out)
+}
+
+// BuildPodSecurityReports evaluates pods against the Pod Security Standards and groups the results
+// per namespace; namespaceLabels supplies the PSA labels and namespaces without pods
+func BuildPodSecurityReports(pods []v1.Pod, namespaceLabels map[string]map[string]string) []NamespacePodSecurityReport {
+	reports := map[string]*NamespacePodSecurityReport{}
+	reportFor := func(ns string) *NamespacePodSecurityReport {
+		if r, ok := reports[ns]; ok {
+			return r
+		}
+		labels := namespaceLabels[ns]
+		r := &NamespacePodSecurityReport{
+			Namespace:      ns,
+			Enforce:        labels[psaEnforceLabel],
+			EnforceVersion: labels[psaEnforceVersionLabel],
+			Audit:          labels[psaAuditLabel],
+			Warn:           labels[psaWarnLabel],
+			Violations:     []PodSecurityViolation{},
+		}
+		reports[ns] = r
+		return r
+	}
+	for ns := range namespaceLabels {
+		reportFor(ns)
+	}
+
+	for i := range pods {
+		pod := &pods[i]
+		// Completed pods no longer matter for compliance
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		report := reportFor(pod.Namespace)
+		report.PodsEvaluated++
+
+		kind, name := "Pod", pod.Name
+		if ref := metav1.GetControllerOf(pod); ref != nil {
+			kind, name = ref.Kind, ref.Name
+		}
+		for _, violation := range evaluatePodSecurity(&pod.Spec) {
+			violation.Pod = pod.Name
+			violation.WorkloadKind = kind
+			violation.WorkloadName = name
+			if violation.Level == levelBaseline {
+				report.BaselineViolations++
+			} else {
+				report.RestrictedViolations++
+			}
+			report.Violations = append(report.Violations, violation)
+		}
+	}
+
+	out := make([]NamespacePodSecurityReport, 0, len(reports))
+	for _, report := range reports {
+		switch {
+		case report.BaselineViolations > 0:
+			report.CompliantLevel = "privileged"
+		case report.RestrictedViolations > 0:
+			report.CompliantLevel = levelBaseline
+		default:
+			report.CompliantLevel = levelRestricted
+		}
+		out = append(out, *report)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Namespace < out[j].Namespace })
+	return out
+}
@@ -0,0 +1,453 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/internal/tracing"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Policy engines
+const (
+	engineGatekeeper = "gatekeeper"
+	engineKyverno    = "kyverno"
+)
+
+var (
+	kyvernoClusterPolicyGVR = schema.GroupVersionResource{Group: "kyverno.io", Version: "v1", Resource: "clusterpolicies"}
+	kyvernoPolicyGVR        = schema.GroupVersionResource{Group: "kyverno.io", Version: "v1", Resource: "policies"}
+	policyReportGVR         = schema.GroupVersionResource{Group: "wgpolicyk8s.io", Version: "v1alpha2", Resource: "policyreports"}
+	clusterPolicyReportGVR  = schema.GroupVersionResource{Group: "wgpolicyk8s.io", Version: "v1alpha2", Resource: "clusterpolicyreports"}
+	// Gatekeeper creates one resource per ConstraintTemplate in this group
+	gatekeeperConstraintsGroupVer = schema.GroupVersion{Group: "constraints.gatekeeper.sh", Version: "v1beta1"}
+)
+
+// PolicyViolation is one resource that fails a policy
+type PolicyViolation struct {
+	Engine    string `json:"engine"`
+	Policy    string `json:"policy"`
+	Rule      string `json:"rule,omitempty"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Message   string `json:"message"`
+	Action    string `json:"action,omitempty"`
+	Severity  string `json:"severity,omitempty"`
+}
+
+// PolicySummary reports the state of a single Gatekeeper constraint or Kyverno policy
+type PolicySummary struct {
+	Engine     string `json:"engine"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace,omitempty"`
+	Action     string `json:"action,omitempty"` // enforcementAction (Gatekeeper) or validationFailureAction (Kyverno)
+	Ready      bool   `json:"ready"`
+	Violations int    `json:"violations"`
+}
+
+// NamespacePolicyViolations counts violations in one namespace
+type NamespacePolicyViolations struct {
+	Namespace  string         `json:"namespace"`
+	Violations int            `json:"violations"`
+	ByPolicy   map[string]int `json:"byPolicy"`
+}
+
+// PolicyStatusResponse summarizes policy state across all detected engines
+type PolicyStatusResponse struct {
+	Engines         []string                    `json:"engines"`
+	Policies        []PolicySummary             `json:"policies"`
+	Namespaces      []NamespacePolicyViolations `json:"namespaces"`
+	TotalViolations int                         `json:"totalViolations"`
+}
+
+// PoliciesHandler surfaces Gatekeeper and Kyverno policy state
+type PoliciesHandler struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	logger        *logger.Logger
+	tracingHelper *tracing.TracingHelper
+}
+
+// NewPoliciesHandler creates a new policies handler
+func NewPoliciesHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger) *PoliciesHandler {
+	return &PoliciesHandler{
+		store:         store,
+		clientFactory: clientFactory,
+		logger:        log,
+		tracingHelper: tracing.GetTracingHelper(),
+	}
+}
+
+// getClients gets the typed and dynamic Kubernetes clients for the current request
+func (h *PoliciesHandler) getClients(c *gin.Context) (kubernetes.Interface, dynamic.Interface, error) {
+	configID := c.Query("config")
+	cluster := c.Query("cluster")
+
+	if configID == "" {
+		return nil, nil, fmt.Errorf("config parameter is required")
+	}
+
+	config, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("config not found: %w", err)
+	}
+
+	client, err := h.clientFactory.GetClientForConfig(config, cluster)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get Kubernetes client: %w", err)
+	}
+
+	dynamicClient, err := h.clientFactory.GetDynamicClientForConfig(config, cluster)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get dynamic client: %w", err)
+	}
+
+	return client, dynamicClient, nil
+}
+
+// collectGatekeeper lists every constraint kind and the violations recorded by the audit controller
+func collectGatekeeper(ctx context.Context, client kubernetes.Interface, dynamicClient dynamic.Interface) ([]PolicySummary, []PolicyViolation, bool, error) {
+	resources, err := client.Discovery().ServerResourcesForGroupVersion(gatekeeperConstraintsGroupVer.String())
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil, false, nil
+		}
+		return nil, nil, false, err
+	}
+
+	var policies []PolicySummary
+	var violations []PolicyViolation
+	for _, resource := range resources.APIResources {
+		// Skip subresources such as <kind>/status
+		if strings.Contains(resource.Name, "/") {
+			continue
+		}
+		gvr := gatekeeperConstraintsGroupVer.WithResource(resource.Name)
+		constraints, err := dynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, nil, true, fmt.Errorf("failed to list %s constraints: %w", resource.Kind, err)
+		}
+		for _, constraint := range constraints.Items {
+			action, _, _ := unstructured.NestedString(constraint.Object, "spec", "enforcementAction")
+			if action == "" {
+				action = "deny"
+			}
+			total, _, _ := unstructured.NestedInt64(constraint.Object, "status", "totalViolations")
+			ready := true
+			byPod, _, _ := unstructured.NestedSlice(constraint.Object, "status", "byPod")
+			for _, entry := range byPod {
+				if m, ok := entry.(map[string]interface{}); ok {
+					if enforced, ok := m["enforced"].(bool); ok && !enforced {
+						ready = false
+					}
+				}
+			}
+			policies = append(policies, PolicySummary{
+				Engine:     engineGatekeeper,
+				Kind:       constraint.GetKind(),
+				Name:       constraint.GetName(),
+				Action:     action,
+				Ready:      ready && len(byPod) > 0,
+				Violations: int(total),
+			})
+
+			entries, _, _ := unstructured.NestedSlice(constraint.Object, "status", "violations")
+			for _, entry := range entries {
+				m, ok := entry.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				v := PolicyViolation{Engine: engineGatekeeper, Policy: constraint.GetName(), Action: action}
+				v.Kind, _ = m["kind"].(string)
+				v.Name, _ = m["name"].(string)
+				v.Namespace, _ = m["namespace"].(string)
+				v.Message, _ = m["message"].(string)
+				if entryAction, ok := m["enforcementAction"].(string); ok && entryAction != "" {
+					v.Action = entryAction
+				}
+				violations = append(violations, v)
+			}
+		}
+	}
+	return policies, violations, true, nil
+}
+
+// collectKyverno lists Kyverno policies and the failures recorded in PolicyReports
+func collectKyverno(ctx context.Context, dynamicClient dynamic.Interface) ([]PolicySummary, []PolicyViolation, bool, error) {
+	var policies []PolicySummary
+	installed := false
+	for _, gvr := range []schema.GroupVersionResource{kyvernoClusterPolicyGVR, kyvernoPolicyGVR} {
+		list, err := dynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, nil, true, fmt.Errorf("failed to list kyverno %s: %w", gvr.Resource, err)
+		}
+		installed = true
+		for _, policy := range list.Items {
+			action, _, _ := unstructured.NestedString(policy.Object, "spec", "validationFailureAction")
+			if action == "" {
+				action = "Audit"
+			}
+			ready, found, _ := unstructured.NestedBool(policy.Object, "status", "ready")
+			if !found {
+				// Newer Kyverno releases report readiness through conditions
+				conditions, _, _ := unstructured.NestedSlice(policy.Object, "status", "conditions")
+				for _, condition := range conditions {
+					if m, ok := condition.(map[string]interface{}); ok && m["type"] == "Ready" {
+						ready = m["status"] == "True"
+					}
+				}
+			}
+			policies = append(policies, PolicySummary{
+				Engine:    engineKyverno,
+				Kind:      policy.GetKind(),
+				Name:      policy.GetName(),
+				Namespace: policy.GetNamespace(),
+				Action:    action,
+				Ready:     ready,
+			})
+		}
+	}
+	if !installed {
+		return nil, nil, false, nil
+	}
+
+	var violations []PolicyViolation
+	for _, gvr := range []schema.GroupVersionResource{policyReportGVR, clusterPolicyReportGVR} {
+		reports, err := dynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, nil, true, fmt.Errorf("failed to list %s: %w", gvr.Resource, err)
+		}
+		for _, report := range reports.Items {
+			// Report-level scope is used when results omit their resources
+			scope, _, _ := unstructured.NestedMap(report.Object, "scope")
+			results, _, _ := unstructured.NestedSlice(report.Object, "results")
+			for _, entry := range results {
+				result, ok := entry.(map[string]interface{})
+				if !ok || (result["result"] != "fail" && result["result"] != "error") {
+					continue
+				}
+				base := PolicyViolation{Engine: engineKyverno}
+				base.Policy, _ = result["policy"].(string)
+				base.Rule, _ = result["rule"].(string)
+				base.Message, _ = result["message"].(string)
+				base.Severity, _ = result["severity"].(string)
+
+				resources, _ := result["resources"].([]interface{})
+				if len(resources) == 0 && scope != nil {
+					resources = []interface{}{scope}
+				}
+				for _, res := range resources {
+					m, ok := res.(map[string]interface{})
+					if !ok {
+						continue
+					}
+					v := base
+					v.Kind, _ = m["kind"].(string)
+					v.Name, _ = m["name"].(string)
+					v.Namespace, _ = m["namespace"].(string)
+					if v.Namespace == "" {
+						v.Namespace = report.GetNamespace()
+					}
+					violations = append(violations, v)
+				}
+			}
+		}
+	}
+
+	// Attribute report failures back to their policies
+	counts := map[string]int{}
+	for _, v := range violations {
+		counts[v.Policy]++
+	}
+	for i := range policies {
+		policies[i].Violations = counts[policies[i].Name]
+	}
+	return policies, violations, true, nil
+}
+
+// collectPolicies gathers policies and violations from every installed engine
+func (h *PoliciesHandler) collectPolicies(ctx context.Context, client kubernetes.Interface, dynamicClient dynamic.Interface) ([]string, []PolicySummary, []PolicyViolation, error) {
+	engines := []string{}
+	var policies []PolicySummary
+	var violations []PolicyViolation
+
+	gkPolicies, gkViolations, installed, err := collectGatekeeper(ctx, client, dynamicClient)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if installed {
+		engines = append(engines, engineGatekeeper)
+		policies = append(policies, gkPolicies...)
+		violations = append(violations, gkViolations...)
+	}
+
+	kyPolicies, kyViolations, installed, err := collectKyverno(ctx, dynamicClient)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if installed {
+		engines = append(engines, engineKyverno)
+		policies = append(policies, kyPolicies...)
+		violations = append(violations, kyViolations...)
+	}
+	return engines, policies, violations, nil
+}
+
+// GetPolicyStatus summarizes Gatekeeper and Kyverno violations per policy and per namespace
+// @Summary Get policy engine status
+// @Description Detects OPA Gatekeeper constraints and Kyverno policies/PolicyReports and summarizes violations per policy and per namespace
+// @Tags Security
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param namespace query string false "Only count violations in this namespace"
+// @Success 200 {object} PolicyStatusResponse "Policy status summary"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/security/policies [get]
+func (h *PoliciesHandler) GetPolicyStatus(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	client, dynamicClient, err := h.getClients(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for policy status")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
+
+	_, listSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "policies", "")
+	defer listSpan.End()
+
+	engines, policies, violations, err := h.collectPolicies(c.Request.Context(), client, dynamicClient)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to collect policy status")
+		h.tracingHelper.RecordError(listSpan, err, "Failed to collect policies")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.AddResourceAttributes(listSpan, "", "policies", len(policies))
+	h.tracingHelper.RecordSuccess(listSpan, fmt.Sprintf("Collected %d policies", len(policies)))
+
+	namespace := c.Query("namespace")
+	byNamespace := map[string]*NamespacePolicyViolations{}
+	total := 0
+	for _, v := range violations {
+		if namespace != "" && v.Namespace != namespace {
+			continue
+		}
+		total++
+		ns := byNamespace[v.Namespace]
+		if ns == nil {
+			ns = &NamespacePolicyViolations{Namespace: v.Namespace, ByPolicy: map[string]int{}}
+			byNamespace[v.Namespace] = ns
+		}
+		ns.Violations++
+		ns.ByPolicy[v.Policy]++
+	}
+
+	namespaces := make([]NamespacePolicyViolations, 0, len(byNamespace))
+	for _, ns := range byNamespace {
+		namespaces = append(namespaces, *ns)
+	}
+	sort.Slice(namespaces, func(i, j int) bool { return namespaces[i].Violations > namespaces[j].Violations })
+	sort.Slice(policies, func(i, j int) bool {
+		if policies[i].Violations != policies[j].Violations {
+			return policies[i].Violations > policies[j].Violations
+		}
+		return policies[i].Name < policies[j].Name
+	})
+	if policies == nil {
+		policies = []PolicySummary{}
+	}
+
+	c.JSON(http.StatusOK, PolicyStatusResponse{
+		Engines:         engines,
+		Policies:        policies,
+		Namespaces:      namespaces,
+		TotalViolations: total,
+	})
+}
+
+// GetPolicyViolations lists offending resources, optionally filtered by policy, engine and namespace
+// @Summary Get policy violations
+// @Description Lists resources violating Gatekeeper constraints or Kyverno policies, for drilling down from the policy summary
+// @Tags Security
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param namespace query string false "Filter by resource namespace"
+// @Param policy query string false "Filter by policy or constraint name"
+// @Param engine query string false "Filter by engine (gatekeeper or kyverno)"
+// @Success 200 {array} PolicyViolation "Policy violations"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/security/policies/violations [get]
+func (h *PoliciesHandler) GetPolicyViolations(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	client, dynamicClient, err := h.getClients(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for policy violations")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
+
+	_, listSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "policies", "")
+	defer listSpan.End()
+
+	_, _, violations, err := h.collectPolicies(c.Request.Context(), client, dynamicClient)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to collect policy violations")
+		h.tracingHelper.RecordError(listSpan, err, "Failed to collect policies")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	namespace := c.Query("namespace")
+	policy := c.Query("policy")
+	engine := c.Query("engine")
+	filtered := []PolicyViolation{}
+	for _, v := range violations {
+		if (namespace != "" && v.Namespace != namespace) || (policy != "" && v.Policy != policy) || (engine != "" && v.Engine != engine) {
+			continue
+		}
+		filtered = append(filtered, v)
+	}
+	h.tracingHelper.AddResourceAttributes(listSpan, "", "violations", len(filtered))
+	h.tracingHelper.RecordSuccess(listSpan, fmt.Sprintf("Found %d violations", len(filtered)))
+
+	c.JSON(http.StatusOK, filtered)
+}
@@ -0,0 +1,393 @@
+package security
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/types"
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/internal/tracing"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Sensitive permissions reported by the RBAC audit, in column order
+const (
+	PermissionCreatePods  = "create-pods"
+	PermissionExecPods    = "exec-pods"
+	PermissionReadSecrets = "read-secrets"
+	PermissionEscalate    = "escalate"
+	PermissionBind        = "bind"
+	PermissionImpersonate = "impersonate"
+)
+
+// ClusterScope is the scope of access granted by a ClusterRoleBinding
+const ClusterScope = "*"
+
+// auditPermissions lists the reported permissions in column order
+var auditPermissions = []string{
+	PermissionCreatePods, PermissionExecPods, PermissionReadSecrets,
+	PermissionEscalate, PermissionBind, PermissionImpersonate,
+}
+
+// permissionCheck is one request that grants a sensitive permission when a rule allows it
+type permissionCheck struct {
+	apiGroup string
+	resource string
+	verb     string
+}
+
+// permissionChecks maps each sensitive permission to the requests that grant it
+var permissionChecks = map[string][]permissionCheck{
+	PermissionCreatePods: {{"", "pods", "create"}},
+	PermissionExecPods:   {{"", "pods/exec", "create"}, {"", "pods/exec", "get"}},
+	PermissionReadSecrets: {
+		{"", "secrets", "get"}, {"", "secrets", "list"}, {"", "secrets", "watch"},
+	},
+	PermissionEscalate: {
+		{rbacv1.GroupName, "roles", "escalate"}, {rbacv1.GroupName, "clusterroles", "escalate"},
+	},
+	PermissionBind: {
+		{rbacv1.GroupName, "roles", "bind"}, {rbacv1.GroupName, "clusterroles", "bind"},
+	},
+	PermissionImpersonate: {
+		{"", "users", "impersonate"}, {"", "groups", "impersonate"}, {"", "serviceaccounts", "impersonate"},
+		{"authentication.k8s.io", "userextras", "impersonate"}, {"authentication.k8s.io", "uids", "impersonate"},
+	},
+}
+
+// RBACAuditRow is the sensitive access one subject holds in one scope
+type RBACAuditRow struct {
+	SubjectKind      string          `json:"subjectKind"`
+	SubjectName      string          `json:"subjectName"`
+	SubjectNamespace string          `json:"subjectNamespace,omitempty"`
+	Scope            string          `json:"scope"` // namespace, or * for cluster-wide
+	Permissions      map[string]bool `json:"permissions"`
+	Via              []string        `json:"via"` // Kind/name of the bindings granting the access
+}
+
+// RBACAudit is the sensitive-access matrix for one namespace or the whole cluster
+type RBACAudit struct {
+	Namespace     string         `json:"namespace,omitempty"`
+	GeneratedAt   string         `json:"generatedAt"`
+	GeneratedAtMs int64          `json:"generatedAtMs,omitempty"`
+	Permissions   []string       `json:"permissions"`
+	Rows          []RBACAuditRow `json:"rows"`
+}
+
+// RBACAuditHandler exports who holds sensitive permissions for access reviews
+type RBACAuditHandler struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	logger        *logger.Logger
+	tracingHelper *tracing.TracingHelper
+}
+
+// NewRBACAuditHandler creates a new RBAC audit handler
+func NewRBACAuditHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger) *RBACAuditHandler {
+	return &RBACAuditHandler{
+		store:         store,
+		clientFactory: clientFactory,
+		logger:        log,
+		tracingHelper: tracing.GetTracingHelper(),
+	}
+}
+
+// getClientAndConfig gets the Kubernetes client for the current request
+func (h *RBACAuditHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
+	configID := c.Query("config")
+	cluster := c.Query("cluster")
+
+	if configID == "" {
+		return nil, fmt.Errorf("config parameter is required")
+	}
+
+	config, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, fmt.Errorf("config not found: %w", err)
+	}
+
+	client, err := h.clientFactory.GetClientForConfig(config, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Kubernetes client: %w", err)
+	}
+
+	return client, nil
+}
+
+// ruleAllows reports whether a policy rule allows a request; rules limited to resourceNames
+// count as well, so the audit errs on the side of listing access
+func ruleAllows(rule rbacv1.PolicyRule, check permissionCheck) bool {
+	return matches(rule.APIGroups, check.apiGroup) && matchesResource(rule.Resources, check.resource) && matches(rule.Verbs, check.verb)
+}
+
+// matches reports whether values contain value or the * wildcard
+func matches(values []string, value string) bool {
+	for _, v := range values {
+		if v == rbacv1.ResourceAll || v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesResource is matches with the */subresource wildcard RBAC allows for subresources
+func matchesResource(resources []string, resource string) bool {
+	if matches(resources, resource) {
+		return true
+	}
+	if i := strings.Index(resource, "/"); i >= 0 {
+		return matches(resources, "*"+resource[i:])
+	}
+	return false
+}
+
+// rulePermissions returns the sensitive permissions a set of rules grants
+func rulePermissions(rules []rbacv1.PolicyRule) map[string]bool {
+	granted := map[string]bool{}
+	for _, permission := range auditPermissions {
+		for _, check := range permissionChecks[permission] {
+			for _, rule := range rules {
+				if ruleAllows(rule, check) {
+					granted[permission] = true
+				}
+			}
+		}
+	}
+	return granted
+}
+
+// BuildRBACAudit derives the sensitive-access matrix from role bindings. Role bindings grant
+// access in their namespace and cluster role bindings everywhere; bindings to roles that do
+// not exist or grant nothing sensitive are left out.
+func BuildRBACAudit(namespace string, roles []rbacv1.Role, clusterRoles []rbacv1.ClusterRole, roleBindings []rbacv1.RoleBinding, clusterRoleBindings []rbacv1.ClusterRoleBinding, now time.Time) RBACAudit {
+	roleRules := map[string][]rbacv1.PolicyRule{}
+	for _, role := range roles {
+		roleRules[role.Namespace+"/"+role.Name] = role.Rules
+	}
+	clusterRoleRules := map[string][]rbacv1.PolicyRule{}
+	for _, role := range clusterRoles {
+		clusterRoleRules[role.Name] = role.Rules
+	}
+
+	rows := map[string]*RBACAuditRow{}
+	grant := func(subjects []rbacv1.Subject, scope, bindingNamespace, via string, granted map[string]bool) {
+		if len(granted) == 0 {
+			return
+		}
+		for _, subject := range subjects {
+			subjectNamespace := ""
+			if subject.Kind == rbacv1.ServiceAccountKind {
+				subjectNamespace = subject.Namespace
+				if subjectNamespace == "" {
+					subjectNamespace = bindingNamespace
+				}
+			}
+			key := strings.Join([]string{subject.Kind, subjectNamespace, subject.Name, scope}, "|")
+			row, ok := rows[key]
+			if !ok {
+				row = &RBACAuditRow{
+					SubjectKind:      subject.Kind,
+					SubjectName:      subject.Name,
+					SubjectNamespace: subjectNamespace,
+					Scope:            scope,
+					Permissions:      map[string]bool{},
+					Via:              []string{},
+				}
+				for _, permission := range auditPermissions {
+					row.Permissions[permission] = false
+				}
+				rows[key] = row
+			}
+			for permission := range granted {
+				row.Permissions[permission] = true
+			}
+			row.Via = append(row.Via, via)
+		}
+	}
+
+	for _, binding := range roleBindings {
+		if namespace != "" && binding.Namespace != namespace {
+			continue
+		}
+		var rules []rbacv1.PolicyRule
+		var found bool
+		if binding.RoleRef.Kind == "ClusterRole" {
+			rules, found = clusterRoleRules[binding.RoleRef.Name]
+		} else {
+			rules, found = roleRules[binding.Namespace+"/"+binding.RoleRef.Name]
+		}
+		if !found {
+			continue
+		}
+		grant(binding.Subjects, binding.Namespace, binding.Namespace, "RoleBinding/"+binding.Name, rulePermissions(rules))
+	}
+	for _, binding := range clusterRoleBindings {
+		rules, found := clusterRoleRules[binding.RoleRef.Name]
+		if !found {
+			continue
+		}
+		grant(binding.Subjects, ClusterScope, "", "ClusterRoleBinding/"+binding.Name, rulePermissions(rules))
+	}
+
+	audit := RBACAudit{
+		Namespace:     namespace,
+		GeneratedAt:   types.TimeFormat(now),
+		GeneratedAtMs: types.EpochMillis(now),
+		Permissions:   auditPermissions,
+		Rows:          make([]RBACAuditRow, 0, len(rows)),
+	}
+	for _, row := range rows {
+		sort.Strings(row.Via)
+		audit.Rows = append(audit.Rows, *row)
+	}
+	sort.Slice(audit.Rows, func(i, j int) bool {
+		a, b := audit.Rows[i], audit.Rows[j]
+		if a.Scope != b.Scope {
+			return a.Scope < b.Scope
+		}
+		if a.SubjectKind != b.SubjectKind {
+			return a.SubjectKind < b.SubjectKind
+		}
+		if a.SubjectNamespace != b.SubjectNamespace {
+			return a.SubjectNamespace < b.SubjectNamespace
+		}
+		return a.SubjectName < b.SubjectName
+	})
+	return audit
+}
+
+// RenderRBACAuditCSV writes the audit as one CSV row per subject and scope with a yes/no
+// column per permission
+func RenderRBACAuditCSV(audit RBACAudit) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	header := append([]string{"subject_kind", "subject_name", "subject_namespace", "scope"}, audit.Permissions...)
+	header = append(header, "via")
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	for _, row := range audit.Rows {
+		record := []string{row.SubjectKind, row.SubjectName, row.SubjectNamespace, row.Scope}
+		for _, permission := range audit.Permissions {
+			if row.Permissions[permission] {
+				record = append(record, "yes")
+			} else {
+				record = append(record, "no")
+			}
+		}
+		record = append(record, strings.Join(row.Via, ";"))
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// GetRBACAudit exports who can perform sensitive actions as a JSON or CSV matrix
+// @Summary RBAC access review export
+// @Description Derives from role bindings and cluster role bindings which users, groups and service accounts can create pods, exec into pods, read secrets, escalate or bind roles and impersonate, as one row per subject and scope (a namespace, or * for cluster-wide). With a namespace, only role bindings in that namespace and cluster role bindings are considered. Rules limited to resourceNames are counted as granting access.
+// @Tags Security
+// @Produce json
+// @Produce text/csv
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param namespace query string false "Namespace to audit (empty for all namespaces)"
+// @Param format query string false "json or csv (default json)"
+// @Success 200 {object} RBACAudit "Sensitive access matrix"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/security/rbac-audit [get]
+func (h *RBACAuditHandler) GetRBACAudit(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for RBAC audit")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
+
+	namespace := c.Query("namespace")
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or csv"})
+		return
+	}
+
+	_, listSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "rbac", namespace)
+	defer listSpan.End()
+
+	reqCtx := c.Request.Context()
+	fail := func(err error, what string) {
+		h.logger.WithError(err).Errorf("Failed to list %s for RBAC audit", what)
+		h.tracingHelper.RecordError(listSpan, err, "Failed to list "+what)
+		if utils.IsPermissionError(err) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+	roles, err := client.RbacV1().Roles(namespace).List(reqCtx, metav1.ListOptions{})
+	if err != nil {
+		fail(err, "roles")
+		return
+	}
+	roleBindings, err := client.RbacV1().RoleBindings(namespace).List(reqCtx, metav1.ListOptions{})
+	if err != nil {
+		fail(err, "role bindings")
+		return
+	}
+	clusterRoles, err := client.RbacV1().ClusterRoles().List(reqCtx, metav1.ListOptions{})
+	if err != nil {
+		fail(err, "cluster roles")
+		return
+	}
+	clusterRoleBindings, err := client.RbacV1().ClusterRoleBindings().List(reqCtx, metav1.ListOptions{})
+	if err != nil {
+		fail(err, "cluster role bindings")
+		return
+	}
+	h.tracingHelper.RecordSuccess(listSpan, fmt.Sprintf("Listed %d role bindings and %d cluster role bindings", len(roleBindings.Items), len(clusterRoleBindings.Items)))
+
+	_, buildSpan := h.tracingHelper.StartDataProcessingSpan(ctx, "build-rbac-audit")
+	defer buildSpan.End()
+
+	audit := BuildRBACAudit(namespace, roles.Items, clusterRoles.Items, roleBindings.Items, clusterRoleBindings.Items, time.Now())
+	h.tracingHelper.AddResourceAttributes(buildSpan, namespace, "subjects", len(audit.Rows))
+	h.tracingHelper.RecordSuccess(buildSpan, "RBAC audit built")
+
+	if format == "csv" {
+		content, err := RenderRBACAuditCSV(audit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		scope := namespace
+		if scope == "" {
+			scope = "all-namespaces"
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "rbac-audit-"+scope+".csv"))
+		c.Data(http.StatusOK, "text/csv; charset=utf-8", content)
+		return
+	}
+	c.JSON(http.StatusOK, audit)
+}
@@ -0,0 +1,106 @@
+package security
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBuildRBACAudit(t *testing.T) {
+	roles := []rbacv1.Role{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "debugger", Namespace: "team-a"},
+			Rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"pods/exec"}, Verbs: []string{"create"}},
+				{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"*"}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "viewer", Namespace: "team-a"},
+			Rules:      []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "list"}}},
+		},
+	}
+	clusterRoles := []rbacv1.ClusterRole{
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster-admin"}, Rules: []rbacv1.PolicyRule{{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "secret-reader"}, Rules: []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"list"}}}},
+	}
+	roleBindings := []rbacv1.RoleBinding{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "debuggers", Namespace: "team-a"},
+			RoleRef:    rbacv1.RoleRef{Kind: "Role", Name: "debugger"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "alice"}, {Kind: rbacv1.ServiceAccountKind, Name: "ci"}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "secrets", Namespace: "team-a"},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "secret-reader"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "alice"}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "viewers", Namespace: "team-a"},
+			RoleRef:    rbacv1.RoleRef{Kind: "Role", Name: "viewer"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "bob"}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "debuggers", Namespace: "team-b"},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "carol"}},
+		},
+	}
+	clusterRoleBindings := []rbacv1.ClusterRoleBinding{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "admins"},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.GroupKind, Name: "platform"}},
+		},
+	}
+
+	audit := BuildRBACAudit("team-a", roles, clusterRoles, roleBindings, clusterRoleBindings, time.Now())
+
+	// bob only reads pods and carol is bound in another namespace
+	if len(audit.Rows) != 3 {
+		t.Fatalf("expected 3 rows, got %d: %+v", len(audit.Rows), audit.Rows)
+	}
+	admins := audit.Rows[0]
+	if admins.Scope != ClusterScope || admins.SubjectName != "platform" {
+		t.Fatalf("expected the cluster-wide group first, got %+v", admins)
+	}
+	for _, permission := range auditPermissions {
+		if !admins.Permissions[permission] {
+			t.Errorf("cluster-admin should grant %s", permission)
+		}
+	}
+
+	alice := audit.Rows[2]
+	if alice.SubjectName != "alice" || alice.Scope != "team-a" {
+		t.Fatalf("unexpected row %+v", alice)
+	}
+	if !alice.Permissions[PermissionExecPods] || !alice.Permissions[PermissionReadSecrets] || alice.Permissions[PermissionCreatePods] {
+		t.Errorf("unexpected permissions for alice: %v", alice.Permissions)
+	}
+	if strings.Join(alice.Via, ",") != "RoleBinding/debuggers,RoleBinding/secrets" {
+		t.Errorf("unexpected bindings for alice: %v", alice.Via)
+	}
+
+	ci := audit.Rows[1]
+	if ci.SubjectKind != rbacv1.ServiceAccountKind || ci.SubjectNamespace != "team-a" {
+		t.Errorf("service account should default to the binding namespace, got %+v", ci)
+	}
+
+	content, err := RenderRBACAuditCSV(audit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected header and 3 rows, got %d lines", len(lines))
+	}
+	if lines[0] != "subject_kind,subject_name,subject_namespace,scope,create-pods,exec-pods,read-secrets,escalate,bind,impersonate,via" {
+		t.Errorf("unexpected header %q", lines[0])
+	}
+	if lines[3] != "User,alice,,team-a,no,yes,yes,no,no,no,RoleBinding/debuggers;RoleBinding/secrets" {
+		t.Errorf("unexpected row %q", lines[3])
+	}
+}
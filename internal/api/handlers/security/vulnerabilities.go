@@ -0,0 +1,446 @@
+package security
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/handlers/workloads"
+	"github.com/Facets-cloud/kube-dash/internal/cache"
+	"github.com/Facets-cloud/kube-dash/internal/config"
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/internal/tracing"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// vulnerabilityReportGVR is the Trivy operator's per-container report resource
+var vulnerabilityReportGVR = schema.GroupVersionResource{Group: "aquasecurity.github.io", Version: "v1alpha1", Resource: "vulnerabilityreports"}
+
+// onDemandScanConcurrency bounds how many trivy processes run at once
+const onDemandScanConcurrency = 2
+
+// SeverityCounts holds CVE counts by severity
+type SeverityCounts struct {
+	Critical int `json:"critical"`
+	High     int `json:"high"`
+	Medium   int `json:"medium"`
+	Low      int `json:"low"`
+	Unknown  int `json:"unknown"`
+}
+
+func (s *SeverityCounts) add(o SeverityCounts) {
+	s.Critical += o.Critical
+	s.High += o.High
+	s.Medium += o.Medium
+	s.Low += o.Low
+	s.Unknown += o.Unknown
+}
+
+// ContainerVulnerabilities is the CVE summary for one container image
+type ContainerVulnerabilities struct {
+	Container string         `json:"container"`
+	Image     string         `json:"image"`
+	Counts    SeverityCounts `json:"counts"`
+	UpdatedAt string         `json:"updatedAt,omitempty"`
+}
+
+// WorkloadVulnerabilities groups container summaries by workload
+type WorkloadVulnerabilities struct {
+	Namespace  string                     `json:"namespace"`
+	Kind       string                     `json:"kind"`
+	Name       string                     `json:"name"`
+	Totals     SeverityCounts             `json:"totals"`
+	Containers []ContainerVulnerabilities `json:"containers"`
+}
+
+// ImageScanResult is the cached outcome of an on-demand scan
+type ImageScanResult struct {
+	Image     string         `json:"image"`
+	Status    string         `json:"status"` // "pending", "scanned", "failed" or "not-scanned"
+	Counts    SeverityCounts `json:"counts"`
+	Error     string         `json:"error,omitempty"`
+	ScannedAt time.Time      `json:"scannedAt,omitempty"`
+	PodCount  int            `json:"podCount"`
+}
+
+// VulnerabilitySummaryResponse is returned by the vulnerabilities endpoint
+type VulnerabilitySummaryResponse struct {
+	Source            string                    `json:"source"` // "trivy-operator" or "on-demand"
+	OperatorInstalled bool                      `json:"operatorInstalled"`
+	Totals            SeverityCounts            `json:"totals"`
+	Workloads         []WorkloadVulnerabilities `json:"workloads,omitempty"`
+	Images            []ImageScanResult         `json:"images,omitempty"`
+}
+
+// VulnerabilitiesHandler exposes Trivy vulnerability data
+type VulnerabilitiesHandler struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	logger        *logger.Logger
+	tracingHelper *tracing.TracingHelper
+	cfg           *config.SecurityConfig
+
+	// On-demand scan results keyed by image reference
+	scanCache *cache.MemoryCache
+	pending   sync.Map
+	scanSlots chan struct{}
+}
+
+// NewVulnerabilitiesHandler creates a new vulnerabilities handler
+func NewVulnerabilitiesHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger, cfg *config.SecurityConfig) *VulnerabilitiesHandler {
+	return &VulnerabilitiesHandler{
+		store:         store,
+		clientFactory: clientFactory,
+		logger:        log,
+		tracingHelper: tracing.GetTracingHelper(),
+		cfg:           cfg,
+		scanCache:     cache.NewMemoryCache(),
+		scanSlots:     make(chan struct{}, onDemandScanConcurrency),
+	}
+}
+
+// getClients returns the typed and dynamic clients for the current request
+func (h *VulnerabilitiesHandler) getClients(c *gin.Context) (kubernetes.Interface, dynamic.Interface, error) {
+	configID := c.Query("config")
+	cluster := c.Query("cluster")
+
+	if configID == "" {
+		return nil, nil, fmt.Errorf("config parameter is required")
+	}
+
+	config, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("config not found: %w", err)
+	}
+
+	client, err := h.clientFactory.GetClientForConfig(config, cluster)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get Kubernetes client: %w", err)
+	}
+
+	dynamicClient, err := h.clientFactory.GetDynamicClientForConfig(config, cluster)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get dynamic client: %w", err)
+	}
+
+	return client, dynamicClient, nil
+}
+
+// summarizeVulnerabilityReports groups VulnerabilityReports by the workload labels the operator sets
+func summarizeVulnerabilityReports(reports []unstructured.Unstructured) ([]WorkloadVulnerabilities, SeverityCounts) {
+	type key struct{ namespace, kind, name string }
+	byWorkload := map[key]*WorkloadVulnerabilities{}
+	var totals SeverityCounts
+
+	for _, report := range reports {
+		labels := report.GetLabels()
+		k := key{
+			namespace: labels["trivy-operator.resource.namespace"],
+			kind:      labels["trivy-operator.resource.kind"],
+			name:      labels["trivy-operator.resource.name"],
+		}
+		if k.namespace == "" {
+			k.namespace = report.GetNamespace()
+		}
+
+		counts := SeverityCounts{}
+		summary, _, _ := unstructured.NestedMap(report.Object, "report", "summary")
+		counts.Critical = nestedInt(summary, "criticalCount")
+		counts.High = nestedInt(summary, "highCount")
+		counts.Medium = nestedInt(summary, "mediumCount")
+		counts.Low = nestedInt(summary, "lowCount")
+		counts.Unknown = nestedInt(summary, "unknownCount")
+
+		repository, _, _ := unstructured.NestedString(report.Object, "report", "artifact", "repository")
+		tag, _, _ := unstructured.NestedString(report.Object, "report", "artifact", "tag")
+		registry, _, _ := unstructured.NestedString(report.Object, "report", "registry", "server")
+		image := repository
+		if registry != "" {
+			image = registry + "/" + image
+		}
+		if tag != "" {
+			image += ":" + tag
+		}
+		updatedAt, _, _ := unstructured.NestedString(report.Object, "report", "updateTimestamp")
+
+		workload := byWorkload[k]
+		if workload == nil {
+			workload = &WorkloadVulnerabilities{Namespace: k.namespace, Kind: k.kind, Name: k.name, Containers: []ContainerVulnerabilities{}}
+			byWorkload[k] = workload
+		}
+		workload.Containers = append(workload.Containers, ContainerVulnerabilities{
+			Container: labels["trivy-operator.container.name"],
+			Image:     image,
+			Counts:    counts,
+			UpdatedAt: updatedAt,
+		})
+		workload.Totals.add(counts)
+		totals.add(counts)
+	}
+
+	out := make([]WorkloadVulnerabilities, 0, len(byWorkload))
+	for _, w := range byWorkload {
+		out = append(out, *w)
+	}
+	// Most critical workloads first
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Totals.Critical != out[j].Totals.Critical {
+			return out[i].Totals.Critical > out[j].Totals.Critical
+		}
+		if out[i].Totals.High != out[j].Totals.High {
+			return out[i].Totals.High > out[j].Totals.High
+		}
+		return out[i].Namespace+"/"+out[i].Name < out[j].Namespace+"/"+out[j].Name
+	})
+	return out, totals
+}
+
+func nestedInt(m map[string]interface{}, field string) int {
+	switch v := m[field].(type) {
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
+}
+
+// GetVulnerabilities returns per-workload CVE counts from the Trivy operator, or cached on-demand scan results
+// @Summary Get vulnerability summary
+// @Description Summarizes Trivy operator VulnerabilityReports per workload. When the operator is not installed, returns on-demand scan results for images in the image inventory.
+// @Tags Security
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param namespace query string false "Namespace to filter (empty for all namespaces)"
+// @Success 200 {object} VulnerabilitySummaryResponse "Vulnerability summary"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/security/vulnerabilities [get]
+func (h *VulnerabilitiesHandler) GetVulnerabilities(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	client, dynamicClient, err := h.getClients(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for vulnerabilities")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
+
+	namespace := c.Query("namespace")
+
+	_, listSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "vulnerabilityreports", namespace)
+	defer listSpan.End()
+
+	reports, err := dynamicClient.Resource(vulnerabilityReportGVR).Namespace(namespace).List(c.Request.Context(), metav1.ListOptions{})
+	if err == nil {
+		workloadSummaries, totals := summarizeVulnerabilityReports(reports.Items)
+		h.tracingHelper.AddResourceAttributes(listSpan, "", "vulnerabilityreports", len(reports.Items))
+		h.tracingHelper.RecordSuccess(listSpan, fmt.Sprintf("Summarized %d vulnerability reports", len(reports.Items)))
+		c.JSON(http.StatusOK, VulnerabilitySummaryResponse{
+			Source:            "trivy-operator",
+			OperatorInstalled: true,
+			Totals:            totals,
+			Workloads:         workloadSummaries,
+		})
+		return
+	}
+	if !apierrors.IsNotFound(err) {
+		h.logger.WithError(err).Error("Failed to list vulnerability reports")
+		h.tracingHelper.RecordError(listSpan, err, "Failed to list vulnerability reports")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(listSpan, "Trivy operator not installed, using on-demand results")
+
+	// Operator not installed: report what on-demand scans have found for the current inventory
+	inventory, err := workloads.CollectImageInventory(c.Request.Context(), client, namespace)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to collect image inventory for vulnerabilities")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := VulnerabilitySummaryResponse{Source: "on-demand", Images: []ImageScanResult{}}
+	for _, entry := range inventory {
+		result := h.lookupScan(entry.Image)
+		result.PodCount = entry.PodCount
+		response.Totals.add(result.Counts)
+		response.Images = append(response.Images, result)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// lookupScan returns the cached result for an image, or its pending/not-scanned state
+func (h *VulnerabilitiesHandler) lookupScan(image string) ImageScanResult {
+	if cached, ok, _ := h.scanCache.Get(image); ok {
+		return cached.(ImageScanResult)
+	}
+	if _, ok := h.pending.Load(image); ok {
+		return ImageScanResult{Image: image, Status: "pending"}
+	}
+	return ImageScanResult{Image: image, Status: "not-scanned"}
+}
+
+// ScanImages starts on-demand trivy scans for the given images or the whole image inventory
+// @Summary Start on-demand vulnerability scans
+// @Description Runs trivy against the given images, or every image in the inventory, in the background. Results are cached and returned by the vulnerabilities endpoint.
+// @Tags Security
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param namespace query string false "Namespace whose images should be scanned (empty for all namespaces)"
+// @Param body body object{images=[]string,force=bool} false "Images to scan (defaults to the image inventory)"
+// @Success 202 {object} map[string]interface{} "Scans queued"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/security/vulnerabilities/scan [post]
+func (h *VulnerabilitiesHandler) ScanImages(c *gin.Context) {
+	client, _, err := h.getClients(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for vulnerability scan")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var body struct {
+		Images []string `json:"images"`
+		Force  bool     `json:"force"`
+	}
+	// An empty body means "scan the inventory"
+	_ = c.ShouldBindJSON(&body)
+
+	if _, err := exec.LookPath(h.cfg.TrivyPath); err != nil && h.cfg.TrivyServerURL == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("trivy binary not found at %q; set TRIVY_PATH or TRIVY_SERVER_URL", h.cfg.TrivyPath)})
+		return
+	}
+
+	images := body.Images
+	if len(images) == 0 {
+		inventory, err := workloads.CollectImageInventory(c.Request.Context(), client, c.Query("namespace"))
+		if err != nil {
+			h.logger.WithError(err).Error("Failed to collect image inventory for scan")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		for _, entry := range inventory {
+			images = append(images, entry.Image)
+		}
+	}
+
+	queued := 0
+	for _, image := range images {
+		if !body.Force {
+			if _, ok, _ := h.scanCache.Get(image); ok {
+				continue
+			}
+		}
+		if _, loaded := h.pending.LoadOrStore(image, true); loaded {
+			continue
+		}
+		queued++
+		go h.runScan(image)
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Scans queued", "queued": queued, "total": len(images)})
+}
+
+// runScan executes trivy for one image and caches the severity counts
+func (h *VulnerabilitiesHandler) runScan(image string) {
+	h.scanSlots <- struct{}{}
+	defer func() { <-h.scanSlots }()
+	defer h.pending.Delete(image)
+
+	timeout := time.Duration(h.cfg.ScanTimeoutSeconds) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	args := []string{"image", "--format", "json", "--quiet", "--scanners", "vuln"}
+	if h.cfg.TrivyServerURL != "" {
+		args = append(args, "--server", h.cfg.TrivyServerURL)
+	}
+	args = append(args, image)
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, h.cfg.TrivyPath, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	result := ImageScanResult{Image: image, ScannedAt: time.Now()}
+	if err := cmd.Run(); err != nil {
+		result.Status = "failed"
+		result.Error = fmt.Sprintf("%v: %s", err, stderr.String())
+		h.logger.WithError(err).WithField("image", image).Warn("Trivy scan failed")
+	} else if counts, err := parseTrivyOutput(stdout.Bytes()); err != nil {
+		result.Status = "failed"
+		result.Error = err.Error()
+	} else {
+		result.Status = "scanned"
+		result.Counts = counts
+	}
+
+	ttl := time.Duration(h.cfg.ScanCacheTTLMinutes) * time.Minute
+	if result.Status == "failed" {
+		// Retry failed images sooner than successful ones
+		ttl = 5 * time.Minute
+	}
+	_ = h.scanCache.Set(image, result, ttl)
+}
+
+// parseTrivyOutput counts vulnerabilities by severity in trivy's JSON report
+func parseTrivyOutput(raw []byte) (SeverityCounts, error) {
+	var report struct {
+		Results []struct {
+			Vulnerabilities []struct {
+				Severity string `json:"Severity"`
+			} `json:"Vulnerabilities"`
+		} `json:"Results"`
+	}
+	if err := json.Unmarshal(raw, &report); err != nil {
+		return SeverityCounts{}, fmt.Errorf("failed to parse trivy output: %w", err)
+	}
+	var counts SeverityCounts
+	for _, result := range report.Results {
+		for _, vuln := range result.Vulnerabilities {
+			switch vuln.Severity {
+			case "CRITICAL":
+				counts.Critical++
+			case "HIGH":
+				counts.High++
+			case "MEDIUM":
+				counts.Medium++
+			case "LOW":
+				counts.Low++
+			default:
+				counts.Unknown++
+			}
+		}
+	}
+	return counts, nil
+}
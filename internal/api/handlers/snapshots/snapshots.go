@@ -0,0 +1,195 @@
+package snapshots
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/apitokens"
+	"github.com/Facets-cloud/kube-dash/internal/snapshots"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// captureTimeout bounds how long a list endpoint may take to send its first payload
+const captureTimeout = 60 * time.Second
+
+// resourcePattern matches list endpoints below /api/v1 such as pods, events or deployments/prod/web/pods
+var resourcePattern = regexp.MustCompile(`^[a-z0-9-]+(/[A-Za-z0-9._-]+)*$`)
+
+// SnapshotsHandler captures list endpoint payloads into shareable snapshots
+type SnapshotsHandler struct {
+	snapshots *snapshots.Store
+	router    http.Handler
+	logger    *logger.Logger
+}
+
+// CaptureRequest selects the list view to capture
+type CaptureRequest struct {
+	Name     string            `json:"name"`
+	Resource string            `json:"resource" binding:"required"` // e.g. pods, deployments, events
+	ConfigID string            `json:"config" binding:"required"`
+	Cluster  string            `json:"cluster" binding:"required"`
+	Filters  map[string]string `json:"filters"` // query parameters of the list view, e.g. namespace
+}
+
+// NewSnapshotsHandler creates a new snapshots handler. List views are captured by requesting
+// them from router, so snapshots hold exactly the payload the UI receives.
+func NewSnapshotsHandler(store *snapshots.Store, router http.Handler, log *logger.Logger) *SnapshotsHandler {
+	return &SnapshotsHandler{
+		snapshots: store,
+		router:    router,
+		logger:    log,
+	}
+}
+
+func (h *SnapshotsHandler) snapshotError(c *gin.Context, err error) {
+	if errors.Is(err, storage.ErrDocumentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "snapshot not found"})
+		return
+	}
+	h.logger.WithError(err).Error("Snapshot operation failed")
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+// CreateSnapshot captures the current payload of a list endpoint
+// @Summary Capture list snapshot
+// @Description Captures the current transformed payload of a list endpoint such as pods, deployments or events, with its cluster, filters and capture time, and stores it under a shareable ID. Snapshots remain retrievable after the listed objects are gone, e.g. for incident postmortems.
+// @Tags Snapshots
+// @Accept json
+// @Produce json
+// @Param snapshot body CaptureRequest true "List view to capture"
+// @Success 201 {object} snapshots.Snapshot "Captured snapshot without its payload"
+// @Failure 400 {object} map[string]string "Bad request - invalid resource or list endpoint error"
+// @Failure 413 {object} map[string]string "List payload too large"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/snapshots [post]
+func (h *SnapshotsHandler) CreateSnapshot(c *gin.Context) {
+	var req CaptureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	resource := strings.Trim(req.Resource, "/")
+	if !resourcePattern.MatchString(resource) || strings.HasPrefix(resource, "snapshots") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "resource must be a list endpoint below /api/v1, e.g. pods"})
+		return
+	}
+
+	query := url.Values{}
+	for k, v := range req.Filters {
+		query.Set(k, v)
+	}
+	query.Set("config", req.ConfigID)
+	query.Set("cluster", req.Cluster)
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), captureTimeout)
+	defer cancel()
+	payload, err := snapshots.Capture(ctx, h.router, c.Request, "/api/v1/"+resource, query)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, snapshots.ErrPayloadTooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		c.JSON(status, gin.H{"error": "Failed to capture " + resource + ": " + err.Error()})
+		return
+	}
+
+	filters := map[string]string{}
+	for k, v := range req.Filters {
+		if k != "config" && k != "cluster" {
+			filters[k] = v
+		}
+	}
+	snap := &snapshots.Snapshot{
+		Name:       req.Name,
+		Resource:   resource,
+		ConfigID:   req.ConfigID,
+		Cluster:    req.Cluster,
+		Filters:    filters,
+		CapturedAt: time.Now().UTC(),
+		Payload:    payload,
+	}
+	if token, ok := apitokens.FromContext(c); ok {
+		snap.CapturedBy = token.Owner
+	}
+	if err := h.snapshots.Save(snap); err != nil {
+		h.snapshotError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, snap.Summary())
+}
+
+// ListSnapshots lists captured snapshots
+// @Summary List snapshots
+// @Description Lists captured list snapshots without their payloads, newest first
+// @Tags Snapshots
+// @Produce json
+// @Param config query string false "Kubeconfig ID"
+// @Param cluster query string false "Cluster name"
+// @Param resource query string false "Captured list endpoint, e.g. pods"
+// @Success 200 {array} snapshots.Snapshot "Snapshots"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/snapshots [get]
+func (h *SnapshotsHandler) ListSnapshots(c *gin.Context) {
+	list, err := h.snapshots.List(snapshots.Filter{
+		ConfigID: c.Query("config"),
+		Cluster:  c.Query("cluster"),
+		Resource: c.Query("resource"),
+	})
+	if err != nil {
+		h.snapshotError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// GetSnapshot returns a snapshot with its payload
+// @Summary Get snapshot
+// @Description Returns a captured snapshot including the list payload as it was sent when captured
+// @Tags Snapshots
+// @Produce json
+// @Param id path string true "Snapshot ID"
+// @Success 200 {object} snapshots.Snapshot "Snapshot"
+// @Failure 404 {object} map[string]string "Snapshot not found"
+// @Security BearerAuth
+// @Router /api/v1/snapshots/{id} [get]
+func (h *SnapshotsHandler) GetSnapshot(c *gin.Context) {
+	snap, err := h.snapshots.Get(c.Param("id"))
+	if err != nil {
+		h.snapshotError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, snap)
+}
+
+// DeleteSnapshot deletes a snapshot
+// @Summary Delete snapshot
+// @Description Deletes a captured snapshot
+// @Tags Snapshots
+// @Produce json
+// @Param id path string true "Snapshot ID"
+// @Success 200 {object} map[string]string "Snapshot deleted"
+// @Failure 404 {object} map[string]string "Snapshot not found"
+// @Security BearerAuth
+// @Router /api/v1/snapshots/{id} [delete]
+func (h *SnapshotsHandler) DeleteSnapshot(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := h.snapshots.Get(id); err != nil {
+		h.snapshotError(c, err)
+		return
+	}
+	if err := h.snapshots.Delete(id); err != nil {
+		h.snapshotError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Snapshot deleted"})
+}
@@ -1,8 +1,10 @@
 package storage
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/Facets-cloud/kube-dash/internal/api/transformers"
 	"github.com/Facets-cloud/kube-dash/internal/api/types"
@@ -44,7 +46,7 @@ func NewPersistentVolumeClaimsHandler(store *storage.KubeConfigStore, clientFact
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *PersistentVolumeClaimsHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *PersistentVolumeClaimsHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -74,6 +76,12 @@ func (h *PersistentVolumeClaimsHandler) getClientAndConfig(c *gin.Context) (*kub
 // @Param config query string true "Kubernetes configuration ID"
 // @Param cluster query string false "Cluster name"
 // @Param namespace query string false "Namespace filter"
+// @Param namespaces query string false "Comma-separated namespaces to list across, instead of namespace"
+// @Param namespaceGroup query string false "ID of a saved namespace group to list across"
+// @Param fields query string false "Comma-separated response fields to return, e.g. name,status,restarts; name, namespace and uid are always included"
+// @Param labelSelector query string false "Kubernetes label selector, e.g. app=web,tier!=cache"
+// @Param sort query string false "Response field to sort by, e.g. name or -restarts for descending"
+// @Param view query string false "ID of a saved view supplying namespaces, labelSelector, sort and fields"
 // @Success 200 {array} types.PersistentVolumeClaimListResponse "Stream of PVC data"
 // @Failure 400 {object} map[string]string "Bad request"
 // @Failure 500 {object} map[string]string "Internal server error"
@@ -93,7 +101,8 @@ func (h *PersistentVolumeClaimsHandler) GetPersistentVolumeClaimsSSE(c *gin.Cont
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed for persistent volume claims SSE")
 
-	namespace := c.Query("namespace")
+	namespaces := utils.RequestedNamespaces(c)
+	namespace := strings.Join(namespaces, ",")
 
 	// Function to fetch persistent volume claims data
 	fetchPVCs := func() (interface{}, error) {
@@ -101,7 +110,14 @@ func (h *PersistentVolumeClaimsHandler) GetPersistentVolumeClaimsSSE(c *gin.Cont
 		_, k8sSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "persistentvolumeclaim", namespace)
 		defer k8sSpan.End()
 
-		pvcs, err := client.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+		items, err := utils.ListInNamespaces(ctx, namespaces, func(ctx context.Context, namespace string) ([]corev1.PersistentVolumeClaim, error) {
+			list, err := client.CoreV1().PersistentVolumeClaims(namespace).List(ctx, utils.ListOptions(c))
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		pvcs := &corev1.PersistentVolumeClaimList{Items: items}
 		if err != nil {
 			h.tracingHelper.RecordError(k8sSpan, err, "Failed to list persistent volume claims")
 			return nil, err
@@ -154,7 +170,7 @@ func (h *PersistentVolumeClaimsHandler) GetPVC(c *gin.Context) {
 		if c.GetHeader("Accept") == "text/event-stream" {
 			h.sseHandler.SendSSEError(c, http.StatusBadRequest, err.Error())
 		} else {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			utils.RespondError(c, http.StatusBadRequest, err)
 		}
 		return
 	}
@@ -175,7 +191,7 @@ func (h *PersistentVolumeClaimsHandler) GetPVC(c *gin.Context) {
 		if c.GetHeader("Accept") == "text/event-stream" {
 			h.sseHandler.SendSSEError(c, http.StatusNotFound, err.Error())
 		} else {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			utils.RespondError(c, http.StatusNotFound, err)
 		}
 		return
 	}
@@ -185,7 +201,7 @@ func (h *PersistentVolumeClaimsHandler) GetPVC(c *gin.Context) {
 	// Check if this is an SSE request (EventSource expects SSE format)
 	acceptHeader := c.GetHeader("Accept")
 	if acceptHeader == "text/event-stream" {
-		h.sseHandler.SendSSEResponse(c, pvc)
+		h.sseHandler.SendSSEDetailResponse(c, pvc, pvc, client.CoreV1().PersistentVolumeClaims(namespace).Watch)
 		return
 	}
 
@@ -202,7 +218,7 @@ func (h *PersistentVolumeClaimsHandler) GetPVCByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for persistent volume claim by name")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get client for persistent volume claim by name")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed for persistent volume claim by name")
@@ -211,7 +227,7 @@ func (h *PersistentVolumeClaimsHandler) GetPVCByName(c *gin.Context) {
 	namespace := c.Query("namespace")
 
 	if namespace == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -223,7 +239,7 @@ func (h *PersistentVolumeClaimsHandler) GetPVCByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("pvc", name).WithField("namespace", namespace).Error("Failed to get persistent volume claim by name")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get persistent volume claim by name")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(k8sSpan, name, "persistentvolumeclaim", 1)
@@ -242,7 +258,7 @@ func (h *PersistentVolumeClaimsHandler) GetPVCYAMLByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for persistent volume claim YAML by name")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get client for persistent volume claim YAML by name")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed for persistent volume claim YAML by name")
@@ -251,7 +267,7 @@ func (h *PersistentVolumeClaimsHandler) GetPVCYAMLByName(c *gin.Context) {
 	namespace := c.Query("namespace")
 
 	if namespace == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -263,7 +279,7 @@ func (h *PersistentVolumeClaimsHandler) GetPVCYAMLByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("pvc", name).WithField("namespace", namespace).Error("Failed to get persistent volume claim for YAML by name")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get persistent volume claim for YAML by name")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(k8sSpan, name, "persistentvolumeclaim", 1)
@@ -287,7 +303,7 @@ func (h *PersistentVolumeClaimsHandler) GetPVCYAML(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for persistent volume claim YAML")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get client for persistent volume claim YAML")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed for persistent volume claim YAML")
@@ -303,7 +319,7 @@ func (h *PersistentVolumeClaimsHandler) GetPVCYAML(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("pvc", name).WithField("namespace", namespace).Error("Failed to get persistent volume claim for YAML")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get persistent volume claim for YAML")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(k8sSpan, name, "persistentvolumeclaim", 1)
@@ -327,7 +343,7 @@ func (h *PersistentVolumeClaimsHandler) GetPVCEventsByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for persistent volume claim events")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get client for persistent volume claim events")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed for persistent volume claim events")
@@ -337,7 +353,7 @@ func (h *PersistentVolumeClaimsHandler) GetPVCEventsByName(c *gin.Context) {
 
 	if namespace == "" {
 		h.logger.WithField("pvc", name).Error("Namespace is required for persistent volume claim events lookup")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -359,7 +375,7 @@ func (h *PersistentVolumeClaimsHandler) GetPVCEvents(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for persistent volume claim events")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get client for persistent volume claim events")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed for persistent volume claim events")
@@ -384,7 +400,7 @@ func (h *PersistentVolumeClaimsHandler) ScalePVC(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for PVC scaling")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get client for PVC scaling")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed for PVC scaling")
@@ -404,7 +420,7 @@ func (h *PersistentVolumeClaimsHandler) ScalePVC(c *gin.Context) {
 	if err := c.ShouldBindJSON(&request); err != nil {
 		h.logger.WithError(err).Error("Failed to parse PVC scale request")
 		h.tracingHelper.RecordError(processSpan, err, "Failed to parse PVC scale request")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 	h.tracingHelper.RecordSuccess(processSpan, "Successfully parsed PVC scale request")
@@ -418,7 +434,7 @@ func (h *PersistentVolumeClaimsHandler) ScalePVC(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("pvc", name).WithField("namespace", namespace).Error("Failed to get PVC for scaling")
 		h.tracingHelper.RecordError(getSpan, err, "Failed to get PVC for scaling")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(getSpan, name, "persistentvolumeclaim", 1)
@@ -427,7 +443,7 @@ func (h *PersistentVolumeClaimsHandler) ScalePVC(c *gin.Context) {
 	// Get current size
 	currentSize := pvc.Spec.Resources.Requests.Storage()
 	if currentSize == nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Current PVC size cannot be determined"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "Current PVC size cannot be determined")
 		return
 	}
 
@@ -435,13 +451,13 @@ func (h *PersistentVolumeClaimsHandler) ScalePVC(c *gin.Context) {
 	newSize, err := resource.ParseQuantity(request.Size)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to parse new size")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid size format"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "Invalid size format")
 		return
 	}
 
 	// Validate that new size is greater than current size
 	if newSize.Cmp(*currentSize) <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "New size must be greater than current size"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "New size must be greater than current size")
 		return
 	}
 
@@ -457,7 +473,7 @@ func (h *PersistentVolumeClaimsHandler) ScalePVC(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("pvc", name).WithField("namespace", namespace).Error("Failed to update PVC size")
 		h.tracingHelper.RecordError(updateSpan, err, "Failed to update PVC size")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(updateSpan, name, "persistentvolumeclaim", 1)
@@ -496,7 +512,7 @@ func (h *PersistentVolumeClaimsHandler) GetPVCPods(c *gin.Context) {
 		if c.GetHeader("Accept") == "text/event-stream" {
 			h.sseHandler.SendSSEError(c, http.StatusBadRequest, err.Error())
 		} else {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			utils.RespondError(c, http.StatusBadRequest, err)
 		}
 		return
 	}
@@ -563,7 +579,7 @@ func (h *PersistentVolumeClaimsHandler) GetPVCPods(c *gin.Context) {
 	data, err := fetchPods()
 	if err != nil {
 		h.logger.WithError(err).WithField("pvc", name).WithField("namespace", namespace).Error("Failed to get PVC pods")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -584,7 +600,7 @@ func (h *PersistentVolumeClaimsHandler) GetPVCPodsByName(c *gin.Context) {
 		if c.GetHeader("Accept") == "text/event-stream" {
 			h.sseHandler.SendSSEError(c, http.StatusBadRequest, err.Error())
 		} else {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			utils.RespondError(c, http.StatusBadRequest, err)
 		}
 		return
 	}
@@ -598,7 +614,7 @@ func (h *PersistentVolumeClaimsHandler) GetPVCPodsByName(c *gin.Context) {
 		if c.GetHeader("Accept") == "text/event-stream" {
 			h.sseHandler.SendSSEError(c, http.StatusBadRequest, "namespace parameter is required")
 		} else {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+			utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		}
 		return
 	}
@@ -661,7 +677,7 @@ func (h *PersistentVolumeClaimsHandler) GetPVCPodsByName(c *gin.Context) {
 	data, err := fetchPods()
 	if err != nil {
 		h.logger.WithError(err).WithField("pvc", name).WithField("namespace", namespace).Error("Failed to get PVC pods by name")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/types"
+	"github.com/Facets-cloud/kube-dash/internal/testharness"
+)
+
+func TestGetPersistentVolumeClaimsSSE(t *testing.T) {
+	h := testharness.New(t, "pvcs.yaml")
+	handler := NewPersistentVolumeClaimsHandler(h.Store, h.Factory, h.Logger)
+
+	var pvcs []types.PersistentVolumeClaimListResponse
+	data := h.FirstEvent("/api/v1/persistentvolumeclaims", handler.GetPersistentVolumeClaimsSSE, "/api/v1/persistentvolumeclaims?namespace=shop")
+	if err := json.Unmarshal(data, &pvcs); err != nil {
+		t.Fatalf("invalid event payload: %v", err)
+	}
+	if len(pvcs) != 1 || pvcs[0].Name != "data-db-0" {
+		t.Fatalf("claims in shop = %+v", pvcs)
+	}
+	if pvcs[0].Spec.StorageClassName != "standard" || pvcs[0].Spec.Resources.Requests["storage"] != "10Gi" {
+		t.Errorf("spec = %+v", pvcs[0].Spec)
+	}
+}
+
+func TestGetPVCPods(t *testing.T) {
+	h := testharness.New(t, "pvcs.yaml")
+	handler := NewPersistentVolumeClaimsHandler(h.Store, h.Factory, h.Logger)
+
+	var pods []types.PodListResponse
+	h.DoJSON(http.MethodGet, "/api/v1/persistentvolumeclaims/:namespace/:name/pods", handler.GetPVCPods, "/api/v1/persistentvolumeclaims/shop/data-db-0/pods", nil, &pods)
+	if len(pods) != 1 || pods[0].Name != "db-0" {
+		t.Errorf("pods mounting data-db-0 = %+v, want db-0 only", pods)
+	}
+}
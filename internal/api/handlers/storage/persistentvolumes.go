@@ -42,7 +42,7 @@ func NewPersistentVolumesHandler(store *storage.KubeConfigStore, clientFactory *
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *PersistentVolumesHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *PersistentVolumesHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -141,7 +141,7 @@ func (h *PersistentVolumesHandler) GetPersistentVolumesSSE(c *gin.Context) {
 	}
 
 	// For non-SSE requests, return JSON
-	c.JSON(http.StatusOK, initialData)
+	h.sseHandler.SendJSON(c, initialData)
 }
 
 // GetPersistentVolume returns a specific persistent volume
@@ -158,7 +158,7 @@ func (h *PersistentVolumesHandler) GetPersistentVolume(c *gin.Context) {
 		if c.GetHeader("Accept") == "text/event-stream" {
 			h.sseHandler.SendSSEError(c, http.StatusBadRequest, err.Error())
 		} else {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			utils.RespondError(c, http.StatusBadRequest, err)
 		}
 		return
 	}
@@ -178,7 +178,7 @@ func (h *PersistentVolumesHandler) GetPersistentVolume(c *gin.Context) {
 		if c.GetHeader("Accept") == "text/event-stream" {
 			h.sseHandler.SendSSEError(c, http.StatusNotFound, err.Error())
 		} else {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			utils.RespondError(c, http.StatusNotFound, err)
 		}
 		return
 	}
@@ -188,7 +188,7 @@ func (h *PersistentVolumesHandler) GetPersistentVolume(c *gin.Context) {
 	// Check if this is an SSE request (EventSource expects SSE format)
 	acceptHeader := c.GetHeader("Accept")
 	if acceptHeader == "text/event-stream" {
-		h.sseHandler.SendSSEResponse(c, pv)
+		h.sseHandler.SendSSEDetailResponse(c, pv, pv, client.CoreV1().PersistentVolumes().Watch)
 		return
 	}
 
@@ -205,7 +205,7 @@ func (h *PersistentVolumesHandler) GetPersistentVolumeByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for persistent volume by name")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get client for persistent volume by name")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed for persistent volume by name")
@@ -220,7 +220,7 @@ func (h *PersistentVolumesHandler) GetPersistentVolumeByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("pv", name).Error("Failed to get persistent volume by name")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get persistent volume by name")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(k8sSpan, name, "persistentvolume", 1)
@@ -239,7 +239,7 @@ func (h *PersistentVolumesHandler) GetPersistentVolumeYAMLByName(c *gin.Context)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for persistent volume YAML by name")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get client for persistent volume YAML by name")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed for persistent volume YAML by name")
@@ -254,7 +254,7 @@ func (h *PersistentVolumesHandler) GetPersistentVolumeYAMLByName(c *gin.Context)
 	if err != nil {
 		h.logger.WithError(err).WithField("pv", name).Error("Failed to get persistent volume for YAML by name")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get persistent volume for YAML by name")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(k8sSpan, name, "persistentvolume", 1)
@@ -278,7 +278,7 @@ func (h *PersistentVolumesHandler) GetPersistentVolumeYAML(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for persistent volume YAML")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get client for persistent volume YAML")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed for persistent volume YAML")
@@ -293,7 +293,7 @@ func (h *PersistentVolumesHandler) GetPersistentVolumeYAML(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("pv", name).Error("Failed to get persistent volume for YAML")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get persistent volume for YAML")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(k8sSpan, name, "persistentvolume", 1)
@@ -317,7 +317,7 @@ func (h *PersistentVolumesHandler) GetPersistentVolumeEventsByName(c *gin.Contex
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for persistent volume events")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get client for persistent volume events")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed for persistent volume events")
@@ -342,7 +342,7 @@ func (h *PersistentVolumesHandler) GetPersistentVolumeEvents(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for persistent volume events")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get client for persistent volume events")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed for persistent volume events")
@@ -42,7 +42,7 @@ func NewStorageClassesHandler(store *storage.KubeConfigStore, clientFactory *k8s
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *StorageClassesHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *StorageClassesHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -137,7 +137,7 @@ func (h *StorageClassesHandler) GetStorageClass(c *gin.Context) {
 		if c.GetHeader("Accept") == "text/event-stream" {
 			h.sseHandler.SendSSEError(c, http.StatusBadRequest, err.Error())
 		} else {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			utils.RespondError(c, http.StatusBadRequest, err)
 		}
 		return
 	}
@@ -157,7 +157,7 @@ func (h *StorageClassesHandler) GetStorageClass(c *gin.Context) {
 		if c.GetHeader("Accept") == "text/event-stream" {
 			h.sseHandler.SendSSEError(c, http.StatusNotFound, err.Error())
 		} else {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			utils.RespondError(c, http.StatusNotFound, err)
 		}
 		return
 	}
@@ -167,7 +167,7 @@ func (h *StorageClassesHandler) GetStorageClass(c *gin.Context) {
 	// Check if this is an SSE request (EventSource expects SSE format)
 	acceptHeader := c.GetHeader("Accept")
 	if acceptHeader == "text/event-stream" {
-		h.sseHandler.SendSSEResponse(c, storageClass)
+		h.sseHandler.SendSSEDetailResponse(c, storageClass, storageClass, client.StorageV1().StorageClasses().Watch)
 		return
 	}
 
@@ -184,7 +184,7 @@ func (h *StorageClassesHandler) GetStorageClassByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for storage class by name")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get client for storage class by name")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed for storage class by name")
@@ -199,7 +199,7 @@ func (h *StorageClassesHandler) GetStorageClassByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("storageclass", name).Error("Failed to get storage class by name")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get storage class by name")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(k8sSpan, name, "storageclass", 1)
@@ -218,7 +218,7 @@ func (h *StorageClassesHandler) GetStorageClassYAMLByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for storage class YAML by name")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get client for storage class YAML by name")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed for storage class YAML by name")
@@ -233,7 +233,7 @@ func (h *StorageClassesHandler) GetStorageClassYAMLByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("storageclass", name).Error("Failed to get storage class for YAML by name")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get storage class for YAML by name")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(k8sSpan, name, "storageclass", 1)
@@ -257,7 +257,7 @@ func (h *StorageClassesHandler) GetStorageClassYAML(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for storage class YAML")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get client for storage class YAML")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed for storage class YAML")
@@ -272,7 +272,7 @@ func (h *StorageClassesHandler) GetStorageClassYAML(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("storageclass", name).Error("Failed to get storage class for YAML")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get storage class for YAML")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(k8sSpan, name, "storageclass", 1)
@@ -296,7 +296,7 @@ func (h *StorageClassesHandler) GetStorageClassEventsByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for storage class events")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get client for storage class events")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed for storage class events")
@@ -321,7 +321,7 @@ func (h *StorageClassesHandler) GetStorageClassEvents(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for storage class events")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get client for storage class events")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed for storage class events")
@@ -0,0 +1,80 @@
+package streams
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/streamhub"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// multiplexWriteTimeout bounds writing one message to a multiplexing client
+const multiplexWriteTimeout = 10 * time.Second
+
+// MultiplexHandler serves many streaming subscriptions over one WebSocket
+type MultiplexHandler struct {
+	hub      *streamhub.Hub
+	logger   *logger.Logger
+	upgrader websocket.Upgrader
+}
+
+// NewMultiplexHandler creates a new multiplexing handler
+func NewMultiplexHandler(hub *streamhub.Hub, log *logger.Logger) *MultiplexHandler {
+	return &MultiplexHandler{
+		hub:    hub,
+		logger: log,
+		upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool {
+				return true // Allow all origins for now
+			},
+			ReadBufferSize:  1024,
+			WriteBufferSize: 4096,
+		},
+	}
+}
+
+// ServeMultiplexed serves streaming subscriptions over a WebSocket
+// @Summary Multiplexed streams
+// @Description Upgrades to a WebSocket that carries many subscriptions to streaming endpoints, so a browser tab needs one connection instead of an SSE connection per list, event or metrics stream. Clients send {"type":"subscribe","id":"pods","path":"/api/v1/pods","query":{"config":"...","cluster":"...","namespace":"shop"}}, {"type":"unsubscribe","id":"pods"} and {"type":"ping"}. The server answers with subscribed, event (the endpoint's SSE event name and data, tagged with the subscription ID), error, closed and pong messages. Each subscription is served by the endpoint it names with the caller's credentials and counts against the stream caps.
+// @Tags System
+// @Success 101 {string} string "Switching Protocols"
+// @Failure 400 {object} map[string]string "Not a WebSocket request"
+// @Security BearerAuth
+// @Router /api/v1/streams/ws [get]
+func (h *MultiplexHandler) ServeMultiplexed(c *gin.Context) {
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to upgrade multiplexed streams connection")
+		return
+	}
+	defer conn.Close()
+
+	session := h.hub.NewSession(c.Request.Context(), c.Request)
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+		for msg := range session.Messages() {
+			_ = conn.SetWriteDeadline(time.Now().Add(multiplexWriteTimeout))
+			if err := conn.WriteJSON(msg); err != nil {
+				// Ends the read loop below, which closes the session
+				_ = conn.Close()
+			}
+		}
+	}()
+
+	for {
+		var msg streamhub.ClientMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				h.logger.WithError(err).Debug("Multiplexed streams connection closed")
+			}
+			break
+		}
+		session.Handle(msg)
+	}
+	session.Close()
+	<-writerDone
+}
@@ -0,0 +1,31 @@
+package streams
+
+import (
+	"net/http"
+
+	"github.com/Facets-cloud/kube-dash/internal/streams"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StreamsHandler reports the SSE and WebSocket streams the server holds open
+type StreamsHandler struct {
+	registry *streams.Registry
+}
+
+// NewStreamsHandler creates a new streams handler
+func NewStreamsHandler(registry *streams.Registry) *StreamsHandler {
+	return &StreamsHandler{registry: registry}
+}
+
+// GetStreams lists the open streams
+// @Summary List open streams
+// @Description Lists the SSE and WebSocket streams the server holds open, with their cluster, client and last activity, and the per-cluster counts against the configured caps. Streams over a cap are refused with 429 and a Retry-After header.
+// @Tags System
+// @Produce json
+// @Success 200 {object} streams.Stats "Open streams"
+// @Security BearerAuth
+// @Router /api/v1/streams [get]
+func (h *StreamsHandler) GetStreams(c *gin.Context) {
+	c.JSON(http.StatusOK, h.registry.Stats())
+}
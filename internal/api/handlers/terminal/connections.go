@@ -0,0 +1,249 @@
+package terminal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+const (
+	// defaultNetDebugImage runs cat in ephemeral containers added to pods without one
+	defaultNetDebugImage = "busybox:1.36"
+	connectionsTimeout   = 30 * time.Second
+	debugContainerWait   = 60 * time.Second
+	// resolveTimeout bounds listing pods, services and nodes to name remote addresses
+	resolveTimeout  = 10 * time.Second
+	maxProcNetBytes = 4 << 20
+)
+
+// limitedBuffer keeps the first max bytes written to it
+type limitedBuffer struct {
+	bytes.Buffer
+	max int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.max - b.Len(); remaining > 0 {
+		if len(p) > remaining {
+			b.Buffer.Write(p[:remaining])
+		} else {
+			b.Buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+// execCommand runs a non-interactive command in a container and returns its output, trying the
+// WebSocket exec protocol first and falling back to SPDY for older API servers
+func execCommand(ctx context.Context, client kubernetes.Interface, restConfig *rest.Config, namespace, pod, container string, command []string) (string, string, error) {
+	req := client.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("exec").
+		VersionedParams(&v1.PodExecOptions{Container: container, Command: command, Stdout: true, Stderr: true}, scheme.ParameterCodec)
+
+	wsExecutor, err := remotecommand.NewWebSocketExecutor(restConfig, "GET", req.URL().String())
+	if err != nil {
+		return "", "", err
+	}
+	spdyExecutor, err := remotecommand.NewSPDYExecutor(restConfig, "POST", req.URL())
+	if err != nil {
+		return "", "", err
+	}
+	executor, err := remotecommand.NewFallbackExecutor(wsExecutor, spdyExecutor, func(err error) bool {
+		return httpstream.IsUpgradeFailure(err) || httpstream.IsHTTPSProxyError(err)
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	stdout := &limitedBuffer{max: maxProcNetBytes}
+	stderr := &limitedBuffer{max: 64 * 1024}
+	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{Stdout: stdout, Stderr: stderr})
+	return stdout.String(), stderr.String(), err
+}
+
+// runDebugContainer adds an ephemeral container running command alongside target, waits for it
+// to exit and returns its logs. Ephemeral containers cannot be removed, so the container stays in
+// the pod spec until the pod is replaced.
+func runDebugContainer(ctx context.Context, client kubernetes.Interface, pod *v1.Pod, name, target, image string, command []string) (string, error) {
+	updated := pod.DeepCopy()
+	updated.Spec.EphemeralContainers = append(updated.Spec.EphemeralContainers, v1.EphemeralContainer{
+		EphemeralContainerCommon: v1.EphemeralContainerCommon{
+			Name:                     name,
+			Image:                    image,
+			Command:                  command,
+			ImagePullPolicy:          v1.PullIfNotPresent,
+			TerminationMessagePolicy: v1.TerminationMessageReadFile,
+		},
+		TargetContainerName: target,
+	})
+	pods := client.CoreV1().Pods(pod.Namespace)
+	if _, err := pods.UpdateEphemeralContainers(ctx, pod.Name, updated, metav1.UpdateOptions{}); err != nil {
+		return "", fmt.Errorf("failed to add debug container: %w", err)
+	}
+
+	var waitReason string
+	err := wait.PollUntilContextTimeout(ctx, time.Second, debugContainerWait, true, func(ctx context.Context) (bool, error) {
+		current, err := pods.Get(ctx, pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, status := range current.Status.EphemeralContainerStatuses {
+			if status.Name != name {
+				continue
+			}
+			if status.State.Waiting != nil {
+				waitReason = status.State.Waiting.Reason
+			}
+			return status.State.Terminated != nil, nil
+		}
+		return false, nil
+	})
+	if err != nil {
+		if waitReason != "" {
+			return "", fmt.Errorf("debug container did not finish: %s", waitReason)
+		}
+		return "", fmt.Errorf("debug container did not finish: %w", err)
+	}
+
+	limit := int64(maxProcNetBytes)
+	logs, err := pods.GetLogs(pod.Name, &v1.PodLogOptions{Container: name, LimitBytes: &limit}).DoRaw(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to read debug container output: %w", err)
+	}
+	return string(logs), nil
+}
+
+// loadEndpointResolver lists the cluster's pods, services and nodes from the API server cache to
+// name remote addresses
+func loadEndpointResolver(ctx context.Context, client kubernetes.Interface) (*endpointResolver, error) {
+	ctx, cancel := context.WithTimeout(ctx, resolveTimeout)
+	defer cancel()
+	opts := metav1.ListOptions{ResourceVersion: "0"}
+	pods, err := client.CoreV1().Pods("").List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	services, err := client.CoreV1().Services("").List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	// Nodes are only needed for host-network peers; callers without node access still get the rest
+	var nodes []v1.Node
+	if nodeList, err := client.CoreV1().Nodes().List(ctx, opts); err == nil {
+		nodes = nodeList.Items
+	}
+	return newEndpointResolver(pods.Items, services.Items, nodes), nil
+}
+
+// GetPodConnections lists a pod's listening sockets and connections
+// @Summary Get pod network connections
+// @Description Reads the socket tables of the pod's network namespace, like ss or netstat, by running cat on /proc/net/{tcp,tcp6,udp,udp6} in a container, and names remote addresses after the pods, services and nodes that own them. Containers without cat can be inspected from an ephemeral debug container with debug=true; the container stays in the pod spec until the pod is replaced. Both are subject to the exec policy.
+// @Tags Terminal
+// @Produce json
+// @Param namespace path string true "Namespace name"
+// @Param name path string true "Pod name"
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param container query string false "Container to run in, or to target with the debug container (defaults to the first container)"
+// @Param debug query bool false "Read the sockets from an ephemeral debug container instead of exec"
+// @Param image query string false "Debug container image (default busybox:1.36)"
+// @Success 200 {object} terminal.PodConnections "Listening sockets, connections and peers"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 403 {object} utils.ErrorResponse "Exec denied by policy"
+// @Failure 404 {object} utils.ErrorResponse "Pod not found"
+// @Failure 502 {object} utils.ErrorResponse "The socket tables could not be read from the container"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/pods/{namespace}/{name}/connections [get]
+func (h *Handler) GetPodConnections(c *gin.Context) {
+	namespace, name := c.Param("namespace"), c.Param("name")
+	client, restConfig, err := h.getClientAndConfig(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), connectionsTimeout+debugContainerWait)
+	defer cancel()
+
+	pod, err := client.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		utils.RespondError(c, http.StatusNotFound, err)
+		return
+	}
+	if pod.Status.Phase != v1.PodRunning {
+		utils.RespondErrorMessage(c, http.StatusConflict, fmt.Sprintf("pod is not running, current phase: %s", pod.Status.Phase))
+		return
+	}
+	container := c.Query("container")
+	if container == "" {
+		container = GetDefaultContainer(pod)
+	}
+	command := append([]string{"cat"}, procNetFiles...)
+	commandLine := strings.Join(command, " ")
+
+	result := PodConnections{Namespace: namespace, Pod: name, Container: container, Method: "exec"}
+	var output string
+	if c.Query("debug") == "true" {
+		result.Method = "debug-container"
+		debugName := "netinspect-" + utilrand.String(5)
+		image := c.DefaultQuery("image", defaultNetDebugImage)
+		// Evaluate the policy against the debug container as it will run
+		withDebug := pod.DeepCopy()
+		withDebug.Spec.EphemeralContainers = append(withDebug.Spec.EphemeralContainers, v1.EphemeralContainer{
+			EphemeralContainerCommon: v1.EphemeralContainerCommon{Name: debugName, Image: image},
+		})
+		if decision := h.authorizeExec(c, withDebug, debugName, commandLine); !decision.Allowed {
+			utils.RespondErrorMessage(c, http.StatusForbidden, "exec not permitted: "+decision.Reason)
+			return
+		}
+		output, err = runDebugContainer(ctx, client, pod, debugName, container, image, command)
+		if err != nil {
+			utils.RespondError(c, http.StatusBadGateway, err)
+			return
+		}
+	} else {
+		if decision := h.authorizeExec(c, pod, container, commandLine); !decision.Allowed {
+			utils.RespondErrorMessage(c, http.StatusForbidden, "exec not permitted: "+decision.Reason)
+			return
+		}
+		execCtx, execCancel := context.WithTimeout(ctx, connectionsTimeout)
+		stdout, stderr, err := execCommand(execCtx, client, restConfig, namespace, name, container, command)
+		execCancel()
+		// cat exits non-zero when a table is missing, e.g. tcp6 with IPv6 disabled; the rest is still usable
+		if err != nil && !strings.Contains(stdout, "local_address") {
+			if stderr != "" {
+				err = errors.New(strings.TrimSpace(stderr))
+			}
+			utils.RespondError(c, http.StatusBadGateway, fmt.Errorf("failed to read socket tables from container %s (retry with debug=true for containers without cat): %w", container, err))
+			return
+		}
+		output = stdout
+	}
+
+	resolver, err := loadEndpointResolver(ctx, client)
+	if err != nil {
+		h.logger.WithError(err).Debug("Failed to load cluster endpoints for connection resolution")
+	}
+	result.Listening, result.Connections, result.Peers = buildPodConnections(parseProcNet(output), resolver)
+	c.JSON(http.StatusOK, result)
+}
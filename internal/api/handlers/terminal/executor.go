@@ -10,10 +10,12 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
 	"github.com/Facets-cloud/kube-dash/pkg/logger"
 	"github.com/gorilla/websocket"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 )
@@ -21,7 +23,7 @@ import (
 // K8sExecutor manages the WebSocket connection to the Kubernetes API server
 // for executing commands in pods using the v5.channel.k8s.io protocol
 type K8sExecutor struct {
-	client     *kubernetes.Clientset
+	client     kubernetes.Interface
 	restConfig *rest.Config
 	config     *TerminalConfig
 	logger     *logger.Logger
@@ -46,7 +48,7 @@ type K8sMessage struct {
 }
 
 // NewK8sExecutor creates a new K8s WebSocket executor
-func NewK8sExecutor(client *kubernetes.Clientset, restConfig *rest.Config, config *TerminalConfig, log *logger.Logger) *K8sExecutor {
+func NewK8sExecutor(client kubernetes.Interface, restConfig *rest.Config, config *TerminalConfig, log *logger.Logger) *K8sExecutor {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &K8sExecutor{
@@ -185,15 +187,30 @@ func (e *K8sExecutor) createDialer() (*websocket.Dialer, error) {
 		tlsConfig.Certificates = []tls.Certificate{cert}
 	}
 
+	// Bastions and tunnels often reach the API server under another name
+	tlsConfig.ServerName = e.restConfig.TLSClientConfig.ServerName
+
 	dialer := &websocket.Dialer{
 		TLSClientConfig:  tlsConfig,
 		HandshakeTimeout: 30 * time.Second,
 		Subprotocols:     []string{SubprotocolV5, SubprotocolV4},
+		Proxy:            proxyFor(e.restConfig),
+		NetDialContext:   e.restConfig.Dial,
 	}
 
 	return dialer, nil
 }
 
+// proxyFor returns the proxy the REST client itself would use: the kubeconfig's proxy-url
+// (HTTP, HTTPS or SOCKS5) when set, else the HTTPS_PROXY and NO_PROXY environment, including
+// NO_PROXY CIDRs the way client-go honors them
+func proxyFor(restConfig *rest.Config) func(*http.Request) (*url.URL, error) {
+	if restConfig.Proxy != nil {
+		return restConfig.Proxy
+	}
+	return utilnet.NewProxierWithNoProxyCIDR(http.ProxyFromEnvironment)
+}
+
 // buildHeaders builds HTTP headers for the WebSocket connection
 func (e *K8sExecutor) buildHeaders() http.Header {
 	headers := http.Header{}
@@ -393,7 +410,7 @@ func (e *K8sExecutor) Close() error {
 }
 
 // ValidatePod validates that the pod exists and is running
-func ValidatePod(ctx context.Context, client *kubernetes.Clientset, namespace, podName string) (*v1.Pod, error) {
+func ValidatePod(ctx context.Context, client kubernetes.Interface, namespace, podName string) (*v1.Pod, error) {
 	pod, err := client.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("pod not found: %w", err)
@@ -406,10 +423,7 @@ func ValidatePod(ctx context.Context, client *kubernetes.Clientset, namespace, p
 	return pod, nil
 }
 
-// GetDefaultContainer returns the first container name from a pod
+// GetDefaultContainer returns the container to use when none is given, skipping injected sidecars
 func GetDefaultContainer(pod *v1.Pod) string {
-	if len(pod.Spec.Containers) > 0 {
-		return pod.Spec.Containers[0].Name
-	}
-	return ""
+	return utils.SelectDefaultContainer(pod).Container
 }
@@ -0,0 +1,94 @@
+package terminal
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+	"github.com/gorilla/websocket"
+	"k8s.io/client-go/rest"
+)
+
+// connectProxy is a minimal HTTP CONNECT proxy counting the tunnels it opens
+func connectProxy(t *testing.T, tunnels *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			http.Error(w, "CONNECT only", http.StatusMethodNotAllowed)
+			return
+		}
+		upstream, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		atomic.AddInt32(tunnels, 1)
+		w.WriteHeader(http.StatusOK)
+		client, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			upstream.Close()
+			return
+		}
+		go func() {
+			defer upstream.Close()
+			defer client.Close()
+			go io.Copy(upstream, client)
+			io.Copy(client, upstream)
+		}()
+	}))
+}
+
+func TestExecutorConnectsThroughKubeconfigProxy(t *testing.T) {
+	upgrader := websocket.Upgrader{Subprotocols: []string{SubprotocolV5}}
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/namespaces/shop/pods/web-1/exec" {
+			http.NotFound(w, r)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer apiServer.Close()
+
+	var tunnels int32
+	proxy := connectProxy(t, &tunnels)
+	defer proxy.Close()
+	proxyURL, _ := url.Parse(proxy.URL)
+
+	restConfig := &rest.Config{Host: apiServer.URL, Proxy: http.ProxyURL(proxyURL)}
+	executor := NewK8sExecutor(nil, restConfig, &TerminalConfig{Namespace: "shop", PodName: "web-1", Command: []string{"sh"}, Stdout: true}, logger.New("error"))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := executor.Connect(ctx); err != nil {
+		t.Fatalf("Connect() through proxy failed: %v", err)
+	}
+	defer executor.Close()
+
+	if got := atomic.LoadInt32(&tunnels); got != 1 {
+		t.Errorf("expected the exec connection to tunnel through the proxy once, got %d", got)
+	}
+}
+
+func TestProxyForPrefersKubeconfigProxy(t *testing.T) {
+	proxyURL, _ := url.Parse("socks5://bastion:1080")
+	proxy := proxyFor(&rest.Config{Proxy: http.ProxyURL(proxyURL)})
+	req, _ := http.NewRequest(http.MethodGet, "https://10.0.0.1:6443/api", nil)
+	got, err := proxy(req)
+	if err != nil || got.String() != proxyURL.String() {
+		t.Errorf("proxyFor() = %v, %v; want %v", got, err, proxyURL)
+	}
+}
@@ -3,8 +3,13 @@ package terminal
 import (
 	"fmt"
 	"net/http"
+	"time"
 
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+	"github.com/Facets-cloud/kube-dash/internal/audit"
+	"github.com/Facets-cloud/kube-dash/internal/execpolicy"
 	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/snippets"
 	"github.com/Facets-cloud/kube-dash/internal/storage"
 	"github.com/Facets-cloud/kube-dash/internal/tracing"
 	"github.com/Facets-cloud/kube-dash/pkg/logger"
@@ -23,14 +28,22 @@ type Handler struct {
 	logger        *logger.Logger
 	upgrader      websocket.Upgrader
 	tracingHelper *tracing.TracingHelper
+	policies      *execpolicy.Store
+	auditor       *audit.Recorder
+	snippets      *snippets.Store
+	scrollbacks   *ScrollbackRegistry
 }
 
 // NewHandler creates a new terminal Handler
-func NewHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger) *Handler {
+func NewHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, policies *execpolicy.Store, snippetStore *snippets.Store, scrollbacks *ScrollbackRegistry, auditor *audit.Recorder, log *logger.Logger) *Handler {
 	return &Handler{
 		store:         store,
 		clientFactory: clientFactory,
 		logger:        log,
+		policies:      policies,
+		auditor:       auditor,
+		snippets:      snippetStore,
+		scrollbacks:   scrollbacks,
 		upgrader: websocket.Upgrader{
 			CheckOrigin: func(r *http.Request) bool {
 				return true // Allow all origins for now
@@ -43,7 +56,7 @@ func NewHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory
 }
 
 // getClientAndConfig gets the Kubernetes client and REST config for the given config ID and cluster
-func (h *Handler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, *rest.Config, error) {
+func (h *Handler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, *rest.Config, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -92,7 +105,7 @@ func (h *Handler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, *re
 
 // HandleExec handles WebSocket-based pod exec using native K8s WebSocket protocol
 // @Summary Execute Commands in Pod via WebSocket (v5 Protocol)
-// @Description Execute interactive commands in a pod container via WebSocket using K8s v5.channel.k8s.io protocol
+// @Description Execute interactive commands in a pod container via WebSocket using K8s v5.channel.k8s.io protocol. Requests are checked against the exec policy before the K8s connection is made.
 // @Tags Terminal
 // @Accept json
 // @Produce json
@@ -100,10 +113,12 @@ func (h *Handler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, *re
 // @Param name path string true "Pod name"
 // @Param config query string true "Kubernetes configuration ID"
 // @Param cluster query string false "Cluster name"
-// @Param container query string false "Container name (defaults to first container)"
+// @Param container query string false "Container name (defaults to the kubectl.kubernetes.io/default-container annotation, else the first container that is not a known sidecar)"
 // @Param command query string false "Command to execute (default: /bin/sh)"
+// @Param scrollback query bool false "Keep the session output for search and download; the session ID is sent in the connected status"
 // @Success 101 {string} string "WebSocket connection established"
 // @Failure 400 {object} map[string]string "Bad request"
+// @Failure 403 {object} map[string]string "Exec denied by policy (sent as a WebSocket error message)"
 // @Failure 404 {object} map[string]string "Pod not found"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /api/v1/terminal/exec/{namespace}/{name}/ws [get]
@@ -181,14 +196,27 @@ func (h *Handler) HandleExec(c *gin.Context) {
 		return
 	}
 
-	// If no container specified, use the first one
+	// If no container specified, pick the likely application container
+	var selection *utils.ContainerSelection
 	if container == "" {
-		container = GetDefaultContainer(pod)
+		chosen := utils.SelectDefaultContainer(pod)
+		selection = &chosen
+		container = chosen.Container
 	}
 
 	h.tracingHelper.RecordSuccess(validationSpan, "Pod validation completed")
 	validationSpan.End()
 
+	// Enforce the exec policy before dialing the K8s exec endpoint
+	if decision := h.authorizeExec(c, pod, container, command); !decision.Allowed {
+		err := fmt.Errorf("exec not permitted: %s", decision.Reason)
+		h.logger.WithField("pod", podName).WithField("namespace", namespace).Warn(err.Error())
+		h.sendError(conn, err.Error())
+		conn.Close()
+		h.tracingHelper.RecordError(span, err, "Terminal exec denied by policy")
+		return
+	}
+
 	// Child span for K8s connection setup
 	_, k8sSpan := h.tracingHelper.StartKubernetesAPISpan(validationCtx, "k8s_executor_setup", "pod", namespace)
 
@@ -224,8 +252,33 @@ func (h *Handler) HandleExec(c *gin.Context) {
 	// Create protocol bridge
 	bridge := NewProtocolBridge(conn, executor, h.logger)
 
+	// Tee output into a scrollback buffer when the client opted in
+	connected := NewServerMessage("status").WithStatus(StatusConnected, fmt.Sprintf("Connected to %s/%s", namespace, podName))
+	connected.Status.Pod = podName
+	connected.Status.Namespace = namespace
+	connected.Status.Container = container
+	if selection != nil {
+		connected.Status.Message = fmt.Sprintf("Connected to %s/%s, container %s (%s)", namespace, podName, container, selection.Reason)
+		connected.Status.ContainerReason = selection.Reason
+		connected.Status.Alternatives = selection.Alternatives
+	}
+	if c.Query("scrollback") == "true" && h.scrollbacks.Enabled() {
+		owner, _ := snippetOwner(c)
+		sb := h.scrollbacks.Start(ScrollbackSession{
+			Owner:     owner,
+			ConfigID:  c.Query("config"),
+			Cluster:   c.Query("cluster"),
+			Namespace: namespace,
+			Pod:       podName,
+			Container: container,
+		})
+		defer func() { sb.End(time.Now()) }()
+		bridge.SetOutputTee(sb)
+		connected.Status.Session = sb.Info().ID
+	}
+
 	// Send connected status to client
-	bridge.SendStatus(StatusConnected, fmt.Sprintf("Connected to %s/%s", namespace, podName))
+	bridge.sendToClient(connected)
 
 	// Start the bridge (this blocks until connection closes)
 	if err := bridge.Start(); err != nil {
@@ -0,0 +1,286 @@
+package terminal
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// procNetFiles are the socket tables read from the pod's network namespace; every container of a
+// pod shares it, so any container, including an ephemeral one, sees all of the pod's sockets
+var procNetFiles = []string{"/proc/net/tcp", "/proc/net/tcp6", "/proc/net/udp", "/proc/net/udp6"}
+
+// tcpStates maps the hex states of /proc/net/tcp to their names
+var tcpStates = map[string]string{
+	"01": "ESTABLISHED",
+	"02": "SYN_SENT",
+	"03": "SYN_RECV",
+	"04": "FIN_WAIT1",
+	"05": "FIN_WAIT2",
+	"06": "TIME_WAIT",
+	"07": "CLOSE",
+	"08": "CLOSE_WAIT",
+	"09": "LAST_ACK",
+	"0A": "LISTEN",
+	"0B": "CLOSING",
+}
+
+// Socket is a socket of a pod's network namespace
+type Socket struct {
+	Protocol   string    `json:"protocol"` // tcp or udp
+	Family     string    `json:"family"`   // ipv4 or ipv6
+	State      string    `json:"state"`    // TCP state; UDP sockets are LISTEN unless connected
+	LocalIP    string    `json:"localIP"`
+	LocalPort  int       `json:"localPort"`
+	RemoteIP   string    `json:"remoteIP,omitempty"`
+	RemotePort int       `json:"remotePort,omitempty"`
+	Remote     *Endpoint `json:"remote,omitempty"` // cluster object the remote address belongs to
+	UID        int       `json:"uid"`
+}
+
+// Endpoint is the cluster object behind an IP address
+type Endpoint struct {
+	Kind      string `json:"kind"` // Pod, Service or Node
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	PortName  string `json:"portName,omitempty"` // named service or container port the connection uses
+}
+
+// String returns kind/namespace/name, or kind/name for cluster-scoped objects
+func (e *Endpoint) String() string {
+	if e.Namespace == "" {
+		return e.Kind + "/" + e.Name
+	}
+	return e.Kind + "/" + e.Namespace + "/" + e.Name
+}
+
+// Peer sums up the connections to one remote endpoint
+type Peer struct {
+	Address     string    `json:"address"` // the endpoint's kind/namespace/name, or the remote IP when unresolved
+	Remote      *Endpoint `json:"remote,omitempty"`
+	Ports       []int     `json:"ports"`
+	Connections int       `json:"connections"`
+}
+
+// PodConnections is the network activity of a pod
+type PodConnections struct {
+	Namespace   string   `json:"namespace"`
+	Pod         string   `json:"pod"`
+	Container   string   `json:"container"`
+	Method      string   `json:"method"` // exec, or debug-container when read from an ephemeral container
+	Listening   []Socket `json:"listening"`
+	Connections []Socket `json:"connections"`
+	Peers       []Peer   `json:"peers"` // established connections grouped by remote endpoint
+}
+
+// parseProcNet parses the concatenated contents of procNetFiles. Each table starts with a header;
+// UDP tables are told apart by their trailing drops column and IPv6 ones by their address length.
+func parseProcNet(output string) []Socket {
+	var sockets []Socket
+	protocol := "tcp"
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "sl" {
+			protocol = "tcp"
+			if fields[len(fields)-1] == "drops" {
+				protocol = "udp"
+			}
+			continue
+		}
+		if len(fields) < 8 {
+			continue
+		}
+		localIP, localPort, err := parseProcAddress(fields[1])
+		if err != nil {
+			continue
+		}
+		remoteIP, remotePort, err := parseProcAddress(fields[2])
+		if err != nil {
+			continue
+		}
+		socket := Socket{Protocol: protocol, Family: "ipv4", LocalIP: localIP.String(), LocalPort: localPort}
+		if localIP.To4() == nil {
+			socket.Family = "ipv6"
+		}
+		socket.UID, _ = strconv.Atoi(fields[7])
+		if protocol == "tcp" {
+			socket.State = tcpStates[strings.ToUpper(fields[3])]
+		} else if remotePort == 0 {
+			socket.State = "LISTEN"
+		} else {
+			socket.State = "ESTABLISHED"
+		}
+		if socket.State != "LISTEN" {
+			socket.RemoteIP, socket.RemotePort = remoteIP.String(), remotePort
+		}
+		sockets = append(sockets, socket)
+	}
+	return sockets
+}
+
+// parseProcAddress parses an address of /proc/net/*: hex IP bytes in host (little-endian) order
+// per 32-bit word, then a colon and the hex port
+func parseProcAddress(value string) (net.IP, int, error) {
+	hexIP, hexPort, ok := strings.Cut(value, ":")
+	if !ok {
+		return nil, 0, fmt.Errorf("invalid address %q", value)
+	}
+	raw, err := hex.DecodeString(hexIP)
+	if err != nil || (len(raw) != net.IPv4len && len(raw) != net.IPv6len) {
+		return nil, 0, fmt.Errorf("invalid address %q", value)
+	}
+	port, err := strconv.ParseUint(hexPort, 16, 16)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid port in %q", value)
+	}
+	ip := make(net.IP, len(raw))
+	for word := 0; word < len(raw); word += 4 {
+		for i := 0; i < 4; i++ {
+			ip[word+i] = raw[word+3-i]
+		}
+	}
+	if v4 := ip.To4(); v4 != nil {
+		// IPv4-mapped addresses of dual-stack sockets
+		ip = v4
+	}
+	return ip, int(port), nil
+}
+
+// endpointResolver maps cluster IPs to the pods, services and nodes that own them
+type endpointResolver struct {
+	pods     map[string]*v1.Pod
+	services map[string]*v1.Service
+	nodes    map[string]string
+}
+
+func newEndpointResolver(pods []v1.Pod, services []v1.Service, nodes []v1.Node) *endpointResolver {
+	r := &endpointResolver{pods: map[string]*v1.Pod{}, services: map[string]*v1.Service{}, nodes: map[string]string{}}
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Spec.HostNetwork || pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		for _, ip := range pod.Status.PodIPs {
+			r.pods[ip.IP] = pod
+		}
+		if pod.Status.PodIP != "" {
+			r.pods[pod.Status.PodIP] = pod
+		}
+	}
+	for i := range services {
+		svc := &services[i]
+		for _, ip := range append([]string{svc.Spec.ClusterIP}, svc.Spec.ClusterIPs...) {
+			if ip != "" && ip != v1.ClusterIPNone {
+				r.services[ip] = svc
+			}
+		}
+	}
+	for _, node := range nodes {
+		for _, address := range node.Status.Addresses {
+			if address.Type == v1.NodeInternalIP || address.Type == v1.NodeExternalIP {
+				r.nodes[address.Address] = node.Name
+			}
+		}
+	}
+	return r
+}
+
+// resolve returns the cluster object behind ip, naming the port when the object declares it
+func (r *endpointResolver) resolve(ip string, port int) *Endpoint {
+	if svc, ok := r.services[ip]; ok {
+		endpoint := &Endpoint{Kind: "Service", Namespace: svc.Namespace, Name: svc.Name}
+		for _, p := range svc.Spec.Ports {
+			if int(p.Port) == port {
+				endpoint.PortName = p.Name
+			}
+		}
+		return endpoint
+	}
+	if pod, ok := r.pods[ip]; ok {
+		endpoint := &Endpoint{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name}
+		for _, container := range pod.Spec.Containers {
+			for _, p := range container.Ports {
+				if int(p.ContainerPort) == port {
+					endpoint.PortName = p.Name
+				}
+			}
+		}
+		return endpoint
+	}
+	if node, ok := r.nodes[ip]; ok {
+		return &Endpoint{Kind: "Node", Name: node}
+	}
+	return nil
+}
+
+// buildPodConnections splits sockets into listening sockets and connections, resolves remote
+// addresses and groups established connections by peer
+func buildPodConnections(sockets []Socket, resolver *endpointResolver) ([]Socket, []Socket, []Peer) {
+	listening, connections := []Socket{}, []Socket{}
+	peers := map[string]*Peer{}
+	for _, socket := range sockets {
+		if socket.State == "LISTEN" {
+			listening = append(listening, socket)
+			continue
+		}
+		if resolver != nil && !net.ParseIP(socket.RemoteIP).IsLoopback() {
+			socket.Remote = resolver.resolve(socket.RemoteIP, socket.RemotePort)
+		}
+		connections = append(connections, socket)
+		if socket.State != "ESTABLISHED" {
+			continue
+		}
+		address := socket.RemoteIP
+		if socket.Remote != nil {
+			address = socket.Remote.String()
+		}
+		peer, ok := peers[address]
+		if !ok {
+			peer = &Peer{Address: address, Remote: socket.Remote}
+			peers[address] = peer
+		}
+		peer.Connections++
+		if !containsPort(peer.Ports, socket.RemotePort) {
+			peer.Ports = append(peer.Ports, socket.RemotePort)
+		}
+	}
+
+	sort.Slice(listening, func(i, j int) bool {
+		if listening[i].LocalPort != listening[j].LocalPort {
+			return listening[i].LocalPort < listening[j].LocalPort
+		}
+		return listening[i].Protocol < listening[j].Protocol
+	})
+	sort.SliceStable(connections, func(i, j int) bool { return connections[i].State < connections[j].State })
+	peerList := make([]Peer, 0, len(peers))
+	for _, peer := range peers {
+		sort.Ints(peer.Ports)
+		peerList = append(peerList, *peer)
+	}
+	sort.Slice(peerList, func(i, j int) bool {
+		if peerList[i].Connections != peerList[j].Connections {
+			return peerList[i].Connections > peerList[j].Connections
+		}
+		return peerList[i].Address < peerList[j].Address
+	})
+	return listening, connections, peerList
+}
+
+func containsPort(ports []int, port int) bool {
+	for _, p := range ports {
+		if p == port {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package terminal
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const sampleProcNet = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 12345 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:C001 0100007F:1F90 01 00000000:00000000 00:00000000 00000000  1000        0 12346 1 0000000000000000 20 4 30 10 -1
+   2: 0A00000A:C350 0A60000A:0050 01 00000000:00000000 00:00000000 00000000  1000        0 12347 1 0000000000000000 20 4 30 10 -1
+   3: 0A00000A:C351 0302010A:1538 01 00000000:00000000 00:00000000 00000000  1000        0 12348 1 0000000000000000 20 4 30 10 -1
+   4: 0A00000A:C352 0302010A:1538 06 00000000:00000000 03:00000FA0 00000000     0        0 0 3 0000000000000000
+  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000000000000000000000000000:1F90 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 22345 1 0000000000000000 100 0 0 10 0
+   1: 0000000000000000FFFF00000A00000A:C353 0000000000000000FFFF00000A60000A:0050 01 00000000:00000000 00:00000000 00000000  1000        0 22346 1 0000000000000000 20 4 30 10 -1
+   sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode ref pointer drops
+  5: 00000000:0035 00000000:0000 07 00000000:00000000 00:00000000 00000000     0        0 999 2 0000000000000000 0
+`
+
+func TestParseProcNet(t *testing.T) {
+	sockets := parseProcNet(sampleProcNet)
+	if len(sockets) != 8 {
+		t.Fatalf("parseProcNet() returned %d sockets, want 8", len(sockets))
+	}
+	if s := sockets[0]; s.Protocol != "tcp" || s.State != "LISTEN" || s.LocalIP != "0.0.0.0" || s.LocalPort != 8080 || s.RemoteIP != "" || s.UID != 1000 {
+		t.Errorf("listening socket = %+v", s)
+	}
+	if s := sockets[2]; s.State != "ESTABLISHED" || s.LocalIP != "10.0.0.10" || s.LocalPort != 50000 || s.RemoteIP != "10.0.96.10" || s.RemotePort != 80 {
+		t.Errorf("established socket = %+v", s)
+	}
+	if s := sockets[4]; s.State != "TIME_WAIT" {
+		t.Errorf("socket state = %q, want TIME_WAIT", s.State)
+	}
+	if s := sockets[5]; s.Family != "ipv6" || s.LocalIP != "::" || s.LocalPort != 8080 {
+		t.Errorf("IPv6 listening socket = %+v", s)
+	}
+	if s := sockets[6]; s.Family != "ipv4" || s.RemoteIP != "10.0.96.10" {
+		t.Errorf("IPv4-mapped socket = %+v, want it reduced to IPv4", s)
+	}
+	if s := sockets[7]; s.Protocol != "udp" || s.State != "LISTEN" || s.LocalPort != 53 {
+		t.Errorf("UDP socket = %+v", s)
+	}
+}
+
+func TestBuildPodConnections(t *testing.T) {
+	services := []v1.Service{{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+		Spec:       v1.ServiceSpec{ClusterIP: "10.0.96.10", Ports: []v1.ServicePort{{Name: "http", Port: 80}}},
+	}}
+	pods := []v1.Pod{{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "data", Name: "db"},
+		Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "postgres", Ports: []v1.ContainerPort{{Name: "postgres", ContainerPort: 5432}}}}},
+		Status:     v1.PodStatus{Phase: v1.PodRunning, PodIP: "10.1.2.3"},
+	}}
+	resolver := newEndpointResolver(pods, services, nil)
+
+	listening, connections, peers := buildPodConnections(parseProcNet(sampleProcNet), resolver)
+	if len(listening) != 3 || listening[0].LocalPort != 53 || listening[1].LocalPort != 8080 {
+		t.Errorf("listening = %+v, want udp 53 then both 8080 sockets", listening)
+	}
+	if len(connections) != 5 {
+		t.Errorf("got %d connections, want 5", len(connections))
+	}
+	for _, conn := range connections {
+		if conn.RemoteIP == "10.1.2.3" && (conn.Remote == nil || conn.Remote.PortName != "postgres") {
+			t.Errorf("connection to the db pod resolved to %+v", conn.Remote)
+		}
+		if conn.RemoteIP == "127.0.0.1" && conn.Remote != nil {
+			t.Errorf("loopback connection resolved to %+v", conn.Remote)
+		}
+	}
+
+	if len(peers) != 3 {
+		t.Fatalf("peers = %+v, want 3", peers)
+	}
+	if p := peers[0]; p.Address != "Service/default/web" || p.Connections != 2 || len(p.Ports) != 1 || p.Remote.PortName != "http" {
+		t.Errorf("top peer = %+v, want both connections to the web service", p)
+	}
+	// The TIME_WAIT connection to the db pod is not counted
+	if p := peers[2]; p.Address != "Pod/data/db" || p.Connections != 1 {
+		t.Errorf("db peer = %+v", p)
+	}
+}
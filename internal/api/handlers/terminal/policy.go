@@ -0,0 +1,230 @@
+package terminal
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Facets-cloud/kube-dash/internal/audit"
+	"github.com/Facets-cloud/kube-dash/internal/execpolicy"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+)
+
+// containerImage returns the image of the named container, including ephemeral containers
+func containerImage(pod *v1.Pod, container string) string {
+	for _, c := range pod.Spec.Containers {
+		if c.Name == container {
+			return c.Image
+		}
+	}
+	for _, c := range pod.Spec.InitContainers {
+		if c.Name == container {
+			return c.Image
+		}
+	}
+	for _, c := range pod.Spec.EphemeralContainers {
+		if c.Name == container {
+			return c.Image
+		}
+	}
+	return ""
+}
+
+// authorizeExec evaluates the exec policy for a request and records the decision in the audit trail
+func (h *Handler) authorizeExec(c *gin.Context, pod *v1.Pod, container, command string) execpolicy.Decision {
+	req := execpolicy.Request{
+		ConfigID:  c.Query("config"),
+		Cluster:   c.Query("cluster"),
+		Namespace: pod.Namespace,
+		Pod:       pod.Name,
+		Container: container,
+		Image:     containerImage(pod, container),
+		Command:   command,
+	}
+	decision := h.policies.Evaluate(req)
+
+	outcome := audit.OutcomeAllowed
+	if !decision.Allowed {
+		outcome = audit.OutcomeDenied
+	}
+	h.auditor.Record(audit.Event{
+		Action:     "terminal.exec",
+		Outcome:    outcome,
+		Reason:     decision.Reason,
+		RemoteAddr: c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+		ConfigID:   req.ConfigID,
+		Cluster:    req.Cluster,
+		Namespace:  req.Namespace,
+		Resource:   "Pod/" + req.Pod,
+		Details: map[string]string{
+			"container": req.Container,
+			"image":     req.Image,
+			"command":   req.Command,
+			"policy":    decision.RuleName,
+		},
+	})
+	return decision
+}
+
+func (h *Handler) policyError(c *gin.Context, err error) {
+	if errors.Is(err, storage.ErrDocumentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "exec policy not found"})
+		return
+	}
+	h.logger.WithError(err).Error("Exec policy operation failed")
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+// ListExecPolicies returns all exec policy rules
+// @Summary List exec policies
+// @Description Lists the rules restricting which commands, images and namespaces may be exec'd into, along with the namespaces denied by default
+// @Tags Terminal
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Rules and default denied namespaces"
+// @Security BearerAuth
+// @Router /api/v1/terminal/policies [get]
+func (h *Handler) ListExecPolicies(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"rules":          h.policies.ListRules(),
+		"denyNamespaces": h.policies.DenyNamespaces(),
+	})
+}
+
+// GetExecPolicy returns a single exec policy rule
+// @Summary Get exec policy
+// @Description Returns an exec policy rule by ID
+// @Tags Terminal
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Success 200 {object} execpolicy.Rule "Exec policy rule"
+// @Failure 404 {object} map[string]string "Rule not found"
+// @Security BearerAuth
+// @Router /api/v1/terminal/policies/{id} [get]
+func (h *Handler) GetExecPolicy(c *gin.Context) {
+	rule, err := h.policies.GetRule(c.Param("id"))
+	if err != nil {
+		h.policyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// CreateExecPolicy creates an exec policy rule
+// @Summary Create exec policy
+// @Description Creates an allow or deny rule scoped to a config, cluster and namespace patterns, matching commands and container images. Deny rules always win; when allow rules are in scope only matching requests are permitted.
+// @Tags Terminal
+// @Accept json
+// @Produce json
+// @Param rule body execpolicy.Rule true "Exec policy rule"
+// @Success 201 {object} execpolicy.Rule "Created rule"
+// @Failure 400 {object} map[string]string "Bad request - invalid rule"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/terminal/policies [post]
+func (h *Handler) CreateExecPolicy(c *gin.Context) {
+	var rule execpolicy.Rule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	rule.ID = ""
+	if err := rule.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.policies.SaveRule(&rule); err != nil {
+		h.policyError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, rule)
+}
+
+// UpdateExecPolicy replaces an exec policy rule
+// @Summary Update exec policy
+// @Description Replaces an exec policy rule, keeping its ID and creation time
+// @Tags Terminal
+// @Accept json
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Param rule body execpolicy.Rule true "Exec policy rule"
+// @Success 200 {object} execpolicy.Rule "Updated rule"
+// @Failure 400 {object} map[string]string "Bad request - invalid rule"
+// @Failure 404 {object} map[string]string "Rule not found"
+// @Security BearerAuth
+// @Router /api/v1/terminal/policies/{id} [put]
+func (h *Handler) UpdateExecPolicy(c *gin.Context) {
+	existing, err := h.policies.GetRule(c.Param("id"))
+	if err != nil {
+		h.policyError(c, err)
+		return
+	}
+	var rule execpolicy.Rule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	rule.ID = existing.ID
+	rule.CreatedAt = existing.CreatedAt
+	if err := rule.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.policies.SaveRule(&rule); err != nil {
+		h.policyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteExecPolicy deletes an exec policy rule
+// @Summary Delete exec policy
+// @Description Deletes an exec policy rule
+// @Tags Terminal
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Success 200 {object} map[string]string "Rule deleted"
+// @Failure 404 {object} map[string]string "Rule not found"
+// @Security BearerAuth
+// @Router /api/v1/terminal/policies/{id} [delete]
+func (h *Handler) DeleteExecPolicy(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := h.policies.GetRule(id); err != nil {
+		h.policyError(c, err)
+		return
+	}
+	if err := h.policies.DeleteRule(id); err != nil {
+		h.policyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Exec policy deleted"})
+}
+
+// EvaluateExecPolicy checks whether an exec request would be allowed without connecting
+// @Summary Evaluate exec policy
+// @Description Evaluates an exec request against the current policy without opening a terminal, for testing rules
+// @Tags Terminal
+// @Accept json
+// @Produce json
+// @Param request body execpolicy.Request true "Exec request"
+// @Success 200 {object} execpolicy.Decision "Policy decision"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Security BearerAuth
+// @Router /api/v1/terminal/policies/evaluate [post]
+func (h *Handler) EvaluateExecPolicy(c *gin.Context) {
+	var req execpolicy.Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if req.Namespace == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace is required"})
+		return
+	}
+	if req.Command == "" {
+		req.Command = "/bin/sh"
+	}
+	c.JSON(http.StatusOK, h.policies.Evaluate(req))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"sync"
 	"time"
 
@@ -40,6 +41,9 @@ type ProtocolBridge struct {
 
 	// Optional resize callback for handling resize from client
 	onResize func(cols, rows uint16)
+
+	// Optional writer that receives a copy of stdout/stderr, e.g. for scrollback
+	outputTee io.Writer
 }
 
 // NewProtocolBridge creates a new protocol bridge
@@ -81,6 +85,11 @@ func (b *ProtocolBridge) SetResizeCallback(fn func(cols, rows uint16)) {
 	b.onResize = fn
 }
 
+// SetOutputTee sets a writer that receives a copy of all output sent to the client
+func (b *ProtocolBridge) SetOutputTee(w io.Writer) {
+	b.outputTee = w
+}
+
 // Start begins the bidirectional message bridging
 func (b *ProtocolBridge) Start() error {
 	// Start the write buffer processor
@@ -236,6 +245,10 @@ func (b *ProtocolBridge) bufferOutput(msgType string, data []byte) error {
 		return nil
 	}
 
+	if b.outputTee != nil {
+		b.outputTee.Write(data)
+	}
+
 	msg := NewServerMessage(msgType).WithData(string(data))
 
 	select {
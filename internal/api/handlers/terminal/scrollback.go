@@ -0,0 +1,407 @@
+package terminal
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultSearchLimit   = 100
+	maxSearchLimit       = 1000
+	defaultSearchContext = 2
+	maxSearchContext     = 20
+)
+
+// ansiEscape matches CSI, OSC and two-character terminal escape sequences
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)|\x1b[@-Z\\-_]`)
+
+// ringBuffer keeps the most recent bytes written to it, up to its capacity
+type ringBuffer struct {
+	buf     []byte
+	start   int
+	size    int
+	dropped int64
+}
+
+func newRingBuffer(capacity int) *ringBuffer {
+	return &ringBuffer{buf: make([]byte, capacity)}
+}
+
+// Write appends p, overwriting the oldest bytes once the buffer is full
+func (r *ringBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	capacity := len(r.buf)
+	if capacity == 0 {
+		r.dropped += int64(n)
+		return n, nil
+	}
+	if n >= capacity {
+		r.dropped += int64(r.size + n - capacity)
+		copy(r.buf, p[n-capacity:])
+		r.start, r.size = 0, capacity
+		return n, nil
+	}
+	if overflow := r.size + n - capacity; overflow > 0 {
+		r.start = (r.start + overflow) % capacity
+		r.size -= overflow
+		r.dropped += int64(overflow)
+	}
+	end := (r.start + r.size) % capacity
+	copied := copy(r.buf[end:], p)
+	copy(r.buf, p[copied:])
+	r.size += n
+	return n, nil
+}
+
+// Bytes returns a copy of the buffered bytes, oldest first
+func (r *ringBuffer) Bytes() []byte {
+	out := make([]byte, r.size)
+	copied := copy(out, r.buf[r.start:min(r.start+r.size, len(r.buf))])
+	copy(out[copied:], r.buf[:r.size-copied])
+	return out
+}
+
+// ScrollbackSession describes a terminal session whose output is kept for search and export
+type ScrollbackSession struct {
+	ID        string     `json:"id"`
+	Owner     string     `json:"owner,omitempty"`
+	ConfigID  string     `json:"configId"`
+	Cluster   string     `json:"cluster,omitempty"`
+	Namespace string     `json:"namespace"`
+	Pod       string     `json:"pod"`
+	Container string     `json:"container"`
+	StartedAt time.Time  `json:"startedAt"`
+	EndedAt   *time.Time `json:"endedAt,omitempty"`
+	Bytes     int        `json:"bytes"`     // output currently kept
+	Truncated bool       `json:"truncated"` // older output was dropped to stay within the limit
+}
+
+// Scrollback is the kept output of one session
+type Scrollback struct {
+	mu      sync.Mutex
+	session ScrollbackSession
+	buffer  *ringBuffer
+}
+
+// Write tees session output into the ring buffer
+func (s *Scrollback) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buffer.Write(p)
+}
+
+// End marks the session as closed, starting its retention period
+func (s *Scrollback) End(at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.session.EndedAt == nil {
+		s.session.EndedAt = &at
+	}
+}
+
+// Info returns the session metadata
+func (s *Scrollback) Info() ScrollbackSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info := s.session
+	info.Bytes = s.buffer.size
+	info.Truncated = s.buffer.dropped > 0
+	return info
+}
+
+// Raw returns the kept output exactly as received
+func (s *Scrollback) Raw() []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buffer.Bytes()
+}
+
+// Text returns the kept output as plain text lines, with escape sequences removed and carriage
+// returns applied the way a terminal overwrites a line
+func (s *Scrollback) Text() []string {
+	raw := s.Raw()
+	truncated := s.Info().Truncated
+
+	lines := strings.Split(ansiEscape.ReplaceAllString(string(raw), ""), "\n")
+	if truncated && len(lines) > 1 {
+		// The first line was cut off by the ring buffer
+		lines = lines[1:]
+	}
+	for i, line := range lines {
+		line = strings.TrimSuffix(line, "\r")
+		if cr := strings.LastIndex(line, "\r"); cr >= 0 && cr < len(line)-1 {
+			line = line[cr+1:]
+		}
+		lines[i] = strings.ReplaceAll(line, "\r", "")
+	}
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// ScrollbackMatch is a line of the scrollback that matches a search, with surrounding lines
+type ScrollbackMatch struct {
+	Line   int      `json:"line"` // 1-based line number in the exported text
+	Text   string   `json:"text"`
+	Before []string `json:"before,omitempty"`
+	After  []string `json:"after,omitempty"`
+}
+
+// searchLines finds the lines that match, up to limit, with context lines around each
+func searchLines(lines []string, match func(string) bool, limit, context int) ([]ScrollbackMatch, bool) {
+	matches := []ScrollbackMatch{}
+	for i, line := range lines {
+		if !match(line) {
+			continue
+		}
+		if len(matches) == limit {
+			return matches, true
+		}
+		m := ScrollbackMatch{Line: i + 1, Text: line}
+		if context > 0 {
+			m.Before = append([]string(nil), lines[max(0, i-context):i]...)
+			m.After = append([]string(nil), lines[i+1:min(len(lines), i+1+context)]...)
+		}
+		matches = append(matches, m)
+	}
+	return matches, false
+}
+
+// ScrollbackRegistry keeps the output of opted-in terminal sessions while they run and for a
+// retention period after they end
+type ScrollbackRegistry struct {
+	mu        sync.Mutex
+	sessions  map[string]*Scrollback
+	capacity  int
+	retention time.Duration
+	now       func() time.Time
+}
+
+// NewScrollbackRegistry creates a registry keeping up to capacity bytes of output per session
+func NewScrollbackRegistry(capacity int, retention time.Duration) *ScrollbackRegistry {
+	return &ScrollbackRegistry{
+		sessions:  map[string]*Scrollback{},
+		capacity:  capacity,
+		retention: retention,
+		now:       time.Now,
+	}
+}
+
+// Enabled reports whether sessions may keep scrollback
+func (r *ScrollbackRegistry) Enabled() bool {
+	return r != nil && r.capacity > 0
+}
+
+// Start registers a session and returns the writer its output is teed into
+func (r *ScrollbackRegistry) Start(session ScrollbackSession) *Scrollback {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pruneLocked()
+	session.ID = uuid.New().String()
+	session.StartedAt = r.now()
+	sb := &Scrollback{session: session, buffer: newRingBuffer(r.capacity)}
+	r.sessions[session.ID] = sb
+	return sb
+}
+
+// Get returns a running session or one that ended within the retention period
+func (r *ScrollbackRegistry) Get(id string) (*Scrollback, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pruneLocked()
+	sb, ok := r.sessions[id]
+	return sb, ok
+}
+
+// List returns the sessions of an owner, or all sessions for an empty owner, newest first
+func (r *ScrollbackRegistry) List(owner string) []ScrollbackSession {
+	r.mu.Lock()
+	r.pruneLocked()
+	list := make([]ScrollbackSession, 0, len(r.sessions))
+	for _, sb := range r.sessions {
+		list = append(list, sb.Info())
+	}
+	r.mu.Unlock()
+
+	filtered := list[:0]
+	for _, session := range list {
+		if owner == "" || session.Owner == owner {
+			filtered = append(filtered, session)
+		}
+	}
+	sort.Slice(filtered, func(i, j int) bool { return filtered[i].StartedAt.After(filtered[j].StartedAt) })
+	return filtered
+}
+
+// pruneLocked drops sessions that ended more than the retention period ago
+func (r *ScrollbackRegistry) pruneLocked() {
+	cutoff := r.now().Add(-r.retention)
+	for id, sb := range r.sessions {
+		if info := sb.Info(); info.EndedAt != nil && info.EndedAt.Before(cutoff) {
+			delete(r.sessions, id)
+		}
+	}
+}
+
+// ScrollbackSearchResult is the response of a scrollback search
+type ScrollbackSearchResult struct {
+	Session   ScrollbackSession `json:"session"`
+	Query     string            `json:"query"`
+	Lines     int               `json:"lines"` // lines searched
+	Matches   []ScrollbackMatch `json:"matches"`
+	Truncated bool              `json:"truncated"` // more lines matched than the limit
+}
+
+// loadScrollback returns a scrollback the caller may access. Sessions started by someone else are
+// hidden from callers authenticated with an API token.
+func (h *Handler) loadScrollback(c *gin.Context) (*Scrollback, bool) {
+	sb, ok := h.scrollbacks.Get(c.Param("id"))
+	if ok {
+		owner, authenticated := snippetOwner(c)
+		if session := sb.Info(); authenticated && session.Owner != "" && session.Owner != owner {
+			ok = false
+		}
+	}
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "terminal session not found"})
+	}
+	return sb, ok
+}
+
+// boundedQueryInt parses an optional non-negative integer query parameter, capped at max
+func boundedQueryInt(c *gin.Context, name string, def, max int) (int, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return def, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer", name)
+	}
+	return min(value, max), nil
+}
+
+// ListScrollbackSessions returns the terminal sessions with scrollback visible to the caller
+// @Summary List terminal sessions with scrollback
+// @Description Lists running terminal sessions that opted into scrollback and ended sessions still within the retention period, newest first
+// @Tags Terminal
+// @Produce json
+// @Param owner query string false "Only sessions started by this owner"
+// @Success 200 {array} ScrollbackSession "Terminal sessions"
+// @Security BearerAuth
+// @Router /api/v1/terminal/sessions [get]
+func (h *Handler) ListScrollbackSessions(c *gin.Context) {
+	owner, _ := snippetOwner(c)
+	c.JSON(http.StatusOK, h.scrollbacks.List(owner))
+}
+
+// SearchScrollback searches the output of a terminal session
+// @Summary Search terminal scrollback
+// @Description Searches the kept output of a running or recently ended terminal session. Escape sequences are removed before matching. Matching is a case-insensitive substring match unless regex is set.
+// @Tags Terminal
+// @Produce json
+// @Param id path string true "Terminal session ID"
+// @Param q query string true "Text or regular expression to search for"
+// @Param regex query bool false "Treat q as a regular expression"
+// @Param caseSensitive query bool false "Match case"
+// @Param limit query int false "Maximum matches (default 100, max 1000)"
+// @Param context query int false "Lines of context around each match (default 2, max 20)"
+// @Success 200 {object} ScrollbackSearchResult "Matching lines"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Terminal session not found"
+// @Security BearerAuth
+// @Router /api/v1/terminal/sessions/{id}/scrollback/search [get]
+func (h *Handler) SearchScrollback(c *gin.Context) {
+	query := c.Query("q")
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+	limit, err := boundedQueryInt(c, "limit", defaultSearchLimit, maxSearchLimit)
+	if err == nil && limit == 0 {
+		err = fmt.Errorf("limit must be positive")
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	context, err := boundedQueryInt(c, "context", defaultSearchContext, maxSearchContext)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	caseSensitive := c.Query("caseSensitive") == "true"
+	var match func(string) bool
+	if c.Query("regex") == "true" {
+		pattern := query
+		if !caseSensitive {
+			pattern = "(?i)" + pattern
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid regular expression: " + err.Error()})
+			return
+		}
+		match = re.MatchString
+	} else if caseSensitive {
+		match = func(line string) bool { return strings.Contains(line, query) }
+	} else {
+		lower := strings.ToLower(query)
+		match = func(line string) bool { return strings.Contains(strings.ToLower(line), lower) }
+	}
+
+	sb, ok := h.loadScrollback(c)
+	if !ok {
+		return
+	}
+	lines := sb.Text()
+	matches, truncated := searchLines(lines, match, limit, context)
+	c.JSON(http.StatusOK, ScrollbackSearchResult{
+		Session:   sb.Info(),
+		Query:     query,
+		Lines:     len(lines),
+		Matches:   matches,
+		Truncated: truncated,
+	})
+}
+
+// DownloadScrollback returns the output of a terminal session as a text file
+// @Summary Download terminal scrollback
+// @Description Downloads the kept output of a running or recently ended terminal session as plain text, with escape sequences removed unless raw is set
+// @Tags Terminal
+// @Produce plain
+// @Param id path string true "Terminal session ID"
+// @Param raw query bool false "Return the output exactly as received, including escape sequences"
+// @Success 200 {string} string "Session output"
+// @Failure 404 {object} map[string]string "Terminal session not found"
+// @Security BearerAuth
+// @Router /api/v1/terminal/sessions/{id}/scrollback/download [get]
+func (h *Handler) DownloadScrollback(c *gin.Context) {
+	sb, ok := h.loadScrollback(c)
+	if !ok {
+		return
+	}
+	session := sb.Info()
+
+	var body []byte
+	if c.Query("raw") == "true" {
+		body = sb.Raw()
+	} else if lines := sb.Text(); len(lines) > 0 {
+		body = []byte(strings.Join(lines, "\n") + "\n")
+	}
+
+	filename := fmt.Sprintf("%s-%s.txt", session.Pod, session.StartedAt.UTC().Format("20060102-150405"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", body)
+}
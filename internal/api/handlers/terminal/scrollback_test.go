@@ -0,0 +1,87 @@
+package terminal
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRingBufferKeepsMostRecentBytes(t *testing.T) {
+	r := newRingBuffer(8)
+	r.Write([]byte("abcde"))
+	r.Write([]byte("fgh"))
+	if got := string(r.Bytes()); got != "abcdefgh" || r.dropped != 0 {
+		t.Fatalf("full buffer = %q (dropped %d), want abcdefgh", got, r.dropped)
+	}
+	r.Write([]byte("ij"))
+	if got := string(r.Bytes()); got != "cdefghij" || r.dropped != 2 {
+		t.Errorf("wrapped buffer = %q (dropped %d), want cdefghij", got, r.dropped)
+	}
+	r.Write([]byte("0123456789"))
+	if got := string(r.Bytes()); got != "23456789" || r.dropped != 12 {
+		t.Errorf("oversized write = %q (dropped %d), want 23456789", got, r.dropped)
+	}
+}
+
+func TestScrollbackText(t *testing.T) {
+	sb := &Scrollback{buffer: newRingBuffer(1024)}
+	sb.Write([]byte("\x1b[1;32muser@pod\x1b[0m:~$ ls\r\n"))
+	sb.Write([]byte("Downloading 10%\rDownloading 100%\r\n"))
+	sb.Write([]byte("\x1b]0;title\x07done\n"))
+
+	want := []string{"user@pod:~$ ls", "Downloading 100%", "done"}
+	if got := sb.Text(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Text() = %q, want %q", got, want)
+	}
+
+	truncated := &Scrollback{buffer: newRingBuffer(12)}
+	truncated.Write([]byte("first line\nsecond\n"))
+	if got := truncated.Text(); !reflect.DeepEqual(got, []string{"second"}) {
+		t.Errorf("Text() after truncation = %q, want the partial first line dropped", got)
+	}
+}
+
+func TestSearchLines(t *testing.T) {
+	lines := []string{"a", "error one", "b", "c", "error two", "d", "error three"}
+	match := func(line string) bool { return strings.Contains(line, "error") }
+
+	matches, truncated := searchLines(lines, match, 2, 1)
+	if !truncated || len(matches) != 2 {
+		t.Fatalf("searchLines() = %d matches (truncated %v), want 2 truncated", len(matches), truncated)
+	}
+	if m := matches[0]; m.Line != 2 || !reflect.DeepEqual(m.Before, []string{"a"}) || !reflect.DeepEqual(m.After, []string{"b"}) {
+		t.Errorf("first match = %+v", m)
+	}
+
+	matches, truncated = searchLines(lines, match, 10, 0)
+	if truncated || len(matches) != 3 || matches[2].Line != 7 || matches[2].Before != nil {
+		t.Errorf("searchLines() without context = %+v (truncated %v)", matches, truncated)
+	}
+}
+
+func TestScrollbackRegistryRetention(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	registry := NewScrollbackRegistry(1024, 30*time.Minute)
+	registry.now = func() time.Time { return now }
+
+	ended := registry.Start(ScrollbackSession{Owner: "alice", Pod: "api"})
+	ended.End(now)
+	now = now.Add(time.Minute)
+	running := registry.Start(ScrollbackSession{Owner: "bob", Pod: "worker"})
+
+	if list := registry.List(""); len(list) != 2 || list[0].ID != running.Info().ID {
+		t.Fatalf("List() = %+v, want both sessions newest first", list)
+	}
+	if list := registry.List("alice"); len(list) != 1 || list[0].Pod != "api" {
+		t.Errorf("List(alice) = %+v", list)
+	}
+
+	now = now.Add(time.Hour)
+	if _, ok := registry.Get(ended.Info().ID); ok {
+		t.Error("ended session still available after the retention period")
+	}
+	if _, ok := registry.Get(running.Info().ID); !ok {
+		t.Error("running session was pruned")
+	}
+}
@@ -0,0 +1,310 @@
+package terminal
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Facets-cloud/kube-dash/internal/apitokens"
+	"github.com/Facets-cloud/kube-dash/internal/snippets"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RenderSnippetRequest supplies the pod a snippet runs in and values for its other variables.
+// Namespace, pod and container fill the variables of the same name unless Variables sets them.
+type RenderSnippetRequest struct {
+	Namespace string            `json:"namespace,omitempty"`
+	Pod       string            `json:"pod,omitempty"`
+	Container string            `json:"container,omitempty"`
+	Variables map[string]string `json:"variables,omitempty"`
+}
+
+// RecordCommandRequest is a command run in a terminal
+type RecordCommandRequest struct {
+	Kind    string `json:"kind" binding:"required"` // pod kind the history is kept for, e.g. the container image or owning workload
+	Command string `json:"command" binding:"required"`
+}
+
+// snippetOwner identifies the caller: the owner of the API token used, or the owner query parameter
+func snippetOwner(c *gin.Context) (string, bool) {
+	if token, ok := apitokens.FromContext(c); ok && token.Owner != "" {
+		return token.Owner, true
+	}
+	return c.Query("owner"), false
+}
+
+func (h *Handler) snippetError(c *gin.Context, err error) {
+	if errors.Is(err, storage.ErrDocumentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "snippet not found"})
+		return
+	}
+	h.logger.WithError(err).Error("Terminal snippet operation failed")
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+// loadSnippet returns a snippet the caller may access. Snippets owned by someone else are
+// hidden from callers authenticated with an API token.
+func (h *Handler) loadSnippet(c *gin.Context, id string) (*snippets.Snippet, error) {
+	snippet, err := h.snippets.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if owner, authenticated := snippetOwner(c); authenticated && snippet.Owner != "" && snippet.Owner != owner {
+		return nil, storage.ErrDocumentNotFound
+	}
+	return snippet, nil
+}
+
+// ListSnippets returns the terminal snippets visible to the caller
+// @Summary List terminal snippets
+// @Description Lists the caller's own and shared terminal command snippets for the snippet palette, optionally only those with a tag
+// @Tags Terminal
+// @Produce json
+// @Param owner query string false "Only the owner's and shared snippets"
+// @Param tag query string false "Only snippets with this tag"
+// @Success 200 {array} snippets.Snippet "Terminal snippets"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/terminal/snippets [get]
+func (h *Handler) ListSnippets(c *gin.Context) {
+	owner, _ := snippetOwner(c)
+	list, err := h.snippets.List(owner, c.Query("tag"))
+	if err != nil {
+		h.snippetError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// GetSnippet returns a single terminal snippet
+// @Summary Get terminal snippet
+// @Description Returns a terminal snippet by ID
+// @Tags Terminal
+// @Produce json
+// @Param id path string true "Snippet ID"
+// @Success 200 {object} snippets.Snippet "Terminal snippet"
+// @Failure 404 {object} map[string]string "Snippet not found"
+// @Security BearerAuth
+// @Router /api/v1/terminal/snippets/{id} [get]
+func (h *Handler) GetSnippet(c *gin.Context) {
+	snippet, err := h.loadSnippet(c, c.Param("id"))
+	if err != nil {
+		h.snippetError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, snippet)
+}
+
+// CreateSnippet saves a new terminal snippet
+// @Summary Create terminal snippet
+// @Description Saves a terminal command snippet. Commands may reference variables such as {{namespace}}, {{pod}} and {{container}}, filled in when rendered. Snippets without an owner are shared.
+// @Tags Terminal
+// @Accept json
+// @Produce json
+// @Param snippet body snippets.Snippet true "Terminal snippet"
+// @Success 201 {object} snippets.Snippet "Created snippet"
+// @Failure 400 {object} map[string]string "Bad request - invalid snippet"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/terminal/snippets [post]
+func (h *Handler) CreateSnippet(c *gin.Context) {
+	var snippet snippets.Snippet
+	if err := c.ShouldBindJSON(&snippet); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	snippet.ID = ""
+	if owner, authenticated := snippetOwner(c); authenticated {
+		snippet.Owner = owner
+	}
+	if err := snippet.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.snippets.Save(&snippet); err != nil {
+		h.snippetError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, snippet)
+}
+
+// UpdateSnippet replaces a terminal snippet
+// @Summary Update terminal snippet
+// @Description Replaces a terminal snippet, keeping its ID, owner and creation time
+// @Tags Terminal
+// @Accept json
+// @Produce json
+// @Param id path string true "Snippet ID"
+// @Param snippet body snippets.Snippet true "Terminal snippet"
+// @Success 200 {object} snippets.Snippet "Updated snippet"
+// @Failure 400 {object} map[string]string "Bad request - invalid snippet"
+// @Failure 404 {object} map[string]string "Snippet not found"
+// @Security BearerAuth
+// @Router /api/v1/terminal/snippets/{id} [put]
+func (h *Handler) UpdateSnippet(c *gin.Context) {
+	existing, err := h.loadSnippet(c, c.Param("id"))
+	if err != nil {
+		h.snippetError(c, err)
+		return
+	}
+	var snippet snippets.Snippet
+	if err := c.ShouldBindJSON(&snippet); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	snippet.ID = existing.ID
+	snippet.Owner = existing.Owner
+	snippet.CreatedAt = existing.CreatedAt
+	if err := snippet.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.snippets.Save(&snippet); err != nil {
+		h.snippetError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, snippet)
+}
+
+// DeleteSnippet deletes a terminal snippet
+// @Summary Delete terminal snippet
+// @Description Deletes a terminal snippet
+// @Tags Terminal
+// @Produce json
+// @Param id path string true "Snippet ID"
+// @Success 200 {object} map[string]string "Snippet deleted"
+// @Failure 404 {object} map[string]string "Snippet not found"
+// @Security BearerAuth
+// @Router /api/v1/terminal/snippets/{id} [delete]
+func (h *Handler) DeleteSnippet(c *gin.Context) {
+	snippet, err := h.loadSnippet(c, c.Param("id"))
+	if err != nil {
+		h.snippetError(c, err)
+		return
+	}
+	if err := h.snippets.Delete(snippet.ID); err != nil {
+		h.snippetError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Snippet deleted"})
+}
+
+// RenderSnippet fills in the variables of a snippet for a pod
+// @Summary Render terminal snippet
+// @Description Substitutes the pod's namespace, name and container and any other variable values into a snippet's command, shell-quoting values where needed. Every variable the command references must have a value.
+// @Tags Terminal
+// @Accept json
+// @Produce json
+// @Param id path string true "Snippet ID"
+// @Param request body RenderSnippetRequest true "Variable values"
+// @Success 200 {object} map[string]string "Rendered command"
+// @Failure 400 {object} map[string]string "Bad request - missing variable values"
+// @Failure 404 {object} map[string]string "Snippet not found"
+// @Security BearerAuth
+// @Router /api/v1/terminal/snippets/{id}/render [post]
+func (h *Handler) RenderSnippet(c *gin.Context) {
+	snippet, err := h.loadSnippet(c, c.Param("id"))
+	if err != nil {
+		h.snippetError(c, err)
+		return
+	}
+	var req RenderSnippetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	values := map[string]string{}
+	for name, value := range map[string]string{"namespace": req.Namespace, "pod": req.Pod, "container": req.Container} {
+		if value != "" {
+			values[name] = value
+		}
+	}
+	for name, value := range req.Variables {
+		values[name] = value
+	}
+	command, err := snippets.Render(snippet.Command, values)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"command": command})
+}
+
+// GetCommandHistory returns the caller's recent commands for a pod kind
+// @Summary Get terminal command history
+// @Description Returns the caller's most recent terminal commands for a pod kind, newest first
+// @Tags Terminal
+// @Produce json
+// @Param kind query string true "Pod kind, e.g. the container image or owning workload"
+// @Param owner query string false "History owner"
+// @Success 200 {object} snippets.History "Recent commands"
+// @Failure 400 {object} map[string]string "Bad request - kind is required"
+// @Security BearerAuth
+// @Router /api/v1/terminal/history [get]
+func (h *Handler) GetCommandHistory(c *gin.Context) {
+	kind := c.Query("kind")
+	if kind == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "kind is required"})
+		return
+	}
+	owner, _ := snippetOwner(c)
+	history, err := h.snippets.History(owner, kind)
+	if err != nil {
+		h.snippetError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, history)
+}
+
+// RecordCommand adds a command to the caller's recent commands for a pod kind
+// @Summary Record terminal command
+// @Description Adds a command to the caller's recent terminal commands for a pod kind, keeping the most recent ones
+// @Tags Terminal
+// @Accept json
+// @Produce json
+// @Param owner query string false "History owner"
+// @Param request body RecordCommandRequest true "Command run"
+// @Success 200 {object} snippets.History "Recent commands"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Security BearerAuth
+// @Router /api/v1/terminal/history [post]
+func (h *Handler) RecordCommand(c *gin.Context) {
+	var req RecordCommandRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	owner, _ := snippetOwner(c)
+	history, err := h.snippets.Record(owner, req.Kind, req.Command)
+	if err != nil {
+		h.snippetError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, history)
+}
+
+// ClearCommandHistory forgets the caller's recent commands for a pod kind
+// @Summary Clear terminal command history
+// @Description Forgets the caller's recent terminal commands for a pod kind
+// @Tags Terminal
+// @Produce json
+// @Param kind query string true "Pod kind"
+// @Param owner query string false "History owner"
+// @Success 200 {object} map[string]string "History cleared"
+// @Failure 400 {object} map[string]string "Bad request - kind is required"
+// @Security BearerAuth
+// @Router /api/v1/terminal/history [delete]
+func (h *Handler) ClearCommandHistory(c *gin.Context) {
+	kind := c.Query("kind")
+	if kind == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "kind is required"})
+		return
+	}
+	owner, _ := snippetOwner(c)
+	if err := h.snippets.ClearHistory(owner, kind); err != nil {
+		h.snippetError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Command history cleared"})
+}
@@ -74,6 +74,11 @@ type StatusPayload struct {
 	Pod       string          `json:"pod,omitempty"`
 	Namespace string          `json:"namespace,omitempty"`
 	Container string          `json:"container,omitempty"`
+	Session   string          `json:"session,omitempty"` // scrollback session ID when output is kept
+
+	// Set when the container was chosen for the client: why, and the other containers
+	ContainerReason string   `json:"containerReason,omitempty"`
+	Alternatives    []string `json:"alternatives,omitempty"`
 }
 
 // K8sErrorStatus represents the error status from K8s error channel
@@ -0,0 +1,186 @@
+package thresholds
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/internal/thresholds"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ThresholdsHandler manages metric threshold rules and their breach history
+type ThresholdsHandler struct {
+	manager *thresholds.Manager
+	logger  *logger.Logger
+}
+
+// NewThresholdsHandler creates a new metric thresholds handler
+func NewThresholdsHandler(manager *thresholds.Manager, log *logger.Logger) *ThresholdsHandler {
+	return &ThresholdsHandler{
+		manager: manager,
+		logger:  log,
+	}
+}
+
+func (h *ThresholdsHandler) ruleError(c *gin.Context, err error) {
+	if errors.Is(err, storage.ErrDocumentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "metric threshold not found"})
+		return
+	}
+	h.logger.WithError(err).Error("Metric threshold operation failed")
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+// ListThresholds returns all metric threshold rules
+// @Summary List metric thresholds
+// @Description Lists threshold rules evaluated on pod and node metrics streams, with the metrics each target kind supports
+// @Tags Metrics
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Threshold rules and supported metrics"
+// @Security BearerAuth
+// @Router /api/v1/metrics/thresholds [get]
+func (h *ThresholdsHandler) ListThresholds(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"thresholds": h.manager.ListRules(),
+		"metrics":    thresholds.Metrics,
+	})
+}
+
+// CreateThreshold creates a metric threshold rule
+// @Summary Create metric threshold
+// @Description Creates a threshold such as cpu_limit_percent > 80 for 300 seconds. It is evaluated server-side on the Prometheus metrics streams of matching pods or nodes, which report pending and firing alerts under "alerts" and record breaches. The stream's range must cover the duration.
+// @Tags Metrics
+// @Accept json
+// @Produce json
+// @Param threshold body thresholds.Rule true "Threshold rule"
+// @Success 201 {object} thresholds.Rule "Created threshold"
+// @Failure 400 {object} map[string]string "Bad request - invalid threshold"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/metrics/thresholds [post]
+func (h *ThresholdsHandler) CreateThreshold(c *gin.Context) {
+	var rule thresholds.Rule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	rule.ID = ""
+	if err := rule.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.manager.SaveRule(&rule); err != nil {
+		h.ruleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, rule)
+}
+
+// UpdateThreshold replaces a metric threshold rule
+// @Summary Update metric threshold
+// @Description Replaces a threshold rule, keeping its ID and creation time
+// @Tags Metrics
+// @Accept json
+// @Produce json
+// @Param id path string true "Threshold ID"
+// @Param threshold body thresholds.Rule true "Threshold rule"
+// @Success 200 {object} thresholds.Rule "Updated threshold"
+// @Failure 400 {object} map[string]string "Bad request - invalid threshold"
+// @Failure 404 {object} map[string]string "Threshold not found"
+// @Security BearerAuth
+// @Router /api/v1/metrics/thresholds/{id} [put]
+func (h *ThresholdsHandler) UpdateThreshold(c *gin.Context) {
+	existing, err := h.manager.GetRule(c.Param("id"))
+	if err != nil {
+		h.ruleError(c, err)
+		return
+	}
+	var rule thresholds.Rule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	rule.ID = existing.ID
+	rule.CreatedAt = existing.CreatedAt
+	if err := rule.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.manager.SaveRule(&rule); err != nil {
+		h.ruleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteThreshold deletes a metric threshold rule
+// @Summary Delete metric threshold
+// @Description Deletes a threshold rule and resolves its open breaches. Recorded breaches are kept.
+// @Tags Metrics
+// @Produce json
+// @Param id path string true "Threshold ID"
+// @Success 200 {object} map[string]string "Threshold deleted"
+// @Failure 404 {object} map[string]string "Threshold not found"
+// @Security BearerAuth
+// @Router /api/v1/metrics/thresholds/{id} [delete]
+func (h *ThresholdsHandler) DeleteThreshold(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := h.manager.GetRule(id); err != nil {
+		h.ruleError(c, err)
+		return
+	}
+	if err := h.manager.DeleteRule(id); err != nil {
+		h.ruleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Metric threshold deleted"})
+}
+
+// ListBreaches returns the recorded threshold breach history
+// @Summary List metric threshold breaches
+// @Description Lists periods during which a threshold fired for a pod or node, newest first. Breaches are recorded while a metrics stream evaluates the threshold; resolved breaches are kept for 30 days.
+// @Tags Metrics
+// @Produce json
+// @Param config query string false "Kubeconfig ID"
+// @Param cluster query string false "Cluster name"
+// @Param kind query string false "pod or node"
+// @Param namespace query string false "Pod namespace"
+// @Param name query string false "Pod or node name"
+// @Param ruleId query string false "Threshold ID"
+// @Param active query bool false "Only unresolved breaches"
+// @Param limit query int false "Maximum breaches to return" default(200)
+// @Success 200 {array} thresholds.Breach "Breaches"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/metrics/threshold-breaches [get]
+func (h *ThresholdsHandler) ListBreaches(c *gin.Context) {
+	limit := 200
+	if l := c.Query("limit"); l != "" {
+		parsed, err := strconv.Atoi(l)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		limit = parsed
+	}
+	breaches, err := h.manager.ListBreaches(thresholds.BreachFilter{
+		ConfigID:  c.Query("config"),
+		Cluster:   c.Query("cluster"),
+		Kind:      c.Query("kind"),
+		Namespace: c.Query("namespace"),
+		Name:      c.Query("name"),
+		RuleID:    c.Query("ruleId"),
+		Active:    c.Query("active") == "true",
+		Limit:     limit,
+	})
+	if err != nil {
+		h.ruleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, breaches)
+}
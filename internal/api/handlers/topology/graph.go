@@ -0,0 +1,404 @@
+package topology
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	appsV1 "k8s.io/api/apps/v1"
+	autoscalingV2 "k8s.io/api/autoscaling/v2"
+	batchV1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	networkingV1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Node is a resource in the topology graph
+type Node struct {
+	ID        string `json:"id"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Count     int    `json:"count,omitempty"` // number of collapsed replicas
+	Status    string `json:"status,omitempty"`
+}
+
+// Edge is a directed relationship between two resources
+type Edge struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Relation string `json:"relation"` // owns, routes, selects, mounts, uses, scales, binds
+}
+
+// Graph is a resource topology
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+// Resources holds the namespace objects the graph is built from. ConfigMaps, Secrets and
+// ServiceAccounts appear only when a pod references them, so they are not listed.
+type Resources struct {
+	Pods         []v1.Pod
+	Services     []v1.Service
+	PVCs         []v1.PersistentVolumeClaim
+	Deployments  []appsV1.Deployment
+	ReplicaSets  []appsV1.ReplicaSet
+	StatefulSets []appsV1.StatefulSet
+	DaemonSets   []appsV1.DaemonSet
+	Jobs         []batchV1.Job
+	CronJobs     []batchV1.CronJob
+	Ingresses    []networkingV1.Ingress
+	HPAs         []autoscalingV2.HorizontalPodAutoscaler
+}
+
+// BuildOptions controls which resources end up in the graph
+type BuildOptions struct {
+	Include  map[string]bool // kinds to keep; empty keeps all
+	Exclude  map[string]bool // kinds to drop
+	Collapse bool            // collapse pods and replica sets into their owning workload
+	Root     string          // node ID (Kind/name) to restrict the graph to its connected resources
+	Depth    int             // maximum hops from Root; 0 is unlimited
+}
+
+func nodeID(kind, name string) string {
+	return kind + "/" + name
+}
+
+type builder struct {
+	graph Graph
+	nodes map[string]int
+	edges map[string]bool
+}
+
+func (b *builder) addNode(kind string, meta metav1.ObjectMeta, status string) string {
+	id := nodeID(kind, meta.Name)
+	if _, ok := b.nodes[id]; !ok {
+		b.nodes[id] = len(b.graph.Nodes)
+		b.graph.Nodes = append(b.graph.Nodes, Node{ID: id, Kind: kind, Name: meta.Name, Namespace: meta.Namespace, Status: status})
+	}
+	return id
+}
+
+func (b *builder) addEdge(from, to, relation string) {
+	key := from + "|" + to + "|" + relation
+	if from == to || b.edges[key] {
+		return
+	}
+	b.edges[key] = true
+	b.graph.Edges = append(b.graph.Edges, Edge{From: from, To: to, Relation: relation})
+}
+
+// ownerID returns the node ID of the controller owning an object
+func ownerID(meta metav1.ObjectMeta) string {
+	if ref := metav1.GetControllerOfNoCopy(&meta); ref != nil {
+		return nodeID(ref.Kind, ref.Name)
+	}
+	return ""
+}
+
+// BuildGraph links workloads, pods, services, ingresses, config and storage by ownership,
+// selectors and pod spec references, then applies the build options
+func BuildGraph(res *Resources, opts BuildOptions) *Graph {
+	b := &builder{nodes: map[string]int{}, edges: map[string]bool{}}
+
+	for _, o := range res.Deployments {
+		b.addNode("Deployment", o.ObjectMeta, fmt.Sprintf("%d/%d ready", o.Status.ReadyReplicas, o.Status.Replicas))
+	}
+	for _, o := range res.StatefulSets {
+		b.addNode("StatefulSet", o.ObjectMeta, fmt.Sprintf("%d/%d ready", o.Status.ReadyReplicas, o.Status.Replicas))
+	}
+	for _, o := range res.DaemonSets {
+		b.addNode("DaemonSet", o.ObjectMeta, fmt.Sprintf("%d/%d ready", o.Status.NumberReady, o.Status.DesiredNumberScheduled))
+	}
+	for _, o := range res.CronJobs {
+		b.addNode("CronJob", o.ObjectMeta, "")
+	}
+	for _, o := range res.ReplicaSets {
+		// Old replica sets scaled to zero add noise without describing the running topology
+		if o.Status.Replicas == 0 && ownerID(o.ObjectMeta) != "" {
+			continue
+		}
+		id := b.addNode("ReplicaSet", o.ObjectMeta, "")
+		if owner := ownerID(o.ObjectMeta); owner != "" {
+			b.addEdge(owner, id, "owns")
+		}
+	}
+	for _, o := range res.Jobs {
+		id := b.addNode("Job", o.ObjectMeta, "")
+		if owner := ownerID(o.ObjectMeta); owner != "" {
+			b.addEdge(owner, id, "owns")
+		}
+	}
+	for i := range res.Pods {
+		pod := &res.Pods[i]
+		id := b.addNode("Pod", pod.ObjectMeta, string(pod.Status.Phase))
+		if owner := ownerID(pod.ObjectMeta); owner != "" {
+			if _, ok := b.nodes[owner]; ok {
+				b.addEdge(owner, id, "owns")
+			}
+		}
+		if sa := pod.Spec.ServiceAccountName; sa != "" && sa != "default" {
+			b.addEdge(id, b.addNode("ServiceAccount", metav1.ObjectMeta{Name: sa, Namespace: pod.Namespace}, ""), "uses")
+		}
+		for _, ref := range podReferences(&pod.Spec) {
+			b.addEdge(id, b.addNode(ref.kind, metav1.ObjectMeta{Name: ref.name, Namespace: pod.Namespace}, ""), ref.relation)
+		}
+	}
+	for _, svc := range res.Services {
+		id := b.addNode("Service", svc.ObjectMeta, string(svc.Spec.Type))
+		if len(svc.Spec.Selector) == 0 {
+			continue
+		}
+		selector := labels.SelectorFromSet(svc.Spec.Selector)
+		for _, pod := range res.Pods {
+			if selector.Matches(labels.Set(pod.Labels)) {
+				b.addEdge(id, nodeID("Pod", pod.Name), "selects")
+			}
+		}
+	}
+	for _, ing := range res.Ingresses {
+		id := b.addNode("Ingress", ing.ObjectMeta, "")
+		for _, svc := range ingressServices(&ing) {
+			b.addEdge(id, b.addNode("Service", metav1.ObjectMeta{Name: svc, Namespace: ing.Namespace}, ""), "routes")
+		}
+	}
+	for _, pvc := range res.PVCs {
+		id := b.addNode("PersistentVolumeClaim", pvc.ObjectMeta, string(pvc.Status.Phase))
+		if pvc.Spec.VolumeName != "" {
+			b.addEdge(id, b.addNode("PersistentVolume", metav1.ObjectMeta{Name: pvc.Spec.VolumeName}, ""), "binds")
+		}
+	}
+	for _, hpa := range res.HPAs {
+		id := b.addNode("HorizontalPodAutoscaler", hpa.ObjectMeta, "")
+		target := nodeID(hpa.Spec.ScaleTargetRef.Kind, hpa.Spec.ScaleTargetRef.Name)
+		if _, ok := b.nodes[target]; ok {
+			b.addEdge(id, target, "scales")
+		}
+	}
+
+	graph := &b.graph
+	if opts.Collapse {
+		graph = collapse(graph)
+	}
+	if opts.Root != "" {
+		graph = reachable(graph, opts.Root, opts.Depth)
+	}
+	graph = filterKinds(graph, opts.Include, opts.Exclude)
+	sortGraph(graph)
+	return graph
+}
+
+type podRef struct {
+	kind, name, relation string
+}
+
+// podReferences lists ConfigMaps, Secrets and PVCs a pod spec mounts or reads
+func podReferences(spec *v1.PodSpec) []podRef {
+	var refs []podRef
+	for _, vol := range spec.Volumes {
+		switch {
+		case vol.ConfigMap != nil:
+			refs = append(refs, podRef{"ConfigMap", vol.ConfigMap.Name, "mounts"})
+		case vol.Secret != nil:
+			refs = append(refs, podRef{"Secret", vol.Secret.SecretName, "mounts"})
+		case vol.PersistentVolumeClaim != nil:
+			refs = append(refs, podRef{"PersistentVolumeClaim", vol.PersistentVolumeClaim.ClaimName, "mounts"})
+		case vol.Projected != nil:
+			for _, src := range vol.Projected.Sources {
+				if src.ConfigMap != nil {
+					refs = append(refs, podRef{"ConfigMap", src.ConfigMap.Name, "mounts"})
+				}
+				if src.Secret != nil {
+					refs = append(refs, podRef{"Secret", src.Secret.Name, "mounts"})
+				}
+			}
+		}
+	}
+	containers := append(append([]v1.Container{}, spec.InitContainers...), spec.Containers...)
+	for _, c := range containers {
+		for _, from := range c.EnvFrom {
+			if from.ConfigMapRef != nil {
+				refs = append(refs, podRef{"ConfigMap", from.ConfigMapRef.Name, "uses"})
+			}
+			if from.SecretRef != nil {
+				refs = append(refs, podRef{"Secret", from.SecretRef.Name, "uses"})
+			}
+		}
+		for _, env := range c.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if ref := env.ValueFrom.ConfigMapKeyRef; ref != nil {
+				refs = append(refs, podRef{"ConfigMap", ref.Name, "uses"})
+			}
+			if ref := env.ValueFrom.SecretKeyRef; ref != nil {
+				refs = append(refs, podRef{"Secret", ref.Name, "uses"})
+			}
+		}
+	}
+	for _, ps := range spec.ImagePullSecrets {
+		refs = append(refs, podRef{"Secret", ps.Name, "uses"})
+	}
+	return refs
+}
+
+// ingressServices returns the backend services of an ingress
+func ingressServices(ing *networkingV1.Ingress) []string {
+	var services []string
+	if b := ing.Spec.DefaultBackend; b != nil && b.Service != nil {
+		services = append(services, b.Service.Name)
+	}
+	for _, rule := range ing.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			if path.Backend.Service != nil {
+				services = append(services, path.Backend.Service.Name)
+			}
+		}
+	}
+	return services
+}
+
+// collapse folds replica sets and pods into the top-level workload that owns them
+func collapse(g *Graph) *Graph {
+	parent := map[string]string{}
+	for _, e := range g.Edges {
+		if e.Relation == "owns" {
+			parent[e.To] = e.From
+		}
+	}
+	kinds := map[string]string{}
+	for _, n := range g.Nodes {
+		kinds[n.ID] = n.Kind
+	}
+	// representative maps a collapsible node to the workload it folds into
+	representative := func(id string) string {
+		for kinds[id] == "Pod" || kinds[id] == "ReplicaSet" {
+			p, ok := parent[id]
+			if !ok {
+				break
+			}
+			id = p
+		}
+		return id
+	}
+
+	out := &Graph{}
+	index := map[string]int{}
+	for _, n := range g.Nodes {
+		rep := representative(n.ID)
+		if rep == n.ID {
+			index[n.ID] = len(out.Nodes)
+			out.Nodes = append(out.Nodes, n)
+		}
+	}
+	for _, n := range g.Nodes {
+		if n.Kind == "Pod" {
+			if rep := representative(n.ID); rep != n.ID {
+				out.Nodes[index[rep]].Count++
+			}
+		}
+	}
+	seen := map[string]bool{}
+	for _, e := range g.Edges {
+		from, to := representative(e.From), representative(e.To)
+		key := from + "|" + to + "|" + e.Relation
+		if from == to || seen[key] {
+			continue
+		}
+		seen[key] = true
+		out.Edges = append(out.Edges, Edge{From: from, To: to, Relation: e.Relation})
+	}
+	return out
+}
+
+// reachable keeps nodes connected to root within depth hops, following edges in both directions
+func reachable(g *Graph, root string, depth int) *Graph {
+	adjacent := map[string][]string{}
+	for _, e := range g.Edges {
+		adjacent[e.From] = append(adjacent[e.From], e.To)
+		adjacent[e.To] = append(adjacent[e.To], e.From)
+	}
+	distance := map[string]int{}
+	for _, n := range g.Nodes {
+		if strings.EqualFold(n.ID, root) {
+			distance[n.ID] = 0
+			root = n.ID
+		}
+	}
+	if _, ok := distance[root]; !ok {
+		return &Graph{}
+	}
+	queue := []string{root}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if depth > 0 && distance[id] >= depth {
+			continue
+		}
+		for _, next := range adjacent[id] {
+			if _, ok := distance[next]; !ok {
+				distance[next] = distance[id] + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	out := &Graph{}
+	for _, n := range g.Nodes {
+		if _, ok := distance[n.ID]; ok {
+			out.Nodes = append(out.Nodes, n)
+		}
+	}
+	for _, e := range g.Edges {
+		_, fromOK := distance[e.From]
+		_, toOK := distance[e.To]
+		if fromOK && toOK {
+			out.Edges = append(out.Edges, e)
+		}
+	}
+	return out
+}
+
+// filterKinds drops nodes by kind along with their edges
+func filterKinds(g *Graph, include, exclude map[string]bool) *Graph {
+	if len(include) == 0 && len(exclude) == 0 {
+		return g
+	}
+	keep := map[string]bool{}
+	out := &Graph{}
+	for _, n := range g.Nodes {
+		kind := strings.ToLower(n.Kind)
+		if (len(include) > 0 && !include[kind]) || exclude[kind] {
+			continue
+		}
+		keep[n.ID] = true
+		out.Nodes = append(out.Nodes, n)
+	}
+	for _, e := range g.Edges {
+		if keep[e.From] && keep[e.To] {
+			out.Edges = append(out.Edges, e)
+		}
+	}
+	return out
+}
+
+func sortGraph(g *Graph) {
+	if g.Nodes == nil {
+		g.Nodes = []Node{}
+	}
+	if g.Edges == nil {
+		g.Edges = []Edge{}
+	}
+	sort.Slice(g.Nodes, func(i, j int) bool { return g.Nodes[i].ID < g.Nodes[j].ID })
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		return g.Edges[i].To < g.Edges[j].To
+	})
+}
@@ -0,0 +1,83 @@
+package topology
+
+import (
+	"strings"
+	"testing"
+
+	appsV1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func owned(name, kind, owner string, lbls map[string]string) metav1.ObjectMeta {
+	controller := true
+	return metav1.ObjectMeta{
+		Name:            name,
+		Namespace:       "shop",
+		Labels:          lbls,
+		OwnerReferences: []metav1.OwnerReference{{Kind: kind, Name: owner, Controller: &controller}},
+	}
+}
+
+func testResources() *Resources {
+	podLabels := map[string]string{"app": "api"}
+	pod := func(name string) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: owned(name, "ReplicaSet", "api-7d9f", podLabels),
+			Spec: v1.PodSpec{
+				Volumes: []v1.Volume{{Name: "cfg", VolumeSource: v1.VolumeSource{ConfigMap: &v1.ConfigMapVolumeSource{LocalObjectReference: v1.LocalObjectReference{Name: "api-config"}}}}},
+			},
+		}
+	}
+	return &Resources{
+		Deployments: []appsV1.Deployment{{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"}}},
+		ReplicaSets: []appsV1.ReplicaSet{{ObjectMeta: owned("api-7d9f", "Deployment", "api", nil), Status: appsV1.ReplicaSetStatus{Replicas: 2}}},
+		Pods:        []v1.Pod{pod("api-7d9f-a"), pod("api-7d9f-b"), {ObjectMeta: metav1.ObjectMeta{Name: "debug", Namespace: "shop"}}},
+		Services:    []v1.Service{{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"}, Spec: v1.ServiceSpec{Selector: podLabels}}},
+	}
+}
+
+func TestBuildGraphCollapse(t *testing.T) {
+	graph := BuildGraph(testResources(), BuildOptions{Collapse: true})
+
+	ids := map[string]Node{}
+	for _, n := range graph.Nodes {
+		ids[n.ID] = n
+	}
+	if _, ok := ids["Pod/api-7d9f-a"]; ok {
+		t.Fatalf("expected pods to be collapsed, got nodes %v", graph.Nodes)
+	}
+	if ids["Deployment/api"].Count != 2 {
+		t.Fatalf("expected deployment to carry 2 collapsed pods, got %d", ids["Deployment/api"].Count)
+	}
+	if _, ok := ids["Pod/debug"]; !ok {
+		t.Fatalf("expected standalone pod to remain")
+	}
+
+	want := map[string]bool{
+		"Service/api|Deployment/api|selects":         true,
+		"Deployment/api|ConfigMap/api-config|mounts": true,
+	}
+	for _, e := range graph.Edges {
+		delete(want, e.From+"|"+e.To+"|"+e.Relation)
+	}
+	if len(want) > 0 {
+		t.Fatalf("missing edges %v in %v", want, graph.Edges)
+	}
+}
+
+func TestBuildGraphRootAndExclude(t *testing.T) {
+	graph := BuildGraph(testResources(), BuildOptions{Root: "service/api", Depth: 1, Exclude: map[string]bool{"configmap": true}})
+
+	var ids []string
+	for _, n := range graph.Nodes {
+		ids = append(ids, n.ID)
+	}
+	got := strings.Join(ids, ",")
+	if got != "Pod/api-7d9f-a,Pod/api-7d9f-b,Service/api" {
+		t.Fatalf("unexpected nodes %s", got)
+	}
+	if !strings.Contains(RenderMermaid(graph), "-->|selects|") {
+		t.Fatalf("expected mermaid output to contain selects edges")
+	}
+}
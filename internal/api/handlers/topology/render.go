@@ -0,0 +1,99 @@
+package topology
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Export formats
+const (
+	FormatDOT       = "dot"
+	FormatMermaid   = "mermaid"
+	FormatCytoscape = "cytoscape"
+)
+
+// nodeLabel is the display label of a node, including the collapsed replica count
+func nodeLabel(n Node) string {
+	label := n.Kind + "\n" + n.Name
+	if n.Count > 0 {
+		label += fmt.Sprintf(" (x%d)", n.Count)
+	}
+	return label
+}
+
+// dotShapes gives each kind a recognizable Graphviz shape
+var dotShapes = map[string]string{
+	"Ingress":               "invhouse",
+	"Service":               "ellipse",
+	"Pod":                   "box",
+	"ConfigMap":             "note",
+	"Secret":                "note",
+	"PersistentVolumeClaim": "cylinder",
+	"PersistentVolume":      "cylinder",
+}
+
+// RenderDOT renders the graph in Graphviz DOT format
+func RenderDOT(g *Graph, title string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %q {\n", title)
+	b.WriteString("  rankdir=LR;\n  node [fontname=\"Helvetica\", fontsize=10, style=rounded];\n  edge [fontname=\"Helvetica\", fontsize=8];\n")
+	for _, n := range g.Nodes {
+		shape := dotShapes[n.Kind]
+		if shape == "" {
+			shape = "box3d"
+		}
+		fmt.Fprintf(&b, "  %q [label=%q, shape=%s];\n", n.ID, nodeLabel(n), shape)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %q -> %q [label=%q];\n", e.From, e.To, e.Relation)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// RenderMermaid renders the graph as a Mermaid flowchart
+func RenderMermaid(g *Graph) string {
+	ids := make(map[string]string, len(g.Nodes))
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for i, n := range g.Nodes {
+		id := fmt.Sprintf("n%d", i)
+		ids[n.ID] = id
+		label := strings.ReplaceAll(nodeLabel(n), "\n", "<br/>")
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", id, strings.ReplaceAll(label, "\"", "#quot;"))
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s -->|%s| %s\n", ids[e.From], e.Relation, ids[e.To])
+	}
+	return b.String()
+}
+
+// CytoscapeElement is a node or edge in Cytoscape.js elements JSON
+type CytoscapeElement struct {
+	Group string                 `json:"group"`
+	Data  map[string]interface{} `json:"data"`
+}
+
+// RenderCytoscape converts the graph to Cytoscape.js elements
+func RenderCytoscape(g *Graph) map[string][]CytoscapeElement {
+	elements := map[string][]CytoscapeElement{"nodes": {}, "edges": {}}
+	for _, n := range g.Nodes {
+		data := map[string]interface{}{"id": n.ID, "label": strings.ReplaceAll(nodeLabel(n), "\n", " "), "kind": n.Kind, "name": n.Name}
+		if n.Namespace != "" {
+			data["namespace"] = n.Namespace
+		}
+		if n.Count > 0 {
+			data["count"] = n.Count
+		}
+		if n.Status != "" {
+			data["status"] = n.Status
+		}
+		elements["nodes"] = append(elements["nodes"], CytoscapeElement{Group: "nodes", Data: data})
+	}
+	for i, e := range g.Edges {
+		elements["edges"] = append(elements["edges"], CytoscapeElement{Group: "edges", Data: map[string]interface{}{
+			"id": fmt.Sprintf("e%d", i), "source": e.From, "target": e.To, "label": e.Relation,
+		}})
+	}
+	return elements
+}
@@ -0,0 +1,212 @@
+package topology
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/internal/tracing"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// TopologyHandler exports resource relationship graphs
+type TopologyHandler struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	logger        *logger.Logger
+	tracingHelper *tracing.TracingHelper
+}
+
+// NewTopologyHandler creates a new topology export handler
+func NewTopologyHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger) *TopologyHandler {
+	return &TopologyHandler{
+		store:         store,
+		clientFactory: clientFactory,
+		logger:        log,
+		tracingHelper: tracing.GetTracingHelper(),
+	}
+}
+
+// getClientAndConfig gets the Kubernetes client for the current request
+func (h *TopologyHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
+	configID := c.Query("config")
+	cluster := c.Query("cluster")
+
+	if configID == "" {
+		return nil, fmt.Errorf("config parameter is required")
+	}
+
+	config, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, fmt.Errorf("config not found: %w", err)
+	}
+
+	client, err := h.clientFactory.GetClientForConfig(config, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Kubernetes client: %w", err)
+	}
+
+	return client, nil
+}
+
+// listResources lists the namespace objects used to build the graph
+func listResources(ctx context.Context, client kubernetes.Interface, namespace string) (*Resources, error) {
+	res := &Resources{}
+	opts := metav1.ListOptions{}
+
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	res.Pods = pods.Items
+	if list, err := client.CoreV1().Services(namespace).List(ctx, opts); err == nil {
+		res.Services = list.Items
+	}
+	if list, err := client.CoreV1().PersistentVolumeClaims(namespace).List(ctx, opts); err == nil {
+		res.PVCs = list.Items
+	}
+	if list, err := client.AppsV1().Deployments(namespace).List(ctx, opts); err == nil {
+		res.Deployments = list.Items
+	}
+	if list, err := client.AppsV1().ReplicaSets(namespace).List(ctx, opts); err == nil {
+		res.ReplicaSets = list.Items
+	}
+	if list, err := client.AppsV1().StatefulSets(namespace).List(ctx, opts); err == nil {
+		res.StatefulSets = list.Items
+	}
+	if list, err := client.AppsV1().DaemonSets(namespace).List(ctx, opts); err == nil {
+		res.DaemonSets = list.Items
+	}
+	if list, err := client.BatchV1().Jobs(namespace).List(ctx, opts); err == nil {
+		res.Jobs = list.Items
+	}
+	if list, err := client.BatchV1().CronJobs(namespace).List(ctx, opts); err == nil {
+		res.CronJobs = list.Items
+	}
+	if list, err := client.NetworkingV1().Ingresses(namespace).List(ctx, opts); err == nil {
+		res.Ingresses = list.Items
+	}
+	if list, err := client.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, opts); err == nil {
+		res.HPAs = list.Items
+	}
+	return res, nil
+}
+
+// kindSet parses a comma-separated kind list into a lowercase set
+func kindSet(value string) map[string]bool {
+	set := map[string]bool{}
+	for _, kind := range strings.Split(value, ",") {
+		if kind = strings.TrimSpace(kind); kind != "" {
+			set[strings.ToLower(kind)] = true
+		}
+	}
+	return set
+}
+
+// ExportTopology exports the resource graph of a namespace or of one resource's related resources
+// @Summary Export resource topology
+// @Description Builds the relationship graph of a namespace (ingresses, services, workloads, pods, config, storage and autoscalers) or of the resources related to one root resource, and exports it as Graphviz DOT, Mermaid or Cytoscape.js JSON for embedding in docs and runbooks
+// @Tags Topology
+// @Accept json
+// @Produce json
+// @Produce plain
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param namespace query string true "Namespace to export"
+// @Param format query string false "dot, mermaid or cytoscape (default cytoscape)"
+// @Param root query string false "Only export resources related to this resource, as Kind/name (e.g. Deployment/api)"
+// @Param depth query int false "Maximum hops from the root resource (default unlimited)"
+// @Param include query string false "Comma-separated kinds to include"
+// @Param exclude query string false "Comma-separated kinds to exclude"
+// @Param collapse query bool false "Collapse pods and replica sets into their owning workload"
+// @Success 200 {object} map[string]interface{} "Cytoscape elements, or DOT/Mermaid text"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 404 {object} map[string]string "Root resource not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/topology/export [get]
+func (h *TopologyHandler) ExportTopology(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for topology export")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
+
+	namespace := c.Query("namespace")
+	if namespace == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		return
+	}
+	format := c.DefaultQuery("format", FormatCytoscape)
+	if format != FormatDOT && format != FormatMermaid && format != FormatCytoscape {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be dot, mermaid or cytoscape"})
+		return
+	}
+	opts := BuildOptions{
+		Include:  kindSet(c.Query("include")),
+		Exclude:  kindSet(c.Query("exclude")),
+		Collapse: c.Query("collapse") == "true",
+		Root:     c.Query("root"),
+	}
+	if d := c.Query("depth"); d != "" {
+		depth, err := strconv.Atoi(d)
+		if err != nil || depth < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "depth must be a non-negative integer"})
+			return
+		}
+		opts.Depth = depth
+	}
+	if opts.Root != "" && !strings.Contains(opts.Root, "/") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "root must be in the form Kind/name"})
+		return
+	}
+
+	_, listSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "topology", namespace)
+	defer listSpan.End()
+
+	res, err := listResources(c.Request.Context(), client, namespace)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list resources for topology export")
+		h.tracingHelper.RecordError(listSpan, err, "Failed to list resources")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.tracingHelper.RecordSuccess(listSpan, "Listed topology resources")
+
+	_, buildSpan := h.tracingHelper.StartDataProcessingSpan(ctx, "build-topology-graph")
+	defer buildSpan.End()
+
+	graph := BuildGraph(res, opts)
+	if opts.Root != "" && len(graph.Nodes) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("resource %s not found in namespace %s", opts.Root, namespace)})
+		return
+	}
+	h.tracingHelper.AddResourceAttributes(buildSpan, namespace, "topology", len(graph.Nodes))
+	h.tracingHelper.RecordSuccess(buildSpan, fmt.Sprintf("Built graph with %d nodes and %d edges", len(graph.Nodes), len(graph.Edges)))
+
+	switch format {
+	case FormatDOT:
+		c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", namespace+".dot"))
+		c.Data(http.StatusOK, "text/vnd.graphviz; charset=utf-8", []byte(RenderDOT(graph, namespace)))
+	case FormatMermaid:
+		c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", namespace+".mmd"))
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(RenderMermaid(graph)))
+	default:
+		c.JSON(http.StatusOK, gin.H{"elements": RenderCytoscape(graph)})
+	}
+}
@@ -0,0 +1,104 @@
+package websockets
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Log frame formats a client can ask for with the format query parameter
+const (
+	// LogFormatJSON sends every line as its own JSON text frame
+	LogFormatJSON = "json"
+	// LogFormatNDJSON batches lines into text frames of newline-delimited JSON
+	LogFormatNDJSON = "ndjson"
+	// LogFormatBinary batches lines like ndjson but in binary frames, which clients read without
+	// UTF-8 validation
+	LogFormatBinary = "binary"
+)
+
+const (
+	// defaultLogBatchSize is the number of lines per frame when batching without a batch size
+	defaultLogBatchSize = 100
+	// maxLogBatchSize bounds the lines per frame, keeping single frames small enough to compress
+	// and parse quickly
+	maxLogBatchSize = 1000
+	// logBatchInterval is how long a partial batch waits for more lines before it is sent
+	logBatchInterval = 100 * time.Millisecond
+)
+
+// logFrameOptions is how log lines are framed on a connection. Control messages are always
+// single JSON text frames, so clients tell them apart from batches by frame type or line count.
+type logFrameOptions struct {
+	Format    string
+	BatchSize int
+}
+
+// batched reports whether log lines are collected into multi-line frames
+func (o logFrameOptions) batched() bool {
+	return o.Format == LogFormatNDJSON || o.Format == LogFormatBinary
+}
+
+// messageType is the WebSocket frame type that carries a batch
+func (o logFrameOptions) messageType() int {
+	if o.Format == LogFormatBinary {
+		return websocket.BinaryMessage
+	}
+	return websocket.TextMessage
+}
+
+// parseLogFrameOptions reads the format and batch-size query parameters
+func parseLogFrameOptions(values url.Values) (logFrameOptions, error) {
+	opts := logFrameOptions{Format: strings.ToLower(values.Get("format")), BatchSize: 1}
+	switch opts.Format {
+	case "", LogFormatJSON:
+		opts.Format = LogFormatJSON
+		return opts, nil
+	case LogFormatNDJSON, LogFormatBinary:
+	default:
+		return opts, fmt.Errorf("unsupported format %q; use json, ndjson or binary", opts.Format)
+	}
+
+	opts.BatchSize = defaultLogBatchSize
+	if raw := values.Get("batch-size"); raw != "" {
+		size, err := strconv.Atoi(raw)
+		if err != nil || size < 1 {
+			return opts, fmt.Errorf("batch-size must be a positive number")
+		}
+		opts.BatchSize = min(size, maxLogBatchSize)
+	}
+	return opts, nil
+}
+
+// compressionOffered reports whether the client offered permessage-deflate, which the upgrader
+// then negotiates
+func compressionOffered(header http.Header) bool {
+	for _, extensions := range header.Values("Sec-WebSocket-Extensions") {
+		for _, extension := range strings.Split(extensions, ",") {
+			name, _, _ := strings.Cut(extension, ";")
+			if strings.TrimSpace(name) == "permessage-deflate" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// encodeBatch encodes messages as newline-delimited JSON
+func encodeBatch(batch []interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, msg := range batch {
+		if err := encoder.Encode(msg); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
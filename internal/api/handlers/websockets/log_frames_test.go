@@ -0,0 +1,114 @@
+package websockets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestParseLogFrameOptions(t *testing.T) {
+	opts, err := parseLogFrameOptions(url.Values{})
+	if err != nil || opts.Format != LogFormatJSON || opts.batched() {
+		t.Errorf("default options = %+v, %v", opts, err)
+	}
+	opts, err = parseLogFrameOptions(url.Values{"format": {"binary"}})
+	if err != nil || opts.BatchSize != defaultLogBatchSize || opts.messageType() != websocket.BinaryMessage {
+		t.Errorf("binary options = %+v, %v", opts, err)
+	}
+	opts, err = parseLogFrameOptions(url.Values{"format": {"ndjson"}, "batch-size": {"5000"}})
+	if err != nil || opts.BatchSize != maxLogBatchSize || opts.messageType() != websocket.TextMessage {
+		t.Errorf("ndjson options = %+v, %v", opts, err)
+	}
+	for _, values := range []url.Values{{"format": {"xml"}}, {"format": {"ndjson"}, "batch-size": {"0"}}} {
+		if _, err := parseLogFrameOptions(values); err == nil {
+			t.Errorf("parseLogFrameOptions(%v) succeeded, want an error", values)
+		}
+	}
+
+	header := http.Header{}
+	header.Set("Sec-WebSocket-Extensions", "permessage-deflate; client_max_window_bits")
+	if !compressionOffered(header) || compressionOffered(http.Header{}) {
+		t.Error("compressionOffered() did not detect permessage-deflate")
+	}
+}
+
+// dialWriter serves a connWriter with the given options and returns the client end
+func dialWriter(t *testing.T, opts logFrameOptions) (*connWriter, *websocket.Conn) {
+	t.Helper()
+	writers := make(chan *connWriter, 1)
+	upgrader := websocket.Upgrader{EnableCompression: true}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		conn.EnableWriteCompression(true)
+		_, cancel := context.WithCancel(context.Background())
+		writers <- newConnWriter(conn, cancel, opts)
+	}))
+	t.Cleanup(server.Close)
+
+	dialer := websocket.Dialer{EnableCompression: true}
+	client, _, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return <-writers, client
+}
+
+func TestConnWriterBatchesLogLines(t *testing.T) {
+	writer, client := dialWriter(t, logFrameOptions{Format: LogFormatBinary, BatchSize: 3})
+	for i := 1; i <= 4; i++ {
+		writer.send(LogMessage{Type: "log", Message: "line", LineNumber: i})
+	}
+	writer.send(ControlMessage{Type: "previous_logs_end"})
+	writer.close()
+
+	client.SetReadDeadline(time.Now().Add(5 * time.Second))
+	want := []struct {
+		frameType int
+		lines     int
+	}{
+		{websocket.BinaryMessage, 3}, // a full batch
+		{websocket.BinaryMessage, 1}, // flushed ahead of the control message
+		{websocket.TextMessage, 1},
+	}
+	for i, w := range want {
+		frameType, data, err := client.ReadMessage()
+		if err != nil {
+			t.Fatalf("frame %d: %v", i, err)
+		}
+		lines := bytes.Split(bytes.TrimSuffix(data, []byte("\n")), []byte("\n"))
+		if frameType != w.frameType || len(lines) != w.lines {
+			t.Errorf("frame %d: type %d with %d lines, want type %d with %d", i, frameType, len(lines), w.frameType, w.lines)
+		}
+		var msg map[string]interface{}
+		if err := json.Unmarshal(lines[0], &msg); err != nil {
+			t.Errorf("frame %d: %v", i, err)
+		}
+	}
+}
+
+func TestConnWriterFlushesPartialBatch(t *testing.T) {
+	writer, client := dialWriter(t, logFrameOptions{Format: LogFormatNDJSON, BatchSize: 100})
+	defer writer.close()
+	writer.send(LogMessage{Type: "log", Message: "only line", LineNumber: 1})
+
+	client.SetReadDeadline(time.Now().Add(5 * time.Second))
+	frameType, data, err := client.ReadMessage()
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if frameType != websocket.TextMessage || !bytes.Contains(data, []byte("only line")) {
+		t.Errorf("frame = %d %q, want the partial batch after the batch interval", frameType, data)
+	}
+}
@@ -0,0 +1,256 @@
+package websockets
+
+import (
+	"container/heap"
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// logMergeWindow is how long a line waits for lines from slower containers before it is sent
+	logMergeWindow = 500 * time.Millisecond
+	// writeTimeout bounds a single WebSocket write so a stalled client cannot block streaming
+	writeTimeout = 10 * time.Second
+	// writeQueueSize is the number of messages buffered ahead of the connection
+	writeQueueSize = 256
+)
+
+// connWriter owns all writes to a WebSocket connection. Gorilla connections support one
+// concurrent writer, so every message is queued and written by a single goroutine. When the
+// frame options ask for batching, log lines are collected into multi-line frames; any other
+// message first flushes the pending batch so the stream stays in order.
+type connWriter struct {
+	conn   *websocket.Conn
+	cancel context.CancelFunc
+	opts   logFrameOptions
+	queue  chan interface{}
+	done   chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+func newConnWriter(conn *websocket.Conn, cancel context.CancelFunc, opts logFrameOptions) *connWriter {
+	w := &connWriter{
+		conn:   conn,
+		cancel: cancel,
+		opts:   opts,
+		queue:  make(chan interface{}, writeQueueSize),
+		done:   make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+func (w *connWriter) run() {
+	defer close(w.done)
+	failed := false
+	var batch []interface{}
+	var timer *time.Timer
+	var timeout <-chan time.Time
+
+	write := func(fn func() error) {
+		if failed {
+			// Keep draining so senders never block on a dead connection
+			return
+		}
+		w.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if err := fn(); err != nil {
+			failed = true
+			w.cancel()
+		}
+	}
+	flush := func() {
+		if timer != nil {
+			timer.Stop()
+			timer, timeout = nil, nil
+		}
+		if len(batch) == 0 {
+			return
+		}
+		frame, err := encodeBatch(batch)
+		batch = batch[:0]
+		if err != nil {
+			return
+		}
+		write(func() error { return w.conn.WriteMessage(w.opts.messageType(), frame) })
+	}
+
+	for {
+		select {
+		case msg, ok := <-w.queue:
+			if !ok {
+				flush()
+				return
+			}
+			if _, isLog := msg.(LogMessage); isLog && w.opts.batched() {
+				batch = append(batch, msg)
+				if len(batch) >= w.opts.BatchSize {
+					flush()
+				} else if timer == nil {
+					timer = time.NewTimer(logBatchInterval)
+					timeout = timer.C
+				}
+				continue
+			}
+			flush()
+			write(func() error { return w.conn.WriteJSON(msg) })
+		case <-timeout:
+			timer, timeout = nil, nil
+			flush()
+		}
+	}
+}
+
+// send queues a message, reporting false once the writer is closed
+func (w *connWriter) send(msg interface{}) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return false
+	}
+	w.queue <- msg
+	return true
+}
+
+// close writes the queued messages and stops the writer
+func (w *connWriter) close() {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.mu.Unlock()
+	<-w.done
+}
+
+// splitKubeletTimestamp separates the RFC3339Nano timestamp the kubelet prefixes to each line
+// when timestamps are requested
+func splitKubeletTimestamp(line string) (time.Time, string, bool) {
+	i := strings.IndexByte(line, ' ')
+	if i <= 0 {
+		return time.Time{}, line, false
+	}
+	ts, err := time.Parse(time.RFC3339Nano, line[:i])
+	if err != nil {
+		return time.Time{}, line, false
+	}
+	return ts, line[i+1:], true
+}
+
+// mergeItem is a buffered message ordered by the time of the line it belongs to
+type mergeItem struct {
+	at      time.Time
+	seq     int
+	arrived time.Time
+	msg     interface{}
+}
+
+type mergeHeap []mergeItem
+
+func (h mergeHeap) Len() int { return len(h) }
+func (h mergeHeap) Less(i, j int) bool {
+	if !h[i].at.Equal(h[j].at) {
+		return h[i].at.Before(h[j].at)
+	}
+	return h[i].seq < h[j].seq
+}
+func (h mergeHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *mergeHeap) Push(x interface{}) { *h = append(*h, x.(mergeItem)) }
+func (h *mergeHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
+
+// logMerger interleaves lines from several container streams in timestamp order. A line is
+// released once every open stream has reached its timestamp, so no earlier line can still
+// arrive, or once it has waited the merge window for streams that have gone quiet.
+type logMerger struct {
+	window time.Duration
+	emit   func(interface{})
+
+	mu      sync.Mutex
+	pending mergeHeap
+	seen    map[string]time.Time // latest line time per open stream
+	seq     int
+}
+
+func newLogMerger(window time.Duration, emit func(interface{})) *logMerger {
+	return &logMerger{window: window, emit: emit, seen: map[string]time.Time{}}
+}
+
+// open registers a stream so its lines hold back later lines of other streams
+func (m *logMerger) open(source string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.seen[source]; !ok {
+		m.seen[source] = time.Time{}
+	}
+}
+
+// add buffers a message of a stream taken at the given time and releases what is ready
+func (m *logMerger) add(source string, at time.Time, msg interface{}, now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if at.After(m.seen[source]) {
+		m.seen[source] = at
+	}
+	m.seq++
+	heap.Push(&m.pending, mergeItem{at: at, seq: m.seq, arrived: now, msg: msg})
+	m.release(now, false)
+}
+
+// close marks a stream finished so it no longer holds back other streams
+func (m *logMerger) close(source string, now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.seen, source)
+	m.release(now, false)
+}
+
+// flush releases lines that have waited the merge window, or everything when force is set
+func (m *logMerger) flush(now time.Time, force bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.release(now, force)
+}
+
+func (m *logMerger) release(now time.Time, force bool) {
+	var watermark time.Time
+	open := len(m.seen) > 0
+	first := true
+	for _, t := range m.seen {
+		if first || t.Before(watermark) {
+			watermark, first = t, false
+		}
+	}
+	for m.pending.Len() > 0 {
+		top := m.pending[0]
+		ready := force || !open || !top.at.After(watermark) || now.Sub(top.arrived) >= m.window
+		if !ready {
+			return
+		}
+		heap.Pop(&m.pending)
+		m.emit(top.msg)
+	}
+}
+
+// run flushes lines held for quiet streams until the context ends
+func (m *logMerger) run(ctx context.Context) {
+	ticker := time.NewTicker(m.window / 4)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			m.flush(now, false)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package websockets
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSplitKubeletTimestamp(t *testing.T) {
+	at, line, ok := splitKubeletTimestamp("2024-05-01T10:00:00.123456789Z level=info started")
+	if !ok || line != "level=info started" || at.Nanosecond() != 123456789 {
+		t.Errorf("unexpected split %v %q %v", at, line, ok)
+	}
+	if _, line, ok := splitKubeletTimestamp("plain line"); ok || line != "plain line" {
+		t.Errorf("expected line without timestamp to be kept, got %q %v", line, ok)
+	}
+}
+
+func TestLogMergerOrdersAcrossStreams(t *testing.T) {
+	var out []interface{}
+	m := newLogMerger(time.Second, func(msg interface{}) { out = append(out, msg) })
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	now := base
+
+	m.open("app")
+	m.open("sidecar")
+	m.add("app", base.Add(2*time.Millisecond), "app-2", now)
+	m.add("app", base.Add(4*time.Millisecond), "app-4", now)
+	if len(out) != 0 {
+		t.Fatalf("expected lines to wait for the sidecar stream, got %v", out)
+	}
+	m.add("sidecar", base.Add(1*time.Millisecond), "sidecar-1", now)
+	m.add("sidecar", base.Add(3*time.Millisecond), "sidecar-3", now)
+	if want := []interface{}{"sidecar-1", "app-2", "sidecar-3"}; !reflect.DeepEqual(out, want) {
+		t.Fatalf("expected %v, got %v", want, out)
+	}
+
+	// A quiet stream holds lines back for at most the window
+	m.flush(now.Add(500*time.Millisecond), false)
+	if len(out) != 3 {
+		t.Fatalf("expected app-4 to be held within the window, got %v", out)
+	}
+	m.flush(now.Add(time.Second), false)
+	if len(out) != 4 || out[3] != "app-4" {
+		t.Fatalf("expected app-4 after the window, got %v", out)
+	}
+
+	// Closed streams no longer hold others back; equal times keep arrival order
+	m.add("app", base.Add(5*time.Millisecond), "app-5a", now)
+	m.add("app", base.Add(5*time.Millisecond), "app-5b", now)
+	m.close("sidecar", now)
+	if want := []interface{}{"app-5a", "app-5b"}; !reflect.DeepEqual(out[4:], want) {
+		t.Errorf("expected %v, got %v", want, out[4:])
+	}
+}
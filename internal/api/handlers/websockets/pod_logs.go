@@ -2,6 +2,7 @@ package websockets
 
 import (
 	"bufio"
+	"compress/flate"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -12,6 +13,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Facets-cloud/kube-dash/internal/api/types"
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
 	"github.com/Facets-cloud/kube-dash/internal/k8s"
 	"github.com/Facets-cloud/kube-dash/internal/storage"
 	"github.com/Facets-cloud/kube-dash/internal/tracing"
@@ -39,6 +42,7 @@ type PodLogsHandler struct {
 type LogMessage struct {
 	Type          string    `json:"type"`
 	Timestamp     time.Time `json:"timestamp"`
+	TimestampMs   int64     `json:"timestampMs,omitempty"`
 	Message       string    `json:"message"`
 	Container     string    `json:"container"`
 	Level         string    `json:"level,omitempty"`
@@ -68,13 +72,15 @@ func NewPodLogsHandler(store *storage.KubeConfigStore, clientFactory *k8s.Client
 			},
 			ReadBufferSize:  1024,
 			WriteBufferSize: 1024,
+			// Negotiates permessage-deflate when the client offers it; log lines compress well
+			EnableCompression: true,
 		},
 		tracingHelper: tracing.GetTracingHelper(),
 	}
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *PodLogsHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, *rest.Config, error) {
+func (h *PodLogsHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, *rest.Config, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -117,18 +123,11 @@ func (h *PodLogsHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clients
 }
 
 // sendWebSocketMessage sends a message to the WebSocket connection
-// This function is NOT thread-safe and should be called through sendWebSocketMessageSafe
+// This function is NOT thread-safe; once streaming starts all writes go through a connWriter
 func (h *PodLogsHandler) sendWebSocketMessage(conn *websocket.Conn, message interface{}) error {
 	return conn.WriteJSON(message)
 }
 
-// sendWebSocketMessageSafe sends a message to the WebSocket connection in a thread-safe manner
-func (h *PodLogsHandler) sendWebSocketMessageSafe(conn *websocket.Conn, writeMu *sync.Mutex, message interface{}) error {
-	writeMu.Lock()
-	defer writeMu.Unlock()
-	return conn.WriteJSON(message)
-}
-
 // sendWebSocketError sends an error message to the WebSocket connection
 func (h *PodLogsHandler) sendWebSocketError(conn *websocket.Conn, errorMsg string) {
 	errorMessage := ControlMessage{
@@ -164,6 +163,11 @@ func (h *PodLogsHandler) extractTimestamp(logLine string) (time.Time, string) {
 		if match := pattern.regex.FindStringSubmatch(logLine); len(match) > 1 {
 			timestampStr := match[1]
 			if parsedTime, err := time.Parse(pattern.format, timestampStr); err == nil {
+				if pattern.format == "15:04:05" && !types.LegacyTimestamps() {
+					// A bare time of day is taken to be today, not year zero; legacy clients get it as parsed
+					now := time.Now().UTC()
+					parsedTime = time.Date(now.Year(), now.Month(), now.Day(), parsedTime.Hour(), parsedTime.Minute(), parsedTime.Second(), 0, time.UTC)
+				}
 				return parsedTime, timestampStr
 			}
 		}
@@ -196,7 +200,7 @@ func (h *PodLogsHandler) detectLogLevel(logLine string) string {
 
 // HandlePodLogs handles WebSocket-based pod logs streaming
 // @Summary Stream Pod Logs via WebSocket
-// @Description Stream real-time pod logs via WebSocket connection with support for multiple containers, previous logs, and filtering
+// @Description Stream real-time pod logs via WebSocket connection with support for multiple containers, previous logs, and filtering. Frames are compressed with permessage-deflate when the client offers it, and chatty containers can be streamed in batched ndjson or binary frames.
 // @Tags WebSocket
 // @Accept json
 // @Produce json
@@ -204,12 +208,14 @@ func (h *PodLogsHandler) detectLogLevel(logLine string) string {
 // @Param name path string true "Pod name"
 // @Param config query string true "Kubernetes configuration ID"
 // @Param cluster query string false "Cluster name"
-// @Param container query string false "Container name (defaults to first container)"
+// @Param container query string false "Container name (defaults to the kubectl.kubernetes.io/default-container annotation, else the first container that is not a known sidecar)"
 // @Param all-containers query boolean false "Stream logs from all containers"
 // @Param previous query boolean false "Include logs from previous pod instance"
 // @Param all-logs query boolean false "Get all logs (ignores tail-lines)"
 // @Param tail-lines query integer false "Number of lines to tail (default: 100)"
 // @Param since-time query string false "Start time for logs (RFC3339 format)"
+// @Param format query string false "Log frame format: json sends one JSON text frame per line (default), ndjson batches lines into text frames of newline-delimited JSON and binary sends the same batches as binary frames. Control messages are always single JSON text frames." Enums(json, ndjson, binary)
+// @Param batch-size query integer false "Lines per frame with ndjson or binary (default: 100, max: 1000); partial batches are sent after 100ms"
 // @Success 101 {string} string "WebSocket connection established"
 // @Failure 400 {object} map[string]string "Bad request"
 // @Failure 404 {object} map[string]string "Pod not found"
@@ -242,6 +248,18 @@ func (h *PodLogsHandler) HandlePodLogs(c *gin.Context) {
 	h.tracingHelper.RecordSuccess(connSpan, "WebSocket connection established")
 	connSpan.End()
 
+	compressed := compressionOffered(c.Request.Header)
+	if compressed {
+		conn.EnableWriteCompression(true)
+		conn.SetCompressionLevel(flate.BestSpeed)
+	}
+	frameOpts, err := parseLogFrameOptions(c.Request.URL.Query())
+	if err != nil {
+		h.sendWebSocketError(conn, err.Error())
+		h.tracingHelper.RecordError(span, err, "Pod logs operation failed")
+		return
+	}
+
 	// Get parameters
 	container := c.Query("container")
 	allContainers := c.Query("all-containers") == "true"
@@ -296,21 +314,6 @@ func (h *PodLogsHandler) HandlePodLogs(c *gin.Context) {
 	h.tracingHelper.RecordSuccess(validationSpan, "Pod validation completed")
 	validationSpan.End()
 
-	// Create mutex for thread-safe WebSocket writes
-	var writeMu sync.Mutex
-
-	// Send connection established message
-	connectionMsg := ControlMessage{
-		Type: "connected",
-		Data: map[string]interface{}{
-			"pod":       podName,
-			"namespace": namespace,
-			"message":   "Connected to pod logs stream",
-		},
-		Timestamp: time.Now(),
-	}
-	h.sendWebSocketMessageSafe(conn, &writeMu, connectionMsg)
-
 	// Child span for log streaming operations
 	streamCtx, streamSpan := h.tracingHelper.StartKubernetesAPISpan(validationCtx, "log_streaming", "pod", namespace)
 	defer func() {
@@ -323,6 +326,33 @@ func (h *PodLogsHandler) HandlePodLogs(c *gin.Context) {
 	streamingCtx, cancel := context.WithCancel(streamCtx)
 	defer cancel()
 
+	// All writes go through one writer goroutine from here on
+	writer := newConnWriter(conn, cancel, frameOpts)
+	defer writer.close()
+
+	// Send connection established message
+	connectionMsg := ControlMessage{
+		Type: "connected",
+		Data: map[string]interface{}{
+			"pod":         podName,
+			"namespace":   namespace,
+			"message":     "Connected to pod logs stream",
+			"format":      frameOpts.Format,
+			"batchSize":   frameOpts.BatchSize,
+			"compression": compressed,
+		},
+		Timestamp: time.Now(),
+	}
+	// Without a container, say which one was picked and what else the pod runs
+	var selection utils.ContainerSelection
+	if container == "" && !allContainers {
+		selection = utils.SelectDefaultContainer(pod)
+		connectionMsg.Data["container"] = selection.Container
+		connectionMsg.Data["containerReason"] = selection.Reason
+		connectionMsg.Data["alternatives"] = selection.Alternatives
+	}
+	writer.send(connectionMsg)
+
 	// Handle WebSocket messages from client (for pause/resume, etc.)
 	go func() {
 		for {
@@ -350,7 +380,7 @@ func (h *PodLogsHandler) HandlePodLogs(c *gin.Context) {
 								Type:      "pong",
 								Timestamp: time.Now(),
 							}
-							h.sendWebSocketMessageSafe(conn, &writeMu, pongMsg)
+							writer.send(pongMsg)
 						case "close":
 							// Client requested close
 							cancel()
@@ -362,13 +392,43 @@ func (h *PodLogsHandler) HandlePodLogs(c *gin.Context) {
 		}
 	}()
 
+	// Determine which containers to stream
+	var containersToStream []string
+	if allContainers {
+		// Stream logs from all containers
+		for _, containerSpec := range pod.Spec.Containers {
+			containersToStream = append(containersToStream, containerSpec.Name)
+		}
+	} else if container != "" {
+		// Stream logs from specific container
+		containersToStream = []string{container}
+	} else if selection.Container != "" {
+		// Default to the likely application container
+		containersToStream = []string{selection.Container}
+	}
+
+	// Lines from several containers are merged in kubelet timestamp order
+	var merger *logMerger
+	if len(containersToStream) > 1 {
+		merger = newLogMerger(logMergeWindow, func(msg interface{}) { writer.send(msg) })
+		go merger.run(streamingCtx)
+	}
+	var streams sync.WaitGroup
+
 	// Function to stream logs for a specific container with enhanced options
-	streamContainerLogs := func(containerName string, isPrevious bool, podInstance string, writeMu *sync.Mutex) error {
+	streamContainerLogs := func(containerName string, isPrevious bool, podInstance string) error {
+		source := podInstance + "/" + containerName
+		if merger != nil {
+			merger.open(source)
+			defer merger.close(source, time.Now())
+		}
+
 		// Build pod log options
 		podLogOptions := &v1.PodLogOptions{
-			Container: containerName,
-			Follow:    !isPrevious, // Don't follow for previous logs
-			Previous:  isPrevious,  // New parameter for previous logs
+			Container:  containerName,
+			Follow:     !isPrevious,   // Don't follow for previous logs
+			Previous:   isPrevious,    // New parameter for previous logs
+			Timestamps: merger != nil, // Kubelet timestamps order lines across containers
 		}
 
 		// Set tail lines based on allLogs parameter
@@ -399,7 +459,7 @@ func (h *PodLogsHandler) HandlePodLogs(c *gin.Context) {
 			},
 			Timestamp: time.Now(),
 		}
-		h.sendWebSocketMessageSafe(conn, writeMu, containerMsg)
+		writer.send(containerMsg)
 
 		// Send previous logs start message if applicable
 		if isPrevious {
@@ -411,11 +471,12 @@ func (h *PodLogsHandler) HandlePodLogs(c *gin.Context) {
 				},
 				Timestamp: time.Now(),
 			}
-			h.sendWebSocketMessageSafe(conn, writeMu, previousStartMsg)
+			writer.send(previousStartMsg)
 		}
 
 		scanner := bufio.NewScanner(stream)
 		lineNumber := 1
+		var lastAt time.Time
 
 		for scanner.Scan() {
 			select {
@@ -423,6 +484,10 @@ func (h *PodLogsHandler) HandlePodLogs(c *gin.Context) {
 				return streamingCtx.Err()
 			default:
 				logLine := scanner.Text()
+				at, stripped, ok := splitKubeletTimestamp(logLine)
+				if merger != nil && ok {
+					logLine = stripped
+				}
 				if logLine == "" {
 					continue
 				}
@@ -430,11 +495,15 @@ func (h *PodLogsHandler) HandlePodLogs(c *gin.Context) {
 				// Extract timestamp and detect log level
 				timestamp, rawTimestamp := h.extractTimestamp(logLine)
 				level := h.detectLogLevel(logLine)
+				if merger != nil && ok {
+					timestamp = at
+				}
 
 				// Create log message with enhanced fields
 				logMsg := LogMessage{
 					Type:         "log",
-					Timestamp:    timestamp,
+					Timestamp:    types.NormalizeTime(timestamp),
+					TimestampMs:  types.EpochMillis(timestamp),
 					Message:      logLine,
 					Container:    containerName,
 					Level:        level,
@@ -444,10 +513,15 @@ func (h *PodLogsHandler) HandlePodLogs(c *gin.Context) {
 					PodInstance:  podInstance,
 				}
 
-				// Send log message immediately
-				if err := h.sendWebSocketMessageSafe(conn, writeMu, logMsg); err != nil {
-					h.logger.WithError(err).Debug("Failed to send log message")
-					return err
+				if merger != nil {
+					if !ok {
+						// Lines without a kubelet timestamp stay in place within their stream
+						at = lastAt
+					}
+					lastAt = at
+					merger.add(source, at, logMsg, time.Now())
+				} else if !writer.send(logMsg) {
+					return context.Canceled
 				}
 
 				lineNumber++
@@ -469,7 +543,12 @@ func (h *PodLogsHandler) HandlePodLogs(c *gin.Context) {
 				},
 				Timestamp: time.Now(),
 			}
-			h.sendWebSocketMessageSafe(conn, writeMu, previousEndMsg)
+			if merger != nil {
+				// Queued behind the container's buffered lines
+				merger.add(source, lastAt, previousEndMsg, time.Now())
+			} else {
+				writer.send(previousEndMsg)
+			}
 		}
 
 		return nil
@@ -484,7 +563,7 @@ func (h *PodLogsHandler) HandlePodLogs(c *gin.Context) {
 				wg.Add(1)
 				go func(cName string) {
 					defer wg.Done()
-					if err := streamContainerLogs(cName, true, "previous", &writeMu); err != nil {
+					if err := streamContainerLogs(cName, true, "previous"); err != nil {
 						if streamingCtx.Err() == nil {
 							h.logger.WithError(err).WithField("container", cName).Error("Error streaming previous container logs")
 						}
@@ -493,6 +572,9 @@ func (h *PodLogsHandler) HandlePodLogs(c *gin.Context) {
 			}
 			// Wait for all previous logs to complete
 			wg.Wait()
+			if merger != nil {
+				merger.flush(time.Now(), true)
+			}
 
 			// Send transition message to indicate previous logs are complete
 			transitionMsg := ControlMessage{
@@ -504,13 +586,15 @@ func (h *PodLogsHandler) HandlePodLogs(c *gin.Context) {
 				},
 				Timestamp: time.Now(),
 			}
-			h.sendWebSocketMessageSafe(conn, &writeMu, transitionMsg)
+			writer.send(transitionMsg)
 		}
 
 		// Then, stream current logs
 		for _, containerName := range containerNames {
+			streams.Add(1)
 			go func(cName string) {
-				if err := streamContainerLogs(cName, false, "current", &writeMu); err != nil {
+				defer streams.Done()
+				if err := streamContainerLogs(cName, false, "current"); err != nil {
 					if streamingCtx.Err() == nil {
 						h.logger.WithError(err).WithField("container", cName).Error("Error streaming current container logs")
 					}
@@ -519,28 +603,15 @@ func (h *PodLogsHandler) HandlePodLogs(c *gin.Context) {
 		}
 	}
 
-	// Determine which containers to stream
-	var containersToStream []string
-	if allContainers {
-		// Stream logs from all containers
-		for _, containerSpec := range pod.Spec.Containers {
-			containersToStream = append(containersToStream, containerSpec.Name)
-		}
-	} else if container != "" {
-		// Stream logs from specific container
-		containersToStream = []string{container}
-	} else {
-		// Default to first container
-		if len(pod.Spec.Containers) > 0 {
-			containersToStream = []string{pod.Spec.Containers[0].Name}
-		}
-	}
-
 	// Start streaming for selected containers
 	streamLogsForContainers(containersToStream)
 
-	// Wait for context cancellation
+	// Wait for context cancellation, then for the streams to stop writing
 	<-streamingCtx.Done()
+	streams.Wait()
+	if merger != nil {
+		merger.flush(time.Now(), true)
+	}
 
 	// Send disconnection message
 	disconnectMsg := ControlMessage{
@@ -550,5 +621,5 @@ func (h *PodLogsHandler) HandlePodLogs(c *gin.Context) {
 		},
 		Timestamp: time.Now(),
 	}
-	h.sendWebSocketMessageSafe(conn, &writeMu, disconnectMsg)
+	writer.send(disconnectMsg)
 }
\ No newline at end of file
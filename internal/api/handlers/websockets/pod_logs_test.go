@@ -0,0 +1,29 @@
+package websockets
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/types"
+)
+
+func TestExtractTimeOfDay(t *testing.T) {
+	h := &PodLogsHandler{}
+	legacy := types.LegacyTimestamps()
+	t.Cleanup(func() { types.SetLegacyTimestamps(legacy) })
+	types.SetLegacyTimestamps(false)
+
+	// The date is read on both sides of the call so a run across midnight still passes
+	before := time.Now().UTC()
+	at, raw := h.extractTimestamp("10:15:30 request served")
+	after := time.Now().UTC()
+	sameDay := func(a, b time.Time) bool { return a.Year() == b.Year() && a.YearDay() == b.YearDay() }
+	if raw != "10:15:30" || !(sameDay(at, before) || sameDay(at, after)) || at.Hour() != 10 || at.Location() != time.UTC {
+		t.Errorf("expected a bare time of day to be taken as today in UTC, got %v (%q)", at, raw)
+	}
+
+	types.SetLegacyTimestamps(true)
+	if at, _ := h.extractTimestamp("10:15:30 request served"); at.Year() != 0 || at.Hour() != 10 {
+		t.Errorf("expected legacy mode to keep the time as parsed, got %v", at)
+	}
+}
@@ -0,0 +1,219 @@
+package workloads
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSchedule is a parsed standard five-field cron expression, as accepted by the CronJob controller
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// domStar/dowStar record an unrestricted field; when both day fields are restricted a day
+	// matching either one runs, as in Vixie cron
+	domStar, dowStar bool
+	// every is set for @every schedules, which run at a fixed interval rather than on a calendar
+	every time.Duration
+	// location is set by a CRON_TZ= or TZ= prefix, which older clusters still accept
+	location *time.Location
+}
+
+type cronField struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = cronField{0, 59, nil}
+	hourField   = cronField{0, 23, nil}
+	domField    = cronField{1, 31, nil}
+	monthField  = cronField{1, 12, map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// Sunday may be written as 0 or 7
+	dowField = cronField{0, 7, map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// parseCronSchedule parses a CronJob schedule: five fields with lists, ranges, steps and month
+// and weekday names, the @hourly style macros, and @every intervals
+func parseCronSchedule(spec string) (*cronSchedule, error) {
+	spec = strings.TrimSpace(spec)
+	sched := &cronSchedule{}
+	for _, prefix := range []string{"CRON_TZ=", "TZ="} {
+		if strings.HasPrefix(spec, prefix) {
+			parts := strings.SplitN(strings.TrimPrefix(spec, prefix), " ", 2)
+			loc, err := time.LoadLocation(parts[0])
+			if err != nil {
+				return nil, fmt.Errorf("unknown time zone %q", parts[0])
+			}
+			sched.location = loc
+			spec = ""
+			if len(parts) == 2 {
+				spec = strings.TrimSpace(parts[1])
+			}
+			break
+		}
+	}
+
+	if strings.HasPrefix(spec, "@every ") {
+		every, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+		if err != nil || every < time.Second {
+			return nil, fmt.Errorf("invalid @every interval in %q", spec)
+		}
+		sched.every = every
+		return sched, nil
+	}
+	if macro, ok := cronMacros[strings.ToLower(spec)]; ok {
+		spec = macro
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields, found %d in %q", len(fields), spec)
+	}
+	var err error
+	if sched.minute, err = minuteField.parse(fields[0]); err != nil {
+		return nil, fmt.Errorf("minute: %w", err)
+	}
+	if sched.hour, err = hourField.parse(fields[1]); err != nil {
+		return nil, fmt.Errorf("hour: %w", err)
+	}
+	if sched.dom, err = domField.parse(fields[2]); err != nil {
+		return nil, fmt.Errorf("day of month: %w", err)
+	}
+	if sched.month, err = monthField.parse(fields[3]); err != nil {
+		return nil, fmt.Errorf("month: %w", err)
+	}
+	if sched.dow, err = dowField.parse(fields[4]); err != nil {
+		return nil, fmt.Errorf("day of week: %w", err)
+	}
+	if sched.dow&(1<<7) != 0 {
+		sched.dow |= 1
+	}
+	sched.domStar = fields[2] == "*" || fields[2] == "?"
+	sched.dowStar = fields[4] == "*" || fields[4] == "?"
+	return sched, nil
+}
+
+// parse turns a field expression into a bit set of the values it matches
+func (f cronField) parse(expr string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(expr, ",") {
+		rangeExpr, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			rangeExpr, step = part[:i], n
+		}
+
+		low, high := f.min, f.max
+		switch {
+		case rangeExpr == "*" || rangeExpr == "?":
+		case strings.Contains(rangeExpr, "-"):
+			bounds := strings.SplitN(rangeExpr, "-", 2)
+			var err error
+			if low, err = f.value(bounds[0]); err != nil {
+				return 0, err
+			}
+			if high, err = f.value(bounds[1]); err != nil {
+				return 0, err
+			}
+		default:
+			v, err := f.value(rangeExpr)
+			if err != nil {
+				return 0, err
+			}
+			low, high = v, v
+			// "5/15" means every 15 starting at 5
+			if step > 1 {
+				high = f.max
+			}
+		}
+		if low > high {
+			return 0, fmt.Errorf("range %q is backwards", rangeExpr)
+		}
+		for v := low; v <= high; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("value %d out of range %d-%d", v, f.min, f.max)
+	}
+	return v, nil
+}
+
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// next returns the first run strictly after t, in loc unless the schedule names its own zone.
+// Wall-clock times skipped by a daylight saving jump do not run that day. The zero time is
+// returned when no run falls within five years, e.g. for 30 February.
+func (s *cronSchedule) next(t time.Time, loc *time.Location) time.Time {
+	if s.every > 0 {
+		return t.Add(s.every)
+	}
+	if s.location != nil {
+		loc = s.location
+	}
+	t = t.In(loc).Truncate(time.Minute).Add(time.Minute)
+	yearLimit := t.Year() + 5
+
+	for t.Year() <= yearLimit {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			next := time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			// A daylight saving jump can map the next hour back onto this one
+			if !next.After(t) {
+				next = t.Add(time.Duration(60-t.Minute()) * time.Minute)
+			}
+			t = next
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
@@ -0,0 +1,127 @@
+package workloads
+
+import (
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCronScheduleNext(t *testing.T) {
+	from := time.Date(2025, 3, 7, 10, 7, 30, 0, time.UTC) // a Friday
+	tests := []struct {
+		schedule string
+		want     string
+	}{
+		{"*/15 * * * *", "2025-03-07T10:15:00Z"},
+		{"5/20 * * * *", "2025-03-07T10:25:00Z"},
+		{"0 9-17 * * MON-FRI", "2025-03-07T11:00:00Z"},
+		{"30 2 * * sat,sun", "2025-03-08T02:30:00Z"},
+		{"0 0 1 * *", "2025-04-01T00:00:00Z"},
+		{"@weekly", "2025-03-09T00:00:00Z"},
+		{"0 0 * * 7", "2025-03-09T00:00:00Z"},
+		// Both day fields restricted: either one matching is enough
+		{"0 12 15 * 1", "2025-03-10T12:00:00Z"},
+		{"CRON_TZ=Asia/Kolkata 0 9 * * *", "2025-03-08T09:00:00+05:30"},
+	}
+	for _, tt := range tests {
+		sched, err := parseCronSchedule(tt.schedule)
+		if err != nil {
+			t.Fatalf("parseCronSchedule(%q): %v", tt.schedule, err)
+		}
+		if got := sched.next(from, time.UTC).Format(time.RFC3339); got != tt.want {
+			t.Errorf("next(%q) = %s, want %s", tt.schedule, got, tt.want)
+		}
+	}
+
+	for _, invalid := range []string{"* * * *", "60 * * * *", "*/0 * * * *", "0 0 * FOO *", "5-1 * * * *"} {
+		if _, err := parseCronSchedule(invalid); err == nil {
+			t.Errorf("parseCronSchedule(%q) should fail", invalid)
+		}
+	}
+
+	sched, _ := parseCronSchedule("0 0 30 2 *")
+	if next := sched.next(from, time.UTC); !next.IsZero() {
+		t.Errorf("30 February should never run, got %s", next)
+	}
+}
+
+func TestCronScheduleNextAcrossDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database unavailable")
+	}
+	sched, _ := parseCronSchedule("30 2 * * *")
+	// 2:30 does not exist on 9 March 2025, so that day has no run
+	next := sched.next(time.Date(2025, 3, 9, 0, 0, 0, 0, loc), loc)
+	if next.Day() != 10 || next.Hour() != 2 || next.Minute() != 30 {
+		t.Errorf("next across spring forward = %s", next)
+	}
+}
+
+func TestBuildCronJobCalendar(t *testing.T) {
+	suspend := true
+	berlin := "Europe/Berlin"
+	cronJob := func(name, schedule string) batchv1.CronJob {
+		return batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "batch"},
+			Spec:       batchv1.CronJobSpec{Schedule: schedule},
+		}
+	}
+	cronJobs := []batchv1.CronJob{
+		cronJob("report", "0 * * * *"),
+		cronJob("cleanup", "0 */2 * * *"),
+		cronJob("sync", "@hourly"),
+		cronJob("paused", "0 * * * *"),
+		cronJob("broken", "not a schedule"),
+		cronJob("local", "0 9 * * *"),
+	}
+	cronJobs[3].Spec.Suspend = &suspend
+	cronJobs[5].Spec.TimeZone = &berlin
+
+	jobs := []batchv1.Job{{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "report-1", Namespace: "batch",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "CronJob", Name: "report"}},
+		},
+		Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}},
+	}}
+
+	from := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	calendar := buildCronJobCalendar(cronJobs, jobs, from, from.Add(24*time.Hour), 15*time.Minute, 3)
+
+	if calendar.Summary.CronJobs != 6 || calendar.Summary.Suspended != 1 || calendar.Summary.Invalid != 1 || calendar.Summary.LastFailed != 1 {
+		t.Errorf("summary = %+v", calendar.Summary)
+	}
+	// 24 + 12 + 24 + 1
+	if calendar.Summary.RunsInWindow != 61 {
+		t.Errorf("runs in window = %d, want 61", calendar.Summary.RunsInWindow)
+	}
+	byName := map[string]CronJobRunSchedule{}
+	for _, entry := range calendar.CronJobs {
+		byName[entry.Name] = entry
+	}
+	if got := byName["local"]; got.NextRun == nil || got.NextRun.UTC().Hour() != 7 || got.TimeZone != berlin {
+		t.Errorf("Berlin 09:00 in summer should run at 07:00 UTC: %+v", got)
+	}
+	if got := byName["report"]; got.LastRunOutcome != runOutcomeFailed || got.LastJob != "report-1" {
+		t.Errorf("report last run = %+v", got)
+	}
+	if got := byName["paused"]; got.RunsInWindow != 0 || got.NextRun != nil {
+		t.Errorf("suspended CronJob should have no runs: %+v", got)
+	}
+	if got := byName["broken"]; got.Error == "" {
+		t.Error("invalid schedule should report an error")
+	}
+
+	// Three CronJobs start together at every even hour, and at 07:00 UTC with the Berlin one
+	if len(calendar.Hotspots) != 13 {
+		t.Fatalf("hotspots = %d, want 13", len(calendar.Hotspots))
+	}
+	first := calendar.Hotspots[0]
+	if !first.Start.Equal(from) || len(first.CronJobs) != 3 || first.CronJobs[0] != "batch/cleanup" {
+		t.Errorf("first hotspot = %+v", first)
+	}
+}
@@ -0,0 +1,352 @@
+package workloads
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+
+	"github.com/gin-gonic/gin"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultCalendarHours         = 24
+	maxCalendarHours             = 7 * 24
+	defaultCalendarBucketMinutes = 15
+	defaultCalendarMinOverlap    = 3
+	// maxListedRuns bounds the run times returned per CronJob; RunsInWindow still counts them all
+	maxListedRuns = 200
+	// maxCalendarHotspots bounds the busiest buckets returned
+	maxCalendarHotspots = 20
+)
+
+// Last run outcomes
+const (
+	runOutcomeSucceeded = "Succeeded"
+	runOutcomeFailed    = "Failed"
+	runOutcomeRunning   = "Running"
+	runOutcomeNone      = "None"
+)
+
+// CronJobRunSchedule is a CronJob's upcoming runs within the calendar window and its last run
+type CronJobRunSchedule struct {
+	Name              string      `json:"name"`
+	Namespace         string      `json:"namespace"`
+	Schedule          string      `json:"schedule"`
+	TimeZone          string      `json:"timeZone"`
+	Suspended         bool        `json:"suspended"`
+	ConcurrencyPolicy string      `json:"concurrencyPolicy"`
+	NextRun           *time.Time  `json:"nextRun,omitempty"`
+	Runs              []time.Time `json:"runs"`
+	RunsInWindow      int         `json:"runsInWindow"`
+	ActiveJobs        int         `json:"activeJobs"`
+	LastScheduleTime  *time.Time  `json:"lastScheduleTime,omitempty"`
+	LastSuccessTime   *time.Time  `json:"lastSuccessTime,omitempty"`
+	LastJob           string      `json:"lastJob,omitempty"`
+	LastRunOutcome    string      `json:"lastRunOutcome"`
+	Error             string      `json:"error,omitempty"`
+}
+
+// CronJobHotspot is a time bucket in which several CronJobs start together
+type CronJobHotspot struct {
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Runs     int       `json:"runs"`     // run starts in the bucket, counting repeats of one CronJob
+	CronJobs []string  `json:"cronJobs"` // namespace/name of each CronJob starting in the bucket
+}
+
+// CronJobCalendarSummary counts CronJobs and runs in the window
+type CronJobCalendarSummary struct {
+	CronJobs     int `json:"cronJobs"`
+	Suspended    int `json:"suspended"`
+	Invalid      int `json:"invalid"`
+	LastFailed   int `json:"lastFailed"`
+	RunsInWindow int `json:"runsInWindow"`
+}
+
+// CronJobCalendar is the batch activity timeline for a window
+type CronJobCalendar struct {
+	From          time.Time              `json:"from"`
+	To            time.Time              `json:"to"`
+	BucketMinutes int                    `json:"bucketMinutes"`
+	Summary       CronJobCalendarSummary `json:"summary"`
+	CronJobs      []CronJobRunSchedule   `json:"cronJobs"`
+	Hotspots      []CronJobHotspot       `json:"hotspots"`
+}
+
+// cronJobLocation returns the zone a CronJob's schedule is evaluated in. Without spec.timeZone the
+// controller uses its own local zone, which is UTC on almost every control plane.
+func cronJobLocation(cronJob *batchv1.CronJob) (*time.Location, error) {
+	if cronJob.Spec.TimeZone == nil || *cronJob.Spec.TimeZone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(*cronJob.Spec.TimeZone)
+}
+
+// lastRun finds the most recently created Job owned by a CronJob and its outcome
+func lastRun(cronJob *batchv1.CronJob, jobs []batchv1.Job) (string, string) {
+	var latest *batchv1.Job
+	for i := range jobs {
+		job := &jobs[i]
+		if job.Namespace != cronJob.Namespace {
+			continue
+		}
+		owned := false
+		for _, ref := range job.OwnerReferences {
+			// Jobs left behind by an earlier CronJob of the same name have a different owner UID
+			if ref.Kind == "CronJob" && ref.Name == cronJob.Name && (ref.UID == "" || cronJob.UID == "" || ref.UID == cronJob.UID) {
+				owned = true
+			}
+		}
+		if owned && (latest == nil || job.CreationTimestamp.After(latest.CreationTimestamp.Time)) {
+			latest = job
+		}
+	}
+	if latest == nil {
+		return "", runOutcomeNone
+	}
+	for _, condition := range latest.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return latest.Name, runOutcomeSucceeded
+		case batchv1.JobFailed:
+			return latest.Name, runOutcomeFailed
+		}
+	}
+	return latest.Name, runOutcomeRunning
+}
+
+// buildCronJobCalendar computes each CronJob's runs in [from, to) and the buckets where at least
+// minOverlap CronJobs start together
+func buildCronJobCalendar(cronJobs []batchv1.CronJob, jobs []batchv1.Job, from, to time.Time, bucket time.Duration, minOverlap int) CronJobCalendar {
+	calendar := CronJobCalendar{
+		From:          from,
+		To:            to,
+		BucketMinutes: int(bucket / time.Minute),
+		CronJobs:      make([]CronJobRunSchedule, 0, len(cronJobs)),
+		Hotspots:      []CronJobHotspot{},
+	}
+	buckets := map[int64]*CronJobHotspot{}
+
+	for i := range cronJobs {
+		cronJob := &cronJobs[i]
+		entry := CronJobRunSchedule{
+			Name:              cronJob.Name,
+			Namespace:         cronJob.Namespace,
+			Schedule:          cronJob.Spec.Schedule,
+			TimeZone:          "UTC",
+			Suspended:         cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend,
+			ConcurrencyPolicy: string(cronJob.Spec.ConcurrencyPolicy),
+			Runs:              []time.Time{},
+			ActiveJobs:        len(cronJob.Status.Active),
+		}
+		if entry.ConcurrencyPolicy == "" {
+			entry.ConcurrencyPolicy = string(batchv1.AllowConcurrent)
+		}
+		if cronJob.Status.LastScheduleTime != nil {
+			entry.LastScheduleTime = &cronJob.Status.LastScheduleTime.Time
+		}
+		if cronJob.Status.LastSuccessfulTime != nil {
+			entry.LastSuccessTime = &cronJob.Status.LastSuccessfulTime.Time
+		}
+		entry.LastJob, entry.LastRunOutcome = lastRun(cronJob, jobs)
+
+		calendar.Summary.CronJobs++
+		if entry.LastRunOutcome == runOutcomeFailed {
+			calendar.Summary.LastFailed++
+		}
+		if entry.Suspended {
+			calendar.Summary.Suspended++
+		}
+
+		loc, err := cronJobLocation(cronJob)
+		var sched *cronSchedule
+		if err == nil {
+			sched, err = parseCronSchedule(cronJob.Spec.Schedule)
+		}
+		if err != nil {
+			entry.Error = err.Error()
+			calendar.Summary.Invalid++
+			calendar.CronJobs = append(calendar.CronJobs, entry)
+			continue
+		}
+		entry.TimeZone = loc.String()
+		if sched.location != nil {
+			entry.TimeZone = sched.location.String()
+		}
+
+		if !entry.Suspended {
+			name := cronJob.Namespace + "/" + cronJob.Name
+			next := sched.next(from.Add(-time.Nanosecond), loc)
+			if !next.IsZero() {
+				entry.NextRun = &next
+			}
+			for run := next; !run.IsZero() && run.Before(to); run = sched.next(run, loc) {
+				entry.RunsInWindow++
+				if len(entry.Runs) < maxListedRuns {
+					entry.Runs = append(entry.Runs, run)
+				}
+				index := int64(run.Sub(from) / bucket)
+				hotspot, ok := buckets[index]
+				if !ok {
+					start := from.Add(time.Duration(index) * bucket)
+					hotspot = &CronJobHotspot{Start: start, End: start.Add(bucket), CronJobs: []string{}}
+					buckets[index] = hotspot
+				}
+				hotspot.Runs++
+				if len(hotspot.CronJobs) == 0 || hotspot.CronJobs[len(hotspot.CronJobs)-1] != name {
+					hotspot.CronJobs = append(hotspot.CronJobs, name)
+				}
+			}
+			calendar.Summary.RunsInWindow += entry.RunsInWindow
+		}
+		calendar.CronJobs = append(calendar.CronJobs, entry)
+	}
+
+	for _, hotspot := range buckets {
+		if len(hotspot.CronJobs) >= minOverlap {
+			sort.Strings(hotspot.CronJobs)
+			calendar.Hotspots = append(calendar.Hotspots, *hotspot)
+		}
+	}
+	sort.Slice(calendar.Hotspots, func(i, j int) bool {
+		a, b := calendar.Hotspots[i], calendar.Hotspots[j]
+		if len(a.CronJobs) != len(b.CronJobs) {
+			return len(a.CronJobs) > len(b.CronJobs)
+		}
+		return a.Start.Before(b.Start)
+	})
+	if len(calendar.Hotspots) > maxCalendarHotspots {
+		calendar.Hotspots = calendar.Hotspots[:maxCalendarHotspots]
+	}
+
+	// Soonest first; suspended and invalid CronJobs last
+	sort.SliceStable(calendar.CronJobs, func(i, j int) bool {
+		a, b := calendar.CronJobs[i], calendar.CronJobs[j]
+		if (a.NextRun == nil) != (b.NextRun == nil) {
+			return a.NextRun != nil
+		}
+		if a.NextRun != nil && !a.NextRun.Equal(*b.NextRun) {
+			return a.NextRun.Before(*b.NextRun)
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return calendar
+}
+
+// calendarIntParam reads an integer query parameter within [min, max], or its default when absent
+func calendarIntParam(c *gin.Context, name string, def, min, max int) (int, error) {
+	value := c.Query(name)
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min || n > max {
+		return 0, fmt.Errorf("%s must be an integer between %d and %d", name, min, max)
+	}
+	return n, nil
+}
+
+// GetCronJobCalendar returns the upcoming runs of all CronJobs in scope and their overlap hotspots
+// @Summary CronJob schedule calendar
+// @Description Parses every CronJob schedule in scope, honoring spec.timeZone, and returns run times within the window, the next run, overlap hotspots where several CronJobs start in the same bucket, and each CronJob's last run outcome. Suspended CronJobs are listed without runs.
+// @Tags Workloads
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name for multi-cluster setups"
+// @Param namespace query string false "Kubernetes namespace to filter resources"
+// @Param namespaces query string false "Comma-separated namespaces to list across, instead of namespace"
+// @Param from query string false "Window start (RFC3339, default now)"
+// @Param hours query int false "Window length in hours (default 24, max 168)"
+// @Param bucketMinutes query int false "Hotspot bucket size in minutes (default 15)"
+// @Param minOverlap query int false "CronJobs starting in one bucket to report it as a hotspot (default 3)"
+// @Success 200 {object} CronJobCalendar "CronJob calendar"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/cronjobs/calendar [get]
+func (h *CronJobsHandler) GetCronJobCalendar(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for cronjob calendar")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
+
+	from := time.Now().Truncate(time.Minute)
+	if v := c.Query("from"); v != "" {
+		if from, err = time.Parse(time.RFC3339, v); err != nil {
+			utils.RespondErrorMessage(c, http.StatusBadRequest, "from must be an RFC3339 timestamp")
+			return
+		}
+	}
+	hours, err := calendarIntParam(c, "hours", defaultCalendarHours, 1, maxCalendarHours)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	bucketMinutes, err := calendarIntParam(c, "bucketMinutes", defaultCalendarBucketMinutes, 1, 24*60)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	minOverlap, err := calendarIntParam(c, "minOverlap", defaultCalendarMinOverlap, 2, 1000)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	namespaces := utils.RequestedNamespaces(c)
+	namespace := strings.Join(namespaces, ",")
+
+	_, listSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "cronjobs", namespace)
+	defer listSpan.End()
+
+	cronJobs, err := utils.ListInNamespaces(c.Request.Context(), namespaces, func(ctx context.Context, namespace string) ([]batchv1.CronJob, error) {
+		list, err := client.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	})
+	var jobs []batchv1.Job
+	if err == nil {
+		jobs, err = utils.ListInNamespaces(c.Request.Context(), namespaces, func(ctx context.Context, namespace string) ([]batchv1.Job, error) {
+			list, err := client.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+	}
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list cronjobs for calendar")
+		h.tracingHelper.RecordError(listSpan, err, "Failed to list cronjobs")
+		utils.RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	h.tracingHelper.AddResourceAttributes(listSpan, "", "cronjobs", len(cronJobs))
+	h.tracingHelper.RecordSuccess(listSpan, fmt.Sprintf("Listed %d cronjobs", len(cronJobs)))
+
+	to := from.Add(time.Duration(hours) * time.Hour)
+	c.JSON(http.StatusOK, buildCronJobCalendar(cronJobs, jobs, from, to, time.Duration(bucketMinutes)*time.Minute, minOverlap))
+}
@@ -1,8 +1,10 @@
 package workloads
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/Facets-cloud/kube-dash/internal/api/transformers"
 	"github.com/Facets-cloud/kube-dash/internal/api/types"
@@ -43,7 +45,7 @@ func NewCronJobsHandler(store *storage.KubeConfigStore, clientFactory *k8s.Clien
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *CronJobsHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *CronJobsHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -73,6 +75,12 @@ func (h *CronJobsHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Client
 // @Param config query string true "Kubernetes configuration ID"
 // @Param cluster query string false "Cluster name for multi-cluster setups"
 // @Param namespace query string false "Kubernetes namespace to filter resources"
+// @Param namespaces query string false "Comma-separated namespaces to list across, instead of namespace"
+// @Param namespaceGroup query string false "ID of a saved namespace group to list across"
+// @Param fields query string false "Comma-separated response fields to return, e.g. name,status,restarts; name, namespace and uid are always included"
+// @Param labelSelector query string false "Kubernetes label selector, e.g. app=web,tier!=cache"
+// @Param sort query string false "Response field to sort by, e.g. name or -restarts for descending"
+// @Param view query string false "ID of a saved view supplying namespaces, labelSelector, sort and fields"
 // @Success 200 {array} types.CronJobListResponse "Streaming CronJobs data"
 // @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
 // @Failure 403 {object} map[string]string "Forbidden - insufficient permissions"
@@ -94,7 +102,8 @@ func (h *CronJobsHandler) GetCronJobsSSE(c *gin.Context) {
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client setup for SSE")
 
-	namespace := c.Query("namespace")
+	namespaces := utils.RequestedNamespaces(c)
+	namespace := strings.Join(namespaces, ",")
 
 	// Function to fetch cronjobs data
 	fetchCronJobs := func() (interface{}, error) {
@@ -102,7 +111,14 @@ func (h *CronJobsHandler) GetCronJobsSSE(c *gin.Context) {
 		_, fetchSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "cronjobs", namespace)
 		defer fetchSpan.End()
 
-		cronJobList, err := client.BatchV1().CronJobs(namespace).List(c.Request.Context(), metav1.ListOptions{})
+		items, err := utils.ListInNamespaces(c.Request.Context(), namespaces, func(ctx context.Context, namespace string) ([]batchv1.CronJob, error) {
+			list, err := client.BatchV1().CronJobs(namespace).List(ctx, utils.ListOptions(c))
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		cronJobList := &batchv1.CronJobList{Items: items}
 		if err != nil {
 			h.tracingHelper.RecordError(fetchSpan, err, "Failed to list cronjobs for SSE")
 			return nil, err
@@ -148,7 +164,7 @@ func (h *CronJobsHandler) GetCronJobsSSE(c *gin.Context) {
 	}
 
 	// For non-SSE requests, return JSON
-	c.JSON(http.StatusOK, initialData)
+	h.sseHandler.SendJSON(c, initialData)
 }
 
 // GetCronJob returns a specific cronjob
@@ -181,7 +197,7 @@ func (h *CronJobsHandler) GetCronJob(c *gin.Context) {
 		if c.GetHeader("Accept") == "text/event-stream" {
 			h.sseHandler.SendSSEError(c, http.StatusBadRequest, err.Error())
 		} else {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			utils.RespondError(c, http.StatusBadRequest, err)
 		}
 		return
 	}
@@ -202,7 +218,7 @@ func (h *CronJobsHandler) GetCronJob(c *gin.Context) {
 		if c.GetHeader("Accept") == "text/event-stream" {
 			h.sseHandler.SendSSEError(c, http.StatusNotFound, err.Error())
 		} else {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			utils.RespondError(c, http.StatusNotFound, err)
 		}
 		return
 	}
@@ -212,7 +228,7 @@ func (h *CronJobsHandler) GetCronJob(c *gin.Context) {
 	// Check if this is an SSE request (EventSource expects SSE format)
 	acceptHeader := c.GetHeader("Accept")
 	if acceptHeader == "text/event-stream" {
-		h.sseHandler.SendSSEResponse(c, cronJob)
+		h.sseHandler.SendSSEDetailResponse(c, cronJob, cronJob, client.BatchV1().CronJobs(namespace).Watch)
 		return
 	}
 
@@ -240,7 +256,7 @@ func (h *CronJobsHandler) GetCronJobByName(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for cronjob by name")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -248,14 +264,14 @@ func (h *CronJobsHandler) GetCronJobByName(c *gin.Context) {
 	namespace := c.Query("namespace")
 
 	if namespace == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
 	cronJob, err := client.BatchV1().CronJobs(namespace).Get(c.Request.Context(), name, metav1.GetOptions{})
 	if err != nil {
 		h.logger.WithError(err).WithField("cronjob", name).WithField("namespace", namespace).Error("Failed to get cronjob by name")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -283,7 +299,7 @@ func (h *CronJobsHandler) GetCronJobYAMLByName(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for cronjob YAML by name")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -291,14 +307,14 @@ func (h *CronJobsHandler) GetCronJobYAMLByName(c *gin.Context) {
 	namespace := c.Query("namespace")
 
 	if namespace == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
 	cronJob, err := client.BatchV1().CronJobs(namespace).Get(c.Request.Context(), name, metav1.GetOptions{})
 	if err != nil {
 		h.logger.WithError(err).WithField("cronjob", name).WithField("namespace", namespace).Error("Failed to get cronjob for YAML by name")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -326,7 +342,7 @@ func (h *CronJobsHandler) GetCronJobYAML(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for cronjob YAML")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -336,7 +352,7 @@ func (h *CronJobsHandler) GetCronJobYAML(c *gin.Context) {
 	cronJob, err := client.BatchV1().CronJobs(namespace).Get(c.Request.Context(), name, metav1.GetOptions{})
 	if err != nil {
 		h.logger.WithError(err).WithField("cronjob", name).WithField("namespace", namespace).Error("Failed to get cronjob for YAML")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -364,7 +380,7 @@ func (h *CronJobsHandler) GetCronJobEventsByName(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for cronjob events")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -373,7 +389,7 @@ func (h *CronJobsHandler) GetCronJobEventsByName(c *gin.Context) {
 
 	if namespace == "" {
 		h.logger.WithField("cronjob", name).Error("Namespace is required for cronjob events lookup")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -400,7 +416,7 @@ func (h *CronJobsHandler) GetCronJobEvents(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for cronjob events")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -429,7 +445,7 @@ func (h *CronJobsHandler) GetCronJobJobsByName(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for cronjob jobs by name")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -437,7 +453,7 @@ func (h *CronJobsHandler) GetCronJobJobsByName(c *gin.Context) {
 	namespace := c.Query("namespace")
 
 	if namespace == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -447,7 +463,7 @@ func (h *CronJobsHandler) GetCronJobJobsByName(c *gin.Context) {
 	})
 	if err != nil {
 		h.logger.WithError(err).WithField("cronjob", name).WithField("namespace", namespace).Error("Failed to get cronjob jobs")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -475,7 +491,7 @@ func (h *CronJobsHandler) TriggerCronJob(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for cronjob trigger")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -486,7 +502,7 @@ func (h *CronJobsHandler) TriggerCronJob(c *gin.Context) {
 	cronJob, err := client.BatchV1().CronJobs(namespace).Get(c.Request.Context(), name, metav1.GetOptions{})
 	if err != nil {
 		h.logger.WithError(err).WithField("cronjob", name).WithField("namespace", namespace).Error("Failed to get cronjob for trigger")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -506,7 +522,7 @@ func (h *CronJobsHandler) TriggerCronJob(c *gin.Context) {
 	createdJob, err := client.BatchV1().Jobs(namespace).Create(c.Request.Context(), job, metav1.CreateOptions{})
 	if err != nil {
 		h.logger.WithError(err).WithField("cronjob", name).WithField("namespace", namespace).Error("Failed to create job from cronjob")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -542,7 +558,7 @@ func (h *CronJobsHandler) SuspendCronJob(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for cronjob suspend")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -555,7 +571,7 @@ func (h *CronJobsHandler) SuspendCronJob(c *gin.Context) {
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.WithError(err).Error("Failed to parse suspend request")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
@@ -563,7 +579,7 @@ func (h *CronJobsHandler) SuspendCronJob(c *gin.Context) {
 	cronJob, err := client.BatchV1().CronJobs(namespace).Get(c.Request.Context(), name, metav1.GetOptions{})
 	if err != nil {
 		h.logger.WithError(err).WithField("cronjob", name).WithField("namespace", namespace).Error("Failed to get cronjob for suspend")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -574,7 +590,7 @@ func (h *CronJobsHandler) SuspendCronJob(c *gin.Context) {
 	updatedCronJob, err := client.BatchV1().CronJobs(namespace).Update(c.Request.Context(), cronJob, metav1.UpdateOptions{})
 	if err != nil {
 		h.logger.WithError(err).WithField("cronjob", name).WithField("namespace", namespace).WithField("suspend", req.Suspend).Error("Failed to update cronjob suspend state")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
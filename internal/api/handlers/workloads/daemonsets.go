@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/Facets-cloud/kube-dash/internal/api/transformers"
@@ -15,6 +16,7 @@ import (
 	"github.com/Facets-cloud/kube-dash/pkg/logger"
 
 	"github.com/gin-gonic/gin"
+	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -44,7 +46,7 @@ func NewDaemonSetsHandler(store *storage.KubeConfigStore, clientFactory *k8s.Cli
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *DaemonSetsHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *DaemonSetsHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -74,6 +76,12 @@ func (h *DaemonSetsHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clie
 // @Param config query string true "Kubernetes config ID"
 // @Param cluster query string false "Cluster name (for multi-cluster configs)"
 // @Param namespace query string false "Namespace to filter daemonsets (empty for all namespaces)"
+// @Param namespaces query string false "Comma-separated namespaces to list across, instead of namespace"
+// @Param namespaceGroup query string false "ID of a saved namespace group to list across"
+// @Param fields query string false "Comma-separated response fields to return, e.g. name,status,restarts; name, namespace and uid are always included"
+// @Param labelSelector query string false "Kubernetes label selector, e.g. app=web,tier!=cache"
+// @Param sort query string false "Response field to sort by, e.g. name or -restarts for descending"
+// @Param view query string false "ID of a saved view supplying namespaces, labelSelector, sort and fields"
 // @Success 200 {array} types.DaemonSetListResponse "Stream of daemonset data"
 // @Failure 400 {object} map[string]string "Bad request - invalid parameters"
 // @Failure 403 {object} map[string]string "Forbidden - insufficient permissions"
@@ -95,7 +103,8 @@ func (h *DaemonSetsHandler) GetDaemonSetsSSE(c *gin.Context) {
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client setup for SSE")
 
-	namespace := c.Query("namespace")
+	namespaces := utils.RequestedNamespaces(c)
+	namespace := strings.Join(namespaces, ",")
 
 	// Function to fetch and transform daemonsets data
 	fetchDaemonSets := func() (interface{}, error) {
@@ -103,7 +112,14 @@ func (h *DaemonSetsHandler) GetDaemonSetsSSE(c *gin.Context) {
 		_, fetchSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "daemonsets", namespace)
 		defer fetchSpan.End()
 
-		daemonSetList, err := client.AppsV1().DaemonSets(namespace).List(c.Request.Context(), metav1.ListOptions{})
+		items, err := utils.ListInNamespaces(c.Request.Context(), namespaces, func(ctx context.Context, namespace string) ([]appsv1.DaemonSet, error) {
+			list, err := client.AppsV1().DaemonSets(namespace).List(ctx, utils.ListOptions(c))
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		daemonSetList := &appsv1.DaemonSetList{Items: items}
 		if err != nil {
 			h.tracingHelper.RecordError(fetchSpan, err, "Failed to list daemonsets for SSE")
 			return nil, err
@@ -149,7 +165,7 @@ func (h *DaemonSetsHandler) GetDaemonSetsSSE(c *gin.Context) {
 	}
 
 	// For non-SSE requests, return JSON
-	c.JSON(http.StatusOK, initialData)
+	h.sseHandler.SendJSON(c, initialData)
 }
 
 // GetDaemonSet returns a specific daemonset
@@ -181,7 +197,7 @@ func (h *DaemonSetsHandler) GetDaemonSet(c *gin.Context) {
 		if c.GetHeader("Accept") == "text/event-stream" {
 			h.sseHandler.SendSSEError(c, http.StatusBadRequest, err.Error())
 		} else {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			utils.RespondError(c, http.StatusBadRequest, err)
 		}
 		return
 	}
@@ -202,7 +218,7 @@ func (h *DaemonSetsHandler) GetDaemonSet(c *gin.Context) {
 		if c.GetHeader("Accept") == "text/event-stream" {
 			h.sseHandler.SendSSEError(c, http.StatusNotFound, err.Error())
 		} else {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			utils.RespondError(c, http.StatusNotFound, err)
 		}
 		return
 	}
@@ -213,7 +229,7 @@ func (h *DaemonSetsHandler) GetDaemonSet(c *gin.Context) {
 	// Check if this is an SSE request (EventSource expects SSE format)
 	acceptHeader := c.GetHeader("Accept")
 	if acceptHeader == "text/event-stream" {
-		h.sseHandler.SendSSEResponse(c, daemonSet)
+		h.sseHandler.SendSSEDetailResponse(c, daemonSet, daemonSet, client.AppsV1().DaemonSets(namespace).Watch)
 		return
 	}
 
@@ -245,7 +261,7 @@ func (h *DaemonSetsHandler) GetDaemonSetByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for daemonset by name")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
@@ -254,7 +270,7 @@ func (h *DaemonSetsHandler) GetDaemonSetByName(c *gin.Context) {
 	namespace := c.Query("namespace")
 
 	if namespace == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -266,7 +282,7 @@ func (h *DaemonSetsHandler) GetDaemonSetByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("daemonset", name).WithField("namespace", namespace).Error("Failed to get daemonset by name")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get daemonset by name")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -301,7 +317,7 @@ func (h *DaemonSetsHandler) GetDaemonSetYAMLByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for daemonset YAML by name")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
@@ -310,7 +326,7 @@ func (h *DaemonSetsHandler) GetDaemonSetYAMLByName(c *gin.Context) {
 	namespace := c.Query("namespace")
 
 	if namespace == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -322,7 +338,7 @@ func (h *DaemonSetsHandler) GetDaemonSetYAMLByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("daemonset", name).WithField("namespace", namespace).Error("Failed to get daemonset for YAML by name")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get daemonset for YAML")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -362,7 +378,7 @@ func (h *DaemonSetsHandler) GetDaemonSetYAML(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for daemonset YAML")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
@@ -378,7 +394,7 @@ func (h *DaemonSetsHandler) GetDaemonSetYAML(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("daemonset", name).WithField("namespace", namespace).Error("Failed to get daemonset for YAML")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get daemonset for YAML")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -412,7 +428,7 @@ func (h *DaemonSetsHandler) GetDaemonSetEventsByName(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for daemonset events")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -421,7 +437,7 @@ func (h *DaemonSetsHandler) GetDaemonSetEventsByName(c *gin.Context) {
 
 	if namespace == "" {
 		h.logger.WithField("daemonset", name).Error("Namespace is required for daemonset events lookup")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -447,7 +463,7 @@ func (h *DaemonSetsHandler) GetDaemonSetEvents(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for daemonset events")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -476,7 +492,7 @@ func (h *DaemonSetsHandler) GetDaemonSetPods(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for daemonset pods")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -487,7 +503,7 @@ func (h *DaemonSetsHandler) GetDaemonSetPods(c *gin.Context) {
 	daemonSet, err := client.AppsV1().DaemonSets(namespace).Get(c.Request.Context(), name, metav1.GetOptions{})
 	if err != nil {
 		h.logger.WithError(err).WithField("daemonset", name).WithField("namespace", namespace).Error("Failed to get daemonset for pods")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -503,7 +519,7 @@ func (h *DaemonSetsHandler) GetDaemonSetPods(c *gin.Context) {
 	})
 	if err != nil {
 		h.logger.WithError(err).WithField("daemonset", name).WithField("namespace", namespace).Error("Failed to get daemonset pods")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -542,7 +558,7 @@ func (h *DaemonSetsHandler) RestartDaemonSet(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for restarting daemonset")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error(), "code": http.StatusBadRequest})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
@@ -550,7 +566,7 @@ func (h *DaemonSetsHandler) RestartDaemonSet(c *gin.Context) {
 	name := c.Param("name")
 	namespace := c.Query("namespace")
 	if namespace == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "namespace parameter is required", "code": http.StatusBadRequest})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -563,7 +579,7 @@ func (h *DaemonSetsHandler) RestartDaemonSet(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("daemonset", name).WithField("namespace", namespace).Error("Failed to perform rolling restart")
 		h.tracingHelper.RecordError(restartSpan, err, "Failed to perform rolling restart")
-		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error(), "code": http.StatusBadRequest})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -574,7 +590,7 @@ func (h *DaemonSetsHandler) RestartDaemonSet(c *gin.Context) {
 }
 
 // performRollingRestart performs a rolling restart by adding a restart annotation
-func (h *DaemonSetsHandler) performRollingRestart(client *kubernetes.Clientset, name, namespace string) error {
+func (h *DaemonSetsHandler) performRollingRestart(client kubernetes.Interface, name, namespace string) error {
 	// Get the current daemonset
 	daemonSet, err := client.AppsV1().DaemonSets(namespace).Get(context.Background(), name, metav1.GetOptions{})
 	if err != nil {
@@ -617,7 +633,7 @@ func (h *DaemonSetsHandler) GetDaemonSetPodsByName(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for daemonset pods by name")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -625,7 +641,7 @@ func (h *DaemonSetsHandler) GetDaemonSetPodsByName(c *gin.Context) {
 	namespace := c.Query("namespace")
 
 	if namespace == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -633,7 +649,7 @@ func (h *DaemonSetsHandler) GetDaemonSetPodsByName(c *gin.Context) {
 	daemonSet, err := client.AppsV1().DaemonSets(namespace).Get(c.Request.Context(), name, metav1.GetOptions{})
 	if err != nil {
 		h.logger.WithError(err).WithField("daemonset", name).WithField("namespace", namespace).Error("Failed to get daemonset for pods by name")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -649,7 +665,7 @@ func (h *DaemonSetsHandler) GetDaemonSetPodsByName(c *gin.Context) {
 	})
 	if err != nil {
 		h.logger.WithError(err).WithField("daemonset", name).WithField("namespace", namespace).Error("Failed to get daemonset pods by name")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -0,0 +1,327 @@
+package workloads
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	debugPodLabel           = "kube-dash.io/debug-pod"
+	debugSourceKindLabel    = "kube-dash.io/debug-source-kind"
+	debugSourceNameLabel    = "kube-dash.io/debug-source-name"
+	debugExpiresAnnotation  = "kube-dash.io/debug-expires-at"
+	debugOverrideAnnotation = "kube-dash.io/debug-overrides"
+
+	defaultDebugPodTTL = time.Hour
+	maxDebugPodTTL     = 24 * time.Hour
+)
+
+// DebugPodRequest describes the source and overrides of a debug pod
+type DebugPodRequest struct {
+	Kind       string      `json:"kind"` // Pod, Deployment, StatefulSet, DaemonSet, ReplicaSet, Job or CronJob
+	Name       string      `json:"name"`
+	Namespace  string      `json:"namespace"`
+	Container  string      `json:"container,omitempty"` // defaults to the first container
+	Command    []string    `json:"command,omitempty"`
+	Args       []string    `json:"args,omitempty"`
+	Env        []v1.EnvVar `json:"env,omitempty"` // upserted by name
+	Image      string      `json:"image,omitempty"`
+	ImageTag   string      `json:"imageTag,omitempty"` // replaces only the tag of the current image
+	TTLSeconds int64       `json:"ttlSeconds,omitempty"`
+	KeepProbes bool        `json:"keepProbes,omitempty"`
+}
+
+// debugPodSource loads the pod spec to clone for the requested kind
+func debugPodSource(ctx context.Context, client kubernetes.Interface, req DebugPodRequest) (*v1.PodSpec, error) {
+	switch req.Kind {
+	case "Pod":
+		pod, err := client.CoreV1().Pods(req.Namespace).Get(ctx, req.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &pod.Spec, nil
+	case "Deployment":
+		obj, err := client.AppsV1().Deployments(req.Namespace).Get(ctx, req.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &obj.Spec.Template.Spec, nil
+	case "StatefulSet":
+		obj, err := client.AppsV1().StatefulSets(req.Namespace).Get(ctx, req.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &obj.Spec.Template.Spec, nil
+	case "DaemonSet":
+		obj, err := client.AppsV1().DaemonSets(req.Namespace).Get(ctx, req.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &obj.Spec.Template.Spec, nil
+	case "ReplicaSet":
+		obj, err := client.AppsV1().ReplicaSets(req.Namespace).Get(ctx, req.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &obj.Spec.Template.Spec, nil
+	case "Job":
+		obj, err := client.BatchV1().Jobs(req.Namespace).Get(ctx, req.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &obj.Spec.Template.Spec, nil
+	case "CronJob":
+		obj, err := client.BatchV1().CronJobs(req.Namespace).Get(ctx, req.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &obj.Spec.JobTemplate.Spec.Template.Spec, nil
+	}
+	return nil, fmt.Errorf("unsupported kind %q", req.Kind)
+}
+
+// buildDebugPod clones a pod spec into a standalone pod with the requested overrides.
+// Source labels are dropped so services and controllers never select or adopt the clone.
+func buildDebugPod(req DebugPodRequest, source *v1.PodSpec, now time.Time) (*v1.Pod, error) {
+	ttl := defaultDebugPodTTL
+	if req.TTLSeconds > 0 {
+		ttl = time.Duration(req.TTLSeconds) * time.Second
+	}
+	if ttl > maxDebugPodTTL {
+		return nil, fmt.Errorf("ttlSeconds must not exceed %d", int64(maxDebugPodTTL/time.Second))
+	}
+
+	spec := source.DeepCopy()
+	target := -1
+	for i := range spec.Containers {
+		if req.Container == "" || spec.Containers[i].Name == req.Container {
+			target = i
+			break
+		}
+	}
+	if target < 0 {
+		return nil, fmt.Errorf("container %q not found", req.Container)
+	}
+
+	container := &spec.Containers[target]
+	var overrides []string
+	if req.Command != nil {
+		container.Command = req.Command
+		overrides = append(overrides, "command")
+	}
+	if req.Args != nil {
+		container.Args = req.Args
+		overrides = append(overrides, "args")
+	}
+	for _, env := range req.Env {
+		if env.Name == "" {
+			return nil, fmt.Errorf("env entries require a name")
+		}
+		replaced := false
+		for i := range container.Env {
+			if container.Env[i].Name == env.Name {
+				container.Env[i] = env
+				replaced = true
+			}
+		}
+		if !replaced {
+			container.Env = append(container.Env, env)
+		}
+	}
+	if len(req.Env) > 0 {
+		overrides = append(overrides, "env")
+	}
+	switch {
+	case req.Image != "":
+		container.Image = req.Image
+		overrides = append(overrides, "image")
+	case req.ImageTag != "":
+		container.Image = withImageTag(container.Image, req.ImageTag)
+		overrides = append(overrides, "image")
+	}
+
+	// A debug pod runs once on whatever node fits and is killed when its TTL passes
+	deadline := int64(ttl / time.Second)
+	spec.ActiveDeadlineSeconds = &deadline
+	spec.RestartPolicy = v1.RestartPolicyNever
+	spec.NodeName = ""
+	spec.Hostname = ""
+	spec.Subdomain = ""
+	spec.EphemeralContainers = nil
+	if !req.KeepProbes {
+		for i := range spec.Containers {
+			spec.Containers[i].LivenessProbe = nil
+			spec.Containers[i].ReadinessProbe = nil
+			spec.Containers[i].StartupProbe = nil
+		}
+	}
+
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: debugPodPrefix(req.Name),
+			Namespace:    req.Namespace,
+			Labels: map[string]string{
+				debugPodLabel:        "true",
+				debugSourceKindLabel: strings.ToLower(req.Kind),
+				debugSourceNameLabel: truncateLabelValue(req.Name),
+			},
+			Annotations: map[string]string{
+				debugExpiresAnnotation:  now.Add(ttl).UTC().Format(time.RFC3339),
+				debugOverrideAnnotation: strings.Join(overrides, ","),
+			},
+		},
+		Spec: *spec,
+	}, nil
+}
+
+// withImageTag replaces the tag (or digest) of an image reference
+func withImageTag(image, tag string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image + ":" + tag
+}
+
+func debugPodPrefix(name string) string {
+	const suffix = "-debug-"
+	// Leave room for the five random characters of the generated name
+	if max := 63 - len(suffix) - 5; len(name) > max {
+		name = name[:max]
+	}
+	return strings.TrimRight(name, "-.") + suffix
+}
+
+func truncateLabelValue(value string) string {
+	if len(value) > 63 {
+		value = value[:63]
+	}
+	return strings.TrimRight(value, "-_.")
+}
+
+// debugPodExpired reports whether a debug pod has outlived its TTL
+func debugPodExpired(pod *v1.Pod, now time.Time) bool {
+	expires, err := time.Parse(time.RFC3339, pod.Annotations[debugExpiresAnnotation])
+	return err == nil && now.After(expires)
+}
+
+// reapExpiredDebugPods deletes debug pods whose TTL has passed; failures are logged and ignored
+func (h *PodsHandler) reapExpiredDebugPods(ctx context.Context, client kubernetes.Interface, namespace string) []string {
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: debugPodLabel + "=true"})
+	if err != nil {
+		h.logger.WithError(err).WithField("namespace", namespace).Warn("Failed to list debug pods for cleanup")
+		return nil
+	}
+	var reaped []string
+	now := time.Now()
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !debugPodExpired(pod, now) {
+			continue
+		}
+		if err := client.CoreV1().Pods(namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{}); err != nil {
+			h.logger.WithError(err).WithField("pod", pod.Name).WithField("namespace", namespace).Warn("Failed to delete expired debug pod")
+			continue
+		}
+		reaped = append(reaped, pod.Name)
+	}
+	return reaped
+}
+
+// CreateDebugPod clones a pod or workload pod template into a standalone debug pod
+// @Summary Launch a debug copy of a pod
+// @Description Clones a pod, or the pod template of a Deployment, StatefulSet, DaemonSet, ReplicaSet, Job or CronJob, into a standalone pod with no owner. The command, args, env vars and image of one container can be overridden. The copy is labeled kube-dash.io/debug-pod=true, has no source labels so services never route to it, and is stopped by activeDeadlineSeconds once its TTL passes (default 1h, max 24h). Expired debug pods in the namespace are deleted on each launch.
+// @Tags Workloads
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name for multi-cluster setups"
+// @Param body body DebugPodRequest true "Source and overrides"
+// @Success 201 {object} map[string]interface{} "Created debug pod"
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
+// @Failure 404 {object} map[string]string "Source not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/pods/debug [post]
+func (h *PodsHandler) CreateDebugPod(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	client, err := h.getClientAndConfigWithContext(c, ctx)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for debug pod")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client setup completed")
+
+	var req DebugPodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	if req.Kind == "" || req.Name == "" || req.Namespace == "" {
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "kind, name and namespace are required")
+		return
+	}
+
+	_, sourceSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "get", strings.ToLower(req.Kind), req.Namespace)
+	source, err := debugPodSource(c.Request.Context(), client, req)
+	if err != nil {
+		h.tracingHelper.RecordError(sourceSpan, err, "Failed to get debug pod source")
+		sourceSpan.End()
+		status := http.StatusNotFound
+		if strings.HasPrefix(err.Error(), "unsupported kind") {
+			status = http.StatusBadRequest
+		}
+		utils.RespondError(c, status, err)
+		return
+	}
+	h.tracingHelper.RecordSuccess(sourceSpan, fmt.Sprintf("Loaded pod spec of %s %s", req.Kind, req.Name))
+	sourceSpan.End()
+
+	pod, err := buildDebugPod(req, source, time.Now())
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	reaped := h.reapExpiredDebugPods(c.Request.Context(), client, req.Namespace)
+
+	_, createSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "create", "pod", req.Namespace)
+	defer createSpan.End()
+	created, err := client.CoreV1().Pods(req.Namespace).Create(c.Request.Context(), pod, metav1.CreateOptions{})
+	if err != nil {
+		h.logger.WithError(err).WithField("source", req.Name).WithField("namespace", req.Namespace).Error("Failed to create debug pod")
+		h.tracingHelper.RecordError(createSpan, err, "Failed to create debug pod")
+		utils.RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	h.tracingHelper.RecordSuccess(createSpan, fmt.Sprintf("Created debug pod %s", created.Name))
+
+	h.logger.WithField("pod", created.Name).WithField("source", req.Name).WithField("namespace", req.Namespace).Info("Created debug pod")
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Debug pod created successfully",
+		"pod": gin.H{
+			"name":      created.Name,
+			"namespace": created.Namespace,
+			"expiresAt": created.Annotations[debugExpiresAnnotation],
+			"overrides": created.Annotations[debugOverrideAnnotation],
+		},
+		"reaped": reaped,
+	})
+}
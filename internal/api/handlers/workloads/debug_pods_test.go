@@ -0,0 +1,74 @@
+package workloads
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestBuildDebugPod(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	source := &v1.PodSpec{
+		NodeName: "node-a",
+		Hostname: "web-0",
+		Containers: []v1.Container{
+			{Name: "app", Image: "registry:5000/shop/web:v1", Env: []v1.EnvVar{{Name: "LOG_LEVEL", Value: "info"}},
+				LivenessProbe: &v1.Probe{}},
+			{Name: "sidecar", Image: "envoy:1.29"},
+		},
+	}
+
+	pod, err := buildDebugPod(DebugPodRequest{
+		Kind: "StatefulSet", Name: "web", Namespace: "shop",
+		Command:  []string{"sleep", "infinity"},
+		Env:      []v1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}, {Name: "TRACE", Value: "1"}},
+		ImageTag: "v2", TTLSeconds: 600,
+	}, source, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	app := pod.Spec.Containers[0]
+	if app.Image != "registry:5000/shop/web:v2" || app.Command[1] != "infinity" || app.LivenessProbe != nil {
+		t.Errorf("unexpected container %+v", app)
+	}
+	if len(app.Env) != 2 || app.Env[0].Value != "debug" || app.Env[1].Name != "TRACE" {
+		t.Errorf("unexpected env %+v", app.Env)
+	}
+	if source.Containers[0].Env[0].Value != "info" {
+		t.Errorf("source spec must not be modified")
+	}
+	if pod.Spec.NodeName != "" || pod.Spec.Hostname != "" || pod.Spec.RestartPolicy != v1.RestartPolicyNever || *pod.Spec.ActiveDeadlineSeconds != 600 {
+		t.Errorf("unexpected spec %+v", pod.Spec)
+	}
+	if pod.Labels[debugPodLabel] != "true" || pod.OwnerReferences != nil || pod.GenerateName != "web-debug-" {
+		t.Errorf("unexpected metadata %+v", pod.ObjectMeta)
+	}
+	if pod.Annotations[debugExpiresAnnotation] != "2024-05-01T12:10:00Z" || pod.Annotations[debugOverrideAnnotation] != "command,env,image" {
+		t.Errorf("unexpected annotations %v", pod.Annotations)
+	}
+	if !debugPodExpired(pod, now.Add(11*time.Minute)) || debugPodExpired(pod, now) {
+		t.Errorf("unexpected expiry for %v", pod.Annotations)
+	}
+
+	if _, err := buildDebugPod(DebugPodRequest{Name: "web", Container: "missing"}, source, now); err == nil {
+		t.Errorf("expected missing container error")
+	}
+	if _, err := buildDebugPod(DebugPodRequest{Name: "web", TTLSeconds: 2 * 86400}, source, now); err == nil {
+		t.Errorf("expected TTL limit error")
+	}
+}
+
+func TestWithImageTag(t *testing.T) {
+	cases := map[string]string{
+		"nginx":                        "nginx:dev",
+		"nginx:1.25":                   "nginx:dev",
+		"registry:5000/app":            "registry:5000/app:dev",
+		"ghcr.io/org/app:v1@sha256:ab": "ghcr.io/org/app:dev",
+	}
+	for image, want := range cases {
+		if got := withImageTag(image, "dev"); got != want {
+			t.Errorf("withImageTag(%q) = %q, want %q", image, got, want)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package workloads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+
+	"github.com/gin-gonic/gin"
+	appsV1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+)
+
+// recreateReplicasAnnotation records the replica count a recreate restart scales back up to,
+// so a restart interrupted while the deployment is at zero can be resumed
+const recreateReplicasAnnotation = "kube-dash.io/recreate-restart-replicas"
+
+const (
+	// recreateTerminationTimeout bounds how long a recreate restart waits for old pods to terminate
+	recreateTerminationTimeout = 5 * time.Minute
+	// recreateReadyTimeout bounds how long a recreate restart waits for the new pods to become ready
+	recreateReadyTimeout = 10 * time.Minute
+)
+
+// RecreateRestartStatus tracks the progress of a recreate restart
+type RecreateRestartStatus struct {
+	Deployment    string     `json:"deployment"`
+	Namespace     string     `json:"namespace"`
+	State         string     `json:"state"` // "running", "completed" or "failed"
+	Phase         string     `json:"phase"` // "scaling-down", "terminating", "scaling-up", "waiting-ready" or "done"
+	Replicas      int32      `json:"replicas"`
+	RemainingPods int        `json:"remainingPods"`
+	ReadyReplicas int32      `json:"readyReplicas"`
+	Resumed       bool       `json:"resumed,omitempty"` // continued a restart interrupted at zero replicas
+	Message       string     `json:"message,omitempty"`
+	StartedAt     time.Time  `json:"startedAt"`
+	FinishedAt    *time.Time `json:"finishedAt,omitempty"`
+}
+
+// recreateRestartKey scopes recreate restart progress to a config, cluster and deployment
+func recreateRestartKey(configID, cluster, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", configID, cluster, namespace, name)
+}
+
+// recreateTargetReplicas returns the replicas to scale back up to: those recorded by an interrupted
+// restart when the deployment is still at zero, otherwise the current spec. A recorded count on a
+// deployment that has since been scaled is stale, for example when clearing it failed, and is ignored.
+func recreateTargetReplicas(deployment *appsV1.Deployment) (int32, bool) {
+	current := int32(1)
+	if deployment.Spec.Replicas != nil {
+		current = *deployment.Spec.Replicas
+	}
+	if value, ok := deployment.Annotations[recreateReplicasAnnotation]; ok && current == 0 {
+		if replicas, err := strconv.ParseInt(value, 10, 32); err == nil && replicas >= 0 {
+			return int32(replicas), true
+		}
+	}
+	return current, false
+}
+
+// errRecreateRestartRunning is returned when a recreate restart of the deployment is still in progress
+var errRecreateRestartRunning = errors.New("a recreate restart is already in progress")
+
+// newRecreateRestartTracker tracks recreate restarts; a running restart blocks another of the same deployment
+func newRecreateRestartTracker() *utils.ProgressTracker[RecreateRestartStatus] {
+	return utils.NewProgressTracker(utils.ProgressRetention,
+		func(s *RecreateRestartStatus) bool { return s.State == "running" }, nil)
+}
+
+// startRecreateRestart records the target replicas and runs the recreate restart in the background
+func (h *DeploymentsHandler) startRecreateRestart(client kubernetes.Interface, key, name, namespace string) (*RecreateRestartStatus, error) {
+	status := &RecreateRestartStatus{
+		Deployment: name,
+		Namespace:  namespace,
+		State:      "running",
+		Phase:      "scaling-down",
+		StartedAt:  time.Now(),
+	}
+	if _, started := h.recreateRestarts.Start(key, status); !started {
+		return nil, fmt.Errorf("%w for deployment %s", errRecreateRestartRunning, name)
+	}
+
+	deployment, err := client.AppsV1().Deployments(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		h.recreateRestarts.Delete(key)
+		return nil, fmt.Errorf("failed to get deployment: %w", err)
+	}
+	replicas, resumed := recreateTargetReplicas(deployment)
+	if !resumed {
+		if err := h.patchRecreateAnnotation(client, name, namespace, strconv.Itoa(int(replicas))); err != nil {
+			h.recreateRestarts.Delete(key)
+			return nil, fmt.Errorf("failed to record replicas: %w", err)
+		}
+	}
+	status, _ = h.recreateRestarts.Update(key, func(s *RecreateRestartStatus) {
+		s.Replicas = replicas
+		s.Resumed = resumed
+	})
+
+	go h.runRecreateRestart(client, key, deployment, replicas)
+
+	return status, nil
+}
+
+// runRecreateRestart scales to zero, waits for every pod to terminate, scales back up and waits for readiness.
+// The deployment is scaled back up even when termination times out so it is never left at zero.
+func (h *DeploymentsHandler) runRecreateRestart(client kubernetes.Interface, key string, deployment *appsV1.Deployment, replicas int32) {
+	name, namespace := deployment.Name, deployment.Namespace
+	fail := func(err error) {
+		h.logger.WithError(err).WithField("deployment", name).WithField("namespace", namespace).Error("Recreate restart failed")
+		h.recreateRestarts.Update(key, func(s *RecreateRestartStatus) {
+			s.State = "failed"
+			s.Message = err.Error()
+			s.FinishedAt = ptr.To(time.Now())
+		})
+	}
+
+	if err := h.scaleDeploymentWithRetry(client, name, namespace, 0); err != nil {
+		fail(fmt.Errorf("failed to scale down deployment: %w", err))
+		return
+	}
+
+	h.recreateRestarts.Update(key, func(s *RecreateRestartStatus) { s.Phase = "terminating" })
+	selector := metav1.FormatLabelSelector(deployment.Spec.Selector)
+	terminateCtx, cancelTerminate := context.WithTimeout(context.Background(), recreateTerminationTimeout)
+	terminationErr := waitForPodsGone(terminateCtx, client, namespace, selector, func(remaining int) {
+		h.recreateRestarts.Update(key, func(s *RecreateRestartStatus) { s.RemainingPods = remaining })
+	})
+	cancelTerminate()
+	if terminationErr != nil {
+		h.logger.WithError(terminationErr).WithField("deployment", name).WithField("namespace", namespace).Warn("Scaling back up before all pods terminated")
+		h.recreateRestarts.Update(key, func(s *RecreateRestartStatus) { s.Message = terminationErr.Error() })
+	}
+
+	h.recreateRestarts.Update(key, func(s *RecreateRestartStatus) { s.Phase = "scaling-up" })
+	if err := h.scaleDeploymentWithRetry(client, name, namespace, replicas); err != nil {
+		fail(fmt.Errorf("failed to scale up deployment to %d replicas: %w", replicas, err))
+		return
+	}
+	if err := h.patchRecreateAnnotation(client, name, namespace, nil); err != nil {
+		h.logger.WithError(err).WithField("deployment", name).WithField("namespace", namespace).Warn("Failed to clear recreate restart annotation")
+	}
+
+	h.recreateRestarts.Update(key, func(s *RecreateRestartStatus) { s.Phase = "waiting-ready" })
+	readyCtx, cancelReady := context.WithTimeout(context.Background(), recreateReadyTimeout)
+	defer cancelReady()
+	if err := waitForDeploymentReady(readyCtx, client, namespace, name, replicas, func(ready int32) {
+		h.recreateRestarts.Update(key, func(s *RecreateRestartStatus) { s.ReadyReplicas = ready })
+	}); err != nil {
+		fail(err)
+		return
+	}
+
+	h.recreateRestarts.Update(key, func(s *RecreateRestartStatus) {
+		s.State = "completed"
+		s.Phase = "done"
+		s.FinishedAt = ptr.To(time.Now())
+	})
+	h.logger.WithField("deployment", name).WithField("namespace", namespace).WithField("replicas", replicas).Info("Recreate restart completed")
+}
+
+// patchRecreateAnnotation sets or clears (value nil) the recorded recreate replicas
+func (h *DeploymentsHandler) patchRecreateAnnotation(client kubernetes.Interface, name, namespace string, value interface{}) error {
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": map[string]interface{}{recreateReplicasAnnotation: value}},
+	})
+	_, err := client.AppsV1().Deployments(namespace).Patch(context.Background(), name, k8stypes.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// waitForPodsGone watches the pods matching selector until none remain, reporting the remaining count
+func waitForPodsGone(ctx context.Context, client kubernetes.Interface, namespace, selector string, progress func(remaining int)) error {
+	for {
+		pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return fmt.Errorf("failed to list pods: %w", err)
+		}
+		remaining := map[string]bool{}
+		for _, pod := range pods.Items {
+			remaining[pod.Name] = true
+		}
+		progress(len(remaining))
+		if len(remaining) == 0 {
+			return nil
+		}
+
+		watcher, err := client.CoreV1().Pods(namespace).Watch(ctx, metav1.ListOptions{LabelSelector: selector, ResourceVersion: pods.ResourceVersion})
+		if err != nil {
+			return fmt.Errorf("failed to watch pods: %w", err)
+		}
+		done, err := drainPodWatch(ctx, watcher, remaining, progress)
+		watcher.Stop()
+		if done || err != nil {
+			return err
+		}
+		// The watch expired; list again and resume
+	}
+}
+
+// drainPodWatch tracks pod additions and deletions until no pods remain or the watch ends
+func drainPodWatch(ctx context.Context, watcher watch.Interface, remaining map[string]bool, progress func(remaining int)) (bool, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return false, fmt.Errorf("%d pods still terminating after %s", len(remaining), recreateTerminationTimeout)
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return false, nil
+			}
+			pod, isPod := event.Object.(metav1.Object)
+			if !isPod || event.Type == watch.Error {
+				return false, nil
+			}
+			switch event.Type {
+			case watch.Added:
+				remaining[pod.GetName()] = true
+			case watch.Deleted:
+				delete(remaining, pod.GetName())
+			}
+			progress(len(remaining))
+			if len(remaining) == 0 {
+				return true, nil
+			}
+		}
+	}
+}
+
+// waitForDeploymentReady polls until the deployment reports the expected ready replicas
+func waitForDeploymentReady(ctx context.Context, client kubernetes.Interface, namespace, name string, replicas int32, progress func(ready int32)) error {
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+	for {
+		deployment, err := client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err == nil {
+			progress(deployment.Status.ReadyReplicas)
+			if deployment.Status.ObservedGeneration >= deployment.Generation && deployment.Status.ReadyReplicas >= replicas {
+				return nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("deployment %s did not become ready within %s", name, recreateReadyTimeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetRecreateRestartStatus returns the progress of the latest recreate restart
+// @Summary Get Deployment recreate restart status
+// @Description Returns the progress of the most recent recreate restart of a deployment. Requests accepting text/event-stream receive updates until they disconnect.
+// @Tags Workloads
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param namespace path string true "Namespace name"
+// @Param name path string true "Deployment name"
+// @Success 200 {object} RecreateRestartStatus "Recreate restart progress"
+// @Failure 404 {object} map[string]string "No recreate restart found"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/deployments/{namespace}/{name}/restart-status [get]
+func (h *DeploymentsHandler) GetRecreateRestartStatus(c *gin.Context) {
+	key := recreateRestartKey(c.Query("config"), c.Query("cluster"), c.Param("namespace"), c.Param("name"))
+	status, ok := h.recreateRestarts.Load(key)
+	if !ok {
+		utils.RespondErrorMessage(c, http.StatusNotFound, "no recreate restart found for this deployment")
+		return
+	}
+
+	if c.GetHeader("Accept") == "text/event-stream" {
+		h.sseHandler.SendSSEResponseWithUpdates(c, status, func() (interface{}, error) {
+			current, _ := h.recreateRestarts.Load(key)
+			return current, nil
+		})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
@@ -0,0 +1,53 @@
+package workloads
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	appsV1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/utils/ptr"
+)
+
+func TestRecreateTargetReplicas(t *testing.T) {
+	deployment := &appsV1.Deployment{Spec: appsV1.DeploymentSpec{Replicas: ptr.To(int32(3))}}
+	if replicas, resumed := recreateTargetReplicas(deployment); replicas != 3 || resumed {
+		t.Errorf("got %d, %v", replicas, resumed)
+	}
+
+	// An interrupted restart left the deployment at zero with the original count recorded
+	deployment.Spec.Replicas = ptr.To(int32(0))
+	deployment.Annotations = map[string]string{recreateReplicasAnnotation: "4"}
+	if replicas, resumed := recreateTargetReplicas(deployment); replicas != 4 || !resumed {
+		t.Errorf("got %d, %v", replicas, resumed)
+	}
+
+	// The annotation outlived its restart and the deployment has since been scaled
+	deployment.Spec.Replicas = ptr.To(int32(5))
+	if replicas, resumed := recreateTargetReplicas(deployment); replicas != 5 || resumed {
+		t.Errorf("stale annotation: got %d, %v", replicas, resumed)
+	}
+}
+
+func TestDrainPodWatch(t *testing.T) {
+	watcher := watch.NewFake()
+	remaining := map[string]bool{"web-a": true, "web-b": true}
+	var seen []int
+	go func() {
+		watcher.Delete(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-a"}})
+		watcher.Add(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-c"}})
+		watcher.Delete(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-b"}})
+		watcher.Delete(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-c"}})
+	}()
+
+	done, err := drainPodWatch(context.Background(), watcher, remaining, func(n int) { seen = append(seen, n) })
+	if err != nil || !done {
+		t.Fatalf("done=%v err=%v", done, err)
+	}
+	if want := []int{1, 2, 1, 0}; !reflect.DeepEqual(seen, want) {
+		t.Errorf("progress = %v, want %v", seen, want)
+	}
+}
@@ -0,0 +1,371 @@
+package workloads
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+
+	"github.com/gin-gonic/gin"
+	appsV1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+)
+
+// revisionAnnotation is the annotation the deployment controller stamps on every ReplicaSet it owns
+const revisionAnnotation = "deployment.kubernetes.io/revision"
+
+// defaultRevisionHistoryLimit mirrors the apps/v1 default when spec.revisionHistoryLimit is unset
+const defaultRevisionHistoryLimit int32 = 10
+
+// ReplicaSetRevision describes a ReplicaSet owned by a deployment
+type ReplicaSetRevision struct {
+	Name              string   `json:"name"`
+	Namespace         string   `json:"namespace"`
+	Revision          int64    `json:"revision"`
+	Replicas          int32    `json:"replicas"`
+	ReadyReplicas     int32    `json:"readyReplicas"`
+	Active            bool     `json:"active"`
+	BeyondHistory     bool     `json:"beyondHistory"`
+	CreationTimestamp string   `json:"creationTimestamp"`
+	Images            []string `json:"images"`
+}
+
+// ReplicaSetRevisionsResponse lists the ReplicaSets of a deployment split by activity
+type ReplicaSetRevisionsResponse struct {
+	Deployment           string               `json:"deployment"`
+	Namespace            string               `json:"namespace"`
+	RevisionHistoryLimit int32                `json:"revisionHistoryLimit"`
+	Active               []ReplicaSetRevision `json:"active"`
+	Inactive             []ReplicaSetRevision `json:"inactive"`
+}
+
+// ReplicaSetCleanupRequest selects which inactive ReplicaSets to delete
+type ReplicaSetCleanupRequest struct {
+	Names              []string `json:"names"`
+	BeyondHistoryLimit bool     `json:"beyondHistoryLimit"`
+	DryRun             bool     `json:"dryRun"`
+}
+
+// ReplicaSetCleanupResult reports the outcome of a cleanup on one ReplicaSet
+type ReplicaSetCleanupResult struct {
+	Name   string `json:"name"`
+	Status string `json:"status"` // deleted, skipped or failed
+	Error  string `json:"error,omitempty"`
+}
+
+// ReplicaSetCleanupResponse reports the outcome of a cleanup run
+type ReplicaSetCleanupResponse struct {
+	Deployment           string                    `json:"deployment"`
+	Namespace            string                    `json:"namespace"`
+	DryRun               bool                      `json:"dryRun"`
+	Deleted              []string                  `json:"deleted"`
+	Skipped              []string                  `json:"skipped"`
+	Failed               []string                  `json:"failed"`
+	Results              []ReplicaSetCleanupResult `json:"results"`
+	ReclaimedReplicaSets int                       `json:"reclaimedReplicaSets"`
+}
+
+// isActiveReplicaSet reports whether a ReplicaSet runs pods or belongs to the deployment's current revision
+func isActiveReplicaSet(rs *appsV1.ReplicaSet, currentRevision string) bool {
+	if currentRevision != "" && rs.Annotations[revisionAnnotation] == currentRevision {
+		return true
+	}
+	return ptr.Deref(rs.Spec.Replicas, 0) > 0 || rs.Status.Replicas > 0
+}
+
+// listDeploymentRevisions returns the deployment and its owned ReplicaSets sorted newest revision first
+func listDeploymentRevisions(ctx context.Context, client kubernetes.Interface, namespace, name string) (*appsV1.Deployment, []ReplicaSetRevision, error) {
+	deployment, err := client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid deployment selector: %w", err)
+	}
+
+	rsList, err := client.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The ReplicaSet of the current revision stays active even when the deployment is scaled to zero
+	currentRevision := deployment.Annotations[revisionAnnotation]
+
+	var revisions []ReplicaSetRevision
+	for _, rs := range rsList.Items {
+		if !metav1.IsControlledBy(&rs, deployment) {
+			continue
+		}
+		revision, _ := strconv.ParseInt(rs.Annotations[revisionAnnotation], 10, 64)
+		var images []string
+		for _, container := range rs.Spec.Template.Spec.Containers {
+			images = append(images, container.Image)
+		}
+		revisions = append(revisions, ReplicaSetRevision{
+			Name:              rs.Name,
+			Namespace:         rs.Namespace,
+			Revision:          revision,
+			Replicas:          rs.Status.Replicas,
+			ReadyReplicas:     rs.Status.ReadyReplicas,
+			Active:            isActiveReplicaSet(&rs, currentRevision),
+			CreationTimestamp: rs.CreationTimestamp.Time.Format("2006-01-02T15:04:05Z"),
+			Images:            images,
+		})
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Revision > revisions[j].Revision
+	})
+
+	// The newest inactive ReplicaSets up to the history limit are kept for rollback
+	limit := defaultRevisionHistoryLimit
+	if deployment.Spec.RevisionHistoryLimit != nil {
+		limit = *deployment.Spec.RevisionHistoryLimit
+	}
+	kept := int32(0)
+	for i := range revisions {
+		if revisions[i].Active {
+			continue
+		}
+		if kept >= limit {
+			revisions[i].BeyondHistory = true
+			continue
+		}
+		kept++
+	}
+
+	return deployment, revisions, nil
+}
+
+// cleanupDeploymentRevisions deletes the selected inactive ReplicaSets of a deployment; active
+// ReplicaSets and names the deployment does not own are reported as skipped
+func cleanupDeploymentRevisions(ctx context.Context, client kubernetes.Interface, namespace, name string, req ReplicaSetCleanupRequest) (*ReplicaSetCleanupResponse, error) {
+	_, revisions, err := listDeploymentRevisions(ctx, client, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make(map[string]bool, len(req.Names))
+	for _, n := range req.Names {
+		selected[n] = true
+	}
+
+	response := &ReplicaSetCleanupResponse{
+		Deployment: name,
+		Namespace:  namespace,
+		DryRun:     req.DryRun,
+		Deleted:    []string{},
+		Skipped:    []string{},
+		Failed:     []string{},
+		Results:    []ReplicaSetCleanupResult{},
+	}
+	record := func(name, status string, err error) {
+		result := ReplicaSetCleanupResult{Name: name, Status: status}
+		switch status {
+		case "deleted":
+			response.Deleted = append(response.Deleted, name)
+		case "skipped":
+			response.Skipped = append(response.Skipped, name)
+		case "failed":
+			response.Failed = append(response.Failed, name)
+			result.Error = err.Error()
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	deleteOpts := metav1.DeleteOptions{PropagationPolicy: ptr.To(metav1.DeletePropagationBackground)}
+	if req.DryRun {
+		deleteOpts.DryRun = []string{metav1.DryRunAll}
+	}
+
+	known := make(map[string]bool, len(revisions))
+	for _, rev := range revisions {
+		known[rev.Name] = true
+		wanted := selected[rev.Name] || (req.BeyondHistoryLimit && rev.BeyondHistory)
+		if !wanted {
+			continue
+		}
+		if rev.Active {
+			record(rev.Name, "skipped", nil)
+			continue
+		}
+		if err := client.AppsV1().ReplicaSets(namespace).Delete(ctx, rev.Name, deleteOpts); err != nil {
+			record(rev.Name, "failed", err)
+			continue
+		}
+		record(rev.Name, "deleted", nil)
+	}
+	// Names that do not belong to this deployment are reported rather than touched
+	for _, n := range req.Names {
+		if !known[n] {
+			record(n, "skipped", nil)
+		}
+	}
+	response.ReclaimedReplicaSets = len(response.Deleted)
+	return response, nil
+}
+
+// cleanupFailure describes a cleanup in which every selected ReplicaSet failed to delete
+func cleanupFailure(response *ReplicaSetCleanupResponse) error {
+	for _, result := range response.Results {
+		if result.Status == "failed" {
+			return fmt.Errorf("failed to delete %d replicasets, %s: %s", len(response.Failed), result.Name, result.Error)
+		}
+	}
+	return fmt.Errorf("failed to delete %d replicasets", len(response.Failed))
+}
+
+// GetDeploymentRevisions lists the active and inactive ReplicaSets of a deployment
+// @Summary Get Deployment ReplicaSet revisions
+// @Description Lists the ReplicaSets owned by a deployment with revision, age and whether they exceed revisionHistoryLimit
+// @Tags Workloads
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param namespace path string true "Namespace name"
+// @Param name path string true "Deployment name"
+// @Success 200 {object} ReplicaSetRevisionsResponse "Deployment ReplicaSet revisions"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Deployment not found"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/deployments/{namespace}/{name}/revisions [get]
+func (h *DeploymentsHandler) GetDeploymentRevisions(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for deployment revisions")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
+
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+
+	_, listSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "replicasets", namespace)
+	defer listSpan.End()
+
+	deployment, revisions, err := listDeploymentRevisions(ctx, client, namespace, name)
+	if err != nil {
+		h.logger.WithError(err).WithField("deployment", name).WithField("namespace", namespace).Error("Failed to list deployment revisions")
+		h.tracingHelper.RecordError(listSpan, err, "Failed to list deployment revisions")
+		utils.RespondError(c, http.StatusNotFound, err)
+		return
+	}
+	h.tracingHelper.AddResourceAttributes(listSpan, name, "replicasets", len(revisions))
+	h.tracingHelper.RecordSuccess(listSpan, fmt.Sprintf("Listed %d replicasets", len(revisions)))
+
+	response := ReplicaSetRevisionsResponse{
+		Deployment:           deployment.Name,
+		Namespace:            deployment.Namespace,
+		RevisionHistoryLimit: defaultRevisionHistoryLimit,
+		Active:               []ReplicaSetRevision{},
+		Inactive:             []ReplicaSetRevision{},
+	}
+	if deployment.Spec.RevisionHistoryLimit != nil {
+		response.RevisionHistoryLimit = *deployment.Spec.RevisionHistoryLimit
+	}
+	for _, rev := range revisions {
+		if rev.Active {
+			response.Active = append(response.Active, rev)
+		} else {
+			response.Inactive = append(response.Inactive, rev)
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CleanupDeploymentRevisions deletes selected inactive ReplicaSets of a deployment
+// @Summary Clean up old Deployment ReplicaSets
+// @Description Deletes the named inactive ReplicaSets, or all inactive ReplicaSets beyond revisionHistoryLimit. Active ReplicaSets are never deleted.
+// @Tags Workloads
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param name path string true "Deployment name"
+// @Param namespace query string true "Namespace name"
+// @Param body body ReplicaSetCleanupRequest true "Cleanup selection"
+// @Success 200 {object} ReplicaSetCleanupResponse "Cleanup result, listing any ReplicaSets that could not be deleted"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Deployment not found"
+// @Failure 500 {object} map[string]string "No selected ReplicaSet could be deleted"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/deployments/{name}/revisions/cleanup [post]
+func (h *DeploymentsHandler) CleanupDeploymentRevisions(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for deployment revision cleanup")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
+
+	name := c.Param("name")
+	namespace := c.Query("namespace")
+	if namespace == "" {
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
+		return
+	}
+
+	var req ReplicaSetCleanupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if len(req.Names) == 0 && !req.BeyondHistoryLimit {
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "either names or beyondHistoryLimit must be provided")
+		return
+	}
+
+	_, deleteSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "delete", "replicasets", namespace)
+	defer deleteSpan.End()
+
+	response, err := cleanupDeploymentRevisions(ctx, client, namespace, name, req)
+	if err != nil {
+		h.logger.WithError(err).WithField("deployment", name).WithField("namespace", namespace).Error("Failed to list deployment revisions")
+		h.tracingHelper.RecordError(deleteSpan, err, "Failed to list deployment revisions")
+		utils.RespondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	h.tracingHelper.AddResourceAttributes(deleteSpan, name, "replicasets", response.ReclaimedReplicaSets)
+	h.logger.WithFields(map[string]interface{}{
+		"deployment": name,
+		"namespace":  namespace,
+		"deleted":    response.ReclaimedReplicaSets,
+		"failed":     len(response.Failed),
+		"dryRun":     req.DryRun,
+	}).Info("Cleaned up deployment replicasets")
+
+	if len(response.Failed) > 0 {
+		h.tracingHelper.RecordError(deleteSpan, fmt.Errorf("%d replicasets failed to delete", len(response.Failed)), "ReplicaSet cleanup failed")
+		// Partial failures are listed in the result; only a cleanup that deleted nothing is an error
+		if len(response.Deleted) == 0 {
+			utils.RespondError(c, http.StatusInternalServerError, cleanupFailure(response))
+			return
+		}
+		c.JSON(http.StatusOK, response)
+		return
+	}
+	h.tracingHelper.RecordSuccess(deleteSpan, fmt.Sprintf("Deleted %d replicasets", response.ReclaimedReplicaSets))
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,145 @@
+package workloads
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+
+	appsV1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
+)
+
+// revisionFixture is a deployment scaled to zero at revision 4 with ReplicaSets for revisions 1-4
+func revisionFixture() *fake.Clientset {
+	labels := map[string]string{"app": "web"}
+	deployment := &appsV1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", UID: types.UID("web-uid"),
+			Annotations: map[string]string{revisionAnnotation: "4"}},
+		Spec: appsV1.DeploymentSpec{
+			Replicas:             ptr.To(int32(0)),
+			RevisionHistoryLimit: ptr.To(int32(1)),
+			Selector:             &metav1.LabelSelector{MatchLabels: labels},
+		},
+	}
+	objects := []runtime.Object{deployment}
+	for revision := 1; revision <= 4; revision++ {
+		objects = append(objects, &appsV1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "web-" + strconv.Itoa(revision),
+				Namespace:       "shop",
+				Labels:          labels,
+				Annotations:     map[string]string{revisionAnnotation: strconv.Itoa(revision)},
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(deployment, appsV1.SchemeGroupVersion.WithKind("Deployment"))},
+			},
+			Spec: appsV1.ReplicaSetSpec{Replicas: ptr.To(int32(0))},
+		})
+	}
+	// A ReplicaSet matching the selector but not owned by the deployment
+	objects = append(objects, &appsV1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "stray", Namespace: "shop", Labels: labels}})
+
+	return fake.NewSimpleClientset(objects...)
+}
+
+func remainingReplicaSets(t *testing.T, client *fake.Clientset) []string {
+	list, err := client.AppsV1().ReplicaSets("shop").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, rs := range list.Items {
+		names = append(names, rs.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestListDeploymentRevisionsScaledToZero(t *testing.T) {
+	_, revisions, err := listDeploymentRevisions(context.Background(), revisionFixture(), "shop", "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revisions) != 4 {
+		t.Fatalf("expected only owned ReplicaSets, got %+v", revisions)
+	}
+	current := revisions[0]
+	if current.Name != "web-4" || !current.Active || current.BeyondHistory {
+		t.Errorf("expected the current revision to stay active at zero replicas, got %+v", current)
+	}
+	// One inactive ReplicaSet is kept for rollback, the older ones are beyond history
+	if revisions[1].BeyondHistory || !revisions[2].BeyondHistory || !revisions[3].BeyondHistory {
+		t.Errorf("unexpected history classification %+v", revisions)
+	}
+}
+
+func TestCleanupDeploymentRevisions(t *testing.T) {
+	client := revisionFixture()
+	response, err := cleanupDeploymentRevisions(context.Background(), client, "shop", "web", ReplicaSetCleanupRequest{
+		Names: []string{"web-4", "stray"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Deleted) != 0 || !reflect.DeepEqual(response.Skipped, []string{"web-4", "stray"}) {
+		t.Errorf("expected the current revision and unowned ReplicaSets to be skipped, got %+v", response)
+	}
+
+	response, err = cleanupDeploymentRevisions(context.Background(), client, "shop", "web", ReplicaSetCleanupRequest{BeyondHistoryLimit: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(response.Deleted, []string{"web-2", "web-1"}) || response.ReclaimedReplicaSets != 2 {
+		t.Errorf("unexpected cleanup %+v", response)
+	}
+	if got := remainingReplicaSets(t, client); !reflect.DeepEqual(got, []string{"stray", "web-3", "web-4"}) {
+		t.Errorf("remaining ReplicaSets = %v", got)
+	}
+
+	if _, err := cleanupDeploymentRevisions(context.Background(), client, "shop", "missing", ReplicaSetCleanupRequest{BeyondHistoryLimit: true}); err == nil {
+		t.Error("expected an error for an unknown deployment")
+	}
+}
+
+func TestCleanupDeploymentRevisionsReportsFailures(t *testing.T) {
+	client := revisionFixture()
+	client.PrependReactor("delete", "replicasets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.(k8stesting.DeleteAction).GetName() == "web-1" {
+			return true, nil, errors.New("admission webhook denied the request")
+		}
+		return false, nil, nil
+	})
+
+	response, err := cleanupDeploymentRevisions(context.Background(), client, "shop", "web", ReplicaSetCleanupRequest{
+		Names: []string{"web-2", "web-1", "web-4"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(response.Deleted, []string{"web-2"}) || !reflect.DeepEqual(response.Failed, []string{"web-1"}) ||
+		!reflect.DeepEqual(response.Skipped, []string{"web-4"}) {
+		t.Errorf("unexpected cleanup %+v", response)
+	}
+	want := []ReplicaSetCleanupResult{
+		{Name: "web-4", Status: "skipped"},
+		{Name: "web-2", Status: "deleted"},
+		{Name: "web-1", Status: "failed", Error: "admission webhook denied the request"},
+	}
+	if !reflect.DeepEqual(response.Results, want) {
+		t.Errorf("results = %+v, want %+v", response.Results, want)
+	}
+
+	response, err = cleanupDeploymentRevisions(context.Background(), client, "shop", "web", ReplicaSetCleanupRequest{Names: []string{"web-1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cleanupFailure(response).Error(); got != "failed to delete 1 replicasets, web-1: admission webhook denied the request" {
+		t.Errorf("unexpected failure message %q", got)
+	}
+}
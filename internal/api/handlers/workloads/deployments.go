@@ -2,6 +2,7 @@ package workloads
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -30,6 +31,9 @@ type DeploymentsHandler struct {
 	yamlHandler   *utils.YAMLHandler
 	eventsHandler *utils.EventsHandler
 	tracingHelper *tracing.TracingHelper
+
+	// Recreate restart progress keyed by config/cluster/namespace/name
+	recreateRestarts *utils.ProgressTracker[RecreateRestartStatus]
 }
 
 // NewDeploymentsHandler creates a new deployments handler
@@ -42,6 +46,8 @@ func NewDeploymentsHandler(store *storage.KubeConfigStore, clientFactory *k8s.Cl
 		yamlHandler:   utils.NewYAMLHandler(log),
 		eventsHandler: utils.NewEventsHandler(log),
 		tracingHelper: tracing.GetTracingHelper(),
+
+		recreateRestarts: newRecreateRestartTracker(),
 	}
 }
 
@@ -70,7 +76,7 @@ func (h *DeploymentsHandler) ScaleDeployment(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for scaling deployment")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error(), "code": http.StatusBadRequest})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
@@ -78,7 +84,7 @@ func (h *DeploymentsHandler) ScaleDeployment(c *gin.Context) {
 	name := c.Param("name")
 	namespace := c.Query("namespace")
 	if namespace == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "namespace parameter is required", "code": http.StatusBadRequest})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -91,7 +97,7 @@ func (h *DeploymentsHandler) ScaleDeployment(c *gin.Context) {
 	}
 	if err := c.BindJSON(&body); err != nil {
 		h.tracingHelper.RecordError(parseSpan, err, "Failed to parse request body")
-		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid request body", "code": http.StatusBadRequest})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "invalid request body")
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(parseSpan, name, "deployment-scale", int(body.Replicas))
@@ -105,7 +111,7 @@ func (h *DeploymentsHandler) ScaleDeployment(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("deployment", name).WithField("namespace", namespace).Error("Failed to get deployment scale")
 		h.tracingHelper.RecordError(getScaleSpan, err, "Failed to get deployment scale")
-		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error(), "code": http.StatusBadRequest})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(getScaleSpan, name, "deployment", int(scale.Spec.Replicas))
@@ -119,7 +125,7 @@ func (h *DeploymentsHandler) ScaleDeployment(c *gin.Context) {
 	if _, err := client.AppsV1().Deployments(namespace).UpdateScale(ctx, name, scale, metav1.UpdateOptions{}); err != nil {
 		h.logger.WithError(err).WithField("deployment", name).WithField("namespace", namespace).Error("Failed to update deployment scale")
 		h.tracingHelper.RecordError(updateScaleSpan, err, "Failed to update deployment scale")
-		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error(), "code": http.StatusBadRequest})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(updateScaleSpan, name, "deployment", int(body.Replicas))
@@ -139,7 +145,8 @@ func (h *DeploymentsHandler) ScaleDeployment(c *gin.Context) {
 // @Param name path string true "Deployment name"
 // @Param namespace query string true "Namespace name"
 // @Param body body object{restartType=string} false "Restart request body (restartType: 'rolling' or 'recreate', defaults to 'rolling')"
-// @Success 200 {object} map[string]string "Deployment restart initiated successfully"
+// @Success 200 {object} map[string]string "Rolling restart initiated successfully"
+// @Success 202 {object} map[string]interface{} "Recreate restart started; progress at restart-status"
 // @Failure 400 {object} map[string]string "Bad request - invalid parameters or restart type"
 // @Security BearerAuth
 // @Security KubeConfig
@@ -153,7 +160,7 @@ func (h *DeploymentsHandler) RestartDeployment(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for restarting deployment")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error(), "code": http.StatusBadRequest})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
@@ -161,7 +168,7 @@ func (h *DeploymentsHandler) RestartDeployment(c *gin.Context) {
 	name := c.Param("name")
 	namespace := c.Query("namespace")
 	if namespace == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "namespace parameter is required", "code": http.StatusBadRequest})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -180,7 +187,7 @@ func (h *DeploymentsHandler) RestartDeployment(c *gin.Context) {
 	// Validate restart type
 	if body.RestartType != "rolling" && body.RestartType != "recreate" {
 		h.tracingHelper.RecordError(parseSpan, fmt.Errorf("invalid restart type: %s", body.RestartType), "Invalid restart type")
-		c.JSON(http.StatusBadRequest, gin.H{"message": "restartType must be 'rolling' or 'recreate'", "code": http.StatusBadRequest})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "restartType must be 'rolling' or 'recreate'")
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(parseSpan, name, "deployment-restart", 1)
@@ -196,7 +203,7 @@ func (h *DeploymentsHandler) RestartDeployment(c *gin.Context) {
 		if err != nil {
 			h.logger.WithError(err).WithField("deployment", name).WithField("namespace", namespace).Error("Failed to perform rolling restart")
 			h.tracingHelper.RecordError(restartSpan, err, "Failed to perform rolling restart")
-			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error(), "code": http.StatusBadRequest})
+			utils.RespondError(c, http.StatusBadRequest, err)
 			return
 		}
 		h.tracingHelper.AddResourceAttributes(restartSpan, name, "deployment", 1)
@@ -207,22 +214,27 @@ func (h *DeploymentsHandler) RestartDeployment(c *gin.Context) {
 		_, restartSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "recreate-restart", "deployment", namespace)
 		defer restartSpan.End()
 
-		// Recreate restart: Set replicas to 0, then back to original count
-		err = h.performRecreateRestart(client, name, namespace)
+		// Recreate restart: Set replicas to 0, wait for the pods to terminate, then back to original count
+		key := recreateRestartKey(c.Query("config"), c.Query("cluster"), namespace, name)
+		status, err := h.startRecreateRestart(client, key, name, namespace)
 		if err != nil {
 			h.logger.WithError(err).WithField("deployment", name).WithField("namespace", namespace).Error("Failed to perform recreate restart")
 			h.tracingHelper.RecordError(restartSpan, err, "Failed to perform recreate restart")
-			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error(), "code": http.StatusBadRequest})
+			status := http.StatusBadRequest
+			if errors.Is(err, errRecreateRestartRunning) {
+				status = http.StatusConflict
+			}
+			utils.RespondError(c, status, err)
 			return
 		}
 		h.tracingHelper.AddResourceAttributes(restartSpan, name, "deployment", 1)
 		h.tracingHelper.RecordSuccess(restartSpan, "Recreate restart initiated successfully")
-		c.JSON(http.StatusOK, gin.H{"message": "Recreate restart initiated - pods will be restarted in the background"})
+		c.JSON(http.StatusAccepted, gin.H{"message": "Recreate restart initiated - poll restart-status for progress", "status": status})
 	}
 }
 
 // performRollingRestart performs a rolling restart by adding a restart annotation
-func (h *DeploymentsHandler) performRollingRestart(client *kubernetes.Clientset, name, namespace string) error {
+func (h *DeploymentsHandler) performRollingRestart(client kubernetes.Interface, name, namespace string) error {
 	// Get the current deployment
 	deployment, err := client.AppsV1().Deployments(namespace).Get(context.Background(), name, metav1.GetOptions{})
 	if err != nil {
@@ -244,52 +256,8 @@ func (h *DeploymentsHandler) performRollingRestart(client *kubernetes.Clientset,
 	return nil
 }
 
-// performRecreateRestart performs a recreate restart by scaling to 0 then back to original count
-func (h *DeploymentsHandler) performRecreateRestart(client *kubernetes.Clientset, name, namespace string) error {
-	// Get the current deployment to get original replica count
-	deployment, err := client.AppsV1().Deployments(namespace).Get(context.Background(), name, metav1.GetOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to get deployment: %w", err)
-	}
-
-	originalReplicas := int32(1)
-	if deployment.Spec.Replicas != nil {
-		originalReplicas = *deployment.Spec.Replicas
-	}
-
-	// Scale down to 0 with retry mechanism
-	err = h.scaleDeploymentWithRetry(client, name, namespace, 0)
-	if err != nil {
-		return fmt.Errorf("failed to scale down deployment: %w", err)
-	}
-
-	// Start the scale-up process in a goroutine to avoid blocking the response
-	go func() {
-		// Wait a moment for pods to terminate
-		time.Sleep(3 * time.Second)
-
-		// Scale back up to original replicas with retry mechanism
-		err := h.scaleDeploymentWithRetry(client, name, namespace, originalReplicas)
-		if err != nil {
-			h.logger.WithError(err).WithFields(map[string]interface{}{
-				"deployment": name,
-				"namespace":  namespace,
-				"replicas":   originalReplicas,
-			}).Error("Failed to scale up deployment during recreate restart")
-		} else {
-			h.logger.WithFields(map[string]interface{}{
-				"deployment": name,
-				"namespace":  namespace,
-				"replicas":   originalReplicas,
-			}).Info("Successfully completed recreate restart scale-up")
-		}
-	}()
-
-	return nil
-}
-
 // scaleDeploymentWithRetry scales a deployment with retry mechanism for handling "object has been modified" errors
-func (h *DeploymentsHandler) scaleDeploymentWithRetry(client *kubernetes.Clientset, name, namespace string, replicas int32) error {
+func (h *DeploymentsHandler) scaleDeploymentWithRetry(client kubernetes.Interface, name, namespace string, replicas int32) error {
 	maxRetries := 5
 	backoffDuration := 100 * time.Millisecond
 
@@ -341,7 +309,7 @@ func isConflictError(err error) bool {
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *DeploymentsHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *DeploymentsHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -362,6 +330,17 @@ func (h *DeploymentsHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Cli
 	return client, nil
 }
 
+// listDeployments lists the deployments of one namespace, or of all namespaces for ""
+func listDeployments(client kubernetes.Interface, opts metav1.ListOptions) func(ctx context.Context, namespace string) ([]appsV1.Deployment, error) {
+	return func(ctx context.Context, namespace string) ([]appsV1.Deployment, error) {
+		list, err := client.AppsV1().Deployments(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	}
+}
+
 // GetDeployments returns all deployments
 // @Summary Get Deployments (JSON)
 // @Description Retrieve all deployments in JSON format (non-streaming)
@@ -371,6 +350,8 @@ func (h *DeploymentsHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Cli
 // @Param config query string true "Kubernetes config ID"
 // @Param cluster query string false "Cluster name (for multi-cluster configs)"
 // @Param namespace query string false "Namespace to filter deployments (empty for all namespaces)"
+// @Param namespaces query string false "Comma-separated namespaces to list across, instead of namespace"
+// @Param namespaceGroup query string false "ID of a saved namespace group to list across"
 // @Success 200 {array} object "List of deployments"
 // @Failure 400 {object} map[string]string "Bad request - invalid parameters"
 // @Failure 500 {object} map[string]string "Internal server error"
@@ -386,29 +367,26 @@ func (h *DeploymentsHandler) GetDeployments(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for deployments")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
 
-	namespace := c.Query("namespace")
+	namespaces := utils.RequestedNamespaces(c)
+	namespace := strings.Join(namespaces, ",")
 	var deployments interface{}
-	var err2 error
 
 	// Start child span for Kubernetes API call
 	_, k8sSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "deployments", namespace)
 	defer k8sSpan.End()
 
-	if namespace != "" {
-		deployments, err2 = client.AppsV1().Deployments(namespace).List(c.Request.Context(), metav1.ListOptions{})
-	} else {
-		deployments, err2 = client.AppsV1().Deployments("").List(c.Request.Context(), metav1.ListOptions{})
-	}
+	items, err2 := utils.ListInNamespaces(c.Request.Context(), namespaces, listDeployments(client, utils.ListOptions(c)))
+	deployments = &appsV1.DeploymentList{Items: items}
 
 	if err2 != nil {
 		h.logger.WithError(err2).Error("Failed to list deployments")
 		h.tracingHelper.RecordError(k8sSpan, err2, "Failed to list deployments")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err2.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err2)
 		return
 	}
 
@@ -432,6 +410,12 @@ func (h *DeploymentsHandler) GetDeployments(c *gin.Context) {
 // @Param config query string true "Kubernetes config ID"
 // @Param cluster query string false "Cluster name (for multi-cluster configs)"
 // @Param namespace query string false "Namespace to filter deployments (empty for all namespaces)"
+// @Param namespaces query string false "Comma-separated namespaces to list across, instead of namespace"
+// @Param namespaceGroup query string false "ID of a saved namespace group to list across"
+// @Param fields query string false "Comma-separated response fields to return, e.g. name,status,restarts; name, namespace and uid are always included"
+// @Param labelSelector query string false "Kubernetes label selector, e.g. app=web,tier!=cache"
+// @Param sort query string false "Response field to sort by, e.g. name or -restarts for descending"
+// @Param view query string false "ID of a saved view supplying namespaces, labelSelector, sort and fields"
 // @Success 200 {array} types.DeploymentListResponse "Stream of deployment data"
 // @Failure 400 {object} map[string]string "Bad request - invalid parameters"
 // @Failure 403 {object} map[string]string "Forbidden - insufficient permissions"
@@ -453,7 +437,8 @@ func (h *DeploymentsHandler) GetDeploymentsSSE(c *gin.Context) {
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client setup for SSE")
 
-	namespace := c.Query("namespace")
+	namespaces := utils.RequestedNamespaces(c)
+	namespace := strings.Join(namespaces, ",")
 
 	// Function to fetch and transform deployments data
 	fetchDeployments := func() (interface{}, error) {
@@ -461,14 +446,8 @@ func (h *DeploymentsHandler) GetDeploymentsSSE(c *gin.Context) {
 		_, fetchSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "deployments", namespace)
 		defer fetchSpan.End()
 
-		var deploymentList *appsV1.DeploymentList
-		var err2 error
-
-		if namespace != "" {
-			deploymentList, err2 = client.AppsV1().Deployments(namespace).List(c.Request.Context(), metav1.ListOptions{})
-		} else {
-			deploymentList, err2 = client.AppsV1().Deployments("").List(c.Request.Context(), metav1.ListOptions{})
-		}
+		items, err2 := utils.ListInNamespaces(c.Request.Context(), namespaces, listDeployments(client, utils.ListOptions(c)))
+		deploymentList := &appsV1.DeploymentList{Items: items}
 
 		if err2 != nil {
 			h.tracingHelper.RecordError(fetchSpan, err2, "Failed to list deployments for SSE")
@@ -516,7 +495,7 @@ func (h *DeploymentsHandler) GetDeploymentsSSE(c *gin.Context) {
 	}
 
 	// For non-SSE requests, return JSON
-	c.JSON(http.StatusOK, initialData)
+	h.sseHandler.SendJSON(c, initialData)
 }
 
 // GetDeployment returns a specific deployment
@@ -544,7 +523,7 @@ func (h *DeploymentsHandler) GetDeployment(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for deployment")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
@@ -560,7 +539,7 @@ func (h *DeploymentsHandler) GetDeployment(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("deployment", name).WithField("namespace", namespace).Error("Failed to get deployment")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get deployment")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -568,7 +547,7 @@ func (h *DeploymentsHandler) GetDeployment(c *gin.Context) {
 	h.tracingHelper.RecordSuccess(k8sSpan, fmt.Sprintf("Retrieved deployment: %s", name))
 
 	// Always send SSE format for detail endpoints since they're used by EventSource
-	h.sseHandler.SendSSEResponse(c, deployment)
+	h.sseHandler.SendSSEDetailResponse(c, deployment, deployment, client.AppsV1().Deployments(namespace).Watch)
 }
 
 // GetDeploymentByName returns a specific deployment by name using namespace from query parameters
@@ -596,7 +575,7 @@ func (h *DeploymentsHandler) GetDeploymentByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for deployment")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
@@ -606,7 +585,7 @@ func (h *DeploymentsHandler) GetDeploymentByName(c *gin.Context) {
 
 	if namespace == "" {
 		h.logger.WithField("deployment", name).Error("Namespace is required for deployment lookup")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -618,7 +597,7 @@ func (h *DeploymentsHandler) GetDeploymentByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("deployment", name).WithField("namespace", namespace).Error("Failed to get deployment")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get deployment")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -626,7 +605,7 @@ func (h *DeploymentsHandler) GetDeploymentByName(c *gin.Context) {
 	h.tracingHelper.RecordSuccess(k8sSpan, fmt.Sprintf("Retrieved deployment: %s", name))
 
 	// Always send SSE format for detail endpoints since they're used by EventSource
-	h.sseHandler.SendSSEResponse(c, deployment)
+	h.sseHandler.SendSSEDetailResponse(c, deployment, deployment, client.AppsV1().Deployments(namespace).Watch)
 }
 
 // GetDeploymentYAMLByName returns the YAML representation of a specific deployment by name
@@ -654,7 +633,7 @@ func (h *DeploymentsHandler) GetDeploymentYAMLByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for deployment YAML")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
@@ -664,7 +643,7 @@ func (h *DeploymentsHandler) GetDeploymentYAMLByName(c *gin.Context) {
 
 	if namespace == "" {
 		h.logger.WithField("deployment", name).Error("Namespace is required for deployment YAML lookup")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -676,7 +655,7 @@ func (h *DeploymentsHandler) GetDeploymentYAMLByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("deployment", name).WithField("namespace", namespace).Error("Failed to get deployment for YAML")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get deployment for YAML")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -716,7 +695,7 @@ func (h *DeploymentsHandler) GetDeploymentYAML(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for deployment YAML")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
@@ -732,7 +711,7 @@ func (h *DeploymentsHandler) GetDeploymentYAML(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("deployment", name).WithField("namespace", namespace).Error("Failed to get deployment for YAML")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get deployment for YAML")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -772,7 +751,7 @@ func (h *DeploymentsHandler) GetDeploymentEventsByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for deployment events")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
@@ -782,7 +761,7 @@ func (h *DeploymentsHandler) GetDeploymentEventsByName(c *gin.Context) {
 
 	if namespace == "" {
 		h.logger.WithField("deployment", name).Error("Namespace is required for deployment events lookup")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -820,7 +799,7 @@ func (h *DeploymentsHandler) GetDeploymentEvents(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for deployment events")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
@@ -862,7 +841,7 @@ func (h *DeploymentsHandler) GetDeploymentPods(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for deployment pods")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
@@ -879,7 +858,7 @@ func (h *DeploymentsHandler) GetDeploymentPods(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("deployment", name).WithField("namespace", namespace).Error("Failed to get deployment")
 		h.tracingHelper.RecordError(deploymentSpan, err, "Failed to get deployment")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(deploymentSpan, name, "deployment", 1)
@@ -897,7 +876,7 @@ func (h *DeploymentsHandler) GetDeploymentPods(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("deployment", name).WithField("namespace", namespace).Error("Failed to get deployment pods")
 		h.tracingHelper.RecordError(podsSpan, err, "Failed to get deployment pods")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -934,7 +913,7 @@ func (h *DeploymentsHandler) GetDeploymentPodsByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for deployment pods")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
@@ -944,7 +923,7 @@ func (h *DeploymentsHandler) GetDeploymentPodsByName(c *gin.Context) {
 
 	if namespace == "" {
 		h.logger.WithField("deployment", name).Error("Namespace is required for deployment pods lookup")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -957,7 +936,7 @@ func (h *DeploymentsHandler) GetDeploymentPodsByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("deployment", name).WithField("namespace", namespace).Error("Failed to get deployment")
 		h.tracingHelper.RecordError(deploymentSpan, err, "Failed to get deployment")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(deploymentSpan, name, "deployment", 1)
@@ -975,7 +954,7 @@ func (h *DeploymentsHandler) GetDeploymentPodsByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("deployment", name).WithField("namespace", namespace).Error("Failed to get deployment pods")
 		h.tracingHelper.RecordError(podsSpan, err, "Failed to get deployment pods")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
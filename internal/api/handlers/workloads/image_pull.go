@@ -0,0 +1,344 @@
+package workloads
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/registry"
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Root causes reported by the image pull diagnostics
+const (
+	pullCauseInvalidName    = "invalid-image-name"
+	pullCauseNeverPull      = "never-pull-policy"
+	pullCauseMissingSecret  = "missing-secret"
+	pullCauseNoCredentials  = "no-credentials"
+	pullCauseBadCredentials = "bad-credentials"
+	pullCauseTagNotFound    = "tag-not-found"
+	pullCauseRateLimited    = "rate-limited"
+	pullCauseUnreachable    = "registry-unreachable"
+	pullCauseNodeSide       = "node-pull-failure"
+	pullCauseUnknown        = "unknown"
+)
+
+// imagePullFailureReasons are container waiting reasons set by the kubelet when a pull fails
+var imagePullFailureReasons = map[string]bool{
+	"ErrImagePull":        true,
+	"ImagePullBackOff":    true,
+	"InvalidImageName":    true,
+	"ErrImageNeverPull":   true,
+	"RegistryUnavailable": true,
+}
+
+// PullSecretStatus describes one imagePullSecret referenced by the pod
+type PullSecretStatus struct {
+	Name       string   `json:"name"`
+	Source     string   `json:"source"` // pod or serviceAccount
+	Found      bool     `json:"found"`
+	Type       string   `json:"type,omitempty"`
+	Registries []string `json:"registries,omitempty"`
+	Matches    bool     `json:"matches"` // Whether the secret has credentials for the image's registry
+	Error      string   `json:"error,omitempty"`
+}
+
+// ImagePullDiagnosis explains why one container's image cannot be pulled
+type ImagePullDiagnosis struct {
+	Container       string                  `json:"container"`
+	Image           string                  `json:"image"`
+	Reference       registry.Reference      `json:"reference"`
+	WaitingReason   string                  `json:"waitingReason,omitempty"`
+	WaitingMessage  string                  `json:"waitingMessage,omitempty"`
+	EventMessages   []string                `json:"eventMessages,omitempty"`
+	PullSecrets     []PullSecretStatus      `json:"pullSecrets"`
+	CredentialsFrom string                  `json:"credentialsFrom,omitempty"`
+	Probe           *registry.ManifestProbe `json:"probe,omitempty"`
+	RootCause       string                  `json:"rootCause"`
+	Detail          string                  `json:"detail"`
+}
+
+// ImagePullDiagnostics is the diagnostics response for a pod
+type ImagePullDiagnostics struct {
+	Pod       string               `json:"pod"`
+	Namespace string               `json:"namespace"`
+	Diagnoses []ImagePullDiagnosis `json:"diagnoses"`
+}
+
+// imagePullInput is what classifyImagePull decides on
+type imagePullInput struct {
+	waitingReason  string
+	messages       []string
+	missingSecrets []string
+	hasCredentials bool
+	probe          *registry.ManifestProbe
+}
+
+func messagesContain(messages []string, needles ...string) bool {
+	for _, m := range messages {
+		lower := strings.ToLower(m)
+		for _, needle := range needles {
+			if strings.Contains(lower, needle) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// classifyImagePull turns the registry probe, pull secrets and kubelet messages into a root cause.
+// The probe is trusted over kubelet messages because it answers for the exact credentials the pod uses.
+func classifyImagePull(in imagePullInput) (string, string) {
+	switch in.waitingReason {
+	case "InvalidImageName":
+		return pullCauseInvalidName, "the image reference cannot be parsed"
+	case "ErrImageNeverPull":
+		return pullCauseNeverPull, "imagePullPolicy is Never and the image is not present on the node"
+	}
+
+	missing := ""
+	if len(in.missingSecrets) > 0 {
+		missing = fmt.Sprintf("imagePullSecrets not found: %s", strings.Join(in.missingSecrets, ", "))
+	}
+	authFailure := func() (string, string) {
+		switch {
+		case in.hasCredentials:
+			return pullCauseBadCredentials, "the registry rejected the credentials from the pull secret"
+		case missing != "":
+			return pullCauseMissingSecret, missing
+		default:
+			return pullCauseNoCredentials, "the registry requires authentication and no pull secret has credentials for it"
+		}
+	}
+
+	if p := in.probe; p != nil {
+		switch {
+		case p.StatusCode == http.StatusTooManyRequests:
+			return pullCauseRateLimited, "the registry is rate limiting pulls"
+		case p.StatusCode == http.StatusNotFound:
+			return pullCauseTagNotFound, "the registry has no manifest for this tag or digest"
+		case p.StatusCode == http.StatusUnauthorized || p.StatusCode == http.StatusForbidden:
+			return authFailure()
+		case p.StatusCode == http.StatusOK:
+			if messagesContain(in.messages, "no match for platform") {
+				return pullCauseNodeSide, "the image has no variant for the node's platform"
+			}
+			return pullCauseNodeSide, "the registry serves the image with the pod's credentials; the node could not pull it, check node egress, registry mirrors and proxies"
+		case p.StatusCode == 0 && p.Error != "":
+			return pullCauseUnreachable, "the registry could not be reached: " + p.Error
+		}
+	}
+
+	switch {
+	case messagesContain(in.messages, "toomanyrequests", "rate limit"):
+		return pullCauseRateLimited, "the registry is rate limiting pulls"
+	case messagesContain(in.messages, "manifest unknown", "not found"):
+		return pullCauseTagNotFound, "the registry has no manifest for this tag or digest"
+	case messagesContain(in.messages, "unauthorized", "authentication required", "denied", "forbidden"):
+		return authFailure()
+	case messagesContain(in.messages, "no such host", "i/o timeout", "connection refused"):
+		return pullCauseUnreachable, "the node could not reach the registry"
+	case missing != "":
+		return pullCauseMissingSecret, missing
+	}
+	return pullCauseUnknown, "no specific cause found; check the kubelet event messages"
+}
+
+// resolvePullSecrets loads the pod's imagePullSecrets and finds credentials for a registry
+func resolvePullSecrets(ctx context.Context, client kubernetes.Interface, pod *v1.Pod, registryHost string) ([]PullSecretStatus, *registry.Credentials, string) {
+	fromServiceAccount := map[string]bool{}
+	if pod.Spec.ServiceAccountName != "" {
+		if sa, err := client.CoreV1().ServiceAccounts(pod.Namespace).Get(ctx, pod.Spec.ServiceAccountName, metav1.GetOptions{}); err == nil {
+			for _, ref := range sa.ImagePullSecrets {
+				fromServiceAccount[ref.Name] = true
+			}
+		}
+	}
+
+	statuses := []PullSecretStatus{}
+	var creds *registry.Credentials
+	var credsFrom string
+	for _, ref := range pod.Spec.ImagePullSecrets {
+		status := PullSecretStatus{Name: ref.Name, Source: "pod"}
+		if fromServiceAccount[ref.Name] {
+			status.Source = "serviceAccount"
+		}
+		secret, err := client.CoreV1().Secrets(pod.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				status.Error = err.Error()
+			}
+			statuses = append(statuses, status)
+			continue
+		}
+		status.Found = true
+		status.Type = string(secret.Type)
+
+		var data []byte
+		switch secret.Type {
+		case v1.SecretTypeDockerConfigJson:
+			data = secret.Data[v1.DockerConfigJsonKey]
+		case v1.SecretTypeDockercfg:
+			data = secret.Data[v1.DockerConfigKey]
+		default:
+			status.Error = fmt.Sprintf("secret type %s is not a registry credential", secret.Type)
+			statuses = append(statuses, status)
+			continue
+		}
+		status.Registries = registry.ConfigRegistries(data)
+		found, key, err := registry.CredentialsForRegistry(data, registryHost)
+		if err != nil {
+			status.Error = err.Error()
+		} else if key != "" {
+			status.Matches = true
+			// The kubelet uses the first matching secret in order
+			if creds == nil {
+				creds, credsFrom = found, ref.Name
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, creds, credsFrom
+}
+
+// podImagePullEvents returns the messages of the pod's failed pull events
+func podImagePullEvents(ctx context.Context, client kubernetes.Interface, pod *v1.Pod) []v1.Event {
+	selector := fields.AndSelectors(
+		fields.OneTermEqualSelector("involvedObject.name", pod.Name),
+		fields.OneTermEqualSelector("involvedObject.kind", "Pod"),
+		fields.OneTermEqualSelector("type", v1.EventTypeWarning),
+	).String()
+	events, err := client.CoreV1().Events(pod.Namespace).List(ctx, metav1.ListOptions{FieldSelector: selector})
+	if err != nil {
+		return nil
+	}
+	var failed []v1.Event
+	for _, event := range events.Items {
+		if event.InvolvedObject.UID == pod.UID && (event.Reason == "Failed" || event.Reason == "BackOff") {
+			failed = append(failed, event)
+		}
+	}
+	return failed
+}
+
+// GetImagePullDiagnostics explains why a pod's images cannot be pulled
+// @Summary Diagnose image pull failures
+// @Description For containers stuck in ErrImagePull, ImagePullBackOff or InvalidImageName, resolves which imagePullSecrets apply (from the pod and its service account), probes the registry with a manifest HEAD request using the matching credentials and returns a structured root cause: invalid-image-name, never-pull-policy, missing-secret, no-credentials, bad-credentials, tag-not-found, rate-limited, registry-unreachable or node-pull-failure. The probe runs from the dashboard server, so node-specific network issues show up as node-pull-failure.
+// @Tags Workloads
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name for multi-cluster setups"
+// @Param namespace path string true "Kubernetes namespace"
+// @Param name path string true "Pod name"
+// @Param container query string false "Diagnose this container even if it is not failing to pull"
+// @Success 200 {object} ImagePullDiagnostics
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Pod not found"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/pods/{namespace}/{name}/image-pull [get]
+func (h *ImagesHandler) GetImagePullDiagnostics(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for image pull diagnostics")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
+
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	only := c.Query("container")
+
+	_, podSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "get", "pods", namespace)
+	pod, err := client.CoreV1().Pods(namespace).Get(c.Request.Context(), name, metav1.GetOptions{})
+	if err != nil {
+		h.tracingHelper.RecordError(podSpan, err, "Failed to get pod")
+		podSpan.End()
+		utils.RespondError(c, http.StatusNotFound, err)
+		return
+	}
+	h.tracingHelper.RecordSuccess(podSpan, "Pod retrieved")
+	podSpan.End()
+
+	statuses := map[string]v1.ContainerStatus{}
+	for _, status := range append(append([]v1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...) {
+		statuses[status.Name] = status
+	}
+
+	_, diagnoseSpan := h.tracingHelper.StartDataProcessingSpan(ctx, "diagnose-image-pull")
+	defer diagnoseSpan.End()
+	probeCtx, cancel := context.WithTimeout(c.Request.Context(), 20*time.Second)
+	defer cancel()
+
+	var events []v1.Event
+	result := ImagePullDiagnostics{Pod: name, Namespace: namespace, Diagnoses: []ImagePullDiagnosis{}}
+	for _, container := range append(append([]v1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...) {
+		status := statuses[container.Name]
+		var reason, message string
+		if status.State.Waiting != nil {
+			reason, message = status.State.Waiting.Reason, status.State.Waiting.Message
+		}
+		if only != "" && container.Name != only {
+			continue
+		}
+		if only == "" && !imagePullFailureReasons[reason] {
+			continue
+		}
+		if events == nil {
+			events = podImagePullEvents(c.Request.Context(), client, pod)
+		}
+
+		ref := registry.ParseReference(container.Image)
+		diagnosis := ImagePullDiagnosis{
+			Container:      container.Name,
+			Image:          container.Image,
+			Reference:      ref,
+			WaitingReason:  reason,
+			WaitingMessage: message,
+		}
+		for _, event := range events {
+			if strings.Contains(event.Message, container.Image) {
+				diagnosis.EventMessages = append(diagnosis.EventMessages, event.Message)
+			}
+		}
+
+		secrets, creds, credsFrom := resolvePullSecrets(c.Request.Context(), client, pod, ref.Registry)
+		diagnosis.PullSecrets, diagnosis.CredentialsFrom = secrets, credsFrom
+		var missing []string
+		for _, secret := range secrets {
+			if !secret.Found && secret.Error == "" {
+				missing = append(missing, secret.Name)
+			}
+		}
+
+		if reason != "InvalidImageName" {
+			probe := h.registryClient.ProbeManifest(probeCtx, ref, creds)
+			diagnosis.Probe = &probe
+		}
+		diagnosis.RootCause, diagnosis.Detail = classifyImagePull(imagePullInput{
+			waitingReason:  reason,
+			messages:       append([]string{message}, diagnosis.EventMessages...),
+			missingSecrets: missing,
+			hasCredentials: creds != nil,
+			probe:          diagnosis.Probe,
+		})
+		result.Diagnoses = append(result.Diagnoses, diagnosis)
+	}
+	h.tracingHelper.RecordSuccess(diagnoseSpan, fmt.Sprintf("Diagnosed %d containers", len(result.Diagnoses)))
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,37 @@
+package workloads
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Facets-cloud/kube-dash/internal/registry"
+)
+
+func TestClassifyImagePull(t *testing.T) {
+	probe := func(code int, err string) *registry.ManifestProbe {
+		return &registry.ManifestProbe{StatusCode: code, Error: err}
+	}
+	tests := []struct {
+		name  string
+		input imagePullInput
+		want  string
+	}{
+		{"invalid name", imagePullInput{waitingReason: "InvalidImageName"}, pullCauseInvalidName},
+		{"tag not found", imagePullInput{probe: probe(http.StatusNotFound, "not found")}, pullCauseTagNotFound},
+		{"rate limited", imagePullInput{probe: probe(http.StatusTooManyRequests, "")}, pullCauseRateLimited},
+		{"bad credentials", imagePullInput{hasCredentials: true, probe: probe(http.StatusUnauthorized, "")}, pullCauseBadCredentials},
+		{"missing secret", imagePullInput{missingSecrets: []string{"regcred"}, probe: probe(http.StatusUnauthorized, "")}, pullCauseMissingSecret},
+		{"no credentials", imagePullInput{probe: probe(http.StatusForbidden, "")}, pullCauseNoCredentials},
+		{"unreachable", imagePullInput{probe: probe(0, "dial tcp: no such host")}, pullCauseUnreachable},
+		{"node side", imagePullInput{probe: probe(http.StatusOK, "")}, pullCauseNodeSide},
+		{"rate limit from events", imagePullInput{messages: []string{"429 toomanyrequests: You have reached your pull rate limit"}}, pullCauseRateLimited},
+		{"unknown", imagePullInput{}, pullCauseUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, detail := classifyImagePull(tt.input); got != tt.want {
+				t.Errorf("classifyImagePull() = %s (%s), want %s", got, detail, tt.want)
+			}
+		})
+	}
+}
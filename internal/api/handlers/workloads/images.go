@@ -0,0 +1,271 @@
+package workloads
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/registry"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/internal/tracing"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// registryLookupConcurrency bounds parallel registry calls when checking for newer images
+const registryLookupConcurrency = 8
+
+// ImageInventoryEntry aggregates every use of one image reference in the cluster
+type ImageInventoryEntry struct {
+	Image            string   `json:"image"`
+	Registry         string   `json:"registry"`
+	Repository       string   `json:"repository"`
+	Tag              string   `json:"tag,omitempty"`
+	Digest           string   `json:"digest,omitempty"`
+	RunningDigests   []string `json:"runningDigests"`
+	PodCount         int      `json:"podCount"`
+	ContainerCount   int      `json:"containerCount"`
+	Namespaces       []string `json:"namespaces"`
+	PullPolicies     []string `json:"pullPolicies"`
+	ImagePullSecrets []string `json:"imagePullSecrets"`
+
+	// Registry metadata, only populated when a registry check is requested
+	RegistryDigest string `json:"registryDigest,omitempty"`
+	LatestTag      string `json:"latestTag,omitempty"`
+	Outdated       bool   `json:"outdated"`
+	RegistryError  string `json:"registryError,omitempty"`
+}
+
+// ImagesHandler serves the cluster image inventory
+type ImagesHandler struct {
+	store          *storage.KubeConfigStore
+	clientFactory  *k8s.ClientFactory
+	logger         *logger.Logger
+	tracingHelper  *tracing.TracingHelper
+	registryClient *registry.Client
+}
+
+// NewImagesHandler creates a new images handler
+func NewImagesHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger) *ImagesHandler {
+	return &ImagesHandler{
+		store:          store,
+		clientFactory:  clientFactory,
+		logger:         log,
+		tracingHelper:  tracing.GetTracingHelper(),
+		registryClient: registry.NewClient(10 * time.Second),
+	}
+}
+
+// getClientAndConfig gets the Kubernetes client for the current request
+func (h *ImagesHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
+	configID := c.Query("config")
+	cluster := c.Query("cluster")
+
+	if configID == "" {
+		return nil, fmt.Errorf("config parameter is required")
+	}
+
+	config, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, fmt.Errorf("config not found: %w", err)
+	}
+
+	client, err := h.clientFactory.GetClientForConfig(config, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Kubernetes client: %w", err)
+	}
+
+	return client, nil
+}
+
+// CollectImageInventory lists pods and aggregates the images they run
+func CollectImageInventory(ctx context.Context, client kubernetes.Interface, namespace string) ([]ImageInventoryEntry, error) {
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return buildImageInventory(pods.Items), nil
+}
+
+// buildImageInventory groups containers by image reference
+func buildImageInventory(pods []v1.Pod) []ImageInventoryEntry {
+	type aggregate struct {
+		entry        *ImageInventoryEntry
+		pods         map[string]bool
+		digests      map[string]bool
+		namespaces   map[string]bool
+		pullPolicies map[string]bool
+		pullSecrets  map[string]bool
+	}
+	byImage := map[string]*aggregate{}
+
+	for i := range pods {
+		pod := &pods[i]
+		// Map container names to the digest the kubelet actually pulled
+		imageIDs := map[string]string{}
+		for _, status := range append(append([]v1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...) {
+			if at := strings.LastIndex(status.ImageID, "@"); at >= 0 {
+				imageIDs[status.Name] = status.ImageID[at+1:]
+			}
+		}
+
+		containers := append(append([]v1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+		for _, container := range containers {
+			agg, ok := byImage[container.Image]
+			if !ok {
+				ref := registry.ParseReference(container.Image)
+				agg = &aggregate{
+					entry: &ImageInventoryEntry{
+						Image:      container.Image,
+						Registry:   ref.Registry,
+						Repository: ref.Repository,
+						Tag:        ref.Tag,
+						Digest:     ref.Digest,
+					},
+					pods:         map[string]bool{},
+					digests:      map[string]bool{},
+					namespaces:   map[string]bool{},
+					pullPolicies: map[string]bool{},
+					pullSecrets:  map[string]bool{},
+				}
+				byImage[container.Image] = agg
+			}
+			agg.entry.ContainerCount++
+			agg.pods[pod.Namespace+"/"+pod.Name] = true
+			agg.namespaces[pod.Namespace] = true
+			if container.ImagePullPolicy != "" {
+				agg.pullPolicies[string(container.ImagePullPolicy)] = true
+			}
+			for _, secret := range pod.Spec.ImagePullSecrets {
+				agg.pullSecrets[pod.Namespace+"/"+secret.Name] = true
+			}
+			if digest := imageIDs[container.Name]; digest != "" {
+				agg.digests[digest] = true
+			}
+		}
+	}
+
+	sortedKeys := func(m map[string]bool) []string {
+		out := make([]string, 0, len(m))
+		for k := range m {
+			out = append(out, k)
+		}
+		sort.Strings(out)
+		return out
+	}
+
+	inventory := make([]ImageInventoryEntry, 0, len(byImage))
+	for _, agg := range byImage {
+		agg.entry.PodCount = len(agg.pods)
+		agg.entry.RunningDigests = sortedKeys(agg.digests)
+		agg.entry.Namespaces = sortedKeys(agg.namespaces)
+		agg.entry.PullPolicies = sortedKeys(agg.pullPolicies)
+		agg.entry.ImagePullSecrets = sortedKeys(agg.pullSecrets)
+		inventory = append(inventory, *agg.entry)
+	}
+	sort.Slice(inventory, func(i, j int) bool { return inventory[i].Image < inventory[j].Image })
+	return inventory
+}
+
+// enrichWithRegistry queries registries for the current digest and newest version tag of each image
+func (h *ImagesHandler) enrichWithRegistry(ctx context.Context, inventory []ImageInventoryEntry) {
+	sem := make(chan struct{}, registryLookupConcurrency)
+	var wg sync.WaitGroup
+	for i := range inventory {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(entry *ImageInventoryEntry) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			ref := registry.Reference{Registry: entry.Registry, Repository: entry.Repository, Tag: entry.Tag}
+			if entry.Tag != "" {
+				digest, err := h.registryClient.ResolveDigest(ctx, ref)
+				if err != nil {
+					entry.RegistryError = err.Error()
+					return
+				}
+				entry.RegistryDigest = digest
+				// A tag that now points elsewhere means the running pods are behind
+				for _, running := range entry.RunningDigests {
+					if running != digest {
+						entry.Outdated = true
+						break
+					}
+				}
+			}
+			if tags, err := h.registryClient.ListTags(ctx, ref); err == nil {
+				entry.LatestTag = registry.LatestVersionTag(tags)
+				if entry.LatestTag != "" && entry.Tag != "" && entry.LatestTag != entry.Tag && registry.LatestVersionTag([]string{entry.Tag, entry.LatestTag}) == entry.LatestTag {
+					entry.Outdated = true
+				}
+			}
+		}(&inventory[i])
+	}
+	wg.Wait()
+}
+
+// GetImageInventory returns all container images running in the cluster or a namespace
+// @Summary Get image inventory
+// @Description Aggregates all container images running in the cluster (or a namespace) with pod counts, pull policies and imagePullSecrets usage. Optionally queries registries for the current tag digest and newest version tag to flag outdated images.
+// @Tags Workloads
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param namespace query string false "Namespace to filter (empty for all namespaces)"
+// @Param checkRegistry query bool false "Query registries for newer digests and tags"
+// @Success 200 {array} ImageInventoryEntry "Image inventory"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/images [get]
+func (h *ImagesHandler) GetImageInventory(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for image inventory")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
+
+	namespace := c.Query("namespace")
+
+	_, listSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "pods", namespace)
+	defer listSpan.End()
+	inventory, err := CollectImageInventory(c.Request.Context(), client, namespace)
+	if err != nil {
+		h.logger.WithError(err).WithField("namespace", namespace).Error("Failed to collect image inventory")
+		h.tracingHelper.RecordError(listSpan, err, "Failed to list pods")
+		utils.RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	h.tracingHelper.AddResourceAttributes(listSpan, "", "images", len(inventory))
+	h.tracingHelper.RecordSuccess(listSpan, fmt.Sprintf("Collected %d images", len(inventory)))
+
+	if c.Query("checkRegistry") == "true" {
+		_, registrySpan := h.tracingHelper.StartDataProcessingSpan(ctx, "check-registries")
+		defer registrySpan.End()
+		registryCtx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+		h.enrichWithRegistry(registryCtx, inventory)
+		h.tracingHelper.RecordSuccess(registrySpan, "Registry metadata collected")
+	}
+
+	c.JSON(http.StatusOK, inventory)
+}
@@ -1,8 +1,10 @@
 package workloads
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/Facets-cloud/kube-dash/internal/api/transformers"
 	"github.com/Facets-cloud/kube-dash/internal/api/types"
@@ -13,6 +15,7 @@ import (
 	"github.com/Facets-cloud/kube-dash/pkg/logger"
 
 	"github.com/gin-gonic/gin"
+	batchv1 "k8s.io/api/batch/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -42,7 +45,7 @@ func NewJobsHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFac
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *JobsHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *JobsHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -72,6 +75,12 @@ func (h *JobsHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset,
 // @Param config query string true "Kubernetes configuration ID"
 // @Param cluster query string false "Cluster name for multi-cluster setups"
 // @Param namespace query string false "Kubernetes namespace to filter resources"
+// @Param namespaces query string false "Comma-separated namespaces to list across, instead of namespace"
+// @Param namespaceGroup query string false "ID of a saved namespace group to list across"
+// @Param fields query string false "Comma-separated response fields to return, e.g. name,status,restarts; name, namespace and uid are always included"
+// @Param labelSelector query string false "Kubernetes label selector, e.g. app=web,tier!=cache"
+// @Param sort query string false "Response field to sort by, e.g. name or -restarts for descending"
+// @Param view query string false "ID of a saved view supplying namespaces, labelSelector, sort and fields"
 // @Success 200 {array} types.JobListResponse "Streaming Jobs data"
 // @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
 // @Failure 403 {object} map[string]string "Forbidden - insufficient permissions"
@@ -93,7 +102,8 @@ func (h *JobsHandler) GetJobsSSE(c *gin.Context) {
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client setup for SSE")
 
-	namespace := c.Query("namespace")
+	namespaces := utils.RequestedNamespaces(c)
+	namespace := strings.Join(namespaces, ",")
 
 	// Function to fetch and transform jobs data
 	fetchJobs := func() (interface{}, error) {
@@ -101,7 +111,14 @@ func (h *JobsHandler) GetJobsSSE(c *gin.Context) {
 		_, fetchSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "jobs", namespace)
 		defer fetchSpan.End()
 
-		jobList, err := client.BatchV1().Jobs(namespace).List(c.Request.Context(), metav1.ListOptions{})
+		items, err := utils.ListInNamespaces(c.Request.Context(), namespaces, func(ctx context.Context, namespace string) ([]batchv1.Job, error) {
+			list, err := client.BatchV1().Jobs(namespace).List(ctx, utils.ListOptions(c))
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		jobList := &batchv1.JobList{Items: items}
 		if err != nil {
 			h.tracingHelper.RecordError(fetchSpan, err, "Failed to list jobs for SSE")
 			return nil, err
@@ -147,7 +164,7 @@ func (h *JobsHandler) GetJobsSSE(c *gin.Context) {
 	}
 
 	// For non-SSE requests, return JSON
-	c.JSON(http.StatusOK, initialData)
+	h.sseHandler.SendJSON(c, initialData)
 }
 
 // GetJob returns a specific job
@@ -180,7 +197,7 @@ func (h *JobsHandler) GetJob(c *gin.Context) {
 		if c.GetHeader("Accept") == "text/event-stream" {
 			h.sseHandler.SendSSEError(c, http.StatusBadRequest, err.Error())
 		} else {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			utils.RespondError(c, http.StatusBadRequest, err)
 		}
 		return
 	}
@@ -201,7 +218,7 @@ func (h *JobsHandler) GetJob(c *gin.Context) {
 		if c.GetHeader("Accept") == "text/event-stream" {
 			h.sseHandler.SendSSEError(c, http.StatusNotFound, err.Error())
 		} else {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			utils.RespondError(c, http.StatusNotFound, err)
 		}
 		return
 	}
@@ -211,7 +228,7 @@ func (h *JobsHandler) GetJob(c *gin.Context) {
 	// Check if this is an SSE request (EventSource expects SSE format)
 	acceptHeader := c.GetHeader("Accept")
 	if acceptHeader == "text/event-stream" {
-		h.sseHandler.SendSSEResponse(c, job)
+		h.sseHandler.SendSSEDetailResponse(c, job, job, client.BatchV1().Jobs(namespace).Watch)
 		return
 	}
 
@@ -244,7 +261,7 @@ func (h *JobsHandler) GetJobByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for job by name")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client setup completed")
@@ -253,7 +270,7 @@ func (h *JobsHandler) GetJobByName(c *gin.Context) {
 	namespace := c.Query("namespace")
 
 	if namespace == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -265,7 +282,7 @@ func (h *JobsHandler) GetJobByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("job", name).WithField("namespace", namespace).Error("Failed to get job by name")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get job by name")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(k8sSpan, name, "job", 1)
@@ -300,7 +317,7 @@ func (h *JobsHandler) GetJobYAMLByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for job YAML by name")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client setup completed")
@@ -309,7 +326,7 @@ func (h *JobsHandler) GetJobYAMLByName(c *gin.Context) {
 	namespace := c.Query("namespace")
 
 	if namespace == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -321,7 +338,7 @@ func (h *JobsHandler) GetJobYAMLByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("job", name).WithField("namespace", namespace).Error("Failed to get job for YAML by name")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get job for YAML")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(k8sSpan, name, "job", 1)
@@ -361,7 +378,7 @@ func (h *JobsHandler) GetJobYAML(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for job YAML")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client setup completed")
@@ -377,7 +394,7 @@ func (h *JobsHandler) GetJobYAML(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("job", name).WithField("namespace", namespace).Error("Failed to get job for YAML")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get job for YAML")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(k8sSpan, name, "job", 1)
@@ -412,7 +429,7 @@ func (h *JobsHandler) GetJobEventsByName(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for job events")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -421,7 +438,7 @@ func (h *JobsHandler) GetJobEventsByName(c *gin.Context) {
 
 	if namespace == "" {
 		h.logger.WithField("job", name).Error("Namespace is required for job events lookup")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -448,7 +465,7 @@ func (h *JobsHandler) GetJobEvents(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for job events")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -477,7 +494,7 @@ func (h *JobsHandler) GetJobPodsByName(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for job pods by name")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -485,7 +502,7 @@ func (h *JobsHandler) GetJobPodsByName(c *gin.Context) {
 	namespace := c.Query("namespace")
 
 	if namespace == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -493,7 +510,7 @@ func (h *JobsHandler) GetJobPodsByName(c *gin.Context) {
 	job, err := client.BatchV1().Jobs(namespace).Get(c.Request.Context(), name, metav1.GetOptions{})
 	if err != nil {
 		h.logger.WithError(err).WithField("job", name).WithField("namespace", namespace).Error("Failed to get job")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -504,7 +521,7 @@ func (h *JobsHandler) GetJobPodsByName(c *gin.Context) {
 	})
 	if err != nil {
 		h.logger.WithError(err).WithField("job", name).WithField("namespace", namespace).Error("Failed to get job pods")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
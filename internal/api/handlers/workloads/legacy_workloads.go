@@ -0,0 +1,459 @@
+package workloads
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/transformers"
+	"github.com/Facets-cloud/kube-dash/internal/api/types"
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+
+	"github.com/gin-gonic/gin"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// mirrorPodAnnotation marks the API copy of a static pod run by the kubelet
+	mirrorPodAnnotation = "kubernetes.io/config.mirror"
+	// createdByAnnotation is the serialized creator reference older controllers set on pods
+	createdByAnnotation = "kubernetes.io/created-by"
+	// lastAppliedAnnotation holds kubectl's last applied configuration
+	lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+// generatedPodLabels are added to pods by controllers and must not end up in a new selector
+var generatedPodLabels = []string{"pod-template-hash", "controller-revision-hash", "controller-uid", "batch.kubernetes.io/controller-uid", "statefulset.kubernetes.io/pod-name", "apps.kubernetes.io/pod-index"}
+
+// AdoptionHint suggests what created or could take over a pod without an owner
+type AdoptionHint struct {
+	Kind   string `json:"kind"` // ReplicationController, ReplicaSet, Job or Tool
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// UnownedPod is a pod without owner references together with hints about where it came from
+type UnownedPod struct {
+	types.PodListResponse
+	Hints []AdoptionHint `json:"hints"`
+}
+
+// DeploymentConversion is a Deployment equivalent to a legacy workload with the steps to migrate to it
+type DeploymentConversion struct {
+	SourceKind string             `json:"sourceKind"`
+	SourceName string             `json:"sourceName"`
+	Namespace  string             `json:"namespace"`
+	Deployment *appsv1.Deployment `json:"deployment"`
+	YAML       string             `json:"yaml"`
+	Steps      []string           `json:"steps"`
+	Warnings   []string           `json:"warnings,omitempty"`
+}
+
+// adoptionHints explains where an unowned pod may come from: controllers whose selector matches it,
+// the creator reference older clusters recorded, and labels set by tools such as Helm or kubectl run
+func adoptionHints(pod *v1.Pod, controllers []v1.ReplicationController, replicaSets []appsv1.ReplicaSet) []AdoptionHint {
+	hints := []AdoptionHint{}
+	podLabels := labels.Set(pod.Labels)
+	for _, rc := range controllers {
+		if rc.Namespace != pod.Namespace || len(rc.Spec.Selector) == 0 {
+			continue
+		}
+		if labels.SelectorFromSet(rc.Spec.Selector).Matches(podLabels) {
+			hints = append(hints, AdoptionHint{Kind: "ReplicationController", Name: rc.Name, Reason: "selector matches the pod's labels; the controller adopts it unless it is being deleted"})
+		}
+	}
+	for _, rs := range replicaSets {
+		if rs.Namespace != pod.Namespace || rs.Spec.Selector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(rs.Spec.Selector)
+		if err != nil || selector.Empty() {
+			continue
+		}
+		if selector.Matches(podLabels) {
+			hints = append(hints, AdoptionHint{Kind: "ReplicaSet", Name: rs.Name, Reason: "selector matches the pod's labels; the controller adopts it unless it is being deleted"})
+		}
+	}
+
+	if createdBy, ok := pod.Annotations[createdByAnnotation]; ok {
+		var ref struct {
+			Reference v1.ObjectReference `json:"reference"`
+		}
+		if err := json.Unmarshal([]byte(createdBy), &ref); err == nil && ref.Reference.Kind != "" {
+			hints = append(hints, AdoptionHint{Kind: ref.Reference.Kind, Name: ref.Reference.Name, Reason: "recorded as creator in the " + createdByAnnotation + " annotation"})
+		}
+	}
+	if jobName := pod.Labels["job-name"]; jobName != "" {
+		hints = append(hints, AdoptionHint{Kind: "Job", Name: jobName, Reason: "job-name label; the job was deleted without its pods"})
+	}
+	if manager := pod.Labels["app.kubernetes.io/managed-by"]; manager != "" {
+		hints = append(hints, AdoptionHint{Kind: "Tool", Name: manager, Reason: "app.kubernetes.io/managed-by label"})
+	}
+	if chart := pod.Labels["helm.sh/chart"]; chart != "" {
+		hints = append(hints, AdoptionHint{Kind: "Tool", Name: "Helm", Reason: "helm.sh/chart label " + chart})
+	}
+	if run := pod.Labels["run"]; run != "" {
+		hints = append(hints, AdoptionHint{Kind: "Tool", Name: "kubectl run", Reason: "run=" + run + " label set by kubectl run"})
+	}
+	return hints
+}
+
+// isUnowned reports whether a pod has no controller and is not a static pod mirrored by a kubelet
+func isUnowned(pod *v1.Pod) bool {
+	if len(pod.OwnerReferences) > 0 {
+		return false
+	}
+	_, mirror := pod.Annotations[mirrorPodAnnotation]
+	return !mirror
+}
+
+// legacyObjectMeta copies the labels and annotations worth keeping onto a new object
+func legacyObjectMeta(name, namespace string, meta metav1.ObjectMeta) metav1.ObjectMeta {
+	out := metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{}}
+	for key, value := range meta.Labels {
+		out.Labels[key] = value
+	}
+	for key, value := range meta.Annotations {
+		if key == lastAppliedAnnotation || key == createdByAnnotation || strings.HasPrefix(key, "deployment.kubernetes.io/") {
+			continue
+		}
+		if out.Annotations == nil {
+			out.Annotations = map[string]string{}
+		}
+		out.Annotations[key] = value
+	}
+	for _, key := range generatedPodLabels {
+		delete(out.Labels, key)
+	}
+	if len(out.Labels) == 0 {
+		out.Labels = nil
+	}
+	return out
+}
+
+// stripInjectedVolumes removes the service account token volume the API server injects into every
+// pod; it is injected again into the Deployment's pods
+func stripInjectedVolumes(spec *v1.PodSpec) {
+	injected := map[string]bool{}
+	volumes := spec.Volumes[:0]
+	for _, volume := range spec.Volumes {
+		if strings.HasPrefix(volume.Name, "kube-api-access-") && volume.Projected != nil {
+			injected[volume.Name] = true
+			continue
+		}
+		volumes = append(volumes, volume)
+	}
+	spec.Volumes = volumes
+	if len(injected) == 0 {
+		return
+	}
+	strip := func(containers []v1.Container) {
+		for i := range containers {
+			mounts := containers[i].VolumeMounts[:0]
+			for _, mount := range containers[i].VolumeMounts {
+				if !injected[mount.Name] {
+					mounts = append(mounts, mount)
+				}
+			}
+			containers[i].VolumeMounts = mounts
+		}
+	}
+	strip(spec.InitContainers)
+	strip(spec.Containers)
+}
+
+// deploymentFromReplicationController builds the Deployment that replaces a replication controller.
+// The selector and template carry over, so Services keep routing to the new pods.
+func deploymentFromReplicationController(rc *v1.ReplicationController, name string) (*DeploymentConversion, error) {
+	if rc.Spec.Template == nil {
+		return nil, fmt.Errorf("replication controller %s has no pod template", rc.Name)
+	}
+	if name == "" {
+		name = rc.Name
+	}
+	template := rc.Spec.Template.DeepCopy()
+	template.ObjectMeta = legacyObjectMeta("", "", template.ObjectMeta)
+
+	// A replication controller without a selector selects its template labels
+	selector := rc.Spec.Selector
+	if len(selector) == 0 {
+		selector = template.Labels
+	}
+	var warnings []string
+	if len(selector) == 0 {
+		return nil, fmt.Errorf("replication controller %s has neither a selector nor template labels", rc.Name)
+	}
+	if !labels.SelectorFromSet(selector).Matches(labels.Set(template.Labels)) {
+		return nil, fmt.Errorf("the selector of replication controller %s does not match its template labels", rc.Name)
+	}
+
+	replicas := int32(1)
+	if rc.Spec.Replicas != nil {
+		replicas = *rc.Spec.Replicas
+	}
+	deployment := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: legacyObjectMeta(name, rc.Namespace, rc.ObjectMeta),
+		Spec: appsv1.DeploymentSpec{
+			Replicas:        &replicas,
+			Selector:        &metav1.LabelSelector{MatchLabels: selector},
+			Template:        *template,
+			MinReadySeconds: rc.Spec.MinReadySeconds,
+		},
+	}
+	if name == rc.Name {
+		warnings = append(warnings, "The Deployment has the replication controller's name; Deployments and replication controllers are different kinds, so both can exist at once")
+	}
+	if replicas == 0 {
+		warnings = append(warnings, "The replication controller is scaled to 0, so the Deployment starts with no pods")
+	}
+
+	steps := []string{
+		fmt.Sprintf("Apply the Deployment to namespace %s", rc.Namespace),
+		fmt.Sprintf("Wait until deployment/%s reports %d available replicas; until then Services route to pods of both", name, replicas),
+		fmt.Sprintf("Scale the replication controller down: kubectl -n %s scale rc/%s --replicas=0", rc.Namespace, rc.Name),
+		fmt.Sprintf("Delete the replication controller: kubectl -n %s delete rc/%s", rc.Namespace, rc.Name),
+	}
+	return newDeploymentConversion("ReplicationController", rc.Name, rc.Namespace, deployment, steps, warnings)
+}
+
+// deploymentFromPod builds a single-replica Deployment that runs a standalone pod's spec
+func deploymentFromPod(pod *v1.Pod, name string) (*DeploymentConversion, error) {
+	if name == "" {
+		name = legacyAppName(pod)
+	}
+	template := v1.PodTemplateSpec{
+		ObjectMeta: legacyObjectMeta("", "", pod.ObjectMeta),
+		Spec:       *pod.Spec.DeepCopy(),
+	}
+	spec := &template.Spec
+
+	var warnings []string
+	if spec.NodeName != "" {
+		warnings = append(warnings, fmt.Sprintf("The pod was bound to node %s; the Deployment's pods are scheduled freely", spec.NodeName))
+		spec.NodeName = ""
+	}
+	if len(spec.EphemeralContainers) > 0 {
+		warnings = append(warnings, "Ephemeral debug containers are not carried over")
+		spec.EphemeralContainers = nil
+	}
+	if spec.RestartPolicy != "" && spec.RestartPolicy != v1.RestartPolicyAlways {
+		warnings = append(warnings, fmt.Sprintf("Restart policy %s becomes Always, the only policy Deployments allow; consider a Job if the pod runs to completion", spec.RestartPolicy))
+	}
+	spec.RestartPolicy = v1.RestartPolicyAlways
+	stripInjectedVolumes(spec)
+	for _, volume := range spec.Volumes {
+		if volume.EmptyDir != nil {
+			warnings = append(warnings, fmt.Sprintf("Data in emptyDir volume %s is lost when the pod is replaced", volume.Name))
+		}
+	}
+
+	selector := map[string]string{}
+	for key, value := range template.Labels {
+		selector[key] = value
+	}
+	if len(selector) == 0 {
+		selector["app"] = name
+		template.Labels = map[string]string{"app": name}
+		warnings = append(warnings, fmt.Sprintf("The pod has no labels; app=%s was added, so Services must select it before they route to the Deployment", name))
+	}
+
+	replicas := int32(1)
+	deployment := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: pod.Namespace, Labels: template.Labels},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: selector},
+			Template: template,
+		},
+	}
+
+	steps := []string{
+		fmt.Sprintf("Apply the Deployment to namespace %s", pod.Namespace),
+		fmt.Sprintf("Wait until deployment/%s reports 1 available replica", name),
+		fmt.Sprintf("Delete the standalone pod: kubectl -n %s delete pod/%s", pod.Namespace, pod.Name),
+	}
+	return newDeploymentConversion("Pod", pod.Name, pod.Namespace, deployment, steps, warnings)
+}
+
+// legacyAppName picks a Deployment name for a standalone pod from its app labels
+func legacyAppName(pod *v1.Pod) string {
+	for _, key := range []string{"app.kubernetes.io/name", "app", "k8s-app", "run"} {
+		if value := pod.Labels[key]; value != "" {
+			return value
+		}
+	}
+	if pod.GenerateName != "" {
+		return strings.TrimSuffix(pod.GenerateName, "-")
+	}
+	return pod.Name
+}
+
+// newDeploymentConversion renders a Deployment as applicable YAML without status or server-set fields
+func newDeploymentConversion(kind, name, namespace string, deployment *appsv1.Deployment, steps, warnings []string) (*DeploymentConversion, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(deployment)
+	if err != nil {
+		return nil, err
+	}
+	unstructured.RemoveNestedField(obj, "status")
+	unstructured.RemoveNestedField(obj, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(obj, "spec", "template", "metadata", "creationTimestamp")
+	out, err := yaml.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	return &DeploymentConversion{
+		SourceKind: kind,
+		SourceName: name,
+		Namespace:  namespace,
+		Deployment: deployment,
+		YAML:       string(out),
+		Steps:      steps,
+		Warnings:   warnings,
+	}, nil
+}
+
+// GetUnownedPods lists pods that no controller owns
+// @Summary List unowned pods
+// @Description Lists pods without owner references, which are invisible under any workload: pods created directly or by external systems, pods orphaned by a cascade=orphan delete and pods left over from older clusters. Static pods mirrored by a kubelet are excluded. Each pod comes with adoption hints: replication controllers or replica sets whose selector matches it, the creator recorded by older clusters, and Helm, kubectl run or managed-by labels.
+// @Tags Workloads
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name for multi-cluster setups"
+// @Param namespace query string false "Kubernetes namespace to filter resources"
+// @Param namespaces query string false "Comma-separated namespaces to list across, instead of namespace"
+// @Param labelSelector query string false "Kubernetes label selector, e.g. app=web,tier!=cache"
+// @Success 200 {array} UnownedPod "Unowned pods"
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/pods/unowned [get]
+func (h *PodsHandler) GetUnownedPods(c *gin.Context) {
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for unowned pods")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	namespaces := utils.RequestedNamespaces(c)
+	pods, err := utils.ListInNamespaces(c.Request.Context(), namespaces, func(ctx context.Context, namespace string) ([]v1.Pod, error) {
+		list, err := client.CoreV1().Pods(namespace).List(ctx, utils.ListOptions(c))
+		if err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	})
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list pods for unowned pods")
+		utils.RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	unowned := []v1.Pod{}
+	for _, pod := range pods {
+		if isUnowned(&pod) {
+			unowned = append(unowned, pod)
+		}
+	}
+
+	// Controllers are only listed for hints, so failing to list them is not fatal
+	var controllers []v1.ReplicationController
+	var replicaSets []appsv1.ReplicaSet
+	if len(unowned) > 0 {
+		controllers, err = utils.ListInNamespaces(c.Request.Context(), namespaces, func(ctx context.Context, namespace string) ([]v1.ReplicationController, error) {
+			list, err := client.CoreV1().ReplicationControllers(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		if err != nil {
+			h.logger.WithError(err).Warn("Failed to list replication controllers for adoption hints")
+		}
+		replicaSets, err = utils.ListInNamespaces(c.Request.Context(), namespaces, func(ctx context.Context, namespace string) ([]appsv1.ReplicaSet, error) {
+			list, err := client.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		if err != nil {
+			h.logger.WithError(err).Warn("Failed to list replica sets for adoption hints")
+		}
+	}
+
+	configID := c.Query("config")
+	clusterName := c.Query("cluster")
+	response := make([]UnownedPod, 0, len(unowned))
+	for i := range unowned {
+		response = append(response, UnownedPod{
+			PodListResponse: transformers.TransformPodToResponse(&unowned[i], configID, clusterName),
+			Hints:           adoptionHints(&unowned[i], controllers, replicaSets),
+		})
+	}
+	sort.Slice(response, func(i, j int) bool {
+		if response[i].Namespace != response[j].Namespace {
+			return response[i].Namespace < response[j].Namespace
+		}
+		return response[i].Name < response[j].Name
+	})
+	c.JSON(http.StatusOK, response)
+}
+
+// ConvertPodToDeployment returns a Deployment that runs a standalone pod
+// @Summary Convert pod to Deployment
+// @Description Builds a single-replica Deployment from a standalone pod's spec with the steps to migrate to it. Node binding, ephemeral containers and the injected service account token volume are dropped and the restart policy becomes Always. Nothing is applied to the cluster.
+// @Tags Workloads
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name for multi-cluster setups"
+// @Param namespace path string true "Kubernetes namespace"
+// @Param name path string true "Pod name"
+// @Param deployment query string false "Deployment name; defaults to the pod's app label or name"
+// @Success 200 {object} DeploymentConversion "Deployment and migration steps"
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
+// @Failure 404 {object} map[string]string "Pod not found"
+// @Failure 409 {object} map[string]string "The pod has an owner"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/pods/{namespace}/{name}/deployment [get]
+func (h *PodsHandler) ConvertPodToDeployment(c *gin.Context) {
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for pod conversion")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	pod, err := client.CoreV1().Pods(namespace).Get(c.Request.Context(), name, metav1.GetOptions{})
+	if err != nil {
+		h.logger.WithError(err).WithField("pod", name).WithField("namespace", namespace).Error("Failed to get pod for conversion")
+		utils.RespondError(c, http.StatusNotFound, err)
+		return
+	}
+	if owner := metav1.GetControllerOf(pod); owner != nil {
+		utils.RespondErrorMessage(c, http.StatusConflict, fmt.Sprintf("pod %s is managed by %s %s", name, owner.Kind, owner.Name))
+		return
+	}
+
+	conversion, err := deploymentFromPod(pod, c.Query("deployment"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	c.JSON(http.StatusOK, conversion)
+}
@@ -0,0 +1,129 @@
+package workloads
+
+import (
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDeploymentFromReplicationController(t *testing.T) {
+	replicas := int32(3)
+	rc := &v1.ReplicationController{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "frontend",
+			Namespace:   "shop",
+			Labels:      map[string]string{"app": "frontend"},
+			Annotations: map[string]string{lastAppliedAnnotation: "{}", "team": "web"},
+		},
+		Spec: v1.ReplicationControllerSpec{
+			Replicas: &replicas,
+			Template: &v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "frontend", "tier": "web"}},
+				Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "nginx", Image: "nginx:1.25"}}},
+			},
+		},
+	}
+
+	conversion, err := deploymentFromReplicationController(rc, "frontend-v2")
+	if err != nil {
+		t.Fatalf("deploymentFromReplicationController() error = %v", err)
+	}
+	deployment := conversion.Deployment
+	if deployment.Name != "frontend-v2" || *deployment.Spec.Replicas != 3 {
+		t.Errorf("deployment = %s with %d replicas", deployment.Name, *deployment.Spec.Replicas)
+	}
+	// Without a selector the controller selects its template labels
+	if len(deployment.Spec.Selector.MatchLabels) != 2 || deployment.Spec.Selector.MatchLabels["tier"] != "web" {
+		t.Errorf("selector = %v, want the template labels", deployment.Spec.Selector.MatchLabels)
+	}
+	if _, ok := deployment.Annotations[lastAppliedAnnotation]; ok || deployment.Annotations["team"] != "web" {
+		t.Errorf("annotations = %v", deployment.Annotations)
+	}
+	if !strings.Contains(conversion.YAML, "kind: Deployment") || strings.Contains(conversion.YAML, "creationTimestamp") || strings.Contains(conversion.YAML, "status") {
+		t.Errorf("YAML =\n%s", conversion.YAML)
+	}
+	if len(conversion.Steps) != 4 {
+		t.Errorf("steps = %v", conversion.Steps)
+	}
+
+	rc.Spec.Selector = map[string]string{"app": "backend"}
+	if _, err := deploymentFromReplicationController(rc, ""); err == nil {
+		t.Error("deploymentFromReplicationController() succeeded with a selector that does not match the template")
+	}
+}
+
+func TestDeploymentFromPod(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-x7k2p", Namespace: "shop", Labels: map[string]string{"run": "worker", "pod-template-hash": "abc"}},
+		Spec: v1.PodSpec{
+			NodeName:      "node-1",
+			RestartPolicy: v1.RestartPolicyOnFailure,
+			Volumes: []v1.Volume{
+				{Name: "kube-api-access-abcde", VolumeSource: v1.VolumeSource{Projected: &v1.ProjectedVolumeSource{}}},
+				{Name: "scratch", VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}},
+			},
+			Containers: []v1.Container{{
+				Name:         "worker",
+				Image:        "worker:1",
+				VolumeMounts: []v1.VolumeMount{{Name: "kube-api-access-abcde", MountPath: "/var/run/secrets"}, {Name: "scratch", MountPath: "/tmp"}},
+			}},
+		},
+	}
+
+	conversion, err := deploymentFromPod(pod, "")
+	if err != nil {
+		t.Fatalf("deploymentFromPod() error = %v", err)
+	}
+	deployment := conversion.Deployment
+	spec := deployment.Spec.Template.Spec
+	if deployment.Name != "worker" || spec.NodeName != "" || spec.RestartPolicy != v1.RestartPolicyAlways {
+		t.Errorf("deployment %s: node %q, restart policy %s", deployment.Name, spec.NodeName, spec.RestartPolicy)
+	}
+	if len(spec.Volumes) != 1 || len(spec.Containers[0].VolumeMounts) != 1 || spec.Volumes[0].Name != "scratch" {
+		t.Errorf("volumes = %v, mounts = %v, want only scratch", spec.Volumes, spec.Containers[0].VolumeMounts)
+	}
+	if selector := deployment.Spec.Selector.MatchLabels; len(selector) != 1 || selector["run"] != "worker" {
+		t.Errorf("selector = %v, want run=worker without generated labels", selector)
+	}
+	if len(conversion.Warnings) != 3 {
+		t.Errorf("warnings = %v, want node binding, restart policy and emptyDir", conversion.Warnings)
+	}
+	if len(pod.Spec.Volumes) != 2 || pod.Spec.NodeName != "node-1" {
+		t.Error("deploymentFromPod() modified the pod")
+	}
+}
+
+func TestAdoptionHints(t *testing.T) {
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:        "web-1",
+		Namespace:   "shop",
+		Labels:      map[string]string{"app": "web", "helm.sh/chart": "web-1.2.0"},
+		Annotations: map[string]string{createdByAnnotation: `{"kind":"SerializedReference","reference":{"kind":"ReplicationController","namespace":"shop","name":"web-old"}}`},
+	}}
+	controllers := []v1.ReplicationController{
+		{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"}, Spec: v1.ReplicationControllerSpec{Selector: map[string]string{"app": "web"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "other"}, Spec: v1.ReplicationControllerSpec{Selector: map[string]string{"app": "web"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"}, Spec: v1.ReplicationControllerSpec{Selector: map[string]string{"app": "api"}}},
+	}
+	replicaSets := []appsv1.ReplicaSet{
+		{ObjectMeta: metav1.ObjectMeta{Name: "everything", Namespace: "shop"}, Spec: appsv1.ReplicaSetSpec{Selector: &metav1.LabelSelector{}}},
+	}
+
+	hints := adoptionHints(pod, controllers, replicaSets)
+	want := []string{"ReplicationController/web", "ReplicationController/web-old", "Tool/Helm"}
+	if len(hints) != len(want) {
+		t.Fatalf("adoptionHints() = %+v, want %v", hints, want)
+	}
+	for i, hint := range hints {
+		if got := hint.Kind + "/" + hint.Name; got != want[i] {
+			t.Errorf("hint %d = %s, want %s", i, got, want[i])
+		}
+	}
+
+	if isUnowned(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{mirrorPodAnnotation: "abc"}}}) {
+		t.Error("isUnowned() reported a mirror pod")
+	}
+}
@@ -0,0 +1,421 @@
+package workloads
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	zoneLabel       = "topology.kubernetes.io/zone"
+	legacyZoneLabel = "failure-domain.beta.kubernetes.io/zone"
+)
+
+// DomainCount is the number of workload pods in one topology domain
+type DomainCount struct {
+	Domain string   `json:"domain"`
+	Pods   int      `json:"pods"`
+	Names  []string `json:"names,omitempty"`
+}
+
+// SpreadEvaluation compares the actual spread against one spread constraint or anti-affinity term
+type SpreadEvaluation struct {
+	Source      string        `json:"source"` // topologySpreadConstraint or podAntiAffinity
+	TopologyKey string        `json:"topologyKey"`
+	MaxSkew     int32         `json:"maxSkew,omitempty"`
+	Required    bool          `json:"required"`
+	Domains     []DomainCount `json:"domains"`
+	Skew        int           `json:"skew"`
+	Violated    bool          `json:"violated"`
+	Message     string        `json:"message,omitempty"`
+}
+
+// DistributionFinding is an availability concern in a workload's pod placement
+type DistributionFinding struct {
+	Severity string `json:"severity"` // critical or warning
+	Type     string `json:"type"`
+	Message  string `json:"message"`
+}
+
+// PodDistribution reports how a workload's pods are spread over nodes and zones
+type PodDistribution struct {
+	Kind          string                `json:"kind"`
+	Name          string                `json:"name"`
+	Namespace     string                `json:"namespace"`
+	TotalPods     int                   `json:"totalPods"`
+	ScheduledPods int                   `json:"scheduledPods"`
+	PendingPods   int                   `json:"pendingPods"`
+	ClusterZones  int                   `json:"clusterZones"`
+	Nodes         []DomainCount         `json:"nodes"`
+	Zones         []DomainCount         `json:"zones"`
+	Evaluations   []SpreadEvaluation    `json:"evaluations"`
+	Findings      []DistributionFinding `json:"findings"`
+	NodesSkipped  string                `json:"nodesSkipped,omitempty"`
+}
+
+func nodeZone(node *v1.Node) string {
+	if zone := node.Labels[zoneLabel]; zone != "" {
+		return zone
+	}
+	return node.Labels[legacyZoneLabel]
+}
+
+// domainCounts groups pods by the value of a node label; nodes carrying the key
+// but running no pods are reported as empty domains so skew reflects them
+func domainCounts(pods []v1.Pod, nodes map[string]*v1.Node, key string, eligible func(*v1.Node) bool) []DomainCount {
+	counts := map[string]*DomainCount{}
+	for _, node := range nodes {
+		if eligible != nil && !eligible(node) {
+			continue
+		}
+		if value, ok := node.Labels[key]; ok && counts[value] == nil {
+			counts[value] = &DomainCount{Domain: value}
+		}
+	}
+	for _, pod := range pods {
+		node := nodes[pod.Spec.NodeName]
+		if node == nil {
+			continue
+		}
+		value, ok := node.Labels[key]
+		if !ok {
+			continue
+		}
+		if counts[value] == nil {
+			counts[value] = &DomainCount{Domain: value}
+		}
+		counts[value].Pods++
+		counts[value].Names = append(counts[value].Names, pod.Name)
+	}
+	result := make([]DomainCount, 0, len(counts))
+	for _, count := range counts {
+		sort.Strings(count.Names)
+		result = append(result, *count)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Pods != result[j].Pods {
+			return result[i].Pods > result[j].Pods
+		}
+		return result[i].Domain < result[j].Domain
+	})
+	return result
+}
+
+func podsMatching(pods []v1.Pod, selector *metav1.LabelSelector) []v1.Pod {
+	if selector == nil {
+		return pods
+	}
+	sel, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return pods
+	}
+	var matched []v1.Pod
+	for _, pod := range pods {
+		if sel.Matches(labels.Set(pod.Labels)) {
+			matched = append(matched, pod)
+		}
+	}
+	return matched
+}
+
+// skewOf returns the difference between the most and least populated domains;
+// fewer domains than minDomains counts the global minimum as zero
+func skewOf(domains []DomainCount, minDomains int32) int {
+	if len(domains) == 0 {
+		return 0
+	}
+	max, min := domains[0].Pods, domains[0].Pods
+	for _, d := range domains {
+		if d.Pods > max {
+			max = d.Pods
+		}
+		if d.Pods < min {
+			min = d.Pods
+		}
+	}
+	if minDomains > 0 && int32(len(domains)) < minDomains {
+		min = 0
+	}
+	return max - min
+}
+
+// buildPodDistribution evaluates workload pods against the template's spread constraints and anti-affinity
+func buildPodDistribution(template *v1.PodTemplateSpec, pods []v1.Pod, nodes map[string]*v1.Node) PodDistribution {
+	dist := PodDistribution{TotalPods: len(pods)}
+	var scheduled []v1.Pod
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" {
+			dist.PendingPods++
+			continue
+		}
+		scheduled = append(scheduled, pod)
+	}
+	dist.ScheduledPods = len(scheduled)
+
+	nodeCounts := map[string]*DomainCount{}
+	for _, pod := range scheduled {
+		if nodeCounts[pod.Spec.NodeName] == nil {
+			nodeCounts[pod.Spec.NodeName] = &DomainCount{Domain: pod.Spec.NodeName}
+		}
+		nodeCounts[pod.Spec.NodeName].Pods++
+		nodeCounts[pod.Spec.NodeName].Names = append(nodeCounts[pod.Spec.NodeName].Names, pod.Name)
+	}
+	for _, count := range nodeCounts {
+		sort.Strings(count.Names)
+		dist.Nodes = append(dist.Nodes, *count)
+	}
+	sort.Slice(dist.Nodes, func(i, j int) bool {
+		if dist.Nodes[i].Pods != dist.Nodes[j].Pods {
+			return dist.Nodes[i].Pods > dist.Nodes[j].Pods
+		}
+		return dist.Nodes[i].Domain < dist.Nodes[j].Domain
+	})
+
+	zones := map[string]bool{}
+	for _, node := range nodes {
+		if zone := nodeZone(node); zone != "" {
+			zones[zone] = true
+		}
+	}
+	dist.ClusterZones = len(zones)
+	zoneKey := zoneLabel
+	for _, node := range nodes {
+		if node.Labels[zoneLabel] == "" && node.Labels[legacyZoneLabel] != "" {
+			zoneKey = legacyZoneLabel
+			break
+		}
+	}
+	dist.Zones = domainCounts(scheduled, nodes, zoneKey, nil)
+
+	for _, constraint := range template.Spec.TopologySpreadConstraints {
+		var minDomains int32
+		if constraint.MinDomains != nil {
+			minDomains = *constraint.MinDomains
+		}
+		selector := template.Spec.NodeSelector
+		eligible := func(node *v1.Node) bool {
+			return labels.SelectorFromSet(selector).Matches(labels.Set(node.Labels))
+		}
+		domains := domainCounts(podsMatching(scheduled, constraint.LabelSelector), nodes, constraint.TopologyKey, eligible)
+		eval := SpreadEvaluation{
+			Source:      "topologySpreadConstraint",
+			TopologyKey: constraint.TopologyKey,
+			MaxSkew:     constraint.MaxSkew,
+			Required:    constraint.WhenUnsatisfiable == v1.DoNotSchedule,
+			Domains:     domains,
+			Skew:        skewOf(domains, minDomains),
+		}
+		if eval.Skew > int(constraint.MaxSkew) {
+			eval.Violated = true
+			eval.Message = fmt.Sprintf("skew %d exceeds maxSkew %d across %s", eval.Skew, constraint.MaxSkew, constraint.TopologyKey)
+		}
+		dist.Evaluations = append(dist.Evaluations, eval)
+	}
+
+	if affinity := template.Spec.Affinity; affinity != nil && affinity.PodAntiAffinity != nil {
+		evaluate := func(term v1.PodAffinityTerm, required bool) {
+			domains := domainCounts(podsMatching(scheduled, term.LabelSelector), nodes, term.TopologyKey, nil)
+			eval := SpreadEvaluation{Source: "podAntiAffinity", TopologyKey: term.TopologyKey, Required: required, Domains: domains, Skew: skewOf(domains, 0)}
+			var crowded []string
+			for _, d := range domains {
+				if d.Pods > 1 {
+					crowded = append(crowded, d.Domain)
+				}
+			}
+			if len(crowded) > 0 {
+				eval.Violated = true
+				eval.Message = fmt.Sprintf("multiple matching pods share %s %s", term.TopologyKey, strings.Join(crowded, ", "))
+			}
+			dist.Evaluations = append(dist.Evaluations, eval)
+		}
+		for _, term := range affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+			evaluate(term, true)
+		}
+		for _, weighted := range affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			evaluate(weighted.PodAffinityTerm, false)
+		}
+	}
+
+	for _, eval := range dist.Evaluations {
+		if !eval.Violated {
+			continue
+		}
+		severity := "warning"
+		if eval.Required {
+			severity = "critical"
+		}
+		dist.Findings = append(dist.Findings, DistributionFinding{Severity: severity, Type: eval.Source + "-violation", Message: eval.Message})
+	}
+	if dist.ScheduledPods > 1 && len(dist.Nodes) == 1 {
+		dist.Findings = append(dist.Findings, DistributionFinding{
+			Severity: "critical",
+			Type:     "single-node",
+			Message:  fmt.Sprintf("all %d scheduled pods run on node %s", dist.ScheduledPods, dist.Nodes[0].Domain),
+		})
+	}
+	if dist.ScheduledPods > 1 && dist.ClusterZones > 1 {
+		populated := 0
+		for _, zone := range dist.Zones {
+			if zone.Pods > 0 {
+				populated++
+			}
+		}
+		if populated == 1 {
+			dist.Findings = append(dist.Findings, DistributionFinding{
+				Severity: "warning",
+				Type:     "single-zone",
+				Message:  fmt.Sprintf("all %d scheduled pods run in zone %s although the cluster spans %d zones", dist.ScheduledPods, dist.Zones[0].Domain, dist.ClusterZones),
+			})
+		}
+	}
+	if dist.PendingPods > 0 {
+		dist.Findings = append(dist.Findings, DistributionFinding{
+			Severity: "warning",
+			Type:     "pending",
+			Message:  fmt.Sprintf("%d pods are not scheduled", dist.PendingPods),
+		})
+	}
+	return dist
+}
+
+// getPodDistribution loads a workload, its pods and the cluster nodes and reports their spread
+func (h *ResourceReferencesHandler) getPodDistribution(c *gin.Context, kind string) {
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for pod distribution")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	name := c.Param("name")
+	namespace := c.Param("namespace")
+	ctx := c.Request.Context()
+
+	var template *v1.PodTemplateSpec
+	var selector *metav1.LabelSelector
+	switch kind {
+	case "Deployment":
+		obj, getErr := client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err = getErr; err == nil {
+			template, selector = &obj.Spec.Template, obj.Spec.Selector
+		}
+	case "StatefulSet":
+		obj, getErr := client.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err = getErr; err == nil {
+			template, selector = &obj.Spec.Template, obj.Spec.Selector
+		}
+	case "ReplicaSet":
+		obj, getErr := client.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err = getErr; err == nil {
+			template, selector = &obj.Spec.Template, obj.Spec.Selector
+		}
+	}
+	if err != nil {
+		h.logger.WithError(err).WithField("name", name).WithField("namespace", namespace).Errorf("Failed to get %s", strings.ToLower(kind))
+		utils.RespondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	podList, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: metav1.FormatLabelSelector(selector)})
+	if err != nil {
+		h.logger.WithError(err).WithField("name", name).WithField("namespace", namespace).Error("Failed to list pods for distribution")
+		utils.RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	var pods []v1.Pod
+	for _, pod := range podList.Items {
+		if pod.DeletionTimestamp == nil && pod.Status.Phase != v1.PodSucceeded && pod.Status.Phase != v1.PodFailed {
+			pods = append(pods, pod)
+		}
+	}
+
+	// Node labels are needed for zones; without node access only per-node counts are reported
+	nodes := map[string]*v1.Node{}
+	var nodesSkipped string
+	nodeList, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	switch {
+	case err == nil:
+		for i := range nodeList.Items {
+			nodes[nodeList.Items[i].Name] = &nodeList.Items[i]
+		}
+	case apierrors.IsForbidden(err):
+		nodesSkipped = "nodes: forbidden"
+	default:
+		h.logger.WithError(err).Error("Failed to list nodes for pod distribution")
+		utils.RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	dist := buildPodDistribution(template, pods, nodes)
+	dist.Kind, dist.Name, dist.Namespace, dist.NodesSkipped = kind, name, namespace, nodesSkipped
+	c.JSON(http.StatusOK, dist)
+}
+
+// GetDeploymentPodDistribution reports how a deployment's pods are spread
+// @Summary Get deployment pod distribution
+// @Description Reports how a deployment's pods are spread across nodes and zones, evaluates topologySpreadConstraints and podAntiAffinity terms and flags violations and single-node or single-zone concentrations
+// @Tags Workloads
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name for multi-cluster setups"
+// @Param namespace path string true "Kubernetes namespace"
+// @Param name path string true "Deployment name"
+// @Success 200 {object} PodDistribution
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Deployment not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/deployments/{namespace}/{name}/distribution [get]
+func (h *ResourceReferencesHandler) GetDeploymentPodDistribution(c *gin.Context) {
+	h.getPodDistribution(c, "Deployment")
+}
+
+// GetStatefulSetPodDistribution reports how a statefulset's pods are spread
+// @Summary Get statefulset pod distribution
+// @Description Reports how a statefulset's pods are spread across nodes and zones, evaluates topologySpreadConstraints and podAntiAffinity terms and flags violations and single-node or single-zone concentrations
+// @Tags Workloads
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name for multi-cluster setups"
+// @Param namespace path string true "Kubernetes namespace"
+// @Param name path string true "StatefulSet name"
+// @Success 200 {object} PodDistribution
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "StatefulSet not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/statefulsets/{namespace}/{name}/distribution [get]
+func (h *ResourceReferencesHandler) GetStatefulSetPodDistribution(c *gin.Context) {
+	h.getPodDistribution(c, "StatefulSet")
+}
+
+// GetReplicaSetPodDistribution reports how a replicaset's pods are spread
+// @Summary Get replicaset pod distribution
+// @Description Reports how a replicaset's pods are spread across nodes and zones, evaluates topologySpreadConstraints and podAntiAffinity terms and flags violations and single-node or single-zone concentrations
+// @Tags Workloads
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name for multi-cluster setups"
+// @Param namespace path string true "Kubernetes namespace"
+// @Param name path string true "ReplicaSet name"
+// @Success 200 {object} PodDistribution
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "ReplicaSet not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/replicasets/{namespace}/{name}/distribution [get]
+func (h *ResourceReferencesHandler) GetReplicaSetPodDistribution(c *gin.Context) {
+	h.getPodDistribution(c, "ReplicaSet")
+}
@@ -0,0 +1,73 @@
+package workloads
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBuildPodDistribution(t *testing.T) {
+	node := func(name, zone string) *v1.Node {
+		return &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{
+			zoneLabel: zone, "kubernetes.io/hostname": name,
+		}}}
+	}
+	nodes := map[string]*v1.Node{
+		"a1": node("a1", "zone-a"),
+		"a2": node("a2", "zone-a"),
+		"b1": node("b1", "zone-b"),
+	}
+	pod := func(name, nodeName string) v1.Pod {
+		return v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"app": "web"}}, Spec: v1.PodSpec{NodeName: nodeName}}
+	}
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+	template := &v1.PodTemplateSpec{Spec: v1.PodSpec{
+		TopologySpreadConstraints: []v1.TopologySpreadConstraint{
+			{MaxSkew: 1, TopologyKey: zoneLabel, WhenUnsatisfiable: v1.DoNotSchedule, LabelSelector: selector},
+		},
+		Affinity: &v1.Affinity{PodAntiAffinity: &v1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []v1.WeightedPodAffinityTerm{
+				{Weight: 100, PodAffinityTerm: v1.PodAffinityTerm{TopologyKey: "kubernetes.io/hostname", LabelSelector: selector}},
+			},
+		}},
+	}}
+
+	dist := buildPodDistribution(template, []v1.Pod{pod("web-1", "a1"), pod("web-2", "a1"), pod("web-3", "a2"), pod("web-4", "")}, nodes)
+	if dist.TotalPods != 4 || dist.ScheduledPods != 3 || dist.PendingPods != 1 || dist.ClusterZones != 2 {
+		t.Fatalf("unexpected counts %+v", dist)
+	}
+	if dist.Nodes[0].Domain != "a1" || dist.Nodes[0].Pods != 2 {
+		t.Errorf("unexpected node counts %+v", dist.Nodes)
+	}
+	if len(dist.Zones) != 2 || dist.Zones[0].Pods != 3 || dist.Zones[1].Domain != "zone-b" || dist.Zones[1].Pods != 0 {
+		t.Errorf("expected the empty zone to be reported, got %+v", dist.Zones)
+	}
+	spread, anti := dist.Evaluations[0], dist.Evaluations[1]
+	if !spread.Violated || spread.Skew != 3 || !spread.Required {
+		t.Errorf("expected zone spread violation, got %+v", spread)
+	}
+	if !anti.Violated || anti.Required {
+		t.Errorf("expected soft anti-affinity violation, got %+v", anti)
+	}
+	types := map[string]string{}
+	for _, f := range dist.Findings {
+		types[f.Type] = f.Severity
+	}
+	want := map[string]string{
+		"topologySpreadConstraint-violation": "critical",
+		"podAntiAffinity-violation":          "warning",
+		"single-zone":                        "warning",
+		"pending":                            "warning",
+	}
+	for typ, severity := range want {
+		if types[typ] != severity {
+			t.Errorf("expected %s finding with severity %s, got %v", typ, severity, types)
+		}
+	}
+
+	balanced := buildPodDistribution(template, []v1.Pod{pod("web-1", "a1"), pod("web-2", "b1")}, nodes)
+	if len(balanced.Findings) != 0 {
+		t.Errorf("expected no findings for a balanced spread, got %+v", balanced.Findings)
+	}
+}
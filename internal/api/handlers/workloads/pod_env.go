@@ -0,0 +1,709 @@
+package workloads
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Sources of a resolved environment variable
+const (
+	EnvSourceLiteral   = "literal"
+	EnvSourceConfigMap = "configmap"
+	EnvSourceSecret    = "secret"
+	EnvSourceField     = "field"
+	EnvSourceResource  = "resource"
+	EnvSourceService   = "service"
+)
+
+// envVarName matches the names the kubelet accepts for variables imported with envFrom
+var envVarName = regexp.MustCompile(`^[-._a-zA-Z][-._a-zA-Z0-9]*$`)
+
+// EnvSource is where a variable's value comes from
+type EnvSource struct {
+	Kind     string `json:"kind"`           // literal, configmap, secret, field, resource or service
+	Name     string `json:"name,omitempty"` // ConfigMap, Secret, Service or container name
+	Key      string `json:"key,omitempty"`  // key, field path or resource
+	Prefix   string `json:"prefix,omitempty"`
+	Optional bool   `json:"optional,omitempty"`
+}
+
+// ResolvedEnvVar is a variable as the container process receives it
+type ResolvedEnvVar struct {
+	Name    string      `json:"name"`
+	Value   string      `json:"value"`
+	Masked  bool        `json:"masked,omitempty"` // the value comes from a Secret and was not revealed
+	Source  EnvSource   `json:"source"`
+	Error   string      `json:"error,omitempty"`
+	Shadows []EnvSource `json:"shadows,omitempty"` // earlier definitions of the name that this one replaces
+}
+
+// DownwardAPIFile is a file projected into the container by a downwardAPI volume
+type DownwardAPIFile struct {
+	Path   string    `json:"path"` // full path inside the container
+	Value  string    `json:"value"`
+	Source EnvSource `json:"source"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// ContainerEnvironment is the resolved environment of a container
+type ContainerEnvironment struct {
+	Namespace string            `json:"namespace"`
+	Pod       string            `json:"pod"`
+	Container string            `json:"container"`
+	Init      bool              `json:"init,omitempty"`
+	Revealed  bool              `json:"revealed"`
+	Variables []ResolvedEnvVar  `json:"variables"`
+	Files     []DownwardAPIFile `json:"files,omitempty"`
+	Warnings  []string          `json:"warnings,omitempty"`
+}
+
+// envResolver resolves a container's environment the way the kubelet builds it, caching the
+// objects it reads
+type envResolver struct {
+	ctx    context.Context
+	client kubernetes.Interface
+	pod    *v1.Pod
+	reveal bool
+
+	configMaps map[string]*v1.ConfigMap
+	secrets    map[string]*v1.Secret
+	errors     map[string]error
+	node       *v1.Node
+	nodeErr    error
+	nodeRead   bool
+}
+
+func newEnvResolver(ctx context.Context, client kubernetes.Interface, pod *v1.Pod, reveal bool) *envResolver {
+	return &envResolver{
+		ctx:        ctx,
+		client:     client,
+		pod:        pod,
+		reveal:     reveal,
+		configMaps: map[string]*v1.ConfigMap{},
+		secrets:    map[string]*v1.Secret{},
+		errors:     map[string]error{},
+	}
+}
+
+func (r *envResolver) configMap(name string) (*v1.ConfigMap, error) {
+	key := "configmap/" + name
+	if cm, ok := r.configMaps[name]; ok {
+		return cm, nil
+	}
+	if err, ok := r.errors[key]; ok {
+		return nil, err
+	}
+	cm, err := r.client.CoreV1().ConfigMaps(r.pod.Namespace).Get(r.ctx, name, metav1.GetOptions{})
+	if err != nil {
+		r.errors[key] = err
+		return nil, err
+	}
+	r.configMaps[name] = cm
+	return cm, nil
+}
+
+func (r *envResolver) secret(name string) (*v1.Secret, error) {
+	key := "secret/" + name
+	if secret, ok := r.secrets[name]; ok {
+		return secret, nil
+	}
+	if err, ok := r.errors[key]; ok {
+		return nil, err
+	}
+	secret, err := r.client.CoreV1().Secrets(r.pod.Namespace).Get(r.ctx, name, metav1.GetOptions{})
+	if err != nil {
+		r.errors[key] = err
+		return nil, err
+	}
+	r.secrets[name] = secret
+	return secret, nil
+}
+
+func (r *envResolver) nodeAllocatable() (v1.ResourceList, error) {
+	if !r.nodeRead {
+		r.nodeRead = true
+		if r.pod.Spec.NodeName == "" {
+			r.nodeErr = fmt.Errorf("pod is not scheduled")
+		} else {
+			r.node, r.nodeErr = r.client.CoreV1().Nodes().Get(r.ctx, r.pod.Spec.NodeName, metav1.GetOptions{})
+		}
+	}
+	if r.nodeErr != nil {
+		return nil, r.nodeErr
+	}
+	return r.node.Status.Allocatable, nil
+}
+
+// resolve builds the environment of a container: service link variables first, then envFrom
+// sources in order, then env entries, each replacing earlier definitions of the same name.
+// env values may reference earlier variables with $(NAME).
+func (r *envResolver) resolve(container *v1.Container) ([]ResolvedEnvVar, []string) {
+	var order []string
+	vars := map[string]*ResolvedEnvVar{}
+	var warnings []string
+	set := func(v ResolvedEnvVar) {
+		if existing, ok := vars[v.Name]; ok {
+			v.Shadows = append(append([]EnvSource{}, existing.Shadows...), existing.Source)
+		} else {
+			order = append(order, v.Name)
+		}
+		vars[v.Name] = &v
+	}
+
+	services, err := r.serviceLinks()
+	if err != nil {
+		warnings = append(warnings, "Service link variables are not shown: "+err.Error())
+	}
+	for _, v := range services {
+		set(v)
+	}
+
+	for _, from := range container.EnvFrom {
+		for _, v := range r.resolveEnvFrom(from, &warnings) {
+			set(v)
+		}
+	}
+
+	for _, env := range container.Env {
+		v, ok := r.resolveEnv(container, env, vars)
+		if !ok {
+			continue
+		}
+		if v.Error != "" {
+			warnings = append(warnings, fmt.Sprintf("%s: %s", v.Name, v.Error))
+		}
+		set(v)
+	}
+
+	result := make([]ResolvedEnvVar, 0, len(order))
+	for _, name := range order {
+		result = append(result, *vars[name])
+	}
+	return result, warnings
+}
+
+// resolveEnvFrom imports the keys of a ConfigMap or Secret. Keys that are not valid variable
+// names are skipped by the kubelet.
+func (r *envResolver) resolveEnvFrom(from v1.EnvFromSource, warnings *[]string) []ResolvedEnvVar {
+	var data map[string]string
+	var source EnvSource
+	var err error
+	switch {
+	case from.ConfigMapRef != nil:
+		source = EnvSource{Kind: EnvSourceConfigMap, Name: from.ConfigMapRef.Name, Prefix: from.Prefix, Optional: isOptional(from.ConfigMapRef.Optional)}
+		var cm *v1.ConfigMap
+		if cm, err = r.configMap(source.Name); err == nil {
+			data = cm.Data
+		}
+	case from.SecretRef != nil:
+		source = EnvSource{Kind: EnvSourceSecret, Name: from.SecretRef.Name, Prefix: from.Prefix, Optional: isOptional(from.SecretRef.Optional)}
+		var secret *v1.Secret
+		if secret, err = r.secret(source.Name); err == nil {
+			data = make(map[string]string, len(secret.Data))
+			for key, value := range secret.Data {
+				data[key] = string(value)
+			}
+		}
+	default:
+		return nil
+	}
+	if err != nil {
+		if !(apierrors.IsNotFound(err) && source.Optional) {
+			*warnings = append(*warnings, fmt.Sprintf("envFrom %s %s: %s", source.Kind, source.Name, describeRefError(err, source.Optional)))
+		}
+		return nil
+	}
+
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	vars := make([]ResolvedEnvVar, 0, len(keys))
+	var invalid []string
+	for _, key := range keys {
+		name := from.Prefix + key
+		if !envVarName.MatchString(name) {
+			invalid = append(invalid, key)
+			continue
+		}
+		keySource := source
+		keySource.Key = key
+		vars = append(vars, r.secretAware(ResolvedEnvVar{Name: name, Value: data[key], Source: keySource}))
+	}
+	if len(invalid) > 0 {
+		*warnings = append(*warnings, fmt.Sprintf("envFrom %s %s: keys skipped as invalid variable names: %s", source.Kind, source.Name, strings.Join(invalid, ", ")))
+	}
+	return vars
+}
+
+// resolveEnv resolves one env entry. ok is false when the entry sets nothing, like an optional
+// reference to a missing key.
+func (r *envResolver) resolveEnv(container *v1.Container, env v1.EnvVar, defined map[string]*ResolvedEnvVar) (ResolvedEnvVar, bool) {
+	v := ResolvedEnvVar{Name: env.Name, Source: EnvSource{Kind: EnvSourceLiteral}}
+	if env.ValueFrom == nil {
+		masked := false
+		v.Value = expandEnv(env.Value, func(name string) (string, bool) {
+			ref, ok := defined[name]
+			if !ok || ref.Error != "" {
+				return "", false
+			}
+			masked = masked || ref.Masked || ref.Source.Kind == EnvSourceSecret
+			return ref.Value, true
+		})
+		if masked && !r.reveal {
+			v.Value, v.Masked = "", true
+		}
+		return v, true
+	}
+
+	from := env.ValueFrom
+	switch {
+	case from.ConfigMapKeyRef != nil:
+		ref := from.ConfigMapKeyRef
+		v.Source = EnvSource{Kind: EnvSourceConfigMap, Name: ref.Name, Key: ref.Key, Optional: isOptional(ref.Optional)}
+		cm, err := r.configMap(ref.Name)
+		if err != nil {
+			if apierrors.IsNotFound(err) && v.Source.Optional {
+				return v, false
+			}
+			v.Error = describeRefError(err, v.Source.Optional)
+			return v, true
+		}
+		value, ok := cm.Data[ref.Key]
+		if !ok {
+			if v.Source.Optional {
+				return v, false
+			}
+			v.Error = fmt.Sprintf("key %q not found; the container cannot start", ref.Key)
+			return v, true
+		}
+		v.Value = value
+	case from.SecretKeyRef != nil:
+		ref := from.SecretKeyRef
+		v.Source = EnvSource{Kind: EnvSourceSecret, Name: ref.Name, Key: ref.Key, Optional: isOptional(ref.Optional)}
+		secret, err := r.secret(ref.Name)
+		if err != nil {
+			if apierrors.IsNotFound(err) && v.Source.Optional {
+				return v, false
+			}
+			v.Error = describeRefError(err, v.Source.Optional)
+			return v, true
+		}
+		value, ok := secret.Data[ref.Key]
+		if !ok {
+			if v.Source.Optional {
+				return v, false
+			}
+			v.Error = fmt.Sprintf("key %q not found; the container cannot start", ref.Key)
+			return v, true
+		}
+		v.Value = string(value)
+	case from.FieldRef != nil:
+		v.Source = EnvSource{Kind: EnvSourceField, Key: from.FieldRef.FieldPath}
+		value, err := podFieldValue(r.pod, from.FieldRef.FieldPath)
+		if err != nil {
+			v.Error = err.Error()
+			return v, true
+		}
+		v.Value = value
+	case from.ResourceFieldRef != nil:
+		value, source, err := r.resourceFieldValue(container, from.ResourceFieldRef)
+		v.Source = source
+		if err != nil {
+			v.Error = err.Error()
+			return v, true
+		}
+		v.Value = value
+	}
+	return r.secretAware(v), true
+}
+
+// secretAware masks a value read from a Secret unless values are revealed
+func (r *envResolver) secretAware(v ResolvedEnvVar) ResolvedEnvVar {
+	if v.Source.Kind == EnvSourceSecret && !r.reveal {
+		v.Value, v.Masked = "", true
+	}
+	return v
+}
+
+func isOptional(optional *bool) bool {
+	return optional != nil && *optional
+}
+
+func describeRefError(err error, optional bool) string {
+	if apierrors.IsNotFound(err) && !optional {
+		return "not found; the container cannot start"
+	}
+	if apierrors.IsForbidden(err) {
+		return "not readable with the current credentials"
+	}
+	return err.Error()
+}
+
+// expandEnv expands $(NAME) references like the kubelet: $$ escapes a dollar sign and
+// references to undefined variables are left as they are
+func expandEnv(input string, lookup func(string) (string, bool)) string {
+	var out strings.Builder
+	for i := 0; i < len(input); i++ {
+		if input[i] != '$' || i+1 >= len(input) {
+			out.WriteByte(input[i])
+			continue
+		}
+		switch input[i+1] {
+		case '$':
+			out.WriteByte('$')
+			i++
+		case '(':
+			end := strings.IndexByte(input[i+2:], ')')
+			if end < 0 {
+				out.WriteString(input[i:])
+				return out.String()
+			}
+			name := input[i+2 : i+2+end]
+			if value, ok := lookup(name); ok {
+				out.WriteString(value)
+			} else {
+				out.WriteString("$(" + name + ")")
+			}
+			i += end + 2
+		default:
+			out.WriteByte('$')
+		}
+	}
+	return out.String()
+}
+
+// podFieldValue returns the value of a downward API field path
+func podFieldValue(pod *v1.Pod, fieldPath string) (string, error) {
+	if key, ok := subscriptKey(fieldPath, "metadata.labels"); ok {
+		return pod.Labels[key], nil
+	}
+	if key, ok := subscriptKey(fieldPath, "metadata.annotations"); ok {
+		return pod.Annotations[key], nil
+	}
+	switch fieldPath {
+	case "metadata.name":
+		return pod.Name, nil
+	case "metadata.namespace":
+		return pod.Namespace, nil
+	case "metadata.uid":
+		return string(pod.UID), nil
+	case "metadata.labels":
+		return formatDownwardMap(pod.Labels), nil
+	case "metadata.annotations":
+		return formatDownwardMap(pod.Annotations), nil
+	case "spec.nodeName":
+		return pod.Spec.NodeName, nil
+	case "spec.serviceAccountName":
+		return pod.Spec.ServiceAccountName, nil
+	case "status.hostIP":
+		return pod.Status.HostIP, nil
+	case "status.hostIPs":
+		ips := make([]string, 0, len(pod.Status.HostIPs))
+		for _, ip := range pod.Status.HostIPs {
+			ips = append(ips, ip.IP)
+		}
+		return strings.Join(ips, ","), nil
+	case "status.podIP":
+		return pod.Status.PodIP, nil
+	case "status.podIPs":
+		ips := make([]string, 0, len(pod.Status.PodIPs))
+		for _, ip := range pod.Status.PodIPs {
+			ips = append(ips, ip.IP)
+		}
+		return strings.Join(ips, ","), nil
+	}
+	return "", fmt.Errorf("unsupported field path %q", fieldPath)
+}
+
+// subscriptKey extracts key from a field path like metadata.labels['key']
+func subscriptKey(fieldPath, prefix string) (string, bool) {
+	if !strings.HasPrefix(fieldPath, prefix+"['") || !strings.HasSuffix(fieldPath, "']") {
+		return "", false
+	}
+	return fieldPath[len(prefix)+2 : len(fieldPath)-2], true
+}
+
+// formatDownwardMap renders labels or annotations the way downwardAPI volumes do
+func formatDownwardMap(m map[string]string) string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		lines = append(lines, fmt.Sprintf("%s=%q", key, m[key]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// resourceFieldValue returns a container's request or limit in units of the divisor, rounded
+// up. Unset limits resolve to the node's allocatable capacity, as the kubelet does.
+func (r *envResolver) resourceFieldValue(container *v1.Container, ref *v1.ResourceFieldSelector) (string, EnvSource, error) {
+	target := container
+	if ref.ContainerName != "" && ref.ContainerName != container.Name {
+		target = findContainer(r.pod, ref.ContainerName)
+		if target == nil {
+			return "", EnvSource{Kind: EnvSourceResource, Name: ref.ContainerName, Key: ref.Resource}, fmt.Errorf("container %q not found", ref.ContainerName)
+		}
+	}
+	source := EnvSource{Kind: EnvSourceResource, Name: target.Name, Key: ref.Resource}
+
+	kind, name, ok := strings.Cut(ref.Resource, ".")
+	if !ok || (kind != "limits" && kind != "requests") {
+		return "", source, fmt.Errorf("unsupported resource %q", ref.Resource)
+	}
+	resourceName := v1.ResourceName(name)
+	list := target.Resources.Requests
+	if kind == "limits" {
+		list = target.Resources.Limits
+	}
+	quantity, ok := list[resourceName]
+	if !ok && kind == "limits" {
+		allocatable, err := r.nodeAllocatable()
+		if err != nil {
+			return "", source, fmt.Errorf("limit not set, so the node's allocatable %s applies, which could not be read: %v", name, err)
+		}
+		quantity, ok = allocatable[resourceName]
+	}
+	if !ok {
+		return "0", source, nil
+	}
+
+	divisor := ref.Divisor
+	if divisor.IsZero() {
+		divisor = resource.MustParse("1")
+	}
+	var value float64
+	if resourceName == v1.ResourceCPU {
+		value = math.Ceil(float64(quantity.MilliValue()) / float64(divisor.MilliValue()))
+	} else {
+		value = math.Ceil(float64(quantity.Value()) / float64(divisor.Value()))
+	}
+	return strconv.FormatInt(int64(value), 10), source, nil
+}
+
+func findContainer(pod *v1.Pod, name string) *v1.Container {
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == name {
+			return &pod.Spec.Containers[i]
+		}
+	}
+	for i := range pod.Spec.InitContainers {
+		if pod.Spec.InitContainers[i].Name == name {
+			return &pod.Spec.InitContainers[i]
+		}
+	}
+	return nil
+}
+
+// serviceLinks returns the Docker-link style variables the kubelet adds for the services of the
+// pod's namespace when service links are enabled, and always for the kubernetes service
+func (r *envResolver) serviceLinks() ([]ResolvedEnvVar, error) {
+	var services []v1.Service
+	if r.pod.Spec.EnableServiceLinks == nil || *r.pod.Spec.EnableServiceLinks {
+		list, err := r.client.CoreV1().Services(r.pod.Namespace).List(r.ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		services = list.Items
+	}
+	if r.pod.Namespace != metav1.NamespaceDefault || len(services) == 0 {
+		if master, err := r.client.CoreV1().Services(metav1.NamespaceDefault).Get(r.ctx, "kubernetes", metav1.GetOptions{}); err == nil {
+			services = append(services, *master)
+		}
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+
+	var vars []ResolvedEnvVar
+	seen := map[string]bool{}
+	for _, service := range services {
+		if seen[service.Name] {
+			continue
+		}
+		seen[service.Name] = true
+		for _, link := range serviceLinkVars(&service) {
+			link.Source = EnvSource{Kind: EnvSourceService, Name: service.Name}
+			vars = append(vars, link)
+		}
+	}
+	return vars, nil
+}
+
+// serviceLinkVars returns the variables of one service, following the kubelet's envvars package
+func serviceLinkVars(service *v1.Service) []ResolvedEnvVar {
+	ip := service.Spec.ClusterIP
+	if ip == "" || ip == v1.ClusterIPNone || len(service.Spec.Ports) == 0 {
+		return nil
+	}
+	prefix := strings.ToUpper(strings.ReplaceAll(service.Name, "-", "_"))
+	vars := []ResolvedEnvVar{
+		{Name: prefix + "_SERVICE_HOST", Value: ip},
+		{Name: prefix + "_SERVICE_PORT", Value: strconv.Itoa(int(service.Spec.Ports[0].Port))},
+	}
+	for _, port := range service.Spec.Ports {
+		if port.Name != "" {
+			name := prefix + "_SERVICE_PORT_" + strings.ToUpper(strings.ReplaceAll(port.Name, "-", "_"))
+			vars = append(vars, ResolvedEnvVar{Name: name, Value: strconv.Itoa(int(port.Port))})
+		}
+	}
+	for i, port := range service.Spec.Ports {
+		protocol := strings.ToLower(string(port.Protocol))
+		if protocol == "" {
+			protocol = "tcp"
+		}
+		url := fmt.Sprintf("%s://%s", protocol, net.JoinHostPort(ip, strconv.Itoa(int(port.Port))))
+		if i == 0 {
+			vars = append(vars, ResolvedEnvVar{Name: prefix + "_PORT", Value: url})
+		}
+		portPrefix := fmt.Sprintf("%s_PORT_%d_%s", prefix, port.Port, strings.ToUpper(protocol))
+		vars = append(vars,
+			ResolvedEnvVar{Name: portPrefix, Value: url},
+			ResolvedEnvVar{Name: portPrefix + "_PROTO", Value: protocol},
+			ResolvedEnvVar{Name: portPrefix + "_PORT", Value: strconv.Itoa(int(port.Port))},
+			ResolvedEnvVar{Name: portPrefix + "_ADDR", Value: ip},
+		)
+	}
+	return vars
+}
+
+// downwardAPIFiles resolves the files of downwardAPI volumes and projections the container mounts
+func (r *envResolver) downwardAPIFiles(container *v1.Container) []DownwardAPIFile {
+	mounts := map[string]v1.VolumeMount{}
+	for _, mount := range container.VolumeMounts {
+		mounts[mount.Name] = mount
+	}
+	var files []DownwardAPIFile
+	addItems := func(mount v1.VolumeMount, items []v1.DownwardAPIVolumeFile) {
+		for _, item := range items {
+			file := DownwardAPIFile{Path: path.Join(mount.MountPath, item.Path)}
+			if mount.SubPath != "" {
+				// Only the file at the sub path is visible, at the mount path itself
+				if item.Path != mount.SubPath {
+					continue
+				}
+				file.Path = mount.MountPath
+			}
+			var err error
+			switch {
+			case item.FieldRef != nil:
+				file.Source = EnvSource{Kind: EnvSourceField, Key: item.FieldRef.FieldPath}
+				file.Value, err = podFieldValue(r.pod, item.FieldRef.FieldPath)
+			case item.ResourceFieldRef != nil:
+				file.Value, file.Source, err = r.resourceFieldValue(container, item.ResourceFieldRef)
+			}
+			if err != nil {
+				file.Error = err.Error()
+			}
+			files = append(files, file)
+		}
+	}
+	for _, volume := range r.pod.Spec.Volumes {
+		mount, ok := mounts[volume.Name]
+		if !ok {
+			continue
+		}
+		if volume.DownwardAPI != nil {
+			addItems(mount, volume.DownwardAPI.Items)
+		}
+		if volume.Projected != nil {
+			for _, source := range volume.Projected.Sources {
+				if source.DownwardAPI != nil {
+					addItems(mount, source.DownwardAPI.Items)
+				}
+			}
+		}
+	}
+	return files
+}
+
+// GetPodContainerEnv returns the resolved environment of a pod's container
+// @Summary Get resolved container environment
+// @Description Resolves a container's environment as the kubelet builds it, without exec'ing into the pod: service link variables, envFrom ConfigMaps and Secrets, and env entries with ConfigMap, Secret, downward API field and resource references resolved and $(VAR) references expanded. Later definitions replace earlier ones and list what they shadow. Values from Secrets are masked unless reveal is set. Files of mounted downwardAPI volumes are included. Variables set by the container image are not visible to the API and are not listed.
+// @Tags Workloads
+// @Produce json
+// @Param namespace path string true "Namespace name"
+// @Param name path string true "Pod name"
+// @Param container query string false "Container name (defaults to the first container)"
+// @Param reveal query bool false "Return Secret values instead of masking them"
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Success 200 {object} ContainerEnvironment "Resolved environment"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Pod or container not found"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/pods/{namespace}/{name}/env [get]
+func (h *PodsHandler) GetPodContainerEnv(c *gin.Context) {
+	ctx, span := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer span.End()
+
+	client, err := h.getClientAndConfigWithContext(c, ctx)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for container environment")
+		h.tracingHelper.RecordError(span, err, "Failed to get Kubernetes client")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	h.tracingHelper.RecordSuccess(span, "Successfully obtained Kubernetes client")
+
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	pod, err := client.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		h.logger.WithError(err).WithField("pod", name).WithField("namespace", namespace).Error("Failed to get pod for container environment")
+		utils.RespondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	containerName := c.Query("container")
+	if containerName == "" && len(pod.Spec.Containers) > 0 {
+		containerName = pod.Spec.Containers[0].Name
+	}
+	container := findContainer(pod, containerName)
+	if container == nil {
+		utils.RespondErrorMessage(c, http.StatusNotFound, fmt.Sprintf("container %q not found in pod %s", containerName, name))
+		return
+	}
+
+	isInit := true
+	for _, regular := range pod.Spec.Containers {
+		if regular.Name == container.Name {
+			isInit = false
+		}
+	}
+
+	reveal := c.Query("reveal") == "true"
+	resolver := newEnvResolver(ctx, client, pod, reveal)
+	variables, warnings := resolver.resolve(container)
+	env := ContainerEnvironment{
+		Namespace: namespace,
+		Pod:       name,
+		Container: container.Name,
+		Init:      isInit,
+		Revealed:  reveal,
+		Variables: variables,
+		Files:     resolver.downwardAPIFiles(container),
+		Warnings:  warnings,
+	}
+	c.JSON(http.StatusOK, env)
+}
@@ -0,0 +1,100 @@
+package workloads
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestResolveContainerEnv(t *testing.T) {
+	optional := true
+	disabled := false
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-1", Namespace: "shop", Labels: map[string]string{"app": "api"}},
+		Spec: v1.PodSpec{
+			NodeName:           "node-a",
+			EnableServiceLinks: &disabled,
+			Containers: []v1.Container{{
+				Name: "app",
+				EnvFrom: []v1.EnvFromSource{
+					{ConfigMapRef: &v1.ConfigMapEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "settings"}}},
+					{Prefix: "DB_", SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "db"}}},
+				},
+				Env: []v1.EnvVar{
+					{Name: "LOG_LEVEL", Value: "debug"},
+					{Name: "DSN", Value: "postgres://$(DB_USER)@db/$(MISSING) costs $$5"},
+					{Name: "POD_NAME", ValueFrom: &v1.EnvVarSource{FieldRef: &v1.ObjectFieldSelector{FieldPath: "metadata.name"}}},
+					{Name: "APP", ValueFrom: &v1.EnvVarSource{FieldRef: &v1.ObjectFieldSelector{FieldPath: "metadata.labels['app']"}}},
+					{Name: "MEMORY_MB", ValueFrom: &v1.EnvVarSource{ResourceFieldRef: &v1.ResourceFieldSelector{Resource: "limits.memory", Divisor: resource.MustParse("1Mi")}}},
+					{Name: "CPU", ValueFrom: &v1.EnvVarSource{ResourceFieldRef: &v1.ResourceFieldSelector{Resource: "requests.cpu"}}},
+					{Name: "OPTIONAL", ValueFrom: &v1.EnvVarSource{ConfigMapKeyRef: &v1.ConfigMapKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "absent"}, Key: "x", Optional: &optional}}},
+					{Name: "REQUIRED", ValueFrom: &v1.EnvVarSource{SecretKeyRef: &v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "db"}, Key: "missing"}}},
+				},
+				Resources:    v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("250m")}},
+				VolumeMounts: []v1.VolumeMount{{Name: "podinfo", MountPath: "/etc/podinfo"}},
+			}},
+			Volumes: []v1.Volume{{Name: "podinfo", VolumeSource: v1.VolumeSource{DownwardAPI: &v1.DownwardAPIVolumeSource{
+				Items: []v1.DownwardAPIVolumeFile{{Path: "labels", FieldRef: &v1.ObjectFieldSelector{FieldPath: "metadata.labels"}}},
+			}}}},
+		},
+	}
+	client := fake.NewSimpleClientset(
+		&v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "shop"}, Data: map[string]string{"LOG_LEVEL": "info", "bad key": "x"}},
+		&v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "shop"}, Data: map[string][]byte{"USER": []byte("admin")}},
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}, Status: v1.NodeStatus{Allocatable: v1.ResourceList{v1.ResourceMemory: resource.MustParse("2Gi")}}},
+		&v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "kubernetes", Namespace: "default"}, Spec: v1.ServiceSpec{ClusterIP: "10.0.0.1", Ports: []v1.ServicePort{{Name: "https", Port: 443, Protocol: v1.ProtocolTCP}}}},
+	)
+
+	vars, warnings := newEnvResolver(context.Background(), client, pod, false).resolve(&pod.Spec.Containers[0])
+	byName := map[string]ResolvedEnvVar{}
+	for _, v := range vars {
+		byName[v.Name] = v
+	}
+
+	if v := byName["KUBERNETES_SERVICE_HOST"]; v.Value != "10.0.0.1" || v.Source.Kind != EnvSourceService {
+		t.Errorf("KUBERNETES_SERVICE_HOST = %+v", v)
+	}
+	if byName["KUBERNETES_PORT_443_TCP"].Value != "tcp://10.0.0.1:443" {
+		t.Errorf("KUBERNETES_PORT_443_TCP = %+v", byName["KUBERNETES_PORT_443_TCP"])
+	}
+	if v := byName["LOG_LEVEL"]; v.Value != "debug" || len(v.Shadows) != 1 || v.Shadows[0].Kind != EnvSourceConfigMap {
+		t.Errorf("LOG_LEVEL = %+v, want the literal shadowing the ConfigMap key", v)
+	}
+	if v := byName["DB_USER"]; !v.Masked || v.Value != "" {
+		t.Errorf("DB_USER = %+v, want masked", v)
+	}
+	if v := byName["DSN"]; !v.Masked {
+		t.Errorf("DSN = %+v, want masked because it expands a Secret value", v)
+	}
+	if byName["POD_NAME"].Value != "api-1" || byName["APP"].Value != "api" {
+		t.Errorf("field refs = %+v, %+v", byName["POD_NAME"], byName["APP"])
+	}
+	if byName["MEMORY_MB"].Value != "2048" || byName["CPU"].Value != "1" {
+		t.Errorf("resource refs = %+v, %+v", byName["MEMORY_MB"], byName["CPU"])
+	}
+	if _, ok := byName["OPTIONAL"]; ok {
+		t.Error("OPTIONAL is set, want it skipped because its ConfigMap is missing")
+	}
+	if byName["REQUIRED"].Error == "" {
+		t.Errorf("REQUIRED = %+v, want an error", byName["REQUIRED"])
+	}
+	if _, ok := byName["bad key"]; ok || len(warnings) != 2 {
+		t.Errorf("warnings = %v, want the skipped key and the missing Secret key", warnings)
+	}
+
+	revealed, _ := newEnvResolver(context.Background(), client, pod, true).resolve(&pod.Spec.Containers[0])
+	for _, v := range revealed {
+		if v.Name == "DSN" && v.Value != "postgres://admin@db/$(MISSING) costs $5" {
+			t.Errorf("revealed DSN = %q", v.Value)
+		}
+	}
+
+	files := newEnvResolver(context.Background(), client, pod, false).downwardAPIFiles(&pod.Spec.Containers[0])
+	if len(files) != 1 || files[0].Path != "/etc/podinfo/labels" || files[0].Value != `app="api"` {
+		t.Errorf("downwardAPIFiles() = %+v", files)
+	}
+}
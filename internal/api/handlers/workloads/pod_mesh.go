@@ -0,0 +1,36 @@
+package workloads
+
+import (
+	"context"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/transformers"
+	"github.com/Facets-cloud/kube-dash/internal/api/types"
+
+	appsV1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// hasMeshSidecars reports whether any pod runs a mesh data plane
+func hasMeshSidecars(pods []types.PodListResponse) bool {
+	for i := range pods {
+		if pods[i].Mesh != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// MeshControlPlanes returns the proxy version of each Istio revision and Linkerd control plane
+// in the cluster, keyed as transformers.ApplyMeshVersionSkew expects. Control planes that cannot
+// be listed are left out.
+func MeshControlPlanes(ctx context.Context, client kubernetes.Interface) map[string]string {
+	var deployments []appsV1.Deployment
+	for _, selector := range []string{transformers.IstiodSelector, transformers.LinkerdDestinationSelector} {
+		list, err := client.AppsV1().Deployments("").List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err == nil {
+			deployments = append(deployments, list.Items...)
+		}
+	}
+	return transformers.MeshControlPlaneVersions(deployments)
+}
@@ -0,0 +1,46 @@
+package workloads
+
+import (
+	"net/http"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/transformers"
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetPodSandbox returns the sandbox and network details of a pod
+// @Summary Get pod sandbox details
+// @Description Returns the node, network identity and container runtime IDs of a pod as a typed struct: pod IPs (both families on dual-stack clusters), host IPs, the nominated node of a pod waiting on preemption, host networking, DNS policy and runtime class.
+// @Tags Workloads
+// @Produce json
+// @Param namespace path string true "Namespace name"
+// @Param name path string true "Pod name"
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Success 200 {object} types.PodSandboxDetails "Pod sandbox details"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Pod not found"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/pods/{namespace}/{name}/sandbox [get]
+func (h *PodsHandler) GetPodSandbox(c *gin.Context) {
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for pod sandbox")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	pod, err := client.CoreV1().Pods(namespace).Get(c.Request.Context(), name, metav1.GetOptions{})
+	if err != nil {
+		h.logger.WithError(err).WithField("pod", name).WithField("namespace", namespace).Error("Failed to get pod for sandbox details")
+		utils.RespondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transformers.PodSandbox(pod))
+}
@@ -0,0 +1,46 @@
+package workloads
+
+import (
+	"net/http"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/transformers"
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetPodSecurity returns the effective security context of a pod's containers
+// @Summary Get pod security context
+// @Description Flattens the security settings of a pod for its security tab: for every init, regular and ephemeral container the effective security context with pod-level settings merged in (user and group, root and privilege escalation flags, capabilities added and dropped, seccomp, AppArmor and SELinux profiles, filesystem flags), which level each setting came from, and the risks it carries. Pod-level host namespaces, hostPath volumes, sysctls and service account token mounting are flagged too.
+// @Tags Workloads
+// @Produce json
+// @Param namespace path string true "Namespace name"
+// @Param name path string true "Pod name"
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Success 200 {object} types.PodSecurityDetails "Pod security details"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Pod not found"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/pods/{namespace}/{name}/security [get]
+func (h *PodsHandler) GetPodSecurity(c *gin.Context) {
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for pod security")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	pod, err := client.CoreV1().Pods(namespace).Get(c.Request.Context(), name, metav1.GetOptions{})
+	if err != nil {
+		h.logger.WithError(err).WithField("pod", name).WithField("namespace", namespace).Error("Failed to get pod for security details")
+		utils.RespondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transformers.PodSecurity(pod))
+}
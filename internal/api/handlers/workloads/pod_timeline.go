@@ -0,0 +1,305 @@
+package workloads
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Timeline entry types
+const (
+	TimelineLifecycle = "lifecycle"
+	TimelineCondition = "condition"
+	TimelineContainer = "container"
+	TimelineEvent     = "event"
+)
+
+// maxTimelineLogLines bounds the log snippet attached to each restart
+const maxTimelineLogLines = 200
+
+// PodTimelineEntry is one point in a pod's history
+type PodTimelineEntry struct {
+	Time       time.Time `json:"time"`
+	Type       string    `json:"type"` // lifecycle, condition, container or event
+	Severity   string    `json:"severity"`
+	Reason     string    `json:"reason"`
+	Message    string    `json:"message,omitempty"`
+	Container  string    `json:"container,omitempty"`
+	ExitCode   *int32    `json:"exitCode,omitempty"`
+	Count      int32     `json:"count,omitempty"`
+	Current    bool      `json:"current,omitempty"` // describes the present state rather than a past transition
+	LogSnippet []string  `json:"logSnippet,omitempty"`
+}
+
+// PodTimeline is the chronological history of a pod
+type PodTimeline struct {
+	Namespace string             `json:"namespace"`
+	Name      string             `json:"name"`
+	Phase     string             `json:"phase"`
+	Node      string             `json:"node,omitempty"`
+	Restarts  int32              `json:"restarts"`
+	Entries   []PodTimelineEntry `json:"entries"`
+	Warnings  []string           `json:"warnings,omitempty"`
+}
+
+// buildPodTimeline assembles timeline entries from a pod's status and events. now is used
+// for current waiting states, which carry no timestamp.
+func buildPodTimeline(pod *v1.Pod, events []v1.Event, now time.Time) PodTimeline {
+	timeline := PodTimeline{
+		Namespace: pod.Namespace,
+		Name:      pod.Name,
+		Phase:     string(pod.Status.Phase),
+		Node:      pod.Spec.NodeName,
+		Entries:   []PodTimelineEntry{},
+	}
+	add := func(entry PodTimelineEntry) {
+		if entry.Time.IsZero() {
+			return
+		}
+		if entry.Severity == "" {
+			entry.Severity = "info"
+		}
+		timeline.Entries = append(timeline.Entries, entry)
+	}
+
+	add(PodTimelineEntry{Time: pod.CreationTimestamp.Time, Type: TimelineLifecycle, Reason: "Created"})
+	if pod.Status.StartTime != nil {
+		add(PodTimelineEntry{Time: pod.Status.StartTime.Time, Type: TimelineLifecycle, Reason: "Started", Message: "Pod accepted by the kubelet on " + pod.Spec.NodeName})
+	}
+	if pod.DeletionTimestamp != nil {
+		add(PodTimelineEntry{Time: pod.DeletionTimestamp.Time, Type: TimelineLifecycle, Severity: "warning", Reason: "Terminating", Message: "Pod deletion requested"})
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		severity := "info"
+		if condition.Status != v1.ConditionTrue {
+			severity = "warning"
+		}
+		message := condition.Message
+		if condition.Reason != "" {
+			message = strings.TrimSpace(condition.Reason + " " + message)
+		}
+		add(PodTimelineEntry{
+			Time:     condition.LastTransitionTime.Time,
+			Type:     TimelineCondition,
+			Severity: severity,
+			Reason:   fmt.Sprintf("%s=%s", condition.Type, condition.Status),
+			Message:  message,
+		})
+	}
+
+	statuses := append(append(append([]v1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...), pod.Status.EphemeralContainerStatuses...)
+	for _, status := range statuses {
+		timeline.Restarts += status.RestartCount
+		if last := status.LastTerminationState.Terminated; last != nil {
+			add(PodTimelineEntry{Time: last.StartedAt.Time, Type: TimelineContainer, Container: status.Name, Reason: "ContainerStarted"})
+			add(terminatedEntry(status.Name, last, false))
+		}
+		switch {
+		case status.State.Running != nil:
+			add(PodTimelineEntry{Time: status.State.Running.StartedAt.Time, Type: TimelineContainer, Container: status.Name, Reason: "ContainerStarted", Current: true,
+				Message: fmt.Sprintf("Running (restart count %d)", status.RestartCount)})
+		case status.State.Terminated != nil:
+			add(PodTimelineEntry{Time: status.State.Terminated.StartedAt.Time, Type: TimelineContainer, Container: status.Name, Reason: "ContainerStarted"})
+			add(terminatedEntry(status.Name, status.State.Terminated, true))
+		case status.State.Waiting != nil:
+			severity := "info"
+			if status.State.Waiting.Reason != "ContainerCreating" && status.State.Waiting.Reason != "PodInitializing" {
+				severity = "error"
+			}
+			add(PodTimelineEntry{Time: now, Type: TimelineContainer, Container: status.Name, Severity: severity, Current: true,
+				Reason: status.State.Waiting.Reason, Message: status.State.Waiting.Message})
+		}
+	}
+
+	for i := range events {
+		event := &events[i]
+		at := event.LastTimestamp.Time
+		if at.IsZero() {
+			at = event.EventTime.Time
+		}
+		if at.IsZero() {
+			at = event.FirstTimestamp.Time
+		}
+		severity := "info"
+		if event.Type == v1.EventTypeWarning {
+			severity = "warning"
+		}
+		entry := PodTimelineEntry{Time: at, Type: TimelineEvent, Severity: severity, Reason: event.Reason, Message: event.Message, Count: event.Count}
+		// Events about one container carry a field path like spec.containers{app}
+		if path := event.InvolvedObject.FieldPath; strings.Contains(path, "{") && strings.HasSuffix(path, "}") {
+			entry.Container = path[strings.Index(path, "{")+1 : len(path)-1]
+		}
+		add(entry)
+	}
+
+	sort.SliceStable(timeline.Entries, func(i, j int) bool { return timeline.Entries[i].Time.Before(timeline.Entries[j].Time) })
+	return timeline
+}
+
+func terminatedEntry(container string, state *v1.ContainerStateTerminated, current bool) PodTimelineEntry {
+	severity := "info"
+	if state.ExitCode != 0 {
+		severity = "error"
+	}
+	reason := state.Reason
+	if reason == "" {
+		reason = "Terminated"
+	}
+	exitCode := state.ExitCode
+	message := state.Message
+	if state.Signal != 0 {
+		message = strings.TrimSpace(fmt.Sprintf("signal %d %s", state.Signal, message))
+	}
+	return PodTimelineEntry{
+		Time:      state.FinishedAt.Time,
+		Type:      TimelineContainer,
+		Severity:  severity,
+		Reason:    reason,
+		Message:   message,
+		Container: container,
+		ExitCode:  &exitCode,
+		Current:   current,
+	}
+}
+
+// attachRestartLogs adds the tail of each restarted container's previous logs to the entry
+// for its last termination. The kubelet keeps only the previous instance's logs.
+func attachRestartLogs(ctx context.Context, client kubernetes.Interface, pod *v1.Pod, timeline *PodTimeline, lines int64) {
+	for i := range timeline.Entries {
+		entry := &timeline.Entries[i]
+		if entry.Type != TimelineContainer || entry.ExitCode == nil || entry.Current {
+			continue
+		}
+		// Only the most recent previous termination of each container has logs available
+		if !isLastTermination(pod, entry) {
+			continue
+		}
+		req := client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &v1.PodLogOptions{
+			Container:  entry.Container,
+			Previous:   true,
+			TailLines:  &lines,
+			Timestamps: false,
+		})
+		stream, err := req.Stream(ctx)
+		if err != nil {
+			timeline.Warnings = append(timeline.Warnings, fmt.Sprintf("logs for %s unavailable: %v", entry.Container, err))
+			continue
+		}
+		data, err := io.ReadAll(io.LimitReader(stream, 256*1024))
+		stream.Close()
+		if err != nil {
+			timeline.Warnings = append(timeline.Warnings, fmt.Sprintf("failed to read logs for %s: %v", entry.Container, err))
+			continue
+		}
+		if text := strings.TrimRight(string(data), "\n"); text != "" {
+			entry.LogSnippet = strings.Split(text, "\n")
+		}
+	}
+}
+
+func isLastTermination(pod *v1.Pod, entry *PodTimelineEntry) bool {
+	statuses := append(append(append([]v1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...), pod.Status.EphemeralContainerStatuses...)
+	for _, status := range statuses {
+		if status.Name == entry.Container && status.LastTerminationState.Terminated != nil {
+			return status.LastTerminationState.Terminated.FinishedAt.Time.Equal(entry.Time)
+		}
+	}
+	return false
+}
+
+// GetPodTimeline returns a chronological timeline of what happened to a pod
+// @Summary Get Pod lifecycle timeline
+// @Description Assembles a chronological timeline of a pod from its lifecycle, condition transitions, container starts and terminations, and events. With logs=true the tail of the previous container logs is attached to each container's last restart.
+// @Tags Workloads
+// @Produce json
+// @Param namespace path string true "Namespace name"
+// @Param name path string true "Pod name"
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param logs query bool false "Attach previous container logs to restarts"
+// @Param logLines query int false "Log lines per restart (default 20, max 200)"
+// @Success 200 {object} PodTimeline "Pod timeline"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Pod not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/pods/{namespace}/{name}/timeline [get]
+func (h *PodsHandler) GetPodTimeline(c *gin.Context) {
+	ctx, span := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer span.End()
+
+	client, err := h.getClientAndConfigWithContext(c, ctx)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for pod timeline")
+		h.tracingHelper.RecordError(span, err, "Failed to get Kubernetes client")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	h.tracingHelper.RecordSuccess(span, "Successfully obtained Kubernetes client")
+
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	lines := int64(20)
+	if l := c.Query("logLines"); l != "" {
+		parsed, err := strconv.ParseInt(l, 10, 64)
+		if err != nil || parsed <= 0 {
+			utils.RespondErrorMessage(c, http.StatusBadRequest, "logLines must be a positive integer")
+			return
+		}
+		if parsed > maxTimelineLogLines {
+			parsed = maxTimelineLogLines
+		}
+		lines = parsed
+	}
+
+	_, podSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "get", "pod", namespace)
+	defer podSpan.End()
+	pod, err := client.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		h.logger.WithError(err).WithField("pod", name).WithField("namespace", namespace).Error("Failed to get pod for timeline")
+		h.tracingHelper.RecordError(podSpan, err, "Failed to get pod")
+		utils.RespondError(c, http.StatusNotFound, err)
+		return
+	}
+	h.tracingHelper.RecordSuccess(podSpan, "Retrieved pod")
+
+	var warnings []string
+	selector := fields.Set{
+		"involvedObject.kind": "Pod",
+		"involvedObject.name": name,
+		"involvedObject.uid":  string(pod.UID),
+	}.AsSelector().String()
+	events, err := client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{FieldSelector: selector})
+	var eventItems []v1.Event
+	if err != nil {
+		warnings = append(warnings, "events unavailable: "+err.Error())
+	} else {
+		eventItems = events.Items
+	}
+
+	_, buildSpan := h.tracingHelper.StartDataProcessingSpan(ctx, "build-pod-timeline")
+	defer buildSpan.End()
+	timeline := buildPodTimeline(pod, eventItems, time.Now())
+	timeline.Warnings = warnings
+	if c.Query("logs") == "true" {
+		attachRestartLogs(ctx, client, pod, &timeline, lines)
+	}
+	h.tracingHelper.RecordSuccess(buildSpan, fmt.Sprintf("Built timeline with %d entries", len(timeline.Entries)))
+
+	c.JSON(http.StatusOK, timeline)
+}
@@ -0,0 +1,62 @@
+package workloads
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBuildPodTimeline(t *testing.T) {
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	at := func(minutes int) metav1.Time { return metav1.NewTime(base.Add(time.Duration(minutes) * time.Minute)) }
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-1", Namespace: "shop", CreationTimestamp: at(0)},
+		Status: v1.PodStatus{
+			Phase:      v1.PodRunning,
+			StartTime:  &metav1.Time{Time: base.Add(time.Minute)},
+			Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue, LastTransitionTime: at(6)}},
+			ContainerStatuses: []v1.ContainerStatus{{
+				Name:         "app",
+				RestartCount: 1,
+				State:        v1.ContainerState{Running: &v1.ContainerStateRunning{StartedAt: at(5)}},
+				LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{
+					ExitCode: 137, Reason: "OOMKilled", StartedAt: at(2), FinishedAt: at(4),
+				}},
+			}},
+		},
+	}
+	events := []v1.Event{{
+		Reason:         "BackOff",
+		Type:           v1.EventTypeWarning,
+		Count:          3,
+		LastTimestamp:  at(4),
+		InvolvedObject: v1.ObjectReference{FieldPath: "spec.containers{app}"},
+	}}
+
+	timeline := buildPodTimeline(pod, events, base.Add(time.Hour))
+	var reasons []string
+	for i, e := range timeline.Entries {
+		if i > 0 && e.Time.Before(timeline.Entries[i-1].Time) {
+			t.Fatalf("entries not in order: %+v", timeline.Entries)
+		}
+		reasons = append(reasons, e.Reason)
+	}
+	want := []string{"Created", "Started", "ContainerStarted", "OOMKilled", "BackOff", "ContainerStarted", "Ready=True"}
+	if len(reasons) != len(want) {
+		t.Fatalf("got %v, want %v", reasons, want)
+	}
+	for i := range want {
+		if reasons[i] != want[i] {
+			t.Fatalf("got %v, want %v", reasons, want)
+		}
+	}
+	if timeline.Restarts != 1 || timeline.Entries[3].Severity != "error" || timeline.Entries[4].Container != "app" {
+		t.Errorf("unexpected timeline: %+v", timeline)
+	}
+	if !isLastTermination(pod, &timeline.Entries[3]) {
+		t.Errorf("expected the OOMKilled entry to be the last termination")
+	}
+}
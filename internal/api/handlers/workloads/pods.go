@@ -75,12 +75,12 @@ func NewPodsHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFac
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *PodsHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *PodsHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	return h.getClientAndConfigWithContext(c, c.Request.Context())
 }
 
 // getClientAndConfigWithContext gets the Kubernetes client and config with tracing context
-func (h *PodsHandler) getClientAndConfigWithContext(c *gin.Context, ctx context.Context) (*kubernetes.Clientset, error) {
+func (h *PodsHandler) getClientAndConfigWithContext(c *gin.Context, ctx context.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -114,7 +114,7 @@ func (h *PodsHandler) GetPods(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for pods")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -130,7 +130,7 @@ func (h *PodsHandler) GetPods(c *gin.Context) {
 
 	if err2 != nil {
 		h.logger.WithError(err2).Error("Failed to list pods")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err2.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err2)
 		return
 	}
 
@@ -146,6 +146,12 @@ func (h *PodsHandler) GetPods(c *gin.Context) {
 // @Param config query string true "Kubernetes configuration ID"
 // @Param cluster query string false "Cluster name"
 // @Param namespace query string false "Namespace filter"
+// @Param namespaces query string false "Comma-separated namespaces to list across, instead of namespace"
+// @Param namespaceGroup query string false "ID of a saved namespace group to list across"
+// @Param fields query string false "Comma-separated response fields to return, e.g. name,status,restarts; name, namespace and uid are always included"
+// @Param labelSelector query string false "Kubernetes label selector, e.g. app=web,tier!=cache"
+// @Param sort query string false "Response field to sort by, e.g. name or -restarts for descending"
+// @Param view query string false "ID of a saved view supplying namespaces, labelSelector, sort and fields"
 // @Param node query string false "Node name filter"
 // @Param owner query string false "Owner type (deployment, daemonset, etc.)"
 // @Param ownerName query string false "Owner name"
@@ -168,7 +174,12 @@ func (h *PodsHandler) GetPodsSSE(c *gin.Context) {
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Client setup completed for pods SSE")
 
-	namespace := c.Query("namespace")
+	// Owner filters and metrics are scoped to a namespace only when a single one is requested
+	namespaces := utils.RequestedNamespaces(c)
+	namespace := ""
+	if len(namespaces) == 1 {
+		namespace = namespaces[0]
+	}
 	node := c.Query("node")
 	owner := c.Query("owner")
 	ownerName := c.Query("ownerName")
@@ -233,18 +244,26 @@ func (h *PodsHandler) GetPodsSSE(c *gin.Context) {
 		}
 		h.tracingHelper.RecordSuccess(ownerSpan, "Owner filters resolved")
 
+		// A labelSelector parameter narrows the owner's pods further
+		if selector := utils.ListOptions(c).LabelSelector; selector != "" {
+			if listOptions.LabelSelector != "" {
+				selector = listOptions.LabelSelector + "," + selector
+			}
+			listOptions.LabelSelector = selector
+		}
+
 		// Start child span for Kubernetes API call
 		_, k8sSpan := h.tracingHelper.StartKubernetesAPISpan(fetchCtx, "list", "pods", namespace)
 		defer k8sSpan.End()
 
-		var podList *v1.PodList
-		var err2 error
-
-		if namespace != "" {
-			podList, err2 = client.CoreV1().Pods(namespace).List(fetchCtx, listOptions)
-		} else {
-			podList, err2 = client.CoreV1().Pods("").List(fetchCtx, listOptions)
-		}
+		items, err2 := utils.ListInNamespaces(fetchCtx, namespaces, func(ctx context.Context, namespace string) ([]v1.Pod, error) {
+			list, err := client.CoreV1().Pods(namespace).List(ctx, listOptions)
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		podList := &v1.PodList{Items: items}
 
 		if err2 != nil {
 			h.tracingHelper.RecordError(k8sSpan, err2, "Failed to list pods")
@@ -259,12 +278,49 @@ func (h *PodsHandler) GetPodsSSE(c *gin.Context) {
 
 		// Transform pods to the expected format first (fast path)
 		var transformedPods []types.PodListResponse
+		memoryLimits := make(map[string]int64, len(podList.Items))
 		for _, pod := range podList.Items {
 			transformedPods = append(transformedPods, transformers.TransformPodToResponse(&pod, configID, cluster))
+			memoryLimits[pod.Namespace+"/"+pod.Name] = transformers.PodMemoryLimit(&pod)
 		}
 		h.tracingHelper.AddResourceAttributes(transformSpan, "pods", "transform", len(transformedPods))
 		h.tracingHelper.RecordSuccess(transformSpan, fmt.Sprintf("Transformed %d pods", len(transformedPods)))
 
+		// Best-effort node overlay; node labels tell whether a pod runs on reclaimable capacity
+		// and node conditions whether the kubelet may start evicting it
+		nodesCtx, cancelNodes := context.WithTimeout(fetchCtx, 800*time.Millisecond)
+		if nodeList, err := client.CoreV1().Nodes().List(nodesCtx, metav1.ListOptions{}); err == nil {
+			spotNodes := transformers.SpotNodeNames(nodeList.Items)
+			pressuredNodes := transformers.PressuredNodes(nodeList.Items)
+			for i := range transformedPods {
+				transformedPods[i].Spot = spotNodes[transformedPods[i].Node]
+				transformedPods[i].NodePressure = pressuredNodes[transformedPods[i].Node]
+			}
+		}
+		cancelNodes()
+
+		// Best-effort mesh overlay; the control planes tell whether sidecars run an outdated proxy
+		if hasMeshSidecars(transformedPods) {
+			meshCtx, cancelMesh := context.WithTimeout(fetchCtx, 800*time.Millisecond)
+			controlPlanes := MeshControlPlanes(meshCtx, client)
+			for i := range transformedPods {
+				transformers.ApplyMeshVersionSkew(transformedPods[i].Mesh, controlPlanes)
+			}
+			cancelMesh()
+		}
+
+		// Best-effort reboot overlay; restarts of pods on a node that rebooted may be down to the reboot
+		eventsCtx, cancelEvents := context.WithTimeout(fetchCtx, 800*time.Millisecond)
+		if events, err := client.CoreV1().Events("").List(eventsCtx, metav1.ListOptions{FieldSelector: "involvedObject.kind=Node,reason=Rebooted"}); err == nil {
+			reboots := transformers.NodeReboots(events.Items)
+			for i := range transformedPods {
+				if nodeReboots := reboots[transformedPods[i].Node]; len(nodeReboots) > 0 {
+					transformedPods[i].RestartCauses = transformers.PodRestartCauses(&podList.Items[i], nodeReboots)
+				}
+			}
+		}
+		cancelEvents()
+
 		// Best-effort metrics overlay: single List call with short timeout; do not block initial response
 		if mClient != nil {
 			// Start child span for metrics collection
@@ -320,6 +376,7 @@ func (h *PodsHandler) GetPodsSSE(c *gin.Context) {
 					if v, ok := metricsMap[key]; ok {
 						transformedPods[i].CPU = formatCPU(v.cpuMilli)
 						transformedPods[i].Memory = bytesToMiBString(v.memBytes)
+						transformedPods[i].NearMemoryLimit = transformers.NearMemoryLimit(v.memBytes, memoryLimits[key])
 						metricsApplied++
 					}
 				}
@@ -358,7 +415,37 @@ func (h *PodsHandler) GetPodsSSE(c *gin.Context) {
 	}
 
 	// For non-SSE requests, return JSON
-	c.JSON(http.StatusOK, initialData)
+	h.sseHandler.SendJSON(c, initialData)
+}
+
+// podRisk computes the pod's risk flags, overlaying metrics and node conditions on a best-effort basis
+func (h *PodsHandler) podRisk(c *gin.Context, client kubernetes.Interface, pod *v1.Pod) types.PodRisk {
+	risk := types.PodRisk{
+		QOSClass:    string(transformers.PodQOSClass(pod)),
+		MemoryLimit: transformers.PodMemoryLimit(pod),
+	}
+
+	// Use short timeouts so a slow metrics server or node lookup never blocks the detail view
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 800*time.Millisecond)
+	defer cancel()
+
+	if pod.Spec.NodeName != "" {
+		if node, err := client.CoreV1().Nodes().Get(ctx, pod.Spec.NodeName, metav1.GetOptions{}); err == nil {
+			risk.NodePressure = transformers.NodePressure(node)
+		}
+	}
+
+	if mClient, err := h.getMetricsClient(c); err == nil {
+		if pm, err := mClient.MetricsV1beta1().PodMetricses(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{}); err == nil {
+			for _, cont := range pm.Containers {
+				if memQty, ok := cont.Usage[v1.ResourceMemory]; ok {
+					risk.MemoryUsage += memQty.Value()
+				}
+			}
+			risk.NearMemoryLimit = transformers.NearMemoryLimit(risk.MemoryUsage, risk.MemoryLimit)
+		}
+	}
+	return risk
 }
 
 // GetPodByName returns a specific pod by name using namespace from query parameters
@@ -366,7 +453,7 @@ func (h *PodsHandler) GetPodByName(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for pod")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -375,19 +462,19 @@ func (h *PodsHandler) GetPodByName(c *gin.Context) {
 
 	if namespace == "" {
 		h.logger.WithField("pod", name).Error("Namespace is required for pod lookup")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
 	pod, err := client.CoreV1().Pods(namespace).Get(c.Request.Context(), name, metav1.GetOptions{})
 	if err != nil {
 		h.logger.WithError(err).WithField("pod", name).WithField("namespace", namespace).Error("Failed to get pod")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 
 	// Always send SSE format for detail endpoints since they're used by EventSource
-	h.sseHandler.SendSSEResponse(c, pod)
+	h.sseHandler.SendSSEDetailResponse(c, types.PodDetailResponse{Pod: pod, Risk: h.podRisk(c, client, pod), ContainerRuntimeIDs: transformers.ContainerRuntimeIDs(pod)}, pod, client.CoreV1().Pods(namespace).Watch)
 }
 
 // GetPod returns a specific pod
@@ -400,7 +487,7 @@ func (h *PodsHandler) GetPod(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for pod")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
@@ -416,14 +503,14 @@ func (h *PodsHandler) GetPod(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("pod", name).WithField("namespace", namespace).Error("Failed to get pod")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get pod")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(k8sSpan, name, "pod", 1)
 	h.tracingHelper.RecordSuccess(k8sSpan, fmt.Sprintf("Retrieved pod %s", name))
 
 	// Always send SSE format for detail endpoints since they're used by EventSource
-	h.sseHandler.SendSSEResponse(c, pod)
+	h.sseHandler.SendSSEDetailResponse(c, types.PodDetailResponse{Pod: pod, Risk: h.podRisk(c, client, pod), ContainerRuntimeIDs: transformers.ContainerRuntimeIDs(pod)}, pod, client.CoreV1().Pods(namespace).Watch)
 }
 
 // GetPodYAMLByName returns the YAML representation of a specific pod by name
@@ -431,7 +518,7 @@ func (h *PodsHandler) GetPodYAMLByName(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for pod YAML")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -440,14 +527,14 @@ func (h *PodsHandler) GetPodYAMLByName(c *gin.Context) {
 
 	if namespace == "" {
 		h.logger.WithField("pod", name).Error("Namespace is required for pod YAML lookup")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
 	pod, err := client.CoreV1().Pods(namespace).Get(c.Request.Context(), name, metav1.GetOptions{})
 	if err != nil {
 		h.logger.WithError(err).WithField("pod", name).WithField("namespace", namespace).Error("Failed to get pod for YAML")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -459,7 +546,7 @@ func (h *PodsHandler) GetPodYAML(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for pod YAML")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -469,7 +556,7 @@ func (h *PodsHandler) GetPodYAML(c *gin.Context) {
 	pod, err := client.CoreV1().Pods(namespace).Get(c.Request.Context(), name, metav1.GetOptions{})
 	if err != nil {
 		h.logger.WithError(err).WithField("pod", name).WithField("namespace", namespace).Error("Failed to get pod for YAML")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -481,7 +568,7 @@ func (h *PodsHandler) GetPodEventsByName(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for pod events")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -490,7 +577,7 @@ func (h *PodsHandler) GetPodEventsByName(c *gin.Context) {
 
 	if namespace == "" {
 		h.logger.WithField("pod", name).Error("Namespace is required for pod events lookup")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -502,7 +589,7 @@ func (h *PodsHandler) GetPodEvents(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for pod events")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -576,6 +663,7 @@ func (h *PodsHandler) GetPodExecByName(c *gin.Context) {
 type ContainerRestartInfo struct {
 	ContainerName string                  `json:"containerName"`
 	RestartCount  int32                   `json:"restartCount"`
+	LastCause     string                  `json:"lastCause,omitempty"` // why the container last restarted, e.g. OOMKilled or NodeReboot
 	LastState     *ContainerStateInfo     `json:"lastState,omitempty"`
 	CurrentState  *ContainerStateInfo     `json:"currentState"`
 }
@@ -592,7 +680,7 @@ type ContainerStateInfo struct {
 
 // GetPodContainerRestartInfo returns restart/termination information for all containers in a pod
 // @Summary Get Pod Container Restart Information
-// @Description Get detailed restart and termination information for all containers in a pod
+// @Description Get detailed restart and termination information for all containers in a pod, with the cause of each container's last restart (OOMKilled, Error, Completed, NodeReboot or Unknown)
 // @Tags Workloads
 // @Accept json
 // @Produce json
@@ -610,7 +698,7 @@ func (h *PodsHandler) GetPodContainerRestartInfo(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for pod restart info")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -620,10 +708,12 @@ func (h *PodsHandler) GetPodContainerRestartInfo(c *gin.Context) {
 	pod, err := client.CoreV1().Pods(namespace).Get(c.Request.Context(), name, metav1.GetOptions{})
 	if err != nil {
 		h.logger.WithError(err).WithField("pod", name).WithField("namespace", namespace).Error("Failed to get pod for restart info")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 
+	reboots := h.nodeReboots(c, client, pod.Spec.NodeName)
+
 	var restartInfos []ContainerRestartInfo
 
 	// Process all container statuses (regular containers)
@@ -640,6 +730,9 @@ func (h *PodsHandler) GetPodContainerRestartInfo(c *gin.Context) {
 		   containerStatus.LastTerminationState.Running != nil {
 			info.LastState = extractContainerState(containerStatus.LastTerminationState)
 		}
+		if containerStatus.RestartCount > 0 {
+			info.LastCause = transformers.ClassifyTermination(containerStatus.LastTerminationState.Terminated, reboots)
+		}
 
 		restartInfos = append(restartInfos, info)
 	}
@@ -657,6 +750,9 @@ func (h *PodsHandler) GetPodContainerRestartInfo(c *gin.Context) {
 		   containerStatus.LastTerminationState.Running != nil {
 			info.LastState = extractContainerState(containerStatus.LastTerminationState)
 		}
+		if containerStatus.RestartCount > 0 {
+			info.LastCause = transformers.ClassifyTermination(containerStatus.LastTerminationState.Terminated, reboots)
+		}
 
 		restartInfos = append(restartInfos, info)
 	}
@@ -674,6 +770,9 @@ func (h *PodsHandler) GetPodContainerRestartInfo(c *gin.Context) {
 		   containerStatus.LastTerminationState.Running != nil {
 			info.LastState = extractContainerState(containerStatus.LastTerminationState)
 		}
+		if containerStatus.RestartCount > 0 {
+			info.LastCause = transformers.ClassifyTermination(containerStatus.LastTerminationState.Terminated, reboots)
+		}
 
 		restartInfos = append(restartInfos, info)
 	}
@@ -681,6 +780,21 @@ func (h *PodsHandler) GetPodContainerRestartInfo(c *gin.Context) {
 	c.JSON(http.StatusOK, restartInfos)
 }
 
+// nodeReboots returns when the node rebooted according to its events, on a best-effort basis
+func (h *PodsHandler) nodeReboots(c *gin.Context, client kubernetes.Interface, nodeName string) []time.Time {
+	if nodeName == "" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 800*time.Millisecond)
+	defer cancel()
+	selector := fmt.Sprintf("involvedObject.kind=Node,involvedObject.name=%s,reason=Rebooted", nodeName)
+	events, err := client.CoreV1().Events("").List(ctx, metav1.ListOptions{FieldSelector: selector})
+	if err != nil {
+		return nil
+	}
+	return transformers.NodeReboots(events.Items)[nodeName]
+}
+
 // extractContainerState extracts state information from a ContainerState
 func extractContainerState(state v1.ContainerState) *ContainerStateInfo {
 	if state.Running != nil {
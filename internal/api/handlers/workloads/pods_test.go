@@ -0,0 +1,48 @@
+package workloads
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/types"
+	"github.com/Facets-cloud/kube-dash/internal/testharness"
+)
+
+func TestGetPodsSSEAsJSON(t *testing.T) {
+	h := testharness.New(t, "pods.yaml")
+	handler := NewPodsHandler(h.Store, h.Factory, h.Logger)
+
+	var all []types.PodListResponse
+	h.DoJSON(http.MethodGet, "/api/v1/pods", handler.GetPodsSSE, "/api/v1/pods", nil, &all)
+	if len(all) != 2 {
+		t.Fatalf("got %d pods across namespaces, want 2", len(all))
+	}
+
+	var shop []types.PodListResponse
+	h.DoJSON(http.MethodGet, "/api/v1/pods", handler.GetPodsSSE, "/api/v1/pods?namespace=shop", nil, &shop)
+	if len(shop) != 1 || shop[0].Name != "web-7d4b9c6f5-abcde" || shop[0].Node != "node-a" {
+		t.Fatalf("pods in shop = %+v", shop)
+	}
+	if shop[0].Restarts != "1" {
+		t.Errorf("restarts = %q, want 1", shop[0].Restarts)
+	}
+}
+
+func TestGetPodSandbox(t *testing.T) {
+	h := testharness.New(t, "pods.yaml")
+	handler := NewPodsHandler(h.Store, h.Factory, h.Logger)
+	route := "/api/v1/pods/:namespace/:name/sandbox"
+
+	var sandbox types.PodSandboxDetails
+	h.DoJSON(http.MethodGet, route, handler.GetPodSandbox, "/api/v1/pods/shop/web-7d4b9c6f5-abcde/sandbox", nil, &sandbox)
+	if !sandbox.DualStack || len(sandbox.PodIPs) != 2 || sandbox.NodeName != "node-a" {
+		t.Errorf("sandbox = %+v, want a dual-stack pod on node-a", sandbox)
+	}
+	if len(sandbox.Containers) != 1 || sandbox.Containers[0].Runtime != "containerd" {
+		t.Errorf("containers = %+v, want the containerd runtime", sandbox.Containers)
+	}
+
+	if w := h.Do(http.MethodGet, route, handler.GetPodSandbox, "/api/v1/pods/shop/missing/sandbox", nil); w.Code != http.StatusNotFound {
+		t.Errorf("missing pod answered %d, want 404", w.Code)
+	}
+}
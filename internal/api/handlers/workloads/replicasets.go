@@ -1,8 +1,10 @@
 package workloads
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/Facets-cloud/kube-dash/internal/api/transformers"
 	"github.com/Facets-cloud/kube-dash/internal/api/types"
@@ -13,6 +15,7 @@ import (
 	"github.com/Facets-cloud/kube-dash/pkg/logger"
 
 	"github.com/gin-gonic/gin"
+	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -42,7 +45,7 @@ func NewReplicaSetsHandler(store *storage.KubeConfigStore, clientFactory *k8s.Cl
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *ReplicaSetsHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *ReplicaSetsHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -72,6 +75,12 @@ func (h *ReplicaSetsHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Cli
 // @Param config query string true "Kubernetes configuration ID"
 // @Param cluster query string false "Cluster name for multi-cluster setups"
 // @Param namespace query string false "Kubernetes namespace to filter resources"
+// @Param namespaces query string false "Comma-separated namespaces to list across, instead of namespace"
+// @Param namespaceGroup query string false "ID of a saved namespace group to list across"
+// @Param fields query string false "Comma-separated response fields to return, e.g. name,status,restarts; name, namespace and uid are always included"
+// @Param labelSelector query string false "Kubernetes label selector, e.g. app=web,tier!=cache"
+// @Param sort query string false "Response field to sort by, e.g. name or -restarts for descending"
+// @Param view query string false "ID of a saved view supplying namespaces, labelSelector, sort and fields"
 // @Success 200 {array} types.ReplicaSetListResponse "Streaming ReplicaSets data"
 // @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
 // @Failure 403 {object} map[string]string "Forbidden - insufficient permissions"
@@ -93,7 +102,8 @@ func (h *ReplicaSetsHandler) GetReplicaSetsSSE(c *gin.Context) {
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client setup for SSE")
 
-	namespace := c.Query("namespace")
+	namespaces := utils.RequestedNamespaces(c)
+	namespace := strings.Join(namespaces, ",")
 
 	// Function to fetch and transform replicasets data
 	fetchReplicaSets := func() (interface{}, error) {
@@ -101,7 +111,14 @@ func (h *ReplicaSetsHandler) GetReplicaSetsSSE(c *gin.Context) {
 		_, fetchSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "replicasets", namespace)
 		defer fetchSpan.End()
 
-		replicaSetList, err := client.AppsV1().ReplicaSets(namespace).List(c.Request.Context(), metav1.ListOptions{})
+		items, err := utils.ListInNamespaces(c.Request.Context(), namespaces, func(ctx context.Context, namespace string) ([]appsv1.ReplicaSet, error) {
+			list, err := client.AppsV1().ReplicaSets(namespace).List(ctx, utils.ListOptions(c))
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		replicaSetList := &appsv1.ReplicaSetList{Items: items}
 		if err != nil {
 			h.tracingHelper.RecordError(fetchSpan, err, "Failed to list replicasets for SSE")
 			return nil, err
@@ -147,7 +164,7 @@ func (h *ReplicaSetsHandler) GetReplicaSetsSSE(c *gin.Context) {
 	}
 
 	// For non-SSE requests, return JSON
-	c.JSON(http.StatusOK, initialData)
+	h.sseHandler.SendJSON(c, initialData)
 }
 
 // GetReplicaSet returns a specific replicaset
@@ -180,7 +197,7 @@ func (h *ReplicaSetsHandler) GetReplicaSet(c *gin.Context) {
 		if c.GetHeader("Accept") == "text/event-stream" {
 			h.sseHandler.SendSSEError(c, http.StatusBadRequest, err.Error())
 		} else {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			utils.RespondError(c, http.StatusBadRequest, err)
 		}
 		return
 	}
@@ -201,7 +218,7 @@ func (h *ReplicaSetsHandler) GetReplicaSet(c *gin.Context) {
 		if c.GetHeader("Accept") == "text/event-stream" {
 			h.sseHandler.SendSSEError(c, http.StatusNotFound, err.Error())
 		} else {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			utils.RespondError(c, http.StatusNotFound, err)
 		}
 		return
 	}
@@ -211,7 +228,7 @@ func (h *ReplicaSetsHandler) GetReplicaSet(c *gin.Context) {
 	// Check if this is an SSE request (EventSource expects SSE format)
 	acceptHeader := c.GetHeader("Accept")
 	if acceptHeader == "text/event-stream" {
-		h.sseHandler.SendSSEResponse(c, replicaSet)
+		h.sseHandler.SendSSEDetailResponse(c, replicaSet, replicaSet, client.AppsV1().ReplicaSets(namespace).Watch)
 		return
 	}
 
@@ -244,7 +261,7 @@ func (h *ReplicaSetsHandler) GetReplicaSetByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for replicaset by name")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client setup completed")
@@ -253,7 +270,7 @@ func (h *ReplicaSetsHandler) GetReplicaSetByName(c *gin.Context) {
 	namespace := c.Query("namespace")
 
 	if namespace == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -265,7 +282,7 @@ func (h *ReplicaSetsHandler) GetReplicaSetByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("replicaset", name).WithField("namespace", namespace).Error("Failed to get replicaset by name")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get replicaset by name")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(k8sSpan, name, "replicaset", 1)
@@ -300,7 +317,7 @@ func (h *ReplicaSetsHandler) GetReplicaSetYAMLByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for replicaset YAML by name")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client setup completed")
@@ -309,7 +326,7 @@ func (h *ReplicaSetsHandler) GetReplicaSetYAMLByName(c *gin.Context) {
 	namespace := c.Query("namespace")
 
 	if namespace == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -321,7 +338,7 @@ func (h *ReplicaSetsHandler) GetReplicaSetYAMLByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("replicaset", name).WithField("namespace", namespace).Error("Failed to get replicaset for YAML by name")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get replicaset for YAML")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(k8sSpan, name, "replicaset", 1)
@@ -361,7 +378,7 @@ func (h *ReplicaSetsHandler) GetReplicaSetYAML(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for replicaset YAML")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client setup completed")
@@ -377,7 +394,7 @@ func (h *ReplicaSetsHandler) GetReplicaSetYAML(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("replicaset", name).WithField("namespace", namespace).Error("Failed to get replicaset for YAML")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get replicaset for YAML")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(k8sSpan, name, "replicaset", 1)
@@ -412,7 +429,7 @@ func (h *ReplicaSetsHandler) GetReplicaSetEventsByName(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for replicaset events")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -421,7 +438,7 @@ func (h *ReplicaSetsHandler) GetReplicaSetEventsByName(c *gin.Context) {
 
 	if namespace == "" {
 		h.logger.WithField("replicaset", name).Error("Namespace is required for replicaset events lookup")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -448,7 +465,7 @@ func (h *ReplicaSetsHandler) GetReplicaSetEvents(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for replicaset events")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -477,7 +494,7 @@ func (h *ReplicaSetsHandler) GetReplicaSetPods(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for replicaset pods")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -488,7 +505,7 @@ func (h *ReplicaSetsHandler) GetReplicaSetPods(c *gin.Context) {
 	replicaSet, err := client.AppsV1().ReplicaSets(namespace).Get(c.Request.Context(), name, metav1.GetOptions{})
 	if err != nil {
 		h.logger.WithError(err).WithField("replicaset", name).WithField("namespace", namespace).Error("Failed to get replicaset for pods")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -504,7 +521,7 @@ func (h *ReplicaSetsHandler) GetReplicaSetPods(c *gin.Context) {
 	})
 	if err != nil {
 		h.logger.WithError(err).WithField("replicaset", name).WithField("namespace", namespace).Error("Failed to get replicaset pods")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -540,7 +557,7 @@ func (h *ReplicaSetsHandler) GetReplicaSetPodsByName(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for replicaset pods by name")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -548,7 +565,7 @@ func (h *ReplicaSetsHandler) GetReplicaSetPodsByName(c *gin.Context) {
 	namespace := c.Query("namespace")
 
 	if namespace == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -556,7 +573,7 @@ func (h *ReplicaSetsHandler) GetReplicaSetPodsByName(c *gin.Context) {
 	replicaSet, err := client.AppsV1().ReplicaSets(namespace).Get(c.Request.Context(), name, metav1.GetOptions{})
 	if err != nil {
 		h.logger.WithError(err).WithField("replicaset", name).WithField("namespace", namespace).Error("Failed to get replicaset for pods by name")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -572,7 +589,7 @@ func (h *ReplicaSetsHandler) GetReplicaSetPodsByName(c *gin.Context) {
 	})
 	if err != nil {
 		h.logger.WithError(err).WithField("replicaset", name).WithField("namespace", namespace).Error("Failed to get replicaset pods by name")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -0,0 +1,387 @@
+package workloads
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/transformers"
+	"github.com/Facets-cloud/kube-dash/internal/api/types"
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/internal/tracing"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ReplicationControllersHandler handles ReplicationController-related operations
+type ReplicationControllersHandler struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	logger        *logger.Logger
+	eventsHandler *utils.EventsHandler
+	yamlHandler   *utils.YAMLHandler
+	sseHandler    *utils.SSEHandler
+	tracingHelper *tracing.TracingHelper
+}
+
+// NewReplicationControllersHandler creates a new ReplicationControllers handler
+func NewReplicationControllersHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger) *ReplicationControllersHandler {
+	return &ReplicationControllersHandler{
+		store:         store,
+		clientFactory: clientFactory,
+		logger:        log,
+		eventsHandler: utils.NewEventsHandler(log),
+		yamlHandler:   utils.NewYAMLHandler(log),
+		sseHandler:    utils.NewSSEHandler(log),
+		tracingHelper: tracing.GetTracingHelper(),
+	}
+}
+
+// getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
+func (h *ReplicationControllersHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
+	configID := c.Query("config")
+	cluster := c.Query("cluster")
+
+	if configID == "" {
+		return nil, fmt.Errorf("config parameter is required")
+	}
+
+	config, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, fmt.Errorf("config not found: %w", err)
+	}
+
+	client, err := h.clientFactory.GetClientForConfig(config, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Kubernetes client: %w", err)
+	}
+
+	return client, nil
+}
+
+// GetReplicationControllersSSE returns replication controllers as Server-Sent Events with real-time updates
+// @Summary Get ReplicationControllers (SSE)
+// @Description Streams ReplicationControllers in real-time using Server-Sent Events. Replication controllers predate Deployments but older clusters and manifests still carry them. Supports namespace filtering and multi-cluster configurations.
+// @Tags Workloads
+// @Accept text/event-stream
+// @Produce text/event-stream,application/json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name for multi-cluster setups"
+// @Param namespace query string false "Kubernetes namespace to filter resources"
+// @Param namespaces query string false "Comma-separated namespaces to list across, instead of namespace"
+// @Param namespaceGroup query string false "ID of a saved namespace group to list across"
+// @Param labelSelector query string false "Kubernetes label selector, e.g. app=web,tier!=cache"
+// @Success 200 {array} types.ReplicationControllerListResponse "Streaming ReplicationControllers data"
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
+// @Failure 403 {object} map[string]string "Forbidden - insufficient permissions"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/replicationcontrollers [get]
+func (h *ReplicationControllersHandler) GetReplicationControllersSSE(c *gin.Context) {
+	// Start child span for client setup
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "setup-client-for-sse")
+	defer clientSpan.End()
+
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for replicationcontrollers SSE")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		h.sseHandler.SendSSEError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client setup for SSE")
+
+	namespaces := utils.RequestedNamespaces(c)
+	namespace := strings.Join(namespaces, ",")
+
+	// Function to fetch and transform replication controllers data
+	fetchReplicationControllers := func() (interface{}, error) {
+		_, fetchSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "replicationcontrollers", namespace)
+		defer fetchSpan.End()
+
+		items, err := utils.ListInNamespaces(c.Request.Context(), namespaces, func(ctx context.Context, namespace string) ([]v1.ReplicationController, error) {
+			list, err := client.CoreV1().ReplicationControllers(namespace).List(ctx, utils.ListOptions(c))
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		if err != nil {
+			h.tracingHelper.RecordError(fetchSpan, err, "Failed to list replicationcontrollers for SSE")
+			return nil, err
+		}
+
+		response := []types.ReplicationControllerListResponse{}
+		for i := range items {
+			response = append(response, transformers.TransformReplicationControllerToResponse(&items[i]))
+		}
+
+		h.tracingHelper.AddResourceAttributes(fetchSpan, "", "replicationcontrollers", len(items))
+		h.tracingHelper.RecordSuccess(fetchSpan, fmt.Sprintf("Listed %d replicationcontrollers for SSE", len(items)))
+		return response, nil
+	}
+
+	// Get initial data
+	initialData, err := fetchReplicationControllers()
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list replicationcontrollers for SSE")
+		if utils.IsPermissionError(err) {
+			h.sseHandler.SendSSEPermissionError(c, err)
+		} else {
+			h.sseHandler.SendSSEError(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	if c.GetHeader("Accept") == "text/event-stream" {
+		h.sseHandler.SendSSEResponseWithUpdates(c, initialData, fetchReplicationControllers)
+		return
+	}
+
+	// For non-SSE requests, return JSON
+	h.sseHandler.SendJSON(c, initialData)
+}
+
+// GetReplicationController returns a specific replication controller
+// @Summary Get ReplicationController by namespace and name
+// @Description Retrieves detailed information about a specific ReplicationController in a given namespace
+// @Tags Workloads
+// @Accept json
+// @Produce json,text/event-stream
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name for multi-cluster setups"
+// @Param namespace path string true "Kubernetes namespace"
+// @Param name path string true "ReplicationController name"
+// @Success 200 {object} object "ReplicationController details"
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
+// @Failure 404 {object} map[string]string "ReplicationController not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/replicationcontrollers/{namespace}/{name} [get]
+func (h *ReplicationControllersHandler) GetReplicationController(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for replicationcontroller")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		if c.GetHeader("Accept") == "text/event-stream" {
+			h.sseHandler.SendSSEError(c, http.StatusBadRequest, err.Error())
+		} else {
+			utils.RespondError(c, http.StatusBadRequest, err)
+		}
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client setup completed")
+
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+
+	_, k8sSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "get", "replicationcontroller", namespace)
+	defer k8sSpan.End()
+
+	controller, err := client.CoreV1().ReplicationControllers(namespace).Get(c.Request.Context(), name, metav1.GetOptions{})
+	if err != nil {
+		h.logger.WithError(err).WithField("replicationcontroller", name).WithField("namespace", namespace).Error("Failed to get replicationcontroller")
+		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get replicationcontroller")
+		if c.GetHeader("Accept") == "text/event-stream" {
+			h.sseHandler.SendSSEError(c, http.StatusNotFound, err.Error())
+		} else {
+			utils.RespondError(c, http.StatusNotFound, err)
+		}
+		return
+	}
+	h.tracingHelper.AddResourceAttributes(k8sSpan, name, "replicationcontroller", 1)
+	h.tracingHelper.RecordSuccess(k8sSpan, fmt.Sprintf("Retrieved replicationcontroller: %s", name))
+
+	if c.GetHeader("Accept") == "text/event-stream" {
+		h.sseHandler.SendSSEDetailResponse(c, controller, controller, client.CoreV1().ReplicationControllers(namespace).Watch)
+		return
+	}
+
+	c.JSON(http.StatusOK, controller)
+}
+
+// GetReplicationControllerYAML returns the YAML representation of a specific replication controller
+// @Summary Get ReplicationController YAML by namespace and name
+// @Description Retrieves the YAML representation of a specific ReplicationController in a given namespace
+// @Tags Workloads
+// @Accept json
+// @Produce text/plain
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name for multi-cluster setups"
+// @Param namespace path string true "Kubernetes namespace"
+// @Param name path string true "ReplicationController name"
+// @Success 200 {string} string "ReplicationController YAML representation"
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
+// @Failure 404 {object} map[string]string "ReplicationController not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/replicationcontrollers/{namespace}/{name}/yaml [get]
+func (h *ReplicationControllersHandler) GetReplicationControllerYAML(c *gin.Context) {
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for replicationcontroller YAML")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+
+	controller, err := client.CoreV1().ReplicationControllers(namespace).Get(c.Request.Context(), name, metav1.GetOptions{})
+	if err != nil {
+		h.logger.WithError(err).WithField("replicationcontroller", name).WithField("namespace", namespace).Error("Failed to get replicationcontroller for YAML")
+		utils.RespondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	h.yamlHandler.SendYAMLResponse(c, controller, name)
+}
+
+// GetReplicationControllerEvents returns events for a specific replication controller
+// @Summary Get ReplicationController events by namespace and name
+// @Description Retrieves events related to a specific ReplicationController in a given namespace
+// @Tags Workloads
+// @Accept json
+// @Produce json,text/event-stream
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name for multi-cluster setups"
+// @Param namespace path string true "Kubernetes namespace"
+// @Param name path string true "ReplicationController name"
+// @Success 200 {array} object "ReplicationController events"
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/replicationcontrollers/{namespace}/{name}/events [get]
+func (h *ReplicationControllersHandler) GetReplicationControllerEvents(c *gin.Context) {
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for replicationcontroller events")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	name := c.Param("name")
+	h.eventsHandler.GetResourceEvents(c, client, "ReplicationController", name, h.sseHandler.SendSSEResponse)
+}
+
+// GetReplicationControllerPods returns pods for a specific replication controller
+// @Summary Get ReplicationController pods by namespace and name
+// @Description Retrieves all pods selected by a specific ReplicationController in a given namespace
+// @Tags Workloads
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name for multi-cluster setups"
+// @Param namespace path string true "Kubernetes namespace"
+// @Param name path string true "ReplicationController name"
+// @Success 200 {array} types.PodListResponse "ReplicationController pods"
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
+// @Failure 404 {object} map[string]string "ReplicationController not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/replicationcontrollers/{namespace}/{name}/pods [get]
+func (h *ReplicationControllersHandler) GetReplicationControllerPods(c *gin.Context) {
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for replicationcontroller pods")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+
+	controller, err := client.CoreV1().ReplicationControllers(namespace).Get(c.Request.Context(), name, metav1.GetOptions{})
+	if err != nil {
+		h.logger.WithError(err).WithField("replicationcontroller", name).WithField("namespace", namespace).Error("Failed to get replicationcontroller for pods")
+		utils.RespondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	// An empty selector would list every pod in the namespace, so fall back to the template labels
+	// the controller defaults its selector to
+	selector := controller.Spec.Selector
+	if len(selector) == 0 && controller.Spec.Template != nil {
+		selector = controller.Spec.Template.Labels
+	}
+	response := []types.PodListResponse{}
+	if len(selector) == 0 {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	podList, err := client.CoreV1().Pods(namespace).List(c.Request.Context(), metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(selector).String(),
+	})
+	if err != nil {
+		h.logger.WithError(err).WithField("replicationcontroller", name).WithField("namespace", namespace).Error("Failed to get replicationcontroller pods")
+		utils.RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	configID := c.Query("config")
+	clusterName := c.Query("cluster")
+	for i := range podList.Items {
+		response = append(response, transformers.TransformPodToResponse(&podList.Items[i], configID, clusterName))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ConvertReplicationControllerToDeployment returns a Deployment that replaces a replication controller
+// @Summary Convert ReplicationController to Deployment
+// @Description Builds an apps/v1 Deployment with the replication controller's selector, pod template and replicas, with the steps to migrate without downtime: apply the Deployment, wait for it to become available, then scale down and delete the replication controller. Nothing is applied to the cluster.
+// @Tags Workloads
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name for multi-cluster setups"
+// @Param namespace path string true "Kubernetes namespace"
+// @Param name path string true "ReplicationController name"
+// @Param deployment query string false "Deployment name; defaults to the replication controller's name"
+// @Success 200 {object} DeploymentConversion "Deployment and migration steps"
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
+// @Failure 404 {object} map[string]string "ReplicationController not found"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/replicationcontrollers/{namespace}/{name}/deployment [get]
+func (h *ReplicationControllersHandler) ConvertReplicationControllerToDeployment(c *gin.Context) {
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for replicationcontroller conversion")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+
+	controller, err := client.CoreV1().ReplicationControllers(namespace).Get(c.Request.Context(), name, metav1.GetOptions{})
+	if err != nil {
+		h.logger.WithError(err).WithField("replicationcontroller", name).WithField("namespace", namespace).Error("Failed to get replicationcontroller for conversion")
+		utils.RespondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	conversion, err := deploymentFromReplicationController(controller, c.Query("deployment"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	c.JSON(http.StatusOK, conversion)
+}
@@ -38,7 +38,7 @@ func NewResourceReferencesHandler(store *storage.KubeConfigStore, clientFactory
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *ResourceReferencesHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *ResourceReferencesHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
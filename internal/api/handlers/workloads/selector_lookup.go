@@ -0,0 +1,309 @@
+package workloads
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	defaultSelectorSamples = 10
+	maxSelectorSamples     = 100
+)
+
+// SelectorPodSample is one pod matched by a label selector
+type SelectorPodSample struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Node      string `json:"node,omitempty"`
+	Phase     string `json:"phase"`
+}
+
+// SelectorNodeSample is one node matched by a label selector
+type SelectorNodeSample struct {
+	Name  string `json:"name"`
+	Ready bool   `json:"ready"`
+}
+
+// SelectorLookup reports the pods and nodes a label selector matches
+type SelectorLookup struct {
+	Selector        string               `json:"selector"`
+	PodCount        int                  `json:"podCount"`
+	PodsByNamespace map[string]int       `json:"podsByNamespace"`
+	PodSamples      []SelectorPodSample  `json:"podSamples"`
+	NodeCount       int                  `json:"nodeCount"`
+	NodeSamples     []SelectorNodeSample `json:"nodeSamples"`
+	NodesSkipped    string               `json:"nodesSkipped,omitempty"`
+}
+
+// PodSelectorMatch is an object whose selector matches the pod
+type PodSelectorMatch struct {
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	Selector string `json:"selector"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// PodSelectedBy lists the Services, NetworkPolicies and PodDisruptionBudgets selecting a pod
+type PodSelectedBy struct {
+	Pod                  string             `json:"pod"`
+	Namespace            string             `json:"namespace"`
+	Labels               map[string]string  `json:"labels,omitempty"`
+	Services             []PodSelectorMatch `json:"services"`
+	NetworkPolicies      []PodSelectorMatch `json:"networkPolicies"`
+	PodDisruptionBudgets []PodSelectorMatch `json:"podDisruptionBudgets"`
+	Skipped              []string           `json:"skipped,omitempty"`
+}
+
+// summarizeSelectorMatches counts matched pods per namespace and samples pods and nodes in name order
+func summarizeSelectorMatches(selector string, pods []v1.Pod, nodes []v1.Node, samples int) SelectorLookup {
+	lookup := SelectorLookup{
+		Selector:        selector,
+		PodCount:        len(pods),
+		PodsByNamespace: map[string]int{},
+		PodSamples:      []SelectorPodSample{},
+		NodeCount:       len(nodes),
+		NodeSamples:     []SelectorNodeSample{},
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		if pods[i].Namespace != pods[j].Namespace {
+			return pods[i].Namespace < pods[j].Namespace
+		}
+		return pods[i].Name < pods[j].Name
+	})
+	for _, pod := range pods {
+		lookup.PodsByNamespace[pod.Namespace]++
+		if len(lookup.PodSamples) < samples {
+			lookup.PodSamples = append(lookup.PodSamples, SelectorPodSample{
+				Name: pod.Name, Namespace: pod.Namespace, Node: pod.Spec.NodeName, Phase: string(pod.Status.Phase),
+			})
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+	for _, node := range nodes {
+		if len(lookup.NodeSamples) == samples {
+			break
+		}
+		ready := false
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == v1.NodeReady {
+				ready = condition.Status == v1.ConditionTrue
+			}
+		}
+		lookup.NodeSamples = append(lookup.NodeSamples, SelectorNodeSample{Name: node.Name, Ready: ready})
+	}
+	return lookup
+}
+
+// buildPodSelectedBy finds the objects in the pod's namespace whose selectors match its labels.
+// Services without a selector and PDBs with a null selector select nothing, while an empty
+// NetworkPolicy podSelector selects every pod in the namespace.
+func buildPodSelectedBy(pod *v1.Pod, services []v1.Service, policies []networkingv1.NetworkPolicy, pdbs []policyv1.PodDisruptionBudget) PodSelectedBy {
+	podLabels := labels.Set(pod.Labels)
+	result := PodSelectedBy{
+		Pod:                  pod.Name,
+		Namespace:            pod.Namespace,
+		Labels:               pod.Labels,
+		Services:             []PodSelectorMatch{},
+		NetworkPolicies:      []PodSelectorMatch{},
+		PodDisruptionBudgets: []PodSelectorMatch{},
+	}
+
+	for _, svc := range services {
+		if len(svc.Spec.Selector) == 0 {
+			continue
+		}
+		if labels.SelectorFromSet(svc.Spec.Selector).Matches(podLabels) {
+			result.Services = append(result.Services, PodSelectorMatch{
+				Kind: "Service", Name: svc.Name, Selector: labels.FormatLabels(svc.Spec.Selector), Detail: string(svc.Spec.Type),
+			})
+		}
+	}
+
+	for _, policy := range policies {
+		selector, err := metav1.LabelSelectorAsSelector(&policy.Spec.PodSelector)
+		if err != nil || !selector.Matches(podLabels) {
+			continue
+		}
+		policyTypes := make([]string, 0, len(policy.Spec.PolicyTypes))
+		for _, policyType := range policy.Spec.PolicyTypes {
+			policyTypes = append(policyTypes, string(policyType))
+		}
+		result.NetworkPolicies = append(result.NetworkPolicies, PodSelectorMatch{
+			Kind: "NetworkPolicy", Name: policy.Name, Selector: metav1.FormatLabelSelector(&policy.Spec.PodSelector), Detail: strings.Join(policyTypes, ","),
+		})
+	}
+
+	for _, pdb := range pdbs {
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || !selector.Matches(podLabels) {
+			continue
+		}
+		result.PodDisruptionBudgets = append(result.PodDisruptionBudgets, PodSelectorMatch{
+			Kind: "PodDisruptionBudget", Name: pdb.Name, Selector: metav1.FormatLabelSelector(pdb.Spec.Selector),
+			Detail: fmt.Sprintf("%d disruptions allowed", pdb.Status.DisruptionsAllowed),
+		})
+	}
+	return result
+}
+
+// LookupSelector reports the pods and nodes a label selector matches
+// @Summary Look up a label selector
+// @Description Returns how many pods (per namespace) and nodes match a label selector, with samples of each in name order. Without a namespace pods are matched across all namespaces. Nodes are skipped when listing them is forbidden.
+// @Tags Workloads
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name for multi-cluster setups"
+// @Param selector query string true "Label selector, e.g. app=web,tier!=cache"
+// @Param namespace query string false "Only match pods in this namespace"
+// @Param samples query int false "Samples of pods and nodes to return (default 10, max 100)"
+// @Success 200 {object} SelectorLookup
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid selector"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/selectors/lookup [get]
+func (h *ResourceReferencesHandler) LookupSelector(c *gin.Context) {
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for selector lookup")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	raw := c.Query("selector")
+	if raw == "" {
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "selector parameter is required")
+		return
+	}
+	selector, err := labels.Parse(raw)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, fmt.Errorf("invalid selector: %w", err))
+		return
+	}
+	samples := defaultSelectorSamples
+	if s := c.Query("samples"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			utils.RespondErrorMessage(c, http.StatusBadRequest, "samples must be a non-negative integer")
+			return
+		}
+		samples = min(n, maxSelectorSamples)
+	}
+
+	ctx := c.Request.Context()
+	opts := metav1.ListOptions{LabelSelector: selector.String()}
+	podList, err := client.CoreV1().Pods(c.Query("namespace")).List(ctx, opts)
+	if err != nil {
+		h.logger.WithError(err).WithField("selector", raw).Error("Failed to list pods for selector lookup")
+		utils.RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	var nodes []v1.Node
+	var nodesSkipped string
+	nodeList, err := client.CoreV1().Nodes().List(ctx, opts)
+	switch {
+	case err == nil:
+		nodes = nodeList.Items
+	case apierrors.IsForbidden(err):
+		nodesSkipped = "nodes: forbidden"
+	default:
+		h.logger.WithError(err).WithField("selector", raw).Error("Failed to list nodes for selector lookup")
+		utils.RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	lookup := summarizeSelectorMatches(selector.String(), podList.Items, nodes, samples)
+	lookup.NodesSkipped = nodesSkipped
+	c.JSON(http.StatusOK, lookup)
+}
+
+// GetPodSelectedBy reports which Services, NetworkPolicies and PodDisruptionBudgets select a pod
+// @Summary Get the objects selecting a pod
+// @Description Answers "who targets this pod": the Services routing to it, the NetworkPolicies applying to it and the PodDisruptionBudgets covering it, all matched against the pod's current labels. Kinds the caller may not list are named in skipped.
+// @Tags Workloads
+// @Produce json
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name for multi-cluster setups"
+// @Param namespace path string true "Kubernetes namespace"
+// @Param name path string true "Pod name"
+// @Success 200 {object} PodSelectedBy
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Pod not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/pods/{namespace}/{name}/selected-by [get]
+func (h *ResourceReferencesHandler) GetPodSelectedBy(c *gin.Context) {
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for pod selectors")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	name := c.Param("name")
+	namespace := c.Param("namespace")
+	ctx := c.Request.Context()
+
+	pod, err := client.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		h.logger.WithError(err).WithField("pod", name).WithField("namespace", namespace).Error("Failed to get pod")
+		utils.RespondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	// A kind the caller may not list is reported as skipped rather than failing the lookup
+	var skipped []string
+	listErr := func(kind string, err error) bool {
+		if err == nil {
+			return false
+		}
+		if apierrors.IsForbidden(err) {
+			skipped = append(skipped, kind+": forbidden")
+			return false
+		}
+		h.logger.WithError(err).WithField("namespace", namespace).Errorf("Failed to list %s for pod selectors", kind)
+		utils.RespondError(c, http.StatusInternalServerError, err)
+		return true
+	}
+
+	var services []v1.Service
+	svcList, err := client.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if listErr("services", err) {
+		return
+	} else if err == nil {
+		services = svcList.Items
+	}
+	var policies []networkingv1.NetworkPolicy
+	policyList, err := client.NetworkingV1().NetworkPolicies(namespace).List(ctx, metav1.ListOptions{})
+	if listErr("networkpolicies", err) {
+		return
+	} else if err == nil {
+		policies = policyList.Items
+	}
+	var pdbs []policyv1.PodDisruptionBudget
+	pdbList, err := client.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{})
+	if listErr("poddisruptionbudgets", err) {
+		return
+	} else if err == nil {
+		pdbs = pdbList.Items
+	}
+
+	result := buildPodSelectedBy(pod, services, policies, pdbs)
+	result.Skipped = skipped
+	c.JSON(http.StatusOK, result)
+}
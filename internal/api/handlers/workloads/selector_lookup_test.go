@@ -0,0 +1,62 @@
+package workloads
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSummarizeSelectorMatches(t *testing.T) {
+	pod := func(namespace, name string) v1.Pod {
+		return v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}, Status: v1.PodStatus{Phase: v1.PodRunning}}
+	}
+	nodes := []v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "n2"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "n1"}, Status: v1.NodeStatus{Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}}}},
+	}
+
+	lookup := summarizeSelectorMatches("app=web", []v1.Pod{pod("b", "web-2"), pod("a", "web-1"), pod("b", "web-1")}, nodes, 2)
+	if lookup.PodCount != 3 || lookup.PodsByNamespace["a"] != 1 || lookup.PodsByNamespace["b"] != 2 {
+		t.Fatalf("unexpected pod counts %+v", lookup)
+	}
+	if len(lookup.PodSamples) != 2 || lookup.PodSamples[0].Namespace != "a" || lookup.PodSamples[1].Name != "web-1" {
+		t.Errorf("expected the first two pods in name order, got %+v", lookup.PodSamples)
+	}
+	if lookup.NodeCount != 2 || lookup.NodeSamples[0].Name != "n1" || !lookup.NodeSamples[0].Ready || lookup.NodeSamples[1].Ready {
+		t.Errorf("unexpected node samples %+v", lookup.NodeSamples)
+	}
+}
+
+func TestBuildPodSelectedBy(t *testing.T) {
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "shop", Labels: map[string]string{"app": "web", "tier": "frontend"}}}
+	services := []v1.Service{
+		{ObjectMeta: metav1.ObjectMeta{Name: "web"}, Spec: v1.ServiceSpec{Selector: map[string]string{"app": "web"}, Type: v1.ServiceTypeClusterIP}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "api"}, Spec: v1.ServiceSpec{Selector: map[string]string{"app": "api"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "external"}},
+	}
+	policies := []networkingv1.NetworkPolicy{
+		{ObjectMeta: metav1.ObjectMeta{Name: "default-deny"}, Spec: networkingv1.NetworkPolicySpec{PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "frontend"}, Spec: networkingv1.NetworkPolicySpec{PodSelector: metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "tier", Operator: metav1.LabelSelectorOpIn, Values: []string{"frontend"}}},
+		}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "db"}, Spec: networkingv1.NetworkPolicySpec{PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}}}},
+	}
+	pdbs := []policyv1.PodDisruptionBudget{
+		{ObjectMeta: metav1.ObjectMeta{Name: "web"}, Spec: policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}}, Status: policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 1}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "none"}},
+	}
+
+	result := buildPodSelectedBy(pod, services, policies, pdbs)
+	if len(result.Services) != 1 || result.Services[0].Name != "web" || result.Services[0].Selector != "app=web" {
+		t.Errorf("unexpected services %+v", result.Services)
+	}
+	if len(result.NetworkPolicies) != 2 || result.NetworkPolicies[0].Name != "default-deny" || result.NetworkPolicies[0].Detail != "Ingress" {
+		t.Errorf("expected default-deny and frontend policies, got %+v", result.NetworkPolicies)
+	}
+	if len(result.PodDisruptionBudgets) != 1 || result.PodDisruptionBudgets[0].Detail != "1 disruptions allowed" {
+		t.Errorf("unexpected PDBs %+v", result.PodDisruptionBudgets)
+	}
+}
@@ -0,0 +1,440 @@
+package workloads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+
+	"github.com/gin-gonic/gin"
+	appsV1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+)
+
+// defaultOrdinalReadyTimeout bounds how long an ordered restart waits for a single pod to become ready
+const defaultOrdinalReadyTimeout = 5 * time.Minute
+
+// OrderedRestartStatus tracks the progress of an ordinal-by-ordinal restart
+type OrderedRestartStatus struct {
+	StatefulSet    string     `json:"statefulSet"`
+	Namespace      string     `json:"namespace"`
+	State          string     `json:"state"` // "running", "completed" or "failed"
+	TotalOrdinals  int32      `json:"totalOrdinals"`
+	CurrentOrdinal int32      `json:"currentOrdinal"`
+	Restarted      []string   `json:"restarted"`
+	Message        string     `json:"message,omitempty"`
+	StartedAt      time.Time  `json:"startedAt"`
+	FinishedAt     *time.Time `json:"finishedAt,omitempty"`
+}
+
+// StatefulSetPVCInfo describes the claim backing one volumeClaimTemplate at one ordinal
+type StatefulSetPVCInfo struct {
+	Name         string `json:"name"`
+	Template     string `json:"template"`
+	Ordinal      int32  `json:"ordinal"`
+	Exists       bool   `json:"exists"`
+	Phase        string `json:"phase,omitempty"`
+	Capacity     string `json:"capacity,omitempty"`
+	StorageClass string `json:"storageClass,omitempty"`
+	VolumeName   string `json:"volumeName,omitempty"`
+	// Orphaned is set for claims whose ordinal is outside the statefulset's current ordinals
+	Orphaned bool `json:"orphaned"`
+}
+
+// StatefulSetPVCResponse lists the per-ordinal PVCs of a statefulset with its retention policy
+type StatefulSetPVCResponse struct {
+	StatefulSet            string               `json:"statefulSet"`
+	Namespace              string               `json:"namespace"`
+	Replicas               int32                `json:"replicas"`
+	WhenDeleted            string               `json:"whenDeleted"`
+	WhenScaled             string               `json:"whenScaled"`
+	PersistentVolumeClaims []StatefulSetPVCInfo `json:"persistentVolumeClaims"`
+}
+
+// orderedRestartKey scopes restart progress to a config, cluster and statefulset
+func orderedRestartKey(configID, cluster, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", configID, cluster, namespace, name)
+}
+
+// errOrderedRestartRunning is returned when an ordered restart of the statefulset is still in progress
+var errOrderedRestartRunning = errors.New("an ordered restart is already in progress")
+
+// ordinalReadyPollInterval is how often a replaced pod is checked for readiness
+var ordinalReadyPollInterval = 2 * time.Second
+
+// newOrderedRestartTracker tracks ordered restarts; a running restart blocks another of the same statefulset
+func newOrderedRestartTracker() *utils.ProgressTracker[OrderedRestartStatus] {
+	return utils.NewProgressTracker(utils.ProgressRetention,
+		func(s *OrderedRestartStatus) bool { return s.State == "running" },
+		func(s *OrderedRestartStatus) *OrderedRestartStatus {
+			next := *s
+			next.Restarted = append([]string{}, s.Restarted...)
+			return &next
+		})
+}
+
+// ordinalRange returns the first ordinal of a statefulset and its replica count
+func ordinalRange(statefulSet *appsV1.StatefulSet) (int32, int32) {
+	start, replicas := int32(0), int32(1)
+	if statefulSet.Spec.Ordinals != nil {
+		start = statefulSet.Spec.Ordinals.Start
+	}
+	if statefulSet.Spec.Replicas != nil {
+		replicas = *statefulSet.Spec.Replicas
+	}
+	return start, replicas
+}
+
+// performOrderedRestart deletes pods from the highest ordinal down, waiting for each replacement to become ready
+func (h *StatefulSetsHandler) performOrderedRestart(client kubernetes.Interface, key, name, namespace string, readyTimeout time.Duration) error {
+	statefulSet, err := client.AppsV1().StatefulSets(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get statefulset: %w", err)
+	}
+
+	start, replicas := ordinalRange(statefulSet)
+	last := start + replicas - 1
+	status := &OrderedRestartStatus{
+		StatefulSet:    name,
+		Namespace:      namespace,
+		State:          "running",
+		TotalOrdinals:  replicas,
+		CurrentOrdinal: last,
+		Restarted:      []string{},
+		StartedAt:      time.Now(),
+	}
+	if _, started := h.orderedRestarts.Start(key, status); !started {
+		return fmt.Errorf("%w for statefulset %s", errOrderedRestartRunning, name)
+	}
+
+	go func() {
+		for ordinal := last; ordinal >= start; ordinal-- {
+			podName := fmt.Sprintf("%s-%d", name, ordinal)
+			h.orderedRestarts.Update(key, func(s *OrderedRestartStatus) { s.CurrentOrdinal = ordinal })
+
+			if err := h.restartOrdinal(client, namespace, podName, readyTimeout); err != nil {
+				h.logger.WithError(err).WithField("statefulset", name).WithField("namespace", namespace).WithField("pod", podName).Error("Ordered restart halted")
+				h.orderedRestarts.Update(key, func(s *OrderedRestartStatus) {
+					s.State = "failed"
+					s.Message = err.Error()
+					s.FinishedAt = ptr.To(time.Now())
+				})
+				return
+			}
+			h.orderedRestarts.Update(key, func(s *OrderedRestartStatus) { s.Restarted = append(s.Restarted, podName) })
+		}
+
+		h.orderedRestarts.Update(key, func(s *OrderedRestartStatus) {
+			s.State = "completed"
+			s.FinishedAt = ptr.To(time.Now())
+		})
+		h.logger.WithField("statefulset", name).WithField("namespace", namespace).Info("Ordered restart completed")
+	}()
+
+	return nil
+}
+
+// restartOrdinal deletes a single pod and blocks until its replacement is ready
+func (h *StatefulSetsHandler) restartOrdinal(client kubernetes.Interface, namespace, podName string, readyTimeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), readyTimeout)
+	defer cancel()
+
+	pod, err := client.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get pod %s: %w", podName, err)
+	}
+
+	var oldUID k8stypes.UID
+	if pod != nil && err == nil {
+		oldUID = pod.UID
+		if err := client.CoreV1().Pods(namespace).Delete(ctx, podName, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete pod %s: %w", podName, err)
+		}
+	}
+
+	ticker := time.NewTicker(ordinalReadyPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("pod %s did not become ready within %s", podName, readyTimeout)
+		case <-ticker.C:
+			current, err := client.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+			if err != nil {
+				continue
+			}
+			if current.UID != oldUID && current.DeletionTimestamp == nil && isPodReady(current) {
+				return nil
+			}
+		}
+	}
+}
+
+// isPodReady reports whether the pod's Ready condition is true
+func isPodReady(pod *v1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// GetOrderedRestartStatus returns the progress of the latest ordered restart
+// @Summary Get StatefulSet ordered restart status
+// @Description Returns the progress of the most recent ordinal-by-ordinal restart of a statefulset
+// @Tags Workloads
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param namespace path string true "Namespace name"
+// @Param name path string true "StatefulSet name"
+// @Success 200 {object} OrderedRestartStatus "Ordered restart progress"
+// @Failure 404 {object} map[string]string "No ordered restart found"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/statefulsets/{namespace}/{name}/restart-status [get]
+func (h *StatefulSetsHandler) GetOrderedRestartStatus(c *gin.Context) {
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+
+	status, ok := h.orderedRestarts.Load(orderedRestartKey(c.Query("config"), c.Query("cluster"), namespace, name))
+	if !ok {
+		utils.RespondErrorMessage(c, http.StatusNotFound, "no ordered restart found for this statefulset")
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// GetStatefulSetPVCs lists the per-ordinal PVCs of a statefulset along with its retention policy
+// @Summary Get StatefulSet PVCs
+// @Description Lists the PersistentVolumeClaims created from each volumeClaimTemplate per ordinal, including claims left behind by scale-down, and the PVC retention policy
+// @Tags Workloads
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param namespace path string true "Namespace name"
+// @Param name path string true "StatefulSet name"
+// @Success 200 {object} StatefulSetPVCResponse "StatefulSet PVCs and retention policy"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 404 {object} map[string]string "StatefulSet not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/statefulsets/{namespace}/{name}/pvcs [get]
+func (h *StatefulSetsHandler) GetStatefulSetPVCs(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for statefulset PVCs")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client setup completed")
+
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+
+	_, k8sSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "persistentvolumeclaims", namespace)
+	defer k8sSpan.End()
+
+	statefulSet, err := client.AppsV1().StatefulSets(namespace).Get(c.Request.Context(), name, metav1.GetOptions{})
+	if err != nil {
+		h.logger.WithError(err).WithField("statefulset", name).WithField("namespace", namespace).Error("Failed to get statefulset for PVCs")
+		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get statefulset")
+		utils.RespondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	pvcList, err := client.CoreV1().PersistentVolumeClaims(namespace).List(c.Request.Context(), metav1.ListOptions{})
+	if err != nil {
+		h.logger.WithError(err).WithField("statefulset", name).WithField("namespace", namespace).Error("Failed to list PVCs for statefulset")
+		h.tracingHelper.RecordError(k8sSpan, err, "Failed to list persistentvolumeclaims")
+		utils.RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	response := buildStatefulSetPVCResponse(statefulSet, pvcList.Items)
+	h.tracingHelper.AddResourceAttributes(k8sSpan, name, "persistentvolumeclaims", len(response.PersistentVolumeClaims))
+	h.tracingHelper.RecordSuccess(k8sSpan, fmt.Sprintf("Found %d PVCs for statefulset %s", len(response.PersistentVolumeClaims), name))
+
+	c.JSON(http.StatusOK, response)
+}
+
+// buildStatefulSetPVCResponse matches claims named <template>-<statefulset>-<ordinal> against the statefulset
+func buildStatefulSetPVCResponse(statefulSet *appsV1.StatefulSet, pvcs []v1.PersistentVolumeClaim) StatefulSetPVCResponse {
+	start, replicas := ordinalRange(statefulSet)
+	end := start + replicas
+
+	response := StatefulSetPVCResponse{
+		StatefulSet:            statefulSet.Name,
+		Namespace:              statefulSet.Namespace,
+		Replicas:               replicas,
+		WhenDeleted:            string(appsV1.RetainPersistentVolumeClaimRetentionPolicyType),
+		WhenScaled:             string(appsV1.RetainPersistentVolumeClaimRetentionPolicyType),
+		PersistentVolumeClaims: []StatefulSetPVCInfo{},
+	}
+	if policy := statefulSet.Spec.PersistentVolumeClaimRetentionPolicy; policy != nil {
+		if policy.WhenDeleted != "" {
+			response.WhenDeleted = string(policy.WhenDeleted)
+		}
+		if policy.WhenScaled != "" {
+			response.WhenScaled = string(policy.WhenScaled)
+		}
+	}
+
+	byName := make(map[string]*v1.PersistentVolumeClaim, len(pvcs))
+	for i := range pvcs {
+		byName[pvcs[i].Name] = &pvcs[i]
+	}
+
+	for _, template := range statefulSet.Spec.VolumeClaimTemplates {
+		prefix := fmt.Sprintf("%s-%s-", template.Name, statefulSet.Name)
+		seen := make(map[int32]bool)
+
+		// Existing claims, including ones left behind by a scale-down
+		for pvcName, pvc := range byName {
+			if !strings.HasPrefix(pvcName, prefix) {
+				continue
+			}
+			var ordinal int32
+			if _, err := fmt.Sscanf(strings.TrimPrefix(pvcName, prefix), "%d", &ordinal); err != nil || fmt.Sprintf("%s%d", prefix, ordinal) != pvcName {
+				continue
+			}
+			seen[ordinal] = true
+			info := StatefulSetPVCInfo{
+				Name:       pvcName,
+				Template:   template.Name,
+				Ordinal:    ordinal,
+				Exists:     true,
+				Phase:      string(pvc.Status.Phase),
+				VolumeName: pvc.Spec.VolumeName,
+				Orphaned:   ordinal < start || ordinal >= end,
+			}
+			if storage, ok := pvc.Status.Capacity[v1.ResourceStorage]; ok {
+				info.Capacity = storage.String()
+			}
+			if pvc.Spec.StorageClassName != nil {
+				info.StorageClass = *pvc.Spec.StorageClassName
+			}
+			response.PersistentVolumeClaims = append(response.PersistentVolumeClaims, info)
+		}
+
+		// Ordinals that should have a claim but do not yet
+		for ordinal := start; ordinal < end; ordinal++ {
+			if seen[ordinal] {
+				continue
+			}
+			response.PersistentVolumeClaims = append(response.PersistentVolumeClaims, StatefulSetPVCInfo{
+				Name:     fmt.Sprintf("%s%d", prefix, ordinal),
+				Template: template.Name,
+				Ordinal:  ordinal,
+			})
+		}
+	}
+
+	sort.Slice(response.PersistentVolumeClaims, func(i, j int) bool {
+		if response.PersistentVolumeClaims[i].Template != response.PersistentVolumeClaims[j].Template {
+			return response.PersistentVolumeClaims[i].Template < response.PersistentVolumeClaims[j].Template
+		}
+		return response.PersistentVolumeClaims[i].Ordinal < response.PersistentVolumeClaims[j].Ordinal
+	})
+	return response
+}
+
+// SetStatefulSetPartition sets spec.updateStrategy.rollingUpdate.partition for staged rollouts
+// @Summary Set StatefulSet rollout partition
+// @Description Sets the rollingUpdate partition so only pods with an ordinal greater than or equal to the partition are updated
+// @Tags Workloads
+// @Accept json
+// @Produce json
+// @Param config query string true "Kubernetes config ID"
+// @Param cluster query string false "Cluster name (for multi-cluster configs)"
+// @Param name path string true "StatefulSet name"
+// @Param namespace query string true "Namespace name"
+// @Param body body object{partition=int32} true "Partition request body"
+// @Success 200 {object} map[string]string "StatefulSet partition updated"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters or update strategy"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/statefulsets/{name}/partition [post]
+func (h *StatefulSetsHandler) SetStatefulSetPartition(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for statefulset partition")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client setup completed")
+
+	name := c.Param("name")
+	namespace := c.Query("namespace")
+	if namespace == "" {
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
+		return
+	}
+
+	var body struct {
+		Partition *int32 `json:"partition"`
+	}
+	if err := c.BindJSON(&body); err != nil || body.Partition == nil || *body.Partition < 0 {
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "partition must be a non-negative integer")
+		return
+	}
+
+	_, patchSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "patch", "statefulset", namespace)
+	defer patchSpan.End()
+
+	statefulSet, err := client.AppsV1().StatefulSets(namespace).Get(c.Request.Context(), name, metav1.GetOptions{})
+	if err != nil {
+		h.tracingHelper.RecordError(patchSpan, err, "Failed to get statefulset")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	if statefulSet.Spec.UpdateStrategy.Type == appsV1.OnDeleteStatefulSetStrategyType {
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "partition requires the RollingUpdate update strategy")
+		return
+	}
+
+	patch, _ := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"updateStrategy": map[string]interface{}{
+				"type": string(appsV1.RollingUpdateStatefulSetStrategyType),
+				"rollingUpdate": map[string]interface{}{
+					"partition": *body.Partition,
+				},
+			},
+		},
+	})
+	if _, err := client.AppsV1().StatefulSets(namespace).Patch(c.Request.Context(), name, k8stypes.StrategicMergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		h.logger.WithError(err).WithField("statefulset", name).WithField("namespace", namespace).Error("Failed to set statefulset partition")
+		h.tracingHelper.RecordError(patchSpan, err, "Failed to patch statefulset partition")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	h.tracingHelper.AddResourceAttributes(patchSpan, name, "statefulset", int(*body.Partition))
+	h.tracingHelper.RecordSuccess(patchSpan, fmt.Sprintf("Set partition of statefulset %s to %d", name, *body.Partition))
+
+	c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("StatefulSet partition set to %d", *body.Partition)})
+}
@@ -0,0 +1,151 @@
+package workloads
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	appsV1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
+)
+
+func readyPod(name string, uid types.UID) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", UID: uid},
+		Status:     v1.PodStatus{Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}}},
+	}
+}
+
+// orderedRestartFixture is a statefulset whose ordinals start at 3, with a controller stand-in
+// that replaces every deleted pod with a ready one
+func orderedRestartFixture() *fake.Clientset {
+	client := fake.NewSimpleClientset(
+		&appsV1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "shop"},
+			Spec:       appsV1.StatefulSetSpec{Replicas: ptr.To(int32(2)), Ordinals: &appsV1.StatefulSetOrdinals{Start: 3}},
+		},
+		readyPod("db-3", "old-3"),
+		readyPod("db-4", "old-4"),
+	)
+	client.PrependReactor("delete", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		name := action.(k8stesting.DeleteAction).GetName()
+		pods := v1.SchemeGroupVersion.WithResource("pods")
+		if err := client.Tracker().Delete(pods, "shop", name); err != nil {
+			return true, nil, err
+		}
+		return true, nil, client.Tracker().Add(readyPod(name, types.UID("new-"+name)))
+	})
+	return client
+}
+
+// waitForOrderedRestart polls the restart until it stops running
+func waitForOrderedRestart(h *StatefulSetsHandler, key string) *OrderedRestartStatus {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		status, _ := h.orderedRestarts.Load(key)
+		if status.State != "running" || time.Now().After(deadline) {
+			return status
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestOrdinalRange(t *testing.T) {
+	start, replicas := ordinalRange(&appsV1.StatefulSet{})
+	if start != 0 || replicas != 1 {
+		t.Errorf("defaults = %d, %d", start, replicas)
+	}
+	start, replicas = ordinalRange(&appsV1.StatefulSet{Spec: appsV1.StatefulSetSpec{
+		Replicas: ptr.To(int32(3)), Ordinals: &appsV1.StatefulSetOrdinals{Start: 5},
+	}})
+	if start != 5 || replicas != 3 {
+		t.Errorf("got %d, %d", start, replicas)
+	}
+}
+
+func TestOrderedRestart(t *testing.T) {
+	ordinalReadyPollInterval = 5 * time.Millisecond
+	defer func() { ordinalReadyPollInterval = 2 * time.Second }()
+
+	client := orderedRestartFixture()
+	h := NewStatefulSetsHandler(nil, nil, logger.New("error"))
+	key := orderedRestartKey("c1", "", "shop", "db")
+
+	// Two requests racing to start a restart of the same statefulset: only one may win
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() { errs <- h.performOrderedRestart(client, key, "db", "shop", time.Second) }()
+	}
+	var started, rejected int
+	for i := 0; i < 2; i++ {
+		switch err := <-errs; {
+		case err == nil:
+			started++
+		case errors.Is(err, errOrderedRestartRunning):
+			rejected++
+		default:
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+	if started != 1 || rejected != 1 {
+		t.Fatalf("started %d and rejected %d restarts, want one each", started, rejected)
+	}
+
+	status := waitForOrderedRestart(h, key)
+	if status.State != "completed" || status.FinishedAt == nil || status.CurrentOrdinal != 3 {
+		t.Fatalf("unexpected status %+v", status)
+	}
+	if !reflect.DeepEqual(status.Restarted, []string{"db-4", "db-3"}) {
+		t.Errorf("restarted %v, want the highest ordinal first", status.Restarted)
+	}
+	for _, name := range []string{"db-3", "db-4"} {
+		pod, err := client.CoreV1().Pods("shop").Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil || pod.UID != types.UID("new-"+name) {
+			t.Errorf("expected %s to be replaced, got %v, %v", name, pod, err)
+		}
+	}
+
+	if err := h.performOrderedRestart(client, key, "db", "shop", time.Second); err != nil {
+		t.Errorf("expected a finished restart not to block a new one, got %v", err)
+	}
+	waitForOrderedRestart(h, key)
+}
+
+func TestStatefulSetPVCsWithOrdinalStart(t *testing.T) {
+	statefulSet := &appsV1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "shop"},
+		Spec: appsV1.StatefulSetSpec{
+			Replicas:             ptr.To(int32(2)),
+			Ordinals:             &appsV1.StatefulSetOrdinals{Start: 3},
+			VolumeClaimTemplates: []v1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{Name: "data"}}},
+		},
+	}
+	claim := func(name string) v1.PersistentVolumeClaim {
+		return v1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"}}
+	}
+	response := buildStatefulSetPVCResponse(statefulSet, []v1.PersistentVolumeClaim{claim("data-db-0"), claim("data-db-3")})
+
+	type state struct{ exists, orphaned bool }
+	got := map[string]state{}
+	for _, info := range response.PersistentVolumeClaims {
+		got[info.Name] = state{info.Exists, info.Orphaned}
+	}
+	want := map[string]state{
+		"data-db-0": {exists: true, orphaned: true},
+		"data-db-3": {exists: true},
+		"data-db-4": {},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -2,8 +2,10 @@ package workloads
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/Facets-cloud/kube-dash/internal/api/transformers"
@@ -15,6 +17,7 @@ import (
 	"github.com/Facets-cloud/kube-dash/pkg/logger"
 
 	"github.com/gin-gonic/gin"
+	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -28,6 +31,9 @@ type StatefulSetsHandler struct {
 	yamlHandler   *utils.YAMLHandler
 	sseHandler    *utils.SSEHandler
 	tracingHelper *tracing.TracingHelper
+
+	// Progress of ordered restarts keyed by config/cluster/namespace/name
+	orderedRestarts *utils.ProgressTracker[OrderedRestartStatus]
 }
 
 // NewStatefulSetsHandler creates a new StatefulSets handler
@@ -40,11 +46,13 @@ func NewStatefulSetsHandler(store *storage.KubeConfigStore, clientFactory *k8s.C
 		yamlHandler:   utils.NewYAMLHandler(log),
 		sseHandler:    utils.NewSSEHandler(log),
 		tracingHelper: tracing.GetTracingHelper(),
+
+		orderedRestarts: newOrderedRestartTracker(),
 	}
 }
 
 // getClientAndConfig gets the Kubernetes client and config for the given config ID and cluster
-func (h *StatefulSetsHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Clientset, error) {
+func (h *StatefulSetsHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
 	configID := c.Query("config")
 	cluster := c.Query("cluster")
 
@@ -74,6 +82,12 @@ func (h *StatefulSetsHandler) getClientAndConfig(c *gin.Context) (*kubernetes.Cl
 // @Param config query string true "Kubernetes config ID"
 // @Param cluster query string false "Cluster name (for multi-cluster configs)"
 // @Param namespace query string false "Namespace to filter statefulsets (empty for all namespaces)"
+// @Param namespaces query string false "Comma-separated namespaces to list across, instead of namespace"
+// @Param namespaceGroup query string false "ID of a saved namespace group to list across"
+// @Param fields query string false "Comma-separated response fields to return, e.g. name,status,restarts; name, namespace and uid are always included"
+// @Param labelSelector query string false "Kubernetes label selector, e.g. app=web,tier!=cache"
+// @Param sort query string false "Response field to sort by, e.g. name or -restarts for descending"
+// @Param view query string false "ID of a saved view supplying namespaces, labelSelector, sort and fields"
 // @Success 200 {array} types.StatefulSetListResponse "Stream of statefulset data"
 // @Failure 400 {object} map[string]string "Bad request - invalid parameters"
 // @Failure 403 {object} map[string]string "Forbidden - insufficient permissions"
@@ -95,7 +109,8 @@ func (h *StatefulSetsHandler) GetStatefulSetsSSE(c *gin.Context) {
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client setup for SSE")
 
-	namespace := c.Query("namespace")
+	namespaces := utils.RequestedNamespaces(c)
+	namespace := strings.Join(namespaces, ",")
 
 	// Function to fetch and transform statefulsets data
 	fetchStatefulSets := func() (interface{}, error) {
@@ -103,7 +118,14 @@ func (h *StatefulSetsHandler) GetStatefulSetsSSE(c *gin.Context) {
 		_, fetchSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "list", "statefulsets", namespace)
 		defer fetchSpan.End()
 
-		statefulSetList, err := client.AppsV1().StatefulSets(namespace).List(c.Request.Context(), metav1.ListOptions{})
+		items, err := utils.ListInNamespaces(c.Request.Context(), namespaces, func(ctx context.Context, namespace string) ([]appsv1.StatefulSet, error) {
+			list, err := client.AppsV1().StatefulSets(namespace).List(ctx, utils.ListOptions(c))
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		statefulSetList := &appsv1.StatefulSetList{Items: items}
 		if err != nil {
 			h.tracingHelper.RecordError(fetchSpan, err, "Failed to list statefulsets for SSE")
 			return nil, err
@@ -149,7 +171,7 @@ func (h *StatefulSetsHandler) GetStatefulSetsSSE(c *gin.Context) {
 	}
 
 	// For non-SSE requests, return JSON
-	c.JSON(http.StatusOK, initialData)
+	h.sseHandler.SendJSON(c, initialData)
 }
 
 // GetStatefulSet returns a specific statefulset
@@ -181,7 +203,7 @@ func (h *StatefulSetsHandler) GetStatefulSet(c *gin.Context) {
 		if c.GetHeader("Accept") == "text/event-stream" {
 			h.sseHandler.SendSSEError(c, http.StatusBadRequest, err.Error())
 		} else {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			utils.RespondError(c, http.StatusBadRequest, err)
 		}
 		return
 	}
@@ -202,7 +224,7 @@ func (h *StatefulSetsHandler) GetStatefulSet(c *gin.Context) {
 		if c.GetHeader("Accept") == "text/event-stream" {
 			h.sseHandler.SendSSEError(c, http.StatusNotFound, err.Error())
 		} else {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			utils.RespondError(c, http.StatusNotFound, err)
 		}
 		return
 	}
@@ -212,7 +234,7 @@ func (h *StatefulSetsHandler) GetStatefulSet(c *gin.Context) {
 	// Check if this is an SSE request (EventSource expects SSE format)
 	acceptHeader := c.GetHeader("Accept")
 	if acceptHeader == "text/event-stream" {
-		h.sseHandler.SendSSEResponse(c, statefulSet)
+		h.sseHandler.SendSSEDetailResponse(c, statefulSet, statefulSet, client.AppsV1().StatefulSets(namespace).Watch)
 		return
 	}
 
@@ -244,7 +266,7 @@ func (h *StatefulSetsHandler) GetStatefulSetByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for statefulset by name")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client setup completed")
@@ -253,7 +275,7 @@ func (h *StatefulSetsHandler) GetStatefulSetByName(c *gin.Context) {
 	namespace := c.Query("namespace")
 
 	if namespace == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -265,7 +287,7 @@ func (h *StatefulSetsHandler) GetStatefulSetByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("statefulset", name).WithField("namespace", namespace).Error("Failed to get statefulset by name")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get statefulset by name")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(k8sSpan, name, "statefulset", 1)
@@ -299,7 +321,7 @@ func (h *StatefulSetsHandler) GetStatefulSetYAMLByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for statefulset YAML by name")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client setup completed")
@@ -308,7 +330,7 @@ func (h *StatefulSetsHandler) GetStatefulSetYAMLByName(c *gin.Context) {
 	namespace := c.Query("namespace")
 
 	if namespace == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -320,7 +342,7 @@ func (h *StatefulSetsHandler) GetStatefulSetYAMLByName(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("statefulset", name).WithField("namespace", namespace).Error("Failed to get statefulset for YAML by name")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get statefulset for YAML")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(k8sSpan, name, "statefulset", 1)
@@ -359,7 +381,7 @@ func (h *StatefulSetsHandler) GetStatefulSetYAML(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for statefulset YAML")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client setup completed")
@@ -375,7 +397,7 @@ func (h *StatefulSetsHandler) GetStatefulSetYAML(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("statefulset", name).WithField("namespace", namespace).Error("Failed to get statefulset for YAML")
 		h.tracingHelper.RecordError(k8sSpan, err, "Failed to get statefulset for YAML")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(k8sSpan, name, "statefulset", 1)
@@ -408,7 +430,7 @@ func (h *StatefulSetsHandler) GetStatefulSetEventsByName(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for statefulset events")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -417,7 +439,7 @@ func (h *StatefulSetsHandler) GetStatefulSetEventsByName(c *gin.Context) {
 
 	if namespace == "" {
 		h.logger.WithField("statefulset", name).Error("Namespace is required for statefulset events lookup")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -443,7 +465,7 @@ func (h *StatefulSetsHandler) GetStatefulSetEvents(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for statefulset events")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -472,7 +494,7 @@ func (h *StatefulSetsHandler) GetStatefulSetPods(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for statefulset pods")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -483,7 +505,7 @@ func (h *StatefulSetsHandler) GetStatefulSetPods(c *gin.Context) {
 	statefulSet, err := client.AppsV1().StatefulSets(namespace).Get(c.Request.Context(), name, metav1.GetOptions{})
 	if err != nil {
 		h.logger.WithError(err).WithField("statefulset", name).WithField("namespace", namespace).Error("Failed to get statefulset for pods")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -499,7 +521,7 @@ func (h *StatefulSetsHandler) GetStatefulSetPods(c *gin.Context) {
 	})
 	if err != nil {
 		h.logger.WithError(err).WithField("statefulset", name).WithField("namespace", namespace).Error("Failed to get statefulset pods")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -535,7 +557,7 @@ func (h *StatefulSetsHandler) GetStatefulSetPodsByName(c *gin.Context) {
 	client, err := h.getClientAndConfig(c)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for statefulset pods by name")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -543,7 +565,7 @@ func (h *StatefulSetsHandler) GetStatefulSetPodsByName(c *gin.Context) {
 	namespace := c.Query("namespace")
 
 	if namespace == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "namespace parameter is required"})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -551,7 +573,7 @@ func (h *StatefulSetsHandler) GetStatefulSetPodsByName(c *gin.Context) {
 	statefulSet, err := client.AppsV1().StatefulSets(namespace).Get(c.Request.Context(), name, metav1.GetOptions{})
 	if err != nil {
 		h.logger.WithError(err).WithField("statefulset", name).WithField("namespace", namespace).Error("Failed to get statefulset for pods by name")
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -567,7 +589,7 @@ func (h *StatefulSetsHandler) GetStatefulSetPodsByName(c *gin.Context) {
 	})
 	if err != nil {
 		h.logger.WithError(err).WithField("statefulset", name).WithField("namespace", namespace).Error("Failed to get statefulset pods by name")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -607,7 +629,7 @@ func (h *StatefulSetsHandler) ScaleStatefulSet(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for scaling statefulset")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error(), "code": http.StatusBadRequest})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client setup completed")
@@ -615,7 +637,7 @@ func (h *StatefulSetsHandler) ScaleStatefulSet(c *gin.Context) {
 	name := c.Param("name")
 	namespace := c.Query("namespace")
 	if namespace == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "namespace parameter is required", "code": http.StatusBadRequest})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -628,7 +650,7 @@ func (h *StatefulSetsHandler) ScaleStatefulSet(c *gin.Context) {
 	}
 	if err := c.BindJSON(&body); err != nil {
 		h.tracingHelper.RecordError(parseSpan, err, "Failed to parse scale request")
-		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid request body", "code": http.StatusBadRequest})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "invalid request body")
 		return
 	}
 	h.tracingHelper.RecordSuccess(parseSpan, fmt.Sprintf("Parsed scale request for %d replicas", body.Replicas))
@@ -641,7 +663,7 @@ func (h *StatefulSetsHandler) ScaleStatefulSet(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).WithField("statefulset", name).WithField("namespace", namespace).Error("Failed to get statefulset scale")
 		h.tracingHelper.RecordError(getScaleSpan, err, "Failed to get statefulset scale")
-		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error(), "code": http.StatusBadRequest})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(getScaleSpan, name, "statefulset", 1)
@@ -655,7 +677,7 @@ func (h *StatefulSetsHandler) ScaleStatefulSet(c *gin.Context) {
 	if _, err := client.AppsV1().StatefulSets(namespace).UpdateScale(c.Request.Context(), name, scale, metav1.UpdateOptions{}); err != nil {
 		h.logger.WithError(err).WithField("statefulset", name).WithField("namespace", namespace).Error("Failed to update statefulset scale")
 		h.tracingHelper.RecordError(updateScaleSpan, err, "Failed to update statefulset scale")
-		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error(), "code": http.StatusBadRequest})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.AddResourceAttributes(updateScaleSpan, name, "statefulset", int(body.Replicas))
@@ -674,7 +696,7 @@ func (h *StatefulSetsHandler) ScaleStatefulSet(c *gin.Context) {
 // @Param cluster query string false "Cluster name (for multi-cluster configs)"
 // @Param name path string true "StatefulSet name"
 // @Param namespace query string true "Namespace name"
-// @Param body body object{restartType=string} false "Restart request body (restartType: 'rolling' or 'recreate', defaults to 'rolling')"
+// @Param body body object{restartType=string,readyTimeoutSeconds=int} false "Restart request body (restartType: 'rolling', 'recreate' or 'ordered', defaults to 'rolling')"
 // @Success 200 {object} map[string]string "StatefulSet restart initiated successfully"
 // @Failure 400 {object} map[string]string "Bad request - invalid parameters or restart type"
 // @Security BearerAuth
@@ -689,7 +711,7 @@ func (h *StatefulSetsHandler) RestartStatefulSet(c *gin.Context) {
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get client for restarting statefulset")
 		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
-		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error(), "code": http.StatusBadRequest})
+		utils.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client setup completed")
@@ -697,7 +719,7 @@ func (h *StatefulSetsHandler) RestartStatefulSet(c *gin.Context) {
 	name := c.Param("name")
 	namespace := c.Query("namespace")
 	if namespace == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "namespace parameter is required", "code": http.StatusBadRequest})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "namespace parameter is required")
 		return
 	}
 
@@ -706,7 +728,8 @@ func (h *StatefulSetsHandler) RestartStatefulSet(c *gin.Context) {
 	defer parseSpan.End()
 
 	var body struct {
-		RestartType string `json:"restartType"` // "rolling" or "recreate"
+		RestartType         string `json:"restartType"` // "rolling", "recreate" or "ordered"
+		ReadyTimeoutSeconds int    `json:"readyTimeoutSeconds"`
 	}
 	if err := c.BindJSON(&body); err != nil {
 		// Default to rolling restart if no body provided
@@ -714,9 +737,9 @@ func (h *StatefulSetsHandler) RestartStatefulSet(c *gin.Context) {
 	}
 
 	// Validate restart type
-	if body.RestartType != "rolling" && body.RestartType != "recreate" {
+	if body.RestartType != "rolling" && body.RestartType != "recreate" && body.RestartType != "ordered" {
 		h.tracingHelper.RecordError(parseSpan, fmt.Errorf("invalid restart type: %s", body.RestartType), "Invalid restart type")
-		c.JSON(http.StatusBadRequest, gin.H{"message": "restartType must be 'rolling' or 'recreate'", "code": http.StatusBadRequest})
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "restartType must be 'rolling', 'recreate' or 'ordered'")
 		return
 	}
 	h.tracingHelper.RecordSuccess(parseSpan, fmt.Sprintf("Parsed restart request with type: %s", body.RestartType))
@@ -731,19 +754,40 @@ func (h *StatefulSetsHandler) RestartStatefulSet(c *gin.Context) {
 		if err != nil {
 			h.logger.WithError(err).WithField("statefulset", name).WithField("namespace", namespace).Error("Failed to perform rolling restart")
 			h.tracingHelper.RecordError(restartSpan, err, "Failed to perform rolling restart")
-			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error(), "code": http.StatusBadRequest})
+			utils.RespondError(c, http.StatusBadRequest, err)
 			return
 		}
 		h.tracingHelper.AddResourceAttributes(restartSpan, name, "statefulset", 1)
 		h.tracingHelper.RecordSuccess(restartSpan, fmt.Sprintf("Rolling restart initiated for statefulset: %s", name))
 		c.JSON(http.StatusOK, gin.H{"message": "Rolling restart initiated - pods will be replaced gradually while maintaining availability"})
+	} else if body.RestartType == "ordered" {
+		// Ordered restart: Delete pods from the highest ordinal down, waiting for each to become ready
+		readyTimeout := defaultOrdinalReadyTimeout
+		if body.ReadyTimeoutSeconds > 0 {
+			readyTimeout = time.Duration(body.ReadyTimeoutSeconds) * time.Second
+		}
+		key := orderedRestartKey(c.Query("config"), c.Query("cluster"), namespace, name)
+		err = h.performOrderedRestart(client, key, name, namespace, readyTimeout)
+		if err != nil {
+			h.logger.WithError(err).WithField("statefulset", name).WithField("namespace", namespace).Error("Failed to perform ordered restart")
+			h.tracingHelper.RecordError(restartSpan, err, "Failed to perform ordered restart")
+			status := http.StatusBadRequest
+			if errors.Is(err, errOrderedRestartRunning) {
+				status = http.StatusConflict
+			}
+			utils.RespondError(c, status, err)
+			return
+		}
+		h.tracingHelper.AddResourceAttributes(restartSpan, name, "statefulset", 1)
+		h.tracingHelper.RecordSuccess(restartSpan, fmt.Sprintf("Ordered restart initiated for statefulset: %s", name))
+		c.JSON(http.StatusOK, gin.H{"message": "Ordered restart initiated - pods will be restarted one ordinal at a time"})
 	} else {
 		// Recreate restart: Set replicas to 0, then back to original count
 		err = h.performRecreateRestart(client, name, namespace)
 		if err != nil {
 			h.logger.WithError(err).WithField("statefulset", name).WithField("namespace", namespace).Error("Failed to perform recreate restart")
 			h.tracingHelper.RecordError(restartSpan, err, "Failed to perform recreate restart")
-			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error(), "code": http.StatusBadRequest})
+			utils.RespondError(c, http.StatusBadRequest, err)
 			return
 		}
 		h.tracingHelper.AddResourceAttributes(restartSpan, name, "statefulset", 1)
@@ -753,7 +797,7 @@ func (h *StatefulSetsHandler) RestartStatefulSet(c *gin.Context) {
 }
 
 // performRollingRestart performs a rolling restart by adding a restart annotation
-func (h *StatefulSetsHandler) performRollingRestart(client *kubernetes.Clientset, name, namespace string) error {
+func (h *StatefulSetsHandler) performRollingRestart(client kubernetes.Interface, name, namespace string) error {
 	// Get the current statefulset
 	statefulSet, err := client.AppsV1().StatefulSets(namespace).Get(context.Background(), name, metav1.GetOptions{})
 	if err != nil {
@@ -776,7 +820,7 @@ func (h *StatefulSetsHandler) performRollingRestart(client *kubernetes.Clientset
 }
 
 // performRecreateRestart performs a recreate restart by scaling to 0 and back
-func (h *StatefulSetsHandler) performRecreateRestart(client *kubernetes.Clientset, name, namespace string) error {
+func (h *StatefulSetsHandler) performRecreateRestart(client kubernetes.Interface, name, namespace string) error {
 	// Get the current statefulset
 	statefulSet, err := client.AppsV1().StatefulSets(namespace).Get(context.Background(), name, metav1.GetOptions{})
 	if err != nil {
@@ -806,7 +850,7 @@ func (h *StatefulSetsHandler) performRecreateRestart(client *kubernetes.Clientse
 }
 
 // scaleStatefulSetWithRetry scales a statefulset with retry logic for conflict errors
-func (h *StatefulSetsHandler) scaleStatefulSetWithRetry(client *kubernetes.Clientset, name, namespace string, replicas int32) error {
+func (h *StatefulSetsHandler) scaleStatefulSetWithRetry(client kubernetes.Interface, name, namespace string, replicas int32) error {
 	for i := 0; i < 5; i++ {
 		scale, err := client.AppsV1().StatefulSets(namespace).GetScale(context.Background(), name, metav1.GetOptions{})
 		if err != nil {
@@ -0,0 +1,588 @@
+package workloads
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	appsV1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Parts of a pod spec compared against the workload template
+const (
+	DriftFieldContainer   = "container"
+	DriftFieldImage       = "image"
+	DriftFieldEnv         = "env"
+	DriftFieldVolumeMount = "volumeMount"
+	DriftFieldVolume      = "volume"
+)
+
+// DriftDifference is one way a pod differs from its workload template. Values of literal
+// environment variables are never included.
+type DriftDifference struct {
+	Container string `json:"container,omitempty"`
+	Field     string `json:"field"`          // container, image, env, volumeMount or volume
+	Name      string `json:"name,omitempty"` // variable name, mount path or volume name
+	Change    string `json:"change"`         // missing or changed
+	Expected  string `json:"expected,omitempty"`
+	Actual    string `json:"actual,omitempty"`
+}
+
+// PodDrift is the comparison of one pod with its workload template
+type PodDrift struct {
+	Name              string            `json:"name"`
+	Node              string            `json:"node,omitempty"`
+	Phase             string            `json:"phase"`
+	Ready             bool              `json:"ready"`
+	CreationTimestamp time.Time         `json:"creationTimestamp"`
+	Revision          string            `json:"revision,omitempty"`
+	OutOfDate         bool              `json:"outOfDate"`
+	Differences       []DriftDifference `json:"differences"`
+}
+
+// WorkloadDriftReport lists the pods of a workload that do not run its current template
+type WorkloadDriftReport struct {
+	Kind            string     `json:"kind"`
+	Namespace       string     `json:"namespace"`
+	Name            string     `json:"name"`
+	CurrentRevision string     `json:"currentRevision,omitempty"`
+	Replicas        int32      `json:"replicas"`
+	UpToDate        int        `json:"upToDate"`
+	OutOfDate       int        `json:"outOfDate"`
+	Causes          []string   `json:"causes,omitempty"` // why the controller is not replacing out-of-date pods
+	Pods            []PodDrift `json:"pods"`
+	Warnings        []string   `json:"warnings,omitempty"`
+}
+
+// StalePodsDeleteRequest selects out-of-date pods to delete; no names selects all of them
+type StalePodsDeleteRequest struct {
+	Pods   []string `json:"pods"`
+	DryRun bool     `json:"dryRun"`
+}
+
+// StalePodFailure describes a pod that could not be deleted
+type StalePodFailure struct {
+	Name    string `json:"name"`
+	Message string `json:"message"`
+}
+
+// StalePodsDeleteResponse reports the outcome of deleting out-of-date pods
+type StalePodsDeleteResponse struct {
+	Kind      string            `json:"kind"`
+	Namespace string            `json:"namespace"`
+	Name      string            `json:"name"`
+	DryRun    bool              `json:"dryRun"`
+	Deleted   []string          `json:"deleted"`
+	Skipped   []string          `json:"skipped,omitempty"` // requested pods that are up to date or gone
+	Failures  []StalePodFailure `json:"failures,omitempty"`
+	Warnings  []string          `json:"warnings,omitempty"`
+}
+
+// driftInput is what a drift report is built from
+type driftInput struct {
+	template        *v1.PodTemplateSpec
+	replicas        int32
+	revisionLabel   string // pod label carrying the revision the pod was created from
+	currentRevision string // empty when it cannot be determined
+	causes          []string
+	pods            []v1.Pod
+}
+
+// WorkloadDriftHandler compares the running pods of workloads with their templates
+type WorkloadDriftHandler struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	logger        *logger.Logger
+}
+
+// NewWorkloadDriftHandler creates a new workload drift handler
+func NewWorkloadDriftHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger) *WorkloadDriftHandler {
+	return &WorkloadDriftHandler{
+		store:         store,
+		clientFactory: clientFactory,
+		logger:        log,
+	}
+}
+
+// getClientAndConfig gets the Kubernetes client for the current request
+func (h *WorkloadDriftHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
+	configID := c.Query("config")
+	cluster := c.Query("cluster")
+
+	if configID == "" {
+		return nil, fmt.Errorf("config parameter is required")
+	}
+
+	config, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, fmt.Errorf("config not found: %w", err)
+	}
+
+	client, err := h.clientFactory.GetClientForConfig(config, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Kubernetes client: %w", err)
+	}
+
+	return client, nil
+}
+
+// currentReplicaSetHash returns the pod-template-hash of the ReplicaSet of a deployment's
+// current revision
+func currentReplicaSetHash(ctx context.Context, client kubernetes.Interface, deployment *appsV1.Deployment) (string, error) {
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return "", fmt.Errorf("invalid deployment selector: %w", err)
+	}
+	rsList, err := client.AppsV1().ReplicaSets(deployment.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return "", err
+	}
+	want := deployment.Annotations[revisionAnnotation]
+	var newest *appsV1.ReplicaSet
+	var newestRevision int64 = -1
+	for i := range rsList.Items {
+		rs := &rsList.Items[i]
+		if !metav1.IsControlledBy(rs, deployment) {
+			continue
+		}
+		if want != "" && rs.Annotations[revisionAnnotation] == want {
+			return rs.Labels[appsV1.DefaultDeploymentUniqueLabelKey], nil
+		}
+		revision, _ := strconv.ParseInt(rs.Annotations[revisionAnnotation], 10, 64)
+		if revision > newestRevision {
+			newest, newestRevision = rs, revision
+		}
+	}
+	if newest == nil {
+		return "", nil
+	}
+	return newest.Labels[appsV1.DefaultDeploymentUniqueLabelKey], nil
+}
+
+// currentDaemonSetHash returns the hash of the newest ControllerRevision of a daemonset, which
+// its pods carry in the controller-revision-hash label
+func currentDaemonSetHash(ctx context.Context, client kubernetes.Interface, ds *appsV1.DaemonSet) (string, error) {
+	selector, err := metav1.LabelSelectorAsSelector(ds.Spec.Selector)
+	if err != nil {
+		return "", fmt.Errorf("invalid daemonset selector: %w", err)
+	}
+	revisions, err := client.AppsV1().ControllerRevisions(ds.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return "", err
+	}
+	var newest *appsV1.ControllerRevision
+	for i := range revisions.Items {
+		revision := &revisions.Items[i]
+		if !metav1.IsControlledBy(revision, ds) {
+			continue
+		}
+		if newest == nil || revision.Revision > newest.Revision {
+			newest = revision
+		}
+	}
+	if newest == nil {
+		return "", nil
+	}
+	if hash := newest.Labels[appsV1.ControllerRevisionHashLabelKey]; hash != "" {
+		return hash, nil
+	}
+	return strings.TrimPrefix(newest.Name, ds.Name+"-"), nil
+}
+
+// loadDriftInput fetches a workload, its current revision and its pods
+func loadDriftInput(ctx context.Context, client kubernetes.Interface, kind, namespace, name string) (*driftInput, error) {
+	input := &driftInput{revisionLabel: appsV1.ControllerRevisionHashLabelKey}
+	var selector *metav1.LabelSelector
+	switch kind {
+	case workloadKindDeployments:
+		obj, err := client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		input.template, selector = &obj.Spec.Template, obj.Spec.Selector
+		input.replicas = 1
+		if obj.Spec.Replicas != nil {
+			input.replicas = *obj.Spec.Replicas
+		}
+		input.revisionLabel = appsV1.DefaultDeploymentUniqueLabelKey
+		if input.currentRevision, err = currentReplicaSetHash(ctx, client, obj); err != nil {
+			return nil, fmt.Errorf("failed to list replicasets: %w", err)
+		}
+		if obj.Spec.Paused {
+			input.causes = append(input.causes, "the rollout is paused; resume it to replace out-of-date pods")
+		}
+		for _, cond := range obj.Status.Conditions {
+			if cond.Type == appsV1.DeploymentProgressing && cond.Reason == "ProgressDeadlineExceeded" {
+				input.causes = append(input.causes, fmt.Sprintf("the rollout exceeded its progress deadline: %s", cond.Message))
+			}
+		}
+	case workloadKindStatefulSets:
+		obj, err := client.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		input.template, selector = &obj.Spec.Template, obj.Spec.Selector
+		input.replicas = 1
+		if obj.Spec.Replicas != nil {
+			input.replicas = *obj.Spec.Replicas
+		}
+		input.currentRevision = obj.Status.UpdateRevision
+		switch strategy := obj.Spec.UpdateStrategy; {
+		case strategy.Type == appsV1.OnDeleteStatefulSetStrategyType:
+			input.causes = append(input.causes, "the update strategy is OnDelete; pods are only replaced when they are deleted")
+		case strategy.RollingUpdate != nil && strategy.RollingUpdate.Partition != nil && *strategy.RollingUpdate.Partition > 0:
+			input.causes = append(input.causes, fmt.Sprintf("the rolling update partition is %d; pods with a lower ordinal keep their revision", *strategy.RollingUpdate.Partition))
+		}
+	case workloadKindDaemonSets:
+		obj, err := client.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		input.template, selector = &obj.Spec.Template, obj.Spec.Selector
+		input.replicas = obj.Status.DesiredNumberScheduled
+		if input.currentRevision, err = currentDaemonSetHash(ctx, client, obj); err != nil {
+			return nil, fmt.Errorf("failed to list controller revisions: %w", err)
+		}
+		if obj.Spec.UpdateStrategy.Type == appsV1.OnDeleteDaemonSetStrategyType {
+			input.causes = append(input.causes, "the update strategy is OnDelete; pods are only replaced when they are deleted")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported workload kind %q; use deployments, statefulsets or daemonsets", kind)
+	}
+
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: metav1.FormatLabelSelector(selector)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	input.pods = pods.Items
+	return input, nil
+}
+
+// envSource describes where an environment variable gets its value, without literal values
+func envSource(env v1.EnvVar) string {
+	from := env.ValueFrom
+	switch {
+	case from == nil:
+		return "value"
+	case from.SecretKeyRef != nil:
+		return fmt.Sprintf("secret %s/%s", from.SecretKeyRef.Name, from.SecretKeyRef.Key)
+	case from.ConfigMapKeyRef != nil:
+		return fmt.Sprintf("configmap %s/%s", from.ConfigMapKeyRef.Name, from.ConfigMapKeyRef.Key)
+	case from.FieldRef != nil:
+		return "field " + from.FieldRef.FieldPath
+	case from.ResourceFieldRef != nil:
+		return "resource " + from.ResourceFieldRef.Resource
+	}
+	return "valueFrom"
+}
+
+// envEqual compares two environment variables by value or source
+func envEqual(a, b v1.EnvVar) bool {
+	if a.ValueFrom == nil || b.ValueFrom == nil {
+		return a.ValueFrom == nil && b.ValueFrom == nil && a.Value == b.Value
+	}
+	return envSource(a) == envSource(b)
+}
+
+// mountDescription renders a volume mount for comparison
+func mountDescription(mount v1.VolumeMount) string {
+	description := mount.Name
+	if mount.SubPath != "" {
+		description += " subPath " + mount.SubPath
+	}
+	if mount.ReadOnly {
+		description += " (read-only)"
+	}
+	return description
+}
+
+// volumeDescription renders the source of a volume for comparison, naming the object it uses
+func volumeDescription(volume v1.Volume) string {
+	source := volume.VolumeSource
+	switch {
+	case source.ConfigMap != nil:
+		return "configmap " + source.ConfigMap.Name
+	case source.Secret != nil:
+		return "secret " + source.Secret.SecretName
+	case source.PersistentVolumeClaim != nil:
+		return "persistentvolumeclaim " + source.PersistentVolumeClaim.ClaimName
+	case source.EmptyDir != nil:
+		return "emptydir"
+	case source.HostPath != nil:
+		return "hostpath " + source.HostPath.Path
+	case source.Projected != nil:
+		var parts []string
+		for _, p := range source.Projected.Sources {
+			switch {
+			case p.ConfigMap != nil:
+				parts = append(parts, "configmap "+p.ConfigMap.Name)
+			case p.Secret != nil:
+				parts = append(parts, "secret "+p.Secret.Name)
+			}
+		}
+		return "projected " + strings.Join(parts, ", ")
+	}
+	return "other"
+}
+
+// compareContainer lists how a pod container differs from its template container
+func compareContainer(expected, actual v1.Container) []DriftDifference {
+	var differences []DriftDifference
+	if expected.Image != actual.Image {
+		differences = append(differences, DriftDifference{Container: expected.Name, Field: DriftFieldImage, Change: "changed", Expected: expected.Image, Actual: actual.Image})
+	}
+
+	podEnv := make(map[string]v1.EnvVar, len(actual.Env))
+	for _, env := range actual.Env {
+		podEnv[env.Name] = env
+	}
+	for _, env := range expected.Env {
+		got, ok := podEnv[env.Name]
+		switch {
+		case !ok:
+			differences = append(differences, DriftDifference{Container: expected.Name, Field: DriftFieldEnv, Name: env.Name, Change: "missing", Expected: envSource(env)})
+		case !envEqual(env, got):
+			differences = append(differences, DriftDifference{Container: expected.Name, Field: DriftFieldEnv, Name: env.Name, Change: "changed", Expected: envSource(env), Actual: envSource(got)})
+		}
+	}
+
+	podMounts := make(map[string]v1.VolumeMount, len(actual.VolumeMounts))
+	for _, mount := range actual.VolumeMounts {
+		podMounts[mount.MountPath] = mount
+	}
+	for _, mount := range expected.VolumeMounts {
+		got, ok := podMounts[mount.MountPath]
+		switch {
+		case !ok:
+			differences = append(differences, DriftDifference{Container: expected.Name, Field: DriftFieldVolumeMount, Name: mount.MountPath, Change: "missing", Expected: mountDescription(mount)})
+		case mountDescription(mount) != mountDescription(got):
+			differences = append(differences, DriftDifference{Container: expected.Name, Field: DriftFieldVolumeMount, Name: mount.MountPath, Change: "changed", Expected: mountDescription(mount), Actual: mountDescription(got)})
+		}
+	}
+	return differences
+}
+
+// comparePod lists how a pod differs from a template. Only what the template declares is
+// compared, since admission webhooks add containers, variables and volumes to pods.
+func comparePod(template *v1.PodTemplateSpec, pod *v1.Pod) []DriftDifference {
+	differences := []DriftDifference{}
+	for _, lists := range [][2][]v1.Container{
+		{template.Spec.InitContainers, pod.Spec.InitContainers},
+		{template.Spec.Containers, pod.Spec.Containers},
+	} {
+		podContainers := make(map[string]v1.Container, len(lists[1]))
+		for _, container := range lists[1] {
+			podContainers[container.Name] = container
+		}
+		for _, container := range lists[0] {
+			actual, ok := podContainers[container.Name]
+			if !ok {
+				differences = append(differences, DriftDifference{Container: container.Name, Field: DriftFieldContainer, Change: "missing", Expected: container.Image})
+				continue
+			}
+			differences = append(differences, compareContainer(container, actual)...)
+		}
+	}
+
+	podVolumes := make(map[string]v1.Volume, len(pod.Spec.Volumes))
+	for _, volume := range pod.Spec.Volumes {
+		podVolumes[volume.Name] = volume
+	}
+	for _, volume := range template.Spec.Volumes {
+		got, ok := podVolumes[volume.Name]
+		switch {
+		case !ok:
+			differences = append(differences, DriftDifference{Field: DriftFieldVolume, Name: volume.Name, Change: "missing", Expected: volumeDescription(volume)})
+		case volumeDescription(volume) != volumeDescription(got):
+			differences = append(differences, DriftDifference{Field: DriftFieldVolume, Name: volume.Name, Change: "changed", Expected: volumeDescription(volume), Actual: volumeDescription(got)})
+		}
+	}
+	return differences
+}
+
+// buildDriftReport compares every pod of a workload with its template. A pod is out of date
+// when it was created from another revision or its spec differs from the template.
+func buildDriftReport(input *driftInput) WorkloadDriftReport {
+	report := WorkloadDriftReport{
+		CurrentRevision: input.currentRevision,
+		Replicas:        input.replicas,
+		Causes:          input.causes,
+		Pods:            []PodDrift{},
+	}
+	if input.currentRevision == "" {
+		report.Warnings = append(report.Warnings, "the current revision could not be determined; pods are compared by spec only")
+	}
+	for i := range input.pods {
+		pod := &input.pods[i]
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		drift := PodDrift{
+			Name:              pod.Name,
+			Node:              pod.Spec.NodeName,
+			Phase:             string(pod.Status.Phase),
+			Ready:             isPodReady(pod),
+			CreationTimestamp: pod.CreationTimestamp.Time,
+			Revision:          pod.Labels[input.revisionLabel],
+			Differences:       comparePod(input.template, pod),
+		}
+		staleRevision := input.currentRevision != "" && drift.Revision != "" && drift.Revision != input.currentRevision
+		drift.OutOfDate = staleRevision || len(drift.Differences) > 0
+		if drift.OutOfDate {
+			report.OutOfDate++
+		} else {
+			report.UpToDate++
+		}
+		report.Pods = append(report.Pods, drift)
+	}
+	sort.Slice(report.Pods, func(i, j int) bool {
+		if report.Pods[i].OutOfDate != report.Pods[j].OutOfDate {
+			return report.Pods[i].OutOfDate
+		}
+		return report.Pods[i].Name < report.Pods[j].Name
+	})
+	return report
+}
+
+// respondDriftLoadError maps a failure to load a workload to a status code
+func (h *WorkloadDriftHandler) respondDriftLoadError(c *gin.Context, namespace, name string, err error) {
+	code := http.StatusBadRequest
+	if apierrors.IsNotFound(err) {
+		code = http.StatusNotFound
+	} else if utils.IsPermissionError(err) {
+		code = http.StatusForbidden
+	}
+	h.logger.WithError(err).WithField("workload", namespace+"/"+name).Error("Failed to load workload for drift scan")
+	utils.RespondError(c, code, err)
+}
+
+// GetWorkloadDrift compares the running pods of a workload with its current template
+// @Summary Get workload replica drift
+// @Description Compares every pod of a deployment, statefulset or daemonset with the workload's current pod template: the revision the pod was created from, container images, environment variables (by value or source; literal values are not returned), volume mounts and volume sources. Pods created from an older revision or whose spec differs are reported as out of date, with the reasons the controller is not replacing them such as a paused rollout, an exceeded progress deadline, an OnDelete update strategy or a statefulset partition. Only what the template declares is compared, so containers and variables injected by admission webhooks are not reported.
+// @Tags Workloads
+// @Produce json
+// @Param kind path string true "Workload kind: deployments, statefulsets or daemonsets"
+// @Param namespace path string true "Namespace name"
+// @Param name path string true "Workload name"
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Success 200 {object} WorkloadDriftReport "Drift report"
+// @Failure 400 {object} map[string]string "Bad request - invalid kind"
+// @Failure 404 {object} map[string]string "Workload not found"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/workloads/{kind}/{namespace}/{name}/drift [get]
+func (h *WorkloadDriftHandler) GetWorkloadDrift(c *gin.Context) {
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for workload drift")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	kind, namespace, name := c.Param("kind"), c.Param("namespace"), c.Param("name")
+	input, err := loadDriftInput(c.Request.Context(), client, kind, namespace, name)
+	if err != nil {
+		h.respondDriftLoadError(c, namespace, name, err)
+		return
+	}
+
+	report := buildDriftReport(input)
+	report.Kind, report.Namespace, report.Name = kind, namespace, name
+	c.JSON(http.StatusOK, report)
+}
+
+// DeleteStalePods deletes out-of-date pods of a workload so they are recreated from its template
+// @Summary Delete out-of-date workload pods
+// @Description Deletes the pods of a workload that the drift scan reports as out of date, all of them or the named ones, so the controller recreates them from the current template. Named pods that are up to date or no longer exist are skipped. Pods of a deployment belong to the ReplicaSet of their revision, which recreates them with the old spec until the rollout progresses; a warning says so.
+// @Tags Workloads
+// @Accept json
+// @Produce json
+// @Param kind path string true "Workload kind: deployments, statefulsets or daemonsets"
+// @Param namespace path string true "Namespace name"
+// @Param name path string true "Workload name"
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param request body StalePodsDeleteRequest false "Pods to delete and dry run"
+// @Success 200 {object} StalePodsDeleteResponse "Deletion result"
+// @Failure 400 {object} map[string]string "Bad request - invalid kind or body"
+// @Failure 404 {object} map[string]string "Workload not found"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/workloads/{kind}/{namespace}/{name}/drift/delete-stale [post]
+func (h *WorkloadDriftHandler) DeleteStalePods(c *gin.Context) {
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for stale pod deletion")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	var req StalePodsDeleteRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.RespondError(c, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	kind, namespace, name := c.Param("kind"), c.Param("namespace"), c.Param("name")
+	input, err := loadDriftInput(ctx, client, kind, namespace, name)
+	if err != nil {
+		h.respondDriftLoadError(c, namespace, name, err)
+		return
+	}
+	report := buildDriftReport(input)
+
+	stale := make(map[string]bool, report.OutOfDate)
+	for _, pod := range report.Pods {
+		if pod.OutOfDate {
+			stale[pod.Name] = true
+		}
+	}
+	result := StalePodsDeleteResponse{Kind: kind, Namespace: namespace, Name: name, DryRun: req.DryRun, Deleted: []string{}}
+	targets := req.Pods
+	if len(targets) == 0 {
+		for _, pod := range report.Pods {
+			if pod.OutOfDate {
+				targets = append(targets, pod.Name)
+			}
+		}
+	}
+	if kind == workloadKindDeployments && len(stale) > 0 {
+		result.Warnings = append(result.Warnings, "deleted pods are recreated by the ReplicaSet they belong to; pods of an old revision keep the old spec until the rollout progresses")
+	}
+
+	for _, pod := range targets {
+		if !stale[pod] {
+			result.Skipped = append(result.Skipped, pod)
+			continue
+		}
+		if req.DryRun {
+			result.Deleted = append(result.Deleted, pod)
+			continue
+		}
+		if err := client.CoreV1().Pods(namespace).Delete(ctx, pod, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			h.logger.WithError(err).WithField("pod", namespace+"/"+pod).Error("Failed to delete out-of-date pod")
+			result.Failures = append(result.Failures, StalePodFailure{Name: pod, Message: err.Error()})
+			continue
+		}
+		result.Deleted = append(result.Deleted, pod)
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,158 @@
+package workloads
+
+import (
+	"context"
+	"testing"
+
+	appsV1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+func driftTemplate() v1.PodTemplateSpec {
+	return v1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "web"}},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{
+				Name:  "web",
+				Image: "app:v2",
+				Env: []v1.EnvVar{
+					{Name: "MODE", Value: "fast"},
+					{Name: "TOKEN", ValueFrom: &v1.EnvVarSource{SecretKeyRef: &v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "web-v2"}, Key: "token"}}},
+				},
+				VolumeMounts: []v1.VolumeMount{{Name: "config", MountPath: "/etc/web"}},
+			}},
+			Volumes: []v1.Volume{{Name: "config", VolumeSource: v1.VolumeSource{ConfigMap: &v1.ConfigMapVolumeSource{LocalObjectReference: v1.LocalObjectReference{Name: "web-config-v2"}}}}},
+		},
+	}
+}
+
+func TestComparePod(t *testing.T) {
+	template := driftTemplate()
+	current := &v1.Pod{Spec: *template.Spec.DeepCopy()}
+	// Injected sidecars and variables are not drift
+	current.Spec.Containers[0].Env = append(current.Spec.Containers[0].Env, v1.EnvVar{Name: "INJECTED", Value: "1"})
+	current.Spec.Containers = append(current.Spec.Containers, v1.Container{Name: "proxy", Image: "proxy:1"})
+	if differences := comparePod(&template, current); len(differences) != 0 {
+		t.Errorf("current pod differences = %+v, want none", differences)
+	}
+
+	old := &v1.Pod{Spec: *template.Spec.DeepCopy()}
+	old.Spec.Containers[0].Image = "app:v1"
+	old.Spec.Containers[0].Env = []v1.EnvVar{
+		{Name: "MODE", Value: "slow"},
+		{Name: "TOKEN", ValueFrom: &v1.EnvVarSource{SecretKeyRef: &v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "web-v1"}, Key: "token"}}},
+	}
+	old.Spec.Containers[0].VolumeMounts[0].ReadOnly = true
+	old.Spec.Volumes[0].ConfigMap.Name = "web-config-v1"
+
+	differences := comparePod(&template, old)
+	want := map[string]DriftDifference{
+		DriftFieldImage:       {Container: "web", Field: DriftFieldImage, Change: "changed", Expected: "app:v2", Actual: "app:v1"},
+		"env MODE":            {Container: "web", Field: DriftFieldEnv, Name: "MODE", Change: "changed", Expected: "value", Actual: "value"},
+		"env TOKEN":           {Container: "web", Field: DriftFieldEnv, Name: "TOKEN", Change: "changed", Expected: "secret web-v2/token", Actual: "secret web-v1/token"},
+		DriftFieldVolumeMount: {Container: "web", Field: DriftFieldVolumeMount, Name: "/etc/web", Change: "changed", Expected: "config", Actual: "config (read-only)"},
+		DriftFieldVolume:      {Field: DriftFieldVolume, Name: "config", Change: "changed", Expected: "configmap web-config-v2", Actual: "configmap web-config-v1"},
+	}
+	if len(differences) != len(want) {
+		t.Fatalf("differences = %+v, want %d", differences, len(want))
+	}
+	for _, difference := range differences {
+		key := difference.Field
+		if key == DriftFieldEnv {
+			key += " " + difference.Name
+		}
+		if difference != want[key] {
+			t.Errorf("difference %s = %+v, want %+v", key, difference, want[key])
+		}
+	}
+
+	missing := &v1.Pod{}
+	if differences := comparePod(&template, missing); len(differences) != 2 || differences[0].Field != DriftFieldContainer {
+		t.Errorf("differences of a pod without the container = %+v", differences)
+	}
+}
+
+func TestLoadDriftInputDeployment(t *testing.T) {
+	template := driftTemplate()
+	deployment := &appsV1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", UID: "dep-uid", Annotations: map[string]string{revisionAnnotation: "2"}},
+		Spec: appsV1.DeploymentSpec{
+			Replicas: ptr.To[int32](2),
+			Paused:   true,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			Template: template,
+		},
+	}
+	owner := []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", UID: "dep-uid", Controller: ptr.To(true)}}
+	replicaSet := func(hash, revision string) *appsV1.ReplicaSet {
+		return &appsV1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+			Name: "web-" + hash, Namespace: "shop", OwnerReferences: owner,
+			Labels:      map[string]string{"app": "web", appsV1.DefaultDeploymentUniqueLabelKey: hash},
+			Annotations: map[string]string{revisionAnnotation: revision},
+		}}
+	}
+	pod := func(name, hash, image string) *v1.Pod {
+		spec := *template.Spec.DeepCopy()
+		spec.Containers[0].Image = image
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", Labels: map[string]string{"app": "web", appsV1.DefaultDeploymentUniqueLabelKey: hash}},
+			Spec:       spec,
+		}
+	}
+	client := fake.NewSimpleClientset(deployment, replicaSet("aaa", "1"), replicaSet("bbb", "2"),
+		pod("web-bbb-1", "bbb", "app:v2"), pod("web-aaa-1", "aaa", "app:v1"), pod("web-aaa-2", "aaa", "app:v2"))
+
+	input, err := loadDriftInput(context.Background(), client, workloadKindDeployments, "shop", "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if input.currentRevision != "bbb" || len(input.causes) != 1 {
+		t.Fatalf("current revision = %q with causes %v, want bbb and a paused rollout", input.currentRevision, input.causes)
+	}
+
+	report := buildDriftReport(input)
+	if report.OutOfDate != 2 || report.UpToDate != 1 {
+		t.Fatalf("report = %+v, want 2 out-of-date pods and 1 up to date", report)
+	}
+	if report.Pods[0].Name != "web-aaa-1" || len(report.Pods[0].Differences) != 1 {
+		t.Errorf("first pod = %+v, want web-aaa-1 with an image difference", report.Pods[0])
+	}
+	// Same spec, old revision
+	if report.Pods[1].Name != "web-aaa-2" || !report.Pods[1].OutOfDate || len(report.Pods[1].Differences) != 0 {
+		t.Errorf("second pod = %+v, want web-aaa-2 out of date by revision only", report.Pods[1])
+	}
+
+	if _, err := loadDriftInput(context.Background(), client, "jobs", "shop", "web"); err == nil {
+		t.Error("loadDriftInput() accepted an unsupported kind")
+	}
+}
+
+func TestLoadDriftInputStatefulSet(t *testing.T) {
+	template := driftTemplate()
+	sts := &appsV1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "shop"},
+		Spec: appsV1.StatefulSetSpec{
+			Selector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			Template:       template,
+			UpdateStrategy: appsV1.StatefulSetUpdateStrategy{Type: appsV1.OnDeleteStatefulSetStrategyType},
+		},
+		Status: appsV1.StatefulSetStatus{UpdateRevision: "db-2"},
+	}
+	pods := []*v1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "db-0", Namespace: "shop", Labels: map[string]string{"app": "web", appsV1.ControllerRevisionHashLabelKey: "db-1"}}, Spec: template.Spec},
+		{ObjectMeta: metav1.ObjectMeta{Name: "db-1", Namespace: "shop", Labels: map[string]string{"app": "web", appsV1.ControllerRevisionHashLabelKey: "db-2"}}, Spec: template.Spec},
+	}
+	client := fake.NewSimpleClientset(sts, pods[0], pods[1])
+
+	input, err := loadDriftInput(context.Background(), client, workloadKindStatefulSets, "shop", "db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := buildDriftReport(input)
+	if report.OutOfDate != 1 || report.Pods[0].Name != "db-0" || len(report.Causes) != 1 {
+		t.Errorf("report = %+v, want db-0 out of date because of OnDelete", report)
+	}
+}
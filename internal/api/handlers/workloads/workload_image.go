@@ -0,0 +1,656 @@
+package workloads
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/registry"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/internal/tracing"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	appsV1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// changeCauseAnnotation records why a workload revision was created, shown in rollout history
+const changeCauseAnnotation = "kubernetes.io/change-cause"
+
+// Workload kinds whose images can be updated and rollouts tracked, as used in routes
+const (
+	workloadKindDeployments  = "deployments"
+	workloadKindStatefulSets = "statefulsets"
+	workloadKindDaemonSets   = "daemonsets"
+)
+
+// Rollout phases reported by the rollout status stream
+const (
+	RolloutPhasePending     = "pending"     // the controller has not yet seen the new spec
+	RolloutPhaseProgressing = "progressing" // pods are being replaced
+	RolloutPhaseComplete    = "complete"
+	RolloutPhaseFailed      = "failed"     // the deployment exceeded its progress deadline
+	RolloutPhaseSuperseded  = "superseded" // a newer change replaced the tracked generation
+)
+
+const (
+	// rolloutPollInterval is how often the rollout status stream re-reads the workload
+	rolloutPollInterval = 2 * time.Second
+	// maxRolloutStream bounds how long one rollout status stream stays open
+	maxRolloutStream = 30 * time.Minute
+)
+
+var (
+	// imageNameComponent is one path component of a repository name
+	imageNameComponent = regexp.MustCompile(`^[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*$`)
+	// imageRegistryHost is a registry host with an optional port
+	imageRegistryHost = regexp.MustCompile(`^(?:[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)*(?::[0-9]+)?$`)
+	imageTag          = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+	imageDigest       = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9a-fA-F]{32,}$`)
+)
+
+// ImageUpdateRequest selects a container and its new image. Either image replaces the whole
+// reference, or tag and/or digest replace those parts of the current image.
+type ImageUpdateRequest struct {
+	Container   string `json:"container"`
+	Image       string `json:"image,omitempty"`
+	Tag         string `json:"tag,omitempty"`
+	Digest      string `json:"digest,omitempty"`
+	ChangeCause string `json:"changeCause,omitempty"` // defaults to a description of the change
+	Verify      bool   `json:"verify,omitempty"`      // check the image exists in its registry first
+}
+
+// RolloutHandle identifies the rollout started by an image update
+type RolloutHandle struct {
+	Kind          string                  `json:"kind"`
+	Namespace     string                  `json:"namespace"`
+	Name          string                  `json:"name"`
+	Container     string                  `json:"container"`
+	PreviousImage string                  `json:"previousImage"`
+	Image         string                  `json:"image"`
+	Reference     registry.Reference      `json:"reference"`
+	ChangeCause   string                  `json:"changeCause"`
+	Generation    int64                   `json:"generation"` // the generation the rollout status stream tracks
+	Probe         *registry.ManifestProbe `json:"probe,omitempty"`
+	StatusURL     string                  `json:"statusUrl"`
+}
+
+// RolloutStatus is the progress of a workload towards its latest spec
+type RolloutStatus struct {
+	Kind               string `json:"kind"`
+	Namespace          string `json:"namespace"`
+	Name               string `json:"name"`
+	Generation         int64  `json:"generation"`
+	ObservedGeneration int64  `json:"observedGeneration"`
+	Phase              string `json:"phase"`
+	Message            string `json:"message"`
+	Desired            int32  `json:"desired"`
+	Updated            int32  `json:"updated"`
+	Ready              int32  `json:"ready"`
+	Available          int32  `json:"available"`
+	Done               bool   `json:"done"`
+}
+
+// WorkloadImagesHandler updates workload container images and streams the resulting rollouts
+type WorkloadImagesHandler struct {
+	store          *storage.KubeConfigStore
+	clientFactory  *k8s.ClientFactory
+	logger         *logger.Logger
+	tracingHelper  *tracing.TracingHelper
+	registryClient *registry.Client
+}
+
+// NewWorkloadImagesHandler creates a new workload images handler
+func NewWorkloadImagesHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger) *WorkloadImagesHandler {
+	return &WorkloadImagesHandler{
+		store:          store,
+		clientFactory:  clientFactory,
+		logger:         log,
+		tracingHelper:  tracing.GetTracingHelper(),
+		registryClient: registry.NewClient(10 * time.Second),
+	}
+}
+
+// getClientAndConfig gets the Kubernetes client for the current request
+func (h *WorkloadImagesHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
+	configID := c.Query("config")
+	cluster := c.Query("cluster")
+
+	if configID == "" {
+		return nil, fmt.Errorf("config parameter is required")
+	}
+
+	config, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, fmt.Errorf("config not found: %w", err)
+	}
+
+	client, err := h.clientFactory.GetClientForConfig(config, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Kubernetes client: %w", err)
+	}
+
+	return client, nil
+}
+
+// validateImageReference checks an image against the distribution reference grammar, which
+// ParseReference does not, so that typos fail here rather than as InvalidImageName pods
+func validateImageReference(image string) error {
+	if image == "" {
+		return fmt.Errorf("image is required")
+	}
+	if strings.ContainsAny(image, " \t\n") {
+		return fmt.Errorf("image %q contains whitespace", image)
+	}
+	rest, digest, hasDigest := strings.Cut(image, "@")
+	if hasDigest && !imageDigest.MatchString(digest) {
+		return fmt.Errorf("invalid digest %q", digest)
+	}
+	if i := strings.LastIndex(rest, ":"); i > strings.LastIndex(rest, "/") {
+		if tag := rest[i+1:]; !imageTag.MatchString(tag) {
+			return fmt.Errorf("invalid tag %q", tag)
+		}
+		rest = rest[:i]
+	}
+	components := strings.Split(rest, "/")
+	if first := components[0]; len(components) > 1 && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		if !imageRegistryHost.MatchString(first) {
+			return fmt.Errorf("invalid registry host %q", first)
+		}
+		components = components[1:]
+	}
+	for _, component := range components {
+		if !imageNameComponent.MatchString(component) {
+			return fmt.Errorf("invalid repository %q: path components must be lowercase letters, digits and separators", rest)
+		}
+	}
+	return nil
+}
+
+// imageRepository strips the tag and digest from an image, keeping it as written
+func imageRepository(image string) string {
+	rest, _, _ := strings.Cut(image, "@")
+	if i := strings.LastIndex(rest, ":"); i > strings.LastIndex(rest, "/") {
+		rest = rest[:i]
+	}
+	return rest
+}
+
+// resolveNewImage builds the new image of a container from the request and its current image
+func resolveNewImage(req ImageUpdateRequest, current string) (string, error) {
+	image := strings.TrimSpace(req.Image)
+	if image != "" && (req.Tag != "" || req.Digest != "") {
+		return "", fmt.Errorf("set either image or tag/digest, not both")
+	}
+	if image == "" {
+		if req.Tag == "" && req.Digest == "" {
+			return "", fmt.Errorf("image, tag or digest is required")
+		}
+		image = imageRepository(current)
+		if req.Tag != "" {
+			image += ":" + strings.TrimSpace(req.Tag)
+		}
+		if req.Digest != "" {
+			image += "@" + strings.TrimSpace(req.Digest)
+		}
+	}
+	if err := validateImageReference(image); err != nil {
+		return "", err
+	}
+	return image, nil
+}
+
+// workloadTemplate reads the pod template of a workload
+func workloadTemplate(ctx context.Context, client kubernetes.Interface, kind, namespace, name string) (*v1.PodTemplateSpec, error) {
+	switch kind {
+	case workloadKindDeployments:
+		obj, err := client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &obj.Spec.Template, nil
+	case workloadKindStatefulSets:
+		obj, err := client.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &obj.Spec.Template, nil
+	case workloadKindDaemonSets:
+		obj, err := client.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &obj.Spec.Template, nil
+	}
+	return nil, fmt.Errorf("unsupported workload kind %q; use deployments, statefulsets or daemonsets", kind)
+}
+
+// imagePatch builds a strategic merge patch setting one container's image and the change cause
+func imagePatch(template *v1.PodTemplateSpec, container, image, changeCause string) ([]byte, string, error) {
+	for _, list := range []struct {
+		field      string
+		containers []v1.Container
+	}{{"containers", template.Spec.Containers}, {"initContainers", template.Spec.InitContainers}} {
+		for _, existing := range list.containers {
+			if existing.Name != container {
+				continue
+			}
+			patch, err := json.Marshal(map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]string{changeCauseAnnotation: changeCause},
+				},
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							list.field: []map[string]string{{"name": container, "image": image}},
+						},
+					},
+				},
+			})
+			return patch, existing.Image, err
+		}
+	}
+	return nil, "", fmt.Errorf("container %q not found", container)
+}
+
+// patchWorkload applies a strategic merge patch and returns the workload's new generation
+func patchWorkload(ctx context.Context, client kubernetes.Interface, kind, namespace, name string, patch []byte) (int64, error) {
+	var meta metav1.Object
+	var err error
+	switch kind {
+	case workloadKindDeployments:
+		meta, err = client.AppsV1().Deployments(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case workloadKindStatefulSets:
+		meta, err = client.AppsV1().StatefulSets(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case workloadKindDaemonSets:
+		meta, err = client.AppsV1().DaemonSets(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	default:
+		return 0, fmt.Errorf("unsupported workload kind %q", kind)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return meta.GetGeneration(), nil
+}
+
+// deploymentRolloutStatus mirrors kubectl rollout status for a Deployment
+func deploymentRolloutStatus(d *appsV1.Deployment) RolloutStatus {
+	status := RolloutStatus{
+		Generation:         d.Generation,
+		ObservedGeneration: d.Status.ObservedGeneration,
+		Desired:            1,
+		Updated:            d.Status.UpdatedReplicas,
+		Ready:              d.Status.ReadyReplicas,
+		Available:          d.Status.AvailableReplicas,
+	}
+	if d.Spec.Replicas != nil {
+		status.Desired = *d.Spec.Replicas
+	}
+	if d.Generation > d.Status.ObservedGeneration {
+		status.Phase, status.Message = RolloutPhasePending, "waiting for the deployment spec update to be observed"
+		return status
+	}
+	for _, cond := range d.Status.Conditions {
+		if cond.Type == appsV1.DeploymentProgressing && cond.Reason == "ProgressDeadlineExceeded" {
+			status.Phase, status.Message = RolloutPhaseFailed, fmt.Sprintf("deployment %q exceeded its progress deadline", d.Name)
+			return status
+		}
+	}
+	status.Phase = RolloutPhaseProgressing
+	switch {
+	case d.Status.UpdatedReplicas < status.Desired:
+		status.Message = fmt.Sprintf("%d of %d new replicas have been updated", d.Status.UpdatedReplicas, status.Desired)
+	case d.Status.Replicas > d.Status.UpdatedReplicas:
+		status.Message = fmt.Sprintf("%d old replicas are pending termination", d.Status.Replicas-d.Status.UpdatedReplicas)
+	case d.Status.AvailableReplicas < d.Status.UpdatedReplicas:
+		status.Message = fmt.Sprintf("%d of %d updated replicas are available", d.Status.AvailableReplicas, d.Status.UpdatedReplicas)
+	default:
+		status.Phase, status.Message = RolloutPhaseComplete, fmt.Sprintf("deployment %q successfully rolled out", d.Name)
+	}
+	return status
+}
+
+// statefulSetRolloutStatus mirrors kubectl rollout status for a StatefulSet, honouring partitions
+func statefulSetRolloutStatus(s *appsV1.StatefulSet) RolloutStatus {
+	status := RolloutStatus{
+		Generation:         s.Generation,
+		ObservedGeneration: s.Status.ObservedGeneration,
+		Desired:            1,
+		Updated:            s.Status.UpdatedReplicas,
+		Ready:              s.Status.ReadyReplicas,
+		Available:          s.Status.AvailableReplicas,
+	}
+	if s.Spec.Replicas != nil {
+		status.Desired = *s.Spec.Replicas
+	}
+	if s.Status.ObservedGeneration == 0 || s.Generation > s.Status.ObservedGeneration {
+		status.Phase, status.Message = RolloutPhasePending, "waiting for the statefulset spec update to be observed"
+		return status
+	}
+	status.Phase = RolloutPhaseProgressing
+	if s.Status.ReadyReplicas < status.Desired {
+		status.Message = fmt.Sprintf("%d of %d pods are ready", s.Status.ReadyReplicas, status.Desired)
+		return status
+	}
+	if s.Spec.UpdateStrategy.Type == appsV1.RollingUpdateStatefulSetStrategyType && s.Spec.UpdateStrategy.RollingUpdate != nil &&
+		s.Spec.UpdateStrategy.RollingUpdate.Partition != nil && *s.Spec.UpdateStrategy.RollingUpdate.Partition > 0 {
+		partition := *s.Spec.UpdateStrategy.RollingUpdate.Partition
+		if s.Status.UpdatedReplicas < status.Desired-partition {
+			status.Message = fmt.Sprintf("%d of %d pods above partition %d have been updated", s.Status.UpdatedReplicas, status.Desired-partition, partition)
+			return status
+		}
+		status.Phase, status.Message = RolloutPhaseComplete, fmt.Sprintf("partitioned roll out complete: %d new pods have been updated", s.Status.UpdatedReplicas)
+		return status
+	}
+	if s.Status.UpdateRevision != s.Status.CurrentRevision {
+		status.Message = fmt.Sprintf("%d of %d pods have been updated to revision %s", s.Status.UpdatedReplicas, status.Desired, s.Status.UpdateRevision)
+		return status
+	}
+	status.Phase, status.Message = RolloutPhaseComplete, fmt.Sprintf("statefulset %q rolled out at revision %s", s.Name, s.Status.CurrentRevision)
+	return status
+}
+
+// daemonSetRolloutStatus mirrors kubectl rollout status for a DaemonSet
+func daemonSetRolloutStatus(d *appsV1.DaemonSet) RolloutStatus {
+	status := RolloutStatus{
+		Generation:         d.Generation,
+		ObservedGeneration: d.Status.ObservedGeneration,
+		Desired:            d.Status.DesiredNumberScheduled,
+		Updated:            d.Status.UpdatedNumberScheduled,
+		Ready:              d.Status.NumberReady,
+		Available:          d.Status.NumberAvailable,
+	}
+	if d.Generation > d.Status.ObservedGeneration {
+		status.Phase, status.Message = RolloutPhasePending, "waiting for the daemonset spec update to be observed"
+		return status
+	}
+	status.Phase = RolloutPhaseProgressing
+	switch {
+	case d.Status.UpdatedNumberScheduled < d.Status.DesiredNumberScheduled:
+		status.Message = fmt.Sprintf("%d of %d updated pods have been scheduled", d.Status.UpdatedNumberScheduled, d.Status.DesiredNumberScheduled)
+	case d.Status.NumberAvailable < d.Status.DesiredNumberScheduled:
+		status.Message = fmt.Sprintf("%d of %d updated pods are available", d.Status.NumberAvailable, d.Status.DesiredNumberScheduled)
+	default:
+		status.Phase, status.Message = RolloutPhaseComplete, fmt.Sprintf("daemonset %q successfully rolled out", d.Name)
+	}
+	return status
+}
+
+// getRolloutStatus reads a workload and reports its rollout progress. A generation newer than
+// the tracked one means another change superseded the tracked rollout.
+func getRolloutStatus(ctx context.Context, client kubernetes.Interface, kind, namespace, name string, generation int64) (RolloutStatus, error) {
+	var status RolloutStatus
+	switch kind {
+	case workloadKindDeployments:
+		obj, err := client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return status, err
+		}
+		status = deploymentRolloutStatus(obj)
+	case workloadKindStatefulSets:
+		obj, err := client.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return status, err
+		}
+		status = statefulSetRolloutStatus(obj)
+	case workloadKindDaemonSets:
+		obj, err := client.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return status, err
+		}
+		status = daemonSetRolloutStatus(obj)
+	default:
+		return status, fmt.Errorf("unsupported workload kind %q; use deployments, statefulsets or daemonsets", kind)
+	}
+	status.Kind, status.Namespace, status.Name = kind, namespace, name
+	if generation > 0 && status.Generation > generation {
+		status.Phase = RolloutPhaseSuperseded
+		status.Message = fmt.Sprintf("generation %d was replaced by generation %d", generation, status.Generation)
+	}
+	status.Done = status.Phase == RolloutPhaseComplete || status.Phase == RolloutPhaseFailed || status.Phase == RolloutPhaseSuperseded
+	return status, nil
+}
+
+// rolloutStatusURL is the rollout status stream of a workload at a generation
+func rolloutStatusURL(c *gin.Context, kind, namespace, name string, generation int64) string {
+	query := url.Values{}
+	query.Set("config", c.Query("config"))
+	if cluster := c.Query("cluster"); cluster != "" {
+		query.Set("cluster", cluster)
+	}
+	query.Set("generation", strconv.FormatInt(generation, 10))
+	return fmt.Sprintf("/api/v1/workloads/%s/%s/%s/rollout-status?%s", kind, namespace, name, query.Encode())
+}
+
+// UpdateWorkloadImage sets the image of one container in a Deployment, StatefulSet or DaemonSet
+// @Summary Update workload image
+// @Description Sets the image of one container (or init container) of a Deployment, StatefulSet or DaemonSet, either as a whole image reference or by replacing the tag and/or digest of the current image. The image is validated against the image reference grammar and, with verify, checked for existence in its registry using the pod template's pull secrets. The change is recorded in the kubernetes.io/change-cause annotation. The response is a rollout handle whose statusUrl streams the rollout's progress.
+// @Tags Workloads
+// @Accept json
+// @Produce json
+// @Param kind path string true "Workload kind" Enums(deployments, statefulsets, daemonsets)
+// @Param namespace path string true "Namespace name"
+// @Param name path string true "Workload name"
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param body body ImageUpdateRequest true "Container and new image"
+// @Success 200 {object} RolloutHandle "Image updated; rollout started"
+// @Failure 400 {object} map[string]string "Bad request - invalid kind, container or image"
+// @Failure 404 {object} map[string]string "Workload not found"
+// @Failure 422 {object} map[string]interface{} "Image not found in its registry"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/workloads/{kind}/{namespace}/{name}/image [put]
+func (h *WorkloadImagesHandler) UpdateWorkloadImage(c *gin.Context) {
+	ctx, clientSpan := h.tracingHelper.StartAuthSpan(c.Request.Context(), "get-client-config")
+	defer clientSpan.End()
+
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for workload image update")
+		h.tracingHelper.RecordError(clientSpan, err, "Failed to get Kubernetes client")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	h.tracingHelper.RecordSuccess(clientSpan, "Kubernetes client obtained")
+
+	kind, namespace, name := c.Param("kind"), c.Param("namespace"), c.Param("name")
+	var req ImageUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+	if req.Container == "" {
+		utils.RespondErrorMessage(c, http.StatusBadRequest, "container is required")
+		return
+	}
+
+	_, getSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "get", kind, namespace)
+	template, err := workloadTemplate(c.Request.Context(), client, kind, namespace, name)
+	if err != nil {
+		h.tracingHelper.RecordError(getSpan, err, "Failed to get workload")
+		getSpan.End()
+		status := http.StatusBadRequest
+		if apierrors.IsNotFound(err) {
+			status = http.StatusNotFound
+		}
+		utils.RespondError(c, status, err)
+		return
+	}
+	h.tracingHelper.RecordSuccess(getSpan, "Workload retrieved")
+	getSpan.End()
+
+	var current string
+	for _, container := range append(append([]v1.Container{}, template.Spec.Containers...), template.Spec.InitContainers...) {
+		if container.Name == req.Container {
+			current = container.Image
+		}
+	}
+	if current == "" {
+		utils.RespondErrorMessage(c, http.StatusBadRequest, fmt.Sprintf("container %q not found", req.Container))
+		return
+	}
+	image, err := resolveNewImage(req, current)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	if req.ChangeCause == "" {
+		req.ChangeCause = fmt.Sprintf("set image %s=%s", req.Container, image)
+	}
+	patch, previous, err := imagePatch(template, req.Container, image, req.ChangeCause)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	handle := RolloutHandle{
+		Kind:          kind,
+		Namespace:     namespace,
+		Name:          name,
+		Container:     req.Container,
+		PreviousImage: previous,
+		Image:         image,
+		Reference:     registry.ParseReference(image),
+		ChangeCause:   req.ChangeCause,
+	}
+	if req.Verify {
+		_, verifySpan := h.tracingHelper.StartDataProcessingSpan(ctx, "verify-image")
+		probeCtx, cancel := context.WithTimeout(c.Request.Context(), 20*time.Second)
+		pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace}, Spec: template.Spec}
+		_, creds, _ := resolvePullSecrets(probeCtx, client, pod, handle.Reference.Registry)
+		probe := h.registryClient.ProbeManifest(probeCtx, handle.Reference, creds)
+		cancel()
+		verifySpan.End()
+		handle.Probe = &probe
+		if probe.StatusCode != http.StatusOK {
+			response := utils.NewErrorResponse(c, http.StatusUnprocessableEntity, fmt.Errorf("image %s could not be verified", image))
+			c.JSON(response.Status, struct {
+				utils.ErrorResponse
+				Probe registry.ManifestProbe `json:"probe"`
+			}{response, probe})
+			return
+		}
+	}
+
+	_, patchSpan := h.tracingHelper.StartKubernetesAPISpan(ctx, "patch", kind, namespace)
+	defer patchSpan.End()
+	generation, err := patchWorkload(c.Request.Context(), client, kind, namespace, name, patch)
+	if err != nil {
+		h.logger.WithError(err).WithField("workload", name).WithField("namespace", namespace).Error("Failed to update workload image")
+		h.tracingHelper.RecordError(patchSpan, err, "Failed to patch workload")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	h.tracingHelper.RecordSuccess(patchSpan, fmt.Sprintf("Set image %s=%s", req.Container, image))
+
+	handle.Generation = generation
+	handle.StatusURL = rolloutStatusURL(c, kind, namespace, name, generation)
+	c.JSON(http.StatusOK, handle)
+}
+
+// GetWorkloadRolloutStatus streams the rollout progress of a workload
+// @Summary Stream workload rollout status
+// @Description Streams the rollout progress of a Deployment, StatefulSet or DaemonSet as Server-Sent Events, like kubectl rollout status. A status is sent whenever it changes; the stream ends with a done event once the rollout completes, fails, or is superseded by a generation newer than the one tracked.
+// @Tags Workloads
+// @Produce text/event-stream
+// @Param kind path string true "Workload kind" Enums(deployments, statefulsets, daemonsets)
+// @Param namespace path string true "Namespace name"
+// @Param name path string true "Workload name"
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param generation query int false "Generation to track, from the rollout handle of an image update"
+// @Success 200 {object} RolloutStatus "Rollout status events"
+// @Failure 400 {object} map[string]string "Bad request - invalid kind or generation"
+// @Failure 404 {object} map[string]string "Workload not found"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/workloads/{kind}/{namespace}/{name}/rollout-status [get]
+func (h *WorkloadImagesHandler) GetWorkloadRolloutStatus(c *gin.Context) {
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for rollout status")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	kind, namespace, name := c.Param("kind"), c.Param("namespace"), c.Param("name")
+	var generation int64
+	if raw := c.Query("generation"); raw != "" {
+		if generation, err = strconv.ParseInt(raw, 10, 64); err != nil || generation < 1 {
+			utils.RespondErrorMessage(c, http.StatusBadRequest, "generation must be a positive number")
+			return
+		}
+	}
+
+	status, err := getRolloutStatus(c.Request.Context(), client, kind, namespace, name, generation)
+	if err != nil {
+		code := http.StatusBadRequest
+		if apierrors.IsNotFound(err) {
+			code = http.StatusNotFound
+		}
+		utils.RespondError(c, code, err)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache, no-store, must-revalidate")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), maxRolloutStream)
+	defer cancel()
+	ticker := time.NewTicker(rolloutPollInterval)
+	defer ticker.Stop()
+
+	var last []byte
+	for {
+		payload, err := json.Marshal(status)
+		if err != nil {
+			h.logger.WithError(err).Error("Failed to marshal rollout status")
+			return
+		}
+		if status.Done {
+			c.SSEvent("done", string(payload))
+			c.Writer.Flush()
+			return
+		}
+		if string(payload) != string(last) {
+			c.Data(http.StatusOK, "text/event-stream", []byte("data: "+string(payload)+"\n\n"))
+			c.Writer.Flush()
+			last = payload
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		next, err := getRolloutStatus(ctx, client, kind, namespace, name, generation)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			h.logger.WithError(err).WithField("workload", name).Warn("Failed to read rollout status")
+			c.SSEvent("error", gin.H{"error": err.Error()})
+			c.Writer.Flush()
+			if apierrors.IsNotFound(err) {
+				return
+			}
+			continue
+		}
+		status = next
+	}
+}
@@ -0,0 +1,183 @@
+package workloads
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	appsV1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestValidateImageReference(t *testing.T) {
+	valid := []string{
+		"nginx",
+		"nginx:1.27-alpine",
+		"library/nginx@sha256:" + strings.Repeat("a", 64),
+		"registry.example.com:5000/team/app:v2.1.0",
+		"localhost/app_x__y:latest",
+		"ghcr.io/org/app:1.0@sha256:" + strings.Repeat("0", 64),
+	}
+	for _, image := range valid {
+		if err := validateImageReference(image); err != nil {
+			t.Errorf("validateImageReference(%q) = %v", image, err)
+		}
+	}
+	invalid := []string{"", "Nginx", "nginx:", "nginx:-bad", "app@sha256:xyz", "bad host.io/app", "reg_istry.io:x/app", "team//app"}
+	for _, image := range invalid {
+		if err := validateImageReference(image); err == nil {
+			t.Errorf("validateImageReference(%q) succeeded, want an error", image)
+		}
+	}
+}
+
+func TestResolveNewImage(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("b", 64)
+	cases := []struct {
+		req     ImageUpdateRequest
+		current string
+		want    string
+	}{
+		{ImageUpdateRequest{Image: "nginx:1.27"}, "nginx:1.25", "nginx:1.27"},
+		{ImageUpdateRequest{Tag: "v2"}, "registry.local:5000/app:v1", "registry.local:5000/app:v2"},
+		{ImageUpdateRequest{Tag: "v2"}, "app:v1@sha256:" + strings.Repeat("c", 64), "app:v2"},
+		{ImageUpdateRequest{Digest: digest}, "registry.local:5000/app", "registry.local:5000/app@" + digest},
+	}
+	for _, tc := range cases {
+		if got, err := resolveNewImage(tc.req, tc.current); err != nil || got != tc.want {
+			t.Errorf("resolveNewImage(%+v, %q) = %q, %v; want %q", tc.req, tc.current, got, err, tc.want)
+		}
+	}
+	for _, req := range []ImageUpdateRequest{{}, {Image: "app:v2", Tag: "v3"}, {Tag: "bad tag"}} {
+		if _, err := resolveNewImage(req, "app:v1"); err == nil {
+			t.Errorf("resolveNewImage(%+v) succeeded, want an error", req)
+		}
+	}
+}
+
+func TestImagePatch(t *testing.T) {
+	template := &v1.PodTemplateSpec{Spec: v1.PodSpec{
+		InitContainers: []v1.Container{{Name: "migrate", Image: "app:v1"}},
+		Containers:     []v1.Container{{Name: "web", Image: "app:v1"}},
+	}}
+	patch, previous, err := imagePatch(template, "migrate", "app:v2", "release 2")
+	if err != nil || previous != "app:v1" {
+		t.Fatalf("imagePatch() = %q, %v", previous, err)
+	}
+	var decoded struct {
+		Metadata metav1.ObjectMeta `json:"metadata"`
+		Spec     struct {
+			Template v1.PodTemplateSpec `json:"template"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(patch, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Metadata.Annotations[changeCauseAnnotation] != "release 2" ||
+		len(decoded.Spec.Template.Spec.InitContainers) != 1 || len(decoded.Spec.Template.Spec.Containers) != 0 {
+		t.Errorf("patch = %s", patch)
+	}
+	if _, _, err := imagePatch(template, "sidecar", "app:v2", ""); err == nil {
+		t.Error("imagePatch() succeeded for a missing container")
+	}
+}
+
+func TestDeploymentRolloutStatus(t *testing.T) {
+	replicas := int32(3)
+	d := &appsV1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Generation: 2},
+		Spec:       appsV1.DeploymentSpec{Replicas: &replicas},
+		Status:     appsV1.DeploymentStatus{ObservedGeneration: 1},
+	}
+	if got := deploymentRolloutStatus(d).Phase; got != RolloutPhasePending {
+		t.Errorf("unobserved phase = %s", got)
+	}
+	d.Status = appsV1.DeploymentStatus{ObservedGeneration: 2, Replicas: 4, UpdatedReplicas: 3, AvailableReplicas: 3}
+	if got := deploymentRolloutStatus(d); got.Phase != RolloutPhaseProgressing || !strings.Contains(got.Message, "pending termination") {
+		t.Errorf("status with old replicas = %+v", got)
+	}
+	d.Status.Replicas = 3
+	if got := deploymentRolloutStatus(d).Phase; got != RolloutPhaseComplete {
+		t.Errorf("rolled out phase = %s", got)
+	}
+	d.Status.Conditions = []appsV1.DeploymentCondition{{Type: appsV1.DeploymentProgressing, Reason: "ProgressDeadlineExceeded"}}
+	if got := deploymentRolloutStatus(d).Phase; got != RolloutPhaseFailed {
+		t.Errorf("deadline exceeded phase = %s", got)
+	}
+}
+
+func TestStatefulSetAndDaemonSetRolloutStatus(t *testing.T) {
+	replicas, partition := int32(4), int32(2)
+	s := &appsV1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Generation: 3},
+		Spec: appsV1.StatefulSetSpec{Replicas: &replicas, UpdateStrategy: appsV1.StatefulSetUpdateStrategy{
+			Type:          appsV1.RollingUpdateStatefulSetStrategyType,
+			RollingUpdate: &appsV1.RollingUpdateStatefulSetStrategy{Partition: &partition},
+		}},
+		Status: appsV1.StatefulSetStatus{ObservedGeneration: 3, ReadyReplicas: 4, UpdatedReplicas: 1, CurrentRevision: "db-1", UpdateRevision: "db-2"},
+	}
+	if got := statefulSetRolloutStatus(s).Phase; got != RolloutPhaseProgressing {
+		t.Errorf("partitioned phase = %s", got)
+	}
+	s.Status.UpdatedReplicas = 2
+	if got := statefulSetRolloutStatus(s).Phase; got != RolloutPhaseComplete {
+		t.Errorf("partition reached phase = %s", got)
+	}
+	s.Spec.UpdateStrategy.RollingUpdate = nil
+	if got := statefulSetRolloutStatus(s).Phase; got != RolloutPhaseProgressing {
+		t.Errorf("revision mismatch phase = %s", got)
+	}
+
+	ds := &appsV1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Generation: 5},
+		Status:     appsV1.DaemonSetStatus{ObservedGeneration: 5, DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberAvailable: 2},
+	}
+	if got := daemonSetRolloutStatus(ds).Phase; got != RolloutPhaseProgressing {
+		t.Errorf("daemonset phase = %s", got)
+	}
+	ds.Status.NumberAvailable = 3
+	if got := daemonSetRolloutStatus(ds).Phase; got != RolloutPhaseComplete {
+		t.Errorf("daemonset rolled out phase = %s", got)
+	}
+}
+
+func TestImageUpdateRollout(t *testing.T) {
+	replicas := int32(1)
+	client := fake.NewSimpleClientset(&appsV1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", Generation: 4},
+		Spec: appsV1.DeploymentSpec{Replicas: &replicas, Template: v1.PodTemplateSpec{Spec: v1.PodSpec{
+			Containers: []v1.Container{{Name: "web", Image: "shop/web:v1"}, {Name: "proxy", Image: "envoy:v1"}},
+		}}},
+	})
+	ctx := context.Background()
+	template, err := workloadTemplate(ctx, client, workloadKindDeployments, "shop", "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	patch, _, err := imagePatch(template, "web", "shop/web:v2", "set image web=shop/web:v2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := patchWorkload(ctx, client, workloadKindDeployments, "shop", "web", patch); err != nil {
+		t.Fatal(err)
+	}
+	updated, _ := client.AppsV1().Deployments("shop").Get(ctx, "web", metav1.GetOptions{})
+	containers := updated.Spec.Template.Spec.Containers
+	if len(containers) != 2 || containers[0].Image != "shop/web:v2" || containers[1].Image != "envoy:v1" {
+		t.Errorf("containers after patch = %+v", containers)
+	}
+	if updated.Annotations[changeCauseAnnotation] == "" {
+		t.Error("change cause annotation not set")
+	}
+
+	status, err := getRolloutStatus(ctx, client, workloadKindDeployments, "shop", "web", 3)
+	if err != nil || status.Phase != RolloutPhaseSuperseded || !status.Done {
+		t.Errorf("getRolloutStatus() = %+v, %v; want superseded", status, err)
+	}
+	if _, err := getRolloutStatus(ctx, client, "pods", "shop", "web", 0); err == nil {
+		t.Error("getRolloutStatus() accepted an unsupported kind")
+	}
+}
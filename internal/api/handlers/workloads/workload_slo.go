@@ -0,0 +1,513 @@
+package workloads
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	appsV1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Causes of unavailability counted against a workload SLO
+const (
+	SLOCauseRestart   = "restart"   // a container was down between termination and restart
+	SLOCauseReadiness = "readiness" // a readiness probe failed or the pod was not ready
+	SLOCauseRollout   = "rollout"   // the workload was below minimum availability, or a replaced pod was starting
+)
+
+const (
+	defaultSLOWindow    = 24 * time.Hour
+	maxSLOWindow        = 30 * 24 * time.Hour
+	defaultSLOObjective = 99.9
+	// sloTrendBuckets is the number of points in the availability trend
+	sloTrendBuckets = 24
+	// readinessProbeGap is the downtime counted for a single failed readiness probe
+	readinessProbeGap = 10 * time.Second
+)
+
+// SLOIncident is one period a workload, or one of its pods, was unavailable
+type SLOIncident struct {
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Cause   string    `json:"cause"` // restart, readiness or rollout
+	Pod     string    `json:"pod,omitempty"`
+	Message string    `json:"message,omitempty"`
+	Ongoing bool      `json:"ongoing,omitempty"`
+}
+
+// SLODowntime is the unavailable replica time of a workload per cause, in replica-seconds
+type SLODowntime struct {
+	RestartSeconds   float64 `json:"restartSeconds"`
+	ReadinessSeconds float64 `json:"readinessSeconds"`
+	RolloutSeconds   float64 `json:"rolloutSeconds"`
+	TotalSeconds     float64 `json:"totalSeconds"` // overlapping causes are counted once
+}
+
+// SLOTrendPoint is the availability of a workload over one bucket of the window
+type SLOTrendPoint struct {
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	Availability float64   `json:"availability"` // percent
+}
+
+// WorkloadSLOSummary estimates the availability of a workload over a window
+type WorkloadSLOSummary struct {
+	Kind                 string          `json:"kind"`
+	Namespace            string          `json:"namespace"`
+	Name                 string          `json:"name"`
+	Window               string          `json:"window"`
+	WindowStart          time.Time       `json:"windowStart"`
+	WindowEnd            time.Time       `json:"windowEnd"`
+	Replicas             int32           `json:"replicas"`
+	Objective            float64         `json:"objective"`            // percent
+	Availability         float64         `json:"availability"`         // percent of replica time available
+	ErrorBudgetRemaining float64         `json:"errorBudgetRemaining"` // percent of the budget the objective allows; negative when exceeded
+	Met                  bool            `json:"met"`
+	Restarts             int             `json:"restarts"` // container restarts within the window, at least
+	Downtime             SLODowntime     `json:"downtime"`
+	Incidents            []SLOIncident   `json:"incidents"`
+	Trend                []SLOTrendPoint `json:"trend"`
+	Warnings             []string        `json:"warnings,omitempty"`
+}
+
+// sloInput is what an SLO summary is estimated from
+type sloInput struct {
+	replicas int32
+	created  time.Time // workload creation; earlier parts of the window are not counted
+	// replacedPodsStartDown is true for kinds that delete a pod before starting its replacement,
+	// so the startup of pods created within the window is downtime
+	replacedPodsStartDown bool
+	pods                  []v1.Pod
+	events                []v1.Event
+	unavailableSince      *time.Time // workload below minimum availability since
+	unavailableReason     string
+}
+
+// WorkloadSLOHandler estimates workload availability from pod status and events
+type WorkloadSLOHandler struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	logger        *logger.Logger
+}
+
+// NewWorkloadSLOHandler creates a new workload SLO handler
+func NewWorkloadSLOHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger) *WorkloadSLOHandler {
+	return &WorkloadSLOHandler{
+		store:         store,
+		clientFactory: clientFactory,
+		logger:        log,
+	}
+}
+
+// getClientAndConfig gets the Kubernetes client for the current request
+func (h *WorkloadSLOHandler) getClientAndConfig(c *gin.Context) (kubernetes.Interface, error) {
+	configID := c.Query("config")
+	cluster := c.Query("cluster")
+
+	if configID == "" {
+		return nil, fmt.Errorf("config parameter is required")
+	}
+
+	config, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, fmt.Errorf("config not found: %w", err)
+	}
+
+	client, err := h.clientFactory.GetClientForConfig(config, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Kubernetes client: %w", err)
+	}
+
+	return client, nil
+}
+
+// parseSLOWindow parses a window such as 7d, 1w or 12h
+func parseSLOWindow(value string) (time.Duration, error) {
+	if value == "" {
+		return defaultSLOWindow, nil
+	}
+	var window time.Duration
+	unit := value[len(value)-1]
+	if unit == 'd' || unit == 'w' {
+		n, err := strconv.Atoi(strings.TrimSpace(value[:len(value)-1]))
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid window %q", value)
+		}
+		if unit == 'w' {
+			n *= 7
+		}
+		window = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return 0, fmt.Errorf("invalid window %q", value)
+		}
+		window = d
+	}
+	if window > maxSLOWindow {
+		return 0, fmt.Errorf("window must be at most 30d")
+	}
+	return window, nil
+}
+
+// loadSLOInput reads the workload, its pods and the events of its pods
+func loadSLOInput(ctx context.Context, client kubernetes.Interface, kind, namespace, name string) (*sloInput, error) {
+	input := &sloInput{}
+	var selector *metav1.LabelSelector
+	switch kind {
+	case workloadKindDeployments:
+		obj, err := client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector, input.created = obj.Spec.Selector, obj.CreationTimestamp.Time
+		input.replicas = 1
+		if obj.Spec.Replicas != nil {
+			input.replicas = *obj.Spec.Replicas
+		}
+		for _, cond := range obj.Status.Conditions {
+			if cond.Type == appsV1.DeploymentAvailable && cond.Status == v1.ConditionFalse {
+				since := cond.LastTransitionTime.Time
+				input.unavailableSince, input.unavailableReason = &since, cond.Message
+			}
+		}
+	case workloadKindStatefulSets:
+		obj, err := client.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector, input.created = obj.Spec.Selector, obj.CreationTimestamp.Time
+		input.replicas = 1
+		if obj.Spec.Replicas != nil {
+			input.replicas = *obj.Spec.Replicas
+		}
+		input.replacedPodsStartDown = true
+	case workloadKindDaemonSets:
+		obj, err := client.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector, input.created = obj.Spec.Selector, obj.CreationTimestamp.Time
+		input.replicas = obj.Status.DesiredNumberScheduled
+		input.replacedPodsStartDown = true
+	default:
+		return nil, fmt.Errorf("unsupported workload kind %q; use deployments, statefulsets or daemonsets", kind)
+	}
+
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: metav1.FormatLabelSelector(selector)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	input.pods = pods.Items
+
+	// Events of pods that no longer exist still count; they are matched by the workload's name prefix
+	events, err := client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{FieldSelector: "involvedObject.kind=Pod"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	current := make(map[string]bool, len(pods.Items))
+	for _, pod := range pods.Items {
+		current[pod.Name] = true
+	}
+	for _, event := range events.Items {
+		if current[event.InvolvedObject.Name] || strings.HasPrefix(event.InvolvedObject.Name, name+"-") {
+			input.events = append(input.events, event)
+		}
+	}
+	return input, nil
+}
+
+// sloInterval is a period one replica was unavailable
+type sloInterval struct {
+	start, end time.Time
+}
+
+// clip limits an interval to [from, to], reporting whether anything is left
+func (i sloInterval) clip(from, to time.Time) (sloInterval, bool) {
+	if i.start.Before(from) {
+		i.start = from
+	}
+	if i.end.After(to) {
+		i.end = to
+	}
+	return i, i.end.After(i.start)
+}
+
+// mergeIntervals returns the union of intervals, sorted by start
+func mergeIntervals(intervals []sloInterval) []sloInterval {
+	if len(intervals) == 0 {
+		return nil
+	}
+	sorted := append([]sloInterval(nil), intervals...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].start.Before(sorted[j].start) })
+	merged := []sloInterval{sorted[0]}
+	for _, next := range sorted[1:] {
+		last := &merged[len(merged)-1]
+		if !next.start.After(last.end) {
+			if next.end.After(last.end) {
+				last.end = next.end
+			}
+			continue
+		}
+		merged = append(merged, next)
+	}
+	return merged
+}
+
+// overlap returns the seconds of intervals within [from, to]
+func overlap(intervals []sloInterval, from, to time.Time) float64 {
+	total := 0.0
+	for _, interval := range intervals {
+		if clipped, ok := interval.clip(from, to); ok {
+			total += clipped.end.Sub(clipped.start).Seconds()
+		}
+	}
+	return total
+}
+
+// eventTimes returns the first and last time an event was seen
+func eventTimes(event v1.Event) (time.Time, time.Time) {
+	first, last := event.FirstTimestamp.Time, event.LastTimestamp.Time
+	if first.IsZero() {
+		first = event.EventTime.Time
+	}
+	if last.IsZero() {
+		last = first
+	}
+	if event.Series != nil && event.Series.LastObservedTime.After(last) {
+		last = event.Series.LastObservedTime.Time
+	}
+	return first, last
+}
+
+// buildWorkloadSLO estimates availability over [now-window, now] from pod status and events.
+// Every pod's unavailable periods are merged and summed as replica time, so availability is
+// the share of the desired replica time that was served.
+func buildWorkloadSLO(input *sloInput, window time.Duration, objective float64, now time.Time) WorkloadSLOSummary {
+	start := now.Add(-window)
+	summary := WorkloadSLOSummary{
+		Window:      window.String(),
+		WindowStart: start,
+		WindowEnd:   now,
+		Replicas:    input.replicas,
+		Objective:   objective,
+		Incidents:   []SLOIncident{},
+		Trend:       []SLOTrendPoint{},
+	}
+	counted := start
+	if input.created.After(counted) {
+		counted = input.created
+		summary.Warnings = append(summary.Warnings, "the workload was created within the window; availability is counted from its creation")
+	}
+
+	// Unavailable periods by pod, and by cause and pod
+	perPod := map[string][]sloInterval{}
+	byCause := map[string]map[string][]sloInterval{}
+	add := func(cause, pod, message string, interval sloInterval, ongoing bool) {
+		clipped, ok := interval.clip(counted, now)
+		if !ok {
+			return
+		}
+		perPod[pod] = append(perPod[pod], clipped)
+		if byCause[cause] == nil {
+			byCause[cause] = map[string][]sloInterval{}
+		}
+		byCause[cause][pod] = append(byCause[cause][pod], clipped)
+		summary.Incidents = append(summary.Incidents, SLOIncident{Start: clipped.start, End: clipped.end, Cause: cause, Pod: pod, Message: message, Ongoing: ongoing})
+	}
+
+	for _, pod := range input.pods {
+		for _, status := range append(append([]v1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...) {
+			terminated := status.LastTerminationState.Terminated
+			if terminated == nil || terminated.FinishedAt.IsZero() {
+				continue
+			}
+			if terminated.FinishedAt.After(start) {
+				summary.Restarts++
+			}
+			end, ongoing := now, true
+			if status.State.Running != nil && status.State.Running.StartedAt.After(terminated.FinishedAt.Time) {
+				end, ongoing = status.State.Running.StartedAt.Time, false
+			}
+			message := fmt.Sprintf("container %s exited with code %d (%s)", status.Name, terminated.ExitCode, terminated.Reason)
+			add(SLOCauseRestart, pod.Name, message, sloInterval{terminated.FinishedAt.Time, end}, ongoing)
+		}
+
+		var ready *v1.PodCondition
+		for i := range pod.Status.Conditions {
+			if pod.Status.Conditions[i].Type == v1.PodReady {
+				ready = &pod.Status.Conditions[i]
+			}
+		}
+		switch {
+		case ready != nil && ready.Status != v1.ConditionTrue && pod.DeletionTimestamp == nil:
+			since := ready.LastTransitionTime.Time
+			if since.IsZero() {
+				since = pod.CreationTimestamp.Time
+			}
+			cause := SLOCauseReadiness
+			if input.replacedPodsStartDown && since.Equal(pod.CreationTimestamp.Time) {
+				cause = SLOCauseRollout
+			}
+			add(cause, pod.Name, "pod is not ready", sloInterval{since, now}, true)
+		case ready != nil && input.replacedPodsStartDown && pod.CreationTimestamp.After(counted):
+			// The replacement of a pod deleted during a rollout is down until it first becomes ready
+			add(SLOCauseRollout, pod.Name, "replacement pod starting", sloInterval{pod.CreationTimestamp.Time, ready.LastTransitionTime.Time}, false)
+		}
+	}
+
+	oldestEvent := now
+	for _, event := range input.events {
+		first, last := eventTimes(event)
+		if !first.IsZero() && first.Before(oldestEvent) {
+			oldestEvent = first
+		}
+		if event.Reason != "Unhealthy" || !strings.HasPrefix(event.Message, "Readiness probe failed") {
+			continue
+		}
+		add(SLOCauseReadiness, event.InvolvedObject.Name, event.Message, sloInterval{first, last.Add(readinessProbeGap)}, false)
+	}
+	if oldestEvent.After(counted) {
+		summary.Warnings = append(summary.Warnings, fmt.Sprintf("events only go back to %s; earlier readiness failures are not counted", oldestEvent.UTC().Format(time.RFC3339)))
+	}
+
+	// Below minimum availability, every replica counts as down
+	outageStart := now
+	if input.unavailableSince != nil {
+		outageStart = maxTime(*input.unavailableSince, counted)
+		if outageStart.Before(now) {
+			summary.Incidents = append(summary.Incidents, SLOIncident{Start: outageStart, End: now, Cause: SLOCauseRollout, Message: input.unavailableReason, Ongoing: true})
+		}
+	}
+	if len(input.pods) > 0 {
+		summary.Warnings = append(summary.Warnings, "only the most recent restart of each container is known from pod status")
+	}
+
+	replicaSeconds := func(pods map[string][]sloInterval, from, to time.Time) float64 {
+		total := 0.0
+		for _, intervals := range pods {
+			total += overlap(mergeIntervals(intervals), from, to)
+		}
+		return total
+	}
+	// downtime is the unavailable replica time within [from, to]; pod downtime during an outage
+	// is already covered by the outage
+	downtime := func(from, to time.Time) float64 {
+		outageFrom := maxTime(from, outageStart)
+		if !outageFrom.Before(to) {
+			return replicaSeconds(perPod, from, to)
+		}
+		return replicaSeconds(perPod, from, outageFrom) + float64(input.replicas)*to.Sub(outageFrom).Seconds()
+	}
+	availability := func(from, to time.Time) float64 {
+		capacity := float64(input.replicas) * to.Sub(from).Seconds()
+		if capacity <= 0 {
+			return 100
+		}
+		return math.Max(0, 100*(1-downtime(from, to)/capacity))
+	}
+
+	summary.Downtime = SLODowntime{
+		RestartSeconds:   replicaSeconds(byCause[SLOCauseRestart], counted, now),
+		ReadinessSeconds: replicaSeconds(byCause[SLOCauseReadiness], counted, now),
+		RolloutSeconds:   replicaSeconds(byCause[SLOCauseRollout], counted, now) + float64(input.replicas)*now.Sub(outageStart).Seconds(),
+		TotalSeconds:     downtime(counted, now),
+	}
+	summary.Availability = availability(counted, now)
+	if budget := 100 - objective; budget > 0 {
+		summary.ErrorBudgetRemaining = 100 * (1 - (100-summary.Availability)/budget)
+	}
+	summary.Met = summary.Availability >= objective
+
+	step := window / sloTrendBuckets
+	for i := 0; i < sloTrendBuckets; i++ {
+		from, to := start.Add(time.Duration(i)*step), start.Add(time.Duration(i+1)*step)
+		if !to.After(counted) {
+			continue
+		}
+		summary.Trend = append(summary.Trend, SLOTrendPoint{Start: from, End: to, Availability: availability(maxTime(from, counted), to)})
+	}
+
+	sort.Slice(summary.Incidents, func(i, j int) bool { return summary.Incidents[i].Start.After(summary.Incidents[j].Start) })
+	return summary
+}
+
+// maxTime returns the later of two times
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// GetWorkloadSLO returns an availability estimate and SLO summary for a workload
+// @Summary Get workload SLO summary
+// @Description Estimates the availability of a deployment, statefulset or daemonset over a window from pod status and events, without SLO tooling: container restart downtime (termination until restart), readiness gaps (failed readiness probes and pods not ready) and rollout unavailability (deployments below minimum availability, and pods of statefulsets and daemonsets replaced during a rollout until they become ready). Availability is the share of desired replica time that was served, compared against the objective with the remaining error budget, an incident list and a 24-point trend. Event retention limits how far back readiness failures are seen; warnings say so.
+// @Tags Workloads
+// @Produce json
+// @Param kind path string true "Workload kind: deployments, statefulsets or daemonsets"
+// @Param namespace path string true "Namespace name"
+// @Param name path string true "Workload name"
+// @Param config query string true "Kubernetes configuration ID"
+// @Param cluster query string false "Cluster name"
+// @Param window query string false "Window such as 24h, 7d or 1w, at most 30d" default(24h)
+// @Param objective query number false "Availability objective in percent" default(99.9)
+// @Success 200 {object} WorkloadSLOSummary "SLO summary"
+// @Failure 400 {object} map[string]string "Bad request - invalid kind, window or objective"
+// @Failure 404 {object} map[string]string "Workload not found"
+// @Security BearerAuth
+// @Security KubeConfig
+// @Router /api/v1/workloads/{kind}/{namespace}/{name}/slo [get]
+func (h *WorkloadSLOHandler) GetWorkloadSLO(c *gin.Context) {
+	client, err := h.getClientAndConfig(c)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get client for workload SLO")
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	window, err := parseSLOWindow(c.Query("window"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	objective := defaultSLOObjective
+	if raw := c.Query("objective"); raw != "" {
+		if objective, err = strconv.ParseFloat(raw, 64); err != nil || objective <= 0 || objective >= 100 {
+			utils.RespondErrorMessage(c, http.StatusBadRequest, "objective must be a percentage between 0 and 100")
+			return
+		}
+	}
+
+	kind, namespace, name := c.Param("kind"), c.Param("namespace"), c.Param("name")
+	input, err := loadSLOInput(c.Request.Context(), client, kind, namespace, name)
+	if err != nil {
+		code := http.StatusBadRequest
+		if apierrors.IsNotFound(err) {
+			code = http.StatusNotFound
+		} else if utils.IsPermissionError(err) {
+			code = http.StatusForbidden
+		}
+		h.logger.WithError(err).WithField("workload", namespace+"/"+name).Error("Failed to load workload for SLO summary")
+		utils.RespondError(c, code, err)
+		return
+	}
+
+	summary := buildWorkloadSLO(input, window, objective, time.Now())
+	summary.Kind, summary.Namespace, summary.Name = kind, namespace, name
+	c.JSON(http.StatusOK, summary)
+}
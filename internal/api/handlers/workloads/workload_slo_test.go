@@ -0,0 +1,113 @@
+package workloads
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func sloPod(name string, created time.Time, ready bool, readySince time.Time) v1.Pod {
+	status := v1.ConditionTrue
+	if !ready {
+		status = v1.ConditionFalse
+	}
+	return v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(created)},
+		Status: v1.PodStatus{Conditions: []v1.PodCondition{
+			{Type: v1.PodReady, Status: status, LastTransitionTime: metav1.NewTime(readySince)},
+		}},
+	}
+}
+
+func TestBuildWorkloadSLO(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	long := now.Add(-30 * 24 * time.Hour)
+
+	restarted := sloPod("web-1", long, true, now.Add(-59*time.Minute))
+	restarted.Status.ContainerStatuses = []v1.ContainerStatus{{
+		Name:                 "web",
+		LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled", FinishedAt: metav1.NewTime(now.Add(-time.Hour))}},
+		State:                v1.ContainerState{Running: &v1.ContainerStateRunning{StartedAt: metav1.NewTime(now.Add(-time.Hour + 6*time.Minute))}},
+	}}
+	probe := v1.Event{
+		InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "web-2"},
+		Reason:         "Unhealthy",
+		Message:        "Readiness probe failed: HTTP probe failed with statuscode: 503",
+		FirstTimestamp: metav1.NewTime(now.Add(-2 * time.Hour)),
+		LastTimestamp:  metav1.NewTime(now.Add(-2*time.Hour + 50*time.Second)),
+	}
+	input := &sloInput{
+		replicas: 2,
+		created:  long,
+		pods:     []v1.Pod{restarted, sloPod("web-2", long, true, long)},
+		events:   []v1.Event{probe},
+	}
+
+	summary := buildWorkloadSLO(input, 24*time.Hour, 99.5, now)
+	if summary.Restarts != 1 || summary.Downtime.RestartSeconds != 360 {
+		t.Errorf("restarts = %d with %vs downtime, want 1 with 360s", summary.Restarts, summary.Downtime.RestartSeconds)
+	}
+	if summary.Downtime.ReadinessSeconds != 60 || summary.Downtime.TotalSeconds != 420 {
+		t.Errorf("downtime = %+v, want 60s of readiness gaps and 420s in total", summary.Downtime)
+	}
+	want := 100 * (1 - 420.0/(2*24*3600))
+	if math.Abs(summary.Availability-want) > 1e-9 || !summary.Met {
+		t.Errorf("availability = %v (met %v), want %v", summary.Availability, summary.Met, want)
+	}
+	if len(summary.Incidents) != 2 || summary.Incidents[0].Cause != SLOCauseRestart || !strings.Contains(summary.Incidents[0].Message, "OOMKilled") {
+		t.Errorf("incidents = %+v, want the restart first", summary.Incidents)
+	}
+	if len(summary.Trend) != sloTrendBuckets || summary.Trend[len(summary.Trend)-1].Availability >= 100 || summary.Trend[0].Availability != 100 {
+		t.Errorf("trend = %+v", summary.Trend)
+	}
+}
+
+func TestBuildWorkloadSLORollouts(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	long := now.Add(-30 * 24 * time.Hour)
+
+	// A statefulset pod recreated during a rollout is down until it becomes ready
+	statefulSet := &sloInput{
+		replicas:              1,
+		created:               long,
+		replacedPodsStartDown: true,
+		pods:                  []v1.Pod{sloPod("db-0", now.Add(-3*time.Hour), true, now.Add(-3*time.Hour+90*time.Second))},
+	}
+	if summary := buildWorkloadSLO(statefulSet, 24*time.Hour, 99.9, now); summary.Downtime.RolloutSeconds != 90 {
+		t.Errorf("statefulset rollout downtime = %vs, want 90s", summary.Downtime.RolloutSeconds)
+	}
+
+	// A deployment below minimum availability counts every replica as down
+	since := now.Add(-36 * time.Minute)
+	deployment := &sloInput{
+		replicas:          2,
+		created:           now.Add(-2 * time.Hour),
+		pods:              []v1.Pod{sloPod("api-1", now.Add(-2*time.Hour), false, since)},
+		unavailableSince:  &since,
+		unavailableReason: "Deployment does not have minimum availability.",
+	}
+	summary := buildWorkloadSLO(deployment, 24*time.Hour, 99, now)
+	if math.Abs(summary.Availability-70) > 1e-9 || summary.Met || summary.ErrorBudgetRemaining >= 0 {
+		t.Errorf("summary = %+v, want 70%% availability and an exhausted budget", summary)
+	}
+	if len(summary.Trend) != 2 || len(summary.Warnings) == 0 {
+		t.Errorf("trend = %+v, warnings = %v; want the buckets since creation and a warning", summary.Trend, summary.Warnings)
+	}
+}
+
+func TestParseSLOWindow(t *testing.T) {
+	for value, want := range map[string]time.Duration{"": 24 * time.Hour, "12h": 12 * time.Hour, "7d": 7 * 24 * time.Hour, "2w": 14 * 24 * time.Hour} {
+		if got, err := parseSLOWindow(value); err != nil || got != want {
+			t.Errorf("parseSLOWindow(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"0h", "x", "-1d", "31d"} {
+		if _, err := parseSLOWindow(value); err == nil {
+			t.Errorf("parseSLOWindow(%q) succeeded", value)
+		}
+	}
+}
@@ -7,8 +7,11 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Facets-cloud/kube-dash/internal/clustermeta"
+	"github.com/Facets-cloud/kube-dash/internal/config"
 	"github.com/Facets-cloud/kube-dash/internal/k8s"
 	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/internal/warmcache"
 	"github.com/Facets-cloud/kube-dash/pkg/logger"
 
 	"github.com/gin-gonic/gin"
@@ -23,14 +26,20 @@ type KubeConfigHandler struct {
 	store         *storage.KubeConfigStore
 	clientFactory *k8s.ClientFactory
 	logger        *logger.Logger
+	config        *config.K8sConfig
+	clusterMeta   *clustermeta.Store
+	warmCache     *warmcache.Cache
 }
 
 // NewKubeConfigHandler creates a new kubeconfig handler
-func NewKubeConfigHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger) *KubeConfigHandler {
+func NewKubeConfigHandler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger, cfg *config.K8sConfig, clusterMeta *clustermeta.Store, warmCache *warmcache.Cache) *KubeConfigHandler {
 	return &KubeConfigHandler{
 		store:         store,
 		clientFactory: clientFactory,
 		logger:        log,
+		config:        cfg,
+		clusterMeta:   clusterMeta,
+		warmCache:     warmCache,
 	}
 }
 
@@ -44,7 +53,7 @@ func NewKubeConfigHandler(store *storage.KubeConfigStore, clientFactory *k8s.Cli
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/v1/app/config [get]
 func (h *KubeConfigHandler) GetConfigs(c *gin.Context) {
-	response := h.store.GetClustersResponse()
+	response := h.store.GetClustersResponse(h.sessionID(c))
 	c.JSON(http.StatusOK, response)
 }
 
@@ -57,6 +66,8 @@ func (h *KubeConfigHandler) GetConfigs(c *gin.Context) {
 // @Param kubeconfig formData file false "Kubeconfig file to upload"
 // @Param file formData string false "Kubeconfig content as text"
 // @Param filename formData string false "Filename for the kubeconfig"
+// @Param sessionOnly formData bool false "Keep the kubeconfig in memory for this browser session only"
+// @Param ttlMinutes formData int false "Lifetime of a session-only kubeconfig in minutes"
 // @Success 200 {object} map[string]interface{} "Kubeconfig uploaded successfully"
 // @Failure 400 {object} map[string]interface{} "Bad request - invalid file or format"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
@@ -111,7 +122,7 @@ func (h *KubeConfigHandler) AddKubeconfig(c *gin.Context) {
 		return
 	}
 
-	configID, err := h.store.AddKubeConfig(config, filename)
+	configID, expiresAt, err := h.addKubeConfig(c, config, filename)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to add kubeconfig")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -119,10 +130,7 @@ func (h *KubeConfigHandler) AddKubeconfig(c *gin.Context) {
 	}
 
 	h.logger.WithField("config_id", configID).Info("Kubeconfig added successfully")
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Kubeconfig added successfully",
-		"id":      configID,
-	})
+	c.JSON(http.StatusOK, addedResponse("Kubeconfig added successfully", configID, expiresAt))
 }
 
 // AddBearerKubeconfig handles bearer token kubeconfig creation
@@ -135,6 +143,8 @@ func (h *KubeConfigHandler) AddKubeconfig(c *gin.Context) {
 // @Param serverIP formData string true "Kubernetes API server URL"
 // @Param token formData string true "Bearer token for authentication"
 // @Param cluster formData string false "Cluster name (defaults to name if not provided)"
+// @Param sessionOnly formData bool false "Keep the kubeconfig in memory for this browser session only"
+// @Param ttlMinutes formData int false "Lifetime of a session-only kubeconfig in minutes"
 // @Success 200 {object} map[string]interface{} "Bearer kubeconfig created successfully"
 // @Failure 400 {object} map[string]interface{} "Bad request - missing required fields"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
@@ -175,7 +185,7 @@ func (h *KubeConfigHandler) AddBearerKubeconfig(c *gin.Context) {
 	config.Contexts[cluster] = kubeContext
 	config.CurrentContext = cluster
 
-	configID, err := h.store.AddKubeConfig(config, name)
+	configID, expiresAt, err := h.addKubeConfig(c, config, name)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to add bearer kubeconfig")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -183,10 +193,7 @@ func (h *KubeConfigHandler) AddBearerKubeconfig(c *gin.Context) {
 	}
 
 	h.logger.WithField("config_id", configID).Info("Bearer kubeconfig added successfully")
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Bearer kubeconfig added successfully",
-		"id":      configID,
-	})
+	c.JSON(http.StatusOK, addedResponse("Bearer kubeconfig added successfully", configID, expiresAt))
 }
 
 // AddCertificateKubeconfig handles certificate-based kubeconfig creation
@@ -201,6 +208,8 @@ func (h *KubeConfigHandler) AddBearerKubeconfig(c *gin.Context) {
 // @Param clientKeyData formData string true "Client private key data (PEM format)"
 // @Param cluster formData string false "Cluster name (defaults to name if not provided)"
 // @Param ca formData string false "Certificate Authority data (PEM format)"
+// @Param sessionOnly formData bool false "Keep the kubeconfig in memory for this browser session only"
+// @Param ttlMinutes formData int false "Lifetime of a session-only kubeconfig in minutes"
 // @Success 200 {object} map[string]interface{} "Certificate kubeconfig created successfully"
 // @Failure 400 {object} map[string]interface{} "Bad request - missing required fields"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
@@ -249,7 +258,7 @@ func (h *KubeConfigHandler) AddCertificateKubeconfig(c *gin.Context) {
 	config.Contexts[cluster] = kubeContext
 	config.CurrentContext = cluster
 
-	configID, err := h.store.AddKubeConfig(config, name)
+	configID, expiresAt, err := h.addKubeConfig(c, config, name)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to add certificate kubeconfig")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -257,37 +266,85 @@ func (h *KubeConfigHandler) AddCertificateKubeconfig(c *gin.Context) {
 	}
 
 	h.logger.WithField("config_id", configID).Info("Certificate kubeconfig added successfully")
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Certificate kubeconfig added successfully",
-		"id":      configID,
-	})
+	c.JSON(http.StatusOK, addedResponse("Certificate kubeconfig added successfully", configID, expiresAt))
 }
 
 // DeleteKubeconfig removes a kubeconfig
 // @Summary Delete kubeconfig
-// @Description Remove a stored kubeconfig configuration by ID
+// @Description Remove a stored kubeconfig configuration by ID, tearing down the cached clients, streams, saved views and schedules bound to it
 // @Tags Configuration
 // @Accept json
 // @Produce json
 // @Param id path string true "Kubeconfig ID to delete"
-// @Success 200 {object} map[string]interface{} "Kubeconfig deleted successfully"
+// @Success 200 {object} map[string]interface{} "Kubeconfig deleted, with what each subsystem cleaned up"
 // @Failure 404 {object} map[string]interface{} "Kubeconfig not found"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/v1/app/config/kubeconfigs/{id} [delete]
 func (h *KubeConfigHandler) DeleteKubeconfig(c *gin.Context) {
 	configID := c.Param("id")
 
-	if err := h.store.DeleteKubeConfig(configID); err != nil {
+	report, err := h.store.RemoveKubeConfig(configID)
+	if err != nil {
 		h.logger.WithError(err).WithField("config_id", configID).Error("Failed to delete kubeconfig")
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Clear cached clients for this config
-	h.clientFactory.ClearClients()
-
+	h.logRemoval(report)
 	h.logger.WithField("config_id", configID).Info("Kubeconfig deleted successfully")
-	c.JSON(http.StatusOK, gin.H{"message": "Kubeconfig deleted successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "Kubeconfig deleted successfully", "removal": report})
+}
+
+// DeleteCluster removes one cluster from a kubeconfig
+// @Summary Delete cluster from kubeconfig
+// @Description Remove a cluster from a stored kubeconfig together with its contexts and the credentials only they used, tearing down the cached clients, streams, saved views and schedules bound to it
+// @Tags Configuration
+// @Accept json
+// @Produce json
+// @Param id path string true "Kubeconfig ID"
+// @Param cluster path string true "Cluster name to remove"
+// @Success 200 {object} map[string]interface{} "Cluster removed, with what each subsystem cleaned up"
+// @Failure 400 {object} map[string]interface{} "Cluster not found or the only cluster of the kubeconfig"
+// @Failure 404 {object} map[string]interface{} "Kubeconfig not found"
+// @Router /api/v1/app/config/kubeconfigs/{id}/clusters/{cluster} [delete]
+func (h *KubeConfigHandler) DeleteCluster(c *gin.Context) {
+	configID := c.Param("id")
+	cluster := c.Param("cluster")
+
+	if _, err := h.store.GetKubeConfig(configID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	report, err := h.store.RemoveCluster(configID, cluster)
+	if err != nil {
+		h.logger.WithError(err).WithField("config_id", configID).WithField("cluster", cluster).Error("Failed to remove cluster")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.logRemoval(report)
+	h.logger.WithField("config_id", configID).WithField("cluster", cluster).Info("Cluster removed successfully")
+	c.JSON(http.StatusOK, gin.H{"message": "Cluster removed successfully", "removal": report})
+}
+
+// logRemoval warns about the subsystems that failed to clean up after a removal
+func (h *KubeConfigHandler) logRemoval(report *storage.RemovalReport) {
+	for _, result := range report.Cleanup {
+		if result.Error != "" {
+			h.logger.WithField("config_id", report.ConfigID).WithField("subsystem", result.Subsystem).
+				WithField("error", result.Error).Warn("Cleanup after removal failed")
+		}
+	}
+}
+
+// RegisterCleanupHooks registers the cleanup of the clients, summaries and metadata this handler
+// keeps for removed kubeconfigs and clusters
+func (h *KubeConfigHandler) RegisterCleanupHooks() {
+	h.store.RegisterCleanupHook("clients", func(removal storage.Removal) (int, error) {
+		return h.clientFactory.RemoveClients(removal.Config, removal.Clusters()), nil
+	})
+	h.store.RegisterCleanupHook("warm-cache", h.warmCache.Cleanup)
+	h.store.RegisterCleanupHook("cluster-metadata", h.clusterMeta.Cleanup)
 }
 
 // ValidateKubeconfig handles kubeconfig validation and connectivity testing
@@ -702,8 +759,8 @@ func (h *KubeConfigHandler) ValidateCertificate(c *gin.Context) {
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/v1/app/config/validate-all [get]
 func (h *KubeConfigHandler) ValidateAllKubeconfigs(c *gin.Context) {
-	// Get all existing kubeconfigs
-	allConfigs := h.store.ListKubeConfigs()
+	// Get all kubeconfigs the caller may see
+	allConfigs := h.visibleConfigs(c)
 
 	validationResults := make(map[string]interface{})
 	var wg sync.WaitGroup
@@ -0,0 +1,191 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// sessionExpiryInterval is how often expired session-only kubeconfigs are purged
+const sessionExpiryInterval = 30 * time.Second
+
+// configParams are the query parameters that name a kubeconfig the request resolves
+var configParams = []string{"config", "leftConfig", "rightConfig"}
+
+// SessionKubeConfig describes a session-only kubeconfig and its remaining lifetime
+type SessionKubeConfig struct {
+	ID               string            `json:"id"`
+	Name             string            `json:"name"`
+	Clusters         map[string]string `json:"clusters"`
+	Created          time.Time         `json:"created"`
+	ExpiresAt        time.Time         `json:"expiresAt"`
+	RemainingSeconds int64             `json:"remainingSeconds"`
+}
+
+// sessionID returns the caller's session, or an empty string if it has none
+func (h *KubeConfigHandler) sessionID(c *gin.Context) string {
+	return utils.SessionID(c)
+}
+
+// ensureSessionID returns the caller's session, starting one with a browser-session cookie if needed
+func (h *KubeConfigHandler) ensureSessionID(c *gin.Context) string {
+	if id := h.sessionID(c); id != "" {
+		return id
+	}
+	id := uuid.New().String()
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     utils.SessionCookie,
+		Value:    id,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   c.Request.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	})
+	return id
+}
+
+// RequireSessionOwner rejects requests that resolve another session's session-only kubeconfig, named
+// by one of the config parameters or by the ID of the kubeconfig management routes. They are
+// answered as if the kubeconfig did not exist. Handlers that take a kubeconfig from the request
+// body check it themselves.
+func (h *KubeConfigHandler) RequireSessionOwner(c *gin.Context) {
+	configIDs := make([]string, 0, len(configParams)+1)
+	for _, param := range configParams {
+		configIDs = append(configIDs, c.Query(param))
+	}
+	if strings.HasPrefix(c.FullPath(), "/api/v1/app/config/kubeconfigs/:id") {
+		configIDs = append(configIDs, c.Param("id"))
+	}
+	sessionID := h.sessionID(c)
+	for _, configID := range configIDs {
+		if configID != "" && !h.store.UsableBy(configID, sessionID) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "kubeconfig not found: " + configID})
+			return
+		}
+	}
+	c.Next()
+}
+
+// sessionTTL returns the requested lifetime clamped to the configured maximum
+func (h *KubeConfigHandler) sessionTTL(c *gin.Context) time.Duration {
+	minutes := h.config.SessionKubeconfigTTLMinutes
+	if v, err := strconv.Atoi(c.PostForm("ttlMinutes")); err == nil && v > 0 {
+		minutes = v
+	}
+	if max := h.config.SessionKubeconfigMaxTTLMinutes; max > 0 && minutes > max {
+		minutes = max
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// addKubeConfig stores a kubeconfig, keeping it in memory for the caller's session when sessionOnly is set
+func (h *KubeConfigHandler) addKubeConfig(c *gin.Context, config *api.Config, name string) (string, *time.Time, error) {
+	if sessionOnly, _ := strconv.ParseBool(c.PostForm("sessionOnly")); !sessionOnly {
+		id, err := h.store.AddKubeConfig(config, name)
+		if err == nil {
+			h.warmCache.Warm(id)
+		}
+		return id, nil, err
+	}
+
+	ttl := h.sessionTTL(c)
+	id, err := h.store.AddSessionKubeConfig(config, name, h.ensureSessionID(c), ttl)
+	if err != nil {
+		return "", nil, err
+	}
+	h.warmCache.Warm(id)
+	expiresAt := time.Now().Add(ttl)
+	h.logger.WithField("config_id", id).WithField("ttl", ttl).Info("Session-only kubeconfig added")
+	return id, &expiresAt, nil
+}
+
+// addedResponse builds the response for a newly added kubeconfig
+func addedResponse(message, id string, expiresAt *time.Time) gin.H {
+	response := gin.H{
+		"message": message,
+		"id":      id,
+	}
+	if expiresAt != nil {
+		response["sessionOnly"] = true
+		response["expiresAt"] = expiresAt
+	}
+	return response
+}
+
+// GetSessionKubeconfigs returns the caller's session-only kubeconfigs with their remaining lifetime
+// @Summary Get session-only kubeconfigs
+// @Description List the kubeconfigs held in memory for the current session and how long until each is purged
+// @Tags Configuration
+// @Produce json
+// @Success 200 {array} SessionKubeConfig "Session-only kubeconfigs"
+// @Router /api/v1/app/config/session [get]
+func (h *KubeConfigHandler) GetSessionKubeconfigs(c *gin.Context) {
+	result := []SessionKubeConfig{}
+	sessionID := h.sessionID(c)
+	if sessionID == "" {
+		c.JSON(http.StatusOK, result)
+		return
+	}
+
+	now := time.Now()
+	for _, k := range h.store.ListSessionKubeConfigs(sessionID) {
+		result = append(result, SessionKubeConfig{
+			ID:               k.ID,
+			Name:             k.Name,
+			Clusters:         k.Clusters,
+			Created:          k.Created,
+			ExpiresAt:        *k.ExpiresAt,
+			RemainingSeconds: int64(k.ExpiresAt.Sub(now).Seconds()),
+		})
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// EndSession purges the caller's session-only kubeconfigs
+// @Summary End session
+// @Description Log out of the current session, immediately purging its session-only kubeconfigs
+// @Tags Configuration
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Session ended"
+// @Router /api/v1/app/config/session/logout [post]
+func (h *KubeConfigHandler) EndSession(c *gin.Context) {
+	purged := 0
+	if sessionID := h.sessionID(c); sessionID != "" {
+		purged = len(h.store.PurgeSessionKubeConfigs(sessionID))
+	}
+
+	// Expire the cookie so the next upload starts a fresh session
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     utils.SessionCookie,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   c.Request.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	h.logger.WithField("purged", purged).Info("Session ended")
+	c.JSON(http.StatusOK, gin.H{"message": "Session ended", "purged": purged})
+}
+
+// StartSessionExpiry starts a background goroutine that purges expired session-only kubeconfigs
+func (h *KubeConfigHandler) StartSessionExpiry() {
+	go func() {
+		ticker := time.NewTicker(sessionExpiryInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if ids := h.store.PurgeExpiredKubeConfigs(); len(ids) > 0 {
+				h.logger.WithField("count", len(ids)).Info("Purged expired session-only kubeconfigs")
+			}
+		}
+	}()
+}
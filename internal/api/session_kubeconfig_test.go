@@ -0,0 +1,78 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/handlers/compare"
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+	"github.com/Facets-cloud/kube-dash/internal/config"
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func sessionTestConfig() *api.Config {
+	config := api.NewConfig()
+	config.Clusters["c1"] = &api.Cluster{Server: "https://c1.example.com"}
+	config.AuthInfos["u1"] = &api.AuthInfo{Token: "secret"}
+	config.Contexts["c1"] = &api.Context{Cluster: "c1", AuthInfo: "u1"}
+	config.CurrentContext = "c1"
+	return config
+}
+
+func TestRequireSessionOwner(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := storage.NewKubeConfigStore()
+	shared, err := store.AddKubeConfig(sessionTestConfig(), "shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+	private, err := store.AddSessionKubeConfig(sessionTestConfig(), "private", "session-a", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := &KubeConfigHandler{store: store}
+	router := gin.New()
+	router.Use(h.RequireSessionOwner)
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/api/v1/pods", ok)
+	router.DELETE("/api/v1/app/config/kubeconfigs/:id", ok)
+	compareHandler := compare.NewCompareHandler(store, k8s.NewClientFactory(&config.K8sConfig{}), logger.New("error"))
+	router.GET("/api/v1/compare", compareHandler.CompareClusters)
+	router.GET("/api/v1/compare/workload", compareHandler.CompareWorkloads)
+
+	cases := []struct {
+		name    string
+		method  string
+		path    string
+		session string
+		want    int
+	}{
+		{"shared config", http.MethodGet, "/api/v1/pods?config=" + shared, "", http.StatusOK},
+		{"owning session", http.MethodGet, "/api/v1/pods?config=" + private, "session-a", http.StatusOK},
+		{"other session", http.MethodGet, "/api/v1/pods?config=" + private, "session-b", http.StatusNotFound},
+		{"no session", http.MethodGet, "/api/v1/pods?config=" + private, "", http.StatusNotFound},
+		{"management route", http.MethodDelete, "/api/v1/app/config/kubeconfigs/" + private, "session-b", http.StatusNotFound},
+		{"compare right side", http.MethodGet, "/api/v1/compare?leftConfig=" + shared + "&rightConfig=" + private, "session-b", http.StatusNotFound},
+		{"compare left side", http.MethodGet, "/api/v1/compare/workload?kind=Deployment&name=web&leftNamespace=a&rightNamespace=b&leftConfig=" + private + "&rightConfig=" + shared, "session-b", http.StatusNotFound},
+		{"unknown config", http.MethodGet, "/api/v1/pods?config=missing", "", http.StatusOK},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		if tc.session != "" {
+			req.Header.Set(utils.SessionHeader, tc.session)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Errorf("%s: got %d, want %d", tc.name, rec.Code, tc.want)
+		}
+	}
+}
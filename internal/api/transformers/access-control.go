@@ -2,7 +2,6 @@ package transformers
 
 import (
 	"strings"
-	"time"
 
 	v1 "k8s.io/api/core/v1"
 	rbacV1 "k8s.io/api/rbac/v1"
@@ -14,12 +13,13 @@ import (
 func TransformServiceAccountToResponse(serviceAccount *v1.ServiceAccount) types.ServiceAccountListResponse {
 	age := ""
 	if !serviceAccount.CreationTimestamp.IsZero() {
-		age = serviceAccount.CreationTimestamp.Time.Format(time.RFC3339)
+		age = types.TimeFormat(serviceAccount.CreationTimestamp.Time)
 	}
 
 	return types.ServiceAccountListResponse{
 		BaseResponse: types.BaseResponse{
 			Age:        age,
+			AgeMs:      types.EpochMillis(serviceAccount.CreationTimestamp.Time),
 			HasUpdated: false,
 			Name:       serviceAccount.Name,
 			UID:        string(serviceAccount.UID),
@@ -37,7 +37,7 @@ func TransformServiceAccountToResponse(serviceAccount *v1.ServiceAccount) types.
 func TransformRoleToResponse(role *rbacV1.Role) types.RoleListResponse {
 	age := ""
 	if !role.CreationTimestamp.IsZero() {
-		age = role.CreationTimestamp.Time.Format(time.RFC3339)
+		age = types.TimeFormat(role.CreationTimestamp.Time)
 	}
 
 	// Transform rules to string format
@@ -68,6 +68,7 @@ func TransformRoleToResponse(role *rbacV1.Role) types.RoleListResponse {
 		NamespacedResponse: types.NamespacedResponse{
 			BaseResponse: types.BaseResponse{
 				Age:        age,
+				AgeMs:      types.EpochMillis(role.CreationTimestamp.Time),
 				HasUpdated: false,
 				Name:       role.Name,
 				UID:        string(role.UID),
@@ -86,7 +87,7 @@ func TransformRoleToResponse(role *rbacV1.Role) types.RoleListResponse {
 func TransformRoleBindingToResponse(roleBinding *rbacV1.RoleBinding) types.RoleBindingListResponse {
 	age := ""
 	if !roleBinding.CreationTimestamp.IsZero() {
-		age = roleBinding.CreationTimestamp.Time.Format(time.RFC3339)
+		age = types.TimeFormat(roleBinding.CreationTimestamp.Time)
 	}
 
 	// Transform subjects to string format
@@ -103,6 +104,7 @@ func TransformRoleBindingToResponse(roleBinding *rbacV1.RoleBinding) types.RoleB
 		NamespacedResponse: types.NamespacedResponse{
 			BaseResponse: types.BaseResponse{
 				Age:        age,
+				AgeMs:      types.EpochMillis(roleBinding.CreationTimestamp.Time),
 				HasUpdated: false,
 				Name:       roleBinding.Name,
 				UID:        string(roleBinding.UID),
@@ -121,7 +123,7 @@ func TransformRoleBindingToResponse(roleBinding *rbacV1.RoleBinding) types.RoleB
 func TransformClusterRoleToResponse(clusterRole *rbacV1.ClusterRole) types.ClusterRoleListResponse {
 	age := ""
 	if !clusterRole.CreationTimestamp.IsZero() {
-		age = clusterRole.CreationTimestamp.Time.Format(time.RFC3339)
+		age = types.TimeFormat(clusterRole.CreationTimestamp.Time)
 	}
 
 	// Transform rules to string format
@@ -151,6 +153,7 @@ func TransformClusterRoleToResponse(clusterRole *rbacV1.ClusterRole) types.Clust
 	return types.ClusterRoleListResponse{
 		BaseResponse: types.BaseResponse{
 			Age:        age,
+			AgeMs:      types.EpochMillis(clusterRole.CreationTimestamp.Time),
 			HasUpdated: false,
 			Name:       clusterRole.Name,
 			UID:        string(clusterRole.UID),
@@ -167,7 +170,7 @@ func TransformClusterRoleToResponse(clusterRole *rbacV1.ClusterRole) types.Clust
 func TransformClusterRoleBindingToResponse(clusterRoleBinding *rbacV1.ClusterRoleBinding) types.ClusterRoleBindingListResponse {
 	age := ""
 	if !clusterRoleBinding.CreationTimestamp.IsZero() {
-		age = clusterRoleBinding.CreationTimestamp.Time.Format(time.RFC3339)
+		age = types.TimeFormat(clusterRoleBinding.CreationTimestamp.Time)
 	}
 
 	// Transform subjects to string format
@@ -183,6 +186,7 @@ func TransformClusterRoleBindingToResponse(clusterRoleBinding *rbacV1.ClusterRol
 	return types.ClusterRoleBindingListResponse{
 		BaseResponse: types.BaseResponse{
 			Age:        age,
+			AgeMs:      types.EpochMillis(clusterRoleBinding.CreationTimestamp.Time),
 			HasUpdated: false,
 			Name:       clusterRoleBinding.Name,
 			UID:        string(clusterRoleBinding.UID),
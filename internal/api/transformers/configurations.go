@@ -1,8 +1,6 @@
 package transformers
 
 import (
-	"time"
-
 	"github.com/Facets-cloud/kube-dash/internal/api/types"
 
 	autoscalingV2 "k8s.io/api/autoscaling/v2"
@@ -17,7 +15,7 @@ import (
 func TransformConfigMapToResponse(configMap *v1.ConfigMap) types.ConfigMapListResponse {
 	age := ""
 	if !configMap.CreationTimestamp.IsZero() {
-		age = configMap.CreationTimestamp.Time.Format(time.RFC3339)
+		age = types.TimeFormat(configMap.CreationTimestamp.Time)
 	}
 
 	// Extract keys from data
@@ -28,6 +26,7 @@ func TransformConfigMapToResponse(configMap *v1.ConfigMap) types.ConfigMapListRe
 
 	return types.ConfigMapListResponse{
 		Age:        age,
+		AgeMs:      types.EpochMillis(configMap.CreationTimestamp.Time),
 		HasUpdated: false,
 		Name:       configMap.Name,
 		Namespace:  configMap.Namespace,
@@ -40,7 +39,7 @@ func TransformConfigMapToResponse(configMap *v1.ConfigMap) types.ConfigMapListRe
 func TransformSecretToResponse(secret *v1.Secret) types.SecretListResponse {
 	age := ""
 	if !secret.CreationTimestamp.IsZero() {
-		age = secret.CreationTimestamp.Time.Format(time.RFC3339)
+		age = types.TimeFormat(secret.CreationTimestamp.Time)
 	}
 
 	// Extract keys from data
@@ -51,6 +50,7 @@ func TransformSecretToResponse(secret *v1.Secret) types.SecretListResponse {
 
 	return types.SecretListResponse{
 		Age:        age,
+		AgeMs:      types.EpochMillis(secret.CreationTimestamp.Time),
 		HasUpdated: false,
 		Name:       secret.Name,
 		Namespace:  secret.Namespace,
@@ -64,7 +64,7 @@ func TransformSecretToResponse(secret *v1.Secret) types.SecretListResponse {
 func TransformHPAToResponse(hpa *autoscalingV2.HorizontalPodAutoscaler) types.HPAListResponse {
 	age := ""
 	if !hpa.CreationTimestamp.IsZero() {
-		age = hpa.CreationTimestamp.Time.Format(time.RFC3339)
+		age = types.TimeFormat(hpa.CreationTimestamp.Time)
 	}
 
 	minPods := int32(0)
@@ -78,6 +78,7 @@ func TransformHPAToResponse(hpa *autoscalingV2.HorizontalPodAutoscaler) types.HP
 
 	return types.HPAListResponse{
 		Age:        age,
+		AgeMs:      types.EpochMillis(hpa.CreationTimestamp.Time),
 		HasUpdated: false,
 		Name:       hpa.Name,
 		Namespace:  hpa.Namespace,
@@ -96,11 +97,12 @@ func TransformHPAToResponse(hpa *autoscalingV2.HorizontalPodAutoscaler) types.HP
 func TransformLimitRangeToResponse(limitRange *v1.LimitRange) types.LimitRangeListResponse {
 	age := ""
 	if !limitRange.CreationTimestamp.IsZero() {
-		age = limitRange.CreationTimestamp.Time.Format(time.RFC3339)
+		age = types.TimeFormat(limitRange.CreationTimestamp.Time)
 	}
 
 	return types.LimitRangeListResponse{
 		Age:        age,
+		AgeMs:      types.EpochMillis(limitRange.CreationTimestamp.Time),
 		HasUpdated: false,
 		Name:       limitRange.Name,
 		Namespace:  limitRange.Namespace,
@@ -117,7 +119,7 @@ func TransformLimitRangeToResponse(limitRange *v1.LimitRange) types.LimitRangeLi
 func TransformResourceQuotaToResponse(quota *v1.ResourceQuota) types.ResourceQuotaListResponse {
 	age := ""
 	if !quota.CreationTimestamp.IsZero() {
-		age = quota.CreationTimestamp.Time.Format(time.RFC3339)
+		age = types.TimeFormat(quota.CreationTimestamp.Time)
 	}
 
 	// Convert hard limits to string map
@@ -134,6 +136,7 @@ func TransformResourceQuotaToResponse(quota *v1.ResourceQuota) types.ResourceQuo
 
 	return types.ResourceQuotaListResponse{
 		Age:        age,
+		AgeMs:      types.EpochMillis(quota.CreationTimestamp.Time),
 		HasUpdated: false,
 		Name:       quota.Name,
 		Namespace:  quota.Namespace,
@@ -155,7 +158,7 @@ func TransformResourceQuotaToResponse(quota *v1.ResourceQuota) types.ResourceQuo
 func TransformPodDisruptionBudgetToResponse(pdb *policyV1.PodDisruptionBudget) types.PodDisruptionBudgetListResponse {
 	age := ""
 	if !pdb.CreationTimestamp.IsZero() {
-		age = pdb.CreationTimestamp.Time.Format(time.RFC3339)
+		age = types.TimeFormat(pdb.CreationTimestamp.Time)
 	}
 
 	minAvailable := ""
@@ -169,6 +172,7 @@ func TransformPodDisruptionBudgetToResponse(pdb *policyV1.PodDisruptionBudget) t
 
 	return types.PodDisruptionBudgetListResponse{
 		Age:        age,
+		AgeMs:      types.EpochMillis(pdb.CreationTimestamp.Time),
 		HasUpdated: false,
 		Name:       pdb.Name,
 		Namespace:  pdb.Namespace,
@@ -198,11 +202,12 @@ func TransformPodDisruptionBudgetToResponse(pdb *policyV1.PodDisruptionBudget) t
 func TransformPriorityClassToResponse(priorityClass *schedulingV1.PriorityClass) types.PriorityClassListResponse {
 	age := ""
 	if !priorityClass.CreationTimestamp.IsZero() {
-		age = priorityClass.CreationTimestamp.Time.Format(time.RFC3339)
+		age = types.TimeFormat(priorityClass.CreationTimestamp.Time)
 	}
 
 	return types.PriorityClassListResponse{
 		Age:           age,
+		AgeMs:         types.EpochMillis(priorityClass.CreationTimestamp.Time),
 		HasUpdated:    false,
 		Name:          priorityClass.Name,
 		UID:           string(priorityClass.UID),
@@ -216,11 +221,12 @@ func TransformPriorityClassToResponse(priorityClass *schedulingV1.PriorityClass)
 func TransformRuntimeClassToResponse(runtimeClass *nodeV1.RuntimeClass) types.RuntimeClassListResponse {
 	age := ""
 	if !runtimeClass.CreationTimestamp.IsZero() {
-		age = runtimeClass.CreationTimestamp.Time.Format(time.RFC3339)
+		age = types.TimeFormat(runtimeClass.CreationTimestamp.Time)
 	}
 
 	return types.RuntimeClassListResponse{
 		Age:        age,
+		AgeMs:      types.EpochMillis(runtimeClass.CreationTimestamp.Time),
 		HasUpdated: false,
 		Name:       runtimeClass.Name,
 		UID:        string(runtimeClass.UID),
@@ -232,7 +238,7 @@ func TransformRuntimeClassToResponse(runtimeClass *nodeV1.RuntimeClass) types.Ru
 func TransformLeaseToResponse(lease *coordinationV1.Lease) types.LeaseListResponse {
 	age := ""
 	if !lease.CreationTimestamp.IsZero() {
-		age = lease.CreationTimestamp.Time.Format(time.RFC3339)
+		age = types.TimeFormat(lease.CreationTimestamp.Time)
 	}
 
 	holderIdentity := ""
@@ -246,11 +252,12 @@ func TransformLeaseToResponse(lease *coordinationV1.Lease) types.LeaseListRespon
 
 	return types.LeaseListResponse{
 		Age:                  age,
-		HasUpdated:          false,
-		Name:                lease.Name,
-		Namespace:           lease.Namespace,
-		UID:                 string(lease.UID),
-		HolderIdentity:      holderIdentity,
+		AgeMs:                types.EpochMillis(lease.CreationTimestamp.Time),
+		HasUpdated:           false,
+		Name:                 lease.Name,
+		Namespace:            lease.Namespace,
+		UID:                  string(lease.UID),
+		HolderIdentity:       holderIdentity,
 		LeaseDurationSeconds: leaseDurationSeconds,
 	}
 }
@@ -1,6 +1,8 @@
 package transformers
 
 import (
+	"github.com/Facets-cloud/kube-dash/internal/api/types"
+
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
@@ -9,6 +11,7 @@ type CustomResourceDefinition struct {
 	ActiveVersion            string                    `json:"activeVersion"`
 	AdditionalPrinterColumns []AdditionalPrinterColumn `json:"additionalPrinterColumns"`
 	Age                      string                    `json:"age"`
+	AgeMs                    int64                     `json:"ageMs,omitempty"`
 	HasUpdated               bool                      `json:"hasUpdated"`
 	Name                     string                    `json:"name"`
 	QueryParam               string                    `json:"queryParam"`
@@ -16,6 +19,19 @@ type CustomResourceDefinition struct {
 	Spec                     CRDSpec                   `json:"spec"`
 	Versions                 int                       `json:"versions"`
 	UID                      string                    `json:"uid"`
+	VersionStatus            CRDVersionStatus          `json:"versionStatus"`
+	InstanceCount            *int                      `json:"instanceCount,omitempty"`
+	InstanceCountError       string                    `json:"instanceCountError,omitempty"`
+}
+
+// CRDVersionStatus compares the versions a CRD serves with the versions objects are stored in
+type CRDVersionStatus struct {
+	Served          []string `json:"served"`
+	StorageVersion  string   `json:"storageVersion"`
+	Stored          []string `json:"stored"`                   // status.storedVersions
+	StaleStored     []string `json:"staleStored"`              // stored versions other than the storage version
+	UnservedStored  []string `json:"unservedStored,omitempty"` // stale stored versions that are no longer served
+	MigrationNeeded bool     `json:"migrationNeeded"`          // objects may still be persisted in a stale version
 }
 
 // CRDSpec represents the spec section of a CRD
@@ -28,11 +44,12 @@ type CRDSpec struct {
 
 // CRDNames represents the names section of a CRD
 type CRDNames struct {
-	Kind       string  `json:"kind"`
-	ListKind   string  `json:"listKind"`
-	Plural     string  `json:"plural"`
-	ShortNames *string `json:"shortNames"`
-	Singular   string  `json:"singular"`
+	Kind       string   `json:"kind"`
+	ListKind   string   `json:"listKind"`
+	Plural     string   `json:"plural"`
+	ShortNames *string  `json:"shortNames"`
+	Singular   string   `json:"singular"`
+	Categories []string `json:"categories"` // e.g. all, or categories defined by an operator
 }
 
 // AdditionalPrinterColumn represents additional printer columns for a CRD
@@ -61,7 +78,10 @@ func transformCRD(crd unstructured.Unstructured) CustomResourceDefinition {
 	creationTimestamp := metadata["creationTimestamp"].(string)
 
 	// Send creation timestamp instead of calculated age (frontend will handle age calculation)
-	age := creationTimestamp
+	age, ageMs := creationTimestamp, int64(0)
+	if created, ok := types.ParseTimestamp(creationTimestamp); ok {
+		age, ageMs = types.TimeFormat(created), types.EpochMillis(created)
+	}
 
 	// Extract spec
 	spec := crd.Object["spec"].(map[string]interface{})
@@ -84,6 +104,11 @@ func transformCRD(crd unstructured.Unstructured) CustomResourceDefinition {
 		}
 	}
 
+	categories, _, _ := unstructured.NestedStringSlice(names, "categories")
+	if categories == nil {
+		categories = []string{}
+	}
+
 	// Extract versions
 	versions := spec["versions"].([]interface{})
 	activeVersion := ""
@@ -126,6 +151,7 @@ func transformCRD(crd unstructured.Unstructured) CustomResourceDefinition {
 		ActiveVersion:            activeVersion,
 		AdditionalPrinterColumns: additionalPrinterColumns,
 		Age:                      age,
+		AgeMs:                    ageMs,
 		HasUpdated:               false, // This would need to be calculated based on resourceVersion changes
 		Name:                     name,
 		QueryParam:               queryParam,
@@ -139,10 +165,47 @@ func transformCRD(crd unstructured.Unstructured) CustomResourceDefinition {
 				Plural:     plural,
 				ShortNames: shortNames,
 				Singular:   singular,
+				Categories: categories,
 			},
 			Scope: scope,
 		},
-		Versions: len(versions),
-		UID:      uid,
+		Versions:      len(versions),
+		UID:           uid,
+		VersionStatus: crdVersionStatus(crd),
+	}
+}
+
+// crdVersionStatus reports whether old stored versions need a storage version migration before
+// they can be dropped from status.storedVersions and from the CRD
+func crdVersionStatus(crd unstructured.Unstructured) CRDVersionStatus {
+	status := CRDVersionStatus{Served: []string{}, Stored: []string{}, StaleStored: []string{}}
+	served := map[string]bool{}
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for _, version := range versions {
+		versionMap, ok := version.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := versionMap["name"].(string)
+		if isServed, _ := versionMap["served"].(bool); isServed {
+			status.Served = append(status.Served, name)
+			served[name] = true
+		}
+		if isStorage, _ := versionMap["storage"].(bool); isStorage {
+			status.StorageVersion = name
+		}
+	}
+	stored, _, _ := unstructured.NestedStringSlice(crd.Object, "status", "storedVersions")
+	for _, version := range stored {
+		status.Stored = append(status.Stored, version)
+		if version == status.StorageVersion {
+			continue
+		}
+		status.StaleStored = append(status.StaleStored, version)
+		if !served[version] {
+			status.UnservedStored = append(status.UnservedStored, version)
+		}
 	}
+	status.MigrationNeeded = len(status.StaleStored) > 0
+	return status
 }
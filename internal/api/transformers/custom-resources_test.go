@@ -0,0 +1,37 @@
+package transformers
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestCRDVersionStatus(t *testing.T) {
+	crd := unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"versions": []interface{}{
+				map[string]interface{}{"name": "v1alpha1", "served": false, "storage": false},
+				map[string]interface{}{"name": "v1beta1", "served": true, "storage": false},
+				map[string]interface{}{"name": "v1", "served": true, "storage": true},
+			},
+		},
+		"status": map[string]interface{}{
+			"storedVersions": []interface{}{"v1alpha1", "v1beta1", "v1"},
+		},
+	}}
+
+	status := crdVersionStatus(crd)
+	if status.StorageVersion != "v1" || !reflect.DeepEqual(status.Served, []string{"v1beta1", "v1"}) {
+		t.Errorf("unexpected served/storage versions %+v", status)
+	}
+	if !status.MigrationNeeded || !reflect.DeepEqual(status.StaleStored, []string{"v1alpha1", "v1beta1"}) ||
+		!reflect.DeepEqual(status.UnservedStored, []string{"v1alpha1"}) {
+		t.Errorf("expected stale stored versions to need migration, got %+v", status)
+	}
+
+	unstructured.SetNestedStringSlice(crd.Object, []string{"v1"}, "status", "storedVersions")
+	if status := crdVersionStatus(crd); status.MigrationNeeded || len(status.StaleStored) != 0 {
+		t.Errorf("expected no migration once only the storage version is stored, got %+v", status)
+	}
+}
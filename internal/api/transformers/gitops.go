@@ -0,0 +1,43 @@
+package transformers
+
+import (
+	"strings"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/types"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Labels and annotations GitOps controllers stamp on the resources they apply
+const (
+	argoCDTrackingIDAnnotation = "argocd.argoproj.io/tracking-id"
+	argoCDInstanceLabel        = "argocd.argoproj.io/instance"
+	fluxKustomizationName      = "kustomize.toolkit.fluxcd.io/name"
+	fluxKustomizationNamespace = "kustomize.toolkit.fluxcd.io/namespace"
+	fluxHelmReleaseName        = "helm.toolkit.fluxcd.io/name"
+	fluxHelmReleaseNamespace   = "helm.toolkit.fluxcd.io/namespace"
+)
+
+// GitOpsOwnerFor returns the Argo CD or Flux application managing an object, or nil if it is not GitOps managed.
+// app.kubernetes.io/instance is deliberately ignored because Helm sets it on every release.
+func GitOpsOwnerFor(meta metav1.ObjectMeta) *types.GitOpsOwner {
+	if name := meta.Labels[fluxHelmReleaseName]; name != "" {
+		return &types.GitOpsOwner{Tool: "flux", Kind: "HelmRelease", Name: name, Namespace: meta.Labels[fluxHelmReleaseNamespace]}
+	}
+	if name := meta.Labels[fluxKustomizationName]; name != "" {
+		return &types.GitOpsOwner{Tool: "flux", Kind: "Kustomization", Name: name, Namespace: meta.Labels[fluxKustomizationNamespace]}
+	}
+	// Tracking IDs look like "<app>:<group>/<kind>:<namespace>/<name>", where <app> may be "<namespace>_<name>"
+	if trackingID := meta.Annotations[argoCDTrackingIDAnnotation]; trackingID != "" {
+		app, _, _ := strings.Cut(trackingID, ":")
+		owner := &types.GitOpsOwner{Tool: "argocd", Kind: "Application", Name: app}
+		if ns, name, ok := strings.Cut(app, "_"); ok {
+			owner.Namespace, owner.Name = ns, name
+		}
+		return owner
+	}
+	if app := meta.Labels[argoCDInstanceLabel]; app != "" {
+		return &types.GitOpsOwner{Tool: "argocd", Kind: "Application", Name: app}
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package transformers
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/types"
+
+	appsV1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+)
+
+// Service meshes
+const (
+	MeshIstio   = "istio"
+	MeshLinkerd = "linkerd"
+)
+
+// Data plane modes
+const (
+	MeshModeSidecar       = "sidecar"
+	MeshModeNativeSidecar = "native-sidecar" // proxy runs as a restartable init container
+	MeshModeAmbient       = "ambient"
+)
+
+const (
+	istioProxyContainer   = "istio-proxy"
+	linkerdProxyContainer = "linkerd-proxy"
+	istiodContainer       = "discovery"
+	destinationContainer  = "destination"
+	// istioStatusAnnotation is written by the Istio injector and records the injecting revision
+	istioStatusAnnotation = "sidecar.istio.io/status"
+	istioRevisionLabel    = "istio.io/rev"
+	defaultIstioRevision  = "default"
+	// linkerdVersionAnnotation is written by the Linkerd injector with the proxy version
+	linkerdVersionAnnotation = "linkerd.io/proxy-version"
+	ambientAnnotation        = "ambient.istio.io/redirection"
+)
+
+// Label selectors of the control plane deployments that tell which proxy version is current
+const (
+	IstiodSelector             = "app=istiod"
+	LinkerdDestinationSelector = "linkerd.io/control-plane-component=destination"
+)
+
+// meshProxy finds a proxy container among the pod's containers or restartable init containers
+func meshProxy(pod *v1.Pod, name string) (*v1.Container, bool) {
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == name {
+			return &pod.Spec.Containers[i], false
+		}
+	}
+	for i := range pod.Spec.InitContainers {
+		c := &pod.Spec.InitContainers[i]
+		if c.Name == name && c.RestartPolicy != nil && *c.RestartPolicy == v1.ContainerRestartPolicyAlways {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// PodMeshSidecar detects the Istio or Linkerd data plane of a pod, or returns nil
+func PodMeshSidecar(pod *v1.Pod) *types.MeshSidecar {
+	if proxy, native := meshProxy(pod, istioProxyContainer); proxy != nil {
+		sidecar := &types.MeshSidecar{Mesh: MeshIstio, Mode: MeshModeSidecar, Revision: IstioRevision(pod), Version: MeshVersion(proxy.Image)}
+		if native {
+			sidecar.Mode = MeshModeNativeSidecar
+		}
+		return sidecar
+	}
+	if proxy, native := meshProxy(pod, linkerdProxyContainer); proxy != nil {
+		sidecar := &types.MeshSidecar{Mesh: MeshLinkerd, Mode: MeshModeSidecar, Version: pod.Annotations[linkerdVersionAnnotation]}
+		if sidecar.Version == "" {
+			sidecar.Version = MeshVersion(proxy.Image)
+		}
+		if native {
+			sidecar.Mode = MeshModeNativeSidecar
+		}
+		return sidecar
+	}
+	if pod.Annotations[ambientAnnotation] == "enabled" {
+		return &types.MeshSidecar{Mesh: MeshIstio, Mode: MeshModeAmbient}
+	}
+	return nil
+}
+
+// IstioRevision returns the Istio revision that injected a pod
+func IstioRevision(pod *v1.Pod) string {
+	var status struct {
+		Revision string `json:"revision"`
+	}
+	if raw := pod.Annotations[istioStatusAnnotation]; raw != "" && json.Unmarshal([]byte(raw), &status) == nil && status.Revision != "" {
+		return status.Revision
+	}
+	if rev := pod.Labels[istioRevisionLabel]; rev != "" {
+		return rev
+	}
+	return defaultIstioRevision
+}
+
+// MeshVersion returns the version in a proxy or control plane image tag, without variant
+// suffixes such as -distroless
+func MeshVersion(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	slash := strings.LastIndex(image, "/")
+	colon := strings.LastIndex(image, ":")
+	if colon <= slash {
+		return ""
+	}
+	tag := image[colon+1:]
+	for _, suffix := range []string{"-distroless", "-debug"} {
+		tag = strings.TrimSuffix(tag, suffix)
+	}
+	return tag
+}
+
+// MeshControlPlaneVersions maps the Istio revisions and Linkerd control plane found among the
+// istiod and linkerd-destination deployments to the proxy version they inject
+func MeshControlPlaneVersions(deployments []appsV1.Deployment) map[string]string {
+	versions := map[string]string{}
+	for i := range deployments {
+		d := &deployments[i]
+		template := &d.Spec.Template
+		switch {
+		case d.Labels["app"] == "istiod":
+			revision := d.Labels[istioRevisionLabel]
+			if revision == "" {
+				revision = defaultIstioRevision
+			}
+			for _, c := range template.Spec.Containers {
+				if c.Name == istiodContainer {
+					versions[MeshIstio+"/"+revision] = MeshVersion(c.Image)
+				}
+			}
+		case d.Labels["linkerd.io/control-plane-component"] == "destination":
+			// The control plane runs the proxy of its own release
+			version := template.Annotations[linkerdVersionAnnotation]
+			if version == "" {
+				for _, c := range template.Spec.Containers {
+					if c.Name == destinationContainer {
+						version = MeshVersion(c.Image)
+					}
+				}
+			}
+			versions[MeshLinkerd] = version
+		}
+	}
+	return versions
+}
+
+// ApplyMeshVersionSkew records the version of the sidecar's control plane and flags a mismatch
+func ApplyMeshVersionSkew(sidecar *types.MeshSidecar, controlPlanes map[string]string) {
+	if sidecar == nil || sidecar.Mode == MeshModeAmbient {
+		return
+	}
+	key := MeshLinkerd
+	if sidecar.Mesh == MeshIstio {
+		key = MeshIstio + "/" + sidecar.Revision
+	}
+	version := controlPlanes[key]
+	if version == "" {
+		return
+	}
+	sidecar.ControlPlaneVersion = version
+	sidecar.VersionSkew = sidecar.Version != "" && sidecar.Version != version
+}
@@ -0,0 +1,104 @@
+package transformers
+
+import (
+	"testing"
+
+	appsV1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMeshVersion(t *testing.T) {
+	tests := map[string]string{
+		"docker.io/istio/proxyv2:1.20.3":                 "1.20.3",
+		"gcr.io/istio-release/proxyv2:1.22.0-distroless": "1.22.0",
+		"cr.l5d.io/linkerd/proxy:stable-2.14.10":         "stable-2.14.10",
+		"registry:5000/istio/proxyv2":                    "",
+		"istio/proxyv2:1.21.1@sha256:abc":                "1.21.1",
+	}
+	for image, want := range tests {
+		if got := MeshVersion(image); got != want {
+			t.Errorf("MeshVersion(%q) = %q, want %q", image, got, want)
+		}
+	}
+}
+
+func TestPodMeshSidecar(t *testing.T) {
+	always := v1.ContainerRestartPolicyAlways
+	istio := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"sidecar.istio.io/status": `{"containers":["istio-proxy"],"revision":"canary"}`}},
+		Spec: v1.PodSpec{Containers: []v1.Container{
+			{Name: "app", Image: "app:1"},
+			{Name: "istio-proxy", Image: "docker.io/istio/proxyv2:1.20.3"},
+		}},
+	}
+	native := &v1.Pod{Spec: v1.PodSpec{
+		InitContainers: []v1.Container{{Name: "istio-proxy", Image: "istio/proxyv2:1.22.0", RestartPolicy: &always}},
+		Containers:     []v1.Container{{Name: "app"}},
+	}}
+	linkerd := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"linkerd.io/proxy-version": "stable-2.14.9"}},
+		Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "app"}, {Name: "linkerd-proxy", Image: "cr.l5d.io/linkerd/proxy:stable-2.14.9"}}},
+	}
+	ambient := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"ambient.istio.io/redirection": "enabled"}}}
+
+	if got := PodMeshSidecar(istio); got == nil || got.Mesh != MeshIstio || got.Mode != MeshModeSidecar || got.Revision != "canary" || got.Version != "1.20.3" {
+		t.Errorf("istio sidecar = %+v", got)
+	}
+	if got := PodMeshSidecar(native); got == nil || got.Mode != MeshModeNativeSidecar || got.Revision != "default" {
+		t.Errorf("native istio sidecar = %+v", got)
+	}
+	if got := PodMeshSidecar(linkerd); got == nil || got.Mesh != MeshLinkerd || got.Version != "stable-2.14.9" {
+		t.Errorf("linkerd sidecar = %+v", got)
+	}
+	if got := PodMeshSidecar(ambient); got == nil || got.Mode != MeshModeAmbient {
+		t.Errorf("ambient pod = %+v", got)
+	}
+	if got := PodMeshSidecar(&v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{{Name: "app"}}}}); got != nil {
+		t.Errorf("pod without a mesh = %+v, want nil", got)
+	}
+}
+
+func TestApplyMeshVersionSkew(t *testing.T) {
+	deployments := []appsV1.Deployment{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "istiod-canary", Labels: map[string]string{"app": "istiod", "istio.io/rev": "canary"}},
+			Spec:       appsV1.DeploymentSpec{Template: v1.PodTemplateSpec{Spec: v1.PodSpec{Containers: []v1.Container{{Name: "discovery", Image: "docker.io/istio/pilot:1.21.0"}}}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "linkerd-destination", Labels: map[string]string{"linkerd.io/control-plane-component": "destination"}},
+			Spec: appsV1.DeploymentSpec{Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"linkerd.io/proxy-version": "stable-2.14.10"}},
+			}},
+		},
+	}
+	versions := MeshControlPlaneVersions(deployments)
+	if versions["istio/canary"] != "1.21.0" || versions["linkerd"] != "stable-2.14.10" {
+		t.Fatalf("MeshControlPlaneVersions() = %v", versions)
+	}
+
+	istio := PodMeshSidecar(&v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"istio.io/rev": "canary"}},
+		Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "istio-proxy", Image: "istio/proxyv2:1.21.0"}}},
+	})
+	ApplyMeshVersionSkew(istio, versions)
+	if istio.VersionSkew || istio.ControlPlaneVersion != "1.21.0" {
+		t.Errorf("current istio sidecar = %+v, want no skew", istio)
+	}
+
+	linkerd := PodMeshSidecar(&v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"linkerd.io/proxy-version": "stable-2.14.9"}},
+		Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "linkerd-proxy"}}},
+	})
+	ApplyMeshVersionSkew(linkerd, versions)
+	if !linkerd.VersionSkew {
+		t.Errorf("outdated linkerd sidecar = %+v, want skew", linkerd)
+	}
+
+	// Sidecars of a revision whose control plane is unknown are not flagged
+	unknown := PodMeshSidecar(&v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{{Name: "istio-proxy", Image: "istio/proxyv2:1.19.0"}}}})
+	ApplyMeshVersionSkew(unknown, versions)
+	if unknown.VersionSkew || unknown.ControlPlaneVersion != "" {
+		t.Errorf("sidecar of an unknown revision = %+v", unknown)
+	}
+}
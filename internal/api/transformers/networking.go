@@ -32,6 +32,7 @@ func TransformServiceToResponse(service *v1.Service) types.ServiceListResponse {
 
 	return types.ServiceListResponse{
 		Age:        age,
+		AgeMs:      types.EpochMillis(service.CreationTimestamp.Time),
 		HasUpdated: false, // This would be set based on resource version comparison
 		Name:       service.Name,
 		Namespace:  service.Namespace,
@@ -82,6 +83,7 @@ func TransformIngressToResponse(ingress *networkingV1.Ingress) types.IngressList
 
 	return types.IngressListResponse{
 		Age:        age,
+		AgeMs:      types.EpochMillis(ingress.CreationTimestamp.Time),
 		HasUpdated: false, // This would be set based on resource version comparison
 		Name:       ingress.Name,
 		Namespace:  ingress.Namespace,
@@ -114,6 +116,7 @@ func TransformEndpointToResponse(endpoint *v1.Endpoints) types.EndpointListRespo
 
 	return types.EndpointListResponse{
 		Age:        age,
+		AgeMs:      types.EpochMillis(endpoint.CreationTimestamp.Time),
 		HasUpdated: false, // This would be set based on resource version comparison
 		Name:       endpoint.Name,
 		Namespace:  endpoint.Namespace,
@@ -0,0 +1,90 @@
+package transformers
+
+import v1 "k8s.io/api/core/v1"
+
+// nearMemoryLimitRatio is the share of its memory limit a pod may use before it is flagged as at risk of OOM
+const nearMemoryLimitRatio = 0.9
+
+// nodePressureConditions are the node conditions that indicate the kubelet may start evicting pods
+var nodePressureConditions = []v1.NodeConditionType{
+	v1.NodeMemoryPressure,
+	v1.NodeDiskPressure,
+	v1.NodePIDPressure,
+}
+
+// PodQOSClass returns the pod's QoS class, computing it from container resources when the
+// status does not carry one yet (e.g. pods that have not been admitted by a kubelet)
+func PodQOSClass(pod *v1.Pod) v1.PodQOSClass {
+	if pod.Status.QOSClass != "" {
+		return pod.Status.QOSClass
+	}
+
+	containers := append(append([]v1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	hasResources := false
+	guaranteed := len(containers) > 0
+	for _, container := range containers {
+		for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+			request, hasRequest := container.Resources.Requests[name]
+			limit, hasLimit := container.Resources.Limits[name]
+			if (hasRequest && !request.IsZero()) || (hasLimit && !limit.IsZero()) {
+				hasResources = true
+			}
+			// A missing request defaults to the limit, so only a differing request breaks Guaranteed
+			if !hasLimit || limit.IsZero() || (hasRequest && request.Cmp(limit) != 0) {
+				guaranteed = false
+			}
+		}
+	}
+
+	switch {
+	case !hasResources:
+		return v1.PodQOSBestEffort
+	case guaranteed:
+		return v1.PodQOSGuaranteed
+	default:
+		return v1.PodQOSBurstable
+	}
+}
+
+// PodMemoryLimit returns the sum of the memory limits of the pod's containers, or 0 when
+// any container is unbounded and the pod as a whole has no effective limit
+func PodMemoryLimit(pod *v1.Pod) int64 {
+	var total int64
+	for _, container := range pod.Spec.Containers {
+		limit, ok := container.Resources.Limits[v1.ResourceMemory]
+		if !ok || limit.IsZero() {
+			return 0
+		}
+		total += limit.Value()
+	}
+	return total
+}
+
+// NearMemoryLimit reports whether memory usage is close enough to the limit that the pod risks being OOM killed
+func NearMemoryLimit(usageBytes, limitBytes int64) bool {
+	return limitBytes > 0 && float64(usageBytes) >= float64(limitBytes)*nearMemoryLimitRatio
+}
+
+// NodePressure returns the pressure conditions (MemoryPressure, DiskPressure, PIDPressure) currently true on a node
+func NodePressure(node *v1.Node) []string {
+	var pressure []string
+	for _, conditionType := range nodePressureConditions {
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == conditionType && condition.Status == v1.ConditionTrue {
+				pressure = append(pressure, string(conditionType))
+			}
+		}
+	}
+	return pressure
+}
+
+// PressuredNodes maps the names of nodes under pressure to their active pressure conditions
+func PressuredNodes(nodes []v1.Node) map[string][]string {
+	pressured := make(map[string][]string)
+	for i := range nodes {
+		if pressure := NodePressure(&nodes[i]); len(pressure) > 0 {
+			pressured[nodes[i].Name] = pressure
+		}
+	}
+	return pressured
+}
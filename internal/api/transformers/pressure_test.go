@@ -0,0 +1,89 @@
+package transformers
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func containerWith(requests, limits v1.ResourceList) v1.Container {
+	return v1.Container{Name: "app", Resources: v1.ResourceRequirements{Requests: requests, Limits: limits}}
+}
+
+func TestPodQOSClass(t *testing.T) {
+	cpuMem := func(cpu, mem string) v1.ResourceList {
+		return v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu), v1.ResourceMemory: resource.MustParse(mem)}
+	}
+	tests := []struct {
+		name       string
+		containers []v1.Container
+		status     v1.PodQOSClass
+		expected   v1.PodQOSClass
+	}{
+		{"status wins", []v1.Container{containerWith(nil, nil)}, v1.PodQOSGuaranteed, v1.PodQOSGuaranteed},
+		{"no resources", []v1.Container{containerWith(nil, nil)}, "", v1.PodQOSBestEffort},
+		{"requests equal limits", []v1.Container{containerWith(cpuMem("100m", "128Mi"), cpuMem("100m", "128Mi"))}, "", v1.PodQOSGuaranteed},
+		{"limits only", []v1.Container{containerWith(nil, cpuMem("100m", "128Mi"))}, "", v1.PodQOSGuaranteed},
+		{"requests below limits", []v1.Container{containerWith(cpuMem("50m", "64Mi"), cpuMem("100m", "128Mi"))}, "", v1.PodQOSBurstable},
+		{"requests only", []v1.Container{containerWith(cpuMem("50m", "64Mi"), nil)}, "", v1.PodQOSBurstable},
+		{"one unbounded container", []v1.Container{
+			containerWith(cpuMem("100m", "128Mi"), cpuMem("100m", "128Mi")),
+			containerWith(nil, nil),
+		}, "", v1.PodQOSBurstable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &v1.Pod{Spec: v1.PodSpec{Containers: tt.containers}, Status: v1.PodStatus{QOSClass: tt.status}}
+			if got := PodQOSClass(pod); got != tt.expected {
+				t.Errorf("PodQOSClass() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPodMemoryLimitAndNearMemoryLimit(t *testing.T) {
+	mem := func(q string) v1.ResourceList { return v1.ResourceList{v1.ResourceMemory: resource.MustParse(q)} }
+	pod := &v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{
+		containerWith(nil, mem("100Mi")),
+		containerWith(nil, mem("50Mi")),
+	}}}
+	limit := PodMemoryLimit(pod)
+	if limit != 150*1024*1024 {
+		t.Fatalf("PodMemoryLimit() = %d, want %d", limit, 150*1024*1024)
+	}
+	if NearMemoryLimit(100*1024*1024, limit) {
+		t.Error("usage at 67% of the limit should not be flagged")
+	}
+	if !NearMemoryLimit(140*1024*1024, limit) {
+		t.Error("usage at 93% of the limit should be flagged")
+	}
+
+	pod.Spec.Containers = append(pod.Spec.Containers, containerWith(nil, nil))
+	if limit := PodMemoryLimit(pod); limit != 0 {
+		t.Errorf("PodMemoryLimit() with an unbounded container = %d, want 0", limit)
+	}
+	if NearMemoryLimit(1<<40, 0) {
+		t.Error("pods without a limit should never be flagged")
+	}
+}
+
+func TestPressuredNodes(t *testing.T) {
+	node := func(name string, conditions ...v1.NodeCondition) v1.Node {
+		return v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}, Status: v1.NodeStatus{Conditions: conditions}}
+	}
+	nodes := []v1.Node{
+		node("healthy",
+			v1.NodeCondition{Type: v1.NodeReady, Status: v1.ConditionTrue},
+			v1.NodeCondition{Type: v1.NodeMemoryPressure, Status: v1.ConditionFalse}),
+		node("squeezed",
+			v1.NodeCondition{Type: v1.NodeDiskPressure, Status: v1.ConditionTrue},
+			v1.NodeCondition{Type: v1.NodeMemoryPressure, Status: v1.ConditionTrue}),
+	}
+	want := map[string][]string{"squeezed": {"MemoryPressure", "DiskPressure"}}
+	if got := PressuredNodes(nodes); !reflect.DeepEqual(got, want) {
+		t.Errorf("PressuredNodes() = %v, want %v", got, want)
+	}
+}
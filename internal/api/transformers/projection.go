@@ -0,0 +1,110 @@
+package transformers
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// identityFields are always kept by a projection so clients can key and diff table rows
+var identityFields = []string{"name", "namespace", "uid"}
+
+// Projection is a parsed fields parameter: the keys to keep, with nested keys for dotted fields.
+// A nil subtree keeps the whole value.
+type Projection map[string]Projection
+
+// ParseFields parses a comma-separated projection such as "name,status,restarts,node.zone".
+// It returns nil when no projection is requested.
+func ParseFields(value string) Projection {
+	var tree Projection
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if tree == nil {
+			tree = Projection{}
+			for _, key := range identityFields {
+				tree[key] = nil
+			}
+		}
+		node := tree
+		parts := strings.Split(field, ".")
+		for i, part := range parts {
+			sub, exists := node[part]
+			if i == len(parts)-1 {
+				// A shorter path already keeps the whole value
+				if !exists || sub != nil {
+					node[part] = nil
+				}
+				break
+			}
+			if exists && sub == nil {
+				break
+			}
+			if sub == nil {
+				sub = Projection{}
+				node[part] = sub
+			}
+			node = sub
+		}
+	}
+	return tree
+}
+
+// MarshalProjected marshals list response data, keeping only the projected fields of every item.
+// Responses that are not lists are returned whole.
+func MarshalProjected(data interface{}, fields Projection) ([]byte, error) {
+	raw, err := json.Marshal(data)
+	if err != nil || fields == nil {
+		return raw, err
+	}
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) == 0 || trimmed[0] != '[' {
+		return raw, nil
+	}
+	return projectRaw(raw, fields)
+}
+
+// projectRaw keeps the projected keys of an object, projecting arrays element-wise
+func projectRaw(raw json.RawMessage, fields Projection) (json.RawMessage, error) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 {
+		return raw, nil
+	}
+	switch trimmed[0] {
+	case '[':
+		var items []json.RawMessage
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return nil, err
+		}
+		for i, item := range items {
+			projected, err := projectRaw(item, fields)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = projected
+		}
+		return json.Marshal(items)
+	case '{':
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(trimmed, &object); err != nil {
+			return nil, err
+		}
+		projected := make(map[string]json.RawMessage, len(fields))
+		for key, sub := range fields {
+			value, ok := object[key]
+			if !ok {
+				continue
+			}
+			if sub != nil {
+				var err error
+				if value, err = projectRaw(value, sub); err != nil {
+					return nil, err
+				}
+			}
+			projected[key] = value
+		}
+		return json.Marshal(projected)
+	}
+	return raw, nil
+}
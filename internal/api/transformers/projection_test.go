@@ -0,0 +1,57 @@
+package transformers
+
+import (
+	"testing"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/types"
+)
+
+func TestParseFields(t *testing.T) {
+	if ParseFields(" , ") != nil {
+		t.Error("expected no projection for an empty fields parameter")
+	}
+	fields := ParseFields("status,node.zone,node,restarts.count")
+	for _, key := range []string{"name", "namespace", "uid", "status"} {
+		if sub, ok := fields[key]; !ok || sub != nil {
+			t.Errorf("expected %q to be kept whole", key)
+		}
+	}
+	if sub, ok := fields["node"]; !ok || sub != nil {
+		t.Error("expected node to be kept whole after a shorter path was requested")
+	}
+	if sub := fields["restarts"]; sub == nil || len(sub) != 1 {
+		t.Errorf("expected nested projection for restarts, got %v", sub)
+	}
+}
+
+func TestMarshalProjected(t *testing.T) {
+	pods := []types.PodListResponse{{
+		BaseResponse: types.BaseResponse{Name: "web-0", UID: "u1", Age: "1d"},
+		Namespace:    "shop",
+		Node:         "node-a",
+	}}
+	got, err := MarshalProjected(pods, ParseFields("node"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `[{"name":"web-0","namespace":"shop","node":"node-a","uid":"u1"}]`; string(got) != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	nested, err := MarshalProjected([]map[string]interface{}{{"name": "a", "spec": map[string]interface{}{"replicas": 2, "paused": false}}}, ParseFields("spec.replicas"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `[{"name":"a","spec":{"replicas":2}}]`; string(nested) != want {
+		t.Errorf("got %s, want %s", nested, want)
+	}
+
+	// Non-list responses are returned whole
+	object, err := MarshalProjected(map[string]int{"total": 3}, ParseFields("name"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"total":3}`; string(object) != want {
+		t.Errorf("got %s, want %s", object, want)
+	}
+}
@@ -0,0 +1,119 @@
+package transformers
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/types"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// Restart causes a container termination is classified as
+const (
+	RestartCauseOOMKilled  = "OOMKilled"
+	RestartCauseError      = "Error"
+	RestartCauseCompleted  = "Completed"
+	RestartCauseNodeReboot = "NodeReboot"
+	RestartCauseUnknown    = "Unknown"
+)
+
+// rebootGrace is how long after a node reboot the kubelet may take to record the terminations it caused
+const rebootGrace = 10 * time.Minute
+
+// NodeReboots returns the times of the Rebooted events the kubelet records on nodes, keyed by node name
+func NodeReboots(events []v1.Event) map[string][]time.Time {
+	reboots := make(map[string][]time.Time)
+	for _, event := range events {
+		if event.InvolvedObject.Kind != "Node" || event.Reason != "Rebooted" {
+			continue
+		}
+		at := event.LastTimestamp.Time
+		if at.IsZero() {
+			at = event.EventTime.Time
+		}
+		if at.IsZero() {
+			at = event.FirstTimestamp.Time
+		}
+		if !at.IsZero() {
+			reboots[event.InvolvedObject.Name] = append(reboots[event.InvolvedObject.Name], at)
+		}
+	}
+	return reboots
+}
+
+// ClassifyTermination returns why a container terminated. A termination that is neither an OOM kill
+// nor a clean exit is put down to a node reboot when the node rebooted while the container ran.
+func ClassifyTermination(terminated *v1.ContainerStateTerminated, reboots []time.Time) string {
+	if terminated == nil {
+		return RestartCauseUnknown
+	}
+	switch {
+	case terminated.Reason == "OOMKilled":
+		return RestartCauseOOMKilled
+	case terminated.Reason == "Completed" || (terminated.Reason == "" && terminated.ExitCode == 0):
+		return RestartCauseCompleted
+	}
+	for _, reboot := range reboots {
+		if !terminated.StartedAt.IsZero() && reboot.Before(terminated.StartedAt.Time) {
+			continue
+		}
+		if !terminated.FinishedAt.IsZero() && reboot.After(terminated.FinishedAt.Add(rebootGrace)) {
+			continue
+		}
+		return RestartCauseNodeReboot
+	}
+	if terminated.Reason == "Error" || terminated.ExitCode != 0 {
+		return RestartCauseError
+	}
+	return RestartCauseUnknown
+}
+
+// PodRestartCauses breaks a pod's restarts down by cause. Kubernetes only keeps the last termination
+// of each container, so that one is classified and a container's earlier restarts count as Unknown.
+// reboots are the reboot times of the pod's node, if known.
+func PodRestartCauses(pod *v1.Pod, reboots []time.Time) []types.RestartCauseCount {
+	type causeKey struct {
+		cause    string
+		exitCode int32
+	}
+	counts := make(map[causeKey]int32)
+	statuses := append(append([]v1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if status.RestartCount <= 0 {
+			continue
+		}
+		terminated := status.LastTerminationState.Terminated
+		key := causeKey{cause: ClassifyTermination(terminated, reboots)}
+		if key.cause == RestartCauseError {
+			key.exitCode = terminated.ExitCode
+		}
+		counts[key]++
+		if earlier := status.RestartCount - 1; earlier > 0 {
+			counts[causeKey{cause: RestartCauseUnknown}] += earlier
+		}
+	}
+	if len(counts) == 0 {
+		return nil
+	}
+
+	causes := make([]types.RestartCauseCount, 0, len(counts))
+	for key, count := range counts {
+		entry := types.RestartCauseCount{Cause: key.cause, Count: count}
+		if key.cause == RestartCauseError {
+			exitCode := key.exitCode
+			entry.ExitCode = &exitCode
+		}
+		causes = append(causes, entry)
+	}
+	sort.Slice(causes, func(i, j int) bool {
+		if causes[i].Count != causes[j].Count {
+			return causes[i].Count > causes[j].Count
+		}
+		if causes[i].Cause != causes[j].Cause {
+			return causes[i].Cause < causes[j].Cause
+		}
+		return causes[i].ExitCode != nil && causes[j].ExitCode != nil && *causes[i].ExitCode < *causes[j].ExitCode
+	})
+	return causes
+}
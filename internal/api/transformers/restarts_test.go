@@ -0,0 +1,79 @@
+package transformers
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestClassifyTermination(t *testing.T) {
+	started := time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC)
+	terminated := func(reason string, exitCode int32) *v1.ContainerStateTerminated {
+		return &v1.ContainerStateTerminated{
+			Reason:     reason,
+			ExitCode:   exitCode,
+			StartedAt:  metav1.NewTime(started),
+			FinishedAt: metav1.NewTime(started.Add(time.Hour)),
+		}
+	}
+	during := []time.Time{started.Add(30 * time.Minute)}
+	before := []time.Time{started.Add(-time.Hour)}
+
+	tests := []struct {
+		name       string
+		terminated *v1.ContainerStateTerminated
+		reboots    []time.Time
+		want       string
+	}{
+		{"no termination recorded", nil, nil, RestartCauseUnknown},
+		{"oom kill", terminated("OOMKilled", 137), during, RestartCauseOOMKilled},
+		{"clean exit", terminated("Completed", 0), nil, RestartCauseCompleted},
+		{"error", terminated("Error", 1), nil, RestartCauseError},
+		{"error during reboot", terminated("Unknown", 255), during, RestartCauseNodeReboot},
+		{"error after an earlier reboot", terminated("Error", 2), before, RestartCauseError},
+	}
+	for _, tt := range tests {
+		if got := ClassifyTermination(tt.terminated, tt.reboots); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPodRestartCauses(t *testing.T) {
+	pod := &v1.Pod{Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{
+		{Name: "app", RestartCount: 3, LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}}},
+		{Name: "sidecar", RestartCount: 1, LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}}},
+		{Name: "idle"},
+	}}}
+	causes := PodRestartCauses(pod, nil)
+	if len(causes) != 3 {
+		t.Fatalf("causes = %+v", causes)
+	}
+	if causes[0].Cause != RestartCauseUnknown || causes[0].Count != 2 {
+		t.Errorf("earlier restarts should count as unknown first: %+v", causes[0])
+	}
+	if causes[1].Cause != RestartCauseError || causes[1].ExitCode == nil || *causes[1].ExitCode != 1 {
+		t.Errorf("errors should carry their exit code: %+v", causes[1])
+	}
+	if causes[2].Cause != RestartCauseOOMKilled || causes[2].ExitCode != nil {
+		t.Errorf("unexpected cause: %+v", causes[2])
+	}
+	if PodRestartCauses(&v1.Pod{}, nil) != nil {
+		t.Error("a pod without restarts should have no causes")
+	}
+}
+
+func TestNodeReboots(t *testing.T) {
+	at := time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC)
+	events := []v1.Event{
+		{InvolvedObject: v1.ObjectReference{Kind: "Node", Name: "node-a"}, Reason: "Rebooted", LastTimestamp: metav1.NewTime(at)},
+		{InvolvedObject: v1.ObjectReference{Kind: "Node", Name: "node-a"}, Reason: "NodeReady", LastTimestamp: metav1.NewTime(at)},
+		{InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "node-b"}, Reason: "Rebooted", LastTimestamp: metav1.NewTime(at)},
+	}
+	reboots := NodeReboots(events)
+	if len(reboots) != 1 || len(reboots["node-a"]) != 1 || !reboots["node-a"][0].Equal(at) {
+		t.Errorf("reboots = %v", reboots)
+	}
+}
@@ -0,0 +1,105 @@
+package transformers
+
+import (
+	"net"
+	"strings"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/types"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// ContainerRuntimeIDs lists the runtime IDs of a pod's init, regular and ephemeral containers, in
+// spec order. Container IDs have the form <runtime>://<id>.
+func ContainerRuntimeIDs(pod *v1.Pod) []types.ContainerRuntimeID {
+	statuses := map[string]v1.ContainerStatus{}
+	for _, list := range [][]v1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses, pod.Status.EphemeralContainerStatuses} {
+		for _, status := range list {
+			statuses[status.Name] = status
+		}
+	}
+	ids := []types.ContainerRuntimeID{}
+	add := func(name, kind string) {
+		entry := types.ContainerRuntimeID{Name: name, Type: kind}
+		if status, ok := statuses[name]; ok {
+			entry.Runtime, entry.ID = splitContainerID(status.ContainerID)
+			entry.ImageID = status.ImageID
+		}
+		ids = append(ids, entry)
+	}
+	for _, container := range pod.Spec.InitContainers {
+		add(container.Name, "init")
+	}
+	for _, container := range pod.Spec.Containers {
+		add(container.Name, "container")
+	}
+	for _, container := range pod.Spec.EphemeralContainers {
+		add(container.Name, "ephemeral")
+	}
+	return ids
+}
+
+// splitContainerID splits a container ID into its runtime and ID
+func splitContainerID(containerID string) (string, string) {
+	runtime, id, found := strings.Cut(containerID, "://")
+	if !found {
+		return "", containerID
+	}
+	return runtime, id
+}
+
+// ipFamily returns IPv4 or IPv6 for an IP address, or an empty string if it does not parse
+func ipFamily(ip string) string {
+	parsed := net.ParseIP(ip)
+	switch {
+	case parsed == nil:
+		return ""
+	case parsed.To4() != nil:
+		return string(v1.IPv4Protocol)
+	default:
+		return string(v1.IPv6Protocol)
+	}
+}
+
+// PodSandbox returns the sandbox details of a pod
+func PodSandbox(pod *v1.Pod) types.PodSandboxDetails {
+	details := types.PodSandboxDetails{
+		Name:              pod.Name,
+		Namespace:         pod.Namespace,
+		Phase:             string(pod.Status.Phase),
+		NodeName:          pod.Spec.NodeName,
+		NominatedNodeName: pod.Status.NominatedNodeName,
+		HostIP:            pod.Status.HostIP,
+		HostIPs:           []string{},
+		PodIP:             pod.Status.PodIP,
+		PodIPs:            []string{},
+		IPFamilies:        []string{},
+		HostNetwork:       pod.Spec.HostNetwork,
+		DNSPolicy:         string(pod.Spec.DNSPolicy),
+		Containers:        ContainerRuntimeIDs(pod),
+	}
+	if pod.Spec.RuntimeClassName != nil {
+		details.RuntimeClassName = *pod.Spec.RuntimeClassName
+	}
+	for _, ip := range pod.Status.HostIPs {
+		details.HostIPs = append(details.HostIPs, ip.IP)
+	}
+	if len(details.HostIPs) == 0 && pod.Status.HostIP != "" {
+		details.HostIPs = append(details.HostIPs, pod.Status.HostIP)
+	}
+	for _, ip := range pod.Status.PodIPs {
+		details.PodIPs = append(details.PodIPs, ip.IP)
+	}
+	if len(details.PodIPs) == 0 && pod.Status.PodIP != "" {
+		details.PodIPs = append(details.PodIPs, pod.Status.PodIP)
+	}
+	families := map[string]bool{}
+	for _, ip := range details.PodIPs {
+		if family := ipFamily(ip); family != "" {
+			details.IPFamilies = append(details.IPFamilies, family)
+			families[family] = true
+		}
+	}
+	details.DualStack = len(families) == 2
+	return details
+}
@@ -0,0 +1,51 @@
+package transformers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/types"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodSandbox(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+		Spec: v1.PodSpec{
+			NodeName:       "node-a",
+			InitContainers: []v1.Container{{Name: "migrate"}},
+			Containers:     []v1.Container{{Name: "app"}, {Name: "sidecar"}},
+		},
+		Status: v1.PodStatus{
+			Phase:                 v1.PodRunning,
+			HostIP:                "10.0.0.5",
+			PodIP:                 "192.168.1.7",
+			PodIPs:                []v1.PodIP{{IP: "192.168.1.7"}, {IP: "fd00::7"}},
+			InitContainerStatuses: []v1.ContainerStatus{{Name: "migrate", ContainerID: "containerd://abc", ImageID: "sha256:1"}},
+			ContainerStatuses:     []v1.ContainerStatus{{Name: "app", ContainerID: "cri-o://def"}, {Name: "sidecar"}},
+		},
+	}
+
+	details := PodSandbox(pod)
+	if !details.DualStack || !reflect.DeepEqual(details.IPFamilies, []string{"IPv4", "IPv6"}) {
+		t.Errorf("expected a dual-stack pod, got %v / %v", details.DualStack, details.IPFamilies)
+	}
+	if !reflect.DeepEqual(details.HostIPs, []string{"10.0.0.5"}) {
+		t.Errorf("host IPs should fall back to hostIP, got %v", details.HostIPs)
+	}
+	want := []types.ContainerRuntimeID{
+		{Name: "migrate", Type: "init", Runtime: "containerd", ID: "abc", ImageID: "sha256:1"},
+		{Name: "app", Type: "container", Runtime: "cri-o", ID: "def"},
+		{Name: "sidecar", Type: "container"},
+	}
+	if !reflect.DeepEqual(details.Containers, want) {
+		t.Errorf("containers = %+v, want %+v", details.Containers, want)
+	}
+
+	pending := PodSandbox(&v1.Pod{Status: v1.PodStatus{NominatedNodeName: "node-b"}})
+	if pending.NominatedNodeName != "node-b" || pending.DualStack || len(pending.PodIPs) != 0 {
+		t.Errorf("unexpected details for a pending pod: %+v", pending)
+	}
+}
@@ -0,0 +1,346 @@
+package transformers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/types"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// Severities of security risks, highest first
+const (
+	RiskHigh   = "high"
+	RiskMedium = "medium"
+	RiskLow    = "low"
+	RiskNone   = "none"
+)
+
+var riskRank = map[string]int{RiskHigh: 3, RiskMedium: 2, RiskLow: 1, RiskNone: 0}
+
+// appArmorAnnotationPrefix is the pre-1.30 way of setting a container's AppArmor profile
+const appArmorAnnotationPrefix = "container.apparmor.security.beta.kubernetes.io/"
+
+// dangerousCapabilities are capabilities that let a container escape or control its node
+var dangerousCapabilities = map[string]bool{
+	"ALL":             true,
+	"SYS_ADMIN":       true,
+	"SYS_MODULE":      true,
+	"SYS_PTRACE":      true,
+	"SYS_RAWIO":       true,
+	"SYS_BOOT":        true,
+	"SYS_TIME":        true,
+	"NET_ADMIN":       true,
+	"DAC_READ_SEARCH": true,
+	"BPF":             true,
+	"PERFMON":         true,
+	"MKNOD":           true,
+}
+
+// safeSysctls are the namespaced sysctls the kubelet allows without opting in to unsafe ones
+var safeSysctls = map[string]bool{
+	"kernel.shm_rmid_forced":              true,
+	"net.ipv4.ip_local_port_range":        true,
+	"net.ipv4.ip_local_reserved_ports":    true,
+	"net.ipv4.tcp_syncookies":             true,
+	"net.ipv4.ping_group_range":           true,
+	"net.ipv4.ip_unprivileged_port_start": true,
+	"net.ipv4.tcp_keepalive_time":         true,
+	"net.ipv4.tcp_fin_timeout":            true,
+	"net.ipv4.tcp_keepalive_intvl":        true,
+	"net.ipv4.tcp_keepalive_probes":       true,
+}
+
+// PodSecurity flattens the security settings of a pod and its containers and flags the risky ones
+func PodSecurity(pod *v1.Pod) types.PodSecurityDetails {
+	podSC := pod.Spec.SecurityContext
+	if podSC == nil {
+		podSC = &v1.PodSecurityContext{}
+	}
+	details := types.PodSecurityDetails{
+		Name:                         pod.Name,
+		Namespace:                    pod.Namespace,
+		ServiceAccountName:           pod.Spec.ServiceAccountName,
+		AutomountServiceAccountToken: pod.Spec.AutomountServiceAccountToken == nil || *pod.Spec.AutomountServiceAccountToken,
+		HostNetwork:                  pod.Spec.HostNetwork,
+		HostPID:                      pod.Spec.HostPID,
+		HostIPC:                      pod.Spec.HostIPC,
+		HostUsers:                    pod.Spec.HostUsers == nil || *pod.Spec.HostUsers,
+		FSGroup:                      podSC.FSGroup,
+		SupplementalGroups:           append([]int64{}, podSC.SupplementalGroups...),
+		Sysctls:                      []string{},
+		HostPathVolumes:              []string{},
+		Containers:                   []types.ContainerSecurity{},
+		Risks:                        []types.SecurityRisk{},
+	}
+
+	if pod.Spec.HostNetwork {
+		details.Risks = append(details.Risks, types.SecurityRisk{Severity: RiskHigh, Field: "hostNetwork", Message: "Pod shares the node's network namespace"})
+	}
+	if pod.Spec.HostPID {
+		details.Risks = append(details.Risks, types.SecurityRisk{Severity: RiskHigh, Field: "hostPID", Message: "Pod can see and signal every process on the node"})
+	}
+	if pod.Spec.HostIPC {
+		details.Risks = append(details.Risks, types.SecurityRisk{Severity: RiskHigh, Field: "hostIPC", Message: "Pod shares the node's IPC namespace"})
+	}
+	for _, volume := range pod.Spec.Volumes {
+		if volume.HostPath != nil {
+			details.HostPathVolumes = append(details.HostPathVolumes, volume.HostPath.Path)
+			details.Risks = append(details.Risks, types.SecurityRisk{
+				Severity: RiskHigh,
+				Field:    "volumes." + volume.Name,
+				Message:  fmt.Sprintf("Mounts host path %s from the node", volume.HostPath.Path),
+			})
+		}
+	}
+	for _, sysctl := range podSC.Sysctls {
+		details.Sysctls = append(details.Sysctls, sysctl.Name+"="+sysctl.Value)
+		if !safeSysctls[sysctl.Name] {
+			details.Risks = append(details.Risks, types.SecurityRisk{
+				Severity: RiskMedium,
+				Field:    "securityContext.sysctls",
+				Message:  fmt.Sprintf("Sets unsafe sysctl %s", sysctl.Name),
+			})
+		}
+	}
+	if details.AutomountServiceAccountToken {
+		details.Risks = append(details.Risks, types.SecurityRisk{Severity: RiskLow, Field: "automountServiceAccountToken", Message: "Service account token is mounted into the containers"})
+	}
+	sortRisks(details.Risks)
+
+	highest := highestRisk(details.Risks)
+	add := func(container *v1.Container, kind string) {
+		security := containerSecurity(pod, podSC, container, kind)
+		details.Containers = append(details.Containers, security)
+		if level := highestRisk(security.Risks); riskRank[level] > riskRank[highest] {
+			highest = level
+		}
+	}
+	for i := range pod.Spec.InitContainers {
+		add(&pod.Spec.InitContainers[i], "init")
+	}
+	for i := range pod.Spec.Containers {
+		add(&pod.Spec.Containers[i], "container")
+	}
+	for i := range pod.Spec.EphemeralContainers {
+		container := v1.Container(pod.Spec.EphemeralContainers[i].EphemeralContainerCommon)
+		add(&container, "ephemeral")
+	}
+	details.HighestRisk = highest
+	return details
+}
+
+// containerSecurity merges the pod and container security contexts of a container; container
+// settings win over pod settings
+func containerSecurity(pod *v1.Pod, podSC *v1.PodSecurityContext, container *v1.Container, kind string) types.ContainerSecurity {
+	sc := container.SecurityContext
+	if sc == nil {
+		sc = &v1.SecurityContext{}
+	}
+	security := types.ContainerSecurity{
+		Name:                container.Name,
+		Type:                kind,
+		Privileged:          sc.Privileged != nil && *sc.Privileged,
+		CapabilitiesAdded:   []string{},
+		CapabilitiesDropped: []string{},
+		Sources:             map[string]string{},
+		Risks:               []types.SecurityRisk{},
+	}
+	source := func(field string, fromContainer bool) {
+		if fromContainer {
+			security.Sources[field] = "container"
+		} else {
+			security.Sources[field] = "pod"
+		}
+	}
+
+	switch {
+	case sc.RunAsUser != nil:
+		security.RunAsUser = sc.RunAsUser
+		source("runAsUser", true)
+	case podSC.RunAsUser != nil:
+		security.RunAsUser = podSC.RunAsUser
+		source("runAsUser", false)
+	}
+	switch {
+	case sc.RunAsGroup != nil:
+		security.RunAsGroup = sc.RunAsGroup
+		source("runAsGroup", true)
+	case podSC.RunAsGroup != nil:
+		security.RunAsGroup = podSC.RunAsGroup
+		source("runAsGroup", false)
+	}
+	switch {
+	case sc.RunAsNonRoot != nil:
+		security.RunAsNonRoot = *sc.RunAsNonRoot
+		source("runAsNonRoot", true)
+	case podSC.RunAsNonRoot != nil:
+		security.RunAsNonRoot = *podSC.RunAsNonRoot
+		source("runAsNonRoot", false)
+	}
+	switch {
+	case sc.SeccompProfile != nil:
+		security.SeccompProfile = seccompProfile(sc.SeccompProfile)
+		source("seccompProfile", true)
+	case podSC.SeccompProfile != nil:
+		security.SeccompProfile = seccompProfile(podSC.SeccompProfile)
+		source("seccompProfile", false)
+	}
+	switch {
+	case sc.AppArmorProfile != nil:
+		security.AppArmorProfile = appArmorProfile(sc.AppArmorProfile)
+		source("appArmorProfile", true)
+	case pod.Annotations[appArmorAnnotationPrefix+container.Name] != "":
+		security.AppArmorProfile = appArmorAnnotation(pod.Annotations[appArmorAnnotationPrefix+container.Name])
+		source("appArmorProfile", false)
+	case podSC.AppArmorProfile != nil:
+		security.AppArmorProfile = appArmorProfile(podSC.AppArmorProfile)
+		source("appArmorProfile", false)
+	}
+	switch {
+	case sc.SELinuxOptions != nil && sc.SELinuxOptions.Type != "":
+		security.SELinuxType = sc.SELinuxOptions.Type
+		source("seLinuxOptions", true)
+	case podSC.SELinuxOptions != nil && podSC.SELinuxOptions.Type != "":
+		security.SELinuxType = podSC.SELinuxOptions.Type
+		source("seLinuxOptions", false)
+	}
+
+	if sc.Capabilities != nil {
+		for _, capability := range sc.Capabilities.Add {
+			security.CapabilitiesAdded = append(security.CapabilitiesAdded, capabilityName(capability))
+		}
+		for _, capability := range sc.Capabilities.Drop {
+			security.CapabilitiesDropped = append(security.CapabilitiesDropped, capabilityName(capability))
+		}
+	}
+	sort.Strings(security.CapabilitiesAdded)
+	sort.Strings(security.CapabilitiesDropped)
+	security.ReadOnlyRootFilesystem = sc.ReadOnlyRootFilesystem != nil && *sc.ReadOnlyRootFilesystem
+	if sc.ProcMount != nil {
+		security.ProcMount = string(*sc.ProcMount)
+	}
+
+	// Privileged containers and those with CAP_SYS_ADMIN can always escalate; otherwise Linux
+	// allows it unless it is turned off
+	addsSysAdmin := false
+	for _, capability := range security.CapabilitiesAdded {
+		addsSysAdmin = addsSysAdmin || capability == "SYS_ADMIN" || capability == "ALL"
+	}
+	security.AllowPrivilegeEscalation = sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation || security.Privileged || addsSysAdmin
+
+	security.Risks = containerRisks(&security)
+	return security
+}
+
+// containerRisks flags the risky settings of an effective container security context
+func containerRisks(security *types.ContainerSecurity) []types.SecurityRisk {
+	risks := []types.SecurityRisk{}
+	flag := func(severity, field, message string) {
+		risks = append(risks, types.SecurityRisk{Severity: severity, Field: field, Message: message})
+	}
+
+	if security.Privileged {
+		flag(RiskHigh, "securityContext.privileged", "Container is privileged and has full access to the node")
+	}
+	for _, capability := range security.CapabilitiesAdded {
+		if dangerousCapabilities[capability] {
+			flag(RiskHigh, "securityContext.capabilities.add", fmt.Sprintf("Adds capability %s", capability))
+		} else {
+			flag(RiskLow, "securityContext.capabilities.add", fmt.Sprintf("Adds capability %s", capability))
+		}
+	}
+	if security.ProcMount == string(v1.UnmaskedProcMount) {
+		flag(RiskHigh, "securityContext.procMount", "/proc is not masked")
+	}
+	switch {
+	case security.RunAsUser != nil && *security.RunAsUser == 0:
+		flag(RiskHigh, "securityContext.runAsUser", "Runs as root")
+	case security.RunAsUser == nil && !security.RunAsNonRoot:
+		flag(RiskMedium, "securityContext.runAsNonRoot", "May run as root; the image decides the user")
+	}
+	if security.AllowPrivilegeEscalation && !security.Privileged {
+		flag(RiskMedium, "securityContext.allowPrivilegeEscalation", "Processes can gain more privileges than their parent")
+	}
+	switch {
+	case security.SeccompProfile == string(v1.SeccompProfileTypeUnconfined):
+		flag(RiskMedium, "securityContext.seccompProfile", "Seccomp is disabled")
+	case security.SeccompProfile == "":
+		flag(RiskLow, "securityContext.seccompProfile", "No seccomp profile is set; the runtime may not apply one")
+	}
+	if security.AppArmorProfile == string(v1.AppArmorProfileTypeUnconfined) {
+		flag(RiskMedium, "securityContext.appArmorProfile", "AppArmor is disabled")
+	}
+	if !dropsAll(security.CapabilitiesDropped) {
+		flag(RiskLow, "securityContext.capabilities.drop", "Keeps the runtime's default capabilities; drop ALL and add back what is needed")
+	}
+	if !security.ReadOnlyRootFilesystem {
+		flag(RiskLow, "securityContext.readOnlyRootFilesystem", "Root filesystem is writable")
+	}
+	sortRisks(risks)
+	return risks
+}
+
+// capabilityName normalizes a capability to its name without the CAP_ prefix
+func capabilityName(capability v1.Capability) string {
+	return strings.TrimPrefix(strings.ToUpper(string(capability)), "CAP_")
+}
+
+func dropsAll(dropped []string) bool {
+	for _, capability := range dropped {
+		if capability == "ALL" {
+			return true
+		}
+	}
+	return false
+}
+
+func seccompProfile(profile *v1.SeccompProfile) string {
+	if profile.Type == v1.SeccompProfileTypeLocalhost && profile.LocalhostProfile != nil {
+		return string(profile.Type) + "/" + *profile.LocalhostProfile
+	}
+	return string(profile.Type)
+}
+
+func appArmorProfile(profile *v1.AppArmorProfile) string {
+	if profile.Type == v1.AppArmorProfileTypeLocalhost && profile.LocalhostProfile != nil {
+		return string(profile.Type) + "/" + *profile.LocalhostProfile
+	}
+	return string(profile.Type)
+}
+
+// appArmorAnnotation converts an AppArmor annotation value to the profile type it stands for
+func appArmorAnnotation(value string) string {
+	switch {
+	case value == "runtime/default":
+		return string(v1.AppArmorProfileTypeRuntimeDefault)
+	case value == "unconfined":
+		return string(v1.AppArmorProfileTypeUnconfined)
+	case strings.HasPrefix(value, "localhost/"):
+		return string(v1.AppArmorProfileTypeLocalhost) + "/" + strings.TrimPrefix(value, "localhost/")
+	}
+	return value
+}
+
+// sortRisks orders risks by severity, highest first, then by field
+func sortRisks(risks []types.SecurityRisk) {
+	sort.SliceStable(risks, func(i, j int) bool {
+		if riskRank[risks[i].Severity] != riskRank[risks[j].Severity] {
+			return riskRank[risks[i].Severity] > riskRank[risks[j].Severity]
+		}
+		return risks[i].Field < risks[j].Field
+	})
+}
+
+// highestRisk returns the highest severity among risks, or none
+func highestRisk(risks []types.SecurityRisk) string {
+	highest := RiskNone
+	for _, risk := range risks {
+		if riskRank[risk.Severity] > riskRank[highest] {
+			highest = risk.Severity
+		}
+	}
+	return highest
+}
@@ -0,0 +1,98 @@
+package transformers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/types"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodSecurity(t *testing.T) {
+	root := int64(0)
+	user := int64(1000)
+	yes, no := true, false
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "web",
+			Namespace:   "shop",
+			Annotations: map[string]string{appArmorAnnotationPrefix + "agent": "unconfined"},
+		},
+		Spec: v1.PodSpec{
+			HostPID:                      true,
+			AutomountServiceAccountToken: &no,
+			SecurityContext: &v1.PodSecurityContext{
+				RunAsUser:      &user,
+				RunAsNonRoot:   &yes,
+				SeccompProfile: &v1.SeccompProfile{Type: v1.SeccompProfileTypeRuntimeDefault},
+				Sysctls:        []v1.Sysctl{{Name: "net.ipv4.tcp_syncookies", Value: "1"}, {Name: "kernel.msgmax", Value: "65536"}},
+			},
+			Volumes: []v1.Volume{{Name: "docker", VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: "/var/run/docker.sock"}}}},
+			Containers: []v1.Container{
+				{Name: "app", SecurityContext: &v1.SecurityContext{
+					AllowPrivilegeEscalation: &no,
+					ReadOnlyRootFilesystem:   &yes,
+					Capabilities:             &v1.Capabilities{Drop: []v1.Capability{"ALL"}, Add: []v1.Capability{"NET_BIND_SERVICE"}},
+				}},
+				{Name: "agent", SecurityContext: &v1.SecurityContext{
+					RunAsUser:    &root,
+					Capabilities: &v1.Capabilities{Add: []v1.Capability{"CAP_SYS_ADMIN"}},
+				}},
+			},
+		},
+	}
+
+	details := PodSecurity(pod)
+	if details.HighestRisk != RiskHigh || details.AutomountServiceAccountToken || !details.HostPID {
+		t.Errorf("unexpected pod-level details: %+v", details)
+	}
+	if !reflect.DeepEqual(details.HostPathVolumes, []string{"/var/run/docker.sock"}) {
+		t.Errorf("hostPath volumes = %v", details.HostPathVolumes)
+	}
+	if got := fields(details.Risks); !reflect.DeepEqual(got, []string{"hostPID", "volumes.docker", "securityContext.sysctls"}) {
+		t.Errorf("pod risks = %v", got)
+	}
+
+	app := details.Containers[0]
+	if app.RunAsUser == nil || *app.RunAsUser != 1000 || app.Sources["runAsUser"] != "pod" || app.SeccompProfile != "RuntimeDefault" {
+		t.Errorf("app should inherit the pod's user and seccomp profile: %+v", app)
+	}
+	if app.AllowPrivilegeEscalation || !app.ReadOnlyRootFilesystem {
+		t.Errorf("app flags = %+v", app)
+	}
+	if got := fields(app.Risks); !reflect.DeepEqual(got, []string{"securityContext.capabilities.add"}) || app.Risks[0].Severity != RiskLow {
+		t.Errorf("app risks = %+v", app.Risks)
+	}
+
+	agent := details.Containers[1]
+	if agent.RunAsUser == nil || *agent.RunAsUser != 0 || agent.Sources["runAsUser"] != "container" {
+		t.Errorf("agent should override the pod's user: %+v", agent)
+	}
+	if !agent.AllowPrivilegeEscalation || !reflect.DeepEqual(agent.CapabilitiesAdded, []string{"SYS_ADMIN"}) {
+		t.Errorf("CAP_SYS_ADMIN should imply privilege escalation: %+v", agent)
+	}
+	if agent.AppArmorProfile != "Unconfined" {
+		t.Errorf("AppArmor profile = %q, want the annotation's Unconfined", agent.AppArmorProfile)
+	}
+	want := []string{
+		"securityContext.capabilities.add",
+		"securityContext.runAsUser",
+		"securityContext.allowPrivilegeEscalation",
+		"securityContext.appArmorProfile",
+		"securityContext.capabilities.drop",
+		"securityContext.readOnlyRootFilesystem",
+	}
+	if got := fields(agent.Risks); !reflect.DeepEqual(got, want) {
+		t.Errorf("agent risks = %v, want %v", got, want)
+	}
+}
+
+func fields(risks []types.SecurityRisk) []string {
+	out := []string{}
+	for _, risk := range risks {
+		out = append(out, risk.Field)
+	}
+	return out
+}
@@ -0,0 +1,124 @@
+package transformers
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SortSpec is a parsed sort parameter such as "-age" or "node.zone": the path of the key to
+// sort list items by, descending when prefixed with "-"
+type SortSpec struct {
+	Path       []string
+	Descending bool
+}
+
+// ParseSort parses a sort parameter. It returns nil when no sort is requested.
+func ParseSort(value string) *SortSpec {
+	value = strings.TrimSpace(value)
+	spec := &SortSpec{}
+	if strings.HasPrefix(value, "-") {
+		spec.Descending = true
+		value = value[1:]
+	}
+	if value == "" {
+		return nil
+	}
+	spec.Path = strings.Split(value, ".")
+	return spec
+}
+
+// SortRaw sorts a marshaled JSON array by the spec's key. Numbers, and strings that hold numbers
+// such as restart counts, compare numerically; other values compare as text. Items without the
+// key sort last in either direction. Anything other than an array is returned unchanged.
+func SortRaw(raw []byte, spec *SortSpec) ([]byte, error) {
+	trimmed := bytes.TrimSpace(raw)
+	if spec == nil || len(trimmed) == 0 || trimmed[0] != '[' {
+		return raw, nil
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(trimmed, &items); err != nil {
+		return nil, err
+	}
+
+	type keyed struct {
+		item    json.RawMessage
+		present bool
+		number  *float64
+		text    string
+	}
+	rows := make([]keyed, len(items))
+	for i, item := range items {
+		rows[i].item = item
+		value, ok := lookupRaw(item, spec.Path)
+		if !ok {
+			continue
+		}
+		rows[i].present = true
+		switch v := value.(type) {
+		case float64:
+			rows[i].number = &v
+		case string:
+			if n, err := strconv.ParseFloat(v, 64); err == nil {
+				rows[i].number = &n
+			}
+			rows[i].text = v
+		case bool:
+			rows[i].text = strconv.FormatBool(v)
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if a.present != b.present {
+			return a.present
+		}
+		var less, greater bool
+		if a.number != nil && b.number != nil {
+			less, greater = *a.number < *b.number, *a.number > *b.number
+		} else {
+			less, greater = a.text < b.text, a.text > b.text
+		}
+		if spec.Descending {
+			return greater
+		}
+		return less
+	})
+	for i := range rows {
+		items[i] = rows[i].item
+	}
+	return json.Marshal(items)
+}
+
+// lookupRaw returns the value at a dotted path of a JSON object
+func lookupRaw(raw json.RawMessage, path []string) (interface{}, bool) {
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, false
+	}
+	for _, key := range path {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = object[key]; !ok || value == nil {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// MarshalList marshals list response data sorted by spec, then keeps the projected fields of every
+// item. Sorting first lets clients sort by a key the projection drops.
+func MarshalList(data interface{}, spec *SortSpec, fields Projection) ([]byte, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	if raw, err = SortRaw(raw, spec); err != nil {
+		return nil, err
+	}
+	return MarshalProjected(json.RawMessage(raw), fields)
+}
@@ -0,0 +1,62 @@
+package transformers
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseSort(t *testing.T) {
+	if ParseSort(" ") != nil || ParseSort("-") != nil {
+		t.Error("expected no sort for an empty sort parameter")
+	}
+	spec := ParseSort("-node.zone")
+	if spec == nil || !spec.Descending || !reflect.DeepEqual(spec.Path, []string{"node", "zone"}) {
+		t.Errorf("ParseSort(-node.zone) = %+v", spec)
+	}
+}
+
+func TestSortRaw(t *testing.T) {
+	raw := []byte(`[
+		{"name":"a","restarts":"10","node":{"zone":"b"}},
+		{"name":"b","restarts":"9"},
+		{"name":"c","restarts":"100","node":{"zone":"a"}}
+	]`)
+	names := func(sorted []byte) []string {
+		var items []struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(sorted, &items); err != nil {
+			t.Fatal(err)
+		}
+		var out []string
+		for _, item := range items {
+			out = append(out, item.Name)
+		}
+		return out
+	}
+	tests := []struct {
+		sort string
+		want []string
+	}{
+		{"restarts", []string{"b", "a", "c"}},
+		{"-restarts", []string{"c", "a", "b"}},
+		{"node.zone", []string{"c", "a", "b"}},
+		{"-node.zone", []string{"a", "c", "b"}},
+		{"missing", []string{"a", "b", "c"}},
+	}
+	for _, tt := range tests {
+		sorted, err := SortRaw(raw, ParseSort(tt.sort))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := names(sorted); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("sort=%s: got %v, want %v", tt.sort, got, tt.want)
+		}
+	}
+
+	object := []byte(`{"name":"a"}`)
+	if sorted, _ := SortRaw(object, ParseSort("name")); string(sorted) != string(object) {
+		t.Error("non-array responses should be returned unchanged")
+	}
+}
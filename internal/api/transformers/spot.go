@@ -0,0 +1,42 @@
+package transformers
+
+import v1 "k8s.io/api/core/v1"
+
+// spotLabels are the node labels cloud providers and autoscalers use to mark spot or
+// preemptible capacity, with the value that indicates it ("" means any value)
+var spotLabels = []struct {
+	key, value, provider string
+}{
+	{"eks.amazonaws.com/capacityType", "SPOT", "aws"},
+	{"karpenter.sh/capacity-type", "spot", "karpenter"},
+	{"cloud.google.com/gke-spot", "true", "gcp"},
+	{"cloud.google.com/gke-preemptible", "true", "gcp"},
+	{"kubernetes.azure.com/scalesetpriority", "spot", "azure"},
+	{"spotinst.io/node-lifecycle", "spot", "spot-ocean"},
+	{"node.kubernetes.io/lifecycle", "spot", "generic"},
+	{"lifecycle", "Ec2Spot", "aws"},
+	{"node-role.kubernetes.io/spot-worker", "", "generic"},
+}
+
+// SpotProvider returns which provider marks a node with these labels as spot or
+// preemptible capacity, or "" for on-demand nodes
+func SpotProvider(labels map[string]string) string {
+	for _, l := range spotLabels {
+		value, ok := labels[l.key]
+		if ok && (l.value == "" || value == l.value) {
+			return l.provider
+		}
+	}
+	return ""
+}
+
+// SpotNodeNames returns the names of the nodes running on spot or preemptible capacity
+func SpotNodeNames(nodes []v1.Node) map[string]bool {
+	spot := make(map[string]bool)
+	for i := range nodes {
+		if SpotProvider(nodes[i].Labels) != "" {
+			spot[nodes[i].Name] = true
+		}
+	}
+	return spot
+}
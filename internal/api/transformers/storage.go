@@ -47,6 +47,7 @@ func TransformPVCToResponse(pvc *v1.PersistentVolumeClaim) types.PersistentVolum
 
 	return types.PersistentVolumeClaimListResponse{
 		Age:        age,
+		AgeMs:      types.EpochMillis(pvc.CreationTimestamp.Time),
 		HasUpdated: false, // This would be set based on resource version comparison
 		Name:       pvc.Name,
 		Namespace:  pvc.Namespace,
@@ -109,6 +110,7 @@ func TransformPVToResponse(pv *v1.PersistentVolume) types.PersistentVolumeListRe
 
 	return types.PersistentVolumeListResponse{
 		Age:        age,
+		AgeMs:      types.EpochMillis(pv.CreationTimestamp.Time),
 		HasUpdated: false, // This would be set based on resource version comparison
 		Name:       pv.Name,
 		UID:        string(pv.UID),
@@ -149,6 +151,7 @@ func TransformStorageClassToResponse(sc *storageV1.StorageClass) types.StorageCl
 
 	return types.StorageClassListResponse{
 		Age:               age,
+		AgeMs:             types.EpochMillis(sc.CreationTimestamp.Time),
 		HasUpdated:        false, // This would be set based on resource version comparison
 		Name:              sc.Name,
 		UID:               string(sc.UID),
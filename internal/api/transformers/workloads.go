@@ -177,17 +177,16 @@ func TransformPodToResponse(pod *v1.Pod, configName, clusterName string) types.P
 	podIP := pod.Status.PodIP
 
 	// Get QoS class
-	qos := string(v1.PodQOSBestEffort)
-	if pod.Status.QOSClass != "" {
-		qos = string(pod.Status.QOSClass)
-	}
+	qos := string(PodQOSClass(pod))
 
 	return types.PodListResponse{
 		BaseResponse: types.BaseResponse{
 			Age:        age,
+			AgeMs:      types.EpochMillis(pod.CreationTimestamp.Time),
 			HasUpdated: false,
 			Name:       pod.Name,
 			UID:        string(pod.UID),
+			GitOps:     GitOpsOwnerFor(pod.ObjectMeta),
 		},
 		Namespace:         pod.Namespace,
 		Node:              pod.Spec.NodeName,
@@ -198,8 +197,10 @@ func TransformPodToResponse(pod *v1.Pod, configName, clusterName string) types.P
 		Restarts:          restarts,
 		LastRestartAt:     lastRestartAt,
 		LastRestartReason: lastRestartReason,
+		RestartCauses:     PodRestartCauses(pod, nil),
 		PodIP:             podIP,
 		QOS:               qos,
+		Mesh:              PodMeshSidecar(pod),
 		ConfigName:        configName,
 		ClusterName:       clusterName,
 	}
@@ -228,9 +229,11 @@ func TransformDeploymentToResponse(deployment *appsV1.Deployment) types.Deployme
 		NamespacedResponse: types.NamespacedResponse{
 			BaseResponse: types.BaseResponse{
 				Age:        age,
+				AgeMs:      types.EpochMillis(deployment.CreationTimestamp.Time),
 				HasUpdated: false,
 				Name:       deployment.Name,
 				UID:        string(deployment.UID),
+				GitOps:     GitOpsOwnerFor(deployment.ObjectMeta),
 			},
 			Namespace: deployment.Namespace,
 		},
@@ -266,9 +269,11 @@ func TransformDaemonSetToResponse(daemonSet *appsV1.DaemonSet) types.DaemonSetLi
 		NamespacedResponse: types.NamespacedResponse{
 			BaseResponse: types.BaseResponse{
 				Age:        age,
+				AgeMs:      types.EpochMillis(daemonSet.CreationTimestamp.Time),
 				HasUpdated: false,
 				Name:       daemonSet.Name,
 				UID:        string(daemonSet.UID),
+				GitOps:     GitOpsOwnerFor(daemonSet.ObjectMeta),
 			},
 			Namespace: daemonSet.Namespace,
 		},
@@ -300,9 +305,11 @@ func TransformStatefulSetToResponse(statefulSet *appsV1.StatefulSet) types.State
 		NamespacedResponse: types.NamespacedResponse{
 			BaseResponse: types.BaseResponse{
 				Age:        age,
+				AgeMs:      types.EpochMillis(statefulSet.CreationTimestamp.Time),
 				HasUpdated: false,
 				Name:       statefulSet.Name,
 				UID:        string(statefulSet.UID),
+				GitOps:     GitOpsOwnerFor(statefulSet.ObjectMeta),
 			},
 			Namespace: statefulSet.Namespace,
 		},
@@ -330,9 +337,11 @@ func TransformReplicaSetToResponse(replicaSet *appsV1.ReplicaSet) types.ReplicaS
 		NamespacedResponse: types.NamespacedResponse{
 			BaseResponse: types.BaseResponse{
 				Age:        age,
+				AgeMs:      types.EpochMillis(replicaSet.CreationTimestamp.Time),
 				HasUpdated: false,
 				Name:       replicaSet.Name,
 				UID:        string(replicaSet.UID),
+				GitOps:     GitOpsOwnerFor(replicaSet.ObjectMeta),
 			},
 			Namespace: replicaSet.Namespace,
 		},
@@ -352,6 +361,36 @@ func TransformReplicaSetToResponse(replicaSet *appsV1.ReplicaSet) types.ReplicaS
 	}
 }
 
+// TransformReplicationControllerToResponse transforms a Kubernetes replication controller to the frontend-expected format
+func TransformReplicationControllerToResponse(controller *v1.ReplicationController) types.ReplicationControllerListResponse {
+	age := types.TimeFormat(controller.CreationTimestamp.Time)
+
+	response := types.ReplicationControllerListResponse{
+		NamespacedResponse: types.NamespacedResponse{
+			BaseResponse: types.BaseResponse{
+				Age:        age,
+				AgeMs:      types.EpochMillis(controller.CreationTimestamp.Time),
+				HasUpdated: false,
+				Name:       controller.Name,
+				UID:        string(controller.UID),
+				GitOps:     GitOpsOwnerFor(controller.ObjectMeta),
+			},
+			Namespace: controller.Namespace,
+		},
+	}
+	response.Spec.Replicas = 1
+	if controller.Spec.Replicas != nil {
+		response.Spec.Replicas = *controller.Spec.Replicas
+	}
+	response.Spec.Selector = controller.Spec.Selector
+	response.Status.Replicas = controller.Status.Replicas
+	response.Status.FullyLabeledReplicas = controller.Status.FullyLabeledReplicas
+	response.Status.ReadyReplicas = controller.Status.ReadyReplicas
+	response.Status.AvailableReplicas = controller.Status.AvailableReplicas
+	response.Status.ObservedGeneration = controller.Status.ObservedGeneration
+	return response
+}
+
 // TransformJobToResponse transforms a Kubernetes job to the frontend-expected format
 func TransformJobToResponse(job *batchV1.Job) types.JobListResponse {
 	age := types.TimeFormat(job.CreationTimestamp.Time)
@@ -375,9 +414,11 @@ func TransformJobToResponse(job *batchV1.Job) types.JobListResponse {
 		NamespacedResponse: types.NamespacedResponse{
 			BaseResponse: types.BaseResponse{
 				Age:        age,
+				AgeMs:      types.EpochMillis(job.CreationTimestamp.Time),
 				HasUpdated: false,
 				Name:       job.Name,
 				UID:        string(job.UID),
+				GitOps:     GitOpsOwnerFor(job.ObjectMeta),
 			},
 			Namespace: job.Namespace,
 		},
@@ -429,9 +470,11 @@ func TransformCronJobToResponse(cronJob *batchV1.CronJob) types.CronJobListRespo
 		NamespacedResponse: types.NamespacedResponse{
 			BaseResponse: types.BaseResponse{
 				Age:        age,
+				AgeMs:      types.EpochMillis(cronJob.CreationTimestamp.Time),
 				HasUpdated: false,
 				Name:       cronJob.Name,
 				UID:        string(cronJob.UID),
+				GitOps:     GitOpsOwnerFor(cronJob.ObjectMeta),
 			},
 			Namespace: cronJob.Namespace,
 		},
@@ -1,13 +1,42 @@
 package types
 
-import "time"
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// legacyTimestamps keeps the timestamp formats of earlier releases for clients that parse them
+var legacyTimestamps atomic.Bool
+
+// SetLegacyTimestamps switches timestamps back to the formats of earlier releases: each
+// timestamp in its own layout and zone, and no epoch millisecond fields
+func SetLegacyTimestamps(enabled bool) {
+	legacyTimestamps.Store(enabled)
+}
+
+// LegacyTimestamps reports whether the legacy timestamp formats are in effect
+func LegacyTimestamps() bool {
+	return legacyTimestamps.Load()
+}
 
 // BaseResponse contains common fields that all resource responses share
 type BaseResponse struct {
 	Age        string `json:"age"`
+	AgeMs      int64  `json:"ageMs,omitempty"`
 	HasUpdated bool   `json:"hasUpdated"`
 	Name       string `json:"name"`
 	UID        string `json:"uid"`
+	// GitOps is set when the resource is managed by an Argo CD or Flux application
+	GitOps *GitOpsOwner `json:"gitOps,omitempty"`
+}
+
+// GitOpsOwner identifies the GitOps application that manages a resource
+type GitOpsOwner struct {
+	Tool      string `json:"tool"` // argocd or flux
+	Kind      string `json:"kind"` // Application, Kustomization or HelmRelease
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
 }
 
 // NamespacedResponse extends BaseResponse with namespace information
@@ -28,10 +57,60 @@ type Condition struct {
 	Status string `json:"status"`
 }
 
-// TimeFormat formats a time.Time to RFC3339 string, handling zero values
+// TimeFormat formats a time.Time to an RFC3339 UTC string, handling zero values
 func TimeFormat(t time.Time) string {
+	return FormatTimestamp(t, time.RFC3339)
+}
+
+// FormatTimestamp formats a timestamp under the API timestamp policy: RFC3339 in UTC, with
+// sub-second precision when t has it. In legacy mode it keeps the layout and zone the field
+// used before. Zero times format as "".
+func FormatTimestamp(t time.Time, legacyLayout string) string {
 	if t.IsZero() {
 		return ""
 	}
-	return t.Format(time.RFC3339)
+	if LegacyTimestamps() {
+		return t.Format(legacyLayout)
+	}
+	if t.Nanosecond() != 0 {
+		return t.UTC().Format(time.RFC3339Nano)
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// NormalizeTime returns t in UTC for fields that serialize time.Time directly; legacy mode
+// leaves the zone alone
+func NormalizeTime(t time.Time) time.Time {
+	if LegacyTimestamps() {
+		return t
+	}
+	return t.UTC()
+}
+
+// EpochMillis returns t as Unix milliseconds for the *Ms companion of a timestamp field. It
+// is 0, and so omitted, for zero times and in legacy mode.
+func EpochMillis(t time.Time) int64 {
+	if t.IsZero() || LegacyTimestamps() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+// ParseTimestamp reads the timestamp formats found in Kubernetes objects and tool output:
+// RFC3339 with or without fractional seconds, and Unix seconds or milliseconds
+func ParseTimestamp(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, true
+	}
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		// Anything past year 2286 in seconds is taken to be milliseconds
+		if n > 9999999999 {
+			return time.UnixMilli(n), true
+		}
+		return time.Unix(n, 0), true
+	}
+	return time.Time{}, false
 }
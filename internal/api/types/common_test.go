@@ -0,0 +1,47 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimestampPolicy(t *testing.T) {
+	zone := time.FixedZone("IST", 5*3600+1800)
+	at := time.Date(2024, 3, 1, 15, 30, 0, 0, zone)
+
+	if got := TimeFormat(at); got != "2024-03-01T10:00:00Z" {
+		t.Errorf("TimeFormat() = %q, want UTC", got)
+	}
+	if got := FormatTimestamp(at.Add(250*time.Millisecond), time.RFC3339); got != "2024-03-01T10:00:00.25Z" {
+		t.Errorf("FormatTimestamp() = %q, want fractional seconds kept", got)
+	}
+	if got := EpochMillis(at); got != at.UnixMilli() {
+		t.Errorf("EpochMillis() = %d", got)
+	}
+	if TimeFormat(time.Time{}) != "" || EpochMillis(time.Time{}) != 0 {
+		t.Error("zero times should format as empty")
+	}
+
+	legacy := LegacyTimestamps()
+	t.Cleanup(func() { SetLegacyTimestamps(legacy) })
+	SetLegacyTimestamps(true)
+	if got := TimeFormat(at); got != "2024-03-01T15:30:00+05:30" {
+		t.Errorf("legacy TimeFormat() = %q, want original zone", got)
+	}
+	if EpochMillis(at) != 0 {
+		t.Error("legacy mode should omit epoch millis")
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	want := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	for _, value := range []string{"2024-03-01T10:00:00Z", "2024-03-01T15:30:00+05:30", "1709287200", "1709287200000"} {
+		got, ok := ParseTimestamp(value)
+		if !ok || !got.Equal(want) {
+			t.Errorf("ParseTimestamp(%q) = %v, %v", value, got, ok)
+		}
+	}
+	if _, ok := ParseTimestamp("yesterday"); ok {
+		t.Error("ParseTimestamp() accepted an unknown format")
+	}
+}
@@ -3,6 +3,7 @@ package types
 // ConfigMapListResponse represents the response format expected by the frontend for configmaps
 type ConfigMapListResponse struct {
 	Age        string   `json:"age"`
+	AgeMs      int64    `json:"ageMs,omitempty"`
 	HasUpdated bool     `json:"hasUpdated"`
 	Name       string   `json:"name"`
 	Namespace  string   `json:"namespace"`
@@ -13,6 +14,7 @@ type ConfigMapListResponse struct {
 // SecretListResponse represents the response format expected by the frontend for secrets
 type SecretListResponse struct {
 	Age        string   `json:"age"`
+	AgeMs      int64    `json:"ageMs,omitempty"`
 	HasUpdated bool     `json:"hasUpdated"`
 	Name       string   `json:"name"`
 	Namespace  string   `json:"namespace"`
@@ -24,6 +26,7 @@ type SecretListResponse struct {
 // HPAListResponse represents the response format expected by the frontend for HPAs
 type HPAListResponse struct {
 	Age        string `json:"age"`
+	AgeMs      int64  `json:"ageMs,omitempty"`
 	HasUpdated bool   `json:"hasUpdated"`
 	Name       string `json:"name"`
 	Namespace  string `json:"namespace"`
@@ -37,6 +40,7 @@ type HPAListResponse struct {
 // LimitRangeListResponse represents the response format expected by the frontend for limit ranges
 type LimitRangeListResponse struct {
 	Age        string `json:"age"`
+	AgeMs      int64  `json:"ageMs,omitempty"`
 	HasUpdated bool   `json:"hasUpdated"`
 	Name       string `json:"name"`
 	Namespace  string `json:"namespace"`
@@ -49,6 +53,7 @@ type LimitRangeListResponse struct {
 // ResourceQuotaListResponse represents the response format expected by the frontend for resource quotas
 type ResourceQuotaListResponse struct {
 	Age        string `json:"age"`
+	AgeMs      int64  `json:"ageMs,omitempty"`
 	HasUpdated bool   `json:"hasUpdated"`
 	Name       string `json:"name"`
 	Namespace  string `json:"namespace"`
@@ -64,6 +69,7 @@ type ResourceQuotaListResponse struct {
 // PodDisruptionBudgetListResponse represents the response format expected by the frontend for pod disruption budgets
 type PodDisruptionBudgetListResponse struct {
 	Age        string `json:"age"`
+	AgeMs      int64  `json:"ageMs,omitempty"`
 	HasUpdated bool   `json:"hasUpdated"`
 	Name       string `json:"name"`
 	Namespace  string `json:"namespace"`
@@ -83,6 +89,7 @@ type PodDisruptionBudgetListResponse struct {
 // PriorityClassListResponse represents the response format expected by the frontend for priority classes
 type PriorityClassListResponse struct {
 	Age           string `json:"age"`
+	AgeMs         int64  `json:"ageMs,omitempty"`
 	HasUpdated    bool   `json:"hasUpdated"`
 	Name          string `json:"name"`
 	UID           string `json:"uid"`
@@ -94,6 +101,7 @@ type PriorityClassListResponse struct {
 // RuntimeClassListResponse represents the response format expected by the frontend for runtime classes
 type RuntimeClassListResponse struct {
 	Age        string `json:"age"`
+	AgeMs      int64  `json:"ageMs,omitempty"`
 	HasUpdated bool   `json:"hasUpdated"`
 	Name       string `json:"name"`
 	UID        string `json:"uid"`
@@ -103,10 +111,11 @@ type RuntimeClassListResponse struct {
 // LeaseListResponse represents the response format expected by the frontend for leases
 type LeaseListResponse struct {
 	Age                  string `json:"age"`
-	HasUpdated          bool   `json:"hasUpdated"`
-	Name                string `json:"name"`
-	Namespace           string `json:"namespace"`
-	UID                 string `json:"uid"`
-	HolderIdentity      string `json:"holderIdentity"`
+	AgeMs                int64  `json:"ageMs,omitempty"`
+	HasUpdated           bool   `json:"hasUpdated"`
+	Name                 string `json:"name"`
+	Namespace            string `json:"namespace"`
+	UID                  string `json:"uid"`
+	HolderIdentity       string `json:"holderIdentity"`
 	LeaseDurationSeconds int32  `json:"leaseDurationSeconds"`
 }
@@ -1,35 +1,33 @@
 package types
 
-import (
-	"time"
-)
-
 // HelmRelease represents a Helm release
 type HelmRelease struct {
-	Name        string    `json:"name"`
-	Namespace   string    `json:"namespace"`
-	Status      string    `json:"status"`
-	Revision    int       `json:"revision"`
-	Updated     time.Time `json:"updated"`
-	Chart       string    `json:"chart"`
-	AppVersion  string    `json:"appVersion"`
-	Version     string    `json:"version"`
-	Description string    `json:"description"`
-	Notes       string    `json:"notes"`
-	Values      string    `json:"values"`
-	Manifests   string    `json:"manifests"`
-	Deployments []string  `json:"deployments"`
+	Name        string   `json:"name"`
+	Namespace   string   `json:"namespace"`
+	Status      string   `json:"status"`
+	Revision    int      `json:"revision"`
+	Updated     string   `json:"updated"`
+	UpdatedMs   int64    `json:"updatedMs,omitempty"`
+	Chart       string   `json:"chart"`
+	AppVersion  string   `json:"appVersion"`
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	Notes       string   `json:"notes"`
+	Values      string   `json:"values"`
+	Manifests   string   `json:"manifests"`
+	Deployments []string `json:"deployments"`
 }
 
 // HelmReleaseHistory represents a Helm release revision
 type HelmReleaseHistory struct {
-	Revision    int       `json:"revision"`
-	Updated     time.Time `json:"updated"`
-	Status      string    `json:"status"`
-	Chart       string    `json:"chart"`
-	AppVersion  string    `json:"appVersion"`
-	Description string    `json:"description"`
-	IsLatest    bool      `json:"isLatest"`
+	Revision    int    `json:"revision"`
+	Updated     string `json:"updated"`
+	UpdatedMs   int64  `json:"updatedMs,omitempty"`
+	Status      string `json:"status"`
+	Chart       string `json:"chart"`
+	AppVersion  string `json:"appVersion"`
+	Description string `json:"description"`
+	IsLatest    bool   `json:"isLatest"`
 }
 
 // HelmReleaseList represents a list of Helm releases
@@ -54,6 +52,7 @@ type HelmReleaseResponse struct {
 	Status    string `json:"status"`
 	Revision  int    `json:"revision"`
 	Updated   string `json:"updated"`
+	UpdatedMs int64  `json:"updatedMs,omitempty"`
 	Chart     string `json:"chart"`
 	Version   string `json:"version"`
 }
@@ -62,6 +61,7 @@ type HelmReleaseResponse struct {
 type HelmReleaseHistoryResponse struct {
 	Revision    int    `json:"revision"`
 	Updated     string `json:"updated"`
+	UpdatedMs   int64  `json:"updatedMs,omitempty"`
 	Status      string `json:"status"`
 	Chart       string `json:"chart"`
 	AppVersion  string `json:"appVersion"`
@@ -77,6 +77,7 @@ type HelmReleaseResource struct {
 	Status     string            `json:"status"`
 	Age        string            `json:"age"`
 	Created    string            `json:"created"`
+	CreatedMs  int64             `json:"createdMs,omitempty"`
 	Labels     map[string]string `json:"labels,omitempty"`
 	APIVersion string            `json:"apiVersion,omitempty"`
 }
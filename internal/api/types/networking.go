@@ -3,6 +3,7 @@ package types
 // ServiceListResponse represents a service in the list view
 type ServiceListResponse struct {
 	Age        string `json:"age"`
+	AgeMs      int64  `json:"ageMs,omitempty"`
 	HasUpdated bool   `json:"hasUpdated"`
 	Name       string `json:"name"`
 	Namespace  string `json:"namespace"`
@@ -21,6 +22,7 @@ type ServiceListResponse struct {
 // IngressListResponse represents an ingress in the list view
 type IngressListResponse struct {
 	Age        string `json:"age"`
+	AgeMs      int64  `json:"ageMs,omitempty"`
 	HasUpdated bool   `json:"hasUpdated"`
 	Name       string `json:"name"`
 	Namespace  string `json:"namespace"`
@@ -33,6 +35,7 @@ type IngressListResponse struct {
 // EndpointListResponse represents an endpoint in the list view
 type EndpointListResponse struct {
 	Age        string `json:"age"`
+	AgeMs      int64  `json:"ageMs,omitempty"`
 	HasUpdated bool   `json:"hasUpdated"`
 	Name       string `json:"name"`
 	Namespace  string `json:"namespace"`
@@ -3,6 +3,7 @@ package types
 // PersistentVolumeClaimListResponse represents a persistent volume claim in the list view
 type PersistentVolumeClaimListResponse struct {
 	Age        string `json:"age"`
+	AgeMs      int64  `json:"ageMs,omitempty"`
 	HasUpdated bool   `json:"hasUpdated"`
 	Name       string `json:"name"`
 	Namespace  string `json:"namespace"`
@@ -24,6 +25,7 @@ type PersistentVolumeClaimListResponse struct {
 // PersistentVolumeListResponse represents a persistent volume in the list view
 type PersistentVolumeListResponse struct {
 	Age        string `json:"age"`
+	AgeMs      int64  `json:"ageMs,omitempty"`
 	HasUpdated bool   `json:"hasUpdated"`
 	Name       string `json:"name"`
 	UID        string `json:"uid"`
@@ -41,6 +43,7 @@ type PersistentVolumeListResponse struct {
 // StorageClassListResponse represents a storage class in the list view
 type StorageClassListResponse struct {
 	Age               string `json:"age"`
+	AgeMs             int64  `json:"ageMs,omitempty"`
 	HasUpdated        bool   `json:"hasUpdated"`
 	Name              string `json:"name"`
 	UID               string `json:"uid"`
@@ -1,21 +1,138 @@
 package types
 
+import v1 "k8s.io/api/core/v1"
+
 // PodListResponse represents the response format expected by the frontend for pods
 type PodListResponse struct {
 	BaseResponse
-	Namespace         string `json:"namespace"`
-	Node              string `json:"node"`
-	Ready             string `json:"ready"`
-	Status            string `json:"status"`
-	CPU               string `json:"cpu"`
-	Memory            string `json:"memory"`
-	Restarts          string `json:"restarts"`
-	LastRestartAt     string `json:"lastRestartAt"`
-	LastRestartReason string `json:"lastRestartReason"`
-	PodIP             string `json:"podIP"`
-	QOS               string `json:"qos"`
-	ConfigName        string `json:"configName"`
-	ClusterName       string `json:"clusterName"`
+	Namespace         string              `json:"namespace"`
+	Node              string              `json:"node"`
+	Ready             string              `json:"ready"`
+	Status            string              `json:"status"`
+	CPU               string              `json:"cpu"`
+	Memory            string              `json:"memory"`
+	Restarts          string              `json:"restarts"`
+	LastRestartAt     string              `json:"lastRestartAt"`
+	LastRestartReason string              `json:"lastRestartReason"`
+	RestartCauses     []RestartCauseCount `json:"restartCauses,omitempty"` // restarts broken down by cause, most frequent first
+	PodIP             string              `json:"podIP"`
+	QOS               string              `json:"qos"`
+	Spot              bool                `json:"spot,omitempty"`            // scheduled on spot/preemptible capacity
+	NearMemoryLimit   bool                `json:"nearMemoryLimit,omitempty"` // memory usage is close to the limit (from metrics)
+	NodePressure      []string            `json:"nodePressure,omitempty"`    // pressure conditions active on the pod's node
+	Mesh              *MeshSidecar        `json:"mesh,omitempty"`            // service mesh data plane, nil for pods outside a mesh
+	ConfigName        string              `json:"configName"`
+	ClusterName       string              `json:"clusterName"`
+}
+
+// MeshSidecar describes the service mesh data plane of a pod
+type MeshSidecar struct {
+	Mesh                string `json:"mesh"`                          // istio or linkerd
+	Mode                string `json:"mode"`                          // sidecar, native-sidecar or ambient
+	Revision            string `json:"revision,omitempty"`            // Istio control plane revision
+	Version             string `json:"version,omitempty"`             // proxy version
+	ControlPlaneVersion string `json:"controlPlaneVersion,omitempty"` // version of the control plane the proxy belongs to, when known
+	VersionSkew         bool   `json:"versionSkew,omitempty"`         // the proxy differs from its control plane and needs a restart to catch up
+}
+
+// RestartCauseCount counts the container restarts attributed to one cause. Errors are counted
+// per exit code.
+type RestartCauseCount struct {
+	Cause    string `json:"cause"` // OOMKilled, Error, Completed, NodeReboot or Unknown
+	ExitCode *int32 `json:"exitCode,omitempty"`
+	Count    int32  `json:"count"`
+}
+
+// PodRisk summarises the signals that put a pod at risk of eviction or OOM kills
+type PodRisk struct {
+	QOSClass        string   `json:"qosClass"`
+	MemoryUsage     int64    `json:"memoryUsage,omitempty"` // bytes, when metrics are available
+	MemoryLimit     int64    `json:"memoryLimit,omitempty"` // bytes, 0 when any container is unbounded
+	NearMemoryLimit bool     `json:"nearMemoryLimit"`
+	NodePressure    []string `json:"nodePressure,omitempty"`
+}
+
+// PodDetailResponse is a pod object augmented with its computed risk flags and the runtime IDs
+// of its containers
+type PodDetailResponse struct {
+	*v1.Pod
+	Risk                PodRisk              `json:"risk"`
+	ContainerRuntimeIDs []ContainerRuntimeID `json:"containerRuntimeIDs"`
+}
+
+// ContainerRuntimeID identifies a container of a pod in the node's container runtime
+type ContainerRuntimeID struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`              // container, init or ephemeral
+	Runtime string `json:"runtime,omitempty"` // containerd, cri-o or docker, from the container ID scheme
+	ID      string `json:"id,omitempty"`      // runtime container ID, empty until the container is created
+	ImageID string `json:"imageID,omitempty"`
+}
+
+// PodSandboxDetails describes the sandbox a pod runs in: its node, network identity and runtime
+type PodSandboxDetails struct {
+	Name              string               `json:"name"`
+	Namespace         string               `json:"namespace"`
+	Phase             string               `json:"phase"`
+	NodeName          string               `json:"nodeName,omitempty"`
+	NominatedNodeName string               `json:"nominatedNodeName,omitempty"` // node the scheduler preempted pods on, while the pod waits for it
+	HostIP            string               `json:"hostIP,omitempty"`
+	HostIPs           []string             `json:"hostIPs"`
+	PodIP             string               `json:"podIP,omitempty"`
+	PodIPs            []string             `json:"podIPs"`
+	IPFamilies        []string             `json:"ipFamilies"` // IPv4 and/or IPv6, in pod IP order
+	DualStack         bool                 `json:"dualStack"`
+	HostNetwork       bool                 `json:"hostNetwork"`
+	DNSPolicy         string               `json:"dnsPolicy,omitempty"`
+	RuntimeClassName  string               `json:"runtimeClassName,omitempty"`
+	Containers        []ContainerRuntimeID `json:"containers"`
+}
+
+// SecurityRisk flags a security setting of a pod or container
+type SecurityRisk struct {
+	Severity string `json:"severity"` // high, medium or low
+	Field    string `json:"field"`    // setting the risk comes from, e.g. securityContext.privileged
+	Message  string `json:"message"`
+}
+
+// ContainerSecurity is the effective security context of a container, with pod-level settings
+// applied where the container does not override them
+type ContainerSecurity struct {
+	Name                     string            `json:"name"`
+	Type                     string            `json:"type"` // container, init or ephemeral
+	Privileged               bool              `json:"privileged"`
+	RunAsUser                *int64            `json:"runAsUser,omitempty"` // unset when the image decides
+	RunAsGroup               *int64            `json:"runAsGroup,omitempty"`
+	RunAsNonRoot             bool              `json:"runAsNonRoot"`
+	AllowPrivilegeEscalation bool              `json:"allowPrivilegeEscalation"`
+	ReadOnlyRootFilesystem   bool              `json:"readOnlyRootFilesystem"`
+	ProcMount                string            `json:"procMount,omitempty"`
+	CapabilitiesAdded        []string          `json:"capabilitiesAdded"`
+	CapabilitiesDropped      []string          `json:"capabilitiesDropped"`
+	SeccompProfile           string            `json:"seccompProfile,omitempty"`  // RuntimeDefault, Unconfined or Localhost/<path>
+	AppArmorProfile          string            `json:"appArmorProfile,omitempty"` // RuntimeDefault, Unconfined or Localhost/<profile>
+	SELinuxType              string            `json:"seLinuxType,omitempty"`
+	Sources                  map[string]string `json:"sources"` // setting to the level it came from: pod or container
+	Risks                    []SecurityRisk    `json:"risks"`
+}
+
+// PodSecurityDetails is the flattened security posture of a pod for its security tab
+type PodSecurityDetails struct {
+	Name                         string              `json:"name"`
+	Namespace                    string              `json:"namespace"`
+	ServiceAccountName           string              `json:"serviceAccountName,omitempty"`
+	AutomountServiceAccountToken bool                `json:"automountServiceAccountToken"`
+	HostNetwork                  bool                `json:"hostNetwork"`
+	HostPID                      bool                `json:"hostPID"`
+	HostIPC                      bool                `json:"hostIPC"`
+	HostUsers                    bool                `json:"hostUsers"` // false when the pod runs in a user namespace
+	FSGroup                      *int64              `json:"fsGroup,omitempty"`
+	SupplementalGroups           []int64             `json:"supplementalGroups"`
+	Sysctls                      []string            `json:"sysctls"`
+	HostPathVolumes              []string            `json:"hostPathVolumes"`
+	Containers                   []ContainerSecurity `json:"containers"`
+	Risks                        []SecurityRisk      `json:"risks"`       // pod-level risks
+	HighestRisk                  string              `json:"highestRisk"` // highest severity across the pod and its containers, or none
 }
 
 // PodMetricsPoint represents a single datapoint for CPU/memory usage
@@ -87,6 +204,22 @@ type ReplicaSetListResponse struct {
 	} `json:"status"`
 }
 
+// ReplicationControllerListResponse represents the response format expected by the frontend for replication controllers
+type ReplicationControllerListResponse struct {
+	NamespacedResponse
+	Spec struct {
+		Replicas int32             `json:"replicas"`
+		Selector map[string]string `json:"selector"`
+	} `json:"spec"`
+	Status struct {
+		Replicas             int32 `json:"replicas"`
+		FullyLabeledReplicas int32 `json:"fullyLabeledReplicas"`
+		ReadyReplicas        int32 `json:"readyReplicas"`
+		AvailableReplicas    int32 `json:"availableReplicas"`
+		ObservedGeneration   int64 `json:"observedGeneration"`
+	} `json:"status"`
+}
+
 // JobListResponse represents the response format expected by the frontend for jobs
 type JobListResponse struct {
 	NamespacedResponse
@@ -0,0 +1,97 @@
+package utils
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+// DefaultContainerAnnotation names the container kubectl picks when none is given
+const DefaultContainerAnnotation = "kubectl.kubernetes.io/default-container"
+
+// Reasons a container was chosen by SelectDefaultContainer
+const (
+	ContainerReasonAnnotation = "annotation" // named by the default-container annotation
+	ContainerReasonOnly       = "only"       // the pod has a single container
+	ContainerReasonFirstApp   = "first-app"  // first container that is not a known sidecar
+	ContainerReasonFirst      = "first"      // every container is a known sidecar
+)
+
+// knownSidecars are containers injected by meshes, secret agents and log shippers
+var knownSidecars = map[string]bool{
+	"istio-proxy":                  true,
+	"linkerd-proxy":                true,
+	"envoy":                        true,
+	"envoy-sidecar":                true,
+	"consul-dataplane":             true,
+	"consul-connect-envoy-sidecar": true,
+	"kuma-sidecar":                 true,
+	"vault-agent":                  true,
+	"cloud-sql-proxy":              true,
+	"cloudsql-proxy":               true,
+	"oauth2-proxy":                 true,
+	"kube-rbac-proxy":              true,
+	"fluent-bit":                   true,
+	"fluentd":                      true,
+	"filebeat":                     true,
+	"otel-collector":               true,
+	"datadog-agent":                true,
+	"aws-xray-daemon":              true,
+	"dapr-sidecar":                 true,
+	"daprd":                        true,
+}
+
+// ContainerSelection is the container logs and exec use when the request names none
+type ContainerSelection struct {
+	Container    string   `json:"container"`
+	Reason       string   `json:"reason"`
+	Alternatives []string `json:"alternatives,omitempty"`
+}
+
+// IsKnownSidecar reports whether a container name belongs to a commonly injected sidecar
+func IsKnownSidecar(name string) bool {
+	return knownSidecars[name]
+}
+
+// SelectDefaultContainer picks the container a user most likely means: the one named by the
+// default-container annotation, else the first container that is not a known sidecar, else
+// the first container. Alternatives lists the other containers in spec order.
+func SelectDefaultContainer(pod *v1.Pod) ContainerSelection {
+	containers := pod.Spec.Containers
+	if len(containers) == 0 {
+		return ContainerSelection{}
+	}
+
+	selection := ContainerSelection{Container: containers[0].Name, Reason: ContainerReasonFirst}
+	switch {
+	case len(containers) == 1:
+		selection.Reason = ContainerReasonOnly
+	case hasContainer(containers, pod.Annotations[DefaultContainerAnnotation]):
+		selection = ContainerSelection{Container: pod.Annotations[DefaultContainerAnnotation], Reason: ContainerReasonAnnotation}
+	default:
+		for _, c := range containers {
+			if !IsKnownSidecar(c.Name) {
+				selection = ContainerSelection{Container: c.Name, Reason: ContainerReasonFirstApp}
+				break
+			}
+		}
+	}
+
+	for _, c := range containers {
+		if c.Name != selection.Container {
+			selection.Alternatives = append(selection.Alternatives, c.Name)
+		}
+	}
+	return selection
+}
+
+// hasContainer reports whether a regular container has the given name
+func hasContainer(containers []v1.Container, name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range containers {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func podWithContainers(annotations map[string]string, names ...string) *v1.Pod {
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Annotations: annotations}}
+	for _, name := range names {
+		pod.Spec.Containers = append(pod.Spec.Containers, v1.Container{Name: name})
+	}
+	return pod
+}
+
+func TestSelectDefaultContainer(t *testing.T) {
+	tests := []struct {
+		name string
+		pod  *v1.Pod
+		want ContainerSelection
+	}{
+		{
+			name: "no containers",
+			pod:  podWithContainers(nil),
+			want: ContainerSelection{},
+		},
+		{
+			name: "single container",
+			pod:  podWithContainers(nil, "app"),
+			want: ContainerSelection{Container: "app", Reason: ContainerReasonOnly},
+		},
+		{
+			name: "annotation wins over order",
+			pod:  podWithContainers(map[string]string{DefaultContainerAnnotation: "worker"}, "app", "worker"),
+			want: ContainerSelection{Container: "worker", Reason: ContainerReasonAnnotation, Alternatives: []string{"app"}},
+		},
+		{
+			name: "annotation naming a missing container is ignored",
+			pod:  podWithContainers(map[string]string{DefaultContainerAnnotation: "gone"}, "istio-proxy", "app"),
+			want: ContainerSelection{Container: "app", Reason: ContainerReasonFirstApp, Alternatives: []string{"istio-proxy"}},
+		},
+		{
+			name: "injected sidecars are skipped",
+			pod:  podWithContainers(nil, "istio-proxy", "vault-agent", "app", "fluent-bit"),
+			want: ContainerSelection{Container: "app", Reason: ContainerReasonFirstApp, Alternatives: []string{"istio-proxy", "vault-agent", "fluent-bit"}},
+		},
+		{
+			name: "only sidecars falls back to the first",
+			pod:  podWithContainers(nil, "linkerd-proxy", "envoy"),
+			want: ContainerSelection{Container: "linkerd-proxy", Reason: ContainerReasonFirst, Alternatives: []string{"envoy"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SelectDefaultContainer(tt.pod); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SelectDefaultContainer() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
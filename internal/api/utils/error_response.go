@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Facets-cloud/kube-dash/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Error codes returned in ErrorResponse.Code
+const (
+	ErrorCodeBadRequest         = "BAD_REQUEST"
+	ErrorCodeUnauthorized       = "UNAUTHORIZED"
+	ErrorCodeForbidden          = "FORBIDDEN"
+	ErrorCodeNotFound           = "NOT_FOUND"
+	ErrorCodeConflict           = "CONFLICT"
+	ErrorCodeAlreadyExists      = "ALREADY_EXISTS"
+	ErrorCodeInvalid            = "INVALID"
+	ErrorCodeGone               = "GONE"
+	ErrorCodeTooManyRequests    = "TOO_MANY_REQUESTS"
+	ErrorCodeTimeout            = "TIMEOUT"
+	ErrorCodeServiceUnavailable = "SERVICE_UNAVAILABLE"
+	ErrorCodeInternal           = "INTERNAL"
+)
+
+// ErrorResponse is the error body API handlers return. error keeps the human-readable message
+// existing clients read; the other fields let clients handle failures without parsing it.
+type ErrorResponse struct {
+	Error         string `json:"error"`
+	Code          string `json:"code"`             // stable machine-readable code, e.g. NOT_FOUND
+	Reason        string `json:"reason,omitempty"` // Kubernetes status reason when the API server failed the request
+	Status        int    `json:"status"`
+	Retryable     bool   `json:"retryable"` // the same request may succeed later
+	CorrelationID string `json:"correlationId,omitempty"`
+}
+
+// errorCodes maps HTTP statuses to error codes
+var errorCodes = map[int]string{
+	http.StatusBadRequest:          ErrorCodeBadRequest,
+	http.StatusUnauthorized:        ErrorCodeUnauthorized,
+	http.StatusForbidden:           ErrorCodeForbidden,
+	http.StatusNotFound:            ErrorCodeNotFound,
+	http.StatusConflict:            ErrorCodeConflict,
+	http.StatusGone:                ErrorCodeGone,
+	http.StatusUnprocessableEntity: ErrorCodeInvalid,
+	http.StatusTooManyRequests:     ErrorCodeTooManyRequests,
+	http.StatusServiceUnavailable:  ErrorCodeServiceUnavailable,
+	http.StatusGatewayTimeout:      ErrorCodeTimeout,
+}
+
+// reasonCodes refines the code for Kubernetes status reasons that share an HTTP status
+var reasonCodes = map[metav1.StatusReason]string{
+	metav1.StatusReasonAlreadyExists: ErrorCodeAlreadyExists,
+	metav1.StatusReasonTimeout:       ErrorCodeTimeout,
+	metav1.StatusReasonServerTimeout: ErrorCodeTimeout,
+	metav1.StatusReasonExpired:       ErrorCodeGone,
+}
+
+// NewErrorResponse builds the error body for err. Errors from the Kubernetes API server keep their
+// status code and reason, so a forbidden list is reported as 403 whatever status the handler chose.
+func NewErrorResponse(c *gin.Context, status int, err error) ErrorResponse {
+	response := ErrorResponse{Error: err.Error(), Status: status}
+	var apiStatus apierrors.APIStatus
+	if errors.As(err, &apiStatus) {
+		if s := apiStatus.Status(); s.Code != 0 {
+			response.Status = int(s.Code)
+			response.Reason = string(s.Reason)
+		}
+	}
+	if response.Status == 0 {
+		response.Status = http.StatusInternalServerError
+	}
+
+	response.Code = errorCodes[response.Status]
+	if code, ok := reasonCodes[metav1.StatusReason(response.Reason)]; ok {
+		response.Code = code
+	}
+	if response.Code == "" {
+		response.Code = ErrorCodeInternal
+		if response.Status < http.StatusInternalServerError {
+			response.Code = ErrorCodeBadRequest
+		}
+	}
+
+	switch response.Status {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		response.Retryable = true
+	case http.StatusConflict:
+		// An update that lost a race may be retried; a create for an existing name may not
+		response.Retryable = response.Code == ErrorCodeConflict && response.Reason == string(metav1.StatusReasonConflict)
+	case http.StatusInternalServerError:
+		response.Retryable = response.Code == ErrorCodeTimeout
+	}
+	if c != nil {
+		response.CorrelationID = middleware.CorrelationIDFrom(c)
+	}
+	return response
+}
+
+// RespondError writes an ErrorResponse for err, using status unless err carries a Kubernetes status
+func RespondError(c *gin.Context, status int, err error) {
+	response := NewErrorResponse(c, status, err)
+	c.JSON(response.Status, response)
+}
+
+// RespondErrorMessage writes an ErrorResponse for a handler's own validation or lookup failure
+func RespondErrorMessage(c *gin.Context, status int, message string) {
+	RespondError(c, status, errors.New(message))
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Facets-cloud/kube-dash/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestNewErrorResponse(t *testing.T) {
+	pods := schema.GroupResource{Resource: "pods"}
+	tests := []struct {
+		name          string
+		status        int
+		err           error
+		wantStatus    int
+		wantCode      string
+		wantRetryable bool
+	}{
+		{"plain message", http.StatusBadRequest, errors.New("name is required"), http.StatusBadRequest, ErrorCodeBadRequest, false},
+		{"kubernetes status wins", http.StatusNotFound, apierrors.NewForbidden(pods, "web", errors.New("denied")), http.StatusForbidden, ErrorCodeForbidden, false},
+		{"already exists", http.StatusInternalServerError, apierrors.NewAlreadyExists(pods, "web"), http.StatusConflict, ErrorCodeAlreadyExists, false},
+		{"update conflict", http.StatusInternalServerError, apierrors.NewConflict(pods, "web", errors.New("modified")), http.StatusConflict, ErrorCodeConflict, true},
+		{"throttled", http.StatusInternalServerError, apierrors.NewTooManyRequests("slow down", 1), http.StatusTooManyRequests, ErrorCodeTooManyRequests, true},
+		{"server timeout", http.StatusInternalServerError, apierrors.NewServerTimeout(pods, "list", 1), http.StatusInternalServerError, ErrorCodeTimeout, true},
+		{"wrapped", http.StatusInternalServerError, errors.Join(errors.New("list failed"), apierrors.NewNotFound(pods, "web")), http.StatusNotFound, ErrorCodeNotFound, false},
+		{"internal", http.StatusInternalServerError, errors.New("boom"), http.StatusInternalServerError, ErrorCodeInternal, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewErrorResponse(nil, tt.status, tt.err)
+			if got.Status != tt.wantStatus || got.Code != tt.wantCode || got.Retryable != tt.wantRetryable {
+				t.Errorf("NewErrorResponse() = status %d code %s retryable %v, want %d %s %v",
+					got.Status, got.Code, got.Retryable, tt.wantStatus, tt.wantCode, tt.wantRetryable)
+			}
+			if got.Error != tt.err.Error() {
+				t.Errorf("Error = %q, want %q", got.Error, tt.err.Error())
+			}
+		})
+	}
+}
+
+func TestRespondErrorCorrelationID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.CorrelationID())
+	router.GET("/fail", func(c *gin.Context) {
+		response := NewErrorResponse(c, http.StatusBadRequest, errors.New("bad"))
+		c.JSON(response.Status, response)
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/fail", nil)
+	req.Header.Set(middleware.CorrelationIDHeader, "abc-123")
+	router.ServeHTTP(w, req)
+	if got := w.Header().Get(middleware.CorrelationIDHeader); got != "abc-123" {
+		t.Errorf("response header = %q, want abc-123", got)
+	}
+	if body := w.Body.String(); !strings.Contains(body, `"correlationId":"abc-123"`) {
+		t.Errorf("body %s does not carry the correlation ID", body)
+	}
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/fail", nil)
+	req.Header.Set(middleware.CorrelationIDHeader, "not valid\n")
+	router.ServeHTTP(w, req)
+	if got := w.Header().Get(middleware.CorrelationIDHeader); got == "" || got == "not valid\n" {
+		t.Errorf("invalid client ID was not replaced: %q", got)
+	}
+}
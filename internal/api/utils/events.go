@@ -25,7 +25,7 @@ func NewEventsHandler(log *logger.Logger) *EventsHandler {
 }
 
 // GetResourceEvents gets events for a specific resource
-func (h *EventsHandler) GetResourceEvents(c *gin.Context, client *kubernetes.Clientset, resourceKind, resourceName string, sseHandler func(*gin.Context, interface{})) {
+func (h *EventsHandler) GetResourceEvents(c *gin.Context, client kubernetes.Interface, resourceKind, resourceName string, sseHandler func(*gin.Context, interface{})) {
 	// Get events filtered by the resource name and kind
 	events, err := client.CoreV1().Events("").List(c.Request.Context(), metav1.ListOptions{
 		FieldSelector: fmt.Sprintf("involvedObject.name=%s,involvedObject.kind=%s", resourceName, resourceKind),
@@ -53,7 +53,7 @@ func (h *EventsHandler) GetResourceEvents(c *gin.Context, client *kubernetes.Cli
 }
 
 // GetResourceEventsWithNamespace gets events for a specific resource in a namespace
-func (h *EventsHandler) GetResourceEventsWithNamespace(c *gin.Context, client *kubernetes.Clientset, resourceKind, resourceName, namespace string, sseHandler func(*gin.Context, interface{})) {
+func (h *EventsHandler) GetResourceEventsWithNamespace(c *gin.Context, client kubernetes.Interface, resourceKind, resourceName, namespace string, sseHandler func(*gin.Context, interface{})) {
 	// Get events filtered by the resource name, kind, and namespace
 	events, err := client.CoreV1().Events(namespace).List(c.Request.Context(), metav1.ListOptions{
 		FieldSelector: fmt.Sprintf("involvedObject.name=%s,involvedObject.kind=%s", resourceName, resourceKind),
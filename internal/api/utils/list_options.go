@@ -0,0 +1,14 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ListOptions returns the options for the Kubernetes list calls of a list request: the label
+// selector given by the labelSelector parameter, which the API server validates
+func ListOptions(c *gin.Context) metav1.ListOptions {
+	return metav1.ListOptions{LabelSelector: strings.TrimSpace(c.Query("labelSelector"))}
+}
@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// namespaceListConcurrency bounds the per-namespace list calls a multi-namespace request issues at once
+const namespaceListConcurrency = 8
+
+// RequestedNamespaces returns the namespaces a list request targets: the comma-separated
+// namespaces parameter when set, otherwise the namespace parameter. nil means all namespaces.
+func RequestedNamespaces(c *gin.Context) []string {
+	value := c.Query("namespaces")
+	if value == "" {
+		value = c.Query("namespace")
+	}
+	seen := map[string]bool{}
+	var namespaces []string
+	for _, ns := range strings.Split(value, ",") {
+		ns = strings.TrimSpace(ns)
+		if ns != "" && !seen[ns] {
+			seen[ns] = true
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
+
+// ListInNamespaces lists once cluster-wide when namespaces is empty, otherwise once per namespace
+// concurrently, and merges the items in namespace order. The first failing namespace fails the list,
+// except in degraded-results mode (PartialResultsMiddleware), where namespaces denied by RBAC are
+// skipped and recorded as long as at least one namespace could be listed.
+func ListInNamespaces[T any](ctx context.Context, namespaces []string, list func(ctx context.Context, namespace string) ([]T, error)) ([]T, error) {
+	if len(namespaces) == 0 {
+		return list(ctx, "")
+	}
+	if len(namespaces) == 1 {
+		return list(ctx, namespaces[0])
+	}
+
+	degraded := recordDenied(ctx, nil)
+	listCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make([][]T, len(namespaces))
+	errs := make([]error, len(namespaces))
+	sem := make(chan struct{}, namespaceListConcurrency)
+	var wg sync.WaitGroup
+	for i, ns := range namespaces {
+		wg.Add(1)
+		go func(i int, ns string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i], errs[i] = list(listCtx, ns)
+			if errs[i] != nil && !(degraded && IsPermissionError(errs[i])) {
+				cancel()
+			}
+		}(i, ns)
+	}
+	wg.Wait()
+
+	// Report the error that failed the list rather than the cancellations it caused
+	var firstErr error
+	var denied []DeniedNamespace
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		if degraded && IsPermissionError(err) {
+			denied = append(denied, DeniedNamespace{Namespace: namespaces[i], Reason: err.Error()})
+		} else if firstErr == nil || errors.Is(firstErr, context.Canceled) {
+			firstErr = err
+		}
+	}
+	if firstErr == nil && len(denied) == len(namespaces) {
+		// Nothing could be listed: surface the permission error as before
+		firstErr = errs[0]
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	recordDenied(ctx, denied)
+	var merged []T
+	for i := range namespaces {
+		merged = append(merged, results[i]...)
+	}
+	return merged, nil
+}
@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestRequestedNamespaces(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", nil},
+		{"namespace=shop", []string{"shop"}},
+		{"namespaces=shop,%20payments,,shop", []string{"shop", "payments"}},
+		{"namespace=shop&namespaces=payments,billing", []string{"payments", "billing"}},
+	}
+	for _, tt := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/api/v1/pods?"+tt.query, nil)
+		if got := RequestedNamespaces(c); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("RequestedNamespaces(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestListInNamespaces(t *testing.T) {
+	list := func(ctx context.Context, namespace string) ([]string, error) {
+		switch namespace {
+		case "":
+			return []string{"all"}, nil
+		case "broken":
+			return nil, errors.New("forbidden")
+		}
+		return []string{namespace + "/a", namespace + "/b"}, nil
+	}
+
+	all, err := ListInNamespaces(context.Background(), nil, list)
+	if err != nil || !reflect.DeepEqual(all, []string{"all"}) {
+		t.Fatalf("cluster-wide list = %v, %v", all, err)
+	}
+
+	merged, err := ListInNamespaces(context.Background(), []string{"shop", "payments"}, list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"shop/a", "shop/b", "payments/a", "payments/b"}
+	if !reflect.DeepEqual(merged, want) {
+		t.Errorf("merged = %v, want %v", merged, want)
+	}
+
+	if _, err := ListInNamespaces(context.Background(), []string{"shop", "broken", "payments"}, list); err == nil || err.Error() != "forbidden" {
+		t.Errorf("expected the failing namespace's error, got %v", err)
+	}
+}
+
+func TestListInNamespacesDegraded(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", errors.New("no access"))
+	list := func(ctx context.Context, namespace string) ([]string, error) {
+		switch namespace {
+		case "restricted", "locked":
+			return nil, forbidden
+		case "broken":
+			return nil, errors.New("connection refused")
+		}
+		return []string{namespace + "/a"}, nil
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/v1/pods?namespaces=shop,restricted,payments", nil)
+	PartialResultsMiddleware()(c)
+	ctx := c.Request.Context()
+
+	items, err := ListInNamespaces(ctx, []string{"shop", "restricted", "payments"}, list)
+	if err != nil {
+		t.Fatalf("degraded list should succeed: %v", err)
+	}
+	if want := []string{"shop/a", "payments/a"}; !reflect.DeepEqual(items, want) {
+		t.Errorf("items = %v, want %v", items, want)
+	}
+	partial := GetPartialResults(c)
+	if partial == nil || len(partial.Denied) != 1 || partial.Denied[0].Namespace != "restricted" {
+		t.Fatalf("expected restricted to be reported as denied, got %+v", partial)
+	}
+	NewSSEHandler(logger.New("error")).SendJSON(c, items)
+	if !strings.Contains(w.Header().Get(PartialResultsHeader), `"namespace":"restricted"`) {
+		t.Errorf("partial header = %q", w.Header().Get(PartialResultsHeader))
+	}
+
+	// A later list that succeeds everywhere clears the partial section
+	if _, err := ListInNamespaces(ctx, []string{"shop", "payments"}, list); err != nil || GetPartialResults(c) != nil {
+		t.Errorf("expected a complete list, got %v / %+v", err, GetPartialResults(c))
+	}
+	if _, err := ListInNamespaces(ctx, []string{"restricted", "locked"}, list); !apierrors.IsForbidden(err) {
+		t.Errorf("a list denied everywhere should fail with the permission error, got %v", err)
+	}
+	if _, err := ListInNamespaces(ctx, []string{"shop", "restricted", "broken"}, list); err == nil || err.Error() != "connection refused" {
+		t.Errorf("other errors should still fail the list, got %v", err)
+	}
+	if _, err := ListInNamespaces(context.Background(), []string{"shop", "restricted"}, list); !apierrors.IsForbidden(err) {
+		t.Errorf("without the middleware a denied namespace should fail the list, got %v", err)
+	}
+}
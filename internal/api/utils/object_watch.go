@@ -0,0 +1,166 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// ObjectChange is a new state of a watched object
+type ObjectChange struct {
+	Object  runtime.Object
+	Deleted bool
+}
+
+// ObjectWatches shares one field-selector watch per object among the detail streams showing it,
+// so any number of open detail pages cost the API server a single watch and no polling GETs
+type ObjectWatches struct {
+	mu      sync.Mutex
+	watches map[string]*objectWatch
+}
+
+// objectWatch is the shared watch of one object and the streams subscribed to it
+type objectWatch struct {
+	subscribers map[chan ObjectChange]struct{}
+	cancel      context.CancelFunc
+}
+
+// NewObjectWatches creates an empty set of shared object watches
+func NewObjectWatches() *ObjectWatches {
+	return &ObjectWatches{watches: map[string]*objectWatch{}}
+}
+
+// sharedObjectWatches backs every detail stream of the server
+var sharedObjectWatches = NewObjectWatches()
+
+// ObjectWatchKey identifies a watched object for the request's kubeconfig and cluster
+func ObjectWatchKey(c *gin.Context, obj metav1.Object) string {
+	kind := fmt.Sprintf("%T", obj)
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		kind = u.GetAPIVersion() + "/" + u.GetKind()
+	}
+	return c.Query("config") + "|" + c.Query("cluster") + "|" + kind + "|" + obj.GetNamespace() + "|" + obj.GetName()
+}
+
+// Subscribe returns the changes of the named object after resourceVersion, starting a shared
+// watch when the object is not watched yet. Only the latest pending change is kept for a slow
+// subscriber. The returned function unsubscribes, stopping the watch after the last subscriber.
+func (w *ObjectWatches) Subscribe(key, name, resourceVersion string, watchObject DetailWatchFunc) (<-chan ObjectChange, func()) {
+	changes := make(chan ObjectChange, 1)
+
+	w.mu.Lock()
+	shared, ok := w.watches[key]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		shared = &objectWatch{subscribers: map[chan ObjectChange]struct{}{}, cancel: cancel}
+		w.watches[key] = shared
+		go w.run(ctx, key, name, resourceVersion, watchObject)
+	}
+	shared.subscribers[changes] = struct{}{}
+	w.mu.Unlock()
+
+	var once sync.Once
+	return changes, func() {
+		once.Do(func() {
+			w.mu.Lock()
+			defer w.mu.Unlock()
+			delete(shared.subscribers, changes)
+			if len(shared.subscribers) == 0 && w.watches[key] == shared {
+				shared.cancel()
+				delete(w.watches, key)
+			}
+		})
+	}
+}
+
+// watching reports how many objects are being watched
+func (w *ObjectWatches) watching() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.watches)
+}
+
+// publish hands a change to every subscriber of key, replacing a change it has not read yet
+func (w *ObjectWatches) publish(key string, change ObjectChange) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	shared, ok := w.watches[key]
+	if !ok {
+		return
+	}
+	for subscriber := range shared.subscribers {
+		select {
+		case subscriber <- change:
+		default:
+			select {
+			case <-subscriber:
+			default:
+			}
+			subscriber <- change
+		}
+	}
+}
+
+// run keeps a field-selector watch on one object open until ctx ends, resuming from the last
+// resourceVersion seen and publishing only events that change it
+func (w *ObjectWatches) run(ctx context.Context, key, name, resourceVersion string, watchObject DetailWatchFunc) {
+	seen := resourceVersion
+	watchFrom := resourceVersion
+	for {
+		opts := metav1.ListOptions{
+			FieldSelector:       fields.OneTermEqualSelector("metadata.name", name).String(),
+			ResourceVersion:     watchFrom,
+			AllowWatchBookmarks: true,
+		}
+		watcher, err := watchObject(ctx, opts)
+		if err == nil {
+			watchFrom, seen = w.consume(ctx, key, watcher, watchFrom, seen)
+			watcher.Stop()
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(detailWatchRetry):
+		}
+	}
+}
+
+// consume reads one watch until it ends, returning the resourceVersion to resume from and the
+// last one published
+func (w *ObjectWatches) consume(ctx context.Context, key string, watcher watch.Interface, watchFrom, seen string) (string, string) {
+	for {
+		select {
+		case <-ctx.Done():
+			return watchFrom, seen
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				// The API server ends watches periodically; resume from the last version seen
+				return watchFrom, seen
+			}
+			if event.Type == watch.Error {
+				// Usually an expired resourceVersion: rewatch from the current state, which
+				// arrives as an ADDED event and is compared with the last version seen
+				return "", seen
+			}
+			obj, err := meta.Accessor(event.Object)
+			if err != nil {
+				continue
+			}
+			watchFrom = obj.GetResourceVersion()
+			if event.Type == watch.Bookmark || obj.GetResourceVersion() == seen {
+				continue
+			}
+			seen = obj.GetResourceVersion()
+			w.publish(key, ObjectChange{Object: event.Object, Deleted: event.Type == watch.Deleted})
+		}
+	}
+}
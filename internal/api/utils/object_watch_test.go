@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func TestObjectWatchesShareOneWatch(t *testing.T) {
+	watches := NewObjectWatches()
+	fake := watch.NewFake()
+	var opened atomic.Int32
+	watchFn := func(context.Context, metav1.ListOptions) (watch.Interface, error) {
+		opened.Add(1)
+		return fake, nil
+	}
+
+	first, unsubscribeFirst := watches.Subscribe("cfg||pod|shop|api", "api", "5", watchFn)
+	second, unsubscribeSecond := watches.Subscribe("cfg||pod|shop|api", "api", "5", watchFn)
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop", ResourceVersion: "5"}}
+	fake.Modify(pod)
+	updated := pod.DeepCopy()
+	updated.ResourceVersion = "6"
+	fake.Modify(updated)
+
+	for _, changes := range []<-chan ObjectChange{first, second} {
+		select {
+		case change := <-changes:
+			if change.Object.(*corev1.Pod).ResourceVersion != "6" {
+				t.Errorf("change = %+v, want resourceVersion 6 only", change)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("subscriber did not receive the change")
+		}
+	}
+	if n := opened.Load(); n != 1 {
+		t.Errorf("opened %d watches for one object, want 1", n)
+	}
+
+	unsubscribeFirst()
+	if watches.watching() != 1 {
+		t.Error("watch stopped while a subscriber remains")
+	}
+	unsubscribeSecond()
+	if watches.watching() != 0 {
+		t.Error("watch kept after the last subscriber left")
+	}
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PartialResultsHeader carries the partial section of plain JSON list responses, which stay arrays
+const PartialResultsHeader = "X-Partial-Results"
+
+// partialResultsKey is the request context key of the partial results collector
+type partialResultsKey struct{}
+
+// DeniedNamespace is a namespace left out of a list because the caller may not list there
+type DeniedNamespace struct {
+	Namespace string `json:"namespace"`
+	Reason    string `json:"reason"`
+}
+
+// PartialResults is the partial section of a degraded list: the namespaces that were skipped
+type PartialResults struct {
+	Partial bool              `json:"partial"`
+	Denied  []DeniedNamespace `json:"denied"`
+}
+
+// partialCollector records the namespaces the latest multi-namespace list of a request skipped
+type partialCollector struct {
+	mu     sync.Mutex
+	denied []DeniedNamespace
+}
+
+// PartialResultsMiddleware lets multi-namespace lists of the request return what they could list
+// and report namespaces denied by RBAC, instead of failing on the first denied namespace
+func PartialResultsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := context.WithValue(c.Request.Context(), partialResultsKey{}, &partialCollector{})
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// recordDenied replaces the namespaces skipped by the request's latest list; it reports false
+// when the request is not in degraded-results mode
+func recordDenied(ctx context.Context, denied []DeniedNamespace) bool {
+	collector, ok := ctx.Value(partialResultsKey{}).(*partialCollector)
+	if !ok {
+		return false
+	}
+	sort.Slice(denied, func(i, j int) bool { return denied[i].Namespace < denied[j].Namespace })
+	collector.mu.Lock()
+	collector.denied = denied
+	collector.mu.Unlock()
+	return true
+}
+
+// GetPartialResults returns the partial section of the request's latest multi-namespace list,
+// or nil when every namespace was listed
+func GetPartialResults(c *gin.Context) *PartialResults {
+	collector, ok := c.Request.Context().Value(partialResultsKey{}).(*partialCollector)
+	if !ok {
+		return nil
+	}
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	if len(collector.denied) == 0 {
+		return nil
+	}
+	return &PartialResults{Partial: true, Denied: append([]DeniedNamespace(nil), collector.denied...)}
+}
+
+// setPartialHeader reports the partial section of a plain JSON list response in a header
+func setPartialHeader(c *gin.Context) {
+	if partial := GetPartialResults(c); partial != nil {
+		if raw, err := json.Marshal(partial); err == nil {
+			c.Header(PartialResultsHeader, string(raw))
+		}
+	}
+}
+
+// partialEvent renders the partial SSE event sent before a data frame. A stream that recovers gets
+// one event with no denied namespaces; reported tracks whether the previous frame was partial.
+func partialEvent(c *gin.Context, reported *bool) []byte {
+	partial := GetPartialResults(c)
+	if partial == nil {
+		if !*reported {
+			return nil
+		}
+		*reported = false
+		partial = &PartialResults{Denied: []DeniedNamespace{}}
+	} else {
+		*reported = true
+	}
+	raw, err := json.Marshal(partial)
+	if err != nil {
+		return nil
+	}
+	return []byte("event: partial\ndata: " + string(raw) + "\n\n")
+}
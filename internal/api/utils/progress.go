@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// ProgressRetention is how long the progress of a finished background operation stays readable
+const ProgressRetention = time.Hour
+
+// ProgressTracker keeps the progress of background operations keyed by target, such as a restart
+// of one workload. Starting is atomic, so at most one active operation runs per key, and every
+// update replaces the stored value with a mutated copy so readers never see partial writes.
+// Finished operations are dropped once they have not changed for the retention period.
+type ProgressTracker[T any] struct {
+	mu        sync.Mutex
+	entries   map[string]progressEntry[T]
+	retention time.Duration
+	active    func(*T) bool
+	clone     func(*T) *T
+}
+
+type progressEntry[T any] struct {
+	value   *T
+	updated time.Time
+}
+
+// NewProgressTracker creates a tracker. active reports whether an operation still blocks a new one
+// for its key; clone copies a value deeply enough for a mutation not to touch the original, and may
+// be nil for values without slices or maps.
+func NewProgressTracker[T any](retention time.Duration, active func(*T) bool, clone func(*T) *T) *ProgressTracker[T] {
+	if clone == nil {
+		clone = func(v *T) *T {
+			next := *v
+			return &next
+		}
+	}
+	return &ProgressTracker[T]{
+		entries:   make(map[string]progressEntry[T]),
+		retention: retention,
+		active:    active,
+		clone:     clone,
+	}
+}
+
+// Start records a new operation unless an active one exists for the key, which is returned instead
+func (t *ProgressTracker[T]) Start(key string, value *T) (*T, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.prune(time.Now())
+	if existing, ok := t.entries[key]; ok && t.active(existing.value) {
+		return existing.value, false
+	}
+	t.entries[key] = progressEntry[T]{value: value, updated: time.Now()}
+	return value, true
+}
+
+// Update applies a mutation to a copy of the key's value and stores the copy, returning it
+func (t *ProgressTracker[T]) Update(key string, mutate func(*T)) (*T, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	current, ok := t.entries[key]
+	if !ok {
+		return nil, false
+	}
+	next := t.clone(current.value)
+	mutate(next)
+	t.entries[key] = progressEntry[T]{value: next, updated: time.Now()}
+	return next, true
+}
+
+// Load returns the latest value of the key. Values are never mutated once stored.
+func (t *ProgressTracker[T]) Load(key string) (*T, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.prune(time.Now())
+	entry, ok := t.entries[key]
+	return entry.value, ok
+}
+
+// Delete forgets the key, releasing an operation that failed before it got going
+func (t *ProgressTracker[T]) Delete(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.entries, key)
+}
+
+// prune drops finished operations that have not changed for the retention period
+func (t *ProgressTracker[T]) prune(now time.Time) {
+	for key, entry := range t.entries {
+		if !t.active(entry.value) && now.Sub(entry.updated) > t.retention {
+			delete(t.entries, key)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type trackedOperation struct {
+	State string
+	Done  []string
+}
+
+func newTestTracker(retention time.Duration) *ProgressTracker[trackedOperation] {
+	return NewProgressTracker(retention,
+		func(op *trackedOperation) bool { return op.State == "running" },
+		func(op *trackedOperation) *trackedOperation {
+			next := *op
+			next.Done = append([]string{}, op.Done...)
+			return &next
+		})
+}
+
+func TestProgressTrackerStartsOnce(t *testing.T) {
+	tracker := newTestTracker(time.Hour)
+	var started atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, ok := tracker.Start("web", &trackedOperation{State: "running"}); ok {
+				started.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if started.Load() != 1 {
+		t.Fatalf("expected exactly one concurrent start to win, got %d", started.Load())
+	}
+
+	tracker.Update("web", func(op *trackedOperation) { op.State = "completed" })
+	if _, ok := tracker.Start("web", &trackedOperation{State: "running"}); !ok {
+		t.Error("expected a finished operation not to block a new one")
+	}
+}
+
+func TestProgressTrackerCopiesOnWrite(t *testing.T) {
+	tracker := newTestTracker(time.Hour)
+	tracker.Start("web", &trackedOperation{State: "running"})
+	before, _ := tracker.Load("web")
+	after, _ := tracker.Update("web", func(op *trackedOperation) { op.Done = append(op.Done, "web-0") })
+	if len(before.Done) != 0 || len(after.Done) != 1 {
+		t.Errorf("expected readers to keep their snapshot, got %v and %v", before.Done, after.Done)
+	}
+	if _, ok := tracker.Update("missing", func(*trackedOperation) {}); ok {
+		t.Error("expected updates of unknown keys to be ignored")
+	}
+}
+
+func TestProgressTrackerPrunesFinished(t *testing.T) {
+	tracker := newTestTracker(0)
+	tracker.Start("running", &trackedOperation{State: "running"})
+	tracker.Start("done", &trackedOperation{State: "completed"})
+	time.Sleep(time.Millisecond)
+	if _, ok := tracker.Load("done"); ok {
+		t.Error("expected a finished operation past retention to be dropped")
+	}
+	if _, ok := tracker.Load("running"); !ok {
+		t.Error("expected a running operation to be kept")
+	}
+}
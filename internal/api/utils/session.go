@@ -0,0 +1,21 @@
+package utils
+
+import "github.com/gin-gonic/gin"
+
+const (
+	// SessionCookie identifies the browser session that owns session-only kubeconfigs
+	SessionCookie = "kube_dash_session"
+	// SessionHeader lets non-browser clients supply the session explicitly
+	SessionHeader = "X-Kube-Dash-Session"
+)
+
+// SessionID returns the caller's session, or an empty string if it has none
+func SessionID(c *gin.Context) string {
+	if id := c.GetHeader(SessionHeader); id != "" {
+		return id
+	}
+	if id, err := c.Cookie(SessionCookie); err == nil {
+		return id
+	}
+	return ""
+}
@@ -2,61 +2,60 @@ package utils
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strings"
-	"sync"
 	"time"
 
+	"github.com/Facets-cloud/kube-dash/internal/api/transformers"
+	"github.com/Facets-cloud/kube-dash/internal/sourcelinks"
 	"github.com/Facets-cloud/kube-dash/pkg/logger"
 
 	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// SSEHandler provides utility functions for Server-Sent Events operations
+// SSEHandler provides utility functions for Server-Sent Events operations. Open streams are
+// tracked, capped and closed on shutdown by the streams registry middleware.
 type SSEHandler struct {
 	logger *logger.Logger
-	// Connection pool for managing active SSE connections
-	connections sync.Map
-}
-
-// SSEConnection represents an active SSE connection
-type SSEConnection struct {
-	ID        string
-	CreatedAt time.Time
-	LastPing  time.Time
-	Context   *gin.Context
 }
 
 // NewSSEHandler creates a new SSE handler
 func NewSSEHandler(log *logger.Logger) *SSEHandler {
-	handler := &SSEHandler{
+	return &SSEHandler{
 		logger: log,
 	}
-
-	// Start connection cleanup goroutine
-	go handler.cleanupConnections()
-
-	return handler
 }
 
-// cleanupConnections periodically removes stale connections
-func (h *SSEHandler) cleanupConnections() {
-	ticker := time.NewTicker(5 * time.Minute)
-	defer ticker.Stop()
+// marshalResponse marshals list data, applying the sort and column projection requested by the
+// sort and fields parameters. Single objects get the Git source they were applied from, if known.
+func marshalResponse(c *gin.Context, data interface{}) ([]byte, error) {
+	raw, err := transformers.MarshalList(data, transformers.ParseSort(c.Query("sort")), transformers.ParseFields(c.Query("fields")))
+	if err != nil {
+		return nil, err
+	}
+	if obj, ok := data.(metav1.Object); ok {
+		if resolver, ok := sourcelinks.FromContext(c); ok {
+			return sourcelinks.WithSource(raw, resolver.Resolve(c, obj))
+		}
+	}
+	return raw, nil
+}
 
-	for range ticker.C {
-		now := time.Now()
-		h.connections.Range(func(key, value interface{}) bool {
-			if conn, ok := value.(*SSEConnection); ok {
-				// Remove connections older than 10 minutes or with no ping in 5 minutes
-				if now.Sub(conn.CreatedAt) > 10*time.Minute || now.Sub(conn.LastPing) > 5*time.Minute {
-					h.connections.Delete(key)
-					h.logger.Info("Cleaned up stale SSE connection", "id", conn.ID)
-				}
-			}
-			return true
-		})
+// SendJSON sends list data as a plain JSON response, applying the fields projection like the SSE responses
+func (h *SSEHandler) SendJSON(c *gin.Context, data interface{}) {
+	if data == nil {
+		data = []interface{}{}
+	}
+	jsonData, err := marshalResponse(c, data)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to marshal response data")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
+	setPartialHeader(c)
+	c.Data(http.StatusOK, "application/json; charset=utf-8", jsonData)
 }
 
 // SendSSEResponse sends a Server-Sent Events response with real-time updates
@@ -76,13 +75,17 @@ func (h *SSEHandler) SendSSEResponse(c *gin.Context, data interface{}) {
 	}
 
 	// Send data directly without event wrapper
-	jsonData, err := json.Marshal(data)
+	jsonData, err := marshalResponse(c, data)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to marshal SSE data")
 		return
 	}
 
 	// Send data and close connection immediately for non-updating endpoints
+	var partialReported bool
+	if event := partialEvent(c, &partialReported); event != nil {
+		c.Data(http.StatusOK, "text/event-stream", event)
+	}
 	c.Data(http.StatusOK, "text/event-stream", []byte("data: "+string(jsonData)+"\n\n"))
 	c.Writer.Flush()
 
@@ -115,30 +118,23 @@ func (h *SSEHandler) SendSSEResponseWithUpdates(c *gin.Context, data interface{}
 	c.Header("X-Accel-Buffering", "no")   // Disable nginx buffering if present
 	c.Header("Keep-Alive", "timeout=300") // 5 minute keep-alive
 
-	// Create connection ID for tracking
-	connID := c.ClientIP() + ":" + c.Request.URL.Path
-	conn := &SSEConnection{
-		ID:        connID,
-		CreatedAt: time.Now(),
-		LastPing:  time.Now(),
-		Context:   c,
-	}
-	h.connections.Store(connID, conn)
-
 	// Ensure we always send a valid array, never null
 	if data == nil {
 		data = []interface{}{}
 	}
 
 	// Send initial data
-	jsonData, err := json.Marshal(data)
+	jsonData, err := marshalResponse(c, data)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to marshal SSE data")
-		h.connections.Delete(connID)
 		return
 	}
 
-	// Send data directly without event wrapper
+	// Send data directly without event wrapper, preceded by the denied namespaces of a partial list
+	var partialReported bool
+	if event := partialEvent(c, &partialReported); event != nil {
+		c.Data(http.StatusOK, "text/event-stream", event)
+	}
 	c.Data(http.StatusOK, "text/event-stream", []byte("data: "+string(jsonData)+"\n\n"))
 	c.Writer.Flush()
 
@@ -168,16 +164,8 @@ func (h *SSEHandler) SendSSEResponseWithUpdates(c *gin.Context, data interface{}
 		select {
 		case <-c.Request.Context().Done():
 			h.logger.Info("SSE connection closed by client")
-			h.connections.Delete(connID)
 			return
 		case <-ticker.C:
-			// Update last ping time
-			if connValue, exists := h.connections.Load(connID); exists {
-				if conn, ok := connValue.(*SSEConnection); ok {
-					conn.LastPing = time.Now()
-				}
-			}
-
 			// Fetch fresh data and send update with optimized timeout
 			if updateFunc != nil {
 				// Create a channel for the update result
@@ -234,7 +222,7 @@ func (h *SSEHandler) SendSSEResponseWithUpdates(c *gin.Context, data interface{}
 						result.data = []interface{}{}
 					}
 
-					jsonData, err := json.Marshal(result.data)
+					jsonData, err := marshalResponse(c, result.data)
 					if err != nil {
 						h.logger.WithError(err).Error("Failed to marshal fresh SSE data")
 						// Send keep-alive instead of failing
@@ -244,6 +232,9 @@ func (h *SSEHandler) SendSSEResponseWithUpdates(c *gin.Context, data interface{}
 					}
 
 					// Send data directly without event wrapper
+					if event := partialEvent(c, &partialReported); event != nil {
+						c.Data(http.StatusOK, "text/event-stream", event)
+					}
 					c.Data(http.StatusOK, "text/event-stream", []byte("data: "+string(jsonData)+"\n\n"))
 					c.Writer.Flush()
 
@@ -270,7 +261,7 @@ func (h *SSEHandler) SendSSEError(c *gin.Context, statusCode int, message string
 	c.Header("Access-Control-Allow-Origin", "*")
 	c.Header("Access-Control-Allow-Headers", "Cache-Control")
 
-	errorData := gin.H{"error": message}
+	errorData := NewErrorResponse(c, statusCode, errors.New(message))
 	jsonData, err := json.Marshal(errorData)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to marshal SSE error data")
@@ -0,0 +1,181 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// ChangedExternallyEvent is the SSE event type sent when the object shown by a detail stream changes
+const ChangedExternallyEvent = "changed-externally"
+
+// ObjectEvent is the SSE event type carrying the new state of the object shown by a detail stream
+const ObjectEvent = "object"
+
+// detailWatchRetry is how long a detail stream waits before reopening a closed or failed watch
+const detailWatchRetry = 5 * time.Second
+
+// DetailWatchFunc opens a watch; typed and dynamic clients' Watch methods satisfy it
+type DetailWatchFunc func(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+
+// ExternalChange is the payload of a changed-externally event. It lets the UI warn about a
+// concurrent edit before saving the object it shows fails with a 409 conflict.
+type ExternalChange struct {
+	Name                    string     `json:"name"`
+	Namespace               string     `json:"namespace,omitempty"`
+	PreviousResourceVersion string     `json:"previousResourceVersion"`
+	ResourceVersion         string     `json:"resourceVersion"`
+	Generation              int64      `json:"generation,omitempty"` // unchanged for status and metadata-only updates
+	Deleted                 bool       `json:"deleted,omitempty"`
+	Actor                   string     `json:"actor,omitempty"`       // field manager of the most recent managedFields entry
+	Operation               string     `json:"operation,omitempty"`   // Apply or Update
+	Subresource             string     `json:"subresource,omitempty"` // e.g. status or scale
+	ChangedAt               *time.Time `json:"changedAt,omitempty"`
+}
+
+// newExternalChange describes a change to obj since previousResourceVersion, attributing it to the
+// manager whose managedFields entry was updated last
+func newExternalChange(obj metav1.Object, previousResourceVersion string, deleted bool) ExternalChange {
+	change := ExternalChange{
+		Name:                    obj.GetName(),
+		Namespace:               obj.GetNamespace(),
+		PreviousResourceVersion: previousResourceVersion,
+		ResourceVersion:         obj.GetResourceVersion(),
+		Generation:              obj.GetGeneration(),
+		Deleted:                 deleted,
+	}
+	var latest *metav1.ManagedFieldsEntry
+	for i, entry := range obj.GetManagedFields() {
+		if entry.Time == nil {
+			continue
+		}
+		if latest == nil || !entry.Time.Before(latest.Time) {
+			latest = &obj.GetManagedFields()[i]
+		}
+	}
+	if latest != nil {
+		changedAt := latest.Time.Time
+		change.Actor = latest.Manager
+		change.Operation = string(latest.Operation)
+		change.Subresource = latest.Subresource
+		change.ChangedAt = &changedAt
+	}
+	return change
+}
+
+// editableContent returns the object without the fields that change without anyone editing it:
+// status, resourceVersion and managedFields. It returns nil for objects it cannot convert.
+func editableContent(obj interface{}) map[string]interface{} {
+	runtimeObj, ok := obj.(runtime.Object)
+	if !ok {
+		return nil
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(runtimeObj.DeepCopyObject())
+	if err != nil {
+		return nil
+	}
+	// Typed clients leave apiVersion and kind unset on some objects and set them on others
+	delete(content, "apiVersion")
+	delete(content, "kind")
+	delete(content, "status")
+	if metadata, ok := content["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "resourceVersion")
+		delete(metadata, "managedFields")
+	}
+	return content
+}
+
+// SendSSEDetailResponse sends a detail object like SendSSEResponse and then follows the object
+// it was read from through a watch shared with every other stream showing it. Every new
+// resourceVersion is sent as an object event carrying the new state, so the page needs no new
+// GET. Changes outside status, such as spec, data, labels and annotations, and deletions are
+// first announced by a changed-externally event naming the actor from managedFields; the status
+// updates controllers make all the time are not.
+func (h *SSEHandler) SendSSEDetailResponse(c *gin.Context, data interface{}, current metav1.Object, watchObject DetailWatchFunc) {
+	if current == nil || watchObject == nil {
+		h.SendSSEResponse(c, data)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache, no-store, must-revalidate")
+	c.Header("Connection", "keep-alive")
+	c.Header("Access-Control-Allow-Origin", "*")
+	c.Header("Access-Control-Allow-Headers", "Cache-Control")
+	c.Header("X-Accel-Buffering", "no")
+	c.Header("Keep-Alive", "timeout=300")
+
+	jsonData, err := marshalResponse(c, data)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to marshal SSE data")
+		return
+	}
+	c.Data(http.StatusOK, "text/event-stream", []byte("data: "+string(jsonData)+"\n\n"))
+	c.Writer.Flush()
+
+	seen := current.GetResourceVersion()
+	content := editableContent(current)
+	changes, unsubscribe := sharedObjectWatches.Subscribe(ObjectWatchKey(c, current), current.GetName(), seen, watchObject)
+	defer unsubscribe()
+
+	// send reports a change to the client, returning false once the object is gone
+	send := func(change ObjectChange) bool {
+		obj, err := meta.Accessor(change.Object)
+		if err != nil || obj.GetResourceVersion() == seen {
+			return true
+		}
+		external := newExternalChange(obj, seen, change.Deleted)
+		seen = obj.GetResourceVersion()
+		next := editableContent(change.Object)
+		if change.Deleted || next == nil || content == nil || !reflect.DeepEqual(next, content) {
+			payload, err := json.Marshal(external)
+			if err != nil {
+				h.logger.WithError(err).Error("Failed to marshal changed-externally event")
+				return true
+			}
+			c.SSEvent(ChangedExternallyEvent, string(payload))
+		}
+		content = next
+		if !change.Deleted {
+			if state, err := marshalResponse(c, change.Object); err == nil {
+				c.SSEvent(ObjectEvent, string(state))
+			}
+		}
+		c.Writer.Flush()
+		return !change.Deleted
+	}
+
+	ticker := time.NewTicker(60 * time.Second)
+	defer ticker.Stop()
+
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			// Deliver a change that arrived together with the disconnect
+			select {
+			case change := <-changes:
+				send(change)
+			default:
+			}
+			return
+		case <-ticker.C:
+			c.Data(http.StatusOK, "text/event-stream", []byte(": keep-alive\n\n"))
+			c.Writer.Flush()
+		case change := <-changes:
+			if !send(change) {
+				// Keep the stream open but stop following a deleted object
+				unsubscribe()
+				changes = nil
+			}
+		}
+	}
+}
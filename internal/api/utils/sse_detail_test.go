@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func TestNewExternalChange(t *testing.T) {
+	earlier := metav1.NewTime(time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC))
+	later := metav1.NewTime(earlier.Add(time.Minute))
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Name:            "settings",
+		Namespace:       "shop",
+		ResourceVersion: "12",
+		ManagedFields: []metav1.ManagedFieldsEntry{
+			{Manager: "kube-dash", Operation: metav1.ManagedFieldsOperationApply, Time: &earlier},
+			{Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate, Time: &later},
+			{Manager: "no-time", Operation: metav1.ManagedFieldsOperationUpdate},
+		},
+	}}
+
+	change := newExternalChange(cm, "10", false)
+	if change.Actor != "kubectl-edit" || change.Operation != "Update" || change.PreviousResourceVersion != "10" || change.ResourceVersion != "12" {
+		t.Errorf("newExternalChange() = %+v", change)
+	}
+	if change.ChangedAt == nil || !change.ChangedAt.Equal(later.Time) {
+		t.Errorf("ChangedAt = %v, want %v", change.ChangedAt, later.Time)
+	}
+}
+
+func TestSendSSEDetailResponseChangedExternally(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	ctx, cancel := context.WithCancel(context.Background())
+	c.Request = httptest.NewRequest("GET", "/api/v1/configmaps/shop/settings", nil).WithContext(ctx)
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "shop", ResourceVersion: "10"}}
+	fake := watch.NewFake()
+	var opened metav1.ListOptions
+	watchFn := func(_ context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+		opened = opts
+		return fake, nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		NewSSEHandler(logger.New("error")).SendSSEDetailResponse(c, cm, cm, watchFn)
+		close(done)
+	}()
+
+	unchanged := cm.DeepCopy()
+	fake.Modify(unchanged)
+	changed := cm.DeepCopy()
+	changed.ResourceVersion = "11"
+	changed.Data = map[string]string{"level": "debug"}
+	fake.Modify(changed)
+	// The shared watch has published the change once it reads the next event
+	fake.Modify(changed.DeepCopy())
+	cancel()
+	<-done
+
+	if opened.FieldSelector != "metadata.name=settings" || opened.ResourceVersion != "10" {
+		t.Errorf("watch options = %+v", opened)
+	}
+	body := recorder.Body.String()
+	if strings.Count(body, "event:"+ChangedExternallyEvent) != 1 {
+		t.Fatalf("want one changed-externally event, got body:\n%s", body)
+	}
+	if !strings.Contains(body, `"previousResourceVersion":"10"`) || !strings.Contains(body, `"resourceVersion":"11"`) {
+		t.Errorf("changed-externally payload missing versions:\n%s", body)
+	}
+	if strings.Count(body, "event:"+ObjectEvent) != 1 || !strings.Contains(body, `"level":"debug"`) {
+		t.Errorf("want one object event with the new state, got body:\n%s", body)
+	}
+}
+
+func TestSendSSEDetailResponseIgnoresStatusUpdates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	ctx, cancel := context.WithCancel(context.Background())
+	c.Request = httptest.NewRequest("GET", "/api/v1/pods/shop/web", nil).WithContext(ctx)
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", ResourceVersion: "10", Labels: map[string]string{"app": "web"}},
+		Status:     corev1.PodStatus{Phase: corev1.PodPending},
+	}
+	fake := watch.NewFake()
+	watchFn := func(context.Context, metav1.ListOptions) (watch.Interface, error) { return fake, nil }
+
+	done := make(chan struct{})
+	go func() {
+		NewSSEHandler(logger.New("error")).SendSSEDetailResponse(c, pod, pod, watchFn)
+		close(done)
+	}()
+
+	running := pod.DeepCopy()
+	running.ResourceVersion = "11"
+	running.Status.Phase = corev1.PodRunning
+	running.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: "kubelet", Operation: metav1.ManagedFieldsOperationUpdate, Subresource: "status"}}
+	fake.Modify(running)
+	relabeled := running.DeepCopy()
+	relabeled.ResourceVersion = "12"
+	relabeled.Labels["tier"] = "frontend"
+	fake.Modify(relabeled)
+	// The shared watch has published the changes once it reads the next event
+	fake.Modify(relabeled.DeepCopy())
+	cancel()
+	<-done
+
+	body := recorder.Body.String()
+	if strings.Count(body, "event:"+ChangedExternallyEvent) != 1 || !strings.Contains(body, `"previousResourceVersion":"11"`) {
+		t.Fatalf("want a changed-externally event for the label change only, got body:\n%s", body)
+	}
+	if strings.Count(body, "event:"+ObjectEvent) != 2 {
+		t.Errorf("want an object event for both updates, got body:\n%s", body)
+	}
+}
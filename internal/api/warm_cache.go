@@ -0,0 +1,46 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetWarmSummary returns the prefetched summary of a cluster
+// @Summary Get a prefetched cluster summary
+// @Description In warm-cache mode (WARM_CACHE_ENABLED), namespaces, nodes and workload counts of every cluster are prefetched when its kubeconfig is registered, so a page can render them instantly after switching clusters while its own data loads. The summary is returned as cached with its age; stale or missing summaries are refetched in the background, and refreshing tells the client to poll again.
+// @Tags Configuration
+// @Produce json
+// @Param id path string true "Kubeconfig ID"
+// @Param cluster query string true "Cluster name"
+// @Success 200 {object} warmcache.Entry "Cached summary with staleness"
+// @Failure 400 {object} map[string]interface{} "Missing cluster"
+// @Failure 404 {object} map[string]interface{} "Kubeconfig not found or warm cache disabled"
+// @Router /api/v1/app/config/kubeconfigs/{id}/summary [get]
+func (h *KubeConfigHandler) GetWarmSummary(c *gin.Context) {
+	if !h.warmCache.Enabled() {
+		c.JSON(http.StatusNotFound, gin.H{"error": "warm cache is disabled; set WARM_CACHE_ENABLED to prefetch cluster summaries"})
+		return
+	}
+	configID := c.Param("id")
+	if _, ok := h.visibleConfigs(c)[configID]; !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "kubeconfig not found"})
+		return
+	}
+	config, err := h.store.GetKubeConfig(configID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	cluster := c.Query("cluster")
+	if cluster == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cluster parameter is required"})
+		return
+	}
+	if _, ok := config.Contexts[cluster]; !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "cluster not found in kubeconfig"})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.warmCache.Get(configID, cluster))
+}
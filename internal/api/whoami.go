@@ -0,0 +1,289 @@
+package api
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authenticationv1beta1 "k8s.io/api/authentication/v1beta1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Sources of a reported identity
+const (
+	IdentitySourceSelfSubjectReview = "SelfSubjectReview"
+	IdentitySourceCertificate       = "client-certificate"
+	IdentitySourceUnknown           = "unknown"
+)
+
+// PermissionProbe is one access check run with the cluster's credentials
+type PermissionProbe struct {
+	Description string `json:"description"`
+	Verb        string `json:"verb"`
+	Group       string `json:"group,omitempty"`
+	Resource    string `json:"resource"`
+	Namespace   string `json:"namespace,omitempty"`
+	Allowed     bool   `json:"allowed"`
+}
+
+// ClusterIdentity is who the dashboard acts as on one cluster
+type ClusterIdentity struct {
+	ConfigID     string              `json:"configId"`
+	ConfigName   string              `json:"configName"`
+	Cluster      string              `json:"cluster"`
+	Contexts     []string            `json:"contexts"`
+	AuthMethod   string              `json:"authMethod"`
+	Source       string              `json:"source"` // how the identity was determined
+	Username     string              `json:"username,omitempty"`
+	UID          string              `json:"uid,omitempty"`
+	Groups       []string            `json:"groups,omitempty"`
+	Extra        map[string][]string `json:"extra,omitempty"`
+	ClusterAdmin bool                `json:"clusterAdmin"`
+	Permissions  []PermissionProbe   `json:"permissions,omitempty"`
+	Error        string              `json:"error,omitempty"`
+}
+
+// identityProbes are the access checks that sketch what the credentials can do
+var identityProbes = []PermissionProbe{
+	{Description: "Everything (cluster-admin)", Verb: "*", Group: "*", Resource: "*"},
+	{Description: "List pods in all namespaces", Verb: "list", Resource: "pods"},
+	{Description: "Read secrets in all namespaces", Verb: "get", Resource: "secrets"},
+	{Description: "Exec into pods", Verb: "create", Resource: "pods/exec"},
+	{Description: "Update deployments", Verb: "update", Group: "apps", Resource: "deployments"},
+	{Description: "Delete namespaces", Verb: "delete", Resource: "namespaces"},
+	{Description: "Update nodes", Verb: "update", Resource: "nodes"},
+	{Description: "Manage RBAC roles", Verb: "escalate", Group: "rbac.authorization.k8s.io", Resource: "clusterroles"},
+	{Description: "Impersonate users", Verb: "impersonate", Resource: "users"},
+}
+
+// authMethod describes how a kubeconfig user authenticates
+func authMethod(authInfo *api.AuthInfo) string {
+	switch {
+	case authInfo == nil:
+		return "none"
+	case authInfo.Exec != nil:
+		return "exec: " + authInfo.Exec.Command
+	case authInfo.AuthProvider != nil:
+		return "auth-provider: " + authInfo.AuthProvider.Name
+	case authInfo.Token != "" || authInfo.TokenFile != "":
+		return "token"
+	case len(authInfo.ClientCertificateData) > 0 || authInfo.ClientCertificate != "":
+		return "client-certificate"
+	case authInfo.Username != "":
+		return "basic"
+	}
+	return "none"
+}
+
+// certificateIdentity reads the user and groups from a client certificate: the API server takes
+// the username from the common name and groups from the organizations
+func certificateIdentity(authInfo *api.AuthInfo) (string, []string, bool) {
+	if authInfo == nil {
+		return "", nil, false
+	}
+	data := authInfo.ClientCertificateData
+	if len(data) == 0 && authInfo.ClientCertificate != "" {
+		var err error
+		if data, err = os.ReadFile(authInfo.ClientCertificate); err != nil {
+			return "", nil, false
+		}
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return "", nil, false
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return "", nil, false
+	}
+	return cert.Subject.CommonName, cert.Subject.Organization, true
+}
+
+// reviewSelf asks the API server who the credentials are, using the newest SelfSubjectReview
+// version it serves. ok is false when no version is served.
+func reviewSelf(ctx context.Context, client kubernetes.Interface, identity *ClusterIdentity) (bool, error) {
+	review, err := client.AuthenticationV1().SelfSubjectReviews().Create(ctx, &authenticationv1.SelfSubjectReview{}, metav1.CreateOptions{})
+	if err == nil {
+		user := review.Status.UserInfo
+		identity.Username, identity.UID, identity.Groups = user.Username, user.UID, user.Groups
+		identity.Extra = extraValues(user.Extra)
+		return true, nil
+	}
+	if !reviewUnavailable(err) {
+		return false, err
+	}
+
+	// SelfSubjectReview went GA in 1.28; 1.27 serves it in beta
+	betaReview, err := client.AuthenticationV1beta1().SelfSubjectReviews().Create(ctx, &authenticationv1beta1.SelfSubjectReview{}, metav1.CreateOptions{})
+	if err == nil {
+		user := betaReview.Status.UserInfo
+		identity.Username, identity.UID, identity.Groups = user.Username, user.UID, user.Groups
+		identity.Extra = extraValues(user.Extra)
+		return true, nil
+	}
+	if !reviewUnavailable(err) {
+		return false, err
+	}
+	return false, nil
+}
+
+// reviewUnavailable reports whether a SelfSubjectReview failed because the API server does not
+// serve or allow it
+func reviewUnavailable(err error) bool {
+	return apierrors.IsNotFound(err) || apierrors.IsMethodNotSupported(err) || apierrors.IsForbidden(err)
+}
+
+func extraValues(extra map[string]authenticationv1.ExtraValue) map[string][]string {
+	if len(extra) == 0 {
+		return nil
+	}
+	values := make(map[string][]string, len(extra))
+	for key, value := range extra {
+		values[key] = value
+	}
+	return values
+}
+
+// probePermissions runs the identity probes with SelfSubjectAccessReviews
+func probePermissions(ctx context.Context, client kubernetes.Interface) ([]PermissionProbe, error) {
+	probes := make([]PermissionProbe, 0, len(identityProbes))
+	for _, probe := range identityProbes {
+		resource, subresource, _ := strings.Cut(probe.Resource, "/")
+		review := &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Verb:        probe.Verb,
+					Group:       probe.Group,
+					Resource:    resource,
+					Subresource: subresource,
+					Namespace:   probe.Namespace,
+				},
+			},
+		}
+		result, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+		if err != nil {
+			return probes, err
+		}
+		probe.Allowed = result.Status.Allowed
+		probes = append(probes, probe)
+	}
+	return probes, nil
+}
+
+// resolveIdentity fills in who the credentials are and what they may do on a cluster. The
+// certificate subject stands in for the identity when the API server serves no SelfSubjectReview.
+func resolveIdentity(ctx context.Context, client kubernetes.Interface, authInfo *api.AuthInfo, identity *ClusterIdentity) {
+	identity.AuthMethod = authMethod(authInfo)
+	identity.Source = IdentitySourceUnknown
+
+	reviewed, err := reviewSelf(ctx, client, identity)
+	if err != nil {
+		identity.Error = "Failed to review identity: " + err.Error()
+		return
+	}
+	if reviewed {
+		identity.Source = IdentitySourceSelfSubjectReview
+	} else if username, groups, ok := certificateIdentity(authInfo); ok {
+		identity.Source = IdentitySourceCertificate
+		identity.Username, identity.Groups = username, groups
+	}
+
+	probes, err := probePermissions(ctx, client)
+	identity.Permissions = probes
+	if err != nil {
+		identity.Error = "Failed to check permissions: " + err.Error()
+		return
+	}
+	identity.ClusterAdmin = len(probes) > 0 && probes[0].Allowed
+}
+
+// GetIdentities reports the identity the dashboard uses on each cluster
+// @Summary Get identity per cluster
+// @Description Reports who the stored credentials authenticate as on each cluster: username, UID, groups and extra attributes from a SelfSubjectReview, or the client certificate subject on API servers older than 1.27. Each cluster also gets a set of SelfSubjectAccessReview probes (cluster-admin, reading secrets, exec, updating deployments, ...) that show what the dashboard can do with those credentials.
+// @Tags Configuration
+// @Produce json
+// @Param config query string false "Only report clusters of this kubeconfig"
+// @Success 200 {array} ClusterIdentity "Identity per cluster"
+// @Failure 404 {object} map[string]interface{} "Kubeconfig not found"
+// @Router /api/v1/app/config/whoami [get]
+func (h *KubeConfigHandler) GetIdentities(c *gin.Context) {
+	configs := h.visibleConfigs(c)
+	if configID := c.Query("config"); configID != "" {
+		metadata, ok := configs[configID]
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "kubeconfig not found"})
+			return
+		}
+		configs = map[string]*storage.KubeConfig{configID: metadata}
+	}
+
+	identities := []*ClusterIdentity{}
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, 5)
+	for configID, metadata := range configs {
+		config, err := h.store.GetKubeConfig(configID)
+		if err != nil {
+			h.logger.WithError(err).WithField("config_id", configID).Error("Failed to get kubeconfig for identity")
+			continue
+		}
+
+		// The client factory talks to a cluster through the first context that names it, so one
+		// identity is reported per cluster
+		byCluster := map[string]*ClusterIdentity{}
+		contextNames := make([]string, 0, len(config.Contexts))
+		for name := range config.Contexts {
+			contextNames = append(contextNames, name)
+		}
+		sort.Strings(contextNames)
+		for _, name := range contextNames {
+			cluster := config.Contexts[name].Cluster
+			if identity, ok := byCluster[cluster]; ok {
+				identity.Contexts = append(identity.Contexts, name)
+				continue
+			}
+			identity := &ClusterIdentity{ConfigID: configID, ConfigName: metadata.Name, Cluster: cluster, Contexts: []string{name}}
+			byCluster[cluster] = identity
+			identities = append(identities, identity)
+
+			wg.Add(1)
+			go func(config *api.Config, identity *ClusterIdentity, authInfo *api.AuthInfo) {
+				defer wg.Done()
+				semaphore <- struct{}{}
+				defer func() { <-semaphore }()
+
+				client, err := h.clientFactory.GetClientForConfig(config, identity.Cluster)
+				if err != nil {
+					identity.AuthMethod = authMethod(authInfo)
+					identity.Source = IdentitySourceUnknown
+					identity.Error = "Failed to create Kubernetes client: " + err.Error()
+					return
+				}
+				ctx, cancel := context.WithTimeout(c.Request.Context(), clusterProbeTimeout)
+				defer cancel()
+				resolveIdentity(ctx, client, authInfo, identity)
+			}(config, identity, config.AuthInfos[config.Contexts[name].AuthInfo])
+		}
+	}
+	wg.Wait()
+
+	sort.Slice(identities, func(i, j int) bool {
+		if identities[i].ConfigName != identities[j].ConfigName {
+			return identities[i].ConfigName < identities[j].ConfigName
+		}
+		return identities[i].Cluster < identities[j].Cluster
+	})
+	c.JSON(http.StatusOK, identities)
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func testCertificate(t *testing.T, commonName string, organizations ...string) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName, Organization: organizations},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+// allowPods answers access reviews, allowing only listing pods
+func allowPods(action k8stesting.Action) (bool, runtime.Object, error) {
+	review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+	attrs := review.Spec.ResourceAttributes
+	review.Status.Allowed = attrs.Verb == "list" && attrs.Resource == "pods"
+	return true, review, nil
+}
+
+func TestResolveIdentityFromSelfSubjectReview(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "selfsubjectreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &authenticationv1.SelfSubjectReview{Status: authenticationv1.SelfSubjectReviewStatus{
+			UserInfo: authenticationv1.UserInfo{
+				Username: "alice@example.com",
+				Groups:   []string{"developers", "system:authenticated"},
+				Extra:    map[string]authenticationv1.ExtraValue{"scopes": {"openid"}},
+			},
+		}}, nil
+	})
+	client.PrependReactor("create", "selfsubjectaccessreviews", allowPods)
+
+	identity := &ClusterIdentity{}
+	resolveIdentity(context.Background(), client, &api.AuthInfo{Exec: &api.ExecConfig{Command: "aws"}}, identity)
+	if identity.Error != "" {
+		t.Fatalf("resolveIdentity() error = %s", identity.Error)
+	}
+	if identity.Source != IdentitySourceSelfSubjectReview || identity.Username != "alice@example.com" || len(identity.Groups) != 2 || identity.Extra["scopes"][0] != "openid" {
+		t.Errorf("identity = %+v", identity)
+	}
+	if identity.AuthMethod != "exec: aws" || identity.ClusterAdmin {
+		t.Errorf("auth method = %q, cluster admin = %v", identity.AuthMethod, identity.ClusterAdmin)
+	}
+	allowed := 0
+	for _, probe := range identity.Permissions {
+		if probe.Allowed {
+			allowed++
+		}
+	}
+	if len(identity.Permissions) != len(identityProbes) || allowed != 1 {
+		t.Errorf("permissions = %+v, want only listing pods allowed", identity.Permissions)
+	}
+}
+
+func TestResolveIdentityFallsBackToCertificate(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "selfsubjectreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewNotFound(schema.GroupResource{Group: "authentication.k8s.io", Resource: "selfsubjectreviews"}, "")
+	})
+	client.PrependReactor("create", "selfsubjectaccessreviews", allowPods)
+
+	authInfo := &api.AuthInfo{ClientCertificateData: testCertificate(t, "admin", "system:masters")}
+	identity := &ClusterIdentity{}
+	resolveIdentity(context.Background(), client, authInfo, identity)
+	if identity.Source != IdentitySourceCertificate || identity.Username != "admin" || len(identity.Groups) != 1 || identity.Groups[0] != "system:masters" {
+		t.Errorf("identity = %+v, want the certificate subject", identity)
+	}
+	if identity.AuthMethod != "client-certificate" || len(identity.Permissions) != len(identityProbes) {
+		t.Errorf("auth method = %q, permissions = %d", identity.AuthMethod, len(identity.Permissions))
+	}
+}
@@ -0,0 +1,129 @@
+package apitokens
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Facets-cloud/kube-dash/internal/audit"
+
+	"github.com/gin-gonic/gin"
+)
+
+const contextKey = "apiToken"
+
+// managementPath is the route prefix for token management, which tokens themselves cannot use
+const managementPath = "/api/v1/auth/tokens"
+
+// clusterParams are the query parameter pairs that name a kubeconfig and cluster
+var clusterParams = [][2]string{
+	{"config", "cluster"},
+	{"leftConfig", "leftCluster"},
+	{"rightConfig", "rightCluster"},
+}
+
+// FromContext returns the API token that authenticated the request, if any
+func FromContext(c *gin.Context) (*Token, bool) {
+	value, ok := c.Get(contextKey)
+	if !ok {
+		return nil, false
+	}
+	token, ok := value.(*Token)
+	return token, ok
+}
+
+// RequestOwner identifies the caller: the owner of the API token used, or the owner query
+// parameter of dashboard sessions. The flag reports whether a token vouched for the owner.
+func RequestOwner(c *gin.Context) (string, bool) {
+	if token, ok := FromContext(c); ok && token.Owner != "" {
+		return token.Owner, true
+	}
+	return c.Query("owner"), false
+}
+
+// Middleware authenticates kube-dash API tokens sent as bearer credentials and
+// enforces their scope. Requests without a kube-dash token pass through unchanged
+// so browser sessions keep working.
+func Middleware(store *Store, auditor *audit.Recorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		secret, ok := bearerToken(c.GetHeader("Authorization"))
+		if !ok || !IsToken(secret) {
+			c.Next()
+			return
+		}
+
+		deny := func(status int, token *Token, reason string) {
+			event := audit.Event{
+				Action:     "apitoken.request",
+				Outcome:    audit.OutcomeDenied,
+				Reason:     reason,
+				RemoteAddr: c.ClientIP(),
+				UserAgent:  c.Request.UserAgent(),
+				ConfigID:   c.Query("config"),
+				Cluster:    c.Query("cluster"),
+				Details:    map[string]string{"method": c.Request.Method, "path": c.Request.URL.Path},
+			}
+			if token != nil {
+				event.Details["token"] = token.ID
+				event.Details["tokenName"] = token.Name
+			}
+			auditor.Record(event)
+			c.AbortWithStatusJSON(status, gin.H{"error": reason})
+		}
+
+		token, err := store.Authenticate(secret)
+		if err != nil {
+			deny(http.StatusUnauthorized, nil, err.Error())
+			return
+		}
+		if strings.HasPrefix(c.Request.URL.Path, managementPath) {
+			deny(http.StatusForbidden, token, "API tokens cannot be used to manage API tokens")
+			return
+		}
+		if !token.AllowsMethod(c.Request.Method) {
+			deny(http.StatusForbidden, token, "API token is read-only")
+			return
+		}
+		if len(token.Clusters) > 0 {
+			identified := false
+			for _, params := range clusterParams {
+				configID := c.Query(params[0])
+				if configID == "" {
+					continue
+				}
+				identified = true
+				if !token.AllowsCluster(configID, c.Query(params[1])) {
+					deny(http.StatusForbidden, token, "API token is not scoped to this cluster")
+					return
+				}
+			}
+			// Cluster-scoped tokens may read cluster-independent endpoints but not change them
+			if !identified && !readOnlyMethod(c.Request.Method) {
+				deny(http.StatusForbidden, token, "cluster-scoped API tokens can only modify resources in their clusters")
+				return
+			}
+		}
+
+		c.Set(contextKey, token)
+		c.Next()
+	}
+}
+
+func bearerToken(header string) (string, bool) {
+	const scheme = "Bearer "
+	if len(header) <= len(scheme) || !strings.EqualFold(header[:len(scheme)], scheme) {
+		return "", false
+	}
+	return strings.TrimSpace(header[len(scheme):]), true
+}
+
+// Actor names the caller for audit records and decisions: the API token's owner or name, or the
+// dashboard session
+func Actor(c *gin.Context) string {
+	if token, ok := FromContext(c); ok {
+		if token.Owner != "" {
+			return token.Owner
+		}
+		return "token:" + token.Name
+	}
+	return "dashboard"
+}
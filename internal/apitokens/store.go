@@ -0,0 +1,180 @@
+package apitokens
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+const (
+	tokensCollection = "api_tokens"
+
+	// lastUsedInterval limits how often last-used times are written back to storage
+	lastUsedInterval = time.Minute
+)
+
+// Authentication errors
+var (
+	ErrInvalidToken = errors.New("invalid API token")
+	ErrTokenExpired = errors.New("API token has expired")
+	ErrTokenRevoked = errors.New("API token has been revoked")
+)
+
+// Store persists API tokens and authenticates bearer secrets against them
+type Store struct {
+	documents *storage.DocumentStore
+	logger    *logger.Logger
+
+	mu     sync.RWMutex
+	tokens map[string]*Token // keyed by hash
+}
+
+// NewStore creates a token store
+func NewStore(documents *storage.DocumentStore, log *logger.Logger) *Store {
+	s := &Store{
+		documents: documents,
+		logger:    log,
+		tokens:    map[string]*Token{},
+	}
+	if err := s.reload(); err != nil {
+		log.WithError(err).Error("Failed to load API tokens")
+	}
+	return s
+}
+
+// Create validates and stores a new token, returning the secret. The secret is
+// not stored and cannot be retrieved again.
+func (s *Store) Create(token *Token) (string, error) {
+	if err := token.Validate(); err != nil {
+		return "", err
+	}
+	secret, err := generateSecret()
+	if err != nil {
+		return "", err
+	}
+	token.ID = uuid.New().String()
+	token.Hash = hashSecret(secret)
+	token.Prefix = secret[:len(tokenPrefix)+6]
+	token.CreatedAt = time.Now()
+	token.RevokedAt = nil
+	token.LastUsedAt = nil
+	if err := s.put(token); err != nil {
+		return "", err
+	}
+	return secret, s.reload()
+}
+
+// List returns all tokens, newest first
+func (s *Store) List() []Token {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	tokens := make([]Token, 0, len(s.tokens))
+	for _, t := range s.tokens {
+		tokens = append(tokens, *t)
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].CreatedAt.After(tokens[j].CreatedAt) })
+	return tokens
+}
+
+// Get returns a token by ID
+func (s *Store) Get(id string) (*Token, error) {
+	var stored storedToken
+	if err := s.documents.Get(tokensCollection, id, &stored); err != nil {
+		return nil, err
+	}
+	stored.Token.Hash = stored.Hash
+	return &stored.Token, nil
+}
+
+// Revoke marks a token as revoked. Revoked tokens are kept so their use shows up as revoked.
+func (s *Store) Revoke(id string) (*Token, error) {
+	token, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if token.RevokedAt == nil {
+		now := time.Now()
+		token.RevokedAt = &now
+		if err := s.put(token); err != nil {
+			return nil, err
+		}
+	}
+	return token, s.reload()
+}
+
+// Delete removes a token permanently
+func (s *Store) Delete(id string) error {
+	if err := s.documents.Delete(tokensCollection, id); err != nil {
+		return err
+	}
+	return s.reload()
+}
+
+// Authenticate resolves a bearer secret to its token and records its use
+func (s *Store) Authenticate(secret string) (*Token, error) {
+	hash := hashSecret(secret)
+	now := time.Now()
+
+	s.mu.Lock()
+	token, ok := s.tokens[hash]
+	if !ok {
+		s.mu.Unlock()
+		return nil, ErrInvalidToken
+	}
+	if token.RevokedAt != nil {
+		s.mu.Unlock()
+		return nil, ErrTokenRevoked
+	}
+	if !token.Active(now) {
+		s.mu.Unlock()
+		return nil, ErrTokenExpired
+	}
+	persist := token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= lastUsedInterval
+	if persist {
+		token.LastUsedAt = &now
+	}
+	snapshot := *token
+	s.mu.Unlock()
+
+	if persist {
+		if err := s.put(&snapshot); err != nil {
+			s.logger.WithError(err).WithField("token", snapshot.ID).Warn("Failed to record API token use")
+		}
+	}
+	return &snapshot, nil
+}
+
+func (s *Store) put(token *Token) error {
+	return s.documents.Put(tokensCollection, token.ID, storedToken{Token: *token, Hash: token.Hash})
+}
+
+// reload refreshes the in-memory cache used on the request path
+func (s *Store) reload() error {
+	docs, err := s.documents.List(tokensCollection)
+	if err != nil {
+		return err
+	}
+	tokens := make(map[string]*Token, len(docs))
+	for id, data := range docs {
+		var stored storedToken
+		if err := json.Unmarshal(data, &stored); err != nil || stored.Hash == "" {
+			s.logger.WithField("token", id).Error("Skipping unreadable API token")
+			continue
+		}
+		token := stored.Token
+		token.Hash = stored.Hash
+		tokens[token.Hash] = &token
+	}
+
+	s.mu.Lock()
+	s.tokens = tokens
+	s.mu.Unlock()
+	return nil
+}
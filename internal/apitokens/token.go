@@ -0,0 +1,125 @@
+package apitokens
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Token kinds
+const (
+	KindPersonal = "personal"
+	KindService  = "service"
+)
+
+// tokenPrefix marks kube-dash tokens so other bearer tokens (e.g. from an auth proxy) are left alone
+const tokenPrefix = "kd_"
+
+// ClusterScope limits a token to one kubeconfig, optionally to a single cluster (context) in it
+type ClusterScope struct {
+	ConfigID string `json:"configId"`
+	Cluster  string `json:"cluster,omitempty"`
+}
+
+// Token is an API token for automation. Only the SHA-256 hash of the secret is stored.
+type Token struct {
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Kind        string         `json:"kind"` // personal or service
+	Owner       string         `json:"owner,omitempty"`
+	Prefix      string         `json:"prefix"` // first characters of the secret, for identification
+	Hash        string         `json:"-"`
+	ReadOnly    bool           `json:"readOnly"`
+	Clusters    []ClusterScope `json:"clusters,omitempty"` // empty means all clusters
+	ExpiresAt   *time.Time     `json:"expiresAt,omitempty"`
+	RevokedAt   *time.Time     `json:"revokedAt,omitempty"`
+	LastUsedAt  *time.Time     `json:"lastUsedAt,omitempty"`
+	CreatedAt   time.Time      `json:"createdAt"`
+
+	// RequireElevation limits pod exec, debug pods and resource deletion to namespaces
+	// with an approved, unexpired elevated access grant
+	RequireElevation bool `json:"requireElevation,omitempty"`
+}
+
+// storedToken is the persisted form; Hash is hidden from API responses but must be stored
+type storedToken struct {
+	Token
+	Hash string `json:"hash"`
+}
+
+// Validate checks that a token definition is well formed
+func (t *Token) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if t.Kind != KindPersonal && t.Kind != KindService {
+		return fmt.Errorf("kind must be %s or %s", KindPersonal, KindService)
+	}
+	for _, scope := range t.Clusters {
+		if scope.ConfigID == "" {
+			return fmt.Errorf("cluster scopes require a configId")
+		}
+	}
+	if t.ExpiresAt != nil && !t.ExpiresAt.After(time.Now()) {
+		return fmt.Errorf("expiresAt must be in the future")
+	}
+	return nil
+}
+
+// Active reports whether the token can currently be used
+func (t *Token) Active(now time.Time) bool {
+	if t.RevokedAt != nil {
+		return false
+	}
+	return t.ExpiresAt == nil || now.Before(*t.ExpiresAt)
+}
+
+// AllowsMethod reports whether the token's access level permits an HTTP method
+func (t *Token) AllowsMethod(method string) bool {
+	return !t.ReadOnly || readOnlyMethod(method)
+}
+
+func readOnlyMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// AllowsCluster reports whether the token is scoped to a kubeconfig and cluster
+func (t *Token) AllowsCluster(configID, cluster string) bool {
+	if len(t.Clusters) == 0 {
+		return true
+	}
+	for _, scope := range t.Clusters {
+		if scope.ConfigID != configID {
+			continue
+		}
+		if scope.Cluster == "" || scope.Cluster == cluster {
+			return true
+		}
+	}
+	return false
+}
+
+// generateSecret returns a new random token secret
+func generateSecret() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return tokenPrefix + hex.EncodeToString(buf), nil
+}
+
+// hashSecret returns the stored form of a token secret
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsToken reports whether a bearer credential looks like a kube-dash API token
+func IsToken(credential string) bool {
+	return strings.HasPrefix(credential, tokenPrefix)
+}
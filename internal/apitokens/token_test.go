@@ -0,0 +1,66 @@
+package apitokens
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestTokenScope(t *testing.T) {
+	token := Token{
+		ReadOnly: true,
+		Clusters: []ClusterScope{{ConfigID: "prod"}, {ConfigID: "shared", Cluster: "ci"}},
+	}
+	if !token.AllowsMethod(http.MethodGet) || token.AllowsMethod(http.MethodPost) {
+		t.Errorf("read-only token should only allow reads")
+	}
+	cases := []struct {
+		config, cluster string
+		want            bool
+	}{
+		{"prod", "", true},
+		{"prod", "eu-1", true},
+		{"shared", "ci", true},
+		{"shared", "other", false},
+		{"staging", "", false},
+	}
+	for _, tc := range cases {
+		if got := token.AllowsCluster(tc.config, tc.cluster); got != tc.want {
+			t.Errorf("AllowsCluster(%q, %q) = %v, want %v", tc.config, tc.cluster, got, tc.want)
+		}
+	}
+	if !(&Token{}).AllowsCluster("anything", "") {
+		t.Errorf("unscoped token should allow every cluster")
+	}
+}
+
+func TestTokenActive(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	if !(&Token{ExpiresAt: &future}).Active(now) {
+		t.Errorf("token before expiry should be active")
+	}
+	if (&Token{ExpiresAt: &past}).Active(now) {
+		t.Errorf("expired token should be inactive")
+	}
+	if (&Token{RevokedAt: &past}).Active(now) {
+		t.Errorf("revoked token should be inactive")
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	secret, err := generateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, ok := bearerToken("bearer " + secret)
+	if !ok || got != secret || !IsToken(got) {
+		t.Errorf("bearerToken = %q, %v", got, ok)
+	}
+	if _, ok := bearerToken("Basic abc"); ok {
+		t.Errorf("non-bearer credentials should be ignored")
+	}
+	if hashSecret(secret) == secret || len(hashSecret(secret)) != 64 {
+		t.Errorf("unexpected hash")
+	}
+}
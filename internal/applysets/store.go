@@ -0,0 +1,184 @@
+package applysets
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// setsCollection is the document collection holding apply sets
+const setsCollection = "apply_sets"
+
+// maxSetsPerCluster bounds the apply sets kept for one cluster; the oldest are dropped first
+const maxSetsPerCluster = 50
+
+// ErrRolledBack is returned when rolling back an apply set that was already rolled back
+var ErrRolledBack = errors.New("apply set was already rolled back")
+
+// Object is an object an apply modified, with its state before the apply
+type Object struct {
+	Group     string `json:"group"`
+	Version   string `json:"version"`
+	Kind      string `json:"kind"`
+	Resource  string `json:"resource"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// Existed is false when the apply created the object
+	Existed bool `json:"existed"`
+	// Prior is the object before the apply, set when it existed
+	Prior map[string]interface{} `json:"prior,omitempty"`
+	// AppliedResourceVersion is the resourceVersion the apply left the object at, to tell whether
+	// it changed since
+	AppliedResourceVersion string `json:"appliedResourceVersion,omitempty"`
+	// Error is set when the prior state could not be read; such objects are not rolled back
+	Error string `json:"error,omitempty"`
+}
+
+// ApplySet records the objects one apply modified so it can be rolled back
+type ApplySet struct {
+	ID           string     `json:"id"`
+	ConfigID     string     `json:"configId"`
+	Cluster      string     `json:"cluster"`
+	AppliedBy    string     `json:"appliedBy,omitempty"`
+	AppliedAt    time.Time  `json:"appliedAt"`
+	Objects      []Object   `json:"objects"` // in apply order
+	RolledBackAt *time.Time `json:"rolledBackAt,omitempty"`
+	RolledBackBy string     `json:"rolledBackBy,omitempty"`
+}
+
+// Summary returns the apply set without the prior states of its objects
+func (s ApplySet) Summary() ApplySet {
+	objects := make([]Object, len(s.Objects))
+	for i, obj := range s.Objects {
+		obj.Prior = nil
+		objects[i] = obj
+	}
+	s.Objects = objects
+	return s
+}
+
+// Filter narrows the apply sets returned by List; empty fields match everything
+type Filter struct {
+	ConfigID string
+	Cluster  string
+}
+
+// Store persists apply sets
+type Store struct {
+	documents *storage.DocumentStore
+	logger    *logger.Logger
+}
+
+// NewStore creates an apply set store
+func NewStore(documents *storage.DocumentStore, log *logger.Logger) *Store {
+	return &Store{
+		documents: documents,
+		logger:    log,
+	}
+}
+
+// list returns the apply sets matching the filter with their prior states, newest first
+func (s *Store) list(filter Filter) ([]ApplySet, error) {
+	docs, err := s.documents.List(setsCollection)
+	if err != nil {
+		return nil, err
+	}
+	sets := make([]ApplySet, 0, len(docs))
+	for id, data := range docs {
+		var set ApplySet
+		if err := json.Unmarshal(data, &set); err != nil {
+			s.logger.WithError(err).WithField("apply_set", id).Error("Skipping unreadable apply set")
+			continue
+		}
+		if (filter.ConfigID != "" && set.ConfigID != filter.ConfigID) || (filter.Cluster != "" && set.Cluster != filter.Cluster) {
+			continue
+		}
+		sets = append(sets, set)
+	}
+	sort.Slice(sets, func(i, j int) bool { return sets[i].AppliedAt.After(sets[j].AppliedAt) })
+	return sets, nil
+}
+
+// List returns apply set summaries matching the filter, newest first
+func (s *Store) List(filter Filter) ([]ApplySet, error) {
+	sets, err := s.list(filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sets {
+		sets[i] = sets[i].Summary()
+	}
+	return sets, nil
+}
+
+// Get returns an apply set with the prior states of its objects
+func (s *Store) Get(id string) (*ApplySet, error) {
+	var set ApplySet
+	if err := s.documents.Get(setsCollection, id, &set); err != nil {
+		return nil, err
+	}
+	return &set, nil
+}
+
+// Save persists a new apply set, assigning its ID, and drops the oldest sets of its cluster
+// beyond the retention limit
+func (s *Store) Save(set *ApplySet) error {
+	set.ID = uuid.New().String()
+	if set.AppliedAt.IsZero() {
+		set.AppliedAt = time.Now()
+	}
+	if err := s.documents.Put(setsCollection, set.ID, set); err != nil {
+		return err
+	}
+
+	sets, err := s.list(Filter{ConfigID: set.ConfigID, Cluster: set.Cluster})
+	if err != nil {
+		return nil
+	}
+	for i := maxSetsPerCluster; i < len(sets); i++ {
+		if err := s.documents.Delete(setsCollection, sets[i].ID); err != nil {
+			s.logger.WithError(err).WithField("apply_set", sets[i].ID).Warn("Failed to delete expired apply set")
+		}
+	}
+	return nil
+}
+
+// MarkRolledBack records that an apply set was rolled back
+func (s *Store) MarkRolledBack(id, by string) error {
+	set, err := s.Get(id)
+	if err != nil {
+		return err
+	}
+	if set.RolledBackAt != nil {
+		return ErrRolledBack
+	}
+	now := time.Now()
+	set.RolledBackAt = &now
+	set.RolledBackBy = by
+	return s.documents.Put(setsCollection, set.ID, set)
+}
+
+// Cleanup removes the apply sets of a removed kubeconfig or cluster
+func (s *Store) Cleanup(removal storage.Removal) (int, error) {
+	sets, err := s.list(Filter{ConfigID: removal.ConfigID})
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, set := range sets {
+		if !removal.Matches(set.ConfigID, set.Cluster) {
+			continue
+		}
+		if err := s.documents.Delete(setsCollection, set.ID); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
@@ -0,0 +1,156 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+const (
+	eventsCollection = "audit_events"
+
+	// maxEvents bounds the stored trail; older events are pruned
+	maxEvents = 5000
+	// pruneEvery is how many writes happen between prune passes
+	pruneEvery = 100
+)
+
+// Outcomes
+const (
+	OutcomeAllowed = "allowed"
+	OutcomeDenied  = "denied"
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// Event is a single entry in the audit trail
+type Event struct {
+	ID         string            `json:"id"`
+	Time       time.Time         `json:"time"`
+	Action     string            `json:"action"` // e.g. terminal.exec
+	Outcome    string            `json:"outcome"`
+	Reason     string            `json:"reason,omitempty"`
+	RemoteAddr string            `json:"remoteAddr,omitempty"`
+	UserAgent  string            `json:"userAgent,omitempty"`
+	ConfigID   string            `json:"configId,omitempty"`
+	Cluster    string            `json:"cluster,omitempty"`
+	Namespace  string            `json:"namespace,omitempty"`
+	Resource   string            `json:"resource,omitempty"` // Kind/name
+	Details    map[string]string `json:"details,omitempty"`
+}
+
+// Filter narrows the events returned by List; empty fields match everything
+type Filter struct {
+	Action    string
+	Outcome   string
+	ConfigID  string
+	Cluster   string
+	Namespace string
+	Since     time.Time
+	Limit     int
+}
+
+// Recorder writes audit events to the log and the document store
+type Recorder struct {
+	documents *storage.DocumentStore
+	logger    *logger.Logger
+
+	mu     sync.Mutex
+	writes int
+}
+
+// NewRecorder creates an audit recorder
+func NewRecorder(documents *storage.DocumentStore, log *logger.Logger) *Recorder {
+	return &Recorder{
+		documents: documents,
+		logger:    log,
+	}
+}
+
+// Record stores an event, filling in its ID and time. Failures are logged and
+// never returned so auditing cannot break the audited operation.
+func (r *Recorder) Record(event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	// IDs sort chronologically so listing and pruning need no extra index
+	event.ID = fmt.Sprintf("%020d-%s", event.Time.UnixNano(), uuid.New().String()[:8])
+
+	entry := r.logger.WithField("audit", event.Action).
+		WithField("outcome", event.Outcome).
+		WithField("namespace", event.Namespace).
+		WithField("resource", event.Resource).
+		WithField("remoteAddr", event.RemoteAddr)
+	if event.Reason != "" {
+		entry = entry.WithField("reason", event.Reason)
+	}
+	entry.Info("Audit event")
+
+	if err := r.documents.Put(eventsCollection, event.ID, event); err != nil {
+		r.logger.WithError(err).Error("Failed to store audit event")
+		return
+	}
+
+	r.mu.Lock()
+	r.writes++
+	prune := r.writes%pruneEvery == 0
+	r.mu.Unlock()
+	if prune {
+		r.prune()
+	}
+}
+
+// List returns matching events, newest first
+func (r *Recorder) List(filter Filter) ([]Event, error) {
+	docs, err := r.documents.List(eventsCollection)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]Event, 0, len(docs))
+	for _, data := range docs {
+		var event Event
+		if err := json.Unmarshal(data, &event); err != nil {
+			continue
+		}
+		if (filter.Action != "" && event.Action != filter.Action) ||
+			(filter.Outcome != "" && event.Outcome != filter.Outcome) ||
+			(filter.ConfigID != "" && event.ConfigID != filter.ConfigID) ||
+			(filter.Cluster != "" && event.Cluster != filter.Cluster) ||
+			(filter.Namespace != "" && event.Namespace != filter.Namespace) ||
+			(!filter.Since.IsZero() && event.Time.Before(filter.Since)) {
+			continue
+		}
+		events = append(events, event)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].ID > events[j].ID })
+	if filter.Limit > 0 && len(events) > filter.Limit {
+		events = events[:filter.Limit]
+	}
+	return events, nil
+}
+
+// prune deletes the oldest events beyond maxEvents
+func (r *Recorder) prune() {
+	docs, err := r.documents.List(eventsCollection)
+	if err != nil || len(docs) <= maxEvents {
+		return
+	}
+	ids := make([]string, 0, len(docs))
+	for id := range docs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids[:len(ids)-maxEvents] {
+		if err := r.documents.Delete(eventsCollection, id); err != nil {
+			r.logger.WithError(err).Warn("Failed to prune audit event")
+			return
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package bookmarks
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Document collections of favorite and recently viewed resources
+const (
+	favoritesCollection = "resource_favorites"
+	recentCollection    = "resource_recent"
+)
+
+// MaxRecent is the number of recently viewed resources kept per user and cluster
+const MaxRecent = 50
+
+// ResourceRef identifies a resource of any kind on a cluster
+type ResourceRef struct {
+	ConfigID   string `json:"configId"`
+	Cluster    string `json:"cluster,omitempty"`
+	APIVersion string `json:"apiVersion"` // e.g. v1 or apps/v1
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"` // empty for cluster-scoped resources
+	Name       string `json:"name"`
+}
+
+// Validate checks that a reference is complete, trimming its fields
+func (r *ResourceRef) Validate() error {
+	r.APIVersion = strings.TrimSpace(r.APIVersion)
+	r.Kind = strings.TrimSpace(r.Kind)
+	r.Namespace = strings.TrimSpace(r.Namespace)
+	r.Name = strings.TrimSpace(r.Name)
+	if r.ConfigID == "" {
+		return fmt.Errorf("configId is required")
+	}
+	if r.Kind == "" || r.Name == "" {
+		return fmt.Errorf("kind and name are required")
+	}
+	if r.APIVersion == "" {
+		return fmt.Errorf("apiVersion is required")
+	}
+	if _, err := schema.ParseGroupVersion(r.APIVersion); err != nil {
+		return fmt.Errorf("invalid apiVersion %q: %w", r.APIVersion, err)
+	}
+	return nil
+}
+
+// GroupVersionKind returns the kind of the referenced resource
+func (r ResourceRef) GroupVersionKind() schema.GroupVersionKind {
+	gv, _ := schema.ParseGroupVersion(r.APIVersion)
+	return gv.WithKind(r.Kind)
+}
+
+// key identifies the resource regardless of its version, so a resource is kept once
+func (r ResourceRef) key() string {
+	return strings.Join([]string{r.ConfigID, r.Cluster, r.GroupVersionKind().Group, r.Kind, r.Namespace, r.Name}, "\x00")
+}
+
+// inCluster reports whether the resource is on a cluster; an empty config ID matches any
+func (r ResourceRef) inCluster(configID, cluster string) bool {
+	return configID == "" || (r.ConfigID == configID && r.Cluster == cluster)
+}
+
+// Favorite is a resource a user marked for their home screen
+type Favorite struct {
+	Owner string `json:"owner,omitempty"` // empty for callers that do not identify themselves
+	ResourceRef
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// RecentItem is a resource a user viewed
+type RecentItem struct {
+	Owner string `json:"owner,omitempty"`
+	ResourceRef
+	ViewedAt time.Time `json:"viewedAt"`
+	Views    int       `json:"views"`
+}
+
+// bookmarkID derives the document ID of a user's favorite or recent item
+func bookmarkID(owner string, ref ResourceRef) string {
+	sum := sha256.Sum256([]byte(owner + "\x00" + ref.key()))
+	return hex.EncodeToString(sum[:16])
+}
+
+// Store persists the favorite and recently viewed resources of users
+type Store struct {
+	documents *storage.DocumentStore
+	logger    *logger.Logger
+	now       func() time.Time
+}
+
+// NewStore creates a resource bookmark store
+func NewStore(documents *storage.DocumentStore, log *logger.Logger) *Store {
+	return &Store{
+		documents: documents,
+		logger:    log,
+		now:       time.Now,
+	}
+}
+
+// Favorites returns a user's favorites on a cluster, or on all clusters when configID is empty,
+// sorted by kind, namespace and name
+func (s *Store) Favorites(owner, configID, cluster string) ([]Favorite, error) {
+	docs, err := s.documents.List(favoritesCollection)
+	if err != nil {
+		return nil, err
+	}
+	favorites := []Favorite{}
+	for id, data := range docs {
+		var f Favorite
+		if err := json.Unmarshal(data, &f); err != nil {
+			s.logger.WithError(err).WithField("favorite", id).Error("Skipping unreadable resource favorite")
+			continue
+		}
+		if f.Owner == owner && f.inCluster(configID, cluster) {
+			favorites = append(favorites, f)
+		}
+	}
+	sort.Slice(favorites, func(i, j int) bool {
+		a, b := favorites[i], favorites[j]
+		if a.ConfigID != b.ConfigID || a.Cluster != b.Cluster {
+			return a.ConfigID+"\x00"+a.Cluster < b.ConfigID+"\x00"+b.Cluster
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return favorites, nil
+}
+
+// AddFavorite marks a resource as a favorite, keeping the creation time when it already is one
+func (s *Store) AddFavorite(owner string, ref ResourceRef) (*Favorite, error) {
+	if err := ref.Validate(); err != nil {
+		return nil, err
+	}
+	id := bookmarkID(owner, ref)
+	favorite := Favorite{Owner: owner, ResourceRef: ref, CreatedAt: s.now()}
+	var existing Favorite
+	if err := s.documents.Get(favoritesCollection, id, &existing); err == nil {
+		favorite.CreatedAt = existing.CreatedAt
+	}
+	if err := s.documents.Put(favoritesCollection, id, favorite); err != nil {
+		return nil, err
+	}
+	return &favorite, nil
+}
+
+// RemoveFavorite unmarks a resource
+func (s *Store) RemoveFavorite(owner string, ref ResourceRef) error {
+	if err := ref.Validate(); err != nil {
+		return err
+	}
+	return s.documents.Delete(favoritesCollection, bookmarkID(owner, ref))
+}
+
+// recent returns all of a user's recent items on a cluster, most recently viewed first
+func (s *Store) recent(owner, configID, cluster string) ([]RecentItem, error) {
+	docs, err := s.documents.List(recentCollection)
+	if err != nil {
+		return nil, err
+	}
+	items := []RecentItem{}
+	for id, data := range docs {
+		var item RecentItem
+		if err := json.Unmarshal(data, &item); err != nil {
+			s.logger.WithError(err).WithField("item", id).Error("Skipping unreadable recent resource")
+			continue
+		}
+		if item.Owner == owner && item.inCluster(configID, cluster) {
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ViewedAt.After(items[j].ViewedAt) })
+	return items, nil
+}
+
+// Recent returns a user's recently viewed resources on a cluster, or on all clusters when
+// configID is empty, most recent first; limit bounds the items when positive
+func (s *Store) Recent(owner, configID, cluster string, limit int) ([]RecentItem, error) {
+	items, err := s.recent(owner, configID, cluster)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+// RecordView notes that a user viewed a resource. Only the MaxRecent most recent resources of
+// the cluster are kept.
+func (s *Store) RecordView(owner string, ref ResourceRef) (*RecentItem, error) {
+	if err := ref.Validate(); err != nil {
+		return nil, err
+	}
+	id := bookmarkID(owner, ref)
+	item := RecentItem{Owner: owner, ResourceRef: ref, ViewedAt: s.now(), Views: 1}
+	var existing RecentItem
+	if err := s.documents.Get(recentCollection, id, &existing); err == nil {
+		item.Views = existing.Views + 1
+	}
+	if err := s.documents.Put(recentCollection, id, item); err != nil {
+		return nil, err
+	}
+
+	items, err := s.recent(owner, ref.ConfigID, ref.Cluster)
+	if err != nil {
+		return nil, err
+	}
+	for _, old := range items[min(len(items), MaxRecent):] {
+		if err := s.documents.Delete(recentCollection, bookmarkID(owner, old.ResourceRef)); err != nil {
+			s.logger.WithError(err).Warn("Failed to trim recent resources")
+		}
+	}
+	return &item, nil
+}
+
+// RemoveRecent forgets a viewed resource
+func (s *Store) RemoveRecent(owner string, ref ResourceRef) error {
+	if err := ref.Validate(); err != nil {
+		return err
+	}
+	return s.documents.Delete(recentCollection, bookmarkID(owner, ref))
+}
+
+// ClearRecent forgets all resources a user viewed on a cluster, returning how many were removed
+func (s *Store) ClearRecent(owner, configID, cluster string) (int, error) {
+	if configID == "" {
+		return 0, fmt.Errorf("configId is required")
+	}
+	items, err := s.recent(owner, configID, cluster)
+	if err != nil {
+		return 0, err
+	}
+	for _, item := range items {
+		if err := s.documents.Delete(recentCollection, bookmarkID(owner, item.ResourceRef)); err != nil {
+			return 0, err
+		}
+	}
+	return len(items), nil
+}
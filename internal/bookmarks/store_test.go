@@ -0,0 +1,136 @@
+package bookmarks
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+)
+
+func TestResourceRefValidate(t *testing.T) {
+	ref := ResourceRef{ConfigID: "cfg", APIVersion: "apps/v1", Kind: " Deployment ", Namespace: "shop", Name: "web "}
+	if err := ref.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if ref.Kind != "Deployment" || ref.Name != "web" || ref.GroupVersionKind().Group != "apps" {
+		t.Errorf("ref = %+v", ref)
+	}
+
+	for i, invalid := range []ResourceRef{
+		{APIVersion: "v1", Kind: "Pod", Name: "web"},
+		{ConfigID: "cfg", APIVersion: "v1", Name: "web"},
+		{ConfigID: "cfg", Kind: "Pod", Name: "web"},
+		{ConfigID: "cfg", APIVersion: "apps/v1/beta", Kind: "Pod", Name: "web"},
+	} {
+		if err := invalid.Validate(); err == nil {
+			t.Errorf("case %d: expected a validation error", i)
+		}
+	}
+
+	v1 := ResourceRef{ConfigID: "cfg", APIVersion: "autoscaling/v1", Kind: "HorizontalPodAutoscaler", Namespace: "shop", Name: "web"}
+	v2 := v1
+	v2.APIVersion = "autoscaling/v2"
+	if bookmarkID("alice", v1) != bookmarkID("alice", v2) {
+		t.Error("bookmarkID() differs across versions of the same resource")
+	}
+	if bookmarkID("alice", v1) == bookmarkID("bob", v1) {
+		t.Error("bookmarkID() collided across owners")
+	}
+}
+
+func TestFavorites(t *testing.T) {
+	store := NewStore(storage.NewDocumentStore(nil), logger.New("error"))
+	web := ResourceRef{ConfigID: "cfg", APIVersion: "apps/v1", Kind: "Deployment", Namespace: "shop", Name: "web"}
+	node := ResourceRef{ConfigID: "cfg", APIVersion: "v1", Kind: "Node", Name: "worker-1"}
+	other := ResourceRef{ConfigID: "cfg", Cluster: "staging", APIVersion: "v1", Kind: "Node", Name: "worker-1"}
+
+	first, err := store.AddFavorite("alice", web)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ref := range []ResourceRef{web, node, other} {
+		if _, err := store.AddFavorite("alice", ref); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := store.AddFavorite("bob", web); err != nil {
+		t.Fatal(err)
+	}
+
+	favorites, err := store.Favorites("alice", "cfg", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(favorites) != 2 || favorites[0].Kind != "Deployment" || favorites[1].Kind != "Node" {
+		t.Fatalf("favorites = %+v, want the deployment and node of the default cluster", favorites)
+	}
+	if !favorites[0].CreatedAt.Equal(first.CreatedAt) {
+		t.Errorf("re-adding a favorite changed its creation time")
+	}
+	if all, _ := store.Favorites("alice", "", ""); len(all) != 3 {
+		t.Errorf("got %d favorites across clusters, want 3", len(all))
+	}
+
+	if err := store.RemoveFavorite("alice", web); err != nil {
+		t.Fatal(err)
+	}
+	if favorites, _ := store.Favorites("alice", "cfg", ""); len(favorites) != 1 {
+		t.Errorf("favorites after removal = %+v", favorites)
+	}
+	if favorites, _ := store.Favorites("bob", "cfg", ""); len(favorites) != 1 {
+		t.Errorf("removing alice's favorite changed bob's: %+v", favorites)
+	}
+}
+
+func TestRecordView(t *testing.T) {
+	store := NewStore(storage.NewDocumentStore(nil), logger.New("error"))
+	clock := time.Date(2026, 1, 10, 10, 0, 0, 0, time.UTC)
+	store.now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+	pod := func(i int) ResourceRef {
+		return ResourceRef{ConfigID: "cfg", APIVersion: "v1", Kind: "Pod", Namespace: "shop", Name: fmt.Sprintf("web-%d", i)}
+	}
+
+	for i := 0; i < MaxRecent+5; i++ {
+		if _, err := store.RecordView("alice", pod(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	item, err := store.RecordView("alice", pod(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Views != 2 {
+		t.Errorf("views = %d, want 2", item.Views)
+	}
+
+	recent, err := store.Recent("alice", "cfg", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recent) != MaxRecent || recent[0].Name != "web-10" || recent[1].Name != fmt.Sprintf("web-%d", MaxRecent+4) {
+		t.Fatalf("got %d recent items starting %+v", len(recent), recent[0])
+	}
+	for _, item := range recent {
+		if item.Name == "web-0" {
+			t.Error("the oldest view was not trimmed")
+		}
+	}
+	if limited, _ := store.Recent("alice", "cfg", "", 5); len(limited) != 5 {
+		t.Errorf("limit 5 returned %d items", len(limited))
+	}
+
+	if err := store.RemoveRecent("alice", pod(10)); err != nil {
+		t.Fatal(err)
+	}
+	if removed, err := store.ClearRecent("alice", "cfg", ""); err != nil || removed != MaxRecent-1 {
+		t.Errorf("ClearRecent() = %d, %v", removed, err)
+	}
+	if recent, _ := store.Recent("alice", "cfg", "", 0); len(recent) != 0 {
+		t.Errorf("recent after clearing = %+v", recent)
+	}
+}
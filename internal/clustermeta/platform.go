@@ -0,0 +1,66 @@
+package clustermeta
+
+import "strings"
+
+// Platforms reported by DetectPlatform
+const (
+	PlatformEKS       = "EKS"
+	PlatformGKE       = "GKE"
+	PlatformAKS       = "AKS"
+	PlatformK3s       = "k3s"
+	PlatformRKE2      = "RKE2"
+	PlatformOpenShift = "OpenShift"
+	PlatformDOKS      = "DOKS"
+	PlatformKind      = "kind"
+	PlatformMinikube  = "minikube"
+	PlatformUnknown   = "unknown"
+)
+
+// versionMarkers identify distributions that tag the server's git version
+var versionMarkers = []struct{ marker, platform string }{
+	{"-eks-", PlatformEKS},
+	{"-gke.", PlatformGKE},
+	{"+k3s", PlatformK3s},
+	{"+rke2", PlatformRKE2},
+}
+
+// nodeLabelMarkers identify platforms by a label they set on nodes
+var nodeLabelMarkers = []struct{ label, platform string }{
+	{"eks.amazonaws.com/nodegroup", PlatformEKS},
+	{"cloud.google.com/gke-nodepool", PlatformGKE},
+	{"kubernetes.azure.com/cluster", PlatformAKS},
+	{"node.openshift.io/os_id", PlatformOpenShift},
+	{"doks.digitalocean.com/node-id", PlatformDOKS},
+	{"minikube.k8s.io/name", PlatformMinikube},
+}
+
+// providerPrefixes identify platforms by node provider ID
+var providerPrefixes = []struct{ prefix, platform string }{
+	{"azure://", PlatformAKS},
+	{"gce://", PlatformGKE},
+	{"k3s://", PlatformK3s},
+	{"kind://", PlatformKind},
+	{"digitalocean://", PlatformDOKS},
+}
+
+// DetectPlatform guesses the Kubernetes distribution from the server git version and a sample
+// node's provider ID and labels. Version markers are checked first since they are set by the
+// control plane itself; AWS provider IDs alone do not imply EKS.
+func DetectPlatform(gitVersion, providerID string, nodeLabels map[string]string) string {
+	for _, m := range versionMarkers {
+		if strings.Contains(gitVersion, m.marker) {
+			return m.platform
+		}
+	}
+	for _, m := range nodeLabelMarkers {
+		if _, ok := nodeLabels[m.label]; ok {
+			return m.platform
+		}
+	}
+	for _, m := range providerPrefixes {
+		if strings.HasPrefix(providerID, m.prefix) {
+			return m.platform
+		}
+	}
+	return PlatformUnknown
+}
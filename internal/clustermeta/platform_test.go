@@ -0,0 +1,39 @@
+package clustermeta
+
+import "testing"
+
+func TestDetectPlatform(t *testing.T) {
+	cases := []struct {
+		version, providerID string
+		labels              map[string]string
+		want                string
+	}{
+		{"v1.29.4-eks-036c24b", "aws:///us-east-1a/i-0abc", nil, PlatformEKS},
+		{"v1.30.2-gke.1587003", "", nil, PlatformGKE},
+		{"v1.30.4+k3s1", "", nil, PlatformK3s},
+		{"v1.29.6", "azure:///subscriptions/x/resourceGroups/y", map[string]string{"kubernetes.azure.com/cluster": "mc_rg"}, PlatformAKS},
+		{"v1.31.0", "kind://docker/dev/dev-control-plane", nil, PlatformKind},
+		{"v1.28.3", "", map[string]string{"minikube.k8s.io/name": "minikube"}, PlatformMinikube},
+		{"v1.29.0", "aws:///us-east-1a/i-0abc", nil, PlatformUnknown},
+	}
+	for _, tc := range cases {
+		if got := DetectPlatform(tc.version, tc.providerID, tc.labels); got != tc.want {
+			t.Errorf("DetectPlatform(%q, %q) = %s, want %s", tc.version, tc.providerID, got, tc.want)
+		}
+	}
+}
+
+func TestMetadataValidate(t *testing.T) {
+	m := Metadata{ConfigID: "cfg", Context: "prod", DisplayName: " Production ", Color: "#E11D48", Tags: []string{"team-a", " ", "team-a", "eu"}}
+	if err := m.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if m.DisplayName != "Production" || len(m.Tags) != 2 {
+		t.Errorf("got %+v", m)
+	}
+	for i, invalid := range []Metadata{{Context: "prod"}, {ConfigID: "cfg", Context: "prod", Color: "red"}} {
+		if err := invalid.Validate(); err == nil {
+			t.Errorf("case %d: expected a validation error", i)
+		}
+	}
+}
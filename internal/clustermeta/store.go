@@ -0,0 +1,150 @@
+package clustermeta
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+)
+
+// metadataCollection is the document collection holding user-edited cluster metadata
+const metadataCollection = "cluster_metadata"
+
+// maxTags bounds the tags one context may carry
+const maxTags = 20
+
+// colorPattern matches #rgb and #rrggbb colors
+var colorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// Metadata is how a user labels a kubeconfig context in the cluster switcher
+type Metadata struct {
+	ConfigID    string    `json:"configId"`
+	Context     string    `json:"context"`
+	DisplayName string    `json:"displayName,omitempty"`
+	Color       string    `json:"color,omitempty"` // #rgb or #rrggbb
+	Tags        []string  `json:"tags,omitempty"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// Validate checks that metadata can be saved and normalizes its tags
+func (m *Metadata) Validate() error {
+	if m.ConfigID == "" || m.Context == "" {
+		return fmt.Errorf("configId and context are required")
+	}
+	m.DisplayName = strings.TrimSpace(m.DisplayName)
+	if len(m.DisplayName) > 100 {
+		return fmt.Errorf("displayName may be at most 100 characters")
+	}
+	if m.Color != "" && !colorPattern.MatchString(m.Color) {
+		return fmt.Errorf("color must be a hex color such as #1f77b4")
+	}
+	seen := map[string]bool{}
+	var tags []string
+	for _, tag := range m.Tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	if len(tags) > maxTags {
+		return fmt.Errorf("a context may carry at most %d tags", maxTags)
+	}
+	m.Tags = tags
+	return nil
+}
+
+// Store persists cluster metadata keyed by config and context
+type Store struct {
+	documents *storage.DocumentStore
+	logger    *logger.Logger
+}
+
+// NewStore creates a cluster metadata store
+func NewStore(documents *storage.DocumentStore, log *logger.Logger) *Store {
+	return &Store{
+		documents: documents,
+		logger:    log,
+	}
+}
+
+// metadataID keys the metadata of a context within a config
+func metadataID(configID, context string) string {
+	return configID + "/" + context
+}
+
+// List returns all saved metadata keyed by config ID and context
+func (s *Store) List() (map[string]*Metadata, error) {
+	docs, err := s.documents.List(metadataCollection)
+	if err != nil {
+		return nil, err
+	}
+	all := make(map[string]*Metadata, len(docs))
+	for id, data := range docs {
+		var m Metadata
+		if err := json.Unmarshal(data, &m); err != nil {
+			s.logger.WithError(err).WithField("metadata", id).Error("Skipping unreadable cluster metadata")
+			continue
+		}
+		all[metadataID(m.ConfigID, m.Context)] = &m
+	}
+	return all, nil
+}
+
+// Lookup returns the metadata of a context from the result of List, or nil
+func Lookup(all map[string]*Metadata, configID, context string) *Metadata {
+	return all[metadataID(configID, context)]
+}
+
+// Save validates and persists the metadata of a context
+func (s *Store) Save(m *Metadata) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+	m.UpdatedAt = time.Now()
+	return s.documents.Put(metadataCollection, metadataID(m.ConfigID, m.Context), m)
+}
+
+// Delete removes the metadata of a context
+func (s *Store) Delete(configID, context string) error {
+	return s.documents.Delete(metadataCollection, metadataID(configID, context))
+}
+
+// DeleteConfig removes the metadata of every context of a config
+func (s *Store) DeleteConfig(configID string) error {
+	all, err := s.List()
+	if err != nil {
+		return err
+	}
+	for _, m := range all {
+		if m.ConfigID == configID {
+			if err := s.Delete(m.ConfigID, m.Context); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Cleanup removes the metadata of the contexts of a removed kubeconfig or cluster
+func (s *Store) Cleanup(removal storage.Removal) (int, error) {
+	all, err := s.List()
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, m := range all {
+		if removal.Matches(m.ConfigID, m.Context) {
+			if err := s.Delete(m.ConfigID, m.Context); err != nil {
+				return removed, err
+			}
+			removed++
+		}
+	}
+	return removed, nil
+}
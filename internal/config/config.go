@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 )
 
 // Config holds all configuration for the application
@@ -13,6 +14,26 @@ type Config struct {
 	StaticFiles StaticFilesConfig
 	Tracing     TracingConfig
 	Database    DatabaseConfig
+	Security    SecurityConfig
+	Loki        LokiConfig
+	Cost        CostConfig
+	SMTP        SMTPConfig
+	Exec        ExecConfig
+	Rollouts    RolloutsConfig
+	Lint        LintConfig
+	PodCleanup  PodCleanupConfig
+	Events      EventHistoryConfig
+	Crashes     CrashReportsConfig
+	Storms      RestartStormsConfig
+	Elevation   ElevationConfig
+	SourceLinks SourceLinksConfig
+	Streams     StreamsConfig
+	Objects     ObjectStorageConfig
+	Actions     CustomActionsConfig
+	NodeLogs    NodeLogsConfig
+	Scaling     ScaleSchedulesConfig
+	ServiceProxy ServiceProxyConfig
+	WarmCache   WarmCacheConfig
 }
 
 // ServerConfig holds server-specific configuration
@@ -22,6 +43,9 @@ type ServerConfig struct {
 	ReadTimeout  int // in seconds
 	WriteTimeout int // in seconds
 	IdleTimeout  int // in seconds
+	// LegacyTimestamps keeps the timestamp formats of earlier releases (mixed layouts and zones,
+	// no epoch millisecond fields) for clients that still parse them
+	LegacyTimestamps bool
 }
 
 // LoggingConfig holds logging-specific configuration
@@ -31,7 +55,13 @@ type LoggingConfig struct {
 
 // K8sConfig holds Kubernetes-specific configuration
 type K8sConfig struct {
-	DefaultNamespace string
+	DefaultNamespace               string
+	SessionKubeconfigTTLMinutes    int      // Default lifetime of session-only kubeconfigs
+	SessionKubeconfigMaxTTLMinutes int      // Upper bound a caller may request for a session-only kubeconfig
+	APIContentType                 string   // "protobuf" to talk protobuf for built-in types, "json" to fall back to JSON everywhere
+	JSONClusters                   []string // Clusters always talked to with JSON, for API servers or proxies that mishandle protobuf
+	DisableCompression             bool     // Turns off gzip compression of API responses
+	DiscoveryCacheTTLSeconds       int      // How long a cluster's cached API discovery and REST mappings are reused
 }
 
 // StaticFilesConfig holds static files configuration
@@ -58,21 +88,177 @@ type DatabaseConfig struct {
 	Path string // SQLite database file path
 }
 
+// SecurityConfig holds configuration for security scanning integrations
+type SecurityConfig struct {
+	TrivyPath           string // Path to the trivy binary used for on-demand scans
+	TrivyServerURL      string // Optional trivy server to run scans in client/server mode
+	ScanTimeoutSeconds  int
+	ScanCacheTTLMinutes int
+}
+
+// LokiConfig holds configuration for the Loki log backend
+type LokiConfig struct {
+	URL      string // External Loki URL; when empty Loki is discovered in-cluster
+	TenantID string // Sent as X-Scope-OrgID for multi-tenant Loki
+	Username string
+	Password string
+	MaxLines int // Upper bound on lines returned by a single query
+}
+
+// CostConfig holds configuration for cost reporting
+type CostConfig struct {
+	OpenCostURL      string  // External OpenCost/Kubecost URL; when empty it is discovered in-cluster
+	CPUCoreHourly    float64 // Price per CPU core hour used by the request-based estimate
+	MemoryGBHourly   float64 // Price per GiB of memory per hour used by the request-based estimate
+	StorageGBMonthly float64 // Price per GiB of persistent volume per month used by the request-based estimate
+}
+
+// SMTPConfig holds configuration for outgoing notification email
+type SMTPConfig struct {
+	Host     string // SMTP server host; email channels are disabled when empty
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// RolloutsConfig holds configuration for rollout history tracking
+type RolloutsConfig struct {
+	TrackingIntervalSeconds int // How often tracked clusters are polled for rollouts; 0 disables background tracking
+	HistoryDays             int // How long rollout records are kept
+}
+
+// WarmCacheConfig holds configuration for prefetching cluster summaries when kubeconfigs are registered
+type WarmCacheConfig struct {
+	Enabled                bool // Prefetch namespaces, nodes and workload counts of every registered cluster
+	StaleAfterSeconds      int  // Summaries older than this are marked stale and refetched when read
+	RefreshIntervalSeconds int  // How often all summaries are refetched in the background; 0 to refetch only when read stale
+}
+
+// ExecConfig holds defaults for the pod exec policy and terminal sessions
+type ExecConfig struct {
+	DenyNamespaces             []string // Namespace patterns where exec is denied unless a policy rule allows it
+	ScrollbackBytes            int      // Output kept per terminal session that opts into scrollback; 0 disables it
+	ScrollbackRetentionMinutes int      // How long scrollback stays available after a session ends
+}
+
+// LintConfig holds the server policy for best-practice checks on applied manifests
+type LintConfig struct {
+	Mode          string   // "warn" returns findings, "strict" blocks applies with findings, "off" disables linting
+	DisabledRules []string // Rule IDs that are never reported
+}
+
+// PodCleanupConfig holds configuration for the evicted and failed pod cleanup job
+type PodCleanupConfig struct {
+	IntervalSeconds    int // How often clusters with an enabled policy are cleaned; 0 disables scheduled cleanup
+	DefaultMaxAgeHours int // Age after which failed pods are deleted when a policy does not set one
+	RunRetention       int // How many cleanup reports are kept per cluster
+}
+
+// EventHistoryConfig holds configuration for the event history recorder
+type EventHistoryConfig struct {
+	RetentionHours  int // How long recorded events are kept after they were last seen
+	MaxMemoryEvents int // Upper bound on events kept when no database is configured
+}
+
+// CrashReportsConfig holds configuration for crash-loop report capture
+type CrashReportsConfig struct {
+	LogLines      int // Lines of the crashed instance's logs kept in a report; 0 skips logs
+	RetentionDays int // How long crash reports are kept
+}
+
+// RestartStormsConfig holds configuration for restart storm detection
+type RestartStormsConfig struct {
+	IntervalSeconds          int // How often tracked clusters are analyzed in the background; 0 analyzes only when queried
+	WindowMinutes            int // Restarts within this window are grouped into a storm
+	MinContainers            int // Distinct containers of a namespace or node that must restart within the window
+	ResolvedRetentionMinutes int // How long resolved incidents are still reported
+}
+
+// ElevationConfig holds configuration for time-bounded elevated access grants
+type ElevationConfig struct {
+	DefaultDurationMinutes int // Grant length when the approver does not choose one
+	MaxDurationMinutes     int // Longest grant an approver can give
+}
+
+// SourceLinksConfig holds configuration for resolving the Git source of resources to browse URLs
+type SourceLinksConfig struct {
+	RepoMappings    []string // "<repo URL prefix>=<browse URL prefix>" rewrites for repos not browsable at their clone URL
+	GitLabHosts     []string // Self-hosted Git servers that use GitLab URL layout; gitlab.com always does
+	DefaultRef      string   // Ref linked to when a source does not record a revision
+	ArgoCDNamespace string   // Namespace of Argo CD Applications whose tracking IDs carry no namespace
+}
+
+// StreamsConfig holds limits for long-lived SSE and WebSocket streams
+type StreamsConfig struct {
+	MaxPerCluster             int // Concurrent streams per cluster; 0 for no limit
+	MaxTotal                  int // Concurrent streams across all clusters; 0 for no limit
+	IdleTimeoutSeconds        int // Streams that send and receive nothing for this long are closed; 0 to keep them open
+	ShutdownRetryAfterSeconds int // Reconnect delay suggested to clients when a stream is refused or the server stops
+	MaxSubscriptionsPerSocket int // Subscriptions one multiplexing WebSocket may hold; 0 for no limit
+}
+
+// ObjectStorageConfig holds configuration for storing large artifacts such as generated reports
+type ObjectStorageConfig struct {
+	Backend           string   // "local" or "s3"
+	LocalPath         string   // Directory objects are written to by the local backend
+	S3Endpoint        string   // S3-compatible endpoint; empty for AWS S3 in S3Region
+	S3Region          string
+	S3Bucket          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	S3PathStyle       bool     // Address the bucket in the path, as MinIO and most S3-compatible stores expect
+	Retention         []string // "<key prefix>=<days>" lifecycle rules, e.g. reports=90
+}
+
+// CustomActionsConfig holds configuration for operator-defined actions on resources
+type CustomActionsConfig struct {
+	File               string // YAML or JSON file defining the actions; empty for none
+	HTTPTimeoutSeconds int    // Timeout of the HTTP calls made by actions
+}
+
+// NodeLogsConfig holds configuration for reading kubelet and journal logs through the nodes proxy
+type NodeLogsConfig struct {
+	Enabled  bool // Node log access is off unless enabled, as it exposes host logs to anyone allowed nodes/proxy
+	MaxLines int  // Upper bound on lines returned by a single request
+}
+
+// ScaleSchedulesConfig holds configuration for time-based deployment scaling
+type ScaleSchedulesConfig struct {
+	IntervalSeconds int // How often schedules are evaluated; 0 disables scheduled scaling
+	RunRetention    int // How many runs are kept per schedule
+}
+
+// ServiceProxyConfig holds configuration for proxying HTTP requests to in-cluster services
+type ServiceProxyConfig struct {
+	Enabled         bool     // The service proxy is off unless enabled
+	AllowedServices []string // namespace/service:port entries that may be proxied; * matches any service or port
+	MaxBodyBytes    int      // Upper bound on proxied request bodies
+	TimeoutSeconds  int      // Timeout of a proxied request
+}
+
 // Load loads configuration from environment variables
 func Load() *Config {
 	return &Config{
 		Server: ServerConfig{
-			Port:         getEnv("PORT", "7080"),
-			Host:         getEnv("HOST", "0.0.0.0"),
-			ReadTimeout:  getEnvAsInt("SERVER_READ_TIMEOUT", 60),
-			WriteTimeout: getEnvAsInt("SERVER_WRITE_TIMEOUT", 3600),
-			IdleTimeout:  getEnvAsInt("SERVER_IDLE_TIMEOUT", 120),
+			Port:             getEnv("PORT", "7080"),
+			Host:             getEnv("HOST", "0.0.0.0"),
+			ReadTimeout:      getEnvAsInt("SERVER_READ_TIMEOUT", 60),
+			WriteTimeout:     getEnvAsInt("SERVER_WRITE_TIMEOUT", 3600),
+			IdleTimeout:      getEnvAsInt("SERVER_IDLE_TIMEOUT", 120),
+			LegacyTimestamps: getEnvAsBool("API_LEGACY_TIMESTAMPS", false),
 		},
 		Logging: LoggingConfig{
 			Level: getEnv("LOG_LEVEL", "info"),
 		},
 		K8s: K8sConfig{
-			DefaultNamespace: getEnv("K8S_DEFAULT_NAMESPACE", "default"),
+			DefaultNamespace:               getEnv("K8S_DEFAULT_NAMESPACE", "default"),
+			SessionKubeconfigTTLMinutes:    getEnvAsInt("SESSION_KUBECONFIG_TTL_MINUTES", 480),
+			SessionKubeconfigMaxTTLMinutes: getEnvAsInt("SESSION_KUBECONFIG_MAX_TTL_MINUTES", 1440),
+			APIContentType:                 getEnv("K8S_API_CONTENT_TYPE", "protobuf"),
+			JSONClusters:                   getEnvAsList("K8S_JSON_CLUSTERS", nil),
+			DisableCompression:             getEnvAsBool("K8S_DISABLE_COMPRESSION", false),
+			DiscoveryCacheTTLSeconds:       getEnvAsInt("K8S_DISCOVERY_CACHE_TTL_SECONDS", 300),
 		},
 		StaticFiles: StaticFilesConfig{
 			Path: getEnv("STATIC_FILES_PATH", "client/dist"),
@@ -92,6 +278,115 @@ func Load() *Config {
 			URL:  getEnv("DATABASE_URL", ""),
 			Path: getEnv("DATABASE_PATH", "./kube-dash.db"),
 		},
+		Security: SecurityConfig{
+			TrivyPath:           getEnv("TRIVY_PATH", "trivy"),
+			TrivyServerURL:      getEnv("TRIVY_SERVER_URL", ""),
+			ScanTimeoutSeconds:  getEnvAsInt("TRIVY_SCAN_TIMEOUT", 300),
+			ScanCacheTTLMinutes: getEnvAsInt("TRIVY_SCAN_CACHE_TTL", 360),
+		},
+		Loki: LokiConfig{
+			URL:      getEnv("LOKI_URL", ""),
+			TenantID: getEnv("LOKI_TENANT_ID", ""),
+			Username: getEnv("LOKI_USERNAME", ""),
+			Password: getEnv("LOKI_PASSWORD", ""),
+			MaxLines: getEnvAsInt("LOKI_MAX_LINES", 5000),
+		},
+		Cost: CostConfig{
+			OpenCostURL:      getEnv("OPENCOST_URL", ""),
+			CPUCoreHourly:    getEnvAsFloat("COST_CPU_CORE_HOURLY", 0.031611),
+			MemoryGBHourly:   getEnvAsFloat("COST_MEMORY_GB_HOURLY", 0.004237),
+			StorageGBMonthly: getEnvAsFloat("COST_STORAGE_GB_MONTHLY", 0.04),
+		},
+		SMTP: SMTPConfig{
+			Host:     getEnv("SMTP_HOST", ""),
+			Port:     getEnvAsInt("SMTP_PORT", 587),
+			Username: getEnv("SMTP_USERNAME", ""),
+			Password: getEnv("SMTP_PASSWORD", ""),
+			From:     getEnv("SMTP_FROM", ""),
+		},
+		Exec: ExecConfig{
+			DenyNamespaces:             getEnvAsList("EXEC_DENY_NAMESPACES", nil),
+			ScrollbackBytes:            getEnvAsInt("TERMINAL_SCROLLBACK_BYTES", 1048576),
+			ScrollbackRetentionMinutes: getEnvAsInt("TERMINAL_SCROLLBACK_RETENTION_MINUTES", 30),
+		},
+		WarmCache: WarmCacheConfig{
+			Enabled:                getEnvAsBool("WARM_CACHE_ENABLED", false),
+			StaleAfterSeconds:      getEnvAsInt("WARM_CACHE_STALE_AFTER_SECONDS", 60),
+			RefreshIntervalSeconds: getEnvAsInt("WARM_CACHE_REFRESH_INTERVAL_SECONDS", 300),
+		},
+		Rollouts: RolloutsConfig{
+			TrackingIntervalSeconds: getEnvAsInt("ROLLOUT_TRACKING_INTERVAL_SECONDS", 120),
+			HistoryDays:             getEnvAsInt("ROLLOUT_HISTORY_DAYS", 90),
+		},
+		Lint: LintConfig{
+			Mode:          getEnv("APPLY_LINT_MODE", "warn"),
+			DisabledRules: getEnvAsList("APPLY_LINT_DISABLED_RULES", nil),
+		},
+		PodCleanup: PodCleanupConfig{
+			IntervalSeconds:    getEnvAsInt("POD_CLEANUP_INTERVAL_SECONDS", 3600),
+			DefaultMaxAgeHours: getEnvAsInt("POD_CLEANUP_MAX_AGE_HOURS", 24),
+			RunRetention:       getEnvAsInt("POD_CLEANUP_RUN_RETENTION", 50),
+		},
+		Events: EventHistoryConfig{
+			RetentionHours:  getEnvAsInt("EVENT_HISTORY_RETENTION_HOURS", 168),
+			MaxMemoryEvents: getEnvAsInt("EVENT_HISTORY_MAX_MEMORY_EVENTS", 10000),
+		},
+		Crashes: CrashReportsConfig{
+			LogLines:      getEnvAsInt("CRASH_REPORT_LOG_LINES", 200),
+			RetentionDays: getEnvAsInt("CRASH_REPORT_RETENTION_DAYS", 30),
+		},
+		Storms: RestartStormsConfig{
+			IntervalSeconds:          getEnvAsInt("RESTART_STORM_INTERVAL_SECONDS", 30),
+			WindowMinutes:            getEnvAsInt("RESTART_STORM_WINDOW_MINUTES", 10),
+			MinContainers:            getEnvAsInt("RESTART_STORM_MIN_CONTAINERS", 5),
+			ResolvedRetentionMinutes: getEnvAsInt("RESTART_STORM_RESOLVED_RETENTION_MINUTES", 60),
+		},
+		Elevation: ElevationConfig{
+			DefaultDurationMinutes: getEnvAsInt("ELEVATION_DEFAULT_DURATION_MINUTES", 60),
+			MaxDurationMinutes:     getEnvAsInt("ELEVATION_MAX_DURATION_MINUTES", 480),
+		},
+		SourceLinks: SourceLinksConfig{
+			RepoMappings:    getEnvAsList("SOURCE_REPO_MAPPINGS", nil),
+			GitLabHosts:     getEnvAsList("SOURCE_GITLAB_HOSTS", nil),
+			DefaultRef:      getEnv("SOURCE_DEFAULT_REF", "HEAD"),
+			ArgoCDNamespace: getEnv("ARGOCD_NAMESPACE", "argocd"),
+		},
+		Objects: ObjectStorageConfig{
+			Backend:           getEnv("OBJECT_STORAGE_BACKEND", "local"),
+			LocalPath:         getEnv("OBJECT_STORAGE_PATH", "./kube-dash-objects"),
+			S3Endpoint:        getEnv("S3_ENDPOINT", ""),
+			S3Region:          getEnv("S3_REGION", "us-east-1"),
+			S3Bucket:          getEnv("S3_BUCKET", ""),
+			S3AccessKeyID:     getEnv("S3_ACCESS_KEY_ID", os.Getenv("AWS_ACCESS_KEY_ID")),
+			S3SecretAccessKey: getEnv("S3_SECRET_ACCESS_KEY", os.Getenv("AWS_SECRET_ACCESS_KEY")),
+			S3PathStyle:       getEnvAsBool("S3_FORCE_PATH_STYLE", false),
+			Retention:         getEnvAsList("OBJECT_STORAGE_RETENTION", []string{"reports=365"}),
+		},
+		Streams: StreamsConfig{
+			MaxPerCluster:             getEnvAsInt("STREAM_MAX_PER_CLUSTER", 200),
+			MaxTotal:                  getEnvAsInt("STREAM_MAX_TOTAL", 1000),
+			IdleTimeoutSeconds:        getEnvAsInt("STREAM_IDLE_TIMEOUT_SECONDS", 1800),
+			ShutdownRetryAfterSeconds: getEnvAsInt("STREAM_SHUTDOWN_RETRY_AFTER_SECONDS", 5),
+			MaxSubscriptionsPerSocket: getEnvAsInt("STREAM_MAX_SUBSCRIPTIONS_PER_SOCKET", 100),
+		},
+		Actions: CustomActionsConfig{
+			File:               getEnv("CUSTOM_ACTIONS_FILE", ""),
+			HTTPTimeoutSeconds: getEnvAsInt("CUSTOM_ACTIONS_HTTP_TIMEOUT_SECONDS", 15),
+		},
+		NodeLogs: NodeLogsConfig{
+			Enabled:  getEnvAsBool("ENABLE_NODE_LOGS", false),
+			MaxLines: getEnvAsInt("NODE_LOGS_MAX_LINES", 5000),
+		},
+		Scaling: ScaleSchedulesConfig{
+			IntervalSeconds: getEnvAsInt("SCALE_SCHEDULE_INTERVAL_SECONDS", 60),
+			RunRetention:    getEnvAsInt("SCALE_SCHEDULE_RUN_RETENTION", 100),
+		},
+		ServiceProxy: ServiceProxyConfig{
+			Enabled:         getEnvAsBool("ENABLE_SERVICE_PROXY", false),
+			AllowedServices: getEnvAsList("SERVICE_PROXY_ALLOWED_SERVICES", nil),
+			MaxBodyBytes:    getEnvAsInt("SERVICE_PROXY_MAX_BODY_BYTES", 10<<20),
+			TimeoutSeconds:  getEnvAsInt("SERVICE_PROXY_TIMEOUT_SECONDS", 30),
+		},
 	}
 }
 
@@ -132,3 +427,18 @@ func getEnvAsFloat(key string, defaultValue float64) float64 {
 	}
 	return defaultValue
 }
+
+// getEnvAsList gets a comma-separated environment variable as a list or returns a default value
+func getEnvAsList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,124 @@
+package crashreports
+
+import (
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// crashLoopReason is the waiting reason the kubelet sets while backing off a crashing container
+const crashLoopReason = "CrashLoopBackOff"
+
+// Report is the state of a container captured the first time it entered CrashLoopBackOff
+type Report struct {
+	ID           string     `json:"id"`
+	ConfigID     string     `json:"configId"`
+	Cluster      string     `json:"cluster,omitempty"`
+	Namespace    string     `json:"namespace"`
+	Pod          string     `json:"pod"`
+	PodUID       string     `json:"podUid"`
+	Container    string     `json:"container"`
+	Init         bool       `json:"init,omitempty"`
+	Image        string     `json:"image"`
+	Node         string     `json:"node,omitempty"`
+	CapturedAt   time.Time  `json:"capturedAt"`
+	RestartCount int32      `json:"restartCount"`
+	ExitCode     *int32     `json:"exitCode,omitempty"`
+	Signal       int32      `json:"signal,omitempty"`
+	Reason       string     `json:"reason,omitempty"`  // termination reason, e.g. Error or OOMKilled
+	Message      string     `json:"message,omitempty"` // termination message written by the container
+	StartedAt    *time.Time `json:"startedAt,omitempty"`
+	FinishedAt   *time.Time `json:"finishedAt,omitempty"`
+	Logs         []string   `json:"logs,omitempty"` // tail of the crashed instance's logs
+	LogsError    string     `json:"logsError,omitempty"`
+	Events       []Event    `json:"events,omitempty"`
+}
+
+// Event is a pod event captured alongside a crash report
+type Event struct {
+	Type     string    `json:"type"`
+	Reason   string    `json:"reason"`
+	Message  string    `json:"message"`
+	Count    int32     `json:"count"`
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+// reportID identifies the report of one container of one pod instance
+func reportID(configID, cluster, podUID, container string) string {
+	return configID + "|" + cluster + "|" + podUID + "|" + container
+}
+
+// CrashingContainer is a crash-looping container status and whether it is an init container
+type CrashingContainer struct {
+	Status v1.ContainerStatus
+	Init   bool
+}
+
+// CrashLoopingContainers returns the statuses of the pod's init and app containers that are in CrashLoopBackOff
+func CrashLoopingContainers(pod *v1.Pod) []CrashingContainer {
+	var crashing []CrashingContainer
+	add := func(statuses []v1.ContainerStatus, init bool) {
+		for _, status := range statuses {
+			if status.State.Waiting != nil && status.State.Waiting.Reason == crashLoopReason {
+				crashing = append(crashing, CrashingContainer{Status: status, Init: init})
+			}
+		}
+	}
+	add(pod.Status.InitContainerStatuses, true)
+	add(pod.Status.ContainerStatuses, false)
+	return crashing
+}
+
+// NewReport builds the report of a crash-looping container from the pod's status; logs and
+// events are attached by the caller
+func NewReport(configID, cluster string, pod *v1.Pod, container CrashingContainer, now time.Time) *Report {
+	status := container.Status
+	report := &Report{
+		ID:           reportID(configID, cluster, string(pod.UID), status.Name),
+		ConfigID:     configID,
+		Cluster:      cluster,
+		Namespace:    pod.Namespace,
+		Pod:          pod.Name,
+		PodUID:       string(pod.UID),
+		Container:    status.Name,
+		Init:         container.Init,
+		Image:        status.Image,
+		Node:         pod.Spec.NodeName,
+		CapturedAt:   now,
+		RestartCount: status.RestartCount,
+	}
+	if terminated := status.LastTerminationState.Terminated; terminated != nil {
+		exitCode := terminated.ExitCode
+		report.ExitCode = &exitCode
+		report.Signal = terminated.Signal
+		report.Reason = terminated.Reason
+		report.Message = terminated.Message
+		if !terminated.StartedAt.IsZero() {
+			startedAt := terminated.StartedAt.Time
+			report.StartedAt = &startedAt
+		}
+		if !terminated.FinishedAt.IsZero() {
+			finishedAt := terminated.FinishedAt.Time
+			report.FinishedAt = &finishedAt
+		}
+	}
+	return report
+}
+
+// reportEvents converts pod events into report events, most recent first
+func reportEvents(events []v1.Event) []Event {
+	result := make([]Event, 0, len(events))
+	for _, e := range events {
+		lastSeen := e.LastTimestamp.Time
+		if lastSeen.IsZero() {
+			lastSeen = e.EventTime.Time
+		}
+		if lastSeen.IsZero() {
+			lastSeen = e.CreationTimestamp.Time
+		}
+		result = append(result, Event{Type: e.Type, Reason: e.Reason, Message: e.Message, Count: e.Count, LastSeen: lastSeen})
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].LastSeen.After(result[j].LastSeen) })
+	return result
+}
@@ -0,0 +1,67 @@
+package crashreports
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCrashLoopingContainersAndNewReport(t *testing.T) {
+	finished := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	crashLoop := v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-0", Namespace: "shop", UID: "uid-1"},
+		Spec:       v1.PodSpec{NodeName: "node-a"},
+		Status: v1.PodStatus{
+			InitContainerStatuses: []v1.ContainerStatus{
+				{Name: "migrate", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 0}}},
+			},
+			ContainerStatuses: []v1.ContainerStatus{
+				{Name: "sidecar", State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}},
+				{
+					Name:         "api",
+					Image:        "shop/api:1.2",
+					RestartCount: 3,
+					State:        crashLoop,
+					LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{
+						ExitCode:   137,
+						Reason:     "OOMKilled",
+						Message:    "out of memory",
+						FinishedAt: metav1.NewTime(finished),
+					}},
+				},
+			},
+		},
+	}
+
+	crashing := CrashLoopingContainers(pod)
+	if len(crashing) != 1 || crashing[0].Status.Name != "api" || crashing[0].Init {
+		t.Fatalf("CrashLoopingContainers() = %+v, want only api", crashing)
+	}
+
+	now := finished.Add(time.Minute)
+	report := NewReport("cfg", "prod", pod, crashing[0], now)
+	if report.ID != "cfg|prod|uid-1|api" || report.Node != "node-a" || report.Image != "shop/api:1.2" || report.RestartCount != 3 {
+		t.Errorf("unexpected report identity: %+v", report)
+	}
+	if report.ExitCode == nil || *report.ExitCode != 137 || report.Reason != "OOMKilled" || report.Message != "out of memory" {
+		t.Errorf("unexpected termination details: %+v", report)
+	}
+	if report.FinishedAt == nil || !report.FinishedAt.Equal(finished) || report.StartedAt != nil {
+		t.Errorf("startedAt/finishedAt = %v/%v", report.StartedAt, report.FinishedAt)
+	}
+}
+
+func TestReportEventsNewestFirst(t *testing.T) {
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	events := reportEvents([]v1.Event{
+		{Reason: "Pulled", LastTimestamp: metav1.NewTime(base)},
+		{Reason: "BackOff", LastTimestamp: metav1.NewTime(base.Add(2 * time.Minute))},
+		{Reason: "Started", EventTime: metav1.NewMicroTime(base.Add(time.Minute))},
+	})
+	if len(events) != 3 || events[0].Reason != "BackOff" || events[1].Reason != "Started" || events[2].Reason != "Pulled" {
+		t.Errorf("reportEvents() = %+v", events)
+	}
+}
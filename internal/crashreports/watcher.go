@@ -0,0 +1,436 @@
+package crashreports
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/config"
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Document collections used by the watcher
+const (
+	reportsCollection  = "crash_reports"
+	clustersCollection = "crash_report_clusters"
+)
+
+const (
+	watchRetryWait = 10 * time.Second
+	pruneInterval  = time.Hour
+	captureTimeout = 30 * time.Second
+	maxLogBytes    = 256 * 1024
+	// maxConcurrentCaptures bounds the log and event fetches running at once per cluster
+	maxConcurrentCaptures = 4
+)
+
+// WatchedCluster is a cluster whose pods are watched for crash loops
+type WatchedCluster struct {
+	ConfigID      string     `json:"configId"`
+	Cluster       string     `json:"cluster,omitempty"`
+	EnabledAt     time.Time  `json:"enabledAt"`
+	LastCaptureAt *time.Time `json:"lastCaptureAt,omitempty"`
+	LastError     string     `json:"lastError,omitempty"`
+}
+
+// Watcher captures a crash report the first time a container of a watched cluster enters
+// CrashLoopBackOff, while the crashed instance's logs and the pod's events still exist
+type Watcher struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	documents     *storage.DocumentStore
+	logger        *logger.Logger
+	config        *config.CrashReportsConfig
+
+	mu       sync.RWMutex
+	clusters map[string]*WatchedCluster
+	watchers map[string]context.CancelFunc
+	captured map[string]bool // report IDs already captured or being captured
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewWatcher creates a crash report watcher; call Start to begin watching the enabled clusters
+func NewWatcher(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, documents *storage.DocumentStore, log *logger.Logger, cfg *config.CrashReportsConfig) *Watcher {
+	w := &Watcher{
+		store:         store,
+		clientFactory: clientFactory,
+		documents:     documents,
+		logger:        log,
+		config:        cfg,
+		clusters:      make(map[string]*WatchedCluster),
+		watchers:      make(map[string]context.CancelFunc),
+		captured:      make(map[string]bool),
+	}
+	if err := w.reload(); err != nil {
+		log.WithError(err).Error("Failed to load crash report state")
+	}
+	return w
+}
+
+func (w *Watcher) reload() error {
+	clusters, err := w.documents.List(clustersCollection)
+	if err != nil {
+		return err
+	}
+	reports, err := w.documents.List(reportsCollection)
+	if err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for id, data := range clusters {
+		var c WatchedCluster
+		if err := json.Unmarshal(data, &c); err != nil {
+			w.logger.WithError(err).WithField("cluster", id).Error("Skipping unreadable crash report cluster")
+			continue
+		}
+		w.clusters[storage.ClusterKey(c.ConfigID, c.Cluster)] = &c
+	}
+	for id := range reports {
+		w.captured[id] = true
+	}
+	return nil
+}
+
+// Start begins watching the enabled clusters and pruning expired reports
+func (w *Watcher) Start() {
+	w.ctx, w.cancel = context.WithCancel(context.Background())
+	w.mu.Lock()
+	for key, c := range w.clusters {
+		w.startWatcher(key, c.ConfigID, c.Cluster)
+	}
+	w.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(pruneInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.ctx.Done():
+				return
+			case <-ticker.C:
+				w.prune()
+			}
+		}
+	}()
+}
+
+// Stop cancels all watchers and the prune loop
+func (w *Watcher) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for key, cancel := range w.watchers {
+		cancel()
+		delete(w.watchers, key)
+	}
+}
+
+// startWatcher starts the pod watcher of a cluster; the caller holds w.mu
+func (w *Watcher) startWatcher(key, configID, cluster string) {
+	if w.ctx == nil {
+		return
+	}
+	if _, ok := w.watchers[key]; ok {
+		return
+	}
+	ctx, cancel := context.WithCancel(w.ctx)
+	w.watchers[key] = cancel
+	go w.watchPods(ctx, configID, cluster)
+}
+
+// Enable starts watching a cluster for crash loops
+func (w *Watcher) Enable(configID, cluster string) (*WatchedCluster, error) {
+	key := storage.ClusterKey(configID, cluster)
+	w.mu.Lock()
+	c, ok := w.clusters[key]
+	if !ok {
+		c = &WatchedCluster{ConfigID: configID, Cluster: cluster, EnabledAt: time.Now()}
+		w.clusters[key] = c
+	}
+	snapshot := *c
+	w.startWatcher(key, configID, cluster)
+	w.mu.Unlock()
+
+	if !ok {
+		if err := w.documents.Put(clustersCollection, key, &snapshot); err != nil {
+			return nil, fmt.Errorf("failed to persist crash report cluster: %w", err)
+		}
+	}
+	return &snapshot, nil
+}
+
+// Disable stops watching a cluster; captured reports are kept until they expire
+func (w *Watcher) Disable(configID, cluster string) error {
+	key := storage.ClusterKey(configID, cluster)
+	w.mu.Lock()
+	delete(w.clusters, key)
+	if cancel, ok := w.watchers[key]; ok {
+		cancel()
+		delete(w.watchers, key)
+	}
+	w.mu.Unlock()
+	if err := w.documents.Delete(clustersCollection, key); err != nil {
+		return fmt.Errorf("failed to delete crash report cluster: %w", err)
+	}
+	return nil
+}
+
+// Clusters returns the clusters watched for crash loops
+func (w *Watcher) Clusters() []WatchedCluster {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	result := make([]WatchedCluster, 0, len(w.clusters))
+	for _, c := range w.clusters {
+		result = append(result, *c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return storage.ClusterKey(result[i].ConfigID, result[i].Cluster) < storage.ClusterKey(result[j].ConfigID, result[j].Cluster)
+	})
+	return result
+}
+
+// Query selects captured crash reports; empty fields match everything except the cluster
+type Query struct {
+	ConfigID  string
+	Cluster   string
+	Namespace string
+	Pod       string
+}
+
+// List returns the reports matching the query, most recently captured first
+func (w *Watcher) List(q Query) ([]Report, error) {
+	docs, err := w.documents.List(reportsCollection)
+	if err != nil {
+		return nil, err
+	}
+	reports := []Report{}
+	for _, data := range docs {
+		var r Report
+		if err := json.Unmarshal(data, &r); err != nil {
+			continue
+		}
+		if r.ConfigID != q.ConfigID || r.Cluster != q.Cluster ||
+			(q.Namespace != "" && r.Namespace != q.Namespace) || (q.Pod != "" && r.Pod != q.Pod) {
+			continue
+		}
+		reports = append(reports, r)
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].CapturedAt.After(reports[j].CapturedAt) })
+	return reports, nil
+}
+
+// prune deletes reports captured before the retention window
+func (w *Watcher) prune() {
+	if w.config.RetentionDays <= 0 {
+		return
+	}
+	cutoff := time.Now().AddDate(0, 0, -w.config.RetentionDays)
+	docs, err := w.documents.List(reportsCollection)
+	if err != nil {
+		w.logger.WithError(err).Warn("Failed to list crash reports for pruning")
+		return
+	}
+	for id, data := range docs {
+		var r Report
+		if err := json.Unmarshal(data, &r); err != nil || !r.CapturedAt.Before(cutoff) {
+			continue
+		}
+		if err := w.documents.Delete(reportsCollection, id); err != nil {
+			w.logger.WithError(err).WithField("report", id).Warn("Failed to delete expired crash report")
+			continue
+		}
+		w.mu.Lock()
+		delete(w.captured, id)
+		w.mu.Unlock()
+	}
+}
+
+func (w *Watcher) getClient(configID, cluster string) (kubernetes.Interface, error) {
+	cfg, err := w.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, err
+	}
+	return w.clientFactory.GetClientForConfig(cfg, cluster)
+}
+
+// watchPods watches the pods of a cluster until ctx is cancelled, re-establishing the watch on failure
+func (w *Watcher) watchPods(ctx context.Context, configID, cluster string) {
+	log := w.logger.WithField("config", configID).WithField("cluster", cluster)
+	captures := make(chan struct{}, maxConcurrentCaptures)
+	for {
+		if _, err := w.store.GetKubeConfig(configID); err != nil {
+			// The kubeconfig was removed; stop watching but keep the reports
+			log.Info("Kubeconfig removed, stopping crash report capture")
+			if err := w.Disable(configID, cluster); err != nil {
+				log.WithError(err).Warn("Failed to disable crash report capture")
+			}
+			return
+		}
+		if err := w.watchPodsOnce(ctx, configID, cluster, captures); err != nil && ctx.Err() == nil {
+			log.WithError(err).Warn("Crash report pod watch failed, retrying")
+			w.setClusterState(configID, cluster, err, false)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(watchRetryWait):
+		}
+	}
+}
+
+func (w *Watcher) watchPodsOnce(ctx context.Context, configID, cluster string, captures chan struct{}) error {
+	client, err := w.getClient(configID, cluster)
+	if err != nil {
+		return err
+	}
+	// Pods already crash looping when the watch starts are captured too; their previous logs may still exist
+	list, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range list.Items {
+		w.inspect(ctx, client, configID, cluster, &list.Items[i], captures)
+	}
+
+	watcher, err := client.CoreV1().Pods("").Watch(ctx, metav1.ListOptions{ResourceVersion: list.ResourceVersion})
+	if err != nil {
+		return err
+	}
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-watcher.ResultChan():
+			if !ok {
+				return fmt.Errorf("watch closed")
+			}
+			if ev.Type == watch.Error {
+				return fmt.Errorf("watch error: %v", ev.Object)
+			}
+			if ev.Type != watch.Added && ev.Type != watch.Modified {
+				continue
+			}
+			if pod, ok := ev.Object.(*v1.Pod); ok {
+				w.inspect(ctx, client, configID, cluster, pod, captures)
+			}
+		}
+	}
+}
+
+// inspect starts a capture for each container of the pod entering CrashLoopBackOff for the first time
+func (w *Watcher) inspect(ctx context.Context, client kubernetes.Interface, configID, cluster string, pod *v1.Pod, captures chan struct{}) {
+	for _, container := range CrashLoopingContainers(pod) {
+		id := reportID(configID, cluster, string(pod.UID), container.Status.Name)
+		w.mu.Lock()
+		seen := w.captured[id]
+		w.captured[id] = true
+		w.mu.Unlock()
+		if seen {
+			continue
+		}
+
+		report := NewReport(configID, cluster, pod, container, time.Now())
+		go func() {
+			select {
+			case captures <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-captures }()
+			w.capture(ctx, client, report)
+		}()
+	}
+}
+
+// capture attaches the crashed instance's logs and the pod's events to a report and persists it
+func (w *Watcher) capture(ctx context.Context, client kubernetes.Interface, report *Report) {
+	ctx, cancel := context.WithTimeout(ctx, captureTimeout)
+	defer cancel()
+
+	lines := int64(w.config.LogLines)
+	if lines > 0 {
+		report.Logs, report.LogsError = previousLogs(ctx, client, report, lines)
+	}
+
+	events, err := client.CoreV1().Events(report.Namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("involvedObject.uid", report.PodUID).String(),
+	})
+	if err == nil {
+		report.Events = reportEvents(events.Items)
+	}
+
+	if err := w.documents.Put(reportsCollection, report.ID, report); err != nil {
+		w.logger.WithError(err).WithField("report", report.ID).Error("Failed to persist crash report")
+		// Allow a later pod update to retry the capture
+		w.mu.Lock()
+		delete(w.captured, report.ID)
+		w.mu.Unlock()
+		return
+	}
+	w.setClusterState(report.ConfigID, report.Cluster, nil, true)
+	w.logger.WithField("namespace", report.Namespace).
+		WithField("pod", report.Pod).
+		WithField("container", report.Container).
+		Info("Captured crash report")
+}
+
+// previousLogs reads the tail of the crashed container instance's logs
+func previousLogs(ctx context.Context, client kubernetes.Interface, report *Report, lines int64) ([]string, string) {
+	stream, err := client.CoreV1().Pods(report.Namespace).GetLogs(report.Pod, &v1.PodLogOptions{
+		Container: report.Container,
+		Previous:  true,
+		TailLines: &lines,
+	}).Stream(ctx)
+	if err != nil {
+		return nil, err.Error()
+	}
+	defer stream.Close()
+	data, err := io.ReadAll(io.LimitReader(stream, maxLogBytes))
+	if err != nil {
+		return nil, err.Error()
+	}
+	text := strings.TrimRight(string(data), "\n")
+	if text == "" {
+		return nil, ""
+	}
+	return strings.Split(text, "\n"), ""
+}
+
+// setClusterState records the outcome of watching a cluster
+func (w *Watcher) setClusterState(configID, cluster string, err error, captured bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	c, ok := w.clusters[storage.ClusterKey(configID, cluster)]
+	if !ok {
+		return
+	}
+	if err != nil {
+		c.LastError = err.Error()
+		return
+	}
+	c.LastError = ""
+	if captured {
+		now := time.Now()
+		c.LastCaptureAt = &now
+	}
+}
@@ -0,0 +1,115 @@
+package crdfavorites
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+)
+
+// favoritesCollection is the document collection holding favorite CustomResourceDefinitions
+const favoritesCollection = "crd_favorites"
+
+// Favorite is a CustomResourceDefinition a user marked on one cluster. Pinned favorites are
+// listed first in the custom resources navigation.
+type Favorite struct {
+	Owner     string    `json:"owner,omitempty"` // empty for callers that do not identify themselves
+	ConfigID  string    `json:"configId"`
+	Cluster   string    `json:"cluster,omitempty"`
+	Name      string    `json:"name"` // CRD name, e.g. certificates.cert-manager.io
+	Pinned    bool      `json:"pinned"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Validate checks that a favorite can be saved
+func (f *Favorite) Validate() error {
+	if f.ConfigID == "" {
+		return fmt.Errorf("configId is required")
+	}
+	f.Name = strings.TrimSpace(f.Name)
+	if f.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	// CRD names are <plural>.<group>, and a group always contains a dot
+	if !strings.Contains(f.Name, ".") {
+		return fmt.Errorf("invalid CustomResourceDefinition name %q", f.Name)
+	}
+	return nil
+}
+
+// favoriteID derives the document ID of a user's favorite, so a CRD is marked at most once
+// per user and cluster
+func favoriteID(owner, configID, cluster, name string) string {
+	sum := sha256.Sum256([]byte(owner + "\x00" + configID + "\x00" + cluster + "\x00" + name))
+	return hex.EncodeToString(sum[:16])
+}
+
+// Store persists favorite CustomResourceDefinitions
+type Store struct {
+	documents *storage.DocumentStore
+	logger    *logger.Logger
+}
+
+// NewStore creates a favorite CRD store
+func NewStore(documents *storage.DocumentStore, log *logger.Logger) *Store {
+	return &Store{
+		documents: documents,
+		logger:    log,
+	}
+}
+
+// List returns a user's favorites on a cluster, pinned ones first, then by name
+func (s *Store) List(owner, configID, cluster string) ([]Favorite, error) {
+	docs, err := s.documents.List(favoritesCollection)
+	if err != nil {
+		return nil, err
+	}
+	favorites := []Favorite{}
+	for id, data := range docs {
+		var f Favorite
+		if err := json.Unmarshal(data, &f); err != nil {
+			s.logger.WithError(err).WithField("favorite", id).Error("Skipping unreadable CRD favorite")
+			continue
+		}
+		if f.Owner != owner || f.ConfigID != configID || f.Cluster != cluster {
+			continue
+		}
+		favorites = append(favorites, f)
+	}
+	sort.Slice(favorites, func(i, j int) bool {
+		if favorites[i].Pinned != favorites[j].Pinned {
+			return favorites[i].Pinned
+		}
+		return favorites[i].Name < favorites[j].Name
+	})
+	return favorites, nil
+}
+
+// Set validates and stores a favorite, keeping the creation time of an existing one
+func (s *Store) Set(f *Favorite) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+	id := favoriteID(f.Owner, f.ConfigID, f.Cluster, f.Name)
+	now := time.Now()
+	var existing Favorite
+	if err := s.documents.Get(favoritesCollection, id, &existing); err == nil {
+		f.CreatedAt = existing.CreatedAt
+	} else {
+		f.CreatedAt = now
+	}
+	f.UpdatedAt = now
+	return s.documents.Put(favoritesCollection, id, f)
+}
+
+// Delete removes a user's favorite on a cluster
+func (s *Store) Delete(owner, configID, cluster, name string) error {
+	return s.documents.Delete(favoritesCollection, favoriteID(owner, configID, cluster, name))
+}
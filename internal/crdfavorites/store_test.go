@@ -0,0 +1,68 @@
+package crdfavorites
+
+import (
+	"testing"
+
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+)
+
+func TestFavoriteValidate(t *testing.T) {
+	f := Favorite{ConfigID: "cfg", Name: " widgets.example.com "}
+	if err := f.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if f.Name != "widgets.example.com" {
+		t.Errorf("name = %q, want widgets.example.com", f.Name)
+	}
+
+	invalid := []Favorite{
+		{Name: "widgets.example.com"},
+		{ConfigID: "cfg"},
+		{ConfigID: "cfg", Name: "widgets"},
+	}
+	for i, f := range invalid {
+		if err := f.Validate(); err == nil {
+			t.Errorf("case %d: expected a validation error", i)
+		}
+	}
+}
+
+func TestStoreListsPinnedFirst(t *testing.T) {
+	s := NewStore(storage.NewDocumentStore(nil), logger.New("error"))
+	for _, f := range []Favorite{
+		{Owner: "alice", ConfigID: "cfg", Name: "alpha.example.com"},
+		{Owner: "alice", ConfigID: "cfg", Name: "zeta.example.com", Pinned: true},
+		{Owner: "bob", ConfigID: "cfg", Name: "beta.example.com"},
+		{Owner: "alice", ConfigID: "cfg", Cluster: "staging", Name: "gamma.example.com"},
+	} {
+		f := f
+		if err := s.Set(&f); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	favorites, err := s.List("alice", "cfg", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(favorites) != 2 || favorites[0].Name != "zeta.example.com" || favorites[1].Name != "alpha.example.com" {
+		t.Fatalf("unexpected favorites: %+v", favorites)
+	}
+
+	created := favorites[1].CreatedAt
+	repinned := Favorite{Owner: "alice", ConfigID: "cfg", Name: "alpha.example.com", Pinned: true}
+	if err := s.Set(&repinned); err != nil {
+		t.Fatal(err)
+	}
+	if !repinned.CreatedAt.Equal(created) {
+		t.Errorf("updating a favorite changed its creation time")
+	}
+
+	if err := s.Delete("alice", "cfg", "", "zeta.example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if favorites, _ := s.List("alice", "cfg", ""); len(favorites) != 1 || favorites[0].Name != "alpha.example.com" {
+		t.Errorf("unexpected favorites after delete: %+v", favorites)
+	}
+}
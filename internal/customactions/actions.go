@@ -0,0 +1,260 @@
+package customactions
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/yaml"
+)
+
+// ActionsAnnotation lists, comma-separated, the IDs of opt-in actions a resource offers
+const ActionsAnnotation = "kube-dash.io/actions"
+
+// Action types
+const (
+	TypeHTTP  = "http"
+	TypePatch = "patch"
+)
+
+var actionIDPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// Action is an operator-defined operation on the resources its target selects, rendered as a
+// button by the UI. It either calls an HTTP endpoint or patches the resource's annotations and
+// labels; URLs, headers, bodies and patch values are Go templates executed with TemplateData.
+type Action struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Icon        string    `json:"icon,omitempty"`
+	Confirm     string    `json:"confirm,omitempty"` // prompt the UI shows before running the action
+	Target      Target    `json:"target"`
+	HTTP        *HTTPCall `json:"http,omitempty"`
+	Patch       *Patch    `json:"patch,omitempty"`
+
+	templates map[string]*template.Template
+}
+
+// Target selects the resources an action applies to
+type Target struct {
+	Group         string            `json:"group,omitempty"` // API group; empty for the core group
+	Kind          string            `json:"kind"`
+	Namespaces    []string          `json:"namespaces,omitempty"` // glob patterns; empty for all
+	LabelSelector string            `json:"labelSelector,omitempty"`
+	Annotations   map[string]string `json:"annotations,omitempty"` // required annotations; an empty value only requires the key
+	OptIn         bool              `json:"optIn,omitempty"`       // only resources listing the action in ActionsAnnotation
+
+	selector labels.Selector
+}
+
+// HTTPCall is an HTTP request made by an action
+type HTTPCall struct {
+	Method  string            `json:"method,omitempty"` // default POST
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+}
+
+// Patch is a merge patch of the resource's metadata made by an action
+type Patch struct {
+	Annotations map[string]*string `json:"annotations,omitempty"` // null removes the annotation
+	Labels      map[string]*string `json:"labels,omitempty"`      // null removes the label
+}
+
+// TemplateData is what action templates are executed with. Besides the standard functions,
+// templates can use env to read the server's environment, e.g. for tokens kept out of the
+// actions file, and json to quote a value for a JSON body.
+type TemplateData struct {
+	ConfigID    string
+	Cluster     string
+	Group       string
+	Version     string
+	Kind        string
+	Namespace   string
+	Name        string
+	UID         string
+	Labels      map[string]string
+	Annotations map[string]string
+	Actor       string // who runs the action: the API token's owner or name, or dashboard
+	Now         string // RFC 3339
+}
+
+var templateFuncs = template.FuncMap{
+	"env": os.Getenv,
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// actionsFile is the layout of the actions file
+type actionsFile struct {
+	Actions []Action `json:"actions"`
+}
+
+// Load reads and validates the actions defined in a YAML or JSON file
+func Load(filename string) ([]Action, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var file actionsFile
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid actions file %s: %w", filename, err)
+	}
+	ids := map[string]bool{}
+	for i := range file.Actions {
+		action := &file.Actions[i]
+		if err := action.compile(); err != nil {
+			return nil, fmt.Errorf("action %q: %w", action.ID, err)
+		}
+		if ids[action.ID] {
+			return nil, fmt.Errorf("duplicate action id %q", action.ID)
+		}
+		ids[action.ID] = true
+	}
+	return file.Actions, nil
+}
+
+// Type returns http or patch
+func (a *Action) Type() string {
+	if a.Patch != nil {
+		return TypePatch
+	}
+	return TypeHTTP
+}
+
+// compile validates an action and parses its selector and templates
+func (a *Action) compile() error {
+	if !actionIDPattern.MatchString(a.ID) {
+		return fmt.Errorf("id must be lowercase letters, digits and dashes")
+	}
+	if strings.TrimSpace(a.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if a.Target.Kind == "" {
+		return fmt.Errorf("target kind is required")
+	}
+	for _, pattern := range a.Target.Namespaces {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid namespace pattern %q", pattern)
+		}
+	}
+	selector, err := labels.Parse(a.Target.LabelSelector)
+	if err != nil {
+		return fmt.Errorf("invalid label selector: %w", err)
+	}
+	a.Target.selector = selector
+	if (a.HTTP == nil) == (a.Patch == nil) {
+		return fmt.Errorf("exactly one of http and patch is required")
+	}
+
+	sources := map[string]string{}
+	if a.HTTP != nil {
+		if a.HTTP.Method == "" {
+			a.HTTP.Method = http.MethodPost
+		}
+		a.HTTP.Method = strings.ToUpper(a.HTTP.Method)
+		switch a.HTTP.Method {
+		case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		default:
+			return fmt.Errorf("unsupported HTTP method %s", a.HTTP.Method)
+		}
+		if a.HTTP.URL == "" {
+			return fmt.Errorf("http url is required")
+		}
+		sources["url"] = a.HTTP.URL
+		sources["body"] = a.HTTP.Body
+		for name, value := range a.HTTP.Headers {
+			sources["header:"+name] = value
+		}
+	} else {
+		if len(a.Patch.Annotations) == 0 && len(a.Patch.Labels) == 0 {
+			return fmt.Errorf("patch must set or remove annotations or labels")
+		}
+		for key, value := range a.Patch.Annotations {
+			if value != nil {
+				sources["annotation:"+key] = *value
+			}
+		}
+		for key, value := range a.Patch.Labels {
+			if value != nil {
+				sources["label:"+key] = *value
+			}
+		}
+	}
+
+	a.templates = map[string]*template.Template{}
+	for name, source := range sources {
+		tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=zero").Parse(source)
+		if err != nil {
+			return fmt.Errorf("invalid %s template: %w", name, err)
+		}
+		a.templates[name] = tmpl
+	}
+	return nil
+}
+
+// render executes one of the action's templates
+func (a *Action) render(name string, data *TemplateData) (string, error) {
+	tmpl, ok := a.templates[name]
+	if !ok {
+		return "", nil
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", name, err)
+	}
+	return b.String(), nil
+}
+
+// TargetsKind reports whether the action's target is of the given group and kind
+func (a *Action) TargetsKind(group, kind string) bool {
+	return a.Target.Group == group && a.Target.Kind == kind
+}
+
+// Matches reports whether the action applies to a resource
+func (a *Action) Matches(obj *unstructured.Unstructured) bool {
+	gvk := obj.GroupVersionKind()
+	if !a.TargetsKind(gvk.Group, gvk.Kind) {
+		return false
+	}
+	if len(a.Target.Namespaces) > 0 {
+		matched := false
+		for _, pattern := range a.Target.Namespaces {
+			if ok, _ := path.Match(pattern, obj.GetNamespace()); ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if a.Target.selector != nil && !a.Target.selector.Matches(labels.Set(obj.GetLabels())) {
+		return false
+	}
+	annotations := obj.GetAnnotations()
+	for key, value := range a.Target.Annotations {
+		actual, ok := annotations[key]
+		if !ok || (value != "" && actual != value) {
+			return false
+		}
+	}
+	if a.Target.OptIn {
+		for _, id := range strings.Split(annotations[ActionsAnnotation], ",") {
+			if strings.TrimSpace(id) == a.ID {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,195 @@
+package customactions
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+const testActions = `
+actions:
+- id: flush-cache
+  name: Flush cache
+  target:
+    group: apps
+    kind: Deployment
+    namespaces: ["prod-*"]
+    labelSelector: app=redis
+  http:
+    url: "{{ index .Annotations \"cache.example.com/admin-url\" }}/flush?name={{ .Name | urlquery }}"
+    headers:
+      Authorization: "Bearer {{ env \"FLUSH_TOKEN\" }}"
+    body: '{"resource": {{ json .Name }}, "by": {{ json .Actor }}}'
+- id: trigger-sync
+  name: Trigger sync
+  target:
+    group: apps
+    kind: Deployment
+    optIn: true
+  patch:
+    annotations:
+      example.com/sync-requested-at: "{{ .Now }}"
+      example.com/last-error: null
+`
+
+func loadTestActions(t *testing.T, content string) ([]Action, error) {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "actions.yaml")
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return Load(file)
+}
+
+func deployment(namespace, name string, labels, annotations map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("apps/v1")
+	obj.SetKind("Deployment")
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetLabels(labels)
+	obj.SetAnnotations(annotations)
+	return obj
+}
+
+func TestLoadValidation(t *testing.T) {
+	actions, err := loadTestActions(t, testActions)
+	if err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+	if len(actions) != 2 || actions[0].HTTP.Method != http.MethodPost || actions[1].Type() != TypePatch {
+		t.Errorf("Load() = %+v", actions)
+	}
+
+	invalid := map[string]string{
+		"bad id":         "actions: [{id: Flush, name: x, target: {kind: Pod}, http: {url: http://x}}]",
+		"no kind":        "actions: [{id: a, name: x, target: {}, http: {url: http://x}}]",
+		"http and patch": "actions: [{id: a, name: x, target: {kind: Pod}, http: {url: http://x}, patch: {labels: {a: b}}}]",
+		"bad template":   "actions: [{id: a, name: x, target: {kind: Pod}, http: {url: '{{ .Name'}}]",
+		"bad selector":   "actions: [{id: a, name: x, target: {kind: Pod, labelSelector: '=='}, http: {url: http://x}}]",
+		"unknown field":  "actions: [{id: a, name: x, target: {kind: Pod}, http: {url: http://x, verb: GET}}]",
+		"duplicate id":   "actions: [{id: a, name: x, target: {kind: Pod}, http: {url: http://x}}, {id: a, name: y, target: {kind: Pod}, http: {url: http://y}}]",
+	}
+	for name, content := range invalid {
+		if _, err := loadTestActions(t, content); err == nil {
+			t.Errorf("Load() accepted an action with %s", name)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	actions, err := loadTestActions(t, testActions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry := newRegistry(actions, time.Second, logger.New("error"))
+
+	ids := func(obj *unstructured.Unstructured) string {
+		var matched []string
+		for _, a := range registry.ForResource(obj) {
+			matched = append(matched, a.ID)
+		}
+		return strings.Join(matched, ",")
+	}
+	redis := map[string]string{"app": "redis"}
+	if got := ids(deployment("prod-eu", "cache", redis, nil)); got != "flush-cache" {
+		t.Errorf("actions of a prod redis deployment = %q, want flush-cache", got)
+	}
+	if got := ids(deployment("staging", "cache", redis, nil)); got != "" {
+		t.Errorf("actions outside the target namespaces = %q, want none", got)
+	}
+	if got := ids(deployment("prod-eu", "api", nil, map[string]string{ActionsAnnotation: "restart, trigger-sync"})); got != "trigger-sync" {
+		t.Errorf("actions of an opted-in deployment = %q, want trigger-sync", got)
+	}
+	statefulSet := deployment("prod-eu", "cache", redis, nil)
+	statefulSet.SetKind("StatefulSet")
+	if got := ids(statefulSet); got != "" {
+		t.Errorf("actions of another kind = %q, want none", got)
+	}
+}
+
+func TestRunHTTP(t *testing.T) {
+	t.Setenv("FLUSH_TOKEN", "secret")
+	var gotPath, gotAuth, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotAuth = r.URL.RequestURI(), r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		if strings.Contains(r.URL.RawQuery, "broken") {
+			http.Error(w, "cache unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("flushed"))
+	}))
+	defer server.Close()
+
+	actions, err := loadTestActions(t, testActions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry := newRegistry(actions, time.Second, logger.New("error"))
+	action, _ := registry.Get("flush-cache")
+
+	obj := deployment("prod-eu", "cache", map[string]string{"app": "redis"}, map[string]string{"cache.example.com/admin-url": server.URL})
+	result, err := registry.Run(context.Background(), action, obj, nil, NewTemplateData(obj, "c1", "", "alice"))
+	if err != nil || result.StatusCode != http.StatusOK || result.Response != "flushed" {
+		t.Fatalf("Run() = %+v %v", result, err)
+	}
+	if gotPath != "/flush?name=cache" || gotAuth != "Bearer secret" || gotBody != `{"resource": "cache", "by": "alice"}` {
+		t.Errorf("request = %s auth %q body %s", gotPath, gotAuth, gotBody)
+	}
+
+	obj.SetName("broken")
+	if result, err := registry.Run(context.Background(), action, obj, nil, NewTemplateData(obj, "c1", "", "alice")); err == nil || result.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Run() against a failing endpoint = %+v %v, want an error", result, err)
+	}
+
+	staging := deployment("staging", "cache", map[string]string{"app": "redis"}, nil)
+	if _, err := registry.Run(context.Background(), action, staging, nil, NewTemplateData(staging, "c1", "", "alice")); err != ErrNotApplicable {
+		t.Errorf("Run() on an unselected resource = %v, want ErrNotApplicable", err)
+	}
+}
+
+func TestRunPatch(t *testing.T) {
+	actions, err := loadTestActions(t, testActions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry := newRegistry(actions, time.Second, logger.New("error"))
+	action, _ := registry.Get("trigger-sync")
+
+	obj := deployment("prod-eu", "api", nil, map[string]string{ActionsAnnotation: "trigger-sync", "example.com/last-error": "timeout"})
+	gvr := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{gvr: "DeploymentList"}, obj.DeepCopy())
+	resource := client.Resource(gvr).Namespace("prod-eu")
+
+	data := NewTemplateData(obj, "c1", "", "alice")
+	if _, err := registry.Run(context.Background(), action, obj, resource, data); err != nil {
+		t.Fatalf("Run() = %v", err)
+	}
+	patched, err := resource.Get(context.Background(), "api", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	annotations := patched.GetAnnotations()
+	if annotations["example.com/sync-requested-at"] != data.Now {
+		t.Errorf("sync-requested-at = %q, want %q", annotations["example.com/sync-requested-at"], data.Now)
+	}
+	if _, ok := annotations["example.com/last-error"]; ok {
+		t.Error("last-error annotation was not removed")
+	}
+}
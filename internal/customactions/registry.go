@@ -0,0 +1,209 @@
+package customactions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/config"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// maxResponseBytes bounds the HTTP response body returned to the caller
+const maxResponseBytes = 64 * 1024
+
+var (
+	// ErrNotFound is returned for an unknown action ID
+	ErrNotFound = errors.New("custom action not found")
+	// ErrNotApplicable is returned when running an action on a resource its target does not select
+	ErrNotApplicable = errors.New("custom action does not apply to this resource")
+)
+
+// Result is the outcome of running an action
+type Result struct {
+	Action     string `json:"action"`
+	Type       string `json:"type"`
+	StatusCode int    `json:"statusCode,omitempty"` // HTTP status of the call
+	Response   string `json:"response,omitempty"`   // start of the HTTP response body
+	Patch      string `json:"patch,omitempty"`      // merge patch applied to the resource
+}
+
+// Registry holds the configured actions and runs them
+type Registry struct {
+	actions []Action
+	client  *http.Client
+	logger  *logger.Logger
+}
+
+// NewRegistry loads the actions file; a missing or invalid file is logged and leaves no actions
+func NewRegistry(cfg *config.CustomActionsConfig, log *logger.Logger) *Registry {
+	var actions []Action
+	if cfg.File != "" {
+		var err error
+		if actions, err = Load(cfg.File); err != nil {
+			log.WithError(err).Error("Failed to load custom actions")
+		} else {
+			log.Info("Loaded custom actions", "count", len(actions), "file", cfg.File)
+		}
+	}
+	return newRegistry(actions, time.Duration(cfg.HTTPTimeoutSeconds)*time.Second, log)
+}
+
+func newRegistry(actions []Action, timeout time.Duration, log *logger.Logger) *Registry {
+	if timeout <= 0 {
+		timeout = 15 * time.Second
+	}
+	return &Registry{actions: actions, client: &http.Client{Timeout: timeout}, logger: log}
+}
+
+// List returns all actions
+func (r *Registry) List() []Action {
+	return r.actions
+}
+
+// Get returns an action by ID
+func (r *Registry) Get(id string) (*Action, error) {
+	for i := range r.actions {
+		if r.actions[i].ID == id {
+			return &r.actions[i], nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// TargetsKind reports whether any action targets the given group and kind
+func (r *Registry) TargetsKind(group, kind string) bool {
+	for i := range r.actions {
+		if r.actions[i].TargetsKind(group, kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// ForResource returns the actions that apply to a resource
+func (r *Registry) ForResource(obj *unstructured.Unstructured) []Action {
+	matched := []Action{}
+	for i := range r.actions {
+		if r.actions[i].Matches(obj) {
+			matched = append(matched, r.actions[i])
+		}
+	}
+	return matched
+}
+
+// NewTemplateData describes a resource for action templates
+func NewTemplateData(obj *unstructured.Unstructured, configID, cluster, actor string) *TemplateData {
+	gvk := obj.GroupVersionKind()
+	return &TemplateData{
+		ConfigID:    configID,
+		Cluster:     cluster,
+		Group:       gvk.Group,
+		Version:     gvk.Version,
+		Kind:        gvk.Kind,
+		Namespace:   obj.GetNamespace(),
+		Name:        obj.GetName(),
+		UID:         string(obj.GetUID()),
+		Labels:      obj.GetLabels(),
+		Annotations: obj.GetAnnotations(),
+		Actor:       actor,
+		Now:         time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+// Run runs an action on a resource; resource is the dynamic client of the resource's kind and
+// namespace, used by patch actions
+func (r *Registry) Run(ctx context.Context, action *Action, obj *unstructured.Unstructured, resource dynamic.ResourceInterface, data *TemplateData) (*Result, error) {
+	if !action.Matches(obj) {
+		return nil, ErrNotApplicable
+	}
+	if action.Patch != nil {
+		return r.runPatch(ctx, action, obj, resource, data)
+	}
+	return r.runHTTP(ctx, action, data)
+}
+
+func (r *Registry) runHTTP(ctx context.Context, action *Action, data *TemplateData) (*Result, error) {
+	url, err := action.render("url", data)
+	if err != nil {
+		return nil, err
+	}
+	body, err := action.render("body", data)
+	if err != nil {
+		return nil, err
+	}
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, action.HTTP.Method, url, reader)
+	if err != nil {
+		return nil, fmt.Errorf("invalid action request: %w", err)
+	}
+	for name := range action.HTTP.Headers {
+		value, err := action.render("header:"+name, data)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set(name, value)
+	}
+	if body != "" && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("action request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	response, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	result := &Result{Action: action.ID, Type: TypeHTTP, StatusCode: resp.StatusCode, Response: string(response)}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return result, fmt.Errorf("action endpoint %s returned %s", req.URL.Host, resp.Status)
+	}
+	return result, nil
+}
+
+func (r *Registry) runPatch(ctx context.Context, action *Action, obj *unstructured.Unstructured, resource dynamic.ResourceInterface, data *TemplateData) (*Result, error) {
+	metadata := map[string]interface{}{}
+	for field, values := range map[string]map[string]*string{"annotations": action.Patch.Annotations, "labels": action.Patch.Labels} {
+		if len(values) == 0 {
+			continue
+		}
+		rendered := map[string]interface{}{}
+		for key, value := range values {
+			if value == nil {
+				rendered[key] = nil
+				continue
+			}
+			prefix := "annotation:"
+			if field == "labels" {
+				prefix = "label:"
+			}
+			text, err := action.render(prefix+key, data)
+			if err != nil {
+				return nil, err
+			}
+			rendered[key] = text
+		}
+		metadata[field] = rendered
+	}
+	patch, err := json.Marshal(map[string]interface{}{"metadata": metadata})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := resource.Patch(ctx, obj.GetName(), types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return nil, err
+	}
+	return &Result{Action: action.ID, Type: TypePatch, Patch: string(patch)}, nil
+}
@@ -0,0 +1,253 @@
+package dashboards
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxPanels bounds the queries a single dashboard run issues against Prometheus
+const maxPanels = 30
+
+// Panel types
+const (
+	PanelTimeSeries = "timeseries"
+	PanelStat       = "stat"
+)
+
+// Threshold colours a panel when its value reaches Value
+type Threshold struct {
+	Value float64 `json:"value"`
+	Color string  `json:"color"`
+	Label string  `json:"label,omitempty"`
+}
+
+// Panel is a saved PromQL query and how to display it
+type Panel struct {
+	ID         string      `json:"id"`
+	Title      string      `json:"title"`
+	Type       string      `json:"type"` // timeseries or stat
+	Query      string      `json:"query"`
+	Legend     string      `json:"legend,omitempty"` // label template such as {{pod}}/{{container}}
+	Unit       string      `json:"unit,omitempty"`   // display unit such as percent, bytes, cores or req/s
+	Thresholds []Threshold `json:"thresholds,omitempty"`
+}
+
+// Dashboard is a named set of PromQL panels saved for an owner and cluster
+type Dashboard struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Owner       string    `json:"owner,omitempty"` // empty for dashboards shared with everyone
+	ConfigID    string    `json:"configId"`
+	Cluster     string    `json:"cluster,omitempty"`
+	Range       string    `json:"range,omitempty"` // default time range such as 1h
+	Step        string    `json:"step,omitempty"`  // default query resolution such as 30s
+	Panels      []Panel   `json:"panels"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// Validate checks that a dashboard can be saved
+func (d *Dashboard) Validate() error {
+	if strings.TrimSpace(d.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if d.ConfigID == "" {
+		return fmt.Errorf("configId is required")
+	}
+	if len(d.Panels) > maxPanels {
+		return fmt.Errorf("a dashboard may have at most %d panels", maxPanels)
+	}
+	if d.Range != "" {
+		if _, err := ParseDuration(d.Range); err != nil {
+			return fmt.Errorf("invalid range: %w", err)
+		}
+	}
+	if d.Step != "" {
+		if _, err := ParseDuration(d.Step); err != nil {
+			return fmt.Errorf("invalid step: %w", err)
+		}
+	}
+	ids := map[string]bool{}
+	for i := range d.Panels {
+		p := &d.Panels[i]
+		if strings.TrimSpace(p.Query) == "" {
+			return fmt.Errorf("panel %d: query is required", i+1)
+		}
+		switch p.Type {
+		case "":
+			p.Type = PanelTimeSeries
+		case PanelTimeSeries, PanelStat:
+		default:
+			return fmt.Errorf("panel %d: type must be timeseries or stat", i+1)
+		}
+		if p.ID == "" {
+			p.ID = strconv.Itoa(i + 1)
+		}
+		if ids[p.ID] {
+			return fmt.Errorf("panel %d: duplicate panel id %q", i+1, p.ID)
+		}
+		ids[p.ID] = true
+		sort.Slice(p.Thresholds, func(a, b int) bool { return p.Thresholds[a].Value < p.Thresholds[b].Value })
+	}
+	return nil
+}
+
+// ParseDuration parses Prometheus-style durations, which also allow d and w units
+func ParseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if n := len(value); n > 1 && (value[n-1] == 'd' || value[n-1] == 'w') {
+		count, err := strconv.Atoi(value[:n-1])
+		if err != nil || count <= 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		day := 24 * time.Hour
+		if value[n-1] == 'w' {
+			day *= 7
+		}
+		return time.Duration(count) * day, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return d, nil
+}
+
+var legendPlaceholder = regexp.MustCompile(`\{\{\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*\}\}`)
+
+// RenderLegend substitutes {{label}} placeholders with the series' label values. Without a
+// template the series is named after its labels, as Prometheus prints them.
+func RenderLegend(template string, labels map[string]string) string {
+	if template != "" {
+		return legendPlaceholder.ReplaceAllStringFunc(template, func(match string) string {
+			return labels[legendPlaceholder.FindStringSubmatch(match)[1]]
+		})
+	}
+	name := labels["__name__"]
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		if k != "__name__" {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		if name == "" {
+			return "value"
+		}
+		return name
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = fmt.Sprintf("%s=%q", k, labels[k])
+	}
+	return name + "{" + strings.Join(pairs, ", ") + "}"
+}
+
+// Point is one sample of a series
+type Point struct {
+	T float64 `json:"t"`
+	V float64 `json:"v"`
+}
+
+// Series is one result series of a panel query
+type Series struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels"`
+	Points []Point           `json:"points"`
+}
+
+// PanelResult is the outcome of running one panel
+type PanelResult struct {
+	PanelID   string   `json:"panelId"`
+	Series    []Series `json:"series"`
+	Value     *float64 `json:"value,omitempty"`     // latest value of the first series, for stat panels
+	Threshold *string  `json:"threshold,omitempty"` // colour of the highest threshold the value reaches
+	Error     string   `json:"error,omitempty"`
+}
+
+type promResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string `json:"metric"`
+			Value  []interface{}     `json:"value"`
+			Values [][]interface{}   `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+func parseSample(pair []interface{}) (Point, bool) {
+	if len(pair) != 2 {
+		return Point{}, false
+	}
+	t, ok := pair[0].(float64)
+	if !ok {
+		return Point{}, false
+	}
+	s, ok := pair[1].(string)
+	if !ok {
+		return Point{}, false
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return Point{}, false
+	}
+	return Point{T: t, V: v}, true
+}
+
+// ParseResult converts a Prometheus query or query_range response into panel series
+func ParseResult(panel *Panel, raw []byte) (PanelResult, error) {
+	result := PanelResult{PanelID: panel.ID, Series: []Series{}}
+	var resp promResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return result, fmt.Errorf("invalid Prometheus response: %w", err)
+	}
+	if resp.Status != "success" {
+		return result, fmt.Errorf("query failed: %s", resp.Error)
+	}
+	for _, r := range resp.Data.Result {
+		s := Series{Name: RenderLegend(panel.Legend, r.Metric), Labels: r.Metric, Points: []Point{}}
+		if r.Metric == nil {
+			s.Labels = map[string]string{}
+		}
+		for _, pair := range r.Values {
+			if p, ok := parseSample(pair); ok {
+				s.Points = append(s.Points, p)
+			}
+		}
+		if p, ok := parseSample(r.Value); ok {
+			s.Points = append(s.Points, p)
+		}
+		result.Series = append(result.Series, s)
+	}
+	sort.SliceStable(result.Series, func(i, j int) bool { return result.Series[i].Name < result.Series[j].Name })
+
+	if len(result.Series) > 0 && len(result.Series[0].Points) > 0 {
+		points := result.Series[0].Points
+		v := points[len(points)-1].V
+		result.Value = &v
+		result.Threshold = matchThreshold(panel.Thresholds, v)
+	}
+	return result, nil
+}
+
+// matchThreshold returns the colour of the highest threshold at or below v
+func matchThreshold(thresholds []Threshold, v float64) *string {
+	var color *string
+	for i := range thresholds {
+		if v >= thresholds[i].Value {
+			color = &thresholds[i].Color
+		}
+	}
+	return color
+}
@@ -0,0 +1,91 @@
+package dashboards
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	d := Dashboard{Name: "API", ConfigID: "cfg", Range: "7d", Panels: []Panel{
+		{Title: "Requests", Query: "sum(rate(http_requests_total[5m]))", Thresholds: []Threshold{{Value: 100, Color: "red"}, {Value: 50, Color: "yellow"}}},
+		{Title: "Errors", Type: PanelStat, Query: "sum(rate(http_errors_total[5m]))"},
+	}}
+	if err := d.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.Panels[0].Type != PanelTimeSeries || d.Panels[0].ID != "1" || d.Panels[1].ID != "2" {
+		t.Errorf("expected default type and ids, got %+v", d.Panels)
+	}
+	if d.Panels[0].Thresholds[0].Value != 50 {
+		t.Errorf("expected thresholds sorted ascending, got %+v", d.Panels[0].Thresholds)
+	}
+
+	invalid := []Dashboard{
+		{ConfigID: "cfg"},
+		{Name: "x"},
+		{Name: "x", ConfigID: "cfg", Range: "soon"},
+		{Name: "x", ConfigID: "cfg", Panels: []Panel{{Title: "empty"}}},
+		{Name: "x", ConfigID: "cfg", Panels: []Panel{{Query: "up", Type: "pie"}}},
+		{Name: "x", ConfigID: "cfg", Panels: []Panel{{ID: "a", Query: "up"}, {ID: "a", Query: "up"}}},
+	}
+	for i, d := range invalid {
+		if err := d.Validate(); err == nil {
+			t.Errorf("case %d: expected validation error", i)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	cases := map[string]time.Duration{"30s": 30 * time.Second, "1h": time.Hour, "2d": 48 * time.Hour, "1w": 7 * 24 * time.Hour}
+	for value, want := range cases {
+		if got, err := ParseDuration(value); err != nil || got != want {
+			t.Errorf("ParseDuration(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+	if _, err := ParseDuration("-1h"); err == nil {
+		t.Error("expected negative duration to be rejected")
+	}
+}
+
+func TestRenderLegend(t *testing.T) {
+	labels := map[string]string{"__name__": "up", "pod": "web-1", "container": "app"}
+	if got := RenderLegend("{{pod}}/{{ container }}", labels); got != "web-1/app" {
+		t.Errorf("unexpected legend %q", got)
+	}
+	if got := RenderLegend("", labels); got != `up{container="app", pod="web-1"}` {
+		t.Errorf("unexpected default legend %q", got)
+	}
+	if got := RenderLegend("", map[string]string{}); got != "value" {
+		t.Errorf("unexpected legend for unlabelled series %q", got)
+	}
+}
+
+func TestParseResult(t *testing.T) {
+	panel := &Panel{ID: "cpu", Legend: "{{pod}}", Thresholds: []Threshold{{Value: 50, Color: "yellow"}, {Value: 80, Color: "red"}}}
+	raw := []byte(`{"status":"success","data":{"resultType":"matrix","result":[
+		{"metric":{"pod":"b"},"values":[[1700000000,"10"],[1700000015,"NaN"]]},
+		{"metric":{"pod":"a"},"values":[[1700000000,"60"],[1700000015,"85"]]}]}}`)
+	result, err := ParseResult(panel, raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Series) != 2 || result.Series[0].Name != "a" {
+		t.Fatalf("expected series sorted by name, got %+v", result.Series)
+	}
+	if len(result.Series[1].Points) != 1 {
+		t.Errorf("expected NaN sample to be dropped, got %+v", result.Series[1].Points)
+	}
+	if result.Value == nil || *result.Value != 85 || result.Threshold == nil || *result.Threshold != "red" {
+		t.Errorf("expected value 85 at red threshold, got %v %v", result.Value, result.Threshold)
+	}
+
+	instant := []byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"42"]}]}}`)
+	result, err = ParseResult(&Panel{ID: "stat"}, instant)
+	if err != nil || result.Value == nil || *result.Value != 42 || result.Threshold != nil {
+		t.Errorf("unexpected instant result %+v, %v", result, err)
+	}
+
+	if _, err := ParseResult(panel, []byte(`{"status":"error","error":"parse error"}`)); err == nil {
+		t.Error("expected failed query to return an error")
+	}
+}
@@ -0,0 +1,92 @@
+package dashboards
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// dashboardsCollection is the document collection holding saved dashboards
+const dashboardsCollection = "dashboards"
+
+// Filter narrows the dashboards returned by List; empty fields match everything
+type Filter struct {
+	Owner    string // also matches shared dashboards, which have no owner
+	ConfigID string
+	Cluster  string // also matches dashboards not bound to a cluster
+}
+
+// Store persists dashboard definitions
+type Store struct {
+	documents *storage.DocumentStore
+	logger    *logger.Logger
+}
+
+// NewStore creates a dashboard store
+func NewStore(documents *storage.DocumentStore, log *logger.Logger) *Store {
+	return &Store{
+		documents: documents,
+		logger:    log,
+	}
+}
+
+// List returns the dashboards matching the filter sorted by name
+func (s *Store) List(filter Filter) ([]Dashboard, error) {
+	docs, err := s.documents.List(dashboardsCollection)
+	if err != nil {
+		return nil, err
+	}
+	dashboards := make([]Dashboard, 0, len(docs))
+	for id, data := range docs {
+		var d Dashboard
+		if err := json.Unmarshal(data, &d); err != nil {
+			s.logger.WithError(err).WithField("dashboard", id).Error("Skipping unreadable dashboard")
+			continue
+		}
+		if filter.Owner != "" && d.Owner != "" && d.Owner != filter.Owner {
+			continue
+		}
+		if filter.ConfigID != "" && d.ConfigID != filter.ConfigID {
+			continue
+		}
+		if filter.Cluster != "" && d.Cluster != "" && d.Cluster != filter.Cluster {
+			continue
+		}
+		dashboards = append(dashboards, d)
+	}
+	sort.Slice(dashboards, func(i, j int) bool { return dashboards[i].Name < dashboards[j].Name })
+	return dashboards, nil
+}
+
+// Get returns a single dashboard
+func (s *Store) Get(id string) (*Dashboard, error) {
+	var d Dashboard
+	if err := s.documents.Get(dashboardsCollection, id, &d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// Save validates and persists a dashboard, assigning an ID to new dashboards
+func (s *Store) Save(d *Dashboard) error {
+	if err := d.Validate(); err != nil {
+		return err
+	}
+	now := time.Now()
+	if d.ID == "" {
+		d.ID = uuid.New().String()
+		d.CreatedAt = now
+	}
+	d.UpdatedAt = now
+	return s.documents.Put(dashboardsCollection, d.ID, d)
+}
+
+// Delete removes a dashboard
+func (s *Store) Delete(id string) error {
+	return s.documents.Delete(dashboardsCollection, id)
+}
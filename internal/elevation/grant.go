@@ -0,0 +1,92 @@
+package elevation
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Actions that require an elevated access grant
+const (
+	ActionExec   = "exec"   // pod exec and debug pods
+	ActionDelete = "delete" // deleting namespaced resources
+)
+
+// Request statuses
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusDenied   = "denied"
+	StatusRevoked  = "revoked"
+	StatusExpired  = "expired"
+)
+
+// Request is a request for temporary exec or delete rights on a namespace. Once approved it is
+// the grant itself: it covers its actions in that namespace until ExpiresAt.
+type Request struct {
+	ID        string   `json:"id"`
+	TokenID   string   `json:"tokenId"`   // API token the grant applies to
+	Requester string   `json:"requester"` // token owner, or its name
+	ConfigID  string   `json:"configId"`
+	Cluster   string   `json:"cluster,omitempty"`
+	Namespace string   `json:"namespace"`
+	Actions   []string `json:"actions"`
+	Reason    string   `json:"reason"`
+	// DurationMinutes is the grant length asked for; the approver may shorten it
+	DurationMinutes int        `json:"durationMinutes"`
+	Status          string     `json:"status"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	DecidedAt       *time.Time `json:"decidedAt,omitempty"`
+	DecidedBy       string     `json:"decidedBy,omitempty"`
+	DecisionNote    string     `json:"decisionNote,omitempty"`
+	ExpiresAt       *time.Time `json:"expiresAt,omitempty"`
+}
+
+// Validate checks that a request is well formed
+func (r *Request) Validate() error {
+	if r.ConfigID == "" {
+		return fmt.Errorf("configId is required")
+	}
+	if strings.TrimSpace(r.Namespace) == "" {
+		return fmt.Errorf("namespace is required")
+	}
+	if len(r.Actions) == 0 {
+		return fmt.Errorf("at least one action is required")
+	}
+	for _, action := range r.Actions {
+		if action != ActionExec && action != ActionDelete {
+			return fmt.Errorf("actions must be %s or %s", ActionExec, ActionDelete)
+		}
+	}
+	if strings.TrimSpace(r.Reason) == "" {
+		return fmt.Errorf("reason is required")
+	}
+	if r.DurationMinutes < 0 {
+		return fmt.Errorf("durationMinutes must not be negative")
+	}
+	return nil
+}
+
+// effectiveStatus reports approved grants past their expiry as expired
+func (r *Request) effectiveStatus(now time.Time) string {
+	if r.Status == StatusApproved && r.ExpiresAt != nil && !now.Before(*r.ExpiresAt) {
+		return StatusExpired
+	}
+	return r.Status
+}
+
+// Covers reports whether the request is an active grant for the action in a namespace
+func (r *Request) Covers(tokenID, configID, cluster, namespace, action string, now time.Time) bool {
+	if r.effectiveStatus(now) != StatusApproved || r.TokenID != tokenID || r.ConfigID != configID || r.Namespace != namespace {
+		return false
+	}
+	if r.Cluster != "" && r.Cluster != cluster {
+		return false
+	}
+	for _, a := range r.Actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
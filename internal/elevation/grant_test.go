@@ -0,0 +1,119 @@
+package elevation
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/config"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCoversExpiryAndScope(t *testing.T) {
+	now := time.Now()
+	expires := now.Add(time.Hour)
+	req := Request{
+		TokenID:   "t1",
+		ConfigID:  "cfg",
+		Namespace: "payments",
+		Actions:   []string{ActionExec},
+		Status:    StatusApproved,
+		ExpiresAt: &expires,
+	}
+
+	if !req.Covers("t1", "cfg", "any", "payments", ActionExec, now) {
+		t.Fatal("expected an active grant to cover exec in its namespace")
+	}
+	if req.Covers("t1", "cfg", "", "payments", ActionDelete, now) {
+		t.Error("grant should not cover actions it was not given")
+	}
+	if req.Covers("t1", "cfg", "", "billing", ActionExec, now) {
+		t.Error("grant should not cover other namespaces")
+	}
+	if req.Covers("t2", "cfg", "", "payments", ActionExec, now) {
+		t.Error("grant should not cover other tokens")
+	}
+	if req.Covers("t1", "cfg", "", "payments", ActionExec, expires) {
+		t.Error("grant should not cover requests at or after expiry")
+	}
+	if got := req.effectiveStatus(expires.Add(time.Second)); got != StatusExpired {
+		t.Errorf("effective status after expiry = %q, want %q", got, StatusExpired)
+	}
+
+	req.Cluster = "east"
+	if req.Covers("t1", "cfg", "west", "payments", ActionExec, now) {
+		t.Error("cluster-bound grant should not cover other clusters")
+	}
+}
+
+func TestStoreApproveCapsDuration(t *testing.T) {
+	store := NewStore(storage.NewDocumentStore(nil), &config.ElevationConfig{DefaultDurationMinutes: 30, MaxDurationMinutes: 60}, logger.New("error"))
+	req := &Request{TokenID: "t1", ConfigID: "cfg", Namespace: "payments", Actions: []string{ActionDelete}, Reason: "cleanup"}
+	if err := store.Create(req); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if req.DurationMinutes != 30 {
+		t.Errorf("default duration = %d, want 30", req.DurationMinutes)
+	}
+	if _, ok := store.Grant("t1", "cfg", "", "payments", ActionDelete); ok {
+		t.Fatal("pending request should not grant access")
+	}
+
+	approved, err := store.Approve(req.ID, "admin", "", 600)
+	if err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	if approved.DurationMinutes != 60 {
+		t.Errorf("approved duration = %d, want capped 60", approved.DurationMinutes)
+	}
+	if _, ok := store.Grant("t1", "cfg", "", "payments", ActionDelete); !ok {
+		t.Fatal("approved request should grant access")
+	}
+	if _, err := store.Approve(req.ID, "admin", "", 0); err != ErrNotPending {
+		t.Errorf("second approval error = %v, want ErrNotPending", err)
+	}
+
+	if _, err := store.Revoke(req.ID, "admin", "done"); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	if _, ok := store.Grant("t1", "cfg", "", "payments", ActionDelete); ok {
+		t.Error("revoked request should not grant access")
+	}
+}
+
+func TestBodyNamespaces(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{"list of items", `[{"name":"a","namespace":"b"},{"name":"c","namespace":"a"},{"name":"d","namespace":"b"}]`, []string{"a", "b"}},
+		{"bulk request", `{"items":[{"name":"a","namespace":"x"}],"dryRun":true}`, []string{"x"}},
+		{"debug request", `{"namespace":"payments","podName":"api-0"}`, []string{"payments"}},
+		{"cluster scoped", `[{"name":"node-1"}]`, []string{""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodDelete, "/", strings.NewReader(tt.body))
+			got, err := bodyNamespaces(c)
+			if err != nil {
+				t.Fatalf("bodyNamespaces: %v", err)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("namespaces = %v, want %v", got, tt.want)
+			}
+			rest, err := io.ReadAll(c.Request.Body)
+			if err != nil || string(rest) != tt.body {
+				t.Errorf("body was not restored for the handler: %q", rest)
+			}
+		})
+	}
+}
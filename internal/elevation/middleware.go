@@ -0,0 +1,166 @@
+package elevation
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Facets-cloud/kube-dash/internal/apitokens"
+	"github.com/Facets-cloud/kube-dash/internal/audit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// guardedRoute is an endpoint that needs a grant and where it finds the target namespaces
+type guardedRoute struct {
+	action     string
+	namespaces func(c *gin.Context) ([]string, error)
+}
+
+// guardedRoutes maps method and route pattern to the grant they need
+var guardedRoutes = map[string]guardedRoute{
+	"GET /api/v1/pods/:namespace/:name/exec/ws":                        {ActionExec, pathNamespace},
+	"GET /api/v1/pods/:namespace/:name/connections":                    {ActionExec, pathNamespace},
+	"GET /api/v1/terminal/exec/:namespace/:name/ws":                    {ActionExec, pathNamespace},
+	"POST /api/v1/pods/debug":                                          {ActionExec, bodyNamespaces},
+	"DELETE /api/v1/:resourcekind":                                     {ActionDelete, bodyNamespaces},
+	"DELETE /api/v1/bulk/:resourcekind":                                {ActionDelete, bodyNamespaces},
+	"POST /api/v1/workloads/:kind/:namespace/:name/drift/delete-stale": {ActionDelete, pathNamespace},
+	"POST /api/v1/deployments/:name/revisions/cleanup":                 {ActionDelete, queryNamespace},
+	"POST /api/v1/pod-cleanup/run":                                     {ActionDelete, clusterWide},
+	"GET /api/v1/terminal/cloudshell/:namespace/:name/ws":              {ActionExec, pathNamespace},
+	"DELETE /api/v1/cloudshell/:name":                                  {ActionDelete, queryNamespace},
+	"POST /api/v1/cloudshell/cleanup":                                  {ActionDelete, clusterWide},
+	"POST /api/v1/app/apply/sets/:id/rollback":                         {ActionDelete, clusterWide},
+	"POST /api/v1/nodes/:name/drain":                                   {ActionDelete, clusterWide},
+}
+
+// Guarded reports whether a route needs an elevated access grant for elevation-bound tokens
+func Guarded(method, path string) bool {
+	_, ok := guardedRoutes[method+" "+path]
+	return ok
+}
+
+func pathNamespace(c *gin.Context) ([]string, error) {
+	return []string{c.Param("namespace")}, nil
+}
+
+func queryNamespace(c *gin.Context) ([]string, error) {
+	return []string{c.Query("namespace")}, nil
+}
+
+// clusterWide marks an endpoint that acts on every namespace, or on objects no request parameter
+// names such as those of a stored apply set, which no namespace grant covers
+func clusterWide(c *gin.Context) ([]string, error) {
+	return []string{""}, nil
+}
+
+// bodyNamespaces collects the namespaces named in a JSON body: a debug pod request, a list of
+// items to delete, or a bulk delete request. The body is restored for the handler.
+func bodyNamespaces(c *gin.Context) ([]string, error) {
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(data))
+
+	type item struct {
+		Namespace string `json:"namespace"`
+	}
+	var items []item
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return nil, err
+		}
+	} else {
+		var body struct {
+			item
+			Items []item `json:"items"`
+		}
+		if err := json.Unmarshal(data, &body); err != nil {
+			return nil, err
+		}
+		items = body.Items
+		if len(items) == 0 {
+			items = []item{body.item}
+		}
+	}
+
+	seen := map[string]bool{}
+	namespaces := []string{}
+	for _, it := range items {
+		if !seen[it.Namespace] {
+			seen[it.Namespace] = true
+			namespaces = append(namespaces, it.Namespace)
+		}
+	}
+	sort.Strings(namespaces)
+	return namespaces, nil
+}
+
+// Middleware enforces elevated access grants for API tokens that require elevation. It must run
+// after the API token middleware; requests without such a token pass through unchanged.
+func Middleware(store *Store, auditor *audit.Recorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := apitokens.FromContext(c)
+		if !ok || !token.RequireElevation {
+			c.Next()
+			return
+		}
+		route, guarded := guardedRoutes[c.Request.Method+" "+c.FullPath()]
+		if !guarded {
+			c.Next()
+			return
+		}
+
+		configID, cluster := c.Query("config"), c.Query("cluster")
+		record := func(outcome, reason, namespace, grantID string) {
+			auditor.Record(audit.Event{
+				Action:     "elevation." + route.action,
+				Outcome:    outcome,
+				Reason:     reason,
+				RemoteAddr: c.ClientIP(),
+				UserAgent:  c.Request.UserAgent(),
+				ConfigID:   configID,
+				Cluster:    cluster,
+				Namespace:  namespace,
+				Details: map[string]string{
+					"method":    c.Request.Method,
+					"path":      c.Request.URL.Path,
+					"token":     token.ID,
+					"tokenName": token.Name,
+					"grant":     grantID,
+				},
+			})
+		}
+
+		namespaces, err := route.namespaces(c)
+		if err != nil {
+			record(audit.OutcomeDenied, "could not determine the target namespace", "", "")
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "could not determine the target namespace: " + err.Error()})
+			return
+		}
+		grants := make([]string, 0, len(namespaces))
+		for _, namespace := range namespaces {
+			if namespace == "" {
+				reason := "cluster-scoped resources and cluster-wide operations cannot be covered by a namespace grant"
+				record(audit.OutcomeDenied, reason, "", "")
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": reason})
+				return
+			}
+			grant, ok := store.Grant(token.ID, configID, cluster, namespace, route.action)
+			if !ok {
+				reason := "no approved " + route.action + " access for namespace " + namespace
+				record(audit.OutcomeDenied, reason, namespace, "")
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": reason + "; request elevated access first"})
+				return
+			}
+			grants = append(grants, grant.ID)
+		}
+		record(audit.OutcomeAllowed, "", strings.Join(namespaces, ","), strings.Join(grants, ","))
+		c.Next()
+	}
+}
@@ -0,0 +1,200 @@
+package elevation
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/config"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+const requestsCollection = "elevation_requests"
+
+// ErrNotPending is returned when deciding a request that was already decided
+var ErrNotPending = errors.New("access request is not pending")
+
+// ErrNotApproved is returned when revoking a request that is not an active grant
+var ErrNotApproved = errors.New("access request is not an active grant")
+
+// Filter narrows the requests returned by List; empty fields match everything
+type Filter struct {
+	Status    string
+	TokenID   string
+	ConfigID  string
+	Namespace string
+}
+
+// Store persists elevated access requests and answers grant checks from memory
+type Store struct {
+	documents *storage.DocumentStore
+	config    *config.ElevationConfig
+	logger    *logger.Logger
+
+	mu       sync.RWMutex
+	requests []Request
+}
+
+// NewStore creates an elevated access request store
+func NewStore(documents *storage.DocumentStore, cfg *config.ElevationConfig, log *logger.Logger) *Store {
+	s := &Store{
+		documents: documents,
+		config:    cfg,
+		logger:    log,
+	}
+	if err := s.reload(); err != nil {
+		log.WithError(err).Error("Failed to load elevated access requests")
+	}
+	return s
+}
+
+// Create validates and stores a new pending request
+func (s *Store) Create(req *Request) error {
+	if req.DurationMinutes == 0 {
+		req.DurationMinutes = s.config.DefaultDurationMinutes
+	}
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	if req.DurationMinutes > s.config.MaxDurationMinutes {
+		req.DurationMinutes = s.config.MaxDurationMinutes
+	}
+	req.ID = uuid.New().String()
+	req.Status = StatusPending
+	req.CreatedAt = time.Now()
+	req.DecidedAt, req.DecidedBy, req.DecisionNote, req.ExpiresAt = nil, "", "", nil
+	return s.put(req)
+}
+
+// Get returns a request by ID with its effective status
+func (s *Store) Get(id string) (*Request, error) {
+	var req Request
+	if err := s.documents.Get(requestsCollection, id, &req); err != nil {
+		return nil, err
+	}
+	req.Status = req.effectiveStatus(time.Now())
+	return &req, nil
+}
+
+// List returns matching requests with their effective status, newest first
+func (s *Store) List(filter Filter) []Request {
+	now := time.Now()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	requests := []Request{}
+	for _, req := range s.requests {
+		req.Status = req.effectiveStatus(now)
+		if (filter.Status != "" && req.Status != filter.Status) ||
+			(filter.TokenID != "" && req.TokenID != filter.TokenID) ||
+			(filter.ConfigID != "" && req.ConfigID != filter.ConfigID) ||
+			(filter.Namespace != "" && req.Namespace != filter.Namespace) {
+			continue
+		}
+		requests = append(requests, req)
+	}
+	return requests
+}
+
+// Approve grants a pending request for durationMinutes, or the requested duration when zero.
+// Grants never exceed the configured maximum.
+func (s *Store) Approve(id, approver, note string, durationMinutes int) (*Request, error) {
+	req, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Status != StatusPending {
+		return nil, ErrNotPending
+	}
+	if durationMinutes <= 0 {
+		durationMinutes = req.DurationMinutes
+	}
+	if durationMinutes > s.config.MaxDurationMinutes {
+		durationMinutes = s.config.MaxDurationMinutes
+	}
+	now := time.Now()
+	expiresAt := now.Add(time.Duration(durationMinutes) * time.Minute)
+	req.Status = StatusApproved
+	req.DurationMinutes = durationMinutes
+	req.DecidedAt, req.DecidedBy, req.DecisionNote, req.ExpiresAt = &now, approver, note, &expiresAt
+	return req, s.put(req)
+}
+
+// Deny rejects a pending request
+func (s *Store) Deny(id, approver, note string) (*Request, error) {
+	req, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Status != StatusPending {
+		return nil, ErrNotPending
+	}
+	now := time.Now()
+	req.Status = StatusDenied
+	req.DecidedAt, req.DecidedBy, req.DecisionNote = &now, approver, note
+	return req, s.put(req)
+}
+
+// Revoke ends an active grant before it expires
+func (s *Store) Revoke(id, approver, note string) (*Request, error) {
+	req, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Status != StatusApproved {
+		return nil, ErrNotApproved
+	}
+	now := time.Now()
+	req.Status = StatusRevoked
+	req.ExpiresAt = &now
+	req.DecidedBy, req.DecisionNote = approver, note
+	return req, s.put(req)
+}
+
+// Grant returns the active grant covering an action by a token in a namespace, if any
+func (s *Store) Grant(tokenID, configID, cluster, namespace, action string) (*Request, bool) {
+	now := time.Now()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for i := range s.requests {
+		if s.requests[i].Covers(tokenID, configID, cluster, namespace, action, now) {
+			grant := s.requests[i]
+			return &grant, true
+		}
+	}
+	return nil, false
+}
+
+func (s *Store) put(req *Request) error {
+	if err := s.documents.Put(requestsCollection, req.ID, req); err != nil {
+		return err
+	}
+	return s.reload()
+}
+
+// reload refreshes the in-memory cache used on the request path
+func (s *Store) reload() error {
+	docs, err := s.documents.List(requestsCollection)
+	if err != nil {
+		return err
+	}
+	requests := make([]Request, 0, len(docs))
+	for id, data := range docs {
+		var req Request
+		if err := json.Unmarshal(data, &req); err != nil {
+			s.logger.WithError(err).WithField("request", id).Error("Skipping unreadable elevated access request")
+			continue
+		}
+		requests = append(requests, req)
+	}
+	sort.Slice(requests, func(i, j int) bool { return requests[i].CreatedAt.After(requests[j].CreatedAt) })
+
+	s.mu.Lock()
+	s.requests = requests
+	s.mu.Unlock()
+	return nil
+}
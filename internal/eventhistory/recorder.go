@@ -0,0 +1,440 @@
+package eventhistory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/config"
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/internal/types"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+)
+
+// clustersCollection is the document collection holding the clusters events are recorded for
+const clustersCollection = "event_history_clusters"
+
+const (
+	watchRetryWait = 10 * time.Second
+	pruneInterval  = time.Hour
+)
+
+// RecordedCluster is a cluster whose events are recorded in the background
+type RecordedCluster struct {
+	ConfigID    string     `json:"configId"`
+	Cluster     string     `json:"cluster,omitempty"`
+	EnabledAt   time.Time  `json:"enabledAt"`
+	LastEventAt *time.Time `json:"lastEventAt,omitempty"`
+	LastError   string     `json:"lastError,omitempty"`
+}
+
+// Recorder watches the events of opted-in clusters and persists them so they outlive the
+// cluster's event TTL. Events go to the database when one is configured and to a bounded
+// in-memory store otherwise.
+type Recorder struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	documents     *storage.DocumentStore
+	db            storage.DatabaseStorage
+	logger        *logger.Logger
+	config        *config.EventHistoryConfig
+
+	mu       sync.RWMutex
+	clusters map[string]*RecordedCluster
+	watchers map[string]context.CancelFunc
+	memory   map[string]*types.StoredEvent
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewRecorder creates an event recorder; db may be nil for in-memory storage. Call Start to
+// begin watching the recorded clusters.
+func NewRecorder(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, documents *storage.DocumentStore, db storage.DatabaseStorage, log *logger.Logger, cfg *config.EventHistoryConfig) *Recorder {
+	r := &Recorder{
+		store:         store,
+		clientFactory: clientFactory,
+		documents:     documents,
+		db:            db,
+		logger:        log,
+		config:        cfg,
+		clusters:      make(map[string]*RecordedCluster),
+		watchers:      make(map[string]context.CancelFunc),
+		memory:        make(map[string]*types.StoredEvent),
+	}
+	if err := r.reload(); err != nil {
+		log.WithError(err).Error("Failed to load event history clusters")
+	}
+	return r
+}
+
+func (r *Recorder) reload() error {
+	docs, err := r.documents.List(clustersCollection)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, data := range docs {
+		var c RecordedCluster
+		if err := json.Unmarshal(data, &c); err != nil {
+			r.logger.WithError(err).WithField("cluster", id).Error("Skipping unreadable event history cluster")
+			continue
+		}
+		r.clusters[storage.ClusterKey(c.ConfigID, c.Cluster)] = &c
+	}
+	return nil
+}
+
+// Start begins watching the recorded clusters and pruning expired events
+func (r *Recorder) Start() {
+	r.ctx, r.cancel = context.WithCancel(context.Background())
+	r.mu.Lock()
+	for key, c := range r.clusters {
+		r.startWatcher(key, c.ConfigID, c.Cluster)
+	}
+	r.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(pruneInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.ctx.Done():
+				return
+			case <-ticker.C:
+				r.prune()
+			}
+		}
+	}()
+}
+
+// Stop cancels all watchers and the prune loop
+func (r *Recorder) Stop() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key, cancel := range r.watchers {
+		cancel()
+		delete(r.watchers, key)
+	}
+}
+
+// startWatcher starts the watcher of a cluster; the caller holds r.mu
+func (r *Recorder) startWatcher(key, configID, cluster string) {
+	if r.ctx == nil {
+		return
+	}
+	if _, ok := r.watchers[key]; ok {
+		return
+	}
+	ctx, cancel := context.WithCancel(r.ctx)
+	r.watchers[key] = cancel
+	go r.watchEvents(ctx, configID, cluster)
+}
+
+// Enable starts recording the events of a cluster
+func (r *Recorder) Enable(configID, cluster string) (*RecordedCluster, error) {
+	key := storage.ClusterKey(configID, cluster)
+	r.mu.Lock()
+	c, ok := r.clusters[key]
+	if !ok {
+		c = &RecordedCluster{ConfigID: configID, Cluster: cluster, EnabledAt: time.Now()}
+		r.clusters[key] = c
+	}
+	snapshot := *c
+	r.startWatcher(key, configID, cluster)
+	r.mu.Unlock()
+
+	if !ok {
+		if err := r.documents.Put(clustersCollection, key, &snapshot); err != nil {
+			return nil, fmt.Errorf("failed to persist event history cluster: %w", err)
+		}
+	}
+	return &snapshot, nil
+}
+
+// Disable stops recording the events of a cluster; events already recorded are kept until they expire
+func (r *Recorder) Disable(configID, cluster string) error {
+	key := storage.ClusterKey(configID, cluster)
+	r.mu.Lock()
+	delete(r.clusters, key)
+	if cancel, ok := r.watchers[key]; ok {
+		cancel()
+		delete(r.watchers, key)
+	}
+	r.mu.Unlock()
+	if err := r.documents.Delete(clustersCollection, key); err != nil {
+		return fmt.Errorf("failed to delete event history cluster: %w", err)
+	}
+	return nil
+}
+
+// Clusters returns the clusters whose events are recorded
+func (r *Recorder) Clusters() []RecordedCluster {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]RecordedCluster, 0, len(r.clusters))
+	for _, c := range r.clusters {
+		result = append(result, *c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return storage.ClusterKey(result[i].ConfigID, result[i].Cluster) < storage.ClusterKey(result[j].ConfigID, result[j].Cluster)
+	})
+	return result
+}
+
+// Query returns recorded events matching the filter, most recently seen first, and the total number of matches
+func (r *Recorder) Query(filter types.EventFilter) ([]*types.StoredEvent, int, error) {
+	if r.db != nil {
+		return r.db.QueryEvents(filter)
+	}
+
+	r.mu.RLock()
+	var matched []*types.StoredEvent
+	for _, e := range r.memory {
+		if filter.Matches(e) {
+			copied := *e
+			matched = append(matched, &copied)
+		}
+	}
+	r.mu.RUnlock()
+
+	sortByLastSeen(matched)
+	total := len(matched)
+	if filter.Offset >= total {
+		return []*types.StoredEvent{}, total, nil
+	}
+	matched = matched[filter.Offset:]
+	if filter.Limit > 0 && len(matched) > filter.Limit {
+		matched = matched[:filter.Limit]
+	}
+	return matched, total, nil
+}
+
+// sortByLastSeen orders events most recently seen first
+func sortByLastSeen(events []*types.StoredEvent) {
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].LastSeen.Equal(events[j].LastSeen) {
+			return events[i].LastSeen.After(events[j].LastSeen)
+		}
+		return events[i].ID < events[j].ID
+	})
+}
+
+// record persists a batch of events and notes when the cluster last saw one
+func (r *Recorder) record(configID, cluster string, events []*types.StoredEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	if r.db != nil {
+		if err := r.db.StoreEvents(events); err != nil {
+			return err
+		}
+	} else {
+		r.mu.Lock()
+		for _, e := range events {
+			if existing, ok := r.memory[e.ID]; ok && existing.FirstSeen.Before(e.FirstSeen) {
+				e.FirstSeen = existing.FirstSeen
+			}
+			r.memory[e.ID] = e
+		}
+		r.mu.Unlock()
+		r.trimMemory()
+	}
+
+	now := time.Now()
+	r.mu.Lock()
+	if c, ok := r.clusters[storage.ClusterKey(configID, cluster)]; ok {
+		c.LastEventAt = &now
+		c.LastError = ""
+	}
+	r.mu.Unlock()
+	return nil
+}
+
+// trimMemory drops the least recently seen events once the in-memory store exceeds its bound
+func (r *Recorder) trimMemory() {
+	limit := r.config.MaxMemoryEvents
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if limit <= 0 || len(r.memory) <= limit {
+		return
+	}
+	events := make([]*types.StoredEvent, 0, len(r.memory))
+	for _, e := range r.memory {
+		events = append(events, e)
+	}
+	sortByLastSeen(events)
+	// Trim to 90% of the bound so a busy cluster does not sort on every event
+	for _, e := range events[limit*9/10:] {
+		delete(r.memory, e.ID)
+	}
+}
+
+// prune deletes events last seen before the retention window
+func (r *Recorder) prune() {
+	if r.config.RetentionHours <= 0 {
+		return
+	}
+	cutoff := time.Now().Add(-time.Duration(r.config.RetentionHours) * time.Hour)
+	if r.db != nil {
+		if err := r.db.DeleteExpiredEvents(cutoff); err != nil {
+			r.logger.WithError(err).Warn("Failed to prune event history")
+		}
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, e := range r.memory {
+		if e.LastSeen.Before(cutoff) {
+			delete(r.memory, id)
+		}
+	}
+}
+
+func (r *Recorder) getClient(configID, cluster string) (kubernetes.Interface, error) {
+	cfg, err := r.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, err
+	}
+	return r.clientFactory.GetClientForConfig(cfg, cluster)
+}
+
+// watchEvents records the events of a cluster until ctx is cancelled, re-establishing the watch on failure
+func (r *Recorder) watchEvents(ctx context.Context, configID, cluster string) {
+	log := r.logger.WithField("config", configID).WithField("cluster", cluster)
+	for {
+		if _, err := r.store.GetKubeConfig(configID); err != nil {
+			// The kubeconfig was removed; stop recording but keep the history
+			log.Info("Kubeconfig removed, stopping event history recording")
+			if err := r.Disable(configID, cluster); err != nil {
+				log.WithError(err).Warn("Failed to disable event history recording")
+			}
+			return
+		}
+		if err := r.watchEventsOnce(ctx, configID, cluster); err != nil && ctx.Err() == nil {
+			log.WithError(err).Warn("Event history watch failed, retrying")
+			r.mu.Lock()
+			if c, ok := r.clusters[storage.ClusterKey(configID, cluster)]; ok {
+				c.LastError = err.Error()
+			}
+			r.mu.Unlock()
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(watchRetryWait):
+		}
+	}
+}
+
+func (r *Recorder) watchEventsOnce(ctx context.Context, configID, cluster string) error {
+	client, err := r.getClient(configID, cluster)
+	if err != nil {
+		return err
+	}
+	// Record the events the cluster still holds, then follow changes from that point
+	list, err := client.CoreV1().Events("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	initial := make([]*types.StoredEvent, 0, len(list.Items))
+	for i := range list.Items {
+		initial = append(initial, ToStoredEvent(configID, cluster, &list.Items[i]))
+	}
+	if err := r.record(configID, cluster, initial); err != nil {
+		return fmt.Errorf("failed to store events: %w", err)
+	}
+
+	w, err := client.CoreV1().Events("").Watch(ctx, metav1.ListOptions{ResourceVersion: list.ResourceVersion})
+	if err != nil {
+		return err
+	}
+	defer w.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-w.ResultChan():
+			if !ok {
+				return fmt.Errorf("watch closed")
+			}
+			if ev.Type == watch.Error {
+				return fmt.Errorf("watch error: %v", ev.Object)
+			}
+			// Deletions are the cluster's TTL expiring events, which is exactly what we keep
+			if ev.Type != watch.Added && ev.Type != watch.Modified {
+				continue
+			}
+			if event, ok := ev.Object.(*v1.Event); ok {
+				if err := r.record(configID, cluster, []*types.StoredEvent{ToStoredEvent(configID, cluster, event)}); err != nil {
+					return fmt.Errorf("failed to store event: %w", err)
+				}
+			}
+		}
+	}
+}
+
+// ToStoredEvent converts a core event into its stored form, normalising the
+// timestamps and counts that differ between legacy and events.k8s.io reporters
+func ToStoredEvent(configID, cluster string, event *v1.Event) *types.StoredEvent {
+	lastSeen := event.CreationTimestamp.Time
+	switch {
+	case event.Series != nil && !event.Series.LastObservedTime.IsZero():
+		lastSeen = event.Series.LastObservedTime.Time
+	case !event.LastTimestamp.IsZero():
+		lastSeen = event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		lastSeen = event.EventTime.Time
+	}
+	firstSeen := lastSeen
+	switch {
+	case !event.FirstTimestamp.IsZero():
+		firstSeen = event.FirstTimestamp.Time
+	case !event.EventTime.IsZero():
+		firstSeen = event.EventTime.Time
+	}
+
+	count := event.Count
+	if event.Series != nil && event.Series.Count > count {
+		count = event.Series.Count
+	}
+	if count < 1 {
+		count = 1
+	}
+
+	source := event.Source.Component
+	if source == "" {
+		source = event.ReportingController
+	}
+
+	return &types.StoredEvent{
+		ID:           storage.ClusterKey(configID, cluster) + "|" + string(event.UID),
+		ConfigID:     configID,
+		Cluster:      cluster,
+		Namespace:    event.Namespace,
+		Name:         event.Name,
+		Type:         event.Type,
+		Reason:       event.Reason,
+		Message:      event.Message,
+		InvolvedKind: event.InvolvedObject.Kind,
+		InvolvedName: event.InvolvedObject.Name,
+		Source:       source,
+		Count:        count,
+		FirstSeen:    firstSeen.UTC(),
+		LastSeen:     lastSeen.UTC(),
+	}
+}
@@ -0,0 +1,101 @@
+package eventhistory
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/config"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/internal/types"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newMemoryRecorder(cfg *config.EventHistoryConfig) *Recorder {
+	return NewRecorder(nil, nil, storage.NewDocumentStore(nil), nil, logger.New("error"), cfg)
+}
+
+func TestToStoredEvent(t *testing.T) {
+	first := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	last := first.Add(30 * time.Minute)
+	event := &v1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "web.17c", Namespace: "shop", UID: "abc"},
+		InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "web-0"},
+		Reason:         "BackOff",
+		Type:           v1.EventTypeWarning,
+		Count:          7,
+		FirstTimestamp: metav1.NewTime(first),
+		LastTimestamp:  metav1.NewTime(last),
+		Source:         v1.EventSource{Component: "kubelet"},
+	}
+	got := ToStoredEvent("cfg", "prod", event)
+	if got.ID != "cfg|prod|abc" || got.InvolvedKind != "Pod" || got.InvolvedName != "web-0" || got.Source != "kubelet" {
+		t.Errorf("unexpected event identity: %+v", got)
+	}
+	if got.Count != 7 || !got.FirstSeen.Equal(first) || !got.LastSeen.Equal(last) {
+		t.Errorf("count/timestamps = %d %s %s", got.Count, got.FirstSeen, got.LastSeen)
+	}
+
+	// events.k8s.io reporters set eventTime and a series instead of the legacy fields
+	observed := last.Add(time.Hour)
+	series := &v1.Event{
+		ObjectMeta:          metav1.ObjectMeta{UID: "def"},
+		EventTime:           metav1.NewMicroTime(first),
+		Series:              &v1.EventSeries{Count: 3, LastObservedTime: metav1.NewMicroTime(observed)},
+		ReportingController: "karpenter",
+	}
+	got = ToStoredEvent("cfg", "", series)
+	if got.Count != 3 || !got.FirstSeen.Equal(first) || !got.LastSeen.Equal(observed) || got.Source != "karpenter" {
+		t.Errorf("series event = %+v", got)
+	}
+}
+
+func TestMemoryQueryAndRetention(t *testing.T) {
+	r := newMemoryRecorder(&config.EventHistoryConfig{RetentionHours: 24, MaxMemoryEvents: 100})
+	now := time.Now().UTC()
+	events := []*types.StoredEvent{
+		{ID: "1", ConfigID: "cfg", Namespace: "shop", InvolvedKind: "Pod", Reason: "BackOff", LastSeen: now.Add(-time.Minute)},
+		{ID: "2", ConfigID: "cfg", Namespace: "shop", InvolvedKind: "Pod", Reason: "Killing", LastSeen: now},
+		{ID: "3", ConfigID: "cfg", Namespace: "infra", InvolvedKind: "Node", Reason: "BackOff", LastSeen: now.Add(-time.Hour)},
+		{ID: "4", ConfigID: "cfg", Namespace: "shop", InvolvedKind: "Pod", Reason: "BackOff", LastSeen: now.Add(-48 * time.Hour)},
+	}
+	if err := r.record("cfg", "", events); err != nil {
+		t.Fatal(err)
+	}
+
+	got, total, err := r.Query(types.EventFilter{ConfigID: "cfg", Namespace: "shop", InvolvedKind: "Pod"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 3 || len(got) != 3 || got[0].ID != "2" || got[1].ID != "1" || got[2].ID != "4" {
+		t.Errorf("query returned %d of %d, want 2,1,4 newest first", len(got), total)
+	}
+
+	got, total, _ = r.Query(types.EventFilter{ConfigID: "cfg", Reason: "BackOff", Limit: 1, Offset: 1})
+	if total != 3 || len(got) != 1 || got[0].ID != "3" {
+		t.Errorf("paged query = %v (total %d), want event 3", got, total)
+	}
+
+	r.prune()
+	if _, total, _ := r.Query(types.EventFilter{ConfigID: "cfg"}); total != 3 {
+		t.Errorf("after prune total = %d, want 3", total)
+	}
+}
+
+func TestMemoryBound(t *testing.T) {
+	r := newMemoryRecorder(&config.EventHistoryConfig{MaxMemoryEvents: 10})
+	now := time.Now()
+	for i := 0; i < 11; i++ {
+		e := &types.StoredEvent{ID: fmt.Sprintf("%02d", i), ConfigID: "cfg", LastSeen: now.Add(time.Duration(i) * time.Second)}
+		if err := r.record("cfg", "", []*types.StoredEvent{e}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, total, _ := r.Query(types.EventFilter{ConfigID: "cfg"})
+	if total != 9 || got[0].ID != "10" || got[len(got)-1].ID != "02" {
+		t.Errorf("after trimming kept %d events (%s..%s), want the 9 most recent", total, got[0].ID, got[len(got)-1].ID)
+	}
+}
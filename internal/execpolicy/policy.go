@@ -0,0 +1,169 @@
+package execpolicy
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// Rule effects
+const (
+	EffectAllow = "allow"
+	EffectDeny  = "deny"
+)
+
+// Rule allows or denies pod exec for matching commands and images within a scope.
+// Empty scope and selector fields match everything; in patterns * matches any
+// sequence of characters (including /) and ? matches one character.
+type Rule struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Enabled     bool      `json:"enabled"`
+	Effect      string    `json:"effect"` // allow or deny
+	ConfigID    string    `json:"configId,omitempty"`
+	Cluster     string    `json:"cluster,omitempty"`
+	Namespaces  []string  `json:"namespaces,omitempty"`
+	Commands    []string  `json:"commands,omitempty"` // matched against the full command and the executable name
+	Images      []string  `json:"images,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// Request describes an exec attempt
+type Request struct {
+	ConfigID  string `json:"configId"`
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+	Image     string `json:"image"`
+	Command   string `json:"command"`
+}
+
+// Decision is the result of evaluating a request
+type Decision struct {
+	Allowed  bool   `json:"allowed"`
+	Reason   string `json:"reason"`
+	RuleID   string `json:"ruleId,omitempty"`
+	RuleName string `json:"ruleName,omitempty"`
+}
+
+// Validate checks that a rule is well formed
+func (r *Rule) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if r.Effect != EffectAllow && r.Effect != EffectDeny {
+		return fmt.Errorf("effect must be %s or %s", EffectAllow, EffectDeny)
+	}
+	for _, patterns := range [][]string{r.Namespaces, r.Commands, r.Images} {
+		for _, p := range patterns {
+			if strings.TrimSpace(p) == "" {
+				return fmt.Errorf("patterns must not be empty")
+			}
+		}
+	}
+	return nil
+}
+
+// inScope reports whether the rule applies to the request's cluster and namespace
+func (r *Rule) inScope(req Request) bool {
+	if !r.Enabled {
+		return false
+	}
+	if r.ConfigID != "" && r.ConfigID != req.ConfigID {
+		return false
+	}
+	if r.Cluster != "" && r.Cluster != req.Cluster {
+		return false
+	}
+	return len(r.Namespaces) == 0 || matchAny(r.Namespaces, req.Namespace)
+}
+
+// matches reports whether the rule's command and image selectors match the request
+func (r *Rule) matches(req Request) bool {
+	if len(r.Commands) > 0 {
+		executable := ""
+		if fields := strings.Fields(req.Command); len(fields) > 0 {
+			executable = path.Base(fields[0])
+		}
+		if !matchAny(r.Commands, req.Command) && !matchAny(r.Commands, executable) {
+			return false
+		}
+	}
+	return len(r.Images) == 0 || matchAny(r.Images, req.Image)
+}
+
+// Evaluate decides whether an exec request is allowed. A matching deny rule
+// always wins. When allow rules are in scope the request must match one of them
+// (allowlist). Namespaces in denyNamespaces are denied unless an allow rule matches.
+func Evaluate(rules []Rule, denyNamespaces []string, req Request) Decision {
+	var allowInScope bool
+	var allowed *Rule
+	for i := range rules {
+		rule := &rules[i]
+		if !rule.inScope(req) {
+			continue
+		}
+		if rule.Effect == EffectDeny {
+			if rule.matches(req) {
+				return Decision{Reason: fmt.Sprintf("denied by policy %q", rule.Name), RuleID: rule.ID, RuleName: rule.Name}
+			}
+			continue
+		}
+		allowInScope = true
+		if allowed == nil && rule.matches(req) {
+			allowed = rule
+		}
+	}
+
+	if allowed != nil {
+		return Decision{Allowed: true, Reason: fmt.Sprintf("allowed by policy %q", allowed.Name), RuleID: allowed.ID, RuleName: allowed.Name}
+	}
+	if allowInScope {
+		return Decision{Reason: fmt.Sprintf("command %q in image %q is not in the exec allowlist for namespace %s", req.Command, req.Image, req.Namespace)}
+	}
+	if matchAny(denyNamespaces, req.Namespace) {
+		return Decision{Reason: fmt.Sprintf("exec into namespace %s is denied by default", req.Namespace)}
+	}
+	return Decision{Allowed: true, Reason: "no policy restricts this exec"}
+}
+
+// matchAny reports whether value matches any of the patterns
+func matchAny(patterns []string, value string) bool {
+	for _, p := range patterns {
+		if globMatch(p, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// globMatch matches value against a pattern where * matches any sequence and ? one character
+func globMatch(pattern, value string) bool {
+	p, v := []rune(pattern), []rune(value)
+	pi, vi := 0, 0
+	star, mark := -1, 0
+	for vi < len(v) {
+		switch {
+		case pi < len(p) && (p[pi] == '?' || p[pi] == v[vi]):
+			pi++
+			vi++
+		case pi < len(p) && p[pi] == '*':
+			star, mark = pi, vi
+			pi++
+		case star >= 0:
+			pi = star + 1
+			mark++
+			vi = mark
+		default:
+			return false
+		}
+	}
+	for pi < len(p) && p[pi] == '*' {
+		pi++
+	}
+	return pi == len(p)
+}
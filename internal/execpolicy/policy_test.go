@@ -0,0 +1,46 @@
+package execpolicy
+
+import "testing"
+
+func TestEvaluate(t *testing.T) {
+	rules := []Rule{
+		{ID: "1", Name: "no-shells-in-prod", Enabled: true, Effect: EffectDeny, Namespaces: []string{"prod-*"}, Commands: []string{"sh", "bash"}},
+		{ID: "2", Name: "system-readonly", Enabled: true, Effect: EffectAllow, Namespaces: []string{"kube-system"}, Commands: []string{"cat *", "ls*"}},
+		{ID: "3", Name: "disabled", Enabled: false, Effect: EffectDeny, Commands: []string{"*"}},
+		{ID: "4", Name: "other-cluster", Enabled: true, Effect: EffectDeny, Cluster: "staging"},
+	}
+	deny := []string{"kube-system", "kube-public"}
+
+	tests := []struct {
+		name    string
+		req     Request
+		allowed bool
+		ruleID  string
+	}{
+		{"deny rule matches executable name", Request{Namespace: "prod-api", Command: "/bin/bash"}, false, "1"},
+		{"deny rule ignores other commands", Request{Namespace: "prod-api", Command: "/bin/ls"}, true, ""},
+		{"allowlist permits matching command", Request{Namespace: "kube-system", Command: "cat /etc/resolv.conf"}, true, "2"},
+		{"allowlist rejects other commands", Request{Namespace: "kube-system", Command: "/bin/sh"}, false, ""},
+		{"default deny namespace", Request{Namespace: "kube-public", Command: "/bin/sh"}, false, ""},
+		{"unrestricted namespace", Request{Namespace: "dev", Command: "/bin/sh"}, true, ""},
+		{"cluster scoped rule", Request{Cluster: "staging", Namespace: "dev", Command: "/bin/sh"}, false, "4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Evaluate(rules, deny, tt.req)
+			if d.Allowed != tt.allowed || d.RuleID != tt.ruleID {
+				t.Errorf("got allowed=%v rule=%q (%s), want allowed=%v rule=%q", d.Allowed, d.RuleID, d.Reason, tt.allowed, tt.ruleID)
+			}
+		})
+	}
+}
+
+func TestImageSelector(t *testing.T) {
+	rules := []Rule{{ID: "1", Name: "no-db-exec", Enabled: true, Effect: EffectDeny, Images: []string{"postgres:*", "*/mysql:*"}}}
+	if d := Evaluate(rules, nil, Request{Namespace: "db", Image: "docker.io/mysql:8", Command: "sh"}); d.Allowed {
+		t.Errorf("expected mysql image to be denied")
+	}
+	if d := Evaluate(rules, nil, Request{Namespace: "db", Image: "redis:7", Command: "sh"}); !d.Allowed {
+		t.Errorf("expected redis image to be allowed: %s", d.Reason)
+	}
+}
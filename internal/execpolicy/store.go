@@ -0,0 +1,114 @@
+package execpolicy
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+const rulesCollection = "exec_policies"
+
+// Store persists exec policy rules and evaluates requests against them
+type Store struct {
+	documents      *storage.DocumentStore
+	denyNamespaces []string
+	logger         *logger.Logger
+
+	mu    sync.RWMutex
+	rules []Rule
+}
+
+// NewStore creates a policy store; denyNamespaces are denied unless a rule allows them
+func NewStore(documents *storage.DocumentStore, denyNamespaces []string, log *logger.Logger) *Store {
+	s := &Store{
+		documents:      documents,
+		denyNamespaces: denyNamespaces,
+		logger:         log,
+	}
+	if err := s.reload(); err != nil {
+		log.WithError(err).Error("Failed to load exec policies")
+	}
+	return s
+}
+
+// DenyNamespaces returns the namespaces denied by default
+func (s *Store) DenyNamespaces() []string {
+	return s.denyNamespaces
+}
+
+// Evaluate decides whether an exec request is allowed by the current rules
+func (s *Store) Evaluate(req Request) Decision {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return Evaluate(s.rules, s.denyNamespaces, req)
+}
+
+// ListRules returns all rules sorted by name
+func (s *Store) ListRules() []Rule {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]Rule{}, s.rules...)
+}
+
+// GetRule returns a rule by ID
+func (s *Store) GetRule(id string) (*Rule, error) {
+	var rule Rule
+	if err := s.documents.Get(rulesCollection, id, &rule); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// SaveRule validates and stores a rule, assigning an ID to new rules
+func (s *Store) SaveRule(rule *Rule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	now := time.Now()
+	if rule.ID == "" {
+		rule.ID = uuid.New().String()
+		rule.CreatedAt = now
+	}
+	rule.UpdatedAt = now
+	if err := s.documents.Put(rulesCollection, rule.ID, rule); err != nil {
+		return err
+	}
+	return s.reload()
+}
+
+// DeleteRule removes a rule
+func (s *Store) DeleteRule(id string) error {
+	if err := s.documents.Delete(rulesCollection, id); err != nil {
+		return err
+	}
+	return s.reload()
+}
+
+// reload refreshes the in-memory rule cache used on the exec path
+func (s *Store) reload() error {
+	docs, err := s.documents.List(rulesCollection)
+	if err != nil {
+		return err
+	}
+	rules := make([]Rule, 0, len(docs))
+	for id, data := range docs {
+		var rule Rule
+		if err := json.Unmarshal(data, &rule); err != nil {
+			s.logger.WithError(err).WithField("rule", id).Error("Skipping unreadable exec policy")
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Name < rules[j].Name })
+
+	s.mu.Lock()
+	s.rules = rules
+	s.mu.Unlock()
+	return nil
+}
@@ -3,40 +3,82 @@ package k8s
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/Facets-cloud/kube-dash/internal/config"
 	"github.com/Facets-cloud/kube-dash/internal/tracing"
 
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
 	metricsclient "k8s.io/metrics/pkg/client/clientset/versioned"
 )
 
+// protobufContentType is the wire format built-in Kubernetes types support besides JSON
+const protobufContentType = "application/vnd.kubernetes.protobuf"
+
 // ClientFactory manages Kubernetes client instances
 type ClientFactory struct {
 	mu            sync.RWMutex
-	clients       map[string]*kubernetes.Clientset
+	clients       map[string]kubernetes.Interface
 	metrics       map[string]*metricsclient.Clientset
+	dynamic       map[string]dynamic.Interface
+	metadata      map[string]metadata.Interface
 	tracingHelper *tracing.TracingHelper
+
+	protobuf           bool
+	jsonClusters       map[string]bool
+	disableCompression bool
+
+	discoveryMu  sync.Mutex
+	discovery    map[string]*discoveryEntry
+	discoveryTTL time.Duration
+}
+
+// NewClientFactory creates a new client factory. Typed clients talk protobuf unless the config
+// falls back to JSON for all or some clusters.
+func NewClientFactory(cfg *config.K8sConfig) *ClientFactory {
+	f := &ClientFactory{
+		clients:            make(map[string]kubernetes.Interface),
+		metrics:            make(map[string]*metricsclient.Clientset),
+		dynamic:            make(map[string]dynamic.Interface),
+		metadata:           make(map[string]metadata.Interface),
+		tracingHelper:      tracing.GetTracingHelper(),
+		protobuf:           !strings.EqualFold(cfg.APIContentType, "json"),
+		jsonClusters:       make(map[string]bool),
+		disableCompression: cfg.DisableCompression,
+		discovery:          make(map[string]*discoveryEntry),
+		discoveryTTL:       time.Duration(cfg.DiscoveryCacheTTLSeconds) * time.Second,
+	}
+	for _, cluster := range cfg.JSONClusters {
+		f.jsonClusters[cluster] = true
+	}
+	return f
 }
 
-// NewClientFactory creates a new client factory
-func NewClientFactory() *ClientFactory {
-	return &ClientFactory{
-		clients:       make(map[string]*kubernetes.Clientset),
-		metrics:       make(map[string]*metricsclient.Clientset),
-		tracingHelper: tracing.GetTracingHelper(),
+// configureTransport sets the wire format and compression of a REST config. Only typed clients
+// can decode protobuf; dynamic, metadata and metrics clients keep JSON.
+func (f *ClientFactory) configureTransport(restConfig *rest.Config, clusterName string, typed bool) {
+	restConfig.DisableCompression = f.disableCompression
+	if typed && f.protobuf && !f.jsonClusters[clusterName] {
+		// JSON stays acceptable for aggregated APIs and raw requests that cannot answer in protobuf
+		restConfig.AcceptContentTypes = protobufContentType + ",application/json"
+		restConfig.ContentType = protobufContentType
 	}
 }
 
 // GetClientForConfig returns a Kubernetes client for a specific config and cluster
-func (f *ClientFactory) GetClientForConfig(config *api.Config, clusterName string) (*kubernetes.Clientset, error) {
+func (f *ClientFactory) GetClientForConfig(config *api.Config, clusterName string) (kubernetes.Interface, error) {
 	return f.GetClientForConfigWithContext(context.Background(), config, clusterName)
 }
 
 // GetClientForConfigWithContext returns a Kubernetes client for a specific config and cluster with tracing context
-func (f *ClientFactory) GetClientForConfigWithContext(ctx context.Context, config *api.Config, clusterName string) (*kubernetes.Clientset, error) {
+func (f *ClientFactory) GetClientForConfigWithContext(ctx context.Context, config *api.Config, clusterName string) (kubernetes.Interface, error) {
 	// Start child span for client creation
 	ctx, clientSpan := f.tracingHelper.StartAuthSpan(ctx, "create-k8s-client")
 	defer clientSpan.End()
@@ -98,6 +140,7 @@ func (f *ClientFactory) GetClientForConfigWithContext(ctx context.Context, confi
 		f.tracingHelper.RecordError(clientSpan, err, "Failed to create client config")
 		return nil, fmt.Errorf("failed to create client config: %w", err)
 	}
+	f.configureTransport(restConfig, clusterName, true)
 	f.tracingHelper.AddResourceAttributes(restConfigSpan, restConfig.Host, "k8s-host", 1)
 	f.tracingHelper.RecordSuccess(restConfigSpan, fmt.Sprintf("Created REST config for host: %s", restConfig.Host))
 
@@ -129,16 +172,47 @@ func (f *ClientFactory) GetClientForConfigWithContext(ctx context.Context, confi
 }
 
 // GetClientForConfigID returns a Kubernetes client for a config ID and cluster
-func (f *ClientFactory) GetClientForConfigID(config *api.Config, configID, clusterName string) (*kubernetes.Clientset, error) {
+func (f *ClientFactory) GetClientForConfigID(config *api.Config, configID, clusterName string) (kubernetes.Interface, error) {
 	return f.GetClientForConfig(config, clusterName)
 }
 
+// InjectedClients are clients a ClientFactory serves for a config and cluster instead of building
+// them from the kubeconfig; nil clients are still built on demand
+type InjectedClients struct {
+	Typed    kubernetes.Interface
+	Dynamic  dynamic.Interface
+	Metadata metadata.Interface
+}
+
+// InjectClients caches clients for a config and cluster, so handlers built on this factory talk to
+// them. Tests use it to serve handlers from fake clientsets; see the testharness package.
+func (f *ClientFactory) InjectClients(config *api.Config, clusterName string, clients InjectedClients) {
+	key := fmt.Sprintf("%p-%s", config, clusterName)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if clients.Typed != nil {
+		f.clients[key] = clients.Typed
+	}
+	if clients.Dynamic != nil {
+		f.dynamic[key] = clients.Dynamic
+	}
+	if clients.Metadata != nil {
+		f.metadata[key] = clients.Metadata
+	}
+}
+
 // ClearClients clears all cached clients
 func (f *ClientFactory) ClearClients() {
 	f.mu.Lock()
 	defer f.mu.Unlock()
-	f.clients = make(map[string]*kubernetes.Clientset)
+	f.clients = make(map[string]kubernetes.Interface)
 	f.metrics = make(map[string]*metricsclient.Clientset)
+	f.dynamic = make(map[string]dynamic.Interface)
+	f.metadata = make(map[string]metadata.Interface)
+
+	f.discoveryMu.Lock()
+	f.discovery = make(map[string]*discoveryEntry)
+	f.discoveryMu.Unlock()
 }
 
 // RemoveClient removes a specific client from cache
@@ -148,6 +222,40 @@ func (f *ClientFactory) RemoveClient(config *api.Config, clusterName string) {
 	defer f.mu.Unlock()
 	delete(f.clients, key)
 	delete(f.metrics, key)
+	delete(f.dynamic, key)
+	delete(f.metadata, key)
+
+	f.discoveryMu.Lock()
+	delete(f.discovery, key)
+	f.discoveryMu.Unlock()
+}
+
+// RemoveClients removes the cached clients of several clusters of a config and returns how many
+// of the clusters had any
+func (f *ClientFactory) RemoveClients(config *api.Config, clusterNames []string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.discoveryMu.Lock()
+	defer f.discoveryMu.Unlock()
+
+	removed := 0
+	for _, clusterName := range clusterNames {
+		key := fmt.Sprintf("%p-%s", config, clusterName)
+		_, typed := f.clients[key]
+		_, metrics := f.metrics[key]
+		_, dyn := f.dynamic[key]
+		_, meta := f.metadata[key]
+		_, disc := f.discovery[key]
+		if typed || metrics || dyn || meta || disc {
+			removed++
+		}
+		delete(f.clients, key)
+		delete(f.metrics, key)
+		delete(f.dynamic, key)
+		delete(f.metadata, key)
+		delete(f.discovery, key)
+	}
+	return removed
 }
 
 // GetMetricsClientForConfig returns a Metrics client for a specific config and cluster
@@ -182,6 +290,7 @@ func (f *ClientFactory) GetMetricsClientForConfig(config *api.Config, clusterNam
 		return nil, fmt.Errorf("failed to create client config: %w", err)
 	}
 
+	f.configureTransport(restConfig, clusterName, false)
 	metricsClient, err := metricsclient.NewForConfig(restConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Metrics client: %w", err)
@@ -193,3 +302,93 @@ func (f *ClientFactory) GetMetricsClientForConfig(config *api.Config, clusterNam
 
 	return metricsClient, nil
 }
+
+// GetDynamicClientForConfig returns a dynamic client for a specific config and cluster
+func (f *ClientFactory) GetDynamicClientForConfig(config *api.Config, clusterName string) (dynamic.Interface, error) {
+	key := fmt.Sprintf("%p-%s", config, clusterName)
+
+	f.mu.RLock()
+	if client, exists := f.dynamic[key]; exists {
+		f.mu.RUnlock()
+		return client, nil
+	}
+	f.mu.RUnlock()
+
+	// Create a copy of the config and set the context to the specific cluster
+	configCopy := config.DeepCopy()
+	for contextName, context := range configCopy.Contexts {
+		if context.Cluster == clusterName {
+			configCopy.CurrentContext = contextName
+			break
+		}
+	}
+	if configCopy.CurrentContext == "" && len(configCopy.Contexts) > 0 {
+		for contextName := range configCopy.Contexts {
+			configCopy.CurrentContext = contextName
+			break
+		}
+	}
+
+	clientConfig := clientcmd.NewDefaultClientConfig(*configCopy, &clientcmd.ConfigOverrides{})
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client config: %w", err)
+	}
+
+	f.configureTransport(restConfig, clusterName, false)
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	f.mu.Lock()
+	f.dynamic[key] = dynamicClient
+	f.mu.Unlock()
+
+	return dynamicClient, nil
+}
+
+// GetMetadataClientForConfig returns a client for metadata-only requests for a specific config and cluster
+func (f *ClientFactory) GetMetadataClientForConfig(config *api.Config, clusterName string) (metadata.Interface, error) {
+	key := fmt.Sprintf("%p-%s", config, clusterName)
+
+	f.mu.RLock()
+	if client, exists := f.metadata[key]; exists {
+		f.mu.RUnlock()
+		return client, nil
+	}
+	f.mu.RUnlock()
+
+	// Create a copy of the config and set the context to the specific cluster
+	configCopy := config.DeepCopy()
+	for contextName, context := range configCopy.Contexts {
+		if context.Cluster == clusterName {
+			configCopy.CurrentContext = contextName
+			break
+		}
+	}
+	if configCopy.CurrentContext == "" && len(configCopy.Contexts) > 0 {
+		for contextName := range configCopy.Contexts {
+			configCopy.CurrentContext = contextName
+			break
+		}
+	}
+
+	clientConfig := clientcmd.NewDefaultClientConfig(*configCopy, &clientcmd.ConfigOverrides{})
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client config: %w", err)
+	}
+
+	f.configureTransport(restConfig, clusterName, false)
+	metadataClient, err := metadata.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metadata client: %w", err)
+	}
+
+	f.mu.Lock()
+	f.metadata[key] = metadataClient
+	f.mu.Unlock()
+
+	return metadataClient, nil
+}
@@ -0,0 +1,92 @@
+package k8s
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/config"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestConfigureTransport(t *testing.T) {
+	f := NewClientFactory(&config.K8sConfig{APIContentType: "protobuf", JSONClusters: []string{"legacy"}})
+
+	typed := &rest.Config{}
+	f.configureTransport(typed, "prod", true)
+	if typed.ContentType != protobufContentType || typed.AcceptContentTypes != protobufContentType+",application/json" {
+		t.Errorf("typed client should talk protobuf, got %q / %q", typed.ContentType, typed.AcceptContentTypes)
+	}
+	for name, cluster := range map[string]struct {
+		name  string
+		typed bool
+	}{
+		"dynamic client": {"prod", false},
+		"JSON cluster":   {"legacy", true},
+	} {
+		restConfig := &rest.Config{}
+		f.configureTransport(restConfig, cluster.name, cluster.typed)
+		if restConfig.ContentType != "" || restConfig.AcceptContentTypes != "" {
+			t.Errorf("%s should keep JSON, got %q", name, restConfig.ContentType)
+		}
+	}
+
+	jsonOnly := NewClientFactory(&config.K8sConfig{APIContentType: "JSON", DisableCompression: true})
+	restConfig := &rest.Config{}
+	jsonOnly.configureTransport(restConfig, "prod", true)
+	if restConfig.ContentType != "" || !restConfig.DisableCompression {
+		t.Errorf("JSON fallback ignored: %+v", restConfig)
+	}
+}
+
+func TestDiscoveryCache(t *testing.T) {
+	f := NewClientFactory(&config.K8sConfig{DiscoveryCacheTTLSeconds: 60})
+	clientset := fake.NewSimpleClientset()
+	fakeDiscovery := clientset.Discovery().(*fakediscovery.FakeDiscovery)
+	fakeDiscovery.Resources = []*metav1.APIResourceList{{
+		GroupVersion: "v1",
+		APIResources: []metav1.APIResource{{Name: "pods", Kind: "Pod", Namespaced: true}},
+	}}
+
+	kubeconfig := api.NewConfig()
+	key := fmt.Sprintf("%p-%s", kubeconfig, "prod")
+	entry := f.storeDiscovery(key, fakeDiscovery)
+	if again := f.storeDiscovery(key, fakeDiscovery); again != entry {
+		t.Fatal("a second store should keep the cached entry")
+	}
+	if _, err := entry.mapper.RESTMapping(schema.GroupKind{Kind: "Pod"}); err != nil {
+		t.Fatalf("Pod should map: %v", err)
+	}
+
+	// A CRD installed later is only seen once the cache is reset
+	fakeDiscovery.Resources = append(fakeDiscovery.Resources, &metav1.APIResourceList{
+		GroupVersion: "example.com/v1",
+		APIResources: []metav1.APIResource{{Name: "widgets", Kind: "Widget", Namespaced: true}},
+	})
+	widget := schema.GroupKind{Group: "example.com", Kind: "Widget"}
+	entry.resetAt = time.Now().Add(-2 * time.Minute)
+	if got := f.cachedDiscovery(key); got != entry {
+		t.Fatal("an expired entry should be reset in place")
+	}
+	if _, err := entry.mapper.RESTMapping(widget); err != nil {
+		t.Fatalf("Widget should map after the TTL reset: %v", err)
+	}
+
+	f.storeDiscovery(fmt.Sprintf("%p-%s", kubeconfig, "staging"), fakeDiscovery)
+	f.storeDiscovery(fmt.Sprintf("%p-%s", api.NewConfig(), "prod"), fakeDiscovery)
+	if n := f.InvalidateDiscovery(kubeconfig, "prod"); n != 1 {
+		t.Errorf("expected 1 cluster invalidated, got %d", n)
+	}
+	if n := f.InvalidateDiscovery(kubeconfig, ""); n != 1 {
+		t.Errorf("expected the remaining cluster of the config invalidated, got %d", n)
+	}
+	if len(f.discovery) != 1 {
+		t.Errorf("other configs should keep their cache, got %d entries", len(f.discovery))
+	}
+}
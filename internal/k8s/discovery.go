@@ -0,0 +1,88 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// discoveryEntry is a cluster's cached API discovery and the REST mapper built on it
+type discoveryEntry struct {
+	discovery discovery.CachedDiscoveryInterface
+	mapper    *restmapper.DeferredDiscoveryRESTMapper
+	resetAt   time.Time
+}
+
+// GetRESTMapperForConfig returns the REST mapper and cached discovery client shared by all
+// requests for a config and cluster. Discovery is refetched lazily once the cache TTL has passed
+// or after InvalidateDiscovery, so new CRDs show up without rebuilding mappers per request.
+func (f *ClientFactory) GetRESTMapperForConfig(config *api.Config, clusterName string) (meta.RESTMapper, discovery.CachedDiscoveryInterface, error) {
+	key := fmt.Sprintf("%p-%s", config, clusterName)
+	if entry := f.cachedDiscovery(key); entry != nil {
+		return entry.mapper, entry.discovery, nil
+	}
+
+	client, err := f.GetClientForConfig(config, clusterName)
+	if err != nil {
+		return nil, nil, err
+	}
+	entry := f.storeDiscovery(key, client.Discovery())
+	return entry.mapper, entry.discovery, nil
+}
+
+// cachedDiscovery returns the cached entry for a key, resetting it when it is older than the TTL
+func (f *ClientFactory) cachedDiscovery(key string) *discoveryEntry {
+	f.discoveryMu.Lock()
+	defer f.discoveryMu.Unlock()
+	entry, ok := f.discovery[key]
+	if !ok {
+		return nil
+	}
+	if f.discoveryTTL > 0 && time.Since(entry.resetAt) > f.discoveryTTL {
+		entry.mapper.Reset()
+		entry.resetAt = time.Now()
+	}
+	return entry
+}
+
+// storeDiscovery caches discovery for a key, keeping an entry another request stored first
+func (f *ClientFactory) storeDiscovery(key string, client discovery.DiscoveryInterface) *discoveryEntry {
+	f.discoveryMu.Lock()
+	defer f.discoveryMu.Unlock()
+	if entry, ok := f.discovery[key]; ok {
+		return entry
+	}
+	cached := memory.NewMemCacheClient(client)
+	entry := &discoveryEntry{
+		discovery: cached,
+		mapper:    restmapper.NewDeferredDiscoveryRESTMapper(cached),
+		resetAt:   time.Now(),
+	}
+	f.discovery[key] = entry
+	return entry
+}
+
+// InvalidateDiscovery drops the cached discovery of a cluster, or of every cluster of the config
+// when clusterName is empty, and returns the number of caches dropped. Mappers already handed out
+// refetch discovery on their next lookup.
+func (f *ClientFactory) InvalidateDiscovery(config *api.Config, clusterName string) int {
+	prefix := fmt.Sprintf("%p-", config)
+	f.discoveryMu.Lock()
+	defer f.discoveryMu.Unlock()
+	invalidated := 0
+	for key, entry := range f.discovery {
+		if !strings.HasPrefix(key, prefix) || (clusterName != "" && key != prefix+clusterName) {
+			continue
+		}
+		entry.mapper.Reset()
+		delete(f.discovery, key)
+		invalidated++
+	}
+	return invalidated
+}
@@ -0,0 +1,66 @@
+package k8s
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+// Resources holds the scheduler-relevant quantities of a pod or node
+type Resources struct {
+	MilliCPU    int64 `json:"milliCPU"`
+	MemoryBytes int64 `json:"memoryBytes"`
+	Pods        int64 `json:"pods"`
+}
+
+// Add returns the sum of both amounts
+func (r Resources) Add(o Resources) Resources {
+	return Resources{MilliCPU: r.MilliCPU + o.MilliCPU, MemoryBytes: r.MemoryBytes + o.MemoryBytes, Pods: r.Pods + o.Pods}
+}
+
+// NodeAllocatable returns the resources a node offers to pods
+func NodeAllocatable(node *v1.Node) Resources {
+	return Resources{
+		MilliCPU:    node.Status.Allocatable.Cpu().MilliValue(),
+		MemoryBytes: node.Status.Allocatable.Memory().Value(),
+		Pods:        node.Status.Allocatable.Pods().Value(),
+	}
+}
+
+// PodRequests returns the effective requests of a pod spec as the scheduler computes them.
+// Restartable (sidecar) init containers run for the pod's whole life, so they add to the app
+// containers and to every init container started after them. The result is the larger of the
+// app containers plus sidecars and the busiest init phase, plus pod overhead.
+func PodRequests(spec *v1.PodSpec) Resources {
+	var app, sidecars, init Resources
+	for _, c := range spec.Containers {
+		app.MilliCPU += c.Resources.Requests.Cpu().MilliValue()
+		app.MemoryBytes += c.Resources.Requests.Memory().Value()
+	}
+	for _, c := range spec.InitContainers {
+		req := Resources{MilliCPU: c.Resources.Requests.Cpu().MilliValue(), MemoryBytes: c.Resources.Requests.Memory().Value()}
+		if c.RestartPolicy != nil && *c.RestartPolicy == v1.ContainerRestartPolicyAlways {
+			sidecars = sidecars.Add(req)
+			req = sidecars
+		} else {
+			req = req.Add(sidecars)
+		}
+		init.MilliCPU = max(init.MilliCPU, req.MilliCPU)
+		init.MemoryBytes = max(init.MemoryBytes, req.MemoryBytes)
+	}
+	total := app.Add(sidecars)
+	total.MilliCPU = max(total.MilliCPU, init.MilliCPU)
+	total.MemoryBytes = max(total.MemoryBytes, init.MemoryBytes)
+	if spec.Overhead != nil {
+		total.MilliCPU += spec.Overhead.Cpu().MilliValue()
+		total.MemoryBytes += spec.Overhead.Memory().Value()
+	}
+	total.Pods = 1
+	return total
+}
+
+// PackingPercent is requested over allocatable as a percentage, or 0 when nothing is allocatable
+func PackingPercent(requested, allocatable int64) float64 {
+	if allocatable == 0 {
+		return 0
+	}
+	return float64(requested) / float64(allocatable) * 100
+}
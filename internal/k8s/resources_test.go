@@ -0,0 +1,55 @@
+package k8s
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func requesting(cpu, memory string) v1.Container {
+	return v1.Container{Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse(cpu),
+		v1.ResourceMemory: resource.MustParse(memory),
+	}}}
+}
+
+func sidecar(cpu, memory string) v1.Container {
+	c := requesting(cpu, memory)
+	always := v1.ContainerRestartPolicyAlways
+	c.RestartPolicy = &always
+	return c
+}
+
+func TestPodRequests(t *testing.T) {
+	cases := []struct {
+		name      string
+		spec      v1.PodSpec
+		milliCPU  int64
+		memoryMiB int64
+	}{
+		{"app containers are summed", v1.PodSpec{Containers: []v1.Container{requesting("100m", "64Mi"), requesting("200m", "64Mi")}}, 300, 128},
+		{"init container larger than app", v1.PodSpec{
+			InitContainers: []v1.Container{requesting("500m", "32Mi")},
+			Containers:     []v1.Container{requesting("100m", "64Mi")},
+		}, 500, 64},
+		{"sidecar adds to app containers", v1.PodSpec{
+			InitContainers: []v1.Container{sidecar("50m", "16Mi")},
+			Containers:     []v1.Container{requesting("100m", "64Mi")},
+		}, 150, 80},
+		{"sidecar adds to later init containers only", v1.PodSpec{
+			InitContainers: []v1.Container{requesting("400m", "16Mi"), sidecar("100m", "16Mi"), requesting("350m", "16Mi")},
+			Containers:     []v1.Container{requesting("100m", "64Mi")},
+		}, 450, 80},
+		{"overhead is added", v1.PodSpec{
+			Containers: []v1.Container{requesting("100m", "64Mi")},
+			Overhead:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("10m"), v1.ResourceMemory: resource.MustParse("8Mi")},
+		}, 110, 72},
+	}
+	for _, tc := range cases {
+		got := PodRequests(&tc.spec)
+		if got.MilliCPU != tc.milliCPU || got.MemoryBytes != tc.memoryMiB<<20 || got.Pods != 1 {
+			t.Errorf("%s: got %+v, want %dm CPU and %dMi memory", tc.name, got, tc.milliCPU, tc.memoryMiB)
+		}
+	}
+}
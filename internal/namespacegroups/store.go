@@ -0,0 +1,142 @@
+package namespacegroups
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/google/uuid"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// groupsCollection is the document collection holding namespace groups
+const groupsCollection = "namespace_groups"
+
+// maxNamespaces bounds the namespaces one group fans list requests out to
+const maxNamespaces = 50
+
+// Group is a saved set of namespaces that list endpoints can be filtered by
+type Group struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Owner      string    `json:"owner,omitempty"` // empty for groups shared with everyone
+	ConfigID   string    `json:"configId"`
+	Cluster    string    `json:"cluster,omitempty"`
+	Namespaces []string  `json:"namespaces"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// Validate checks that a group can be saved and normalizes its namespace list
+func (g *Group) Validate() error {
+	if strings.TrimSpace(g.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if g.ConfigID == "" {
+		return fmt.Errorf("configId is required")
+	}
+	seen := map[string]bool{}
+	var namespaces []string
+	for _, ns := range g.Namespaces {
+		ns = strings.TrimSpace(ns)
+		if ns == "" || seen[ns] {
+			continue
+		}
+		if errs := validation.IsDNS1123Label(ns); len(errs) > 0 {
+			return fmt.Errorf("invalid namespace %q: %s", ns, strings.Join(errs, "; "))
+		}
+		seen[ns] = true
+		namespaces = append(namespaces, ns)
+	}
+	if len(namespaces) == 0 {
+		return fmt.Errorf("at least one namespace is required")
+	}
+	if len(namespaces) > maxNamespaces {
+		return fmt.Errorf("a group may hold at most %d namespaces", maxNamespaces)
+	}
+	sort.Strings(namespaces)
+	g.Namespaces = namespaces
+	return nil
+}
+
+// Filter narrows the groups returned by List; empty fields match everything
+type Filter struct {
+	Owner    string // also matches shared groups, which have no owner
+	ConfigID string
+	Cluster  string
+}
+
+// Store persists namespace groups
+type Store struct {
+	documents *storage.DocumentStore
+	logger    *logger.Logger
+}
+
+// NewStore creates a namespace group store
+func NewStore(documents *storage.DocumentStore, log *logger.Logger) *Store {
+	return &Store{
+		documents: documents,
+		logger:    log,
+	}
+}
+
+// List returns the groups matching the filter sorted by name
+func (s *Store) List(filter Filter) ([]Group, error) {
+	docs, err := s.documents.List(groupsCollection)
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]Group, 0, len(docs))
+	for id, data := range docs {
+		var g Group
+		if err := json.Unmarshal(data, &g); err != nil {
+			s.logger.WithError(err).WithField("group", id).Error("Skipping unreadable namespace group")
+			continue
+		}
+		if filter.Owner != "" && g.Owner != "" && g.Owner != filter.Owner {
+			continue
+		}
+		if filter.ConfigID != "" && g.ConfigID != filter.ConfigID {
+			continue
+		}
+		if filter.Cluster != "" && g.Cluster != filter.Cluster {
+			continue
+		}
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups, nil
+}
+
+// Get returns a single group
+func (s *Store) Get(id string) (*Group, error) {
+	var g Group
+	if err := s.documents.Get(groupsCollection, id, &g); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// Save validates and persists a group, assigning an ID to new groups
+func (s *Store) Save(g *Group) error {
+	if err := g.Validate(); err != nil {
+		return err
+	}
+	now := time.Now()
+	if g.ID == "" {
+		g.ID = uuid.New().String()
+		g.CreatedAt = now
+	}
+	g.UpdatedAt = now
+	return s.documents.Put(groupsCollection, g.ID, g)
+}
+
+// Delete removes a group
+func (s *Store) Delete(id string) error {
+	return s.documents.Delete(groupsCollection, id)
+}
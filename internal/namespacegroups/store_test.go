@@ -0,0 +1,34 @@
+package namespacegroups
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestGroupValidate(t *testing.T) {
+	g := Group{Name: "checkout", ConfigID: "cfg", Namespaces: []string{"shop", " payments ", "", "shop"}}
+	if err := g.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"payments", "shop"}; !reflect.DeepEqual(g.Namespaces, want) {
+		t.Errorf("namespaces = %v, want %v", g.Namespaces, want)
+	}
+
+	tooMany := make([]string, maxNamespaces+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("ns-%d", i)
+	}
+	invalid := []Group{
+		{ConfigID: "cfg", Namespaces: []string{"shop"}},
+		{Name: "checkout", Namespaces: []string{"shop"}},
+		{Name: "checkout", ConfigID: "cfg"},
+		{Name: "checkout", ConfigID: "cfg", Namespaces: []string{"Shop_Prod"}},
+		{Name: "checkout", ConfigID: "cfg", Namespaces: tooMany},
+	}
+	for i, g := range invalid {
+		if err := g.Validate(); err == nil {
+			t.Errorf("case %d: expected a validation error", i)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package namespaceprefs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// prefsCollection is the document collection holding default namespace preferences
+const prefsCollection = "namespace_preferences"
+
+// Preference is the namespace a user opens by default on one cluster
+type Preference struct {
+	Owner     string    `json:"owner,omitempty"` // empty for callers that do not identify themselves
+	ConfigID  string    `json:"configId"`
+	Cluster   string    `json:"cluster,omitempty"`
+	Namespace string    `json:"namespace"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Validate checks that a preference can be saved
+func (p *Preference) Validate() error {
+	if p.ConfigID == "" {
+		return fmt.Errorf("configId is required")
+	}
+	p.Namespace = strings.TrimSpace(p.Namespace)
+	if p.Namespace == "" {
+		return fmt.Errorf("namespace is required")
+	}
+	if errs := validation.IsDNS1123Label(p.Namespace); len(errs) > 0 {
+		return fmt.Errorf("invalid namespace %q: %s", p.Namespace, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// preferenceID derives the document ID of a user's preference on a cluster, so there is at most
+// one per user and cluster
+func preferenceID(owner, configID, cluster string) string {
+	sum := sha256.Sum256([]byte(owner + "\x00" + configID + "\x00" + cluster))
+	return hex.EncodeToString(sum[:16])
+}
+
+// Store persists default namespace preferences
+type Store struct {
+	documents *storage.DocumentStore
+	logger    *logger.Logger
+}
+
+// NewStore creates a namespace preference store
+func NewStore(documents *storage.DocumentStore, log *logger.Logger) *Store {
+	return &Store{
+		documents: documents,
+		logger:    log,
+	}
+}
+
+// Get returns a user's preference on a cluster
+func (s *Store) Get(owner, configID, cluster string) (*Preference, error) {
+	var p Preference
+	if err := s.documents.Get(prefsCollection, preferenceID(owner, configID, cluster), &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// List returns a user's preferences, optionally only those of one kubeconfig, sorted by config and cluster
+func (s *Store) List(owner, configID string) ([]Preference, error) {
+	docs, err := s.documents.List(prefsCollection)
+	if err != nil {
+		return nil, err
+	}
+	prefs := []Preference{}
+	for id, data := range docs {
+		var p Preference
+		if err := json.Unmarshal(data, &p); err != nil {
+			s.logger.WithError(err).WithField("preference", id).Error("Skipping unreadable namespace preference")
+			continue
+		}
+		if p.Owner != owner || (configID != "" && p.ConfigID != configID) {
+			continue
+		}
+		prefs = append(prefs, p)
+	}
+	sort.Slice(prefs, func(i, j int) bool {
+		if prefs[i].ConfigID != prefs[j].ConfigID {
+			return prefs[i].ConfigID < prefs[j].ConfigID
+		}
+		return prefs[i].Cluster < prefs[j].Cluster
+	})
+	return prefs, nil
+}
+
+// Set validates and stores a preference, replacing the user's previous one on the cluster
+func (s *Store) Set(p *Preference) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	p.UpdatedAt = time.Now()
+	return s.documents.Put(prefsCollection, preferenceID(p.Owner, p.ConfigID, p.Cluster), p)
+}
+
+// Delete removes a user's preference on a cluster
+func (s *Store) Delete(owner, configID, cluster string) error {
+	return s.documents.Delete(prefsCollection, preferenceID(owner, configID, cluster))
+}
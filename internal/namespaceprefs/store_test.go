@@ -0,0 +1,28 @@
+package namespaceprefs
+
+import "testing"
+
+func TestPreferenceValidate(t *testing.T) {
+	p := Preference{ConfigID: "cfg", Namespace: " shop "}
+	if err := p.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if p.Namespace != "shop" {
+		t.Errorf("namespace = %q, want shop", p.Namespace)
+	}
+
+	invalid := []Preference{
+		{Namespace: "shop"},
+		{ConfigID: "cfg"},
+		{ConfigID: "cfg", Namespace: "Shop_Prod"},
+	}
+	for i, p := range invalid {
+		if err := p.Validate(); err == nil {
+			t.Errorf("case %d: expected a validation error", i)
+		}
+	}
+
+	if preferenceID("alice", "cfg", "prod") == preferenceID("alice", "cfg", "staging") {
+		t.Error("preferenceID() collided across clusters")
+	}
+}
@@ -0,0 +1,130 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strings"
+
+	"github.com/Facets-cloud/kube-dash/internal/config"
+)
+
+// sender delivers notifications to Slack, generic webhooks and email
+type sender struct {
+	httpClient *http.Client
+	smtp       *config.SMTPConfig
+}
+
+// send delivers a notification to a single channel
+func (s *sender) send(ctx context.Context, ch Channel, n *Notification) error {
+	switch ch.Type {
+	case ChannelSlack:
+		return s.postJSON(ctx, ch.URL, nil, map[string]string{"text": formatText(n)})
+	case ChannelWebhook:
+		return s.postJSON(ctx, ch.URL, ch.Headers, n)
+	case ChannelEmail:
+		return s.sendEmail(ch.To, n)
+	}
+	return fmt.Errorf("unsupported channel type %q", ch.Type)
+}
+
+func (s *sender) postJSON(ctx context.Context, target string, headers map[string]string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+func (s *sender) sendEmail(to []string, n *Notification) error {
+	if s.smtp == nil || s.smtp.Host == "" {
+		return fmt.Errorf("SMTP is not configured")
+	}
+	from := s.smtp.From
+	if from == "" {
+		from = s.smtp.Username
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: [kube-dash] %s\r\n", formatTitle(n))
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(formatText(n))
+	msg.WriteString("\r\n")
+
+	var auth smtp.Auth
+	if s.smtp.Username != "" {
+		auth = smtp.PlainAuth("", s.smtp.Username, s.smtp.Password, s.smtp.Host)
+	}
+	addr := fmt.Sprintf("%s:%d", s.smtp.Host, s.smtp.Port)
+	return smtp.SendMail(addr, auth, from, to, []byte(msg.String()))
+}
+
+// channelTarget describes a channel destination without leaking webhook secrets
+func channelTarget(ch Channel) string {
+	if ch.Type == ChannelEmail {
+		return strings.Join(ch.To, ", ")
+	}
+	if u, err := url.Parse(ch.URL); err == nil {
+		return u.Scheme + "://" + u.Host
+	}
+	return ""
+}
+
+func formatTitle(n *Notification) string {
+	subject := n.Name
+	if n.Namespace != "" && n.Name != "" {
+		subject = n.Namespace + "/" + n.Name
+	}
+	title := n.RuleName
+	if n.Reason != "" {
+		title += ": " + n.Reason
+	}
+	if subject != "" {
+		title += " (" + subject + ")"
+	}
+	return title
+}
+
+func formatText(n *Notification) string {
+	var b strings.Builder
+	b.WriteString(formatTitle(n))
+	fmt.Fprintf(&b, "\nCluster: %s", n.Cluster)
+	if n.Kind != "" {
+		fmt.Fprintf(&b, "\nKind: %s", n.Kind)
+	}
+	if n.Severity != "" {
+		fmt.Fprintf(&b, "\nSeverity: %s", n.Severity)
+	}
+	if n.Value != nil {
+		fmt.Fprintf(&b, "\nValue: %g", *n.Value)
+	}
+	if n.Message != "" {
+		fmt.Fprintf(&b, "\n%s", n.Message)
+	}
+	return b.String()
+}
@@ -0,0 +1,603 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/handlers/metrics"
+	"github.com/Facets-cloud/kube-dash/internal/config"
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/google/uuid"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+)
+
+// rulesCollection is the document collection holding notification rules
+const rulesCollection = "notification_rules"
+
+const (
+	pollInterval   = time.Minute
+	maxDeliveries  = 500
+	watchRetryWait = 10 * time.Second
+)
+
+// Engine evaluates notification rules against cluster events and Prometheus and delivers matches
+type Engine struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	documents     *storage.DocumentStore
+	prometheus    metrics.PrometheusQuerier
+	logger        *logger.Logger
+	sender        *sender
+
+	mu       sync.RWMutex
+	rules    []Rule
+	watchers map[string]context.CancelFunc // event watchers keyed by configID|cluster
+	lastSent map[string]time.Time          // ruleID|condition key -> last delivery
+	firing   map[string]map[string]bool    // ruleID -> condition keys currently firing
+
+	historyMu  sync.RWMutex
+	deliveries []Delivery
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewEngine creates a notification engine; call Start to begin evaluating rules
+func NewEngine(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, documents *storage.DocumentStore, prometheus metrics.PrometheusQuerier, log *logger.Logger, smtpCfg *config.SMTPConfig) *Engine {
+	return &Engine{
+		store:         store,
+		clientFactory: clientFactory,
+		documents:     documents,
+		prometheus:    prometheus,
+		logger:        log,
+		sender:        &sender{httpClient: &http.Client{Timeout: 10 * time.Second}, smtp: smtpCfg},
+		watchers:      make(map[string]context.CancelFunc),
+		lastSent:      make(map[string]time.Time),
+		firing:        make(map[string]map[string]bool),
+	}
+}
+
+// Start loads rules, starts event watchers and the alert/metric poll loop
+func (e *Engine) Start() {
+	e.ctx, e.cancel = context.WithCancel(context.Background())
+	if err := e.Reload(); err != nil {
+		e.logger.WithError(err).Error("Failed to load notification rules")
+	}
+	go e.pollLoop()
+}
+
+// Stop cancels all watchers and the poll loop
+func (e *Engine) Stop() {
+	if e.cancel != nil {
+		e.cancel()
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for key, cancel := range e.watchers {
+		cancel()
+		delete(e.watchers, key)
+	}
+}
+
+// ListRules returns all persisted rules sorted by name
+func (e *Engine) ListRules() ([]Rule, error) {
+	docs, err := e.documents.List(rulesCollection)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]Rule, 0, len(docs))
+	for id, data := range docs {
+		var rule Rule
+		if err := json.Unmarshal(data, &rule); err != nil {
+			e.logger.WithError(err).WithField("rule", id).Error("Skipping unreadable notification rule")
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Name < rules[j].Name })
+	return rules, nil
+}
+
+// GetRule returns a single rule
+func (e *Engine) GetRule(id string) (*Rule, error) {
+	var rule Rule
+	if err := e.documents.Get(rulesCollection, id, &rule); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// SaveRule validates and persists a rule, assigning an ID to new rules
+func (e *Engine) SaveRule(rule *Rule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	now := time.Now()
+	if rule.ID == "" {
+		rule.ID = uuid.New().String()
+		rule.CreatedAt = now
+	}
+	rule.UpdatedAt = now
+	if err := e.documents.Put(rulesCollection, rule.ID, rule); err != nil {
+		return err
+	}
+	return e.Reload()
+}
+
+// DeleteRule removes a rule
+func (e *Engine) DeleteRule(id string) error {
+	if err := e.documents.Delete(rulesCollection, id); err != nil {
+		return err
+	}
+	e.mu.Lock()
+	delete(e.firing, id)
+	e.mu.Unlock()
+	return e.Reload()
+}
+
+// MuteRule suppresses deliveries for a rule until the given time; a zero time unmutes
+func (e *Engine) MuteRule(id string, until time.Time) (*Rule, error) {
+	rule, err := e.GetRule(id)
+	if err != nil {
+		return nil, err
+	}
+	if until.IsZero() {
+		rule.MutedUntil = nil
+	} else {
+		rule.MutedUntil = &until
+	}
+	if err := e.SaveRule(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// TestRule sends a synthetic notification to every channel of the rule
+func (e *Engine) TestRule(ctx context.Context, rule *Rule) []Delivery {
+	n := Notification{
+		RuleID:    rule.ID,
+		RuleName:  rule.Name,
+		Source:    rule.Source,
+		ConfigID:  rule.ConfigID,
+		Cluster:   rule.Cluster,
+		Reason:    "Test",
+		Message:   "This is a test notification from kube-dash.",
+		Timestamp: time.Now(),
+	}
+	return e.deliver(ctx, rule, &n)
+}
+
+// Send delivers a notification to the given channels outside of rule evaluation and records the deliveries
+func (e *Engine) Send(ctx context.Context, channels []Channel, n *Notification) []Delivery {
+	rule := Rule{ID: n.RuleID, Name: n.RuleName, Channels: channels}
+	return e.deliver(ctx, &rule, n)
+}
+
+// Deliveries returns the most recent deliveries, newest first, optionally filtered by rule
+func (e *Engine) Deliveries(ruleID string, limit int) []Delivery {
+	e.historyMu.RLock()
+	defer e.historyMu.RUnlock()
+
+	result := make([]Delivery, 0)
+	for i := len(e.deliveries) - 1; i >= 0; i-- {
+		if ruleID != "" && e.deliveries[i].RuleID != ruleID {
+			continue
+		}
+		result = append(result, e.deliveries[i])
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+	}
+	return result
+}
+
+// Reload re-reads rules and reconciles event watchers with the clusters that need them
+func (e *Engine) Reload() error {
+	rules, err := e.ListRules()
+	if err != nil {
+		return err
+	}
+
+	needed := make(map[string]Rule)
+	for _, r := range rules {
+		if r.Enabled && r.Source == SourceEvent {
+			needed[storage.ClusterKey(r.ConfigID, r.Cluster)] = r
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.rules = rules
+	if e.ctx == nil {
+		return nil
+	}
+	for key, cancel := range e.watchers {
+		if _, ok := needed[key]; !ok {
+			cancel()
+			delete(e.watchers, key)
+		}
+	}
+	for key, r := range needed {
+		if _, ok := e.watchers[key]; ok {
+			continue
+		}
+		ctx, cancel := context.WithCancel(e.ctx)
+		e.watchers[key] = cancel
+		go e.watchEvents(ctx, r.ConfigID, r.Cluster)
+	}
+	return nil
+}
+
+// rulesFor returns enabled rules of a source for one cluster
+func (e *Engine) rulesFor(source, configID, cluster string) []Rule {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	var result []Rule
+	for _, r := range e.rules {
+		if r.Enabled && r.Source == source && r.ConfigID == configID && r.Cluster == cluster {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+func (e *Engine) getClient(configID, cluster string) (kubernetes.Interface, error) {
+	cfg, err := e.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, err
+	}
+	return e.clientFactory.GetClientForConfig(cfg, cluster)
+}
+
+// watchEvents streams core events for a cluster until ctx is cancelled, re-establishing the watch on failure
+func (e *Engine) watchEvents(ctx context.Context, configID, cluster string) {
+	log := e.logger.WithField("config", configID).WithField("cluster", cluster)
+	for {
+		if err := e.watchEventsOnce(ctx, configID, cluster); err != nil && ctx.Err() == nil {
+			log.WithError(err).Warn("Notification event watch failed, retrying")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(watchRetryWait):
+		}
+	}
+}
+
+func (e *Engine) watchEventsOnce(ctx context.Context, configID, cluster string) error {
+	client, err := e.getClient(configID, cluster)
+	if err != nil {
+		return err
+	}
+	// Start from the current resource version so historical events are not replayed
+	list, err := client.CoreV1().Events("").List(ctx, metav1.ListOptions{Limit: 1})
+	if err != nil {
+		return err
+	}
+	w, err := client.CoreV1().Events("").Watch(ctx, metav1.ListOptions{ResourceVersion: list.ResourceVersion})
+	if err != nil {
+		return err
+	}
+	defer w.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-w.ResultChan():
+			if !ok {
+				return fmt.Errorf("watch closed")
+			}
+			if ev.Type == watch.Error {
+				return fmt.Errorf("watch error: %v", ev.Object)
+			}
+			if ev.Type != watch.Added && ev.Type != watch.Modified {
+				continue
+			}
+			if event, ok := ev.Object.(*v1.Event); ok {
+				e.handleEvent(ctx, configID, cluster, event)
+			}
+		}
+	}
+}
+
+func (e *Engine) handleEvent(ctx context.Context, configID, cluster string, event *v1.Event) {
+	for _, rule := range e.rulesFor(SourceEvent, configID, cluster) {
+		n := Notification{
+			RuleID:    rule.ID,
+			RuleName:  rule.Name,
+			Source:    SourceEvent,
+			ConfigID:  configID,
+			Cluster:   cluster,
+			Namespace: event.InvolvedObject.Namespace,
+			Kind:      event.InvolvedObject.Kind,
+			Name:      event.InvolvedObject.Name,
+			Reason:    event.Reason,
+			Severity:  event.Type,
+			Message:   event.Message,
+			Timestamp: eventTime(event),
+		}
+		if !rule.Match.matches(&n, "") {
+			continue
+		}
+		n.key = strings.Join([]string{n.Namespace, n.Kind, n.Name, n.Reason}, "/")
+		e.notify(ctx, &rule, &n)
+	}
+}
+
+func eventTime(event *v1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	}
+	return time.Now()
+}
+
+// pollLoop periodically evaluates alert and metric rules
+func (e *Engine) pollLoop() {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-e.ctx.Done():
+			return
+		case <-ticker.C:
+			e.poll()
+		}
+	}
+}
+
+func (e *Engine) poll() {
+	e.mu.RLock()
+	var rules []Rule
+	for _, r := range e.rules {
+		if r.Enabled && (r.Source == SourceAlert || r.Source == SourceMetric) {
+			rules = append(rules, r)
+		}
+	}
+	e.mu.RUnlock()
+
+	for i := range rules {
+		ctx, cancel := context.WithTimeout(e.ctx, 30*time.Second)
+		var (
+			candidates []Notification
+			err        error
+		)
+		if rules[i].Source == SourceAlert {
+			candidates, err = e.evaluateAlerts(ctx, &rules[i])
+		} else {
+			candidates, err = e.evaluateMetric(ctx, &rules[i])
+		}
+		if err != nil {
+			e.logger.WithError(err).WithField("rule", rules[i].Name).Warn("Failed to evaluate notification rule")
+		} else {
+			e.notifyTransitions(ctx, &rules[i], candidates)
+		}
+		cancel()
+	}
+}
+
+func (e *Engine) queryPrometheus(ctx context.Context, rule *Rule, path string, params map[string]string) ([]byte, error) {
+	if e.prometheus == nil {
+		return nil, fmt.Errorf("prometheus querier not configured")
+	}
+	client, err := e.getClient(rule.ConfigID, rule.Cluster)
+	if err != nil {
+		return nil, err
+	}
+	return e.prometheus.Query(ctx, client, storage.ClusterKey(rule.ConfigID, rule.Cluster), path, params)
+}
+
+// evaluateAlerts returns a notification for every firing Prometheus alert matching the rule
+func (e *Engine) evaluateAlerts(ctx context.Context, rule *Rule) ([]Notification, error) {
+	raw, err := e.queryPrometheus(ctx, rule, "/api/v1/alerts", nil)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Data struct {
+			Alerts []struct {
+				Labels      map[string]string `json:"labels"`
+				Annotations map[string]string `json:"annotations"`
+				State       string            `json:"state"`
+				ActiveAt    time.Time         `json:"activeAt"`
+			} `json:"alerts"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse alerts: %w", err)
+	}
+
+	var result []Notification
+	for _, alert := range resp.Data.Alerts {
+		if alert.State != "firing" {
+			continue
+		}
+		message := alert.Annotations["summary"]
+		if message == "" {
+			message = alert.Annotations["description"]
+		}
+		if message == "" {
+			message = alert.Annotations["message"]
+		}
+		n := Notification{
+			RuleID:    rule.ID,
+			RuleName:  rule.Name,
+			Source:    SourceAlert,
+			ConfigID:  rule.ConfigID,
+			Cluster:   rule.Cluster,
+			Namespace: alert.Labels["namespace"],
+			Name:      firstNonEmpty(alert.Labels["pod"], alert.Labels["deployment"], alert.Labels["node"], alert.Labels["instance"]),
+			Reason:    alert.Labels["alertname"],
+			Severity:  alert.Labels["severity"],
+			Message:   message,
+			Timestamp: alert.ActiveAt,
+			key:       labelsKey(alert.Labels),
+		}
+		if rule.Match.matches(&n, alert.Labels["alertname"]) {
+			result = append(result, n)
+		}
+	}
+	return result, nil
+}
+
+// evaluateMetric returns a notification for every series of the rule's query that breaches the threshold
+func (e *Engine) evaluateMetric(ctx context.Context, rule *Rule) ([]Notification, error) {
+	raw, err := e.queryPrometheus(ctx, rule, "/api/v1/query", map[string]string{"query": rule.Metric.Query})
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Data struct {
+			Result []struct {
+				Metric map[string]string `json:"metric"`
+				Value  []interface{}     `json:"value"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse query result: %w", err)
+	}
+
+	var result []Notification
+	for _, sample := range resp.Data.Result {
+		if len(sample.Value) != 2 {
+			continue
+		}
+		str, _ := sample.Value[1].(string)
+		value, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			continue
+		}
+		if breached, _ := compare(rule.Metric.Operator, value, rule.Metric.Threshold); !breached {
+			continue
+		}
+		v := value
+		n := Notification{
+			RuleID:    rule.ID,
+			RuleName:  rule.Name,
+			Source:    SourceMetric,
+			ConfigID:  rule.ConfigID,
+			Cluster:   rule.Cluster,
+			Namespace: sample.Metric["namespace"],
+			Name:      firstNonEmpty(sample.Metric["pod"], sample.Metric["deployment"], sample.Metric["node"], sample.Metric["instance"]),
+			Reason:    "ThresholdExceeded",
+			Message:   fmt.Sprintf("%s is %g (threshold %s %g)", rule.Metric.Query, value, rule.Metric.Operator, rule.Metric.Threshold),
+			Value:     &v,
+			Timestamp: time.Now(),
+			key:       labelsKey(sample.Metric),
+		}
+		if rule.Match.matches(&n, "") {
+			result = append(result, n)
+		}
+	}
+	return result, nil
+}
+
+// notifyTransitions notifies only for conditions that were not firing on the previous poll
+func (e *Engine) notifyTransitions(ctx context.Context, rule *Rule, candidates []Notification) {
+	current := make(map[string]bool, len(candidates))
+	e.mu.Lock()
+	previous := e.firing[rule.ID]
+	for _, n := range candidates {
+		current[n.key] = true
+	}
+	e.firing[rule.ID] = current
+	e.mu.Unlock()
+
+	for i := range candidates {
+		if previous[candidates[i].key] {
+			continue
+		}
+		e.notify(ctx, rule, &candidates[i])
+	}
+}
+
+// notify delivers a notification unless the rule is muted or the condition is cooling down
+func (e *Engine) notify(ctx context.Context, rule *Rule, n *Notification) {
+	now := time.Now()
+	if rule.isMuted(now) {
+		return
+	}
+	key := rule.ID + "|" + n.key
+	e.mu.Lock()
+	if last, ok := e.lastSent[key]; ok && now.Sub(last) < rule.cooldown() {
+		e.mu.Unlock()
+		return
+	}
+	e.lastSent[key] = now
+	e.mu.Unlock()
+
+	e.deliver(ctx, rule, n)
+}
+
+func (e *Engine) deliver(ctx context.Context, rule *Rule, n *Notification) []Delivery {
+	deliveries := make([]Delivery, 0, len(rule.Channels))
+	for _, ch := range rule.Channels {
+		d := Delivery{
+			ID:           uuid.New().String(),
+			RuleID:       rule.ID,
+			RuleName:     rule.Name,
+			Channel:      ch.Type,
+			Target:       channelTarget(ch),
+			Status:       "sent",
+			Notification: *n,
+			Timestamp:    time.Now(),
+		}
+		if err := e.sender.send(ctx, ch, n); err != nil {
+			d.Status = "failed"
+			d.Error = err.Error()
+			e.logger.WithError(err).WithField("rule", rule.Name).WithField("channel", ch.Type).Warn("Notification delivery failed")
+		}
+		deliveries = append(deliveries, d)
+	}
+	e.recordDeliveries(deliveries)
+	return deliveries
+}
+
+func (e *Engine) recordDeliveries(deliveries []Delivery) {
+	e.historyMu.Lock()
+	defer e.historyMu.Unlock()
+	e.deliveries = append(e.deliveries, deliveries...)
+	if overflow := len(e.deliveries) - maxDeliveries; overflow > 0 {
+		e.deliveries = append([]Delivery(nil), e.deliveries[overflow:]...)
+	}
+}
+
+// labelsKey builds a stable identity from a Prometheus label set
+func labelsKey(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+"="+labels[k])
+	}
+	return strings.Join(parts, ",")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
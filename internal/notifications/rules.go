@@ -0,0 +1,217 @@
+package notifications
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Rule sources
+const (
+	SourceEvent  = "event"
+	SourceAlert  = "alert"
+	SourceMetric = "metric"
+)
+
+// Channel types
+const (
+	ChannelSlack   = "slack"
+	ChannelWebhook = "webhook"
+	ChannelEmail   = "email"
+)
+
+// defaultCooldown is applied when a rule does not set its own cooldown
+const defaultCooldown = 5 * time.Minute
+
+// Rule routes matching cluster events, Prometheus alerts or metric thresholds to channels
+type Rule struct {
+	ID              string           `json:"id"`
+	Name            string           `json:"name"`
+	Enabled         bool             `json:"enabled"`
+	ConfigID        string           `json:"configId"`
+	Cluster         string           `json:"cluster"`
+	Source          string           `json:"source"` // event, alert or metric
+	Match           RuleMatch        `json:"match"`
+	Metric          *MetricCondition `json:"metric,omitempty"`
+	Channels        []Channel        `json:"channels"`
+	CooldownSeconds int              `json:"cooldownSeconds,omitempty"`
+	MutedUntil      *time.Time       `json:"mutedUntil,omitempty"`
+	CreatedAt       time.Time        `json:"createdAt"`
+	UpdatedAt       time.Time        `json:"updatedAt"`
+}
+
+// RuleMatch filters the notifications a rule fires for; empty fields match everything
+type RuleMatch struct {
+	Namespaces      []string `json:"namespaces,omitempty"`
+	Kinds           []string `json:"kinds,omitempty"`      // involved object kind for events
+	Reasons         []string `json:"reasons,omitempty"`    // event reason
+	AlertNames      []string `json:"alertNames,omitempty"` // Prometheus alertname
+	Severity        string   `json:"severity,omitempty"`   // event type (Warning/Normal) or alert severity label
+	MessageContains string   `json:"messageContains,omitempty"`
+}
+
+// MetricCondition fires when any series of a PromQL instant query crosses the threshold
+type MetricCondition struct {
+	Query     string  `json:"query"`
+	Operator  string  `json:"operator"` // >, >=, <, <=, ==, !=
+	Threshold float64 `json:"threshold"`
+}
+
+// Channel is a notification destination
+type Channel struct {
+	Type    string            `json:"type"` // slack, webhook or email
+	URL     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	To      []string          `json:"to,omitempty"`
+}
+
+// Notification is the payload delivered to channels
+type Notification struct {
+	RuleID    string    `json:"ruleId"`
+	RuleName  string    `json:"ruleName"`
+	Source    string    `json:"source"`
+	ConfigID  string    `json:"configId"`
+	Cluster   string    `json:"cluster"`
+	Namespace string    `json:"namespace,omitempty"`
+	Kind      string    `json:"kind,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	Severity  string    `json:"severity,omitempty"`
+	Message   string    `json:"message"`
+	Value     *float64  `json:"value,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+
+	// Details carries structured content such as a generated report for webhook consumers
+	Details interface{} `json:"details,omitempty"`
+
+	// key identifies the underlying condition for cooldown and deduplication
+	key string
+}
+
+// Delivery records one attempt to send a notification to a channel
+type Delivery struct {
+	ID           string       `json:"id"`
+	RuleID       string       `json:"ruleId"`
+	RuleName     string       `json:"ruleName"`
+	Channel      string       `json:"channel"`
+	Target       string       `json:"target"`
+	Status       string       `json:"status"` // sent or failed
+	Error        string       `json:"error,omitempty"`
+	Notification Notification `json:"notification"`
+	Timestamp    time.Time    `json:"timestamp"`
+}
+
+// Validate checks that a rule is complete enough to be evaluated
+func (r *Rule) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if r.ConfigID == "" || r.Cluster == "" {
+		return fmt.Errorf("configId and cluster are required")
+	}
+	switch r.Source {
+	case SourceEvent, SourceAlert:
+	case SourceMetric:
+		if r.Metric == nil || r.Metric.Query == "" {
+			return fmt.Errorf("metric rules require metric.query")
+		}
+		if _, ok := compare(r.Metric.Operator, 0, 0); !ok {
+			return fmt.Errorf("unsupported metric operator %q", r.Metric.Operator)
+		}
+	default:
+		return fmt.Errorf("source must be one of event, alert, metric")
+	}
+	if len(r.Channels) == 0 {
+		return fmt.Errorf("at least one channel is required")
+	}
+	if err := ValidateChannels(r.Channels); err != nil {
+		return err
+	}
+	if r.CooldownSeconds < 0 {
+		return fmt.Errorf("cooldownSeconds must not be negative")
+	}
+	return nil
+}
+
+// ValidateChannels checks channel definitions without requiring a full rule
+func ValidateChannels(channels []Channel) error {
+	for _, ch := range channels {
+		switch ch.Type {
+		case ChannelSlack, ChannelWebhook:
+			if !strings.HasPrefix(ch.URL, "http://") && !strings.HasPrefix(ch.URL, "https://") {
+				return fmt.Errorf("%s channel requires an http(s) url", ch.Type)
+			}
+		case ChannelEmail:
+			if len(ch.To) == 0 {
+				return fmt.Errorf("email channel requires at least one recipient")
+			}
+		default:
+			return fmt.Errorf("unsupported channel type %q", ch.Type)
+		}
+	}
+	return nil
+}
+
+// cooldown returns how long to wait before notifying about the same condition again
+func (r *Rule) cooldown() time.Duration {
+	if r.CooldownSeconds > 0 {
+		return time.Duration(r.CooldownSeconds) * time.Second
+	}
+	return defaultCooldown
+}
+
+// isMuted reports whether the rule is muted at the given time
+func (r *Rule) isMuted(now time.Time) bool {
+	return r.MutedUntil != nil && now.Before(*r.MutedUntil)
+}
+
+// matches reports whether a notification candidate passes the rule's filters
+func (m *RuleMatch) matches(n *Notification, alertName string) bool {
+	if len(m.Namespaces) > 0 && !containsFold(m.Namespaces, n.Namespace) {
+		return false
+	}
+	if len(m.Kinds) > 0 && !containsFold(m.Kinds, n.Kind) {
+		return false
+	}
+	if len(m.Reasons) > 0 && !containsFold(m.Reasons, n.Reason) {
+		return false
+	}
+	if len(m.AlertNames) > 0 && !containsFold(m.AlertNames, alertName) {
+		return false
+	}
+	if m.Severity != "" && !strings.EqualFold(m.Severity, n.Severity) {
+		return false
+	}
+	if m.MessageContains != "" && !strings.Contains(strings.ToLower(n.Message), strings.ToLower(m.MessageContains)) {
+		return false
+	}
+	return true
+}
+
+// compare evaluates value <operator> threshold; ok is false for unknown operators
+func compare(operator string, value, threshold float64) (result bool, ok bool) {
+	switch operator {
+	case ">":
+		return value > threshold, true
+	case ">=":
+		return value >= threshold, true
+	case "<":
+		return value < threshold, true
+	case "<=":
+		return value <= threshold, true
+	case "==":
+		return value == threshold, true
+	case "!=":
+		return value != threshold, true
+	}
+	return false, false
+}
+
+func containsFold(values []string, v string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package nsrequests
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Request statuses
+const (
+	StatusPending      = "pending"
+	StatusProvisioning = "provisioning" // approved; the namespace is being created
+	StatusApproved     = "approved"     // the namespace was created and bootstrapped
+	StatusFailed       = "failed"       // approved, but creating or bootstrapping the namespace failed
+	StatusDenied       = "denied"
+)
+
+// Request asks for a new namespace bootstrapped from a requestable namespace template, the
+// namespace's size tier. Approving it creates the namespace.
+type Request struct {
+	ID        string `json:"id"`
+	Requester string `json:"requester"`         // API token owner or name, or the dashboard session
+	TokenID   string `json:"tokenId,omitempty"` // API token that filed the request
+	ConfigID  string `json:"configId"`
+	Cluster   string `json:"cluster,omitempty"`
+	Namespace string `json:"namespace"`
+	// Tier is the ID of the namespace template to bootstrap with; TierName is its name when filed
+	Tier      string    `json:"tier"`
+	TierName  string    `json:"tierName,omitempty"`
+	Reason    string    `json:"reason"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	DecidedAt    *time.Time `json:"decidedAt,omitempty"`
+	DecidedBy    string     `json:"decidedBy,omitempty"`
+	DecisionNote string     `json:"decisionNote,omitempty"`
+	// Outcome of provisioning: objects applied and failed, or why the namespace was not created
+	Applied int    `json:"applied,omitempty"`
+	Failed  int    `json:"failed,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Validate checks that a request is well formed
+func (r *Request) Validate() error {
+	if r.ConfigID == "" {
+		return fmt.Errorf("configId is required")
+	}
+	if errs := validation.IsDNS1123Label(r.Namespace); len(errs) > 0 {
+		return fmt.Errorf("invalid namespace name %q: %s", r.Namespace, strings.Join(errs, "; "))
+	}
+	if r.Tier == "" {
+		return fmt.Errorf("tier is required")
+	}
+	if strings.TrimSpace(r.Reason) == "" {
+		return fmt.Errorf("reason is required")
+	}
+	return nil
+}
+
+// open reports whether the request is still undecided or being provisioned, so another request
+// for the same namespace would conflict with it
+func (r *Request) open() bool {
+	return r.Status == StatusPending || r.Status == StatusProvisioning
+}
+
+// sameNamespace reports whether two requests are for the same namespace of the same cluster
+func (r *Request) sameNamespace(other *Request) bool {
+	return r.ConfigID == other.ConfigID && r.Cluster == other.Cluster && r.Namespace == other.Namespace
+}
@@ -0,0 +1,169 @@
+package nsrequests
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+const requestsCollection = "namespace_requests"
+
+// ErrNotDecidable is returned when approving or denying a request that is no longer pending.
+// Failed requests may be approved again once the cause is fixed.
+var ErrNotDecidable = errors.New("namespace request is not pending")
+
+// ErrDuplicate is returned when an open request for the same namespace already exists
+var ErrDuplicate = errors.New("an open request for this namespace already exists")
+
+// Filter narrows the requests returned by List; empty fields match everything
+type Filter struct {
+	Status    string
+	Requester string
+	ConfigID  string
+}
+
+// Store persists namespace requests
+type Store struct {
+	documents *storage.DocumentStore
+	logger    *logger.Logger
+
+	// mu serializes status transitions so one request is provisioned once
+	mu sync.Mutex
+}
+
+// NewStore creates a namespace request store
+func NewStore(documents *storage.DocumentStore, log *logger.Logger) *Store {
+	return &Store{
+		documents: documents,
+		logger:    log,
+	}
+}
+
+// Create validates and stores a new pending request
+func (s *Store) Create(req *Request) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	existing, err := s.list()
+	if err != nil {
+		return err
+	}
+	for i := range existing {
+		if existing[i].open() && existing[i].sameNamespace(req) {
+			return ErrDuplicate
+		}
+	}
+	req.ID = uuid.New().String()
+	req.Status = StatusPending
+	req.CreatedAt = time.Now()
+	req.DecidedAt, req.DecidedBy, req.DecisionNote = nil, "", ""
+	req.Applied, req.Failed, req.Error = 0, 0, ""
+	return s.documents.Put(requestsCollection, req.ID, req)
+}
+
+// Get returns a request by ID
+func (s *Store) Get(id string) (*Request, error) {
+	var req Request
+	if err := s.documents.Get(requestsCollection, id, &req); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// List returns matching requests, newest first
+func (s *Store) List(filter Filter) ([]Request, error) {
+	all, err := s.list()
+	if err != nil {
+		return nil, err
+	}
+	requests := []Request{}
+	for _, req := range all {
+		if (filter.Status != "" && req.Status != filter.Status) ||
+			(filter.Requester != "" && req.Requester != filter.Requester) ||
+			(filter.ConfigID != "" && req.ConfigID != filter.ConfigID) {
+			continue
+		}
+		requests = append(requests, req)
+	}
+	return requests, nil
+}
+
+// StartProvisioning claims a pending or failed request for an approver. Only one caller can
+// claim a request; it must then report the outcome with Complete.
+func (s *Store) StartProvisioning(id, approver, note string) (*Request, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	req, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Status != StatusPending && req.Status != StatusFailed {
+		return nil, ErrNotDecidable
+	}
+	now := time.Now()
+	req.Status = StatusProvisioning
+	req.DecidedAt, req.DecidedBy, req.DecisionNote = &now, approver, note
+	req.Applied, req.Failed, req.Error = 0, 0, ""
+	return req, s.documents.Put(requestsCollection, req.ID, req)
+}
+
+// Complete records the provisioning outcome of a claimed request: approved when the namespace
+// and all its objects were created, failed otherwise
+func (s *Store) Complete(id string, applied, failed int, provisionErr string) (*Request, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	req, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	req.Applied, req.Failed, req.Error = applied, failed, provisionErr
+	req.Status = StatusApproved
+	if failed > 0 || provisionErr != "" {
+		req.Status = StatusFailed
+	}
+	return req, s.documents.Put(requestsCollection, req.ID, req)
+}
+
+// Deny rejects a pending or failed request
+func (s *Store) Deny(id, approver, note string) (*Request, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	req, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Status != StatusPending && req.Status != StatusFailed {
+		return nil, ErrNotDecidable
+	}
+	now := time.Now()
+	req.Status = StatusDenied
+	req.DecidedAt, req.DecidedBy, req.DecisionNote = &now, approver, note
+	return req, s.documents.Put(requestsCollection, req.ID, req)
+}
+
+func (s *Store) list() ([]Request, error) {
+	docs, err := s.documents.List(requestsCollection)
+	if err != nil {
+		return nil, err
+	}
+	requests := make([]Request, 0, len(docs))
+	for id, data := range docs {
+		var req Request
+		if err := json.Unmarshal(data, &req); err != nil {
+			s.logger.WithError(err).WithField("request", id).Error("Skipping unreadable namespace request")
+			continue
+		}
+		requests = append(requests, req)
+	}
+	sort.Slice(requests, func(i, j int) bool { return requests[i].CreatedAt.After(requests[j].CreatedAt) })
+	return requests, nil
+}
@@ -0,0 +1,81 @@
+package nsrequests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+)
+
+func TestRequestValidate(t *testing.T) {
+	valid := Request{ConfigID: "c1", Namespace: "payments-dev", Tier: "small", Reason: "new service"}
+	if err := valid.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	invalid := []Request{
+		{Namespace: "payments-dev", Tier: "small", Reason: "x"},
+		{ConfigID: "c1", Namespace: "Payments_Dev", Tier: "small", Reason: "x"},
+		{ConfigID: "c1", Namespace: "payments-dev", Reason: "x"},
+		{ConfigID: "c1", Namespace: "payments-dev", Tier: "small", Reason: " "},
+	}
+	for i, req := range invalid {
+		if err := req.Validate(); err == nil {
+			t.Errorf("case %d: expected a validation error", i)
+		}
+	}
+}
+
+func TestStoreLifecycle(t *testing.T) {
+	store := NewStore(storage.NewDocumentStore(nil), logger.New("error"))
+	req := &Request{Requester: "alice", ConfigID: "c1", Namespace: "payments-dev", Tier: "small", Reason: "new service"}
+	if err := store.Create(req); err != nil {
+		t.Fatal(err)
+	}
+	if req.ID == "" || req.Status != StatusPending {
+		t.Fatalf("unexpected request %+v", req)
+	}
+	duplicate := &Request{Requester: "bob", ConfigID: "c1", Namespace: "payments-dev", Tier: "large", Reason: "mine"}
+	if err := store.Create(duplicate); !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("expected a duplicate error, got %v", err)
+	}
+	other := &Request{Requester: "bob", ConfigID: "c1", Cluster: "eu", Namespace: "payments-dev", Tier: "large", Reason: "other cluster"}
+	if err := store.Create(other); err != nil {
+		t.Fatalf("expected a request on another cluster to be accepted, got %v", err)
+	}
+
+	claimed, err := store.StartProvisioning(req.ID, "carol", "ok")
+	if err != nil || claimed.Status != StatusProvisioning || claimed.DecidedBy != "carol" {
+		t.Fatalf("StartProvisioning() = %+v, %v", claimed, err)
+	}
+	if _, err := store.StartProvisioning(req.ID, "dave", ""); !errors.Is(err, ErrNotDecidable) {
+		t.Fatalf("expected a second claim to be refused, got %v", err)
+	}
+	failed, err := store.Complete(req.ID, 1, 1, "")
+	if err != nil || failed.Status != StatusFailed {
+		t.Fatalf("Complete() = %+v, %v", failed, err)
+	}
+	if _, err := store.StartProvisioning(req.ID, "carol", "retry"); err != nil {
+		t.Fatalf("expected a failed request to be approvable again, got %v", err)
+	}
+	approved, err := store.Complete(req.ID, 3, 0, "")
+	if err != nil || approved.Status != StatusApproved || approved.Applied != 3 {
+		t.Fatalf("Complete() = %+v, %v", approved, err)
+	}
+	if _, err := store.Deny(req.ID, "carol", ""); !errors.Is(err, ErrNotDecidable) {
+		t.Errorf("expected an approved request not to be deniable, got %v", err)
+	}
+
+	denied, err := store.Deny(other.ID, "carol", "use the shared namespace")
+	if err != nil || denied.Status != StatusDenied {
+		t.Fatalf("Deny() = %+v, %v", denied, err)
+	}
+	mine, err := store.List(Filter{Requester: "bob"})
+	if err != nil || len(mine) != 1 || mine[0].ID != other.ID {
+		t.Errorf("List(requester) = %+v, %v", mine, err)
+	}
+	pending, _ := store.List(Filter{Status: StatusPending})
+	if len(pending) != 0 {
+		t.Errorf("expected no pending requests, got %+v", pending)
+	}
+}
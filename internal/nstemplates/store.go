@@ -0,0 +1,164 @@
+package nstemplates
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/google/uuid"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// templatesCollection is the document collection holding namespace templates
+const templatesCollection = "namespace_templates"
+
+// NamespacePlaceholder is replaced with the namespace name when a template is rendered, for
+// fields such as RoleBinding subjects that name the namespace
+const NamespacePlaceholder = "${NAMESPACE}"
+
+// maxResources bounds the objects one template applies
+const maxResources = 50
+
+// Template is a bundle of objects and namespace metadata applied to new namespaces, such as
+// a ResourceQuota, LimitRange, default NetworkPolicies and RBAC bindings
+type Template struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`      // set on the namespace
+	Annotations map[string]string `json:"annotations,omitempty"` // set on the namespace
+	Resources   string            `json:"resources,omitempty"`   // multi-document YAML of namespaced objects, applied in order
+	// Requestable offers the template as a size tier for self-service namespace requests
+	Requestable bool      `json:"requestable,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// Validate checks that a template can be saved and that its resources render
+func (t *Template) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	for key, value := range t.Labels {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid label key %q: %s", key, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Errorf("invalid value for label %q: %s", key, strings.Join(errs, "; "))
+		}
+	}
+	for key := range t.Annotations {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid annotation key %q: %s", key, strings.Join(errs, "; "))
+		}
+	}
+	_, err := t.Render("template-validation")
+	return err
+}
+
+// Render returns the template's objects for a namespace, with the placeholder substituted and
+// metadata.namespace set. Objects may not name a different namespace or be Namespaces themselves.
+func (t *Template) Render(namespace string) ([]*unstructured.Unstructured, error) {
+	content := strings.ReplaceAll(t.Resources, NamespacePlaceholder, namespace)
+	decoder := utilyaml.NewYAMLOrJSONDecoder(strings.NewReader(content), 4096)
+	var objects []*unstructured.Unstructured
+	for {
+		var raw map[string]interface{}
+		if err := decoder.Decode(&raw); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("failed to decode resources: %w", err)
+		}
+		if len(raw) == 0 {
+			continue
+		}
+		obj := &unstructured.Unstructured{Object: raw}
+		ref := fmt.Sprintf("resource %d", len(objects)+1)
+		if obj.GetKind() == "" || obj.GetAPIVersion() == "" || obj.GetName() == "" {
+			return nil, fmt.Errorf("%s: apiVersion, kind and metadata.name are required", ref)
+		}
+		ref = obj.GetKind() + "/" + obj.GetName()
+		if obj.GetKind() == "Namespace" {
+			return nil, fmt.Errorf("%s: namespaces are created from the template's labels and annotations", ref)
+		}
+		if ns := obj.GetNamespace(); ns != "" && ns != namespace {
+			return nil, fmt.Errorf("%s: metadata.namespace must be empty or %s", ref, NamespacePlaceholder)
+		}
+		obj.SetNamespace(namespace)
+		objects = append(objects, obj)
+	}
+	if len(objects) > maxResources {
+		return nil, fmt.Errorf("a template may hold at most %d resources", maxResources)
+	}
+	return objects, nil
+}
+
+// Store persists namespace templates
+type Store struct {
+	documents *storage.DocumentStore
+	logger    *logger.Logger
+}
+
+// NewStore creates a namespace template store
+func NewStore(documents *storage.DocumentStore, log *logger.Logger) *Store {
+	return &Store{
+		documents: documents,
+		logger:    log,
+	}
+}
+
+// List returns all templates sorted by name
+func (s *Store) List() ([]Template, error) {
+	docs, err := s.documents.List(templatesCollection)
+	if err != nil {
+		return nil, err
+	}
+	templates := make([]Template, 0, len(docs))
+	for id, data := range docs {
+		var t Template
+		if err := json.Unmarshal(data, &t); err != nil {
+			s.logger.WithError(err).WithField("template", id).Error("Skipping unreadable namespace template")
+			continue
+		}
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+// Get returns a single template
+func (s *Store) Get(id string) (*Template, error) {
+	var t Template
+	if err := s.documents.Get(templatesCollection, id, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Save validates and persists a template, assigning an ID to new templates
+func (s *Store) Save(t *Template) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	now := time.Now()
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+		t.CreatedAt = now
+	}
+	t.UpdatedAt = now
+	return s.documents.Put(templatesCollection, t.ID, t)
+}
+
+// Delete removes a template
+func (s *Store) Delete(id string) error {
+	return s.documents.Delete(templatesCollection, id)
+}
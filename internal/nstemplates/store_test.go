@@ -0,0 +1,63 @@
+package nstemplates
+
+import "testing"
+
+func TestTemplateRender(t *testing.T) {
+	tmpl := Template{
+		Name:   "team",
+		Labels: map[string]string{"team": "payments"},
+		Resources: `apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: quota
+spec:
+  hard:
+    pods: "20"
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: edit
+  namespace: ${NAMESPACE}
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: edit
+subjects:
+- kind: ServiceAccount
+  name: deployer
+  namespace: ${NAMESPACE}
+---
+`,
+	}
+	if err := tmpl.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	objects, err := tmpl.Render("shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objects) != 2 || objects[0].GetNamespace() != "shop" || objects[1].GetNamespace() != "shop" {
+		t.Fatalf("rendered objects = %v", objects)
+	}
+	subjects := objects[1].Object["subjects"].([]interface{})
+	if ns := subjects[0].(map[string]interface{})["namespace"]; ns != "shop" {
+		t.Errorf("subject namespace = %v, want shop", ns)
+	}
+}
+
+func TestTemplateValidate(t *testing.T) {
+	invalid := []Template{
+		{},
+		{Name: "bad-label", Labels: map[string]string{"bad key": "x"}},
+		{Name: "no-kind", Resources: "apiVersion: v1\nmetadata:\n  name: x\n"},
+		{Name: "namespace", Resources: "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: x\n"},
+		{Name: "other-namespace", Resources: "apiVersion: v1\nkind: LimitRange\nmetadata:\n  name: x\n  namespace: kube-system\n"},
+		{Name: "broken", Resources: "kind: [\n"},
+	}
+	for i, tmpl := range invalid {
+		if err := tmpl.Validate(); err == nil {
+			t.Errorf("case %d: expected a validation error", i)
+		}
+	}
+}
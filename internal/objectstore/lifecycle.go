@@ -0,0 +1,102 @@
+package objectstore
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+)
+
+// lifecycleInterval is how often retention rules are applied
+const lifecycleInterval = time.Hour
+
+// Rule expires the objects under a key prefix once they are older than MaxAge
+type Rule struct {
+	Prefix string
+	MaxAge time.Duration
+}
+
+// ParseRules parses "<prefix>=<days>" retention rules, e.g. reports=90. A prefix without a
+// trailing slash matches that top-level directory.
+func ParseRules(specs []string) ([]Rule, error) {
+	rules := make([]Rule, 0, len(specs))
+	for _, spec := range specs {
+		prefix, days, ok := strings.Cut(spec, "=")
+		prefix = strings.Trim(strings.TrimSpace(prefix), "/")
+		n, err := strconv.Atoi(strings.TrimSpace(days))
+		if !ok || prefix == "" || err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid object retention rule %q, expected <prefix>=<days>", spec)
+		}
+		rules = append(rules, Rule{Prefix: prefix + "/", MaxAge: time.Duration(n) * 24 * time.Hour})
+	}
+	return rules, nil
+}
+
+// Lifecycle deletes objects that have outlived their retention rule
+type Lifecycle struct {
+	store  Store
+	rules  []Rule
+	logger *logger.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewLifecycle creates a lifecycle for store; call Start to begin applying the rules
+func NewLifecycle(store Store, rules []Rule, log *logger.Logger) *Lifecycle {
+	return &Lifecycle{store: store, rules: rules, logger: log}
+}
+
+// Start applies the rules now and then periodically in the background
+func (l *Lifecycle) Start() {
+	l.ctx, l.cancel = context.WithCancel(context.Background())
+	go func() {
+		ticker := time.NewTicker(lifecycleInterval)
+		defer ticker.Stop()
+		for {
+			if deleted, err := l.Apply(l.ctx, time.Now()); err != nil {
+				l.logger.WithError(err).Warn("Failed to apply object retention rules")
+			} else if deleted > 0 {
+				l.logger.WithField("deleted", deleted).Info("Deleted expired objects")
+			}
+			select {
+			case <-l.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops applying the rules
+func (l *Lifecycle) Stop() {
+	if l.cancel != nil {
+		l.cancel()
+	}
+}
+
+// Apply deletes the objects that are older than their rule's maximum age at now and returns
+// how many were deleted
+func (l *Lifecycle) Apply(ctx context.Context, now time.Time) (int, error) {
+	deleted := 0
+	for _, rule := range l.rules {
+		objects, err := l.store.List(ctx, rule.Prefix)
+		if err != nil {
+			return deleted, fmt.Errorf("list %s: %w", rule.Prefix, err)
+		}
+		cutoff := now.Add(-rule.MaxAge)
+		for _, object := range objects {
+			if !object.LastModified.Before(cutoff) {
+				continue
+			}
+			if err := l.store.Delete(ctx, object.Key); err != nil {
+				return deleted, fmt.Errorf("delete %s: %w", object.Key, err)
+			}
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,163 @@
+package objectstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// metaDir holds the content types of objects; keys cannot start with a dot, so it never clashes
+const metaDir = ".meta"
+
+type localMeta struct {
+	ContentType string `json:"contentType"`
+}
+
+// LocalStore keeps objects as files under a directory
+type LocalStore struct {
+	root string
+}
+
+// NewLocalStore creates a store under root, creating the directory if needed
+func NewLocalStore(root string) (*LocalStore, error) {
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create object storage directory: %w", err)
+	}
+	return &LocalStore{root: root}, nil
+}
+
+func (s *LocalStore) path(key string) string {
+	return filepath.Join(s.root, filepath.FromSlash(key))
+}
+
+func (s *LocalStore) metaPath(key string) string {
+	return filepath.Join(s.root, metaDir, filepath.FromSlash(key)+".json")
+}
+
+// Put writes the object to a temporary file and renames it into place, so readers never see a
+// partial object
+func (s *LocalStore) Put(ctx context.Context, key, contentType string, body io.Reader, size int64) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	path := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	written, err := io.Copy(tmp, body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if size >= 0 && written != size {
+		return fmt.Errorf("object %s: wrote %d bytes, expected %d", key, written, size)
+	}
+
+	meta, err := json.Marshal(localMeta{ContentType: contentType})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.metaPath(key)), 0o750); err != nil {
+		return err
+	}
+	if err := os.WriteFile(s.metaPath(key), meta, 0o640); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Open opens the object's file
+func (s *LocalStore) Open(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error) {
+	if err := validateKey(key); err != nil {
+		return nil, nil, err
+	}
+	file, err := os.Open(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	stat, err := file.Stat()
+	if err != nil || stat.IsDir() {
+		file.Close()
+		return nil, nil, ErrNotFound
+	}
+	info := &ObjectInfo{Key: key, Size: stat.Size(), LastModified: stat.ModTime()}
+	if data, err := os.ReadFile(s.metaPath(key)); err == nil {
+		var meta localMeta
+		if json.Unmarshal(data, &meta) == nil {
+			info.ContentType = meta.ContentType
+		}
+	}
+	return file, info, nil
+}
+
+// Delete removes the object's file and metadata
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	if err := os.Remove(s.path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err := os.Remove(s.metaPath(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// List walks the directory for files whose keys start with prefix
+func (s *LocalStore) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	err := filepath.WalkDir(s.root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if strings.HasPrefix(entry.Name(), ".") && path != s.root {
+			if entry.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			return ctx.Err()
+		}
+		rel, err := filepath.Rel(s.root, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+		objects = append(objects, ObjectInfo{Key: key, Size: info.Size(), LastModified: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+	return objects, nil
+}
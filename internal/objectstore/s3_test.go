@@ -0,0 +1,120 @@
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Facets-cloud/kube-dash/internal/config"
+)
+
+// fakeS3 serves path-style object requests for one bucket, listing one key per page
+type fakeS3 struct {
+	t       *testing.T
+	mu      sync.Mutex
+	objects map[string]string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/") || !strings.Contains(auth, "/eu-west-1/s3/aws4_request") ||
+		!strings.Contains(auth, "SignedHeaders=host;x-amz-content-sha256;x-amz-date") || r.Header.Get("X-Amz-Date") == "" {
+		f.t.Errorf("unsigned request %s %s: %q", r.Method, r.URL, auth)
+	}
+	key, ok := strings.CutPrefix(r.URL.Path, "/artifacts/")
+	if !ok && r.URL.Path != "/artifacts/" {
+		http.Error(w, "no such bucket", http.StatusNotFound)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case r.Method == http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		f.objects[key] = string(data)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	case key == "" && r.URL.Query().Get("list-type") == "2":
+		var keys []string
+		for k := range f.objects {
+			if strings.HasPrefix(k, r.URL.Query().Get("prefix")) && k > r.URL.Query().Get("continuation-token") {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		fmt.Fprint(w, `<ListBucketResult>`)
+		if len(keys) > 0 {
+			fmt.Fprintf(w, `<Contents><Key>%s</Key><Size>%d</Size><LastModified>2024-05-01T10:00:00.000Z</LastModified></Contents>`, keys[0], len(f.objects[keys[0]]))
+		}
+		if len(keys) > 1 {
+			fmt.Fprintf(w, `<IsTruncated>true</IsTruncated><NextContinuationToken>%s</NextContinuationToken>`, keys[0])
+		}
+		fmt.Fprint(w, `</ListBucketResult>`)
+	default:
+		data, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `<Error><Code>NoSuchKey</Code></Error>`)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, data)
+	}
+}
+
+func TestS3Store(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(&fakeS3{t: t, objects: map[string]string{}})
+	defer server.Close()
+	store, err := NewS3Store(&config.ObjectStorageConfig{
+		S3Endpoint: server.URL, S3Region: "eu-west-1", S3Bucket: "artifacts",
+		S3AccessKeyID: "AKID", S3SecretAccessKey: "secret", S3PathStyle: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"reports/a.html", "reports/b.html", "exports/c.json"} {
+		if err := store.Put(ctx, key, "text/html", strings.NewReader("<p>"+key+"</p>"), -1); err != nil {
+			t.Fatalf("Put(%s) = %v", key, err)
+		}
+	}
+	data, info, err := ReadAll(ctx, store, "reports/a.html")
+	if err != nil || string(data) != "<p>reports/a.html</p>" || info.ContentType != "text/html" {
+		t.Errorf("ReadAll() = %q %+v %v", data, info, err)
+	}
+
+	objects, err := store.List(ctx, "reports/")
+	if err != nil || len(objects) != 2 || objects[1].Key != "reports/b.html" || objects[0].LastModified.IsZero() {
+		t.Errorf("List(reports/) = %+v %v, want both reports across pages", objects, err)
+	}
+
+	if err := store.Delete(ctx, "reports/a.html"); err != nil {
+		t.Fatalf("Delete() = %v", err)
+	}
+	if _, _, err := store.Open(ctx, "reports/a.html"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open() of a deleted object = %v, want ErrNotFound", err)
+	}
+}
+
+func TestS3ObjectURL(t *testing.T) {
+	store, err := NewS3Store(&config.ObjectStorageConfig{S3Region: "us-west-2", S3Bucket: "kd", S3AccessKeyID: "a", S3SecretAccessKey: "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := store.objectURL("reports/a.pdf").String(); got != "https://kd.s3.us-west-2.amazonaws.com/reports/a.pdf" {
+		t.Errorf("objectURL() = %s", got)
+	}
+	if got := canonicalQuery(map[string][]string{"prefix": {"reports/a b"}, "list-type": {"2"}}); got != "list-type=2&prefix=reports%2Fa%20b" {
+		t.Errorf("canonicalQuery() = %s", got)
+	}
+}
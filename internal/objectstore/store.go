@@ -0,0 +1,85 @@
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/config"
+)
+
+// ErrNotFound is returned when an object does not exist
+var ErrNotFound = errors.New("object not found")
+
+// ObjectInfo describes a stored object
+type ObjectInfo struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	ContentType  string    `json:"contentType,omitempty"` // not reported by List on S3
+	LastModified time.Time `json:"lastModified"`
+}
+
+// Store holds large artifacts, such as generated reports, outside the document store. Keys are
+// slash-separated paths whose first segment names the subsystem that owns them, e.g. reports/.
+type Store interface {
+	// Put writes an object of size bytes, replacing any object with the same key
+	Put(ctx context.Context, key, contentType string, body io.Reader, size int64) error
+	// Open returns the content of an object; the caller closes it
+	Open(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error)
+	// Delete removes an object; deleting a missing object is not an error
+	Delete(ctx context.Context, key string) error
+	// List returns the objects whose keys start with prefix
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+}
+
+// New creates the store of the configured backend
+func New(cfg *config.ObjectStorageConfig) (Store, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.Backend)) {
+	case "local", "":
+		path := cfg.LocalPath
+		if path == "" {
+			path = "./kube-dash-objects"
+		}
+		return NewLocalStore(path)
+	case "s3":
+		return NewS3Store(cfg)
+	default:
+		return nil, fmt.Errorf("unsupported object storage backend: %s (supported: local, s3)", cfg.Backend)
+	}
+}
+
+// ReadAll reads a whole object
+func ReadAll(ctx context.Context, store Store, key string) ([]byte, *ObjectInfo, error) {
+	body, info, err := store.Open(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, info, nil
+}
+
+// validateKey accepts relative slash-separated keys of letters, digits, dots, dashes and
+// underscores, with no empty or dot-leading segments
+func validateKey(key string) error {
+	if key == "" || len(key) > 1024 {
+		return fmt.Errorf("invalid object key %q", key)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment[0] == '.' {
+			return fmt.Errorf("invalid object key %q", key)
+		}
+		for _, r := range segment {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_') {
+				return fmt.Errorf("invalid object key %q", key)
+			}
+		}
+	}
+	return nil
+}
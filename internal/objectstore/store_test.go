@@ -0,0 +1,109 @@
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+)
+
+func TestValidateKey(t *testing.T) {
+	for _, key := range []string{"reports/a/b.pdf", "exports/2024-01-01_bundle.tar.gz"} {
+		if err := validateKey(key); err != nil {
+			t.Errorf("validateKey(%q) = %v", key, err)
+		}
+	}
+	for _, key := range []string{"", "/reports/a", "reports//a", "reports/../a", ".meta/a", "reports/a b", "reports/"} {
+		if err := validateKey(key); err == nil {
+			t.Errorf("validateKey(%q) accepted an invalid key", key)
+		}
+	}
+}
+
+func TestLocalStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put(ctx, "reports/s1/a.html", "text/html", strings.NewReader("<p>a</p>"), 8); err != nil {
+		t.Fatalf("Put() = %v", err)
+	}
+	if err := store.Put(ctx, "exports/b.json", "application/json", strings.NewReader("{}"), -1); err != nil {
+		t.Fatalf("Put() = %v", err)
+	}
+	if err := store.Put(ctx, "reports/short", "", strings.NewReader("abc"), 5); err == nil {
+		t.Error("Put() accepted a body shorter than its size")
+	}
+
+	data, info, err := ReadAll(ctx, store, "reports/s1/a.html")
+	if err != nil || string(data) != "<p>a</p>" || info.ContentType != "text/html" || info.Size != 8 {
+		t.Errorf("ReadAll() = %q %+v %v", data, info, err)
+	}
+
+	objects, err := store.List(ctx, "reports/")
+	if err != nil || len(objects) != 1 || objects[0].Key != "reports/s1/a.html" {
+		t.Errorf("List(reports/) = %+v %v, want only reports/s1/a.html", objects, err)
+	}
+
+	if err := store.Delete(ctx, "reports/s1/a.html"); err != nil {
+		t.Fatalf("Delete() = %v", err)
+	}
+	if err := store.Delete(ctx, "reports/s1/a.html"); err != nil {
+		t.Errorf("Delete() of a missing object = %v", err)
+	}
+	if _, _, err := store.Open(ctx, "reports/s1/a.html"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open() of a deleted object = %v, want ErrNotFound", err)
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules([]string{"reports=90", "/exports/=7"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules[0].Prefix != "reports/" || rules[0].MaxAge != 90*24*time.Hour || rules[1].Prefix != "exports/" {
+		t.Errorf("ParseRules() = %+v", rules)
+	}
+	for _, spec := range []string{"reports", "reports=0", "=5", "reports=x"} {
+		if _, err := ParseRules([]string{spec}); err == nil {
+			t.Errorf("ParseRules(%q) accepted an invalid rule", spec)
+		}
+	}
+}
+
+func TestLifecycleApply(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	store, _ := NewLocalStore(root)
+	for _, key := range []string{"reports/old", "reports/new", "exports/old"} {
+		if err := store.Put(ctx, key, "", strings.NewReader("x"), 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	for _, key := range []string{"reports/old", "exports/old"} {
+		if err := os.Chtimes(filepath.Join(root, key), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lifecycle := NewLifecycle(store, []Rule{{Prefix: "reports/", MaxAge: 24 * time.Hour}}, logger.New("error"))
+	deleted, err := lifecycle.Apply(ctx, time.Now())
+	if err != nil || deleted != 1 {
+		t.Fatalf("Apply() = %d %v, want 1 deleted", deleted, err)
+	}
+	objects, _ := store.List(ctx, "")
+	var keys []string
+	for _, object := range objects {
+		keys = append(keys, object.Key)
+	}
+	if strings.Join(keys, ",") != "exports/old,reports/new" {
+		t.Errorf("objects after Apply() = %v, want exports/old and reports/new", keys)
+	}
+}
@@ -0,0 +1,295 @@
+package podcleanup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/config"
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Document collections used by the cleaner
+const (
+	policiesCollection = "pod_cleanup_policies"
+	runsCollection     = "pod_cleanup_runs"
+)
+
+const runTimeout = 5 * time.Minute
+
+// Cleaner deletes old evicted and failed pods of clusters with an enabled policy
+type Cleaner struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	documents     *storage.DocumentStore
+	logger        *logger.Logger
+	config        *config.PodCleanupConfig
+
+	mu       sync.RWMutex
+	policies map[string]*Policy
+	runs     map[string]*Run
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewCleaner creates a pod cleaner; call Start to begin scheduled cleanups
+func NewCleaner(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, documents *storage.DocumentStore, log *logger.Logger, cfg *config.PodCleanupConfig) *Cleaner {
+	c := &Cleaner{
+		store:         store,
+		clientFactory: clientFactory,
+		documents:     documents,
+		logger:        log,
+		config:        cfg,
+		policies:      make(map[string]*Policy),
+		runs:          make(map[string]*Run),
+	}
+	if err := c.reload(); err != nil {
+		log.WithError(err).Error("Failed to load pod cleanup policies")
+	}
+	return c
+}
+
+func (c *Cleaner) reload() error {
+	policies, err := c.documents.List(policiesCollection)
+	if err != nil {
+		return err
+	}
+	runs, err := c.documents.List(runsCollection)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id, data := range policies {
+		var p Policy
+		if err := json.Unmarshal(data, &p); err != nil {
+			c.logger.WithError(err).WithField("policy", id).Error("Skipping unreadable pod cleanup policy")
+			continue
+		}
+		c.policies[policyID(p.ConfigID, p.Cluster)] = &p
+	}
+	for id, data := range runs {
+		var r Run
+		if err := json.Unmarshal(data, &r); err != nil {
+			c.logger.WithError(err).WithField("run", id).Error("Skipping unreadable pod cleanup run")
+			continue
+		}
+		c.runs[r.ID] = &r
+	}
+	return nil
+}
+
+// Start begins scheduled cleanups. An interval of zero disables them; cleanups then only run on demand.
+func (c *Cleaner) Start() {
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+	if c.config.IntervalSeconds <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(time.Duration(c.config.IntervalSeconds) * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-ticker.C:
+				c.runAll()
+			}
+		}
+	}()
+}
+
+// Stop ends scheduled cleanups
+func (c *Cleaner) Stop() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+func (c *Cleaner) runAll() {
+	c.mu.RLock()
+	var policies []Policy
+	for _, p := range c.policies {
+		if p.Enabled {
+			policies = append(policies, *p)
+		}
+	}
+	c.mu.RUnlock()
+
+	for _, p := range policies {
+		if c.ctx.Err() != nil {
+			return
+		}
+		if _, err := c.store.GetKubeConfig(p.ConfigID); err != nil {
+			// The kubeconfig was removed; drop its policy
+			c.DeletePolicy(p.ConfigID, p.Cluster)
+			continue
+		}
+		ctx, cancel := context.WithTimeout(c.ctx, runTimeout)
+		if _, err := c.Run(ctx, p, "scheduled", false); err != nil {
+			c.logger.WithError(err).WithField("config", p.ConfigID).WithField("cluster", p.Cluster).Warn("Scheduled pod cleanup failed")
+		}
+		cancel()
+	}
+}
+
+// Policy returns the policy of a cluster, or a disabled default policy if none is stored
+func (c *Cleaner) Policy(configID, cluster string) Policy {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if p, ok := c.policies[policyID(configID, cluster)]; ok {
+		return *p
+	}
+	return Policy{ConfigID: configID, Cluster: cluster, MaxAgeHours: c.config.DefaultMaxAgeHours}
+}
+
+// SetPolicy stores the policy of a cluster
+func (c *Cleaner) SetPolicy(p Policy) (Policy, error) {
+	if p.MaxAgeHours <= 0 {
+		p.MaxAgeHours = c.config.DefaultMaxAgeHours
+	}
+	p.UpdatedAt = time.Now()
+	if err := c.documents.Put(policiesCollection, policyID(p.ConfigID, p.Cluster), &p); err != nil {
+		return p, err
+	}
+	c.mu.Lock()
+	c.policies[policyID(p.ConfigID, p.Cluster)] = &p
+	c.mu.Unlock()
+	return p, nil
+}
+
+// DeletePolicy removes the policy of a cluster
+func (c *Cleaner) DeletePolicy(configID, cluster string) {
+	id := policyID(configID, cluster)
+	c.mu.Lock()
+	delete(c.policies, id)
+	c.mu.Unlock()
+	if err := c.documents.Delete(policiesCollection, id); err != nil && err != storage.ErrDocumentNotFound {
+		c.logger.WithError(err).Error("Failed to delete pod cleanup policy")
+	}
+}
+
+func (c *Cleaner) getClient(configID, cluster string) (kubernetes.Interface, error) {
+	cfg, err := c.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, err
+	}
+	return c.clientFactory.GetClientForConfig(cfg, cluster)
+}
+
+// Run deletes the pods the policy selects, or only reports them in a dry run, and records the run
+func (c *Cleaner) Run(ctx context.Context, p Policy, trigger string, dryRun bool) (*Run, error) {
+	if p.MaxAgeHours <= 0 {
+		p.MaxAgeHours = c.config.DefaultMaxAgeHours
+	}
+	run := &Run{
+		ConfigID:    p.ConfigID,
+		Cluster:     p.Cluster,
+		Trigger:     trigger,
+		DryRun:      dryRun,
+		MaxAgeHours: p.MaxAgeHours,
+		EvictedOnly: p.EvictedOnly,
+		StartedAt:   time.Now(),
+		Pods:        []CleanedPod{},
+	}
+	run.ID = runID(p.ConfigID, p.Cluster, run.StartedAt)
+
+	err := c.run(ctx, p, run)
+	run.FinishedAt = time.Now()
+	if err != nil {
+		run.Error = err.Error()
+	}
+	// Dry runs are previews and are not kept in the history
+	if !dryRun {
+		c.record(run)
+	}
+	return run, err
+}
+
+func (c *Cleaner) run(ctx context.Context, p Policy, run *Run) error {
+	client, err := c.getClient(p.ConfigID, p.Cluster)
+	if err != nil {
+		return err
+	}
+	namespaces, err := client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	pods, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("status.phase", "Failed").String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list failed pods: %w", err)
+	}
+
+	excluded, optedOut := ExcludedNamespaces(namespaces.Items, p)
+	run.OptedOut = optedOut
+	run.Pods = SelectPods(pods.Items, excluded, time.Duration(p.MaxAgeHours)*time.Hour, p.EvictedOnly, run.StartedAt)
+	if run.DryRun {
+		return nil
+	}
+	for i := range run.Pods {
+		pod := &run.Pods[i]
+		err := client.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			pod.Error = err.Error()
+			continue
+		}
+		run.Deleted++
+	}
+	return nil
+}
+
+// record stores a run and drops the oldest runs of the cluster beyond the retention
+func (c *Cleaner) record(run *Run) {
+	if err := c.documents.Put(runsCollection, run.ID, run); err != nil {
+		c.logger.WithError(err).WithField("run", run.ID).Error("Failed to persist pod cleanup run")
+	}
+	c.mu.Lock()
+	c.runs[run.ID] = run
+	var expired []string
+	if c.config.RunRetention > 0 {
+		var clusterRuns []*Run
+		for _, r := range c.runs {
+			if r.ConfigID == run.ConfigID && r.Cluster == run.Cluster {
+				clusterRuns = append(clusterRuns, r)
+			}
+		}
+		sort.Slice(clusterRuns, func(i, j int) bool { return clusterRuns[i].StartedAt.After(clusterRuns[j].StartedAt) })
+		for _, r := range clusterRuns[min(len(clusterRuns), c.config.RunRetention):] {
+			delete(c.runs, r.ID)
+			expired = append(expired, r.ID)
+		}
+	}
+	c.mu.Unlock()
+	for _, id := range expired {
+		if err := c.documents.Delete(runsCollection, id); err != nil && err != storage.ErrDocumentNotFound {
+			c.logger.WithError(err).WithField("run", id).Error("Failed to delete expired pod cleanup run")
+		}
+	}
+}
+
+// Runs returns the recorded runs of a cluster, newest first
+func (c *Cleaner) Runs(configID, cluster string) []Run {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	result := []Run{}
+	for _, r := range c.runs {
+		if r.ConfigID == configID && r.Cluster == cluster {
+			result = append(result, *r)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].StartedAt.After(result[j].StartedAt) })
+	return result
+}
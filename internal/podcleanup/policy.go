@@ -0,0 +1,150 @@
+package podcleanup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OptOutAnnotation on a namespace set to "disabled" excludes its pods from cleanup
+const OptOutAnnotation = "kube-dash.io/pod-cleanup"
+
+// Policy is the cleanup policy of one cluster
+type Policy struct {
+	ConfigID          string    `json:"configId"`
+	Cluster           string    `json:"cluster,omitempty"`
+	Enabled           bool      `json:"enabled"`                     // Whether the background job cleans this cluster
+	MaxAgeHours       int       `json:"maxAgeHours"`                 // Pods that failed longer ago than this are deleted
+	EvictedOnly       bool      `json:"evictedOnly"`                 // Only delete evicted pods, keep other failed pods
+	ExcludeNamespaces []string  `json:"excludeNamespaces,omitempty"` // Namespaces never cleaned, in addition to opted-out ones
+	UpdatedAt         time.Time `json:"updatedAt"`
+}
+
+// CleanedPod is a pod deleted (or, in a dry run, selected) by a cleanup run
+type CleanedPod struct {
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Reason    string    `json:"reason"`
+	Message   string    `json:"message,omitempty"`
+	Node      string    `json:"node,omitempty"`
+	FailedAt  time.Time `json:"failedAt"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Run is the report of one cleanup run
+type Run struct {
+	ID          string       `json:"id"`
+	ConfigID    string       `json:"configId"`
+	Cluster     string       `json:"cluster,omitempty"`
+	Trigger     string       `json:"trigger"` // scheduled or manual
+	DryRun      bool         `json:"dryRun"`
+	MaxAgeHours int          `json:"maxAgeHours"`
+	EvictedOnly bool         `json:"evictedOnly"`
+	StartedAt   time.Time    `json:"startedAt"`
+	FinishedAt  time.Time    `json:"finishedAt"`
+	Pods        []CleanedPod `json:"pods"`
+	Deleted     int          `json:"deleted"`
+	OptedOut    []string     `json:"optedOutNamespaces,omitempty"`
+	Error       string       `json:"error,omitempty"`
+}
+
+func policyID(configID, cluster string) string {
+	sum := sha256.Sum256([]byte(configID + "|" + cluster))
+	return hex.EncodeToString(sum[:12])
+}
+
+func runID(configID, cluster string, startedAt time.Time) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%d", configID, cluster, startedAt.UnixNano())))
+	return hex.EncodeToString(sum[:12])
+}
+
+// isEvicted reports whether a failed pod was evicted by the kubelet or the eviction API
+func isEvicted(pod *v1.Pod) bool {
+	return pod.Status.Reason == "Evicted"
+}
+
+// failedAt estimates when a pod failed from its last container termination, falling back to its start time
+func failedAt(pod *v1.Pod) time.Time {
+	var latest time.Time
+	statuses := append(append([]v1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if t := status.State.Terminated; t != nil && t.FinishedAt.Time.After(latest) {
+			latest = t.FinishedAt.Time
+		}
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == v1.DisruptionTarget && cond.LastTransitionTime.Time.After(latest) {
+			latest = cond.LastTransitionTime.Time
+		}
+	}
+	if latest.IsZero() && pod.Status.StartTime != nil {
+		latest = pod.Status.StartTime.Time
+	}
+	if latest.IsZero() {
+		latest = pod.CreationTimestamp.Time
+	}
+	return latest
+}
+
+// SelectPods returns the failed pods the policy allows deleting. Pods of Jobs are kept because
+// the Job controller uses them for backoff accounting and the Job's TTL removes them.
+func SelectPods(pods []v1.Pod, excluded map[string]bool, maxAge time.Duration, evictedOnly bool, now time.Time) []CleanedPod {
+	selected := []CleanedPod{}
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase != v1.PodFailed || pod.DeletionTimestamp != nil || excluded[pod.Namespace] {
+			continue
+		}
+		if evictedOnly && !isEvicted(pod) {
+			continue
+		}
+		if ref := metav1.GetControllerOf(pod); ref != nil && ref.Kind == "Job" {
+			continue
+		}
+		at := failedAt(pod)
+		if now.Sub(at) < maxAge {
+			continue
+		}
+		reason := pod.Status.Reason
+		if reason == "" {
+			reason = "Failed"
+		}
+		selected = append(selected, CleanedPod{
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+			Reason:    reason,
+			Message:   pod.Status.Message,
+			Node:      pod.Spec.NodeName,
+			FailedAt:  at,
+		})
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		if selected[i].Namespace != selected[j].Namespace {
+			return selected[i].Namespace < selected[j].Namespace
+		}
+		return selected[i].Name < selected[j].Name
+	})
+	return selected
+}
+
+// ExcludedNamespaces returns the namespaces excluded by the policy or by the opt-out annotation
+func ExcludedNamespaces(namespaces []v1.Namespace, policy Policy) (map[string]bool, []string) {
+	excluded := map[string]bool{}
+	for _, ns := range policy.ExcludeNamespaces {
+		excluded[ns] = true
+	}
+	var optedOut []string
+	for _, ns := range namespaces {
+		if ns.Annotations[OptOutAnnotation] == "disabled" {
+			excluded[ns.Name] = true
+			optedOut = append(optedOut, ns.Name)
+		}
+	}
+	sort.Strings(optedOut)
+	return excluded, optedOut
+}
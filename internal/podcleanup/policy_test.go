@@ -0,0 +1,54 @@
+package podcleanup
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSelectPods(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	failed := func(namespace, name, reason string, age time.Duration) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Status: v1.PodStatus{Phase: v1.PodFailed, Reason: reason, StartTime: &metav1.Time{Time: now.Add(-age)}},
+		}
+	}
+	controller := true
+	jobPod := failed("shop", "migrate-x", "", 48*time.Hour)
+	jobPod.OwnerReferences = []metav1.OwnerReference{{Kind: "Job", Name: "migrate", Controller: &controller}}
+	crashed := failed("shop", "crashed", "", 72*time.Hour)
+	crashed.Status.ContainerStatuses = []v1.ContainerStatus{{State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{
+		FinishedAt: metav1.NewTime(now.Add(-time.Hour)),
+	}}}}
+
+	pods := []v1.Pod{
+		failed("shop", "evicted-old", "Evicted", 48*time.Hour),
+		failed("shop", "evicted-new", "Evicted", time.Hour),
+		failed("shop", "oom-old", "", 48*time.Hour),
+		failed("payments", "evicted-old", "Evicted", 48*time.Hour),
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "running"}, Status: v1.PodStatus{Phase: v1.PodRunning}},
+		jobPod,
+		crashed,
+	}
+	namespaces := []v1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "payments", Annotations: map[string]string{OptOutAnnotation: "disabled"}}},
+	}
+	excluded, optedOut := ExcludedNamespaces(namespaces, Policy{})
+	if len(optedOut) != 1 || optedOut[0] != "payments" {
+		t.Fatalf("expected payments to opt out, got %v", optedOut)
+	}
+
+	selected := SelectPods(pods, excluded, 24*time.Hour, false, now)
+	if len(selected) != 2 || selected[0].Name != "evicted-old" || selected[1].Name != "oom-old" || selected[1].Reason != "Failed" {
+		t.Errorf("unexpected selection %+v", selected)
+	}
+
+	selected = SelectPods(pods, excluded, 24*time.Hour, true, now)
+	if len(selected) != 1 || selected[0].Name != "evicted-old" {
+		t.Errorf("expected only the old evicted pod, got %+v", selected)
+	}
+}
@@ -0,0 +1,157 @@
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Credentials authenticate pulls from one registry
+type Credentials struct {
+	Username string
+	Password string
+}
+
+func (c *Credentials) basic() string {
+	return base64.StdEncoding.EncodeToString([]byte(c.Username + ":" + c.Password))
+}
+
+// StatusError is an unexpected HTTP status from a registry or its token endpoint
+type StatusError struct {
+	Code    int
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return e.Message
+}
+
+type dockerAuthEntry struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Auth     string `json:"auth"`
+}
+
+// normalizeRegistryHost reduces a docker config key such as https://index.docker.io/v1/ to a host
+func normalizeRegistryHost(key string) string {
+	host := key
+	if strings.Contains(host, "://") {
+		if u, err := url.Parse(host); err == nil {
+			host = u.Host
+		}
+	}
+	if i := strings.Index(host, "/"); i >= 0 {
+		host = host[:i]
+	}
+	switch host {
+	case "index.docker.io", "registry-1.docker.io", "registry.hub.docker.com":
+		return defaultRegistry
+	}
+	return host
+}
+
+// parseDockerConfig reads the auth entries of a dockerconfigjson or legacy dockercfg payload
+func parseDockerConfig(data []byte) (map[string]dockerAuthEntry, error) {
+	var config struct {
+		Auths map[string]dockerAuthEntry `json:"auths"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid docker config: %w", err)
+	}
+	if config.Auths != nil {
+		return config.Auths, nil
+	}
+	// Legacy .dockercfg stores the entries at the top level
+	var auths map[string]dockerAuthEntry
+	if err := json.Unmarshal(data, &auths); err != nil {
+		return nil, fmt.Errorf("invalid docker config: %w", err)
+	}
+	return auths, nil
+}
+
+// ConfigRegistries lists the registry keys of a docker config payload
+func ConfigRegistries(data []byte) []string {
+	auths, err := parseDockerConfig(data)
+	if err != nil {
+		return nil
+	}
+	keys := make([]string, 0, len(auths))
+	for key := range auths {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// CredentialsForRegistry finds the credentials for a registry in a dockerconfigjson or legacy dockercfg
+// payload. It returns the matching config key, or an empty key when the payload has no entry for the registry.
+func CredentialsForRegistry(data []byte, registryHost string) (*Credentials, string, error) {
+	auths, err := parseDockerConfig(data)
+	if err != nil {
+		return nil, "", err
+	}
+
+	want := normalizeRegistryHost(registryHost)
+	for key, entry := range auths {
+		if normalizeRegistryHost(key) != want {
+			continue
+		}
+		creds := &Credentials{Username: entry.Username, Password: entry.Password}
+		if entry.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				return nil, key, fmt.Errorf("invalid auth for %s: %w", key, err)
+			}
+			user, pass, ok := strings.Cut(string(decoded), ":")
+			if !ok {
+				return nil, key, fmt.Errorf("invalid auth for %s: expected user:password", key)
+			}
+			creds.Username, creds.Password = user, pass
+		}
+		return creds, key, nil
+	}
+	return nil, "", nil
+}
+
+// ManifestProbe is the outcome of a manifest HEAD request
+type ManifestProbe struct {
+	StatusCode         int    `json:"statusCode,omitempty"`
+	Digest             string `json:"digest,omitempty"`
+	Authenticated      bool   `json:"authenticated"`
+	RateLimitRemaining string `json:"rateLimitRemaining,omitempty"`
+	Error              string `json:"error,omitempty"`
+}
+
+// ProbeManifest issues a HEAD request for the reference's manifest, the same request a kubelet
+// makes before pulling, and reports the registry's answer rather than failing on non-200 codes
+func (c *Client) ProbeManifest(ctx context.Context, ref Reference, creds *Credentials) ManifestProbe {
+	probe := ManifestProbe{Authenticated: creds != nil}
+	version := ref.Tag
+	if ref.Digest != "" {
+		version = ref.Digest
+	}
+	endpoint := fmt.Sprintf("https://%s/v2/%s/manifests/%s", apiHost(ref.Registry), ref.Repository, url.PathEscape(version))
+	resp, err := c.doWithCredentials(ctx, http.MethodHead, endpoint, ref, manifestAccept, creds)
+	if err != nil {
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) {
+			probe.StatusCode = statusErr.Code
+		}
+		probe.Error = err.Error()
+		return probe
+	}
+	defer resp.Body.Close()
+	probe.StatusCode = resp.StatusCode
+	probe.Digest = resp.Header.Get("Docker-Content-Digest")
+	probe.RateLimitRemaining = resp.Header.Get("RateLimit-Remaining")
+	if resp.StatusCode != http.StatusOK {
+		probe.Error = fmt.Sprintf("registry returned %s for %s", resp.Status, ref.String())
+	}
+	return probe
+}
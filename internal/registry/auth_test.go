@@ -0,0 +1,31 @@
+package registry
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestCredentialsForRegistry(t *testing.T) {
+	auth := base64.StdEncoding.EncodeToString([]byte("robot:s3cret"))
+	config := []byte(`{"auths":{"https://index.docker.io/v1/":{"auth":"` + auth + `"},"ghcr.io":{"username":"bot","password":"token"}}}`)
+
+	creds, key, err := CredentialsForRegistry(config, "docker.io")
+	if err != nil || key != "https://index.docker.io/v1/" || creds.Username != "robot" || creds.Password != "s3cret" {
+		t.Errorf("unexpected docker hub credentials %+v key=%q err=%v", creds, key, err)
+	}
+	creds, key, err = CredentialsForRegistry(config, "ghcr.io")
+	if err != nil || key != "ghcr.io" || creds.Username != "bot" {
+		t.Errorf("unexpected ghcr credentials %+v key=%q err=%v", creds, key, err)
+	}
+	if creds, key, err := CredentialsForRegistry(config, "quay.io"); creds != nil || key != "" || err != nil {
+		t.Errorf("expected no credentials for quay.io, got %+v key=%q err=%v", creds, key, err)
+	}
+
+	legacy := []byte(`{"registry.example.com:5000":{"auth":"` + auth + `"}}`)
+	if creds, _, err := CredentialsForRegistry(legacy, "registry.example.com:5000"); err != nil || creds == nil || creds.Username != "robot" {
+		t.Errorf("unexpected legacy credentials %+v err=%v", creds, err)
+	}
+	if keys := ConfigRegistries(config); len(keys) != 2 || keys[0] != "ghcr.io" {
+		t.Errorf("unexpected registries %v", keys)
+	}
+}
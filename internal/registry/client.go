@@ -0,0 +1,307 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRegistry = "docker.io"
+	// Docker Hub serves the v2 API from a different host than the one used in image references
+	dockerHubAPIHost = "registry-1.docker.io"
+)
+
+// manifestAccept lists the manifest media types we accept so registries return the index digest
+var manifestAccept = strings.Join([]string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}, ", ")
+
+// Reference is a parsed container image reference
+type Reference struct {
+	Registry   string `json:"registry"`
+	Repository string `json:"repository"`
+	Tag        string `json:"tag,omitempty"`
+	Digest     string `json:"digest,omitempty"`
+}
+
+// ParseReference splits an image string into registry, repository, tag and digest,
+// applying the same defaults as the container runtime (docker.io, library/, latest)
+func ParseReference(image string) Reference {
+	ref := Reference{}
+	rest := image
+	if i := strings.Index(rest, "@"); i >= 0 {
+		ref.Digest = rest[i+1:]
+		rest = rest[:i]
+	}
+
+	// The first path component is a registry host only if it looks like one
+	if i := strings.Index(rest, "/"); i >= 0 {
+		host := rest[:i]
+		if strings.ContainsAny(host, ".:") || host == "localhost" {
+			ref.Registry = host
+			rest = rest[i+1:]
+		}
+	}
+	if ref.Registry == "" {
+		ref.Registry = defaultRegistry
+	}
+
+	// A colon after the last slash separates the tag
+	if i := strings.LastIndex(rest, ":"); i > strings.LastIndex(rest, "/") {
+		ref.Tag = rest[i+1:]
+		rest = rest[:i]
+	}
+	if ref.Registry == defaultRegistry && !strings.Contains(rest, "/") {
+		rest = "library/" + rest
+	}
+	ref.Repository = rest
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = "latest"
+	}
+	return ref
+}
+
+// String renders the reference in canonical form
+func (r Reference) String() string {
+	s := r.Registry + "/" + r.Repository
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+	return s
+}
+
+// Client talks to OCI distribution registries anonymously
+type Client struct {
+	httpClient *http.Client
+
+	mu     sync.Mutex
+	tokens map[string]cachedToken
+}
+
+type cachedToken struct {
+	token     string
+	expiresAt time.Time
+}
+
+// NewClient creates a registry client with the given request timeout
+func NewClient(timeout time.Duration) *Client {
+	return &Client{
+		httpClient: &http.Client{Timeout: timeout},
+		tokens:     make(map[string]cachedToken),
+	}
+}
+
+func apiHost(registry string) string {
+	if registry == defaultRegistry || registry == "index.docker.io" {
+		return dockerHubAPIHost
+	}
+	return registry
+}
+
+// ResolveDigest returns the digest the registry currently serves for the reference's tag
+func (c *Client) ResolveDigest(ctx context.Context, ref Reference) (string, error) {
+	if ref.Tag == "" {
+		return "", fmt.Errorf("reference %s has no tag", ref.String())
+	}
+	endpoint := fmt.Sprintf("https://%s/v2/%s/manifests/%s", apiHost(ref.Registry), ref.Repository, url.PathEscape(ref.Tag))
+	resp, err := c.do(ctx, http.MethodHead, endpoint, ref, manifestAccept)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("registry returned %s for %s", resp.Status, ref.String())
+	}
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		return "", fmt.Errorf("registry did not return a digest for %s", ref.String())
+	}
+	return digest, nil
+}
+
+// ListTags returns the tags published for the reference's repository
+func (c *Client) ListTags(ctx context.Context, ref Reference) ([]string, error) {
+	endpoint := fmt.Sprintf("https://%s/v2/%s/tags/list", apiHost(ref.Registry), ref.Repository)
+	resp, err := c.do(ctx, http.MethodGet, endpoint, ref, "application/json")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("registry returned %s listing tags for %s", resp.Status, ref.Repository)
+	}
+	var body struct {
+		Tags []string `json:"tags"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	return body.Tags, nil
+}
+
+// do performs a request, negotiating an anonymous bearer token when the registry challenges
+func (c *Client) do(ctx context.Context, method, endpoint string, ref Reference, accept string) (*http.Response, error) {
+	return c.doWithCredentials(ctx, method, endpoint, ref, accept, nil)
+}
+
+// doWithCredentials performs a request, answering registry challenges with the credentials if given
+func (c *Client) doWithCredentials(ctx context.Context, method, endpoint string, ref Reference, accept string, creds *Credentials) (*http.Response, error) {
+	scopeKey := ref.Registry + "/" + ref.Repository
+	if creds != nil {
+		scopeKey += "|" + creds.Username
+	}
+	send := func(authorization string) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, method, endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", accept)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		return c.httpClient.Do(req)
+	}
+
+	var cached string
+	if token := c.cachedToken(scopeKey); token != "" {
+		cached = "Bearer " + token
+	}
+	resp, err := send(cached)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		return resp, nil
+	}
+	challenge := resp.Header.Get("WWW-Authenticate")
+	resp.Body.Close()
+
+	// Registries such as self-hosted distribution or Artifactory may ask for basic auth directly
+	if creds != nil && strings.HasPrefix(strings.ToLower(challenge), "basic") {
+		return send("Basic " + creds.basic())
+	}
+
+	token, ttl, err := c.fetchToken(ctx, challenge, creds)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.tokens[scopeKey] = cachedToken{token: token, expiresAt: time.Now().Add(ttl)}
+	c.mu.Unlock()
+	return send("Bearer " + token)
+}
+
+func (c *Client) cachedToken(key string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if t, ok := c.tokens[key]; ok && time.Now().Before(t.expiresAt) {
+		return t.token
+	}
+	return ""
+}
+
+var challengeParam = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// fetchToken follows a Bearer challenge to obtain a pull token, anonymously unless credentials are given
+func (c *Client) fetchToken(ctx context.Context, challenge string, creds *Credentials) (string, time.Duration, error) {
+	if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		return "", 0, fmt.Errorf("registry requires unsupported authentication: %q", challenge)
+	}
+	params := map[string]string{}
+	for _, m := range challengeParam.FindAllStringSubmatch(challenge, -1) {
+		params[m[1]] = m[2]
+	}
+	realm := params["realm"]
+	if realm == "" {
+		return "", 0, fmt.Errorf("registry challenge has no realm")
+	}
+	query := url.Values{}
+	if params["service"] != "" {
+		query.Set("service", params["service"])
+	}
+	if params["scope"] != "" {
+		query.Set("scope", params["scope"])
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", 0, err
+	}
+	if creds != nil {
+		req.Header.Set("Authorization", "Basic "+creds.basic())
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, &StatusError{Code: resp.StatusCode, Message: fmt.Sprintf("token endpoint returned %s", resp.Status)}
+	}
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", 0, err
+	}
+	token := body.Token
+	if token == "" {
+		token = body.AccessToken
+	}
+	ttl := time.Duration(body.ExpiresIn) * time.Second
+	if ttl <= 0 {
+		ttl = 60 * time.Second
+	}
+	return token, ttl, nil
+}
+
+var versionTag = regexp.MustCompile(`^v?(\d+)(?:\.(\d+))?(?:\.(\d+))?$`)
+
+// LatestVersionTag picks the highest plain semantic-version tag (e.g. 1.2.3 or v1.2), ignoring pre-releases and variants
+func LatestVersionTag(tags []string) string {
+	type version struct {
+		tag   string
+		parts [3]int
+	}
+	var versions []version
+	for _, tag := range tags {
+		m := versionTag.FindStringSubmatch(tag)
+		if m == nil {
+			continue
+		}
+		v := version{tag: tag}
+		for i := 0; i < 3; i++ {
+			v.parts[i], _ = strconv.Atoi(m[i+1])
+		}
+		versions = append(versions, v)
+	}
+	if len(versions) == 0 {
+		return ""
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		for k := 0; k < 3; k++ {
+			if versions[i].parts[k] != versions[j].parts[k] {
+				return versions[i].parts[k] > versions[j].parts[k]
+			}
+		}
+		return len(versions[i].tag) > len(versions[j].tag)
+	})
+	return versions[0].tag
+}
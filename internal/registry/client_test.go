@@ -0,0 +1,81 @@
+package registry
+
+import (
+	"testing"
+)
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		name     string
+		image    string
+		expected Reference
+	}{
+		{
+			name:     "Docker Hub official image without tag",
+			image:    "nginx",
+			expected: Reference{Registry: "docker.io", Repository: "library/nginx", Tag: "latest"},
+		},
+		{
+			name:     "Docker Hub user image with tag",
+			image:    "bitnami/redis:7.2",
+			expected: Reference{Registry: "docker.io", Repository: "bitnami/redis", Tag: "7.2"},
+		},
+		{
+			name:     "Registry with port",
+			image:    "localhost:5000/team/app:v1",
+			expected: Reference{Registry: "localhost:5000", Repository: "team/app", Tag: "v1"},
+		},
+		{
+			name:     "Tag and digest",
+			image:    "ghcr.io/org/tool:1.0@sha256:abc",
+			expected: Reference{Registry: "ghcr.io", Repository: "org/tool", Tag: "1.0", Digest: "sha256:abc"},
+		},
+		{
+			name:     "Digest only",
+			image:    "quay.io/prometheus/node-exporter@sha256:def",
+			expected: Reference{Registry: "quay.io", Repository: "prometheus/node-exporter", Digest: "sha256:def"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ParseReference(tt.image)
+			if result != tt.expected {
+				t.Errorf("ParseReference(%q) = %+v, expected %+v", tt.image, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLatestVersionTag(t *testing.T) {
+	tests := []struct {
+		name     string
+		tags     []string
+		expected string
+	}{
+		{
+			name:     "Semantic versions",
+			tags:     []string{"1.2.3", "1.10.0", "1.9.9", "latest"},
+			expected: "1.10.0",
+		},
+		{
+			name:     "Prefixed versions and variants",
+			tags:     []string{"v2.0", "v2.1-alpine", "v1.9.0"},
+			expected: "v2.0",
+		},
+		{
+			name:     "No version tags",
+			tags:     []string{"latest", "stable"},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := LatestVersionTag(tt.tags)
+			if result != tt.expected {
+				t.Errorf("LatestVersionTag(%v) = %q, expected %q", tt.tags, result, tt.expected)
+			}
+		})
+	}
+}
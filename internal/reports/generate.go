@@ -0,0 +1,393 @@
+package reports
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/handlers/security"
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	maxTopViolations = 50
+	maxTopReasons    = 10
+)
+
+// deprecatedAPI is a removed or deprecated apiVersion for a kind
+type deprecatedAPI struct {
+	APIVersion     string
+	Kind           string
+	Resource       string
+	RemovedRelease string
+	Replacement    string
+}
+
+// deprecatedAPIs lists apiVersions that manifests commonly still declare
+var deprecatedAPIs = []deprecatedAPI{
+	{"extensions/v1beta1", "Deployment", "deployments", "1.16", "apps/v1"},
+	{"apps/v1beta1", "Deployment", "deployments", "1.16", "apps/v1"},
+	{"apps/v1beta2", "Deployment", "deployments", "1.16", "apps/v1"},
+	{"extensions/v1beta1", "DaemonSet", "daemonsets", "1.16", "apps/v1"},
+	{"apps/v1beta2", "DaemonSet", "daemonsets", "1.16", "apps/v1"},
+	{"apps/v1beta1", "StatefulSet", "statefulsets", "1.16", "apps/v1"},
+	{"apps/v1beta2", "StatefulSet", "statefulsets", "1.16", "apps/v1"},
+	{"extensions/v1beta1", "Ingress", "ingresses", "1.22", "networking.k8s.io/v1"},
+	{"networking.k8s.io/v1beta1", "Ingress", "ingresses", "1.22", "networking.k8s.io/v1"},
+	{"batch/v1beta1", "CronJob", "cronjobs", "1.25", "batch/v1"},
+	{"autoscaling/v2beta1", "HorizontalPodAutoscaler", "horizontalpodautoscalers", "1.25", "autoscaling/v2"},
+	{"autoscaling/v2beta2", "HorizontalPodAutoscaler", "horizontalpodautoscalers", "1.26", "autoscaling/v2"},
+	{"policy/v1beta1", "PodDisruptionBudget", "poddisruptionbudgets", "1.25", "policy/v1"},
+}
+
+// generateOverview summarizes nodes, pods, deployments, capacity and warning events
+func generateOverview(ctx context.Context, client kubernetes.Interface) (*OverviewReport, error) {
+	report := &OverviewReport{PodsByPhase: map[string]int{}, UnavailableDeployments: []string{}, TopWarningReasons: []ReasonCount{}}
+
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	report.Nodes = len(nodes.Items)
+	for _, node := range nodes.Items {
+		for _, cond := range node.Status.Conditions {
+			if cond.Type == v1.NodeReady && cond.Status == v1.ConditionTrue {
+				report.ReadyNodes++
+			}
+		}
+		allocatable := k8s.NodeAllocatable(&node)
+		report.CPUAllocatableCores += float64(allocatable.MilliCPU) / 1000
+		report.MemoryAllocatableGiB += float64(allocatable.MemoryBytes) / (1 << 30)
+	}
+
+	pods, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	for _, pod := range pods.Items {
+		report.PodsByPhase[string(pod.Status.Phase)]++
+		for _, cs := range pod.Status.ContainerStatuses {
+			report.ContainerRestarts += int(cs.RestartCount)
+		}
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		requests := k8s.PodRequests(&pod.Spec)
+		report.CPURequestedCores += float64(requests.MilliCPU) / 1000
+		report.MemoryRequestedGiB += float64(requests.MemoryBytes) / (1 << 30)
+	}
+
+	if namespaces, err := client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{}); err == nil {
+		report.Namespaces = len(namespaces.Items)
+	}
+
+	deployments, err := client.AppsV1().Deployments("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	report.Deployments = len(deployments.Items)
+	for _, d := range deployments.Items {
+		desired := int32(1)
+		if d.Spec.Replicas != nil {
+			desired = *d.Spec.Replicas
+		}
+		if d.Status.AvailableReplicas < desired {
+			report.UnavailableDeployments = append(report.UnavailableDeployments, d.Namespace+"/"+d.Name)
+		}
+	}
+	sort.Strings(report.UnavailableDeployments)
+
+	events, err := client.CoreV1().Events("").List(ctx, metav1.ListOptions{FieldSelector: "type=Warning"})
+	if err == nil {
+		cutoff := time.Now().Add(-24 * time.Hour)
+		reasons := map[string]int{}
+		for _, ev := range events.Items {
+			last := ev.LastTimestamp.Time
+			if last.IsZero() {
+				last = ev.EventTime.Time
+			}
+			if last.Before(cutoff) {
+				continue
+			}
+			report.WarningEvents++
+			reasons[ev.Reason]++
+		}
+		for reason, count := range reasons {
+			report.TopWarningReasons = append(report.TopWarningReasons, ReasonCount{Reason: reason, Count: count})
+		}
+		sort.Slice(report.TopWarningReasons, func(i, j int) bool {
+			return report.TopWarningReasons[i].Count > report.TopWarningReasons[j].Count
+		})
+		if len(report.TopWarningReasons) > maxTopReasons {
+			report.TopWarningReasons = report.TopWarningReasons[:maxTopReasons]
+		}
+	}
+	return report, nil
+}
+
+// generateDeprecations combines the API server's deprecated API request metric with a scan of
+// last-applied manifests for removed apiVersions
+func generateDeprecations(ctx context.Context, client kubernetes.Interface) (*DeprecationReport, error) {
+	report := &DeprecationReport{Findings: []DeprecatedAPIUsage{}}
+	if version, err := client.Discovery().ServerVersion(); err == nil {
+		report.ServerVersion = version.GitVersion
+	}
+
+	raw, err := client.Discovery().RESTClient().Get().AbsPath("/metrics").DoRaw(ctx)
+	if err != nil {
+		report.Note = fmt.Sprintf("API server metrics unavailable (%v); only last-applied manifests were scanned", err)
+	} else {
+		report.Findings = append(report.Findings, parseDeprecatedAPIMetrics(raw)...)
+	}
+
+	byKind := map[string]map[string]deprecatedAPI{}
+	for _, api := range deprecatedAPIs {
+		if byKind[api.Kind] == nil {
+			byKind[api.Kind] = map[string]deprecatedAPI{}
+		}
+		byKind[api.Kind][api.APIVersion] = api
+	}
+
+	objects, err := listLastApplied(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	usage := map[string]*DeprecatedAPIUsage{}
+	for _, obj := range objects {
+		api, ok := byKind[obj.kind][obj.apiVersion]
+		if !ok {
+			continue
+		}
+		key := api.APIVersion + "/" + api.Kind
+		if usage[key] == nil {
+			group, version := splitAPIVersion(api.APIVersion)
+			usage[key] = &DeprecatedAPIUsage{
+				Group:          group,
+				Version:        version,
+				Resource:       api.Resource,
+				RemovedRelease: api.RemovedRelease,
+				Replacement:    api.Replacement,
+				Source:         "last-applied",
+			}
+		}
+		usage[key].Objects = append(usage[key].Objects, obj.name)
+	}
+	for _, u := range usage {
+		sort.Strings(u.Objects)
+		report.Findings = append(report.Findings, *u)
+	}
+	sort.Slice(report.Findings, func(i, j int) bool {
+		if report.Findings[i].RemovedRelease != report.Findings[j].RemovedRelease {
+			return report.Findings[i].RemovedRelease < report.Findings[j].RemovedRelease
+		}
+		return report.Findings[i].Resource < report.Findings[j].Resource
+	})
+	return report, nil
+}
+
+// parseDeprecatedAPIMetrics extracts apiserver_requested_deprecated_apis series from Prometheus text output
+func parseDeprecatedAPIMetrics(raw []byte) []DeprecatedAPIUsage {
+	var findings []DeprecatedAPIUsage
+	seen := map[string]bool{}
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "apiserver_requested_deprecated_apis{") {
+			continue
+		}
+		end := strings.LastIndex(line, "}")
+		if end < 0 {
+			continue
+		}
+		labels := parseMetricLabels(line[len("apiserver_requested_deprecated_apis{"):end])
+		if strings.TrimSpace(line[end+1:]) == "0" {
+			continue
+		}
+		key := labels["group"] + "/" + labels["version"] + "/" + labels["resource"]
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		findings = append(findings, DeprecatedAPIUsage{
+			Group:          labels["group"],
+			Version:        labels["version"],
+			Resource:       labels["resource"],
+			RemovedRelease: labels["removed_release"],
+			Source:         "apiserver-metrics",
+		})
+	}
+	return findings
+}
+
+// parseMetricLabels parses the label set of a Prometheus text exposition line
+func parseMetricLabels(s string) map[string]string {
+	labels := map[string]string{}
+	for len(s) > 0 {
+		eq := strings.Index(s, "=\"")
+		if eq < 0 {
+			break
+		}
+		name := strings.TrimLeft(s[:eq], ", ")
+		rest := s[eq+2:]
+		var value strings.Builder
+		i := 0
+		for ; i < len(rest); i++ {
+			if rest[i] == '\\' && i+1 < len(rest) {
+				i++
+				value.WriteByte(rest[i])
+				continue
+			}
+			if rest[i] == '"' {
+				break
+			}
+			value.WriteByte(rest[i])
+		}
+		labels[name] = value.String()
+		if i+1 > len(rest) {
+			break
+		}
+		s = rest[i+1:]
+	}
+	return labels
+}
+
+type lastAppliedObject struct {
+	kind       string
+	name       string
+	apiVersion string
+}
+
+// listLastApplied returns the apiVersion each object was last applied with
+func listLastApplied(ctx context.Context, client kubernetes.Interface) ([]lastAppliedObject, error) {
+	var objects []lastAppliedObject
+	add := func(kind string, meta metav1.ObjectMeta) {
+		annotation := meta.Annotations[v1.LastAppliedConfigAnnotation]
+		if annotation == "" {
+			return
+		}
+		var applied struct {
+			APIVersion string `json:"apiVersion"`
+		}
+		if json.Unmarshal([]byte(annotation), &applied) != nil {
+			return
+		}
+		objects = append(objects, lastAppliedObject{kind: kind, name: meta.Namespace + "/" + meta.Name, apiVersion: applied.APIVersion})
+	}
+
+	deployments, err := client.AppsV1().Deployments("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	for _, o := range deployments.Items {
+		add("Deployment", o.ObjectMeta)
+	}
+	if list, err := client.AppsV1().DaemonSets("").List(ctx, metav1.ListOptions{}); err == nil {
+		for _, o := range list.Items {
+			add("DaemonSet", o.ObjectMeta)
+		}
+	}
+	if list, err := client.AppsV1().StatefulSets("").List(ctx, metav1.ListOptions{}); err == nil {
+		for _, o := range list.Items {
+			add("StatefulSet", o.ObjectMeta)
+		}
+	}
+	if list, err := client.NetworkingV1().Ingresses("").List(ctx, metav1.ListOptions{}); err == nil {
+		for _, o := range list.Items {
+			add("Ingress", o.ObjectMeta)
+		}
+	}
+	if list, err := client.BatchV1().CronJobs("").List(ctx, metav1.ListOptions{}); err == nil {
+		for _, o := range list.Items {
+			add("CronJob", o.ObjectMeta)
+		}
+	}
+	if list, err := client.AutoscalingV2().HorizontalPodAutoscalers("").List(ctx, metav1.ListOptions{}); err == nil {
+		for _, o := range list.Items {
+			add("HorizontalPodAutoscaler", o.ObjectMeta)
+		}
+	}
+	if list, err := client.PolicyV1().PodDisruptionBudgets("").List(ctx, metav1.ListOptions{}); err == nil {
+		for _, o := range list.Items {
+			add("PodDisruptionBudget", o.ObjectMeta)
+		}
+	}
+	return objects, nil
+}
+
+func splitAPIVersion(apiVersion string) (group, version string) {
+	if i := strings.Index(apiVersion, "/"); i >= 0 {
+		return apiVersion[:i], apiVersion[i+1:]
+	}
+	return "", apiVersion
+}
+
+// generateSecurity runs the Pod Security audit and lists subjects bound to cluster-admin
+func generateSecurity(ctx context.Context, client kubernetes.Interface) (*SecurityReport, error) {
+	pods, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	namespaceLabels := map[string]map[string]string{}
+	if nsList, err := client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{}); err == nil {
+		for _, ns := range nsList.Items {
+			namespaceLabels[ns.Name] = ns.Labels
+		}
+	}
+
+	report := &SecurityReport{
+		Namespaces:           []SecurityNamespaceSummary{},
+		TopViolations:        []security.PodSecurityViolation{},
+		ClusterAdminSubjects: []string{},
+	}
+	for _, ns := range security.BuildPodSecurityReports(pods.Items, namespaceLabels) {
+		report.BaselineViolations += ns.BaselineViolations
+		report.RestrictedViolations += ns.RestrictedViolations
+		report.Namespaces = append(report.Namespaces, SecurityNamespaceSummary{
+			Namespace:            ns.Namespace,
+			Enforce:              ns.Enforce,
+			CompliantLevel:       ns.CompliantLevel,
+			PodsEvaluated:        ns.PodsEvaluated,
+			BaselineViolations:   ns.BaselineViolations,
+			RestrictedViolations: ns.RestrictedViolations,
+		})
+		for _, violation := range ns.Violations {
+			// Only baseline violations are listed individually, restricted ones are counted
+			if violation.Level == "baseline" && len(report.TopViolations) < maxTopViolations {
+				report.TopViolations = append(report.TopViolations, violation)
+			}
+		}
+	}
+
+	bindings, err := client.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cluster role bindings: %w", err)
+	}
+	seen := map[string]bool{}
+	for _, binding := range bindings.Items {
+		if binding.RoleRef.Kind != "ClusterRole" || binding.RoleRef.Name != "cluster-admin" {
+			continue
+		}
+		for _, subject := range binding.Subjects {
+			name := subject.Kind + ":" + subject.Name
+			if subject.Namespace != "" {
+				name = subject.Kind + ":" + subject.Namespace + "/" + subject.Name
+			}
+			if !seen[name] {
+				seen[name] = true
+				report.ClusterAdminSubjects = append(report.ClusterAdminSubjects, name)
+			}
+		}
+	}
+	sort.Strings(report.ClusterAdminSubjects)
+	return report, nil
+}
@@ -0,0 +1,36 @@
+package reports
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDeprecatedAPIMetrics(t *testing.T) {
+	raw := []byte(`# HELP apiserver_requested_deprecated_apis [STABLE] Gauge of deprecated APIs that have been requested
+# TYPE apiserver_requested_deprecated_apis gauge
+apiserver_requested_deprecated_apis{group="policy",removed_release="1.25",resource="podsecuritypolicies",subresource="",version="v1beta1"} 1
+apiserver_requested_deprecated_apis{group="policy",removed_release="1.25",resource="podsecuritypolicies",subresource="status",version="v1beta1"} 1
+apiserver_requested_deprecated_apis{group="flowcontrol.apiserver.k8s.io",removed_release="1.29",resource="flowschemas",subresource="",version="v1beta2"} 0
+apiserver_request_total{code="200",resource="pods",verb="LIST"} 42
+`)
+
+	got := parseDeprecatedAPIMetrics(raw)
+	want := []DeprecatedAPIUsage{{
+		Group:          "policy",
+		Version:        "v1beta1",
+		Resource:       "podsecuritypolicies",
+		RemovedRelease: "1.25",
+		Source:         "apiserver-metrics",
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseDeprecatedAPIMetrics() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseMetricLabels(t *testing.T) {
+	got := parseMetricLabels(`a="1",b="with \"quote\"",c=""`)
+	want := map[string]string{"a": "1", "b": `with "quote"`, "c": ""}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseMetricLabels() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,272 @@
+package reports
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+)
+
+// section is a format-independent block of a rendered report
+type section struct {
+	Title   string
+	Facts   [][2]string
+	Headers []string
+	Rows    [][]string
+	Note    string
+}
+
+// render produces the artifact content and MIME type for a format
+func render(data *ReportData, format string) ([]byte, string, error) {
+	switch format {
+	case FormatHTML:
+		content, err := renderHTML(data)
+		return content, "text/html; charset=utf-8", err
+	case FormatPDF:
+		return renderPDF(data), "application/pdf", nil
+	default:
+		content, err := json.MarshalIndent(data, "", "  ")
+		return content, "application/json", err
+	}
+}
+
+// buildSections flattens report data into titled fact lists and tables
+func buildSections(data *ReportData) []section {
+	var sections []section
+
+	if o := data.Overview; o != nil {
+		s := section{Title: "Cluster health", Facts: [][2]string{
+			{"Nodes ready", fmt.Sprintf("%d / %d", o.ReadyNodes, o.Nodes)},
+			{"Namespaces", fmt.Sprintf("%d", o.Namespaces)},
+			{"Deployments unavailable", fmt.Sprintf("%d / %d", len(o.UnavailableDeployments), o.Deployments)},
+			{"Container restarts", fmt.Sprintf("%d", o.ContainerRestarts)},
+			{"CPU requested", fmt.Sprintf("%.1f / %.1f cores", o.CPURequestedCores, o.CPUAllocatableCores)},
+			{"Memory requested", fmt.Sprintf("%.1f / %.1f GiB", o.MemoryRequestedGiB, o.MemoryAllocatableGiB)},
+			{"Warning events (24h)", fmt.Sprintf("%d", o.WarningEvents)},
+		}}
+		phases := make([]string, 0, len(o.PodsByPhase))
+		for phase := range o.PodsByPhase {
+			phases = append(phases, phase)
+		}
+		sort.Strings(phases)
+		for _, phase := range phases {
+			s.Facts = append(s.Facts, [2]string{"Pods " + phase, fmt.Sprintf("%d", o.PodsByPhase[phase])})
+		}
+		s.Headers = []string{"Warning reason", "Events"}
+		for _, r := range o.TopWarningReasons {
+			s.Rows = append(s.Rows, []string{r.Reason, fmt.Sprintf("%d", r.Count)})
+		}
+		if len(o.UnavailableDeployments) > 0 {
+			s.Note = "Unavailable deployments: " + strings.Join(o.UnavailableDeployments, ", ")
+		}
+		sections = append(sections, s)
+	}
+
+	if c := data.Cost; c != nil {
+		s := section{Title: "Cost", Facts: [][2]string{
+			{"Source", c.Source},
+			{"Window", c.Window},
+			{"Total", fmt.Sprintf("%.2f %s", c.TotalCost, c.Currency)},
+			{"Idle", fmt.Sprintf("%.2f %s", c.IdleCost, c.Currency)},
+		}, Headers: []string{"Namespace", "CPU", "Memory", "Storage", "Total"}, Note: c.Note}
+		for _, a := range c.Allocations {
+			s.Rows = append(s.Rows, []string{a.Name,
+				fmt.Sprintf("%.2f", a.CPUCost), fmt.Sprintf("%.2f", a.MemoryCost),
+				fmt.Sprintf("%.2f", a.StorageCost), fmt.Sprintf("%.2f", a.TotalCost)})
+		}
+		sections = append(sections, s)
+	}
+
+	if d := data.Deprecations; d != nil {
+		s := section{Title: "Deprecated APIs", Facts: [][2]string{
+			{"Server version", d.ServerVersion},
+			{"Findings", fmt.Sprintf("%d", len(d.Findings))},
+		}, Headers: []string{"API", "Resource", "Removed in", "Replacement", "Source", "Objects"}, Note: d.Note}
+		for _, f := range d.Findings {
+			api := f.Version
+			if f.Group != "" {
+				api = f.Group + "/" + f.Version
+			}
+			s.Rows = append(s.Rows, []string{api, f.Resource, f.RemovedRelease, f.Replacement, f.Source, strings.Join(f.Objects, ", ")})
+		}
+		sections = append(sections, s)
+	}
+
+	if sec := data.Security; sec != nil {
+		s := section{Title: "Security", Facts: [][2]string{
+			{"Baseline violations", fmt.Sprintf("%d", sec.BaselineViolations)},
+			{"Restricted violations", fmt.Sprintf("%d", sec.RestrictedViolations)},
+			{"cluster-admin subjects", strings.Join(sec.ClusterAdminSubjects, ", ")},
+		}, Headers: []string{"Namespace", "Enforce", "Compliant level", "Pods", "Baseline", "Restricted"}}
+		for _, ns := range sec.Namespaces {
+			s.Rows = append(s.Rows, []string{ns.Namespace, ns.Enforce, ns.CompliantLevel,
+				fmt.Sprintf("%d", ns.PodsEvaluated), fmt.Sprintf("%d", ns.BaselineViolations), fmt.Sprintf("%d", ns.RestrictedViolations)})
+		}
+		sections = append(sections, s)
+	}
+
+	if len(data.Errors) > 0 {
+		s := section{Title: "Errors", Headers: []string{"Report", "Error"}}
+		for kind, msg := range data.Errors {
+			s.Rows = append(s.Rows, []string{kind, msg})
+		}
+		sort.Slice(s.Rows, func(i, j int) bool { return s.Rows[i][0] < s.Rows[j][0] })
+		sections = append(sections, s)
+	}
+	return sections
+}
+
+// summaryText is a short plain-text digest used for Slack and email deliveries
+func summaryText(data *ReportData) string {
+	var b strings.Builder
+	for _, s := range buildSections(data) {
+		b.WriteString(s.Title + "\n")
+		for _, fact := range s.Facts {
+			fmt.Fprintf(&b, "  %s: %s\n", fact[0], fact[1])
+		}
+		if s.Title == "Errors" {
+			for _, row := range s.Rows {
+				fmt.Fprintf(&b, "  %s: %s\n", row[0], row[1])
+			}
+		}
+	}
+	return b.String()
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>{{.Data.Title}}</title>
+<style>
+body{font-family:-apple-system,Segoe UI,Helvetica,Arial,sans-serif;margin:32px;color:#1f2937}
+h1{font-size:22px}h2{font-size:17px;margin-top:28px;border-bottom:1px solid #e5e7eb;padding-bottom:4px}
+table{border-collapse:collapse;margin-top:8px;font-size:13px}td,th{border:1px solid #e5e7eb;padding:4px 8px;text-align:left}
+th{background:#f9fafb}.facts td:first-child{color:#6b7280}.note{color:#6b7280;font-size:13px}
+</style></head><body>
+<h1>{{.Data.Title}}</h1>
+<p class="note">Cluster {{.Data.Cluster}} &middot; generated {{.Data.GeneratedAt.Format "2006-01-02 15:04 MST"}}</p>
+{{range .Sections}}<h2>{{.Title}}</h2>
+{{if .Facts}}<table class="facts">{{range .Facts}}<tr><td>{{index . 0}}</td><td>{{index . 1}}</td></tr>{{end}}</table>{{end}}
+{{if .Rows}}<table><tr>{{range .Headers}}<th>{{.}}</th>{{end}}</tr>{{range .Rows}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>{{end}}</table>{{end}}
+{{if .Note}}<p class="note">{{.Note}}</p>{{end}}
+{{end}}</body></html>
+`))
+
+func renderHTML(data *ReportData) ([]byte, error) {
+	var buf bytes.Buffer
+	err := htmlTemplate.Execute(&buf, struct {
+		Data     *ReportData
+		Sections []section
+	}{data, buildSections(data)})
+	return buf.Bytes(), err
+}
+
+// PDF layout in points on a US Letter page
+const (
+	pdfPageWidth    = 612
+	pdfPageHeight   = 792
+	pdfMargin       = 48
+	pdfLeading      = 12
+	pdfMaxLineChars = 110
+)
+
+// renderPDF writes the report as a plain-text PDF using the built-in Courier font
+func renderPDF(data *ReportData) []byte {
+	lines := []string{data.Title, fmt.Sprintf("Cluster %s, generated %s", data.Cluster, data.GeneratedAt.Format("2006-01-02 15:04 MST")), ""}
+	for _, s := range buildSections(data) {
+		lines = append(lines, strings.ToUpper(s.Title))
+		for _, fact := range s.Facts {
+			lines = append(lines, fmt.Sprintf("  %s: %s", fact[0], fact[1]))
+		}
+		if len(s.Rows) > 0 {
+			lines = append(lines, "  "+strings.Join(s.Headers, " | "))
+			for _, row := range s.Rows {
+				lines = append(lines, "  "+strings.Join(row, " | "))
+			}
+		}
+		if s.Note != "" {
+			lines = append(lines, "  "+s.Note)
+		}
+		lines = append(lines, "")
+	}
+
+	var wrapped []string
+	for _, line := range lines {
+		runes := []rune(line)
+		for len(runes) > pdfMaxLineChars {
+			wrapped = append(wrapped, pdfSafe(string(runes[:pdfMaxLineChars])))
+			runes = append([]rune("    "), runes[pdfMaxLineChars:]...)
+		}
+		wrapped = append(wrapped, pdfSafe(string(runes)))
+	}
+
+	linesPerPage := (pdfPageHeight - 2*pdfMargin) / pdfLeading
+	var pages [][]string
+	for len(wrapped) > 0 {
+		n := linesPerPage
+		if n > len(wrapped) {
+			n = len(wrapped)
+		}
+		pages = append(pages, wrapped[:n])
+		wrapped = wrapped[n:]
+	}
+	if len(pages) == 0 {
+		pages = [][]string{{""}}
+	}
+
+	// Objects: 1 catalog, 2 page tree, 3 font, then a page and content stream per page
+	var objects []string
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	objects = append(objects,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier >>",
+	)
+	for i, page := range pages {
+		var stream strings.Builder
+		fmt.Fprintf(&stream, "BT /F1 8 Tf %d TL %d %d Td\n", pdfLeading, pdfMargin, pdfPageHeight-pdfMargin)
+		for _, line := range page {
+			fmt.Fprintf(&stream, "(%s) Tj T*\n", line)
+		}
+		stream.WriteString("ET")
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", pdfPageWidth, pdfPageHeight, 5+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", stream.Len(), stream.String()),
+		)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+// pdfSafe escapes PDF string delimiters and replaces characters outside printable ASCII
+func pdfSafe(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 32 || r > 126:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
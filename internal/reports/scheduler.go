@@ -0,0 +1,468 @@
+package reports
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/handlers/cost"
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/notifications"
+	"github.com/Facets-cloud/kube-dash/internal/objectstore"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/google/uuid"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Document collections used by the scheduler
+const (
+	schedulesCollection = "report_schedules"
+	artifactsCollection = "report_artifacts"
+)
+
+// ErrScheduleRunning is returned when a schedule is already generating
+var ErrScheduleRunning = errors.New("report schedule is already running")
+
+// ErrArtifactExpired is returned when a report's content was removed by object storage retention
+var ErrArtifactExpired = errors.New("report content has expired")
+
+// objectPrefix is the object storage prefix report content is written under
+const objectPrefix = "reports/"
+
+const (
+	checkInterval     = time.Minute
+	generationTimeout = 5 * time.Minute
+)
+
+// Scheduler runs report schedules and stores the generated artifacts
+type Scheduler struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	documents     *storage.DocumentStore
+	objects       objectstore.Store // holds artifact content; nil keeps it in the document store
+	cost          *cost.CostHandler
+	notifier      *notifications.Engine
+	logger        *logger.Logger
+
+	mu      sync.Mutex
+	running map[string]bool // schedule IDs currently generating
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewScheduler creates a report scheduler; call Start to begin running due schedules
+func NewScheduler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, documents *storage.DocumentStore, objects objectstore.Store, costHandler *cost.CostHandler, notifier *notifications.Engine, log *logger.Logger) *Scheduler {
+	return &Scheduler{
+		store:         store,
+		clientFactory: clientFactory,
+		documents:     documents,
+		objects:       objects,
+		cost:          costHandler,
+		notifier:      notifier,
+		logger:        log,
+		running:       make(map[string]bool),
+	}
+}
+
+// Start begins checking for due schedules in the background
+func (s *Scheduler) Start() {
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	go func() {
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-ticker.C:
+				s.runDue()
+			}
+		}
+	}()
+}
+
+// Stop stops the background loop; in-flight generations are cancelled
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+func (s *Scheduler) runDue() {
+	schedules, err := s.ListSchedules()
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to load report schedules")
+		return
+	}
+	now := time.Now()
+	for i := range schedules {
+		schedule := schedules[i]
+		if !schedule.Enabled || schedule.NextRunAt == nil || schedule.NextRunAt.After(now) {
+			continue
+		}
+		s.mu.Lock()
+		running := s.running[schedule.ID]
+		s.mu.Unlock()
+		if running {
+			continue
+		}
+		go func() {
+			if _, err := s.RunSchedule(s.ctx, schedule.ID); err != nil {
+				s.logger.WithError(err).WithField("schedule", schedule.Name).Warn("Scheduled report failed")
+			}
+		}()
+	}
+}
+
+// ListSchedules returns all schedules sorted by name
+func (s *Scheduler) ListSchedules() ([]Schedule, error) {
+	docs, err := s.documents.List(schedulesCollection)
+	if err != nil {
+		return nil, err
+	}
+	schedules := make([]Schedule, 0, len(docs))
+	for id, data := range docs {
+		var schedule Schedule
+		if err := json.Unmarshal(data, &schedule); err != nil {
+			s.logger.WithError(err).WithField("schedule", id).Error("Skipping unreadable report schedule")
+			continue
+		}
+		schedules = append(schedules, schedule)
+	}
+	sort.Slice(schedules, func(i, j int) bool { return schedules[i].Name < schedules[j].Name })
+	return schedules, nil
+}
+
+// GetSchedule returns a single schedule
+func (s *Scheduler) GetSchedule(id string) (*Schedule, error) {
+	var schedule Schedule
+	if err := s.documents.Get(schedulesCollection, id, &schedule); err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// SaveSchedule validates and persists a schedule, computing its next run
+func (s *Scheduler) SaveSchedule(schedule *Schedule) error {
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+	now := time.Now()
+	if schedule.ID == "" {
+		schedule.ID = uuid.New().String()
+		schedule.CreatedAt = now
+	}
+	schedule.UpdatedAt = now
+	schedule.NextRunAt = nil
+	if schedule.Enabled {
+		base := now
+		if schedule.LastRunAt != nil {
+			base = *schedule.LastRunAt
+		}
+		next := base.Add(schedule.interval())
+		if next.Before(now) {
+			next = now
+		}
+		schedule.NextRunAt = &next
+	}
+	return s.documents.Put(schedulesCollection, schedule.ID, schedule)
+}
+
+// DeleteSchedule removes a schedule and its artifacts
+func (s *Scheduler) DeleteSchedule(id string) error {
+	artifacts, err := s.ListArtifacts(id)
+	if err != nil {
+		return err
+	}
+	for _, artifact := range artifacts {
+		if err := s.documents.Delete(artifactsCollection, artifact.ID); err != nil {
+			return err
+		}
+	}
+	return s.documents.Delete(schedulesCollection, id)
+}
+
+// Cleanup deletes the schedules of a removed kubeconfig or cluster with their artifacts
+func (s *Scheduler) Cleanup(removal storage.Removal) (int, error) {
+	schedules, err := s.ListSchedules()
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, schedule := range schedules {
+		if !removal.Matches(schedule.ConfigID, schedule.Cluster) {
+			continue
+		}
+		if err := s.DeleteSchedule(schedule.ID); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// ListArtifacts returns artifact metadata without content, newest first, optionally for one schedule
+func (s *Scheduler) ListArtifacts(scheduleID string) ([]Artifact, error) {
+	docs, err := s.documents.List(artifactsCollection)
+	if err != nil {
+		return nil, err
+	}
+	artifacts := make([]Artifact, 0, len(docs))
+	for _, data := range docs {
+		var artifact Artifact
+		if err := json.Unmarshal(data, &artifact); err != nil {
+			continue
+		}
+		if scheduleID != "" && artifact.ScheduleID != scheduleID {
+			continue
+		}
+		artifact.Content = nil
+		artifacts = append(artifacts, artifact)
+	}
+	sort.Slice(artifacts, func(i, j int) bool { return artifacts[i].GeneratedAt.After(artifacts[j].GeneratedAt) })
+	return artifacts, nil
+}
+
+// GetArtifact returns an artifact's metadata
+func (s *Scheduler) GetArtifact(id string) (*Artifact, error) {
+	var artifact Artifact
+	if err := s.documents.Get(artifactsCollection, id, &artifact); err != nil {
+		return nil, err
+	}
+	artifact.Content = nil
+	return &artifact, nil
+}
+
+// ReadArtifact returns an artifact including its content
+func (s *Scheduler) ReadArtifact(ctx context.Context, id string) (*Artifact, error) {
+	var artifact Artifact
+	if err := s.documents.Get(artifactsCollection, id, &artifact); err != nil {
+		return nil, err
+	}
+	if artifact.ObjectKey == "" {
+		// Stored before object storage, or without it
+		return &artifact, nil
+	}
+	if s.objects == nil {
+		return nil, fmt.Errorf("report content is in object storage, which is not configured")
+	}
+	content, _, err := objectstore.ReadAll(ctx, s.objects, artifact.ObjectKey)
+	if errors.Is(err, objectstore.ErrNotFound) {
+		return nil, ErrArtifactExpired
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report content: %w", err)
+	}
+	artifact.Content = content
+	return &artifact, nil
+}
+
+// DeleteArtifact removes a generated report and its content
+func (s *Scheduler) DeleteArtifact(id string) error {
+	var artifact Artifact
+	if err := s.documents.Get(artifactsCollection, id, &artifact); err != nil {
+		return err
+	}
+	if artifact.ObjectKey != "" && s.objects != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if err := s.objects.Delete(ctx, artifact.ObjectKey); err != nil {
+			return fmt.Errorf("failed to delete report content: %w", err)
+		}
+	}
+	return s.documents.Delete(artifactsCollection, id)
+}
+
+// RunSchedule generates a schedule's reports now, stores the artifact and delivers it to the schedule's channels
+func (s *Scheduler) RunSchedule(ctx context.Context, id string) (*Artifact, error) {
+	s.mu.Lock()
+	if s.running[id] {
+		s.mu.Unlock()
+		return nil, ErrScheduleRunning
+	}
+	s.running[id] = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.running, id)
+		s.mu.Unlock()
+	}()
+
+	schedule, err := s.GetSchedule(id)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, generationTimeout)
+	defer cancel()
+
+	artifact, data, genErr := s.generate(ctx, schedule)
+	now := time.Now()
+	schedule.LastRunAt = &now
+	schedule.LastError = ""
+	if genErr != nil {
+		schedule.LastError = genErr.Error()
+	} else {
+		schedule.LastReportID = artifact.ID
+	}
+	if err := s.SaveSchedule(schedule); err != nil {
+		s.logger.WithError(err).WithField("schedule", schedule.Name).Error("Failed to update report schedule")
+	}
+	if genErr != nil {
+		return nil, genErr
+	}
+
+	if err := s.applyRetention(schedule); err != nil {
+		s.logger.WithError(err).WithField("schedule", schedule.Name).Warn("Failed to prune old reports")
+	}
+
+	if len(schedule.Channels) > 0 && s.notifier != nil {
+		s.notifier.Send(ctx, schedule.Channels, &notifications.Notification{
+			RuleID:    schedule.ID,
+			RuleName:  schedule.Name,
+			Source:    "report",
+			ConfigID:  schedule.ConfigID,
+			Cluster:   schedule.Cluster,
+			Reason:    "ScheduledReport",
+			Message:   summaryText(data) + fmt.Sprintf("\nDownload: /api/v1/reports/artifacts/%s/download", artifact.ID),
+			Timestamp: artifact.GeneratedAt,
+			Details:   data,
+		})
+	}
+
+	artifact.Content = nil
+	return artifact, nil
+}
+
+// generate builds the report data for a schedule and stores the rendered artifact
+func (s *Scheduler) generate(ctx context.Context, schedule *Schedule) (*Artifact, *ReportData, error) {
+	cfg, err := s.store.GetKubeConfig(schedule.ConfigID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("config not found: %w", err)
+	}
+	client, err := s.clientFactory.GetClientForConfig(cfg, schedule.Cluster)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get Kubernetes client: %w", err)
+	}
+
+	data := &ReportData{
+		Title:       schedule.Name,
+		ConfigID:    schedule.ConfigID,
+		Cluster:     schedule.Cluster,
+		GeneratedAt: time.Now(),
+		Errors:      map[string]string{},
+	}
+	s.collect(ctx, client, schedule, data)
+	if len(data.Errors) == len(schedule.Reports) {
+		return nil, nil, fmt.Errorf("all reports failed to generate")
+	}
+
+	content, contentType, err := render(data, schedule.Format)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to render report: %w", err)
+	}
+	artifact := &Artifact{
+		ID:           uuid.New().String(),
+		ScheduleID:   schedule.ID,
+		ScheduleName: schedule.Name,
+		ConfigID:     schedule.ConfigID,
+		Cluster:      schedule.Cluster,
+		Reports:      schedule.Reports,
+		Format:       schedule.Format,
+		ContentType:  contentType,
+		Filename:     fmt.Sprintf("%s-%s.%s", schedule.Cluster, data.GeneratedAt.Format("20060102-1504"), schedule.Format),
+		Size:         len(content),
+		Errors:       data.Errors,
+		GeneratedAt:  data.GeneratedAt,
+		Content:      content,
+	}
+	if s.objects != nil {
+		artifact.ObjectKey = objectPrefix + schedule.ID + "/" + artifact.ID + "." + schedule.Format
+		if err := s.objects.Put(ctx, artifact.ObjectKey, contentType, bytes.NewReader(content), int64(len(content))); err != nil {
+			return nil, nil, fmt.Errorf("failed to store report content: %w", err)
+		}
+		artifact.Content = nil
+	}
+	if err := s.documents.Put(artifactsCollection, artifact.ID, artifact); err != nil {
+		if s.objects != nil {
+			_ = s.objects.Delete(ctx, artifact.ObjectKey)
+		}
+		return nil, nil, fmt.Errorf("failed to store report: %w", err)
+	}
+	return artifact, data, nil
+}
+
+// collect runs each requested generator, recording failures per report instead of aborting
+func (s *Scheduler) collect(ctx context.Context, client kubernetes.Interface, schedule *Schedule, data *ReportData) {
+	var err error
+	if schedule.includes(KindOverview) {
+		if data.Overview, err = generateOverview(ctx, client); err != nil {
+			data.Errors[KindOverview] = err.Error()
+		}
+	}
+	if schedule.includes(KindCost) {
+		window, duration := "24h", 24*time.Hour
+		if schedule.interval() > 24*time.Hour {
+			window, duration = "7d", 7*24*time.Hour
+		}
+		key := schedule.ConfigID + "|" + schedule.Cluster
+		if data.Cost, err = s.cost.ComputeReport(ctx, client, key, window, duration, "namespace", "", false); err != nil {
+			data.Errors[KindCost] = err.Error()
+		}
+	}
+	if schedule.includes(KindDeprecations) {
+		if data.Deprecations, err = generateDeprecations(ctx, client); err != nil {
+			data.Errors[KindDeprecations] = err.Error()
+		}
+	}
+	if schedule.includes(KindSecurity) {
+		if data.Security, err = generateSecurity(ctx, client); err != nil {
+			data.Errors[KindSecurity] = err.Error()
+		}
+	}
+}
+
+// applyRetention deletes the oldest artifacts beyond the schedule's retain count, and the
+// artifacts whose content object storage retention has already removed
+func (s *Scheduler) applyRetention(schedule *Schedule) error {
+	artifacts, err := s.ListArtifacts(schedule.ID)
+	if err != nil {
+		return err
+	}
+	var stored map[string]bool
+	if s.objects != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		objects, err := s.objects.List(ctx, objectPrefix+schedule.ID+"/")
+		if err != nil {
+			return err
+		}
+		stored = make(map[string]bool, len(objects))
+		for _, object := range objects {
+			stored[object.Key] = true
+		}
+	}
+	for i, artifact := range artifacts {
+		expired := artifact.ObjectKey != "" && stored != nil && !stored[artifact.ObjectKey]
+		if i < schedule.Retain && !expired {
+			continue
+		}
+		if expired {
+			err = s.documents.Delete(artifactsCollection, artifact.ID)
+		} else {
+			err = s.DeleteArtifact(artifact.ID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
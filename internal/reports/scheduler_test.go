@@ -0,0 +1,56 @@
+package reports
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/objectstore"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+)
+
+func TestArtifactContentInObjectStorage(t *testing.T) {
+	ctx := context.Background()
+	objects, err := objectstore.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	documents := storage.NewDocumentStore(nil)
+	s := NewScheduler(nil, nil, documents, objects, nil, nil, logger.New("error"))
+
+	// Three artifacts of one schedule, newest first; the oldest content was already expired
+	now := time.Now()
+	for i, id := range []string{"new", "mid", "old"} {
+		artifact := Artifact{ID: id, ScheduleID: "s1", ObjectKey: "reports/s1/" + id + ".html", GeneratedAt: now.Add(-time.Duration(i) * time.Hour)}
+		if id != "old" {
+			if err := objects.Put(ctx, artifact.ObjectKey, "text/html", strings.NewReader(id), int64(len(id))); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := documents.Put(artifactsCollection, id, artifact); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	artifact, err := s.ReadArtifact(ctx, "mid")
+	if err != nil || string(artifact.Content) != "mid" {
+		t.Fatalf("ReadArtifact(mid) = %+v %v", artifact, err)
+	}
+	if _, err := s.ReadArtifact(ctx, "old"); !errors.Is(err, ErrArtifactExpired) {
+		t.Errorf("ReadArtifact(old) = %v, want ErrArtifactExpired", err)
+	}
+
+	if err := s.applyRetention(&Schedule{ID: "s1", Retain: 1}); err != nil {
+		t.Fatalf("applyRetention() = %v", err)
+	}
+	remaining, _ := s.ListArtifacts("s1")
+	if len(remaining) != 1 || remaining[0].ID != "new" {
+		t.Errorf("artifacts after retention = %+v, want only new", remaining)
+	}
+	if _, _, err := objects.Open(ctx, "reports/s1/mid.html"); !errors.Is(err, objectstore.ErrNotFound) {
+		t.Errorf("content of a pruned artifact = %v, want it deleted", err)
+	}
+}
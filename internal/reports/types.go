@@ -0,0 +1,196 @@
+package reports
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/handlers/cost"
+	"github.com/Facets-cloud/kube-dash/internal/api/handlers/security"
+	"github.com/Facets-cloud/kube-dash/internal/notifications"
+)
+
+// Report kinds that can be included in a schedule
+const (
+	KindOverview     = "overview"
+	KindCost         = "cost"
+	KindDeprecations = "deprecations"
+	KindSecurity     = "security"
+)
+
+// Artifact formats
+const (
+	FormatJSON = "json"
+	FormatHTML = "html"
+	FormatPDF  = "pdf"
+)
+
+const (
+	defaultIntervalMinutes = 24 * 60
+	minIntervalMinutes     = 15
+	defaultRetain          = 20
+	maxRetain              = 200
+)
+
+// Schedule periodically generates a set of reports for one cluster
+type Schedule struct {
+	ID              string                  `json:"id"`
+	Name            string                  `json:"name"`
+	Enabled         bool                    `json:"enabled"`
+	ConfigID        string                  `json:"configId"`
+	Cluster         string                  `json:"cluster"`
+	Reports         []string                `json:"reports"`         // overview, cost, deprecations, security
+	Format          string                  `json:"format"`          // json, html or pdf
+	IntervalMinutes int                     `json:"intervalMinutes"` // defaults to daily
+	Channels        []notifications.Channel `json:"channels,omitempty"`
+	Retain          int                     `json:"retain,omitempty"` // number of artifacts kept, defaults to 20
+	LastRunAt       *time.Time              `json:"lastRunAt,omitempty"`
+	NextRunAt       *time.Time              `json:"nextRunAt,omitempty"`
+	LastReportID    string                  `json:"lastReportId,omitempty"`
+	LastError       string                  `json:"lastError,omitempty"`
+	CreatedAt       time.Time               `json:"createdAt"`
+	UpdatedAt       time.Time               `json:"updatedAt"`
+}
+
+// Artifact is a generated report file
+type Artifact struct {
+	ID           string            `json:"id"`
+	ScheduleID   string            `json:"scheduleId"`
+	ScheduleName string            `json:"scheduleName"`
+	ConfigID     string            `json:"configId"`
+	Cluster      string            `json:"cluster"`
+	Reports      []string          `json:"reports"`
+	Format       string            `json:"format"`
+	ContentType  string            `json:"contentType"`
+	Filename     string            `json:"filename"`
+	Size         int               `json:"size"`
+	Errors       map[string]string `json:"errors,omitempty"` // report kind -> generation error
+	GeneratedAt  time.Time         `json:"generatedAt"`
+	ObjectKey    string            `json:"objectKey,omitempty"` // object storage key of the content, when stored there
+	Content      []byte            `json:"content,omitempty"`
+}
+
+// ReportData is the structured content of a generated report
+type ReportData struct {
+	Title        string             `json:"title"`
+	ConfigID     string             `json:"configId"`
+	Cluster      string             `json:"cluster"`
+	GeneratedAt  time.Time          `json:"generatedAt"`
+	Overview     *OverviewReport    `json:"overview,omitempty"`
+	Cost         *cost.CostReport   `json:"cost,omitempty"`
+	Deprecations *DeprecationReport `json:"deprecations,omitempty"`
+	Security     *SecurityReport    `json:"security,omitempty"`
+	Errors       map[string]string  `json:"errors,omitempty"`
+}
+
+// OverviewReport summarizes cluster health
+type OverviewReport struct {
+	Nodes                  int            `json:"nodes"`
+	ReadyNodes             int            `json:"readyNodes"`
+	Namespaces             int            `json:"namespaces"`
+	PodsByPhase            map[string]int `json:"podsByPhase"`
+	ContainerRestarts      int            `json:"containerRestarts"`
+	Deployments            int            `json:"deployments"`
+	UnavailableDeployments []string       `json:"unavailableDeployments"`
+	CPUAllocatableCores    float64        `json:"cpuAllocatableCores"`
+	CPURequestedCores      float64        `json:"cpuRequestedCores"`
+	MemoryAllocatableGiB   float64        `json:"memoryAllocatableGiB"`
+	MemoryRequestedGiB     float64        `json:"memoryRequestedGiB"`
+	WarningEvents          int            `json:"warningEvents"`
+	TopWarningReasons      []ReasonCount  `json:"topWarningReasons"`
+}
+
+// ReasonCount is the number of warning events with a given reason
+type ReasonCount struct {
+	Reason string `json:"reason"`
+	Count  int    `json:"count"`
+}
+
+// DeprecatedAPIUsage is a deprecated API version observed in the cluster
+type DeprecatedAPIUsage struct {
+	Group          string   `json:"group"`
+	Version        string   `json:"version"`
+	Resource       string   `json:"resource"`
+	RemovedRelease string   `json:"removedRelease,omitempty"`
+	Replacement    string   `json:"replacement,omitempty"`
+	Source         string   `json:"source"` // apiserver-metrics or last-applied
+	Objects        []string `json:"objects,omitempty"`
+}
+
+// DeprecationReport lists deprecated API usage
+type DeprecationReport struct {
+	ServerVersion string               `json:"serverVersion"`
+	Findings      []DeprecatedAPIUsage `json:"findings"`
+	Note          string               `json:"note,omitempty"`
+}
+
+// SecurityReport summarizes the Pod Security audit and privileged RBAC bindings
+type SecurityReport struct {
+	BaselineViolations   int                             `json:"baselineViolations"`
+	RestrictedViolations int                             `json:"restrictedViolations"`
+	Namespaces           []SecurityNamespaceSummary      `json:"namespaces"`
+	TopViolations        []security.PodSecurityViolation `json:"topViolations"`
+	ClusterAdminSubjects []string                        `json:"clusterAdminSubjects"`
+}
+
+// SecurityNamespaceSummary is the Pod Security result for one namespace
+type SecurityNamespaceSummary struct {
+	Namespace            string `json:"namespace"`
+	Enforce              string `json:"enforce,omitempty"`
+	CompliantLevel       string `json:"compliantLevel"`
+	PodsEvaluated        int    `json:"podsEvaluated"`
+	BaselineViolations   int    `json:"baselineViolations"`
+	RestrictedViolations int    `json:"restrictedViolations"`
+}
+
+// Validate checks a schedule and applies defaults
+func (s *Schedule) Validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if s.ConfigID == "" || s.Cluster == "" {
+		return fmt.Errorf("configId and cluster are required")
+	}
+	if len(s.Reports) == 0 {
+		return fmt.Errorf("at least one report is required")
+	}
+	for _, kind := range s.Reports {
+		switch kind {
+		case KindOverview, KindCost, KindDeprecations, KindSecurity:
+		default:
+			return fmt.Errorf("unsupported report %q", kind)
+		}
+	}
+	switch s.Format {
+	case "":
+		s.Format = FormatJSON
+	case FormatJSON, FormatHTML, FormatPDF:
+	default:
+		return fmt.Errorf("format must be one of json, html, pdf")
+	}
+	if s.IntervalMinutes == 0 {
+		s.IntervalMinutes = defaultIntervalMinutes
+	}
+	if s.IntervalMinutes < minIntervalMinutes {
+		return fmt.Errorf("intervalMinutes must be at least %d", minIntervalMinutes)
+	}
+	if s.Retain == 0 {
+		s.Retain = defaultRetain
+	}
+	if s.Retain < 0 || s.Retain > maxRetain {
+		return fmt.Errorf("retain must be between 1 and %d", maxRetain)
+	}
+	return notifications.ValidateChannels(s.Channels)
+}
+
+func (s *Schedule) interval() time.Duration {
+	return time.Duration(s.IntervalMinutes) * time.Minute
+}
+
+func (s *Schedule) includes(kind string) bool {
+	for _, k := range s.Reports {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
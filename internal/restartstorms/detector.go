@@ -0,0 +1,433 @@
+package restartstorms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/config"
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// clustersCollection holds the clusters analyzed in the background
+const clustersCollection = "restart_storm_clusters"
+
+const analyzeTimeout = time.Minute
+
+// TrackedCluster is a cluster analyzed for restart storms in the background
+type TrackedCluster struct {
+	ConfigID string    `json:"configId"`
+	Cluster  string    `json:"cluster,omitempty"`
+	AddedAt  time.Time `json:"addedAt"`
+}
+
+// Report is the restart storm state of a cluster
+type Report struct {
+	ConfigID      string     `json:"configId"`
+	Cluster       string     `json:"cluster,omitempty"`
+	AnalyzedAt    *time.Time `json:"analyzedAt,omitempty"`
+	WindowMinutes int        `json:"windowMinutes"`
+	MinContainers int        `json:"minContainers"`
+	LastError     string     `json:"lastError,omitempty"`
+	Incidents     []Incident `json:"incidents"` // active incidents first, then recently resolved ones
+}
+
+// clusterState is what the detector remembers about a cluster between analyses
+type clusterState struct {
+	tracked TrackedCluster
+
+	analyzing sync.Mutex // serializes analyses of the cluster
+
+	counts     map[string]int32 // restart count by container, from the previous analysis
+	restarts   []Restart        // restarts within the window
+	bootIDs    map[string]string
+	reboots    map[string]time.Time
+	incidents  map[string]*Incident
+	analyzedAt *time.Time
+	lastError  string
+}
+
+// Detector analyzes the clusters it has been asked about for restart storms: many containers
+// restarting within a namespace or on a node within a short window. Incidents are correlated to
+// node reboots, ConfigMap and Secret edits and workload rollouts that happened around them.
+type Detector struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	documents     *storage.DocumentStore
+	logger        *logger.Logger
+	config        *config.RestartStormsConfig
+
+	mu       sync.RWMutex
+	clusters map[string]*clusterState
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewDetector creates a restart storm detector; call Start to begin analyzing tracked clusters
+func NewDetector(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, documents *storage.DocumentStore, log *logger.Logger, cfg *config.RestartStormsConfig) *Detector {
+	d := &Detector{
+		store:         store,
+		clientFactory: clientFactory,
+		documents:     documents,
+		logger:        log,
+		config:        cfg,
+		clusters:      make(map[string]*clusterState),
+	}
+	docs, err := documents.List(clustersCollection)
+	if err != nil {
+		log.WithError(err).Error("Failed to load restart storm clusters")
+		return d
+	}
+	for id, data := range docs {
+		var c TrackedCluster
+		if err := json.Unmarshal(data, &c); err != nil {
+			log.WithError(err).WithField("cluster", id).Error("Skipping unreadable restart storm cluster")
+			continue
+		}
+		d.clusters[storage.ClusterKey(c.ConfigID, c.Cluster)] = newClusterState(c)
+	}
+	return d
+}
+
+func newClusterState(tracked TrackedCluster) *clusterState {
+	return &clusterState{
+		tracked:   tracked,
+		bootIDs:   map[string]string{},
+		reboots:   map[string]time.Time{},
+		incidents: map[string]*Incident{},
+	}
+}
+
+func (d *Detector) window() time.Duration {
+	return time.Duration(d.config.WindowMinutes) * time.Minute
+}
+
+// Start begins analyzing tracked clusters in the background. An interval of zero disables
+// background analysis; clusters are then only analyzed when queried.
+func (d *Detector) Start() {
+	d.ctx, d.cancel = context.WithCancel(context.Background())
+	if d.config.IntervalSeconds <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(time.Duration(d.config.IntervalSeconds) * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-d.ctx.Done():
+				return
+			case <-ticker.C:
+				d.analyzeAll()
+			}
+		}
+	}()
+}
+
+// Stop ends background analysis
+func (d *Detector) Stop() {
+	if d.cancel != nil {
+		d.cancel()
+	}
+}
+
+func (d *Detector) analyzeAll() {
+	d.mu.RLock()
+	clusters := make([]TrackedCluster, 0, len(d.clusters))
+	for _, state := range d.clusters {
+		clusters = append(clusters, state.tracked)
+	}
+	d.mu.RUnlock()
+
+	for _, c := range clusters {
+		if d.ctx.Err() != nil {
+			return
+		}
+		if _, err := d.store.GetKubeConfig(c.ConfigID); err != nil {
+			// The kubeconfig was removed; stop analyzing the cluster
+			d.Untrack(c.ConfigID, c.Cluster)
+			continue
+		}
+		ctx, cancel := context.WithTimeout(d.ctx, analyzeTimeout)
+		if err := d.Analyze(ctx, c.ConfigID, c.Cluster); err != nil {
+			d.logger.WithError(err).WithField("config", c.ConfigID).WithField("cluster", c.Cluster).Warn("Failed to analyze restart storms")
+		}
+		cancel()
+	}
+}
+
+// Track adds a cluster to background analysis
+func (d *Detector) Track(configID, cluster string) {
+	key := storage.ClusterKey(configID, cluster)
+	d.mu.Lock()
+	if _, ok := d.clusters[key]; ok {
+		d.mu.Unlock()
+		return
+	}
+	tracked := TrackedCluster{ConfigID: configID, Cluster: cluster, AddedAt: time.Now()}
+	d.clusters[key] = newClusterState(tracked)
+	d.mu.Unlock()
+	if err := d.documents.Put(clustersCollection, key, &tracked); err != nil {
+		d.logger.WithError(err).Error("Failed to persist restart storm cluster")
+	}
+}
+
+// Untrack removes a cluster from background analysis and forgets its incidents
+func (d *Detector) Untrack(configID, cluster string) {
+	key := storage.ClusterKey(configID, cluster)
+	d.mu.Lock()
+	delete(d.clusters, key)
+	d.mu.Unlock()
+	if err := d.documents.Delete(clustersCollection, key); err != nil && err != storage.ErrDocumentNotFound {
+		d.logger.WithError(err).Error("Failed to delete restart storm cluster")
+	}
+}
+
+func (d *Detector) state(configID, cluster string) *clusterState {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.clusters[storage.ClusterKey(configID, cluster)]
+}
+
+// Analyzed reports whether a tracked cluster has been analyzed at least once
+func (d *Detector) Analyzed(configID, cluster string) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	state, ok := d.clusters[storage.ClusterKey(configID, cluster)]
+	return ok && state.analyzedAt != nil
+}
+
+func (d *Detector) getClient(configID, cluster string) (kubernetes.Interface, error) {
+	cfg, err := d.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, err
+	}
+	return d.clientFactory.GetClientForConfig(cfg, cluster)
+}
+
+// Analyze lists the pods and nodes of a tracked cluster, records restarts since the previous
+// analysis and opens, updates or resolves incidents
+func (d *Detector) Analyze(ctx context.Context, configID, cluster string) error {
+	state := d.state(configID, cluster)
+	if state == nil {
+		return fmt.Errorf("cluster is not tracked")
+	}
+	state.analyzing.Lock()
+	defer state.analyzing.Unlock()
+
+	err := d.analyze(ctx, configID, cluster, state)
+	now := time.Now()
+	d.mu.Lock()
+	state.analyzedAt = &now
+	state.lastError = ""
+	if err != nil {
+		state.lastError = err.Error()
+	}
+	d.mu.Unlock()
+	return err
+}
+
+func (d *Detector) analyze(ctx context.Context, configID, cluster string, state *clusterState) error {
+	client, err := d.getClient(configID, cluster)
+	if err != nil {
+		return err
+	}
+	pods, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list pods: %w", err)
+	}
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		// Namespace storms are still detected without node access
+		d.logger.WithError(err).WithField("config", configID).WithField("cluster", cluster).Debug("Failed to list nodes for restart storm analysis")
+		nodes = &v1.NodeList{}
+	}
+
+	now := time.Now()
+	window := d.window()
+	since := now.Add(-window)
+
+	d.mu.RLock()
+	previous := state.counts
+	if previous == nil {
+		previous = map[string]int32{}
+	}
+	d.mu.RUnlock()
+	observed, counts := observeRestarts(pods.Items, previous, since, now)
+
+	changes := clusterChanges{
+		nodes:       map[string]*v1.Node{},
+		pods:        map[string]*v1.Pod{},
+		configMaps:  map[string]*v1.ConfigMap{},
+		secrets:     map[string]*v1.Secret{},
+		replicaSets: map[string]*appsv1.ReplicaSet{},
+		revisions:   map[string]*appsv1.ControllerRevision{},
+		deployments: map[string]string{},
+	}
+	for i := range pods.Items {
+		changes.pods[string(pods.Items[i].UID)] = &pods.Items[i]
+	}
+
+	d.mu.Lock()
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		changes.nodes[node.Name] = node
+		bootID := node.Status.NodeInfo.BootID
+		if previousBoot, ok := state.bootIDs[node.Name]; ok && bootID != "" && previousBoot != bootID {
+			state.reboots[node.Name] = now
+		}
+		state.bootIDs[node.Name] = bootID
+	}
+	for name, at := range state.reboots {
+		if at.Before(since.Add(-window)) {
+			delete(state.reboots, name)
+		}
+	}
+	changes.reboots = make(map[string]time.Time, len(state.reboots))
+	for name, at := range state.reboots {
+		changes.reboots[name] = at
+	}
+
+	var restarts []Restart
+	for _, r := range append(observed, state.restarts...) {
+		if r.At.After(since) {
+			restarts = append(restarts, r)
+		}
+	}
+	state.counts = counts
+	state.restarts = restarts
+	d.mu.Unlock()
+
+	storms := detectStorms(restarts, d.config.MinContainers)
+	namespaces := map[string]bool{}
+	for _, s := range storms {
+		for _, r := range s.restarts {
+			namespaces[r.Namespace] = true
+		}
+	}
+	for namespace := range namespaces {
+		d.loadChanges(ctx, client, namespace, changes)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	active := map[string]bool{}
+	for _, s := range storms {
+		id := incidentID(s.scope, s.name)
+		active[id] = true
+		incident, ok := state.incidents[id]
+		if !ok || !incident.Active {
+			incident = &Incident{ID: id, Scope: s.scope, Name: s.name, DetectedAt: now}
+			state.incidents[id] = incident
+		}
+		started := incident.StartedAt
+		s.summarize(incident)
+		if !started.IsZero() && started.Before(incident.StartedAt) {
+			incident.StartedAt = started
+		}
+		incident.Active = true
+		incident.ResolvedAt = nil
+		incident.Causes = correlate(s, incident, changes, window)
+	}
+	retention := time.Duration(d.config.ResolvedRetentionMinutes) * time.Minute
+	for id, incident := range state.incidents {
+		if active[id] {
+			continue
+		}
+		if incident.Active {
+			resolved := now
+			incident.Active = false
+			incident.ResolvedAt = &resolved
+		}
+		if incident.ResolvedAt != nil && incident.ResolvedAt.Before(now.Add(-retention)) {
+			delete(state.incidents, id)
+		}
+	}
+	return nil
+}
+
+// loadChanges adds the ConfigMaps, Secrets, ReplicaSets and controller revisions of a namespace
+// to the changes a storm is correlated with. Objects the caller may not list are skipped.
+func (d *Detector) loadChanges(ctx context.Context, client kubernetes.Interface, namespace string, changes clusterChanges) {
+	log := d.logger.WithField("namespace", namespace)
+	if list, err := client.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+		for i := range list.Items {
+			changes.configMaps[namespace+"/"+list.Items[i].Name] = &list.Items[i]
+		}
+	} else {
+		log.WithError(err).Debug("Failed to list configmaps for restart storm correlation")
+	}
+	if list, err := client.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+		for i := range list.Items {
+			list.Items[i].Data, list.Items[i].StringData = nil, nil
+			changes.secrets[namespace+"/"+list.Items[i].Name] = &list.Items[i]
+		}
+	} else {
+		log.WithError(err).Debug("Failed to list secrets for restart storm correlation")
+	}
+	if list, err := client.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+		for i := range list.Items {
+			rs := &list.Items[i]
+			changes.replicaSets[namespace+"/"+rs.Name] = rs
+			if owner := metav1.GetControllerOf(rs); owner != nil && owner.Kind == "Deployment" {
+				changes.deployments[namespace+"/"+rs.Name] = owner.Name
+			}
+		}
+	} else {
+		log.WithError(err).Debug("Failed to list replicasets for restart storm correlation")
+	}
+	if list, err := client.AppsV1().ControllerRevisions(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+		for i := range list.Items {
+			changes.revisions[namespace+"/"+list.Items[i].Name] = &list.Items[i]
+		}
+	} else {
+		log.WithError(err).Debug("Failed to list controller revisions for restart storm correlation")
+	}
+}
+
+// Report returns the incidents of a cluster, active ones first and then the most recently
+// resolved, each group ordered by restarts
+func (d *Detector) Report(configID, cluster string) Report {
+	report := Report{
+		ConfigID:      configID,
+		Cluster:       cluster,
+		WindowMinutes: d.config.WindowMinutes,
+		MinContainers: d.config.MinContainers,
+		Incidents:     []Incident{},
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	state, ok := d.clusters[storage.ClusterKey(configID, cluster)]
+	if !ok {
+		return report
+	}
+	report.AnalyzedAt = state.analyzedAt
+	report.LastError = state.lastError
+	for _, incident := range state.incidents {
+		report.Incidents = append(report.Incidents, *incident)
+	}
+	sort.Slice(report.Incidents, func(i, j int) bool {
+		a, b := report.Incidents[i], report.Incidents[j]
+		if a.Active != b.Active {
+			return a.Active
+		}
+		if !a.Active && !a.ResolvedAt.Equal(*b.ResolvedAt) {
+			return a.ResolvedAt.After(*b.ResolvedAt)
+		}
+		if a.Containers != b.Containers {
+			return a.Containers > b.Containers
+		}
+		return a.ID < b.ID
+	})
+	return report
+}
@@ -0,0 +1,452 @@
+package restartstorms
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Incident scopes
+const (
+	ScopeNamespace = "namespace"
+	ScopeNode      = "node"
+)
+
+// Likely cause kinds
+const (
+	CauseNodeReboot    = "node-reboot"
+	CauseNodeReadiness = "node-readiness"
+	CauseConfigChange  = "config-change"
+	CauseRollout       = "rollout"
+)
+
+// maxAffected bounds the containers listed in an incident
+const maxAffected = 50
+
+// Restart is one or more restarts of a container seen between two analyses
+type Restart struct {
+	Namespace string
+	Pod       string
+	PodUID    string
+	Container string
+	Node      string
+	Reason    string // termination reason of the restarted instance, e.g. Error or OOMKilled
+	Count     int
+	At        time.Time
+}
+
+func containerKey(podUID, container string) string {
+	return podUID + "/" + container
+}
+
+// observeRestarts compares the pods' restart counts with those seen in the previous analysis and
+// returns the restarts since then, along with the counts to compare against next time.
+// Containers seen for the first time count once if their last termination is after since.
+func observeRestarts(pods []v1.Pod, previous map[string]int32, since, now time.Time) ([]Restart, map[string]int32) {
+	var restarts []Restart
+	counts := make(map[string]int32, len(previous))
+	for i := range pods {
+		pod := &pods[i]
+		statuses := append(append([]v1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+		for _, status := range statuses {
+			key := containerKey(string(pod.UID), status.Name)
+			counts[key] = status.RestartCount
+			if status.RestartCount == 0 {
+				continue
+			}
+
+			at := now
+			reason := ""
+			if terminated := status.LastTerminationState.Terminated; terminated != nil {
+				reason = terminated.Reason
+				if !terminated.FinishedAt.IsZero() {
+					at = terminated.FinishedAt.Time
+				}
+			}
+
+			count := 0
+			if seen, ok := previous[key]; ok {
+				count = int(status.RestartCount - seen)
+			} else if at.After(since) && !at.After(now) {
+				count = 1
+			}
+			if count <= 0 {
+				continue
+			}
+			restarts = append(restarts, Restart{
+				Namespace: pod.Namespace,
+				Pod:       pod.Name,
+				PodUID:    string(pod.UID),
+				Container: status.Name,
+				Node:      pod.Spec.NodeName,
+				Reason:    reason,
+				Count:     count,
+				At:        at,
+			})
+		}
+	}
+	return restarts, counts
+}
+
+// AffectedContainer is a container that restarted during an incident
+type AffectedContainer struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+	Node      string `json:"node,omitempty"`
+	Restarts  int    `json:"restarts"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// Cause is a change that may explain an incident
+type Cause struct {
+	Kind   string    `json:"kind"`
+	Object string    `json:"object"` // e.g. node/worker-1, configmap/shop/settings, deployment/shop/api
+	At     time.Time `json:"at"`
+	Detail string    `json:"detail,omitempty"`
+}
+
+// Incident is a restart storm: many containers restarting within one namespace or on one node
+// within the detection window
+type Incident struct {
+	ID            string              `json:"id"`
+	Scope         string              `json:"scope"` // namespace or node
+	Name          string              `json:"name"`
+	Active        bool                `json:"active"`
+	StartedAt     time.Time           `json:"startedAt"` // first restart of the storm
+	LastRestartAt time.Time           `json:"lastRestartAt"`
+	DetectedAt    time.Time           `json:"detectedAt"`
+	ResolvedAt    *time.Time          `json:"resolvedAt,omitempty"`
+	Containers    int                 `json:"containers"` // distinct containers restarted within the window
+	Restarts      int                 `json:"restarts"`
+	Namespaces    []string            `json:"namespaces,omitempty"` // for node incidents
+	Nodes         []string            `json:"nodes,omitempty"`      // for namespace incidents
+	Reasons       map[string]int      `json:"reasons,omitempty"`    // restarts by termination reason
+	Affected      []AffectedContainer `json:"affected"`
+	Causes        []Cause             `json:"causes"`
+}
+
+func incidentID(scope, name string) string {
+	return scope + "/" + name
+}
+
+// storm is a group of restarts that crossed the threshold
+type storm struct {
+	scope    string
+	name     string
+	restarts []Restart
+}
+
+// detectStorms groups the restarts within the window by namespace and by node and returns the
+// groups in which at least minContainers distinct containers restarted
+func detectStorms(restarts []Restart, minContainers int) []storm {
+	groups := map[string]*storm{}
+	add := func(scope, name string, r Restart) {
+		if name == "" {
+			return
+		}
+		id := incidentID(scope, name)
+		g, ok := groups[id]
+		if !ok {
+			g = &storm{scope: scope, name: name}
+			groups[id] = g
+		}
+		g.restarts = append(g.restarts, r)
+	}
+	for _, r := range restarts {
+		add(ScopeNamespace, r.Namespace, r)
+		add(ScopeNode, r.Node, r)
+	}
+
+	var storms []storm
+	for _, g := range groups {
+		containers := map[string]bool{}
+		for _, r := range g.restarts {
+			containers[containerKey(r.PodUID, r.Container)] = true
+		}
+		if len(containers) >= minContainers {
+			storms = append(storms, *g)
+		}
+	}
+	sort.Slice(storms, func(i, j int) bool {
+		return incidentID(storms[i].scope, storms[i].name) < incidentID(storms[j].scope, storms[j].name)
+	})
+	return storms
+}
+
+// summarize fills the restart statistics of an incident from its storm
+func (s storm) summarize(incident *Incident) {
+	affected := map[string]*AffectedContainer{}
+	namespaces := map[string]bool{}
+	nodes := map[string]bool{}
+	incident.Restarts = 0
+	incident.Reasons = map[string]int{}
+	for i, r := range s.restarts {
+		if i == 0 || r.At.Before(incident.StartedAt) {
+			incident.StartedAt = r.At
+		}
+		if r.At.After(incident.LastRestartAt) {
+			incident.LastRestartAt = r.At
+		}
+		incident.Restarts += r.Count
+		if r.Reason != "" {
+			incident.Reasons[r.Reason] += r.Count
+		}
+		namespaces[r.Namespace] = true
+		if r.Node != "" {
+			nodes[r.Node] = true
+		}
+		key := containerKey(r.PodUID, r.Container)
+		a, ok := affected[key]
+		if !ok {
+			a = &AffectedContainer{Namespace: r.Namespace, Pod: r.Pod, Container: r.Container, Node: r.Node}
+			affected[key] = a
+		}
+		a.Restarts += r.Count
+		if r.Reason != "" {
+			a.Reason = r.Reason
+		}
+	}
+	incident.Containers = len(affected)
+
+	incident.Affected = make([]AffectedContainer, 0, len(affected))
+	for _, a := range affected {
+		incident.Affected = append(incident.Affected, *a)
+	}
+	sort.Slice(incident.Affected, func(i, j int) bool {
+		a, b := incident.Affected[i], incident.Affected[j]
+		if a.Restarts != b.Restarts {
+			return a.Restarts > b.Restarts
+		}
+		return a.Namespace+"/"+a.Pod+"/"+a.Container < b.Namespace+"/"+b.Pod+"/"+b.Container
+	})
+	if len(incident.Affected) > maxAffected {
+		incident.Affected = incident.Affected[:maxAffected]
+	}
+
+	incident.Namespaces, incident.Nodes = nil, nil
+	if s.scope == ScopeNode {
+		incident.Namespaces = sortedKeys(namespaces)
+	} else {
+		incident.Nodes = sortedKeys(nodes)
+	}
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// clusterChanges is what the correlation looks at to explain a storm
+type clusterChanges struct {
+	nodes       map[string]*v1.Node
+	reboots     map[string]time.Time // node name to when its boot ID was seen changing
+	pods        map[string]*v1.Pod   // by UID
+	configMaps  map[string]*v1.ConfigMap
+	secrets     map[string]*v1.Secret
+	replicaSets map[string]*appsv1.ReplicaSet
+	revisions   map[string]*appsv1.ControllerRevision
+	deployments map[string]string // ReplicaSet namespace/name to its deployment name
+}
+
+// correlate returns the changes between lookback before the storm started and its last restart
+// that may have caused it: node reboots and readiness changes, edits of referenced ConfigMaps
+// and Secrets, and rollouts of the restarted pods' workloads
+func correlate(s storm, incident *Incident, changes clusterChanges, lookback time.Duration) []Cause {
+	from := incident.StartedAt.Add(-lookback)
+	to := incident.LastRestartAt
+	inRange := func(at time.Time) bool { return !at.Before(from) && !at.After(to) }
+
+	causes := []Cause{}
+	seen := map[string]bool{}
+	addCause := func(cause Cause) {
+		key := cause.Kind + "|" + cause.Object
+		if seen[key] || !inRange(cause.At) {
+			return
+		}
+		seen[key] = true
+		causes = append(causes, cause)
+	}
+
+	nodeNames := incident.Nodes
+	if s.scope == ScopeNode {
+		nodeNames = []string{s.name}
+	}
+	for _, name := range nodeNames {
+		if at, ok := changes.reboots[name]; ok {
+			addCause(Cause{Kind: CauseNodeReboot, Object: "node/" + name, At: at, Detail: "boot ID changed"})
+		}
+		node, ok := changes.nodes[name]
+		if !ok {
+			continue
+		}
+		for _, condition := range node.Status.Conditions {
+			if condition.Type != v1.NodeReady {
+				continue
+			}
+			detail := "became Ready"
+			if condition.Status != v1.ConditionTrue {
+				detail = "became NotReady"
+			}
+			if condition.Reason != "" {
+				detail += " (" + condition.Reason + ")"
+			}
+			addCause(Cause{Kind: CauseNodeReadiness, Object: "node/" + name, At: condition.LastTransitionTime.Time, Detail: detail})
+		}
+	}
+
+	for _, r := range s.restarts {
+		pod, ok := changes.pods[r.PodUID]
+		if !ok {
+			continue
+		}
+		for _, ref := range configReferences(pod) {
+			var obj metav1.Object
+			switch ref.kind {
+			case "configmap":
+				if cm, ok := changes.configMaps[pod.Namespace+"/"+ref.name]; ok {
+					obj = cm
+				}
+			case "secret":
+				if secret, ok := changes.secrets[pod.Namespace+"/"+ref.name]; ok {
+					obj = secret
+				}
+			}
+			if obj == nil {
+				continue
+			}
+			if at, manager := lastModified(obj); !at.IsZero() {
+				detail := "modified"
+				if manager != "" {
+					detail += " by " + manager
+				}
+				addCause(Cause{Kind: CauseConfigChange, Object: ref.kind + "/" + pod.Namespace + "/" + ref.name, At: at, Detail: detail})
+			}
+		}
+		if cause, ok := rolloutCause(pod, changes); ok {
+			addCause(cause)
+		}
+	}
+
+	sort.Slice(causes, func(i, j int) bool { return causes[i].At.Before(causes[j].At) })
+	return causes
+}
+
+type configReference struct {
+	kind string
+	name string
+}
+
+// configReferences lists the ConfigMaps and Secrets a pod mounts or reads environment from
+func configReferences(pod *v1.Pod) []configReference {
+	var refs []configReference
+	for _, volume := range pod.Spec.Volumes {
+		if volume.ConfigMap != nil {
+			refs = append(refs, configReference{"configmap", volume.ConfigMap.Name})
+		}
+		if volume.Secret != nil {
+			refs = append(refs, configReference{"secret", volume.Secret.SecretName})
+		}
+		if volume.Projected != nil {
+			for _, source := range volume.Projected.Sources {
+				if source.ConfigMap != nil {
+					refs = append(refs, configReference{"configmap", source.ConfigMap.Name})
+				}
+				if source.Secret != nil {
+					refs = append(refs, configReference{"secret", source.Secret.Name})
+				}
+			}
+		}
+	}
+	containers := append(append([]v1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	for _, container := range containers {
+		for _, from := range container.EnvFrom {
+			if from.ConfigMapRef != nil {
+				refs = append(refs, configReference{"configmap", from.ConfigMapRef.Name})
+			}
+			if from.SecretRef != nil {
+				refs = append(refs, configReference{"secret", from.SecretRef.Name})
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if env.ValueFrom.ConfigMapKeyRef != nil {
+				refs = append(refs, configReference{"configmap", env.ValueFrom.ConfigMapKeyRef.Name})
+			}
+			if env.ValueFrom.SecretKeyRef != nil {
+				refs = append(refs, configReference{"secret", env.ValueFrom.SecretKeyRef.Name})
+			}
+		}
+	}
+	return refs
+}
+
+// lastModified returns when an object was last written and by which field manager, from its
+// managedFields, falling back to its creation time
+func lastModified(obj metav1.Object) (time.Time, string) {
+	at, manager := obj.GetCreationTimestamp().Time, ""
+	for _, entry := range obj.GetManagedFields() {
+		if entry.Time != nil && !entry.Time.Time.Before(at) {
+			at, manager = entry.Time.Time, entry.Manager
+		}
+	}
+	return at, manager
+}
+
+// rolloutCause reports the creation of the pod's ReplicaSet or controller revision, which
+// marks a rollout of its Deployment, StatefulSet or DaemonSet
+func rolloutCause(pod *v1.Pod, changes clusterChanges) (Cause, bool) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return Cause{}, false
+	}
+	switch owner.Kind {
+	case "ReplicaSet":
+		rs, ok := changes.replicaSets[pod.Namespace+"/"+owner.Name]
+		if !ok {
+			return Cause{}, false
+		}
+		object := "replicaset/" + pod.Namespace + "/" + rs.Name
+		if deployment, ok := changes.deployments[pod.Namespace+"/"+rs.Name]; ok {
+			object = "deployment/" + pod.Namespace + "/" + deployment
+		}
+		return Cause{Kind: CauseRollout, Object: object, At: rs.CreationTimestamp.Time, Detail: "images " + podImages(&rs.Spec.Template.Spec)}, true
+	case "StatefulSet", "DaemonSet":
+		hash := pod.Labels[appsv1.ControllerRevisionHashLabelKey]
+		if hash == "" {
+			return Cause{}, false
+		}
+		// StatefulSet pods carry the full revision name, DaemonSet pods only its hash suffix
+		revision, ok := changes.revisions[pod.Namespace+"/"+hash]
+		if !ok {
+			revision, ok = changes.revisions[pod.Namespace+"/"+owner.Name+"-"+hash]
+		}
+		if !ok {
+			return Cause{}, false
+		}
+		object := "statefulset/" + pod.Namespace + "/" + owner.Name
+		if owner.Kind == "DaemonSet" {
+			object = "daemonset/" + pod.Namespace + "/" + owner.Name
+		}
+		return Cause{Kind: CauseRollout, Object: object, At: revision.CreationTimestamp.Time, Detail: "images " + podImages(&pod.Spec)}, true
+	}
+	return Cause{}, false
+}
+
+func podImages(spec *v1.PodSpec) string {
+	images := make([]string, 0, len(spec.Containers))
+	for _, container := range spec.Containers {
+		images = append(images, container.Image)
+	}
+	return strings.Join(images, ", ")
+}
@@ -0,0 +1,156 @@
+package restartstorms
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func restartingPod(name, node string, restarts int32, finished time.Time) v1.Pod {
+	return v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", UID: types.UID("uid-" + name)},
+		Spec:       v1.PodSpec{NodeName: node},
+		Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{{
+			Name:         "app",
+			RestartCount: restarts,
+			LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{
+				Reason:     "OOMKilled",
+				FinishedAt: metav1.NewTime(finished),
+			}},
+		}}},
+	}
+}
+
+func TestObserveRestarts(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	since := now.Add(-10 * time.Minute)
+	pods := []v1.Pod{
+		restartingPod("recent", "node-a", 4, now.Add(-time.Minute)),
+		restartingPod("old", "node-a", 9, now.Add(-time.Hour)),
+		restartingPod("seen", "node-b", 7, now.Add(-30*time.Second)),
+	}
+	previous := map[string]int32{containerKey("uid-seen", "app"): 5}
+
+	restarts, counts := observeRestarts(pods, previous, since, now)
+	if len(restarts) != 2 {
+		t.Fatalf("observeRestarts() = %+v, want restarts of recent and seen", restarts)
+	}
+	if r := restarts[0]; r.Pod != "recent" || r.Count != 1 || r.Reason != "OOMKilled" || !r.At.Equal(now.Add(-time.Minute)) {
+		t.Errorf("first-seen container restart = %+v", r)
+	}
+	if r := restarts[1]; r.Pod != "seen" || r.Count != 2 || r.Node != "node-b" {
+		t.Errorf("known container restart = %+v", r)
+	}
+	if counts[containerKey("uid-old", "app")] != 9 || len(counts) != 3 {
+		t.Errorf("counts = %v", counts)
+	}
+}
+
+func TestDetectStormsAndSummarize(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	var restarts []Restart
+	for i := 0; i < 5; i++ {
+		restarts = append(restarts, Restart{
+			Namespace: fmt.Sprintf("ns-%d", i%2),
+			Pod:       fmt.Sprintf("pod-%d", i),
+			PodUID:    fmt.Sprintf("uid-%d", i),
+			Container: "app",
+			Node:      "node-a",
+			Reason:    "Error",
+			Count:     i + 1,
+			At:        start.Add(time.Duration(i) * time.Minute),
+		})
+	}
+
+	storms := detectStorms(restarts, 5)
+	if len(storms) != 1 || storms[0].scope != ScopeNode || storms[0].name != "node-a" {
+		t.Fatalf("detectStorms() = %+v, want only the node-a storm", storms)
+	}
+
+	incident := &Incident{}
+	storms[0].summarize(incident)
+	if incident.Containers != 5 || incident.Restarts != 15 || incident.Reasons["Error"] != 15 {
+		t.Errorf("summary = %d containers, %d restarts, reasons %v", incident.Containers, incident.Restarts, incident.Reasons)
+	}
+	if !incident.StartedAt.Equal(start) || !incident.LastRestartAt.Equal(start.Add(4*time.Minute)) {
+		t.Errorf("incident span = %v - %v", incident.StartedAt, incident.LastRestartAt)
+	}
+	if len(incident.Namespaces) != 2 || incident.Affected[0].Pod != "pod-4" {
+		t.Errorf("namespaces = %v, most restarted = %+v", incident.Namespaces, incident.Affected[0])
+	}
+}
+
+func TestCorrelate(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	edited := metav1.NewTime(start.Add(-2 * time.Minute))
+	stale := metav1.NewTime(start.Add(-48 * time.Hour))
+	isController := true
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "api-7d9-x", Namespace: "shop", UID: "uid-1",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "api-7d9", Controller: &isController}},
+		},
+		Spec: v1.PodSpec{
+			NodeName: "node-a",
+			Volumes: []v1.Volume{
+				{Name: "config", VolumeSource: v1.VolumeSource{ConfigMap: &v1.ConfigMapVolumeSource{LocalObjectReference: v1.LocalObjectReference{Name: "settings"}}}},
+			},
+			Containers: []v1.Container{{
+				Name:    "app",
+				Image:   "shop/api:2.0",
+				EnvFrom: []v1.EnvFromSource{{SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "creds"}}}},
+			}},
+		},
+	}
+	changes := clusterChanges{
+		nodes: map[string]*v1.Node{"node-a": {
+			ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+			Status: v1.NodeStatus{Conditions: []v1.NodeCondition{
+				{Type: v1.NodeReady, Status: v1.ConditionTrue, Reason: "KubeletReady", LastTransitionTime: metav1.NewTime(start.Add(-time.Minute))},
+			}},
+		}},
+		reboots: map[string]time.Time{"node-a": start.Add(-90 * time.Second)},
+		pods:    map[string]*v1.Pod{"uid-1": pod},
+		configMaps: map[string]*v1.ConfigMap{"shop/settings": {ObjectMeta: metav1.ObjectMeta{
+			Name: "settings", CreationTimestamp: stale,
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl-edit", Time: &edited}},
+		}}},
+		secrets: map[string]*v1.Secret{"shop/creds": {ObjectMeta: metav1.ObjectMeta{Name: "creds", CreationTimestamp: stale}}},
+		replicaSets: map[string]*appsv1.ReplicaSet{"shop/api-7d9": {
+			ObjectMeta: metav1.ObjectMeta{Name: "api-7d9", CreationTimestamp: metav1.NewTime(start.Add(-3 * time.Minute))},
+			Spec:       appsv1.ReplicaSetSpec{Template: v1.PodTemplateSpec{Spec: pod.Spec}},
+		}},
+		deployments: map[string]string{"shop/api-7d9": "api"},
+	}
+
+	s := storm{scope: ScopeNamespace, name: "shop", restarts: []Restart{
+		{Namespace: "shop", Pod: pod.Name, PodUID: "uid-1", Container: "app", Node: "node-a", Count: 3, At: start},
+	}}
+	incident := &Incident{}
+	s.summarize(incident)
+	causes := correlate(s, incident, changes, 10*time.Minute)
+
+	want := []string{
+		CauseRollout + " deployment/shop/api",
+		CauseConfigChange + " configmap/shop/settings",
+		CauseNodeReboot + " node/node-a",
+		CauseNodeReadiness + " node/node-a",
+	}
+	if len(causes) != len(want) {
+		t.Fatalf("correlate() = %+v, want %v", causes, want)
+	}
+	for i, cause := range causes {
+		if got := cause.Kind + " " + cause.Object; got != want[i] {
+			t.Errorf("cause %d = %q, want %q", i, got, want[i])
+		}
+	}
+	if causes[1].Detail != "modified by kubectl-edit" || causes[0].Detail != "images shop/api:2.0" {
+		t.Errorf("cause details = %q, %q", causes[0].Detail, causes[1].Detail)
+	}
+}
@@ -0,0 +1,330 @@
+package rollouts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Rollout statuses
+const (
+	StatusProgressing = "progressing"
+	StatusComplete    = "complete"
+	StatusFailed      = "failed"
+	StatusSuperseded  = "superseded" // replaced by a newer revision before completion was observed
+)
+
+const (
+	revisionAnnotation        = "deployment.kubernetes.io/revision"
+	revisionHistoryAnnotation = "deployment.kubernetes.io/revision-history"
+)
+
+// Rollout is one revision rollout of a deployment
+type Rollout struct {
+	ID          string     `json:"id"`
+	ConfigID    string     `json:"configId"`
+	Cluster     string     `json:"cluster,omitempty"`
+	Namespace   string     `json:"namespace"`
+	Deployment  string     `json:"deployment"`
+	Revision    int64      `json:"revision"`
+	ReplicaSet  string     `json:"replicaSet"`
+	Images      []string   `json:"images,omitempty"`
+	Status      string     `json:"status"`
+	Reason      string     `json:"reason,omitempty"`
+	StartedAt   time.Time  `json:"startedAt"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	// StartEstimated is set when the start time was first observed rather than read from the
+	// cluster, which happens for rollbacks that reuse an existing ReplicaSet
+	StartEstimated bool `json:"startEstimated,omitempty"`
+	// Rollback is set when the revision reuses an earlier ReplicaSet's template
+	Rollback bool `json:"rollback,omitempty"`
+	// RolledBack is set when the next revision of the deployment was a rollback
+	RolledBack bool `json:"rolledBack,omitempty"`
+}
+
+// Duration returns how long the rollout took, if it finished
+func (r *Rollout) Duration() (time.Duration, bool) {
+	if r.CompletedAt == nil || r.Status != StatusComplete {
+		return 0, false
+	}
+	d := r.CompletedAt.Sub(r.StartedAt)
+	if d < 0 {
+		return 0, false
+	}
+	return d, true
+}
+
+func rolloutID(configID, cluster, namespace, deployment string, revision int64) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%s|%s|%d", configID, cluster, namespace, deployment, revision)))
+	return hex.EncodeToString(sum[:12])
+}
+
+func parseRevision(value string) int64 {
+	revision, _ := strconv.ParseInt(value, 10, 64)
+	return revision
+}
+
+func parseHistory(value string) []int64 {
+	var revisions []int64
+	for _, part := range strings.Split(value, ",") {
+		if r := parseRevision(strings.TrimSpace(part)); r > 0 {
+			revisions = append(revisions, r)
+		}
+	}
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i] < revisions[j] })
+	return revisions
+}
+
+func progressingCondition(deployment *appsv1.Deployment) *appsv1.DeploymentCondition {
+	for i := range deployment.Status.Conditions {
+		if deployment.Status.Conditions[i].Type == appsv1.DeploymentProgressing {
+			return &deployment.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// rolloutComplete reports whether the deployment's current revision is fully rolled out
+func rolloutComplete(deployment *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	status := deployment.Status
+	return status.ObservedGeneration >= deployment.Generation &&
+		status.UpdatedReplicas == replicas &&
+		status.Replicas == replicas &&
+		status.AvailableReplicas == replicas
+}
+
+// ObserveDeployment derives rollout records for a deployment from its ReplicaSets and conditions and
+// merges them with the existing records (keyed by revision). It returns the records that
+// were created or changed. Start times come from ReplicaSet creation; completion and failure
+// come from the Progressing condition of the current revision.
+func ObserveDeployment(configID, cluster string, deployment *appsv1.Deployment, replicaSets []appsv1.ReplicaSet, existing map[int64]*Rollout, now time.Time) []*Rollout {
+	records := make(map[int64]*Rollout, len(existing))
+	for revision, r := range existing {
+		copied := *r
+		records[revision] = &copied
+	}
+	changed := map[int64]bool{}
+	record := func(revision int64, rs *appsv1.ReplicaSet) *Rollout {
+		if r, ok := records[revision]; ok {
+			return r
+		}
+		r := &Rollout{
+			ID:         rolloutID(configID, cluster, deployment.Namespace, deployment.Name, revision),
+			ConfigID:   configID,
+			Cluster:    cluster,
+			Namespace:  deployment.Namespace,
+			Deployment: deployment.Name,
+			Revision:   revision,
+			ReplicaSet: rs.Name,
+			Status:     StatusProgressing,
+		}
+		for _, c := range rs.Spec.Template.Spec.Containers {
+			r.Images = append(r.Images, c.Image)
+		}
+		records[revision] = r
+		changed[revision] = true
+		return r
+	}
+
+	current := parseRevision(deployment.Annotations[revisionAnnotation])
+	for i := range replicaSets {
+		rs := &replicaSets[i]
+		if !metav1.IsControlledBy(rs, deployment) {
+			continue
+		}
+		revision := parseRevision(rs.Annotations[revisionAnnotation])
+		if revision == 0 {
+			continue
+		}
+		history := parseHistory(rs.Annotations[revisionHistoryAnnotation])
+		if len(history) == 0 {
+			if _, ok := records[revision]; !ok {
+				record(revision, rs).StartedAt = rs.CreationTimestamp.Time
+			}
+			continue
+		}
+		// The ReplicaSet was created for its first revision and reused for later ones
+		if _, ok := records[history[0]]; !ok {
+			record(history[0], rs).StartedAt = rs.CreationTimestamp.Time
+		}
+		if _, ok := records[revision]; !ok && revision == current {
+			r := record(revision, rs)
+			r.StartedAt = now
+			r.StartEstimated = true
+			r.Rollback = true
+		}
+	}
+
+	// Completion and failure are only visible for the current revision
+	if r, ok := records[current]; ok {
+		cond := progressingCondition(deployment)
+		switch {
+		case cond != nil && cond.Status == corev1.ConditionFalse && cond.Reason == "ProgressDeadlineExceeded":
+			if r.Status != StatusFailed {
+				at := cond.LastUpdateTime.Time
+				r.Status, r.Reason, r.CompletedAt = StatusFailed, cond.Message, &at
+				changed[current] = true
+			}
+		case rolloutComplete(deployment) && r.Status != StatusComplete:
+			at := now
+			if cond != nil && cond.Reason == "NewReplicaSetAvailable" && !cond.LastUpdateTime.IsZero() {
+				at = cond.LastUpdateTime.Time
+			}
+			if at.Before(r.StartedAt) {
+				at = r.StartedAt
+			}
+			r.Status, r.Reason, r.CompletedAt = StatusComplete, "", &at
+			changed[current] = true
+		}
+	}
+
+	revisions := make([]int64, 0, len(records))
+	for revision := range records {
+		revisions = append(revisions, revision)
+	}
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i] < revisions[j] })
+	for i, revision := range revisions {
+		r := records[revision]
+		if revision < current && r.Status == StatusProgressing {
+			r.Status = StatusSuperseded
+			changed[revision] = true
+		}
+		if i+1 < len(revisions) && records[revisions[i+1]].Rollback && !r.RolledBack {
+			r.RolledBack = true
+			changed[revision] = true
+		}
+	}
+
+	var result []*Rollout
+	for _, revision := range revisions {
+		if changed[revision] {
+			result = append(result, records[revision])
+		}
+	}
+	return result
+}
+
+// WorkloadStats are DORA-style delivery metrics for one deployment, or a whole scope
+type WorkloadStats struct {
+	Namespace             string     `json:"namespace,omitempty"`
+	Deployment            string     `json:"deployment,omitempty"`
+	Rollouts              int        `json:"rollouts"`
+	Completed             int        `json:"completed"`
+	Failed                int        `json:"failed"`
+	Rollbacks             int        `json:"rollbacks"`
+	InProgress            int        `json:"inProgress"`
+	DeploysPerDay         float64    `json:"deploysPerDay"`
+	DeploysPerWeek        float64    `json:"deploysPerWeek"`
+	AvgDurationSeconds    float64    `json:"avgDurationSeconds"`
+	MedianDurationSeconds float64    `json:"medianDurationSeconds"`
+	MaxDurationSeconds    float64    `json:"maxDurationSeconds"`
+	ChangeFailureRate     float64    `json:"changeFailureRate"` // share of rollouts that failed or were rolled back
+	LastDeployedAt        *time.Time `json:"lastDeployedAt,omitempty"`
+}
+
+// Analytics summarizes rollouts started within a window
+type Analytics struct {
+	Since     time.Time       `json:"since"`
+	Until     time.Time       `json:"until"`
+	Totals    WorkloadStats   `json:"totals"`
+	Workloads []WorkloadStats `json:"workloads"`
+}
+
+// Analyze computes per-deployment and total statistics for rollouts started in [since, until]
+func Analyze(rollouts []Rollout, since, until time.Time) Analytics {
+	analytics := Analytics{Since: since, Until: until, Workloads: []WorkloadStats{}}
+	days := until.Sub(since).Hours() / 24
+
+	byWorkload := map[string][]Rollout{}
+	var all []Rollout
+	for _, r := range rollouts {
+		if r.StartedAt.Before(since) || r.StartedAt.After(until) {
+			continue
+		}
+		key := r.Namespace + "/" + r.Deployment
+		byWorkload[key] = append(byWorkload[key], r)
+		all = append(all, r)
+	}
+	for _, group := range byWorkload {
+		stats := computeStats(group, days)
+		stats.Namespace, stats.Deployment = group[0].Namespace, group[0].Deployment
+		analytics.Workloads = append(analytics.Workloads, stats)
+	}
+	sort.Slice(analytics.Workloads, func(i, j int) bool {
+		a, b := analytics.Workloads[i], analytics.Workloads[j]
+		if a.Rollouts != b.Rollouts {
+			return a.Rollouts > b.Rollouts
+		}
+		return a.Namespace+"/"+a.Deployment < b.Namespace+"/"+b.Deployment
+	})
+	analytics.Totals = computeStats(all, days)
+	return analytics
+}
+
+func computeStats(rollouts []Rollout, days float64) WorkloadStats {
+	var stats WorkloadStats
+	var durations []float64
+	for i := range rollouts {
+		r := &rollouts[i]
+		stats.Rollouts++
+		switch r.Status {
+		case StatusComplete:
+			stats.Completed++
+		case StatusFailed:
+			stats.Failed++
+		case StatusProgressing:
+			stats.InProgress++
+		}
+		if r.Rollback {
+			stats.Rollbacks++
+		}
+		if d, ok := r.Duration(); ok && !r.StartEstimated {
+			durations = append(durations, d.Seconds())
+		}
+		if stats.LastDeployedAt == nil || r.StartedAt.After(*stats.LastDeployedAt) {
+			started := r.StartedAt
+			stats.LastDeployedAt = &started
+		}
+	}
+	if days > 0 {
+		stats.DeploysPerDay = float64(stats.Rollouts) / days
+		stats.DeploysPerWeek = stats.DeploysPerDay * 7
+	}
+	if stats.Rollouts > 0 {
+		failures := 0
+		for i := range rollouts {
+			if rollouts[i].Status == StatusFailed || rollouts[i].RolledBack {
+				failures++
+			}
+		}
+		stats.ChangeFailureRate = float64(failures) / float64(stats.Rollouts)
+	}
+	if len(durations) > 0 {
+		sort.Float64s(durations)
+		total := 0.0
+		for _, d := range durations {
+			total += d
+		}
+		stats.AvgDurationSeconds = total / float64(len(durations))
+		stats.MaxDurationSeconds = durations[len(durations)-1]
+		mid := len(durations) / 2
+		if len(durations)%2 == 1 {
+			stats.MedianDurationSeconds = durations[mid]
+		} else {
+			stats.MedianDurationSeconds = (durations[mid-1] + durations[mid]) / 2
+		}
+	}
+	return stats
+}
@@ -0,0 +1,159 @@
+package rollouts
+
+import (
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var base = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func testDeployment(revision string, replicas int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "web",
+			Namespace:   "prod",
+			UID:         "deploy-uid",
+			Generation:  2,
+			Annotations: map[string]string{revisionAnnotation: revision},
+		},
+		Spec: appsv1.DeploymentSpec{Replicas: &replicas},
+	}
+}
+
+func testReplicaSet(deployment *appsv1.Deployment, name, revision, history string, created time.Time) appsv1.ReplicaSet {
+	controller := true
+	annotations := map[string]string{revisionAnnotation: revision}
+	if history != "" {
+		annotations[revisionHistoryAnnotation] = history
+	}
+	return appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         deployment.Namespace,
+			Annotations:       annotations,
+			CreationTimestamp: metav1.NewTime(created),
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "apps/v1", Kind: "Deployment", Name: deployment.Name, UID: deployment.UID, Controller: &controller,
+			}},
+		},
+	}
+}
+
+func byRevision(rollouts []*Rollout) map[int64]*Rollout {
+	result := map[int64]*Rollout{}
+	for _, r := range rollouts {
+		result[r.Revision] = r
+	}
+	return result
+}
+
+func TestObserveDeploymentCompletesCurrentRevision(t *testing.T) {
+	deployment := testDeployment("2", 3)
+	deployment.Status = appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 3,
+		Conditions: []appsv1.DeploymentCondition{{
+			Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: "NewReplicaSetAvailable",
+			LastUpdateTime: metav1.NewTime(base.Add(90 * time.Second)),
+		}}}
+	replicaSets := []appsv1.ReplicaSet{
+		testReplicaSet(deployment, "web-1", "1", "", base.Add(-time.Hour)),
+		testReplicaSet(deployment, "web-2", "2", "", base),
+	}
+
+	records := byRevision(ObserveDeployment("cfg", "", deployment, replicaSets, nil, base.Add(time.Hour)))
+	if len(records) != 2 {
+		t.Fatalf("expected 2 rollouts, got %d", len(records))
+	}
+	current := records[2]
+	if current.Status != StatusComplete {
+		t.Fatalf("expected revision 2 complete, got %s", current.Status)
+	}
+	if d, ok := current.Duration(); !ok || d != 90*time.Second {
+		t.Errorf("expected 90s duration, got %v (%v)", d, ok)
+	}
+	if records[1].Status != StatusSuperseded {
+		t.Errorf("expected revision 1 superseded, got %s", records[1].Status)
+	}
+
+	// A second observation with the same state changes nothing
+	existing := map[int64]*Rollout{1: records[1], 2: current}
+	if changed := ObserveDeployment("cfg", "", deployment, replicaSets, existing, base.Add(2*time.Hour)); len(changed) != 0 {
+		t.Errorf("expected no changes on re-observation, got %d", len(changed))
+	}
+}
+
+func TestObserveDeploymentFailureAndRollback(t *testing.T) {
+	deployment := testDeployment("2", 2)
+	deployment.Status = appsv1.DeploymentStatus{Conditions: []appsv1.DeploymentCondition{{
+		Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded",
+		Message: "ReplicaSet web-2 has timed out progressing", LastUpdateTime: metav1.NewTime(base.Add(10 * time.Minute)),
+	}}}
+	replicaSets := []appsv1.ReplicaSet{
+		testReplicaSet(deployment, "web-1", "1", "", base.Add(-time.Hour)),
+		testReplicaSet(deployment, "web-2", "2", "", base),
+	}
+	records := byRevision(ObserveDeployment("cfg", "", deployment, replicaSets, nil, base.Add(15*time.Minute)))
+	if records[2].Status != StatusFailed {
+		t.Fatalf("expected revision 2 failed, got %s", records[2].Status)
+	}
+
+	// Rolling back reuses web-1 as revision 3
+	deployment = testDeployment("3", 2)
+	deployment.Status = appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2}
+	replicaSets = []appsv1.ReplicaSet{
+		testReplicaSet(deployment, "web-1", "3", "1", base.Add(-time.Hour)),
+		testReplicaSet(deployment, "web-2", "2", "", base),
+	}
+	now := base.Add(20 * time.Minute)
+	existing := map[int64]*Rollout{1: records[1], 2: records[2]}
+	changed := byRevision(ObserveDeployment("cfg", "", deployment, replicaSets, existing, now))
+	rollback, ok := changed[3]
+	if !ok || !rollback.Rollback || !rollback.StartEstimated || rollback.Status != StatusComplete {
+		t.Fatalf("expected completed rollback revision 3, got %+v", rollback)
+	}
+	if !changed[2].RolledBack {
+		t.Errorf("expected revision 2 to be marked rolled back")
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	completed := func(namespace, name string, revision int64, start time.Time, d time.Duration) Rollout {
+		end := start.Add(d)
+		return Rollout{Namespace: namespace, Deployment: name, Revision: revision, Status: StatusComplete, StartedAt: start, CompletedAt: &end}
+	}
+	failed := completed("prod", "web", 3, base.Add(48*time.Hour), time.Minute)
+	failed.Status = StatusFailed
+	rolledBack := completed("prod", "web", 2, base.Add(24*time.Hour), 3*time.Minute)
+	rolledBack.RolledBack = true
+	rollouts := []Rollout{
+		completed("prod", "web", 1, base, time.Minute),
+		rolledBack,
+		failed,
+		completed("prod", "api", 1, base, 2*time.Minute),
+		completed("prod", "old", 1, base.Add(-30*24*time.Hour), time.Minute), // outside the window
+	}
+
+	analytics := Analyze(rollouts, base.Add(-24*time.Hour), base.Add(6*24*time.Hour))
+	if len(analytics.Workloads) != 2 {
+		t.Fatalf("expected 2 workloads, got %d", len(analytics.Workloads))
+	}
+	web := analytics.Workloads[0]
+	if web.Deployment != "web" || web.Rollouts != 3 || web.Completed != 2 || web.Failed != 1 {
+		t.Fatalf("unexpected web stats: %+v", web)
+	}
+	if web.DeploysPerDay != 3.0/7 {
+		t.Errorf("expected %v deploys per day, got %v", 3.0/7, web.DeploysPerDay)
+	}
+	if web.ChangeFailureRate != 2.0/3 {
+		t.Errorf("expected change failure rate 2/3, got %v", web.ChangeFailureRate)
+	}
+	if web.AvgDurationSeconds != 120 || web.MedianDurationSeconds != 120 || web.MaxDurationSeconds != 180 {
+		t.Errorf("unexpected durations: avg %v median %v max %v", web.AvgDurationSeconds, web.MedianDurationSeconds, web.MaxDurationSeconds)
+	}
+	if analytics.Totals.Rollouts != 4 {
+		t.Errorf("expected 4 rollouts in totals, got %d", analytics.Totals.Rollouts)
+	}
+}
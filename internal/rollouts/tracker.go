@@ -0,0 +1,341 @@
+package rollouts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/config"
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Document collections used by the tracker
+const (
+	rolloutsCollection = "rollouts"
+	clustersCollection = "rollout_clusters"
+)
+
+const observeTimeout = time.Minute
+
+// TrackedCluster is a cluster whose deployments are observed in the background
+type TrackedCluster struct {
+	ConfigID   string     `json:"configId"`
+	Cluster    string     `json:"cluster,omitempty"`
+	AddedAt    time.Time  `json:"addedAt"`
+	ObservedAt *time.Time `json:"observedAt,omitempty"`
+	LastError  string     `json:"lastError,omitempty"`
+}
+
+// Tracker records deployment rollouts for the clusters it has been asked about. ReplicaSets
+// keep start times for the revision history limit only, so the tracker persists what it sees
+// to keep a longer history than the cluster does.
+type Tracker struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	documents     *storage.DocumentStore
+	logger        *logger.Logger
+	config        *config.RolloutsConfig
+
+	mu       sync.RWMutex
+	rollouts map[string]*Rollout
+	clusters map[string]*TrackedCluster
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewTracker creates a rollout tracker; call Start to begin observing tracked clusters
+func NewTracker(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, documents *storage.DocumentStore, log *logger.Logger, cfg *config.RolloutsConfig) *Tracker {
+	t := &Tracker{
+		store:         store,
+		clientFactory: clientFactory,
+		documents:     documents,
+		logger:        log,
+		config:        cfg,
+		rollouts:      make(map[string]*Rollout),
+		clusters:      make(map[string]*TrackedCluster),
+	}
+	if err := t.reload(); err != nil {
+		log.WithError(err).Error("Failed to load rollout history")
+	}
+	return t
+}
+
+func (t *Tracker) reload() error {
+	docs, err := t.documents.List(rolloutsCollection)
+	if err != nil {
+		return err
+	}
+	clusters, err := t.documents.List(clustersCollection)
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for id, data := range docs {
+		var r Rollout
+		if err := json.Unmarshal(data, &r); err != nil {
+			t.logger.WithError(err).WithField("rollout", id).Error("Skipping unreadable rollout record")
+			continue
+		}
+		t.rollouts[r.ID] = &r
+	}
+	for id, data := range clusters {
+		var c TrackedCluster
+		if err := json.Unmarshal(data, &c); err != nil {
+			t.logger.WithError(err).WithField("cluster", id).Error("Skipping unreadable tracked cluster")
+			continue
+		}
+		t.clusters[storage.ClusterKey(c.ConfigID, c.Cluster)] = &c
+	}
+	return nil
+}
+
+// Start begins observing tracked clusters in the background. A tracking interval of zero
+// disables background tracking; clusters are then only observed when queried.
+func (t *Tracker) Start() {
+	t.ctx, t.cancel = context.WithCancel(context.Background())
+	if t.config.TrackingIntervalSeconds <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(time.Duration(t.config.TrackingIntervalSeconds) * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-t.ctx.Done():
+				return
+			case <-ticker.C:
+				t.observeAll()
+			}
+		}
+	}()
+}
+
+// Stop ends background tracking
+func (t *Tracker) Stop() {
+	if t.cancel != nil {
+		t.cancel()
+	}
+}
+
+func (t *Tracker) observeAll() {
+	t.mu.RLock()
+	clusters := make([]TrackedCluster, 0, len(t.clusters))
+	for _, c := range t.clusters {
+		clusters = append(clusters, *c)
+	}
+	t.mu.RUnlock()
+
+	for _, c := range clusters {
+		if t.ctx.Err() != nil {
+			return
+		}
+		if _, err := t.store.GetKubeConfig(c.ConfigID); err != nil {
+			// The kubeconfig was removed; stop tracking the cluster but keep its history
+			t.Untrack(c.ConfigID, c.Cluster)
+			continue
+		}
+		ctx, cancel := context.WithTimeout(t.ctx, observeTimeout)
+		if err := t.Observe(ctx, c.ConfigID, c.Cluster); err != nil {
+			t.logger.WithError(err).WithField("config", c.ConfigID).WithField("cluster", c.Cluster).Warn("Failed to observe rollouts")
+		}
+		cancel()
+	}
+	t.prune()
+}
+
+// Track adds a cluster to background tracking
+func (t *Tracker) Track(configID, cluster string) {
+	key := storage.ClusterKey(configID, cluster)
+	t.mu.Lock()
+	if _, ok := t.clusters[key]; ok {
+		t.mu.Unlock()
+		return
+	}
+	c := TrackedCluster{ConfigID: configID, Cluster: cluster, AddedAt: time.Now()}
+	t.clusters[key] = &c
+	snapshot := c
+	t.mu.Unlock()
+	if err := t.documents.Put(clustersCollection, clusterDocumentID(configID, cluster), &snapshot); err != nil {
+		t.logger.WithError(err).Error("Failed to persist tracked rollout cluster")
+	}
+}
+
+// Untrack removes a cluster from background tracking
+func (t *Tracker) Untrack(configID, cluster string) {
+	t.mu.Lock()
+	delete(t.clusters, storage.ClusterKey(configID, cluster))
+	t.mu.Unlock()
+	if err := t.documents.Delete(clustersCollection, clusterDocumentID(configID, cluster)); err != nil && err != storage.ErrDocumentNotFound {
+		t.logger.WithError(err).Error("Failed to delete tracked rollout cluster")
+	}
+}
+
+// TrackedCluster returns the tracking state of a cluster, or nil if it is not tracked
+func (t *Tracker) TrackedCluster(configID, cluster string) *TrackedCluster {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if c, ok := t.clusters[storage.ClusterKey(configID, cluster)]; ok {
+		copied := *c
+		return &copied
+	}
+	return nil
+}
+
+func clusterDocumentID(configID, cluster string) string {
+	return rolloutID(configID, cluster, "", "", 0)
+}
+
+func (t *Tracker) getClient(configID, cluster string) (kubernetes.Interface, error) {
+	cfg, err := t.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, err
+	}
+	return t.clientFactory.GetClientForConfig(cfg, cluster)
+}
+
+// Observe lists the deployments and ReplicaSets of a cluster and records new or changed rollouts
+func (t *Tracker) Observe(ctx context.Context, configID, cluster string) error {
+	err := t.observe(ctx, configID, cluster)
+
+	now := time.Now()
+	t.mu.Lock()
+	c, ok := t.clusters[storage.ClusterKey(configID, cluster)]
+	if ok {
+		c.ObservedAt = &now
+		c.LastError = ""
+		if err != nil {
+			c.LastError = err.Error()
+		}
+	}
+	var snapshot TrackedCluster
+	if ok {
+		snapshot = *c
+	}
+	t.mu.Unlock()
+	if ok {
+		if perr := t.documents.Put(clustersCollection, clusterDocumentID(configID, cluster), &snapshot); perr != nil {
+			t.logger.WithError(perr).Error("Failed to persist tracked rollout cluster")
+		}
+	}
+	return err
+}
+
+func (t *Tracker) observe(ctx context.Context, configID, cluster string) error {
+	client, err := t.getClient(configID, cluster)
+	if err != nil {
+		return err
+	}
+	deployments, err := client.AppsV1().Deployments("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list deployments: %w", err)
+	}
+	replicaSets, err := client.AppsV1().ReplicaSets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list replicasets: %w", err)
+	}
+	byOwner := map[string][]appsv1.ReplicaSet{}
+	for _, rs := range replicaSets.Items {
+		if ref := metav1.GetControllerOf(&rs); ref != nil && ref.Kind == "Deployment" {
+			byOwner[rs.Namespace+"/"+ref.Name] = append(byOwner[rs.Namespace+"/"+ref.Name], rs)
+		}
+	}
+
+	now := time.Now()
+	var changed []*Rollout
+	t.mu.RLock()
+	recorded := map[string]map[int64]*Rollout{}
+	for _, r := range t.rollouts {
+		if r.ConfigID != configID || r.Cluster != cluster {
+			continue
+		}
+		key := r.Namespace + "/" + r.Deployment
+		if recorded[key] == nil {
+			recorded[key] = map[int64]*Rollout{}
+		}
+		recorded[key][r.Revision] = r
+	}
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		existing := recorded[deployment.Namespace+"/"+deployment.Name]
+		changed = append(changed, ObserveDeployment(configID, cluster, deployment, byOwner[deployment.Namespace+"/"+deployment.Name], existing, now)...)
+	}
+	t.mu.RUnlock()
+
+	if len(changed) == 0 {
+		return nil
+	}
+	t.mu.Lock()
+	for _, r := range changed {
+		t.rollouts[r.ID] = r
+	}
+	t.mu.Unlock()
+	for _, r := range changed {
+		if err := t.documents.Put(rolloutsCollection, r.ID, r); err != nil {
+			t.logger.WithError(err).WithField("rollout", r.ID).Error("Failed to persist rollout")
+		}
+	}
+	return nil
+}
+
+// prune drops rollouts older than the configured history
+func (t *Tracker) prune() {
+	if t.config.HistoryDays <= 0 {
+		return
+	}
+	cutoff := time.Now().AddDate(0, 0, -t.config.HistoryDays)
+	var expired []string
+	t.mu.Lock()
+	for id, r := range t.rollouts {
+		if r.StartedAt.Before(cutoff) {
+			expired = append(expired, id)
+			delete(t.rollouts, id)
+		}
+	}
+	t.mu.Unlock()
+	for _, id := range expired {
+		if err := t.documents.Delete(rolloutsCollection, id); err != nil && err != storage.ErrDocumentNotFound {
+			t.logger.WithError(err).WithField("rollout", id).Error("Failed to delete expired rollout")
+		}
+	}
+}
+
+// Query selects recorded rollouts
+type Query struct {
+	ConfigID   string
+	Cluster    string
+	Namespace  string
+	Deployment string
+	Since      time.Time
+}
+
+// List returns recorded rollouts matching the query, newest first
+func (t *Tracker) List(q Query) []Rollout {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	result := []Rollout{}
+	for _, r := range t.rollouts {
+		if r.ConfigID != q.ConfigID || r.Cluster != q.Cluster {
+			continue
+		}
+		if (q.Namespace != "" && r.Namespace != q.Namespace) || (q.Deployment != "" && r.Deployment != q.Deployment) {
+			continue
+		}
+		if !q.Since.IsZero() && r.StartedAt.Before(q.Since) {
+			continue
+		}
+		result = append(result, *r)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].StartedAt.After(result[j].StartedAt) })
+	return result
+}
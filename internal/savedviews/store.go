@@ -0,0 +1,209 @@
+package savedviews
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/google/uuid"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// viewsCollection is the document collection holding saved views
+const viewsCollection = "saved_views"
+
+// maxNamespaces bounds the namespaces one view fans list requests out to
+const maxNamespaces = 50
+
+// fieldPattern matches a response field path usable as a sort key or column, e.g. node.zone
+var fieldPattern = regexp.MustCompile(`^[A-Za-z0-9_]+(\.[A-Za-z0-9_]+)*$`)
+
+// View is a saved list filter: which list it applies to, the namespaces and labels it selects,
+// its sort and its columns. List endpoints apply it when given its ID as the view parameter.
+type View struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	Owner         string    `json:"owner,omitempty"` // empty for views shared with everyone
+	Shared        bool      `json:"shared"`          // an owned view other users may also apply
+	Kind          string    `json:"kind"`            // list endpoint the view is for, e.g. pods or deployments
+	ConfigID      string    `json:"configId,omitempty"`
+	Cluster       string    `json:"cluster,omitempty"`
+	Namespaces    []string  `json:"namespaces,omitempty"`
+	LabelSelector string    `json:"labelSelector,omitempty"`
+	Sort          string    `json:"sort,omitempty"`    // field to sort by, "-" prefixed for descending
+	Columns       []string  `json:"columns,omitempty"` // response fields to return
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// Validate checks that a view can be saved and normalizes its lists
+func (v *View) Validate() error {
+	v.Name = strings.TrimSpace(v.Name)
+	if v.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	v.Kind = strings.ToLower(strings.TrimSpace(v.Kind))
+	if errs := validation.IsDNS1123Label(v.Kind); v.Kind == "" || len(errs) > 0 {
+		return fmt.Errorf("kind must name a list endpoint, e.g. pods")
+	}
+	if v.Cluster != "" && v.ConfigID == "" {
+		return fmt.Errorf("configId is required with cluster")
+	}
+
+	seen := map[string]bool{}
+	var namespaces []string
+	for _, ns := range v.Namespaces {
+		ns = strings.TrimSpace(ns)
+		if ns == "" || seen[ns] {
+			continue
+		}
+		if errs := validation.IsDNS1123Label(ns); len(errs) > 0 {
+			return fmt.Errorf("invalid namespace %q: %s", ns, strings.Join(errs, "; "))
+		}
+		seen[ns] = true
+		namespaces = append(namespaces, ns)
+	}
+	if len(namespaces) > maxNamespaces {
+		return fmt.Errorf("a view may select at most %d namespaces", maxNamespaces)
+	}
+	sort.Strings(namespaces)
+	v.Namespaces = namespaces
+
+	v.LabelSelector = strings.TrimSpace(v.LabelSelector)
+	if _, err := labels.Parse(v.LabelSelector); err != nil {
+		return fmt.Errorf("invalid labelSelector: %w", err)
+	}
+	v.Sort = strings.TrimSpace(v.Sort)
+	if v.Sort != "" && !fieldPattern.MatchString(strings.TrimPrefix(v.Sort, "-")) {
+		return fmt.Errorf("invalid sort %q", v.Sort)
+	}
+	seen = map[string]bool{}
+	var columns []string
+	for _, column := range v.Columns {
+		column = strings.TrimSpace(column)
+		if column == "" || seen[column] {
+			continue
+		}
+		if !fieldPattern.MatchString(column) {
+			return fmt.Errorf("invalid column %q", column)
+		}
+		seen[column] = true
+		columns = append(columns, column)
+	}
+	v.Columns = columns
+	return nil
+}
+
+// VisibleTo reports whether a caller may apply the view: shared views and their owner's own
+func (v *View) VisibleTo(owner string) bool {
+	return v.Owner == "" || v.Shared || v.Owner == owner
+}
+
+// Filter narrows the views returned by List; empty fields match everything
+type Filter struct {
+	Owner    string // also matches shared views
+	Kind     string
+	ConfigID string // also matches views not bound to a config
+}
+
+// Store persists saved views
+type Store struct {
+	documents *storage.DocumentStore
+	logger    *logger.Logger
+}
+
+// NewStore creates a saved view store
+func NewStore(documents *storage.DocumentStore, log *logger.Logger) *Store {
+	return &Store{
+		documents: documents,
+		logger:    log,
+	}
+}
+
+// List returns the views matching the filter sorted by kind and name
+func (s *Store) List(filter Filter) ([]View, error) {
+	docs, err := s.documents.List(viewsCollection)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]View, 0, len(docs))
+	for id, data := range docs {
+		var v View
+		if err := json.Unmarshal(data, &v); err != nil {
+			s.logger.WithError(err).WithField("view", id).Error("Skipping unreadable saved view")
+			continue
+		}
+		if filter.Owner != "" && !v.VisibleTo(filter.Owner) {
+			continue
+		}
+		if filter.Kind != "" && v.Kind != filter.Kind {
+			continue
+		}
+		if filter.ConfigID != "" && v.ConfigID != "" && v.ConfigID != filter.ConfigID {
+			continue
+		}
+		views = append(views, v)
+	}
+	sort.Slice(views, func(i, j int) bool {
+		if views[i].Kind != views[j].Kind {
+			return views[i].Kind < views[j].Kind
+		}
+		return views[i].Name < views[j].Name
+	})
+	return views, nil
+}
+
+// Get returns a single view
+func (s *Store) Get(id string) (*View, error) {
+	var v View
+	if err := s.documents.Get(viewsCollection, id, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// Save validates and persists a view, assigning an ID to new views
+func (s *Store) Save(v *View) error {
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	now := time.Now()
+	if v.ID == "" {
+		v.ID = uuid.New().String()
+		v.CreatedAt = now
+	}
+	v.UpdatedAt = now
+	return s.documents.Put(viewsCollection, v.ID, v)
+}
+
+// Delete removes a view
+func (s *Store) Delete(id string) error {
+	return s.documents.Delete(viewsCollection, id)
+}
+
+// Cleanup removes the views bound to a removed kubeconfig or cluster. Views bound to a kubeconfig
+// but not to one of its clusters are kept when only a cluster is removed.
+func (s *Store) Cleanup(removal storage.Removal) (int, error) {
+	views, err := s.List(Filter{ConfigID: removal.ConfigID})
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, v := range views {
+		if v.ConfigID == "" || !removal.Matches(v.ConfigID, v.Cluster) {
+			continue
+		}
+		if err := s.Delete(v.ID); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
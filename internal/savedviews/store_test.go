@@ -0,0 +1,124 @@
+package savedviews
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+)
+
+func TestViewValidate(t *testing.T) {
+	v := View{
+		Name:       " failing pods ",
+		Kind:       "Pods",
+		Namespaces: []string{"shop", " payments ", "", "shop"},
+		Sort:       "-restarts",
+		Columns:    []string{"status", " node.zone ", "status"},
+	}
+	if err := v.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if v.Name != "failing pods" || v.Kind != "pods" {
+		t.Errorf("name/kind not normalized: %q %q", v.Name, v.Kind)
+	}
+	if want := []string{"payments", "shop"}; !reflect.DeepEqual(v.Namespaces, want) {
+		t.Errorf("namespaces = %v, want %v", v.Namespaces, want)
+	}
+	if want := []string{"status", "node.zone"}; !reflect.DeepEqual(v.Columns, want) {
+		t.Errorf("columns = %v, want %v", v.Columns, want)
+	}
+
+	invalid := []View{
+		{Kind: "pods"},
+		{Name: "x"},
+		{Name: "x", Kind: "pods/logs"},
+		{Name: "x", Kind: "pods", Cluster: "prod"},
+		{Name: "x", Kind: "pods", Namespaces: []string{"Shop_Prod"}},
+		{Name: "x", Kind: "pods", LabelSelector: "app in (web"},
+		{Name: "x", Kind: "pods", Sort: "status;drop"},
+		{Name: "x", Kind: "pods", Columns: []string{"node..zone"}},
+	}
+	for i, v := range invalid {
+		if err := v.Validate(); err == nil {
+			t.Errorf("case %d: expected a validation error", i)
+		}
+	}
+}
+
+func TestStoreListVisibility(t *testing.T) {
+	store := NewStore(storage.NewDocumentStore(nil), logger.New("error"))
+	for _, v := range []View{
+		{Name: "mine", Kind: "pods", Owner: "alice"},
+		{Name: "theirs", Kind: "pods", Owner: "bob"},
+		{Name: "team", Kind: "pods", Owner: "bob", Shared: true},
+		{Name: "everyone", Kind: "deployments"},
+		{Name: "other cluster", Kind: "pods", ConfigID: "cfg-2"},
+	} {
+		v := v
+		if err := store.Save(&v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names := func(filter Filter) []string {
+		list, err := store.List(filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out []string
+		for _, v := range list {
+			out = append(out, v.Name)
+		}
+		return out
+	}
+	if got, want := names(Filter{Owner: "alice"}), []string{"everyone", "mine", "other cluster", "team"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("alice sees %v, want %v", got, want)
+	}
+	if got, want := names(Filter{Owner: "alice", Kind: "pods", ConfigID: "cfg-1"}), []string{"mine", "team"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("alice's pods views for cfg-1 = %v, want %v", got, want)
+	}
+}
+
+func TestStoreCleanup(t *testing.T) {
+	store := NewStore(storage.NewDocumentStore(nil), logger.New("error"))
+	for _, v := range []View{
+		{Name: "whole config", Kind: "pods", ConfigID: "cfg-1"},
+		{Name: "prod", Kind: "pods", ConfigID: "cfg-1", Cluster: "prod"},
+		{Name: "staging", Kind: "pods", ConfigID: "cfg-1", Cluster: "staging"},
+		{Name: "other config", Kind: "pods", ConfigID: "cfg-2", Cluster: "prod"},
+		{Name: "unbound", Kind: "pods"},
+	} {
+		v := v
+		if err := store.Save(&v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	names := func() []string {
+		list, err := store.List(Filter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out []string
+		for _, v := range list {
+			out = append(out, v.Name)
+		}
+		return out
+	}
+
+	removed, err := store.Cleanup(storage.Removal{ConfigID: "cfg-1", Cluster: "prod"})
+	if err != nil || removed != 1 {
+		t.Fatalf("Cleanup(cluster) = %d, %v, want 1", removed, err)
+	}
+	if got, want := names(), []string{"other config", "staging", "unbound", "whole config"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("views after removing a cluster = %v, want %v", got, want)
+	}
+
+	removed, err = store.Cleanup(storage.Removal{ConfigID: "cfg-1"})
+	if err != nil || removed != 2 {
+		t.Fatalf("Cleanup(config) = %d, %v, want 2", removed, err)
+	}
+	if got, want := names(), []string{"other config", "unbound"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("views after removing a config = %v, want %v", got, want)
+	}
+}
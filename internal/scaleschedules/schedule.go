@@ -0,0 +1,241 @@
+package scaleschedules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+)
+
+// Triggers of a schedule run
+const (
+	TriggerScheduled = "scheduled"
+	TriggerManual    = "manual"
+)
+
+// ConflictHPA is reported when a HorizontalPodAutoscaler also scales the deployment
+const ConflictHPA = "hpa"
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// Window sets the replicas of a deployment on some days between two times of day. A window
+// whose end is before its start runs past midnight into the next day.
+type Window struct {
+	Days     []string `json:"days,omitempty"` // mon, tue, ... sun; empty for every day
+	Start    string   `json:"start"`          // HH:MM
+	End      string   `json:"end"`            // HH:MM, 24:00 for midnight
+	Replicas int32    `json:"replicas"`
+}
+
+// Schedule holds the time-based replica rules of one deployment
+type Schedule struct {
+	ID              string    `json:"id"`
+	ConfigID        string    `json:"configId"`
+	Cluster         string    `json:"cluster,omitempty"`
+	Namespace       string    `json:"namespace"`
+	Deployment      string    `json:"deployment"`
+	Timezone        string    `json:"timezone"`        // IANA zone the windows are in
+	Windows         []Window  `json:"windows"`         // the first window that matches wins
+	DefaultReplicas int32     `json:"defaultReplicas"` // replicas outside every window
+	Enabled         bool      `json:"enabled"`
+	LastApplied     *int32    `json:"lastApplied,omitempty"` // replicas last set by the scheduler
+	LastAppliedAt   time.Time `json:"lastAppliedAt,omitempty"`
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+// Conflict is another scaler of the deployment the schedule would fight with
+type Conflict struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Detail string `json:"detail"`
+}
+
+// Run is the record of one evaluation that set, or tried to set, a deployment's replicas
+type Run struct {
+	ID               string     `json:"id"`
+	ScheduleID       string     `json:"scheduleId"`
+	Trigger          string     `json:"trigger"`
+	At               time.Time  `json:"at"`
+	Window           int        `json:"window"` // index of the matching window, -1 for the default
+	PreviousReplicas int32      `json:"previousReplicas"`
+	DesiredReplicas  int32      `json:"desiredReplicas"`
+	Changed          bool       `json:"changed"`
+	Skipped          bool       `json:"skipped,omitempty"`
+	Conflicts        []Conflict `json:"conflicts,omitempty"`
+	Error            string     `json:"error,omitempty"`
+}
+
+// Status is a schedule with its current and next target
+type Status struct {
+	Schedule
+	CurrentWindow   int        `json:"currentWindow"` // -1 for the default
+	DesiredReplicas int32      `json:"desiredReplicas"`
+	NextChangeAt    *time.Time `json:"nextChangeAt,omitempty"`
+	NextReplicas    *int32     `json:"nextReplicas,omitempty"`
+}
+
+// parseClock parses HH:MM into minutes after midnight
+func parseClock(value string) (int, error) {
+	hours, minutes, ok := strings.Cut(value, ":")
+	h, herr := strconv.Atoi(hours)
+	m, merr := strconv.Atoi(minutes)
+	if !ok || herr != nil || merr != nil || len(minutes) != 2 || h < 0 || m < 0 || m > 59 || h > 24 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("invalid time %q, want HH:MM", value)
+	}
+	return h*60 + m, nil
+}
+
+// Validate checks the schedule and normalizes its timezone and day names
+func (s *Schedule) Validate() error {
+	if s.Namespace == "" || s.Deployment == "" {
+		return fmt.Errorf("namespace and deployment are required")
+	}
+	if s.Timezone == "" {
+		s.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(s.Timezone); err != nil {
+		return fmt.Errorf("unknown timezone %q", s.Timezone)
+	}
+	if len(s.Windows) == 0 {
+		return fmt.Errorf("at least one window is required")
+	}
+	if s.DefaultReplicas < 0 {
+		return fmt.Errorf("defaultReplicas must not be negative")
+	}
+	for i := range s.Windows {
+		w := &s.Windows[i]
+		start, err := parseClock(w.Start)
+		if err != nil {
+			return fmt.Errorf("window %d: %w", i+1, err)
+		}
+		end, err := parseClock(w.End)
+		if err != nil {
+			return fmt.Errorf("window %d: %w", i+1, err)
+		}
+		if start == end || start == 24*60 {
+			return fmt.Errorf("window %d: start and end must differ and start must be before 24:00", i+1)
+		}
+		if w.Replicas < 0 {
+			return fmt.Errorf("window %d: replicas must not be negative", i+1)
+		}
+		for j, day := range w.Days {
+			day = strings.ToLower(day)
+			if len(day) > 3 {
+				day = day[:3]
+			}
+			if _, ok := weekdays[day]; !ok {
+				return fmt.Errorf("window %d: unknown day %q", i+1, w.Days[j])
+			}
+			w.Days[j] = day
+		}
+	}
+	return nil
+}
+
+// onDay reports whether the window applies to a day
+func (w Window) onDay(day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, d := range w.Days {
+		if weekdays[d] == day {
+			return true
+		}
+	}
+	return false
+}
+
+// active reports whether the window covers a local time
+func (w Window) active(t time.Time) bool {
+	start, err := parseClock(w.Start)
+	if err != nil {
+		return false
+	}
+	end, err := parseClock(w.End)
+	if err != nil {
+		return false
+	}
+	minute := t.Hour()*60 + t.Minute()
+	if start < end {
+		return w.onDay(t.Weekday()) && minute >= start && minute < end
+	}
+	// The window runs past midnight: its start day's evening and the next day's morning
+	return (w.onDay(t.Weekday()) && minute >= start) || (w.onDay((t.Weekday()+6)%7) && minute < end)
+}
+
+// location returns the schedule's timezone, UTC if it cannot be loaded
+func (s *Schedule) location() *time.Location {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// desiredIn returns the replicas the windows set at a local time and the index of the window
+// that sets them, or -1 when the default applies
+func (s *Schedule) desiredIn(local time.Time) (int32, int) {
+	for i, w := range s.Windows {
+		if w.active(local) {
+			return w.Replicas, i
+		}
+	}
+	return s.DefaultReplicas, -1
+}
+
+// Desired returns the replicas the schedule sets at a time and the index of the window that
+// sets them, or -1 when the default applies
+func (s *Schedule) Desired(at time.Time) (int32, int) {
+	return s.desiredIn(at.In(s.location()))
+}
+
+// NextChange returns when the desired replicas next change within a week, and to what
+func (s *Schedule) NextChange(from time.Time) (time.Time, int32, bool) {
+	loc := s.location()
+	current, _ := s.desiredIn(from.In(loc))
+	t := from.Truncate(time.Minute)
+	for i := 0; i < 7*24*60; i++ {
+		t = t.Add(time.Minute)
+		if replicas, _ := s.desiredIn(t.In(loc)); replicas != current {
+			return t, replicas, true
+		}
+	}
+	return time.Time{}, 0, false
+}
+
+// Status returns the schedule with its target at a time
+func (s *Schedule) Status(at time.Time) Status {
+	status := Status{Schedule: *s}
+	status.DesiredReplicas, status.CurrentWindow = s.Desired(at)
+	if next, replicas, ok := s.NextChange(at); ok {
+		status.NextChangeAt, status.NextReplicas = &next, &replicas
+	}
+	return status
+}
+
+// hpaConflicts returns the autoscalers that target the schedule's deployment
+func hpaConflicts(hpas []autoscalingv2.HorizontalPodAutoscaler, s *Schedule) []Conflict {
+	var conflicts []Conflict
+	for _, hpa := range hpas {
+		ref := hpa.Spec.ScaleTargetRef
+		if ref.Kind != "Deployment" || ref.Name != s.Deployment || hpa.Namespace != s.Namespace {
+			continue
+		}
+		min := int32(1)
+		if hpa.Spec.MinReplicas != nil {
+			min = *hpa.Spec.MinReplicas
+		}
+		conflicts = append(conflicts, Conflict{
+			Kind:   ConflictHPA,
+			Name:   hpa.Name,
+			Detail: fmt.Sprintf("HorizontalPodAutoscaler scales the deployment between %d and %d replicas and would undo scheduled changes", min, hpa.Spec.MaxReplicas),
+		})
+	}
+	return conflicts
+}
@@ -0,0 +1,95 @@
+package scaleschedules
+
+import (
+	"testing"
+	"time"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidate(t *testing.T) {
+	s := Schedule{Namespace: "shop", Deployment: "api", Windows: []Window{{Days: []string{"Monday", "FRI"}, Start: "09:00", End: "18:00", Replicas: 5}}}
+	if err := s.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+	if s.Timezone != "UTC" || s.Windows[0].Days[0] != "mon" || s.Windows[0].Days[1] != "fri" {
+		t.Errorf("normalized schedule = %+v", s)
+	}
+
+	invalid := []Schedule{
+		{Namespace: "shop", Deployment: "api"},
+		{Namespace: "shop", Deployment: "api", Timezone: "Mars/Olympus", Windows: s.Windows},
+		{Namespace: "shop", Deployment: "api", Windows: []Window{{Start: "9am", End: "18:00"}}},
+		{Namespace: "shop", Deployment: "api", Windows: []Window{{Start: "09:00", End: "09:00"}}},
+		{Namespace: "shop", Deployment: "api", Windows: []Window{{Start: "09:00", End: "24:30"}}},
+		{Namespace: "shop", Deployment: "api", Windows: []Window{{Days: []string{"someday"}, Start: "09:00", End: "18:00"}}},
+		{Namespace: "shop", Deployment: "api", Windows: []Window{{Start: "09:00", End: "18:00", Replicas: -1}}},
+	}
+	for _, sched := range invalid {
+		if err := sched.Validate(); err == nil {
+			t.Errorf("Validate(%+v) succeeded, want an error", sched)
+		}
+	}
+}
+
+func TestDesired(t *testing.T) {
+	s := Schedule{
+		Timezone:        "Europe/Berlin",
+		DefaultReplicas: 1,
+		Windows: []Window{
+			{Days: []string{"mon", "tue", "wed", "thu", "fri"}, Start: "09:00", End: "18:00", Replicas: 5},
+			{Days: []string{"fri"}, Start: "22:00", End: "02:00", Replicas: 3},
+		},
+	}
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	cases := []struct {
+		at       time.Time
+		replicas int32
+		window   int
+	}{
+		{time.Date(2025, 3, 3, 9, 0, 0, 0, berlin), 5, 0},   // Monday opening
+		{time.Date(2025, 3, 3, 17, 59, 0, 0, berlin), 5, 0}, // Monday before closing
+		{time.Date(2025, 3, 3, 18, 0, 0, 0, berlin), 1, -1}, // Monday closing
+		{time.Date(2025, 3, 3, 8, 0, 0, 0, time.UTC), 5, 0}, // 09:00 in Berlin
+		{time.Date(2025, 3, 8, 12, 0, 0, 0, berlin), 1, -1}, // Saturday
+		{time.Date(2025, 3, 7, 23, 0, 0, 0, berlin), 3, 1},  // Friday night
+		{time.Date(2025, 3, 8, 1, 30, 0, 0, berlin), 3, 1},  // past midnight into Saturday
+		{time.Date(2025, 3, 4, 1, 30, 0, 0, berlin), 1, -1}, // Tuesday morning after a non-Friday
+	}
+	for _, tc := range cases {
+		if replicas, window := s.Desired(tc.at); replicas != tc.replicas || window != tc.window {
+			t.Errorf("Desired(%v) = %d, %d, want %d, %d", tc.at, replicas, window, tc.replicas, tc.window)
+		}
+	}
+
+	next, replicas, ok := s.NextChange(time.Date(2025, 3, 3, 12, 30, 15, 0, berlin))
+	if !ok || replicas != 1 || !next.Equal(time.Date(2025, 3, 3, 18, 0, 0, 0, berlin)) {
+		t.Errorf("NextChange() = %v, %d, %v, want 18:00 to 1 replica", next, replicas, ok)
+	}
+}
+
+func TestHPAConflicts(t *testing.T) {
+	min := int32(2)
+	hpas := []autoscalingv2.HorizontalPodAutoscaler{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"},
+			Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+				ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", Name: "api"},
+				MinReplicas:    &min,
+				MaxReplicas:    10,
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "shop"},
+			Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+				ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", Name: "worker"},
+				MaxReplicas:    4,
+			},
+		},
+	}
+	conflicts := hpaConflicts(hpas, &Schedule{Namespace: "shop", Deployment: "api"})
+	if len(conflicts) != 1 || conflicts[0].Kind != ConflictHPA || conflicts[0].Name != "api" {
+		t.Errorf("hpaConflicts() = %+v, want the api autoscaler", conflicts)
+	}
+}
@@ -0,0 +1,423 @@
+package scaleschedules
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/config"
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/google/uuid"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Document collections used by the scheduler
+const (
+	schedulesCollection = "scale_schedules"
+	runsCollection      = "scale_schedule_runs"
+)
+
+const runTimeout = 30 * time.Second
+
+// ErrScheduleExists is returned when another schedule already scales the deployment
+var ErrScheduleExists = errors.New("another schedule already scales this deployment")
+
+// ErrScheduleNotFound is returned for unknown schedule IDs
+var ErrScheduleNotFound = errors.New("scale schedule not found")
+
+// Scheduler sets deployment replicas from time-based schedules
+type Scheduler struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	documents     *storage.DocumentStore
+	logger        *logger.Logger
+	config        *config.ScaleSchedulesConfig
+
+	mu        sync.RWMutex
+	schedules map[string]*Schedule
+	runs      map[string]*Run
+
+	// running serializes runs so a manual run and the ticker never scale the same deployment at once
+	running sync.Mutex
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewScheduler creates a scale scheduler; call Start to begin applying schedules
+func NewScheduler(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, documents *storage.DocumentStore, log *logger.Logger, cfg *config.ScaleSchedulesConfig) *Scheduler {
+	s := &Scheduler{
+		store:         store,
+		clientFactory: clientFactory,
+		documents:     documents,
+		logger:        log,
+		config:        cfg,
+		schedules:     make(map[string]*Schedule),
+		runs:          make(map[string]*Run),
+	}
+	if err := s.reload(); err != nil {
+		log.WithError(err).Error("Failed to load scale schedules")
+	}
+	return s
+}
+
+func (s *Scheduler) reload() error {
+	schedules, err := s.documents.List(schedulesCollection)
+	if err != nil {
+		return err
+	}
+	runs, err := s.documents.List(runsCollection)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, data := range schedules {
+		var sched Schedule
+		if err := json.Unmarshal(data, &sched); err != nil {
+			s.logger.WithError(err).WithField("schedule", id).Error("Skipping unreadable scale schedule")
+			continue
+		}
+		s.schedules[sched.ID] = &sched
+	}
+	for id, data := range runs {
+		var r Run
+		if err := json.Unmarshal(data, &r); err != nil {
+			s.logger.WithError(err).WithField("run", id).Error("Skipping unreadable scale schedule run")
+			continue
+		}
+		s.runs[r.ID] = &r
+	}
+	return nil
+}
+
+// Start begins applying schedules. An interval of zero disables it; schedules then only run on demand.
+func (s *Scheduler) Start() {
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	if s.config.IntervalSeconds <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(time.Duration(s.config.IntervalSeconds) * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-ticker.C:
+				s.runAll()
+			}
+		}
+	}()
+}
+
+// Stop ends applying schedules
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+// runAll applies every enabled schedule whose target changed since it was last applied. Replicas
+// are only set when the target changes, so manual scaling in between is kept until the next change.
+func (s *Scheduler) runAll() {
+	now := time.Now()
+	s.mu.RLock()
+	var due []Schedule
+	for _, sched := range s.schedules {
+		desired, _ := sched.Desired(now)
+		if sched.Enabled && (sched.LastApplied == nil || *sched.LastApplied != desired) {
+			due = append(due, *sched)
+		}
+	}
+	s.mu.RUnlock()
+
+	for _, sched := range due {
+		if s.ctx.Err() != nil {
+			return
+		}
+		if _, err := s.store.GetKubeConfig(sched.ConfigID); err != nil {
+			// The kubeconfig was removed; drop its schedules
+			s.Delete(sched.ID)
+			continue
+		}
+		ctx, cancel := context.WithTimeout(s.ctx, runTimeout)
+		if _, err := s.Run(ctx, sched.ID, TriggerScheduled); err != nil {
+			s.logger.WithError(err).WithField("schedule", sched.ID).WithField("deployment", sched.Namespace+"/"+sched.Deployment).Warn("Scheduled scaling failed")
+		}
+		cancel()
+	}
+}
+
+// List returns the schedules of a cluster sorted by namespace and deployment, with their
+// current and next targets
+func (s *Scheduler) List(configID, cluster string) []Status {
+	now := time.Now()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := []Status{}
+	for _, sched := range s.schedules {
+		if sched.ConfigID == configID && sched.Cluster == cluster {
+			result = append(result, sched.Status(now))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		return result[i].Deployment < result[j].Deployment
+	})
+	return result
+}
+
+// Get returns a schedule by ID
+func (s *Scheduler) Get(id string) (Schedule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	sched, ok := s.schedules[id]
+	if !ok {
+		return Schedule{}, ErrScheduleNotFound
+	}
+	return *sched, nil
+}
+
+// Save validates and stores a schedule, assigning an ID to new schedules. A deployment can only
+// have one schedule.
+func (s *Scheduler) Save(sched Schedule) (Schedule, error) {
+	if err := sched.Validate(); err != nil {
+		return sched, err
+	}
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, other := range s.schedules {
+		if other.ID != sched.ID && other.ConfigID == sched.ConfigID && other.Cluster == sched.Cluster &&
+			other.Namespace == sched.Namespace && other.Deployment == sched.Deployment {
+			return sched, ErrScheduleExists
+		}
+	}
+	if sched.ID == "" {
+		sched.ID = uuid.New().String()
+		sched.CreatedAt = now
+	} else {
+		existing, ok := s.schedules[sched.ID]
+		if !ok {
+			return sched, ErrScheduleNotFound
+		}
+		sched.CreatedAt = existing.CreatedAt
+		// The rules may have changed, so the next evaluation applies the target again
+		sched.LastApplied = nil
+		sched.LastAppliedAt = existing.LastAppliedAt
+	}
+	sched.UpdatedAt = now
+	if err := s.documents.Put(schedulesCollection, sched.ID, &sched); err != nil {
+		return sched, err
+	}
+	s.schedules[sched.ID] = &sched
+	return sched, nil
+}
+
+// Delete removes a schedule and its run history
+func (s *Scheduler) Delete(id string) {
+	s.mu.Lock()
+	delete(s.schedules, id)
+	var runs []string
+	for runID, r := range s.runs {
+		if r.ScheduleID == id {
+			runs = append(runs, runID)
+			delete(s.runs, runID)
+		}
+	}
+	s.mu.Unlock()
+	if err := s.documents.Delete(schedulesCollection, id); err != nil && err != storage.ErrDocumentNotFound {
+		s.logger.WithError(err).WithField("schedule", id).Error("Failed to delete scale schedule")
+	}
+	for _, runID := range runs {
+		if err := s.documents.Delete(runsCollection, runID); err != nil && err != storage.ErrDocumentNotFound {
+			s.logger.WithError(err).WithField("run", runID).Error("Failed to delete scale schedule run")
+		}
+	}
+}
+
+// Cleanup deletes the schedules of a removed kubeconfig or cluster with their run history
+func (s *Scheduler) Cleanup(removal storage.Removal) (int, error) {
+	s.mu.RLock()
+	var ids []string
+	for id, sched := range s.schedules {
+		if removal.Matches(sched.ConfigID, sched.Cluster) {
+			ids = append(ids, id)
+		}
+	}
+	s.mu.RUnlock()
+	for _, id := range ids {
+		s.Delete(id)
+	}
+	return len(ids), nil
+}
+
+func (s *Scheduler) getClient(configID, cluster string) (kubernetes.Interface, error) {
+	cfg, err := s.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, err
+	}
+	return s.clientFactory.GetClientForConfig(cfg, cluster)
+}
+
+// Conflicts returns the autoscalers that also scale a schedule's deployment
+func (s *Scheduler) Conflicts(ctx context.Context, sched Schedule) ([]Conflict, error) {
+	client, err := s.getClient(sched.ConfigID, sched.Cluster)
+	if err != nil {
+		return nil, err
+	}
+	return conflicts(ctx, client, &sched)
+}
+
+func conflicts(ctx context.Context, client kubernetes.Interface, sched *Schedule) ([]Conflict, error) {
+	hpas, err := client.AutoscalingV2().HorizontalPodAutoscalers(sched.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list horizontal pod autoscalers: %w", err)
+	}
+	return hpaConflicts(hpas.Items, sched), nil
+}
+
+// Run sets a schedule's deployment to its current target and records the run. It is skipped
+// while an autoscaler also scales the deployment.
+func (s *Scheduler) Run(ctx context.Context, id, trigger string) (*Run, error) {
+	s.running.Lock()
+	defer s.running.Unlock()
+
+	sched, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	run := &Run{ID: uuid.New().String(), ScheduleID: id, Trigger: trigger, At: time.Now()}
+	run.DesiredReplicas, run.Window = sched.Desired(run.At)
+
+	err = s.apply(ctx, &sched, run)
+	if err != nil {
+		run.Error = err.Error()
+	} else if !run.Skipped {
+		s.markApplied(id, run)
+	}
+	// A skipped or failed scheduled run is retried every interval; only its first occurrence is kept
+	if trigger != TriggerScheduled || !s.repeats(run) {
+		s.record(run)
+	}
+	return run, err
+}
+
+// repeats reports whether a skipped or failed run has the same outcome as the schedule's last run
+func (s *Scheduler) repeats(run *Run) bool {
+	if !run.Skipped && run.Error == "" {
+		return false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var last *Run
+	for _, r := range s.runs {
+		if r.ScheduleID == run.ScheduleID && (last == nil || r.At.After(last.At)) {
+			last = r
+		}
+	}
+	return last != nil && last.Skipped == run.Skipped && last.Error == run.Error && last.DesiredReplicas == run.DesiredReplicas
+}
+
+func (s *Scheduler) apply(ctx context.Context, sched *Schedule, run *Run) error {
+	client, err := s.getClient(sched.ConfigID, sched.Cluster)
+	if err != nil {
+		return err
+	}
+	if run.Conflicts, err = conflicts(ctx, client, sched); err != nil {
+		return err
+	}
+	if len(run.Conflicts) > 0 {
+		run.Skipped = true
+		return nil
+	}
+
+	deployments := client.AppsV1().Deployments(sched.Namespace)
+	scale, err := deployments.GetScale(ctx, sched.Deployment, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get deployment scale: %w", err)
+	}
+	run.PreviousReplicas = scale.Spec.Replicas
+	if scale.Spec.Replicas == run.DesiredReplicas {
+		return nil
+	}
+	scale.Spec.Replicas = run.DesiredReplicas
+	if _, err := deployments.UpdateScale(ctx, sched.Deployment, scale, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to scale deployment: %w", err)
+	}
+	run.Changed = true
+	return nil
+}
+
+// markApplied remembers the target a schedule last applied
+func (s *Scheduler) markApplied(id string, run *Run) {
+	s.mu.Lock()
+	sched, ok := s.schedules[id]
+	if !ok {
+		s.mu.Unlock()
+		return
+	}
+	replicas := run.DesiredReplicas
+	updated := *sched
+	updated.LastApplied = &replicas
+	updated.LastAppliedAt = run.At
+	s.schedules[id] = &updated
+	s.mu.Unlock()
+	if err := s.documents.Put(schedulesCollection, id, &updated); err != nil {
+		s.logger.WithError(err).WithField("schedule", id).Error("Failed to persist scale schedule")
+	}
+}
+
+// record stores a run and drops the oldest runs of the schedule beyond the retention
+func (s *Scheduler) record(run *Run) {
+	if err := s.documents.Put(runsCollection, run.ID, run); err != nil {
+		s.logger.WithError(err).WithField("run", run.ID).Error("Failed to persist scale schedule run")
+	}
+	s.mu.Lock()
+	s.runs[run.ID] = run
+	var expired []string
+	if s.config.RunRetention > 0 {
+		var scheduleRuns []*Run
+		for _, r := range s.runs {
+			if r.ScheduleID == run.ScheduleID {
+				scheduleRuns = append(scheduleRuns, r)
+			}
+		}
+		sort.Slice(scheduleRuns, func(i, j int) bool { return scheduleRuns[i].At.After(scheduleRuns[j].At) })
+		for _, r := range scheduleRuns[min(len(scheduleRuns), s.config.RunRetention):] {
+			delete(s.runs, r.ID)
+			expired = append(expired, r.ID)
+		}
+	}
+	s.mu.Unlock()
+	for _, id := range expired {
+		if err := s.documents.Delete(runsCollection, id); err != nil && err != storage.ErrDocumentNotFound {
+			s.logger.WithError(err).WithField("run", id).Error("Failed to delete expired scale schedule run")
+		}
+	}
+}
+
+// Runs returns the recorded runs of a schedule, newest first
+func (s *Scheduler) Runs(id string) []Run {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := []Run{}
+	for _, r := range s.runs {
+		if r.ScheduleID == id {
+			result = append(result, *r)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].At.After(result[j].At) })
+	return result
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/Facets-cloud/kube-dash/internal/config"
+	"github.com/Facets-cloud/kube-dash/internal/elevation"
+)
+
+// destructiveRoute matches routes that may exec into containers or delete cluster objects
+var destructiveRoute = regexp.MustCompile(`^DELETE |/(exec|debug|cloudshell|cleanup|delete[^/]*|rollback|drain|evict)(/|$)`)
+
+// unguardedRoutes are the routes destructiveRoute matches that neither exec nor delete cluster
+// objects: kube-dash's own records, read-only views and proxied requests
+var unguardedRoutes = map[string]bool{
+	"GET /api/v1/cloudshell":                                      true,
+	"POST /api/v1/cloudshell":                                     true,
+	"GET /api/v1/pod-cleanup/policy":                              true,
+	"PUT /api/v1/pod-cleanup/policy":                              true,
+	"GET /api/v1/pod-cleanup/runs":                                true,
+	"GET /api/v1/nodes/:name/drain/simulate":                      true,
+	"POST /api/v1/helmreleases/:name/rollback":                    true,
+	"DELETE /api/v1/reports/schedules/:id":                        true,
+	"DELETE /api/v1/reports/artifacts/:id":                        true,
+	"DELETE /api/v1/restart-storms":                               true,
+	"DELETE /api/v1/resource-favorites":                           true,
+	"DELETE /api/v1/recent-resources":                             true,
+	"DELETE /api/v1/app/config/kubeconfigs/:id":                   true,
+	"DELETE /api/v1/app/config/kubeconfigs/:id/clusters/:cluster": true,
+	"DELETE /api/v1/app/config/kubeconfigs/:id/metadata":          true,
+	"DELETE /api/v1/alerts/:id/acknowledge":                       true,
+	"DELETE /api/v1/auth/tokens/:id":                              true,
+	"DELETE /api/v1/namespace-groups/:id":                         true,
+	"DELETE /api/v1/namespace-preferences":                        true,
+	"DELETE /api/v1/namespace-templates/:id":                      true,
+	"DELETE /api/v1/notifications/rules/:id":                      true,
+	"DELETE /api/v1/snapshots/:id":                                true,
+	"DELETE /api/v1/scale-schedules/:id":                          true,
+	"DELETE /api/v1/saved-views/:id":                              true,
+	"DELETE /api/v1/services/:namespace/:name/proxy/:port/*path":  true,
+	"DELETE /api/v1/terminal/policies/:id":                        true,
+	"DELETE /api/v1/terminal/snippets/:id":                        true,
+	"DELETE /api/v1/terminal/history":                             true,
+	"DELETE /api/v1/crash-reports/clusters":                       true,
+	"DELETE /api/v1/customresourcedefinitions/favorites/:name":    true,
+	"DELETE /api/v1/metrics/thresholds/:id":                       true,
+	"DELETE /api/v1/dashboards/:id":                               true,
+	"DELETE /api/v1/event-history/clusters":                       true,
+	"DELETE /api/v1/portforward/sessions/:id":                     true,
+}
+
+// TestDestructiveRoutesAreGuarded fails when a route that may exec or delete is registered
+// without an elevation guard, so elevation-bound tokens cannot reach it without a grant
+func TestDestructiveRoutesAreGuarded(t *testing.T) {
+	cfg := config.Load()
+	cfg.Database.Type = "sqlite"
+	cfg.Database.Path = t.TempDir() + "/kube-dash.db"
+	s := New(cfg)
+
+	for _, route := range s.router.Routes() {
+		key := route.Method + " " + route.Path
+		if !destructiveRoute.MatchString(key) || unguardedRoutes[key] {
+			continue
+		}
+		if !elevation.Guarded(route.Method, route.Path) {
+			t.Errorf("%s may exec or delete but is not guarded by the elevation middleware", key)
+		}
+	}
+}
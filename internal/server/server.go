@@ -9,23 +9,88 @@ import (
 	"github.com/Facets-cloud/kube-dash/internal/api"
 	handlers "github.com/Facets-cloud/kube-dash/internal/api/handlers"
 	access_control "github.com/Facets-cloud/kube-dash/internal/api/handlers/access-control"
+	alerts_handlers "github.com/Facets-cloud/kube-dash/internal/api/handlers/alerts"
+	apitokens_handlers "github.com/Facets-cloud/kube-dash/internal/api/handlers/apitokens"
+	"github.com/Facets-cloud/kube-dash/internal/api/handlers/certmanager"
+	"github.com/Facets-cloud/kube-dash/internal/api/handlers/clusterapi"
 	"github.com/Facets-cloud/kube-dash/internal/api/handlers/cloudshell"
+	"github.com/Facets-cloud/kube-dash/internal/api/handlers/cost"
 	"github.com/Facets-cloud/kube-dash/internal/api/handlers/cluster"
+	"github.com/Facets-cloud/kube-dash/internal/api/handlers/compare"
 	"github.com/Facets-cloud/kube-dash/internal/api/handlers/configurations"
 	custom_resources "github.com/Facets-cloud/kube-dash/internal/api/handlers/custom-resources"
+	dashboards_handlers "github.com/Facets-cloud/kube-dash/internal/api/handlers/dashboards"
+	"github.com/Facets-cloud/kube-dash/internal/api/handlers/gitops"
 	"github.com/Facets-cloud/kube-dash/internal/api/handlers/helm"
+	"github.com/Facets-cloud/kube-dash/internal/api/handlers/keda"
+	"github.com/Facets-cloud/kube-dash/internal/api/handlers/logs"
 	metrics_handlers "github.com/Facets-cloud/kube-dash/internal/api/handlers/metrics"
 	"github.com/Facets-cloud/kube-dash/internal/api/handlers/networking"
+	audit_handlers "github.com/Facets-cloud/kube-dash/internal/api/handlers/audit"
+	elevation_handlers "github.com/Facets-cloud/kube-dash/internal/api/handlers/elevation"
+	namespacegroups_handlers "github.com/Facets-cloud/kube-dash/internal/api/handlers/namespacegroups"
+	namespaceprefs_handlers "github.com/Facets-cloud/kube-dash/internal/api/handlers/namespaceprefs"
+	bookmarks_handlers "github.com/Facets-cloud/kube-dash/internal/api/handlers/bookmarks"
+	notifications_handlers "github.com/Facets-cloud/kube-dash/internal/api/handlers/notifications"
+	reports_handlers "github.com/Facets-cloud/kube-dash/internal/api/handlers/reports"
+	podcleanup_handlers "github.com/Facets-cloud/kube-dash/internal/api/handlers/podcleanup"
+	scaleschedules_handlers "github.com/Facets-cloud/kube-dash/internal/api/handlers/scaleschedules"
+	customactions_handlers "github.com/Facets-cloud/kube-dash/internal/api/handlers/customactions"
+	mesh_handlers "github.com/Facets-cloud/kube-dash/internal/api/handlers/mesh"
+	eventhistory_handlers "github.com/Facets-cloud/kube-dash/internal/api/handlers/eventhistory"
+	crashreports_handlers "github.com/Facets-cloud/kube-dash/internal/api/handlers/crashreports"
+	restartstorms_handlers "github.com/Facets-cloud/kube-dash/internal/api/handlers/restartstorms"
+	rollouts_handlers "github.com/Facets-cloud/kube-dash/internal/api/handlers/rollouts"
+	savedviews_handlers "github.com/Facets-cloud/kube-dash/internal/api/handlers/savedviews"
 	"github.com/Facets-cloud/kube-dash/internal/api/handlers/portforward"
+	"github.com/Facets-cloud/kube-dash/internal/api/handlers/security"
+	snapshots_handlers "github.com/Facets-cloud/kube-dash/internal/api/handlers/snapshots"
+	streams_handlers "github.com/Facets-cloud/kube-dash/internal/api/handlers/streams"
 	storage_handlers "github.com/Facets-cloud/kube-dash/internal/api/handlers/storage"
+	thresholds_handlers "github.com/Facets-cloud/kube-dash/internal/api/handlers/thresholds"
 	tracing_handlers "github.com/Facets-cloud/kube-dash/internal/api/handlers/tracing"
 	"github.com/Facets-cloud/kube-dash/internal/api/handlers/terminal"
+	"github.com/Facets-cloud/kube-dash/internal/api/handlers/topology"
 	"github.com/Facets-cloud/kube-dash/internal/api/handlers/websockets"
 	"github.com/Facets-cloud/kube-dash/internal/api/handlers/workloads"
+	"github.com/Facets-cloud/kube-dash/internal/api/types"
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+	"github.com/Facets-cloud/kube-dash/internal/alerts"
+	"github.com/Facets-cloud/kube-dash/internal/apitokens"
+	"github.com/Facets-cloud/kube-dash/internal/applysets"
+	"github.com/Facets-cloud/kube-dash/internal/audit"
+	"github.com/Facets-cloud/kube-dash/internal/elevation"
+	"github.com/Facets-cloud/kube-dash/internal/clustermeta"
 	"github.com/Facets-cloud/kube-dash/internal/config"
+	"github.com/Facets-cloud/kube-dash/internal/crashreports"
+	"github.com/Facets-cloud/kube-dash/internal/crdfavorites"
+	"github.com/Facets-cloud/kube-dash/internal/dashboards"
+	"github.com/Facets-cloud/kube-dash/internal/eventhistory"
+	"github.com/Facets-cloud/kube-dash/internal/execpolicy"
 	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/namespacegroups"
+	"github.com/Facets-cloud/kube-dash/internal/namespaceprefs"
+	"github.com/Facets-cloud/kube-dash/internal/bookmarks"
+	"github.com/Facets-cloud/kube-dash/internal/notifications"
+	"github.com/Facets-cloud/kube-dash/internal/nsrequests"
+	"github.com/Facets-cloud/kube-dash/internal/nstemplates"
+	"github.com/Facets-cloud/kube-dash/internal/customactions"
+	"github.com/Facets-cloud/kube-dash/internal/objectstore"
+	"github.com/Facets-cloud/kube-dash/internal/podcleanup"
+	"github.com/Facets-cloud/kube-dash/internal/scaleschedules"
+	"github.com/Facets-cloud/kube-dash/internal/reports"
+	"github.com/Facets-cloud/kube-dash/internal/restartstorms"
+	"github.com/Facets-cloud/kube-dash/internal/rollouts"
+	"github.com/Facets-cloud/kube-dash/internal/savedviews"
+	"github.com/Facets-cloud/kube-dash/internal/snapshots"
+	"github.com/Facets-cloud/kube-dash/internal/snippets"
+	"github.com/Facets-cloud/kube-dash/internal/sourcelinks"
 	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/internal/streamhub"
+	"github.com/Facets-cloud/kube-dash/internal/streams"
+	"github.com/Facets-cloud/kube-dash/internal/thresholds"
 	"github.com/Facets-cloud/kube-dash/internal/tracing"
+	"github.com/Facets-cloud/kube-dash/internal/warmcache"
 	"github.com/Facets-cloud/kube-dash/pkg/logger"
 	"github.com/Facets-cloud/kube-dash/pkg/middleware"
 	pkg_tracing "github.com/Facets-cloud/kube-dash/pkg/tracing"
@@ -43,9 +108,14 @@ type Server struct {
 	server        *http.Server
 	store         *storage.KubeConfigStore
 	clientFactory *k8s.ClientFactory
+	documents     *storage.DocumentStore
 	kubeHandler   *api.KubeConfigHandler
+	// Background prefetch of cluster summaries for instant first page loads
+	warmCache *warmcache.Cache
 	// Base resources handler for generic operations (delete, permission checks)
 	baseResourcesHandler *handlers.ResourcesHandler
+	// Namespace creation and bootstrap templates, applied through the base resources handler
+	namespaceTemplatesHandler *handlers.NamespaceTemplatesHandler
 
 	// Configuration handlers
 	configMapsHandler           *configurations.ConfigMapsHandler
@@ -59,13 +129,23 @@ type Server struct {
 
 	// Cluster handlers
 	nodesHandler      *cluster.NodesHandler
+	nodeLogsHandler   *cluster.NodeLogsHandler
 	namespacesHandler *cluster.NamespacesHandler
 	eventsHandler     *cluster.EventsHandler
 	leasesHandler     *cluster.LeasesHandler
+	autoscalerHandler *cluster.AutoscalerHandler
+	hygieneHandler    *cluster.HygieneHandler
+	addonsHandler     *cluster.AddonsHandler
+	schedulingHandler *cluster.SchedulingHandler
+	countsHandler     *cluster.ResourceCountsHandler
+
+	// Control-plane health handler
+	controlPlaneHandler *cluster.ControlPlaneHandler
 
 	// Custom Resource handlers
 	customResourceDefinitionsHandler *custom_resources.CustomResourceDefinitionsHandler
 	customResourcesHandler           *custom_resources.CustomResourcesHandler
+	crdNavigationHandler             *custom_resources.CRDNavigationHandler
 
 	// Workload handlers
 	podsHandler               *workloads.PodsHandler
@@ -73,9 +153,16 @@ type Server struct {
 	daemonSetsHandler         *workloads.DaemonSetsHandler
 	statefulSetsHandler       *workloads.StatefulSetsHandler
 	replicaSetsHandler        *workloads.ReplicaSetsHandler
+	replicationControllersHandler *workloads.ReplicationControllersHandler
 	jobsHandler               *workloads.JobsHandler
 	cronJobsHandler           *workloads.CronJobsHandler
 	resourceReferencesHandler *workloads.ResourceReferencesHandler
+	imagesHandler             *workloads.ImagesHandler
+	workloadImagesHandler     *workloads.WorkloadImagesHandler
+	workloadSLOHandler        *workloads.WorkloadSLOHandler
+	workloadDriftHandler      *workloads.WorkloadDriftHandler
+	topologyHandler           *topology.TopologyHandler
+	compareHandler            *compare.CompareHandler
 
 	// Access Control handlers
 	serviceAccountsHandler     *access_control.ServiceAccountsHandler
@@ -86,11 +173,94 @@ type Server struct {
 
 	// Networking handlers
 	servicesHandler  *networking.ServicesHandler
+	serviceProxyHandler *networking.ServiceProxyHandler
 	ingressesHandler *networking.IngressesHandler
 	endpointsHandler *networking.EndpointsHandler
 
 	// Metrics handlers
 	prometheusHandler *metrics_handlers.PrometheusHandler
+	thresholdsHandler *thresholds_handlers.ThresholdsHandler
+	timeTravelHandler *metrics_handlers.TimeTravelHandler
+	lokiHandler       *logs.LokiHandler
+	costHandler       *cost.CostHandler
+
+	// Notification routing
+	notificationEngine   *notifications.Engine
+	notificationsHandler *notifications_handlers.NotificationsHandler
+
+	// External alerts received from Alertmanager
+	alertsHandler *alerts_handlers.AlertsHandler
+
+	// Saved PromQL dashboards
+	dashboardsHandler *dashboards_handlers.DashboardsHandler
+
+	// Shareable snapshots of list views
+	snapshotsHandler *snapshots_handlers.SnapshotsHandler
+
+	// Scheduled reports
+	reportScheduler *reports.Scheduler
+	objectLifecycle *objectstore.Lifecycle
+	reportsHandler  *reports_handlers.ReportsHandler
+
+	// Rollout history and delivery analytics
+	rolloutTracker  *rollouts.Tracker
+	rolloutsHandler *rollouts_handlers.RolloutsHandler
+
+	// Evicted and failed pod cleanup
+	podCleaner        *podcleanup.Cleaner
+	podCleanupHandler *podcleanup_handlers.PodCleanupHandler
+
+	// Time-based deployment scaling
+	scaleScheduler        *scaleschedules.Scheduler
+	scaleSchedulesHandler *scaleschedules_handlers.ScaleSchedulesHandler
+
+	// Operator-defined actions on resources
+	customActionsHandler *customactions_handlers.CustomActionsHandler
+
+	// Event history beyond the cluster's event TTL
+	eventRecorder       *eventhistory.Recorder
+	eventHistoryHandler *eventhistory_handlers.EventHistoryHandler
+
+	// Crash-loop first-failure reports
+	crashWatcher        *crashreports.Watcher
+	crashReportsHandler *crashreports_handlers.CrashReportsHandler
+
+	// Restart storm detection
+	stormDetector        *restartstorms.Detector
+	restartStormsHandler *restartstorms_handlers.RestartStormsHandler
+
+	// Saved namespace groups for multi-namespace lists
+	namespaceGroupsHandler *namespacegroups_handlers.NamespaceGroupsHandler
+
+	// Accessible namespaces and per-user default namespaces
+	namespacePrefsHandler *namespaceprefs_handlers.NamespacePreferencesHandler
+
+	// Per-user favorite and recently viewed resources
+	bookmarksHandler *bookmarks_handlers.BookmarksHandler
+
+	// Saved list views
+	savedViewsHandler *savedviews_handlers.SavedViewsHandler
+
+	// Audit trail
+	auditRecorder *audit.Recorder
+	auditHandler  *audit_handlers.AuditHandler
+
+	// API tokens for automation
+	apiTokens     *apitokens.Store
+	tokensHandler *apitokens_handlers.TokensHandler
+
+	// Time-bounded elevated access for elevation-bound API tokens
+	elevationRequests *elevation.Store
+	elevationHandler  *elevation_handlers.ElevationHandler
+
+	// Git source links added to detail responses
+	sourceLinks *sourcelinks.Resolver
+
+	// Open SSE and WebSocket streams
+	streams        *streams.Registry
+	streamsHandler *streams_handlers.StreamsHandler
+	// Subscriptions to streaming endpoints multiplexed over one WebSocket
+	multiplexHandler *streams_handlers.MultiplexHandler
 
 	// Storage handlers
 	persistentVolumesHandler      *storage_handlers.PersistentVolumesHandler
@@ -105,6 +275,21 @@ type Server struct {
 	// Helm handlers
 	helmHandler *helm.HelmHandler
 
+	// GitOps handlers
+	gitOpsHandler *gitops.GitOpsHandler
+
+	// cert-manager handlers
+	certManagerHandler *certmanager.CertManagerHandler
+
+	// KEDA handlers
+	kedaHandler *keda.KedaHandler
+
+	// Service mesh handlers
+	meshHandler *mesh_handlers.MeshHandler
+
+	// Cluster API handlers
+	clusterAPIHandler *clusterapi.ClusterAPIHandler
+
 	// Cloud Shell handlers
 	cloudShellHandler *cloudshell.CloudShellHandler
 
@@ -114,6 +299,15 @@ type Server struct {
 
 	// Feature flags handler
 	featureFlagsHandler *handlers.FeatureFlagsHandler
+
+	// OpenAPI document of the v2 API
+	openAPIHandler *handlers.OpenAPIHandler
+
+	// Security handlers
+	vulnerabilitiesHandler *security.VulnerabilitiesHandler
+	podSecurityHandler     *security.PodSecurityHandler
+	policiesHandler        *security.PoliciesHandler
+	rbacAuditHandler       *security.RBACAuditHandler
 }
 
 // New creates a new server instance
@@ -128,6 +322,9 @@ func New(cfg *config.Config) *Server {
 	// Create logger
 	log := logger.New(cfg.Logging.Level)
 
+	// Timestamps are RFC3339 UTC with epoch millisecond companions unless older clients need the legacy formats
+	types.SetLegacyTimestamps(cfg.Server.LegacyTimestamps)
+
 	// Create router
 	router := gin.New()
 
@@ -141,8 +338,38 @@ func New(cfg *config.Config) *Server {
 		log.Info("Successfully initialized database storage backend")
 		store = storeWithDB
 	}
-	clientFactory := k8s.NewClientFactory()
-	kubeHandler := api.NewKubeConfigHandler(store, clientFactory, log)
+	clientFactory := k8s.NewClientFactory(&cfg.K8s)
+	documents := storage.NewDocumentStore(store.GetDatabase())
+	// Large artifacts such as generated reports go to object storage, falling back to local disk
+	objects, err := objectstore.New(&cfg.Objects)
+	if err != nil {
+		log.WithError(err).Warn("Failed to initialize object storage, falling back to local disk")
+		if objects, err = objectstore.NewLocalStore(cfg.Objects.LocalPath); err != nil {
+			log.WithError(err).Warn("Failed to initialize local object storage, keeping artifacts in the database")
+			objects = nil
+		}
+	}
+	objectRules, err := objectstore.ParseRules(cfg.Objects.Retention)
+	if err != nil {
+		log.WithError(err).Warn("Ignoring invalid object retention rules")
+	}
+	var objectLifecycle *objectstore.Lifecycle
+	if objects != nil {
+		objectLifecycle = objectstore.NewLifecycle(objects, objectRules, log)
+	}
+	auditRecorder := audit.NewRecorder(documents, log)
+	auditHandler := audit_handlers.NewAuditHandler(auditRecorder, log)
+	apiTokens := apitokens.NewStore(documents, log)
+	tokensHandler := apitokens_handlers.NewTokensHandler(apiTokens, store, auditRecorder, log)
+	elevationRequests := elevation.NewStore(documents, &cfg.Elevation, log)
+	elevationHandler := elevation_handlers.NewElevationHandler(elevationRequests, store, auditRecorder, log)
+	sourceLinks := sourcelinks.NewResolver(&cfg.SourceLinks, store, clientFactory, log)
+	streamRegistry := streams.NewRegistry(&cfg.Streams, log)
+	streamsHandler := streams_handlers.NewStreamsHandler(streamRegistry)
+	streamHub := streamhub.NewHub(router, cfg.Streams.MaxSubscriptionsPerSocket, log, "/api/v1/streams/ws")
+	multiplexHandler := streams_handlers.NewMultiplexHandler(streamHub, log)
+	warmCache := warmcache.NewCache(store, clientFactory, &cfg.WarmCache, log)
+	kubeHandler := api.NewKubeConfigHandler(store, clientFactory, log, &cfg.K8s, clustermeta.NewStore(documents, log), warmCache)
 
 	// Create configuration handlers
 	configMapsHandler := configurations.NewConfigMapsHandler(store, clientFactory, log)
@@ -156,13 +383,20 @@ func New(cfg *config.Config) *Server {
 
 	// Create cluster handlers
 	nodesHandler := cluster.NewNodesHandler(store, clientFactory, log)
+	nodeLogsHandler := cluster.NewNodeLogsHandler(store, clientFactory, log, &cfg.NodeLogs)
 	namespacesHandler := cluster.NewNamespacesHandler(store, clientFactory, log)
 	eventsHandler := cluster.NewEventsHandler(store, clientFactory, log)
 	leasesHandler := cluster.NewLeasesHandler(store, clientFactory, log)
+	autoscalerHandler := cluster.NewAutoscalerHandler(store, clientFactory, log)
+	hygieneHandler := cluster.NewHygieneHandler(store, clientFactory, log)
+	addonsHandler := cluster.NewAddonsHandler(store, clientFactory, log)
+	countsHandler := cluster.NewResourceCountsHandler(store, clientFactory, log)
+	controlPlaneHandler := cluster.NewControlPlaneHandler(store, clientFactory, log)
 
 	// Create custom resource handlers
 	customResourceDefinitionsHandler := custom_resources.NewCustomResourceDefinitionsHandler(store, clientFactory, log)
 	customResourcesHandler := custom_resources.NewCustomResourcesHandler(store, clientFactory, log)
+	crdNavigationHandler := custom_resources.NewCRDNavigationHandler(crdfavorites.NewStore(documents, log), store, clientFactory, log)
 
 	// Create workload handlers
 	podsHandler := workloads.NewPodsHandler(store, clientFactory, log)
@@ -170,9 +404,16 @@ func New(cfg *config.Config) *Server {
 	daemonSetsHandler := workloads.NewDaemonSetsHandler(store, clientFactory, log)
 	statefulSetsHandler := workloads.NewStatefulSetsHandler(store, clientFactory, log)
 	replicaSetsHandler := workloads.NewReplicaSetsHandler(store, clientFactory, log)
+	replicationControllersHandler := workloads.NewReplicationControllersHandler(store, clientFactory, log)
 	jobsHandler := workloads.NewJobsHandler(store, clientFactory, log)
 	cronJobsHandler := workloads.NewCronJobsHandler(store, clientFactory, log)
 	resourceReferencesHandler := workloads.NewResourceReferencesHandler(store, clientFactory, log)
+	imagesHandler := workloads.NewImagesHandler(store, clientFactory, log)
+	workloadImagesHandler := workloads.NewWorkloadImagesHandler(store, clientFactory, log)
+	workloadSLOHandler := workloads.NewWorkloadSLOHandler(store, clientFactory, log)
+	workloadDriftHandler := workloads.NewWorkloadDriftHandler(store, clientFactory, log)
+	topologyHandler := topology.NewTopologyHandler(store, clientFactory, log)
+	compareHandler := compare.NewCompareHandler(store, clientFactory, log)
 
 	// Create access control handlers
 	serviceAccountsHandler := access_control.NewServiceAccountsHandler(store, clientFactory, log)
@@ -187,7 +428,52 @@ func New(cfg *config.Config) *Server {
 	endpointsHandler := networking.NewEndpointsHandler(store, clientFactory, log)
 
 	// Metrics handlers
-	prometheusHandler := metrics_handlers.NewPrometheusHandler(store, clientFactory, log)
+	thresholdManager := thresholds.NewManager(documents, log)
+	prometheusHandler := metrics_handlers.NewPrometheusHandler(store, clientFactory, log, thresholdManager)
+	schedulingHandler := cluster.NewSchedulingHandler(store, clientFactory, prometheusHandler, log)
+	thresholdsHandler := thresholds_handlers.NewThresholdsHandler(thresholdManager, log)
+	lokiHandler := logs.NewLokiHandler(store, clientFactory, log, &cfg.Loki)
+	costHandler := cost.NewCostHandler(store, clientFactory, log, &cfg.Cost)
+
+	// Notification routing evaluates rules against events and Prometheus
+	notificationEngine := notifications.NewEngine(store, clientFactory, documents, prometheusHandler, log, &cfg.SMTP)
+	notificationsHandler := notifications_handlers.NewNotificationsHandler(notificationEngine, log)
+	alertsHandler := alerts_handlers.NewAlertsHandler(alerts.NewStore(documents, log), log)
+	snapshotsHandler := snapshots_handlers.NewSnapshotsHandler(snapshots.NewStore(documents, log), router, log)
+	dashboardsHandler := dashboards_handlers.NewDashboardsHandler(dashboards.NewStore(documents, log), store, clientFactory, prometheusHandler, log)
+
+	// Scheduled reports reuse the cost handler and notification channels
+	reportScheduler := reports.NewScheduler(store, clientFactory, documents, objects, costHandler, notificationEngine, log)
+	reportsHandler := reports_handlers.NewReportsHandler(reportScheduler, log)
+	rolloutTracker := rollouts.NewTracker(store, clientFactory, documents, log, &cfg.Rollouts)
+	rolloutsHandler := rollouts_handlers.NewRolloutsHandler(rolloutTracker, store, log)
+	podCleaner := podcleanup.NewCleaner(store, clientFactory, documents, log, &cfg.PodCleanup)
+	podCleanupHandler := podcleanup_handlers.NewPodCleanupHandler(podCleaner, store, log)
+	scaleScheduler := scaleschedules.NewScheduler(store, clientFactory, documents, log, &cfg.Scaling)
+	scaleSchedulesHandler := scaleschedules_handlers.NewScaleSchedulesHandler(scaleScheduler, store, log)
+	serviceProxyHandler := networking.NewServiceProxyHandler(store, clientFactory, auditRecorder, log, &cfg.ServiceProxy)
+	customActionsHandler := customactions_handlers.NewCustomActionsHandler(customactions.NewRegistry(&cfg.Actions, log), store, clientFactory, auditRecorder, log)
+	eventRecorder := eventhistory.NewRecorder(store, clientFactory, documents, store.GetDatabase(), log, &cfg.Events)
+	eventHistoryHandler := eventhistory_handlers.NewEventHistoryHandler(eventRecorder, store, log)
+	timeTravelHandler := metrics_handlers.NewTimeTravelHandler(prometheusHandler, eventRecorder, rolloutTracker, log)
+	crashWatcher := crashreports.NewWatcher(store, clientFactory, documents, log, &cfg.Crashes)
+	crashReportsHandler := crashreports_handlers.NewCrashReportsHandler(crashWatcher, store, log)
+	stormDetector := restartstorms.NewDetector(store, clientFactory, documents, log, &cfg.Storms)
+	restartStormsHandler := restartstorms_handlers.NewRestartStormsHandler(stormDetector, store, log)
+	namespaceGroupsHandler := namespacegroups_handlers.NewNamespaceGroupsHandler(namespacegroups.NewStore(documents, log), log)
+	savedViews := savedviews.NewStore(documents, log)
+	savedViewsHandler := savedviews_handlers.NewSavedViewsHandler(savedViews, log)
+	namespacePrefsHandler := namespaceprefs_handlers.NewNamespacePreferencesHandler(namespaceprefs.NewStore(documents, log), store, clientFactory, log)
+	bookmarksHandler := bookmarks_handlers.NewBookmarksHandler(bookmarks.NewStore(documents, log), store, clientFactory, log)
+
+	// Removing a kubeconfig or cluster tears down what subsystems hold for it
+	kubeHandler.RegisterCleanupHooks()
+	store.RegisterCleanupHook("streams", func(removal storage.Removal) (int, error) {
+		return streamRegistry.CloseMatching(removal.Matches), nil
+	})
+	store.RegisterCleanupHook("saved-views", savedViews.Cleanup)
+	store.RegisterCleanupHook("scale-schedules", scaleScheduler.Cleanup)
+	store.RegisterCleanupHook("report-schedules", reportScheduler.Cleanup)
 
 	// Create storage handlers
 	persistentVolumesHandler := storage_handlers.NewPersistentVolumesHandler(store, clientFactory, log)
@@ -197,14 +483,24 @@ func New(cfg *config.Config) *Server {
 	// Create WebSocket handlers
 	podLogsHandler := websockets.NewPodLogsHandler(store, clientFactory, log)
 	portForwardHandler := portforward.NewPortForwardHandler(store, clientFactory, log)
-	terminalHandler := terminal.NewHandler(store, clientFactory, log)
+	execPolicies := execpolicy.NewStore(documents, cfg.Exec.DenyNamespaces, log)
+	scrollbacks := terminal.NewScrollbackRegistry(cfg.Exec.ScrollbackBytes, time.Duration(cfg.Exec.ScrollbackRetentionMinutes)*time.Minute)
+	terminalHandler := terminal.NewHandler(store, clientFactory, execPolicies, snippets.NewStore(documents, log), scrollbacks, auditRecorder, log)
 
 	// Create Helm handlers
 	helmFactory := k8s.NewHelmClientFactory()
 	helmHandler := helm.NewHelmHandler(store, clientFactory, helmFactory, log)
+	gitOpsHandler := gitops.NewGitOpsHandler(store, clientFactory, log)
+	certManagerHandler := certmanager.NewCertManagerHandler(store, clientFactory, log)
+	kedaHandler := keda.NewKedaHandler(store, clientFactory, log)
+	meshHandler := mesh_handlers.NewMeshHandler(store, clientFactory, prometheusHandler, log)
+	clusterAPIHandler := clusterapi.NewClusterAPIHandler(store, clientFactory, log)
 
 	// Create base resources handler with helm handler dependency
-	baseResourcesHandler := handlers.NewResourcesHandler(store, clientFactory, log, helmHandler)
+	applySets := applysets.NewStore(documents, log)
+	baseResourcesHandler := handlers.NewResourcesHandler(store, clientFactory, log, helmHandler, &cfg.Lint, applySets)
+	store.RegisterCleanupHook("apply-sets", applySets.Cleanup)
+	namespaceTemplatesHandler := handlers.NewNamespaceTemplatesHandler(nstemplates.NewStore(documents, log), nsrequests.NewStore(documents, log), baseResourcesHandler, auditRecorder, log)
 
 	// Create Cloud Shell handlers
 	cloudShellHandler := cloudshell.NewCloudShellHandler(store, clientFactory, helmFactory, log)
@@ -235,6 +531,13 @@ func New(cfg *config.Config) *Server {
 
 	// Create feature flags handler
 	featureFlagsHandler := handlers.NewFeatureFlagsHandler(log)
+	openAPIHandler := handlers.NewOpenAPIHandler(log)
+
+	// Create security handlers
+	vulnerabilitiesHandler := security.NewVulnerabilitiesHandler(store, clientFactory, log, &cfg.Security)
+	podSecurityHandler := security.NewPodSecurityHandler(store, clientFactory, log)
+	policiesHandler := security.NewPoliciesHandler(store, clientFactory, log)
+	rbacAuditHandler := security.NewRBACAuditHandler(store, clientFactory, log)
 
 	// Create server
 	srv := &Server{
@@ -243,9 +546,13 @@ func New(cfg *config.Config) *Server {
 		router:               router,
 		store:                store,
 		clientFactory:        clientFactory,
+		documents:            documents,
 		kubeHandler:          kubeHandler,
+		warmCache:            warmCache,
 		baseResourcesHandler: baseResourcesHandler,
 
+		namespaceTemplatesHandler: namespaceTemplatesHandler,
+
 		// Configuration handlers
 		configMapsHandler:           configMapsHandler,
 		secretsHandler:              secretsHandler,
@@ -258,13 +565,23 @@ func New(cfg *config.Config) *Server {
 
 		// Cluster handlers
 		nodesHandler:      nodesHandler,
+		nodeLogsHandler:   nodeLogsHandler,
 		namespacesHandler: namespacesHandler,
 		eventsHandler:     eventsHandler,
 		leasesHandler:     leasesHandler,
+		autoscalerHandler: autoscalerHandler,
+		hygieneHandler:    hygieneHandler,
+		addonsHandler:     addonsHandler,
+		schedulingHandler: schedulingHandler,
+		countsHandler:     countsHandler,
+
+		// Control-plane health handler
+		controlPlaneHandler: controlPlaneHandler,
 
 		// Custom Resource handlers
 		customResourceDefinitionsHandler: customResourceDefinitionsHandler,
 		customResourcesHandler:           customResourcesHandler,
+		crdNavigationHandler:             crdNavigationHandler,
 
 		// Workload handlers
 		podsHandler:               podsHandler,
@@ -272,9 +589,16 @@ func New(cfg *config.Config) *Server {
 		daemonSetsHandler:         daemonSetsHandler,
 		statefulSetsHandler:       statefulSetsHandler,
 		replicaSetsHandler:        replicaSetsHandler,
+		replicationControllersHandler: replicationControllersHandler,
 		jobsHandler:               jobsHandler,
 		cronJobsHandler:           cronJobsHandler,
 		resourceReferencesHandler: resourceReferencesHandler,
+		imagesHandler:             imagesHandler,
+		workloadImagesHandler:     workloadImagesHandler,
+		workloadSLOHandler:        workloadSLOHandler,
+		workloadDriftHandler:      workloadDriftHandler,
+		topologyHandler:           topologyHandler,
+		compareHandler:            compareHandler,
 
 		// Access Control handlers
 		serviceAccountsHandler:     serviceAccountsHandler,
@@ -285,11 +609,88 @@ func New(cfg *config.Config) *Server {
 
 		// Networking handlers
 		servicesHandler:  servicesHandler,
+		serviceProxyHandler: serviceProxyHandler,
 		ingressesHandler: ingressesHandler,
 		endpointsHandler: endpointsHandler,
 
 		// Metrics handlers
 		prometheusHandler: prometheusHandler,
+		thresholdsHandler: thresholdsHandler,
+		timeTravelHandler: timeTravelHandler,
+		lokiHandler:       lokiHandler,
+		costHandler:       costHandler,
+
+		// Notification routing
+		notificationEngine:   notificationEngine,
+		notificationsHandler: notificationsHandler,
+
+		// External alerts
+		alertsHandler: alertsHandler,
+
+		// Saved dashboards
+		dashboardsHandler: dashboardsHandler,
+		// List snapshots
+		snapshotsHandler: snapshotsHandler,
+
+		// Scheduled reports
+		reportScheduler: reportScheduler,
+		objectLifecycle: objectLifecycle,
+		reportsHandler:  reportsHandler,
+
+		// Rollout analytics
+		rolloutTracker:  rolloutTracker,
+		rolloutsHandler: rolloutsHandler,
+
+		// Pod cleanup
+		podCleaner:        podCleaner,
+		podCleanupHandler: podCleanupHandler,
+
+		// Scale schedules
+		scaleScheduler:        scaleScheduler,
+		scaleSchedulesHandler: scaleSchedulesHandler,
+
+		// Custom actions
+		customActionsHandler: customActionsHandler,
+
+		// Event history
+		eventRecorder:       eventRecorder,
+		eventHistoryHandler: eventHistoryHandler,
+
+		// Crash reports
+		crashWatcher:        crashWatcher,
+		crashReportsHandler: crashReportsHandler,
+
+		// Restart storms
+		stormDetector:        stormDetector,
+		restartStormsHandler: restartStormsHandler,
+
+		// Namespace groups
+		namespaceGroupsHandler: namespaceGroupsHandler,
+
+		// Default namespaces
+		namespacePrefsHandler: namespacePrefsHandler,
+		bookmarksHandler:      bookmarksHandler,
+
+		// Saved views
+		savedViewsHandler: savedViewsHandler,
+
+		auditRecorder: auditRecorder,
+		auditHandler:  auditHandler,
+
+		// API tokens
+		apiTokens:     apiTokens,
+		tokensHandler: tokensHandler,
+
+		// Elevated access
+		elevationRequests: elevationRequests,
+		elevationHandler:  elevationHandler,
+
+		sourceLinks: sourceLinks,
+
+		streams:        streamRegistry,
+		streamsHandler: streamsHandler,
+
+		multiplexHandler: multiplexHandler,
 
 		// Storage handlers
 		persistentVolumesHandler:      persistentVolumesHandler,
@@ -304,6 +705,21 @@ func New(cfg *config.Config) *Server {
 		// Helm handlers
 		helmHandler: helmHandler,
 
+		// GitOps handlers
+		gitOpsHandler: gitOpsHandler,
+
+		// cert-manager handlers
+		certManagerHandler: certManagerHandler,
+
+		// KEDA handlers
+		kedaHandler: kedaHandler,
+
+		// Service mesh handlers
+		meshHandler: meshHandler,
+
+		// Cluster API handlers
+		clusterAPIHandler: clusterAPIHandler,
+
 		// Cloud Shell handlers
 		cloudShellHandler: cloudShellHandler,
 
@@ -313,6 +729,13 @@ func New(cfg *config.Config) *Server {
 
 		// Feature flags handler
 		featureFlagsHandler: featureFlagsHandler,
+		openAPIHandler:      openAPIHandler,
+
+		// Security handlers
+		vulnerabilitiesHandler: vulnerabilitiesHandler,
+		podSecurityHandler:     podSecurityHandler,
+		policiesHandler:        policiesHandler,
+		rbacAuditHandler:       rbacAuditHandler,
 	}
 
 	// Setup middleware
@@ -333,6 +756,42 @@ func New(cfg *config.Config) *Server {
 	// Start cloud shell cleanup routine
 	srv.cloudShellHandler.StartCleanupRoutine()
 
+	// Purge session-only kubeconfigs once they expire
+	srv.kubeHandler.StartSessionExpiry()
+
+	// Start evaluating notification rules
+	srv.notificationEngine.Start()
+
+	// Start running scheduled reports
+	srv.reportScheduler.Start()
+
+	// Start expiring stored artifacts past their retention
+	if srv.objectLifecycle != nil {
+		srv.objectLifecycle.Start()
+	}
+
+	// Start recording rollouts of tracked clusters
+	srv.rolloutTracker.Start()
+
+	// Start deleting old evicted and failed pods of clusters with a cleanup policy
+	srv.podCleaner.Start()
+	srv.scaleScheduler.Start()
+
+	// Start recording events of clusters with event history enabled
+	srv.eventRecorder.Start()
+
+	// Start capturing crash reports of clusters with capture enabled
+	srv.crashWatcher.Start()
+
+	// Start analyzing tracked clusters for restart storms
+	srv.stormDetector.Start()
+
+	// Start closing idle streams
+	srv.streams.Start()
+
+	// Prefetch the summaries of registered clusters in warm-cache mode
+	srv.warmCache.Start()
+
 	return srv
 }
 
@@ -341,6 +800,9 @@ func (s *Server) setupMiddleware() {
 	// Recovery middleware
 	s.router.Use(middleware.Recovery(s.logger.Logger))
 
+	// Correlation ID middleware, before logging so every log line and error response carries it
+	s.router.Use(middleware.CorrelationID())
+
 	// CORS middleware
 	s.router.Use(middleware.CORS())
 
@@ -351,6 +813,9 @@ func (s *Server) setupMiddleware() {
 
 	// Logging middleware
 	s.router.Use(middleware.Logger(s.logger.Logger))
+
+	// API token authentication for automation; requests without a token pass through
+	s.router.Use(apitokens.Middleware(s.apiTokens, s.auditRecorder))
 }
 
 // setupRoutes configures all routes
@@ -360,17 +825,186 @@ func (s *Server) setupRoutes() {
 
 	// API routes
 	api := s.router.Group("/api/v1")
+	// Track SSE and WebSocket streams, refusing them over the caps and while shutting down
+	api.Use(s.streams.Middleware())
+	// Expand view into the namespaces, labelSelector, sort and fields parameters of list endpoints
+	api.Use(s.savedViewsHandler.ResolveView)
+	// Expand namespaceGroup into the namespaces parameter understood by list endpoints
+	api.Use(s.namespaceGroupsHandler.ResolveNamespaceGroup)
+	// Multi-namespace lists skip namespaces denied by RBAC and report them as partial results
+	api.Use(utils.PartialResultsMiddleware())
+	// Session-only kubeconfigs resolve only for the session that uploaded them
+	api.Use(s.kubeHandler.RequireSessionOwner)
+	// Exec, debug pods and deletes by elevation-bound API tokens need an approved grant
+	api.Use(elevation.Middleware(s.elevationRequests, s.auditRecorder))
+	// Detail responses link to the Git source of the object
+	api.Use(sourcelinks.Middleware(s.sourceLinks))
 	{
 		// Metrics (Prometheus) endpoints
 		api.GET("/metrics/prometheus/availability", s.prometheusHandler.GetAvailability)
 		api.GET("/metrics/pods/:namespace/:name/prometheus", s.prometheusHandler.GetPodEnhancedMetricsSSE)
 		api.GET("/metrics/nodes/:name/prometheus", s.prometheusHandler.GetNodeMetricsSSE)
+		api.GET("/metrics/nodes/heatmap", s.prometheusHandler.GetNodeHeatmap)
 		api.GET("/metrics/overview/prometheus", s.prometheusHandler.GetClusterOverviewSSE)
+		api.GET("/metrics/overview/prometheus/ws", s.prometheusHandler.HandleClusterOverviewWS)
+		api.GET("/metrics/overview/fleet", s.prometheusHandler.GetFleetOverview)
+		api.GET("/metrics/analysis/resources", s.prometheusHandler.GetResourceAnalysis)
+		api.GET("/metrics/prometheus/targets", s.prometheusHandler.GetScrapeHealth)
+		api.POST("/metrics/batch", s.prometheusHandler.GetMetricsBatch)
+		api.GET("/metrics/customresources/:namespace/:name", s.prometheusHandler.GetCustomResourceMetrics)
+		api.GET("/metrics/customresource/:name", s.prometheusHandler.GetCustomResourceMetrics)
+		api.GET("/metrics/time-travel", s.timeTravelHandler.GetTimeTravel)
+		api.GET("/metrics/thresholds", s.thresholdsHandler.ListThresholds)
+		api.POST("/metrics/thresholds", s.thresholdsHandler.CreateThreshold)
+		api.PUT("/metrics/thresholds/:id", s.thresholdsHandler.UpdateThreshold)
+		api.DELETE("/metrics/thresholds/:id", s.thresholdsHandler.DeleteThreshold)
+		api.GET("/metrics/threshold-breaches", s.thresholdsHandler.ListBreaches)
+
+		// Historical logs (Loki)
+		api.GET("/logs/loki/availability", s.lokiHandler.GetAvailability)
+		api.GET("/logs/loki/query", s.lokiHandler.QueryLogs)
+		api.GET("/logs/loki/labels", s.lokiHandler.GetLabels)
+
+		// Cost allocation (OpenCost/Kubecost with request-based fallback)
+		api.GET("/cost/allocation", s.costHandler.GetCosts)
+
+		// Notification routing endpoints
+		api.GET("/notifications/rules", s.notificationsHandler.ListRules)
+		api.POST("/notifications/rules", s.notificationsHandler.CreateRule)
+		api.GET("/notifications/rules/:id", s.notificationsHandler.GetRule)
+		api.PUT("/notifications/rules/:id", s.notificationsHandler.UpdateRule)
+		api.DELETE("/notifications/rules/:id", s.notificationsHandler.DeleteRule)
+		api.POST("/notifications/rules/:id/mute", s.notificationsHandler.MuteRule)
+		api.POST("/notifications/rules/:id/test", s.notificationsHandler.TestRule)
+		api.GET("/notifications/deliveries", s.notificationsHandler.ListDeliveries)
+
+		// External alerts (Alertmanager webhook receiver)
+		api.POST("/alerts/webhook", s.alertsHandler.ReceiveWebhook)
+		api.GET("/alerts", s.alertsHandler.ListAlerts)
+		api.POST("/alerts/:id/acknowledge", s.alertsHandler.AcknowledgeAlert)
+		api.DELETE("/alerts/:id/acknowledge", s.alertsHandler.UnacknowledgeAlert)
+
+		// Saved PromQL dashboards
+		api.GET("/dashboards", s.dashboardsHandler.ListDashboards)
+		api.POST("/dashboards", s.dashboardsHandler.CreateDashboard)
+		api.GET("/dashboards/:id", s.dashboardsHandler.GetDashboard)
+		api.PUT("/dashboards/:id", s.dashboardsHandler.UpdateDashboard)
+		api.DELETE("/dashboards/:id", s.dashboardsHandler.DeleteDashboard)
+		api.GET("/dashboards/:id/run", s.dashboardsHandler.RunDashboardSSE)
+
+		// Shareable snapshots of list views
+		api.GET("/snapshots", s.snapshotsHandler.ListSnapshots)
+		api.POST("/snapshots", s.snapshotsHandler.CreateSnapshot)
+		api.GET("/snapshots/:id", s.snapshotsHandler.GetSnapshot)
+		api.DELETE("/snapshots/:id", s.snapshotsHandler.DeleteSnapshot)
+
+		// Scheduled report endpoints
+		api.GET("/reports/schedules", s.reportsHandler.ListSchedules)
+		api.POST("/reports/schedules", s.reportsHandler.CreateSchedule)
+		api.GET("/reports/schedules/:id", s.reportsHandler.GetSchedule)
+		api.PUT("/reports/schedules/:id", s.reportsHandler.UpdateSchedule)
+		api.DELETE("/reports/schedules/:id", s.reportsHandler.DeleteSchedule)
+		api.POST("/reports/schedules/:id/run", s.reportsHandler.RunSchedule)
+		api.GET("/reports/artifacts", s.reportsHandler.ListArtifacts)
+		api.GET("/reports/artifacts/:id/download", s.reportsHandler.DownloadArtifact)
+		api.DELETE("/reports/artifacts/:id", s.reportsHandler.DeleteArtifact)
+
+		// Rollout analytics
+		api.GET("/analytics/rollouts", s.rolloutsHandler.GetRolloutAnalytics)
+
+		// Evicted and failed pod cleanup
+		api.GET("/pod-cleanup/policy", s.podCleanupHandler.GetPolicy)
+		api.PUT("/pod-cleanup/policy", s.podCleanupHandler.UpdatePolicy)
+		api.POST("/pod-cleanup/run", s.podCleanupHandler.RunCleanup)
+		api.GET("/pod-cleanup/runs", s.podCleanupHandler.GetRuns)
+
+		// Time-based deployment scaling
+		api.GET("/scale-schedules", s.scaleSchedulesHandler.GetSchedules)
+		api.POST("/scale-schedules", s.scaleSchedulesHandler.CreateSchedule)
+		api.PUT("/scale-schedules/:id", s.scaleSchedulesHandler.UpdateSchedule)
+		api.DELETE("/scale-schedules/:id", s.scaleSchedulesHandler.DeleteSchedule)
+		api.POST("/scale-schedules/:id/run", s.scaleSchedulesHandler.RunSchedule)
+		api.GET("/scale-schedules/:id/runs", s.scaleSchedulesHandler.GetScheduleRuns)
+
+		// Operator-defined custom actions
+		api.GET("/custom-actions", s.customActionsHandler.ListActions)
+		api.GET("/custom-actions/resource", s.customActionsHandler.GetResourceActions)
+		api.POST("/custom-actions/:id/run", s.customActionsHandler.RunAction)
+
+		// Event history
+		api.GET("/event-history", s.eventHistoryHandler.GetEventHistory)
+		api.GET("/event-history/clusters", s.eventHistoryHandler.ListRecordedClusters)
+		api.POST("/event-history/clusters", s.eventHistoryHandler.EnableRecording)
+		api.DELETE("/event-history/clusters", s.eventHistoryHandler.DisableRecording)
+
+		// Crash-loop reports
+		api.GET("/crash-reports", s.crashReportsHandler.ListCrashReports)
+		api.GET("/crash-reports/clusters", s.crashReportsHandler.ListWatchedClusters)
+		api.POST("/crash-reports/clusters", s.crashReportsHandler.EnableCapture)
+		api.DELETE("/crash-reports/clusters", s.crashReportsHandler.DisableCapture)
+
+		// Restart storms
+		api.GET("/restart-storms", s.restartStormsHandler.GetRestartStorms)
+		api.DELETE("/restart-storms", s.restartStormsHandler.StopRestartStormAnalysis)
+
+		// Saved namespace groups
+		api.GET("/namespace-groups", s.namespaceGroupsHandler.ListNamespaceGroups)
+		api.POST("/namespace-groups", s.namespaceGroupsHandler.CreateNamespaceGroup)
+		api.GET("/namespace-groups/:id", s.namespaceGroupsHandler.GetNamespaceGroup)
+		api.PUT("/namespace-groups/:id", s.namespaceGroupsHandler.UpdateNamespaceGroup)
+		api.DELETE("/namespace-groups/:id", s.namespaceGroupsHandler.DeleteNamespaceGroup)
+
+		// Saved list views
+		api.GET("/saved-views", s.savedViewsHandler.ListSavedViews)
+		api.POST("/saved-views", s.savedViewsHandler.CreateSavedView)
+		api.GET("/saved-views/:id", s.savedViewsHandler.GetSavedView)
+		api.PUT("/saved-views/:id", s.savedViewsHandler.UpdateSavedView)
+		api.DELETE("/saved-views/:id", s.savedViewsHandler.DeleteSavedView)
+
+		// Default namespaces and accessible namespaces
+		api.GET("/accessible-namespaces", s.namespacePrefsHandler.GetAccessibleNamespaces)
+		api.GET("/namespace-preferences", s.namespacePrefsHandler.GetNamespacePreferences)
+		api.PUT("/namespace-preferences", s.namespacePrefsHandler.SetNamespacePreference)
+		api.DELETE("/namespace-preferences", s.namespacePrefsHandler.DeleteNamespacePreference)
+		api.GET("/resource-favorites", s.bookmarksHandler.GetFavorites)
+		api.PUT("/resource-favorites", s.bookmarksHandler.AddFavorite)
+		api.DELETE("/resource-favorites", s.bookmarksHandler.RemoveFavorite)
+		api.GET("/recent-resources", s.bookmarksHandler.GetRecent)
+		api.POST("/recent-resources", s.bookmarksHandler.RecordView)
+		api.DELETE("/recent-resources", s.bookmarksHandler.ClearRecent)
+
+		// Audit trail
+		api.GET("/audit/events", s.auditHandler.ListEvents)
+
+		// API tokens
+		api.GET("/auth/tokens", s.tokensHandler.ListTokens)
+		api.POST("/auth/tokens", s.tokensHandler.CreateToken)
+		api.POST("/auth/tokens/:id/revoke", s.tokensHandler.RevokeToken)
+		api.DELETE("/auth/tokens/:id", s.tokensHandler.DeleteToken)
+
+		// Elevated access requests
+		api.GET("/access-requests", s.elevationHandler.ListAccessRequests)
+		api.POST("/access-requests", s.elevationHandler.CreateAccessRequest)
+		api.GET("/access-requests/:id", s.elevationHandler.GetAccessRequest)
+		api.POST("/access-requests/:id/approve", s.elevationHandler.ApproveAccessRequest)
+		api.POST("/access-requests/:id/deny", s.elevationHandler.DenyAccessRequest)
+		api.POST("/access-requests/:id/revoke", s.elevationHandler.RevokeAccessRequest)
+
 		// API info
 		api.GET("/", s.apiInfo)
 
 		// Feature flags endpoint
 		api.GET("/feature-flags", s.featureFlagsHandler.GetFeatureFlags)
+		api.GET("/streams", s.streamsHandler.GetStreams)
+		api.GET("/streams/ws", s.multiplexHandler.ServeMultiplexed)
+
+		// Security endpoints
+		api.GET("/security/vulnerabilities", s.vulnerabilitiesHandler.GetVulnerabilities)
+		api.POST("/security/vulnerabilities/scan", s.vulnerabilitiesHandler.ScanImages)
+		api.GET("/security/pod-security", s.podSecurityHandler.GetPodSecurityAudit)
+		api.GET("/security/rbac-audit", s.rbacAuditHandler.GetRBACAudit)
+		api.GET("/security/policies", s.policiesHandler.GetPolicyStatus)
+		api.GET("/security/policies/violations", s.policiesHandler.GetPolicyViolations)
 
 
 
@@ -384,9 +1018,23 @@ func (s *Server) setupRoutes() {
 		api.POST("/app/config/validate-certificate", s.kubeHandler.ValidateCertificate)
 		api.GET("/app/config/validate-all", s.kubeHandler.ValidateAllKubeconfigs)
 		api.DELETE("/app/config/kubeconfigs/:id", s.kubeHandler.DeleteKubeconfig)
+		api.DELETE("/app/config/kubeconfigs/:id/clusters/:cluster", s.kubeHandler.DeleteCluster)
+		api.GET("/app/config/clusters", s.kubeHandler.GetClusterInfo)
+		api.GET("/app/config/whoami", s.kubeHandler.GetIdentities)
+		api.PUT("/app/config/kubeconfigs/:id/metadata", s.kubeHandler.UpdateClusterMetadata)
+		api.DELETE("/app/config/kubeconfigs/:id/metadata", s.kubeHandler.DeleteClusterMetadata)
+		api.POST("/app/config/kubeconfigs/:id/discovery/invalidate", s.kubeHandler.InvalidateDiscoveryCache)
+		api.GET("/app/config/kubeconfigs/:id/summary", s.kubeHandler.GetWarmSummary)
+		api.GET("/app/config/session", s.kubeHandler.GetSessionKubeconfigs)
+		api.POST("/app/config/session/logout", s.kubeHandler.EndSession)
 
 		// Apply Kubernetes resources from YAML
 		api.POST("/app/apply", s.baseResourcesHandler.ApplyResources)
+		api.POST("/app/apply/preview", s.baseResourcesHandler.PreviewMutations)
+		api.GET("/app/apply/sets", s.baseResourcesHandler.ListApplySets)
+		api.GET("/app/apply/sets/:id", s.baseResourcesHandler.GetApplySet)
+		api.POST("/app/apply/sets/:id/rollback", s.baseResourcesHandler.RollbackApplySet)
+		api.POST("/promote", s.baseResourcesHandler.PromoteWorkload)
 
 		// Kubernetes Resources - Cluster-scoped resources (SSE)
 		api.GET("/namespaces", s.namespacesHandler.GetNamespacesSSE)
@@ -394,17 +1042,58 @@ func (s *Server) setupRoutes() {
 		api.GET("/namespaces/:name/yaml", s.namespacesHandler.GetNamespaceYAML)
 		api.GET("/namespaces/:name/events", s.namespacesHandler.GetNamespaceEvents)
 		api.GET("/namespaces/:name/pods", s.namespacesHandler.GetNamespacePods)
+		api.GET("/namespaces/:name/summary", s.namespacesHandler.GetNamespaceSummary)
+		api.GET("/namespaces/:name/suspend-status", s.namespacesHandler.GetNamespaceSuspendStatus)
+		api.POST("/namespaces/:name/suspend", s.namespacesHandler.SuspendNamespace)
+		api.POST("/namespaces/:name/restart", s.namespacesHandler.RestartNamespaceWorkloads)
+		api.GET("/namespaces/:name/restart-status", s.namespacesHandler.GetNamespaceRestartStatus)
+		api.POST("/namespaces/:name/resume", s.namespacesHandler.ResumeNamespace)
+
+		// Namespace creation from bootstrap templates
+		api.POST("/namespaces", s.namespaceTemplatesHandler.CreateNamespace)
+		api.POST("/namespaces/:name/template", s.namespaceTemplatesHandler.ApplyNamespaceTemplate)
+		api.GET("/namespace-templates", s.namespaceTemplatesHandler.ListNamespaceTemplates)
+		api.POST("/namespace-templates", s.namespaceTemplatesHandler.CreateNamespaceTemplate)
+		api.GET("/namespace-templates/:id", s.namespaceTemplatesHandler.GetNamespaceTemplate)
+		api.PUT("/namespace-templates/:id", s.namespaceTemplatesHandler.UpdateNamespaceTemplate)
+		api.DELETE("/namespace-templates/:id", s.namespaceTemplatesHandler.DeleteNamespaceTemplate)
+
+		// Self-service namespace requests, provisioned from a template tier on approval
+		api.GET("/namespace-requests", s.namespaceTemplatesHandler.ListNamespaceRequests)
+		api.POST("/namespace-requests", s.namespaceTemplatesHandler.CreateNamespaceRequest)
+		api.GET("/namespace-requests/:id", s.namespaceTemplatesHandler.GetNamespaceRequest)
+		api.POST("/namespace-requests/:id/approve", s.namespaceTemplatesHandler.ApproveNamespaceRequest)
+		api.POST("/namespace-requests/:id/deny", s.namespaceTemplatesHandler.DenyNamespaceRequest)
+		api.GET("/resource-counts", s.countsHandler.GetResourceCounts)
+		api.GET("/resource-counts/:kind/summary", s.countsHandler.GetResourceSummary)
 		api.GET("/nodes", s.nodesHandler.GetNodesSSE)
+		api.GET("/nodes/inventory", s.nodesHandler.GetNodeInventory)
 		api.GET("/nodes/:name", s.nodesHandler.GetNode)
 		api.GET("/nodes/:name/yaml", s.nodesHandler.GetNodeYAML)
 		api.GET("/nodes/:name/events", s.nodesHandler.GetNodeEvents)
 		api.GET("/nodes/:name/pods", s.nodesHandler.GetNodePods)
+		api.GET("/nodes/:name/logs", s.nodeLogsHandler.GetNodeLogs)
+		api.GET("/nodes/:name/logs/access", s.nodeLogsHandler.GetNodeLogAccess)
 		// Node actions
 		api.POST("/nodes/:name/cordon", s.nodesHandler.CordonNode)
 		api.POST("/nodes/:name/uncordon", s.nodesHandler.UncordonNode)
 		api.POST("/nodes/:name/drain", s.nodesHandler.DrainNode)
+		api.GET("/nodes/:name/drain/simulate", s.nodesHandler.SimulateDrain)
 		api.GET("/nodes/actions/permissions", s.nodesHandler.CheckNodeActionPermission)
+		api.POST("/capacity/simulate", s.nodesHandler.SimulateCapacity)
+		api.GET("/capacity/bin-packing", s.nodesHandler.GetBinPackingReport)
+		api.GET("/capacity/spot-risk", s.nodesHandler.GetSpotRisk)
+		api.GET("/autoscaler/status", s.autoscalerHandler.GetAutoscalerStatus)
+		api.GET("/hygiene", s.hygieneHandler.GetHygieneReport)
+		api.GET("/addons", s.addonsHandler.GetAddonInventory)
+		api.GET("/control-plane/health", s.controlPlaneHandler.GetControlPlaneHealth)
+		api.GET("/scheduling", s.schedulingHandler.GetSchedulingReport)
 		api.GET("/customresourcedefinitions", s.customResourceDefinitionsHandler.GetCustomResourceDefinitionsSSE)
+		api.GET("/customresourcedefinitions/groups", s.crdNavigationHandler.GetCRDGroups)
+		api.GET("/customresourcedefinitions/categories", s.crdNavigationHandler.GetCRDCategories)
+		api.GET("/customresourcedefinitions/favorites", s.crdNavigationHandler.GetCRDFavorites)
+		api.PUT("/customresourcedefinitions/favorites/:name", s.crdNavigationHandler.SetCRDFavorite)
+		api.DELETE("/customresourcedefinitions/favorites/:name", s.crdNavigationHandler.DeleteCRDFavorite)
 		api.GET("/customresourcedefinitions/:name", s.customResourceDefinitionsHandler.GetCustomResourceDefinition)
 		api.GET("/customresources", s.customResourcesHandler.GetCustomResourcesSSE)
 		api.GET("/customresources/:namespace/:name", s.customResourcesHandler.GetCustomResource)
@@ -419,10 +1108,20 @@ func (s *Server) setupRoutes() {
 		api.DELETE("/:resourcekind", s.baseResourcesHandler.DeleteResources)
 		// Optimized bulk delete endpoint for 5+ items
 		api.DELETE("/bulk/:resourcekind", s.baseResourcesHandler.BulkDeleteResources)
+		// Bulk label and annotation editing by selector
+		api.PATCH("/bulk/:resourcekind/metadata", s.baseResourcesHandler.BulkEditMetadata)
 		// Permission check endpoint for actions like delete
 		api.GET("/permissions/check", s.baseResourcesHandler.CheckPermission)
 		// Permission check endpoint for YAML editing
 		api.GET("/permissions/yaml-edit", s.baseResourcesHandler.CheckYamlEditPermission)
+		// Single-object watch for any resource
+		api.GET("/objects/watch", s.baseResourcesHandler.WatchObject)
+		// Change timeline of any object
+		api.GET("/objects/timeline", s.baseResourcesHandler.GetObjectTimeline)
+		// Field documentation from the cluster's OpenAPI schema (kubectl explain)
+		api.GET("/explain", s.baseResourcesHandler.Explain)
+		// Pods and nodes matching a label selector
+		api.GET("/selectors/lookup", s.resourceReferencesHandler.LookupSelector)
 
 		// ConfigMaps endpoints
 		api.GET("/configmaps", s.configMapsHandler.GetConfigMapsSSE)
@@ -439,6 +1138,10 @@ func (s *Server) setupRoutes() {
 		api.GET("/secrets/:namespace/:name", s.secretsHandler.GetSecret)
 		api.GET("/secrets/:namespace/:name/yaml", s.secretsHandler.GetSecretYAML)
 		api.GET("/secrets/:namespace/:name/events", s.secretsHandler.GetSecretEvents)
+		api.PUT("/secrets/:namespace/:name/data", s.secretsHandler.UpdateSecretData)
+		api.POST("/secrets/:namespace/:name/rotate", s.secretsHandler.RotateSecret)
+		api.GET("/secrets/:namespace/:name/rotation", s.secretsHandler.GetSecretRotation)
+		api.POST("/secrets/:namespace/:name/rotation/confirm", s.secretsHandler.ConfirmSecretRotation)
 		api.GET("/secret/:name", s.secretsHandler.GetSecretByName)
 		api.GET("/secret/:name/yaml", s.secretsHandler.GetSecretYAMLByName)
 		api.GET("/secret/:name/events", s.secretsHandler.GetSecretEventsByName)
@@ -511,20 +1214,39 @@ func (s *Server) setupRoutes() {
 		api.GET("/deployments", s.deploymentsHandler.GetDeploymentsSSE)
 		api.POST("/deployments/:name/scale", s.deploymentsHandler.ScaleDeployment)
 		api.POST("/deployments/:name/restart", s.deploymentsHandler.RestartDeployment)
+		api.POST("/deployments/:name/revisions/cleanup", s.deploymentsHandler.CleanupDeploymentRevisions)
 		api.POST("/statefulsets/:name/scale", s.statefulSetsHandler.ScaleStatefulSet)
 		api.POST("/statefulsets/:name/restart", s.statefulSetsHandler.RestartStatefulSet)
+		api.POST("/statefulsets/:name/partition", s.statefulSetsHandler.SetStatefulSetPartition)
 		api.POST("/daemonsets/:name/restart", s.daemonSetsHandler.RestartDaemonSet)
 		api.GET("/daemonsets", s.daemonSetsHandler.GetDaemonSetsSSE)
 		api.GET("/statefulsets", s.statefulSetsHandler.GetStatefulSetsSSE)
 		api.GET("/replicasets", s.replicaSetsHandler.GetReplicaSetsSSE)
+		api.GET("/replicationcontrollers", s.replicationControllersHandler.GetReplicationControllersSSE)
+		api.GET("/pods/unowned", s.podsHandler.GetUnownedPods)
 		api.GET("/jobs", s.jobsHandler.GetJobsSSE)
 		api.GET("/cronjobs", s.cronJobsHandler.GetCronJobsSSE)
+		api.GET("/images", s.imagesHandler.GetImageInventory)
+		api.PUT("/workloads/:kind/:namespace/:name/image", s.workloadImagesHandler.UpdateWorkloadImage)
+		api.GET("/workloads/:kind/:namespace/:name/rollout-status", s.workloadImagesHandler.GetWorkloadRolloutStatus)
+		api.GET("/workloads/:kind/:namespace/:name/slo", s.workloadSLOHandler.GetWorkloadSLO)
+		api.GET("/workloads/:kind/:namespace/:name/drift", s.workloadDriftHandler.GetWorkloadDrift)
+		api.POST("/workloads/:kind/:namespace/:name/drift/delete-stale", s.workloadDriftHandler.DeleteStalePods)
 
 		// Workload detail endpoints
 		api.GET("/pods/:namespace/:name", s.podsHandler.GetPod)
 		api.GET("/pods/:namespace/:name/yaml", s.podsHandler.GetPodYAML)
 		api.GET("/pods/:namespace/:name/events", s.podsHandler.GetPodEvents)
 		api.GET("/pods/:namespace/:name/restarts", s.podsHandler.GetPodContainerRestartInfo)
+		api.GET("/pods/:namespace/:name/timeline", s.podsHandler.GetPodTimeline)
+		api.GET("/pods/:namespace/:name/env", s.podsHandler.GetPodContainerEnv)
+		api.GET("/pods/:namespace/:name/sandbox", s.podsHandler.GetPodSandbox)
+		api.GET("/pods/:namespace/:name/security", s.podsHandler.GetPodSecurity)
+		api.GET("/pods/:namespace/:name/deployment", s.podsHandler.ConvertPodToDeployment)
+		api.GET("/pods/:namespace/:name/crash-reports", s.crashReportsHandler.GetPodCrashReports)
+		api.GET("/pods/:namespace/:name/image-pull", s.imagesHandler.GetImagePullDiagnostics)
+		api.GET("/pods/:namespace/:name/selected-by", s.resourceReferencesHandler.GetPodSelectedBy)
+		api.POST("/pods/debug", s.podsHandler.CreateDebugPod)
 
 		api.GET("/pods/:namespace/:name/logs/ws", s.podLogsHandler.HandlePodLogs)
 		api.GET("/pods/:namespace/:name/metrics", s.podsHandler.GetPodMetricsHistory)
@@ -536,8 +1258,27 @@ func (s *Server) setupRoutes() {
 
 		// Terminal routes (WebSocket-based, K8s v5.channel.k8s.io protocol)
 		api.GET("/pods/:namespace/:name/exec/ws", s.terminalHandler.HandleExec)
+		api.GET("/pods/:namespace/:name/connections", s.terminalHandler.GetPodConnections)
 		api.GET("/terminal/exec/:namespace/:name/ws", s.terminalHandler.HandleExec)
 		api.GET("/terminal/cloudshell/:namespace/:name/ws", s.terminalHandler.HandleCloudShellExec)
+		api.GET("/terminal/policies", s.terminalHandler.ListExecPolicies)
+		api.POST("/terminal/policies", s.terminalHandler.CreateExecPolicy)
+		api.POST("/terminal/policies/evaluate", s.terminalHandler.EvaluateExecPolicy)
+		api.GET("/terminal/policies/:id", s.terminalHandler.GetExecPolicy)
+		api.PUT("/terminal/policies/:id", s.terminalHandler.UpdateExecPolicy)
+		api.DELETE("/terminal/policies/:id", s.terminalHandler.DeleteExecPolicy)
+		api.GET("/terminal/snippets", s.terminalHandler.ListSnippets)
+		api.POST("/terminal/snippets", s.terminalHandler.CreateSnippet)
+		api.GET("/terminal/snippets/:id", s.terminalHandler.GetSnippet)
+		api.PUT("/terminal/snippets/:id", s.terminalHandler.UpdateSnippet)
+		api.DELETE("/terminal/snippets/:id", s.terminalHandler.DeleteSnippet)
+		api.POST("/terminal/snippets/:id/render", s.terminalHandler.RenderSnippet)
+		api.GET("/terminal/history", s.terminalHandler.GetCommandHistory)
+		api.POST("/terminal/history", s.terminalHandler.RecordCommand)
+		api.DELETE("/terminal/history", s.terminalHandler.ClearCommandHistory)
+		api.GET("/terminal/sessions", s.terminalHandler.ListScrollbackSessions)
+		api.GET("/terminal/sessions/:id/scrollback/search", s.terminalHandler.SearchScrollback)
+		api.GET("/terminal/sessions/:id/scrollback/download", s.terminalHandler.DownloadScrollback)
 
 		// Port Forward routes
 		api.GET("/portforward/ws", s.portForwardHandler.HandlePortForward)
@@ -548,6 +1289,10 @@ func (s *Server) setupRoutes() {
 		api.GET("/deployments/:namespace/:name/yaml", s.deploymentsHandler.GetDeploymentYAML)
 		api.GET("/deployments/:namespace/:name/events", s.deploymentsHandler.GetDeploymentEvents)
 		api.GET("/deployments/:namespace/:name/pods", s.resourceReferencesHandler.GetDeploymentPods)
+		api.GET("/deployments/:namespace/:name/distribution", s.resourceReferencesHandler.GetDeploymentPodDistribution)
+		api.GET("/deployments/:namespace/:name/revisions", s.deploymentsHandler.GetDeploymentRevisions)
+		api.GET("/deployments/:namespace/:name/restart-status", s.deploymentsHandler.GetRecreateRestartStatus)
+		api.GET("/deployments/:namespace/:name/rollouts", s.rolloutsHandler.GetDeploymentRollouts)
 		api.GET("/deployment/:name", s.deploymentsHandler.GetDeploymentByName)
 		api.GET("/deployment/:name/yaml", s.deploymentsHandler.GetDeploymentYAMLByName)
 		api.GET("/deployment/:name/events", s.deploymentsHandler.GetDeploymentEventsByName)
@@ -566,6 +1311,9 @@ func (s *Server) setupRoutes() {
 		api.GET("/statefulsets/:namespace/:name/yaml", s.statefulSetsHandler.GetStatefulSetYAML)
 		api.GET("/statefulsets/:namespace/:name/events", s.statefulSetsHandler.GetStatefulSetEvents)
 		api.GET("/statefulsets/:namespace/:name/pods", s.resourceReferencesHandler.GetStatefulSetPods)
+		api.GET("/statefulsets/:namespace/:name/distribution", s.resourceReferencesHandler.GetStatefulSetPodDistribution)
+		api.GET("/statefulsets/:namespace/:name/pvcs", s.statefulSetsHandler.GetStatefulSetPVCs)
+		api.GET("/statefulsets/:namespace/:name/restart-status", s.statefulSetsHandler.GetOrderedRestartStatus)
 		api.GET("/statefulset/:name", s.statefulSetsHandler.GetStatefulSetByName)
 		api.GET("/statefulset/:name/yaml", s.statefulSetsHandler.GetStatefulSetYAMLByName)
 		api.GET("/statefulset/:name/events", s.statefulSetsHandler.GetStatefulSetEventsByName)
@@ -575,11 +1323,18 @@ func (s *Server) setupRoutes() {
 		api.GET("/replicasets/:namespace/:name/yaml", s.replicaSetsHandler.GetReplicaSetYAML)
 		api.GET("/replicasets/:namespace/:name/events", s.replicaSetsHandler.GetReplicaSetEvents)
 		api.GET("/replicasets/:namespace/:name/pods", s.resourceReferencesHandler.GetReplicaSetPods)
+		api.GET("/replicasets/:namespace/:name/distribution", s.resourceReferencesHandler.GetReplicaSetPodDistribution)
 		api.GET("/replicaset/:name", s.replicaSetsHandler.GetReplicaSetByName)
 		api.GET("/replicaset/:name/yaml", s.replicaSetsHandler.GetReplicaSetYAMLByName)
 		api.GET("/replicaset/:name/events", s.replicaSetsHandler.GetReplicaSetEventsByName)
 		api.GET("/replicaset/:name/pods", s.resourceReferencesHandler.GetReplicaSetPodsByName)
 
+		api.GET("/replicationcontrollers/:namespace/:name", s.replicationControllersHandler.GetReplicationController)
+		api.GET("/replicationcontrollers/:namespace/:name/yaml", s.replicationControllersHandler.GetReplicationControllerYAML)
+		api.GET("/replicationcontrollers/:namespace/:name/events", s.replicationControllersHandler.GetReplicationControllerEvents)
+		api.GET("/replicationcontrollers/:namespace/:name/pods", s.replicationControllersHandler.GetReplicationControllerPods)
+		api.GET("/replicationcontrollers/:namespace/:name/deployment", s.replicationControllersHandler.ConvertReplicationControllerToDeployment)
+
 		api.GET("/jobs/:namespace/:name", s.jobsHandler.GetJob)
 		api.GET("/jobs/:namespace/:name/yaml", s.jobsHandler.GetJobYAML)
 		api.GET("/jobs/:namespace/:name/events", s.jobsHandler.GetJobEvents)
@@ -589,10 +1344,17 @@ func (s *Server) setupRoutes() {
 		api.GET("/job/:name/events", s.jobsHandler.GetJobEventsByName)
 		api.GET("/job/:name/pods", s.resourceReferencesHandler.GetJobPodsByName)
 
+		api.GET("/cronjobs/calendar", s.cronJobsHandler.GetCronJobCalendar)
 		api.GET("/cronjobs/:namespace/:name", s.cronJobsHandler.GetCronJob)
 		api.GET("/cronjobs/:namespace/:name/yaml", s.cronJobsHandler.GetCronJobYAML)
 		api.GET("/cronjobs/:namespace/:name/events", s.cronJobsHandler.GetCronJobEvents)
 		api.GET("/cronjobs/:namespace/:name/jobs", s.resourceReferencesHandler.GetCronJobJobs)
+
+		// Topology export
+		api.GET("/topology/export", s.topologyHandler.ExportTopology)
+		api.GET("/compare", s.compareHandler.CompareClusters)
+		api.GET("/compare/workload", s.compareHandler.CompareWorkloads)
+		api.GET("/compare/workload/helm", s.compareHandler.CompareHelmRevision)
 		api.POST("/cronjobs/:namespace/:name/trigger", s.cronJobsHandler.TriggerCronJob)
 		api.PATCH("/cronjobs/:namespace/:name/suspend", s.cronJobsHandler.SuspendCronJob)
 		api.GET("/cronjob/:name", s.cronJobsHandler.GetCronJobByName)
@@ -652,6 +1414,7 @@ func (s *Server) setupRoutes() {
 		api.GET("/services/:namespace/:name", s.servicesHandler.GetService)
 		api.GET("/services/:namespace/:name/yaml", s.servicesHandler.GetServiceYAML)
 		api.GET("/services/:namespace/:name/events", s.servicesHandler.GetServiceEvents)
+		api.Any("/services/:namespace/:name/proxy/:port/*path", s.serviceProxyHandler.ProxyService)
 		api.GET("/service/:name", s.servicesHandler.GetServiceByName)
 		api.GET("/service/:name/yaml", s.servicesHandler.GetServiceYAMLByName)
 		api.GET("/service/:name/events", s.servicesHandler.GetServiceEventsByName)
@@ -705,13 +1468,43 @@ func (s *Server) setupRoutes() {
 		api.GET("/helmreleases/:name", s.helmHandler.GetHelmReleaseDetails)
 		api.GET("/helmreleases/:name/history", s.helmHandler.GetHelmReleaseHistory)
 		api.GET("/helmreleases/:name/resources", s.helmHandler.GetHelmReleaseResources)
+		api.GET("/helmreleases/:name/values-diff", s.helmHandler.GetHelmReleaseValuesDiff)
 		api.POST("/helmreleases/:name/rollback", s.helmHandler.RollbackHelmRelease)
 
+		// GitOps routes
+		api.GET("/gitops/applications", s.gitOpsHandler.GetApplications)
+		api.GET("/gitops/applications/:namespace/:name", s.gitOpsHandler.GetApplication)
+
+		// cert-manager routes
+		api.GET("/certmanager/certificates", s.certManagerHandler.GetCertificates)
+		api.POST("/certmanager/certificates/:name/renew", s.certManagerHandler.RenewCertificate)
+		api.GET("/certmanager/issuers", s.certManagerHandler.GetIssuers)
+		api.GET("/certmanager/certificaterequests", s.certManagerHandler.GetCertificateRequests)
+
+		// KEDA routes
+		api.GET("/keda/scaledobjects", s.kedaHandler.GetScaledObjects)
+		api.GET("/keda/scaledobjects/:namespace/:name", s.kedaHandler.GetScaledObject)
+		api.GET("/keda/scaledjobs", s.kedaHandler.GetScaledJobs)
+		api.POST("/keda/:kind/:namespace/:name/pause", s.kedaHandler.PauseScaling)
+		api.POST("/keda/:kind/:namespace/:name/resume", s.kedaHandler.ResumeScaling)
+
+		// Service mesh (Istio and Linkerd)
+		api.GET("/mesh/status", s.meshHandler.GetMeshStatus)
+		api.GET("/mesh/services/:namespace/:name", s.meshHandler.GetServiceMesh)
+		api.GET("/mesh/services/:namespace/:name/metrics", s.meshHandler.GetServiceGoldenMetrics)
+
+		// Cluster API routes
+		api.GET("/clusterapi/clusters", s.clusterAPIHandler.GetClusters)
+		api.GET("/clusterapi/machinedeployments", s.clusterAPIHandler.GetMachineDeployments)
+		api.POST("/clusterapi/machinedeployments/:name/scale", s.clusterAPIHandler.ScaleMachineDeployment)
+		api.GET("/clusterapi/machines", s.clusterAPIHandler.GetMachines)
+
 		// Helm Charts endpoints
 		api.GET("/helmcharts", s.helmHandler.SearchHelmCharts)
 		api.GET("/helmcharts/:packageId", s.helmHandler.GetHelmChartDetails)
 		api.GET("/helmcharts/:packageId/versions", s.helmHandler.GetHelmChartVersions)
 		api.GET("/helmcharts/:packageId/:version/templates", s.helmHandler.GetHelmChartTemplates)
+		api.POST("/helmcharts/preflight", s.helmHandler.PreflightHelmChart)
 		api.POST("/helmcharts/install", s.helmHandler.InstallHelmChart)
 		api.POST("/helmcharts/upgrade", s.helmHandler.UpgradeHelmChart)
 
@@ -733,6 +1526,25 @@ func (s *Server) setupRoutes() {
 		}
 	}
 
+	// Versioned API with typed responses whose schemas only change in backwards-compatible ways,
+	// for clients generated from its OpenAPI document
+	v2 := s.router.Group("/api/v2")
+	v2.Use(s.kubeHandler.RequireSessionOwner)
+	{
+		v2.GET("/openapi.json", s.openAPIHandler.GetOpenAPIV2)
+		v2.GET("/customresources", s.customResourcesHandler.ListCustomResourcesV2)
+		v2.GET("/helmcharts", s.helmHandler.SearchHelmCharts)
+		v2.GET("/helmcharts/:packageId", s.helmHandler.GetHelmChartDetailsV2)
+		v2.GET("/metrics/prometheus/availability", s.prometheusHandler.GetAvailability)
+		v2.GET("/metrics/prometheus/targets", s.prometheusHandler.GetScrapeHealth)
+		v2.GET("/metrics/nodes/heatmap", s.prometheusHandler.GetNodeHeatmap)
+		v2.GET("/metrics/analysis/resources", s.prometheusHandler.GetResourceAnalysis)
+		v2.POST("/metrics/batch", s.prometheusHandler.GetMetricsBatch)
+		v2.GET("/metrics/time-travel", s.timeTravelHandler.GetTimeTravel)
+		v2.GET("/metrics/customresources/:namespace/:name", s.prometheusHandler.GetCustomResourceMetrics)
+		v2.GET("/metrics/customresource/:name", s.prometheusHandler.GetCustomResourceMetrics)
+	}
+
 	// Swagger documentation endpoint
 	s.router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
@@ -772,7 +1584,7 @@ func (s *Server) apiInfo(c *gin.Context) {
 		"name":        "kube-dash API",
 		"version":     "1.0.0",
 		"description": "Kubernetes Dashboard API",
-		"endpoints":   []string{"/health", "/api/v1/"},
+		"endpoints":   []string{"/health", "/api/v1/", "/api/v2/openapi.json"},
 	})
 }
 
@@ -816,6 +1628,26 @@ func (s *Server) Start() error {
 // Stop gracefully stops the server
 func (s *Server) Stop(ctx context.Context) error {
 	s.logger.Info("Stopping server")
+
+	// Tell stream clients when to reconnect and close their streams; http.Server.Shutdown
+	// neither waits for nor closes hijacked WebSocket connections
+	if err := s.streams.Shutdown(ctx); err != nil {
+		s.logger.WithError(err).Warn("Streams did not close before the shutdown deadline")
+	}
+
+	// Stop background notification and report routines before the store is closed
+	s.notificationEngine.Stop()
+	s.reportScheduler.Stop()
+	if s.objectLifecycle != nil {
+		s.objectLifecycle.Stop()
+	}
+	s.rolloutTracker.Stop()
+	s.podCleaner.Stop()
+	s.scaleScheduler.Stop()
+	s.eventRecorder.Stop()
+	s.crashWatcher.Stop()
+	s.stormDetector.Stop()
+	s.warmCache.Stop()
 	
 	// Close database connection if using persistent storage
 	if err := s.store.Close(); err != nil {
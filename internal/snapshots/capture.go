@@ -0,0 +1,185 @@
+package snapshots
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// maxPayloadBytes bounds the list payload a snapshot may hold
+const maxPayloadBytes = 32 << 20
+
+// ErrPayloadTooLarge is returned when a list payload exceeds maxPayloadBytes
+var ErrPayloadTooLarge = errors.New("list payload exceeds the snapshot size limit")
+
+// Capture requests a list endpoint from handler on behalf of the original request and returns
+// the first payload it sends. List endpoints stream updates over SSE, so the request is
+// cancelled as soon as the initial data event arrives; plain JSON responses are returned whole.
+func Capture(ctx context.Context, handler http.Handler, original *http.Request, path string, query url.Values) (json.RawMessage, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, path+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	// Forward credentials and the session so the capture sees what the caller would
+	req.Header = original.Header.Clone()
+	req.Header.Del("Content-Type")
+	req.Header.Del("Content-Length")
+	req.Header.Del("Accept-Encoding")
+	req.Header.Set("Accept", "text/event-stream")
+	req.RemoteAddr = original.RemoteAddr
+
+	w := newFrameWriter(cancel)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler.ServeHTTP(w, req)
+	}()
+	select {
+	case <-w.ready:
+	case <-done:
+	}
+	cancel()
+	<-done
+	return w.result()
+}
+
+// frameWriter records a response until its first complete SSE event
+type frameWriter struct {
+	header http.Header
+	cancel context.CancelFunc
+	ready  chan struct{}
+
+	mu       sync.Mutex
+	status   int
+	body     bytes.Buffer
+	complete bool
+	tooLarge bool
+}
+
+func newFrameWriter(cancel context.CancelFunc) *frameWriter {
+	return &frameWriter{header: http.Header{}, cancel: cancel, ready: make(chan struct{})}
+}
+
+func (w *frameWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *frameWriter) WriteHeader(status int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *frameWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if w.complete {
+		// Keep-alives and updates after the first event are not part of the snapshot
+		return len(p), nil
+	}
+	if w.body.Len()+len(p) > maxPayloadBytes {
+		w.tooLarge = true
+		w.finish()
+		return 0, ErrPayloadTooLarge
+	}
+	w.body.Write(p)
+	if w.isStream() && bytes.Contains(w.body.Bytes(), []byte("\n\n")) {
+		w.finish()
+	}
+	return len(p), nil
+}
+
+// Flush satisfies http.Flusher, which streaming handlers require
+func (w *frameWriter) Flush() {}
+
+func (w *frameWriter) isStream() bool {
+	return strings.HasPrefix(w.header.Get("Content-Type"), "text/event-stream")
+}
+
+func (w *frameWriter) finish() {
+	if !w.complete {
+		w.complete = true
+		close(w.ready)
+		w.cancel()
+	}
+}
+
+// result extracts the payload from the recorded response
+func (w *frameWriter) result() (json.RawMessage, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.tooLarge {
+		return nil, ErrPayloadTooLarge
+	}
+	body := w.body.Bytes()
+	if w.status == http.StatusNotFound {
+		return nil, fmt.Errorf("no list endpoint at this path")
+	}
+	if !w.isStream() {
+		if w.status != http.StatusOK {
+			return nil, fmt.Errorf("list endpoint returned %d: %s", w.status, responseError(body))
+		}
+		if !json.Valid(body) {
+			return nil, fmt.Errorf("list endpoint did not return JSON")
+		}
+		return json.RawMessage(body), nil
+	}
+
+	event, data := parseEvent(body)
+	if event == "error" || event == "permission_error" {
+		return nil, fmt.Errorf("list endpoint failed: %s", responseError(data))
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("list endpoint sent no data")
+	}
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("list endpoint sent invalid JSON")
+	}
+	return json.RawMessage(data), nil
+}
+
+// parseEvent returns the name and joined data lines of the first SSE event in body
+func parseEvent(body []byte) (string, []byte) {
+	if i := bytes.Index(body, []byte("\n\n")); i >= 0 {
+		body = body[:i]
+	}
+	event := ""
+	var data [][]byte
+	for _, line := range bytes.Split(body, []byte("\n")) {
+		switch {
+		case bytes.HasPrefix(line, []byte("event:")):
+			event = strings.TrimSpace(string(line[len("event:"):]))
+		case bytes.HasPrefix(line, []byte("data:")):
+			data = append(data, bytes.TrimPrefix(line[len("data:"):], []byte(" ")))
+		}
+	}
+	return event, bytes.Join(data, []byte("\n"))
+}
+
+// responseError returns the error message of a {"error": ...} body, or the body itself
+func responseError(body []byte) string {
+	var e struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(body, &e) == nil && e.Error != "" {
+		return e.Error
+	}
+	if len(body) > 200 {
+		body = body[:200]
+	}
+	return strings.TrimSpace(string(body))
+}
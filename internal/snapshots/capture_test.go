@@ -0,0 +1,70 @@
+package snapshots
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestCaptureStream(t *testing.T) {
+	stopped := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("namespace") != "prod" || r.Header.Get("Authorization") != "Bearer t" {
+			t.Errorf("unexpected capture request %s %v", r.URL, r.Header)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte(`data: [{"name":"a"},{"name":"b"}]` + "\n\n"))
+		w.(http.Flusher).Flush()
+		// Streams keep running until the client goes away
+		<-r.Context().Done()
+		close(stopped)
+	})
+	original, _ := http.NewRequest(http.MethodPost, "/api/v1/snapshots", nil)
+	original.Header.Set("Authorization", "Bearer t")
+
+	payload, err := Capture(context.Background(), handler, original, "/api/v1/pods", url.Values{"namespace": {"prod"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(payload) != `[{"name":"a"},{"name":"b"}]` || countItems(payload) != 2 {
+		t.Errorf("unexpected payload %s", payload)
+	}
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Error("expected the stream to be cancelled after the first event")
+	}
+}
+
+func TestCaptureErrors(t *testing.T) {
+	original, _ := http.NewRequest(http.MethodPost, "/api/v1/snapshots", nil)
+	cases := map[string]http.HandlerFunc{
+		"sse error": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Write([]byte("event:error\ndata:{\"error\":\"config not found\"}\n\n"))
+		},
+		"not found": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		},
+		"json error": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"bad"}`))
+		},
+	}
+	for name, handler := range cases {
+		if _, err := Capture(context.Background(), handler, original, "/api/v1/pods", url.Values{}); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	json := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items":[]}`))
+	})
+	if payload, err := Capture(context.Background(), json, original, "/api/v1/pods", url.Values{}); err != nil || countItems(payload) != 1 {
+		t.Errorf("expected plain JSON payload, got %s %v", payload, err)
+	}
+}
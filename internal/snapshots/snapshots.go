@@ -0,0 +1,119 @@
+package snapshots
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// snapshotsCollection is the document collection holding captured snapshots
+const snapshotsCollection = "snapshots"
+
+// Snapshot is a list view payload captured at a point in time, kept after the objects are gone
+type Snapshot struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name,omitempty"`
+	Resource   string            `json:"resource"` // list endpoint below /api/v1, e.g. pods
+	ConfigID   string            `json:"configId"`
+	Cluster    string            `json:"cluster"`
+	Filters    map[string]string `json:"filters,omitempty"` // query parameters the list was captured with
+	CapturedBy string            `json:"capturedBy,omitempty"`
+	CapturedAt time.Time         `json:"capturedAt"`
+	Count      int               `json:"count"` // items in the payload when it is an array
+	Size       int               `json:"size"`  // payload size in bytes
+	Payload    json.RawMessage   `json:"payload,omitempty"`
+}
+
+// Summary returns the snapshot without its payload
+func (s Snapshot) Summary() Snapshot {
+	s.Payload = nil
+	return s
+}
+
+// Filter narrows the snapshots returned by List; empty fields match everything
+type Filter struct {
+	ConfigID string
+	Cluster  string
+	Resource string
+}
+
+// Store persists snapshots
+type Store struct {
+	documents *storage.DocumentStore
+	logger    *logger.Logger
+}
+
+// NewStore creates a snapshot store
+func NewStore(documents *storage.DocumentStore, log *logger.Logger) *Store {
+	return &Store{
+		documents: documents,
+		logger:    log,
+	}
+}
+
+// List returns snapshot summaries matching the filter, newest first
+func (s *Store) List(filter Filter) ([]Snapshot, error) {
+	docs, err := s.documents.List(snapshotsCollection)
+	if err != nil {
+		return nil, err
+	}
+	snapshots := make([]Snapshot, 0, len(docs))
+	for id, data := range docs {
+		var snap Snapshot
+		if err := json.Unmarshal(data, &snap); err != nil {
+			s.logger.WithError(err).WithField("snapshot", id).Error("Skipping unreadable snapshot")
+			continue
+		}
+		if (filter.ConfigID != "" && snap.ConfigID != filter.ConfigID) || (filter.Cluster != "" && snap.Cluster != filter.Cluster) ||
+			(filter.Resource != "" && snap.Resource != filter.Resource) {
+			continue
+		}
+		snapshots = append(snapshots, snap.Summary())
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].CapturedAt.After(snapshots[j].CapturedAt) })
+	return snapshots, nil
+}
+
+// Get returns a snapshot with its payload
+func (s *Store) Get(id string) (*Snapshot, error) {
+	var snap Snapshot
+	if err := s.documents.Get(snapshotsCollection, id, &snap); err != nil {
+		return nil, err
+	}
+	return &snap, nil
+}
+
+// Save persists a new snapshot, assigning its ID and counting the payload's items
+func (s *Store) Save(snap *Snapshot) error {
+	if !json.Valid(snap.Payload) {
+		return fmt.Errorf("snapshot payload is not valid JSON")
+	}
+	snap.ID = uuid.New().String()
+	snap.Size = len(snap.Payload)
+	snap.Count = countItems(snap.Payload)
+	return s.documents.Put(snapshotsCollection, snap.ID, snap)
+}
+
+// Delete removes a snapshot
+func (s *Store) Delete(id string) error {
+	return s.documents.Delete(snapshotsCollection, id)
+}
+
+// countItems returns the length of a JSON array payload, or 1 for any other value
+func countItems(payload json.RawMessage) int {
+	if !strings.HasPrefix(strings.TrimSpace(string(payload)), "[") {
+		return 1
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(payload, &items); err != nil {
+		return 0
+	}
+	return len(items)
+}
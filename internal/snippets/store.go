@@ -0,0 +1,185 @@
+package snippets
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// snippetsCollection is the document collection holding terminal snippets
+	snippetsCollection = "terminal_snippets"
+	// historyCollection is the document collection holding recent terminal commands
+	historyCollection = "terminal_history"
+)
+
+// maxHistory bounds the recent commands kept per owner and pod kind
+const maxHistory = 20
+
+// Snippet is a saved terminal command offered by the snippet palette. The command may
+// reference variables such as {{namespace}} or {{pod}} that are filled in when rendered.
+type Snippet struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Command     string    `json:"command"`
+	Description string    `json:"description,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	Owner       string    `json:"owner,omitempty"` // empty for snippets shared with everyone
+	Variables   []string  `json:"variables"`       // derived from the command on save
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// Validate checks that a snippet can be saved and records the variables its command uses
+func (s *Snippet) Validate() error {
+	s.Name = strings.TrimSpace(s.Name)
+	if s.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if strings.TrimSpace(s.Command) == "" {
+		return fmt.Errorf("command is required")
+	}
+	variables, err := Variables(s.Command)
+	if err != nil {
+		return err
+	}
+	s.Variables = variables
+	return nil
+}
+
+// History holds the most recent commands an owner ran in pods of one kind, newest first
+type History struct {
+	Owner    string    `json:"owner,omitempty"`
+	Kind     string    `json:"kind"`
+	Commands []string  `json:"commands"`
+	Updated  time.Time `json:"updatedAt"`
+}
+
+// Store persists terminal snippets and recent command history
+type Store struct {
+	documents *storage.DocumentStore
+	logger    *logger.Logger
+}
+
+// NewStore creates a snippet store
+func NewStore(documents *storage.DocumentStore, log *logger.Logger) *Store {
+	return &Store{
+		documents: documents,
+		logger:    log,
+	}
+}
+
+// List returns the owner's and shared snippets sorted by name; an empty owner lists every snippet
+func (s *Store) List(owner, tag string) ([]Snippet, error) {
+	docs, err := s.documents.List(snippetsCollection)
+	if err != nil {
+		return nil, err
+	}
+	snippets := make([]Snippet, 0, len(docs))
+	for id, data := range docs {
+		var snippet Snippet
+		if err := json.Unmarshal(data, &snippet); err != nil {
+			s.logger.WithError(err).WithField("snippet", id).Error("Skipping unreadable terminal snippet")
+			continue
+		}
+		if owner != "" && snippet.Owner != "" && snippet.Owner != owner {
+			continue
+		}
+		if tag != "" && !hasTag(snippet.Tags, tag) {
+			continue
+		}
+		snippets = append(snippets, snippet)
+	}
+	sort.Slice(snippets, func(i, j int) bool { return snippets[i].Name < snippets[j].Name })
+	return snippets, nil
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Get returns a single snippet
+func (s *Store) Get(id string) (*Snippet, error) {
+	var snippet Snippet
+	if err := s.documents.Get(snippetsCollection, id, &snippet); err != nil {
+		return nil, err
+	}
+	return &snippet, nil
+}
+
+// Save validates and persists a snippet, assigning an ID to new snippets
+func (s *Store) Save(snippet *Snippet) error {
+	if err := snippet.Validate(); err != nil {
+		return err
+	}
+	now := time.Now()
+	if snippet.ID == "" {
+		snippet.ID = uuid.New().String()
+		snippet.CreatedAt = now
+	}
+	snippet.UpdatedAt = now
+	return s.documents.Put(snippetsCollection, snippet.ID, snippet)
+}
+
+// Delete removes a snippet
+func (s *Store) Delete(id string) error {
+	return s.documents.Delete(snippetsCollection, id)
+}
+
+// historyID keys the history of an owner and pod kind
+func historyID(owner, kind string) string {
+	return owner + "/" + kind
+}
+
+// History returns the recent commands of an owner for a pod kind
+func (s *Store) History(owner, kind string) (*History, error) {
+	history := History{Owner: owner, Kind: kind, Commands: []string{}}
+	err := s.documents.Get(historyCollection, historyID(owner, kind), &history)
+	if err != nil && !errors.Is(err, storage.ErrDocumentNotFound) {
+		return nil, err
+	}
+	return &history, nil
+}
+
+// Record adds a command to the front of the owner's history for a pod kind, dropping
+// earlier runs of the same command and the oldest commands beyond the limit
+func (s *Store) Record(owner, kind, command string) (*History, error) {
+	command = strings.TrimSpace(command)
+	if kind == "" || command == "" {
+		return nil, fmt.Errorf("kind and command are required")
+	}
+	history, err := s.History(owner, kind)
+	if err != nil {
+		return nil, err
+	}
+	commands := []string{command}
+	for _, previous := range history.Commands {
+		if previous != command && len(commands) < maxHistory {
+			commands = append(commands, previous)
+		}
+	}
+	history.Commands = commands
+	history.Updated = time.Now()
+	if err := s.documents.Put(historyCollection, historyID(owner, kind), history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// ClearHistory forgets the recent commands of an owner for a pod kind
+func (s *Store) ClearHistory(owner, kind string) error {
+	return s.documents.Delete(historyCollection, historyID(owner, kind))
+}
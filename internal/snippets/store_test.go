@@ -0,0 +1,51 @@
+package snippets
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+)
+
+func TestRecordHistory(t *testing.T) {
+	store := NewStore(storage.NewDocumentStore(nil), logger.New("error"))
+	for _, command := range []string{"ls", "env", "ls", " ps aux "} {
+		if _, err := store.Record("alice", "nginx", command); err != nil {
+			t.Fatal(err)
+		}
+	}
+	history, err := store.History("alice", "nginx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"ps aux", "ls", "env"}; !reflect.DeepEqual(history.Commands, want) {
+		t.Errorf("commands = %v, want %v", history.Commands, want)
+	}
+
+	for i := 0; i < maxHistory+5; i++ {
+		store.Record("alice", "nginx", fmt.Sprintf("echo %d", i))
+	}
+	if history, _ = store.History("alice", "nginx"); len(history.Commands) != maxHistory {
+		t.Errorf("kept %d commands, want %d", len(history.Commands), maxHistory)
+	}
+	if other, _ := store.History("bob", "nginx"); len(other.Commands) != 0 {
+		t.Errorf("expected histories to be kept per owner, got %v", other.Commands)
+	}
+}
+
+func TestSnippetValidate(t *testing.T) {
+	s := Snippet{Name: " tail logs ", Command: "tail -f /var/log/{{file}} # {{pod}}"}
+	if err := s.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if s.Name != "tail logs" || !reflect.DeepEqual(s.Variables, []string{"file", "pod"}) {
+		t.Errorf("got %+v", s)
+	}
+	for i, invalid := range []Snippet{{Command: "ls"}, {Name: "empty"}, {Name: "bad", Command: "echo {{a b}}"}} {
+		if err := invalid.Validate(); err == nil {
+			t.Errorf("case %d: expected a validation error", i)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package snippets
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// variablePattern matches {{name}} placeholders, allowing spaces inside the braces
+var variablePattern = regexp.MustCompile(`{{\s*([^{}]*?)\s*}}`)
+
+// variableName is the form a placeholder name must take
+var variableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// shellSafe matches values that can be substituted into a shell command without quoting
+var shellSafe = regexp.MustCompile(`^[A-Za-z0-9_./:=@%+,-]+$`)
+
+// Variables returns the distinct variable names a command references, sorted
+func Variables(command string) ([]string, error) {
+	seen := map[string]bool{}
+	variables := []string{}
+	for _, match := range variablePattern.FindAllStringSubmatch(command, -1) {
+		name := match[1]
+		if !variableName.MatchString(name) {
+			return nil, fmt.Errorf("invalid variable %q", match[0])
+		}
+		if !seen[name] {
+			seen[name] = true
+			variables = append(variables, name)
+		}
+	}
+	sort.Strings(variables)
+	return variables, nil
+}
+
+// shellQuote single-quotes a value unless it only holds characters the shell treats literally
+func shellQuote(value string) string {
+	if shellSafe.MatchString(value) {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// Render substitutes variables into a command, shell-quoting values where needed.
+// Every variable the command references must be provided.
+func Render(command string, values map[string]string) (string, error) {
+	variables, err := Variables(command)
+	if err != nil {
+		return "", err
+	}
+	var missing []string
+	for _, name := range variables {
+		if _, ok := values[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("missing values for variables: %s", strings.Join(missing, ", "))
+	}
+	return variablePattern.ReplaceAllStringFunc(command, func(placeholder string) string {
+		name := variablePattern.FindStringSubmatch(placeholder)[1]
+		return shellQuote(values[name])
+	}), nil
+}
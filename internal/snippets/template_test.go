@@ -0,0 +1,37 @@
+package snippets
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVariables(t *testing.T) {
+	got, err := Variables("kubectl -n {{namespace}} logs {{ pod }} -c {{container}} --tail {{ lines }} # {{pod}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"container", "lines", "namespace", "pod"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if _, err := Variables("echo {{not valid}}"); err == nil {
+		t.Error("expected an error for an invalid variable name")
+	}
+}
+
+func TestRender(t *testing.T) {
+	got, err := Render("curl -s {{url}} -H {{header}} # {{pod}}", map[string]string{
+		"url":    "http://localhost:8080/healthz",
+		"header": "X-Note: it's ok",
+		"pod":    "web-0",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `curl -s http://localhost:8080/healthz -H 'X-Note: it'\''s ok' # web-0`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	if _, err := Render("ls {{dir}} {{pattern}}", map[string]string{"dir": "/tmp"}); err == nil || err.Error() != "missing values for variables: pattern" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,154 @@
+package sourcelinks
+
+import (
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/Facets-cloud/kube-dash/internal/config"
+
+	"sigs.k8s.io/yaml"
+)
+
+// originAnnotation is set by kustomize (buildMetadata: [originAnnotations]) to the file a resource was built from
+const originAnnotation = "config.kubernetes.io/origin"
+
+// Source tools
+const (
+	ToolKustomize = "kustomize"
+	ToolArgoCD    = "argocd"
+	ToolFlux      = "flux"
+)
+
+// Source is where a resource is defined in Git, with a browse URL when the repo can be linked to
+type Source struct {
+	Tool     string `json:"tool"`
+	RepoURL  string `json:"repoURL,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Revision string `json:"revision,omitempty"`
+	URL      string `json:"url,omitempty"` // browse URL of the file, or of the directory for GitOps applications
+}
+
+// origin is the value of the kustomize origin annotation
+type origin struct {
+	Path         string `json:"path"`
+	Repo         string `json:"repo"`
+	Ref          string `json:"ref"`
+	ConfiguredIn string `json:"configuredIn"` // set instead of path for generated resources
+}
+
+// OriginSource parses the kustomize origin annotation. Sources without a repo are local to the
+// kustomization and have no URL.
+func OriginSource(annotations map[string]string) *Source {
+	value := annotations[originAnnotation]
+	if value == "" {
+		return nil
+	}
+	var o origin
+	if err := yaml.Unmarshal([]byte(value), &o); err != nil {
+		return nil
+	}
+	if o.Path == "" {
+		o.Path = o.ConfiguredIn
+	}
+	if o.Path == "" && o.Repo == "" {
+		return nil
+	}
+	return &Source{Tool: ToolKustomize, RepoURL: o.Repo, Path: o.Path, Revision: o.Ref}
+}
+
+// browser builds browse URLs for Git repos
+type browser struct {
+	mappings    [][2]string
+	gitlabHosts map[string]bool
+	defaultRef  string
+}
+
+func newBrowser(cfg *config.SourceLinksConfig) *browser {
+	b := &browser{gitlabHosts: map[string]bool{"gitlab.com": true}, defaultRef: cfg.DefaultRef}
+	for _, mapping := range cfg.RepoMappings {
+		if from, to, ok := strings.Cut(mapping, "="); ok && from != "" && to != "" {
+			b.mappings = append(b.mappings, [2]string{from, to})
+		}
+	}
+	for _, host := range cfg.GitLabHosts {
+		b.gitlabHosts[strings.ToLower(host)] = true
+	}
+	return b
+}
+
+// repoBase returns the web URL of a repo from its clone URL: https, ssh or scp-like git@host:org/repo
+func (b *browser) repoBase(repo string) (string, bool) {
+	repo = strings.TrimSuffix(strings.TrimSpace(repo), "/")
+	for _, mapping := range b.mappings {
+		if strings.HasPrefix(repo, mapping[0]) {
+			return strings.TrimSuffix(mapping[1]+strings.TrimPrefix(repo, mapping[0]), ".git"), true
+		}
+	}
+	if !strings.Contains(repo, "://") {
+		// scp-like syntax, e.g. git@github.com:org/repo.git
+		userHost, repoPath, ok := strings.Cut(repo, ":")
+		if !ok {
+			return "", false
+		}
+		_, host, found := strings.Cut(userHost, "@")
+		if !found {
+			host = userHost
+		}
+		repo = "https://" + host + "/" + repoPath
+	}
+	u, err := url.Parse(repo)
+	if err != nil || u.Hostname() == "" {
+		return "", false
+	}
+	switch u.Scheme {
+	case "http", "https", "ssh", "git":
+	default:
+		return "", false
+	}
+	repoPath := strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+	if repoPath == "" {
+		return "", false
+	}
+	return "https://" + u.Hostname() + "/" + repoPath, true
+}
+
+// browseURL links to a file, or a directory when dir is set, at a revision of a repo
+func (b *browser) browseURL(repo, filePath, revision string, dir bool) string {
+	base, ok := b.repoBase(repo)
+	if !ok {
+		return ""
+	}
+	if revision == "" {
+		revision = b.defaultRef
+	}
+	filePath = strings.Trim(path.Clean("/"+filePath), "/")
+
+	u, _ := url.Parse(base)
+	host := strings.ToLower(u.Hostname())
+	switch {
+	case host == "bitbucket.org":
+		return strings.TrimSuffix(base+"/src/"+revision+"/"+filePath, "/")
+	case b.gitlabHosts[host]:
+		base += "/-"
+	}
+	kind := "blob"
+	if dir || filePath == "" {
+		kind = "tree"
+	}
+	return strings.TrimSuffix(base+"/"+kind+"/"+revision+"/"+filePath, "/")
+}
+
+// fluxRevision extracts the commit from a Flux revision such as main@sha1:abc123 or main/abc123
+func fluxRevision(revision string) string {
+	if _, digest, ok := strings.Cut(revision, "@"); ok {
+		if _, commit, ok := strings.Cut(digest, ":"); ok {
+			return commit
+		}
+		return digest
+	}
+	if i := strings.LastIndex(revision, "/"); i >= 0 {
+		return revision[i+1:]
+	}
+	return revision
+}
@@ -0,0 +1,86 @@
+package sourcelinks
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Facets-cloud/kube-dash/internal/config"
+)
+
+func TestBrowseURL(t *testing.T) {
+	b := newBrowser(&config.SourceLinksConfig{
+		RepoMappings: []string{"ssh://git@git.internal:7999/=https://git.internal/scm/"},
+		GitLabHosts:  []string{"gitlab.acme.io"},
+		DefaultRef:   "HEAD",
+	})
+	tests := []struct {
+		repo, path, revision string
+		dir                  bool
+		want                 string
+	}{
+		{"https://github.com/acme/infra.git", "apps/web/deployment.yaml", "v1.2.0", false, "https://github.com/acme/infra/blob/v1.2.0/apps/web/deployment.yaml"},
+		{"git@github.com:acme/infra.git", "./apps/web", "abc123", true, "https://github.com/acme/infra/tree/abc123/apps/web"},
+		{"https://gitlab.com/acme/infra", "base/svc.yaml", "", false, "https://gitlab.com/acme/infra/-/blob/HEAD/base/svc.yaml"},
+		{"ssh://git@gitlab.acme.io/platform/infra.git", "", "main", true, "https://gitlab.acme.io/platform/infra/-/tree/main"},
+		{"ssh://git@git.internal:7999/ops/infra.git", "k8s", "main", true, "https://git.internal/scm/ops/infra/tree/main/k8s"},
+		{"https://bitbucket.org/acme/infra", "k8s/app.yaml", "main", false, "https://bitbucket.org/acme/infra/src/main/k8s/app.yaml"},
+		{"oci://registry.acme.io/charts", "web", "1.0.0", true, ""},
+		{"not a repo", "", "", false, ""},
+	}
+	for _, tt := range tests {
+		if got := b.browseURL(tt.repo, tt.path, tt.revision, tt.dir); got != tt.want {
+			t.Errorf("browseURL(%q, %q) = %q, want %q", tt.repo, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestOriginSource(t *testing.T) {
+	source := OriginSource(map[string]string{originAnnotation: "path: apps/web/deployment.yaml\nrepo: https://github.com/acme/infra\nref: v1.2.0\n"})
+	if source == nil || source.Tool != ToolKustomize || source.RepoURL != "https://github.com/acme/infra" ||
+		source.Path != "apps/web/deployment.yaml" || source.Revision != "v1.2.0" {
+		t.Errorf("origin source = %+v", source)
+	}
+	generated := OriginSource(map[string]string{originAnnotation: "configuredIn: kustomization.yaml\nconfiguredBy:\n  kind: ConfigMapGenerator\n"})
+	if generated == nil || generated.Path != "kustomization.yaml" || generated.RepoURL != "" {
+		t.Errorf("generated source = %+v", generated)
+	}
+	if OriginSource(nil) != nil || OriginSource(map[string]string{originAnnotation: "{"}) != nil {
+		t.Error("expected no source without a readable origin annotation")
+	}
+}
+
+func TestFluxRevision(t *testing.T) {
+	for revision, want := range map[string]string{
+		"main@sha1:abc123": "abc123",
+		"main/abc123":      "abc123",
+		"abc123":           "abc123",
+	} {
+		if got := fluxRevision(revision); got != want {
+			t.Errorf("fluxRevision(%q) = %q, want %q", revision, got, want)
+		}
+	}
+}
+
+func TestWithSource(t *testing.T) {
+	source := &Source{Tool: ToolArgoCD, URL: "https://github.com/acme/infra/tree/abc/apps"}
+	raw, err := WithSource([]byte(`{"kind":"Deployment"}`), source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out struct {
+		Kind   string  `json:"kind"`
+		Source *Source `json:"source"`
+	}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		t.Fatalf("invalid JSON %s: %v", raw, err)
+	}
+	if out.Kind != "Deployment" || out.Source == nil || out.Source.URL != source.URL {
+		t.Errorf("got %s", raw)
+	}
+	if raw, _ := WithSource([]byte(`{}`), source); !json.Valid(raw) {
+		t.Errorf("invalid JSON for an empty object: %s", raw)
+	}
+	if raw, _ := WithSource([]byte(`[1]`), source); string(raw) != `[1]` {
+		t.Error("arrays should be returned unchanged")
+	}
+}
@@ -0,0 +1,257 @@
+package sourcelinks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/transformers"
+	"github.com/Facets-cloud/kube-dash/internal/config"
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const contextKey = "sourceLinks"
+
+// cacheTTL is how long a resolved GitOps application source is reused
+const cacheTTL = time.Minute
+
+// lookupTimeout bounds the GitOps lookups so a slow API server never delays a detail response
+const lookupTimeout = 800 * time.Millisecond
+
+// gvr is a resource with the API versions to try, newest first
+type gvr struct {
+	group    string
+	resource string
+	versions []string
+}
+
+var (
+	argoApplications    = gvr{group: "argoproj.io", resource: "applications", versions: []string{"v1alpha1"}}
+	fluxKustomizations  = gvr{group: "kustomize.toolkit.fluxcd.io", resource: "kustomizations", versions: []string{"v1", "v1beta2"}}
+	fluxGitRepositories = gvr{group: "source.toolkit.fluxcd.io", resource: "gitrepositories", versions: []string{"v1", "v1beta2"}}
+)
+
+type cachedSource struct {
+	source  *Source
+	expires time.Time
+}
+
+// Resolver resolves where resources are defined in Git: from the kustomize origin annotation, or
+// from the Argo CD Application or Flux Kustomization that applied them
+type Resolver struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	browser       *browser
+	argoNamespace string
+	logger        *logger.Logger
+
+	mu    sync.Mutex
+	cache map[string]cachedSource
+}
+
+// NewResolver creates a source link resolver
+func NewResolver(cfg *config.SourceLinksConfig, store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, log *logger.Logger) *Resolver {
+	return &Resolver{
+		store:         store,
+		clientFactory: clientFactory,
+		browser:       newBrowser(cfg),
+		argoNamespace: cfg.ArgoCDNamespace,
+		logger:        log,
+		cache:         make(map[string]cachedSource),
+	}
+}
+
+// Middleware makes the resolver available to response marshaling, which adds the source to detail responses
+func Middleware(r *Resolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(contextKey, r)
+		c.Next()
+	}
+}
+
+// FromContext returns the resolver installed by Middleware
+func FromContext(c *gin.Context) (*Resolver, bool) {
+	value, ok := c.Get(contextKey)
+	if !ok {
+		return nil, false
+	}
+	r, ok := value.(*Resolver)
+	return r, ok
+}
+
+// Resolve returns the Git source of an object in the request's cluster, or nil if it has none
+func (r *Resolver) Resolve(c *gin.Context, obj metav1.Object) *Source {
+	if source := OriginSource(obj.GetAnnotations()); source != nil {
+		if source.RepoURL != "" {
+			source.URL = r.browser.browseURL(source.RepoURL, source.Path, source.Revision, false)
+		}
+		return source
+	}
+
+	owner := transformers.GitOpsOwnerFor(metav1.ObjectMeta{Labels: obj.GetLabels(), Annotations: obj.GetAnnotations()})
+	if owner == nil || (owner.Kind != "Application" && owner.Kind != "Kustomization") {
+		return nil
+	}
+	namespace := owner.Namespace
+	if namespace == "" && owner.Kind == "Application" {
+		namespace = r.argoNamespace
+	}
+	key := strings.Join([]string{c.Query("config"), c.Query("cluster"), owner.Kind, namespace, owner.Name}, "|")
+
+	r.mu.Lock()
+	cached, ok := r.cache[key]
+	r.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return copySource(cached.source)
+	}
+
+	client, err := r.dynamicClient(c)
+	if err != nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), lookupTimeout)
+	defer cancel()
+
+	var source *Source
+	if owner.Kind == "Application" {
+		source, err = r.argoSource(ctx, client, namespace, owner.Name)
+	} else {
+		source, err = r.fluxSource(ctx, client, namespace, owner.Name)
+	}
+	if err != nil {
+		r.logger.WithError(err).WithField("application", namespace+"/"+owner.Name).Debug("Failed to resolve GitOps source")
+		return nil
+	}
+
+	r.mu.Lock()
+	r.cache[key] = cachedSource{source: source, expires: time.Now().Add(cacheTTL)}
+	r.mu.Unlock()
+	return copySource(source)
+}
+
+func copySource(source *Source) *Source {
+	if source == nil {
+		return nil
+	}
+	copied := *source
+	return &copied
+}
+
+func (r *Resolver) dynamicClient(c *gin.Context) (dynamic.Interface, error) {
+	configID := c.Query("config")
+	if configID == "" {
+		return nil, fmt.Errorf("config parameter is required")
+	}
+	config, err := r.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, fmt.Errorf("config not found: %w", err)
+	}
+	return r.clientFactory.GetDynamicClientForConfig(config, c.Query("cluster"))
+}
+
+// get fetches an object using the first API version the cluster serves
+func get(ctx context.Context, client dynamic.Interface, resource gvr, namespace, name string) (*unstructured.Unstructured, error) {
+	var lastErr error
+	for _, version := range resource.versions {
+		gvr := schema.GroupVersionResource{Group: resource.group, Version: version, Resource: resource.resource}
+		obj, err := client.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err == nil {
+			return obj, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// argoSource reads the source of an Argo CD Application, linking to the revision it last synced.
+// Multi-source applications and Helm chart sources have no single directory to link to.
+func (r *Resolver) argoSource(ctx context.Context, client dynamic.Interface, namespace, name string) (*Source, error) {
+	app, err := get(ctx, client, argoApplications, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	repo, _, _ := unstructured.NestedString(app.Object, "spec", "source", "repoURL")
+	if repo == "" {
+		return nil, nil
+	}
+	source := &Source{Tool: ToolArgoCD, RepoURL: repo}
+	source.Revision, _, _ = unstructured.NestedString(app.Object, "status", "sync", "revision")
+	if source.Revision == "" {
+		source.Revision, _, _ = unstructured.NestedString(app.Object, "spec", "source", "targetRevision")
+	}
+	if chart, _, _ := unstructured.NestedString(app.Object, "spec", "source", "chart"); chart != "" {
+		source.Path = chart
+		return source, nil
+	}
+	source.Path, _, _ = unstructured.NestedString(app.Object, "spec", "source", "path")
+	source.URL = r.browser.browseURL(repo, source.Path, source.Revision, true)
+	return source, nil
+}
+
+// fluxSource reads the source of a Flux Kustomization from its GitRepository, linking to the
+// revision it last applied
+func (r *Resolver) fluxSource(ctx context.Context, client dynamic.Interface, namespace, name string) (*Source, error) {
+	ks, err := get(ctx, client, fluxKustomizations, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	if kind, _, _ := unstructured.NestedString(ks.Object, "spec", "sourceRef", "kind"); kind != "GitRepository" {
+		return nil, nil
+	}
+	repoName, _, _ := unstructured.NestedString(ks.Object, "spec", "sourceRef", "name")
+	repoNamespace, _, _ := unstructured.NestedString(ks.Object, "spec", "sourceRef", "namespace")
+	if repoNamespace == "" {
+		repoNamespace = namespace
+	}
+	gitRepo, err := get(ctx, client, fluxGitRepositories, repoNamespace, repoName)
+	if err != nil {
+		return nil, err
+	}
+	repo, _, _ := unstructured.NestedString(gitRepo.Object, "spec", "url")
+	if repo == "" {
+		return nil, nil
+	}
+
+	source := &Source{Tool: ToolFlux, RepoURL: repo}
+	source.Path, _, _ = unstructured.NestedString(ks.Object, "spec", "path")
+	if applied, _, _ := unstructured.NestedString(ks.Object, "status", "lastAppliedRevision"); applied != "" {
+		source.Revision = fluxRevision(applied)
+	} else {
+		source.Revision, _, _ = unstructured.NestedString(gitRepo.Object, "spec", "ref", "branch")
+	}
+	source.URL = r.browser.browseURL(repo, source.Path, source.Revision, true)
+	return source, nil
+}
+
+// WithSource adds a source field to a marshaled JSON object
+func WithSource(raw []byte, source *Source) ([]byte, error) {
+	trimmed := bytes.TrimSpace(raw)
+	if source == nil || len(trimmed) < 2 || trimmed[0] != '{' || trimmed[len(trimmed)-1] != '}' {
+		return raw, nil
+	}
+	encoded, err := json.Marshal(source)
+	if err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	out.Write(trimmed[:len(trimmed)-1])
+	if len(bytes.TrimSpace(trimmed[1:len(trimmed)-1])) > 0 {
+		out.WriteByte(',')
+	}
+	out.WriteString(`"source":`)
+	out.Write(encoded)
+	out.WriteByte('}')
+	return out.Bytes(), nil
+}
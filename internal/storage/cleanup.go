@@ -0,0 +1,184 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// ClusterKey identifies a cluster of a kubeconfig in the maps and documents of subsystems that
+// keep state per cluster
+func ClusterKey(configID, cluster string) string {
+	return configID + "|" + cluster
+}
+
+// Removal describes a removed kubeconfig, or one cluster of it when Cluster is set
+type Removal struct {
+	ConfigID string
+	Cluster  string      // empty when the whole kubeconfig was removed
+	Contexts []string    // contexts that pointed at the removed clusters
+	Config   *api.Config // the kubeconfig as it was before the removal
+}
+
+// Matches reports whether something bound to a config and cluster belongs to what was removed.
+// The cluster may also name one of the removed contexts, as the cluster parameter does for some
+// subsystems.
+func (r Removal) Matches(configID, cluster string) bool {
+	if configID != r.ConfigID {
+		return false
+	}
+	if r.Cluster == "" || cluster == r.Cluster {
+		return true
+	}
+	for _, context := range r.Contexts {
+		if cluster == context {
+			return true
+		}
+	}
+	return false
+}
+
+// Clusters returns the removed cluster names
+func (r Removal) Clusters() []string {
+	if r.Cluster != "" {
+		return []string{r.Cluster}
+	}
+	var clusters []string
+	if r.Config != nil {
+		for name := range r.Config.Clusters {
+			clusters = append(clusters, name)
+		}
+	}
+	sort.Strings(clusters)
+	return clusters
+}
+
+// CleanupHook releases what a subsystem holds for a removed kubeconfig or cluster and returns
+// how many items it released
+type CleanupHook func(removal Removal) (int, error)
+
+// CleanupResult is what one subsystem released for a removal
+type CleanupResult struct {
+	Subsystem string `json:"subsystem"`
+	Removed   int    `json:"removed"`
+	Error     string `json:"error,omitempty"`
+}
+
+// RemovalReport confirms a removal with what every subsystem cleaned up
+type RemovalReport struct {
+	ConfigID string          `json:"configId"`
+	Cluster  string          `json:"cluster,omitempty"`
+	Cleanup  []CleanupResult `json:"cleanup"`
+}
+
+type cleanupHook struct {
+	subsystem string
+	hook      CleanupHook
+}
+
+// RegisterCleanupHook registers a subsystem to clean up after removed kubeconfigs and clusters.
+// Hooks run in registration order, outside the store lock.
+func (s *KubeConfigStore) RegisterCleanupHook(subsystem string, hook CleanupHook) {
+	s.hooksMu.Lock()
+	defer s.hooksMu.Unlock()
+	s.hooks = append(s.hooks, cleanupHook{subsystem: subsystem, hook: hook})
+}
+
+// cleanup runs every hook for a removal; a failing hook does not stop the others
+func (s *KubeConfigStore) cleanup(removal Removal) *RemovalReport {
+	s.hooksMu.Lock()
+	hooks := append([]cleanupHook(nil), s.hooks...)
+	s.hooksMu.Unlock()
+
+	report := &RemovalReport{ConfigID: removal.ConfigID, Cluster: removal.Cluster, Cleanup: []CleanupResult{}}
+	for _, h := range hooks {
+		removed, err := h.hook(removal)
+		result := CleanupResult{Subsystem: h.subsystem, Removed: removed}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		report.Cleanup = append(report.Cleanup, result)
+	}
+	return report
+}
+
+// contextsOf returns the contexts of a kubeconfig that point at a cluster, or all contexts when
+// cluster is empty
+func contextsOf(config *api.Config, cluster string) []string {
+	var contexts []string
+	if config == nil {
+		return contexts
+	}
+	for name, context := range config.Contexts {
+		if cluster == "" || (context != nil && context.Cluster == cluster) {
+			contexts = append(contexts, name)
+		}
+	}
+	sort.Strings(contexts)
+	return contexts
+}
+
+// RemoveKubeConfig deletes a kubeconfig and cleans up what subsystems hold for it
+func (s *KubeConfigStore) RemoveKubeConfig(id string) (*RemovalReport, error) {
+	config, _ := s.GetKubeConfig(id)
+	if err := s.DeleteKubeConfig(id); err != nil {
+		return nil, err
+	}
+	return s.cleanup(Removal{ConfigID: id, Contexts: contextsOf(config, ""), Config: config}), nil
+}
+
+// withoutCluster returns a copy of a kubeconfig without a cluster, the contexts pointing at it
+// and the credentials no remaining context uses
+func withoutCluster(config *api.Config, cluster string) (*api.Config, error) {
+	if _, ok := config.Clusters[cluster]; !ok {
+		return nil, fmt.Errorf("cluster not found: %s", cluster)
+	}
+	if len(config.Clusters) == 1 {
+		return nil, fmt.Errorf("cluster %s is the only cluster of the kubeconfig; remove the kubeconfig instead", cluster)
+	}
+
+	updated := config.DeepCopy()
+	delete(updated.Clusters, cluster)
+	for _, name := range contextsOf(config, cluster) {
+		delete(updated.Contexts, name)
+	}
+	used := map[string]bool{}
+	for _, context := range updated.Contexts {
+		used[context.AuthInfo] = true
+	}
+	for name := range updated.AuthInfos {
+		if !used[name] {
+			delete(updated.AuthInfos, name)
+		}
+	}
+	if _, ok := updated.Contexts[updated.CurrentContext]; !ok {
+		remaining := contextsOf(updated, "")
+		updated.CurrentContext = ""
+		if len(remaining) > 0 {
+			updated.CurrentContext = remaining[0]
+		}
+	}
+	return updated, nil
+}
+
+// RemoveCluster removes one cluster from a kubeconfig, with its contexts and the credentials only
+// they used, and cleans up what subsystems hold for it
+func (s *KubeConfigStore) RemoveCluster(id, cluster string) (*RemovalReport, error) {
+	config, err := s.GetKubeConfig(id)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := s.GetKubeConfigMetadata(id)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := withoutCluster(config, cluster)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.UpdateKubeConfig(id, updated, metadata.Name); err != nil {
+		return nil, err
+	}
+	return s.cleanup(Removal{ConfigID: id, Cluster: cluster, Contexts: contextsOf(config, cluster), Config: config}), nil
+}
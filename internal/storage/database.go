@@ -47,6 +47,17 @@ type DatabaseStorage interface {
 	GetCache(key string) ([]byte, time.Time, error)
 	DeleteExpiredCache(cutoff time.Time) error
 	ClearCache() error
+
+	// Event history storage operations; events are upserted by ID as their counts change
+	StoreEvents(events []*types.StoredEvent) error
+	QueryEvents(filter types.EventFilter) ([]*types.StoredEvent, int, error)
+	DeleteExpiredEvents(cutoff time.Time) error
+
+	// Document storage operations for JSON documents grouped into collections
+	PutDocument(collection, id string, data []byte) error
+	GetDocument(collection, id string) ([]byte, error)
+	ListDocuments(collection string) (map[string][]byte, error)
+	DeleteDocument(collection, id string) error
 }
 
 // StorageType represents the type of storage backend
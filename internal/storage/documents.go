@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrDocumentNotFound is returned when a document does not exist
+var ErrDocumentNotFound = errors.New("document not found")
+
+// DocumentStore persists JSON documents grouped into collections, using the
+// database backend when available and an in-memory map otherwise
+type DocumentStore struct {
+	db DatabaseStorage
+
+	mu     sync.RWMutex
+	memory map[string]map[string][]byte
+}
+
+// NewDocumentStore creates a document store; db may be nil for in-memory storage
+func NewDocumentStore(db DatabaseStorage) *DocumentStore {
+	return &DocumentStore{
+		db:     db,
+		memory: make(map[string]map[string][]byte),
+	}
+}
+
+// Put marshals v to JSON and stores it under collection/id
+func (d *DocumentStore) Put(collection, id string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal document: %w", err)
+	}
+	if d.db != nil {
+		return d.db.PutDocument(collection, id, data)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.memory[collection] == nil {
+		d.memory[collection] = make(map[string][]byte)
+	}
+	d.memory[collection][id] = data
+	return nil
+}
+
+// Get loads collection/id into v, returning ErrDocumentNotFound if it does not exist
+func (d *DocumentStore) Get(collection, id string, v interface{}) error {
+	var data []byte
+	if d.db != nil {
+		var err error
+		if data, err = d.db.GetDocument(collection, id); err != nil {
+			return err
+		}
+	} else {
+		d.mu.RLock()
+		stored, ok := d.memory[collection][id]
+		d.mu.RUnlock()
+		if !ok {
+			return ErrDocumentNotFound
+		}
+		data = stored
+	}
+	return json.Unmarshal(data, v)
+}
+
+// List returns the raw JSON of every document in a collection keyed by ID
+func (d *DocumentStore) List(collection string) (map[string][]byte, error) {
+	if d.db != nil {
+		return d.db.ListDocuments(collection)
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	documents := make(map[string][]byte, len(d.memory[collection]))
+	for id, data := range d.memory[collection] {
+		documents[id] = data
+	}
+	return documents, nil
+}
+
+// Delete removes collection/id; deleting a missing document is not an error
+func (d *DocumentStore) Delete(collection, id string) error {
+	if d.db != nil {
+		return d.db.DeleteDocument(collection, id)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.memory[collection], id)
+	return nil
+}
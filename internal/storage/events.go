@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/Facets-cloud/kube-dash/internal/types"
+)
+
+// eventColumns lists the k8s_events columns in the order scanEvents reads them
+const eventColumns = "id, config_id, cluster, namespace, name, event_type, reason, message, involved_kind, involved_name, source, event_count, first_seen, last_seen"
+
+// eventWhereClause builds the WHERE clause for an event filter; placeholder renders the nth bind parameter
+func eventWhereClause(filter types.EventFilter, placeholder func(n int) string) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, placeholder(len(args))))
+	}
+
+	if filter.ConfigID != "" {
+		add("config_id = %s", filter.ConfigID)
+	}
+	if filter.Cluster != "" {
+		add("cluster = %s", filter.Cluster)
+	}
+	if filter.Namespace != "" {
+		add("namespace = %s", filter.Namespace)
+	}
+	if filter.InvolvedKind != "" {
+		add("involved_kind = %s", filter.InvolvedKind)
+	}
+	if filter.InvolvedName != "" {
+		add("involved_name = %s", filter.InvolvedName)
+	}
+	if filter.Reason != "" {
+		add("reason = %s", filter.Reason)
+	}
+	if filter.Type != "" {
+		add("event_type = %s", filter.Type)
+	}
+	if filter.Since != nil {
+		add("last_seen >= %s", *filter.Since)
+	}
+	if filter.Until != nil {
+		add("last_seen <= %s", *filter.Until)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// scanEvents reads event rows selected with eventColumns
+func scanEvents(rows *sql.Rows) ([]*types.StoredEvent, error) {
+	defer rows.Close()
+
+	var events []*types.StoredEvent
+	for rows.Next() {
+		var e types.StoredEvent
+		if err := rows.Scan(&e.ID, &e.ConfigID, &e.Cluster, &e.Namespace, &e.Name, &e.Type, &e.Reason, &e.Message,
+			&e.InvolvedKind, &e.InvolvedName, &e.Source, &e.Count, &e.FirstSeen, &e.LastSeen); err != nil {
+			return nil, fmt.Errorf("failed to scan event row: %w", err)
+		}
+		events = append(events, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating event rows: %w", err)
+	}
+	return events, nil
+}
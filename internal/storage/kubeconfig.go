@@ -18,6 +18,22 @@ type KubeConfig struct {
 	Clusters map[string]string `json:"clusters"`
 	Created  time.Time         `json:"created"`
 	Updated  time.Time         `json:"updated"`
+
+	// Session-only kubeconfigs live in memory, belong to one browser session and expire
+	SessionOnly bool       `json:"sessionOnly,omitempty"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	SessionID   string     `json:"-"`
+}
+
+// expired reports whether a session-only kubeconfig has passed its expiry
+func (k *KubeConfig) expired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
+
+// VisibleTo reports whether a session may see and use a kubeconfig: persistent kubeconfigs are
+// shared, session-only ones belong to their unexpired session
+func (k *KubeConfig) VisibleTo(sessionID string, now time.Time) bool {
+	return !k.SessionOnly || (sessionID != "" && k.SessionID == sessionID && !k.expired(now))
 }
 
 // ClusterStatus represents the status of a cluster
@@ -36,6 +52,9 @@ type KubeConfigStore struct {
 	metadata map[string]*KubeConfig
 	db       DatabaseStorage // persistent storage backend
 	useDB    bool            // whether to use persistent storage
+
+	hooksMu sync.Mutex
+	hooks   []cleanupHook // subsystems cleaning up after removed kubeconfigs and clusters
 }
 
 // NewKubeConfigStore creates a new kubeconfig store with in-memory storage
@@ -178,11 +197,110 @@ func (s *KubeConfigStore) AddKubeConfig(config *api.Config, name string) (string
 	return id, nil
 }
 
+// AddSessionKubeConfig adds a kubeconfig that is never persisted, is visible only to
+// the given session and is rejected once ttl has elapsed
+func (s *KubeConfigStore) AddSessionKubeConfig(config *api.Config, name, sessionID string, ttl time.Duration) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.validateConfig(config); err != nil {
+		return "", fmt.Errorf("invalid kubeconfig: %w", err)
+	}
+	if sessionID == "" {
+		return "", fmt.Errorf("a session is required for session-only kubeconfigs")
+	}
+
+	id := uuid.New().String()
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+
+	clusters := make(map[string]string)
+	for clusterName := range config.Clusters {
+		clusters[clusterName] = clusterName
+	}
+
+	s.configs[id] = config
+	s.metadata[id] = &KubeConfig{
+		ID:          id,
+		Name:        name,
+		Clusters:    clusters,
+		Created:     now,
+		Updated:     now,
+		SessionOnly: true,
+		ExpiresAt:   &expiresAt,
+		SessionID:   sessionID,
+	}
+
+	return id, nil
+}
+
+// ListSessionKubeConfigs returns the unexpired session-only kubeconfigs of a session
+func (s *KubeConfigStore) ListSessionKubeConfigs(sessionID string) []*KubeConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	var result []*KubeConfig
+	for _, metadata := range s.metadata {
+		if metadata.SessionOnly && metadata.SessionID == sessionID && !metadata.expired(now) {
+			result = append(result, metadata)
+		}
+	}
+	return result
+}
+
+// UsableBy reports whether a session may use a kubeconfig. Unknown IDs are reported usable so
+// that resolving them fails as it would for anyone.
+func (s *KubeConfigStore) UsableBy(id, sessionID string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	metadata, ok := s.metadata[id]
+	return !ok || metadata.VisibleTo(sessionID, time.Now())
+}
+
+// PurgeSessionKubeConfigs removes all session-only kubeconfigs of a session and returns their IDs
+func (s *KubeConfigStore) PurgeSessionKubeConfigs(sessionID string) []string {
+	return s.purgeSessionConfigs(func(k *KubeConfig) bool { return k.SessionID == sessionID })
+}
+
+// PurgeExpiredKubeConfigs removes expired session-only kubeconfigs and returns their IDs
+func (s *KubeConfigStore) PurgeExpiredKubeConfigs() []string {
+	now := time.Now()
+	return s.purgeSessionConfigs(func(k *KubeConfig) bool { return k.expired(now) })
+}
+
+func (s *KubeConfigStore) purgeSessionConfigs(match func(*KubeConfig) bool) []string {
+	s.mu.Lock()
+	var purged []string
+	var removals []Removal
+	for id, metadata := range s.metadata {
+		if metadata.SessionOnly && match(metadata) {
+			config := s.configs[id]
+			removals = append(removals, Removal{ConfigID: id, Contexts: contextsOf(config, ""), Config: config})
+			delete(s.configs, id)
+			delete(s.metadata, id)
+			purged = append(purged, id)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, removal := range removals {
+		s.cleanup(removal)
+	}
+	return purged
+}
+
 // GetKubeConfig retrieves a kubeconfig by ID
 func (s *KubeConfigStore) GetKubeConfig(id string) (*api.Config, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	// Expired session-only configs are unusable even before they are purged
+	if metadata, ok := s.metadata[id]; ok && metadata.expired(time.Now()) {
+		return nil, fmt.Errorf("kubeconfig not found: %s", id)
+	}
+
 	// Try memory cache first
 	config, exists := s.configs[id]
 	if exists {
@@ -298,6 +416,7 @@ func (s *KubeConfigStore) UpdateKubeConfig(id string, config *api.Config, name s
 	if !existsInMemory && !existsInDB {
 		return fmt.Errorf("kubeconfig not found: %s", id)
 	}
+	sessionOnly := s.metadata[id] != nil && s.metadata[id].SessionOnly
 
 	// Validate the new config
 	if err := s.validateConfig(config); err != nil {
@@ -312,8 +431,8 @@ func (s *KubeConfigStore) UpdateKubeConfig(id string, config *api.Config, name s
 
 	now := time.Now()
 
-	// Update in database if using persistent storage
-	if s.useDB && s.db != nil {
+	// Update in database if using persistent storage; session-only configs are never persisted
+	if s.useDB && s.db != nil && !sessionOnly {
 		if err := s.db.UpdateKubeConfig(id, name, config, clusters, now); err != nil {
 			return fmt.Errorf("failed to update kubeconfig in database: %w", err)
 		}
@@ -376,17 +495,22 @@ func (s *KubeConfigStore) validateConfig(config *api.Config) error {
 	return nil
 }
 
-// GetClustersResponse returns the response format expected by the frontend
-func (s *KubeConfigStore) GetClustersResponse() map[string]interface{} {
+// GetClustersResponse returns the response format expected by the frontend.
+// Session-only kubeconfigs are included only for the session that owns them.
+func (s *KubeConfigStore) GetClustersResponse(sessionID string) map[string]interface{} {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	now := time.Now()
 	kubeConfigs := make(map[string]interface{})
 	for id, metadata := range s.metadata {
 		config := s.configs[id]
 		if config == nil {
 			continue
 		}
+		if !metadata.VisibleTo(sessionID, now) {
+			continue
+		}
 
 		// Build detailed cluster information
 		clusters := make(map[string]interface{})
@@ -409,10 +533,15 @@ func (s *KubeConfigStore) GetClustersResponse() map[string]interface{} {
 			}
 		}
 
-		kubeConfigs[id] = map[string]interface{}{
+		entry := map[string]interface{}{
 			"name":     metadata.Name,
 			"clusters": clusters,
 		}
+		if metadata.SessionOnly {
+			entry["sessionOnly"] = true
+			entry["expiresAt"] = metadata.ExpiresAt
+		}
+		kubeConfigs[id] = entry
 	}
 
 	return map[string]interface{}{
@@ -123,6 +123,46 @@ func (p *PostgresStorage) Initialize() error {
 		return fmt.Errorf("failed to create cache_entries table: %w", err)
 	}
 
+	// Create documents table
+	createDocumentsTableSQL := `
+	CREATE TABLE IF NOT EXISTS documents (
+		collection TEXT NOT NULL,
+		id TEXT NOT NULL,
+		data_json JSONB NOT NULL,
+		created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+		updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+		PRIMARY KEY (collection, id)
+	);
+	`
+
+	if _, err := p.db.Exec(createDocumentsTableSQL); err != nil {
+		return fmt.Errorf("failed to create documents table: %w", err)
+	}
+
+	// Create k8s_events table for event history
+	createEventsTableSQL := `
+	CREATE TABLE IF NOT EXISTS k8s_events (
+		id TEXT PRIMARY KEY,
+		config_id TEXT NOT NULL,
+		cluster TEXT NOT NULL,
+		namespace TEXT NOT NULL,
+		name TEXT NOT NULL,
+		event_type TEXT NOT NULL,
+		reason TEXT NOT NULL,
+		message TEXT NOT NULL,
+		involved_kind TEXT NOT NULL,
+		involved_name TEXT NOT NULL,
+		source TEXT NOT NULL,
+		event_count INTEGER NOT NULL,
+		first_seen TIMESTAMP WITH TIME ZONE NOT NULL,
+		last_seen TIMESTAMP WITH TIME ZONE NOT NULL
+	);
+	`
+
+	if _, err := p.db.Exec(createEventsTableSQL); err != nil {
+		return fmt.Errorf("failed to create k8s_events table: %w", err)
+	}
+
 	// Create performance indexes for traces and spans
 	createTraceIndexesSQL := `
 	CREATE INDEX IF NOT EXISTS idx_traces_start_time ON traces(start_time);
@@ -133,6 +173,10 @@ func (p *PostgresStorage) Initialize() error {
 	CREATE INDEX IF NOT EXISTS idx_spans_service_name ON spans(service_name);
 	CREATE INDEX IF NOT EXISTS idx_spans_start_time ON spans(start_time);
 	CREATE INDEX IF NOT EXISTS idx_cache_expires_at ON cache_entries(expires_at);
+	CREATE INDEX IF NOT EXISTS idx_k8s_events_namespace ON k8s_events(config_id, cluster, namespace);
+	CREATE INDEX IF NOT EXISTS idx_k8s_events_involved_kind ON k8s_events(involved_kind, involved_name);
+	CREATE INDEX IF NOT EXISTS idx_k8s_events_reason ON k8s_events(reason);
+	CREATE INDEX IF NOT EXISTS idx_k8s_events_last_seen ON k8s_events(last_seen);
 	`
 
 	if _, err := p.db.Exec(createTraceIndexesSQL); err != nil {
@@ -355,6 +399,55 @@ func (p *PostgresStorage) DeleteExpiredTraces(cutoff time.Time) error {
 	return nil
 }
 
+// StoreEvents upserts events in a single transaction
+func (p *PostgresStorage) StoreEvents(events []*types.StoredEvent) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin event transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO k8s_events (` + eventColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	ON CONFLICT (id) DO UPDATE SET event_type = EXCLUDED.event_type, reason = EXCLUDED.reason, message = EXCLUDED.message,
+		event_count = EXCLUDED.event_count, first_seen = LEAST(k8s_events.first_seen, EXCLUDED.first_seen), last_seen = EXCLUDED.last_seen`
+	for _, e := range events {
+		if _, err := tx.Exec(query, e.ID, e.ConfigID, e.Cluster, e.Namespace, e.Name, e.Type, e.Reason, e.Message,
+			e.InvolvedKind, e.InvolvedName, e.Source, e.Count, e.FirstSeen, e.LastSeen); err != nil {
+			return fmt.Errorf("failed to store event: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// QueryEvents retrieves stored events matching the filter, most recently seen first
+func (p *PostgresStorage) QueryEvents(filter types.EventFilter) ([]*types.StoredEvent, int, error) {
+	whereClause, args := eventWhereClause(filter, func(n int) string { return fmt.Sprintf("$%d", n) })
+
+	var total int
+	if err := p.db.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM k8s_events %s", whereClause), args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count events: %w", err)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM k8s_events %s ORDER BY last_seen DESC LIMIT $%d OFFSET $%d", eventColumns, whereClause, len(args)+1, len(args)+2)
+	rows, err := p.db.Query(query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query events: %w", err)
+	}
+	events, err := scanEvents(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+// DeleteExpiredEvents removes events last seen before the cutoff time
+func (p *PostgresStorage) DeleteExpiredEvents(cutoff time.Time) error {
+	if _, err := p.db.Exec(`DELETE FROM k8s_events WHERE last_seen < $1`, cutoff); err != nil {
+		return fmt.Errorf("failed to delete expired events: %w", err)
+	}
+	return nil
+}
+
 // SetCache stores a cache entry in PostgreSQL
 func (p *PostgresStorage) SetCache(key string, data []byte, expiresAt time.Time) error {
 	now := time.Now()
@@ -403,6 +496,56 @@ func (p *PostgresStorage) ClearCache() error {
 	return nil
 }
 
+// PutDocument creates or replaces a document in PostgreSQL
+func (p *PostgresStorage) PutDocument(collection, id string, data []byte) error {
+	now := time.Now()
+	query := `INSERT INTO documents (collection, id, data_json, created_at, updated_at) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (collection, id) DO UPDATE SET data_json = $3, updated_at = $5`
+	if _, err := p.db.Exec(query, collection, id, string(data), now, now); err != nil {
+		return fmt.Errorf("failed to store document: %w", err)
+	}
+	return nil
+}
+
+// GetDocument retrieves a document from PostgreSQL
+func (p *PostgresStorage) GetDocument(collection, id string) ([]byte, error) {
+	query := `SELECT data_json FROM documents WHERE collection = $1 AND id = $2`
+	var dataJSON string
+	if err := p.db.QueryRow(query, collection, id).Scan(&dataJSON); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrDocumentNotFound
+		}
+		return nil, fmt.Errorf("failed to query document: %w", err)
+	}
+	return []byte(dataJSON), nil
+}
+
+// ListDocuments returns all documents in a collection keyed by ID
+func (p *PostgresStorage) ListDocuments(collection string) (map[string][]byte, error) {
+	rows, err := p.db.Query(`SELECT id, data_json FROM documents WHERE collection = $1`, collection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+	defer rows.Close()
+
+	documents := make(map[string][]byte)
+	for rows.Next() {
+		var id, dataJSON string
+		if err := rows.Scan(&id, &dataJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan document: %w", err)
+		}
+		documents[id] = []byte(dataJSON)
+	}
+	return documents, rows.Err()
+}
+
+// DeleteDocument removes a document from PostgreSQL
+func (p *PostgresStorage) DeleteDocument(collection, id string) error {
+	if _, err := p.db.Exec(`DELETE FROM documents WHERE collection = $1 AND id = $2`, collection, id); err != nil {
+		return fmt.Errorf("failed to delete document: %w", err)
+	}
+	return nil
+}
+
 // Close closes the PostgreSQL database connection
 func (p *PostgresStorage) Close() error {
 	if p.db != nil {
@@ -106,6 +106,46 @@ func (s *SQLiteStorage) Initialize() error {
 		return fmt.Errorf("failed to create cache_entries table: %w", err)
 	}
 
+	// Create the documents table
+	createDocumentsSQL := `
+	CREATE TABLE IF NOT EXISTS documents (
+		collection TEXT NOT NULL,
+		id TEXT NOT NULL,
+		data_json TEXT NOT NULL,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL,
+		PRIMARY KEY (collection, id)
+	);
+	`
+
+	if _, err := s.db.Exec(createDocumentsSQL); err != nil {
+		return fmt.Errorf("failed to create documents table: %w", err)
+	}
+
+	// Create the k8s_events table for event history
+	createEventsSQL := `
+	CREATE TABLE IF NOT EXISTS k8s_events (
+		id TEXT PRIMARY KEY,
+		config_id TEXT NOT NULL,
+		cluster TEXT NOT NULL,
+		namespace TEXT NOT NULL,
+		name TEXT NOT NULL,
+		event_type TEXT NOT NULL,
+		reason TEXT NOT NULL,
+		message TEXT NOT NULL,
+		involved_kind TEXT NOT NULL,
+		involved_name TEXT NOT NULL,
+		source TEXT NOT NULL,
+		event_count INTEGER NOT NULL,
+		first_seen DATETIME NOT NULL,
+		last_seen DATETIME NOT NULL
+	);
+	`
+
+	if _, err := s.db.Exec(createEventsSQL); err != nil {
+		return fmt.Errorf("failed to create k8s_events table: %w", err)
+	}
+
 	// Create indexes for better performance
 	createIndexesSQL := `
 	CREATE INDEX IF NOT EXISTS idx_traces_start_time ON traces(start_time);
@@ -115,6 +155,10 @@ func (s *SQLiteStorage) Initialize() error {
 	CREATE INDEX IF NOT EXISTS idx_spans_service_name ON spans(service_name);
 	CREATE INDEX IF NOT EXISTS idx_spans_start_time ON spans(start_time);
 	CREATE INDEX IF NOT EXISTS idx_cache_expires_at ON cache_entries(expires_at);
+	CREATE INDEX IF NOT EXISTS idx_k8s_events_namespace ON k8s_events(config_id, cluster, namespace);
+	CREATE INDEX IF NOT EXISTS idx_k8s_events_involved_kind ON k8s_events(involved_kind, involved_name);
+	CREATE INDEX IF NOT EXISTS idx_k8s_events_reason ON k8s_events(reason);
+	CREATE INDEX IF NOT EXISTS idx_k8s_events_last_seen ON k8s_events(last_seen);
 	`
 
 	if _, err := s.db.Exec(createIndexesSQL); err != nil {
@@ -317,6 +361,55 @@ func (s *SQLiteStorage) DeleteExpiredTraces(cutoff time.Time) error {
 	return nil
 }
 
+// StoreEvents upserts events in a single transaction
+func (s *SQLiteStorage) StoreEvents(events []*types.StoredEvent) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin event transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO k8s_events (` + eventColumns + `) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT (id) DO UPDATE SET event_type = excluded.event_type, reason = excluded.reason, message = excluded.message,
+		event_count = excluded.event_count, first_seen = MIN(k8s_events.first_seen, excluded.first_seen), last_seen = excluded.last_seen`
+	for _, e := range events {
+		if _, err := tx.Exec(query, e.ID, e.ConfigID, e.Cluster, e.Namespace, e.Name, e.Type, e.Reason, e.Message,
+			e.InvolvedKind, e.InvolvedName, e.Source, e.Count, e.FirstSeen, e.LastSeen); err != nil {
+			return fmt.Errorf("failed to store event: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// QueryEvents retrieves stored events matching the filter, most recently seen first
+func (s *SQLiteStorage) QueryEvents(filter types.EventFilter) ([]*types.StoredEvent, int, error) {
+	whereClause, args := eventWhereClause(filter, func(int) string { return "?" })
+
+	var total int
+	if err := s.db.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM k8s_events %s", whereClause), args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count events: %w", err)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM k8s_events %s ORDER BY last_seen DESC LIMIT ? OFFSET ?", eventColumns, whereClause)
+	rows, err := s.db.Query(query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query events: %w", err)
+	}
+	events, err := scanEvents(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+// DeleteExpiredEvents removes events last seen before the cutoff time
+func (s *SQLiteStorage) DeleteExpiredEvents(cutoff time.Time) error {
+	if _, err := s.db.Exec(`DELETE FROM k8s_events WHERE last_seen < ?`, cutoff); err != nil {
+		return fmt.Errorf("failed to delete expired events: %w", err)
+	}
+	return nil
+}
+
 // SetCache stores a cache entry in SQLite
 func (s *SQLiteStorage) SetCache(key string, data []byte, expiresAt time.Time) error {
 	now := time.Now()
@@ -365,6 +458,57 @@ func (s *SQLiteStorage) ClearCache() error {
 	return nil
 }
 
+// PutDocument creates or replaces a document in SQLite
+func (s *SQLiteStorage) PutDocument(collection, id string, data []byte) error {
+	now := time.Now()
+	query := `INSERT INTO documents (collection, id, data_json, created_at, updated_at) VALUES (?, ?, ?, ?, ?)
+	ON CONFLICT (collection, id) DO UPDATE SET data_json = excluded.data_json, updated_at = excluded.updated_at`
+	if _, err := s.db.Exec(query, collection, id, string(data), now, now); err != nil {
+		return fmt.Errorf("failed to store document: %w", err)
+	}
+	return nil
+}
+
+// GetDocument retrieves a document from SQLite
+func (s *SQLiteStorage) GetDocument(collection, id string) ([]byte, error) {
+	query := `SELECT data_json FROM documents WHERE collection = ? AND id = ?`
+	var dataJSON string
+	if err := s.db.QueryRow(query, collection, id).Scan(&dataJSON); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrDocumentNotFound
+		}
+		return nil, fmt.Errorf("failed to query document: %w", err)
+	}
+	return []byte(dataJSON), nil
+}
+
+// ListDocuments returns all documents in a collection keyed by ID
+func (s *SQLiteStorage) ListDocuments(collection string) (map[string][]byte, error) {
+	rows, err := s.db.Query(`SELECT id, data_json FROM documents WHERE collection = ?`, collection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+	defer rows.Close()
+
+	documents := make(map[string][]byte)
+	for rows.Next() {
+		var id, dataJSON string
+		if err := rows.Scan(&id, &dataJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan document: %w", err)
+		}
+		documents[id] = []byte(dataJSON)
+	}
+	return documents, rows.Err()
+}
+
+// DeleteDocument removes a document from SQLite
+func (s *SQLiteStorage) DeleteDocument(collection, id string) error {
+	if _, err := s.db.Exec(`DELETE FROM documents WHERE collection = ? AND id = ?`, collection, id); err != nil {
+		return fmt.Errorf("failed to delete document: %w", err)
+	}
+	return nil
+}
+
 // Close closes the SQLite database connection
 func (s *SQLiteStorage) Close() error {
 	if s.db != nil {
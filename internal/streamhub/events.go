@@ -0,0 +1,134 @@
+package streamhub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// maxBodyBytes bounds a plain response and a pending SSE event
+const maxBodyBytes = 32 << 20
+
+var errBodyTooLarge = errors.New("response exceeds the subscription size limit")
+
+// eventWriter receives a streaming endpoint's response and emits each SSE event as it completes.
+// Endpoints that answer with plain JSON, or fail before streaming, are recorded whole.
+type eventWriter struct {
+	ctx    context.Context
+	header http.Header
+	emit   func(event string, data []byte) bool
+
+	mu     sync.Mutex
+	status int
+	body   bytes.Buffer
+	failed bool
+}
+
+func newEventWriter(ctx context.Context, emit func(event string, data []byte) bool) *eventWriter {
+	return &eventWriter{ctx: ctx, header: http.Header{}, emit: emit}
+}
+
+func (w *eventWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *eventWriter) WriteHeader(status int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *eventWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+	if w.body.Len()+len(p) > maxBodyBytes {
+		w.failed = true
+		return 0, errBodyTooLarge
+	}
+	w.body.Write(p)
+	if !w.isStream() {
+		return len(p), nil
+	}
+	for {
+		raw := w.body.Bytes()
+		i := bytes.Index(raw, []byte("\n\n"))
+		if i < 0 {
+			break
+		}
+		event, data, ok := parseEvent(raw[:i])
+		w.body.Next(i + 2)
+		if ok && !w.emit(event, data) {
+			return 0, w.ctx.Err()
+		}
+	}
+	return len(p), nil
+}
+
+// Flush satisfies http.Flusher, which streaming handlers require
+func (w *eventWriter) Flush() {}
+
+func (w *eventWriter) isStream() bool {
+	return strings.HasPrefix(w.header.Get("Content-Type"), "text/event-stream")
+}
+
+// result returns the message for a response that ended without streaming, if any
+func (w *eventWriter) result(id string) (ServerMessage, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	switch {
+	case w.failed:
+		return ServerMessage{Type: TypeError, ID: id, Status: http.StatusRequestEntityTooLarge, Error: errBodyTooLarge.Error()}, true
+	case w.isStream():
+		return ServerMessage{}, false
+	case w.status == 0:
+		return ServerMessage{}, false
+	case w.status != http.StatusOK:
+		return ServerMessage{Type: TypeError, ID: id, Status: w.status, Error: responseError(w.body.Bytes())}, true
+	}
+	return ServerMessage{Type: TypeEvent, ID: id, Data: payload(bytes.TrimSpace(w.body.Bytes()))}, true
+}
+
+// parseEvent returns the name and joined data lines of an SSE event; comments such as
+// keep-alives and events without data are skipped
+func parseEvent(raw []byte) (string, []byte, bool) {
+	event := ""
+	var data [][]byte
+	for _, line := range bytes.Split(raw, []byte("\n")) {
+		switch {
+		case bytes.HasPrefix(line, []byte("event:")):
+			event = strings.TrimSpace(string(line[len("event:"):]))
+		case bytes.HasPrefix(line, []byte("data:")):
+			data = append(data, bytes.TrimPrefix(line[len("data:"):], []byte(" ")))
+		}
+	}
+	if data == nil {
+		return "", nil, false
+	}
+	return event, bytes.Join(data, []byte("\n")), true
+}
+
+// responseError returns the error message of a {"error": ...} body, or the body itself
+func responseError(body []byte) string {
+	var e struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(body, &e) == nil && e.Error != "" {
+		return e.Error
+	}
+	if len(body) > 200 {
+		body = body[:200]
+	}
+	return strings.TrimSpace(string(body))
+}
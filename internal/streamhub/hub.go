@@ -0,0 +1,224 @@
+// Package streamhub multiplexes the API's streaming endpoints over a single connection. Each
+// subscription is served by the existing SSE endpoint it names, so every list, event and metrics
+// stream is available without changes to its fetcher; the hub forwards the endpoint's events as
+// messages tagged with the subscription ID.
+package streamhub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+)
+
+// PathPrefix is the prefix of the endpoints a subscription may name
+const PathPrefix = "/api/v1/"
+
+// Client message types
+const (
+	TypeSubscribe   = "subscribe"
+	TypeUnsubscribe = "unsubscribe"
+	TypePing        = "ping"
+)
+
+// Server message types
+const (
+	TypeSubscribed = "subscribed"
+	TypeEvent      = "event"
+	TypeError      = "error"
+	TypeClosed     = "closed"
+	TypePong       = "pong"
+)
+
+// ClientMessage is a message from a multiplexing client
+type ClientMessage struct {
+	Type  string            `json:"type"`            // subscribe, unsubscribe or ping
+	ID    string            `json:"id,omitempty"`    // subscription ID chosen by the client
+	Path  string            `json:"path,omitempty"`  // streaming endpoint, e.g. /api/v1/pods
+	Query map[string]string `json:"query,omitempty"` // query parameters, e.g. config, cluster and namespace
+}
+
+// ServerMessage is a message to a multiplexing client
+type ServerMessage struct {
+	Type   string          `json:"type"`            // subscribed, event, error, closed or pong
+	ID     string          `json:"id,omitempty"`    // subscription the message belongs to
+	Event  string          `json:"event,omitempty"` // SSE event name; empty for data updates
+	Data   json.RawMessage `json:"data,omitempty"`
+	Status int             `json:"status,omitempty"` // HTTP status of a failed subscription
+	Error  string          `json:"error,omitempty"`
+}
+
+// Hub serves subscriptions from the streaming endpoints of a router
+type Hub struct {
+	router           http.Handler
+	excluded         map[string]bool
+	maxSubscriptions int
+	logger           *logger.Logger
+}
+
+// NewHub creates a hub serving subscriptions from router. maxSubscriptions caps the subscriptions
+// of one session, 0 for no limit; excluded paths, such as the multiplexing endpoint itself,
+// cannot be subscribed to.
+func NewHub(router http.Handler, maxSubscriptions int, log *logger.Logger, excluded ...string) *Hub {
+	hub := &Hub{router: router, excluded: map[string]bool{}, maxSubscriptions: maxSubscriptions, logger: log}
+	for _, p := range excluded {
+		hub.excluded[p] = true
+	}
+	return hub
+}
+
+// Session is the set of subscriptions of one client connection
+type Session struct {
+	hub      *Hub
+	original *http.Request
+	ctx      context.Context
+	cancel   context.CancelFunc
+	out      chan ServerMessage
+
+	mu   sync.Mutex
+	subs map[string]context.CancelFunc
+	wg   sync.WaitGroup
+}
+
+// NewSession starts a session on behalf of original, whose credentials every subscription is
+// requested with. The session ends when ctx does or Close is called.
+func (h *Hub) NewSession(ctx context.Context, original *http.Request) *Session {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Session{
+		hub:      h,
+		original: original,
+		ctx:      ctx,
+		cancel:   cancel,
+		out:      make(chan ServerMessage, 16),
+		subs:     make(map[string]context.CancelFunc),
+	}
+}
+
+// Messages returns the messages to send to the client; the channel is closed by Close
+func (s *Session) Messages() <-chan ServerMessage {
+	return s.out
+}
+
+// deliver queues a message for the client. Subscriptions block while the client is behind, which
+// holds back their endpoint the way a slow SSE connection would.
+func (s *Session) deliver(msg ServerMessage) bool {
+	select {
+	case s.out <- msg:
+		return true
+	case <-s.ctx.Done():
+		return false
+	}
+}
+
+// Handle processes a client message
+func (s *Session) Handle(msg ClientMessage) {
+	switch msg.Type {
+	case TypeSubscribe:
+		if err := s.subscribe(msg); err != nil {
+			s.deliver(ServerMessage{Type: TypeError, ID: msg.ID, Status: http.StatusBadRequest, Error: err.Error()})
+		}
+	case TypeUnsubscribe:
+		s.mu.Lock()
+		cancel, ok := s.subs[msg.ID]
+		s.mu.Unlock()
+		if !ok {
+			s.deliver(ServerMessage{Type: TypeError, ID: msg.ID, Status: http.StatusNotFound, Error: "no such subscription"})
+			return
+		}
+		cancel()
+	case TypePing:
+		s.deliver(ServerMessage{Type: TypePong})
+	default:
+		s.deliver(ServerMessage{Type: TypeError, ID: msg.ID, Status: http.StatusBadRequest, Error: fmt.Sprintf("unknown message type %q", msg.Type)})
+	}
+}
+
+// subscribe validates a subscription and starts serving it
+func (s *Session) subscribe(msg ClientMessage) error {
+	if msg.ID == "" {
+		return fmt.Errorf("subscription id is required")
+	}
+	target := path.Clean("/" + strings.TrimPrefix(msg.Path, "/"))
+	if !strings.HasPrefix(target, PathPrefix) || s.hub.excluded[target] {
+		return fmt.Errorf("path must be a streaming endpoint below %s", PathPrefix)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.subs[msg.ID]; ok {
+		return fmt.Errorf("subscription %q already exists", msg.ID)
+	}
+	if s.hub.maxSubscriptions > 0 && len(s.subs) >= s.hub.maxSubscriptions {
+		return fmt.Errorf("too many subscriptions (limit %d)", s.hub.maxSubscriptions)
+	}
+	query := url.Values{}
+	for k, v := range msg.Query {
+		query.Set(k, v)
+	}
+	ctx, cancel := context.WithCancel(s.ctx)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target+"?"+query.Encode(), nil)
+	if err != nil {
+		cancel()
+		return err
+	}
+	// Forward credentials and the session so the subscription sees what the caller would
+	req.Header = s.original.Header.Clone()
+	for _, h := range []string{"Upgrade", "Connection", "Sec-Websocket-Key", "Sec-Websocket-Version", "Sec-Websocket-Extensions", "Sec-Websocket-Protocol", "Accept-Encoding"} {
+		req.Header.Del(h)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.RemoteAddr = s.original.RemoteAddr
+
+	s.subs[msg.ID] = cancel
+	s.wg.Add(1)
+	go s.serve(ctx, cancel, msg.ID, req)
+	return nil
+}
+
+// serve runs a subscription's endpoint until it returns or the subscription is cancelled
+func (s *Session) serve(ctx context.Context, cancel context.CancelFunc, id string, req *http.Request) {
+	defer s.wg.Done()
+	defer func() {
+		cancel()
+		s.mu.Lock()
+		delete(s.subs, id)
+		s.mu.Unlock()
+		s.deliver(ServerMessage{Type: TypeClosed, ID: id})
+	}()
+
+	if !s.deliver(ServerMessage{Type: TypeSubscribed, ID: id}) {
+		return
+	}
+	w := newEventWriter(ctx, func(event string, data []byte) bool {
+		return s.deliver(ServerMessage{Type: TypeEvent, ID: id, Event: event, Data: payload(data)})
+	})
+	s.hub.router.ServeHTTP(w, req)
+	if ctx.Err() != nil {
+		return
+	}
+	if msg, ok := w.result(id); ok {
+		s.deliver(msg)
+	}
+}
+
+// Close ends every subscription of the session and closes its message channel
+func (s *Session) Close() {
+	s.cancel()
+	s.wg.Wait()
+	close(s.out)
+}
+
+// payload returns event data as JSON, quoting data that is not JSON
+func payload(data []byte) json.RawMessage {
+	if json.Valid(data) {
+		return json.RawMessage(data)
+	}
+	quoted, _ := json.Marshal(string(data))
+	return quoted
+}
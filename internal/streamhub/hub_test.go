@@ -0,0 +1,138 @@
+package streamhub
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+func testRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/pods", func(c *gin.Context) {
+		c.Header("Content-Type", "text/event-stream")
+		c.Writer.WriteString("data: [\"web-1\"]\n\n")
+		c.Writer.WriteString(": keep-alive\n\n")
+		c.Writer.WriteString("event: partial\ndata: {\"partial\":")
+		c.Writer.WriteString("true}\n\n")
+		c.Writer.Flush()
+		<-c.Request.Context().Done()
+	})
+	router.GET("/api/v1/whoami", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"authorization": c.GetHeader("Authorization"), "namespace": c.Query("namespace")})
+	})
+	router.GET("/api/v1/secrets", func(c *gin.Context) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	})
+	return router
+}
+
+func newTestSession(t *testing.T, maxSubscriptions int) *Session {
+	t.Helper()
+	hub := NewHub(testRouter(), maxSubscriptions, logger.New("error"), "/api/v1/streams/ws")
+	original := httptest.NewRequest(http.MethodGet, "/api/v1/streams/ws", nil)
+	original.Header.Set("Authorization", "Bearer token")
+	original.Header.Set("Upgrade", "websocket")
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	return hub.NewSession(ctx, original)
+}
+
+// next returns the next message of a session, failing the test if none arrives
+func next(t *testing.T, s *Session) ServerMessage {
+	t.Helper()
+	select {
+	case msg := <-s.Messages():
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a message")
+		return ServerMessage{}
+	}
+}
+
+func TestSessionForwardsEvents(t *testing.T) {
+	s := newTestSession(t, 0)
+	s.Handle(ClientMessage{Type: TypeSubscribe, ID: "pods", Path: "/api/v1/pods"})
+
+	if msg := next(t, s); msg.Type != TypeSubscribed || msg.ID != "pods" {
+		t.Fatalf("first message = %+v, want subscribed", msg)
+	}
+	if msg := next(t, s); msg.Type != TypeEvent || msg.Event != "" || string(msg.Data) != `["web-1"]` {
+		t.Fatalf("data message = %+v", msg)
+	}
+	if msg := next(t, s); msg.Event != "partial" || string(msg.Data) != `{"partial":true}` {
+		t.Fatalf("event split across writes = %+v", msg)
+	}
+
+	s.Handle(ClientMessage{Type: TypeUnsubscribe, ID: "pods"})
+	if msg := next(t, s); msg.Type != TypeClosed || msg.ID != "pods" {
+		t.Fatalf("after unsubscribe = %+v, want closed", msg)
+	}
+	s.Close()
+}
+
+func TestSessionPlainResponses(t *testing.T) {
+	s := newTestSession(t, 0)
+	s.Handle(ClientMessage{Type: TypeSubscribe, ID: "me", Path: "/api/v1/whoami", Query: map[string]string{"namespace": "shop"}})
+	next(t, s)
+	if msg := next(t, s); string(msg.Data) != `{"authorization":"Bearer token","namespace":"shop"}` {
+		t.Errorf("plain response = %s, want the caller's credentials and query", msg.Data)
+	}
+	if msg := next(t, s); msg.Type != TypeClosed {
+		t.Errorf("after a plain response = %+v, want closed", msg)
+	}
+
+	s.Handle(ClientMessage{Type: TypeSubscribe, ID: "secrets", Path: "/api/v1/secrets"})
+	next(t, s)
+	if msg := next(t, s); msg.Type != TypeError || msg.Status != http.StatusForbidden || msg.Error != "forbidden" {
+		t.Errorf("failed response = %+v, want a 403 error", msg)
+	}
+	next(t, s)
+	s.Close()
+}
+
+func TestSessionRejectsInvalidSubscriptions(t *testing.T) {
+	s := newTestSession(t, 1)
+	s.Handle(ClientMessage{Type: TypeSubscribe, ID: "pods", Path: "/api/v1/pods"})
+	next(t, s)
+
+	for _, msg := range []ClientMessage{
+		{Type: TypeSubscribe, Path: "/api/v1/pods"},
+		{Type: TypeSubscribe, ID: "pods", Path: "/api/v1/pods"},
+		{Type: TypeSubscribe, ID: "other", Path: "/api/v1/events"},
+		{Type: TypeSubscribe, ID: "escape", Path: "/api/v1/../../health"},
+		{Type: TypeSubscribe, ID: "self", Path: "/api/v1/streams/ws"},
+		{Type: TypeUnsubscribe, ID: "missing"},
+		{Type: "publish"},
+	} {
+		s.Handle(msg)
+		for {
+			reply := next(t, s)
+			if reply.Type == TypeEvent {
+				continue // events of the open subscription
+			}
+			if reply.Type != TypeError {
+				t.Errorf("%+v answered %+v, want an error", msg, reply)
+			}
+			break
+		}
+	}
+
+	s.Handle(ClientMessage{Type: TypePing})
+	for reply := next(t, s); reply.Type != TypePong; reply = next(t, s) {
+		if reply.Type != TypeEvent {
+			t.Fatalf("ping answered %+v, want pong", reply)
+		}
+	}
+	go func() {
+		for range s.Messages() {
+		}
+	}()
+	s.Close()
+}
@@ -0,0 +1,310 @@
+package streams
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/api/utils"
+	"github.com/Facets-cloud/kube-dash/internal/config"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+	"github.com/Facets-cloud/kube-dash/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Stream kinds
+const (
+	KindSSE       = "sse"
+	KindWebSocket = "websocket"
+)
+
+// idleCheckInterval is how often streams are checked for inactivity
+const idleCheckInterval = time.Minute
+
+// Stream describes an active SSE or WebSocket stream
+type Stream struct {
+	ID           string    `json:"id"`
+	Kind         string    `json:"kind"`
+	Path         string    `json:"path"`
+	ConfigID     string    `json:"configId,omitempty"`
+	Cluster      string    `json:"cluster,omitempty"`
+	ClientIP     string    `json:"clientIp"`
+	RequestID    string    `json:"requestId,omitempty"`
+	StartedAt    time.Time `json:"startedAt"`
+	LastActivity time.Time `json:"lastActivity"`
+}
+
+// Stats summarizes the active streams against the configured caps
+type Stats struct {
+	Total         int            `json:"total"`
+	MaxTotal      int            `json:"maxTotal"`
+	MaxPerCluster int            `json:"maxPerCluster"`
+	PerCluster    map[string]int `json:"perCluster"` // keyed by config ID and cluster name, joined by /
+	Draining      bool           `json:"draining"`
+	Streams       []Stream       `json:"streams"`
+}
+
+type stream struct {
+	info         Stream
+	clusterKey   string
+	lastActivity atomic.Int64 // unix nanoseconds
+	cancel       context.CancelFunc
+	writer       *streamWriter
+}
+
+func (s *stream) touch() {
+	s.lastActivity.Store(time.Now().UnixNano())
+}
+
+// Registry tracks the SSE and WebSocket streams served by the API. It refuses streams beyond the
+// global and per-cluster caps, closes idle ones, and on shutdown tells clients when to reconnect
+// before closing them.
+type Registry struct {
+	config *config.StreamsConfig
+	logger *logger.Logger
+
+	mu         sync.Mutex
+	streams    map[string]*stream
+	perCluster map[string]int
+	draining   bool
+	active     sync.WaitGroup
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewRegistry creates a stream registry
+func NewRegistry(cfg *config.StreamsConfig, log *logger.Logger) *Registry {
+	return &Registry{
+		config:     cfg,
+		logger:     log,
+		streams:    make(map[string]*stream),
+		perCluster: make(map[string]int),
+	}
+}
+
+// Start starts closing idle streams
+func (r *Registry) Start() {
+	if r.config.IdleTimeoutSeconds <= 0 {
+		return
+	}
+	r.ctx, r.cancel = context.WithCancel(context.Background())
+	go func() {
+		ticker := time.NewTicker(idleCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.ctx.Done():
+				return
+			case <-ticker.C:
+				r.closeIdle(time.Now())
+			}
+		}
+	}()
+}
+
+// streamKind returns the kind of stream a request opens, or an empty string for other requests
+func streamKind(req *http.Request) string {
+	if strings.EqualFold(req.Header.Get("Upgrade"), "websocket") {
+		return KindWebSocket
+	}
+	if strings.Contains(req.Header.Get("Accept"), "text/event-stream") {
+		return KindSSE
+	}
+	return ""
+}
+
+// Middleware registers the streams opened by requests for the duration of the handler. Streams over
+// a cap are refused with 429 and streams opened while the server stops with 503, both with a
+// Retry-After header.
+func (r *Registry) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kind := streamKind(c.Request)
+		if kind == "" {
+			c.Next()
+			return
+		}
+		ctx, cancel := context.WithCancel(c.Request.Context())
+		defer cancel()
+		s := &stream{cancel: cancel}
+		s.writer = &streamWriter{ResponseWriter: c.Writer, stream: s}
+		if status, err := r.register(c, kind, s); err != nil {
+			c.Header("Retry-After", strconv.Itoa(r.retryAfter()))
+			utils.RespondErrorMessage(c, status, err.Error())
+			c.Abort()
+			return
+		}
+		defer r.release(s)
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Writer = s.writer
+		c.Next()
+	}
+}
+
+func (r *Registry) retryAfter() int {
+	if r.config.ShutdownRetryAfterSeconds > 0 {
+		return r.config.ShutdownRetryAfterSeconds
+	}
+	return 1
+}
+
+func (r *Registry) register(c *gin.Context, kind string, s *stream) (int, error) {
+	configID, cluster := c.Query("config"), c.Query("cluster")
+	clusterKey := configID + "/" + cluster
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.draining {
+		return http.StatusServiceUnavailable, fmt.Errorf("server is shutting down")
+	}
+	if r.config.MaxTotal > 0 && len(r.streams) >= r.config.MaxTotal {
+		return http.StatusTooManyRequests, fmt.Errorf("too many open streams (limit %d)", r.config.MaxTotal)
+	}
+	if r.config.MaxPerCluster > 0 && r.perCluster[clusterKey] >= r.config.MaxPerCluster {
+		return http.StatusTooManyRequests, fmt.Errorf("too many open streams for this cluster (limit %d)", r.config.MaxPerCluster)
+	}
+
+	now := time.Now()
+	s.info = Stream{
+		ID:        uuid.New().String(),
+		Kind:      kind,
+		Path:      c.Request.URL.Path,
+		ConfigID:  configID,
+		Cluster:   cluster,
+		ClientIP:  c.ClientIP(),
+		RequestID: middleware.CorrelationIDFrom(c),
+		StartedAt: now,
+	}
+	s.clusterKey = clusterKey
+	s.lastActivity.Store(now.UnixNano())
+	r.streams[s.info.ID] = s
+	r.perCluster[clusterKey]++
+	r.active.Add(1)
+	return 0, nil
+}
+
+func (r *Registry) release(s *stream) {
+	r.mu.Lock()
+	if _, ok := r.streams[s.info.ID]; ok {
+		delete(r.streams, s.info.ID)
+		if r.perCluster[s.clusterKey]--; r.perCluster[s.clusterKey] <= 0 {
+			delete(r.perCluster, s.clusterKey)
+		}
+		r.active.Done()
+	}
+	r.mu.Unlock()
+}
+
+// Stats returns the active streams, longest running first
+func (r *Registry) Stats() Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats := Stats{
+		Total:         len(r.streams),
+		MaxTotal:      r.config.MaxTotal,
+		MaxPerCluster: r.config.MaxPerCluster,
+		PerCluster:    make(map[string]int, len(r.perCluster)),
+		Draining:      r.draining,
+		Streams:       make([]Stream, 0, len(r.streams)),
+	}
+	for key, count := range r.perCluster {
+		stats.PerCluster[key] = count
+	}
+	for _, s := range r.streams {
+		info := s.info
+		info.LastActivity = time.Unix(0, s.lastActivity.Load())
+		stats.Streams = append(stats.Streams, info)
+	}
+	sort.Slice(stats.Streams, func(i, j int) bool {
+		return stats.Streams[i].StartedAt.Before(stats.Streams[j].StartedAt)
+	})
+	return stats
+}
+
+// closeIdle closes the streams that have neither sent nor received anything within the idle timeout
+func (r *Registry) closeIdle(now time.Time) {
+	cutoff := now.Add(-time.Duration(r.config.IdleTimeoutSeconds) * time.Second).UnixNano()
+	r.mu.Lock()
+	var idle []*stream
+	for _, s := range r.streams {
+		if s.lastActivity.Load() < cutoff {
+			idle = append(idle, s)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, s := range idle {
+		r.logger.WithField("stream", s.info.Path).WithField("kind", s.info.Kind).Info("Closing idle stream")
+		s.writer.sendSSE("event: close\ndata: {\"reason\":\"idle\"}\n\n")
+		s.writer.closeConn(closeGoingAway, "idle timeout")
+		s.cancel()
+	}
+}
+
+// CloseMatching closes the streams whose config and cluster match, e.g. those of a removed
+// kubeconfig, and returns how many it closed
+func (r *Registry) CloseMatching(match func(configID, cluster string) bool) int {
+	r.mu.Lock()
+	var matched []*stream
+	for _, s := range r.streams {
+		if s.info.ConfigID != "" && match(s.info.ConfigID, s.info.Cluster) {
+			matched = append(matched, s)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, s := range matched {
+		r.logger.WithField("stream", s.info.Path).WithField("kind", s.info.Kind).Info("Closing stream of removed cluster")
+		s.writer.sendSSE("event: close\ndata: {\"reason\":\"cluster removed\"}\n\n")
+		s.writer.closeConn(closeGoingAway, "cluster removed")
+		s.cancel()
+	}
+	return len(matched)
+}
+
+// Shutdown refuses new streams, tells the clients of open streams to reconnect after the
+// configured delay and closes them, then waits for their handlers to return or ctx to end
+func (r *Registry) Shutdown(ctx context.Context) error {
+	r.mu.Lock()
+	r.draining = true
+	open := make([]*stream, 0, len(r.streams))
+	for _, s := range r.streams {
+		open = append(open, s)
+	}
+	r.mu.Unlock()
+	if r.cancel != nil {
+		r.cancel()
+	}
+
+	retryAfter := r.retryAfter()
+	event := fmt.Sprintf("retry: %d\nevent: shutdown\ndata: {\"reason\":\"server shutting down\",\"retryAfter\":%d}\n\n", retryAfter*1000, retryAfter)
+	for _, s := range open {
+		s.writer.sendSSE(event)
+		s.writer.closeConn(closeServiceRestart, fmt.Sprintf("server restarting, retry after %ds", retryAfter))
+		s.cancel()
+	}
+	if len(open) > 0 {
+		r.logger.WithField("streams", len(open)).Info("Closed open streams for shutdown")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		r.active.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,216 @@
+package streams
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/config"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// newTestServer serves an SSE endpoint that sends keep-alives until its context ends, and a
+// WebSocket endpoint that reads until the connection closes
+func newTestServer(t *testing.T, cfg *config.StreamsConfig) (*Registry, *httptest.Server) {
+	gin.SetMode(gin.TestMode)
+	registry := NewRegistry(cfg, logger.New("error"))
+	router := gin.New()
+	router.Use(registry.Middleware())
+	router.GET("/events", func(c *gin.Context) {
+		c.Header("Content-Type", "text/event-stream")
+		c.Data(http.StatusOK, "text/event-stream", []byte("data: []\n\n"))
+		c.Writer.Flush()
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.Request.Context().Done():
+				return
+			case <-ticker.C:
+				if _, err := c.Writer.Write([]byte(": keep-alive\n\n")); err != nil {
+					return
+				}
+				c.Writer.Flush()
+			}
+		}
+	})
+	upgrader := websocket.Upgrader{}
+	router.GET("/ws", func(c *gin.Context) {
+		conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return registry, server
+}
+
+func openSSE(t *testing.T, url string) *http.Response {
+	t.Helper()
+	req, _ := http.NewRequest("GET", url, nil)
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("open stream: %v", err)
+	}
+	return resp
+}
+
+func waitForStreams(t *testing.T, r *Registry, want int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for r.Stats().Total != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("open streams = %d, want %d", r.Stats().Total, want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestRegistryCaps(t *testing.T) {
+	registry, server := newTestServer(t, &config.StreamsConfig{MaxPerCluster: 1, MaxTotal: 2, ShutdownRetryAfterSeconds: 3})
+
+	first := openSSE(t, server.URL+"/events?config=a&cluster=one")
+	defer first.Body.Close()
+	waitForStreams(t, registry, 1)
+
+	refused := openSSE(t, server.URL+"/events?config=a&cluster=one")
+	refused.Body.Close()
+	if refused.StatusCode != http.StatusTooManyRequests || refused.Header.Get("Retry-After") != "3" {
+		t.Errorf("second stream of a cluster = %d Retry-After %q, want 429 and 3", refused.StatusCode, refused.Header.Get("Retry-After"))
+	}
+
+	second := openSSE(t, server.URL+"/events?config=a&cluster=two")
+	defer second.Body.Close()
+	if second.StatusCode != http.StatusOK {
+		t.Fatalf("stream of another cluster = %d, want 200", second.StatusCode)
+	}
+	waitForStreams(t, registry, 2)
+
+	third := openSSE(t, server.URL+"/events?config=b")
+	third.Body.Close()
+	if third.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("stream over the global cap = %d, want 429", third.StatusCode)
+	}
+
+	first.Body.Close()
+	waitForStreams(t, registry, 1)
+	if stats := registry.Stats(); stats.PerCluster["a/one"] != 0 || stats.PerCluster["a/two"] != 1 {
+		t.Errorf("per-cluster counts = %v after closing a stream", stats.PerCluster)
+	}
+}
+
+func TestRegistryShutdown(t *testing.T) {
+	registry, server := newTestServer(t, &config.StreamsConfig{ShutdownRetryAfterSeconds: 7})
+
+	resp := openSSE(t, server.URL+"/events")
+	defer resp.Body.Close()
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", nil)
+	if err != nil {
+		t.Fatalf("dial websocket: %v", err)
+	}
+	defer ws.Close()
+	waitForStreams(t, registry, 2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := registry.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() = %v", err)
+	}
+
+	var body strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		body.WriteString(scanner.Text() + "\n")
+	}
+	if !strings.Contains(body.String(), "retry: 7000\nevent: shutdown\n") || !strings.Contains(body.String(), `"retryAfter":7`) {
+		t.Errorf("SSE stream ended without a shutdown notice:\n%s", body.String())
+	}
+
+	_, _, err = ws.ReadMessage()
+	if !websocket.IsCloseError(err, closeServiceRestart) {
+		t.Errorf("websocket read after shutdown = %v, want close %d", err, closeServiceRestart)
+	}
+
+	refused := openSSE(t, server.URL+"/events")
+	refused.Body.Close()
+	if refused.StatusCode != http.StatusServiceUnavailable || refused.Header.Get("Retry-After") != "7" {
+		t.Errorf("stream during shutdown = %d Retry-After %q, want 503 and 7", refused.StatusCode, refused.Header.Get("Retry-After"))
+	}
+}
+
+func TestRegistryCloseIdle(t *testing.T) {
+	registry, server := newTestServer(t, &config.StreamsConfig{IdleTimeoutSeconds: 60})
+
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", nil)
+	if err != nil {
+		t.Fatalf("dial websocket: %v", err)
+	}
+	defer ws.Close()
+	resp := openSSE(t, server.URL+"/events")
+	defer resp.Body.Close()
+	waitForStreams(t, registry, 2)
+
+	// The SSE stream keeps writing keep-alives; the WebSocket goes quiet after the upgrade
+	registry.closeIdle(time.Now())
+	if got := registry.Stats().Total; got != 2 {
+		t.Fatalf("open streams = %d before the idle timeout, want 2", got)
+	}
+	registry.mu.Lock()
+	for _, s := range registry.streams {
+		if s.info.Kind == KindWebSocket {
+			s.lastActivity.Store(time.Now().Add(-2 * time.Minute).UnixNano())
+		}
+	}
+	registry.mu.Unlock()
+	registry.closeIdle(time.Now())
+	_, _, err = ws.ReadMessage()
+	if !websocket.IsCloseError(err, closeGoingAway) {
+		t.Errorf("idle websocket read = %v, want close %d", err, closeGoingAway)
+	}
+	waitForStreams(t, registry, 1)
+}
+
+func TestRegistryCloseMatching(t *testing.T) {
+	registry, server := newTestServer(t, &config.StreamsConfig{})
+
+	removed := openSSE(t, server.URL+"/events?config=a&cluster=one")
+	defer removed.Body.Close()
+	kept := openSSE(t, server.URL+"/events?config=a&cluster=two")
+	defer kept.Body.Close()
+	waitForStreams(t, registry, 2)
+
+	closed := registry.CloseMatching(func(configID, cluster string) bool {
+		return configID == "a" && cluster == "one"
+	})
+	if closed != 1 {
+		t.Fatalf("CloseMatching() = %d, want 1", closed)
+	}
+
+	var body strings.Builder
+	scanner := bufio.NewScanner(removed.Body)
+	for scanner.Scan() {
+		body.WriteString(scanner.Text() + "\n")
+	}
+	if !strings.Contains(body.String(), `"reason":"cluster removed"`) {
+		t.Errorf("closed stream ended without a close notice:\n%s", body.String())
+	}
+	waitForStreams(t, registry, 1)
+	if stats := registry.Stats(); stats.Streams[0].Cluster != "two" {
+		t.Errorf("remaining stream = %+v, want cluster two", stats.Streams[0])
+	}
+}
@@ -0,0 +1,136 @@
+package streams
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errStreamClosed is returned to handlers writing to a stream the registry has closed
+var errStreamClosed = errors.New("stream closed by server")
+
+// streamWriter records the activity of a stream and serializes the handler's writes with the
+// notices the registry sends when it closes the stream
+type streamWriter struct {
+	gin.ResponseWriter
+	stream *stream
+
+	mu     sync.Mutex
+	closed bool
+	conn   *activityConn // set once a WebSocket upgrade hijacks the connection
+}
+
+func (w *streamWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, errStreamClosed
+	}
+	w.stream.touch()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *streamWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *streamWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.closed {
+		w.ResponseWriter.Flush()
+	}
+}
+
+// Hijack wraps the hijacked connection so WebSocket reads and writes count as activity and the
+// registry can close it
+func (w *streamWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := w.ResponseWriter.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	wrapped := &activityConn{Conn: conn, stream: w.stream}
+	if rw.Reader.Buffered() == 0 {
+		// Nothing was read ahead of the upgrade, so reads can go through the wrapped connection
+		rw = bufio.NewReadWriter(bufio.NewReaderSize(wrapped, rw.Reader.Size()), bufio.NewWriterSize(wrapped, rw.Writer.Size()))
+	}
+	w.mu.Lock()
+	w.conn = wrapped
+	w.mu.Unlock()
+	return wrapped, rw, nil
+}
+
+// sendSSE writes a final event to an SSE stream and stops further writes
+func (w *streamWriter) sendSSE(event string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return
+	}
+	w.closed = true
+	if w.conn != nil || !w.ResponseWriter.Written() || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/event-stream") {
+		// Upgraded to a WebSocket, or no event stream was started
+		return
+	}
+	if _, err := w.ResponseWriter.Write([]byte(event)); err == nil {
+		w.ResponseWriter.Flush()
+	}
+}
+
+// closeConn sends a WebSocket close frame on a hijacked connection and closes it
+func (w *streamWriter) closeConn(code uint16, reason string) {
+	w.mu.Lock()
+	conn := w.conn
+	w.closed = true
+	w.mu.Unlock()
+	if conn == nil {
+		return
+	}
+	_, _ = conn.Write(closeFrame(code, reason))
+	_ = conn.Close()
+}
+
+// activityConn is a hijacked connection whose reads and writes count as stream activity
+type activityConn struct {
+	net.Conn
+	stream *stream
+
+	writeMu sync.Mutex
+}
+
+func (c *activityConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		c.stream.touch()
+	}
+	return n, err
+}
+
+func (c *activityConn) Write(b []byte) (int, error) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.stream.touch()
+	return c.Conn.Write(b)
+}
+
+// WebSocket close codes sent by the registry
+const (
+	closeGoingAway      = 1001
+	closeServiceRestart = 1012
+)
+
+// closeFrame builds an unmasked WebSocket close frame, as servers send them
+func closeFrame(code uint16, reason string) []byte {
+	if len(reason) > 123 {
+		reason = reason[:123]
+	}
+	payload := make([]byte, 2, 2+len(reason))
+	binary.BigEndian.PutUint16(payload, code)
+	payload = append(payload, reason...)
+	return append([]byte{0x88, byte(len(payload))}, payload...)
+}
@@ -0,0 +1,135 @@
+package testharness
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+)
+
+// builtinCodecs decode the kinds the fake clientset serves. The shared client-go scheme is not
+// used, since other packages register more kinds, such as CustomResourceDefinitions, with it.
+var builtinCodecs = func() serializer.CodecFactory {
+	builtin := k8sruntime.NewScheme()
+	utilruntime.Must(scheme.AddToScheme(builtin))
+	return serializer.NewCodecFactory(builtin)
+}()
+
+// Objects are the decoded objects of a fixture file
+type Objects struct {
+	Typed     []k8sruntime.Object                    // built-in kinds, for the typed clientset
+	Custom    []k8sruntime.Object                    // other kinds, as unstructured objects for the dynamic client
+	ListKinds map[schema.GroupVersionResource]string // list kinds declared by CustomResourceDefinitions
+}
+
+// ReadFile reads a fixture from the testdata directory of the calling test's package
+func ReadFile(t *testing.T, name string) []byte {
+	t.Helper()
+	raw, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	return raw
+}
+
+// Recorded returns a recorded external response shipped with the harness, such as
+// prometheus/pod_cpu_matrix.json or artifacthub/search_nginx.json
+func Recorded(t *testing.T, name string) []byte {
+	t.Helper()
+	_, file, _, _ := runtime.Caller(0)
+	raw, err := os.ReadFile(filepath.Join(filepath.Dir(file), "testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read recorded response: %v", err)
+	}
+	return raw
+}
+
+// DecodeObjects decodes multi-document YAML into objects. CustomResourceDefinitions are kept
+// with the custom objects and register the list kind of the resource they define.
+func DecodeObjects(raw []byte) (*Objects, error) {
+	objects := &Objects{ListKinds: map[schema.GroupVersionResource]string{}}
+	decoder := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(raw)))
+	for {
+		doc, err := decoder.Read()
+		if err == io.EOF {
+			return objects, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+		if obj, _, err := builtinCodecs.UniversalDeserializer().Decode(doc, nil, nil); err == nil {
+			objects.Typed = append(objects.Typed, obj)
+			continue
+		}
+
+		var content map[string]interface{}
+		if err := yaml.Unmarshal(doc, &content); err != nil {
+			return nil, err
+		}
+		if len(content) == 0 {
+			continue
+		}
+		obj := &unstructured.Unstructured{Object: content}
+		if obj.GetKind() == "" || obj.GetAPIVersion() == "" {
+			return nil, fmt.Errorf("object %q has no apiVersion or kind", obj.GetName())
+		}
+		objects.Custom = append(objects.Custom, obj)
+		if obj.GetKind() == "CustomResourceDefinition" {
+			registerCRD(objects.ListKinds, obj)
+		}
+	}
+}
+
+// registerCRD registers the list kind of every served version of a CustomResourceDefinition
+func registerCRD(listKinds map[schema.GroupVersionResource]string, crd *unstructured.Unstructured) {
+	group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+	plural, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "plural")
+	kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for _, v := range versions {
+		if version, ok := v.(map[string]interface{}); ok {
+			name, _ := version["name"].(string)
+			listKinds[schema.GroupVersionResource{Group: group, Version: name, Resource: plural}] = kind + "List"
+		}
+	}
+	listKinds[schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}] = "CustomResourceDefinitionList"
+}
+
+// ReplayServer serves recorded responses by request path, e.g.
+// {"/api/v1/packages/search": "artifacthub/search_nginx.json"}. Unknown paths answer 404.
+func ReplayServer(t *testing.T, routes map[string]string) *httptest.Server {
+	t.Helper()
+	responses := map[string][]byte{}
+	for path, name := range routes {
+		responses[path] = Recorded(t, name)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
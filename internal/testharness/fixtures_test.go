@@ -0,0 +1,48 @@
+package testharness
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestDecodeObjects(t *testing.T) {
+	raw := []byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: shop
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+spec:
+  group: example.com
+  names: {plural: widgets, kind: Widget}
+  versions: [{name: v1}, {name: v1beta1}]
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: blue
+`)
+	objects, err := DecodeObjects(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objects.Typed) != 1 || len(objects.Custom) != 2 {
+		t.Fatalf("decoded %d typed and %d custom objects, want 1 and 2", len(objects.Typed), len(objects.Custom))
+	}
+	for _, version := range []string{"v1", "v1beta1"} {
+		gvr := schema.GroupVersionResource{Group: "example.com", Version: version, Resource: "widgets"}
+		if objects.ListKinds[gvr] != "WidgetList" {
+			t.Errorf("list kind of %s = %q, want WidgetList", gvr, objects.ListKinds[gvr])
+		}
+	}
+
+	if _, err := DecodeObjects([]byte("metadata:\n  name: nameless\n")); err == nil {
+		t.Error("DecodeObjects() accepted an object without a kind")
+	}
+}
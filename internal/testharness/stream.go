@@ -0,0 +1,48 @@
+package testharness
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"sync"
+)
+
+// streamRecorder records a streaming response and ends the request once the first SSE data
+// frame is written, since streaming handlers only return when the client goes away
+type streamRecorder struct {
+	*httptest.ResponseRecorder
+	mu     sync.Mutex
+	data   []byte
+	cancel func()
+}
+
+func newStreamRecorder(cancel func()) *streamRecorder {
+	return &streamRecorder{ResponseRecorder: httptest.NewRecorder(), cancel: cancel}
+}
+
+// Write records p, capturing the payload of the first data frame
+func (r *streamRecorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.data == nil && bytes.HasPrefix(p, []byte("data: ")) {
+		r.data = bytes.TrimSpace(bytes.TrimPrefix(p, []byte("data: ")))
+		r.cancel()
+	}
+	return r.ResponseRecorder.Write(p)
+}
+
+// WriteString records s like Write
+func (r *streamRecorder) WriteString(s string) (int, error) {
+	return r.Write([]byte(s))
+}
+
+func (r *streamRecorder) firstData() []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.data
+}
+
+func (r *streamRecorder) body() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.Body.String()
+}
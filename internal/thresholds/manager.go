@@ -0,0 +1,367 @@
+package thresholds
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// Document collections used by the manager
+const (
+	rulesCollection    = "metric_thresholds"
+	breachesCollection = "metric_breaches"
+)
+
+// breachRetention is how long resolved breaches are kept
+const breachRetention = 30 * 24 * time.Hour
+
+// Alert states
+const (
+	StatePending = "pending" // breaching, but not yet for the rule's duration
+	StateFiring  = "firing"
+)
+
+// Alert is the current state of a rule for a streamed target
+type Alert struct {
+	RuleID   string    `json:"ruleId"`
+	RuleName string    `json:"ruleName"`
+	Severity string    `json:"severity"`
+	State    string    `json:"state"`
+	Value    float64   `json:"value"` // peak value while breaching
+	Since    time.Time `json:"since"`
+	Condition
+}
+
+// AlertEvent is a transition of an alert between firing and resolved
+type AlertEvent struct {
+	Type     string    `json:"type"` // firing or resolved
+	RuleID   string    `json:"ruleId"`
+	RuleName string    `json:"ruleName"`
+	Severity string    `json:"severity"`
+	Metric   string    `json:"metric"`
+	Value    float64   `json:"value"`
+	Time     time.Time `json:"time"`
+}
+
+// StreamAlerts is added to metrics stream payloads
+type StreamAlerts struct {
+	Rules  int          `json:"rules"` // thresholds evaluated for the target
+	Active []Alert      `json:"active"`
+	Events []AlertEvent `json:"events,omitempty"` // transitions since the previous message
+}
+
+// Breach is a recorded period during which a threshold fired for a target
+type Breach struct {
+	ID         string     `json:"id"`
+	RuleID     string     `json:"ruleId"`
+	RuleName   string     `json:"ruleName"`
+	Severity   string     `json:"severity"`
+	Target     Target     `json:"target"`
+	Condition  Condition  `json:"condition"`
+	PeakValue  float64    `json:"peakValue"`
+	StartedAt  time.Time  `json:"startedAt"`
+	FiredAt    time.Time  `json:"firedAt"`
+	ResolvedAt *time.Time `json:"resolvedAt,omitempty"`
+}
+
+// BreachFilter narrows the breaches returned by ListBreaches; empty fields match everything
+type BreachFilter struct {
+	ConfigID  string
+	Cluster   string
+	Kind      string
+	Namespace string
+	Name      string
+	RuleID    string
+	Active    bool
+	Limit     int
+}
+
+// Manager stores threshold rules, evaluates them for metrics streams and records breaches
+type Manager struct {
+	documents *storage.DocumentStore
+	logger    *logger.Logger
+
+	mu    sync.RWMutex
+	rules []Rule
+	open  map[string]*Breach // unresolved breaches keyed by rule|target
+}
+
+// NewManager creates a threshold manager
+func NewManager(documents *storage.DocumentStore, log *logger.Logger) *Manager {
+	m := &Manager{
+		documents: documents,
+		logger:    log,
+		open:      map[string]*Breach{},
+	}
+	if err := m.reload(); err != nil {
+		log.WithError(err).Error("Failed to load metric thresholds")
+	}
+	return m
+}
+
+func (m *Manager) reload() error {
+	docs, err := m.documents.List(rulesCollection)
+	if err != nil {
+		return err
+	}
+	rules := make([]Rule, 0, len(docs))
+	for id, data := range docs {
+		var rule Rule
+		if err := json.Unmarshal(data, &rule); err != nil {
+			m.logger.WithError(err).WithField("threshold", id).Error("Skipping unreadable metric threshold")
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Name < rules[j].Name })
+
+	breaches, err := m.documents.List(breachesCollection)
+	if err != nil {
+		return err
+	}
+	open := map[string]*Breach{}
+	for _, data := range breaches {
+		var b Breach
+		if err := json.Unmarshal(data, &b); err == nil && b.ResolvedAt == nil {
+			open[b.RuleID+"|"+b.Target.key()] = &b
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rules = rules
+	m.open = open
+	return nil
+}
+
+// ListRules returns all threshold rules sorted by name
+func (m *Manager) ListRules() []Rule {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]Rule{}, m.rules...)
+}
+
+// GetRule returns a single rule
+func (m *Manager) GetRule(id string) (*Rule, error) {
+	var rule Rule
+	if err := m.documents.Get(rulesCollection, id, &rule); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// SaveRule validates and persists a rule, assigning an ID to new rules
+func (m *Manager) SaveRule(rule *Rule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	now := time.Now()
+	if rule.ID == "" {
+		rule.ID = uuid.New().String()
+		rule.CreatedAt = now
+	}
+	rule.UpdatedAt = now
+	if err := m.documents.Put(rulesCollection, rule.ID, rule); err != nil {
+		return err
+	}
+	return m.reload()
+}
+
+// DeleteRule removes a rule, resolving its open breaches; recorded breaches are kept
+func (m *Manager) DeleteRule(id string) error {
+	if err := m.documents.Delete(rulesCollection, id); err != nil {
+		return err
+	}
+	now := time.Now()
+	m.mu.Lock()
+	var resolved []Breach
+	for key, b := range m.open {
+		if b.RuleID == id {
+			b.ResolvedAt = &now
+			resolved = append(resolved, *b)
+			delete(m.open, key)
+		}
+	}
+	m.mu.Unlock()
+	for i := range resolved {
+		if err := m.documents.Put(breachesCollection, resolved[i].ID, &resolved[i]); err != nil {
+			m.logger.WithError(err).Error("Failed to resolve metric threshold breach")
+		}
+	}
+	return m.reload()
+}
+
+// rulesFor returns the enabled rules matching a target
+func (m *Manager) rulesFor(t Target) []Rule {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var rules []Rule
+	for i := range m.rules {
+		if m.rules[i].Matches(t) {
+			rules = append(rules, m.rules[i])
+		}
+	}
+	return rules
+}
+
+// Session evaluates thresholds for one metrics stream and remembers which alerts the
+// stream has already reported so only transitions are emitted as events
+type Session struct {
+	manager *Manager
+	target  Target
+	inline  []Rule
+	firing  map[string]bool
+}
+
+// NewSession starts evaluating the saved rules matching the target plus inline rules
+func (m *Manager) NewSession(t Target, inline []Rule) *Session {
+	return &Session{manager: m, target: t, inline: inline, firing: map[string]bool{}}
+}
+
+// Active reports whether any thresholds apply to the stream
+func (s *Session) Active() bool {
+	return len(s.inline) > 0 || len(s.manager.rulesFor(s.target)) > 0
+}
+
+// Evaluate checks all thresholds against the latest samples, records breach transitions and
+// returns the alert state to add to the stream payload
+func (s *Session) Evaluate(samples map[string][]Sample, now time.Time) StreamAlerts {
+	rules := append(s.manager.rulesFor(s.target), s.inline...)
+	result := StreamAlerts{Rules: len(rules), Active: []Alert{}}
+	seen := map[string]bool{}
+	for i := range rules {
+		rule := &rules[i]
+		seen[rule.ID] = true
+		series, ok := samples[rule.Metric]
+		if !ok {
+			// Without data the previous state is kept rather than resolving the alert
+			continue
+		}
+		fires, since, peak := evaluate(rule.Condition, series)
+		switch {
+		case fires:
+			result.Active = append(result.Active, Alert{RuleID: rule.ID, RuleName: rule.Name, Severity: rule.Severity, State: StateFiring, Value: peak, Since: since, Condition: rule.Condition})
+			s.manager.recordFiring(rule, s.target, since, peak, now)
+			if !s.firing[rule.ID] {
+				s.firing[rule.ID] = true
+				result.Events = append(result.Events, AlertEvent{Type: StateFiring, RuleID: rule.ID, RuleName: rule.Name, Severity: rule.Severity, Metric: rule.Metric, Value: peak, Time: now})
+			}
+		case !since.IsZero():
+			result.Active = append(result.Active, Alert{RuleID: rule.ID, RuleName: rule.Name, Severity: rule.Severity, State: StatePending, Value: peak, Since: since, Condition: rule.Condition})
+			fallthrough
+		default:
+			s.manager.recordResolved(rule, s.target, now)
+			if s.firing[rule.ID] {
+				delete(s.firing, rule.ID)
+				result.Events = append(result.Events, AlertEvent{Type: "resolved", RuleID: rule.ID, RuleName: rule.Name, Severity: rule.Severity, Metric: rule.Metric, Value: lastValue(series), Time: now})
+			}
+		}
+	}
+	// Rules deleted or disabled while firing resolve in the stream
+	for id := range s.firing {
+		if !seen[id] {
+			delete(s.firing, id)
+			result.Events = append(result.Events, AlertEvent{Type: "resolved", RuleID: id, Time: now})
+		}
+	}
+	return result
+}
+
+func lastValue(samples []Sample) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	return samples[len(samples)-1].V
+}
+
+// recordFiring opens a breach for the rule and target, or updates the peak of the open one
+func (m *Manager) recordFiring(rule *Rule, t Target, since time.Time, peak float64, now time.Time) {
+	key := rule.ID + "|" + t.key()
+	m.mu.Lock()
+	b, ok := m.open[key]
+	if ok && b.PeakValue == peak {
+		m.mu.Unlock()
+		return
+	}
+	if !ok {
+		b = &Breach{
+			ID:        uuid.New().String(),
+			RuleID:    rule.ID,
+			RuleName:  rule.Name,
+			Severity:  rule.Severity,
+			Target:    t,
+			Condition: rule.Condition,
+			PeakValue: peak,
+			StartedAt: since,
+			FiredAt:   now,
+		}
+		m.open[key] = b
+	} else if operators[rule.Operator](peak, b.PeakValue) {
+		b.PeakValue = peak
+	}
+	snapshot := *b
+	m.mu.Unlock()
+	if err := m.documents.Put(breachesCollection, snapshot.ID, &snapshot); err != nil {
+		m.logger.WithError(err).Error("Failed to record metric threshold breach")
+	}
+}
+
+// recordResolved closes the open breach for the rule and target, if any
+func (m *Manager) recordResolved(rule *Rule, t Target, now time.Time) {
+	key := rule.ID + "|" + t.key()
+	m.mu.Lock()
+	b, ok := m.open[key]
+	if !ok {
+		m.mu.Unlock()
+		return
+	}
+	delete(m.open, key)
+	resolved := now
+	b.ResolvedAt = &resolved
+	snapshot := *b
+	m.mu.Unlock()
+	if err := m.documents.Put(breachesCollection, snapshot.ID, &snapshot); err != nil {
+		m.logger.WithError(err).Error("Failed to record resolved metric threshold breach")
+	}
+}
+
+// ListBreaches returns recorded breaches, newest first, pruning resolved breaches past retention
+func (m *Manager) ListBreaches(filter BreachFilter) ([]Breach, error) {
+	docs, err := m.documents.List(breachesCollection)
+	if err != nil {
+		return nil, err
+	}
+	cutoff := time.Now().Add(-breachRetention)
+	breaches := []Breach{}
+	for id, data := range docs {
+		var b Breach
+		if err := json.Unmarshal(data, &b); err != nil {
+			continue
+		}
+		if b.ResolvedAt != nil && b.ResolvedAt.Before(cutoff) {
+			if err := m.documents.Delete(breachesCollection, id); err != nil {
+				m.logger.WithError(err).Warn("Failed to prune metric threshold breach")
+			}
+			continue
+		}
+		t := b.Target
+		if (filter.ConfigID != "" && t.ConfigID != filter.ConfigID) || (filter.Cluster != "" && t.Cluster != filter.Cluster) ||
+			(filter.Kind != "" && t.Kind != filter.Kind) || (filter.Namespace != "" && t.Namespace != filter.Namespace) ||
+			(filter.Name != "" && t.Name != filter.Name) || (filter.RuleID != "" && b.RuleID != filter.RuleID) ||
+			(filter.Active && b.ResolvedAt != nil) {
+			continue
+		}
+		breaches = append(breaches, b)
+	}
+	sort.Slice(breaches, func(i, j int) bool { return breaches[i].FiredAt.After(breaches[j].FiredAt) })
+	if filter.Limit > 0 && len(breaches) > filter.Limit {
+		breaches = breaches[:filter.Limit]
+	}
+	return breaches, nil
+}
@@ -0,0 +1,247 @@
+package thresholds
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Target kinds
+const (
+	KindPod  = "pod"
+	KindNode = "node"
+)
+
+// Metrics that can be thresholded, per target kind
+var Metrics = map[string][]string{
+	KindPod: {
+		"cpu_average", "cpu_maximum", "memory_usage",
+		"cpu_limit_percent", "memory_limit_percent", "cpu_request_percent", "memory_request_percent",
+	},
+	KindNode: {
+		"cpu_usage_percent", "memory_usage_percent", "disk_usage_percent",
+		"cpu_requests_percent", "memory_requests_percent",
+	},
+}
+
+var operators = map[string]func(a, b float64) bool{
+	">":  func(a, b float64) bool { return a > b },
+	">=": func(a, b float64) bool { return a >= b },
+	"<":  func(a, b float64) bool { return a < b },
+	"<=": func(a, b float64) bool { return a <= b },
+}
+
+// Condition is a metric threshold that must hold for a duration before it fires
+type Condition struct {
+	Metric     string  `json:"metric"`
+	Operator   string  `json:"operator"` // >, >=, < or <=
+	Value      float64 `json:"value"`
+	ForSeconds int     `json:"forSeconds,omitempty"`
+}
+
+// String renders the condition in the inline syntax, e.g. cpu_limit_percent>80 for 5m0s
+func (c Condition) String() string {
+	s := c.Metric + c.Operator + strconv.FormatFloat(c.Value, 'g', -1, 64)
+	if c.ForSeconds > 0 {
+		s += " for " + (time.Duration(c.ForSeconds) * time.Second).String()
+	}
+	return s
+}
+
+// Validate checks the condition against the metrics of a target kind
+func (c *Condition) Validate(kind string) error {
+	known := false
+	for _, m := range Metrics[kind] {
+		if m == c.Metric {
+			known = true
+		}
+	}
+	if !known {
+		return fmt.Errorf("unknown %s metric %q, expected one of %s", kind, c.Metric, strings.Join(Metrics[kind], ", "))
+	}
+	if _, ok := operators[c.Operator]; !ok {
+		return fmt.Errorf("operator must be one of >, >=, <, <=")
+	}
+	if c.ForSeconds < 0 {
+		return fmt.Errorf("forSeconds must not be negative")
+	}
+	return nil
+}
+
+// Rule is a saved threshold evaluated on the metrics streams of matching pods or nodes
+type Rule struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Enabled   bool   `json:"enabled"`
+	ConfigID  string `json:"configId"`
+	Cluster   string `json:"cluster,omitempty"`
+	Kind      string `json:"kind"`                // pod or node
+	Namespace string `json:"namespace,omitempty"` // pods only; empty matches all namespaces
+	Target    string `json:"target,omitempty"`    // pod or node name glob; empty matches all
+	Condition
+	Severity  string    `json:"severity,omitempty"` // warning (default) or critical
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Validate checks that a rule can be saved and fills defaults
+func (r *Rule) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if r.ConfigID == "" {
+		return fmt.Errorf("configId is required")
+	}
+	if r.Kind != KindPod && r.Kind != KindNode {
+		return fmt.Errorf("kind must be pod or node")
+	}
+	if r.Kind == KindNode && r.Namespace != "" {
+		return fmt.Errorf("namespace is only valid for pod rules")
+	}
+	if r.Target != "" {
+		if _, err := path.Match(r.Target, ""); err != nil {
+			return fmt.Errorf("invalid target pattern: %w", err)
+		}
+	}
+	switch r.Severity {
+	case "":
+		r.Severity = "warning"
+	case "warning", "critical":
+	default:
+		return fmt.Errorf("severity must be warning or critical")
+	}
+	return r.Condition.Validate(r.Kind)
+}
+
+// Target is the pod or node a metrics stream is about
+type Target struct {
+	ConfigID  string `json:"configId"`
+	Cluster   string `json:"cluster,omitempty"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+func (t Target) key() string {
+	return strings.Join([]string{t.ConfigID, t.Cluster, t.Kind, t.Namespace, t.Name}, "|")
+}
+
+// Matches reports whether an enabled rule applies to the target
+func (r *Rule) Matches(t Target) bool {
+	if !r.Enabled || r.ConfigID != t.ConfigID || r.Kind != t.Kind {
+		return false
+	}
+	if r.Cluster != "" && r.Cluster != t.Cluster {
+		return false
+	}
+	if r.Namespace != "" && r.Namespace != t.Namespace {
+		return false
+	}
+	if r.Target != "" {
+		if ok, _ := path.Match(r.Target, t.Name); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseInline parses ad-hoc conditions such as "cpu_limit_percent>80 for 5m,memory_usage>=1e9"
+// into rules for a target. Their IDs are derived from the condition so breaches of the same
+// inline threshold are recorded once however many streams evaluate it.
+func ParseInline(value string, t Target) ([]Rule, error) {
+	var rules []Rule
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		var cond Condition
+		expr := part
+		if i := strings.Index(expr, " for "); i >= 0 {
+			d, err := time.ParseDuration(strings.TrimSpace(expr[i+5:]))
+			if err != nil {
+				return nil, fmt.Errorf("invalid duration in %q", part)
+			}
+			cond.ForSeconds = int(d.Seconds())
+			expr = expr[:i]
+		}
+		op := ""
+		idx := -1
+		for _, candidate := range []string{">=", "<=", ">", "<"} {
+			if i := strings.Index(expr, candidate); i > 0 {
+				op, idx = candidate, i
+				break
+			}
+		}
+		if op == "" {
+			return nil, fmt.Errorf("missing operator in %q", part)
+		}
+		cond.Metric = strings.TrimSpace(expr[:idx])
+		cond.Operator = op
+		v, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(expr[idx+len(op):]), "%"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value in %q", part)
+		}
+		cond.Value = v
+		if err := cond.Validate(t.Kind); err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256([]byte(cond.String()))
+		rules = append(rules, Rule{
+			ID:        "inline-" + hex.EncodeToString(sum[:6]),
+			Name:      cond.String(),
+			Enabled:   true,
+			ConfigID:  t.ConfigID,
+			Cluster:   t.Cluster,
+			Kind:      t.Kind,
+			Namespace: t.Namespace,
+			Target:    t.Name,
+			Condition: cond,
+			Severity:  "warning",
+		})
+	}
+	return rules, nil
+}
+
+// Sample is one metric value at a Unix timestamp in seconds
+type Sample struct {
+	T float64
+	V float64
+}
+
+// evaluate reports whether the condition holds over the samples: the latest sample must breach
+// the threshold and consecutive breaching samples must span at least the condition's duration.
+// It returns the time the current breaching run started and the peak value within it.
+func evaluate(cond Condition, samples []Sample) (bool, time.Time, float64) {
+	compare := operators[cond.Operator]
+	if compare == nil || len(samples) == 0 {
+		return false, time.Time{}, 0
+	}
+	last := len(samples) - 1
+	if !compare(samples[last].V, cond.Value) {
+		return false, time.Time{}, 0
+	}
+	first := last
+	peak := samples[last].V
+	for first > 0 && compare(samples[first-1].V, cond.Value) {
+		first--
+		// The peak is the most extreme value in the breach direction
+		if compare(samples[first].V, peak) {
+			peak = samples[first].V
+		}
+	}
+	since := unixTime(samples[first].T)
+	if samples[last].T-samples[first].T < float64(cond.ForSeconds) {
+		return false, since, peak
+	}
+	return true, since, peak
+}
+
+func unixTime(t float64) time.Time {
+	sec := int64(t)
+	return time.Unix(sec, int64((t-float64(sec))*1e9)).UTC()
+}
@@ -0,0 +1,92 @@
+package thresholds
+
+import (
+	"testing"
+	"time"
+)
+
+func samplesEvery(step float64, values ...float64) []Sample {
+	samples := make([]Sample, len(values))
+	for i, v := range values {
+		samples[i] = Sample{T: 1700000000 + float64(i)*step, V: v}
+	}
+	return samples
+}
+
+func TestEvaluate(t *testing.T) {
+	cond := Condition{Metric: "cpu_limit_percent", Operator: ">", Value: 80, ForSeconds: 60}
+
+	// 15s step: 5 consecutive breaching samples span 60s
+	fires, since, peak := evaluate(cond, samplesEvery(15, 50, 85, 90, 95, 88, 86))
+	if !fires || peak != 95 || !since.Equal(time.Unix(1700000015, 0).UTC()) {
+		t.Errorf("expected firing since second sample with peak 95, got %v %v %v", fires, since, peak)
+	}
+
+	// Breaching, but not for long enough: pending
+	fires, since, _ = evaluate(cond, samplesEvery(15, 50, 50, 50, 85, 90))
+	if fires || since.IsZero() {
+		t.Errorf("expected pending breach, got fires=%v since=%v", fires, since)
+	}
+
+	// Latest sample below the threshold
+	if fires, since, _ := evaluate(cond, samplesEvery(15, 90, 90, 90, 90, 90, 70)); fires || !since.IsZero() {
+		t.Errorf("expected no breach, got fires=%v since=%v", fires, since)
+	}
+
+	lower := Condition{Metric: "memory_usage", Operator: "<=", Value: 10}
+	if fires, _, peak := evaluate(lower, samplesEvery(15, 20, 5, 8)); !fires || peak != 5 {
+		t.Errorf("expected immediate firing with lowest peak 5, got %v %v", fires, peak)
+	}
+}
+
+func TestParseInline(t *testing.T) {
+	target := Target{ConfigID: "cfg", Kind: KindPod, Namespace: "prod", Name: "web-1"}
+	rules, err := ParseInline("cpu_limit_percent>80% for 5m, memory_usage >= 1e9", target)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("expected 2 rules, got %d", len(rules))
+	}
+	if c := rules[0].Condition; c.Metric != "cpu_limit_percent" || c.Operator != ">" || c.Value != 80 || c.ForSeconds != 300 {
+		t.Errorf("unexpected first condition %+v", c)
+	}
+	if c := rules[1].Condition; c.Metric != "memory_usage" || c.Operator != ">=" || c.Value != 1e9 {
+		t.Errorf("unexpected second condition %+v", c)
+	}
+	again, _ := ParseInline("cpu_limit_percent>80 for 5m", target)
+	if again[0].ID != rules[0].ID {
+		t.Errorf("expected stable inline rule IDs")
+	}
+
+	for _, invalid := range []string{"cpu_limit_percent 80", "disk_usage_percent>80", "memory_usage>lots", "cpu_average>1 for ever"} {
+		if _, err := ParseInline(invalid, target); err == nil {
+			t.Errorf("expected %q to be rejected", invalid)
+		}
+	}
+}
+
+func TestRuleMatches(t *testing.T) {
+	rule := Rule{Name: "hot pods", Enabled: true, ConfigID: "cfg", Kind: KindPod, Namespace: "prod", Target: "web-*",
+		Condition: Condition{Metric: "cpu_average", Operator: ">", Value: 500}}
+	if err := rule.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !rule.Matches(Target{ConfigID: "cfg", Cluster: "any", Kind: KindPod, Namespace: "prod", Name: "web-7f9c"}) {
+		t.Error("expected rule to match web pod")
+	}
+	for _, target := range []Target{
+		{ConfigID: "cfg", Kind: KindPod, Namespace: "dev", Name: "web-1"},
+		{ConfigID: "cfg", Kind: KindPod, Namespace: "prod", Name: "api-1"},
+		{ConfigID: "other", Kind: KindPod, Namespace: "prod", Name: "web-1"},
+		{ConfigID: "cfg", Kind: KindNode, Name: "web-1"},
+	} {
+		if rule.Matches(target) {
+			t.Errorf("expected rule not to match %+v", target)
+		}
+	}
+	rule.Enabled = false
+	if rule.Matches(Target{ConfigID: "cfg", Kind: KindPod, Namespace: "prod", Name: "web-1"}) {
+		t.Error("expected disabled rule not to match")
+	}
+}
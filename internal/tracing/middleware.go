@@ -138,12 +138,12 @@ func RecordSpanError(c *gin.Context, err error) {
 }
 
 // StartChildSpan starts a child span with the given name
-func StartChildSpan(c *gin.Context, name string, opts ...trace.SpanStartOption) (gin.Context, trace.Span) {
+func StartChildSpan(c *gin.Context, name string, opts ...trace.SpanStartOption) (*gin.Context, trace.Span) {
 	tracer := otel.Tracer("kube-dash")
 	ctx, span := tracer.Start(c.Request.Context(), name, opts...)
 
 	// Create new context with the child span
-	newContext := *c
+	newContext := c.Copy()
 	newContext.Request = c.Request.WithContext(ctx)
 
 	return newContext, span
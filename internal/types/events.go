@@ -0,0 +1,51 @@
+package types
+
+import (
+	"time"
+)
+
+// StoredEvent is a Kubernetes event persisted beyond the cluster's event TTL
+type StoredEvent struct {
+	ID           string    `json:"id"` // configID|cluster|event UID
+	ConfigID     string    `json:"configId"`
+	Cluster      string    `json:"cluster"`
+	Namespace    string    `json:"namespace"`
+	Name         string    `json:"name"`
+	Type         string    `json:"type"` // Normal or Warning
+	Reason       string    `json:"reason"`
+	Message      string    `json:"message"`
+	InvolvedKind string    `json:"involvedKind"`
+	InvolvedName string    `json:"involvedName"`
+	Source       string    `json:"source,omitempty"`
+	Count        int32     `json:"count"`
+	FirstSeen    time.Time `json:"firstSeen"`
+	LastSeen     time.Time `json:"lastSeen"`
+}
+
+// EventFilter represents filters for stored event queries; empty fields match everything
+type EventFilter struct {
+	ConfigID     string
+	Cluster      string
+	Namespace    string
+	InvolvedKind string
+	InvolvedName string
+	Reason       string
+	Type         string
+	Since        *time.Time
+	Until        *time.Time
+	Limit        int
+	Offset       int
+}
+
+// Matches reports whether an event satisfies the filter
+func (f EventFilter) Matches(e *StoredEvent) bool {
+	return (f.ConfigID == "" || e.ConfigID == f.ConfigID) &&
+		(f.Cluster == "" || e.Cluster == f.Cluster) &&
+		(f.Namespace == "" || e.Namespace == f.Namespace) &&
+		(f.InvolvedKind == "" || e.InvolvedKind == f.InvolvedKind) &&
+		(f.InvolvedName == "" || e.InvolvedName == f.InvolvedName) &&
+		(f.Reason == "" || e.Reason == f.Reason) &&
+		(f.Type == "" || e.Type == f.Type) &&
+		(f.Since == nil || !e.LastSeen.Before(*f.Since)) &&
+		(f.Until == nil || !e.LastSeen.After(*f.Until))
+}
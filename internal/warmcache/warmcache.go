@@ -0,0 +1,348 @@
+// Package warmcache prefetches small per-cluster summaries when a kubeconfig is registered, so
+// pages can render them instantly while their fresh data loads.
+package warmcache
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/config"
+	"github.com/Facets-cloud/kube-dash/internal/k8s"
+	"github.com/Facets-cloud/kube-dash/internal/storage"
+	"github.com/Facets-cloud/kube-dash/pkg/logger"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// fetchTimeout bounds prefetching the summary of one cluster
+const fetchTimeout = 20 * time.Second
+
+// NamespaceSummary is a namespace and its phase
+type NamespaceSummary struct {
+	Name  string `json:"name"`
+	Phase string `json:"phase"`
+}
+
+// NodeSummary is a node with its readiness and roles
+type NodeSummary struct {
+	Name           string   `json:"name"`
+	Ready          bool     `json:"ready"`
+	Unschedulable  bool     `json:"unschedulable"`
+	Roles          []string `json:"roles"`
+	KubeletVersion string   `json:"kubeletVersion"`
+}
+
+// WorkloadCounts are the number of workloads of each kind across the cluster
+type WorkloadCounts struct {
+	Deployments  int64 `json:"deployments"`
+	StatefulSets int64 `json:"statefulSets"`
+	DaemonSets   int64 `json:"daemonSets"`
+	Jobs         int64 `json:"jobs"`
+	CronJobs     int64 `json:"cronJobs"`
+	Pods         int64 `json:"pods"`
+	RunningPods  int64 `json:"runningPods"`
+}
+
+// Summary is the prefetched overview of one cluster
+type Summary struct {
+	Namespaces []NamespaceSummary `json:"namespaces"`
+	Nodes      []NodeSummary      `json:"nodes"`
+	Workloads  WorkloadCounts     `json:"workloads"`
+}
+
+// Entry is the cached summary of a cluster with its staleness
+type Entry struct {
+	ConfigID   string     `json:"configId"`
+	Cluster    string     `json:"cluster"`
+	Summary    *Summary   `json:"summary"` // nil until the first prefetch completes
+	FetchedAt  *time.Time `json:"fetchedAt,omitempty"`
+	AgeSeconds float64    `json:"ageSeconds"`
+	Stale      bool       `json:"stale"`      // older than the staleness limit, or not fetched yet
+	Refreshing bool       `json:"refreshing"` // a prefetch is running
+	LastError  string     `json:"lastError,omitempty"`
+}
+
+type entry struct {
+	summary    *Summary
+	fetchedAt  time.Time
+	refreshing bool
+	lastError  string
+}
+
+// Cache holds the prefetched summaries of registered clusters
+type Cache struct {
+	store         *storage.KubeConfigStore
+	clientFactory *k8s.ClientFactory
+	config        *config.WarmCacheConfig
+	logger        *logger.Logger
+
+	mu      sync.Mutex
+	entries map[string]*entry
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewCache creates a warm cache; call Start to prefetch the clusters already registered
+func NewCache(store *storage.KubeConfigStore, clientFactory *k8s.ClientFactory, cfg *config.WarmCacheConfig, log *logger.Logger) *Cache {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Cache{
+		store:         store,
+		clientFactory: clientFactory,
+		config:        cfg,
+		logger:        log,
+		entries:       make(map[string]*entry),
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+}
+
+// Enabled reports whether warm-cache mode is on
+func (wc *Cache) Enabled() bool {
+	return wc.config.Enabled
+}
+
+// Start prefetches every registered kubeconfig and refreshes the summaries in the background.
+// A refresh interval of zero leaves summaries to be refreshed when they are read stale.
+func (wc *Cache) Start() {
+	if !wc.Enabled() {
+		return
+	}
+	for id := range wc.store.ListKubeConfigs() {
+		wc.Warm(id)
+	}
+	if wc.config.RefreshIntervalSeconds <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(time.Duration(wc.config.RefreshIntervalSeconds) * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-wc.ctx.Done():
+				return
+			case <-ticker.C:
+				for id := range wc.store.ListKubeConfigs() {
+					wc.Warm(id)
+				}
+			}
+		}
+	}()
+}
+
+// Stop ends background prefetching
+func (wc *Cache) Stop() {
+	wc.cancel()
+}
+
+// Warm prefetches the summary of every cluster of a kubeconfig in the background
+func (wc *Cache) Warm(configID string) {
+	if !wc.Enabled() {
+		return
+	}
+	kubeconfig, err := wc.store.GetKubeConfig(configID)
+	if err != nil {
+		return
+	}
+	for cluster := range kubeconfig.Contexts {
+		wc.refresh(configID, cluster)
+	}
+}
+
+// Forget drops the summaries of a kubeconfig that was removed
+func (wc *Cache) Forget(configID string) {
+	wc.mu.Lock()
+	defer wc.mu.Unlock()
+	for key := range wc.entries {
+		if strings.HasPrefix(key, configID+"|") {
+			delete(wc.entries, key)
+		}
+	}
+}
+
+// Cleanup drops the summaries of a removed kubeconfig or cluster
+func (wc *Cache) Cleanup(removal storage.Removal) (int, error) {
+	wc.mu.Lock()
+	defer wc.mu.Unlock()
+	removed := 0
+	for key := range wc.entries {
+		configID, cluster, _ := strings.Cut(key, "|")
+		if removal.Matches(configID, cluster) {
+			delete(wc.entries, key)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// Get returns the cached summary of a cluster, starting a prefetch if it is stale or missing
+func (wc *Cache) Get(configID, cluster string) Entry {
+	now := time.Now()
+	staleAfter := time.Duration(wc.config.StaleAfterSeconds) * time.Second
+
+	wc.mu.Lock()
+	e := wc.entries[storage.ClusterKey(configID, cluster)]
+	result := Entry{ConfigID: configID, Cluster: cluster, Stale: true}
+	if e != nil {
+		result.Summary, result.Refreshing, result.LastError = e.summary, e.refreshing, e.lastError
+		if e.summary != nil {
+			fetchedAt := e.fetchedAt
+			result.FetchedAt = &fetchedAt
+			result.AgeSeconds = now.Sub(fetchedAt).Seconds()
+			result.Stale = now.Sub(fetchedAt) > staleAfter
+		}
+	}
+	wc.mu.Unlock()
+
+	if result.Stale && !result.Refreshing {
+		result.Refreshing = wc.refresh(configID, cluster)
+	}
+	return result
+}
+
+// refresh starts prefetching one cluster unless a prefetch is already running, reporting
+// whether one is running now
+func (wc *Cache) refresh(configID, cluster string) bool {
+	key := storage.ClusterKey(configID, cluster)
+	wc.mu.Lock()
+	e := wc.entries[key]
+	if e == nil {
+		e = &entry{}
+		wc.entries[key] = e
+	}
+	if e.refreshing {
+		wc.mu.Unlock()
+		return true
+	}
+	e.refreshing = true
+	wc.mu.Unlock()
+
+	go func() {
+		summary, err := wc.fetch(configID, cluster)
+		wc.mu.Lock()
+		defer wc.mu.Unlock()
+		e.refreshing = false
+		if err != nil {
+			e.lastError = err.Error()
+			wc.logger.WithError(err).WithField("config", configID).WithField("cluster", cluster).Warn("Failed to prefetch cluster summary")
+			return
+		}
+		e.summary, e.fetchedAt, e.lastError = summary, time.Now(), ""
+	}()
+	return true
+}
+
+// fetch reads the summary of one cluster
+func (wc *Cache) fetch(configID, cluster string) (*Summary, error) {
+	kubeconfig, err := wc.store.GetKubeConfig(configID)
+	if err != nil {
+		return nil, err
+	}
+	client, err := wc.clientFactory.GetClientForConfig(kubeconfig, cluster)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(wc.ctx, fetchTimeout)
+	defer cancel()
+	return fetchSummary(ctx, client)
+}
+
+// fetchSummary lists namespaces and nodes, and counts workloads a page of one at a time
+func fetchSummary(ctx context.Context, client kubernetes.Interface) (*Summary, error) {
+	summary := &Summary{Namespaces: []NamespaceSummary{}, Nodes: []NodeSummary{}}
+
+	namespaces, err := client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, ns := range namespaces.Items {
+		summary.Namespaces = append(summary.Namespaces, NamespaceSummary{Name: ns.Name, Phase: string(ns.Status.Phase)})
+	}
+	sort.Slice(summary.Namespaces, func(i, j int) bool { return summary.Namespaces[i].Name < summary.Namespaces[j].Name })
+
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range nodes.Items {
+		summary.Nodes = append(summary.Nodes, summarizeNode(&node))
+	}
+	sort.Slice(summary.Nodes, func(i, j int) bool { return summary.Nodes[i].Name < summary.Nodes[j].Name })
+
+	// A limit of one returns the remaining item count, so counts never fetch the objects
+	page := metav1.ListOptions{Limit: 1}
+	deployments, err := client.AppsV1().Deployments("").List(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+	summary.Workloads.Deployments = countOf(deployments, len(deployments.Items))
+	statefulSets, err := client.AppsV1().StatefulSets("").List(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+	summary.Workloads.StatefulSets = countOf(statefulSets, len(statefulSets.Items))
+	daemonSets, err := client.AppsV1().DaemonSets("").List(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+	summary.Workloads.DaemonSets = countOf(daemonSets, len(daemonSets.Items))
+	jobs, err := client.BatchV1().Jobs("").List(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+	summary.Workloads.Jobs = countOf(jobs, len(jobs.Items))
+	cronJobs, err := client.BatchV1().CronJobs("").List(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+	summary.Workloads.CronJobs = countOf(cronJobs, len(cronJobs.Items))
+	pods, err := client.CoreV1().Pods("").List(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+	summary.Workloads.Pods = countOf(pods, len(pods.Items))
+	running := page
+	running.FieldSelector = "status.phase=" + string(v1.PodRunning)
+	runningPods, err := client.CoreV1().Pods("").List(ctx, running)
+	if err != nil {
+		return nil, err
+	}
+	summary.Workloads.RunningPods = countOf(runningPods, len(runningPods.Items))
+	return summary, nil
+}
+
+// countOf returns the number of objects of a list fetched with a limit
+func countOf(list metav1.ListInterface, items int) int64 {
+	count := int64(items)
+	if remaining := list.GetRemainingItemCount(); remaining != nil {
+		count += *remaining
+	}
+	return count
+}
+
+// summarizeNode reduces a node to its readiness, roles and kubelet version
+func summarizeNode(node *v1.Node) NodeSummary {
+	summary := NodeSummary{
+		Name:           node.Name,
+		Unschedulable:  node.Spec.Unschedulable,
+		Roles:          []string{},
+		KubeletVersion: node.Status.NodeInfo.KubeletVersion,
+	}
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == v1.NodeReady {
+			summary.Ready = cond.Status == v1.ConditionTrue
+		}
+	}
+	for label := range node.Labels {
+		if role, ok := strings.CutPrefix(label, "node-role.kubernetes.io/"); ok && role != "" {
+			summary.Roles = append(summary.Roles, role)
+		}
+	}
+	sort.Strings(summary.Roles)
+	return summary
+}
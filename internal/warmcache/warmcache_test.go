@@ -0,0 +1,59 @@
+package warmcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Facets-cloud/kube-dash/internal/config"
+	"github.com/Facets-cloud/kube-dash/internal/testharness"
+)
+
+func TestGetPrefetchesStaleSummary(t *testing.T) {
+	h := testharness.New(t, "cluster.yaml")
+	cache := NewCache(h.Store, h.Factory, &config.WarmCacheConfig{Enabled: true, StaleAfterSeconds: 60}, h.Logger)
+	defer cache.Stop()
+
+	first := cache.Get(h.ConfigID, testharness.Cluster)
+	if first.Summary != nil || !first.Stale || !first.Refreshing {
+		t.Fatalf("expected a missing summary being prefetched, got %+v", first)
+	}
+
+	var entry Entry
+	deadline := time.Now().Add(5 * time.Second)
+	for entry = cache.Get(h.ConfigID, testharness.Cluster); entry.Summary == nil; entry = cache.Get(h.ConfigID, testharness.Cluster) {
+		if time.Now().After(deadline) {
+			t.Fatalf("summary was not prefetched: %+v", entry)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if entry.Stale || entry.LastError != "" {
+		t.Fatalf("expected a fresh summary, got %+v", entry)
+	}
+
+	summary := entry.Summary
+	if len(summary.Namespaces) != 2 || summary.Namespaces[0].Name != "default" {
+		t.Errorf("unexpected namespaces: %+v", summary.Namespaces)
+	}
+	if len(summary.Nodes) != 1 || !summary.Nodes[0].Ready || len(summary.Nodes[0].Roles) != 1 || summary.Nodes[0].Roles[0] != "worker" {
+		t.Errorf("unexpected nodes: %+v", summary.Nodes)
+	}
+	if summary.Workloads.Deployments != 1 || summary.Workloads.Pods != 1 {
+		t.Errorf("unexpected workload counts: %+v", summary.Workloads)
+	}
+
+	cache.Forget(h.ConfigID)
+	if forgotten := cache.Get(h.ConfigID, testharness.Cluster); forgotten.Summary != nil {
+		t.Errorf("expected forgotten summary to be dropped, got %+v", forgotten)
+	}
+}
+
+func TestDisabledCacheDoesNotPrefetch(t *testing.T) {
+	h := testharness.New(t, "cluster.yaml")
+	cache := NewCache(h.Store, h.Factory, &config.WarmCacheConfig{}, h.Logger)
+	defer cache.Stop()
+
+	cache.Warm(h.ConfigID)
+	if len(cache.entries) != 0 {
+		t.Errorf("expected no prefetch while disabled, got %d entries", len(cache.entries))
+	}
+}
@@ -5,9 +5,16 @@ import (
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
 
+// CorrelationIDHeader carries the ID that ties a response, its error body and the server logs together
+const CorrelationIDHeader = "X-Request-ID"
+
+// correlationIDKey is the gin context key holding the request's correlation ID
+const correlationIDKey = "correlationID"
+
 // Logger returns a gin.HandlerFunc for logging HTTP requests
 func Logger(log *logrus.Logger) gin.HandlerFunc {
 	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
@@ -21,6 +28,7 @@ func Logger(log *logrus.Logger) gin.HandlerFunc {
 			"latency":     param.Latency,
 			"user_agent":  param.Request.UserAgent(),
 			"error":       param.ErrorMessage,
+			"request_id":  param.Keys[correlationIDKey],
 		}).Info("HTTP Request")
 
 		return ""
@@ -33,7 +41,8 @@ func CORS() gin.HandlerFunc {
 	config.AllowAllOrigins = true
 	config.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
 	config.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization"}
-	config.ExposeHeaders = []string{"Content-Length"}
+	config.AllowHeaders = append(config.AllowHeaders, CorrelationIDHeader)
+	config.ExposeHeaders = []string{"Content-Length", CorrelationIDHeader}
 	config.AllowCredentials = true
 
 	return cors.New(config)
@@ -55,3 +64,35 @@ func Recovery(log *logrus.Logger) gin.HandlerFunc {
 		})
 	})
 }
+
+// CorrelationID assigns every request a correlation ID, reusing a valid one sent by the client, and
+// echoes it in the X-Request-ID response header
+func CorrelationID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(CorrelationIDHeader)
+		if !validCorrelationID(id) {
+			id = uuid.New().String()
+		}
+		c.Set(correlationIDKey, id)
+		c.Header(CorrelationIDHeader, id)
+		c.Next()
+	}
+}
+
+// CorrelationIDFrom returns the correlation ID assigned by CorrelationID, or an empty string
+func CorrelationIDFrom(c *gin.Context) string {
+	return c.GetString(correlationIDKey)
+}
+
+// validCorrelationID accepts short IDs of letters, digits, dots, dashes and underscores
+func validCorrelationID(id string) bool {
+	if len(id) == 0 || len(id) > 128 {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}